@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/account"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+var accountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "View profiles grouped by underlying account",
+	Long: `Groups vault and isolated profiles by the account they authenticate as
+(provider + email), so the same login saved under several profile names is
+shown, counted, and cooled down once.
+
+Examples:
+  caam accounts ls
+  caam accounts ls claude
+  caam accounts ls --json`,
+}
+
+var accountsLsCmd = &cobra.Command{
+	Use:     "ls [tool]",
+	Aliases: []string{"list"},
+	Short:   "List accounts with their linked profiles, cooldowns, and usage",
+	Args:    cobra.MaximumNArgs(1),
+	RunE:    runAccountsLs,
+}
+
+func init() {
+	rootCmd.AddCommand(accountsCmd)
+	accountsCmd.AddCommand(accountsLsCmd)
+	accountsLsCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// accountView is the aggregated view of one account.
+type accountView struct {
+	Key              string     `json:"key"`
+	Provider         string     `json:"provider"`
+	Email            string     `json:"email"`
+	VaultProfiles    []string   `json:"vault_profiles"`
+	IsolatedProfiles []string   `json:"isolated_profiles"`
+	CooldownUntil    *time.Time `json:"cooldown_until,omitempty"`
+	CooldownProfile  string     `json:"cooldown_profile,omitempty"`
+	Activations      int        `json:"activations"`
+	Errors           int        `json:"errors"`
+	ActiveSeconds    int        `json:"active_seconds"`
+}
+
+// accountsOutput is the JSON output structure for accounts ls.
+type accountsOutput struct {
+	Accounts []accountView `json:"accounts"`
+	Count    int           `json:"count"`
+	Unlinked []string      `json:"unlinked,omitempty"`
+}
+
+func runAccountsLs(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	providers := knownTools()
+	if len(args) > 0 {
+		tool := strings.ToLower(args[0])
+		if _, ok := tools[tool]; !ok {
+			return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
+		}
+		providers = []string{tool}
+	}
+
+	var db *caamdb.DB
+	if d, err := caamdb.Open(); err == nil {
+		db = d
+		defer db.Close()
+	}
+
+	reg, unlinked := buildAccountRegistry(providers)
+
+	now := time.Now().UTC()
+	views := make([]accountView, 0)
+	for _, acct := range reg.Accounts() {
+		views = append(views, summarizeAccount(db, acct, now))
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(accountsOutput{Accounts: views, Count: len(views), Unlinked: unlinked})
+	}
+
+	out := cmd.OutOrStdout()
+	if len(views) == 0 {
+		fmt.Fprintln(out, "No accounts found.")
+		if len(unlinked) > 0 {
+			fmt.Fprintf(out, "Profiles without a detectable email: %s\n", strings.Join(unlinked, ", "))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROVIDER\tEMAIL\tVAULT\tISOLATED\tCOOLDOWN\tACTIVATIONS\tACTIVE")
+	for _, v := range views {
+		cooldown := "-"
+		if v.CooldownUntil != nil {
+			cooldown = fmt.Sprintf("%s (%s)", formatDurationShort(v.CooldownUntil.Sub(now)), v.CooldownProfile)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n",
+			v.Provider,
+			v.Email,
+			joinOrDash(v.VaultProfiles),
+			joinOrDash(v.IsolatedProfiles),
+			cooldown,
+			v.Activations,
+			formatDurationShort(time.Duration(v.ActiveSeconds)*time.Second),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(unlinked) > 0 {
+		fmt.Fprintf(out, "\nProfiles without a detectable email: %s\n", strings.Join(unlinked, ", "))
+	}
+	return nil
+}
+
+// buildAccountRegistry links every vault and isolated profile for the given
+// providers to its account. Profiles whose identity can't be determined are
+// returned as "tool/profile" (isolated ones suffixed with " (isolated)").
+func buildAccountRegistry(providers []string) (*account.Registry, []string) {
+	reg := account.NewRegistry()
+	var unlinked []string
+
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+
+	for _, tool := range providers {
+		names, err := vault.List(tool)
+		if err == nil {
+			for _, name := range names {
+				if authfile.IsSystemProfile(name) {
+					continue
+				}
+				email := ""
+				if id := getVaultIdentity(tool, name); id != nil {
+					email = id.Email
+				}
+				if !reg.Add(tool, email, account.KindVault, name) {
+					unlinked = append(unlinked, tool+"/"+name)
+				}
+			}
+		}
+
+		if profileStore == nil {
+			continue
+		}
+		profs, err := profileStore.List(tool)
+		if err != nil {
+			continue
+		}
+		for _, prof := range profs {
+			email := ""
+			if prof.Identity != nil {
+				email = prof.Identity.Email
+			}
+			if !reg.Add(tool, email, account.KindIsolated, prof.Name) {
+				unlinked = append(unlinked, tool+"/"+prof.Name+" (isolated)")
+			}
+		}
+	}
+
+	sort.Strings(unlinked)
+	return reg, unlinked
+}
+
+// syncAccountLinks records the account each profile of the given providers
+// belongs to, so the database can propagate cooldowns between profiles of the
+// same account. Profiles without a detectable email are unlinked.
+func syncAccountLinks(db *caamdb.DB, providers ...string) {
+	if db == nil {
+		return
+	}
+	reg, _ := buildAccountRegistry(providers)
+	for _, tool := range providers {
+		names, _ := vault.List(tool)
+		for _, name := range names {
+			if authfile.IsSystemProfile(name) {
+				continue
+			}
+			key := ""
+			if acct := reg.Lookup(tool, account.KindVault, name); acct != nil {
+				key = acct.Key
+			}
+			_ = db.LinkAccountProfile(tool, name, key)
+		}
+	}
+	for _, acct := range reg.Accounts() {
+		for _, m := range acct.Members {
+			if m.Kind == account.KindIsolated && reg.Lookup(acct.Provider, account.KindVault, m.Profile) == nil {
+				_ = db.LinkAccountProfile(acct.Provider, m.Profile, acct.Key)
+			}
+		}
+	}
+}
+
+// summarizeAccount aggregates cooldowns and usage stats across every profile
+// name linked to the account. db may be nil, in which case only profile
+// membership is reported.
+func summarizeAccount(db *caamdb.DB, acct *account.Account, now time.Time) accountView {
+	v := accountView{
+		Key:              acct.Key,
+		Provider:         acct.Provider,
+		Email:            acct.Email,
+		VaultProfiles:    []string{},
+		IsolatedProfiles: []string{},
+	}
+	for _, m := range acct.Members {
+		switch m.Kind {
+		case account.KindVault:
+			v.VaultProfiles = append(v.VaultProfiles, m.Profile)
+		case account.KindIsolated:
+			v.IsolatedProfiles = append(v.IsolatedProfiles, m.Profile)
+		}
+	}
+
+	if db == nil {
+		return v
+	}
+
+	// Usage and cooldowns are recorded by profile name, so a name shared by a
+	// vault and an isolated profile is only counted once.
+	for _, name := range acct.ProfileNames() {
+		if ev, err := db.ActiveCooldown(acct.Provider, name, now); err == nil && ev != nil {
+			if v.CooldownUntil == nil || ev.CooldownUntil.After(*v.CooldownUntil) {
+				until := ev.CooldownUntil
+				v.CooldownUntil = &until
+				v.CooldownProfile = name
+			}
+		}
+		if stats, err := db.GetStats(acct.Provider, name); err == nil && stats != nil {
+			v.Activations += stats.TotalActivations
+			v.Errors += stats.TotalErrors
+			v.ActiveSeconds += stats.TotalActiveSeconds
+		}
+	}
+	return v
+}
+
+func joinOrDash(items []string) string {
+	if len(items) == 0 {
+		return "-"
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAccountsTest points the vault, profile store, and database at a temp dir.
+func setupAccountsTest(t *testing.T) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	originalVault := vault
+	originalStore := profileStore
+	t.Cleanup(func() {
+		vault = originalVault
+		profileStore = originalStore
+	})
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	profileStore = profile.NewStore(filepath.Join(tmpDir, "profiles"))
+}
+
+// writeClaudeVaultProfile writes a vault profile whose credentials carry email.
+func writeClaudeVaultProfile(t *testing.T, name, email string) {
+	t.Helper()
+	dir := vault.ProfilePath("claude", name)
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	creds := `{"claudeAiOauth": {"accessToken": "tok-` + name + `", "email": "` + email + `"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".credentials.json"), []byte(creds), 0600))
+}
+
+func TestAccountsLs_GroupsProfilesByEmail(t *testing.T) {
+	setupAccountsTest(t)
+
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "work-old", "Alice@Example.com")
+	writeClaudeVaultProfile(t, "personal", "bob@example.com")
+	require.NoError(t, os.MkdirAll(vault.ProfilePath("claude", "noemail"), 0700))
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	_, err = db.SetCooldown("claude", "work-old", time.Now().UTC(), time.Hour, "")
+	require.NoError(t, err)
+	require.NoError(t, db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "claude", ProfileName: "work"}))
+	require.NoError(t, db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "claude", ProfileName: "work-old"}))
+	require.NoError(t, db.Close())
+
+	var buf bytes.Buffer
+	accountsLsCmd.SetOut(&buf)
+	t.Cleanup(func() { accountsLsCmd.SetOut(nil) })
+	require.NoError(t, accountsLsCmd.Flags().Set("json", "true"))
+	t.Cleanup(func() { _ = accountsLsCmd.Flags().Set("json", "false") })
+
+	require.NoError(t, runAccountsLs(accountsLsCmd, []string{"claude"}))
+
+	var out accountsOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	require.Equal(t, 2, out.Count)
+
+	alice := out.Accounts[0]
+	assert.Equal(t, "alice@example.com", alice.Email)
+	assert.Equal(t, []string{"work", "work-old"}, alice.VaultProfiles)
+	assert.Equal(t, 2, alice.Activations)
+	require.NotNil(t, alice.CooldownUntil, "cooldown on a sibling profile should apply to the account")
+	assert.Equal(t, "work-old", alice.CooldownProfile)
+
+	bob := out.Accounts[1]
+	assert.Equal(t, "bob@example.com", bob.Email)
+	assert.Nil(t, bob.CooldownUntil)
+
+	assert.Equal(t, []string{"claude/noemail"}, out.Unlinked)
+}
+
+func TestAccountsLs_UnknownTool(t *testing.T) {
+	setupAccountsTest(t)
+
+	err := runAccountsLs(accountsLsCmd, []string{"nope"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown tool")
+}
+
+func TestCooldownSet_PropagatesToSameAccountProfiles(t *testing.T) {
+	setupAccountsTest(t)
+
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "work-old", "alice@example.com")
+	writeClaudeVaultProfile(t, "personal", "bob@example.com")
+
+	var buf bytes.Buffer
+	cooldownSetCmd.SetOut(&buf)
+	t.Cleanup(func() { cooldownSetCmd.SetOut(nil) })
+
+	require.NoError(t, runCooldownSet(cooldownSetCmd, []string{"claude/work"}))
+	assert.Contains(t, buf.String(), "Also applied to same-account profiles: work-old")
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now().UTC()
+	ev, err := db.ActiveCooldown("claude", "work-old", now)
+	require.NoError(t, err)
+	assert.NotNil(t, ev, "sibling profile should share the cooldown")
+
+	ev, err = db.ActiveCooldown("claude", "personal", now)
+	require.NoError(t, err)
+	assert.Nil(t, ev, "other accounts must not be cooled down")
+}
@@ -0,0 +1,336 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
+	syncstate "github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+)
+
+var ackCmd = &cobra.Command{
+	Use:   "ack [id...]",
+	Short: "Acknowledge suspicious auth changes flagged by status",
+	Long: `caam watches for auth changes that can mean a credential was copied or
+tampered with, and flags them as alerts in 'caam status' until acknowledged:
+
+  unknown_credential  the live login that matched a profile was replaced by
+                      a credential for another account that isn't in the vault
+  expiry_regressed    a profile's token expiry moved backwards, as when an
+                      older token is written over a newer one
+  new_machine         a profile was activated on a machine not seen before
+                      (each profile's activations are recorded in the vault,
+                      so they travel with sync and bundles)
+
+Without arguments, lists pending alerts. Pass ids (or unique prefixes) to
+acknowledge them once reviewed, or --all. An acknowledged alert is not
+raised again.
+
+Examples:
+  caam ack
+  caam ack 3f9a1c2b
+  caam ack --all
+  caam ack --history --json`,
+	RunE: runAck,
+}
+
+func init() {
+	rootCmd.AddCommand(ackCmd)
+	ackCmd.Flags().Bool("all", false, "acknowledge every pending alert")
+	ackCmd.Flags().Bool("history", false, "list acknowledged alerts too")
+	ackCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+func runAck(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	history, _ := cmd.Flags().GetBool("history")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if healthStore == nil {
+		return fmt.Errorf("health store not initialized")
+	}
+	if all && len(args) > 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("use either ids or --all, not both"))
+	}
+
+	out := cmd.OutOrStdout()
+	if !all && len(args) == 0 {
+		anomalies, err := healthStore.Anomalies(history)
+		if err != nil {
+			return err
+		}
+		if jsonOutput {
+			return writeAnomaliesJSON(out, anomalies)
+		}
+		if len(anomalies) == 0 {
+			fmt.Fprintln(out, "No pending alerts.")
+			return nil
+		}
+		for _, a := range anomalies {
+			fmt.Fprintln(out, formatAnomaly(a))
+		}
+		return nil
+	}
+
+	acked, err := healthStore.AckAnomalies(args...)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if jsonOutput {
+		return writeAnomaliesJSON(out, acked)
+	}
+	if len(acked) == 0 {
+		fmt.Fprintln(out, "No pending alerts.")
+		return nil
+	}
+	for _, a := range acked {
+		fmt.Fprintf(out, "[OK] Acknowledged %s\n", formatAnomaly(a))
+	}
+	return nil
+}
+
+func writeAnomaliesJSON(w io.Writer, anomalies []health.Anomaly) error {
+	if anomalies == nil {
+		anomalies = []health.Anomaly{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(anomalies)
+}
+
+// formatAnomaly renders an alert on one line, e.g.
+// "[ALERT] 3f9a1c2b claude/work new_machine: activated on laptop ...".
+func formatAnomaly(a health.Anomaly) string {
+	target := a.Provider
+	if a.Profile != "" {
+		target += "/" + a.Profile
+	}
+	line := fmt.Sprintf("[ALERT] %s %s %s: %s", a.ID, target, a.Kind, a.Detail)
+	if a.Acked() {
+		line += fmt.Sprintf(" (acknowledged %s)", a.AckedAt.Local().Format("2006-01-02 15:04"))
+	}
+	return line
+}
+
+// detectAuthAnomalies checks tool's live auth and vault profiles for
+// suspicious changes, records new ones and returns tool's pending alerts.
+// activeProfile is the profile the live auth matches, if any.
+func detectAuthAnomalies(tool string, fileSet authfile.AuthFileSet, activeProfile string) []health.Anomaly {
+	if healthStore == nil || vault == nil {
+		return nil
+	}
+
+	checkLiveAuthReplaced(tool, fileSet, activeProfile)
+
+	profiles, _ := vault.List(tool)
+	local, _ := syncstate.LoadLocalIdentity()
+	for _, profile := range profiles {
+		checkExpiryRegressed(tool, profile)
+		checkNewMachines(tool, profile, local)
+	}
+
+	pending, err := healthStore.Anomalies(false)
+	if err != nil {
+		return nil
+	}
+	var out []health.Anomaly
+	for _, a := range pending {
+		if a.Provider == tool {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// checkLiveAuthReplaced flags live auth that matched a profile and now holds
+// a credential for an account none of the tool's profiles belong to. A
+// changed credential for the same account is the tool refreshing its own
+// token and is not flagged.
+func checkLiveAuthReplaced(tool string, fileSet authfile.AuthFileSet, activeProfile string) {
+	fingerprint := liveAuthFingerprint(fileSet)
+	if fingerprint == "" {
+		return
+	}
+	account := identityAccount(liveIdentity(fileSet))
+
+	prev, err := healthStore.ObserveLiveAuth(tool, health.LiveAuthObservation{
+		Fingerprint: fingerprint,
+		Profile:     activeProfile,
+		Identity:    account,
+	})
+	if err != nil || prev == nil || activeProfile != "" || prev.Profile == "" || prev.Fingerprint == fingerprint {
+		return
+	}
+	if account != "" {
+		if strings.EqualFold(account, prev.Identity) {
+			return
+		}
+		profiles, _ := vault.List(tool)
+		for _, profile := range profiles {
+			if strings.EqualFold(account, identityAccount(getVaultIdentity(tool, profile))) {
+				return
+			}
+		}
+	}
+
+	detail := fmt.Sprintf("live auth that matched %s was replaced by a credential that isn't in the vault", prev.Profile)
+	if account != "" {
+		detail += " (" + account + ")"
+	}
+	_, _ = healthStore.RecordAnomaly(health.Anomaly{
+		Kind:     health.AnomalyUnknownCredential,
+		Provider: tool,
+		Profile:  prev.Profile,
+		Detail:   detail,
+		Key:      fmt.Sprintf("%s|%s|%s", health.AnomalyUnknownCredential, tool, fingerprint),
+	})
+}
+
+// checkExpiryRegressed flags a profile whose stored token expiry moved
+// backwards since it was last seen.
+func checkExpiryRegressed(tool, profile string) {
+	info, err := loadExpiryInfo(tool, profile)
+	if err != nil || info == nil || info.ExpiresAt.IsZero() {
+		return
+	}
+	prev, regressed, err := healthStore.ObserveTokenExpiry(tool, profile, info.ExpiresAt)
+	if err != nil || !regressed {
+		return
+	}
+	_, _ = healthStore.RecordAnomaly(health.Anomaly{
+		Kind:     health.AnomalyExpiryRegressed,
+		Provider: tool,
+		Profile:  profile,
+		Detail: fmt.Sprintf("token expiry moved back from %s to %s",
+			prev.Local().Format("2006-01-02 15:04"), info.ExpiresAt.Local().Format("2006-01-02 15:04")),
+		Key: fmt.Sprintf("%s|%s|%s|%d", health.AnomalyExpiryRegressed, tool, profile, info.ExpiresAt.Unix()),
+	})
+}
+
+// checkNewMachines flags machines other than this one recorded as having
+// activated a profile. Each machine is flagged once, whichever profile it
+// shows up in first.
+func checkNewMachines(tool, profile string, local *syncstate.LocalIdentity) {
+	records, err := vault.Machines(tool, profile)
+	if err != nil {
+		return
+	}
+	for _, r := range records {
+		if local != nil && r.ID == local.ID {
+			continue
+		}
+		host := r.Hostname
+		if host == "" {
+			host = "unknown host"
+		}
+		_, _ = healthStore.RecordAnomaly(health.Anomaly{
+			Kind:     health.AnomalyNewMachine,
+			Provider: tool,
+			Profile:  profile,
+			Detail: fmt.Sprintf("activated on %s (machine %s) at %s",
+				host, shortMachineID(r.ID), r.FirstSeen.Local().Format("2006-01-02 15:04")),
+			Key: fmt.Sprintf("%s|%s", health.AnomalyNewMachine, r.ID),
+		})
+	}
+}
+
+// recordActivationMachine notes this machine in the profile's machine list
+// so that activations elsewhere can be flagged (see checkNewMachines), and
+// broadcasts the activation to the sync pool when that is on.
+func recordActivationMachine(tool, profile string) {
+	if vault == nil {
+		return
+	}
+	local, err := syncstate.GetOrCreateLocalIdentity()
+	if err != nil || local == nil {
+		return
+	}
+	now := time.Now()
+	_ = vault.RecordMachine(tool, profile, local.ID, local.Hostname, now)
+	recordBroadcastActivation(local, tool, profile, now)
+}
+
+func shortMachineID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// liveIdentity extracts the account identity from the live auth files.
+func liveIdentity(fileSet authfile.AuthFileSet) *identity.Identity {
+	paths := make(map[string]string, len(fileSet.Files))
+	for _, spec := range fileSet.Files {
+		paths[filepath.Base(spec.Path)] = spec.Path
+	}
+	return identityIn(fileSet.Tool, func(name string) string {
+		return paths[name]
+	})
+}
+
+// identityAccount returns the email or account id identifying id's account.
+func identityAccount(id *identity.Identity) string {
+	if id == nil {
+		return ""
+	}
+	if id.Email != "" {
+		return id.Email
+	}
+	return id.AccountID
+}
+
+// liveAuthFingerprint hashes the live auth files the way ActiveProfile
+// matches them: the required files, or the optional ones for tools that
+// allow optional-only logins. It returns "" when none exist.
+func liveAuthFingerprint(fileSet authfile.AuthFileSet) string {
+	var paths []string
+	for _, spec := range fileSet.Files {
+		if spec.Required {
+			paths = append(paths, spec.Path)
+		}
+	}
+	if fileSet.AllowOptionalOnly && !anyFileExists(paths) {
+		paths = paths[:0]
+		for _, spec := range fileSet.Files {
+			paths = append(paths, spec.Path)
+		}
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	found := false
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		found = true
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.Base(path), len(data))
+		h.Write(data)
+	}
+	if !found {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func anyFileExists(paths []string) bool {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
+}
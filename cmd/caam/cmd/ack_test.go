@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+// setupAnomalyTest gives the test a vault, health store and a live Claude
+// credentials file, returning the live file set.
+func setupAnomalyTest(t *testing.T) authfile.AuthFileSet {
+	t.Helper()
+	setupAccountsTest(t)
+
+	originalHealth := healthStore
+	t.Cleanup(func() { healthStore = originalHealth })
+	healthStore = health.NewStorage(filepath.Join(t.TempDir(), "health.json"))
+
+	return authfile.AuthFileSet{
+		Tool: "claude",
+		Files: []authfile.AuthFileSpec{
+			{Tool: "claude", Path: filepath.Join(t.TempDir(), ".credentials.json"), Required: true},
+		},
+	}
+}
+
+func writeClaudeCreds(t *testing.T, path, token, email string, expiresAt time.Time) {
+	t.Helper()
+	creds := fmt.Sprintf(`{"claudeAiOauth": {"accessToken": %q, "email": %q, "expiresAt": %d}}`, token, email, expiresAt.UnixMilli())
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	require.NoError(t, os.WriteFile(path, []byte(creds), 0600))
+}
+
+func TestDetectAuthAnomalies_UnknownCredential(t *testing.T) {
+	fileSet := setupAnomalyTest(t)
+	live := fileSet.Files[0].Path
+	expires := time.Now().Add(time.Hour)
+
+	writeClaudeCreds(t, vault.BackupPath("claude", "work", ".credentials.json"), "tok-a", "alice@example.com", expires)
+	writeClaudeCreds(t, live, "tok-a", "alice@example.com", expires)
+	assert.Empty(t, detectAuthAnomalies("claude", fileSet, "work"))
+
+	// The tool refreshing its own token changes the file but not the account.
+	writeClaudeCreds(t, live, "tok-a2", "alice@example.com", expires.Add(time.Hour))
+	assert.Empty(t, detectAuthAnomalies("claude", fileSet, ""))
+
+	writeClaudeCreds(t, live, "tok-a", "alice@example.com", expires)
+	assert.Empty(t, detectAuthAnomalies("claude", fileSet, "work"))
+
+	writeClaudeCreds(t, live, "tok-m", "mallory@example.com", expires)
+	alerts := detectAuthAnomalies("claude", fileSet, "")
+	require.Len(t, alerts, 1)
+	assert.Equal(t, health.AnomalyUnknownCredential, alerts[0].Kind)
+	assert.Equal(t, "work", alerts[0].Profile)
+	assert.Contains(t, alerts[0].Detail, "mallory@example.com")
+
+	// Raised once, not on every status.
+	assert.Len(t, detectAuthAnomalies("claude", fileSet, ""), 1)
+}
+
+func TestDetectAuthAnomalies_ExpiryRegressed(t *testing.T) {
+	fileSet := setupAnomalyTest(t)
+	creds := vault.BackupPath("claude", "work", ".credentials.json")
+	expires := time.Now().Add(6 * time.Hour)
+
+	writeClaudeCreds(t, creds, "tok-new", "alice@example.com", expires)
+	assert.Empty(t, detectAuthAnomalies("claude", fileSet, ""))
+
+	writeClaudeCreds(t, creds, "tok-old", "alice@example.com", expires.Add(-3*time.Hour))
+	alerts := detectAuthAnomalies("claude", fileSet, "")
+	require.Len(t, alerts, 1)
+	assert.Equal(t, health.AnomalyExpiryRegressed, alerts[0].Kind)
+	assert.Equal(t, "work", alerts[0].Profile)
+}
+
+func TestDetectAuthAnomalies_NewMachineAndAck(t *testing.T) {
+	fileSet := setupAnomalyTest(t)
+	writeClaudeCreds(t, vault.BackupPath("claude", "work", ".credentials.json"), "tok", "alice@example.com", time.Time{})
+
+	recordActivationMachine("claude", "work")
+	assert.Empty(t, detectAuthAnomalies("claude", fileSet, ""), "this machine's own activations are not flagged")
+
+	require.NoError(t, vault.RecordMachine("claude", "work", "0123456789abcdef", "stranger-laptop", time.Now()))
+	alerts := detectAuthAnomalies("claude", fileSet, "")
+	require.Len(t, alerts, 1)
+	assert.Equal(t, health.AnomalyNewMachine, alerts[0].Kind)
+	assert.Contains(t, alerts[0].Detail, "stranger-laptop")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("all", false, "")
+	cmd.Flags().Bool("history", false, "")
+	cmd.Flags().Bool("json", false, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	require.NoError(t, runAck(cmd, nil))
+	assert.Contains(t, out.String(), alerts[0].ID)
+
+	out.Reset()
+	require.Error(t, runAck(cmd, []string{"nope"}))
+	require.NoError(t, runAck(cmd, []string{alerts[0].ID}))
+	assert.Contains(t, out.String(), "[OK] Acknowledged")
+
+	assert.Empty(t, detectAuthAnomalies("claude", fileSet, ""), "acknowledged machines are not flagged again")
+}
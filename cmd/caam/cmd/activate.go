@@ -5,14 +5,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"os/exec"
 	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/account"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/daemon"
 	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/refresh"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
@@ -22,15 +30,20 @@ import (
 
 // activateOutput is the JSON output structure for activate command.
 type activateOutput struct {
-	Success         bool                    `json:"success"`
-	Tool            string                  `json:"tool"`
-	Profile         string                  `json:"profile"`
-	PreviousProfile string                  `json:"previous_profile,omitempty"`
-	Source          string                  `json:"source,omitempty"`
-	AutoBackup      string                  `json:"auto_backup,omitempty"`
-	Refreshed       bool                    `json:"refreshed,omitempty"`
-	Rotation        *activateRotationResult `json:"rotation,omitempty"`
-	Error           string                  `json:"error,omitempty"`
+	Success         bool                       `json:"success"`
+	Tool            string                     `json:"tool"`
+	Profile         string                     `json:"profile"`
+	PreviousProfile string                     `json:"previous_profile,omitempty"`
+	Source          string                     `json:"source,omitempty"`
+	AutoBackup      string                     `json:"auto_backup,omitempty"`
+	Safety          *authfile.ActivationSafety `json:"safety,omitempty"`
+	Refreshed       bool                       `json:"refreshed,omitempty"`
+	RevertTo        string                     `json:"revert_to,omitempty"`
+	RevertAt        *time.Time                 `json:"revert_at,omitempty"`
+	Rotation        *activateRotationResult    `json:"rotation,omitempty"`
+	Error           string                     `json:"error,omitempty"`
+	ErrorCode       string                     `json:"error_code,omitempty"`
+	ExitCode        int                        `json:"exit_code,omitempty"`
 }
 
 type activateRotationResult struct {
@@ -57,8 +70,12 @@ Examples:
   caam activate codex work-account
   caam activate codex
   caam activate claude personal-max
+  caam activate claude 2          # profile #2 in 'caam ls claude'
+  caam activate claude --email alice@example.com
   caam activate gemini team-ultra
   caam activate claude --auto
+  caam activate claude demo --for 2h
+  caam activate claude --revert
 
 The --auto flag enables smart profile rotation, which selects the best profile
 based on health status, cooldown state, and usage patterns. Three algorithms
@@ -68,6 +85,28 @@ are available (configured in config.yaml):
   round_robin - Sequential rotation through profiles
   random      - Random selection
 
+The --for flag time-boxes the activation: caam records what was active before
+and switches back when the time is up, so borrowing an account for a quick
+test can't accidentally become permanent. The running daemon performs the
+revert; without one, it is scheduled with systemd-run or at. Use --revert to
+switch back early. Activating again without --for keeps the new profile.
+
+Profile names are matched tolerantly: an alias or a case-insensitive name
+works directly, and a unique prefix or substring ("caam activate claude
+work" for work-account-1) asks before activating (--yes skips the question).
+Input matching several profiles lists them instead.
+
+Before the switch, live auth that isn't saved in any vault profile is backed
+up to a timestamped _backup_* profile (safety.auto_backup_before_switch,
+old backups rotated per safety.max_auto_backups), so a mistaken activate
+can't lose a login. --backup-current backs up on every switch; --no-backup
+skips it. The one-time _original snapshot of the pre-caam login is always
+kept.
+
+--email picks the vault profile logged in as that account (see 'caam accounts
+ls'); if several profiles hold the same login, they are listed so you can
+choose one by name.
+
 After activating, just run the tool normally - it will use the new account.`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runActivate,
@@ -75,9 +114,15 @@ After activating, just run the tool normally - it will use the new account.`,
 
 func init() {
 	activateCmd.Flags().Bool("backup-current", false, "backup current auth before switching")
+	activateCmd.Flags().Bool("no-backup", false, "skip the automatic backup of the current auth")
 	activateCmd.Flags().Bool("force", false, "activate even if the profile is in cooldown")
 	activateCmd.Flags().Bool("auto", false, "auto-select profile using rotation algorithm")
 	activateCmd.Flags().Bool("json", false, "output as JSON")
+	activateCmd.Flags().Var(durations.NewValue(0), "for", "switch back automatically after this long, e.g. 2h or 5pm")
+	activateCmd.Flags().Bool("revert", false, "switch back from a time-boxed activation now")
+	activateCmd.Flags().String("email", "", "activate the vault profile logged in as this account email")
+	registerDurationCompletion(activateCmd, "for")
+	_ = activateCmd.RegisterFlagCompletionFunc("email", completeAccountEmails)
 }
 
 func runActivate(cmd *cobra.Command, args []string) error {
@@ -95,6 +140,9 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		if jsonOutput {
 			output.Success = false
 			output.Error = err.Error()
+			output.ErrorCode = errorCode(err)
+			output.ExitCode = ExitCode(err)
+			pendingExitCode = output.ExitCode
 			enc := json.NewEncoder(cmd.OutOrStdout())
 			enc.SetIndent("", "  ")
 			_ = enc.Encode(output)
@@ -104,12 +152,30 @@ func runActivate(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(args) == 2 && autoSelect {
-		return emitJSONError(fmt.Errorf("--auto cannot be used when a profile name is provided"))
+		return emitJSONError(withExitCode(ExitUsage, fmt.Errorf("--auto cannot be used when a profile name is provided")))
+	}
+	timeBox, _ := cmd.Flags().GetDuration("for")
+	if timeBox < 0 {
+		return emitJSONError(withExitCode(ExitUsage, fmt.Errorf("--for must be greater than zero")))
+	}
+	revertNow, _ := cmd.Flags().GetBool("revert")
+	if revertNow && (len(args) == 2 || autoSelect || timeBox > 0) {
+		return emitJSONError(withExitCode(ExitUsage, fmt.Errorf("--revert takes only a tool")))
+	}
+	backupFirst, _ := cmd.Flags().GetBool("backup-current")
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+	if backupFirst && noBackup {
+		return emitJSONError(withExitCode(ExitUsage, fmt.Errorf("--backup-current and --no-backup are mutually exclusive")))
+	}
+	email, _ := cmd.Flags().GetString("email")
+	email = strings.TrimSpace(email)
+	if email != "" && (len(args) == 2 || autoSelect || revertNow) {
+		return emitJSONError(withExitCode(ExitUsage, fmt.Errorf("--email replaces the profile name; it can't be combined with a name, --auto or --revert")))
 	}
 
 	getFileSet, ok := tools[tool]
 	if !ok {
-		return emitJSONError(fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool))
+		return emitJSONError(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())))
 	}
 
 	// Ensure vault is initialized before using it
@@ -117,46 +183,57 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		vault = authfile.NewVault(authfile.DefaultVaultPath())
 	}
 
+	if revertNow {
+		return runActivateRevert(cmd, tool, jsonOutput)
+	}
+
 	fileSet := getFileSet()
 	previousProfile, _ := vault.ActiveProfile(fileSet)
 	output.PreviousProfile = previousProfile
 
-	// Safety: on first activate, preserve the user's pre-caam auth state.
-	if did, err := vault.BackupOriginal(fileSet); err != nil {
-		return emitJSONError(fmt.Errorf("backup original auth: %w", err))
-	} else if did && !jsonOutput {
-		fmt.Printf("Backed up original %s auth to %s\n", tool, "_original")
-	}
-
 	spmCfg, err := config.LoadSPMConfig()
 	if err != nil {
 		// Invalid config should not crash activation; fall back to defaults.
 		spmCfg = config.DefaultSPMConfig()
 		err = nil
 	}
+	applyContextStrategy(spmCfg)
 
 	needDB := spmCfg.Analytics.Enabled || spmCfg.Stealth.Cooldown.Enabled || spmCfg.Stealth.Rotation.Enabled || autoSelect
 	var db *caamdb.DB
 	if needDB {
-		db, err = getDB()
-		if err != nil {
-			if !jsonOutput {
-				fmt.Printf("Warning: could not open database: %v\n", err)
-			}
-		}
+		// A database that can't be opened is reported once by getDB;
+		// activation carries on without cooldown and rotation data.
+		db, _ = getDB()
 	}
 
 	var profileName string
 	var source string
 	var selection *rotation.Result
 
-	if len(args) == 2 {
+	if email != "" {
+		profileName, err = resolveProfileByEmail(tool, email)
+		if err != nil {
+			return emitJSONError(err)
+		}
+		source = "email " + email
+		if !jsonOutput {
+			fmt.Printf("Using %s: %s/%s\n", source, tool, profileName)
+		}
+	} else if len(args) == 2 {
 		profileName = args[1]
 
 		// Try to resolve as alias or fuzzy match
 		profiles, err := vault.List(tool)
 		if err == nil {
-			profileName = resolveProfileName(tool, profileName, profiles, jsonOutput)
+			profileName, err = resolveProfileName(cmd, tool, profileName, profiles, jsonOutput)
+			if errors.Is(err, errCancelled) && !jsonOutput {
+				fmt.Println("Cancelled")
+				return nil
+			}
+			if err != nil {
+				return emitJSONError(err)
+			}
 		}
 	} else {
 		// Resolve from project/default first unless user explicitly requested rotation.
@@ -184,6 +261,10 @@ func runActivate(cmd *cobra.Command, args []string) error {
 			if err != nil {
 				return emitJSONError(fmt.Errorf("list profiles: %w", err))
 			}
+			profiles, required := filterProfilesForClass(tool, profiles)
+			if len(profiles) == 0 && required != "" {
+				return emitJSONError(withExitCode(ExitNoHealthyProfile, fmt.Errorf("no %s profiles of class %s for this directory", tool, required)))
+			}
 
 			selection, err = selectProfileWithRotation(tool, profiles, previousProfile, spmCfg, db)
 			if err != nil {
@@ -220,15 +301,21 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		output.Rotation = rot
 	}
 
+	// Guard rails: keep profiles out of directories requiring another class.
+	if ok, err := confirmProfileClass(cmd, tool, profileName, isTerminal() && !jsonOutput); err != nil {
+		return emitJSONError(err)
+	} else if !ok {
+		fmt.Println("Cancelled")
+		return nil
+	}
+
 	// Stealth: enforce per-profile cooldowns (opt-in).
 	if spmCfg.Stealth.Cooldown.Enabled {
 		force, _ := cmd.Flags().GetBool("force")
 
-		if db == nil {
-			if !jsonOutput {
-				fmt.Printf("Warning: cooldown enforcement enabled but database is unavailable\n")
-			}
-		} else {
+		// Without a database there are no cooldowns to enforce; getDB has
+		// already said so.
+		if db != nil {
 			now := time.Now().UTC()
 			ev, err := db.ActiveCooldown(tool, profileName, now)
 			if err != nil {
@@ -251,9 +338,9 @@ func runActivate(cmd *cobra.Command, args []string) error {
 					fmt.Printf("  Cooldown remaining: %s\n", formatDurationShort(remaining))
 				}
 
-				if !force {
+				if !force && !assumeYes(cmd) {
 					if !isTerminal() || jsonOutput {
-						return emitJSONError(fmt.Errorf("%s/%s is in cooldown (%s remaining); re-run with --force to activate anyway", tool, profileName, formatDurationShort(remaining)))
+						return emitJSONError(withExitCode(ExitAllInCooldown, fmt.Errorf("%s/%s is in cooldown (%s remaining); re-run with --force to activate anyway", tool, profileName, formatDurationShort(remaining))))
 					}
 
 					ok, err := confirmProceed(cmd.InOrStdin(), cmd.OutOrStdout())
@@ -265,7 +352,7 @@ func runActivate(cmd *cobra.Command, args []string) error {
 						return nil
 					}
 				} else if !jsonOutput {
-					fmt.Println("Proceeding due to --force...")
+					fmt.Println("Proceeding anyway...")
 				}
 			}
 		}
@@ -275,100 +362,58 @@ func runActivate(cmd *cobra.Command, args []string) error {
 	refreshed := refreshIfNeeded(cmd.Context(), tool, profileName, jsonOutput)
 	output.Refreshed = refreshed
 
-	// Smart auto-backup before switch (based on safety config)
-	backupMode := strings.TrimSpace(spmCfg.Safety.AutoBackupBeforeSwitch)
-	if backupMode == "" {
-		backupMode = "smart" // Default
+	// --backup-current and --no-backup override the safety config
+	activateOpts := activateOptions(spmCfg)
+	switch {
+	case backupFirst:
+		activateOpts.BackupMode = authfile.BackupAlways
+	case noBackup:
+		activateOpts.BackupMode = authfile.BackupNever
 	}
 
-	// Check if --backup-current flag overrides config
-	backupFirst, _ := cmd.Flags().GetBool("backup-current")
-	if backupFirst {
-		backupMode = "always"
+	if timeBox > 0 && previousProfile == profileName {
+		return emitJSONError(withExitCode(ExitUsage, fmt.Errorf("%s/%s is already active; nothing to switch back to", tool, profileName)))
 	}
 
-	if backupMode != "never" {
-		shouldBackup := false
-		currentProfile, _ := vault.ActiveProfile(fileSet)
-
-		switch backupMode {
-		case "always":
-			// Always backup if there are auth files and we're switching to a different profile
-			shouldBackup = currentProfile != profileName
-		case "smart":
-			// Backup only if current state doesn't match any vault profile (would be lost)
-			shouldBackup = currentProfile == "" && authfile.HasAuthFiles(fileSet)
-		}
-
-		if shouldBackup {
-			backupName, err := vault.BackupCurrent(fileSet)
-			if err != nil {
-				if !jsonOutput {
-					fmt.Printf("Warning: could not auto-backup current state: %v\n", err)
-				}
-			} else if backupName != "" {
-				output.AutoBackup = backupName
-				if !jsonOutput {
-					fmt.Printf("Auto-backed up current state to %s\n", backupName)
-				}
-
-				// Rotate old backups if limit is set
-				if spmCfg.Safety.MaxAutoBackups > 0 {
-					if err := vault.RotateAutoBackups(tool, spmCfg.Safety.MaxAutoBackups); err != nil {
-						if !jsonOutput {
-							fmt.Printf("Warning: could not rotate old backups: %v\n", err)
-						}
-					}
-				}
+	// Between the backups and the switch: the stealth delay, and for
+	// time-boxed activations, what to switch back to before it is
+	// overwritten.
+	var revertTo string
+	var beforeErr error
+	activateOpts.BeforeRestore = func() error {
+		// Skip stealth delay in JSON mode as it's for interactive use
+		if spmCfg.Stealth.SwitchDelay.Enabled && !jsonOutput {
+			if beforeErr = stealthSwitchDelay(cmd, spmCfg); beforeErr != nil {
+				return beforeErr
 			}
 		}
+		if timeBox > 0 {
+			revertTo, beforeErr = timeBoxRevertTarget(fileSet, previousProfile)
+		}
+		return beforeErr
 	}
 
-	// Stealth: optional delay before the actual switch happens.
-	// Skip stealth delay in JSON mode as it's for interactive use
-	if spmCfg.Stealth.SwitchDelay.Enabled && !jsonOutput {
-		delay, err := stealth.ComputeDelay(spmCfg.Stealth.SwitchDelay.MinSeconds, spmCfg.Stealth.SwitchDelay.MaxSeconds, nil)
-		if err != nil {
-			fmt.Printf("Warning: invalid stealth.switch_delay config: %v\n", err)
-		} else if delay > 0 {
-			fmt.Printf("Stealth mode: waiting %d seconds before switch...\n", int(delay.Round(time.Second).Seconds()))
-
-			sigCh := make(chan os.Signal, 1)
-			signal.Notify(sigCh, os.Interrupt)
-
-			skip := make(chan struct{})
-			stop := make(chan struct{})
-			go func() {
-				select {
-				case <-sigCh:
-					close(skip)
-				case <-stop:
-				case <-cmd.Context().Done():
-				}
-			}()
-
-			skipped, waitErr := stealth.Wait(cmd.Context(), delay, stealth.WaitOptions{
-				Output:        os.Stdout,
-				Skip:          skip,
-				ShowCountdown: spmCfg.Stealth.SwitchDelay.ShowCountdown,
-			})
-
-			close(stop)
-			signal.Stop(sigCh)
-
-			if waitErr != nil {
-				return fmt.Errorf("stealth delay: %w", waitErr)
-			}
-			if skipped {
-				fmt.Println("Skipping delay...")
-			}
+	// Back up per the safety config, then restore from the vault
+	safety, err := vault.Activate(fileSet, profileName, activateOpts)
+	output.Safety = safety
+	output.AutoBackup = safety.AutoBackup
+	if !jsonOutput {
+		printActivationSafety(tool, safety)
+	}
+	if err != nil {
+		if beforeErr != nil {
+			return emitJSONError(beforeErr)
 		}
+		err = fmt.Errorf("activate failed: %w", err)
+		if !vaultHasProfile(tool, profileName) {
+			err = withErrorCode("NO_AUTH", err)
+		} else if ExitCode(err) == ExitError {
+			err = withErrorCode("ACTIVATE_FAILED", err)
+		}
+		return emitJSONError(err)
 	}
 
-	// Restore from vault
-	if err := vault.Restore(fileSet, profileName); err != nil {
-		return emitJSONError(fmt.Errorf("activate failed: %w", err))
-	}
+	recordActivationMachine(tool, profileName)
 
 	if spmCfg.Analytics.Enabled && db != nil {
 		_ = db.LogEvent(caamdb.Event{
@@ -382,8 +427,29 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		})
 	}
 
+	var revertRunner string
+	if timeBox > 0 {
+		revertAt := time.Now().Add(timeBox)
+		if err := daemon.ScheduleRevert(daemon.ScheduledRevert{
+			Tool:     tool,
+			Profile:  profileName,
+			RevertTo: revertTo,
+			RevertAt: revertAt,
+		}); err != nil {
+			return emitJSONError(fmt.Errorf("schedule revert: %w", err))
+		}
+		revertRunner = scheduleRevertRunner(spmCfg, revertAt)
+		output.RevertTo = revertTo
+		output.RevertAt = &revertAt
+	} else {
+		// A plain activation is meant to stick.
+		_, _ = daemon.CancelRevert(tool)
+	}
+
 	output.Profile = profileName
 	output.Success = true
+	// The account stays active after caam exits, so the label stays too.
+	labelTerminal(tool, profileName)
 
 	if jsonOutput {
 		enc := json.NewEncoder(cmd.OutOrStdout())
@@ -393,9 +459,215 @@ func runActivate(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Activated %s profile '%s'\n", tool, profileName)
 	fmt.Printf("  Run '%s' to start using this account\n", tool)
+	if output.RevertAt != nil {
+		target := "logged out"
+		if revertTo != "" {
+			target = fmt.Sprintf("'%s'", revertTo)
+		}
+		fmt.Printf("  Switches back to %s at %s (%s)\n", target, output.RevertAt.Local().Format("15:04"), formatDurationShort(timeBox))
+		if revertRunner == "" {
+			fmt.Printf("Warning: no daemon running and neither systemd-run nor at is available;\n")
+			fmt.Printf("  start one with 'caam daemon start' or switch back with 'caam activate %s --revert'\n", tool)
+		}
+	}
+	return nil
+}
+
+// resolveProfileByEmail returns the one vault profile of tool logged in as
+// email, using the account registry that 'caam accounts ls' shows.
+func resolveProfileByEmail(tool, email string) (string, error) {
+	reg, _ := buildAccountRegistry([]string{tool})
+	acct := reg.ByEmail(tool, email)
+	names := acct.ProfilesOfKind(account.KindVault)
+	switch len(names) {
+	case 1:
+		return names[0], nil
+	case 0:
+		if isolated := acct.ProfilesOfKind(account.KindIsolated); len(isolated) > 0 {
+			return "", withExitCode(ExitAuthMissing, fmt.Errorf("%s is only in isolated %s profiles (%s); use 'caam exec %s %s'",
+				email, tool, strings.Join(isolated, ", "), tool, isolated[0]))
+		}
+		return "", withExitCode(ExitAuthMissing, fmt.Errorf("no %s vault profile is logged in as %s (see 'caam accounts ls %s')", tool, email, tool))
+	default:
+		return "", withExitCode(ExitUsage, fmt.Errorf("%s is saved in %d %s profiles: %s; activate one by name, e.g. 'caam activate %s %s'",
+			email, len(names), tool, strings.Join(names, ", "), tool, names[0]))
+	}
+}
+
+// runActivateRevert switches tool back from its time-boxed activation.
+func runActivateRevert(cmd *cobra.Command, tool string, jsonOutput bool) error {
+	outcome, err := daemon.RevertNow(vault, tool)
+	if err == nil && outcome != nil {
+		err = outcome.Err
+	}
+	if err != nil {
+		return fmt.Errorf("revert: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		result := map[string]any{"tool": tool, "reverted": outcome != nil && outcome.Applied}
+		if outcome != nil {
+			result["profile"] = outcome.Revert.Profile
+			result["revert_to"] = outcome.Revert.RevertTo
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	switch {
+	case outcome == nil:
+		fmt.Fprintf(out, "No time-boxed %s activation to revert.\n", tool)
+	case !outcome.Applied:
+		fmt.Fprintf(out, "%s/%s is no longer active; nothing to revert.\n", tool, outcome.Revert.Profile)
+	case outcome.Revert.RevertTo == "":
+		fmt.Fprintf(out, "[OK] Reverted %s/%s (logged out, as before)\n", tool, outcome.Revert.Profile)
+	default:
+		fmt.Fprintf(out, "[OK] Reverted %s/%s to '%s'\n", tool, outcome.Revert.Profile, outcome.Revert.RevertTo)
+	}
 	return nil
 }
 
+// activateOptions returns the activation safety settings from the config.
+func activateOptions(spmCfg *config.SPMConfig) authfile.ActivateOptions {
+	if spmCfg == nil {
+		return authfile.ActivateOptions{}
+	}
+	return authfile.ActivateOptions{
+		BackupMode:     spmCfg.Safety.AutoBackupBeforeSwitch,
+		MaxAutoBackups: spmCfg.Safety.MaxAutoBackups,
+	}
+}
+
+// printActivationSafety reports the backups an activation made.
+func printActivationSafety(tool string, safety *authfile.ActivationSafety) {
+	fprintActivationSafety(os.Stdout, tool, safety)
+}
+
+// fprintActivationSafety is printActivationSafety writing to w.
+func fprintActivationSafety(w io.Writer, tool string, safety *authfile.ActivationSafety) {
+	if safety == nil {
+		return
+	}
+	if safety.OriginalBackup != "" {
+		fmt.Fprintf(w, "Backed up original %s auth to %s\n", tool, safety.OriginalBackup)
+	}
+	if safety.AutoBackup != "" {
+		fmt.Fprintf(w, "Auto-backed up current state to %s\n", safety.AutoBackup)
+	}
+	for _, warning := range safety.Warnings {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+}
+
+// stealthSwitchDelay waits the configured stealth.switch_delay before a
+// switch; Ctrl-C skips the wait.
+func stealthSwitchDelay(cmd *cobra.Command, spmCfg *config.SPMConfig) error {
+	delay, err := stealth.ComputeDelay(spmCfg.Stealth.SwitchDelay.MinSeconds, spmCfg.Stealth.SwitchDelay.MaxSeconds, nil)
+	if err != nil {
+		fmt.Printf("Warning: invalid stealth.switch_delay config: %v\n", err)
+		return nil
+	}
+	if delay <= 0 {
+		return nil
+	}
+	fmt.Printf("Stealth mode: waiting %d seconds before switch...\n", int(delay.Round(time.Second).Seconds()))
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+
+	skip := make(chan struct{})
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-sigCh:
+			close(skip)
+		case <-stop:
+		case <-cmd.Context().Done():
+		}
+	}()
+
+	skipped, waitErr := stealth.Wait(cmd.Context(), delay, stealth.WaitOptions{
+		Output:        os.Stdout,
+		Skip:          skip,
+		ShowCountdown: spmCfg.Stealth.SwitchDelay.ShowCountdown,
+	})
+
+	close(stop)
+	signal.Stop(sigCh)
+
+	if waitErr != nil {
+		return fmt.Errorf("stealth delay: %w", waitErr)
+	}
+	if skipped {
+		fmt.Println("Skipping delay...")
+	}
+	return nil
+}
+
+// timeBoxRevertTarget returns the profile a time-boxed activation switches
+// back to. Chained time-boxed activations keep the original target, and
+// live auth that isn't saved in the vault is backed up first so it survives.
+// Empty means there was no auth to go back to.
+func timeBoxRevertTarget(fileSet authfile.AuthFileSet, previousProfile string) (string, error) {
+	pending, _ := daemon.PendingRevert(fileSet.Tool)
+	if pending != nil && pending.Profile == previousProfile {
+		return pending.RevertTo, nil
+	}
+	if previousProfile != "" {
+		return previousProfile, nil
+	}
+	if !authfile.HasAuthFiles(fileSet) {
+		return "", nil
+	}
+	name := "_revert_" + time.Now().Format("20060102_150405")
+	if err := vault.Backup(fileSet, name); err != nil {
+		return "", fmt.Errorf("back up current auth for revert: %w", err)
+	}
+	return name, nil
+}
+
+// scheduleRevertRunner makes sure something will apply the revert due at
+// at, returning what will ("daemon", "systemd-run" or "at"), or "" if
+// nothing could be arranged.
+var scheduleRevertRunner = func(spmCfg *config.SPMConfig, at time.Time) string {
+	if spmCfg != nil && spmCfg.Runtime.PIDFilePath != "" {
+		daemon.SetPIDFilePath(spmCfg.Runtime.PIDFilePath)
+	}
+	if running, _, _ := daemon.GetDaemonStatus(); running {
+		return "daemon"
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	delay := time.Until(at)
+	if path, err := exec.LookPath("systemd-run"); err == nil {
+		args := []string{"--user", "--quiet", "--collect",
+			fmt.Sprintf("--on-active=%ds", int(math.Ceil(delay.Seconds()))),
+			"--timer-property=AccuracySec=1s"}
+		if caamHome := os.Getenv("CAAM_HOME"); caamHome != "" {
+			args = append(args, "--setenv=CAAM_HOME="+caamHome)
+		}
+		args = append(args, exe, "daemon", "revert-due")
+		if exec.Command(path, args...).Run() == nil {
+			return "systemd-run"
+		}
+	}
+	if path, err := exec.LookPath("at"); err == nil {
+		// at has minute granularity; round up so the job never runs early.
+		minutes := int(delay/time.Minute) + 2
+		job := exec.Command(path, "now", "+", strconv.Itoa(minutes), "minutes")
+		job.Stdin = strings.NewReader(shellQuote(exe) + " daemon revert-due\n")
+		if job.Run() == nil {
+			return "at"
+		}
+	}
+	return ""
+}
+
 func resolveActivateProfile(tool string, spmCfg *config.SPMConfig) (profileName string, source string, err error) {
 	// Prefer project association (if enabled).
 	if spmCfg == nil {
@@ -468,6 +740,7 @@ func refreshIfNeeded(ctx context.Context, provider, profile string, quiet bool)
 		}
 		return false // Continue activation even if refresh fails
 	}
+	recordRefreshEvent(provider, profile)
 
 	if !quiet {
 		fmt.Println("done")
@@ -477,7 +750,7 @@ func refreshIfNeeded(ctx context.Context, provider, profile string, quiet bool)
 
 func selectProfileWithRotation(tool string, profiles []string, currentProfile string, spmCfg *config.SPMConfig, db *caamdb.DB) (*rotation.Result, error) {
 	if len(profiles) == 0 {
-		return nil, fmt.Errorf("no profiles found for %s; create one with 'caam backup %s <name>'", tool, tool)
+		return nil, withExitCode(ExitAuthMissing, fmt.Errorf("no profiles found for %s; create one with 'caam backup %s <name>'", tool, tool))
 	}
 
 	primePlanTypes(tool, profiles)
@@ -490,45 +763,107 @@ func selectProfileWithRotation(tool string, profiles []string, currentProfile st
 	}
 
 	selector := rotation.NewSelector(algorithm, healthStore, db)
+	selector.SetExcluded(quarantinedProfiles(tool))
 	result, err := selector.Select(tool, profiles, currentProfile)
 	if err != nil {
 		return nil, fmt.Errorf("rotation select: %w", err)
 	}
 
+	if globalCfg, err := config.Load(); err == nil {
+		result.Pin(globalCfg.GetPin(tool))
+	}
+
 	return result, nil
 }
 
 // resolveProfileName resolves a profile name from user input.
-// It tries: exact match -> alias resolution -> fuzzy match.
-// The quiet parameter suppresses all output (for JSON mode).
-func resolveProfileName(tool, input string, profiles []string, quiet bool) string {
+// It tries, in order: exact match -> 'caam ls' number -> alias -> unique
+// case-insensitive match of a name or alias -> unique prefix/substring
+// match. A prefix/substring match may not be the profile meant, so it is
+// confirmed first (--yes accepts it); several candidates at either tolerant
+// step are an error listing them. Input that matches nothing is returned as
+// is and fails later with the usual error. The quiet parameter suppresses
+// all output (for JSON mode).
+func resolveProfileName(cmd *cobra.Command, tool, input string, profiles []string, quiet bool) (string, error) {
 	// Check for exact match first
 	for _, p := range profiles {
 		if p == input {
-			return input
+			return input, nil
 		}
 	}
 
-	// Try alias resolution
-	globalCfg, err := config.Load()
-	if err == nil {
+	// A number picks that profile from 'caam ls'
+	if n, ok := parseProfileIndex(input); ok {
+		resolved, err := profileByIndex(tool, n)
+		if err == nil && !quiet {
+			fmt.Printf("Profile #%d: %s\n", n, resolved)
+		}
+		return resolved, err
+	}
+
+	// Try alias resolution (an unreadable config leaves globalCfg nil)
+	globalCfg, _ := config.Load()
+	if globalCfg != nil {
 		if resolved := globalCfg.ResolveAliasForProvider(tool, input); resolved != "" {
 			if !quiet {
 				fmt.Printf("Using alias: %s -> %s\n", input, resolved)
 			}
-			return resolved
+			return resolved, nil
 		}
+	}
 
-		// Try fuzzy matching
-		matches := globalCfg.FuzzyMatch(tool, input, profiles)
-		if len(matches) > 0 {
-			if !quiet && matches[0] != input {
-				fmt.Printf("Matched: %s -> %s\n", input, matches[0])
+	// Case-insensitive match on names and aliases
+	var folded []string
+	for _, p := range profiles {
+		names := []string{p}
+		if globalCfg != nil {
+			names = append(names, globalCfg.GetAliases(tool, p)...)
+		}
+		for _, name := range names {
+			if strings.EqualFold(name, input) {
+				folded = append(folded, p)
+				break
 			}
-			return matches[0]
 		}
 	}
+	switch len(folded) {
+	case 0:
+	case 1:
+		if !quiet {
+			fmt.Printf("Matched: %s -> %s\n", input, folded[0])
+		}
+		return folded[0], nil
+	default:
+		return "", ambiguousProfileError(tool, input, folded)
+	}
+
+	if globalCfg == nil {
+		return input, nil
+	}
+
+	// Try fuzzy (prefix/substring) matching
+	var matches []string
+	for _, m := range globalCfg.FuzzyMatch(tool, input, profiles) {
+		if !slices.Contains(matches, m) {
+			matches = append(matches, m)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		// No match found, return original (will fail later with proper error)
+		return input, nil
+	case 1:
+		if !confirmPrompt(cmd, quiet, fmt.Sprintf("No %s profile named '%s'. Activate '%s'?", tool, input, matches[0])) {
+			return "", errCancelled
+		}
+		return matches[0], nil
+	default:
+		return "", ambiguousProfileError(tool, input, matches)
+	}
+}
 
-	// No match found, return original (will fail later with proper error)
-	return input
+// ambiguousProfileError reports input matching several profiles.
+func ambiguousProfileError(tool, input string, candidates []string) error {
+	return withExitCode(ExitUsage, fmt.Errorf("'%s' matches several %s profiles: %s (use the full name)",
+		input, tool, strings.Join(candidates, ", ")))
 }
@@ -117,6 +117,8 @@ func TestActivateCommand_Extended(t *testing.T) {
 
 	assert.False(t, errOutput.Success)
 	assert.Contains(t, errOutput.Error, "unknown tool")
+	assert.Equal(t, ExitUsage, errOutput.ExitCode)
+	assert.Equal(t, "INVALID_PROVIDER", errOutput.ErrorCode)
 	
 	h.EndStep("Error")
 	
@@ -157,6 +159,7 @@ stealth:
 	// Should fail due to cooldown
 	assert.False(t, cooldownOutput.Success)
 	assert.Contains(t, cooldownOutput.Error, "is in cooldown")
+	assert.Equal(t, ExitAllInCooldown, cooldownOutput.ExitCode)
 	
 	h.EndStep("Cooldown")
 }
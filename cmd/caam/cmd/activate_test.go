@@ -2,12 +2,20 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/daemon"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
 )
 
 func TestActivate_AutoBackupsOriginalOnFirstSwitch(t *testing.T) {
@@ -191,3 +199,248 @@ func TestActivate_AutoBackupsUnsavedStateBeforeSwitch(t *testing.T) {
 		t.Fatalf("auto-backup auth mismatch: got %q want %q", gotBackup, unsaved)
 	}
 }
+
+func TestActivate_NoBackupSkipsAutoBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+
+	// _original already exists, so only the auto-backup could be taken.
+	if err := os.MkdirAll(vault.ProfilePath("codex", "_original"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	targetDir := vault.ProfilePath("codex", "target")
+	if err := os.MkdirAll(targetDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(targetDir, "auth.json"), []byte(`{"access_token":"target"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(os.Getenv("CODEX_HOME"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(os.Getenv("CODEX_HOME"), "auth.json"), []byte(`{"access_token":"unsaved"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("backup-current", false, "")
+		cmd.Flags().Bool("no-backup", true, "")
+		cmd.Flags().Bool("json", false, "")
+		return cmd
+	}
+
+	conflicting := newCmd()
+	if err := conflicting.Flags().Set("backup-current", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runActivate(conflicting, []string{"codex", "target"}); ExitCode(err) != ExitUsage {
+		t.Fatalf("--backup-current --no-backup error = %v, want a usage error", err)
+	}
+
+	if err := runActivate(newCmd(), []string{"codex", "target"}); err != nil {
+		t.Fatalf("runActivate() error = %v", err)
+	}
+	profiles, err := vault.List("codex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range profiles {
+		if strings.HasPrefix(name, "_backup_") {
+			t.Errorf("--no-backup still made %s", name)
+		}
+	}
+}
+
+func TestActivate_TimeBoxedRevert(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+
+	oldRunner := scheduleRevertRunner
+	scheduleRevertRunner = func(*config.SPMConfig, time.Time) string { return "daemon" }
+	t.Cleanup(func() { scheduleRevertRunner = oldRunner })
+
+	for _, profile := range []string{"work", "demo"} {
+		dir := vault.ProfilePath("codex", profile)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"`+profile+`"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fileSet := authfile.CodexAuthFiles()
+	if err := vault.Restore(fileSet, "work"); err != nil {
+		t.Fatal(err)
+	}
+	live := fileSet.Files[0].Path
+
+	newCmd := func(flags ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Var(durations.NewValue(0), "for", "")
+		cmd.Flags().Bool("revert", false, "")
+		cmd.Flags().Bool("json", false, "")
+		for i := 0; i+1 < len(flags); i += 2 {
+			if err := cmd.Flags().Set(flags[i], flags[i+1]); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return cmd
+	}
+
+	if err := runActivate(newCmd("for", "2h"), []string{"codex", "demo"}); err != nil {
+		t.Fatalf("runActivate(--for) error = %v", err)
+	}
+	pending, err := daemon.PendingRevert("codex")
+	if err != nil || pending == nil || pending.Profile != "demo" || pending.RevertTo != "work" {
+		t.Fatalf("PendingRevert() = %+v, %v; want demo -> work", pending, err)
+	}
+	if until := time.Until(pending.RevertAt); until < time.Hour || until > 2*time.Hour {
+		t.Errorf("RevertAt in %v, want about 2h", until)
+	}
+
+	if err := runActivate(newCmd("revert", "true", "for", "1h"), []string{"codex"}); ExitCode(err) != ExitUsage {
+		t.Errorf("--revert with --for: err = %v, want usage error", err)
+	}
+
+	var out strings.Builder
+	revertCmd := newCmd("revert", "true")
+	revertCmd.SetOut(&out)
+	if err := runActivate(revertCmd, []string{"codex"}); err != nil {
+		t.Fatalf("runActivate(--revert) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "Reverted codex/demo to 'work'") {
+		t.Errorf("revert output = %q", out.String())
+	}
+	if data, _ := os.ReadFile(live); string(data) != `{"access_token":"work"}` {
+		t.Errorf("live auth = %s, want work restored", data)
+	}
+
+	// A plain activation afterwards is permanent.
+	if err := runActivate(newCmd("for", "1h"), []string{"codex", "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runActivate(newCmd(), []string{"codex", "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := daemon.PendingRevert("codex"); pending != nil {
+		t.Errorf("pending after plain activate = %+v, want cancelled", pending)
+	}
+}
+
+func TestResolveProfileName_Tolerant(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	profiles := []string{"work-account-1", "Personal", "personal-old", "client-alpha", "client-beta"}
+
+	resolve := func(input, answer string) (string, error) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.SetIn(strings.NewReader(answer))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		return resolveProfileName(cmd, "claude", input, profiles, true)
+	}
+
+	if got, err := resolve("Personal", ""); err != nil || got != "Personal" {
+		t.Errorf("exact = %q, %v", got, err)
+	}
+	if got, err := resolve("WORK-ACCOUNT-1", ""); err != nil || got != "work-account-1" {
+		t.Errorf("case-insensitive = %q, %v; want work-account-1 without asking", got, err)
+	}
+	if got, err := resolve("work", "y\n"); err != nil || got != "work-account-1" {
+		t.Errorf("confirmed prefix = %q, %v; want work-account-1", got, err)
+	}
+	if _, err := resolve("work", "n\n"); !errors.Is(err, errCancelled) {
+		t.Errorf("declined prefix error = %v, want errCancelled", err)
+	}
+	_, err := resolve("client", "y\n")
+	if err == nil || !strings.Contains(err.Error(), "client-alpha, client-beta") || ExitCode(err) != ExitUsage {
+		t.Errorf("ambiguous error = %v, want a usage error listing both", err)
+	}
+	if got, err := resolve("nothing", ""); err != nil || got != "nothing" {
+		t.Errorf("unmatched = %q, %v; want the input back", got, err)
+	}
+}
+
+func TestResolveProfileName_Number(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	for _, name := range []string{"zeta", "alpha", "_backup_20260101_000000", "7"} {
+		if err := os.MkdirAll(vault.ProfilePath("codex", name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	profiles, _ := vault.List("codex")
+	cmd := &cobra.Command{}
+
+	// System profiles aren't numbered: 1=7, 2=alpha, 3=zeta.
+	if got, err := resolveProfileName(cmd, "codex", "2", profiles, true); err != nil || got != "alpha" {
+		t.Errorf("#2 = %q, %v; want alpha", got, err)
+	}
+	if got, err := resolveProfileName(cmd, "codex", "7", profiles, true); err != nil || got != "7" {
+		t.Errorf("a profile named 7 = %q, %v; want it to win over the number", got, err)
+	}
+	if _, err := resolveProfileName(cmd, "codex", "4", profiles, true); err == nil || ExitCode(err) != ExitUsage {
+		t.Errorf("#4 error = %v, want a usage error", err)
+	}
+	if n := profileIndexes("codex")["zeta"]; n != 3 {
+		t.Errorf("ls index of zeta = %d, want 3", n)
+	}
+}
+
+func TestRootArgs(t *testing.T) {
+	if err := rootArgs(rootCmd, []string{"2"}); err != nil {
+		t.Errorf("rootArgs(2) = %v, want the quick shortcut allowed", err)
+	}
+	if err := rootArgs(rootCmd, []string{"activte"}); err == nil || !strings.Contains(err.Error(), "unknown command") {
+		t.Errorf("rootArgs(activte) = %v, want unknown command", err)
+	}
+}
+
+func TestResolveProfileByEmail(t *testing.T) {
+	setupAccountsTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "home", "bob@example.com")
+	writeClaudeVaultProfile(t, "home-copy", "bob@example.com")
+
+	name, err := resolveProfileByEmail("claude", "ALICE@example.com")
+	if err != nil || name != "work" {
+		t.Fatalf("resolveProfileByEmail(alice) = %q, %v; want work", name, err)
+	}
+
+	_, err = resolveProfileByEmail("claude", "bob@example.com")
+	if err == nil || ExitCode(err) != ExitUsage {
+		t.Fatalf("resolveProfileByEmail(bob) error = %v, want a usage error", err)
+	}
+	if !strings.Contains(err.Error(), "home, home-copy") {
+		t.Errorf("ambiguous error should list the profiles, got %q", err)
+	}
+
+	_, err = resolveProfileByEmail("claude", "carol@example.com")
+	if err == nil || ExitCode(err) != ExitAuthMissing {
+		t.Fatalf("resolveProfileByEmail(carol) error = %v, want auth missing", err)
+	}
+}
+
+func TestActivate_EmailConflictsWithName(t *testing.T) {
+	t.Cleanup(func() { _ = activateCmd.Flags().Set("email", "") })
+	if err := activateCmd.Flags().Set("email", "alice@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	err := runActivate(activateCmd, []string{"claude", "work"})
+	if err == nil || ExitCode(err) != ExitUsage {
+		t.Fatalf("runActivate() error = %v, want a usage error", err)
+	}
+}
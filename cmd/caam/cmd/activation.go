@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+var activationModeCmd = &cobra.Command{
+	Use:   "activation-mode [copy|symlink]",
+	Short: "Show or change how profiles are activated",
+	Long: `Shows or changes how 'caam activate' puts a profile's auth files in place.
+
+  copy     Copy the vault files over the live auth paths (default)
+  symlink  Make the live auth paths symlinks into the vault profile. Switches
+           are O(1), and token refreshes the tool writes through the link are
+           captured in the vault automatically.
+
+Without an argument, shows the current mode and whether each tool's live auth
+files are linked into the vault.
+
+Changing the mode saves runtime.activation_mode and migrates the live files:
+switching to symlink re-links each tool's active profile; switching to copy
+replaces links with regular copies. Logins that aren't backed up in the vault
+are never touched.
+
+Note: tools that rewrite their auth file by replacing it (rather than writing
+through it) turn the link back into a regular file; 'caam activation-mode'
+reports those as copies.
+
+Examples:
+  caam activation-mode
+  caam activation-mode symlink
+  caam activation-mode copy`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runActivationMode,
+}
+
+func init() {
+	rootCmd.AddCommand(activationModeCmd)
+}
+
+// applySystemVault makes the shared vault the vault for this invocation when
+// --system is set, so an administrator can save profiles that every user on
+// the host can activate. Its profiles are saved group-readable.
+func applySystemVault(cmd *cobra.Command) bool {
+	system, _ := cmd.Flags().GetBool("system")
+	if !system {
+		return false
+	}
+	vault = authfile.NewVault(authfile.DefaultSharedVaultPath())
+	authfile.ApplyVaultSettings(vault)
+	vault.SetShareable(true)
+	return true
+}
+
+// warnForeignOwnership warns about paths owned by another user, typically
+// left behind by running caam with sudo or by a HOME shared between users:
+// writing to them fails, or mixes that user's credentials with yours.
+func warnForeignOwnership(w io.Writer, paths ...string) {
+	uid := os.Getuid()
+	for _, path := range paths {
+		owner, ok := pathOwner(path)
+		if !ok || owner == uid {
+			continue
+		}
+		fmt.Fprintf(w, "warning: %s is owned by uid %d, not you (uid %d); set CAAM_HOME to a directory you own to keep your profiles separate\n", path, owner, uid)
+	}
+}
+
+func runActivationMode(cmd *cobra.Command, args []string) error {
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+		authfile.ApplyVaultSettings(vault)
+	}
+	out := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Activation mode: %s\n\n", vault.ActivationMode())
+		printActivationStates(out)
+		return nil
+	}
+
+	mode, err := authfile.ParseActivationMode(args[0])
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	spmCfg.Runtime.ActivationMode = string(mode)
+	if err := spmCfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	vault.SetActivationMode(mode)
+	fmt.Fprintf(out, "Activation mode: %s\n", mode)
+
+	for _, tool := range knownTools() {
+		msg, err := migrateActivation(tools[tool](), mode)
+		if err != nil {
+			return fmt.Errorf("migrate %s: %w", tool, err)
+		}
+		if msg != "" {
+			fmt.Fprintf(out, "  %s: %s\n", tool, msg)
+		}
+	}
+	return nil
+}
+
+// migrateActivation converts a tool's live auth files to mode and describes
+// what it did. It returns an empty message when there was nothing to do.
+func migrateActivation(fileSet authfile.AuthFileSet, mode authfile.ActivationMode) (string, error) {
+	if mode == authfile.ActivationCopy {
+		n, err := vault.Unlink(fileSet)
+		if err != nil || n == 0 {
+			return "", err
+		}
+		return fmt.Sprintf("replaced %d link(s) with copies", n), nil
+	}
+
+	if !authfile.HasAuthFiles(fileSet) {
+		return "", nil
+	}
+	active, err := vault.ActiveProfile(fileSet)
+	if err != nil {
+		return "", err
+	}
+	if active == "" {
+		return "current login isn't backed up; left as is (run 'caam backup' first)", nil
+	}
+	for _, state := range vault.LinkStates(fileSet) {
+		if !state.Linked || state.Profile != active || state.Broken {
+			if err := vault.Restore(fileSet, active); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("linked %s into the vault", active), nil
+		}
+	}
+	return "", nil
+}
+
+func printActivationStates(w io.Writer) {
+	for _, tool := range knownTools() {
+		states := vault.LinkStates(tools[tool]())
+		if len(states) == 0 {
+			fmt.Fprintf(w, "%s: no auth files\n", tool)
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", tool)
+		for _, state := range states {
+			switch {
+			case state.Broken:
+				fmt.Fprintf(w, "  %s  broken link\n", state.Path)
+			case state.Linked:
+				fmt.Fprintf(w, "  %s  linked -> %s\n", state.Path, state.Profile)
+			default:
+				fmt.Fprintf(w, "  %s  copy\n", state.Path)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+)
+
+func TestMigrateActivation_RoundTrip(t *testing.T) {
+	setupAccountsTest(t)
+	codexHome := filepath.Join(t.TempDir(), "codex_home")
+	t.Setenv("CODEX_HOME", codexHome)
+	require.NoError(t, os.MkdirAll(codexHome, 0700))
+
+	content := `{"access_token":"work","token_type":"Bearer"}`
+	writeVaultFile(t, "codex", "work", "auth.json", content)
+	authPath := filepath.Join(codexHome, "auth.json")
+	require.NoError(t, os.WriteFile(authPath, []byte(content), 0600))
+
+	fileSet := tools["codex"]()
+
+	vault.SetActivationMode(authfile.ActivationSymlink)
+	msg, err := migrateActivation(fileSet, authfile.ActivationSymlink)
+	require.NoError(t, err)
+	assert.Contains(t, msg, "linked work")
+	states := vault.LinkStates(fileSet)
+	require.Len(t, states, 1)
+	assert.True(t, states[0].Linked)
+
+	// Already linked: nothing to do.
+	msg, err = migrateActivation(fileSet, authfile.ActivationSymlink)
+	require.NoError(t, err)
+	assert.Empty(t, msg)
+
+	vault.SetActivationMode(authfile.ActivationCopy)
+	msg, err = migrateActivation(fileSet, authfile.ActivationCopy)
+	require.NoError(t, err)
+	assert.Contains(t, msg, "replaced 1 link")
+	info, err := os.Lstat(authPath)
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode()&os.ModeSymlink)
+}
+
+func TestMigrateActivation_LeavesUnsavedLogin(t *testing.T) {
+	setupAccountsTest(t)
+	codexHome := filepath.Join(t.TempDir(), "codex_home")
+	t.Setenv("CODEX_HOME", codexHome)
+	require.NoError(t, os.MkdirAll(codexHome, 0700))
+
+	writeVaultFile(t, "codex", "work", "auth.json", `{"access_token":"work"}`)
+	authPath := filepath.Join(codexHome, "auth.json")
+	require.NoError(t, os.WriteFile(authPath, []byte(`{"access_token":"unsaved"}`), 0600))
+
+	msg, err := migrateActivation(tools["codex"](), authfile.ActivationSymlink)
+	require.NoError(t, err)
+	assert.Contains(t, msg, "isn't backed up")
+
+	got, err := os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(got), "unsaved")
+}
+
+func TestApplySystemVault(t *testing.T) {
+	sharedDir := filepath.Join(t.TempDir(), "shared")
+	t.Setenv("CAAM_SHARED_VAULT", sharedDir)
+	oldVault := vault
+	t.Cleanup(func() { vault = oldVault })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("system", false, "")
+	vault = nil
+	assert.False(t, applySystemVault(cmd))
+	assert.Nil(t, vault)
+
+	require.NoError(t, cmd.Flags().Set("system", "true"))
+	assert.True(t, applySystemVault(cmd))
+	require.NotNil(t, vault)
+	assert.Equal(t, sharedDir, vault.BasePath())
+}
+
+func TestWarnForeignOwnership(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no file UIDs on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "caam.db")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	var buf bytes.Buffer
+	warnForeignOwnership(&buf, path, filepath.Join(t.TempDir(), "missing"))
+	assert.Empty(t, buf.String())
+
+	if os.Getuid() != 0 {
+		t.Skip("chown to another user requires root")
+	}
+	require.NoError(t, os.Chown(path, 4242, 4242))
+	warnForeignOwnership(&buf, path)
+	assert.Contains(t, buf.String(), "owned by uid 4242")
+	assert.Contains(t, buf.String(), "CAAM_HOME")
+}
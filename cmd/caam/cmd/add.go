@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,6 +15,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/devicecode"
 	codexprovider "github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/codex"
 )
 
@@ -36,7 +38,7 @@ This command streamlines adding a new account:
 Examples:
   caam add claude              # Interactive - prompts for profile name
   caam add claude work-2       # Pre-specify profile name
-  caam add codex --device-code # Device code flow (headless)
+  caam add codex --device-code # Device code flow (headless), with a QR code
   caam add codex --no-activate # Don't activate after adding
   caam add gemini --timeout 5m # Custom timeout for login flow`,
 	Args: cobra.RangeArgs(1, 2),
@@ -49,6 +51,7 @@ func init() {
 	addCmd.Flags().Duration("timeout", 5*time.Minute, "timeout for login flow completion")
 	addCmd.Flags().Bool("force", false, "skip confirmation prompts")
 	addCmd.Flags().Bool("device-code", false, "use device code flow for codex (headless)")
+	addCmd.Flags().Bool("no-qr", false, "don't show the device code URL as a QR code")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
@@ -57,10 +60,11 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	timeout, _ := cmd.Flags().GetDuration("timeout")
 	force, _ := cmd.Flags().GetBool("force")
 	deviceCode, _ := cmd.Flags().GetBool("device-code")
+	noQR, _ := cmd.Flags().GetBool("no-qr")
 
 	getFileSet, ok := tools[tool]
 	if !ok {
-		return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	// Initialize vault
@@ -91,7 +95,7 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	// Check if auth files currently exist
 	hasExistingAuth := authfile.HasAuthFiles(fileSet)
 
-	if hasExistingAuth && !force {
+	if hasExistingAuth && !force && !assumeYes(cmd) {
 		fmt.Printf("Current %s auth will be backed up and cleared.\n", tool)
 		fmt.Print("Continue? [Y/n]: ")
 		if !promptConfirm(true) {
@@ -121,43 +125,12 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Step 3: Launch login flow
-	fmt.Printf("\nLaunching %s login...\n", tool)
-	fmt.Println("Complete the authentication in the terminal/browser.")
-	fmt.Println("Press Ctrl+C when done or if you want to cancel.")
-	fmt.Println()
-
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	// Handle signals
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	done := make(chan error, 1)
-
-	go func() {
-		done <- runToolLogin(ctx, tool, deviceCode)
-	}()
-
-	select {
-	case err := <-done:
-		signal.Stop(sigChan)
-		if err != nil && ctx.Err() != context.Canceled {
-			// Login process exited - check if auth files appeared
-			if !authfile.HasAuthFiles(fileSet) {
-				fmt.Println("\nLogin process exited but no auth files were created.")
-				fmt.Println("The login may have failed. Try again with: caam add " + tool)
-				return fmt.Errorf("login did not create auth files")
-			}
+	ctx := devicecode.WithQR(context.Background(), !noQR)
+	if err := waitForToolLogin(ctx, tool, fileSet, timeout, deviceCode); err != nil {
+		if errors.Is(err, errLoginNoAuthFiles) {
+			fmt.Println("The login may have failed. Try again with: caam add " + tool)
 		}
-	case <-sigChan:
-		signal.Stop(sigChan)
-		cancel()
-		fmt.Println("\n\nLogin interrupted.")
-	case <-ctx.Done():
-		signal.Stop(sigChan)
-		fmt.Printf("\nTimeout after %v waiting for login to complete.\n", timeout)
-		return fmt.Errorf("login timed out")
+		return err
 	}
 
 	// Step 4: Check if auth files appeared
@@ -201,6 +174,7 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("save profile: %w", err)
 	}
 	fmt.Printf("  Saved %s/%s\n", tool, profileName)
+	printSchemaWarnings(cmd.ErrOrStderr(), tool, profileName)
 
 	// Step 7: Optionally activate
 	if !noActivate {
@@ -220,6 +194,57 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// errLoginNoAuthFiles is returned when the tool's login flow exits without
+// leaving any auth files behind.
+var errLoginNoAuthFiles = errors.New("login did not create auth files")
+
+// waitForToolLogin launches the tool's login flow against the live auth
+// locations and waits until the process exits, the user interrupts it, or the
+// timeout elapses. An interrupt is treated as "done" so that flows which never
+// exit on their own (interactive REPLs) can still be completed. ctx carries
+// the device-code QR preference (devicecode.WithQR).
+func waitForToolLogin(ctx context.Context, tool string, fileSet authfile.AuthFileSet, timeout time.Duration, deviceCode bool) error {
+	fmt.Printf("\nLaunching %s login...\n", tool)
+	fmt.Println("Complete the authentication in the terminal/browser.")
+	fmt.Println("Press Ctrl+C when done or if you want to cancel.")
+	fmt.Println()
+
+	// Create context with timeout
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Handle signals
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	done := make(chan error, 1)
+
+	go func() {
+		done <- runToolLogin(ctx, tool, deviceCode)
+	}()
+
+	select {
+	case err := <-done:
+		signal.Stop(sigChan)
+		if err != nil && ctx.Err() != context.Canceled {
+			// Login process exited - check if auth files appeared
+			if !authfile.HasAuthFiles(fileSet) {
+				fmt.Println("\nLogin process exited but no auth files were created.")
+				return errLoginNoAuthFiles
+			}
+		}
+	case <-sigChan:
+		signal.Stop(sigChan)
+		cancel()
+		fmt.Println("\n\nLogin interrupted.")
+	case <-ctx.Done():
+		signal.Stop(sigChan)
+		fmt.Printf("\nTimeout after %v waiting for login to complete.\n", timeout)
+		return fmt.Errorf("login timed out")
+	}
+
+	return nil
+}
+
 // runToolLogin launches the tool's login command.
 func runToolLogin(ctx context.Context, tool string, deviceCode bool) error {
 	var cmd *exec.Cmd
@@ -247,6 +272,11 @@ func runToolLogin(ctx context.Context, tool string, deviceCode bool) error {
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if tool == "codex" && deviceCode {
+		watcher := devicecode.ForTerminal(ctx)
+		cmd.Stdout = watcher.Wrap(os.Stdout)
+		cmd.Stderr = watcher.Wrap(os.Stderr)
+	}
 
 	return cmd.Run()
 }
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
+)
+
+var addTokenCmd = &cobra.Command{
+	Use:   "add-token <tool> <profile>",
+	Short: "Save a profile from raw OAuth tokens",
+	Long: `Creates a vault profile from raw token values instead of a login flow,
+writing them in the tool's own auth file format:
+
+  claude  .credentials.json (claudeAiOauth)
+  codex   auth.json (tokens)
+  gemini  settings.json + oauth_credentials.json
+
+The synthesized files are parsed back with the same code caam uses for
+health checks before the profile is saved, so malformed or already-expired
+tokens are rejected.
+
+Each token can also be passed through an environment variable, which keeps
+it out of the process list and shell history:
+
+  CAAM_ACCESS_TOKEN, CAAM_REFRESH_TOKEN, CAAM_ID_TOKEN, CAAM_TOKEN_EXPIRES
+
+--expires accepts an RFC3339 timestamp, unix seconds or milliseconds, or a
+duration from now such as 8h or 1d.
+
+Examples:
+  caam add-token claude ci --access-token "$TOKEN" --refresh-token "$REFRESH" --expires 8h
+  CAAM_REFRESH_TOKEN=... caam add-token codex work
+  caam add-token gemini bot --refresh-token "$REFRESH" --force`,
+	Args: cobra.ExactArgs(2),
+	RunE: runAddToken,
+}
+
+func init() {
+	rootCmd.AddCommand(addTokenCmd)
+	addTokenCmd.Flags().String("access-token", "", "OAuth access token (or $CAAM_ACCESS_TOKEN)")
+	addTokenCmd.Flags().String("refresh-token", "", "OAuth refresh token (or $CAAM_REFRESH_TOKEN)")
+	addTokenCmd.Flags().String("id-token", "", "OpenID token, codex only (or $CAAM_ID_TOKEN)")
+	addTokenCmd.Flags().String("expires", "", "access token expiry: RFC3339, unix time or duration from now (or $CAAM_TOKEN_EXPIRES)")
+	addTokenCmd.Flags().Bool("force", false, "overwrite an existing profile")
+}
+
+// rawTokens are the token values add-token writes into a tool's auth files.
+type rawTokens struct {
+	AccessToken  string
+	RefreshToken string
+	IDToken      string
+	ExpiresAt    time.Time
+}
+
+func runAddToken(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	profile := args[1]
+
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s", tool))
+	}
+
+	flagOrEnv := func(name, env string) string {
+		v, _ := cmd.Flags().GetString(name)
+		if v == "" {
+			v = os.Getenv(env)
+		}
+		return strings.TrimSpace(v)
+	}
+
+	now := time.Now()
+	tok := rawTokens{
+		AccessToken:  flagOrEnv("access-token", "CAAM_ACCESS_TOKEN"),
+		RefreshToken: flagOrEnv("refresh-token", "CAAM_REFRESH_TOKEN"),
+		IDToken:      flagOrEnv("id-token", "CAAM_ID_TOKEN"),
+	}
+	if tok.AccessToken == "" && tok.RefreshToken == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("an access or refresh token is required (--access-token, --refresh-token or $CAAM_ACCESS_TOKEN, $CAAM_REFRESH_TOKEN)"))
+	}
+	if tok.IDToken != "" && tool != "codex" {
+		return withExitCode(ExitUsage, fmt.Errorf("--id-token is only supported for codex"))
+	}
+	if expires := flagOrEnv("expires", "CAAM_TOKEN_EXPIRES"); expires != "" {
+		t, err := parseTokenExpiry(expires, now)
+		if err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+		tok.ExpiresAt = t
+	}
+
+	// Catch tools add-token can't write before looking at the vault.
+	if _, err := synthesizeAuthFiles(tool, tok, now); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	if !force && vaultHasProfile(tool, profile) {
+		return fmt.Errorf("profile %s/%s already exists (use --force to overwrite)", tool, profile)
+	}
+
+	info, err := saveTokenProfile(tool, profile, tok, now)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Saved %s/%s from raw tokens\n", tool, profile)
+	if info != nil && !info.ExpiresAt.IsZero() {
+		fmt.Fprintf(out, "  Token expires: %s (%s)\n", formatZoned(info.ExpiresAt, displayLocation()), formatExpiry(info.ExpiresAt))
+	}
+	if tok.RefreshToken == "" {
+		fmt.Fprintln(out, "  No refresh token: caam cannot renew this profile once the access token expires.")
+	}
+	fmt.Fprintf(out, "Activate with: caam activate %s %s\n", tool, profile)
+	return nil
+}
+
+// saveTokenProfile writes tok into tool's auth file format, validates the
+// result and saves it as the vault profile.
+func saveTokenProfile(tool, profile string, tok rawTokens, now time.Time) (*health.ExpiryInfo, error) {
+	files, err := synthesizeAuthFiles(tool, tok, now)
+	if err != nil {
+		return nil, withExitCode(ExitUsage, err)
+	}
+
+	stage, err := os.MkdirTemp("", "caam-add-token-")
+	if err != nil {
+		return nil, fmt.Errorf("create staging dir: %w", err)
+	}
+	defer os.RemoveAll(stage)
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(stage, name), data, 0600); err != nil {
+			return nil, fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+
+	info, err := validateSynthesizedAuth(tool, stage, tok, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Back up the staged files as if they were the tool's live auth files;
+	// files the tool keeps that add-token doesn't write are left out.
+	fileSet := tools[tool]()
+	var specs []authfile.AuthFileSpec
+	for _, spec := range fileSet.Files {
+		name := filepath.Base(spec.Path)
+		if _, ok := files[name]; !ok {
+			continue
+		}
+		spec.Path = filepath.Join(stage, name)
+		specs = append(specs, spec)
+	}
+	fileSet.Files = specs
+	fileSet.ConfigFiles = nil
+
+	if err := vault.Backup(fileSet, profile); err != nil {
+		return nil, fmt.Errorf("save profile: %w", err)
+	}
+	return info, nil
+}
+
+// parseTokenExpiry parses s as an RFC3339 timestamp, unix seconds or
+// milliseconds, or a duration from now.
+func parseTokenExpiry(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		if n > 1e12 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	if d, err := durations.Parse(s); err == nil && d > 0 {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --expires %q (use RFC3339, unix time or a duration like 8h)", s)
+}
+
+// synthesizeAuthFiles renders tok in tool's auth file format, keyed by file
+// name.
+func synthesizeAuthFiles(tool string, tok rawTokens, now time.Time) (map[string][]byte, error) {
+	// setIf adds the non-empty token values, so files never carry "" tokens.
+	setIf := func(m map[string]any, key, value string) {
+		if value != "" {
+			m[key] = value
+		}
+	}
+
+	files := make(map[string]any)
+	switch tool {
+	case "claude":
+		oauth := map[string]any{
+			"scopes": []string{"user:inference", "user:profile"},
+		}
+		setIf(oauth, "accessToken", tok.AccessToken)
+		setIf(oauth, "refreshToken", tok.RefreshToken)
+		if !tok.ExpiresAt.IsZero() {
+			oauth["expiresAt"] = tok.ExpiresAt.UnixMilli()
+		}
+		files[".credentials.json"] = map[string]any{"claudeAiOauth": oauth}
+	case "codex":
+		tokens := map[string]any{}
+		setIf(tokens, "id_token", tok.IDToken)
+		setIf(tokens, "access_token", tok.AccessToken)
+		setIf(tokens, "refresh_token", tok.RefreshToken)
+		if !tok.ExpiresAt.IsZero() {
+			tokens["expires_at"] = tok.ExpiresAt.Unix()
+		}
+		files["auth.json"] = map[string]any{
+			"OPENAI_API_KEY": nil,
+			"tokens":         tokens,
+			"last_refresh":   now.UTC().Format(time.RFC3339),
+		}
+	case "gemini":
+		creds := map[string]any{"token_type": "Bearer"}
+		setIf(creds, "access_token", tok.AccessToken)
+		setIf(creds, "refresh_token", tok.RefreshToken)
+		if !tok.ExpiresAt.IsZero() {
+			creds["expiry"] = tok.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		files["settings.json"] = map[string]any{"selectedAuthType": "oauth-personal"}
+		files["oauth_credentials.json"] = creds
+	default:
+		return nil, fmt.Errorf("add-token does not support %s (supported: claude, codex, gemini)", tool)
+	}
+
+	rendered := make(map[string][]byte, len(files))
+	for name, v := range files {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal %s: %w", name, err)
+		}
+		rendered[name] = append(data, '\n')
+	}
+	return rendered, nil
+}
+
+// validateSynthesizedAuth parses the auth files staged in dir back with the
+// health parsers and checks they carry tok's expiry and haven't expired. For
+// codex, an ID token must also yield an identity.
+func validateSynthesizedAuth(tool, dir string, tok rawTokens, now time.Time) (*health.ExpiryInfo, error) {
+	info, err := expiryInfoAt(tool, dir)
+	switch {
+	case err == nil:
+	case errors.Is(err, health.ErrNoExpiry) && tok.ExpiresAt.IsZero():
+		info = nil
+	default:
+		return nil, fmt.Errorf("synthesized %s auth failed validation: %w", tool, err)
+	}
+
+	if !tok.ExpiresAt.IsZero() {
+		if info == nil || info.ExpiresAt.Sub(tok.ExpiresAt).Abs() > time.Second {
+			return nil, fmt.Errorf("synthesized %s auth failed validation: expiry not recorded", tool)
+		}
+		if !info.ExpiresAt.After(now) && tok.RefreshToken == "" {
+			return nil, fmt.Errorf("access token expired %s and there is no refresh token", info.ExpiresAt.Local().Format(time.RFC3339))
+		}
+	}
+
+	if tool == "codex" && tok.IDToken != "" {
+		if _, err := identity.ExtractFromCodexAuth(filepath.Join(dir, "auth.json")); err != nil {
+			return nil, fmt.Errorf("invalid --id-token: %w", err)
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAddTokenTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("access-token", "", "")
+	cmd.Flags().String("refresh-token", "", "")
+	cmd.Flags().String("id-token", "", "")
+	cmd.Flags().String("expires", "", "")
+	cmd.Flags().Bool("force", false, "")
+	cmd.SetOut(&strings.Builder{})
+	return cmd
+}
+
+func TestAddToken_SavesParseableProfiles(t *testing.T) {
+	setupAccountsTest(t)
+
+	expires := time.Now().Add(8 * time.Hour).Truncate(time.Second)
+	for _, tool := range []string{"claude", "codex", "gemini"} {
+		t.Run(tool, func(t *testing.T) {
+			cmd := newAddTokenTestCmd()
+			require.NoError(t, cmd.Flags().Set("access-token", "access-"+tool))
+			require.NoError(t, cmd.Flags().Set("refresh-token", "refresh-"+tool))
+			require.NoError(t, cmd.Flags().Set("expires", expires.Format(time.RFC3339)))
+
+			require.NoError(t, runAddToken(cmd, []string{tool, "ci"}))
+
+			info, err := loadExpiryInfo(tool, "ci")
+			require.NoError(t, err)
+			assert.True(t, info.ExpiresAt.Equal(expires), "expiry = %v, want %v", info.ExpiresAt, expires)
+			assert.True(t, info.HasRefreshToken)
+		})
+	}
+
+	_, err := os.Stat(filepath.Join(vault.ProfilePath("codex", "ci"), "config.toml"))
+	assert.True(t, os.IsNotExist(err), "add-token should only save the synthesized files")
+}
+
+func TestAddToken_ReadsEnvironment(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("CAAM_REFRESH_TOKEN", "refresh-from-env")
+
+	require.NoError(t, runAddToken(newAddTokenTestCmd(), []string{"claude", "env"}))
+
+	data, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", "env"), ".credentials.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "refresh-from-env")
+}
+
+func TestAddToken_Rejects(t *testing.T) {
+	setupAccountsTest(t)
+
+	cmd := newAddTokenTestCmd()
+	assert.Error(t, runAddToken(cmd, []string{"claude", "none"}), "no tokens")
+
+	cmd = newAddTokenTestCmd()
+	require.NoError(t, cmd.Flags().Set("access-token", "tok"))
+	require.NoError(t, cmd.Flags().Set("expires", "-1h"))
+	assert.Error(t, runAddToken(cmd, []string{"claude", "bad-expiry"}), "invalid --expires")
+
+	cmd = newAddTokenTestCmd()
+	require.NoError(t, cmd.Flags().Set("access-token", "tok"))
+	require.NoError(t, cmd.Flags().Set("expires", "1000000000"))
+	err := runAddToken(cmd, []string{"claude", "expired"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expired")
+
+	cmd = newAddTokenTestCmd()
+	require.NoError(t, cmd.Flags().Set("access-token", "tok"))
+	require.NoError(t, cmd.Flags().Set("id-token", "not-a-jwt"))
+	assert.Error(t, runAddToken(cmd, []string{"codex", "bad-id"}), "malformed id token")
+
+	cmd = newAddTokenTestCmd()
+	require.NoError(t, cmd.Flags().Set("access-token", "tok"))
+	require.NoError(t, runAddToken(cmd, []string{"claude", "dup"}))
+	err = runAddToken(cmd, []string{"claude", "dup"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+	require.NoError(t, cmd.Flags().Set("force", "true"))
+	assert.NoError(t, runAddToken(cmd, []string{"claude", "dup"}))
+}
+
+func TestParseTokenExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"2026-02-01T00:00:00Z", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"1767322800", time.Unix(1767322800, 0)},
+		{"1767322800000", time.UnixMilli(1767322800000)},
+		{"8h", now.Add(8 * time.Hour)},
+	}
+	for _, tt := range tests {
+		got, err := parseTokenExpiry(tt.in, now)
+		require.NoError(t, err, tt.in)
+		assert.True(t, got.Equal(tt.want), "%s: got %v, want %v", tt.in, got, tt.want)
+	}
+
+	_, err := parseTokenExpiry("tomorrow", now)
+	assert.Error(t, err)
+}
@@ -6,9 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/agent"
@@ -55,6 +53,7 @@ Examples:
 var (
 	agentPort          int
 	agentCoordinator   string
+	agentCoordToken    string
 	agentAccounts      []string
 	agentStrategy      string
 	agentChromeProfile string
@@ -69,6 +68,8 @@ func init() {
 	agentCmd.Flags().IntVar(&agentPort, "port", 7891, "HTTP server port")
 	agentCmd.Flags().StringVar(&agentCoordinator, "coordinator", "http://localhost:7890",
 		"Coordinator URL (via SSH tunnel)")
+	agentCmd.Flags().StringVar(&agentCoordToken, "coordinator-token", "",
+		"API token for the coordinator, activate scope (or $CAAM_COORDINATOR_TOKEN)")
 	agentCmd.Flags().StringSliceVar(&agentAccounts, "accounts", nil,
 		"Google account emails for rotation (comma-separated)")
 	agentCmd.Flags().StringVar(&agentStrategy, "strategy", "lru",
@@ -79,6 +80,8 @@ func init() {
 		"Run Chrome in headless mode (may not work with Google OAuth)")
 	agentCmd.Flags().BoolVar(&agentVerbose, "verbose", false, "Verbose output")
 	agentCmd.Flags().StringVar(&agentConfigPath, "config", "", "Path to JSON config file")
+	addPIDFileFlag(agentCmd)
+	registerValueCompletion(agentCmd, "strategy", "lru", "round_robin", "random")
 }
 
 func runAgent(cmd *cobra.Command, args []string) error {
@@ -105,6 +108,9 @@ func runAgent(cmd *cobra.Command, args []string) error {
 	config := agent.DefaultConfig()
 	config.Port = agentPort
 	config.CoordinatorURL = agentCoordinator
+	if !cmd.Flags().Changed("coordinator") && activeContext != nil && activeContext.CoordinatorURL != "" {
+		config.CoordinatorURL = activeContext.CoordinatorURL
+	}
 	config.PollInterval = 2 * time.Second
 	config.ChromeUserDataDir = agentChromeProfile
 	config.Headless = agentHeadless
@@ -164,6 +170,17 @@ func runAgentFromConfig(cmd *cobra.Command, logger *slog.Logger, path string) er
 }
 
 func runSingleAgent(cmd *cobra.Command, logger *slog.Logger, config agent.Config, strategy string, accounts []string, chromeProfile string) error {
+	if agentCoordToken != "" {
+		config.CoordinatorToken = agentCoordToken
+	} else if config.CoordinatorToken == "" {
+		config.CoordinatorToken = os.Getenv("CAAM_COORDINATOR_TOKEN")
+	}
+	apiTokens, err := configuredAPITokens()
+	if err != nil {
+		return err
+	}
+	config.APITokens = apiTokens
+
 	// Create agent
 	ag := agent.New(config)
 
@@ -191,18 +208,17 @@ func runSingleAgent(cmd *cobra.Command, logger *slog.Logger, config agent.Config
 			err)
 	}
 
-	// Start agent
-	ctx, cancel := context.WithCancel(cmd.Context())
-	defer cancel()
+	// Start agent; ctx is canceled on SIGINT/SIGTERM
+	ctx, finish, err := startLongRunning(cmd)
+	if err != nil {
+		return err
+	}
+	defer finish()
 
 	if err := ag.Start(ctx); err != nil {
 		return fmt.Errorf("start agent: %w", err)
 	}
 
-	// Handle signals
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
 	fmt.Printf("Auth agent started\n")
 	fmt.Printf("  API: http://localhost:%d\n", config.Port)
 	fmt.Printf("  Coordinator: %s\n", config.CoordinatorURL)
@@ -216,12 +232,9 @@ func runSingleAgent(cmd *cobra.Command, logger *slog.Logger, config agent.Config
 	fmt.Println("\nWaiting for auth requests...")
 	fmt.Println("Press Ctrl+C to stop.")
 
-	// Wait for signal
-	select {
-	case <-sigCh:
-		fmt.Println("\nShutting down...")
-	case <-ctx.Done():
-	}
+	// Wait for a shutdown signal
+	<-ctx.Done()
+	fmt.Println("\nShutting down...")
 
 	// Graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -236,6 +249,11 @@ func runSingleAgent(cmd *cobra.Command, logger *slog.Logger, config agent.Config
 }
 
 func runMultiAgent(cmd *cobra.Command, logger *slog.Logger, config agent.MultiConfig) error {
+	apiTokens, err := configuredAPITokens()
+	if err != nil {
+		return err
+	}
+	config.APITokens = apiTokens
 	ma := agent.NewMulti(config)
 
 	ma.OnAuthStart = func(coord, url, account string) {
@@ -257,16 +275,16 @@ func runMultiAgent(cmd *cobra.Command, logger *slog.Logger, config agent.MultiCo
 			time.Now().Format("15:04:05"), coord, account, err)
 	}
 
-	ctx, cancel := context.WithCancel(cmd.Context())
-	defer cancel()
+	ctx, finish, err := startLongRunning(cmd)
+	if err != nil {
+		return err
+	}
+	defer finish()
 
 	if err := ma.Start(ctx); err != nil {
 		return fmt.Errorf("start agent: %w", err)
 	}
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
 	fmt.Printf("Auth agent started (multi-coordinator)\n")
 	fmt.Printf("  API: http://localhost:%d\n", config.Port)
 	fmt.Printf("  Coordinators: %d\n", len(config.Coordinators))
@@ -280,11 +298,8 @@ func runMultiAgent(cmd *cobra.Command, logger *slog.Logger, config agent.MultiCo
 	fmt.Println("\nWaiting for auth requests...")
 	fmt.Println("Press Ctrl+C to stop.")
 
-	select {
-	case <-sigCh:
-		fmt.Println("\nShutting down...")
-	case <-ctx.Done():
-	}
+	<-ctx.Done()
+	fmt.Println("\nShutting down...")
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
@@ -301,6 +316,7 @@ type agentFileConfig struct {
 	Port             int                          `json:"port"`
 	CoordinatorURL   string                       `json:"coordinator_url"`
 	Coordinator      string                       `json:"coordinator"`
+	CoordinatorToken string                       `json:"coordinator_token"`
 	PollInterval     string                       `json:"poll_interval"`
 	ChromeProfile    string                       `json:"chrome_profile"`
 	Headless         bool                         `json:"headless"`
@@ -367,6 +383,7 @@ func loadAgentConfig(path string) (bool, agent.Config, agent.MultiConfig, error)
 	}
 	cfg.Accounts = raw.Accounts
 	cfg.CoordinatorURL = firstNonEmpty(raw.CoordinatorURL, raw.Coordinator)
+	cfg.CoordinatorToken = raw.CoordinatorToken
 
 	return false, cfg, agent.MultiConfig{}, nil
 }
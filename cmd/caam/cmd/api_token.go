@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+var apiTokenCmd = &cobra.Command{
+	Use:   "api-token",
+	Short: "Manage scoped tokens for the coordinator and agent APIs",
+	Long: `Creates and revokes the bearer tokens caam's HTTP APIs accept
+('caam auth-coordinator' and 'caam auth-agent'). Once any token exists, every
+request must carry one ("Authorization: Bearer <token>") with the scope the
+endpoint needs:
+
+  read      status, pending logins and pane listings
+  activate  read, plus completing or driving a login
+  admin     everything
+
+Give each client its own token with the least scope it needs, so a
+compromised process can at most read status. Requests are logged with the
+token's name. Only a hash of each token is stored in the config; the token
+itself is shown once, when it is created.
+
+The agent sends its token to the coordinator with --coordinator-token or
+$CAAM_COORDINATOR_TOKEN (or the "token" field of a coordinator in its
+config file).
+
+Examples:
+  caam api-token add laptop-agent --scope activate
+  caam api-token add dashboard --scope read
+  caam api-token ls
+  caam api-token rm dashboard`,
+}
+
+var apiTokenAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a token and print it once",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAPITokenAdd,
+}
+
+var apiTokenLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List tokens and their scopes",
+	Args:    cobra.NoArgs,
+	RunE:    runAPITokenLs,
+}
+
+var apiTokenRmCmd = &cobra.Command{
+	Use:     "rm <name>",
+	Aliases: []string{"revoke"},
+	Short:   "Revoke a token",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runAPITokenRm,
+}
+
+func init() {
+	rootCmd.AddCommand(apiTokenCmd)
+	apiTokenCmd.AddCommand(apiTokenAddCmd, apiTokenLsCmd, apiTokenRmCmd)
+	apiTokenAddCmd.Flags().String("scope", string(apitoken.ScopeRead), "read, activate or admin")
+	apiTokenLsCmd.Flags().Bool("json", false, "output as JSON")
+	registerValueCompletion(apiTokenAddCmd, "scope", "read", "activate", "admin")
+}
+
+func runAPITokenAdd(cmd *cobra.Command, args []string) error {
+	name := strings.TrimSpace(args[0])
+	if name == "" {
+		return withExitCode(ExitUsage, fmt.Errorf("token name is required"))
+	}
+	scopeFlag, _ := cmd.Flags().GetString("scope")
+	scope, err := apitoken.ParseScope(scopeFlag)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	secret, tok, err := apitoken.Generate(name, scope, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := cfg.AddAPIToken(tok); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Created API token %q (scope %s). It is shown only once:\n\n  %s\n\n", name, scope, secret)
+	fmt.Fprintln(out, "Restart running coordinators and agents for it to take effect.")
+	return nil
+}
+
+func runAPITokenLs(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	out := cmd.OutOrStdout()
+
+	if jsonOutput {
+		type tokenInfo struct {
+			Name      string         `json:"name"`
+			Scope     apitoken.Scope `json:"scope"`
+			CreatedAt time.Time      `json:"created_at"`
+		}
+		infos := make([]tokenInfo, 0, len(cfg.APITokens))
+		for _, t := range cfg.APITokens {
+			infos = append(infos, tokenInfo{Name: t.Name, Scope: t.Scope, CreatedAt: t.CreatedAt})
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(infos)
+	}
+
+	if len(cfg.APITokens) == 0 {
+		fmt.Fprintln(out, "No API tokens; the coordinator and agent APIs accept unauthenticated requests.")
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSCOPE\tCREATED")
+	for _, t := range cfg.APITokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", t.Name, t.Scope, formatZoned(t.CreatedAt, displayLocation()))
+	}
+	return w.Flush()
+}
+
+func runAPITokenRm(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if !cfg.RemoveAPIToken(args[0]) {
+		return withExitCode(ExitUsage, fmt.Errorf("no API token named %q", args[0]))
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Revoked API token %q\n", args[0])
+	if len(cfg.APITokens) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No tokens left: the APIs accept unauthenticated requests again.")
+	}
+	return nil
+}
+
+// configuredAPITokens returns the tokens the coordinator and agent APIs
+// should require, warning on stderr when there are none. A config that can't
+// be loaded is an error rather than "no tokens", so a typo in it can't turn
+// authentication off.
+func configuredAPITokens() ([]apitoken.Token, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load API tokens: %w (fix the config; refusing to serve the API without them)", err)
+	}
+	if len(cfg.APITokens) == 0 {
+		fmt.Fprintln(os.Stderr, "Warning: no API tokens configured; the API accepts unauthenticated requests (see 'caam api-token add').")
+		return nil, nil
+	}
+	return cfg.APITokens, nil
+}
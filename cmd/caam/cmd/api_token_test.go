@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+func TestAPIToken_AddListRemove(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	add := func(name, scope string) (string, error) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("scope", scope, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		err := runAPITokenAdd(cmd, []string{name})
+		return out.String(), err
+	}
+
+	out, err := add("dashboard", "read")
+	require.NoError(t, err)
+	secret := regexp.MustCompile(`caam_[A-Za-z0-9_-]+`).FindString(out)
+	require.NotEmpty(t, secret, out)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Len(t, cfg.APITokens, 1)
+	assert.NotContains(t, cfg.APITokens[0].SHA256, secret, "only the hash is stored")
+	tok, ok := apitoken.Match(cfg.APITokens, secret)
+	require.True(t, ok)
+	assert.Equal(t, apitoken.ScopeRead, tok.Scope)
+
+	_, err = add("dashboard", "admin")
+	assert.Error(t, err, "duplicate names are rejected")
+	_, err = add("ops", "root")
+	assert.Equal(t, ExitUsage, ExitCode(err))
+
+	ls := &cobra.Command{}
+	ls.Flags().Bool("json", true, "")
+	var listed bytes.Buffer
+	ls.SetOut(&listed)
+	require.NoError(t, runAPITokenLs(ls, nil))
+	var infos []map[string]any
+	require.NoError(t, json.Unmarshal(listed.Bytes(), &infos))
+	require.Len(t, infos, 1)
+	assert.Equal(t, "dashboard", infos[0]["name"])
+	assert.NotContains(t, listed.String(), cfg.APITokens[0].SHA256)
+
+	rm := &cobra.Command{}
+	rm.SetOut(&bytes.Buffer{})
+	require.NoError(t, runAPITokenRm(rm, []string{"dashboard"}))
+	assert.Error(t, runAPITokenRm(rm, []string{"dashboard"}))
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.APITokens)
+}
+
+func TestConfiguredAPITokens_BrokenConfigFailsClosed(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tokens, err := configuredAPITokens()
+	require.NoError(t, err, "no config at all means no tokens")
+	assert.Nil(t, tokens)
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(config.ConfigPath()), 0700))
+	require.NoError(t, os.WriteFile(config.ConfigPath(), []byte(`{"api_tokens": [`), 0600))
+	_, err = configuredAPITokens()
+	require.Error(t, err, "a config that doesn't parse must not disable authentication")
+	assert.Contains(t, err.Error(), "refusing to serve")
+}
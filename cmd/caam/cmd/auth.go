@@ -56,8 +56,9 @@ var authCmd = &cobra.Command{
 	Long: `Commands for managing authentication credentials.
 
 Subcommands:
-  detect  - Detect existing auth files in system locations
-  import  - Import detected auth into a caam profile`,
+  detect        - Detect existing auth files in system locations
+  import        - Import detected auth into a caam profile
+  from-browser  - Log in through the browser without the vendor CLI (experimental)`,
 }
 
 var authDetectCmd = &cobra.Command{
@@ -90,7 +91,7 @@ This is useful for first-run experience to discover and import existing credenti
 			tool := strings.ToLower(args[0])
 			p, ok := registry.Get(tool)
 			if !ok {
-				return fmt.Errorf("unknown tool: %s (supported: claude, codex, gemini)", tool)
+				return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 			}
 			providersToCheck = append(providersToCheck, p)
 		} else {
@@ -379,7 +380,7 @@ func runAuthImport(cmd *cobra.Command, args []string) error {
 	// Validate provider
 	prov, ok := registry.Get(tool)
 	if !ok {
-		return fmt.Errorf("unknown tool: %s (supported: claude, codex, gemini)", tool)
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	// Check if profile exists
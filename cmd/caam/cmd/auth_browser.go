@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browser"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browserauth"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+var authFromBrowserCmd = &cobra.Command{
+	Use:   "from-browser <tool>",
+	Short: "Log in through the browser and save the tokens as a profile (experimental)",
+	Long: `Onboards an account without launching the vendor CLI: caam opens the
+provider's OAuth consent page in your browser, catches the redirect on a
+localhost listener and writes the tokens into a vault profile in the tool's
+own auth file format.
+
+The browser is the one configured for the profile ('caam profile add
+--browser'), else the one chosen in 'caam init', else the system default.
+Use --print-url to open the link yourself, for example in another browser
+profile.
+
+Experimental: only codex is supported, on its registered redirect
+http://localhost:1455/auth/callback, so the port must be free (quit any
+running 'codex login' first).
+
+Examples:
+  caam auth from-browser codex -n work
+  caam auth from-browser codex -n work --force
+  caam auth from-browser codex --print-url`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAuthFromBrowser,
+}
+
+func init() {
+	authCmd.AddCommand(authFromBrowserCmd)
+	authFromBrowserCmd.Flags().StringP("name", "n", "default", "profile name")
+	authFromBrowserCmd.Flags().Bool("force", false, "overwrite existing profile")
+	authFromBrowserCmd.Flags().Bool("print-url", false, "print the login URL instead of opening a browser")
+	authFromBrowserCmd.Flags().Duration("timeout", 5*time.Minute, "how long to wait for the browser login")
+}
+
+// browserLogin runs the OAuth flow. Tests replace it.
+var browserLogin = browserauth.Login
+
+func runAuthFromBrowser(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	name, _ := cmd.Flags().GetString("name")
+	force, _ := cmd.Flags().GetBool("force")
+	printURL, _ := cmd.Flags().GetBool("print-url")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	out := cmd.OutOrStdout()
+
+	if _, ok := tools[tool]; !ok {
+		return withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s", tool))
+	}
+	spec, ok := browserauth.SpecFor(tool)
+	if !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("browser login is not supported for %s yet (supported: %s); use 'caam add %s' instead",
+			tool, strings.Join(browserauth.Supported(), ", "), tool))
+	}
+	if !force && vaultHasProfile(tool, name) {
+		return fmt.Errorf("profile %s/%s already exists (use --force to overwrite)", tool, name)
+	}
+
+	open := func(authURL string) error {
+		if printURL {
+			fmt.Fprintf(out, "Open this URL to log in to %s:\n\n  %s\n\n", tool, authURL)
+			return nil
+		}
+		launcher := authBrowserLauncher(tool, name)
+		fmt.Fprintf(out, "Opening %s login in %s\n", tool, launcher.Name())
+		fmt.Fprintf(out, "  If it doesn't open, visit: %s\n", authURL)
+		if err := launcher.Open(authURL); err != nil {
+			return fmt.Errorf("open browser: %w", err)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	fmt.Fprintf(out, "Waiting for the browser login (up to %s)...\n", formatDurationShort(timeout))
+	tokens, err := browserLogin(ctx, spec, open)
+	if err != nil {
+		return fmt.Errorf("browser login: %w", err)
+	}
+
+	now := time.Now()
+	tok := rawTokens{
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		IDToken:      tokens.IDToken,
+		ExpiresAt:    tokens.ExpiresAt(now),
+	}
+	info, err := saveTokenProfile(tool, name, tok, now)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Saved %s/%s from the browser login\n", tool, name)
+	if info != nil && !info.ExpiresAt.IsZero() {
+		fmt.Fprintf(out, "  Token expires: %s (%s)\n", formatZoned(info.ExpiresAt, displayLocation()), formatExpiry(info.ExpiresAt))
+	}
+	fmt.Fprintf(out, "Activate with: caam activate %s %s\n", tool, name)
+	return nil
+}
+
+// authBrowserLauncher picks the browser for name's login: its isolated
+// profile's browser config, else the global one from 'caam init'.
+func authBrowserLauncher(tool, name string) browser.Launcher {
+	if prof, _ := loadOpenProfile(tool, name, false); prof != nil && prof.HasBrowserConfig() {
+		return browser.NewLauncher(&browser.Config{
+			Command:    prof.BrowserCommand,
+			ProfileDir: prof.BrowserProfileDir,
+		})
+	}
+	if cfg, err := config.Load(); err == nil && cfg.BrowserCommand != "" {
+		return browser.NewLauncher(&browser.Config{
+			Command:    cfg.BrowserCommand,
+			ProfileDir: cfg.BrowserProfileDir,
+		})
+	}
+	return &browser.DefaultLauncher{}
+}
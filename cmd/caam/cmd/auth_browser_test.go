@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browserauth"
+)
+
+func newAuthFromBrowserTestCmd(name string) (*cobra.Command, *strings.Builder) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("name", "n", name, "")
+	cmd.Flags().Bool("force", false, "")
+	cmd.Flags().Bool("print-url", true, "")
+	cmd.Flags().Duration("timeout", time.Minute, "")
+	var out strings.Builder
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+// stubBrowserLogin makes the browser login hand back tokens, or err.
+func stubBrowserLogin(t *testing.T, tokens *browserauth.Tokens, err error) {
+	t.Helper()
+	original := browserLogin
+	t.Cleanup(func() { browserLogin = original })
+	browserLogin = func(ctx context.Context, spec browserauth.Spec, open func(string) error) (*browserauth.Tokens, error) {
+		if err := open(spec.AuthorizeURL + "?client_id=" + spec.ClientID); err != nil {
+			return nil, err
+		}
+		return tokens, err
+	}
+}
+
+func TestAuthFromBrowser_SavesCodexProfile(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+	stubBrowserLogin(t, &browserauth.Tokens{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresIn: 3600}, nil)
+
+	cmd, out := newAuthFromBrowserTestCmd("work")
+	require.NoError(t, runAuthFromBrowser(cmd, []string{"codex"}))
+	assert.Contains(t, out.String(), "https://auth.openai.com/oauth/authorize?client_id=")
+	assert.Contains(t, out.String(), "Saved codex/work")
+
+	data, err := os.ReadFile(filepath.Join(vault.ProfilePath("codex", "work"), "auth.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"refresh_token": "refresh-1"`)
+
+	info, err := loadExpiryInfo("codex", "work")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), info.ExpiresAt, time.Minute)
+
+	// The profile now exists, so a second login needs --force.
+	cmd, _ = newAuthFromBrowserTestCmd("work")
+	err = runAuthFromBrowser(cmd, []string{"codex"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--force")
+}
+
+func TestAuthFromBrowser_Rejects(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+	stubBrowserLogin(t, nil, browserauth.ErrDenied)
+
+	cmd, _ := newAuthFromBrowserTestCmd("work")
+	err := runAuthFromBrowser(cmd, []string{"claude"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	assert.Contains(t, err.Error(), "not supported")
+
+	cmd, _ = newAuthFromBrowserTestCmd("work")
+	err = runAuthFromBrowser(cmd, []string{"nope"})
+	require.Error(t, err)
+	assert.Equal(t, "INVALID_PROVIDER", errorCode(err))
+
+	cmd, _ = newAuthFromBrowserTestCmd("work")
+	err = runAuthFromBrowser(cmd, []string{"codex"})
+	assert.True(t, errors.Is(err, browserauth.ErrDenied), "err = %v", err)
+	assert.False(t, vaultHasProfile("codex", "work"))
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
+	osexec "os/exec"
+	"runtime"
 	"strings"
 
 	"io"
@@ -57,7 +60,9 @@ The bundle includes a manifest with checksums for integrity verification.
 
 Encryption:
   Use -e/--encrypt to protect the bundle with AES-256-GCM encryption.
-  The password can be provided via --password or will be prompted interactively.
+  The password can be provided via --password, printed by the command set in
+  runtime.passphrase_command (e.g. "op read op://vault/caam/password"), or
+  will be prompted interactively.
   Encrypted bundles have .enc.zip extension and require the password to import.
 
 Filtering:
@@ -130,25 +135,9 @@ func runBundleExport(cmd *cobra.Command, args []string) error {
 	password, _ := cmd.Flags().GetString("password")
 
 	if opts.Encrypt {
-		if password == "" {
-			// Prompt for password
-			var err error
-			password, err = promptPassword("Enter encryption password: ")
-			if err != nil {
-				return fmt.Errorf("read password: %w", err)
-			}
-			if password == "" {
-				return fmt.Errorf("password cannot be empty when encryption is enabled")
-			}
-
-			// Confirm password
-			confirm, err := promptPassword("Confirm password: ")
-			if err != nil {
-				return fmt.Errorf("read password confirmation: %w", err)
-			}
-			if password != confirm {
-				return fmt.Errorf("passwords do not match")
-			}
+		password, err := encryptionPassword(password)
+		if err != nil {
+			return err
 		}
 		opts.Password = password
 	}
@@ -270,15 +259,75 @@ func printContentStatus(out io.Writer, name string, content bundle.OptionalConte
 	}
 }
 
-// promptPassword reads a password from the terminal without echo.
+// passphraseFromCommand returns the bundle passphrase printed by
+// runtime.passphrase_command, or "" when none is configured. The command runs
+// through the shell with the terminal attached so password managers can
+// prompt; its output is never echoed or included in errors.
+func passphraseFromCommand() (string, error) {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil || strings.TrimSpace(spmCfg.Runtime.PassphraseCommand) == "" {
+		return "", nil
+	}
+
+	var c *osexec.Cmd
+	if runtime.GOOS == "windows" {
+		c = osexec.Command("cmd", "/C", spmCfg.Runtime.PassphraseCommand)
+	} else {
+		c = osexec.Command("sh", "-c", spmCfg.Runtime.PassphraseCommand)
+	}
+	var stdout bytes.Buffer
+	c.Stdin = os.Stdin
+	c.Stdout = &stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("runtime.passphrase_command failed: %w", err)
+	}
+
+	password := strings.TrimRight(stdout.String(), "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("runtime.passphrase_command printed an empty passphrase")
+	}
+	return password, nil
+}
+
+// encryptionPassword returns the password to encrypt with: the given one,
+// else runtime.passphrase_command, else one prompted for twice.
+func encryptionPassword(password string) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+	password, err := passphraseFromCommand()
+	if err != nil || password != "" {
+		return password, err
+	}
+
+	password, err = promptPassword("Enter encryption password: ")
+	if err != nil {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	if password == "" {
+		return "", fmt.Errorf("password cannot be empty when encryption is enabled")
+	}
+	confirm, err := promptPassword("Confirm password: ")
+	if err != nil {
+		return "", fmt.Errorf("read password confirmation: %w", err)
+	}
+	if password != confirm {
+		return "", fmt.Errorf("passwords do not match")
+	}
+	return password, nil
+}
+
+// promptPassword reads a password from the terminal without echo. The
+// prompt goes to stderr so it can't end up in piped output.
 func promptPassword(prompt string) (string, error) {
-	fmt.Print(prompt)
+	fmt.Fprint(os.Stderr, prompt)
 
 	// Check if stdin is a terminal
 	if term.IsTerminal(int(os.Stdin.Fd())) {
 		// Read without echo
 		password, err := term.ReadPassword(int(os.Stdin.Fd()))
-		fmt.Println() // Add newline after password input
+		fmt.Fprintln(os.Stderr) // Add newline after password input
 		if err != nil {
 			return "", err
 		}
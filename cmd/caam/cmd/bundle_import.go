@@ -36,9 +36,27 @@ Import Modes:
     - Overwrites all matching profiles from bundle
     - Does NOT delete local profiles not in bundle
 
+Rollback:
+  Before changing anything, profiles the import will add or overwrite are
+  snapshotted and an import ID is printed. 'caam import --rollback <id>'
+  reverts them if the imported tokens turn out to be stale or wrong.
+  Config, health and database files are not part of the snapshot.
+
+Resume:
+  Each profile is journaled as it lands in the vault. If an import stops
+  partway (disk full, a bad file), 'caam import --resume' continues it,
+  skipping profiles whose imported files still match their checksums.
+
+Path Remapping:
+  Auth files that embed absolute paths (Claude's apiKeyHelper and project
+  keys, Codex's trusted projects, Gemini's credentials path) are rewritten
+  from the exporting machine's home, XDG config and CODEX_HOME directories to
+  this machine's. Use --no-remap-paths to keep them as exported.
+
 Encrypted Bundles:
   Bundles with .enc.zip extension require a password.
-  Provide via --password or you will be prompted.
+  Provide via --password or runtime.passphrase_command, or you will be
+  prompted.
 
 Examples:
   caam bundle import ~/backup.zip                    # Smart import
@@ -70,6 +88,7 @@ func init() {
 	bundleImportCmd.Flags().Bool("skip-health", false, "Don't import health metadata")
 	bundleImportCmd.Flags().Bool("skip-database", false, "Don't import activity database")
 	bundleImportCmd.Flags().Bool("skip-sync", false, "Don't import sync configuration")
+	bundleImportCmd.Flags().Bool("no-remap-paths", false, "Keep absolute paths in auth files as they were on the exporting machine")
 
 	// Filtering
 	bundleImportCmd.Flags().StringSlice("providers", nil, "Only import specific providers (claude,codex,gemini)")
@@ -100,28 +119,16 @@ func runBundleImport(cmd *cobra.Command, args []string) error {
 
 	// Password
 	password, _ := cmd.Flags().GetString("password")
-
-	// Check if encrypted and prompt for password if needed
-	encrypted, err := bundle.IsEncrypted(bundlePath)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("check encryption: %w", err)
-	}
-
-	if encrypted && password == "" {
-		var err error
-		password, err = promptPassword("Enter decryption password: ")
-		if err != nil {
-			return fmt.Errorf("read password: %w", err)
-		}
-		if password == "" {
-			return fmt.Errorf("password required for encrypted bundle")
-		}
+	password, err := bundlePassword(bundlePath, password)
+	if err != nil {
+		return err
 	}
 	opts.Password = password
 
 	// Preview/control
 	opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
 	opts.Force, _ = cmd.Flags().GetBool("force")
+	opts.Force = opts.Force || assumeYes(cmd)
 
 	// Optional content exclusion
 	opts.SkipConfig, _ = cmd.Flags().GetBool("skip-config")
@@ -129,19 +136,13 @@ func runBundleImport(cmd *cobra.Command, args []string) error {
 	opts.SkipHealth, _ = cmd.Flags().GetBool("skip-health")
 	opts.SkipDatabase, _ = cmd.Flags().GetBool("skip-database")
 	opts.SkipSync, _ = cmd.Flags().GetBool("skip-sync")
+	opts.SkipPathRemap, _ = cmd.Flags().GetBool("no-remap-paths")
 
 	// Filtering
 	opts.ProviderFilter, _ = cmd.Flags().GetStringSlice("providers")
 	opts.ProfileFilter, _ = cmd.Flags().GetStringSlice("profiles")
 
-	// Set paths
-	vaultPath := authfile.DefaultVaultPath()
-	opts.VaultPath = vaultPath
-	opts.ConfigPath = config.ConfigPath()
-	opts.ProjectsPath = project.DefaultPath()
-	opts.HealthPath = health.DefaultHealthPath()
-	opts.DatabasePath = caamdb.DefaultPath()
-	opts.SyncPath = syncstate.SyncDataDir()
+	setImportPaths(opts)
 
 	// Create importer
 	importer := &bundle.VaultImporter{
@@ -155,6 +156,10 @@ func runBundleImport(cmd *cobra.Command, args []string) error {
 		if result != nil && opts.DryRun {
 			printImportPreview(cmd, result)
 		}
+		if result != nil && result.TransactionID != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Continue with 'caam import --resume %s' or undo with 'caam import --rollback %s'.\n",
+				result.TransactionID, result.TransactionID)
+		}
 		return fmt.Errorf("import failed: %w", err)
 	}
 
@@ -168,6 +173,43 @@ func runBundleImport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// bundlePassword returns the password for the bundle at path: the given
+// one, else runtime.passphrase_command, else a prompt. Unencrypted bundles
+// need none.
+func bundlePassword(path, password string) (string, error) {
+	encrypted, err := bundle.IsEncrypted(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("check encryption: %w", err)
+	}
+	if !encrypted || password != "" {
+		return password, nil
+	}
+
+	if password, err = passphraseFromCommand(); err != nil {
+		return "", err
+	}
+	if password == "" {
+		password, err = promptPassword("Enter decryption password: ")
+		if err != nil {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		if password == "" {
+			return "", fmt.Errorf("password required for encrypted bundle")
+		}
+	}
+	return password, nil
+}
+
+// setImportPaths points opts at this machine's caam data.
+func setImportPaths(opts *bundle.ImportOptions) {
+	opts.VaultPath = authfile.DefaultVaultPath()
+	opts.ConfigPath = config.ConfigPath()
+	opts.ProjectsPath = project.DefaultPath()
+	opts.HealthPath = health.DefaultHealthPath()
+	opts.DatabasePath = caamdb.DefaultPath()
+	opts.SyncPath = syncstate.SyncDataDir()
+}
+
 func printImportPreview(cmd *cobra.Command, result *bundle.ImportResult) {
 	out := cmd.OutOrStdout()
 
@@ -299,6 +341,15 @@ func printImportResult(cmd *cobra.Command, result *bundle.ImportResult) {
 		}
 	}
 
+	// Remapped paths
+	if len(result.RemappedFiles) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Remapped Paths:")
+		for _, file := range result.RemappedFiles {
+			fmt.Fprintf(out, "  ✓ %s\n", file)
+		}
+	}
+
 	// Errors
 	if len(result.Errors) > 0 {
 		fmt.Fprintln(out)
@@ -315,7 +366,14 @@ func printImportResult(cmd *cobra.Command, result *bundle.ImportResult) {
 	}
 
 	fmt.Fprintln(out)
-	fmt.Fprintln(out, "Import complete. Use 'caam status' to verify.")
+	if len(result.Errors) > 0 && result.TransactionID != "" {
+		fmt.Fprintf(out, "Import incomplete. Fix the errors above, then run 'caam import --resume %s'.\n", result.TransactionID)
+	} else {
+		fmt.Fprintln(out, "Import complete. Use 'caam status' to verify.")
+	}
+	if result.TransactionID != "" {
+		fmt.Fprintf(out, "Import ID: %s (undo with 'caam import --rollback %s')\n", result.TransactionID, result.TransactionID)
+	}
 }
 
 // promptPasswordImport reads a password from the terminal for import.
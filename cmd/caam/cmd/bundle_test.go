@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+func setPassphraseCommand(t *testing.T, command string) {
+	t.Helper()
+	t.Setenv("CAAM_HOME", t.TempDir())
+	spmCfg := config.DefaultSPMConfig()
+	spmCfg.Runtime.PassphraseCommand = command
+	if err := spmCfg.Save(); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+}
+
+func TestPassphraseFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell command")
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		setPassphraseCommand(t, "")
+		got, err := passphraseFromCommand()
+		if err != nil || got != "" {
+			t.Errorf("passphraseFromCommand() = %q, %v; want empty", got, err)
+		}
+	})
+
+	t.Run("prints passphrase", func(t *testing.T) {
+		setPassphraseCommand(t, "printf 's3cret pass\\n'")
+		got, err := passphraseFromCommand()
+		if err != nil {
+			t.Fatalf("passphraseFromCommand() error = %v", err)
+		}
+		if got != "s3cret pass" {
+			t.Errorf("passphraseFromCommand() = %q, want %q", got, "s3cret pass")
+		}
+	})
+
+	t.Run("failure hides output", func(t *testing.T) {
+		setPassphraseCommand(t, "echo leaked-secret; exit 3")
+		_, err := passphraseFromCommand()
+		if err == nil {
+			t.Fatal("expected error for failing command")
+		}
+		if strings.Contains(err.Error(), "leaked-secret") {
+			t.Errorf("error %q includes the command's output", err)
+		}
+	})
+
+	t.Run("empty output", func(t *testing.T) {
+		setPassphraseCommand(t, "true")
+		if _, err := passphraseFromCommand(); err == nil {
+			t.Error("expected error for empty passphrase")
+		}
+	})
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+var classCmd = &cobra.Command{
+	Use:   "class [tool] [profile] [work|personal]",
+	Short: "Mark profiles as work or personal",
+	Long: `Mark profiles with a policy class so they can't be used where they don't
+belong. Project directories require a class with 'caam project class'.
+
+Activating a profile of a different class inside such a directory - a
+personal account on employer code, say - asks for confirmation first, and
+is refused when caam can't ask (no terminal, or --json) unless --yes is
+given. Rotation ('caam next', 'caam activate --auto') only picks profiles
+the directory allows. Unmarked profiles are not restricted.
+
+Examples:
+  caam class claude me@gmail.com personal
+  caam class claude alice@corp.com work
+  caam class claude me@gmail.com           # Show the profile's class
+  caam class claude me@gmail.com --clear   # Unmark the profile
+  caam class --list`,
+	Args: cobra.MaximumNArgs(3),
+	RunE: runClass,
+}
+
+func init() {
+	rootCmd.AddCommand(classCmd)
+	classCmd.Flags().Bool("list", false, "list all marked profiles")
+	classCmd.Flags().Bool("clear", false, "remove the profile's class")
+	classCmd.Flags().Bool("json", false, "output in JSON format")
+}
+
+func runClass(cmd *cobra.Command, args []string) error {
+	listFlag, _ := cmd.Flags().GetBool("list")
+	clearFlag, _ := cmd.Flags().GetBool("clear")
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+
+	c, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	out := cmd.OutOrStdout()
+
+	if listFlag {
+		if jsonFlag {
+			classes := c.ProfileClasses
+			if classes == nil {
+				classes = map[string]string{}
+			}
+			data, _ := json.MarshalIndent(classes, "", "  ")
+			fmt.Fprintln(out, string(data))
+			return nil
+		}
+		if len(c.ProfileClasses) == 0 {
+			fmt.Fprintln(out, "No profile classes set.")
+			return nil
+		}
+		keys := make([]string, 0, len(c.ProfileClasses))
+		for key := range c.ProfileClasses {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(out, "  %-40s %s\n", key, c.ProfileClasses[key])
+		}
+		return nil
+	}
+
+	if len(args) < 2 {
+		return withExitCode(ExitUsage, fmt.Errorf("usage: caam class <tool> <profile> [work|personal]"))
+	}
+	tool := strings.ToLower(args[0])
+	profile := args[1]
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+	if clearFlag && len(args) == 3 {
+		return withExitCode(ExitUsage, fmt.Errorf("use either a class or --clear, not both"))
+	}
+
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+	if !vaultHasProfile(tool, profile) {
+		return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found", tool, profile))
+	}
+
+	class := c.GetProfileClass(tool, profile)
+	switch {
+	case len(args) == 3:
+		class = strings.ToLower(strings.TrimSpace(args[2]))
+		if err := config.ValidateProfileClass(class); err != nil {
+			return withExitCode(ExitUsage, err)
+		}
+		c.SetProfileClass(tool, profile, class)
+	case clearFlag:
+		class = ""
+		c.SetProfileClass(tool, profile, "")
+	}
+	if len(args) == 3 || clearFlag {
+		if err := c.Save(); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+	}
+
+	if jsonFlag {
+		data, _ := json.MarshalIndent(map[string]string{
+			"tool":    tool,
+			"profile": profile,
+			"class":   class,
+		}, "", "  ")
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	switch {
+	case len(args) == 3:
+		fmt.Fprintf(out, "[OK] Marked %s/%s as %s\n", tool, profile, class)
+	case clearFlag:
+		fmt.Fprintf(out, "[OK] Cleared the class of %s/%s\n", tool, profile)
+	case class == "":
+		fmt.Fprintf(out, "%s/%s has no class\n", tool, profile)
+	default:
+		fmt.Fprintf(out, "%s/%s: %s\n", tool, profile, class)
+	}
+	return nil
+}
+
+// requiredClassHere returns the profile class the current directory
+// requires and the project path requiring it, or "" if unrestricted.
+func requiredClassHere() (class, source string) {
+	if projectStore == nil {
+		return "", ""
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", ""
+	}
+	class, source, err = projectStore.RequiredClass(cwd)
+	if err != nil {
+		return "", ""
+	}
+	return class, source
+}
+
+// classAllows reports whether a profile of class profileClass may be used
+// where required is required. Unmarked profiles and directories are not
+// restricted.
+func classAllows(required, profileClass string) bool {
+	return required == "" || profileClass == "" || profileClass == required
+}
+
+// filterProfilesForClass drops the profiles the current directory's class
+// rules out, returning the rest and the class required.
+func filterProfilesForClass(tool string, profiles []string) ([]string, string) {
+	required, _ := requiredClassHere()
+	if required == "" {
+		return profiles, ""
+	}
+	c, err := config.Load()
+	if err != nil {
+		return profiles, required
+	}
+	allowed := make([]string, 0, len(profiles))
+	for _, p := range profiles {
+		if classAllows(required, c.GetProfileClass(tool, p)) {
+			allowed = append(allowed, p)
+		}
+	}
+	return allowed, required
+}
+
+// confirmProfileClass enforces the current directory's class rules before
+// tool/profile is activated. A mismatch needs --yes or, when interactive,
+// a confirmation; otherwise it is refused. It returns false if the user
+// declined.
+func confirmProfileClass(cmd *cobra.Command, tool, profile string, interactive bool) (bool, error) {
+	required, source := requiredClassHere()
+	if required == "" {
+		return true, nil
+	}
+	c, err := config.Load()
+	if err != nil {
+		return true, nil
+	}
+	profileClass := c.GetProfileClass(tool, profile)
+	if classAllows(required, profileClass) {
+		return true, nil
+	}
+
+	mismatch := fmt.Errorf("%s/%s is a %s profile, but %s requires %s profiles", tool, profile, profileClass, shortenHomePath(source), required)
+	if assumeYes(cmd) {
+		if interactive {
+			fmt.Fprintf(cmd.OutOrStdout(), "Warning: %v; proceeding (--yes)\n", mismatch)
+		}
+		return true, nil
+	}
+	if !interactive {
+		return false, withExitCode(ExitClassMismatch, fmt.Errorf("%w; re-run with --yes to activate anyway", mismatch))
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Warning: %v\n", mismatch)
+	return confirmProceed(cmd.InOrStdin(), cmd.OutOrStdout())
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+)
+
+func TestProfileClassGuardRails(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+	for _, profile := range []string{"me", "corp", "shared"} {
+		dir := vault.ProfilePath("codex", profile)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"`+profile+`"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	oldStore := projectStore
+	projectStore = project.NewStore(filepath.Join(tmpDir, "projects.json"))
+	t.Cleanup(func() { projectStore = oldStore })
+
+	repo := filepath.Join(tmpDir, "employer", "api")
+	if err := os.MkdirAll(repo, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	classFlags := func() *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("list", false, "")
+		cmd.Flags().Bool("clear", false, "")
+		cmd.Flags().Bool("json", false, "")
+		return cmd
+	}
+	if err := runClass(classFlags(), []string{"codex", "me", "personal"}); err != nil {
+		t.Fatalf("class personal error = %v", err)
+	}
+	if err := runClass(classFlags(), []string{"codex", "corp", "work"}); err != nil {
+		t.Fatalf("class work error = %v", err)
+	}
+	if err := runClass(classFlags(), []string{"codex", "corp", "secret"}); ExitCode(err) != ExitUsage {
+		t.Errorf("invalid class: err = %v, want usage error", err)
+	}
+	if err := runClass(classFlags(), []string{"codex", "missing", "work"}); ExitCode(err) != ExitAuthMissing {
+		t.Errorf("missing profile: err = %v, want auth-missing error", err)
+	}
+	c, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.GetProfileClass("codex", "me"); got != "personal" {
+		t.Fatalf("class of codex/me = %q, want personal", got)
+	}
+
+	// Requirements apply below the directory they are set on.
+	t.Chdir(filepath.Dir(repo))
+	if err := projectClassCmd.RunE(projectClassCmd, []string{"work"}); err != nil {
+		t.Fatalf("project class error = %v", err)
+	}
+	t.Chdir(repo)
+
+	allowed, required := filterProfilesForClass("codex", []string{"corp", "me", "shared"})
+	if required != "work" || strings.Join(allowed, ",") != "corp,shared" {
+		t.Errorf("filterProfilesForClass() = %v (%s), want corp,shared for work", allowed, required)
+	}
+
+	activateFlags := func(yes bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("yes", yes, "")
+		cmd.Flags().Bool("json", false, "")
+		return cmd
+	}
+	err = runActivate(activateFlags(false), []string{"codex", "me"})
+	if ExitCode(err) != ExitClassMismatch || !strings.Contains(err.Error(), "requires work profiles") {
+		t.Fatalf("activating a personal profile in a work directory: err = %v, want class mismatch", err)
+	}
+	if active, _ := vault.ActiveProfile(authfile.CodexAuthFiles()); active == "me" {
+		t.Fatal("refused activation should not switch profiles")
+	}
+
+	if err := runActivate(activateFlags(false), []string{"codex", "shared"}); err != nil {
+		t.Errorf("unmarked profiles are not restricted: err = %v", err)
+	}
+	if err := runActivate(activateFlags(true), []string{"codex", "me"}); err != nil {
+		t.Errorf("--yes should override the class check: err = %v", err)
+	}
+}
@@ -70,6 +70,8 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 			fmt.Println("Dry run - would delete:")
 			fmt.Printf("  Activity logs older than %d days: %d entries\n", cfg.RetentionDays, result.ActivityLogsDeleted)
 			fmt.Printf("  Stale profile stats (>%d days inactive): %d entries\n", cfg.AggregateRetentionDays, result.StatsEntriesDeleted)
+			fmt.Printf("  Cooldowns expired over %d days ago: %d entries\n", cfg.RetentionDays, result.CooldownsDeleted)
+			fmt.Printf("  Exec history older than %d days: %d entries\n", cfg.RetentionDays, result.ExecHistoryDeleted)
 			if result.VacuumRan {
 				fmt.Println("  Would run VACUUM to reclaim space")
 			}
@@ -87,6 +89,8 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 		fmt.Println("Cleanup complete:")
 		fmt.Printf("  Activity logs deleted: %d\n", result.ActivityLogsDeleted)
 		fmt.Printf("  Profile stats deleted: %d\n", result.StatsEntriesDeleted)
+		fmt.Printf("  Expired cooldowns deleted: %d\n", result.CooldownsDeleted)
+		fmt.Printf("  Exec history deleted: %d\n", result.ExecHistoryDeleted)
 		if result.VacuumRan {
 			fmt.Println("  VACUUM ran to reclaim space")
 		}
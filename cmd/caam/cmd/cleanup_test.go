@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -193,6 +194,7 @@ func TestEnvCmd_Flags(t *testing.T) {
 	}{
 		{"unset", "false"},
 		{"export-prefix", "export"},
+		{"shell", "bash"},
 		{"fish", "false"},
 	}
 
@@ -232,3 +234,30 @@ func TestEnvCmd_Args(t *testing.T) {
 		t.Error("Expected error for 3 args")
 	}
 }
+
+func TestEnvLines(t *testing.T) {
+	vars := map[string]string{"HOME": "/tmp/it's here", "CODEX_HOME": "/tmp/codex"}
+
+	tests := []struct {
+		shell string
+		unset bool
+		want  []string
+	}{
+		{"bash", false, []string{"export CODEX_HOME=/tmp/codex", `export HOME='/tmp/it'"'"'s here'`}},
+		{"bash", true, []string{"unset CODEX_HOME", "unset HOME"}},
+		{"fish", false, []string{"set -gx CODEX_HOME /tmp/codex", `set -gx HOME '/tmp/it\'s here'`}},
+		{"fish", true, []string{"set -e CODEX_HOME", "set -e HOME"}},
+		{"powershell", false, []string{"$env:CODEX_HOME = '/tmp/codex'", "$env:HOME = '/tmp/it''s here'"}},
+		{"powershell", true, []string{
+			"Remove-Item Env:CODEX_HOME -ErrorAction SilentlyContinue",
+			"Remove-Item Env:HOME -ErrorAction SilentlyContinue",
+		}},
+	}
+
+	for _, tt := range tests {
+		got := envLines(vars, tt.shell, "export", tt.unset)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("envLines(%s, unset=%v) = %q, want %q", tt.shell, tt.unset, got, tt.want)
+		}
+	}
+}
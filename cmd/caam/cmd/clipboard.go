@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	osexec "os/exec"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/bundle"
+)
+
+// clipboardTool is a command that reads or writes the system clipboard.
+type clipboardTool struct {
+	args []string
+	// env, when set, must be non-empty for the tool to work (a display
+	// server it talks to).
+	env string
+}
+
+var (
+	clipboardReaders = []clipboardTool{
+		{args: []string{"pbpaste"}},
+		{args: []string{"wl-paste", "--no-newline"}, env: "WAYLAND_DISPLAY"},
+		{args: []string{"xclip", "-selection", "clipboard", "-o"}, env: "DISPLAY"},
+		{args: []string{"xsel", "--clipboard", "--output"}, env: "DISPLAY"},
+		{args: []string{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard"}},
+	}
+	clipboardWriters = []clipboardTool{
+		{args: []string{"pbcopy"}},
+		{args: []string{"wl-copy"}, env: "WAYLAND_DISPLAY"},
+		{args: []string{"xclip", "-selection", "clipboard"}, env: "DISPLAY"},
+		{args: []string{"xsel", "--clipboard", "--input"}, env: "DISPLAY"},
+		{args: []string{"clip.exe"}},
+	}
+)
+
+// findClipboardTool returns the first usable tool, or an error naming the
+// ones caam looked for.
+func findClipboardTool(candidates []clipboardTool) ([]string, error) {
+	var names []string
+	for _, c := range candidates {
+		names = append(names, c.args[0])
+		if c.env != "" && os.Getenv(c.env) == "" {
+			continue
+		}
+		if _, err := osexec.LookPath(c.args[0]); err == nil {
+			return c.args, nil
+		}
+	}
+	return nil, fmt.Errorf("no clipboard tool found (tried %v)", names)
+}
+
+// readClipboard returns the clipboard's text, at most bundle.MaxArmorSize
+// bytes of it. Tests replace it.
+var readClipboard = func() ([]byte, error) {
+	args, err := findClipboardTool(clipboardReaders)
+	if err != nil {
+		return nil, err
+	}
+	out, err := osexec.Command(args[0], args[1:]...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("read clipboard with %s: %w", args[0], err)
+	}
+	if len(out) > bundle.MaxArmorSize {
+		return nil, fmt.Errorf("clipboard holds %d bytes, more than an armored profile can be", len(out))
+	}
+	return out, nil
+}
+
+// writeClipboard replaces the clipboard's text. Tests replace it.
+var writeClipboard = func(text []byte) error {
+	args, err := findClipboardTool(clipboardWriters)
+	if err != nil {
+		return err
+	}
+	c := osexec.Command(args[0], args[1:]...)
+	c.Stdin = bytes.NewReader(text)
+	c.Stdout = io.Discard
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("write clipboard with %s: %w", args[0], err)
+	}
+	return nil
+}
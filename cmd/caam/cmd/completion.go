@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
+)
+
+// rotationAlgorithms are the values accepted by --algorithm and --strategy
+// flags that pick a rotation algorithm.
+var rotationAlgorithms = []string{"smart", "round_robin", "random"}
+
+// completeValues returns a flag completion function offering values and no
+// file names.
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// registerDurationCompletion offers example durations for each named flag of
+// cmd, which must already be defined.
+func registerDurationCompletion(cmd *cobra.Command, names ...string) {
+	for _, name := range names {
+		_ = cmd.RegisterFlagCompletionFunc(name, completeValues(durations.Suggestions...))
+	}
+}
+
+// registerValueCompletion offers values for cmd's flag name, which must
+// already be defined.
+func registerValueCompletion(cmd *cobra.Command, name string, values ...string) {
+	_ = cmd.RegisterFlagCompletionFunc(name, completeValues(values...))
+}
+
+// completeAccountEmails offers the account emails of the tool named by the
+// first argument, for --email flags.
+func completeAccountEmails(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	tool := strings.ToLower(args[0])
+	if _, ok := tools[tool]; !ok {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	reg, _ := buildAccountRegistry([]string{tool})
+	var emails []string
+	for _, acct := range reg.Accounts() {
+		if strings.HasPrefix(strings.ToLower(acct.Email), strings.ToLower(toComplete)) {
+			emails = append(emails, acct.Email)
+		}
+	}
+	return emails, cobra.ShellCompDirectiveNoFileComp
+}
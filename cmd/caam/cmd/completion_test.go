@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFlagCompletion_DurationAndStrategyValues(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"__complete", "cooldown", "set", "claude/work", "--for", ""}, "friday 9am"},
+		{[]string{"__complete", "wait", "claude", "--max", ""}, "1d"},
+		{[]string{"__complete", "next", "claude", "--algorithm", ""}, "round_robin"},
+		{[]string{"__complete", "run", "claude", "--cooldown", ""}, "90m"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args[1:3], " "), func(t *testing.T) {
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(tt.args)
+			defer rootCmd.SetArgs(nil)
+			defer rootCmd.SetOut(nil)
+
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("completion error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("completions = %q, want %q offered", out.String(), tt.want)
+			}
+		})
+	}
+}
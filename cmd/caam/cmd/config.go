@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	"gopkg.in/yaml.v3"
 )
@@ -27,6 +28,8 @@ Examples:
   caam config set health.refresh_threshold 5m   # Set value
   caam config reset                             # Reset to defaults`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		applyQuiet(cmd)
+
 		// Load SPM config
 		var err error
 		spmConfig, err = config.LoadSPMConfig()
@@ -79,15 +82,30 @@ Available keys:
   health.warning_threshold            Health warning threshold (duration)
   health.penalty_decay_rate           Penalty decay rate (0-1)
   health.penalty_decay_interval       Penalty decay interval (duration)
+  health.stale_login_days            Warn when a login is unverified this long (days, 0 = off)
   analytics.enabled                   Analytics enabled (bool)
   analytics.retention_days            Detailed log retention (int)
   analytics.aggregate_retention_days  Aggregate retention (int)
   analytics.cleanup_on_startup        Cleanup on startup (bool)
+  analytics.exec_history              Record 'caam exec' arguments (off, hash, truncate)
+  analytics.exec_history_chars        Characters kept in truncate mode (int)
   runtime.file_watching               File watching enabled (bool)
   runtime.reload_on_sighup            Reload on SIGHUP (bool)
   runtime.pid_file                    PID file enabled (bool)
+  runtime.activation_mode             How activate places auth files (copy, symlink)
+  runtime.config_policy               How activate treats config files (preserve, profile)
+  runtime.durability                  Vault write durability (safe, fast)
+  runtime.storage                     Where vault file contents live (file, keychain)
+  runtime.passphrase_command          Command printing the bundle passphrase
   project.enabled                     Project associations enabled (bool)
   project.auto_activate               Auto-activate by CWD (bool)
+  pacing.max_sessions_per_hour        'caam exec' sessions per profile per hour (int, 0 = no cap)
+  pacing.min_gap                      Least time between sessions on a profile (duration)
+  pacing.when_limited                 What a paced exec does (wait, rotate, fail)
+  pacing.max_wait                     Longest a paced exec waits (duration, 0 = no limit)
+  display.timezone                    Zone for expiry/cooldown times, next to UTC (local, UTC, Europe/Berlin)
+  display.terminal_title              Label the terminal/tmux window with tool:profile (bool)
+  daemon.watch_config                 Daemon applies config file edits live (bool)
 
 Examples:
   caam config get health.refresh_threshold
@@ -154,14 +172,9 @@ Examples:
   caam config reset`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Reset configuration to defaults? [y/N]: ")
-			var confirm string
-			fmt.Scanln(&confirm)
-			if strings.ToLower(confirm) != "y" {
-				fmt.Println("Cancelled")
-				return nil
-			}
+		if !force && !confirmPrompt(cmd, false, "Reset configuration to defaults?") {
+			fmt.Println("Cancelled")
+			return nil
 		}
 
 		spmConfig = config.DefaultSPMConfig()
@@ -239,6 +252,10 @@ func getConfigValue(cfg *config.SPMConfig, key string) (string, error) {
 		return getHandoffValue(&cfg.Handoff, field)
 	case "daemon":
 		return getDaemonValue(&cfg.Daemon, field)
+	case "display":
+		return getDisplayValue(&cfg.Display, field)
+	case "pacing":
+		return getPacingValue(&cfg.Pacing, field)
 	default:
 		return "", fmt.Errorf("unknown section: %s", section)
 	}
@@ -254,6 +271,8 @@ func getHealthValue(h *config.HealthConfig, field string) (string, error) {
 		return fmt.Sprintf("%.2f", h.PenaltyDecayRate), nil
 	case "penalty_decay_interval":
 		return h.PenaltyDecayInterval.String(), nil
+	case "stale_login_days":
+		return strconv.Itoa(h.StaleLoginDays), nil
 	default:
 		return "", fmt.Errorf("unknown health field: %s", field)
 	}
@@ -269,6 +288,13 @@ func getAnalyticsValue(a *config.AnalyticsConfig, field string) (string, error)
 		return strconv.Itoa(a.AggregateRetentionDays), nil
 	case "cleanup_on_startup":
 		return strconv.FormatBool(a.CleanupOnStartup), nil
+	case "exec_history":
+		if a.ExecHistory == "" {
+			return config.ExecHistoryOff, nil
+		}
+		return a.ExecHistory, nil
+	case "exec_history_chars":
+		return strconv.Itoa(a.ExecHistoryChars), nil
 	default:
 		return "", fmt.Errorf("unknown analytics field: %s", field)
 	}
@@ -282,6 +308,32 @@ func getRuntimeValue(r *config.RuntimeConfig, field string) (string, error) {
 		return strconv.FormatBool(r.ReloadOnSIGHUP), nil
 	case "pid_file":
 		return strconv.FormatBool(r.PIDFile), nil
+	case "activation_mode":
+		mode, err := authfile.ParseActivationMode(r.ActivationMode)
+		if err != nil {
+			return "", err
+		}
+		return string(mode), nil
+	case "config_policy":
+		policy, err := authfile.ParseConfigPolicy(r.ConfigPolicy)
+		if err != nil {
+			return "", err
+		}
+		return string(policy), nil
+	case "durability":
+		durability, err := authfile.ParseDurability(r.Durability)
+		if err != nil {
+			return "", err
+		}
+		return string(durability), nil
+	case "storage":
+		storage, err := authfile.ParseStorage(r.Storage, nil)
+		if err != nil {
+			return "", err
+		}
+		return storage.Name(), nil
+	case "passphrase_command":
+		return r.PassphraseCommand, nil
 	default:
 		return "", fmt.Errorf("unknown runtime field: %s", field)
 	}
@@ -306,6 +358,8 @@ func getAlertsValue(a *config.AlertConfig, field string) (string, error) {
 		return strconv.Itoa(a.WarningThreshold), nil
 	case "critical_threshold":
 		return strconv.Itoa(a.CriticalThreshold), nil
+	case "project_share_threshold":
+		return strconv.Itoa(a.ProjectShareThreshold), nil
 	default:
 		return "", fmt.Errorf("unknown alerts field: %s", field)
 	}
@@ -347,6 +401,8 @@ func getDaemonValue(d *config.DaemonConfig, field string) (string, error) {
 		return d.RefreshThreshold.String(), nil
 	case "verbose":
 		return strconv.FormatBool(d.Verbose), nil
+	case "watch_config":
+		return strconv.FormatBool(d.WatchConfig), nil
 	default:
 		return "", fmt.Errorf("unknown daemon field: %s", field)
 	}
@@ -423,6 +479,10 @@ func setConfigValue(cfg *config.SPMConfig, key, value string) error {
 		return setHandoffValue(&cfg.Handoff, field, value)
 	case "daemon":
 		return setDaemonValue(&cfg.Daemon, field, value)
+	case "display":
+		return setDisplayValue(&cfg.Display, field, value)
+	case "pacing":
+		return setPacingValue(&cfg.Pacing, field, value)
 	default:
 		return fmt.Errorf("unknown section: %s", section)
 	}
@@ -454,6 +514,15 @@ func setHealthValue(h *config.HealthConfig, field, value string) error {
 			return fmt.Errorf("invalid duration: %w", err)
 		}
 		h.PenaltyDecayInterval = config.Duration(d)
+	case "stale_login_days":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer: %w", err)
+		}
+		if n < 0 {
+			return fmt.Errorf("stale_login_days cannot be negative")
+		}
+		h.StaleLoginDays = n
 	default:
 		return fmt.Errorf("unknown health field: %s", field)
 	}
@@ -486,6 +555,19 @@ func setAnalyticsValue(a *config.AnalyticsConfig, field, value string) error {
 			return err
 		}
 		a.CleanupOnStartup = b
+	case "exec_history":
+		switch value {
+		case config.ExecHistoryOff, config.ExecHistoryHash, config.ExecHistoryTruncate:
+			a.ExecHistory = value
+		default:
+			return fmt.Errorf("invalid exec_history %q (supported: off, hash, truncate)", value)
+		}
+	case "exec_history_chars":
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer: %w", err)
+		}
+		a.ExecHistoryChars = i
 	default:
 		return fmt.Errorf("unknown analytics field: %s", field)
 	}
@@ -512,6 +594,32 @@ func setRuntimeValue(r *config.RuntimeConfig, field, value string) error {
 			return err
 		}
 		r.PIDFile = b
+	case "activation_mode":
+		mode, err := authfile.ParseActivationMode(value)
+		if err != nil {
+			return err
+		}
+		r.ActivationMode = string(mode)
+	case "config_policy":
+		policy, err := authfile.ParseConfigPolicy(value)
+		if err != nil {
+			return err
+		}
+		r.ConfigPolicy = string(policy)
+	case "durability":
+		durability, err := authfile.ParseDurability(value)
+		if err != nil {
+			return err
+		}
+		r.Durability = string(durability)
+	case "storage":
+		storage, err := authfile.ParseStorage(value, nil)
+		if err != nil {
+			return err
+		}
+		r.Storage = storage.Name()
+	case "passphrase_command":
+		r.PassphraseCommand = strings.TrimSpace(value)
 	default:
 		return fmt.Errorf("unknown runtime field: %s", field)
 	}
@@ -558,12 +666,107 @@ func setAlertsValue(a *config.AlertConfig, field, value string) error {
 			return fmt.Errorf("invalid integer: %w", err)
 		}
 		a.CriticalThreshold = i
+	case "project_share_threshold":
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer: %w", err)
+		}
+		a.ProjectShareThreshold = i
 	default:
 		return fmt.Errorf("unknown alerts field: %s", field)
 	}
 	return nil
 }
 
+func getDisplayValue(d *config.DisplayConfig, field string) (string, error) {
+	switch field {
+	case "timezone":
+		if d.Timezone == "" {
+			return "local", nil
+		}
+		return d.Timezone, nil
+	case "terminal_title":
+		return strconv.FormatBool(d.TerminalTitle), nil
+	default:
+		return "", fmt.Errorf("unknown display field: %s", field)
+	}
+}
+
+func setDisplayValue(d *config.DisplayConfig, field, value string) error {
+	switch field {
+	case "timezone":
+		next := config.DisplayConfig{Timezone: value}
+		if _, err := next.Location(); err != nil {
+			return err
+		}
+		d.Timezone = value
+	case "terminal_title":
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		d.TerminalTitle = b
+	default:
+		return fmt.Errorf("unknown display field: %s", field)
+	}
+	return nil
+}
+
+func getPacingValue(p *config.PacingConfig, field string) (string, error) {
+	switch field {
+	case "max_sessions_per_hour":
+		return strconv.Itoa(p.MaxSessionsPerHour), nil
+	case "min_gap":
+		return p.MinGap.String(), nil
+	case "when_limited":
+		if p.WhenLimited == "" {
+			return config.PaceWait, nil
+		}
+		return p.WhenLimited, nil
+	case "max_wait":
+		return p.MaxWait.String(), nil
+	default:
+		return "", fmt.Errorf("unknown pacing field: %s", field)
+	}
+}
+
+func setPacingValue(p *config.PacingConfig, field, value string) error {
+	switch field {
+	case "max_sessions_per_hour":
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer: %w", err)
+		}
+		if i < 0 {
+			return fmt.Errorf("max_sessions_per_hour cannot be negative")
+		}
+		p.MaxSessionsPerHour = i
+	case "min_gap", "max_wait":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration: %w", err)
+		}
+		if d < 0 {
+			return fmt.Errorf("%s cannot be negative", field)
+		}
+		if field == "min_gap" {
+			p.MinGap = config.Duration(d)
+		} else {
+			p.MaxWait = config.Duration(d)
+		}
+	case "when_limited":
+		switch value {
+		case config.PaceWait, config.PaceRotate, config.PaceFail:
+			p.WhenLimited = value
+		default:
+			return fmt.Errorf("invalid when_limited %q (supported: wait, rotate, fail)", value)
+		}
+	default:
+		return fmt.Errorf("unknown pacing field: %s", field)
+	}
+	return nil
+}
+
 func setNotificationsValue(n *config.NotificationConfig, field, value string) error {
 	switch field {
 	case "terminal":
@@ -638,6 +841,12 @@ func setDaemonValue(d *config.DaemonConfig, field, value string) error {
 			return err
 		}
 		d.Verbose = b
+	case "watch_config":
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		d.WatchConfig = b
 	default:
 		return fmt.Errorf("unknown daemon field: %s", field)
 	}
@@ -144,6 +144,7 @@ func TestGetConfigValue_Health(t *testing.T) {
 		{"health.warning_threshold", false},
 		{"health.penalty_decay_rate", false},
 		{"health.penalty_decay_interval", false},
+		{"health.stale_login_days", false},
 		{"health.unknown_field", true},
 	}
 
@@ -191,6 +192,9 @@ func TestGetConfigValue_Runtime(t *testing.T) {
 		{"runtime.file_watching", false},
 		{"runtime.reload_on_sighup", false},
 		{"runtime.pid_file", false},
+		{"runtime.durability", false},
+		{"runtime.storage", false},
+		{"runtime.passphrase_command", false},
 		{"runtime.unknown_field", true},
 	}
 
@@ -292,7 +296,19 @@ func TestSetConfigValue_Health(t *testing.T) {
 		t.Errorf("Expected 0.85, got %f", cfg.Health.PenaltyDecayRate)
 	}
 
-	// Test invalid duration
+	// Test stale_login_days
+	err = setConfigValue(cfg, "health.stale_login_days", "30")
+	if err != nil {
+		t.Errorf("setConfigValue(health.stale_login_days) error: %v", err)
+	}
+	if cfg.Health.StaleLoginDays != 30 {
+		t.Errorf("Expected 30, got %d", cfg.Health.StaleLoginDays)
+	}
+	if err := setConfigValue(cfg, "health.stale_login_days", "-1"); err == nil {
+		t.Error("Expected error for negative stale_login_days")
+	}
+
+// Test invalid duration
 	err = setConfigValue(cfg, "health.refresh_threshold", "not-a-duration")
 	if err == nil {
 		t.Error("Expected error for invalid duration")
@@ -347,6 +363,36 @@ func TestSetConfigValue_Runtime(t *testing.T) {
 	if !cfg.Runtime.PIDFile {
 		t.Error("Expected pid_file=true")
 	}
+
+	// Test durability
+	if err := setConfigValue(cfg, "runtime.durability", "Fast"); err != nil {
+		t.Errorf("setConfigValue(runtime.durability) error: %v", err)
+	}
+	if cfg.Runtime.Durability != "fast" {
+		t.Errorf("Expected durability=fast, got %q", cfg.Runtime.Durability)
+	}
+	if err := setConfigValue(cfg, "runtime.durability", "sometimes"); err == nil {
+		t.Error("Expected error for invalid durability")
+	}
+
+	// Test storage
+	if err := setConfigValue(cfg, "runtime.storage", "Keychain"); err != nil {
+		t.Errorf("setConfigValue(runtime.storage) error: %v", err)
+	}
+	if cfg.Runtime.Storage != "keychain" {
+		t.Errorf("Expected storage=keychain, got %q", cfg.Runtime.Storage)
+	}
+	if err := setConfigValue(cfg, "runtime.storage", "s3"); err == nil {
+		t.Error("Expected error for invalid storage")
+	}
+
+	// Test passphrase_command
+	if err := setConfigValue(cfg, "runtime.passphrase_command", " op read op://vault/caam/password "); err != nil {
+		t.Errorf("setConfigValue(runtime.passphrase_command) error: %v", err)
+	}
+	if cfg.Runtime.PassphraseCommand != "op read op://vault/caam/password" {
+		t.Errorf("Expected trimmed passphrase_command, got %q", cfg.Runtime.PassphraseCommand)
+	}
 }
 
 func TestSetConfigValue_Project(t *testing.T) {
@@ -371,6 +417,53 @@ func TestSetConfigValue_Project(t *testing.T) {
 	}
 }
 
+func TestSetConfigValue_Pacing(t *testing.T) {
+	cfg := config.DefaultSPMConfig()
+
+	for _, tt := range []struct{ key, value, want string }{
+		{"pacing.max_sessions_per_hour", "6", "6"},
+		{"pacing.min_gap", "5m", "5m0s"},
+		{"pacing.when_limited", "rotate", "rotate"},
+		{"pacing.max_wait", "2h", "2h0m0s"},
+	} {
+		if err := setConfigValue(cfg, tt.key, tt.value); err != nil {
+			t.Fatalf("setConfigValue(%s) error: %v", tt.key, err)
+		}
+		if got, err := getConfigValue(cfg, tt.key); err != nil || got != tt.want {
+			t.Errorf("getConfigValue(%s) = %q, %v; want %q", tt.key, got, err, tt.want)
+		}
+	}
+	if cfg.Pacing.MaxSessionsPerHour != 6 || cfg.Pacing.MinGap.Duration() != 5*time.Minute || cfg.Pacing.WhenLimited != "rotate" {
+		t.Errorf("pacing not applied: %+v", cfg.Pacing)
+	}
+
+	for key, value := range map[string]string{
+		"pacing.max_sessions_per_hour": "-1",
+		"pacing.when_limited":          "queue",
+		"pacing.min_gap":               "soon",
+	} {
+		if err := setConfigValue(cfg, key, value); err == nil {
+			t.Errorf("setConfigValue(%s, %s) should fail", key, value)
+		}
+	}
+}
+
+func TestSetConfigValue_TerminalTitle(t *testing.T) {
+	cfg := config.DefaultSPMConfig()
+	if got, _ := getConfigValue(cfg, "display.terminal_title"); got != "false" {
+		t.Errorf("display.terminal_title defaults to %q, want false", got)
+	}
+	if err := setConfigValue(cfg, "display.terminal_title", "on"); err != nil {
+		t.Fatalf("setConfigValue error: %v", err)
+	}
+	if !cfg.Display.TerminalTitle {
+		t.Error("display.terminal_title not applied")
+	}
+	if err := setConfigValue(cfg, "display.terminal_title", "sometimes"); err == nil {
+		t.Error("setConfigValue should reject a non-boolean")
+	}
+}
+
 func TestSetConfigValue_InvalidKeys(t *testing.T) {
 	cfg := config.DefaultSPMConfig()
 
@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// activeContextName and activeContext are the config context in effect for
+// this invocation: --context, or the config's current_context.
+var (
+	activeContextName string
+	activeContext     *config.ContextConfig
+)
+
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context <name>",
+	Short: "Switch to a named context",
+	Long: `Makes <name> the current context. A context bundles a vault path, default
+provider, rotation strategy and coordinator URL, so you can switch between
+setups (e.g. personal and employer) with one command.
+
+Use --context <name> on any command to use a context just once.
+
+Examples:
+  caam config use-context work
+  caam config use-context --none`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigUseContext,
+}
+
+var configCurrentContextCmd = &cobra.Command{
+	Use:   "current-context",
+	Short: "Print the current context",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if cfg.CurrentContext == "" {
+			return fmt.Errorf("no current context (set one with 'caam config use-context <name>')")
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), cfg.CurrentContext)
+		return nil
+	},
+}
+
+var configGetContextsCmd = &cobra.Command{
+	Use:     "get-contexts",
+	Aliases: []string{"contexts"},
+	Short:   "List contexts",
+	Long: `Lists the defined contexts. The current one is marked with '*'.
+
+Examples:
+  caam config get-contexts
+  caam config get-contexts --json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigGetContexts,
+}
+
+var configSetContextCmd = &cobra.Command{
+	Use:   "set-context <name>",
+	Short: "Create or update a context",
+	Long: `Creates a context, or updates the given fields of an existing one. Fields
+left unset fall back to the regular configuration.
+
+Examples:
+  caam config set-context work --vault-path ~/work/caam-vault --provider claude
+  caam config set-context work --strategy round_robin --coordinator http://build-box:7890
+  caam config set-context personal --provider codex`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigSetContext,
+}
+
+var configDeleteContextCmd = &cobra.Command{
+	Use:   "delete-context <name>",
+	Short: "Delete a context",
+	Long: `Deletes a context definition. The vault it points to is not touched.
+
+Examples:
+  caam config delete-context old-job`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		if !cfg.DeleteContext(args[0]) {
+			return fmt.Errorf("context %q not found", args[0])
+		}
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted context %q\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().String("context", "", "config context to use for this command (see 'caam config use-context')")
+
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configCurrentContextCmd)
+	configCmd.AddCommand(configGetContextsCmd)
+	configCmd.AddCommand(configSetContextCmd)
+	configCmd.AddCommand(configDeleteContextCmd)
+
+	configUseContextCmd.Flags().Bool("none", false, "clear the current context")
+	configGetContextsCmd.Flags().Bool("json", false, "output as JSON")
+	configSetContextCmd.Flags().String("vault-path", "", "vault directory for this context")
+	configSetContextCmd.Flags().String("provider", "", "default provider (claude, codex, gemini)")
+	configSetContextCmd.Flags().String("strategy", "", "rotation strategy (smart, round_robin, random)")
+	configSetContextCmd.Flags().String("coordinator", "", "coordinator URL for 'caam auth-agent'")
+	registerValueCompletion(configSetContextCmd, "strategy", rotationAlgorithms...)
+}
+
+func runConfigUseContext(cmd *cobra.Command, args []string) error {
+	none, _ := cmd.Flags().GetBool("none")
+	if none == (len(args) == 1) {
+		return withExitCode(ExitUsage, fmt.Errorf("give a context name or --none"))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	if none {
+		cfg.CurrentContext = ""
+	} else {
+		if _, ok := cfg.GetContext(args[0]); !ok {
+			return fmt.Errorf("context %q not found (available: %s)", args[0], strings.Join(cfg.ListContexts(), ", "))
+		}
+		cfg.CurrentContext = args[0]
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	if none {
+		fmt.Fprintln(cmd.OutOrStdout(), "Cleared the current context")
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Switched to context %q\n", args[0])
+	}
+	return nil
+}
+
+// contextListEntry is one context in get-contexts --json output.
+type contextListEntry struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+	config.ContextConfig
+}
+
+func runConfigGetContexts(cmd *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	entries := []contextListEntry{}
+	for _, name := range cfg.ListContexts() {
+		ctx, _ := cfg.GetContext(name)
+		entries = append(entries, contextListEntry{Name: name, Current: name == cfg.CurrentContext, ContextConfig: ctx})
+	}
+
+	if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+		return writeJSON(cmd.OutOrStdout(), entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No contexts defined. Create one with 'caam config set-context <name>'.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tVAULT\tPROVIDER\tSTRATEGY\tCOORDINATOR")
+	for _, e := range entries {
+		current := ""
+		if e.Current {
+			current = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", current, e.Name,
+			orDash(e.VaultPath), orDash(e.DefaultProvider), orDash(e.Strategy), orDash(e.CoordinatorURL))
+	}
+	return w.Flush()
+}
+
+func runConfigSetContext(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if strings.HasPrefix(name, "_") {
+		return withExitCode(ExitUsage, fmt.Errorf("context names starting with '_' are reserved"))
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	ctx, exists := cfg.GetContext(name)
+
+	if cmd.Flags().Changed("vault-path") {
+		vaultPath, _ := cmd.Flags().GetString("vault-path")
+		if vaultPath != "" {
+			if vaultPath, err = absContextPath(vaultPath); err != nil {
+				return err
+			}
+		}
+		ctx.VaultPath = vaultPath
+	}
+	if cmd.Flags().Changed("provider") {
+		provider, _ := cmd.Flags().GetString("provider")
+		provider = strings.ToLower(strings.TrimSpace(provider))
+		if _, ok := tools[provider]; provider != "" && !ok {
+			return withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s (supported: %s)", provider, supportedTools()))
+		}
+		ctx.DefaultProvider = provider
+	}
+	if cmd.Flags().Changed("strategy") {
+		strategy, _ := cmd.Flags().GetString("strategy")
+		strategy = strings.ToLower(strings.TrimSpace(strategy))
+		switch strategy {
+		case "", "smart", "round_robin", "random":
+		default:
+			return withExitCode(ExitUsage, fmt.Errorf("unknown strategy: %s (supported: smart, round_robin, random)", strategy))
+		}
+		ctx.Strategy = strategy
+	}
+	if cmd.Flags().Changed("coordinator") {
+		ctx.CoordinatorURL, _ = cmd.Flags().GetString("coordinator")
+	}
+
+	cfg.SetContext(name, ctx)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	verb := "Created"
+	if exists {
+		verb = "Updated"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s context %q\n", verb, name)
+	if cfg.CurrentContext != name {
+		fmt.Fprintf(cmd.OutOrStdout(), "Switch to it with: caam config use-context %s\n", name)
+	}
+	return nil
+}
+
+// absContextPath expands a leading ~ and makes path absolute, so a context
+// means the same vault from any working directory.
+func absContextPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expand %s: %w", path, err)
+		}
+		path = filepath.Join(home, strings.TrimPrefix(path, "~"))
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", path, err)
+	}
+	return abs, nil
+}
+
+// applyConfigContext puts the active context (--context, else the config's
+// current_context) into effect for this invocation.
+func applyConfigContext(cmd *cobra.Command, cfg *config.Config) error {
+	activeContextName, activeContext = "", nil
+
+	name, _ := cmd.Flags().GetString("context")
+	explicit := name != ""
+	if !explicit {
+		name = cfg.CurrentContext
+	}
+	if name == "" {
+		return nil
+	}
+
+	ctx, ok := cfg.GetContext(name)
+	if !ok {
+		if explicit {
+			return withExitCode(ExitUsage, fmt.Errorf("context %q not found", name))
+		}
+		fmt.Fprintf(os.Stderr, "warning: current context %q no longer exists; using the default configuration\n", name)
+		return nil
+	}
+	activeContextName, activeContext = name, &ctx
+
+	if ctx.VaultPath != "" {
+		authfile.SetVaultPathOverride(ctx.VaultPath)
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+		authfile.ApplyVaultSettings(vault)
+	}
+	return nil
+}
+
+// applyContextStrategy applies the active context's rotation strategy to a
+// freshly loaded SPM config. Call it before command-line overrides.
+func applyContextStrategy(spmCfg *config.SPMConfig) {
+	if spmCfg != nil && activeContext != nil && activeContext.Strategy != "" {
+		spmCfg.Stealth.Rotation.Algorithm = activeContext.Strategy
+	}
+}
+
+// contextDefaultProvider returns the default provider, preferring the active
+// context's.
+func contextDefaultProvider(cfg *config.Config) string {
+	if activeContext != nil && activeContext.DefaultProvider != "" {
+		return activeContext.DefaultProvider
+	}
+	if cfg != nil {
+		return cfg.DefaultProvider
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+func setupContextTest(t *testing.T) {
+	t.Helper()
+	setupAccountsTest(t)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(func() {
+		activeContextName, activeContext = "", nil
+		authfile.SetVaultPathOverride("")
+	})
+}
+
+func runSetContextForTest(t *testing.T, name string, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	for _, f := range []string{"vault-path", "provider", "strategy", "coordinator"} {
+		cmd.Flags().String(f, "", "")
+	}
+	for k, v := range flags {
+		require.NoError(t, cmd.Flags().Set(k, v))
+	}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	err := runConfigSetContext(cmd, []string{name})
+	return buf.String(), err
+}
+
+func TestSetContext_CreatesAndUpdates(t *testing.T) {
+	setupContextTest(t)
+	vaultDir := filepath.Join(t.TempDir(), "work-vault")
+
+	out, err := runSetContextForTest(t, "work", map[string]string{"vault-path": vaultDir, "provider": "Claude"})
+	require.NoError(t, err)
+	assert.Contains(t, out, `Created context "work"`)
+
+	// Only the given fields change.
+	out, err = runSetContextForTest(t, "work", map[string]string{"strategy": "round_robin"})
+	require.NoError(t, err)
+	assert.Contains(t, out, `Updated context "work"`)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	ctx, ok := cfg.GetContext("work")
+	require.True(t, ok)
+	assert.Equal(t, config.ContextConfig{VaultPath: vaultDir, DefaultProvider: "claude", Strategy: "round_robin"}, ctx)
+}
+
+func TestSetContext_Validates(t *testing.T) {
+	setupContextTest(t)
+
+	_, err := runSetContextForTest(t, "work", map[string]string{"provider": "nope"})
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	_, err = runSetContextForTest(t, "work", map[string]string{"strategy": "fastest"})
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	_, err = runSetContextForTest(t, "_reserved", nil)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
+
+func TestApplyConfigContext(t *testing.T) {
+	setupContextTest(t)
+	vaultDir := filepath.Join(t.TempDir(), "work-vault")
+
+	cfg := config.DefaultConfig()
+	cfg.SetContext("work", config.ContextConfig{VaultPath: vaultDir, DefaultProvider: "codex", Strategy: "random"})
+	cfg.CurrentContext = "work"
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("context", "", "")
+	require.NoError(t, applyConfigContext(cmd, cfg))
+	assert.Equal(t, "work", activeContextName)
+	assert.Equal(t, vaultDir, authfile.DefaultVaultPath())
+	assert.Equal(t, filepath.Join(vaultDir, "claude", "a"), vault.ProfilePath("claude", "a"))
+	assert.Equal(t, "codex", contextDefaultProvider(cfg))
+
+	spmCfg := config.DefaultSPMConfig()
+	applyContextStrategy(spmCfg)
+	assert.Equal(t, "random", spmCfg.Stealth.Rotation.Algorithm)
+
+	// An unknown --context is a usage error.
+	require.NoError(t, cmd.Flags().Set("context", "nope"))
+	assert.Equal(t, ExitUsage, ExitCode(applyConfigContext(cmd, cfg)))
+
+	// A stale current context falls back to the defaults.
+	require.NoError(t, cmd.Flags().Set("context", ""))
+	cfg.CurrentContext = "gone"
+	require.NoError(t, applyConfigContext(cmd, cfg))
+	assert.Empty(t, activeContextName)
+	assert.Nil(t, activeContext)
+}
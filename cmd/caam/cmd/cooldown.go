@@ -11,6 +11,7 @@ import (
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
 	"github.com/spf13/cobra"
 )
 
@@ -21,6 +22,8 @@ var cooldownCmd = &cobra.Command{
 
 Examples:
   caam cooldown set claude/work --minutes 60
+  caam cooldown set claude/work --for 90m
+  caam cooldown set codex/main --for "friday 9am"
   caam cooldown clear claude/work
   caam cooldown clear --all
   caam cooldown list`,
@@ -34,7 +37,7 @@ func init() {
 }
 
 var cooldownSetCmd = &cobra.Command{
-	Use:   "set <provider/profile|provider> [--minutes N] [--notes TEXT]",
+	Use:   "set <provider/profile|provider> [--minutes N | --for DURATION] [--notes TEXT]",
 	Short: "Set a cooldown for a profile",
 	Args:  cobra.ExactArgs(1),
 	RunE:  runCooldownSet,
@@ -42,7 +45,9 @@ var cooldownSetCmd = &cobra.Command{
 
 func init() {
 	cooldownSetCmd.Flags().Int("minutes", 0, "cooldown duration in minutes (default: stealth.cooldown.default_minutes)")
+	cooldownSetCmd.Flags().String("for", "", "cooldown duration or end time, e.g. 90m, 2h30m, 1d, friday 9am")
 	cooldownSetCmd.Flags().String("notes", "", "optional notes to store with the cooldown event")
+	registerDurationCompletion(cooldownSetCmd, "for")
 }
 
 func runCooldownSet(cmd *cobra.Command, args []string) error {
@@ -52,16 +57,9 @@ func runCooldownSet(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	minutes, _ := cmd.Flags().GetInt("minutes")
-	if minutes <= 0 {
-		spmCfg, err := config.LoadSPMConfig()
-		if err != nil {
-			spmCfg = config.DefaultSPMConfig()
-		}
-		minutes = spmCfg.Stealth.Cooldown.DefaultMinutes
-	}
-	if minutes <= 0 {
-		minutes = 60
+	duration, err := cooldownSetDuration(cmd)
+	if err != nil {
+		return err
 	}
 
 	notes, _ := cmd.Flags().GetString("notes")
@@ -72,7 +70,9 @@ func runCooldownSet(cmd *cobra.Command, args []string) error {
 	}
 	defer db.Close()
 
-	ev, err := db.SetCooldown(provider, profile, time.Now().UTC(), time.Duration(minutes)*time.Minute, notes)
+	syncAccountLinks(db, provider)
+
+	ev, err := db.SetCooldown(provider, profile, time.Now().UTC(), duration, notes)
 	if err != nil {
 		return err
 	}
@@ -80,12 +80,47 @@ func runCooldownSet(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(cmd.OutOrStdout(), "Recorded cooldown for %s/%s until %s (%s remaining)\n",
 		ev.Provider,
 		ev.ProfileName,
-		ev.CooldownUntil.Local().Format("2006-01-02 15:04"),
+		formatZoned(ev.CooldownUntil, displayLocation()),
 		formatDurationShort(time.Until(ev.CooldownUntil)),
 	)
+	if len(ev.PropagatedTo) > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "  Also applied to same-account profiles: %s\n", strings.Join(ev.PropagatedTo, ", "))
+	}
 	return nil
 }
 
+// cooldownSetDuration returns the cooldown requested by --for or --minutes,
+// falling back to stealth.cooldown.default_minutes and then an hour.
+func cooldownSetDuration(cmd *cobra.Command) (time.Duration, error) {
+	minutes, _ := cmd.Flags().GetInt("minutes")
+	forStr, _ := cmd.Flags().GetString("for")
+	if forStr = strings.TrimSpace(forStr); forStr != "" {
+		if minutes > 0 {
+			return 0, withExitCode(ExitUsage, fmt.Errorf("use either --for or --minutes, not both"))
+		}
+		d, err := durations.ParseFrom(forStr, time.Now().In(displayLocation()))
+		if err != nil {
+			return 0, withExitCode(ExitUsage, fmt.Errorf("invalid --for: %w", err))
+		}
+		if d <= 0 {
+			return 0, withExitCode(ExitUsage, fmt.Errorf("--for must be greater than zero"))
+		}
+		return d, nil
+	}
+
+	if minutes <= 0 {
+		spmCfg, err := config.LoadSPMConfig()
+		if err != nil {
+			spmCfg = config.DefaultSPMConfig()
+		}
+		minutes = spmCfg.Stealth.Cooldown.DefaultMinutes
+	}
+	if minutes <= 0 {
+		minutes = 60
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
 var cooldownClearCmd = &cobra.Command{
 	Use:   "clear [provider/profile|provider] [--all]",
 	Short: "Clear a cooldown (or all cooldowns)",
@@ -211,10 +246,10 @@ func runCooldownList(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	return renderCooldownList(cmd.OutOrStdout(), now, events)
+	return renderCooldownList(cmd.OutOrStdout(), now, displayLocation(), events)
 }
 
-func renderCooldownList(w io.Writer, now time.Time, events []caamdb.CooldownEvent) error {
+func renderCooldownList(w io.Writer, now time.Time, loc *time.Location, events []caamdb.CooldownEvent) error {
 	_, _ = fmt.Fprintln(w, "Active Cooldowns")
 	_, _ = fmt.Fprintln(w, "───────────────────────────────────────")
 
@@ -225,8 +260,8 @@ func renderCooldownList(w io.Writer, now time.Time, events []caamdb.CooldownEven
 		_, _ = fmt.Fprintf(tw, "%s/%s\t%s\t%s\t%s\t%s\n",
 			ev.Provider,
 			ev.ProfileName,
-			ev.HitAt.Local().Format("2006-01-02 15:04"),
-			ev.CooldownUntil.Local().Format("2006-01-02 15:04"),
+			formatZonedShort(ev.HitAt, loc),
+			formatZoned(ev.CooldownUntil, loc),
 			remaining,
 			ev.Notes,
 		)
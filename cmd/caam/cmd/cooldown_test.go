@@ -111,6 +111,62 @@ func TestCooldownSet_DefaultMinutes(t *testing.T) {
 	}
 }
 
+func TestCooldownSet_ForDuration(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Int("minutes", 0, "")
+	cmd.Flags().String("for", "", "")
+	cmd.Flags().String("notes", "", "")
+	_ = cmd.Flags().Set("for", "1d")
+	cmd.SetOut(&bytes.Buffer{})
+
+	before := time.Now().UTC()
+	if err := runCooldownSet(cmd, []string{"codex/long"}); err != nil {
+		t.Fatalf("runCooldownSet() error = %v", err)
+	}
+
+	db, err := caamdb.Open()
+	if err != nil {
+		t.Fatalf("db.Open() error = %v", err)
+	}
+	defer db.Close()
+	ev, err := db.ActiveCooldown("codex", "long", before)
+	if err != nil || ev == nil {
+		t.Fatalf("ActiveCooldown() = %v, %v; want a cooldown", ev, err)
+	}
+	if got := ev.CooldownUntil.Sub(before); got < 23*time.Hour || got > 25*time.Hour {
+		t.Errorf("cooldown lasts %v, want about 24h", got)
+	}
+}
+
+func TestCooldownSet_ForRejectsBadValues(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+
+	for _, tc := range []struct {
+		forValue string
+		minutes  string
+	}{
+		{"soon", ""},
+		{"0", ""},
+		{"90m", "30"},
+	} {
+		cmd := &cobra.Command{}
+		cmd.Flags().Int("minutes", 0, "")
+		cmd.Flags().String("for", "", "")
+		cmd.Flags().String("notes", "", "")
+		_ = cmd.Flags().Set("for", tc.forValue)
+		if tc.minutes != "" {
+			_ = cmd.Flags().Set("minutes", tc.minutes)
+		}
+		if err := runCooldownSet(cmd, []string{"codex/bad"}); err == nil {
+			t.Errorf("runCooldownSet(--for %q --minutes %q) should fail", tc.forValue, tc.minutes)
+		}
+	}
+}
+
 func TestCooldownClear_ClearsSpecificProfile(t *testing.T) {
 	_, cleanup := setupCooldownTestEnv(t)
 	defer cleanup()
@@ -368,7 +424,7 @@ func TestRenderCooldownList(t *testing.T) {
 	}
 
 	var buf bytes.Buffer
-	if err := renderCooldownList(&buf, now, events); err != nil {
+	if err := renderCooldownList(&buf, now, time.UTC, events); err != nil {
 		t.Fatalf("renderCooldownList() error = %v", err)
 	}
 
@@ -385,6 +441,9 @@ func TestRenderCooldownList(t *testing.T) {
 	if !strings.Contains(output, "rate limit") {
 		t.Error("output should contain notes")
 	}
+	if want := events[0].CooldownUntil.Format("2006-01-02 15:04") + " UTC"; !strings.Contains(output, want) {
+		t.Errorf("output should contain %q with its zone", want)
+	}
 }
 
 func TestConfirmProceed(t *testing.T) {
@@ -6,9 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/coordinator"
@@ -78,6 +76,7 @@ func init() {
 	coordinatorCmd.Flags().StringVar(&coordinatorBackend, "backend", "auto",
 		"Terminal multiplexer backend: wezterm (preferred), tmux, or auto")
 	coordinatorCmd.Flags().StringVar(&coordinatorConfigPath, "config", "", "Path to JSON config file")
+	addPIDFileFlag(coordinatorCmd)
 }
 
 func runCoordinator(cmd *cobra.Command, args []string) error {
@@ -148,19 +147,23 @@ func runCoordinator(cmd *cobra.Command, args []string) error {
 
 	// Create API server
 	api := coordinator.NewAPIServer(coord, apiPort, logger)
+	apiTokens, err := configuredAPITokens()
+	if err != nil {
+		return err
+	}
+	api.RequireTokens(apiTokens)
 
-	// Start coordinator
-	ctx, cancel := context.WithCancel(cmd.Context())
-	defer cancel()
+	// Start coordinator; ctx is canceled on SIGINT/SIGTERM
+	ctx, finish, err := startLongRunning(cmd)
+	if err != nil {
+		return err
+	}
+	defer finish()
 
 	if err := coord.Start(ctx); err != nil {
 		return fmt.Errorf("start coordinator: %w", err)
 	}
 
-	// Handle signals
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
 	// Start API server in background
 	errCh := make(chan error, 1)
 	go func() {
@@ -179,13 +182,12 @@ func runCoordinator(cmd *cobra.Command, args []string) error {
 
 	// Wait for signal or error
 	select {
-	case <-sigCh:
+	case <-ctx.Done():
 		fmt.Println("\nShutting down...")
 	case err := <-errCh:
 		if err != nil {
 			return fmt.Errorf("API server error: %w", err)
 		}
-	case <-ctx.Done():
 	}
 
 	// Graceful shutdown
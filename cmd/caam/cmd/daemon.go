@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -53,6 +54,16 @@ var daemonStatusCmd = &cobra.Command{
 	RunE:  runDaemonStatus,
 }
 
+// daemonRevertDueCmd is what systemd-run or at invokes to apply a
+// time-boxed activation when no daemon is running to do it.
+var daemonRevertDueCmd = &cobra.Command{
+	Use:    "revert-due",
+	Short:  "Switch back time-boxed activations whose time is up",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runDaemonRevertDue,
+}
+
 var daemonLogsCmd = &cobra.Command{
 	Use:   "logs",
 	Short: "View daemon logs",
@@ -65,6 +76,7 @@ func init() {
 	daemonCmd.AddCommand(daemonStopCmd)
 	daemonCmd.AddCommand(daemonStatusCmd)
 	daemonCmd.AddCommand(daemonLogsCmd)
+	daemonCmd.AddCommand(daemonRevertDueCmd)
 
 	// Start flags
 	daemonStartCmd.Flags().Bool("fg", false, "run in foreground (don't daemonize)")
@@ -72,6 +84,10 @@ func init() {
 	daemonStartCmd.Flags().Duration("threshold", daemon.DefaultRefreshThreshold, "refresh threshold (how long before expiry to refresh)")
 	daemonStartCmd.Flags().BoolP("verbose", "v", false, "verbose logging")
 	daemonStartCmd.Flags().Bool("pool", false, "enable auth pool for proactive token monitoring")
+	addPIDFileFlag(daemonStartCmd)
+
+	daemonStopCmd.Flags().String("pidfile", "", "PID file the daemon was started with")
+	daemonStatusCmd.Flags().String("pidfile", "", "PID file the daemon was started with")
 
 	// Logs flags
 	daemonLogsCmd.Flags().IntP("lines", "n", 50, "number of lines to show")
@@ -84,13 +100,17 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 	threshold, _ := cmd.Flags().GetDuration("threshold")
 	verbose, _ := cmd.Flags().GetBool("verbose")
 	usePool, _ := cmd.Flags().GetBool("pool")
+	pidFile, _ := cmd.Flags().GetString("pidfile")
 
-	// Load global config to check for PID file setting
+	// Load global config to check for PID file setting; --pidfile wins.
 	if spmCfg, err := config.LoadSPMConfig(); err == nil {
 		if spmCfg.Runtime.PIDFilePath != "" {
 			daemon.SetPIDFilePath(spmCfg.Runtime.PIDFilePath)
 		}
 	}
+	if pidFile != "" {
+		daemon.SetPIDFilePath(pidFile)
+	}
 
 	// Check if daemon is already running
 	running, pid, err := daemon.GetDaemonStatus()
@@ -105,7 +125,7 @@ func runDaemonStart(cmd *cobra.Command, args []string) error {
 		return runDaemonForeground(interval, threshold, verbose, usePool)
 	}
 
-	return runDaemonBackground(interval, threshold, verbose, usePool)
+	return runDaemonBackground(interval, threshold, verbose, usePool, pidFile)
 }
 
 func runDaemonForeground(interval, threshold time.Duration, verbose, usePool bool) error {
@@ -117,6 +137,7 @@ func runDaemonForeground(interval, threshold time.Duration, verbose, usePool boo
 
 	// Initialize vault and health store
 	v := authfile.NewVault(authfile.DefaultVaultPath())
+	authfile.ApplyVaultSettings(v)
 	hs := health.NewStorage(health.DefaultHealthPath())
 
 	cfg := &daemon.Config{
@@ -128,10 +149,18 @@ func runDaemonForeground(interval, threshold time.Duration, verbose, usePool boo
 
 	d := daemon.New(v, hs, cfg)
 
-	return d.Start()
+	// Start handles SIGINT/SIGTERM itself: it saves pool state and releases
+	// the PID lock before returning.
+	if err := d.Start(); err != nil {
+		if errors.Is(err, daemon.ErrForcedShutdown) {
+			return withExitCode(ExitForcedShutdown, err)
+		}
+		return err
+	}
+	return nil
 }
 
-func runDaemonBackground(interval, threshold time.Duration, verbose, usePool bool) error {
+func runDaemonBackground(interval, threshold time.Duration, verbose, usePool bool, pidFile string) error {
 	// Build the command to run in background
 	args := []string{"daemon", "start", "--fg",
 		"--interval", interval.String(),
@@ -143,6 +172,9 @@ func runDaemonBackground(interval, threshold time.Duration, verbose, usePool boo
 	if usePool {
 		args = append(args, "--pool")
 	}
+	if pidFile != "" {
+		args = append(args, "--pidfile", pidFile)
+	}
 
 	executable, err := os.Executable()
 	if err != nil {
@@ -195,6 +227,9 @@ func runDaemonStop(cmd *cobra.Command, args []string) error {
 			daemon.SetPIDFilePath(spmCfg.Runtime.PIDFilePath)
 		}
 	}
+	if pidFile, _ := cmd.Flags().GetString("pidfile"); pidFile != "" {
+		daemon.SetPIDFilePath(pidFile)
+	}
 
 	running, pid, err := daemon.GetDaemonStatus()
 	if err != nil {
@@ -237,6 +272,9 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 			daemon.SetPIDFilePath(spmCfg.Runtime.PIDFilePath)
 		}
 	}
+	if pidFile, _ := cmd.Flags().GetString("pidfile"); pidFile != "" {
+		daemon.SetPIDFilePath(pidFile)
+	}
 
 	running, pid, err := daemon.GetDaemonStatus()
 	if err != nil {
@@ -250,9 +288,43 @@ func runDaemonStatus(cmd *cobra.Command, args []string) error {
 		fmt.Println("Daemon is not running")
 	}
 
+	if reverts, err := daemon.LoadReverts(); err == nil && len(reverts) > 0 {
+		fmt.Println("Time-boxed activations:")
+		for _, r := range reverts {
+			target := r.RevertTo
+			if target == "" {
+				target = "(logged out)"
+			}
+			fmt.Printf("  %s/%s -> %s at %s\n", r.Tool, r.Profile, target, r.RevertAt.Local().Format("2006-01-02 15:04"))
+		}
+	}
+
 	return nil
 }
 
+func runDaemonRevertDue(cmd *cobra.Command, args []string) error {
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+		authfile.ApplyVaultSettings(vault)
+	}
+	outcomes, err := daemon.ApplyDueReverts(vault, time.Now())
+	if err != nil {
+		return err
+	}
+	var failed error
+	for _, o := range outcomes {
+		r := o.Revert
+		switch {
+		case o.Err != nil:
+			fmt.Fprintf(cmd.ErrOrStderr(), "%s/%s: revert failed: %v\n", r.Tool, r.Profile, o.Err)
+			failed = o.Err
+		case o.Applied:
+			fmt.Fprintf(cmd.OutOrStdout(), "Reverted %s/%s\n", r.Tool, r.Profile)
+		}
+	}
+	return failed
+}
+
 func runDaemonLogs(cmd *cobra.Command, args []string) error {
 	lines, _ := cmd.Flags().GetInt("lines")
 	follow, _ := cmd.Flags().GetBool("follow")
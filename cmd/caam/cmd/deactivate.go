@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/daemon"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+// deactivateCmd ends the current session cleanly.
+var deactivateCmd = &cobra.Command{
+	Use:   "deactivate <tool>",
+	Short: "Save the live login back to its profile and log out",
+	Long: `Ends the current session for a tool: the live auth files are saved back
+into the vault profile they came from, then removed.
+
+Saving first keeps any token refresh the tool made during the session, so
+the next 'caam activate' of that profile starts from fresh tokens. The
+profile is found by exact content, then by the account the live login
+belongs to. A login that matches no profile is kept as a timestamped
+_backup_* profile instead, as 'caam clear' does.
+
+With analytics on, the session length since the profile's last activation
+is recorded and counts toward its active time in 'caam usage'.
+
+Examples:
+  caam deactivate claude
+  caam deactivate codex --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDeactivate,
+}
+
+// deactivateOutput is the JSON output structure for deactivate command.
+type deactivateOutput struct {
+	jsonStatus
+	Tool string `json:"tool"`
+	// Profile is the vault profile the live login was saved into, or "".
+	Profile string `json:"profile,omitempty"`
+	// Synced is set when the live files differed from the profile and were
+	// saved over it.
+	Synced bool `json:"synced"`
+	// Backup names the _backup_* profile taken when no profile matched.
+	Backup         string `json:"backup,omitempty"`
+	SessionSeconds int64  `json:"session_seconds,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(deactivateCmd)
+	deactivateCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+func runDeactivate(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	out := cmd.OutOrStdout()
+
+	output := deactivateOutput{Tool: tool}
+	finish := func(err error) error {
+		if jsonOutput {
+			return writeJSONResult(cmd, &output, err)
+		}
+		return err
+	}
+
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return finish(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s", tool)))
+	}
+	fileSet := getFileSet()
+
+	if !authfile.HasAuthFiles(fileSet) {
+		if jsonOutput {
+			return finish(nil)
+		}
+		fmt.Fprintf(out, "No auth files for %s; nothing to deactivate.\n", tool)
+		return nil
+	}
+
+	db, _ := getDB()
+	profile, exact := deactivateTarget(fileSet, db)
+	switch {
+	case profile != "" && !exact:
+		syncSet := fileSet
+		if categories, err := vault.RecordedCategories(tool, profile); err == nil {
+			syncSet.Categories = categories
+		}
+		if err := vault.Backup(syncSet, profile); err != nil {
+			return finish(fmt.Errorf("save live login to %s/%s: %w", tool, profile, err))
+		}
+		output.Synced = true
+	case profile == "":
+		backup, err := vault.BackupCurrent(fileSet)
+		if err != nil {
+			return finish(fmt.Errorf("backup before deactivate failed: %w", err))
+		}
+		output.Backup = backup
+		if spmCfg, err := config.LoadSPMConfig(); err == nil {
+			_ = vault.RotateAutoBackups(tool, spmCfg.Safety.MaxAutoBackups)
+		}
+	}
+	output.Profile = profile
+
+	if err := authfile.ClearAuthFiles(fileSet); err != nil {
+		return finish(fmt.Errorf("clear failed: %w", err))
+	}
+	// Nothing is active any more, so there is nothing to revert to.
+	_, _ = daemon.CancelRevert(tool)
+
+	if profile != "" && db != nil {
+		if spmCfg, err := config.LoadSPMConfig(); err == nil && spmCfg.Analytics.Enabled {
+			output.SessionSeconds = recordDeactivation(db, tool, profile, output.Synced, time.Now())
+		}
+	}
+
+	if jsonOutput {
+		return finish(nil)
+	}
+	switch {
+	case output.Synced:
+		fmt.Fprintf(out, "Saved refreshed login to %s/%s\n", tool, profile)
+	case output.Backup != "":
+		fmt.Fprintf(out, "Live login matched no profile; backed it up to %s/%s\n", tool, output.Backup)
+	}
+	if output.SessionSeconds > 0 {
+		fmt.Fprintf(out, "Deactivated %s/%s after %s\n", tool, profile, formatDurationShort(time.Duration(output.SessionSeconds)*time.Second))
+	} else if profile != "" {
+		fmt.Fprintf(out, "Deactivated %s/%s\n", tool, profile)
+	} else {
+		fmt.Fprintf(out, "Deactivated %s\n", tool)
+	}
+	return nil
+}
+
+// deactivateTarget finds the vault profile the live login belongs to. exact
+// is set when the live files match the profile byte for byte. Otherwise it
+// falls back to the account: the last activated profile when it holds the
+// live account, else the only non-system profile that does. It returns ""
+// when no profile can be picked safely.
+func deactivateTarget(fileSet authfile.AuthFileSet, db *caamdb.DB) (profile string, exact bool) {
+	tool := fileSet.Tool
+	if active, err := vault.ActiveProfile(fileSet); err == nil && active != "" {
+		return active, true
+	}
+
+	account := identityAccount(liveIdentity(fileSet))
+	if account == "" {
+		return "", false
+	}
+	sameAccount := func(name string) bool {
+		return strings.EqualFold(identityAccount(getVaultIdentity(tool, name)), account)
+	}
+
+	if db != nil {
+		if last, err := db.ProfileActiveAt(tool, time.Now()); err == nil && last != "" &&
+			!authfile.IsSystemProfile(last) && vaultHasProfile(tool, last) && sameAccount(last) {
+			return last, false
+		}
+	}
+
+	profiles, err := vault.List(tool)
+	if err != nil {
+		return "", false
+	}
+	var match string
+	for _, name := range profiles {
+		if authfile.IsSystemProfile(name) || !sameAccount(name) {
+			continue
+		}
+		if match != "" {
+			// Two profiles hold this account; guessing could clobber one.
+			return "", false
+		}
+		match = name
+	}
+	return match, false
+}
+
+// recordDeactivation logs the end of tool/profile's session and returns its
+// length in seconds, or 0 when the activation isn't on record.
+func recordDeactivation(db *caamdb.DB, tool, profile string, synced bool, now time.Time) int64 {
+	var session time.Duration
+	if started, err := db.LastActivation(tool, profile); err == nil && !started.IsZero() && now.After(started) {
+		session = now.Sub(started)
+	}
+	_ = db.LogEvent(caamdb.Event{
+		Type:        caamdb.EventDeactivate,
+		Provider:    tool,
+		ProfileName: profile,
+		Duration:    session,
+		Details: map[string]any{
+			"synced": synced,
+		},
+	})
+	return int64(session / time.Second)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+// setupDeactivateTest points claude's live credentials at a temp file and
+// returns its path.
+func setupDeactivateTest(t *testing.T) string {
+	t.Helper()
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+
+	originalTools := tools
+	t.Cleanup(func() { tools = originalTools })
+	livePath := filepath.Join(t.TempDir(), ".credentials.json")
+	tools = map[string]func() authfile.AuthFileSet{
+		"claude": func() authfile.AuthFileSet {
+			return authfile.AuthFileSet{
+				Tool:  "claude",
+				Files: []authfile.AuthFileSpec{{Path: livePath, Required: true}},
+			}
+		},
+	}
+	return livePath
+}
+
+func runDeactivateJSON(t *testing.T, tool string) deactivateOutput {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", true, "")
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	require.NoError(t, runDeactivate(cmd, []string{tool}))
+	var out deactivateOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+	return out
+}
+
+func TestDeactivate_SyncsRefreshedTokensAndRecordsSession(t *testing.T) {
+	livePath := setupDeactivateTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "personal", "bob@example.com")
+
+	db, err := getDB()
+	require.NoError(t, err)
+	require.NoError(t, db.LogEvent(caamdb.Event{
+		Type:        caamdb.EventActivate,
+		Provider:    "claude",
+		ProfileName: "work",
+		Timestamp:   time.Now().Add(-90 * time.Minute),
+	}))
+
+	// The tool refreshed its token during the session.
+	refreshed := `{"claudeAiOauth": {"accessToken": "tok-refreshed", "email": "alice@example.com"}}`
+	require.NoError(t, os.WriteFile(livePath, []byte(refreshed), 0600))
+
+	out := runDeactivateJSON(t, "claude")
+	assert.Equal(t, "work", out.Profile)
+	assert.True(t, out.Synced)
+	assert.Empty(t, out.Backup)
+	assert.InDelta(t, 90*60, out.SessionSeconds, 60)
+
+	saved, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", "work"), ".credentials.json"))
+	require.NoError(t, err)
+	assert.Equal(t, refreshed, string(saved))
+	_, err = os.Stat(livePath)
+	assert.True(t, os.IsNotExist(err), "live auth should be cleared")
+
+	events, err := db.ListRecentEvents(10)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, caamdb.EventDeactivate, events[0].Type)
+	assert.Equal(t, "work", events[0].ProfileName)
+	assert.InDelta(t, (90 * time.Minute).Seconds(), events[0].Duration.Seconds(), 60)
+}
+
+func TestDeactivate_ExactMatchLeavesProfileAlone(t *testing.T) {
+	livePath := setupDeactivateTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	creds, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", "work"), ".credentials.json"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(livePath, creds, 0600))
+
+	out := runDeactivateJSON(t, "claude")
+	assert.Equal(t, "work", out.Profile)
+	assert.False(t, out.Synced)
+	assert.Empty(t, out.Backup)
+	_, err = os.Stat(livePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeactivate_UnknownLoginIsBackedUp(t *testing.T) {
+	livePath := setupDeactivateTest(t)
+	writeClaudeVaultProfile(t, "a", "carol@example.com")
+	writeClaudeVaultProfile(t, "b", "carol@example.com")
+	live := `{"claudeAiOauth": {"accessToken": "tok-new", "email": "carol@example.com"}}`
+	require.NoError(t, os.WriteFile(livePath, []byte(live), 0600))
+
+	// Two profiles hold the account, so neither is overwritten.
+	out := runDeactivateJSON(t, "claude")
+	assert.Empty(t, out.Profile)
+	assert.False(t, out.Synced)
+	require.NotEmpty(t, out.Backup)
+
+	saved, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", out.Backup), ".credentials.json"))
+	require.NoError(t, err)
+	assert.Equal(t, live, string(saved))
+	for _, name := range []string{"a", "b"} {
+		kept, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", name), ".credentials.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(kept), "tok-"+name)
+	}
+}
+
+func TestDeactivate_NothingLive(t *testing.T) {
+	setupDeactivateTest(t)
+
+	out := runDeactivateJSON(t, "claude")
+	assert.Empty(t, out.Profile)
+	assert.Zero(t, out.SessionSeconds)
+}
+
+func TestDeactivate_UnknownTool(t *testing.T) {
+	setupDeactivateTest(t)
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", false, "")
+	err := runDeactivate(cmd, []string{"nope"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <tool> <profileA> <profileB>",
+	Short: "Compare the stored auth of two profiles",
+	Long: `Compares two vault profiles: which account they log in as, token expiry,
+plan, and the keys in their stored auth files.
+
+Secret values (tokens, keys, passwords) are never printed. Where they differ,
+a short fingerprint is shown instead so you can still tell them apart.
+
+Examples:
+  caam diff claude work work-old
+  caam diff codex a b --all
+  caam diff gemini personal backup --json`,
+	Args: cobra.ExactArgs(3),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().Bool("all", false, "also show fields that are the same")
+	diffCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// diffField is one compared value.
+type diffField struct {
+	Field  string `json:"field"`
+	A      string `json:"a"`
+	B      string `json:"b"`
+	Same   bool   `json:"same"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// diffOutput is the JSON output structure for diff.
+type diffOutput struct {
+	Tool     string      `json:"tool"`
+	ProfileA string      `json:"profile_a"`
+	ProfileB string      `json:"profile_b"`
+	Fields   []diffField `json:"fields"`
+	Differs  int         `json:"differs"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	nameA, nameB := args[1], args[2]
+	showAll, _ := cmd.Flags().GetBool("all")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+	for _, name := range []string{nameA, nameB} {
+		if !vaultHasProfile(tool, name) {
+			return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", tool, name))
+		}
+	}
+
+	fields, err := diffProfiles(tool, nameA, nameB)
+	if err != nil {
+		return err
+	}
+
+	output := diffOutput{Tool: tool, ProfileA: nameA, ProfileB: nameB, Fields: []diffField{}}
+	for _, f := range fields {
+		if !f.Same {
+			output.Differs++
+		}
+		if f.Same && !showAll {
+			continue
+		}
+		output.Fields = append(output.Fields, f)
+	}
+
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(output)
+	}
+
+	if output.Differs == 0 {
+		fmt.Fprintf(out, "%s/%s and %s/%s store identical auth\n", tool, nameA, tool, nameB)
+		if !showAll {
+			return nil
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "FIELD\t%s\t%s\t\n", strings.ToUpper(nameA), strings.ToUpper(nameB))
+	for _, f := range output.Fields {
+		marker := ""
+		if !f.Same {
+			marker = "*"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.Field, f.A, f.B, marker)
+	}
+	return w.Flush()
+}
+
+// diffProfiles compares the identity and auth file contents of two profiles.
+func diffProfiles(tool, nameA, nameB string) ([]diffField, error) {
+	var fields []diffField
+
+	idA := getVaultIdentity(tool, nameA)
+	idB := getVaultIdentity(tool, nameB)
+	for _, f := range []struct {
+		name string
+		get  func(*identity.Identity) string
+	}{
+		{"account.email", func(id *identity.Identity) string { return id.Email }},
+		{"account.organization", func(id *identity.Identity) string { return id.Organization }},
+		{"account.id", func(id *identity.Identity) string { return id.AccountID }},
+		{"account.plan", func(id *identity.Identity) string { return id.PlanType }},
+		{"account.expires", func(id *identity.Identity) string {
+			if id.ExpiresAt.IsZero() {
+				return ""
+			}
+			return id.ExpiresAt.Local().Format(time.RFC3339)
+		}},
+	} {
+		a, b := "", ""
+		if idA != nil {
+			a = f.get(idA)
+		}
+		if idB != nil {
+			b = f.get(idB)
+		}
+		if a == "" && b == "" {
+			continue
+		}
+		fields = append(fields, diffField{Field: f.name, A: orDash(a), B: orDash(b), Same: a == b})
+	}
+
+	filesA, err := diffReadProfileFiles(tool, nameA)
+	if err != nil {
+		return nil, err
+	}
+	filesB, err := diffReadProfileFiles(tool, nameB)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]bool{}
+	for name := range filesA {
+		names[name] = true
+	}
+	for name := range filesB {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		fields = append(fields, diffFileFields(name, filesA[name], filesB[name])...)
+	}
+	return fields, nil
+}
+
+// diffReadProfileFiles reads the stored files of a vault profile, keyed by
+// name, decrypted. caam's own metadata is skipped.
+func diffReadProfileFiles(tool, name string) (map[string][]byte, error) {
+	dir := vault.ProfilePath(tool, name)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read profile %s/%s: %w", tool, name, err)
+	}
+
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == "meta.json" || entry.Name() == authfile.MachinesFile {
+			continue
+		}
+		data, err := vault.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
+		}
+		files[entry.Name()] = data
+	}
+	return files, nil
+}
+
+// diffFileFields compares one stored file. JSON files are compared key by key;
+// anything else is compared by content fingerprint only.
+func diffFileFields(name string, a, b []byte) []diffField {
+	if a == nil || b == nil {
+		return []diffField{{
+			Field: name,
+			A:     presence(a != nil),
+			B:     presence(b != nil),
+		}}
+	}
+
+	valuesA, okA := flattenJSONFile(a)
+	valuesB, okB := flattenJSONFile(b)
+	if !okA || !okB {
+		same := bytes.Equal(a, b)
+		f := diffField{Field: name, A: "(file)", B: "(file)", Same: same, Secret: true}
+		if !same {
+			f.A, f.B = fingerprint(string(a)), fingerprint(string(b))
+		}
+		return []diffField{f}
+	}
+
+	keys := map[string]bool{}
+	for k := range valuesA {
+		keys[k] = true
+	}
+	for k := range valuesB {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var fields []diffField
+	for _, key := range sorted {
+		va, inA := valuesA[key]
+		vb, inB := valuesB[key]
+		f := diffField{Field: name + ":" + key, Same: inA == inB && va == vb, Secret: isSecretKey(key)}
+		f.A, f.B = displayDiffValue(va, inA, f.Secret, f.Same), displayDiffValue(vb, inB, f.Secret, f.Same)
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func displayDiffValue(v string, present, secret, same bool) string {
+	switch {
+	case !present:
+		return "-"
+	case !secret:
+		return v
+	case same:
+		return "<redacted>"
+	default:
+		return fingerprint(v)
+	}
+}
+
+// flattenJSONFile flattens a JSON document into dotted key paths. Arrays of
+// scalars (e.g. OAuth scopes) are kept as one comma-separated value.
+func flattenJSONFile(data []byte) (map[string]string, bool) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, false
+	}
+	out := make(map[string]string)
+	flattenJSONValue("", doc, out)
+	return out, true
+}
+
+func flattenJSONValue(prefix string, v interface{}, out map[string]string) {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			flattenJSONValue(join(k), child, out)
+		}
+	case []interface{}:
+		scalars := make([]string, 0, len(val))
+		for i, child := range val {
+			switch child.(type) {
+			case map[string]interface{}, []interface{}:
+				flattenJSONValue(join(strconv.Itoa(i)), child, out)
+			default:
+				scalars = append(scalars, jsonScalar(child))
+			}
+		}
+		if len(scalars) > 0 || len(val) == 0 {
+			out[prefix] = strings.Join(scalars, ",")
+		}
+	default:
+		out[prefix] = jsonScalar(val)
+	}
+}
+
+func jsonScalar(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// isSecretKey reports whether a JSON key path likely holds a credential.
+func isSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	if i := strings.LastIndex(k, "."); i >= 0 {
+		k = k[i+1:]
+	}
+	for _, s := range []string{"token", "secret", "password", "apikey", "api_key", "private", "cookie", "credential", "session"} {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint returns a short, non-reversible fingerprint of a secret value.
+func fingerprint(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return "sha256:" + hex.EncodeToString(sum[:])[:8]
+}
+
+func presence(present bool) string {
+	if present {
+		return "present"
+	}
+	return "missing"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
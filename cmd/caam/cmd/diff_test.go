@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeVaultFile(t *testing.T, tool, profileName, file, content string) {
+	t.Helper()
+	dir := vault.ProfilePath(tool, profileName)
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0600))
+}
+
+func runDiffForTest(t *testing.T, args []string, flags map[string]string) (string, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("all", false, "")
+	cmd.Flags().Bool("json", false, "")
+	for k, v := range flags {
+		require.NoError(t, cmd.Flags().Set(k, v))
+	}
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	err := runDiff(cmd, args)
+	return buf.String(), err
+}
+
+func TestDiff_ReportsDifferencesAndRedactsSecrets(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+
+	writeVaultFile(t, "claude", "a", ".credentials.json",
+		`{"claudeAiOauth": {"accessToken": "secret-a", "refreshToken": "same-refresh", "email": "a@example.com", "subscriptionType": "max", "scopes": ["user:inference", "user:profile"]}}`)
+	writeVaultFile(t, "claude", "b", ".credentials.json",
+		`{"claudeAiOauth": {"accessToken": "secret-b", "refreshToken": "same-refresh", "email": "b@example.com", "subscriptionType": "pro", "scopes": ["user:inference"]}}`)
+	writeVaultFile(t, "claude", "a", "settings.json", `{"theme": "dark"}`)
+
+	out, err := runDiffForTest(t, []string{"claude", "a", "b"}, map[string]string{"json": "true"})
+	require.NoError(t, err)
+
+	var result diffOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+
+	byField := map[string]diffField{}
+	for _, f := range result.Fields {
+		byField[f.Field] = f
+	}
+
+	email := byField["account.email"]
+	assert.Equal(t, "a@example.com", email.A)
+	assert.Equal(t, "b@example.com", email.B)
+
+	assert.Equal(t, "user:inference,user:profile", byField[".credentials.json:claudeAiOauth.scopes"].A)
+	assert.Equal(t, "pro", byField[".credentials.json:claudeAiOauth.subscriptionType"].B)
+
+	token := byField[".credentials.json:claudeAiOauth.accessToken"]
+	assert.True(t, token.Secret)
+	assert.NotEqual(t, token.A, token.B, "differing secrets should get distinct fingerprints")
+	assert.NotContains(t, out, "secret-a")
+	assert.NotContains(t, out, "secret-b")
+
+	_, listed := byField[".credentials.json:claudeAiOauth.refreshToken"]
+	assert.False(t, listed, "equal fields are hidden without --all")
+	assert.NotContains(t, out, "same-refresh")
+
+	settings := byField["settings.json"]
+	assert.Equal(t, "present", settings.A)
+	assert.Equal(t, "missing", settings.B)
+}
+
+func TestDiff_IdenticalProfiles(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+	writeClaudeVaultProfile(t, "a", "same@example.com")
+	writeVaultFile(t, "claude", "b", ".credentials.json",
+		`{"claudeAiOauth": {"accessToken": "tok-a", "email": "same@example.com"}}`)
+
+	out, err := runDiffForTest(t, []string{"claude", "a", "b"}, nil)
+	require.NoError(t, err)
+	assert.Contains(t, out, "identical auth")
+
+	out, err = runDiffForTest(t, []string{"claude", "a", "b"}, map[string]string{"all": "true"})
+	require.NoError(t, err)
+	assert.Contains(t, out, "<redacted>")
+	assert.NotContains(t, out, "tok-a")
+}
+
+func TestDiff_EncryptedVault(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+	writeClaudeVaultProfile(t, "a", "a@example.com")
+	writeClaudeVaultProfile(t, "b", "b@example.com")
+	encryptTestVault(t)
+
+	out, err := runDiffForTest(t, []string{"claude", "a", "b"}, map[string]string{"json": "true"})
+	require.NoError(t, err)
+	var result diffOutput
+	require.NoError(t, json.Unmarshal([]byte(out), &result))
+	for _, f := range result.Fields {
+		if f.Field == "account.email" {
+			assert.Equal(t, "a@example.com", f.A)
+			assert.Equal(t, "b@example.com", f.B)
+			return
+		}
+	}
+	t.Fatalf("no account.email in diff of the decrypted files:\n%s", out)
+}
+
+func TestDiff_MissingProfile(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+	writeClaudeVaultProfile(t, "a", "a@example.com")
+
+	_, err := runDiffForTest(t, []string{"claude", "a", "nope"}, nil)
+	require.Error(t, err)
+	assert.Equal(t, ExitAuthMissing, ExitCode(err))
+}
+
+func TestIsSecretKey(t *testing.T) {
+	for _, key := range []string{"access_token", "claudeAiOauth.refreshToken", "client_secret", "OPENAI_API_KEY", "tokens.id_token"} {
+		assert.True(t, isSecretKey(key), key)
+	}
+	for _, key := range []string{"email", "claudeAiOauth.scopes", "expiresAt", "tokens.account_id"} {
+		assert.False(t, isSecretKey(key), key)
+	}
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
+)
+
+// Times typed into flags ("--for 5pm") are read on the clock they are shown
+// on.
+func init() {
+	durations.SetLocationSource(displayLocation)
+}
+
+// displayLocation returns the zone expiry and cooldown times are shown in
+// (display.timezone in config.yaml), falling back to local time. Wall-clock
+// times given on the command line are read in it too; everything stored or
+// compared stays in UTC.
+func displayLocation() *time.Location {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return time.Local
+	}
+	loc, err := spmCfg.Display.Location()
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// formatZoned shows t in loc with its zone, followed by UTC unless loc is
+// UTC: "2026-03-14 17:30 CET (16:30 UTC)". The UTC date is repeated only when
+// it differs, so teammates in other zones can compare times at a glance.
+func formatZoned(t time.Time, loc *time.Location) string {
+	local := t.In(loc)
+	s := local.Format("2006-01-02 15:04 MST")
+	utc := t.UTC()
+	if loc == time.UTC {
+		return s
+	}
+	layout := "15:04 UTC"
+	if local.Format("2006-01-02") != utc.Format("2006-01-02") {
+		layout = "2006-01-02 15:04 UTC"
+	}
+	return s + " (" + utc.Format(layout) + ")"
+}
+
+// formatZonedShort shows t in loc with its zone only.
+func formatZonedShort(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
+
+// zonedTimestamps returns the machine-readable forms of t for JSON output:
+// RFC 3339 in loc (with its offset), RFC 3339 in UTC, and formatZoned's
+// display string.
+func zonedTimestamps(t time.Time, loc *time.Location) (at, utc, display string) {
+	return t.In(loc).Format(time.RFC3339), t.UTC().Format(time.RFC3339), formatZoned(t, loc)
+}
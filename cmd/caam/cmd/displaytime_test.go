@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatZoned(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	ts := time.Date(2026, 3, 14, 16, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		loc  *time.Location
+		want string
+	}{
+		{time.UTC, "2026-03-14 16:30 UTC"},
+		{berlin, "2026-03-14 17:30 CET (16:30 UTC)"},
+		{tokyo, "2026-03-15 01:30 JST (2026-03-14 16:30 UTC)"},
+	}
+	for _, tt := range tests {
+		if got := formatZoned(ts, tt.loc); got != tt.want {
+			t.Errorf("formatZoned(%s) = %q, want %q", tt.loc, got, tt.want)
+		}
+	}
+
+	at, utc, display := zonedTimestamps(ts, berlin)
+	if at != "2026-03-14T17:30:00+01:00" || utc != "2026-03-14T16:30:00Z" || display != "2026-03-14 17:30 CET (16:30 UTC)" {
+		t.Errorf("zonedTimestamps() = %q, %q, %q", at, utc, display)
+	}
+}
@@ -17,8 +17,11 @@ var envCmd = &cobra.Command{
 
 This allows you to set up the environment once and run multiple commands
 with the same profile, instead of using 'caam exec' wrapper each time.
+The variables are exactly the ones 'caam exec' sets (HOME, XDG_CONFIG_HOME,
+CODEX_HOME, API key variables, ...).
 
-The output is valid shell syntax (bash/zsh compatible).
+The output is valid shell syntax for --shell bash (default; also zsh/sh),
+fish or powershell.
 
 Examples:
   # Set up environment for codex work profile
@@ -30,21 +33,41 @@ Examples:
   eval "$(caam env claude personal)"
   claude
 
+  # fish and PowerShell
+  caam env codex work --shell fish | source
+  caam env codex work --shell powershell | Out-String | Invoke-Expression
+
   # Unset the variables when done
   eval "$(caam env codex work --unset)"
 
 Use --unset to print unset commands instead of export commands.
-Use --export-prefix to change the export syntax (default: "export").`,
+Use --export-prefix to change the bash export syntax (default: "export").`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
 		name := args[1]
 
+		unset, _ := cmd.Flags().GetBool("unset")
+		exportPrefix, _ := cmd.Flags().GetString("export-prefix")
+		shell, _ := cmd.Flags().GetString("shell")
+		if fishMode, _ := cmd.Flags().GetBool("fish"); fishMode {
+			shell = "fish"
+		}
+		shell = strings.ToLower(shell)
+		switch shell {
+		case "bash", "zsh", "sh", "fish", "powershell", "pwsh":
+		default:
+			return withExitCode(ExitUsage, fmt.Errorf("unsupported shell %q (supported: bash, zsh, sh, fish, powershell)", shell))
+		}
+
 		prov, ok := registry.Get(tool)
 		if !ok {
-			return fmt.Errorf("unknown provider: %s (supported: codex, claude, gemini)", tool)
+			return withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s (supported: %s)", tool, supportedTools()))
 		}
 
+		if !profileStore.Exists(tool, name) {
+			return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found", tool, name))
+		}
 		prof, err := profileStore.Load(tool, name)
 		if err != nil {
 			return err
@@ -56,40 +79,17 @@ Use --export-prefix to change the export syntax (default: "export").`,
 			return fmt.Errorf("get environment: %w", err)
 		}
 
-		unset, _ := cmd.Flags().GetBool("unset")
-		exportPrefix, _ := cmd.Flags().GetString("export-prefix")
-		fishMode, _ := cmd.Flags().GetBool("fish")
-
-		// Sort keys for consistent output
-		keys := make([]string, 0, len(envVars))
-		for k := range envVars {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-
-		// Print environment variables
-		for _, k := range keys {
-			if unset {
-				if fishMode {
-					fmt.Printf("set -e %s\n", k)
-				} else {
-					fmt.Printf("unset %s\n", k)
-				}
-			} else {
-				if fishMode {
-					fmt.Printf("set -gx %s %q\n", k, envVars[k])
-				} else {
-					fmt.Printf("%s %s=%q\n", exportPrefix, k, envVars[k])
-				}
-			}
+		out := cmd.OutOrStdout()
+		for _, line := range envLines(envVars, shell, exportPrefix, unset) {
+			fmt.Fprintln(out, line)
 		}
 
 		// Add a helpful comment
 		if !unset {
-			fmt.Printf("# Environment set for %s profile '%s'\n", tool, name)
-			fmt.Printf("# Run 'eval \"$(caam env %s %s --unset)\"' to unset\n", tool, name)
+			fmt.Fprintf(out, "# Environment set for %s profile '%s'\n", tool, name)
+			fmt.Fprintf(out, "# Run %s to unset\n", envUnsetHint(tool, name, shell))
 		} else {
-			fmt.Printf("# Environment unset for %s profile '%s'\n", tool, name)
+			fmt.Fprintf(out, "# Environment unset for %s profile '%s'\n", tool, name)
 		}
 
 		return nil
@@ -100,5 +100,53 @@ func init() {
 	rootCmd.AddCommand(envCmd)
 	envCmd.Flags().Bool("unset", false, "print unset commands instead of export")
 	envCmd.Flags().String("export-prefix", "export", "export syntax prefix (default: export)")
-	envCmd.Flags().Bool("fish", false, "use fish shell syntax")
+	envCmd.Flags().String("shell", "bash", "output syntax: bash, zsh, sh, fish or powershell")
+	envCmd.Flags().Bool("fish", false, "use fish shell syntax (same as --shell fish)")
+}
+
+// envLines renders envVars as set (or unset) statements for shell, sorted by
+// name for consistent output.
+func envLines(envVars map[string]string, shell, exportPrefix string, unset bool) []string {
+	keys := make([]string, 0, len(envVars))
+	for k := range envVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := envVars[k]
+		switch shell {
+		case "fish":
+			if unset {
+				lines = append(lines, "set -e "+k)
+			} else {
+				lines = append(lines, fmt.Sprintf("set -gx %s %s", k, fishQuote(v)))
+			}
+		case "powershell", "pwsh":
+			if unset {
+				lines = append(lines, fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", k))
+			} else {
+				lines = append(lines, fmt.Sprintf("$env:%s = %s", k, powershellQuote(v)))
+			}
+		default:
+			if unset {
+				lines = append(lines, "unset "+k)
+			} else {
+				lines = append(lines, fmt.Sprintf("%s %s=%s", exportPrefix, k, shellQuote(v)))
+			}
+		}
+	}
+	return lines
+}
+
+func envUnsetHint(tool, name, shell string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("'caam env %s %s --shell fish --unset | source'", tool, name)
+	case "powershell", "pwsh":
+		return fmt.Sprintf("'caam env %s %s --shell powershell --unset | Out-String | Invoke-Expression'", tool, name)
+	default:
+		return fmt.Sprintf("'eval \"$(caam env %s %s --unset)\"'", tool, name)
+	}
 }
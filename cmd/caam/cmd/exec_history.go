@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/spf13/cobra"
+)
+
+// historyExecCmd lists what 'caam exec' ran with each profile.
+var historyExecCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "List what caam exec ran with each profile",
+	Long: `Show the 'caam exec' runs recorded per profile, to see what each account
+was actually used for when reviewing bills or limit usage.
+
+Recording is off by default. Turn it on in ~/.caam/config.yaml:
+
+  caam config set analytics.exec_history truncate  # redacted arguments, cut short
+  caam config set analytics.exec_history hash      # only a fingerprint of them
+
+Arguments are redacted before they are stored: values of secret-looking flags
+(--token, --api-key, ...), NAME=value pairs with secret-looking names, and
+anything shaped like an API key or token. Truncate mode then keeps the first
+analytics.exec_history_chars characters. Records expire with
+analytics.retention_days.
+
+Examples:
+  caam history exec --profile work
+  caam history exec --provider claude --since 7d
+  caam history exec --json`,
+	Args: cobra.NoArgs,
+	RunE: runHistoryExec,
+}
+
+func init() {
+	historyCmd.AddCommand(historyExecCmd)
+	historyExecCmd.Flags().IntP("limit", "n", 20, "maximum number of runs to show (0 = all)")
+	historyExecCmd.Flags().String("provider", "", "filter by provider (claude, codex, gemini)")
+	historyExecCmd.Flags().String("profile", "", "filter by profile name")
+	historyExecCmd.Flags().String("since", "", "only runs newer than duration (e.g., '24h', '7d')")
+	historyExecCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+var (
+	// secretName matches flag and variable names whose values are secrets:
+	// --token, --api-key, OPENAI_API_KEY, GITHUB_TOKEN, DB_PASSWORD, ...
+	secretName = regexp.MustCompile(`(?i)^-*([a-z0-9]+[-_])*(token|secret|password|passwd|pass|api[-_]?key|key|auth|credentials?)$`)
+	// secretValue matches credentials with well-known prefixes and JWTs.
+	secretValue = regexp.MustCompile(`\b(sk-[A-Za-z0-9_-]{8,}|gh[pousr]_[A-Za-z0-9]{16,}|github_pat_[A-Za-z0-9_]{16,}|xox[abprs]-[A-Za-z0-9-]{8,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{20,}|eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_.-]+)`)
+	// longToken matches long unbroken runs of base64/hex; looksLikeSecret
+	// decides whether one is a credential.
+	longToken = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+)
+
+// looksLikeSecret tells a credential from a long path or identifier:
+// credentials mix letters and digits.
+func looksLikeSecret(s string) bool {
+	if !strings.ContainsAny(s, "0123456789") {
+		return false
+	}
+	return strings.IndexFunc(s, func(r rune) bool { return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' }) >= 0
+}
+
+const redacted = "<redacted>"
+
+// redactExecArgs masks secrets in a command line.
+func redactExecArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		if i > 0 && secretName.MatchString(args[i-1]) && strings.HasPrefix(args[i-1], "-") && !strings.HasPrefix(arg, "-") {
+			out[i] = redacted
+			continue
+		}
+		if name, _, ok := strings.Cut(arg, "="); ok && secretName.MatchString(name) {
+			out[i] = name + "=" + redacted
+			continue
+		}
+		arg = secretValue.ReplaceAllString(arg, redacted)
+		out[i] = longToken.ReplaceAllStringFunc(arg, func(m string) string {
+			if looksLikeSecret(m) {
+				return redacted
+			}
+			return m
+		})
+	}
+	return out
+}
+
+// captureExecArgs returns what exec history stores for args in the given
+// mode: the redacted text (truncate mode only) and a fingerprint of it.
+func captureExecArgs(mode string, maxChars int, args []string) (text, hash string) {
+	clean := redactExecArgs(args)
+	quoted := make([]string, len(clean))
+	for i, arg := range clean {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	joined := strings.Join(quoted, " ")
+	sum := sha256.Sum256([]byte(joined))
+	hash = hex.EncodeToString(sum[:8])
+
+	if mode != config.ExecHistoryTruncate {
+		return "", hash
+	}
+	if runes := []rune(joined); maxChars > 0 && len(runes) > maxChars {
+		joined = string(runes[:maxChars]) + "…"
+	}
+	return joined, hash
+}
+
+// recordExecRun stores a finished 'caam exec' run when analytics.exec_history
+// asks for it. Failing to record never fails the run.
+func recordExecRun(provider, profileName string, args []string, started time.Time, runErr error) {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return
+	}
+	mode := spmCfg.Analytics.ExecHistory
+	if mode != config.ExecHistoryHash && mode != config.ExecHistoryTruncate {
+		return
+	}
+
+	text, hash := captureExecArgs(mode, spmCfg.Analytics.ExecHistoryChars, args)
+	workDir, _ := os.Getwd()
+	db, err := getDB()
+	if err == nil {
+		err = db.RecordExec(caamdb.ExecRecord{
+			StartedAt:   started,
+			Provider:    provider,
+			ProfileName: profileName,
+			Mode:        mode,
+			Args:        text,
+			ArgsHash:    hash,
+			WorkDir:     workDir,
+			Duration:    time.Since(started),
+			ExitCode:    ExitCode(runErr),
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: exec history not recorded: %v\n", err)
+	}
+}
+
+type historyExecOutput struct {
+	Runs  []historyExecRun `json:"runs"`
+	Count int              `json:"count"`
+}
+
+type historyExecRun struct {
+	StartedAt       string `json:"started_at"`
+	Provider        string `json:"provider"`
+	Profile         string `json:"profile"`
+	Mode            string `json:"mode"`
+	Args            string `json:"args,omitempty"`
+	ArgsHash        string `json:"args_hash"`
+	WorkDir         string `json:"work_dir,omitempty"`
+	DurationSeconds int64  `json:"duration_seconds"`
+	ExitCode        int    `json:"exit_code"`
+}
+
+func runHistoryExec(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	provider, _ := cmd.Flags().GetString("provider")
+	profileName, _ := cmd.Flags().GetString("profile")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var since time.Time
+	if sinceStr != "" {
+		duration, err := parseDuration(sinceStr)
+		if err != nil {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid --since duration: %w", err))
+		}
+		since = time.Now().Add(-duration)
+	}
+
+	db, err := getDB()
+	if err != nil {
+		return err
+	}
+	runs, err := db.ExecHistory(strings.ToLower(provider), profileName, since, limit)
+	if err != nil {
+		return fmt.Errorf("get exec history: %w", err)
+	}
+
+	if jsonOutput {
+		return renderExecHistoryJSON(cmd.OutOrStdout(), runs)
+	}
+	if len(runs) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No exec runs recorded.")
+		if spmCfg, err := config.LoadSPMConfig(); err == nil {
+			if mode := spmCfg.Analytics.ExecHistory; mode != config.ExecHistoryHash && mode != config.ExecHistoryTruncate {
+				fmt.Fprintln(cmd.OutOrStdout(), "Exec history is off; turn it on with: caam config set analytics.exec_history truncate")
+			}
+		}
+		return nil
+	}
+	return renderExecHistory(cmd.OutOrStdout(), runs)
+}
+
+func renderExecHistoryJSON(w io.Writer, runs []caamdb.ExecRecord) error {
+	output := historyExecOutput{Runs: make([]historyExecRun, len(runs)), Count: len(runs)}
+	for i, r := range runs {
+		output.Runs[i] = historyExecRun{
+			StartedAt:       r.StartedAt.UTC().Format(time.RFC3339),
+			Provider:        r.Provider,
+			Profile:         r.ProfileName,
+			Mode:            r.Mode,
+			Args:            r.Args,
+			ArgsHash:        r.ArgsHash,
+			WorkDir:         r.WorkDir,
+			DurationSeconds: int64(r.Duration.Seconds()),
+			ExitCode:        r.ExitCode,
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+func renderExecHistory(w io.Writer, runs []caamdb.ExecRecord) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "STARTED\tPROFILE\tDURATION\tEXIT\tCOMMAND")
+	for _, r := range runs {
+		command := r.Args
+		if r.Mode == config.ExecHistoryHash {
+			command = "#" + r.ArgsHash
+		} else if command == "" {
+			command = "(interactive)"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s/%s\t%s\t%d\t%s\n",
+			r.StartedAt.Local().Format("2006-01-02 15:04"),
+			r.Provider,
+			r.ProfileName,
+			formatDurationShort(r.Duration),
+			r.ExitCode,
+			command,
+		)
+	}
+	return tw.Flush()
+}
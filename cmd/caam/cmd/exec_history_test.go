@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+func TestRedactExecArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"-p", "fix the login bug"}, []string{"-p", "fix the login bug"}},
+		{[]string{"--api-key", "abc123", "-p", "hi"}, []string{"--api-key", redacted, "-p", "hi"}},
+		{[]string{"--token=abc123"}, []string{"--token=" + redacted}},
+		{[]string{"OPENAI_API_KEY=abc123", "run"}, []string{"OPENAI_API_KEY=" + redacted, "run"}},
+		{[]string{"-p", "use sk-ant-api03-abcdefghijkl to call it"}, []string{"-p", "use " + redacted + " to call it"}},
+		{[]string{"-p", "token 9f8e7d6c5b4a39281706f5e4d3c2b1a0ffee here"}, []string{"-p", "token " + redacted + " here"}},
+		{[]string{"--model", "gpt-5", "src/components/navigation_sidebar_container"}, []string{"--model", "gpt-5", "src/components/navigation_sidebar_container"}},
+		{[]string{"--key", "--verbose"}, []string{"--key", "--verbose"}},
+	}
+	for _, tt := range tests {
+		got := redactExecArgs(tt.args)
+		if strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") {
+			t.Errorf("redactExecArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureExecArgs(t *testing.T) {
+	args := []string{"-p", "refactor the payment service", "--token", "s3cr3t"}
+
+	text, hash := captureExecArgs(config.ExecHistoryTruncate, 20, args)
+	if text != `-p "refactor the pay…` {
+		t.Errorf("truncate text = %q", text)
+	}
+	if strings.Contains(text, "s3cr3t") {
+		t.Error("truncate text leaked the secret")
+	}
+	if len(hash) != 16 {
+		t.Errorf("hash = %q, want 16 hex chars", hash)
+	}
+
+	full, _ := captureExecArgs(config.ExecHistoryTruncate, 0, args)
+	if full != `-p "refactor the payment service" --token `+redacted {
+		t.Errorf("untruncated text = %q", full)
+	}
+
+	hashOnly, sameHash := captureExecArgs(config.ExecHistoryHash, 20, args)
+	if hashOnly != "" || sameHash != hash {
+		t.Errorf("hash mode = %q, %q; want no text and hash %q", hashOnly, sameHash, hash)
+	}
+
+	// The fingerprint is taken after redaction, so it doesn't depend on
+	// (or reveal) the secret.
+	if _, other := captureExecArgs(config.ExecHistoryHash, 0, []string{"-p", "refactor the payment service", "--token", "other"}); other != hash {
+		t.Errorf("hash changed with the redacted secret: %q vs %q", other, hash)
+	}
+}
+
+func TestRenderExecHistory(t *testing.T) {
+	runs := []caamdb.ExecRecord{
+		{StartedAt: time.Now(), Provider: "claude", ProfileName: "work", Mode: config.ExecHistoryTruncate, Args: `-p "fix bug"`, ArgsHash: "abcd", Duration: 2 * time.Minute, ExitCode: 0},
+		{StartedAt: time.Now(), Provider: "codex", ProfileName: "home", Mode: config.ExecHistoryHash, ArgsHash: "ef01", ExitCode: 3},
+		{StartedAt: time.Now(), Provider: "codex", ProfileName: "home", Mode: config.ExecHistoryTruncate},
+	}
+
+	var buf bytes.Buffer
+	if err := renderExecHistory(&buf, runs); err != nil {
+		t.Fatalf("renderExecHistory() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"claude/work", `-p "fix bug"`, "codex/home", "#ef01", "(interactive)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
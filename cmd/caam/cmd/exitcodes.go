@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/exec"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
+)
+
+// Exit codes are part of caam's scripting interface: they stay stable so
+// scripts can branch on the failure class instead of matching error text.
+//
+// 'caam run' and 'caam exec' pass the wrapped tool's own exit code through
+// unchanged once the tool has started.
+const (
+	ExitOK               = 0 // success
+	ExitError            = 1 // unclassified failure
+	ExitUsage            = 2 // invalid arguments, unknown tool, or missing input
+	ExitNoHealthyProfile = 3 // profiles exist but none is usable
+	ExitAllInCooldown    = 4 // the requested profile, or every candidate, is in cooldown
+	ExitAuthMissing      = 5 // the profile or its auth files don't exist
+	ExitLockContention   = 6 // the profile is locked by another process
+	ExitClassMismatch    = 7 // the profile's class isn't allowed in this directory
+	ExitForcedShutdown   = 8 // a long-running command was stopped before it finished cleaning up
+)
+
+// exitCodeError attaches an exit code, and optionally an error code, to an
+// error returned from a command.
+type exitCodeError struct {
+	code int
+	// name is the error code reported as error_code in --json output; ""
+	// lets errorCode derive it from the exit code.
+	name string
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// withExitCode tags err with an exit code. It returns nil for a nil err.
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: code, err: err}
+}
+
+// withErrorCode tags err with one of the robot mode error codes and the exit
+// code that goes with it. It returns nil for a nil err.
+func withErrorCode(name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &exitCodeError{code: robotExitCode(name), name: name, err: err}
+}
+
+// pendingExitCode is set by commands that report failure in their own output
+// (e.g. JSON mode) and return nil so the error isn't printed twice.
+var pendingExitCode = ExitOK
+
+// errReportedFailure is returned by Execute when a command reported its own
+// failure via pendingExitCode.
+var errReportedFailure = errors.New("command failed")
+
+// ExitCode maps an error returned by Execute to a process exit code.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	var toolExit *exec.ExitCodeError
+	if errors.As(err, &toolExit) {
+		return toolExit.Code
+	}
+
+	switch {
+	case errors.Is(err, rotation.ErrAllInCooldown):
+		return ExitAllInCooldown
+	case errors.Is(err, rotation.ErrNoProfiles):
+		return ExitAuthMissing
+	case errors.Is(err, profile.ErrLocked):
+		return ExitLockContention
+	}
+	return ExitError
+}
+
+// robotExitCode maps a robot error code to a process exit code.
+func robotExitCode(code string) int {
+	switch code {
+	case "INVALID_PROVIDER", "INVALID_ACTION", "UNKNOWN_KEY", "MISSING_PROFILE", "IDEMPOTENCY_KEY_REUSED":
+		return ExitUsage
+	case "ALL_BLOCKED":
+		return ExitAllInCooldown
+	case "NO_PROFILES", "NO_AUTH":
+		return ExitAuthMissing
+	case "LOCK_ACTIVE", "ACTION_IN_PROGRESS":
+		return ExitLockContention
+	}
+	return ExitError
+}
+
+// errorCode names err's failure class for the error_code field of --json
+// output. Codes come from the robot mode taxonomy so one script can handle
+// both; errors not tagged with withErrorCode get the code of their exit code
+// class. Like exit codes, the names are stable.
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var coded *exitCodeError
+	for e := err; errors.As(e, &coded); e = coded.err {
+		if coded.name != "" {
+			return coded.name
+		}
+	}
+
+	switch {
+	case errors.Is(err, errCancelled):
+		return "CANCELLED"
+	case errors.Is(err, rotation.ErrNoProfiles):
+		return "NO_PROFILES"
+	}
+	var toolExit *exec.ExitCodeError
+	if errors.As(err, &toolExit) {
+		return "TOOL_FAILED"
+	}
+
+	switch ExitCode(err) {
+	case ExitUsage:
+		return "INVALID_ARGUMENT"
+	case ExitNoHealthyProfile:
+		return "NO_HEALTHY_PROFILE"
+	case ExitAllInCooldown:
+		return "ALL_BLOCKED"
+	case ExitAuthMissing:
+		return "NO_AUTH"
+	case ExitLockContention:
+		return "LOCK_ACTIVE"
+	case ExitClassMismatch:
+		return "CLASS_MISMATCH"
+	case ExitForcedShutdown:
+		return "FORCED_SHUTDOWN"
+	}
+	return "ERROR"
+}
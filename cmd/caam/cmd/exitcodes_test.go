@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/exec"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"plain", errors.New("boom"), ExitError},
+		{"tagged", withExitCode(ExitUsage, errors.New("bad flag")), ExitUsage},
+		{"tagged and wrapped", fmt.Errorf("outer: %w", withExitCode(ExitNoHealthyProfile, errors.New("x"))), ExitNoHealthyProfile},
+		{"all in cooldown", fmt.Errorf("rotation select: %w", &rotation.SelectionError{Tool: "claude", Err: rotation.ErrAllInCooldown}), ExitAllInCooldown},
+		{"no profiles", &rotation.SelectionError{Tool: "claude", Err: rotation.ErrNoProfiles}, ExitAuthMissing},
+		{"locked", fmt.Errorf("lock profile: %w", &profile.LockedError{Name: "work"}), ExitLockContention},
+		{"tool exit", &exec.ExitCodeError{Code: 42}, 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestWithExitCode_Nil(t *testing.T) {
+	assert.NoError(t, withExitCode(ExitUsage, nil))
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), "ERROR"},
+		{"usage", withExitCode(ExitUsage, errors.New("bad flag")), "INVALID_ARGUMENT"},
+		{"named", withErrorCode("INVALID_PROVIDER", errors.New("unknown tool")), "INVALID_PROVIDER"},
+		{"named and wrapped", fmt.Errorf("outer: %w", withErrorCode("ACTIVATE_FAILED", errors.New("x"))), "ACTIVATE_FAILED"},
+		{"retagged exit code keeps name", withExitCode(ExitAuthMissing, withErrorCode("VAULT_ERROR", errors.New("x"))), "VAULT_ERROR"},
+		{"auth missing", withExitCode(ExitAuthMissing, errors.New("x")), "NO_AUTH"},
+		{"all in cooldown", &rotation.SelectionError{Tool: "claude", Err: rotation.ErrAllInCooldown}, "ALL_BLOCKED"},
+		{"no profiles", &rotation.SelectionError{Tool: "claude", Err: rotation.ErrNoProfiles}, "NO_PROFILES"},
+		{"locked", fmt.Errorf("lock profile: %w", &profile.LockedError{Name: "work"}), "LOCK_ACTIVE"},
+		{"cancelled", errCancelled, "CANCELLED"},
+		{"tool exit", &exec.ExitCodeError{Code: 42}, "TOOL_FAILED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errorCode(tt.err))
+		})
+	}
+}
+
+func TestWithErrorCode(t *testing.T) {
+	assert.NoError(t, withErrorCode("NO_AUTH", nil))
+	assert.Equal(t, ExitAuthMissing, ExitCode(withErrorCode("NO_AUTH", errors.New("x"))))
+	assert.Equal(t, ExitUsage, ExitCode(withErrorCode("INVALID_PROVIDER", errors.New("x"))))
+}
+
+func TestRobotExitCode(t *testing.T) {
+	assert.Equal(t, ExitUsage, robotExitCode("INVALID_PROVIDER"))
+	assert.Equal(t, ExitAllInCooldown, robotExitCode("ALL_BLOCKED"))
+	assert.Equal(t, ExitAuthMissing, robotExitCode("NO_PROFILES"))
+	assert.Equal(t, ExitError, robotExitCode("DB_ERROR"))
+}
@@ -0,0 +1,342 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain caam's automatic decisions",
+	Long:  `Shows the reasoning behind decisions caam makes on your behalf.`,
+}
+
+var explainNextCmd = &cobra.Command{
+	Use:   "next <tool>",
+	Short: "Show how each profile scores for automatic selection",
+	Long: `Breaks down the score of every profile for a tool, component by
+component, so you can see why a profile was (or wasn't) picked.
+
+Two scorers are shown:
+  - robot next:  'caam robot next' (health, cooldown, recent errors, token
+                 expiry, estimated limit headroom, time since last use)
+  - rotation:    'caam next' and 'caam activate --auto' (cooldown, health,
+                 penalty, plan, recency, usage; smart rotation adds a few
+                 points of random jitter, so close scores can swap)
+
+The selected profile is marked with *. Profiles a scorer never considers -
+quarantined ones, those in cooldown for robot next, or those ruled out by
+the directory's profile class for rotation - are listed as skipped.
+
+Examples:
+  caam explain next claude
+  caam explain next codex --strategy lru
+  caam explain next claude --algorithm round_robin --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplainNext,
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.AddCommand(explainNextCmd)
+	explainNextCmd.Flags().String("strategy", "smart", "robot next strategy: smart, lru, random")
+	explainNextCmd.Flags().Bool("include-cooldown", false, "score robot next profiles in cooldown too")
+	explainNextCmd.Flags().String("algorithm", "", "rotation algorithm override: smart, round_robin, random")
+	explainNextCmd.Flags().Bool("json", false, "output in JSON format")
+	registerValueCompletion(explainNextCmd, "strategy", "smart", "lru", "random")
+	registerValueCompletion(explainNextCmd, "algorithm", "smart", "round_robin", "random")
+}
+
+// explainScore is one profile's score breakdown.
+type explainScore struct {
+	Profile  string             `json:"profile"`
+	Score    float64            `json:"score"`
+	Selected bool               `json:"selected,omitempty"`
+	Factors  map[string]float64 `json:"factors"`
+	Reasons  []string           `json:"reasons,omitempty"`
+
+	order []string
+}
+
+// explainSkip is a profile a scorer did not consider.
+type explainSkip struct {
+	Profile string `json:"profile"`
+	Reason  string `json:"reason"`
+}
+
+// explainScorer is the outcome of one scorer.
+type explainScorer struct {
+	Scorer   string         `json:"scorer"`
+	Mode     string         `json:"mode"`
+	Selected string         `json:"selected,omitempty"`
+	Profiles []explainScore `json:"profiles"`
+	Skipped  []explainSkip  `json:"skipped,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// explainNextOutput is the JSON output structure for explain next.
+type explainNextOutput struct {
+	Tool           string          `json:"tool"`
+	CurrentProfile string          `json:"current_profile,omitempty"`
+	Scorers        []explainScorer `json:"scorers"`
+}
+
+func runExplainNext(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	strategy, _ := cmd.Flags().GetString("strategy")
+	includeCooldown, _ := cmd.Flags().GetBool("include-cooldown")
+	algoOverride, _ := cmd.Flags().GetString("algorithm")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+	if strategy == "" {
+		strategy = "smart"
+	}
+	switch strategy {
+	case "smart", "lru", "random":
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("invalid strategy %q (use smart, lru or random)", strategy))
+	}
+	switch rotation.Algorithm(algoOverride) {
+	case "", rotation.AlgorithmSmart, rotation.AlgorithmRoundRobin, rotation.AlgorithmRandom:
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("invalid algorithm %q (use smart, round_robin or random)", algoOverride))
+	}
+
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+	profiles, err := vault.List(tool)
+	if err != nil {
+		return fmt.Errorf("list profiles: %w", err)
+	}
+	if len(profiles) == 0 {
+		return withExitCode(ExitAuthMissing, fmt.Errorf("no profiles found for %s; create one with 'caam backup %s <name>'", tool, tool))
+	}
+	currentProfile, _ := vault.ActiveProfile(getFileSet())
+
+	db, err := caamdb.Open()
+	if err == nil {
+		defer db.Close()
+	} else {
+		db = nil
+	}
+
+	output := explainNextOutput{
+		Tool:           tool,
+		CurrentProfile: currentProfile,
+		Scorers: []explainScorer{
+			explainRobotNext(db, tool, profiles, strategy, includeCooldown),
+			explainRotation(db, tool, profiles, currentProfile, algoOverride),
+		},
+	}
+
+	out := cmd.OutOrStdout()
+	if jsonOutput {
+		data, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal json: %w", err)
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Profile scores for %s", tool)
+	if currentProfile != "" {
+		fmt.Fprintf(out, " (active: %s)", currentProfile)
+	}
+	fmt.Fprintln(out)
+	for _, s := range output.Scorers {
+		fmt.Fprintln(out)
+		printExplainScorer(out, s)
+	}
+	return nil
+}
+
+// explainRobotNext scores profiles the way 'caam robot next' does.
+func explainRobotNext(db *caamdb.DB, tool string, profiles []string, strategy string, includeCooldown bool) explainScorer {
+	result := explainScorer{
+		Scorer: "caam robot next",
+		Mode:   "strategy " + strategy,
+	}
+	scored := rankRobotNext(db, tool, profiles, strategy, includeCooldown, time.Now())
+
+	quarantined := make(map[string]bool)
+	for _, p := range quarantinedProfiles(tool) {
+		quarantined[p] = true
+	}
+	considered := make(map[string]bool, len(scored))
+	for i, sp := range scored {
+		considered[sp.name] = true
+		entry := newExplainScore(sp.score, sp.reasons)
+		entry.Selected = i == 0
+		result.Profiles = append(result.Profiles, entry)
+	}
+	if len(scored) > 0 {
+		result.Selected = scored[0].name
+	}
+	for _, p := range profiles {
+		switch {
+		case considered[p]:
+		case quarantined[p]:
+			result.Skipped = append(result.Skipped, explainSkip{Profile: p, Reason: "quarantined"})
+		default:
+			result.Skipped = append(result.Skipped, explainSkip{Profile: p, Reason: "in cooldown (use --include-cooldown to score it)"})
+		}
+	}
+	return result
+}
+
+// explainRotation scores profiles the way 'caam next' and
+// 'caam activate --auto' do.
+func explainRotation(db *caamdb.DB, tool string, profiles []string, currentProfile, algoOverride string) explainScorer {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		spmCfg = config.DefaultSPMConfig()
+	}
+	applyContextStrategy(spmCfg)
+	if algoOverride != "" {
+		spmCfg.Stealth.Rotation.Algorithm = algoOverride
+	}
+	algorithm := strings.TrimSpace(spmCfg.Stealth.Rotation.Algorithm)
+	if algorithm == "" {
+		algorithm = string(rotation.AlgorithmSmart)
+	}
+
+	result := explainScorer{
+		Scorer: "caam next / caam activate --auto",
+		Mode:   "algorithm " + algorithm,
+	}
+
+	allowed, required := filterProfilesForClass(tool, profiles)
+	isAllowed := make(map[string]bool, len(allowed))
+	for _, p := range allowed {
+		isAllowed[p] = true
+	}
+	quarantined := make(map[string]bool)
+	for _, p := range quarantinedProfiles(tool) {
+		quarantined[p] = true
+	}
+	for _, p := range profiles {
+		switch {
+		case quarantined[p]:
+			result.Skipped = append(result.Skipped, explainSkip{Profile: p, Reason: "quarantined"})
+		case !isAllowed[p]:
+			result.Skipped = append(result.Skipped, explainSkip{Profile: p, Reason: fmt.Sprintf("this directory requires %s profiles", required)})
+		case strings.HasPrefix(p, "_"):
+			result.Skipped = append(result.Skipped, explainSkip{Profile: p, Reason: "system profile"})
+		}
+	}
+
+	selection, err := selectProfileWithRotation(tool, allowed, currentProfile, spmCfg, db)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Selected = selection.Selected
+	for _, ps := range selection.Alternatives {
+		reasons := make([]string, 0, len(ps.Reasons))
+		for _, r := range ps.Reasons {
+			reasons = append(reasons, r.Text)
+		}
+		entry := newExplainScore(ps, reasons)
+		entry.Selected = ps.Name == selection.Selected
+		result.Profiles = append(result.Profiles, entry)
+	}
+	return result
+}
+
+func newExplainScore(ps rotation.ProfileScore, reasons []string) explainScore {
+	entry := explainScore{
+		Profile: ps.Name,
+		Score:   ps.Score,
+		Factors: make(map[string]float64, len(ps.Factors)),
+		Reasons: reasons,
+	}
+	for _, f := range ps.Factors {
+		entry.Factors[f.Name] = f.Points
+		entry.order = append(entry.order, f.Name)
+	}
+	return entry
+}
+
+// printExplainScorer prints a scorer's breakdown as a table with one column
+// per factor, followed by skipped profiles and each profile's reasons.
+func printExplainScorer(out io.Writer, s explainScorer) {
+	fmt.Fprintf(out, "%s (%s)\n", s.Scorer, s.Mode)
+	if s.Error != "" {
+		fmt.Fprintf(out, "  No selection: %s\n", s.Error)
+	}
+
+	var factors []string
+	seen := make(map[string]bool)
+	for _, p := range s.Profiles {
+		for _, name := range p.order {
+			if !seen[name] {
+				seen[name] = true
+				factors = append(factors, name)
+			}
+		}
+	}
+
+	if len(s.Profiles) > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprint(w, "\tPROFILE\t")
+		for _, name := range factors {
+			fmt.Fprintf(w, "%s\t", strings.ToUpper(name))
+		}
+		fmt.Fprint(w, "TOTAL\t\n")
+		for _, p := range s.Profiles {
+			marker := ""
+			if p.Selected {
+				marker = "*"
+			}
+			fmt.Fprintf(w, "%s\t%s\t", marker, p.Profile)
+			for _, name := range factors {
+				if points, ok := p.Factors[name]; ok {
+					fmt.Fprintf(w, "%+.1f\t", points)
+				} else {
+					fmt.Fprint(w, "-\t")
+				}
+			}
+			fmt.Fprintf(w, "%.1f\t\n", p.Score)
+		}
+		w.Flush()
+	}
+
+	if len(s.Skipped) > 0 {
+		sort.SliceStable(s.Skipped, func(i, j int) bool { return s.Skipped[i].Profile < s.Skipped[j].Profile })
+		fmt.Fprintln(out, "  Skipped:")
+		for _, sk := range s.Skipped {
+			fmt.Fprintf(out, "    %s: %s\n", sk.Profile, sk.Reason)
+		}
+	}
+
+	var withReasons []explainScore
+	for _, p := range s.Profiles {
+		if len(p.Reasons) > 0 {
+			withReasons = append(withReasons, p)
+		}
+	}
+	if len(withReasons) > 0 {
+		fmt.Fprintln(out, "  Why:")
+		for _, p := range withReasons {
+			fmt.Fprintf(out, "    %s: %s\n", p.Profile, strings.Join(p.Reasons, "; "))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+)
+
+func TestExplainNext_ShowsBothScorers(t *testing.T) {
+	setupAccountsTest(t)
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+
+	originalHealth, originalProjects := healthStore, projectStore
+	t.Cleanup(func() { healthStore, projectStore = originalHealth, originalProjects })
+	healthStore = health.NewStorage(filepath.Join(tmpDir, "health.json"))
+	projectStore = project.NewStore(filepath.Join(tmpDir, "projects.json"))
+
+	for _, name := range []string{"alpha", "beta"} {
+		dir := vault.ProfilePath("codex", name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"`+name+`"}`), 0600))
+	}
+
+	explainFlags := func(jsonOut bool) (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("strategy", "smart", "")
+		cmd.Flags().Bool("include-cooldown", false, "")
+		cmd.Flags().String("algorithm", "", "")
+		cmd.Flags().Bool("json", jsonOut, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		return cmd, &out
+	}
+
+	cmd, out := explainFlags(true)
+	require.NoError(t, runExplainNext(cmd, []string{"codex"}))
+	var result explainNextOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &result))
+	require.Len(t, result.Scorers, 2)
+	for _, scorer := range result.Scorers {
+		assert.Empty(t, scorer.Error, scorer.Scorer)
+		require.Len(t, scorer.Profiles, 2, scorer.Scorer)
+		assert.Equal(t, scorer.Profiles[0].Profile, scorer.Selected, scorer.Scorer)
+		for _, p := range scorer.Profiles {
+			var sum float64
+			for _, points := range p.Factors {
+				sum += points
+			}
+			assert.InDelta(t, p.Score, sum, 1e-9, "%s: %s factors should sum to its score", scorer.Scorer, p.Profile)
+			assert.False(t, math.IsNaN(p.Score))
+		}
+	}
+	assert.Contains(t, result.Scorers[0].Profiles[0].Factors, "health")
+
+	cmd, out = explainFlags(false)
+	require.NoError(t, runExplainNext(cmd, []string{"codex"}))
+	assert.Contains(t, out.String(), "caam robot next (strategy smart)")
+	assert.Contains(t, out.String(), "TOTAL")
+	assert.Contains(t, out.String(), "*")
+
+	cmd, _ = explainFlags(false)
+	require.NoError(t, cmd.Flags().Set("strategy", "bogus"))
+	assert.Equal(t, ExitUsage, ExitCode(runExplainNext(cmd, []string{"codex"})))
+}
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/bundle"
 )
 
 var exportCmd = &cobra.Command{
@@ -21,7 +24,14 @@ This is the recommended way to set up profiles on headless servers:
 4. Import on server: caam import profile.tar.gz
 5. Activate: caam activate codex work
 
-The exported file contains only the auth credentials, not session state.`,
+The exported file contains only the auth credentials, not session state.
+
+--armor encrypts a single profile with a password (--password, else
+runtime.passphrase_command, else a prompt) into one line of text that can
+be pasted anywhere or piped over SSH; --to-clipboard puts that text on the
+clipboard instead:
+  caam export claude/work --armor | ssh box caam import -
+  caam export claude/work --to-clipboard    # then: caam import --from-clipboard`,
 	Args: cobra.RangeArgs(0, 2),
 	RunE: runExport,
 }
@@ -29,6 +39,10 @@ The exported file contains only the auth credentials, not session state.`,
 func init() {
 	exportCmd.Flags().Bool("all", false, "export all profiles (use with optional <tool>)")
 	exportCmd.Flags().StringP("output", "o", "", "write archive to file instead of stdout")
+	exportCmd.Flags().Bool("armor", false, "write one profile as a password-encrypted line of text")
+	exportCmd.Flags().Bool("to-clipboard", false, "copy the armored profile to the clipboard (implies --armor)")
+	exportCmd.Flags().StringP("password", "p", "", "password for --armor")
+	exportCmd.MarkFlagsMutuallyExclusive("output", "to-clipboard")
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
@@ -63,6 +77,9 @@ func runExport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if armor, _ := cmd.Flags().GetBool("armor"); armor || cmd.Flags().Changed("to-clipboard") {
+		return runExportArmored(cmd, targets, manifest, files, outPath)
+	}
 
 	var (
 		w     io.Writer
@@ -99,6 +116,48 @@ func runExport(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runExportArmored writes the one target as an armored payload to the
+// clipboard, outPath or stdout.
+func runExportArmored(cmd *cobra.Command, targets []exportTarget, manifest *vaultExportManifest, files []exportFileSpec, outPath string) error {
+	if len(targets) != 1 {
+		return withExitCode(ExitUsage, fmt.Errorf("--armor exports one profile, not %d; use 'caam bundle export' for more", len(targets)))
+	}
+	toClipboard, _ := cmd.Flags().GetBool("to-clipboard")
+	password, _ := cmd.Flags().GetString("password")
+	password, err := encryptionPassword(password)
+	if err != nil {
+		return err
+	}
+
+	var archive bytes.Buffer
+	if err := writeExportArchive(&archive, manifest, files); err != nil {
+		return err
+	}
+	text, err := bundle.Armor(archive.Bytes(), password)
+	if err != nil {
+		return err
+	}
+
+	errOut := cmd.ErrOrStderr()
+	target := fmt.Sprintf("%s/%s", targets[0].Tool, targets[0].Profile)
+	switch {
+	case toClipboard:
+		if err := writeClipboard([]byte(text)); err != nil {
+			return err
+		}
+		fmt.Fprintf(errOut, "Copied %s to the clipboard (%d bytes, encrypted)\n", target, len(text))
+		fmt.Fprintln(errOut, "  Import with: caam import --from-clipboard")
+	case outPath != "":
+		if err := os.WriteFile(outPath, []byte(text+"\n"), 0600); err != nil {
+			return fmt.Errorf("write output file: %w", err)
+		}
+		fmt.Fprintf(errOut, "Exported %s to %s (encrypted)\n", target, outPath)
+	default:
+		fmt.Fprintln(cmd.OutOrStdout(), text)
+	}
+	return nil
+}
+
 func parseToolProfileArg(arg string) (tool, profile string, err error) {
 	arg = strings.TrimSpace(arg)
 	if arg == "" {
@@ -1,7 +1,18 @@
 package cmd
 
 import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/bundle"
 )
 
 // =============================================================================
@@ -222,3 +233,91 @@ func TestParseToolProfileArg(t *testing.T) {
 		})
 	}
 }
+
+func newArmorExportCmd(flags map[string]string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("all", false, "")
+	cmd.Flags().String("output", "", "")
+	cmd.Flags().Bool("armor", false, "")
+	cmd.Flags().Bool("to-clipboard", false, "")
+	cmd.Flags().String("password", "", "")
+	for name, value := range flags {
+		_ = cmd.Flags().Set(name, value)
+	}
+	return cmd
+}
+
+func newArmorImportCmd(flags map[string]string) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("as", "", "")
+	cmd.Flags().Bool("force", false, "")
+	cmd.Flags().String("rollback", "", "")
+	cmd.Flags().Bool("resume", false, "")
+	cmd.Flags().String("password", "", "")
+	cmd.Flags().Bool("from-clipboard", false, "")
+	cmd.Flags().Bool("yes", false, "")
+	for name, value := range flags {
+		_ = cmd.Flags().Set(name, value)
+	}
+	return cmd
+}
+
+func TestExportArmor_ImportFromStdin(t *testing.T) {
+	setupAccountsTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+
+	export := newArmorExportCmd(map[string]string{"armor": "true", "password": "s3cret"})
+	var payload bytes.Buffer
+	export.SetOut(&payload)
+	export.SetErr(io.Discard)
+	require.NoError(t, runExport(export, []string{"claude/work"}))
+	assert.True(t, strings.HasPrefix(payload.String(), bundle.ArmorPrefix))
+	assert.Equal(t, 1, strings.Count(payload.String(), "\n"), "armored output is one line")
+
+	imp := newArmorImportCmd(map[string]string{"as": "claude/copy", "password": "s3cret"})
+	imp.SetIn(bytes.NewReader(payload.Bytes()))
+	require.NoError(t, runImport(imp, []string{"-"}))
+	got, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", "copy"), ".credentials.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(got), "alice@example.com")
+
+	imp = newArmorImportCmd(map[string]string{"as": "claude/other"})
+	imp.SetIn(bytes.NewReader(payload.Bytes()))
+	err = runImport(imp, []string{"-"})
+	require.Error(t, err, "no terminal to prompt on when stdin carries the payload")
+	assert.Equal(t, ExitUsage, ExitCode(err))
+
+	imp = newArmorImportCmd(map[string]string{"as": "claude/other", "password": "wrong"})
+	imp.SetIn(bytes.NewReader(payload.Bytes()))
+	assert.ErrorContains(t, runImport(imp, []string{"-"}), "wrong password")
+}
+
+func TestExportArmor_ClipboardRoundTrip(t *testing.T) {
+	setupAccountsTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "home", "bob@example.com")
+
+	var clip []byte
+	origRead, origWrite := readClipboard, writeClipboard
+	readClipboard = func() ([]byte, error) { return clip, nil }
+	writeClipboard = func(text []byte) error { clip = text; return nil }
+	t.Cleanup(func() { readClipboard, writeClipboard = origRead, origWrite })
+
+	export := newArmorExportCmd(map[string]string{"to-clipboard": "true", "password": "pw"})
+	export.SetErr(io.Discard)
+	require.NoError(t, runExport(export, []string{"claude", "work"}))
+	require.True(t, bundle.IsArmored(clip))
+
+	imp := newArmorImportCmd(map[string]string{"from-clipboard": "true", "as": "claude/pasted", "password": "pw"})
+	require.NoError(t, runImport(imp, nil))
+	assert.DirExists(t, vault.ProfilePath("claude", "pasted"))
+
+	all := newArmorExportCmd(map[string]string{"all": "true", "armor": "true", "password": "pw"})
+	err := runExport(all, []string{"claude"})
+	require.Error(t, err, "armor carries one profile")
+	assert.Equal(t, ExitUsage, ExitCode(err))
+
+	clip = []byte("just some text")
+	imp = newArmorImportCmd(map[string]string{"from-clipboard": "true"})
+	assert.ErrorContains(t, runImport(imp, nil), "doesn't hold an armored profile")
+}
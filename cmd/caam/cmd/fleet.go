@@ -0,0 +1,457 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/coordinator"
+	syncstate "github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+)
+
+var fleetCmd = &cobra.Command{
+	Use:   "fleet",
+	Short: "Supervise accounts across machines and coordinators",
+}
+
+var fleetStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show every account's use across the fleet",
+	Long: `Asks this machine, the machines in fleet.members (or, without any, every
+machine in the sync pool) and the auth-coordinators listed there for their
+status, and answers per account: where it is saved, who is using it (active
+profiles and coordinator panes), the estimated quota headroom, cooldowns,
+health, and conflicts:
+
+  - the same account in use on more than one machine at once, which shares
+    one quota and, for rotating refresh tokens, logs the others out
+  - an account in use on one machine while another has it in cooldown
+
+Machines are read with 'caam robot status' over SSH; coordinators with
+GET /status. Members that can't be reached are listed with the error and
+left out of the answer. Configure members in config.yaml:
+
+  fleet:
+    members:
+      - name: build-box
+        machine: build-box          # a 'caam sync' pool machine
+      - name: agents
+        coordinator: http://100.64.0.7:7890
+        token: caam_...             # read-scope token ('caam api-token')
+
+Examples:
+  caam fleet status
+  caam fleet status --json`,
+	Args: cobra.NoArgs,
+	RunE: runFleetStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(fleetCmd)
+	fleetCmd.AddCommand(fleetStatusCmd)
+	fleetStatusCmd.Flags().Bool("json", false, "output as JSON")
+	fleetStatusCmd.Flags().Duration("timeout", 10*time.Second, "how long to wait for each coordinator")
+}
+
+// fleetRemoteStatusCommand prints a machine's robot status. Non-interactive
+// SSH sessions often miss ~/.local/bin from PATH, so common install locations
+// are tried too.
+const fleetRemoteStatusCommand = "caam robot status 2>/dev/null || ~/.local/bin/caam robot status 2>/dev/null || /usr/local/bin/caam robot status 2>/dev/null"
+
+// Fleet member kinds.
+const (
+	fleetLocal       = "local"
+	fleetMachine     = "machine"
+	fleetCoordinator = "coordinator"
+)
+
+type fleetMemberJSON struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Profiles  int    `json:"profiles,omitempty"`
+	Panes     int    `json:"panes,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+}
+
+// fleetUseJSON is a place an account is saved or used: a profile on a
+// machine, or a pane of a coordinator.
+type fleetUseJSON struct {
+	Member  string `json:"member"`
+	Profile string `json:"profile,omitempty"`
+	Pane    *int   `json:"pane,omitempty"`
+	State   string `json:"state,omitempty"`
+}
+
+type fleetAccountJSON struct {
+	Provider string `json:"provider"`
+	// Account is the account's email, or the profile name when no
+	// identity is known.
+	Account  string         `json:"account"`
+	Profiles []fleetUseJSON `json:"profiles"`
+	InUse    []fleetUseJSON `json:"in_use"`
+	Health   string         `json:"health"`
+	// Headroom is the lowest quota estimate any machine reports (0 to 1).
+	Headroom      *float64  `json:"headroom,omitempty"`
+	CooldownUntil string    `json:"cooldown_until,omitempty"` // latest, UTC
+	CooldownOn    []string  `json:"cooldown_on,omitempty"`
+	Conflicts     []string  `json:"conflicts,omitempty"`
+	cooldownAt    time.Time // parsed CooldownUntil
+}
+
+type fleetStatusOutput struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Members     []fleetMemberJSON  `json:"members"`
+	Accounts    []fleetAccountJSON `json:"accounts"`
+	Conflicts   int                `json:"conflicts"`
+}
+
+// fleetSnapshot is what one member reported: a machine's robot status or a
+// coordinator's status.
+type fleetSnapshot struct {
+	member      fleetMemberJSON
+	status      *RobotStatusData
+	coordinator *coordinator.StatusResponse
+}
+
+func runFleetStatus(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	members := spmCfg.Fleet.Members
+	if len(members) == 0 {
+		if state, err := loadSyncState(); err == nil {
+			for _, m := range state.Pool.ListMachines() {
+				members = append(members, config.FleetMember{Name: m.Name, Machine: m.Name})
+			}
+		}
+	}
+
+	snaps := collectFleet(members, timeout)
+	out := aggregateFleet(snaps)
+	out.GeneratedAt = time.Now().UTC()
+
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	printFleetStatus(cmd.OutOrStdout(), out)
+	return nil
+}
+
+// collectFleet asks this machine and each member for its status,
+// concurrently.
+func collectFleet(members []config.FleetMember, timeout time.Duration) []fleetSnapshot {
+	snaps := make([]fleetSnapshot, len(members)+1)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		snaps[0] = fleetLocalSnapshot()
+	}()
+
+	var state *syncstate.SyncState
+	var pool *syncstate.ConnectionPool
+	for _, m := range members {
+		if m.Machine != "" {
+			state, _ = loadSyncState()
+			pool = syncstate.NewConnectionPool(syncstate.DefaultConnectOptions())
+			defer pool.CloseAll()
+			break
+		}
+	}
+	client := &http.Client{Timeout: timeout}
+	for i, m := range members {
+		wg.Add(1)
+		go func(i int, m config.FleetMember) {
+			defer wg.Done()
+			if m.Coordinator != "" {
+				snaps[i+1] = fleetCoordinatorSnapshot(client, m)
+			} else {
+				snaps[i+1] = fleetMachineSnapshot(state, pool, m)
+			}
+		}(i, m)
+	}
+	wg.Wait()
+	return snaps
+}
+
+func fleetLocalSnapshot() fleetSnapshot {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		name = "local"
+	}
+	data, _ := collectRobotStatus(knownTools(), false)
+	return fleetSnapshot{
+		member: fleetMemberJSON{Name: name, Kind: fleetLocal, OK: true, Version: data.Version, Profiles: data.Summary.TotalProfiles},
+		status: &data,
+	}
+}
+
+func fleetMachineSnapshot(state *syncstate.SyncState, pool *syncstate.ConnectionPool, m config.FleetMember) fleetSnapshot {
+	snap := fleetSnapshot{member: fleetMemberJSON{Name: m.Name, Kind: fleetMachine}}
+	if state == nil {
+		snap.member.Error = "sync state unavailable"
+		return snap
+	}
+	machine := state.Pool.GetMachineByName(m.Machine)
+	if machine == nil {
+		snap.member.Error = fmt.Sprintf("machine %q not found in the sync pool", m.Machine)
+		return snap
+	}
+	start := time.Now()
+	client, err := pool.Get(machine)
+	if err != nil {
+		snap.member.Error = err.Error()
+		return snap
+	}
+	raw, err := client.Run(fleetRemoteStatusCommand)
+	snap.member.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		snap.member.Error = fmt.Sprintf("caam robot status: %v (is caam installed there?)", err)
+		return snap
+	}
+	data, err := parseRobotStatus(raw)
+	if err != nil {
+		snap.member.Error = err.Error()
+		return snap
+	}
+	snap.member.OK = true
+	snap.member.Version = data.Version
+	snap.member.Profiles = data.Summary.TotalProfiles
+	snap.status = data
+	return snap
+}
+
+// parseRobotStatus decodes 'caam robot status' output.
+func parseRobotStatus(raw string) (*RobotStatusData, error) {
+	var env struct {
+		Success bool            `json:"success"`
+		Data    RobotStatusData `json:"data"`
+		Error   *RobotError     `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(raw), &env); err != nil {
+		return nil, fmt.Errorf("parse robot status: %w", err)
+	}
+	if !env.Success {
+		if env.Error != nil {
+			return nil, fmt.Errorf("robot status: %s", env.Error.Message)
+		}
+		return nil, fmt.Errorf("robot status failed")
+	}
+	return &env.Data, nil
+}
+
+func fleetCoordinatorSnapshot(client *http.Client, m config.FleetMember) fleetSnapshot {
+	snap := fleetSnapshot{member: fleetMemberJSON{Name: m.Name, Kind: fleetCoordinator}}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(m.Coordinator, "/")+"/status", nil)
+	if err != nil {
+		snap.member.Error = err.Error()
+		return snap
+	}
+	apitoken.SetAuthorization(req, m.Token)
+	start := time.Now()
+	resp, err := client.Do(req)
+	snap.member.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		snap.member.Error = err.Error()
+		return snap
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		snap.member.Error = fmt.Sprintf("GET /status: %s", resp.Status)
+		if resp.StatusCode == http.StatusUnauthorized {
+			snap.member.Error += " (set a read-scope token for this member)"
+		}
+		return snap
+	}
+	var status coordinator.StatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		snap.member.Error = fmt.Sprintf("parse status: %v", err)
+		return snap
+	}
+	snap.member.OK = true
+	snap.member.Panes = status.PaneCount
+	snap.coordinator = &status
+	return snap
+}
+
+// healthRank orders health statuses from best to worst.
+var healthRank = map[string]int{"healthy": 0, "unknown": 1, "warning": 2, "critical": 3}
+
+// aggregateFleet groups what the members reported by account and finds
+// conflicts. Accounts are identified by provider and email; a profile with
+// no known email stands for its own account under its name.
+func aggregateFleet(snaps []fleetSnapshot) *fleetStatusOutput {
+	out := &fleetStatusOutput{Members: []fleetMemberJSON{}, Accounts: []fleetAccountJSON{}}
+	accounts := make(map[string]*fleetAccountJSON)
+	get := func(provider, account string) *fleetAccountJSON {
+		key := provider + "\x00" + strings.ToLower(account)
+		a, ok := accounts[key]
+		if !ok {
+			a = &fleetAccountJSON{Provider: provider, Account: account, Profiles: []fleetUseJSON{}, InUse: []fleetUseJSON{}, Health: "unknown"}
+			accounts[key] = a
+		}
+		return a
+	}
+
+	for _, snap := range snaps {
+		out.Members = append(out.Members, snap.member)
+		name := snap.member.Name
+		if snap.status != nil {
+			for _, prov := range snap.status.Providers {
+				for _, p := range prov.Profiles {
+					if p.System {
+						continue
+					}
+					account := p.Email
+					if account == "" {
+						account = p.Name
+					}
+					a := get(prov.ID, account)
+					use := fleetUseJSON{Member: name, Profile: p.Name}
+					if len(a.Profiles) == 0 || healthRank[p.Health.Status] > healthRank[a.Health] {
+						a.Health = p.Health.Status
+					}
+					a.Profiles = append(a.Profiles, use)
+					if p.Active {
+						a.InUse = append(a.InUse, use)
+					}
+					if p.Headroom != nil && (a.Headroom == nil || *p.Headroom < *a.Headroom) {
+						h := *p.Headroom
+						a.Headroom = &h
+					}
+					if p.Cooldown != nil && p.Cooldown.Active {
+						a.CooldownOn = append(a.CooldownOn, name)
+						if until, err := time.Parse(time.RFC3339, p.Cooldown.UntilUTC); err == nil && until.After(a.cooldownAt) {
+							a.cooldownAt = until
+							a.CooldownUntil = until.UTC().Format(time.RFC3339)
+						}
+					}
+				}
+			}
+		}
+		if snap.coordinator != nil {
+			// Coordinators drive Claude Code panes; Account is the email
+			// each pane logged in with.
+			for _, pane := range snap.coordinator.Panes {
+				if pane.Account == "" {
+					continue
+				}
+				id := pane.PaneID
+				a := get("claude", pane.Account)
+				a.InUse = append(a.InUse, fleetUseJSON{Member: name, Pane: &id, State: pane.State})
+			}
+		}
+	}
+
+	for _, a := range accounts {
+		a.Conflicts = fleetConflicts(a)
+		if len(a.Conflicts) > 0 {
+			out.Conflicts++
+		}
+		out.Accounts = append(out.Accounts, *a)
+	}
+	sort.Slice(out.Accounts, func(i, j int) bool {
+		ai, aj := out.Accounts[i], out.Accounts[j]
+		if ai.Provider != aj.Provider {
+			return ai.Provider < aj.Provider
+		}
+		return strings.ToLower(ai.Account) < strings.ToLower(aj.Account)
+	})
+	return out
+}
+
+// fleetConflicts describes what is wrong with how an account is being used.
+func fleetConflicts(a *fleetAccountJSON) []string {
+	var conflicts []string
+	var users []string
+	seen := make(map[string]bool)
+	for _, use := range a.InUse {
+		if !seen[use.Member] {
+			seen[use.Member] = true
+			users = append(users, use.Member)
+		}
+	}
+	if len(users) > 1 {
+		conflicts = append(conflicts, fmt.Sprintf("in use on %d members at once (%s)", len(users), strings.Join(users, ", ")))
+	}
+	if len(a.CooldownOn) > 0 && len(users) > 0 {
+		conflicts = append(conflicts, fmt.Sprintf("in cooldown on %s but in use on %s", strings.Join(a.CooldownOn, ", "), strings.Join(users, ", ")))
+	}
+	return conflicts
+}
+
+func printFleetStatus(w io.Writer, out *fleetStatusOutput) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "MEMBER\tKIND\tSTATUS")
+	for _, m := range out.Members {
+		status := "ok"
+		switch {
+		case !m.OK:
+			status = "unreachable: " + m.Error
+		case m.Kind == fleetCoordinator:
+			status = fmt.Sprintf("ok (%d panes)", m.Panes)
+		default:
+			status = fmt.Sprintf("ok (caam %s, %d profiles)", m.Version, m.Profiles)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", m.Name, m.Kind, status)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	if len(out.Accounts) == 0 {
+		fmt.Fprintln(w, "No accounts found.")
+		return
+	}
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACCOUNT\tPROVIDER\tIN USE BY\tHEADROOM\tCOOLDOWN\tHEALTH")
+	for _, a := range out.Accounts {
+		var users []string
+		for _, use := range a.InUse {
+			if use.Pane != nil {
+				users = append(users, fmt.Sprintf("%s pane %d", use.Member, *use.Pane))
+			} else {
+				users = append(users, use.Member+":"+use.Profile)
+			}
+		}
+		headroom := "-"
+		if a.Headroom != nil {
+			headroom = fmt.Sprintf("%.0f%%", *a.Headroom*100)
+		}
+		cooldown := "-"
+		if !a.cooldownAt.IsZero() {
+			cooldown = robotFormatDuration(time.Until(a.cooldownAt)) + " (" + strings.Join(a.CooldownOn, ", ") + ")"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", a.Account, a.Provider, joinOrDash(users), headroom, cooldown, a.Health)
+	}
+	tw.Flush()
+
+	if out.Conflicts == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nConflicts (%d):\n", out.Conflicts)
+	for _, a := range out.Accounts {
+		for _, c := range a.Conflicts {
+			fmt.Fprintf(w, "  %s %s: %s\n", a.Provider, a.Account, c)
+		}
+	}
+}
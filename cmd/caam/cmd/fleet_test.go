@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/coordinator"
+)
+
+func TestAggregateFleet_GroupsByAccountAndFindsConflicts(t *testing.T) {
+	until := time.Now().Add(2 * time.Hour).UTC().Format(time.RFC3339)
+	low, high := 0.2, 0.7
+	machine := func(name string, profiles ...RobotProfileInfo) fleetSnapshot {
+		return fleetSnapshot{
+			member: fleetMemberJSON{Name: name, Kind: fleetMachine, OK: true},
+			status: &RobotStatusData{Providers: []RobotProviderInfo{{ID: "claude", Profiles: profiles}}},
+		}
+	}
+	snaps := []fleetSnapshot{
+		machine("a",
+			RobotProfileInfo{Name: "work", Email: "alice@example.com", Active: true, Health: RobotHealthInfo{Status: "healthy"}, Headroom: &high},
+			RobotProfileInfo{Name: "side", Email: "bob@example.com", Health: RobotHealthInfo{Status: "healthy"}},
+			RobotProfileInfo{Name: "_original", System: true, Active: true}),
+		machine("b",
+			RobotProfileInfo{Name: "alice", Email: "Alice@Example.com", Active: true, Health: RobotHealthInfo{Status: "warning"}, Headroom: &low,
+				Cooldown: &RobotCooldown{Active: true, UntilUTC: until}}),
+		{
+			member:      fleetMemberJSON{Name: "coord", Kind: fleetCoordinator, OK: true},
+			coordinator: &coordinator.StatusResponse{Panes: []coordinator.PaneStatusResponse{{PaneID: 3, State: "idle", Account: "bob@example.com"}, {PaneID: 4}}},
+		},
+		{member: fleetMemberJSON{Name: "down", Kind: fleetMachine, Error: "dial tcp: timeout"}},
+	}
+
+	out := aggregateFleet(snaps)
+	require.Len(t, out.Members, 4)
+	require.Len(t, out.Accounts, 2, "system profiles aren't accounts")
+
+	alice := out.Accounts[0]
+	assert.Equal(t, "alice@example.com", alice.Account)
+	assert.Len(t, alice.Profiles, 2, "emails match case-insensitively")
+	assert.Equal(t, "warning", alice.Health, "the worst health wins")
+	require.NotNil(t, alice.Headroom)
+	assert.Equal(t, low, *alice.Headroom, "the lowest headroom wins")
+	assert.Equal(t, until, alice.CooldownUntil)
+	assert.Equal(t, []string{"b"}, alice.CooldownOn)
+	require.Len(t, alice.Conflicts, 2)
+	assert.Contains(t, alice.Conflicts[0], "in use on 2 members at once (a, b)")
+	assert.Contains(t, alice.Conflicts[1], "in cooldown on b but in use on a, b")
+
+	bob := out.Accounts[1]
+	require.Len(t, bob.InUse, 1)
+	assert.Equal(t, "coord", bob.InUse[0].Member)
+	assert.Equal(t, 3, *bob.InUse[0].Pane)
+	assert.Empty(t, bob.Conflicts)
+	assert.Equal(t, 1, out.Conflicts)
+
+	var table bytes.Buffer
+	printFleetStatus(&table, out)
+	assert.Contains(t, table.String(), "unreachable: dial tcp: timeout")
+	assert.Contains(t, table.String(), "coord pane 3")
+	assert.Contains(t, table.String(), "Conflicts (1):")
+}
+
+func TestFleetStatus_AsksCoordinators(t *testing.T) {
+	setupAccountsTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(coordinator.StatusResponse{
+			Running: true, PaneCount: 1,
+			Panes: []coordinator.PaneStatusResponse{{PaneID: 1, State: "idle", Account: "alice@example.com"}},
+		})
+	}))
+	defer srv.Close()
+
+	spmCfg := config.DefaultSPMConfig()
+	spmCfg.Fleet.Members = []config.FleetMember{
+		{Name: "agents", Coordinator: srv.URL, Token: "secret"},
+		{Name: "no-token", Coordinator: srv.URL},
+	}
+	require.NoError(t, spmCfg.Save())
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", true, "")
+	cmd.Flags().Duration("timeout", 5*time.Second, "")
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	require.NoError(t, runFleetStatus(cmd, nil))
+
+	var out fleetStatusOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out), stdout.String())
+	require.Len(t, out.Members, 3)
+	assert.Equal(t, fleetLocal, out.Members[0].Kind)
+	assert.True(t, out.Members[1].OK)
+	assert.False(t, out.Members[2].OK)
+	assert.Contains(t, out.Members[2].Error, "read-scope token")
+
+	require.Len(t, out.Accounts, 1)
+	alice := out.Accounts[0]
+	assert.Equal(t, "alice@example.com", alice.Account)
+	require.Len(t, alice.Profiles, 1)
+	assert.Equal(t, "work", alice.Profiles[0].Profile)
+	require.Len(t, alice.InUse, 1)
+	assert.Equal(t, "agents", alice.InUse[0].Member)
+}
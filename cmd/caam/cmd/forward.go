@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authforward"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/exec"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+)
+
+var forwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "Lend vault logins to caam on a remote machine over SSH",
+	Long: `Lets caam on a remote machine borrow logins from your local vault over an
+SSH-forwarded Unix socket, the way ssh-agent lends keys. The remote side
+fetches a profile's auth files for one 'caam exec --forwarded' run, keeps them
+in a tmpfs directory and wipes them when the tool exits, so the tokens never
+persist on the remote disk.
+
+On your machine:
+  caam forward serve --allow 'claude/*'
+
+Then connect with the socket forwarded:
+  ssh -o StreamLocalBindUnlink=yes -R /tmp/caam-$USER.sock:<socket shown by serve> remote
+
+On the remote:
+  export CAAM_AUTH_SOCK=/tmp/caam-$USER.sock
+  caam forward status
+  caam exec --forwarded claude work -- -p "fix the build"
+
+Anyone who can use the forwarded socket on the remote can borrow the lent
+logins, as with ssh-agent forwarding, so only forward to machines you trust
+and narrow what is lent with --allow. A tool that refreshes its token during
+the run writes the new token to the scratch copy, which is wiped; the vault
+keeps the old one.`,
+}
+
+var forwardServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve vault logins on a local socket for forwarding",
+	Args:  cobra.NoArgs,
+	RunE:  runForwardServe,
+}
+
+var forwardStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check the forwarded agent and list the logins it lends",
+	Args:  cobra.NoArgs,
+	RunE:  runForwardStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(forwardCmd)
+	forwardCmd.AddCommand(forwardServeCmd)
+	forwardCmd.AddCommand(forwardStatusCmd)
+
+	forwardServeCmd.Flags().String("socket", "", "socket path to listen on (default $XDG_RUNTIME_DIR/caam-forward.sock)")
+	forwardServeCmd.Flags().StringSlice("allow", nil, "only lend these tool/profile patterns (e.g. claude/*,codex/work)")
+
+	forwardStatusCmd.Flags().String("socket", "", "agent socket (default $"+authforward.SocketEnv+")")
+	forwardStatusCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// forwardFileSet resolves a tool's live auth file set for the agent.
+func forwardFileSet(tool string) (authfile.AuthFileSet, bool) {
+	fileSet, ok := tools[tool]
+	if !ok {
+		return authfile.AuthFileSet{}, false
+	}
+	return fileSet(), true
+}
+
+func runForwardServe(cmd *cobra.Command, args []string) error {
+	socket, _ := cmd.Flags().GetString("socket")
+	allow, _ := cmd.Flags().GetStringSlice("allow")
+	if socket == "" {
+		socket = authforward.DefaultSocketPath()
+	}
+	for _, pattern := range allow {
+		if !strings.Contains(pattern, "/") {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid --allow %q: want tool/profile (e.g. claude/*)", pattern))
+		}
+	}
+
+	var toolNames []string
+	for name := range tools {
+		toolNames = append(toolNames, name)
+	}
+	sort.Strings(toolNames)
+
+	out, errOut := cmd.OutOrStdout(), cmd.ErrOrStderr()
+	server := &authforward.Server{
+		Vault:   vault,
+		FileSet: forwardFileSet,
+		Tools:   toolNames,
+		Allow:   allow,
+		Logf: func(format string, a ...any) {
+			fmt.Fprintf(errOut, "%s %s\n", time.Now().Format("15:04:05"), fmt.Sprintf(format, a...))
+		},
+	}
+
+	listener, err := authforward.Listen(socket)
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+
+	lent := "every vault profile"
+	if len(allow) > 0 {
+		lent = strings.Join(allow, ", ")
+	}
+	fmt.Fprintf(out, "Lending %s on %s (Ctrl-C to stop)\n\n", lent, socket)
+	fmt.Fprintf(out, "Connect with:\n  ssh -o StreamLocalBindUnlink=yes -R /tmp/caam-$USER.sock:%s <host>\n", socket)
+	fmt.Fprintf(out, "and on the remote:\n  export %s=/tmp/caam-$USER.sock\n  caam exec --forwarded <tool> <profile>\n\n", authforward.SocketEnv)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return server.Serve(ctx, listener)
+}
+
+type forwardStatusOutput struct {
+	jsonStatus
+	Socket   string              `json:"socket"`
+	Profiles map[string][]string `json:"profiles"`
+}
+
+func runForwardStatus(cmd *cobra.Command, args []string) error {
+	socket, _ := cmd.Flags().GetString("socket")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	if socket == "" {
+		socket = os.Getenv(authforward.SocketEnv)
+	}
+
+	output := forwardStatusOutput{Socket: socket, Profiles: map[string][]string{}}
+	profiles, err := authforward.List(socket)
+	if err == nil && profiles != nil {
+		output.Profiles = profiles
+	}
+	if jsonOutput {
+		return writeJSONResult(cmd, &output, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Forwarding agent on %s is reachable.\n", socket)
+	if len(output.Profiles) == 0 {
+		fmt.Fprintln(out, "It lends no profiles.")
+		return nil
+	}
+	var toolNames []string
+	for tool := range output.Profiles {
+		toolNames = append(toolNames, tool)
+	}
+	sort.Strings(toolNames)
+	for _, tool := range toolNames {
+		fmt.Fprintf(out, "  %-8s %s\n", tool, strings.Join(output.Profiles[tool], ", "))
+	}
+	return nil
+}
+
+// runForwardedExec runs tool with a profile borrowed from the forwarding
+// agent at $CAAM_AUTH_SOCK. The borrowed files live in a tmpfs scratch
+// profile that is wiped when the tool exits.
+func runForwardedExec(prov provider.Provider, tool, name string, toolArgs []string) error {
+	scratch, err := authforward.NewScratchDir()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := authforward.Wipe(scratch); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to wipe borrowed auth in %s: %v\n", scratch, err)
+		}
+	}()
+
+	prof := &profile.Profile{Name: name, Provider: tool, AuthMode: "oauth", BasePath: scratch}
+	fileSet, ok := authfile.AuthFileSetAt(tool, authfile.AuthDirs{
+		Home:      prof.HomePath(),
+		XDGConfig: prof.XDGConfigPath(),
+		CodexHome: prof.CodexHomePath(),
+	})
+	if !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("--forwarded does not support %s (supported: claude, codex, gemini)", tool))
+	}
+
+	files, err := authforward.Fetch(os.Getenv(authforward.SocketEnv), tool, name)
+	if err != nil {
+		return err
+	}
+	if _, err := authforward.Materialize(files, fileSet); err != nil {
+		return fmt.Errorf("write borrowed auth: %w", err)
+	}
+
+	// A dropped SSH connection hangs up the tool too; caam has to outlive
+	// it to wipe the scratch profile.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	started := time.Now()
+	err = runner.Run(context.Background(), exec.RunOptions{
+		Profile:  prof,
+		Provider: prov,
+		Args:     toolArgs,
+		NoLock:   true,
+	})
+	recordExecRun(tool, name, toolArgs, started, err)
+	return err
+}
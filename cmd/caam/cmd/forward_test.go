@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authforward"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/claude"
+)
+
+// serveTestAgent lends the test vault on a temp socket the way
+// 'caam forward serve' does.
+func serveTestAgent(t *testing.T, allow ...string) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := authforward.Listen(socket)
+	require.NoError(t, err)
+	server := &authforward.Server{Vault: vault, FileSet: forwardFileSet, Tools: []string{"claude", "codex"}, Allow: allow}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = server.Serve(ctx, l)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return socket
+}
+
+func TestForwardStatus_ListsLentProfiles(t *testing.T) {
+	setupAccountsTest(t)
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "home", "bob@example.com")
+	t.Setenv(authforward.SocketEnv, serveTestAgent(t, "claude/work"))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("socket", "", "")
+	cmd.Flags().Bool("json", true, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, runForwardStatus(cmd, nil))
+
+	var output forwardStatusOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &output))
+	assert.True(t, output.Success, output.Error)
+	assert.Equal(t, map[string][]string{"claude": {"work"}}, output.Profiles)
+}
+
+func TestForwardStatus_NoAgent(t *testing.T) {
+	t.Setenv(authforward.SocketEnv, "")
+	cmd := &cobra.Command{}
+	cmd.Flags().String("socket", "", "")
+	cmd.Flags().Bool("json", false, "")
+	err := runForwardStatus(cmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), authforward.SocketEnv)
+}
+
+func TestForwardServe_RejectsBadAllow(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("socket", filepath.Join(t.TempDir(), "s.sock"), "")
+	cmd.Flags().StringSlice("allow", []string{"claude"}, "")
+	err := runForwardServe(cmd, nil)
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
+
+func TestForwardedExec_RefusesUnknownProfile(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv(authforward.DirEnv, t.TempDir())
+	t.Setenv(authforward.SocketEnv, serveTestAgent(t))
+
+	err := runForwardedExec(claude.New(), "claude", "nobody", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+
+	// The scratch profile is wiped even when nothing ran.
+	entries, _ := filepath.Glob(filepath.Join(os.Getenv(authforward.DirEnv), "caam-forward-*"))
+	assert.Empty(t, entries)
+}
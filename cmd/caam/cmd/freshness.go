@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+// stampLoginVerified records that the provider just accepted tool/profile's
+// login, for the stale-login warnings in ls and status. Failures are silent.
+func stampLoginVerified(tool, profile string) {
+	db, err := getDB()
+	if err != nil {
+		return
+	}
+	_ = db.LogEvent(caamdb.Event{
+		Type:        caamdb.EventVerified,
+		Provider:    tool,
+		ProfileName: profile,
+	})
+}
+
+// loginFreshness is when a vault profile's login was last known to work.
+type loginFreshness struct {
+	// CheckedAt is the later of the last verification stamp and the last
+	// backup into the vault; zero when neither is known.
+	CheckedAt time.Time
+	// Stale is set when CheckedAt is older than health.stale_login_days.
+	Stale bool
+}
+
+// checkLoginFreshness looks up tool/profile's freshness. db may be nil, in
+// which case only the backup time counts. System profiles are never stale:
+// auto-backups are old by design.
+func checkLoginFreshness(db *caamdb.DB, staleDays int, tool, profile string, now time.Time) loginFreshness {
+	var f loginFreshness
+	if db != nil {
+		f.CheckedAt, _ = db.LastVerified(tool, profile)
+	}
+	if vault != nil {
+		if backedUp, err := vault.BackedUpAt(tool, profile); err == nil && backedUp.After(f.CheckedAt) {
+			f.CheckedAt = backedUp
+		}
+	}
+	if staleDays > 0 && !f.CheckedAt.IsZero() && !authfile.IsSystemProfile(profile) {
+		f.Stale = now.Sub(f.CheckedAt) > time.Duration(staleDays)*24*time.Hour
+	}
+	return f
+}
+
+// staleLoginDays returns health.stale_login_days from the config, or the
+// default when the config can't be read.
+func staleLoginDays() int {
+	if spmCfg, err := config.LoadSPMConfig(); err == nil {
+		return spmCfg.Health.StaleLoginDays
+	}
+	return config.DefaultSPMConfig().Health.StaleLoginDays
+}
+
+// lsStaleNote returns the note caam ls appends to a profile whose login
+// hasn't been verified recently, or "".
+func lsStaleNote(f loginFreshness, now time.Time) string {
+	if !f.Stale {
+		return ""
+	}
+	return fmt.Sprintf("  [unverified %dd]", int(now.Sub(f.CheckedAt).Hours()/24))
+}
+
+// staleLoginWarning is the caam status warning for a stale login.
+func staleLoginWarning(tool, profile string, f loginFreshness) string {
+	return fmt.Sprintf("%s/%s: login last verified %s; the provider may have revoked it (check with 'caam refresh %s %s --force')",
+		tool, profile, formatTimeAgo(f.CheckedAt), tool, profile)
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+// writeBackedUpAt stamps a vault profile's meta.json with a backup time.
+func writeBackedUpAt(t *testing.T, tool, name string, at time.Time) {
+	t.Helper()
+	meta := `{"tool": "` + tool + `", "profile": "` + name + `", "backed_up_at": "` + at.Format(time.RFC3339) + `"}`
+	require.NoError(t, os.WriteFile(filepath.Join(vault.ProfilePath(tool, name), "meta.json"), []byte(meta), 0600))
+}
+
+func TestCheckLoginFreshness(t *testing.T) {
+	setupAccountsTest(t)
+	now := time.Now()
+	writeClaudeVaultProfile(t, "old", "a@example.com")
+	writeBackedUpAt(t, "claude", "old", now.Add(-30*24*time.Hour))
+	writeClaudeVaultProfile(t, "used", "b@example.com")
+	writeBackedUpAt(t, "claude", "used", now.Add(-30*24*time.Hour))
+	writeClaudeVaultProfile(t, "_backup_20250101_000000", "a@example.com")
+	writeBackedUpAt(t, "claude", "_backup_20250101_000000", now.Add(-30*24*time.Hour))
+	writeClaudeVaultProfile(t, "unknown", "c@example.com")
+
+	db, err := getDB()
+	require.NoError(t, err)
+	verified := now.Add(-2 * 24 * time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, db.LogEvent(caamdb.Event{Type: caamdb.EventVerified, Provider: "claude", ProfileName: "used", Timestamp: verified}))
+
+	old := checkLoginFreshness(db, 14, "claude", "old", now)
+	assert.True(t, old.Stale)
+	assert.Equal(t, "  [unverified 30d]", lsStaleNote(old, now))
+	assert.Contains(t, staleLoginWarning("claude", "old", old), "caam refresh claude old --force")
+
+	used := checkLoginFreshness(db, 14, "claude", "used", now)
+	assert.False(t, used.Stale)
+	assert.True(t, used.CheckedAt.Equal(verified), "the verification stamp beats the older backup")
+	assert.Empty(t, lsStaleNote(used, now))
+
+	assert.False(t, checkLoginFreshness(db, 14, "claude", "_backup_20250101_000000", now).Stale, "system profiles are never stale")
+	assert.False(t, checkLoginFreshness(db, 0, "claude", "old", now).Stale, "0 days turns the warning off")
+
+	unknown := checkLoginFreshness(nil, 14, "claude", "unknown", now)
+	assert.True(t, unknown.CheckedAt.IsZero())
+	assert.False(t, unknown.Stale)
+}
+
+func TestStampLoginVerified(t *testing.T) {
+	setupAccountsTest(t)
+
+	stampLoginVerified("codex", "main")
+	db, err := getDB()
+	require.NoError(t, err)
+	at, err := db.LastVerified("codex", "main")
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), at, time.Minute)
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Reclaim space left behind by interrupted or old operations",
+	Long: `Removes what caam leaves behind over time:
+
+  temp     import/export staging dirs older than a day, in the system temp
+           dir and inside the vault
+  lock     profile lock files whose owning process is gone
+  backup   _backup_* auto-backups older than --backup-days (the newest one
+           per tool and any active one are kept)
+  records  activity_log entries and expired cooldowns older than
+           retention_days (see 'caam cleanup')
+  empty    provider directories with no profiles left
+
+Examples:
+  caam gc --dry-run          # Show what would be removed
+  caam gc                    # Clean up and report the space reclaimed
+  caam gc --backup-days 7    # Keep only a week of auto-backups
+  caam gc --json`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().Bool("dry-run", false, "show what would be removed without removing it")
+	gcCmd.Flags().Int("days", 0, "override retention_days from config for activity logs and cooldowns")
+	gcCmd.Flags().Int("backup-days", 30, "remove auto-backups older than this many days")
+	gcCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// gcTempMaxAge is how old a staging dir must be before gc treats it as
+// abandoned rather than belonging to a running import.
+const gcTempMaxAge = 24 * time.Hour
+
+// gcTempPatterns match the staging dirs caam creates in the system temp dir.
+var gcTempPatterns = []string{"caam-import-*", "caam-export-*", "caam-conflict-*", "caam-verify-restore-*", "caam-add-token-*"}
+
+// gcVaultTempPatterns match the staging dirs imports create inside a vault
+// tool directory.
+var gcVaultTempPatterns = []string{".caam_import_*", ".import_tmp_*"}
+
+// gcItem is one thing gc removed or would remove.
+type gcItem struct {
+	Kind  string `json:"kind"`
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+	Error string `json:"error,omitempty"`
+}
+
+type gcOutput struct {
+	jsonStatus
+	DryRun              bool     `json:"dry_run"`
+	Items               []gcItem `json:"items"`
+	ActivityLogsDeleted int      `json:"activity_logs_deleted"`
+	CooldownsDeleted    int      `json:"cooldowns_deleted"`
+	RetentionDays       int      `json:"retention_days"`
+	BytesReclaimed      int64    `json:"bytes_reclaimed"`
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	overrideDays, _ := cmd.Flags().GetInt("days")
+	backupDays, _ := cmd.Flags().GetInt("backup-days")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	output := gcOutput{DryRun: dryRun, Items: []gcItem{}}
+	err := collectGarbage(&output, overrideDays, backupDays)
+	if jsonOutput {
+		return writeJSONResult(cmd, &output, err)
+	}
+
+	out := cmd.OutOrStdout()
+	if len(output.Items) == 0 && output.ActivityLogsDeleted == 0 && output.CooldownsDeleted == 0 {
+		fmt.Fprintln(out, "Nothing to clean up.")
+		return err
+	}
+
+	if dryRun {
+		fmt.Fprintln(out, "Would remove:")
+	} else {
+		fmt.Fprintln(out, "Removed:")
+	}
+	for _, item := range output.Items {
+		line := fmt.Sprintf("  %-7s %s (%s)", item.Kind, item.Path, formatBytes(item.Bytes))
+		if item.Error != "" {
+			line += " - failed: " + item.Error
+		}
+		fmt.Fprintln(out, line)
+	}
+	if output.ActivityLogsDeleted > 0 || output.CooldownsDeleted > 0 {
+		fmt.Fprintf(out, "  records %d activity log entries and %d expired cooldowns older than %d days\n",
+			output.ActivityLogsDeleted, output.CooldownsDeleted, output.RetentionDays)
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "\nWould reclaim %s. Run without --dry-run to clean up.\n", formatBytes(output.BytesReclaimed))
+	} else {
+		fmt.Fprintf(out, "\nReclaimed %s.\n", formatBytes(output.BytesReclaimed))
+	}
+	return err
+}
+
+// collectGarbage finds (and unless output.DryRun, removes) everything gc
+// cleans, recording each item in output. Items that can't be removed are
+// reported and the rest carry on; the returned error summarizes them.
+func collectGarbage(output *gcOutput, overrideDays, backupDays int) error {
+	now := time.Now()
+
+	remove := func(kind, path string, del func() error) {
+		item := gcItem{Kind: kind, Path: path, Bytes: gcPathSize(path)}
+		if !output.DryRun {
+			if err := del(); err != nil {
+				item.Error = err.Error()
+			}
+		}
+		if item.Error == "" {
+			output.BytesReclaimed += item.Bytes
+		}
+		output.Items = append(output.Items, item)
+	}
+
+	// Abandoned staging dirs.
+	var temps []string
+	for _, pattern := range gcTempPatterns {
+		matches, _ := filepath.Glob(filepath.Join(os.TempDir(), pattern))
+		temps = append(temps, matches...)
+	}
+	for _, tool := range gcSubdirs(vault.BasePath()) {
+		for _, pattern := range gcVaultTempPatterns {
+			matches, _ := filepath.Glob(filepath.Join(vault.BasePath(), tool, pattern))
+			temps = append(temps, matches...)
+		}
+	}
+	sort.Strings(temps)
+	for _, path := range temps {
+		info, err := os.Lstat(path)
+		if err != nil || !info.IsDir() || now.Sub(info.ModTime()) < gcTempMaxAge {
+			continue
+		}
+		remove("temp", path, func() error { return os.RemoveAll(path) })
+	}
+
+	// Lock files left by processes that died without unlocking.
+	storePath := profileStore.BasePath()
+	for _, provider := range gcSubdirs(storePath) {
+		for _, name := range gcSubdirs(filepath.Join(storePath, provider)) {
+			prof := &profile.Profile{Name: name, Provider: provider, BasePath: filepath.Join(storePath, provider, name)}
+			if stale, err := prof.IsLockStale(); err != nil || !stale {
+				continue
+			}
+			remove("lock", prof.LockPath(), func() error {
+				_, err := prof.CleanStaleLock()
+				return err
+			})
+		}
+	}
+
+	// Auto-backups past retention.
+	if backupDays > 0 {
+		cutoff := now.AddDate(0, 0, -backupDays)
+		for _, tool := range gcSubdirs(vault.BasePath()) {
+			stale, err := vault.StaleAutoBackups(tool, cutoff)
+			if err != nil {
+				continue
+			}
+			active := ""
+			if getFileSet, ok := tools[tool]; ok {
+				active, _ = vault.ActiveProfile(getFileSet())
+			}
+			for _, name := range stale {
+				if name == active {
+					continue
+				}
+				remove("backup", vault.ProfilePath(tool, name), func() error { return vault.DeleteForce(tool, name) })
+			}
+		}
+	}
+
+	// Old database records.
+	if err := gcDatabase(output, overrideDays); err != nil {
+		return err
+	}
+
+	// Provider directories with nothing left in them. os.Remove refuses a
+	// non-empty dir, so a profile appearing meanwhile is safe.
+	for _, base := range []string{vault.BasePath(), storePath} {
+		for _, dir := range gcSubdirs(base) {
+			path := filepath.Join(base, dir)
+			entries, err := os.ReadDir(path)
+			if err != nil || len(entries) > 0 {
+				continue
+			}
+			remove("empty", path, func() error { return os.Remove(path) })
+		}
+	}
+
+	failed := 0
+	for _, item := range output.Items {
+		if item.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d item(s) could not be removed", failed)
+	}
+	return nil
+}
+
+// gcDatabase prunes activity logs and expired cooldowns past retention,
+// counting any space VACUUM gives back.
+func gcDatabase(output *gcOutput, overrideDays int) error {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	cfg := caamdb.CleanupConfig{
+		RetentionDays:          spmCfg.Analytics.RetentionDays,
+		AggregateRetentionDays: spmCfg.Analytics.AggregateRetentionDays,
+	}
+	if overrideDays > 0 {
+		cfg.RetentionDays = overrideDays
+	}
+	output.RetentionDays = cfg.RetentionDays
+
+	db, err := caamdb.Open()
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	if output.DryRun {
+		result, err := db.CleanupDryRun(cfg)
+		if err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		output.ActivityLogsDeleted = result.ActivityLogsDeleted
+		output.CooldownsDeleted = result.CooldownsDeleted
+		return nil
+	}
+
+	before := gcPathSize(db.Path())
+	result, err := db.Cleanup(cfg)
+	if err != nil {
+		return fmt.Errorf("cleanup: %w", err)
+	}
+	output.ActivityLogsDeleted = result.ActivityLogsDeleted
+	output.CooldownsDeleted = result.CooldownsDeleted
+	if freed := before - gcPathSize(db.Path()); freed > 0 {
+		output.BytesReclaimed += freed
+	}
+	return nil
+}
+
+// gcSubdirs returns the names of the directories directly under dir,
+// skipping staging dirs.
+func gcSubdirs(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
+
+// gcPathSize returns the total size of the files at or under path.
+func gcPathSize(path string) int64 {
+	var total int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
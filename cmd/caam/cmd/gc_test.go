@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/spf13/cobra"
+)
+
+func newGCTestCmd(dryRun bool) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("dry-run", dryRun, "")
+	cmd.Flags().Int("days", 0, "")
+	cmd.Flags().Int("backup-days", 30, "")
+	cmd.Flags().Bool("json", true, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestGC_CleansStaleState(t *testing.T) {
+	tmpDir, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	t.Setenv("TMPDIR", filepath.Join(tmpDir, "tmp"))
+	t.Setenv("HOME", t.TempDir())
+
+	oldStore := profileStore
+	profileStore = profile.NewStore(filepath.Join(tmpDir, "profiles"))
+	defer func() { profileStore = oldStore }()
+
+	old := time.Now().Add(-48 * time.Hour)
+	mkdir := func(path string, withFile bool) {
+		t.Helper()
+		if err := os.MkdirAll(path, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if withFile {
+			if err := os.WriteFile(filepath.Join(path, "auth.json"), []byte(`{"token":"x"}`), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// An abandoned import and one that may still be running.
+	staleTemp := filepath.Join(os.TempDir(), "caam-import-123")
+	freshTemp := filepath.Join(os.TempDir(), "caam-import-456")
+	mkdir(staleTemp, true)
+	mkdir(freshTemp, true)
+	if err := os.Chtimes(staleTemp, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lock held by a process that has exited.
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skipf("cannot run helper process: %v", err)
+	}
+	lockedDir := filepath.Join(profileStore.BasePath(), "codex", "work")
+	mkdir(lockedDir, false)
+	lockPath := filepath.Join(lockedDir, ".lock")
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf(`{"pid": %d}`, exited.Process.Pid)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two old auto-backups; the newest one must survive.
+	oldBackup := vault.ProfilePath("claude", "_backup_20200101_000000")
+	newestBackup := vault.ProfilePath("claude", "_backup_20200102_000000")
+	mkdir(oldBackup, true)
+	mkdir(newestBackup, true)
+
+	// A provider directory with nothing left in it.
+	emptyDir := filepath.Join(vault.BasePath(), "gemini")
+	mkdir(emptyDir, false)
+
+	db, err := caamdb.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.SetCooldown("codex", "work", time.Now().AddDate(0, 0, -100), time.Hour, ""); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// Dry run: everything is reported, nothing is touched.
+	cmd, out := newGCTestCmd(true)
+	if err := runGC(cmd, nil); err != nil {
+		t.Fatalf("runGC(dry-run) error = %v", err)
+	}
+	var dry gcOutput
+	if err := json.Unmarshal(out.Bytes(), &dry); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out.String())
+	}
+	kinds := map[string]string{}
+	for _, item := range dry.Items {
+		kinds[item.Path] = item.Kind
+	}
+	want := map[string]string{staleTemp: "temp", lockPath: "lock", oldBackup: "backup", emptyDir: "empty"}
+	for path, kind := range want {
+		if kinds[path] != kind {
+			t.Errorf("dry run item %s = %q, want %q (items: %+v)", path, kinds[path], kind, dry.Items)
+		}
+	}
+	if len(dry.Items) != len(want) {
+		t.Errorf("dry run found %d items, want %d: %+v", len(dry.Items), len(want), dry.Items)
+	}
+	if dry.CooldownsDeleted != 1 {
+		t.Errorf("dry run CooldownsDeleted = %d, want 1", dry.CooldownsDeleted)
+	}
+	if dry.BytesReclaimed <= 0 {
+		t.Errorf("dry run BytesReclaimed = %d, want > 0", dry.BytesReclaimed)
+	}
+	for path := range want {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("dry run removed %s", path)
+		}
+	}
+
+	// Real run.
+	cmd, out = newGCTestCmd(false)
+	if err := runGC(cmd, nil); err != nil {
+		t.Fatalf("runGC() error = %v", err)
+	}
+	var result gcOutput
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, out.String())
+	}
+	if !result.Success || result.CooldownsDeleted != 1 {
+		t.Errorf("result = %+v, want success with one cooldown deleted", result)
+	}
+	for path := range want {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s should have been removed", path)
+		}
+	}
+	for _, path := range []string{freshTemp, newestBackup, lockedDir} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s should have been kept: %v", path, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
 	"github.com/spf13/cobra"
 )
 
@@ -24,6 +25,7 @@ Examples:
   caam history --type error        # Show only errors
   caam history --since 24h         # Events from last 24 hours
   caam history --json              # Output as JSON
+  caam history exec --profile work # What 'caam exec' ran with a profile
 
 Event types: activate, login, refresh, error, switch, deactivate`,
 	Args: cobra.NoArgs,
@@ -98,23 +100,7 @@ func runHistory(cmd *cobra.Command, args []string) error {
 
 // parseDuration parses duration strings like "24h", "7d", "30m"
 func parseDuration(s string) (time.Duration, error) {
-	s = strings.ToLower(strings.TrimSpace(s))
-	if s == "" {
-		return 0, fmt.Errorf("empty duration")
-	}
-
-	// Handle day suffix specially
-	if strings.HasSuffix(s, "d") {
-		numStr := strings.TrimSuffix(s, "d")
-		var days int
-		if _, err := fmt.Sscanf(numStr, "%d", &days); err != nil {
-			return 0, fmt.Errorf("invalid days: %s", s)
-		}
-		return time.Duration(days) * 24 * time.Hour, nil
-	}
-
-	// Use standard Go duration parser for h, m, s
-	return time.ParseDuration(s)
+	return durations.Parse(s)
 }
 
 // filterEvents applies all filters to the event list
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// hookCmd is the parent command for shell hooks.
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Shell hooks for project-based auto-activation",
+	Long: `Outputs shell hooks that activate a directory's associated profiles
+(see 'caam project set') when you change into it, and show the active
+profiles in your prompt.`,
+}
+
+var hookPowerShellCmd = &cobra.Command{
+	Use:   "powershell",
+	Short: "Output the PowerShell profile hook (experimental)",
+	Long: `Outputs a PowerShell hook for Windows Terminal / PowerShell.
+
+Add this to your $PROFILE:
+
+  caam hook powershell | Out-String | Invoke-Expression
+
+The hook:
+- Activates the profiles associated with the current directory whenever the
+  location changes (Set-Location, cd, Push-Location, ...)
+- Prefixes your prompt with the active profiles, e.g. [claude:work]
+
+This hook is experimental: it depends on Windows path support in caam.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+
+		caamPath, err := findCaamPath()
+		if err != nil {
+			caamPath = "caam" // Fallback to PATH lookup
+		}
+
+		fmt.Fprint(cmd.OutOrStdout(), generatePowerShellHook(caamPath, !noPrompt))
+		return nil
+	},
+}
+
+// hookEnterCmd is invoked by shell hooks when the working directory changes.
+var hookEnterCmd = &cobra.Command{
+	Use:    "enter",
+	Short:  "Activate the profiles associated with a directory",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		if dir == "" {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get current directory: %w", err)
+			}
+			dir = cwd
+		}
+		return runHookEnter(cmd, dir)
+	},
+}
+
+// hookPromptCmd prints the prompt segment used by shell hooks.
+var hookPromptCmd = &cobra.Command{
+	Use:    "prompt",
+	Short:  "Print the active profiles as a prompt segment",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if segment := hookPromptSegment(); segment != "" {
+			fmt.Fprintln(cmd.OutOrStdout(), segment)
+		}
+		return nil
+	},
+}
+
+func init() {
+	hookPowerShellCmd.Flags().Bool("no-prompt", false, "don't add the active profiles to the prompt")
+	hookEnterCmd.Flags().String("dir", "", "directory to resolve associations for (default: current directory)")
+
+	hookCmd.AddCommand(hookPowerShellCmd)
+	hookCmd.AddCommand(hookEnterCmd)
+	hookCmd.AddCommand(hookPromptCmd)
+	rootCmd.AddCommand(hookCmd)
+}
+
+// runHookEnter activates each profile associated with dir that isn't already
+// active. It runs on every directory change, so it stays quiet unless it
+// actually switches a profile.
+func runHookEnter(cmd *cobra.Command, dir string) error {
+	if projectStore == nil {
+		return fmt.Errorf("project store not initialized")
+	}
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+
+	resolved, err := projectStore.Resolve(dir)
+	if err != nil {
+		return err
+	}
+
+	providers := make([]string, 0, len(resolved.Profiles))
+	for provider := range resolved.Profiles {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	for _, provider := range providers {
+		getFileSet, ok := tools[provider]
+		if !ok {
+			continue
+		}
+		profileName := resolved.Profiles[provider]
+		fileSet := getFileSet()
+
+		if active, _ := vault.ActiveProfile(fileSet); active == profileName {
+			continue
+		}
+		if !vaultHasProfile(provider, profileName) {
+			fmt.Fprintf(cmd.ErrOrStderr(), "caam: %s/%s is associated with this directory but not in the vault\n", provider, profileName)
+			continue
+		}
+
+		// Same safety net as 'caam activate'.
+		spmCfg, err := config.LoadSPMConfig()
+		if err != nil {
+			spmCfg = config.DefaultSPMConfig()
+		}
+		safety, err := vault.Activate(fileSet, profileName, activateOptions(spmCfg))
+		fprintActivationSafety(cmd.ErrOrStderr(), provider, safety)
+		if err != nil {
+			return fmt.Errorf("activate %s/%s: %w", provider, profileName, err)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "caam: activated %s/%s\n", provider, profileName)
+	}
+
+	return nil
+}
+
+// hookPromptSegment returns the active profiles as "tool:profile" pairs.
+func hookPromptSegment() string {
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+
+	var parts []string
+	for _, name := range knownTools() {
+		active, err := vault.ActiveProfile(tools[name]())
+		if err != nil || active == "" || authfile.IsSystemProfile(active) {
+			continue
+		}
+		parts = append(parts, name+":"+active)
+	}
+	return strings.Join(parts, " ")
+}
+
+// powershellQuote returns s as a PowerShell single-quoted string literal.
+func powershellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func generatePowerShellHook(caamPath string, prompt bool) string {
+	var sb strings.Builder
+
+	sb.WriteString("# caam PowerShell hook (experimental)\n")
+	sb.WriteString("# Add to your $PROFILE:\n")
+	sb.WriteString("#   caam hook powershell | Out-String | Invoke-Expression\n\n")
+
+	sb.WriteString(fmt.Sprintf("$global:__CaamExe = %s\n", powershellQuote(caamPath)))
+	sb.WriteString(`$global:__CaamLastDir = $null
+
+# Activate the current directory's associated profiles when the location changes.
+function global:__CaamOnLocation {
+  $dir = (Get-Location).ProviderPath
+  if ($dir -eq $global:__CaamLastDir) { return }
+  $global:__CaamLastDir = $dir
+  & $global:__CaamExe hook enter --dir $dir
+}
+
+# Set-Location hook (PowerShell 7+); the prompt below covers older versions.
+if (-not $global:__CaamHooked) {
+  if ($ExecutionContext.InvokeCommand.PSObject.Properties.Name -contains 'LocationChangedAction') {
+    $global:__CaamPrevLocationAction = $ExecutionContext.InvokeCommand.LocationChangedAction
+    $ExecutionContext.InvokeCommand.LocationChangedAction = {
+      if ($global:__CaamPrevLocationAction) { & $global:__CaamPrevLocationAction @args }
+      __CaamOnLocation
+    }
+  }
+  $global:__CaamOriginalPrompt = $function:prompt
+  $global:__CaamHooked = $true
+}
+
+`)
+
+	if prompt {
+		sb.WriteString(`# Prompt segment showing the active profiles.
+function global:prompt {
+  __CaamOnLocation
+  $segment = & $global:__CaamExe hook prompt 2>$null
+  if ($segment) { Write-Host "[$segment] " -NoNewline -ForegroundColor DarkCyan }
+  & $global:__CaamOriginalPrompt
+}
+`)
+	} else {
+		sb.WriteString(`function global:prompt {
+  __CaamOnLocation
+  & $global:__CaamOriginalPrompt
+}
+`)
+	}
+
+	sb.WriteString("\n__CaamOnLocation\n")
+	return sb.String()
+}
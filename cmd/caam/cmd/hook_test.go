@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+)
+
+func TestGeneratePowerShellHook(t *testing.T) {
+	output := generatePowerShellHook(`C:\Program Files\caam\caam.exe`, true)
+
+	if !strings.Contains(output, `$global:__CaamExe = 'C:\Program Files\caam\caam.exe'`) {
+		t.Error("caam path should be a single-quoted literal")
+	}
+	if !strings.Contains(output, "LocationChangedAction") {
+		t.Error("Missing Set-Location hook")
+	}
+	if !strings.Contains(output, "hook enter --dir $dir") {
+		t.Error("Missing 'hook enter' call")
+	}
+	if !strings.Contains(output, "hook prompt") {
+		t.Error("Missing prompt segment")
+	}
+	if !strings.Contains(output, "function global:prompt") {
+		t.Error("Missing prompt function")
+	}
+}
+
+func TestGeneratePowerShellHook_NoPrompt(t *testing.T) {
+	output := generatePowerShellHook("caam", false)
+
+	if strings.Contains(output, "hook prompt") {
+		t.Error("--no-prompt output should not render the prompt segment")
+	}
+	// Directory changes are still detected from the prompt on older PowerShell.
+	if !strings.Contains(output, "__CaamOnLocation") {
+		t.Error("Missing location check")
+	}
+}
+
+func TestPowershellQuote(t *testing.T) {
+	tests := map[string]string{
+		"caam":                 "'caam'",
+		`C:\Users\me\caam.exe`: `'C:\Users\me\caam.exe'`,
+		"it's":                 "'it''s'",
+		"$env:HOME":            "'$env:HOME'",
+	}
+	for input, want := range tests {
+		if got := powershellQuote(input); got != want {
+			t.Errorf("powershellQuote(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestHookEnter_ActivatesAssociatedProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	if err := os.MkdirAll(os.Getenv("CODEX_HOME"), 0700); err != nil {
+		t.Fatalf("MkdirAll(CODEX_HOME) error = %v", err)
+	}
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+
+	oldProjectStore := projectStore
+	projectStore = project.NewStore(filepath.Join(tmpDir, "projects.json"))
+	t.Cleanup(func() { projectStore = oldProjectStore })
+
+	profileDir := vault.ProfilePath("codex", "work")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatalf("MkdirAll(profile) error = %v", err)
+	}
+	auth := []byte(`{"access_token":"work","token_type":"Bearer"}`)
+	if err := os.WriteFile(filepath.Join(profileDir, "auth.json"), auth, 0600); err != nil {
+		t.Fatalf("WriteFile(auth) error = %v", err)
+	}
+
+	repo := filepath.Join(tmpDir, "repo")
+	if err := os.MkdirAll(filepath.Join(repo, "sub"), 0700); err != nil {
+		t.Fatalf("MkdirAll(repo) error = %v", err)
+	}
+	if err := projectStore.SetAssociation(repo, "codex", "work"); err != nil {
+		t.Fatalf("SetAssociation() error = %v", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+
+	if err := runHookEnter(cmd, filepath.Join(repo, "sub")); err != nil {
+		t.Fatalf("runHookEnter() error = %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(os.Getenv("CODEX_HOME"), "auth.json"))
+	if err != nil {
+		t.Fatalf("ReadFile(active auth) error = %v", err)
+	}
+	if string(got) != string(auth) {
+		t.Fatalf("active auth = %q, want %q", got, auth)
+	}
+	if !strings.Contains(stderr.String(), "activated codex/work") {
+		t.Errorf("stderr = %q, want activation notice", stderr.String())
+	}
+	if segment := hookPromptSegment(); !strings.Contains(segment, "codex:work") {
+		t.Errorf("hookPromptSegment() = %q, want codex:work", segment)
+	}
+
+	// Re-entering with the profile already active is silent.
+	stderr.Reset()
+	if err := runHookEnter(cmd, repo); err != nil {
+		t.Fatalf("runHookEnter() second call error = %v", err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want no output when already active", stderr.String())
+	}
+}
+
+func TestHookEnter_ReportsActivationBackups(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	if err := os.MkdirAll(os.Getenv("CODEX_HOME"), 0700); err != nil {
+		t.Fatalf("MkdirAll(CODEX_HOME) error = %v", err)
+	}
+	live := filepath.Join(os.Getenv("CODEX_HOME"), "auth.json")
+	if err := os.WriteFile(live, []byte(`{"access_token":"mine"}`), 0600); err != nil {
+		t.Fatalf("WriteFile(live auth) error = %v", err)
+	}
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+
+	oldProjectStore := projectStore
+	projectStore = project.NewStore(filepath.Join(tmpDir, "projects.json"))
+	t.Cleanup(func() { projectStore = oldProjectStore })
+
+	profileDir := vault.ProfilePath("codex", "work")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatalf("MkdirAll(profile) error = %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(profileDir, "auth.json"), []byte(`{"access_token":"work"}`), 0600); err != nil {
+		t.Fatalf("WriteFile(auth) error = %v", err)
+	}
+	repo := filepath.Join(tmpDir, "repo")
+	if err := projectStore.SetAssociation(repo, "codex", "work"); err != nil {
+		t.Fatalf("SetAssociation() error = %v", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+	if err := runHookEnter(cmd, repo); err != nil {
+		t.Fatalf("runHookEnter() error = %v", err)
+	}
+
+	if !strings.Contains(stderr.String(), "Backed up original codex auth to _original") {
+		t.Errorf("stderr = %q, want the original backup reported", stderr.String())
+	}
+	if ok, _ := vault.HasOriginalBackup("codex"); !ok {
+		t.Error("the pre-caam login should be kept as _original")
+	}
+}
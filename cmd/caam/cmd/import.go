@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/bundle"
 )
 
 var importCmd = &cobra.Command{
@@ -14,29 +19,116 @@ var importCmd = &cobra.Command{
 	Short: "Import profile(s) from an export archive",
 	Long: `Import profile auth files from an export archive created by "caam export".
 
+"caam bundle import" snapshots every profile it is about to add or overwrite
+and prints an import ID. --rollback <id> undoes that import: overwritten
+profiles get their previous auth files back and added profiles are removed.
+--resume [id] continues a bundle import that stopped partway (the latest
+one by default), re-verifying the profiles it had already imported.
+
+"caam import -" reads stdin, which may carry an export archive, an
+unencrypted bundle zip ("caam bundle export"; encrypted bundles keep their
+key data in a separate .meta file, so import those by path) or an armored
+profile from "caam export --armor". --from-clipboard imports an armored
+profile from the clipboard. Armored profiles are decrypted with --password,
+else runtime.passphrase_command, else a prompt (not when stdin carries the
+payload).
+
 Examples:
   caam import codex-work.tar.gz
   cat codex-work.tar.gz | caam import -
+  caam export claude/work --armor | ssh box caam import -
+  caam import --from-clipboard --as claude/laptop
   caam import codex-work.tar.gz --as codex/server-work
+  caam import --rollback 20261014T101500Z-3fa9c2
+  caam import --resume
 `,
-	Args: cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if cmd == nil {
+			return cobra.ExactArgs(1)(cmd, args)
+		}
+		if rollback, _ := cmd.Flags().GetString("rollback"); rollback != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		if resume, _ := cmd.Flags().GetBool("resume"); resume {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		if fromClipboard, _ := cmd.Flags().GetBool("from-clipboard"); fromClipboard {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	RunE: runImport,
 }
 
 func init() {
 	importCmd.Flags().String("as", "", "import single-profile archive under a new tool/profile (e.g. codex/server-work)")
 	importCmd.Flags().Bool("force", false, "overwrite existing profile(s) if they already exist")
+	importCmd.Flags().String("rollback", "", "undo the bundle import with this ID")
+	importCmd.Flags().Bool("resume", false, "continue an interrupted bundle import (optionally by ID)")
+	importCmd.Flags().StringP("password", "p", "", "password for an armored profile, or an encrypted bundle when resuming")
+	importCmd.Flags().Bool("from-clipboard", false, "import an armored profile from the clipboard")
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
-	inPath := strings.TrimSpace(args[0])
+	if id, _ := cmd.Flags().GetString("rollback"); id != "" {
+		return runImportRollback(cmd, id)
+	}
+	if resume, _ := cmd.Flags().GetBool("resume"); resume {
+		id := ""
+		if len(args) == 1 {
+			id = args[0]
+		}
+		return runImportResume(cmd, id)
+	}
+
 	as, _ := cmd.Flags().GetString("as")
 	force, _ := cmd.Flags().GetBool("force")
+	password, _ := cmd.Flags().GetString("password")
+
+	var opt importOptions
+	opt.Force = force
+	if as != "" {
+		tool, profile, err := parseToolProfileArg(as)
+		if err != nil {
+			return fmt.Errorf("invalid --as: %w", err)
+		}
+		opt.AsTool = tool
+		opt.AsProfile = profile
+	}
+
+	if fromClipboard, _ := cmd.Flags().GetBool("from-clipboard"); fromClipboard {
+		text, err := readClipboard()
+		if err != nil {
+			return err
+		}
+		if !bundle.IsArmored(text) {
+			return fmt.Errorf("the clipboard doesn't hold an armored profile (make one with 'caam export <tool/profile> --to-clipboard')")
+		}
+		return importArmored(text, password, false, opt)
+	}
 
+	inPath := strings.TrimSpace(args[0])
 	var r io.Reader
 	var close func() error
 	if inPath == "-" {
-		r = cmd.InOrStdin()
+		in := bufio.NewReader(cmd.InOrStdin())
+		head, _ := in.Peek(len(bundle.ArmorPrefix))
+		switch {
+		case bundle.IsArmored(head):
+			text, err := io.ReadAll(io.LimitReader(in, bundle.MaxArmorSize+1))
+			if err != nil {
+				return fmt.Errorf("read stdin: %w", err)
+			}
+			return importArmored(text, password, true, opt)
+		case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+			if opt.AsTool != "" {
+				return withExitCode(ExitUsage, fmt.Errorf("--as needs a single-profile archive, not a bundle"))
+			}
+			return importBundleStream(cmd, in, force)
+		case !bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+			return fmt.Errorf("stdin isn't an export archive, bundle zip or armored profile")
+		}
+		r = in
 		close = func() error { return nil }
 	} else {
 		f, err := os.Open(inPath)
@@ -48,17 +140,10 @@ func runImport(cmd *cobra.Command, args []string) error {
 	}
 	defer func() { _ = close() }()
 
-	var opt importOptions
-	opt.Force = force
-	if as != "" {
-		tool, profile, err := parseToolProfileArg(as)
-		if err != nil {
-			return fmt.Errorf("invalid --as: %w", err)
-		}
-		opt.AsTool = tool
-		opt.AsProfile = profile
-	}
+	return importArchiveAndReport(r, opt)
+}
 
+func importArchiveAndReport(r io.Reader, opt importOptions) error {
 	manifest, err := importArchive(r, vault, opt)
 	if err != nil {
 		return err
@@ -73,3 +158,115 @@ func runImport(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Imported %d profile(s)\n", count)
 	return nil
 }
+
+// importArmored decrypts an armored profile from "caam export --armor" and
+// imports the archive inside. fromStdin means stdin carried the payload, so
+// there's no terminal left to prompt for the password on.
+func importArmored(text []byte, password string, fromStdin bool, opt importOptions) error {
+	if password == "" {
+		var err error
+		if password, err = passphraseFromCommand(); err != nil {
+			return err
+		}
+	}
+	if password == "" {
+		if fromStdin {
+			return withExitCode(ExitUsage, fmt.Errorf("armored profile on stdin needs --password or runtime.passphrase_command"))
+		}
+		var err error
+		if password, err = promptPassword("Enter decryption password: "); err != nil {
+			return fmt.Errorf("read password: %w", err)
+		}
+	}
+
+	archive, err := bundle.Dearmor(text, password)
+	if err != nil {
+		return err
+	}
+	return importArchiveAndReport(bytes.NewReader(archive), opt)
+}
+
+// importBundleStream imports a bundle zip read from r. Zip needs random
+// access, so r is spooled to a private temp file first.
+func importBundleStream(cmd *cobra.Command, r io.Reader, force bool) error {
+	tmp, err := os.CreateTemp("", "caam-import-*"+bundle.BundleFileExtension)
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("read bundle from stdin: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write temp file: %w", err)
+	}
+
+	opts := bundle.DefaultImportOptions()
+	opts.Force = force || assumeYes(cmd)
+	setImportPaths(opts)
+	result, err := (&bundle.VaultImporter{BundlePath: tmp.Name()}).Import(opts)
+	if err != nil {
+		if result != nil && result.TransactionID != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Undo with 'caam import --rollback %s'.\n", result.TransactionID)
+		}
+		return fmt.Errorf("import failed: %w", err)
+	}
+	printImportResult(cmd, result)
+	return nil
+}
+
+// runImportRollback reverts a bundle import from its pre-import snapshot.
+func runImportRollback(cmd *cobra.Command, id string) error {
+	tx, err := bundle.RollbackImport(bundle.SnapshotDir(authfile.DefaultVaultPath()), strings.TrimSpace(id))
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	restored, removed := 0, 0
+	for _, p := range tx.Profiles {
+		if p.Existed {
+			restored++
+			fmt.Fprintf(out, "  ↺ %s/%s: restored\n", p.Provider, p.Profile)
+		} else {
+			removed++
+			fmt.Fprintf(out, "  - %s/%s: removed (added by import)\n", p.Provider, p.Profile)
+		}
+	}
+	fmt.Fprintf(out, "Rolled back import %s: %d restored, %d removed\n", tx.ID, restored, removed)
+	return nil
+}
+
+// runImportResume continues an interrupted bundle import; an empty id picks
+// the most recent one.
+func runImportResume(cmd *cobra.Command, id string) error {
+	opts := bundle.DefaultImportOptions()
+	setImportPaths(opts)
+	snapshotDir := bundle.SnapshotDir(opts.VaultPath)
+
+	var tx *bundle.ImportTransaction
+	var err error
+	if id == "" {
+		tx, err = bundle.LatestIncompleteImport(snapshotDir)
+	} else {
+		tx, err = bundle.LoadImportTransaction(snapshotDir, strings.TrimSpace(id))
+	}
+	if err != nil {
+		return err
+	}
+
+	password, _ := cmd.Flags().GetString("password")
+	if opts.Password, err = bundlePassword(tx.BundlePath, password); err != nil {
+		return err
+	}
+	opts.Force = assumeYes(cmd)
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Resuming import %s from %s\n\n", tx.ID, tx.BundlePath)
+	result, err := (&bundle.VaultImporter{}).Resume(opts, tx.ID)
+	if err != nil {
+		return fmt.Errorf("resume failed: %w", err)
+	}
+	printImportResult(cmd, result)
+	return nil
+}
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/logs"
+)
+
+var ingestLogsCmd = &cobra.Command{
+	Use:   "ingest-logs [tool...]",
+	Short: "Record rate-limit and auth errors from tool logs",
+	Long: `Scans each tool's local logs (Claude Code logs, Codex session logs,
+Gemini CLI logs) for rate-limit and authentication errors and records them in
+the caam database, including sessions that were not run under caam exec.
+
+Each event is attributed to the profile that was active at its timestamp
+(from caam's activation history, falling back to the currently active
+profile). Every event counts as an error on that profile; rate limits also
+record a cooldown starting at the time of the hit, unless one was already
+recorded for it.
+
+The first run looks back --since (default 7 days); later runs resume after
+the newest event already ingested, so running it repeatedly never duplicates
+events. With --follow, caam keeps watching the logs until interrupted.
+
+Examples:
+  caam ingest-logs                  # Ingest new events for all tools
+  caam ingest-logs claude codex     # Only Claude and Codex logs
+  caam ingest-logs --since 48h      # Look back 48 hours on first run
+  caam ingest-logs --follow         # Keep ingesting every 30s`,
+	RunE: runIngestLogs,
+}
+
+func init() {
+	rootCmd.AddCommand(ingestLogsCmd)
+	ingestLogsCmd.Flags().Duration("since", 7*24*time.Hour, "how far back to look for events not yet ingested")
+	ingestLogsCmd.Flags().Bool("follow", false, "keep watching the logs for new events")
+	ingestLogsCmd.Flags().Duration("interval", 30*time.Second, "how often to rescan the logs with --follow")
+	ingestLogsCmd.Flags().Int("cooldown", 0, "cooldown minutes for rate limits (default: stealth.cooldown.default_minutes)")
+}
+
+// ingestScanners returns the log scanner for each tool whose logs can be
+// ingested.
+var ingestScanners = map[string]func() logs.Scanner{
+	"claude": func() logs.Scanner { return logs.NewClaudeScanner() },
+	"codex":  func() logs.Scanner { return logs.NewCodexScanner() },
+	"gemini": func() logs.Scanner { return logs.NewGeminiScanner() },
+}
+
+// ingestState tracks the newest event ingested per tool so repeated runs
+// skip events already recorded.
+type ingestState struct {
+	Watermarks map[string]time.Time `json:"watermarks"`
+}
+
+// ingestSummary counts what one ingestion pass recorded for a tool.
+type ingestSummary struct {
+	RateLimits   int
+	AuthErrors   int
+	Cooldowns    int
+	Unattributed int
+}
+
+func ingestStatePath() string {
+	return filepath.Join(config.DefaultDataPath(), "ingest_state.json")
+}
+
+func loadIngestState() (*ingestState, error) {
+	state := &ingestState{Watermarks: make(map[string]time.Time)}
+	data, err := os.ReadFile(ingestStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("read ingest state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parse ingest state: %w", err)
+	}
+	if state.Watermarks == nil {
+		state.Watermarks = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+func saveIngestState(state *ingestState) error {
+	path := ingestStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create ingest state dir: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal ingest state: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("write ingest state: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename ingest state: %w", err)
+	}
+	return nil
+}
+
+func runIngestLogs(cmd *cobra.Command, args []string) error {
+	toolNames := args
+	if len(toolNames) == 0 {
+		toolNames = []string{"claude", "codex", "gemini"}
+	}
+	for i, tool := range toolNames {
+		tool = strings.ToLower(tool)
+		if _, ok := ingestScanners[tool]; !ok {
+			return withExitCode(ExitUsage, fmt.Errorf("no log parser for %s (supported: claude, codex, gemini)", tool))
+		}
+		toolNames[i] = tool
+	}
+
+	since, _ := cmd.Flags().GetDuration("since")
+	follow, _ := cmd.Flags().GetBool("follow")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	minutes, _ := cmd.Flags().GetInt("cooldown")
+	if minutes <= 0 {
+		spmCfg, err := config.LoadSPMConfig()
+		if err != nil {
+			spmCfg = config.DefaultSPMConfig()
+		}
+		minutes = spmCfg.Stealth.Cooldown.DefaultMinutes
+	}
+	if minutes <= 0 {
+		minutes = 60
+	}
+	cooldown := time.Duration(minutes) * time.Minute
+
+	db, err := caamdb.Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	state, err := loadIngestState()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	pass := func(ctx context.Context) error {
+		for _, tool := range toolNames {
+			summary, err := ingestToolLogs(ctx, db, state, tool, time.Now().Add(-since), cooldown)
+			if err != nil {
+				return fmt.Errorf("%s: %w", tool, err)
+			}
+			if !follow || summary.RateLimits+summary.AuthErrors > 0 {
+				printIngestSummary(out, tool, summary)
+			}
+		}
+		return saveIngestState(state)
+	}
+
+	if !follow {
+		return pass(context.Background())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(out, "Watching %s logs every %s (Ctrl+C to stop)\n", strings.Join(toolNames, ", "), interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := pass(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ingestToolLogs records the rate-limit and auth-error events in tool's logs
+// newer than both since and the tool's watermark, advancing the watermark.
+func ingestToolLogs(ctx context.Context, db *caamdb.DB, state *ingestState, tool string, since time.Time, cooldown time.Duration) (ingestSummary, error) {
+	var summary ingestSummary
+
+	watermark := state.Watermarks[tool]
+	if watermark.After(since) {
+		since = watermark
+	}
+
+	result, err := ingestScanners[tool]().Scan(ctx, "", since)
+	if err != nil {
+		return summary, err
+	}
+	events, err := logs.ExtractEvents(tool, result.Entries)
+	if err != nil {
+		return summary, err
+	}
+
+	syncAccountLinks(db, tool)
+
+	var fallbackProfile string
+	if vault != nil {
+		if getFileSet, ok := tools[tool]; ok {
+			fallbackProfile, _ = vault.ActiveProfile(getFileSet())
+		}
+	}
+
+	for _, ev := range events {
+		if !ev.Timestamp.After(watermark) {
+			continue
+		}
+		state.Watermarks[tool] = ev.Timestamp
+
+		profile, err := db.ProfileActiveAt(tool, ev.Timestamp)
+		if err != nil {
+			return summary, err
+		}
+		if profile == "" {
+			profile = fallbackProfile
+		}
+		if profile == "" {
+			summary.Unattributed++
+			continue
+		}
+
+		if err := db.LogEvent(caamdb.Event{
+			Timestamp:   ev.Timestamp,
+			Type:        caamdb.EventError,
+			Provider:    tool,
+			ProfileName: profile,
+			Details: map[string]any{
+				"source":  "logs",
+				"kind":    string(ev.Kind),
+				"message": ev.Message,
+			},
+		}); err != nil {
+			return summary, err
+		}
+
+		if ev.Kind != logs.EventRateLimit {
+			summary.AuthErrors++
+			continue
+		}
+		summary.RateLimits++
+
+		existing, err := db.ActiveCooldown(tool, profile, ev.Timestamp)
+		if err != nil {
+			return summary, err
+		}
+		if existing != nil {
+			continue
+		}
+		if _, err := db.SetCooldown(tool, profile, ev.Timestamp, cooldown, fmt.Sprintf("ingested from %s logs", tool)); err != nil {
+			return summary, err
+		}
+		summary.Cooldowns++
+	}
+	return summary, nil
+}
+
+func printIngestSummary(w io.Writer, tool string, s ingestSummary) {
+	fmt.Fprintf(w, "%s: %d rate limit(s), %d auth error(s), %d cooldown(s) recorded", tool, s.RateLimits, s.AuthErrors, s.Cooldowns)
+	if s.Unattributed > 0 {
+		fmt.Fprintf(w, ", %d skipped (no active profile)", s.Unattributed)
+	}
+	fmt.Fprintln(w)
+}
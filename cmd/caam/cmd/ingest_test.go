@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func newIngestLogsTestCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("since", 7*24*time.Hour, "")
+	cmd.Flags().Bool("follow", false, "")
+	cmd.Flags().Duration("interval", 30*time.Second, "")
+	cmd.Flags().Int("cooldown", 60, "")
+	return cmd
+}
+
+func TestIngestLogs_RecordsEventsAndCooldowns(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+
+	db, err := caamdb.Open()
+	if err != nil {
+		t.Fatalf("db.Open() error = %v", err)
+	}
+	defer db.Close()
+
+	t0 := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Second)
+	if err := db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "codex", ProfileName: "work", Timestamp: t0}); err != nil {
+		t.Fatalf("LogEvent() error = %v", err)
+	}
+
+	ts := func(d time.Duration) string { return t0.Add(d).Format(time.RFC3339) }
+	logDir := filepath.Join(os.Getenv("CODEX_HOME"), "logs")
+	if err := os.MkdirAll(logDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{
+		fmt.Sprintf(`{"timestamp":%q,"type":"error","message":"Rate limit reached for requests"}`, ts(-time.Hour)),
+		fmt.Sprintf(`{"timestamp":%q,"type":"response","usage":{"input_tokens":10}}`, ts(5*time.Minute)),
+		fmt.Sprintf(`{"timestamp":%q,"type":"error","message":"429 Too Many Requests"}`, ts(10*time.Minute)),
+		fmt.Sprintf(`{"timestamp":%q,"type":"error","message":"401 Unauthorized: token expired"}`, ts(20*time.Minute)),
+		fmt.Sprintf(`{"timestamp":%q,"type":"error","message":"rate limit exceeded"}`, ts(25*time.Minute)),
+	}
+	if err := os.WriteFile(filepath.Join(logDir, "session-1.jsonl"), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newIngestLogsTestCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := runIngestLogs(cmd, []string{"codex"}); err != nil {
+		t.Fatalf("runIngestLogs() error = %v", err)
+	}
+	want := "codex: 2 rate limit(s), 1 auth error(s), 1 cooldown(s) recorded, 1 skipped (no active profile)"
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("output = %q, want %q", out.String(), want)
+	}
+
+	stats, err := db.GetStats("codex", "work")
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if stats == nil || stats.TotalErrors != 3 {
+		t.Fatalf("stats = %+v, want 3 errors", stats)
+	}
+
+	history, err := db.CooldownHistory("codex", "work", t0.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("CooldownHistory() error = %v", err)
+	}
+	if len(history) != 1 || !history[0].HitAt.Equal(t0.Add(10*time.Minute)) {
+		t.Fatalf("cooldown history = %+v, want one cooldown at first attributed hit", history)
+	}
+
+	// A second run resumes after the watermark and records nothing new.
+	out.Reset()
+	if err := runIngestLogs(cmd, []string{"codex"}); err != nil {
+		t.Fatalf("runIngestLogs() second run error = %v", err)
+	}
+	if !strings.Contains(out.String(), "codex: 0 rate limit(s), 0 auth error(s), 0 cooldown(s) recorded") {
+		t.Fatalf("second run output = %q, want nothing recorded", out.String())
+	}
+	stats, _ = db.GetStats("codex", "work")
+	if stats.TotalErrors != 3 {
+		t.Fatalf("TotalErrors after second run = %d, want 3", stats.TotalErrors)
+	}
+}
+
+func TestIngestLogs_UnknownTool(t *testing.T) {
+	err := runIngestLogs(newIngestLogsTestCmd(), []string{"cursor"})
+	if err == nil || ExitCode(err) != ExitUsage {
+		t.Fatalf("runIngestLogs(cursor) = %v, want usage error", err)
+	}
+}
@@ -164,6 +164,7 @@ func saveDiscoveredSessions(found []discovery.DiscoveredAuth, autoSave bool) int
 	fmt.Println()
 
 	vault := authfile.NewVault(authfile.DefaultVaultPath())
+	authfile.ApplyVaultSettings(vault)
 	savedCount := 0
 
 	for _, auth := range found {
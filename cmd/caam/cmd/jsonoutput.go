@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// jsonStatus is the common part of every --json output: embed it in the
+// command's output struct so "success", "error", "error_code" and
+// "exit_code" sit next to the command's own fields, the same shape as backup
+// and activate.
+type jsonStatus struct {
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+	ErrorCode string `json:"error_code,omitempty"`
+	ExitCode  int    `json:"exit_code,omitempty"`
+}
+
+func (s *jsonStatus) setResult(err error) {
+	s.Success = err == nil
+	s.Error = ""
+	s.ErrorCode = ""
+	s.ExitCode = ExitOK
+	if err != nil {
+		s.Error = err.Error()
+		s.ErrorCode = errorCode(err)
+		s.ExitCode = ExitCode(err)
+	}
+}
+
+// jsonResult is implemented by output structs that embed jsonStatus.
+type jsonResult interface {
+	setResult(err error)
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeJSONResult records err in output and writes it to the command's
+// stdout. A failure is reported through the process exit code rather than
+// returned, so it isn't printed a second time as plain text.
+func writeJSONResult(cmd *cobra.Command, output jsonResult, err error) error {
+	output.setResult(err)
+	if encErr := writeJSON(cmd.OutOrStdout(), output); encErr != nil {
+		return encErr
+	}
+	if err != nil {
+		pendingExitCode = ExitCode(err)
+	}
+	return nil
+}
+
+// errCancelled reports a declined confirmation in --json output.
+var errCancelled = errors.New("cancelled")
+
+// confirmPrompt asks a y/N question on the command's input; --yes answers it.
+// With --json or --quiet the question goes to stderr so it stays visible and
+// stdout stays clean.
+func confirmPrompt(cmd *cobra.Command, jsonOutput bool, question string) bool {
+	if assumeYes(cmd) {
+		return true
+	}
+	w := cmd.OutOrStdout()
+	if jsonOutput || quietOutput(cmd) {
+		w = cmd.ErrOrStderr()
+	}
+	fmt.Fprintf(w, "%s [y/N]: ", question)
+	var confirm string
+	fmt.Fscanln(cmd.InOrStdin(), &confirm)
+	return strings.ToLower(confirm) == "y"
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSONResult(t *testing.T) {
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+
+	var buf bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&buf)
+
+	output := clearOutput{Tool: "codex", Files: []authfile.ClearPreview{}}
+	require.NoError(t, writeJSONResult(cmd, &output, nil))
+	assert.Equal(t, ExitOK, pendingExitCode)
+	assert.JSONEq(t, `{"success": true, "tool": "codex", "files": []}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeJSONResult(cmd, &output, withExitCode(ExitUsage, errors.New("bad tool"))))
+	assert.Equal(t, ExitUsage, pendingExitCode)
+	assert.JSONEq(t, `{"success": false, "error": "bad tool", "error_code": "INVALID_ARGUMENT", "exit_code": 2, "tool": "codex", "files": []}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeJSONResult(cmd, &output, withErrorCode("INVALID_PROVIDER", errors.New("unknown tool: nope"))))
+	assert.Equal(t, ExitUsage, pendingExitCode)
+	assert.JSONEq(t, `{"success": false, "error": "unknown tool: nope", "error_code": "INVALID_PROVIDER", "exit_code": 2, "tool": "codex", "files": []}`, buf.String())
+}
+
+func TestDeleteJSON_PromptGoesToStderr(t *testing.T) {
+	setupAccountsTest(t)
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+	writeClaudeVaultProfile(t, "old", "old@example.com")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("force", false, "")
+	cmd.Flags().Bool("json", true, "")
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader("n\n"))
+
+	require.NoError(t, deleteCmd.RunE(cmd, []string{"claude", "old"}))
+	assert.Contains(t, stderr.String(), "Delete profile claude/old? [y/N]")
+
+	var out deleteOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	assert.False(t, out.Success)
+	assert.Equal(t, "cancelled", out.Error)
+	assert.Equal(t, "CANCELLED", out.ErrorCode)
+	assert.True(t, vaultHasProfile("claude", "old"))
+
+	stdout.Reset()
+	cmd.SetIn(strings.NewReader("y\n"))
+	require.NoError(t, deleteCmd.RunE(cmd, []string{"claude", "old"}))
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	assert.True(t, out.Success)
+	assert.False(t, vaultHasProfile("claude", "old"))
+}
+
+func TestPathsJSON(t *testing.T) {
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", true, "")
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+
+	require.NoError(t, pathsCmd.RunE(cmd, []string{"codex"}))
+	var out pathsOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.True(t, out.Success)
+	require.Len(t, out.Tools, 1)
+	assert.Equal(t, "codex", out.Tools[0].Tool)
+	assert.NotEmpty(t, out.Tools[0].Files)
+}
+
+func TestConfirmPrompt_GlobalYes(t *testing.T) {
+	cmd := &cobra.Command{Use: "delete"}
+	root := &cobra.Command{Use: "caam"}
+	root.PersistentFlags().Bool("yes", false, "")
+	root.PersistentFlags().Bool("quiet", false, "")
+	root.AddCommand(cmd)
+
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader(""))
+	require.NoError(t, cmd.ParseFlags(nil)) // merges the persistent flags
+
+	require.NoError(t, root.PersistentFlags().Set("quiet", "true"))
+	assert.True(t, quietOutput(cmd))
+	assert.False(t, confirmPrompt(cmd, false, "Delete?"))
+	assert.Contains(t, stderr.String(), "Delete? [y/N]", "quiet prompts stay visible on stderr")
+	assert.Empty(t, stdout.String())
+
+	stderr.Reset()
+	require.NoError(t, root.PersistentFlags().Set("yes", "true"))
+	assert.True(t, confirmPrompt(cmd, false, "Delete?"))
+	assert.Empty(t, stderr.String())
+}
+
+func TestQuietOutput_LocalFlagShadowsGlobal(t *testing.T) {
+	root := &cobra.Command{Use: "caam"}
+	root.PersistentFlags().Bool("quiet", false, "")
+	cmd := &cobra.Command{Use: "wait"}
+	cmd.Flags().Bool("quiet", false, "")
+	root.AddCommand(cmd)
+	require.NoError(t, cmd.ParseFlags(nil))
+
+	require.NoError(t, cmd.Flags().Set("quiet", "true"))
+	assert.False(t, quietOutput(cmd), "a command's own --quiet is handled by the command")
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/logs"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
 )
@@ -32,7 +33,13 @@ Examples:
   caam limits codex               # Show Codex limits only
   caam limits --profile work      # Show limits for a specific profile
   caam limits --format json       # Output as JSON
-  caam limits --best              # Show the best profile for rotation`,
+  caam limits --best              # Show the best profile for rotation
+  caam limits gemini --estimate   # Estimate headroom from recorded usage
+
+--estimate works offline: it applies each provider's known limit structure
+(Claude 5-hour window + weekly cap, ChatGPT message caps, Gemini daily quota)
+to usage and limit hits recorded by caam, so it also covers providers without
+a usage API.`,
 	RunE: runLimits,
 }
 
@@ -44,6 +51,7 @@ func init() {
 	limitsCmd.Flags().Float64("threshold", 0.8, "utilization threshold for rotation (0-1)")
 	limitsCmd.Flags().Bool("recommend", false, "show smart rotation recommendations")
 	limitsCmd.Flags().Bool("forecast", false, "show usage forecasts and optimal switch times")
+	limitsCmd.Flags().Bool("estimate", false, "estimate headroom from recorded usage and limit hits (offline)")
 }
 
 func runLimits(cmd *cobra.Command, args []string) error {
@@ -53,12 +61,17 @@ func runLimits(cmd *cobra.Command, args []string) error {
 	threshold, _ := cmd.Flags().GetFloat64("threshold")
 	showRecommend, _ := cmd.Flags().GetBool("recommend")
 	showForecast, _ := cmd.Flags().GetBool("forecast")
+	showEstimate, _ := cmd.Flags().GetBool("estimate")
 
 	var providers []string
 	if len(args) > 0 {
 		providers = []string{strings.ToLower(args[0])}
 	} else {
-		providers = []string{"claude", "codex", "gemini"}
+		providers = knownTools()
+	}
+
+	if showEstimate {
+		return runLimitsEstimate(cmd.OutOrStdout(), format, providers, profileArg)
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -148,6 +161,165 @@ func getProfileToken(vaultDir, provider, profileName string) (string, error) {
 	}
 }
 
+func runLimitsEstimate(w io.Writer, format string, providers []string, profileArg string) error {
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+
+	db, err := caamdb.Open()
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	estimates := make([]usage.HeadroomEstimate, 0)
+	for _, provider := range providers {
+		if _, ok := usage.LimitModelFor(provider); !ok {
+			return fmt.Errorf("no limit model for provider: %s", provider)
+		}
+		profiles := []string{profileArg}
+		if profileArg == "" {
+			profiles, err = vault.List(provider)
+			if err != nil {
+				return fmt.Errorf("list %s profiles: %w", provider, err)
+			}
+		}
+		for _, name := range profiles {
+			if authfile.IsSystemProfile(name) {
+				continue
+			}
+			if est, ok := estimateProfileHeadroom(db, provider, name, now); ok {
+				estimates = append(estimates, est)
+			}
+		}
+	}
+
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "json":
+		data, err := json.MarshalIndent(estimates, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(data))
+		return nil
+
+	case "table", "":
+		if len(estimates) == 0 {
+			fmt.Fprintln(w, "No profiles found.")
+			return nil
+		}
+
+		fmt.Fprintln(w, "Estimated Limit Headroom (from recorded usage)")
+		fmt.Fprintln(w, "──────────────────────────────────────────────────────────────────────────────────────────")
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "PROFILE\tHEADROOM\tWINDOWS")
+		for _, est := range estimates {
+			windows := make([]string, 0, len(est.Windows))
+			for _, we := range est.Windows {
+				var desc string
+				if we.Exhausted {
+					desc = fmt.Sprintf("%s spent, resets in %s", we.Window, formatLimitsDuration(we.ResetsAt.Sub(now)))
+				} else {
+					desc = fmt.Sprintf("%s %d%% left", we.Window, int(we.Headroom*100))
+				}
+				if we.Learned {
+					desc += " (learned)"
+				}
+				windows = append(windows, desc)
+			}
+			fmt.Fprintf(tw, "%s/%s\t%d%%\t%s\n", est.Provider, est.ProfileName, int(est.Headroom*100), strings.Join(windows, "; "))
+		}
+		return tw.Flush()
+
+	default:
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+// estimateProfileHeadroom applies the provider's limit model to the wrap
+// sessions and limit hits recorded for a profile. It returns false if the
+// provider has no limit model or the history can't be read.
+func estimateProfileHeadroom(db *caamdb.DB, provider, profileName string, now time.Time) (usage.HeadroomEstimate, bool) {
+	model, ok := usage.LimitModelFor(provider)
+	if !ok || db == nil {
+		return usage.HeadroomEstimate{}, false
+	}
+
+	samples, hits, err := profileUsageHistory(db, model, profileName, now)
+	if err != nil {
+		return usage.HeadroomEstimate{}, false
+	}
+	spans := make([]usage.UsageSample, 0, len(samples))
+	for _, s := range samples {
+		spans = append(spans, s.UsageSample)
+	}
+
+	est := usage.EstimateHeadroom(model, spans, hits, now)
+	est.ProfileName = profileName
+	return est, true
+}
+
+// estimateProjectUse is estimateProfileHeadroom narrowed to the sessions
+// that ran in project.
+func estimateProjectUse(db *caamdb.DB, provider, profileName, project string, now time.Time) (usage.ProjectUse, bool) {
+	model, ok := usage.LimitModelFor(provider)
+	if !ok || db == nil || project == "" {
+		return usage.ProjectUse{}, false
+	}
+
+	samples, hits, err := profileUsageHistory(db, model, profileName, now)
+	if err != nil {
+		return usage.ProjectUse{}, false
+	}
+	use, ok := usage.EstimateProjectUse(model, samples, hits, project, now)
+	use.ProfileName = profileName
+	return use, ok
+}
+
+// profileUsageHistory returns a profile's wrap sessions, with where each
+// ran, and its limit hits, looking back far enough to learn a budget from
+// an older hit.
+func profileUsageHistory(db *caamdb.DB, model usage.LimitModel, profileName string, now time.Time) ([]usage.ProjectSample, []time.Time, error) {
+	since := now.Add(-4 * model.MaxWindow())
+
+	sessions, err := db.GetWrapSessions(model.Provider, since, 5000)
+	if err != nil {
+		return nil, nil, err
+	}
+	var samples []usage.ProjectSample
+	for _, s := range sessions {
+		if s.ProfileName != profileName {
+			continue
+		}
+		d := time.Duration(s.DurationSeconds) * time.Second
+		if d <= 0 && !s.EndedAt.IsZero() {
+			d = s.EndedAt.Sub(s.StartedAt)
+		}
+		samples = append(samples, usage.ProjectSample{
+			UsageSample: usage.UsageSample{Start: s.StartedAt, Duration: d},
+			WorkDir:     s.WorkDir,
+		})
+	}
+
+	events, err := db.CooldownHistory(model.Provider, profileName, since)
+	if err != nil {
+		return nil, nil, err
+	}
+	hits := make([]time.Time, 0, len(events))
+	for _, ev := range events {
+		hits = append(hits, ev.HitAt)
+	}
+	return samples, hits, nil
+}
+
+// formatProjectUse phrases a project's share of a window as a warning.
+func formatProjectUse(use usage.ProjectUse) string {
+	return fmt.Sprintf("project %s has used %d%% of %s/%s's %s window",
+		filepath.Base(use.Project), int(use.Share*100), use.Provider, use.ProfileName, use.Window)
+}
+
 func getVaultDir() string {
 	return authfile.DefaultVaultPath()
 }
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitsEstimate_UsesRecordedHistory(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	originalVault := vault
+	t.Cleanup(func() { vault = originalVault })
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	for _, name := range []string{"busy", "idle"} {
+		require.NoError(t, os.MkdirAll(vault.ProfilePath("gemini", name), 0700))
+	}
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	now := time.Now().UTC()
+	require.NoError(t, db.RecordWrapSession(caamdb.WrapSession{
+		Provider:        "gemini",
+		ProfileName:     "busy",
+		StartedAt:       now.Add(-3 * time.Hour),
+		EndedAt:         now.Add(-time.Hour),
+		DurationSeconds: int((2 * time.Hour).Seconds()),
+	}))
+	_, err = db.SetCooldown("gemini", "busy", now.Add(-time.Hour), 30*time.Minute, "")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	var buf bytes.Buffer
+	require.NoError(t, runLimitsEstimate(&buf, "json", []string{"gemini"}, ""))
+
+	var estimates []usage.HeadroomEstimate
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &estimates))
+	require.Len(t, estimates, 2)
+
+	byName := map[string]usage.HeadroomEstimate{}
+	for _, est := range estimates {
+		byName[est.ProfileName] = est
+	}
+	assert.Equal(t, 0.0, byName["busy"].Headroom, "recent limit hit should exhaust the daily window")
+	assert.True(t, byName["busy"].Windows[0].Learned)
+	assert.Equal(t, 1.0, byName["idle"].Headroom)
+
+	buf.Reset()
+	require.NoError(t, runLimitsEstimate(&buf, "table", []string{"gemini"}, "busy"))
+	assert.Contains(t, buf.String(), "gemini/busy")
+	assert.Contains(t, buf.String(), "daily spent")
+}
+
+func TestProjectShareWarning(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	now := time.Now().UTC()
+	// Claude's 5h window defaults to a 3h budget: 2h15m from foo is 75%.
+	for _, s := range []struct {
+		dir     string
+		minutes int
+	}{{"/src/foo", 90}, {"/src/foo/api", 45}, {"/src/bar", 30}} {
+		require.NoError(t, db.RecordWrapSession(caamdb.WrapSession{
+			Provider:        "claude",
+			ProfileName:     "work-2",
+			StartedAt:       now.Add(-4 * time.Hour),
+			EndedAt:         now.Add(-4*time.Hour + time.Duration(s.minutes)*time.Minute),
+			DurationSeconds: s.minutes * 60,
+			WorkDir:         s.dir,
+		}))
+	}
+	require.NoError(t, db.Close())
+
+	dirs := map[string]string{"claude": "/src/foo"}
+	assert.Equal(t, "project foo has used 75% of claude/work-2's 5h window",
+		projectShareWarning(dirs, 70, "claude", "work-2"))
+	assert.Empty(t, projectShareWarning(dirs, 80, "claude", "work-2"), "below threshold")
+	assert.Empty(t, projectShareWarning(dirs, 0, "claude", "work-2"), "disabled")
+	assert.Empty(t, projectShareWarning(dirs, 70, "codex", "work-2"), "no project for tool")
+}
@@ -3,12 +3,15 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -147,3 +150,136 @@ func TestLsCommand_FilterByTool(t *testing.T) {
 	assert.Equal(t, "claude", output.Profiles[0].Tool)
 	assert.Equal(t, "work", output.Profiles[0].Name)
 }
+
+// TestLsCommand_ExpiryAndSort tests `ls --expiry --sort expiry`
+func TestLsCommand_ExpiryAndSort(t *testing.T) {
+	tmpDir := t.TempDir()
+	originalVault, originalHealthStore := vault, healthStore
+	defer func() {
+		vault, healthStore = originalVault, originalHealthStore
+		lsCmd.Flags().Set("json", "false")
+		lsCmd.Flags().Set("expiry", "false")
+		lsCmd.Flags().Set("sort", "name")
+	}()
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	healthStore = nil
+
+	now := time.Now()
+	expiries := map[string]time.Time{
+		"a-late":    now.Add(3*24*time.Hour + time.Hour),
+		"b-soon":    now.Add(2*time.Hour + 30*time.Second),
+		"c-unknown": {},
+		"d-expired": now.Add(-time.Hour),
+	}
+	for name, expiresAt := range expiries {
+		dir := vault.ProfilePath("claude", name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		if expiresAt.IsZero() {
+			continue
+		}
+		creds := fmt.Sprintf(`{"claudeAiOauth":{"accessToken":"tok","expiresAt":%d}}`, expiresAt.UnixMilli())
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".credentials.json"), []byte(creds), 0600))
+	}
+
+	run := func() string {
+		oldStdout := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		lsCmd.SetOut(w)
+		err := runLs(lsCmd, []string{"claude"})
+		w.Close()
+		os.Stdout = oldStdout
+		lsCmd.SetOut(nil)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		return buf.String()
+	}
+
+	lsCmd.Flags().Set("sort", "expiry")
+	lsCmd.Flags().Set("json", "true")
+	var output lsOutput
+	require.NoError(t, json.Unmarshal([]byte(run()), &output))
+	var names []string
+	for _, p := range output.Profiles {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"d-expired", "b-soon", "a-late", "c-unknown"}, names)
+	assert.Equal(t, "2h", output.Profiles[1].Health.ExpiresIn)
+
+	lsCmd.Flags().Set("json", "false")
+	lsCmd.Flags().Set("expiry", "true")
+	text := run()
+	assert.Contains(t, text, "EXPIRES")
+	assert.Regexp(t, `a-late\s+.*\s3d\s`, text)
+	assert.Regexp(t, `d-expired\s+.*\sexpired\s`, text)
+	assert.Regexp(t, `c-unknown\s+.*\s-\s`, text)
+
+	lsCmd.Flags().Set("sort", "oldest")
+	err := runLs(lsCmd, []string{"claude"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
+
+// TestLsCommand_FilterAndActiveOnly tests `ls --filter` and `ls --active-only`
+func TestLsCommand_FilterAndActiveOnly(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	originalHealthStore := healthStore
+	defer func() {
+		healthStore = originalHealthStore
+		lsCmd.Flags().Set("json", "false")
+		lsCmd.Flags().Set("filter", "")
+		lsCmd.Flags().Set("active-only", "false")
+	}()
+	healthStore = nil
+
+	for _, name := range []string{"alt", "work"} {
+		dir := vault.ProfilePath("codex", name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"account":"`+name+`"}`), 0600))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(os.Getenv("CODEX_HOME"), "auth.json"), []byte(`{"account":"work"}`), 0600))
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	_, err = db.SetCooldown("codex", "alt", time.Now(), time.Hour, "")
+	require.NoError(t, err)
+	activatedAt := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	require.NoError(t, db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "codex", ProfileName: "work", Timestamp: activatedAt}))
+	db.Close()
+
+	var lastUsed []string
+	list := func() []string {
+		var buf bytes.Buffer
+		lsCmd.SetOut(&buf)
+		defer lsCmd.SetOut(nil)
+		require.NoError(t, runLs(lsCmd, []string{"codex"}))
+		var output lsOutput
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &output))
+		names := []string{}
+		lastUsed = nil
+		for _, p := range output.Profiles {
+			names = append(names, p.Name)
+			lastUsed = append(lastUsed, p.LastUsed)
+		}
+		return names
+	}
+
+	lsCmd.Flags().Set("json", "true")
+	lsCmd.Flags().Set("active-only", "true")
+	assert.Equal(t, []string{"work"}, list())
+	assert.Equal(t, []string{activatedAt.UTC().Format(time.RFC3339)}, lastUsed)
+
+	lsCmd.Flags().Set("active-only", "false")
+	lsCmd.Flags().Set("filter", "cooldown")
+	assert.Equal(t, []string{"alt"}, list())
+
+	lsCmd.Flags().Set("active-only", "true")
+	assert.Equal(t, []string{}, list())
+
+	lsCmd.Flags().Set("filter", "broken")
+	err = runLs(lsCmd, []string{"codex"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
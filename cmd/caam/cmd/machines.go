@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/version"
+)
+
+// machinesCmd groups commands about the machines in the sync pool.
+var machinesCmd = &cobra.Command{
+	Use:   "machines",
+	Short: "Inspect machines in the sync pool",
+	Long: `Commands for inspecting the machines in the sync pool.
+
+Use 'caam sync' to add, remove and sync with machines.
+
+Subcommands:
+  status    Check machine health: clock skew, caam version, profiles`,
+}
+
+var machinesStatusCmd = &cobra.Command{
+	Use:   "status [machine]",
+	Short: "Check the health of sync pool machines",
+	Long: `Connects to each machine in the sync pool (or just the named one) and
+records its health:
+
+  - clock skew against this machine (freshness comparisons depend on it)
+  - the caam version installed there
+  - how many profiles its vault holds per provider
+
+and shows it alongside the last successful sync per provider. Warns when a
+remote clock is off by more than a minute, or when the remote caam is missing
+or of an incompatible (different major) version. 'caam sync' repeats the
+version warning for machines whose last check found one.
+
+With --no-check, shows the results of the last check without connecting.
+
+Examples:
+  caam machines status
+  caam machines status work-laptop
+  caam machines status --no-check --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runMachinesStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(machinesCmd)
+	machinesCmd.AddCommand(machinesStatusCmd)
+	machinesStatusCmd.Flags().Bool("json", false, "output in JSON format")
+	machinesStatusCmd.Flags().Bool("no-check", false, "show the last recorded check without connecting")
+}
+
+// machineStatusJSON is one machine in 'caam machines status --json' output.
+type machineStatusJSON struct {
+	Name     string               `json:"name"`
+	Address  string               `json:"address"`
+	Status   string               `json:"status"`
+	Error    string               `json:"error,omitempty"`
+	Health   *sync.MachineHealth  `json:"health,omitempty"`
+	LastSync map[string]time.Time `json:"last_sync"`
+	Warnings []string             `json:"warnings,omitempty"`
+}
+
+func runMachinesStatus(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	noCheck, _ := cmd.Flags().GetBool("no-check")
+	out := cmd.OutOrStdout()
+
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+
+	machines := state.Pool.ListMachines()
+	if len(args) > 0 {
+		m := state.Pool.GetMachineByName(args[0])
+		if m == nil {
+			return withExitCode(ExitUsage, fmt.Errorf("machine %q not found in pool", args[0]))
+		}
+		machines = []*sync.Machine{m}
+	}
+	if len(machines) == 0 && !jsonOutput {
+		fmt.Fprintln(out, "No machines in sync pool.")
+		fmt.Fprintln(out, "Add one with: caam sync add <name> <address>")
+		return nil
+	}
+
+	if !noCheck {
+		pool := sync.NewConnectionPool(sync.DefaultConnectOptions())
+		defer pool.CloseAll()
+		for _, m := range machines {
+			checkMachine(pool, m)
+			state.Pool.UpdateMachine(m)
+		}
+		if err := state.Save(); err != nil {
+			return fmt.Errorf("save sync state: %w", err)
+		}
+	}
+
+	local := version.Short()
+	results := make([]machineStatusJSON, 0, len(machines))
+	for _, m := range machines {
+		r := machineStatusJSON{
+			Name:     m.Name,
+			Address:  m.Address,
+			Status:   m.Status,
+			Health:   m.Health,
+			LastSync: state.LastSyncByProvider(m.Name),
+			Warnings: m.Health.Warnings(local),
+		}
+		if m.Status == sync.StatusError {
+			r.Error = m.LastError
+		}
+		results = append(results, r)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+
+	fmt.Fprintf(out, "Local caam: %s\n\n", local)
+	for _, r := range results {
+		printMachineStatus(out, r)
+	}
+	return nil
+}
+
+// checkMachine connects to m and records its health, or the connection
+// error. The previous health is kept when m can't be reached.
+func checkMachine(pool *sync.ConnectionPool, m *sync.Machine) {
+	start := time.Now()
+	client, err := pool.Get(m)
+	if err != nil {
+		m.SetError(err.Error())
+		return
+	}
+	latency := time.Since(start)
+	m.SetOnline()
+	m.Health = sync.CheckMachineHealth(client, remoteVaultPath(m), knownTools())
+	m.Health.Latency = latency
+}
+
+func printMachineStatus(out io.Writer, r machineStatusJSON) {
+	fmt.Fprintf(out, "%s (%s): %s %s\n", r.Name, r.Address, getStatusIcon(r.Status), r.Status)
+	if r.Error != "" {
+		fmt.Fprintf(out, "  Error: %s\n", r.Error)
+	}
+
+	if h := r.Health; h != nil {
+		fmt.Fprintf(out, "  Checked: %s\n", formatTimeAgo(h.CheckedAt))
+		if h.Latency > 0 {
+			fmt.Fprintf(out, "  Latency: %s\n", h.Latency.Round(time.Millisecond))
+		}
+		caamVersion := h.CAAMVersion
+		if caamVersion == "" {
+			caamVersion = "not found"
+		}
+		fmt.Fprintf(out, "  caam: %s\n", caamVersion)
+		if h.ClockSkewKnown {
+			fmt.Fprintf(out, "  Clock skew: %s\n", formatClockSkew(h.ClockSkew))
+		} else {
+			fmt.Fprintln(out, "  Clock skew: unknown")
+		}
+		fmt.Fprintf(out, "  Profiles: %s\n", formatProfileCounts(h.Profiles))
+	}
+
+	if len(r.LastSync) > 0 {
+		providers := make([]string, 0, len(r.LastSync))
+		for provider := range r.LastSync {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+		fmt.Fprintln(out, "  Last sync:")
+		for _, provider := range providers {
+			fmt.Fprintf(out, "    %-8s %s\n", provider, formatTimeAgo(r.LastSync[provider]))
+		}
+	} else {
+		fmt.Fprintln(out, "  Last sync: never")
+	}
+
+	for _, w := range r.Warnings {
+		fmt.Fprintf(out, "  ⚠️  %s\n", w)
+	}
+	fmt.Fprintln(out)
+}
+
+// formatClockSkew describes a remote clock offset, e.g. "3s ahead".
+func formatClockSkew(skew time.Duration) string {
+	switch {
+	case skew == 0:
+		return "none"
+	case skew > 0:
+		return skew.String() + " ahead"
+	default:
+		return (-skew).String() + " behind"
+	}
+}
+
+// formatProfileCounts lists profile counts by provider, e.g.
+// "codex 1, claude 2".
+func formatProfileCounts(counts map[string]int) string {
+	var parts []string
+	for _, provider := range knownTools() {
+		if n := counts[provider]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", provider, n))
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
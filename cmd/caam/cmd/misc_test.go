@@ -475,6 +475,40 @@ func TestOpenCommand(t *testing.T) {
 	}
 }
 
+// TestRunOpen_PrintsUsageURL tests that open defaults to the usage page.
+func TestRunOpen_PrintsUsageURL(t *testing.T) {
+	tests := []struct {
+		args    []string
+		account bool
+		want    string
+	}{
+		{[]string{"codex"}, false, "https://platform.openai.com/usage"},
+		{[]string{"claude", "work"}, false, "https://console.anthropic.com/settings/usage"},
+		{[]string{"gemini"}, true, "https://aistudio.google.com/"},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("url", "", "")
+		cmd.Flags().Bool("account", tt.account, "")
+		cmd.Flags().Bool("print", true, "")
+		var out strings.Builder
+		cmd.SetOut(&out)
+
+		if err := runOpen(cmd, tt.args); err != nil {
+			t.Fatalf("runOpen(%v) error = %v", tt.args, err)
+		}
+		if got := strings.TrimSpace(out.String()); got != tt.want {
+			t.Errorf("runOpen(%v, account=%v) printed %q, want %q", tt.args, tt.account, got, tt.want)
+		}
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("print", true, "")
+	if err := runOpen(cmd, []string{"nope"}); err == nil {
+		t.Error("runOpen(nope) should fail for an unknown provider")
+	}
+}
+
 
 // TestCollectSessions tests session collection function.
 func TestCollectSessions(t *testing.T) {
@@ -5,9 +5,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
@@ -54,6 +53,7 @@ func init() {
 	monitorCmd.Flags().BoolP("once", "1", false, "fetch once and exit")
 	monitorCmd.Flags().Bool("no-emoji", false, "disable emoji in table output")
 	monitorCmd.Flags().IntP("width", "w", 75, "table width")
+	addPIDFileFlag(monitorCmd)
 }
 
 func runMonitor(cmd *cobra.Command, args []string) error {
@@ -95,31 +95,25 @@ func runMonitor(cmd *cobra.Command, args []string) error {
 		renderer = monitor.NewAlertRenderer(threshold)
 	}
 
-	// Set up context with cancellation
-	ctx, cancel := context.WithCancel(cmd.Context())
-	defer cancel()
-
-	// Handle signals
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		<-sigCh
-		cancel()
-	}()
+	// Canceled on SIGINT/SIGTERM; the deferred db.Close below still runs.
+	ctx, finish, err := startLongRunning(cmd)
+	if err != nil {
+		return err
+	}
+	defer finish()
 
 	// Set up monitor dependencies
 	vaultPath := authfile.DefaultVaultPath()
 	vault := authfile.NewVault(vaultPath)
+	authfile.ApplyVaultSettings(vault)
 
 	var db *caamdb.DB
 	var pool *authpool.AuthPool
 	var healthStore *health.Storage
 
-	db, err := caamdb.Open()
-	if err != nil {
-		// Continue without DB - just log warning
-		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not open database: %v\n", err)
-	} else {
+	// Continue without DB; caamdb reports why it couldn't be opened.
+	db, err = caamdb.Open()
+	if err == nil {
 		defer db.Close()
 	}
 
@@ -159,7 +153,9 @@ func runLiveMonitor(ctx context.Context, mon *monitor.Monitor, renderer monitor.
 	// Set up keyboard input for table mode
 	var inputCh <-chan byte
 	if format == "table" && term.IsTerminal(int(os.Stdin.Fd())) {
-		inputCh = setupKeyboardInput()
+		var restore func()
+		inputCh, restore = setupKeyboardInput()
+		defer restore()
 	}
 
 	ticker := time.NewTicker(interval)
@@ -223,17 +219,23 @@ func clearScreen(out io.Writer) {
 	fmt.Fprint(out, "\033[2J\033[H")
 }
 
-func setupKeyboardInput() <-chan byte {
+// setupKeyboardInput puts the terminal in raw mode and returns a channel of
+// key presses and a function that restores the terminal.
+func setupKeyboardInput() (<-chan byte, func()) {
 	ch := make(chan byte, 1)
 
 	// Try to put terminal in raw mode for key input
 	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
 	if err != nil {
-		return ch // Return empty channel if we can't get raw mode
+		return ch, func() {} // Return empty channel if we can't get raw mode
+	}
+	var once sync.Once
+	restore := func() {
+		once.Do(func() { _ = term.Restore(int(os.Stdin.Fd()), oldState) })
 	}
 
 	go func() {
-		defer term.Restore(int(os.Stdin.Fd()), oldState)
+		defer restore()
 		buf := make([]byte, 1)
 		for {
 			n, err := os.Stdin.Read(buf)
@@ -247,5 +249,5 @@ func setupKeyboardInput() <-chan byte {
 		}
 	}()
 
-	return ch
+	return ch, restore
 }
@@ -16,9 +16,8 @@ import (
 
 // nextCmd rotates to the next available profile for a tool.
 var nextCmd = &cobra.Command{
-	Use:     "next <tool>",
-	Aliases: []string{"rotate"},
-	Short:   "Rotate to next available profile",
+	Use:   "next <tool>",
+	Short: "Rotate to next available profile",
 	Long: `Instantly rotate to the next best profile for a tool.
 
 Uses the configured rotation algorithm to select the next profile:
@@ -42,6 +41,7 @@ func init() {
 	nextCmd.Flags().Bool("force", false, "activate even if profile is in cooldown")
 	nextCmd.Flags().String("algorithm", "", "override rotation algorithm (smart, round_robin, random)")
 	nextCmd.Flags().Bool("usage-aware", false, "fetch real-time rate limits to inform selection")
+	registerValueCompletion(nextCmd, "algorithm", rotationAlgorithms...)
 	rootCmd.AddCommand(nextCmd)
 }
 
@@ -56,7 +56,7 @@ func runNext(cmd *cobra.Command, args []string) error {
 	// Validate tool
 	getFileSet, ok := tools[tool]
 	if !ok {
-		return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	// Ensure vault is initialized
@@ -77,6 +77,12 @@ func runNext(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no profiles found for %s; create one with 'caam backup %s <name>'", tool, tool)
 	}
 
+	// Only rotate through profiles the current directory's class allows.
+	profiles, required := filterProfilesForClass(tool, profiles)
+	if len(profiles) == 0 {
+		return withExitCode(ExitNoHealthyProfile, fmt.Errorf("no %s profiles of class %s for this directory", tool, required))
+	}
+
 	if len(profiles) == 1 {
 		if currentProfile == profiles[0] {
 			fmt.Printf("Only one profile available for %s (%s), already active\n", tool, profiles[0])
@@ -84,9 +90,18 @@ func runNext(cmd *cobra.Command, args []string) error {
 		}
 		// Single profile case: just activate it
 		if !dryRun {
-			if err := vault.Restore(fileSet, profiles[0]); err != nil {
+			spmCfg, err := config.LoadSPMConfig()
+			if err != nil {
+				spmCfg = config.DefaultSPMConfig()
+			}
+			safety, err := vault.Activate(fileSet, profiles[0], activateOptions(spmCfg))
+			if !quiet {
+				printActivationSafety(tool, safety)
+			}
+			if err != nil {
 				return fmt.Errorf("activate failed: %w", err)
 			}
+			recordActivationMachine(tool, profiles[0])
 		}
 		if !quiet {
 			if dryRun {
@@ -103,6 +118,7 @@ func runNext(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		spmCfg = config.DefaultSPMConfig()
 	}
+	applyContextStrategy(spmCfg)
 
 	// Override algorithm if specified
 	if algoOverride != "" {
@@ -112,11 +128,7 @@ func runNext(cmd *cobra.Command, args []string) error {
 	// Open database for health/cooldown checks
 	var db *caamdb.DB
 	db, err = caamdb.Open()
-	if err != nil {
-		if !quiet {
-			fmt.Printf("Warning: could not open database: %v\n", err)
-		}
-	} else {
+	if err == nil {
 		defer db.Close()
 	}
 
@@ -178,9 +190,14 @@ func runNext(cmd *cobra.Command, args []string) error {
 	}
 
 	// Activate selected profile
-	if err := vault.Restore(fileSet, selection.Selected); err != nil {
+	safety, err := vault.Activate(fileSet, selection.Selected, activateOptions(spmCfg))
+	if !quiet {
+		printActivationSafety(tool, safety)
+	}
+	if err != nil {
 		return fmt.Errorf("activate failed: %w", err)
 	}
+	recordActivationMachine(tool, selection.Selected)
 
 	// Log event
 	if spmCfg.Analytics.Enabled && db != nil {
@@ -269,6 +286,7 @@ func selectProfileWithRotationAndUsage(tool string, profiles []string, currentPr
 	}
 
 	selector := rotation.NewSelector(algorithm, healthStore, db)
+	selector.SetExcluded(quarantinedProfiles(tool))
 
 	// Set usage data if available
 	if usageData != nil {
@@ -280,5 +298,13 @@ func selectProfileWithRotationAndUsage(tool string, profiles []string, currentPr
 		return nil, fmt.Errorf("rotation select: %w", err)
 	}
 
+	// 'caam next' moves off the current profile, so a pin only holds when
+	// it points somewhere else.
+	if globalCfg, err := config.Load(); err == nil {
+		if pinned := globalCfg.GetPin(tool); pinned != currentProfile {
+			result.Pin(pinned)
+		}
+	}
+
 	return result, nil
 }
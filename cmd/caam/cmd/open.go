@@ -8,84 +8,112 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browser"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
 )
 
 var openCmd = &cobra.Command{
 	Use:   "open <tool> [profile]",
-	Short: "Open provider account page in browser",
-	Long: `Opens the account management page for a provider in your browser.
+	Short: "Open a provider's usage dashboard in the browser",
+	Long: `Opens the provider's usage/quota dashboard in your browser, so checking
+what is left is one command. Use --account for the account settings page.
 
-If a profile is specified and has browser configuration, the URL will be
-opened in that browser profile (so you see the correct account's dashboard).
+The page opens in the browser profile configured for the account (see
+'caam profile add --browser'): the given profile, or else the profile
+currently active for the tool. Without browser configuration the system's
+default browser is used.
 
-If no profile is specified, or the profile has no browser config, the URL
-will be opened in your system's default browser.
-
-Providers and their URLs:
-  codex   - OpenAI Platform (https://platform.openai.com/account)
-  claude  - Anthropic Console (https://console.anthropic.com/)
-  gemini  - Google AI Studio (https://aistudio.google.com/)
+Providers and their pages (usage / --account):
+  codex   - OpenAI Platform (https://platform.openai.com/usage, /account)
+  claude  - Anthropic Console (https://console.anthropic.com/settings/usage, /)
+  gemini  - Google AI subscription (https://one.google.com/about/google-ai-plans/)
+            and Google AI Studio (https://aistudio.google.com/)
 
 Examples:
-  caam open codex           # Open OpenAI account in default browser
-  caam open claude work     # Open Anthropic console in work profile's browser
-  caam open gemini personal # Open AI Studio in personal profile's browser`,
+  caam open codex           # OpenAI usage for the active account
+  caam open claude work     # Anthropic usage in work profile's browser
+  caam open gemini --account
+  caam open codex --print   # Just print the URL`,
 	Args: cobra.RangeArgs(1, 2),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		tool := strings.ToLower(args[0])
-
-		// Validate provider using centralized metadata
-		meta, ok := provider.GetProviderMeta(tool)
-		if !ok {
-			return fmt.Errorf("unknown provider: %s (supported: codex, claude, gemini)", tool)
-		}
-
-		// Allow custom URL override
-		customURL, _ := cmd.Flags().GetString("url")
-		url := meta.AccountURL
-		if customURL != "" {
-			url = customURL
-		}
-
-		// Determine launcher
-		var launcher browser.Launcher
-
-		if len(args) > 1 {
-			// Profile specified - try to use its browser config
-			profileName := args[1]
-			prof, err := profileStore.Load(tool, profileName)
-			if err != nil {
-				return fmt.Errorf("load profile: %w", err)
-			}
-
-			if prof.HasBrowserConfig() {
-				launcher = browser.NewLauncher(&browser.Config{
-					Command:    prof.BrowserCommand,
-					ProfileDir: prof.BrowserProfileDir,
-				})
-				fmt.Printf("Opening %s in browser profile: %s\n", meta.Description, prof.BrowserDisplayName())
-			} else {
-				launcher = &browser.DefaultLauncher{}
-				fmt.Printf("Opening %s in default browser (profile has no browser config)\n", meta.Description)
-			}
-		} else {
-			// No profile - use default browser
-			launcher = &browser.DefaultLauncher{}
-			fmt.Printf("Opening %s in default browser\n", meta.Description)
-		}
-
-		fmt.Printf("  URL: %s\n", url)
-
-		if err := launcher.Open(url); err != nil {
-			return fmt.Errorf("open browser: %w", err)
-		}
-
-		return nil
-	},
+	RunE: runOpen,
 }
 
 func init() {
 	rootCmd.AddCommand(openCmd)
 	openCmd.Flags().String("url", "", "custom URL to open (overrides default)")
+	openCmd.Flags().Bool("account", false, "open the account settings page instead of usage")
+	openCmd.Flags().Bool("print", false, "print the URL instead of opening it")
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	out := cmd.OutOrStdout()
+
+	// Validate provider using centralized metadata
+	meta, ok := provider.GetProviderMeta(tool)
+	if !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s (supported: %s)", tool, supportedTools()))
+	}
+
+	accountPage, _ := cmd.Flags().GetBool("account")
+	url, description := meta.UsageURL, meta.UsageDescription
+	if accountPage || url == "" {
+		url, description = meta.AccountURL, meta.Description
+	}
+	if customURL, _ := cmd.Flags().GetString("url"); customURL != "" {
+		url = customURL
+	}
+
+	if printOnly, _ := cmd.Flags().GetBool("print"); printOnly {
+		fmt.Fprintln(out, url)
+		return nil
+	}
+
+	// Pick the account whose browser profile to use.
+	profileName := ""
+	if len(args) > 1 {
+		profileName = args[1]
+	} else if getFileSet, ok := tools[tool]; ok {
+		profileName, _ = vault.ActiveProfile(getFileSet())
+	}
+
+	var launcher browser.Launcher = &browser.DefaultLauncher{}
+	switch prof, err := loadOpenProfile(tool, profileName, len(args) > 1); {
+	case err != nil:
+		return err
+	case prof != nil && prof.HasBrowserConfig():
+		launcher = browser.NewLauncher(&browser.Config{
+			Command:    prof.BrowserCommand,
+			ProfileDir: prof.BrowserProfileDir,
+		})
+		fmt.Fprintf(out, "Opening %s for %s in browser profile: %s\n", description, profileName, prof.BrowserDisplayName())
+	case profileName != "":
+		fmt.Fprintf(out, "Opening %s in default browser (%s has no browser config)\n", description, profileName)
+	default:
+		fmt.Fprintf(out, "Opening %s in default browser\n", description)
+	}
+
+	fmt.Fprintf(out, "  URL: %s\n", url)
+
+	if err := launcher.Open(url); err != nil {
+		return fmt.Errorf("open browser: %w", err)
+	}
+
+	return nil
+}
+
+// loadOpenProfile loads the isolated profile holding name's browser config.
+// A missing profile is only an error when the user named it.
+func loadOpenProfile(tool, name string, explicit bool) (*profile.Profile, error) {
+	if name == "" {
+		return nil, nil
+	}
+	prof, err := profileStore.Load(tool, name)
+	if err != nil {
+		if explicit {
+			return nil, fmt.Errorf("load profile: %w", err)
+		}
+		return nil, nil
+	}
+	return prof, nil
 }
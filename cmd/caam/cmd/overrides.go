@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var overridesCmd = &cobra.Command{
+	Use:   "overrides",
+	Short: "Manage per-profile config file overrides",
+	Long: `Manages settings a profile applies to its tool's config file (Codex's
+config.toml) when activated, such as the model or approval mode.
+
+On activation the local config file is kept (runtime.config_policy: preserve,
+the default) and the profile's overrides are merged into it, top-level keys
+only. With runtime.config_policy set to profile, the whole config file is
+backed up with each profile and restored before the overrides are merged.
+
+Examples:
+  caam overrides show codex work
+  caam overrides set codex work model=o3 approval_policy=never
+  caam overrides unset codex work model`,
+}
+
+var overridesShowCmd = &cobra.Command{
+	Use:   "show <tool> <profile>",
+	Short: "Show a profile's config overrides",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runOverridesShow,
+}
+
+var overridesSetCmd = &cobra.Command{
+	Use:   "set <tool> <profile> <key=value>...",
+	Short: "Set config overrides for a profile",
+	Long: `Sets top-level config keys applied when the profile is activated.
+
+Values are TOML: booleans, numbers, and quoted strings, arrays or inline
+tables are kept as given; anything else is stored as a string.
+
+Examples:
+  caam overrides set codex work model=o3
+  caam overrides set codex work approval_policy=on-request model_reasoning_effort=high`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: runOverridesSet,
+}
+
+var overridesUnsetCmd = &cobra.Command{
+	Use:   "unset <tool> <profile> <key>...",
+	Short: "Remove config overrides from a profile",
+	Args:  cobra.MinimumNArgs(3),
+	RunE:  runOverridesUnset,
+}
+
+func init() {
+	rootCmd.AddCommand(overridesCmd)
+	overridesCmd.AddCommand(overridesShowCmd)
+	overridesCmd.AddCommand(overridesSetCmd)
+	overridesCmd.AddCommand(overridesUnsetCmd)
+}
+
+// overridesTarget validates tool and profile and returns the name of the
+// tool's config file the overrides apply to.
+func overridesTarget(tool, profileName string) (string, error) {
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return "", withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+	fileSet := getFileSet()
+	if len(fileSet.ConfigFiles) == 0 {
+		return "", withExitCode(ExitUsage, fmt.Errorf("%s has no config file overrides", tool))
+	}
+	if !vaultHasProfile(tool, profileName) {
+		return "", withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", tool, profileName))
+	}
+	return filepath.Base(fileSet.ConfigFiles[0].Path), nil
+}
+
+func runOverridesShow(cmd *cobra.Command, args []string) error {
+	tool, profileName := strings.ToLower(args[0]), args[1]
+	configFile, err := overridesTarget(tool, profileName)
+	if err != nil {
+		return err
+	}
+	overrides, err := vault.ConfigOverrides(tool, profileName, configFile)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if len(overrides) == 0 {
+		fmt.Fprintf(out, "No %s overrides for %s/%s\n", configFile, tool, profileName)
+		return nil
+	}
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(out, "%s = %s\n", key, overrides[key])
+	}
+	return nil
+}
+
+func runOverridesSet(cmd *cobra.Command, args []string) error {
+	tool, profileName := strings.ToLower(args[0]), args[1]
+	configFile, err := overridesTarget(tool, profileName)
+	if err != nil {
+		return err
+	}
+	overrides, err := vault.ConfigOverrides(tool, profileName, configFile)
+	if err != nil {
+		return err
+	}
+	if overrides == nil {
+		overrides = make(map[string]string)
+	}
+
+	for _, arg := range args[2:] {
+		key, value, ok := strings.Cut(arg, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return withExitCode(ExitUsage, fmt.Errorf("invalid override %q (want key=value)", arg))
+		}
+		overrides[key] = tomlValue(strings.TrimSpace(value))
+	}
+	if err := vault.SetConfigOverrides(tool, profileName, configFile, overrides); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Updated %s overrides for %s/%s (applied on next activate)\n", configFile, tool, profileName)
+	return nil
+}
+
+func runOverridesUnset(cmd *cobra.Command, args []string) error {
+	tool, profileName := strings.ToLower(args[0]), args[1]
+	configFile, err := overridesTarget(tool, profileName)
+	if err != nil {
+		return err
+	}
+	overrides, err := vault.ConfigOverrides(tool, profileName, configFile)
+	if err != nil {
+		return err
+	}
+	for _, key := range args[2:] {
+		delete(overrides, key)
+	}
+	if err := vault.SetConfigOverrides(tool, profileName, configFile, overrides); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Updated %s overrides for %s/%s\n", configFile, tool, profileName)
+	return nil
+}
+
+// tomlRawValue matches values already written as TOML: quoted strings,
+// arrays and inline tables.
+var tomlRawValue = regexp.MustCompile(`^(".*"|'.*'|\[.*\]|\{.*\})$`)
+
+// tomlValue returns value as a TOML value, quoting it unless it is already a
+// boolean, number, quoted string, array or inline table.
+func tomlValue(value string) string {
+	if value == "true" || value == "false" || tomlRawValue.MatchString(value) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return strconv.Quote(value)
+}
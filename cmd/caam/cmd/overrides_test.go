@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTomlValue(t *testing.T) {
+	assert.Equal(t, `"o3"`, tomlValue("o3"))
+	assert.Equal(t, `"on-request"`, tomlValue("on-request"))
+	assert.Equal(t, "true", tomlValue("true"))
+	assert.Equal(t, "0.5", tomlValue("0.5"))
+	assert.Equal(t, `'raw'`, tomlValue(`'raw'`))
+	assert.Equal(t, `["a", "b"]`, tomlValue(`["a", "b"]`))
+}
+
+func TestOverrides_SetShowUnset(t *testing.T) {
+	setupAccountsTest(t)
+	codexHome := filepath.Join(t.TempDir(), "codex_home")
+	t.Setenv("CODEX_HOME", codexHome)
+	require.NoError(t, os.MkdirAll(codexHome, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(codexHome, "auth.json"), []byte(`{"tokens": {}}`), 0600))
+	require.NoError(t, vault.Backup(tools["codex"](), "work"))
+
+	var buf bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&buf)
+	require.NoError(t, runOverridesSet(cmd, []string{"codex", "work", "model=o3", "approval_policy=never"}))
+
+	buf.Reset()
+	require.NoError(t, runOverridesShow(cmd, []string{"codex", "work"}))
+	assert.Equal(t, "approval_policy = \"never\"\nmodel = \"o3\"\n", buf.String())
+
+	require.NoError(t, runOverridesUnset(cmd, []string{"codex", "work", "model"}))
+	overrides, err := vault.ConfigOverrides("codex", "work", "config.toml")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"approval_policy": `"never"`}, overrides)
+
+	require.NoError(t, vault.Restore(tools["codex"](), "work"))
+	data, err := os.ReadFile(filepath.Join(codexHome, "config.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, "approval_policy = \"never\"\n", string(data))
+}
+
+func TestOverrides_Errors(t *testing.T) {
+	setupAccountsTest(t)
+	cmd := &cobra.Command{}
+
+	assert.Equal(t, ExitUsage, ExitCode(runOverridesShow(cmd, []string{"claude", "work"})))
+	assert.Equal(t, ExitAuthMissing, ExitCode(runOverridesShow(cmd, []string{"codex", "missing"})))
+}
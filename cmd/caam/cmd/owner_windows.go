@@ -0,0 +1,9 @@
+//go:build windows
+
+package cmd
+
+// pathOwner returns the UID that owns path. Windows has no UIDs, so
+// ownership is never reported.
+func pathOwner(path string) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/pacing"
+)
+
+// paceOff is the --pace value that skips pacing for one run.
+const paceOff = "off"
+
+// paceSleep waits d or until ctx is done; tests replace it.
+var paceSleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// paceExec holds a 'caam exec' on tool/name back until the pacing config
+// allows another session on it, then records the session's start. policy
+// overrides pacing.when_limited; with rotate, the run may move to one of
+// candidates that is free sooner. It returns the profile to run.
+//
+// The check and the recorded start are back to back rather than atomic, so
+// jobs started in the same instant can overshoot a limit by one.
+func paceExec(ctx context.Context, w io.Writer, policy, tool, name string, candidates []string) (string, error) {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return name, nil
+	}
+	limits := pacing.Limits{
+		MaxPerHour: spmCfg.Pacing.MaxSessionsPerHour,
+		MinGap:     spmCfg.Pacing.MinGap.Duration(),
+	}
+	if policy == "" {
+		policy = spmCfg.Pacing.WhenLimited
+	}
+	if policy == "" {
+		policy = config.PaceWait
+	}
+	if policy == paceOff || !limits.Enabled() {
+		return name, nil
+	}
+
+	db, err := getDB()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: pacing skipped: %v\n", err)
+		return name, nil
+	}
+
+	var deadline time.Time
+	if maxWait := spmCfg.Pacing.MaxWait.Duration(); maxWait > 0 {
+		deadline = time.Now().Add(maxWait)
+	}
+	for {
+		now := time.Now()
+		wait, starts, err := paceWait(db, limits, tool, name, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: pacing skipped: %v\n", err)
+			return name, nil
+		}
+		if wait == 0 {
+			recordPacedStart(db, tool, name, now)
+			return name, nil
+		}
+
+		target, targetWait, targetStarts := name, wait, starts
+		if policy == config.PaceRotate {
+			for _, cand := range candidates {
+				if cand == name {
+					continue
+				}
+				if cd, err := db.ActiveCooldown(tool, cand, now); err != nil || cd != nil {
+					continue
+				}
+				candWait, candStarts, err := paceWait(db, limits, tool, cand, now)
+				if err != nil {
+					continue
+				}
+				if candWait == 0 {
+					fmt.Fprintf(w, "caam: %s/%s is paced (%s); using %s/%s\n",
+						tool, name, paceReason(limits, starts, now), tool, cand)
+					recordPacedStart(db, tool, cand, now)
+					return cand, nil
+				}
+				if candWait < targetWait {
+					target, targetWait, targetStarts = cand, candWait, candStarts
+				}
+			}
+		}
+
+		reason := paceReason(limits, targetStarts, now)
+		if policy == config.PaceFail {
+			return "", withExitCode(ExitAllInCooldown, fmt.Errorf("%s/%s is paced (%s); next session allowed in %s",
+				tool, target, reason, formatDurationShort(targetWait)))
+		}
+		if !deadline.IsZero() && now.Add(targetWait).After(deadline) {
+			return "", withExitCode(ExitAllInCooldown, fmt.Errorf("%s/%s is paced (%s); the next slot is %s away, past pacing.max_wait",
+				tool, target, reason, formatDurationShort(targetWait)))
+		}
+		fmt.Fprintf(w, "caam: %s/%s is paced (%s); waiting %s\n", tool, target, reason, formatDurationShort(targetWait))
+		if err := paceSleep(ctx, targetWait); err != nil {
+			return "", err
+		}
+	}
+}
+
+// paceWait returns how long tool/name must wait under limits, and the
+// session starts that decided it.
+func paceWait(db *caamdb.DB, limits pacing.Limits, tool, name string, now time.Time) (time.Duration, []time.Time, error) {
+	starts, err := db.EventTimes(caamdb.EventExec, tool, name, limits.Since(now))
+	if err != nil {
+		return 0, nil, err
+	}
+	return limits.Wait(starts, now), starts, nil
+}
+
+// recordPacedStart counts a session against tool/name. Failing to record
+// never fails the run.
+func recordPacedStart(db *caamdb.DB, tool, name string, now time.Time) {
+	if err := db.LogEvent(caamdb.Event{Type: caamdb.EventExec, Provider: tool, ProfileName: name, Timestamp: now}); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: paced session not recorded: %v\n", err)
+	}
+}
+
+// paceReason says which limit holds a profile back.
+func paceReason(limits pacing.Limits, starts []time.Time, now time.Time) string {
+	if n := pacing.InWindow(starts, now); limits.MaxPerHour > 0 && n >= limits.MaxPerHour {
+		return fmt.Sprintf("%d sessions in the last hour, max %d", n, limits.MaxPerHour)
+	}
+	return fmt.Sprintf("less than %s since the last session", limits.MinGap)
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+// setupPacingTest points CAAM_HOME at a temp dir with the given config.yaml
+// and stubs out paceSleep, returning the waits it was asked for.
+func setupPacingTest(t *testing.T, configYAML string) (*caamdb.DB, *[]time.Duration) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("CAAM_HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.yaml"), []byte(configYAML), 0600))
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+
+	var waits []time.Duration
+	original := paceSleep
+	paceSleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return context.Canceled
+	}
+	t.Cleanup(func() { paceSleep = original })
+
+	db, err := getDB()
+	require.NoError(t, err)
+	return db, &waits
+}
+
+func logExecStarts(t *testing.T, db *caamdb.DB, name string, ago ...time.Duration) {
+	t.Helper()
+	for _, d := range ago {
+		require.NoError(t, db.LogEvent(caamdb.Event{
+			Type: caamdb.EventExec, Provider: "claude", ProfileName: name, Timestamp: time.Now().Add(-d),
+		}))
+	}
+}
+
+func countExecStarts(t *testing.T, db *caamdb.DB, name string) int {
+	t.Helper()
+	starts, err := db.EventTimes(caamdb.EventExec, "claude", name, time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	return len(starts)
+}
+
+const pacingConfig = `
+version: 1
+pacing:
+  max_sessions_per_hour: 2
+  min_gap: 1m
+`
+
+func TestPaceExec_OffWithoutLimits(t *testing.T) {
+	db, _ := setupPacingTest(t, "version: 1\n")
+	logExecStarts(t, db, "work", time.Second, 2*time.Second)
+
+	name, err := paceExec(context.Background(), io.Discard, "", "claude", "work", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "work", name)
+	assert.Equal(t, 2, countExecStarts(t, db, "work"), "unpaced runs aren't recorded")
+}
+
+func TestPaceExec_RecordsStartWhenFree(t *testing.T) {
+	db, waits := setupPacingTest(t, pacingConfig)
+	logExecStarts(t, db, "work", 30*time.Minute)
+
+	name, err := paceExec(context.Background(), io.Discard, "", "claude", "work", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "work", name)
+	assert.Empty(t, *waits)
+	assert.Equal(t, 2, countExecStarts(t, db, "work"))
+}
+
+func TestPaceExec_WaitsForSlot(t *testing.T) {
+	db, waits := setupPacingTest(t, pacingConfig)
+	logExecStarts(t, db, "work", 50*time.Minute, 20*time.Minute)
+
+	var out strings.Builder
+	_, err := paceExec(context.Background(), &out, "", "claude", "work", nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, *waits, 1)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), (*waits)[0].Seconds(), 2)
+	assert.Contains(t, out.String(), "2 sessions in the last hour, max 2")
+	assert.Equal(t, 2, countExecStarts(t, db, "work"), "a waiting run isn't counted yet")
+}
+
+func TestPaceExec_RotatesToFreeProfile(t *testing.T) {
+	db, waits := setupPacingTest(t, pacingConfig)
+	logExecStarts(t, db, "work", 10*time.Second)
+	logExecStarts(t, db, "busy", 20*time.Second)
+	_, err := db.SetCooldown("claude", "cooling", time.Now(), time.Hour, "")
+	require.NoError(t, err)
+
+	var out strings.Builder
+	name, err := paceExec(context.Background(), &out, "rotate", "claude", "work", []string{"busy", "cooling", "spare", "work"})
+	require.NoError(t, err)
+	assert.Equal(t, "spare", name)
+	assert.Empty(t, *waits)
+	assert.Contains(t, out.String(), "using claude/spare")
+	assert.Equal(t, 1, countExecStarts(t, db, "spare"))
+}
+
+func TestPaceExec_RotateWaitsForSoonestProfile(t *testing.T) {
+	db, waits := setupPacingTest(t, pacingConfig)
+	logExecStarts(t, db, "work", 10*time.Second)
+	logExecStarts(t, db, "home", 50*time.Second)
+
+	var out strings.Builder
+	_, err := paceExec(context.Background(), &out, "rotate", "claude", "work", []string{"home", "work"})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, *waits, 1)
+	assert.Less(t, (*waits)[0], 15*time.Second)
+	assert.Contains(t, out.String(), "claude/home is paced")
+}
+
+func TestPaceExec_FailPolicy(t *testing.T) {
+	db, _ := setupPacingTest(t, pacingConfig)
+	logExecStarts(t, db, "work", 10*time.Second)
+
+	_, err := paceExec(context.Background(), io.Discard, "fail", "claude", "work", nil)
+	require.Error(t, err)
+	assert.Equal(t, ExitAllInCooldown, ExitCode(err))
+	assert.Contains(t, err.Error(), "less than 1m0s since the last session")
+}
+
+func TestPaceExec_GivesUpPastMaxWait(t *testing.T) {
+	db, waits := setupPacingTest(t, pacingConfig+"  max_wait: 5m\n")
+	logExecStarts(t, db, "work", 40*time.Minute, 45*time.Minute)
+
+	_, err := paceExec(context.Background(), io.Discard, "", "claude", "work", nil)
+	require.Error(t, err)
+	assert.Equal(t, ExitAllInCooldown, ExitCode(err))
+	assert.Contains(t, err.Error(), "pacing.max_wait")
+	assert.Empty(t, *waits)
+}
+
+func TestPaceExec_OffPolicySkips(t *testing.T) {
+	db, _ := setupPacingTest(t, pacingConfig)
+	logExecStarts(t, db, "work", 10*time.Second)
+
+	name, err := paceExec(context.Background(), io.Discard, paceOff, "claude", "work", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "work", name)
+}
@@ -47,9 +47,7 @@ func runPick(cmd *cobra.Command, args []string) error {
 		tool = strings.ToLower(strings.TrimSpace(args[0]))
 	}
 	if tool == "" {
-		if cfg != nil && cfg.DefaultProvider != "" {
-			tool = strings.ToLower(strings.TrimSpace(cfg.DefaultProvider))
-		}
+		tool = strings.ToLower(strings.TrimSpace(contextDefaultProvider(cfg)))
 	}
 	if tool == "" {
 		inferred, providers, err := inferPickProvider()
@@ -63,32 +61,23 @@ func runPick(cmd *cobra.Command, args []string) error {
 	}
 
 	if _, ok := tools[tool]; !ok {
-		return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	if vault == nil {
 		vault = authfile.NewVault(authfile.DefaultVaultPath())
 	}
 
-	profiles, err := vault.List(tool)
+	// Number them as 'caam ls' does.
+	filtered, err := numberedProfiles(tool)
 	if err != nil {
 		return fmt.Errorf("list profiles: %w", err)
 	}
 
-	var filtered []string
-	for _, p := range profiles {
-		if authfile.IsSystemProfile(p) {
-			continue
-		}
-		filtered = append(filtered, p)
-	}
-
 	if len(filtered) == 0 {
 		return fmt.Errorf("no profiles found for %s", tool)
 	}
 
-	sort.Strings(filtered)
-
 	selection, method, err := pickProfile(cmd, tool, filtered, cfg)
 	if err != nil {
 		if errors.Is(err, errPickCanceled) {
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+var pinCmd = &cobra.Command{
+	Use:   "pin [tool] [profile]",
+	Short: "Make a profile always win automatic selection",
+	Long: `Pins a profile so automatic selection always picks it unless it is in
+cooldown - 'caam robot next', 'caam activate --auto' and 'caam next'
+('caam next' still moves off a pinned profile that is already active).
+Use it for policies like "always prefer the org-owned account".
+
+One profile can be pinned per tool. Without a profile, shows the tool's
+pin; without arguments, lists all pins. The weights the remaining profiles
+are scored with can be tuned under "scoring" in config.json.
+
+Examples:
+  caam pin claude org-account
+  caam pin claude          # Show the pinned claude profile
+  caam pin                 # List all pins
+  caam unpin claude`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runPin,
+}
+
+var unpinCmd = &cobra.Command{
+	Use:   "unpin <tool>",
+	Short: "Remove a tool's pinned profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnpin,
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+	pinCmd.Flags().Bool("json", false, "output in JSON format")
+}
+
+func runPin(cmd *cobra.Command, args []string) error {
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+
+	c, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	out := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		if jsonFlag {
+			pins := c.Pins
+			if pins == nil {
+				pins = map[string]string{}
+			}
+			data, _ := json.MarshalIndent(pins, "", "  ")
+			fmt.Fprintln(out, string(data))
+			return nil
+		}
+		if len(c.Pins) == 0 {
+			fmt.Fprintln(out, "No pinned profiles.")
+			return nil
+		}
+		toolNames := make([]string, 0, len(c.Pins))
+		for tool := range c.Pins {
+			toolNames = append(toolNames, tool)
+		}
+		sort.Strings(toolNames)
+		for _, tool := range toolNames {
+			fmt.Fprintf(out, "  %-8s %s\n", tool, c.Pins[tool])
+		}
+		return nil
+	}
+
+	tool := strings.ToLower(args[0])
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+
+	profile := c.GetPin(tool)
+	if len(args) == 2 {
+		profile = args[1]
+		if vault == nil {
+			vault = authfile.NewVault(authfile.DefaultVaultPath())
+		}
+		if !vaultHasProfile(tool, profile) {
+			return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found", tool, profile))
+		}
+		c.SetPin(tool, profile)
+		if err := c.Save(); err != nil {
+			return fmt.Errorf("save config: %w", err)
+		}
+	}
+
+	if jsonFlag {
+		data, _ := json.MarshalIndent(map[string]string{
+			"tool":    tool,
+			"profile": profile,
+		}, "", "  ")
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	switch {
+	case len(args) == 2:
+		fmt.Fprintf(out, "[OK] Pinned %s/%s; it wins automatic selection unless in cooldown\n", tool, profile)
+	case profile == "":
+		fmt.Fprintf(out, "No %s profile is pinned\n", tool)
+	default:
+		fmt.Fprintf(out, "%s: %s (pinned)\n", tool, profile)
+	}
+	return nil
+}
+
+func runUnpin(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+
+	c, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	profile := c.GetPin(tool)
+	if profile == "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "No %s profile is pinned\n", tool)
+		return nil
+	}
+	c.SetPin(tool, "")
+	if err := c.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "[OK] Unpinned %s/%s\n", tool, profile)
+	return nil
+}
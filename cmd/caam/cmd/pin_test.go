@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+func TestPinAndScoringWeights(t *testing.T) {
+	setupAccountsTest(t)
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+
+	originalHealth := healthStore
+	t.Cleanup(func() { healthStore = originalHealth })
+	healthStore = health.NewStorage(filepath.Join(tmpDir, "health.json"))
+
+	profiles := []string{"alpha", "beta", "gamma"}
+	for _, name := range profiles {
+		dir := vault.ProfilePath("codex", name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"`+name+`"}`), 0600))
+	}
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	c, err := config.Load()
+	require.NoError(t, err)
+	c.Scoring.Unknown = 7
+	c.Scoring.Warning = 7
+	c.Scoring.IdleSmart = 0
+	require.NoError(t, c.Save())
+
+	scored := rankRobotNext(db, "codex", profiles, "smart", false, time.Now())
+	require.Len(t, scored, 3)
+	assert.Equal(t, 7.0, scored[0].score.Score, "configured weights should be used")
+
+	pinFlags := func() (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("json", false, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		return cmd, &out
+	}
+	cmd, _ := pinFlags()
+	assert.Equal(t, ExitAuthMissing, ExitCode(runPin(cmd, []string{"codex", "missing"})))
+	require.NoError(t, runPin(cmd, []string{"codex", "gamma"}))
+
+	scored = rankRobotNext(db, "codex", profiles, "smart", false, time.Now())
+	assert.Equal(t, "gamma", scored[0].name)
+	assert.Equal(t, "pinned", scored[0].reasons[0])
+
+	selection, err := selectProfileWithRotation("codex", profiles, "", config.DefaultSPMConfig(), db)
+	require.NoError(t, err)
+	assert.Equal(t, "gamma", selection.Selected)
+
+	// A pinned profile in cooldown doesn't win.
+	_, err = db.SetCooldown("codex", "gamma", time.Now(), time.Hour, "")
+	require.NoError(t, err)
+	scored = rankRobotNext(db, "codex", profiles, "smart", true, time.Now())
+	assert.NotEqual(t, "gamma", scored[0].name)
+
+	cmd, out := pinFlags()
+	require.NoError(t, runPin(cmd, nil))
+	assert.Contains(t, out.String(), "gamma")
+
+	require.NoError(t, runUnpin(&cobra.Command{}, []string{"codex"}))
+	c, err = config.Load()
+	require.NoError(t, err)
+	assert.Empty(t, c.GetPin("codex"))
+}
@@ -69,6 +69,7 @@ func init() {
 
 func getPool() (*authpool.AuthPool, error) {
 	vault := authfile.NewVault(authfile.DefaultVaultPath())
+	authfile.ApplyVaultSettings(vault)
 	pool := authpool.NewAuthPool(authpool.WithVault(vault))
 
 	// Load persisted state (errors logged but not fatal - state file may not exist)
@@ -135,6 +136,7 @@ func runPoolRefresh(cmd *cobra.Command, args []string) error {
 	}
 
 	vault := authfile.NewVault(authfile.DefaultVaultPath())
+	authfile.ApplyVaultSettings(vault)
 	healthStore := health.NewStorage(health.DefaultHealthPath())
 	pool := authpool.NewAuthPool(authpool.WithVault(vault))
 
@@ -51,6 +51,8 @@ func init() {
 	precheckCmd.Flags().Bool("no-fetch", false, "skip real-time API fetch (use cached/health data)")
 	precheckCmd.Flags().Duration("timeout", 30*time.Second, "timeout for API fetches")
 	precheckCmd.Flags().String("algorithm", "", "override rotation algorithm (smart, round_robin, random)")
+	registerValueCompletion(precheckCmd, "algorithm", rotationAlgorithms...)
+	registerDurationCompletion(precheckCmd, "timeout")
 	rootCmd.AddCommand(precheckCmd)
 }
 
@@ -128,7 +130,7 @@ func runPrecheckCmd(cmd *cobra.Command, args []string) error {
 
 	// Validate provider
 	if _, ok := tools[provider]; !ok {
-		return fmt.Errorf("unknown provider: %s (supported: claude, codex, gemini)", provider)
+		return fmt.Errorf("unknown provider: %s (supported: %s)", provider, supportedTools())
 	}
 
 	// Initialize dependencies
@@ -158,6 +160,7 @@ func runPrecheckCmd(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		spmCfg = config.DefaultSPMConfig()
 	}
+	applyContextStrategy(spmCfg)
 
 	if algoOverride != "" {
 		spmCfg.Stealth.Rotation.Algorithm = algoOverride
@@ -166,9 +169,7 @@ func runPrecheckCmd(cmd *cobra.Command, args []string) error {
 	// Open database
 	var db *caamdb.DB
 	db, err = caamdb.Open()
-	if err != nil {
-		fmt.Fprintf(cmd.OutOrStderr(), "Warning: could not open database: %v\n", err)
-	} else {
+	if err == nil {
 		defer db.Close()
 	}
 
@@ -209,6 +210,7 @@ func runPrecheckCmd(cmd *cobra.Command, args []string) error {
 	}
 
 	selector := rotation.NewSelector(algorithm, healthStoreInst, db)
+	selector.SetExcluded(quarantinedProfiles(provider))
 
 	// Set usage data for smart selection
 	if len(usageMap) > 0 {
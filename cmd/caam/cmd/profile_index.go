@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// numberedProfiles returns the profiles that 'caam ls', 'caam pick' and
+// 'caam activate <tool> <n>' number: the tool's non-system profiles sorted
+// by name, so profile n is the same whatever ls was sorted or filtered by.
+func numberedProfiles(tool string) ([]string, error) {
+	profiles, err := vault.List(tool)
+	if err != nil {
+		return nil, err
+	}
+	var numbered []string
+	for _, p := range profiles {
+		if !authfile.IsSystemProfile(p) {
+			numbered = append(numbered, p)
+		}
+	}
+	sort.Strings(numbered)
+	return numbered, nil
+}
+
+// profileIndexes maps each numbered profile to its 1-based index.
+func profileIndexes(tool string) map[string]int {
+	numbered, _ := numberedProfiles(tool)
+	indexes := make(map[string]int, len(numbered))
+	for i, p := range numbered {
+		indexes[p] = i + 1
+	}
+	return indexes
+}
+
+// parseProfileIndex reports whether input is a profile number.
+func parseProfileIndex(input string) (int, bool) {
+	if input == "" || strings.TrimLeft(input, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(input)
+	return n, err == nil
+}
+
+// profileByIndex returns the n-th numbered profile of tool.
+func profileByIndex(tool string, n int) (string, error) {
+	numbered, err := numberedProfiles(tool)
+	if err != nil {
+		return "", fmt.Errorf("list profiles: %w", err)
+	}
+	if n < 1 || n > len(numbered) {
+		return "", withExitCode(ExitUsage, fmt.Errorf("no %s profile #%d (%d numbered; see 'caam ls %s')", tool, n, len(numbered), tool))
+	}
+	return numbered[n-1], nil
+}
+
+// lsIndexColumn formats a row's number for ls; system profiles have none.
+func lsIndexColumn(indexes map[string]int, profile string) string {
+	if n, ok := indexes[profile]; ok {
+		return strconv.Itoa(n)
+	}
+	return ""
+}
+
+// rootArgs lets 'caam <n>' through to the quick-activate shortcut and
+// otherwise rejects arguments the way cobra does for an unknown command.
+func rootArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	if _, ok := parseProfileIndex(args[0]); ok && len(args) == 1 {
+		return nil
+	}
+	msg := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+	if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+		msg += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+	}
+	return fmt.Errorf("%s", msg)
+}
+
+// runQuickActivate implements 'caam <n>': activate profile n of the default
+// tool (the context's, else default_provider in config.json).
+func runQuickActivate(cmd *cobra.Command, n int) error {
+	cfg, _ := config.Load()
+	tool := strings.ToLower(strings.TrimSpace(contextDefaultProvider(cfg)))
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("'caam %d' needs a default tool; set default_provider or use 'caam activate <tool> %d'", n, n))
+	}
+	profileName, err := profileByIndex(tool, n)
+	if err != nil {
+		return err
+	}
+	activateCmd.SetOut(cmd.OutOrStdout())
+	activateCmd.SetErr(cmd.ErrOrStderr())
+	return runActivate(activateCmd, []string{tool, profileName})
+}
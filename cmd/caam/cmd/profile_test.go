@@ -2,12 +2,18 @@
 package cmd
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/claude"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/gemini"
+	"github.com/spf13/cobra"
 )
 
 // TestProfileCommandStructure tests the profile parent command.
@@ -599,3 +605,214 @@ func TestProfileDirectoryStructure(t *testing.T) {
 		t.Errorf("profile.json should exist: %v", err)
 	}
 }
+
+// setupProfileBridgeTest points the vault, profile store and registry at temp dirs.
+func setupProfileBridgeTest(t *testing.T) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", filepath.Join(tmpDir, "realhome"))
+
+	origVault, origStore, origRegistry := vault, profileStore, registry
+	t.Cleanup(func() {
+		vault, profileStore, registry = origVault, origStore, origRegistry
+	})
+
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	profileStore = profile.NewStore(filepath.Join(tmpDir, "profiles"))
+	registry = provider.NewRegistry()
+	registry.Register(claude.New())
+}
+
+// TestProfilePromoteAndSeed tests moving auth between isolated profiles and the vault.
+func TestProfilePromoteAndSeed(t *testing.T) {
+	setupProfileBridgeTest(t)
+
+	prof, err := profileStore.Create("claude", "work", "oauth")
+	if err != nil {
+		t.Fatalf("Create profile failed: %v", err)
+	}
+	credPath := filepath.Join(prof.HomePath(), ".claude", ".credentials.json")
+	if err := os.MkdirAll(filepath.Dir(credPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(credPath, []byte(`{"claudeAiOauth":{"accessToken":"isolated"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := profilePromoteCmd.RunE(profilePromoteCmd, []string{"claude", "work", "work-vault"}); err != nil {
+		t.Fatalf("promote failed: %v", err)
+	}
+	promoted, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", "work-vault"), ".credentials.json"))
+	if err != nil {
+		t.Fatalf("read promoted credentials: %v", err)
+	}
+	if !strings.Contains(string(promoted), "isolated") {
+		t.Errorf("promoted credentials = %q", promoted)
+	}
+
+	// Promoting over an existing vault profile requires --force.
+	if err := profilePromoteCmd.RunE(profilePromoteCmd, []string{"claude", "work", "work-vault"}); err == nil {
+		t.Error("expected error promoting over existing vault profile without --force")
+	}
+
+	created, err := seedIsolatedProfile(context.Background(), "claude", "parallel", "work-vault", false)
+	if err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+	if !created {
+		t.Error("seed should create a missing isolated profile")
+	}
+	seededProf, err := profileStore.Load("claude", "parallel")
+	if err != nil {
+		t.Fatalf("load seeded profile: %v", err)
+	}
+	seeded, err := os.ReadFile(filepath.Join(seededProf.HomePath(), ".claude", ".credentials.json"))
+	if err != nil {
+		t.Fatalf("read seeded credentials: %v", err)
+	}
+	if !strings.Contains(string(seeded), "isolated") {
+		t.Errorf("seeded credentials = %q", seeded)
+	}
+
+	// Seeding over existing auth requires force.
+	if _, err := seedIsolatedProfile(context.Background(), "claude", "parallel", "work-vault", false); err == nil {
+		t.Error("expected error seeding over existing auth without force")
+	}
+	if _, err := seedIsolatedProfile(context.Background(), "claude", "parallel", "work-vault", true); err != nil {
+		t.Errorf("seed with force failed: %v", err)
+	}
+
+	if _, err := seedIsolatedProfile(context.Background(), "claude", "other", "missing", false); err == nil {
+		t.Error("expected error seeding from missing vault profile")
+	}
+}
+
+// TestProfileMirror tests creating isolated profiles for every vault profile.
+func TestProfileMirror(t *testing.T) {
+	setupProfileBridgeTest(t)
+
+	for _, name := range []string{"alice", "bob", "_original"} {
+		dir := vault.ProfilePath("claude", name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		content := `{"claudeAiOauth":{"accessToken":"` + name + `"}}`
+		if err := os.WriteFile(filepath.Join(dir, ".credentials.json"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := profileMirrorCmd.RunE(profileMirrorCmd, []string{"claude"}); err != nil {
+		t.Fatalf("mirror failed: %v", err)
+	}
+
+	for _, name := range []string{"alice", "bob"} {
+		prof, err := profileStore.Load("claude", name)
+		if err != nil {
+			t.Fatalf("isolated profile %s not created: %v", name, err)
+		}
+		data, err := os.ReadFile(filepath.Join(prof.HomePath(), ".claude", ".credentials.json"))
+		if err != nil {
+			t.Fatalf("read %s credentials: %v", name, err)
+		}
+		if !strings.Contains(string(data), name) {
+			t.Errorf("%s credentials = %q", name, data)
+		}
+	}
+
+	if profileStore.Exists("claude", "_original") {
+		t.Error("system profiles should not be mirrored")
+	}
+
+	// A second run leaves existing isolated auth alone.
+	if err := profileMirrorCmd.RunE(profileMirrorCmd, []string{"claude"}); err != nil {
+		t.Fatalf("second mirror failed: %v", err)
+	}
+}
+
+func TestVertexProfileMetadata(t *testing.T) {
+	newCmd := func(flags map[string]string) *cobra.Command {
+		cmd := &cobra.Command{}
+		for _, name := range []string{"project", "location", "service-account"} {
+			cmd.Flags().String(name, "", "")
+		}
+		for k, v := range flags {
+			if err := cmd.Flags().Set(k, v); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return cmd
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "sa.json")
+	if err := os.WriteFile(keyPath, []byte(`{"type": "service_account"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := vertexProfileMetadata(newCmd(map[string]string{"project": "p1", "service-account": keyPath}), "gemini", "vertex-adc")
+	if err != nil {
+		t.Fatalf("vertexProfileMetadata() error = %v", err)
+	}
+	if meta[gemini.MetaVertexProject] != "p1" || meta[gemini.MetaServiceAccount] != keyPath {
+		t.Errorf("metadata = %v", meta)
+	}
+
+	if _, err := vertexProfileMetadata(newCmd(map[string]string{"project": "p1"}), "gemini", "oauth"); ExitCode(err) != ExitUsage {
+		t.Errorf("--project without vertex-adc: err = %v, want usage error", err)
+	}
+	if _, err := vertexProfileMetadata(newCmd(map[string]string{"service-account": filepath.Join(t.TempDir(), "missing.json")}), "gemini", "vertex-adc"); err == nil {
+		t.Error("missing service account key should fail")
+	}
+}
+
+func TestProfileMCP(t *testing.T) {
+	setupProfileBridgeTest(t)
+
+	prof, err := profileStore.Create("claude", "work", "oauth")
+	if err != nil {
+		t.Fatalf("Create profile failed: %v", err)
+	}
+	mcpPath := filepath.Join(t.TempDir(), "mcp.json")
+	if err := os.WriteFile(mcpPath, []byte(`{"mcpServers": {"notes": {"command": "notes-mcp", "args": ["{{.Profile}}"]}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("clear", false, "")
+	var out strings.Builder
+	cmd.SetOut(&out)
+
+	if err := runProfileMCP(cmd, []string{"claude", "work", mcpPath}); err != nil {
+		t.Fatalf("runProfileMCP(set) error = %v", err)
+	}
+	state, err := os.ReadFile(filepath.Join(prof.HomePath(), ".claude.json"))
+	if err != nil {
+		t.Fatalf("read .claude.json: %v", err)
+	}
+	if !strings.Contains(string(state), `"notes-mcp"`) || !strings.Contains(string(state), `"work"`) {
+		t.Errorf(".claude.json = %s", state)
+	}
+
+	out.Reset()
+	if err := runProfileMCP(cmd, []string{"claude", "work"}); err != nil {
+		t.Fatalf("runProfileMCP(show) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "MCP servers: notes") {
+		t.Errorf("show output = %q", out.String())
+	}
+
+	if err := cmd.Flags().Set("clear", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runProfileMCP(cmd, []string{"claude", "work"}); err != nil {
+		t.Fatalf("runProfileMCP(clear) error = %v", err)
+	}
+	state, _ = os.ReadFile(filepath.Join(prof.HomePath(), ".claude.json"))
+	if strings.Contains(string(state), "notes-mcp") {
+		t.Errorf("cleared .claude.json still lists servers: %s", state)
+	}
+
+	if _, err := mcpServersMetadata("codex", mcpPath); ExitCode(err) != ExitUsage {
+		t.Errorf("codex MCP config: err = %v, want usage error", err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 )
 
 // projectCmd is the parent command for project association management.
@@ -23,6 +25,7 @@ This enables workflows like:
 
 Examples:
   caam project set claude client-a@work.com
+  caam project class work
   caam project show
   caam project list
 `,
@@ -35,6 +38,8 @@ func init() {
 	projectCmd.AddCommand(projectShowCmd)
 	projectCmd.AddCommand(projectRemoveCmd)
 	projectCmd.AddCommand(projectClearCmd)
+	projectCmd.AddCommand(projectClassCmd)
+	projectClassCmd.Flags().Bool("clear", false, "remove the requirement from the current directory")
 }
 
 var projectSetCmd = &cobra.Command{
@@ -46,7 +51,7 @@ var projectSetCmd = &cobra.Command{
 		profileName := args[1]
 
 		if _, ok := tools[tool]; !ok {
-			return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+			return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 		}
 		if projectStore == nil {
 			return fmt.Errorf("project store not initialized")
@@ -76,6 +81,7 @@ type ProjectAssociation struct {
 type ProjectListOutput struct {
 	Projects []ProjectAssociation `json:"projects"`
 	Count    int                  `json:"count"`
+	Classes  map[string]string    `json:"classes,omitempty"`
 }
 
 var projectListCmd = &cobra.Command{
@@ -98,6 +104,7 @@ var projectListCmd = &cobra.Command{
 			output := ProjectListOutput{
 				Projects: make([]ProjectAssociation, 0, len(data.Associations)),
 				Count:    len(data.Associations),
+				Classes:  data.Classes,
 			}
 			for projectPath, assoc := range data.Associations {
 				output.Projects = append(output.Projects, ProjectAssociation{
@@ -113,6 +120,19 @@ var projectListCmd = &cobra.Command{
 			return nil
 		}
 
+		if len(data.Classes) > 0 {
+			paths := make([]string, 0, len(data.Classes))
+			for p := range data.Classes {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			fmt.Println("Class requirements:")
+			for _, p := range paths {
+				fmt.Printf("  %s: %s\n", p, data.Classes[p])
+			}
+			fmt.Println()
+		}
+
 		if len(data.Associations) == 0 {
 			fmt.Println("No project associations set.")
 			return nil
@@ -169,13 +189,24 @@ var projectShowCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
+		class, classSource, err := projectStore.RequiredClass(cwd)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Project: %s\n", cwd)
+		if class != "" {
+			if classSource != cwd {
+				fmt.Printf("Required class: %s  (from %s)\n", class, classSource)
+			} else {
+				fmt.Printf("Required class: %s\n", class)
+			}
+		}
 		if len(resolved.Profiles) == 0 {
-			fmt.Printf("Project: %s\n", cwd)
 			fmt.Println("No associations.")
 			return nil
 		}
 
-		fmt.Printf("Project: %s\n", cwd)
 		fmt.Println("Associations:")
 
 		providers := make([]string, 0, len(resolved.Profiles))
@@ -205,7 +236,7 @@ var projectRemoveCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
 		if _, ok := tools[tool]; !ok {
-			return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+			return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 		}
 		if projectStore == nil {
 			return fmt.Errorf("project store not initialized")
@@ -247,3 +278,61 @@ var projectClearCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var projectClassCmd = &cobra.Command{
+	Use:   "class [work|personal]",
+	Short: "Require a profile class in the current directory",
+	Long: `Require profiles activated in the current directory (and below) to carry
+a policy class, as set with 'caam class'. Activating a profile of another
+class here needs confirmation or --yes, and rotation skips it.
+
+Without an argument, shows the requirement in effect.
+
+Examples:
+  caam project class work
+  caam project class
+  caam project class --clear`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		clearFlag, _ := cmd.Flags().GetBool("clear")
+		if projectStore == nil {
+			return fmt.Errorf("project store not initialized")
+		}
+		if clearFlag && len(args) == 1 {
+			return withExitCode(ExitUsage, fmt.Errorf("use either a class or --clear, not both"))
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("get current directory: %w", err)
+		}
+
+		switch {
+		case len(args) == 1:
+			class := strings.ToLower(strings.TrimSpace(args[0]))
+			if err := config.ValidateProfileClass(class); err != nil {
+				return withExitCode(ExitUsage, err)
+			}
+			if err := projectStore.SetClass(cwd, class); err != nil {
+				return err
+			}
+			fmt.Printf("%s now requires %s profiles\n", cwd, class)
+		case clearFlag:
+			if err := projectStore.RemoveClass(cwd); err != nil {
+				return err
+			}
+			fmt.Printf("Cleared the class requirement from %s\n", cwd)
+		default:
+			class, source, err := projectStore.RequiredClass(cwd)
+			if err != nil {
+				return err
+			}
+			if class == "" {
+				fmt.Printf("%s has no class requirement\n", cwd)
+				return nil
+			}
+			fmt.Printf("%s requires %s profiles  (from %s)\n", cwd, class, source)
+		}
+		return nil
+	},
+}
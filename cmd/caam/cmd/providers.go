@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/manifest"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/plugin"
+)
+
+// providersOutput is the JSON output structure for providers command.
+type providersOutput struct {
+	Providers []provider.Capabilities `json:"providers"`
+	Count     int                     `json:"count"`
+}
+
+var providersCmd = &cobra.Command{
+	Use:   "providers [tool]",
+	Short: "List providers and their capabilities",
+	Long: `Lists every registered provider along with the features it supports:
+device-code login, token refresh, identity extraction, expiry parsing, and the
+auth files it manages.
+
+Scripts can use --json to adapt behavior per provider instead of hardcoding
+tool names.
+
+Tools whose auth is just files can be declared in YAML manifests in
+~/.config/caam/providers.d/<id>.yaml, naming the auth file paths, which are
+required, and the login command. Manifests are YAML, like caam's own config;
+*.toml files there are reported, not loaded:
+
+  display_name: Aider
+  login: [aider, --login]
+  files:
+    - path: ~/.aider/oauth-keys.env
+      required: true
+
+Executables named caam-provider-<id> on PATH are registered as provider
+plugins (see internal/provider/plugin for the JSON-over-stdio protocol).
+Built-in providers take precedence over manifests, and manifests over
+plugins. Manifests that fail to load are reported here.
+
+Examples:
+  caam providers
+  caam providers codex
+  caam providers --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProviders,
+}
+
+func init() {
+	rootCmd.AddCommand(providersCmd)
+	providersCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+func runProviders(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	for _, err := range manifestErrors {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: provider manifest skipped: %v\n", err)
+	}
+
+	caps := registry.Capabilities()
+	if len(args) > 0 {
+		tool := strings.ToLower(args[0])
+		prov, ok := registry.Get(tool)
+		if !ok {
+			return fmt.Errorf("unknown provider: %s", tool)
+		}
+		caps = []provider.Capabilities{provider.CapabilitiesOf(prov)}
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(providersOutput{Providers: caps, Count: len(caps)})
+	}
+
+	out := cmd.OutOrStdout()
+	for i, c := range caps {
+		if i > 0 {
+			fmt.Fprintln(out)
+		}
+		modes := make([]string, 0, len(c.AuthModes))
+		for _, m := range c.AuthModes {
+			modes = append(modes, string(m))
+		}
+		fmt.Fprintf(out, "%s (%s)\n", c.ID, c.DisplayName)
+		if prov, ok := registry.Get(c.ID); ok {
+			switch p := prov.(type) {
+			case *plugin.Provider:
+				fmt.Fprintf(out, "  Plugin:              %s\n", p.Path())
+			case *manifest.Provider:
+				fmt.Fprintf(out, "  Manifest:            %s\n", p.Manifest().Source)
+			}
+		}
+		fmt.Fprintf(out, "  Binary:              %s\n", c.DefaultBin)
+		fmt.Fprintf(out, "  Auth modes:          %s\n", strings.Join(modes, ", "))
+		fmt.Fprintf(out, "  Device code:         %s\n", yesNo(c.DeviceCode))
+		fmt.Fprintf(out, "  Token refresh:       %s\n", yesNo(c.TokenRefresh))
+		fmt.Fprintf(out, "  Identity extraction: %s\n", yesNo(c.IdentityExtraction))
+		fmt.Fprintf(out, "  Expiry parsing:      %s\n", yesNo(c.ExpiryParsing))
+		fmt.Fprintln(out, "  Auth files:")
+		for _, f := range c.AuthFiles {
+			required := ""
+			if f.Required {
+				required = " (required)"
+			}
+			fmt.Fprintf(out, "    %s%s\n", f.Path, required)
+		}
+	}
+	return nil
+}
+
+// manifestErrors holds the provider manifests registerPlugins skipped.
+var manifestErrors []error
+
+// registerPlugins adds the provider manifests in config.ProvidersDir and
+// the provider plugins on PATH to the registry, and makes their auth files
+// available to backup, activate and scheduled reverts.
+func registerPlugins() {
+	manifests, errs := manifest.Register(registry, config.ProvidersDir())
+	manifestErrors = errs
+	for _, p := range manifests {
+		addProviderTool(p)
+	}
+	for _, p := range plugin.Register(registry) {
+		addProviderTool(p)
+	}
+}
+
+// builtinTools are the tools caam ships support for, in the order status
+// and robot output list them.
+var builtinTools = []string{"codex", "claude", "gemini"}
+
+// knownTools returns the built-in tools followed by those added by
+// manifests and plugins, sorted.
+func knownTools() []string {
+	var names, extra []string
+	for _, name := range builtinTools {
+		if _, ok := tools[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for name := range tools {
+		if !slices.Contains(builtinTools, name) {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(names, extra...)
+}
+
+// supportedTools lists knownTools for "unknown tool" errors.
+func supportedTools() string {
+	return strings.Join(knownTools(), ", ")
+}
+
+// addProviderTool adds a tools entry backed by p's auth files.
+func addProviderTool(p provider.Provider) {
+	fileSet := func() authfile.AuthFileSet {
+		set := authfile.AuthFileSet{Tool: p.ID()}
+		for _, spec := range p.AuthFiles() {
+			set.Files = append(set.Files, authfile.AuthFileSpec{
+				Tool:        p.ID(),
+				Path:        spec.Path,
+				Description: spec.Description,
+				Required:    spec.Required,
+			})
+		}
+		return set
+	}
+	tools[p.ID()] = fileSet
+	authfile.RegisterAuthFileSet(p.ID(), fileSet)
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/claude"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/codex"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/gemini"
+)
+
+func TestProvidersJSON(t *testing.T) {
+	origRegistry := registry
+	t.Cleanup(func() { registry = origRegistry })
+
+	registry = provider.NewRegistry()
+	registry.Register(codex.New())
+	registry.Register(claude.New())
+	registry.Register(gemini.New())
+
+	var buf bytes.Buffer
+	providersCmd.SetOut(&buf)
+	t.Cleanup(func() { providersCmd.SetOut(nil) })
+	if err := providersCmd.Flags().Set("json", "true"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = providersCmd.Flags().Set("json", "false") })
+
+	if err := runProviders(providersCmd, nil); err != nil {
+		t.Fatalf("runProviders() error = %v", err)
+	}
+
+	var out providersOutput
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if out.Count != 3 {
+		t.Fatalf("Count = %d, want 3", out.Count)
+	}
+	if out.Providers[0].ID != "claude" || out.Providers[1].ID != "codex" || out.Providers[2].ID != "gemini" {
+		t.Errorf("providers not sorted by ID: %+v", out.Providers)
+	}
+	if !out.Providers[1].DeviceCode {
+		t.Error("codex should report device code support")
+	}
+	if len(out.Providers[0].AuthFiles) == 0 {
+		t.Error("claude should list auth files")
+	}
+}
+
+func TestProvidersUnknownTool(t *testing.T) {
+	origRegistry := registry
+	t.Cleanup(func() { registry = origRegistry })
+	registry = provider.NewRegistry()
+
+	if err := runProviders(providersCmd, []string{"nope"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
+
+func TestRegisterPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test script needs /bin/sh")
+	}
+	origRegistry := registry
+	t.Cleanup(func() {
+		registry = origRegistry
+		delete(tools, "foo")
+	})
+
+	dir := t.TempDir()
+	script := `#!/bin/sh
+home=$(sed -n 's/.*"home":"\([^"]*\)".*/\1/p')
+case "$1" in
+describe) echo '{"protocol": 1, "display_name": "Foo CLI"}' ;;
+auth_files) printf '{"files": [{"path": "%s/.foo/token", "required": true}]}\n' "$home" ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "caam-provider-foo"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "caam-provider-codex"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	home := t.TempDir()
+	// Keep the rest of PATH: the plugin script needs sed.
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", home)
+
+	registry = provider.NewRegistry()
+	registry.Register(codex.New())
+	registerPlugins()
+
+	if prov, _ := registry.Get("codex"); prov.DisplayName() == "Foo CLI" {
+		t.Error("plugin replaced the built-in codex provider")
+	}
+	getFileSet, ok := tools["foo"]
+	if !ok {
+		t.Fatal("plugin not added to tools")
+	}
+	fileSet := getFileSet()
+	if fileSet.Tool != "foo" || len(fileSet.Files) != 1 || fileSet.Files[0].Path != filepath.Join(home, ".foo", "token") {
+		t.Errorf("plugin file set = %+v", fileSet)
+	}
+
+	var buf bytes.Buffer
+	providersCmd.SetOut(&buf)
+	t.Cleanup(func() { providersCmd.SetOut(nil) })
+	if err := runProviders(providersCmd, []string{"foo"}); err != nil {
+		t.Fatalf("runProviders() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "foo (Foo CLI)") || !strings.Contains(buf.String(), "Plugin:") {
+		t.Errorf("providers output = %q", buf.String())
+	}
+}
+
+func TestRegisterManifests(t *testing.T) {
+	origRegistry := registry
+	origVault := vault
+	t.Cleanup(func() {
+		registry = origRegistry
+		vault = origVault
+		manifestErrors = nil
+		delete(tools, "aider")
+	})
+
+	configHome := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", t.TempDir())
+	dir := filepath.Join(configHome, "caam", "providers.d")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	manifests := map[string]string{
+		"aider.yaml":  "display_name: Aider\nlogin: [aider, --login]\nfiles:\n  - path: ~/.aider/oauth-keys.env\n    required: true\n",
+		"broken.yaml": "files: []\n",
+	}
+	for name, content := range manifests {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry = provider.NewRegistry()
+	registry.Register(codex.New())
+	registerPlugins()
+
+	getFileSet, ok := tools["aider"]
+	if !ok {
+		t.Fatal("manifest not added to tools")
+	}
+	if names := knownTools(); names[len(names)-1] != "aider" {
+		t.Errorf("knownTools() = %v, want aider after the built-ins", names)
+	}
+	if got := supportedTools(); got != "codex, claude, gemini, aider" {
+		t.Errorf("supportedTools() = %q, want the manifest tool listed", got)
+	}
+	if len(manifestErrors) != 1 || !strings.Contains(manifestErrors[0].Error(), "broken.yaml") {
+		t.Errorf("manifestErrors = %v, want the broken manifest", manifestErrors)
+	}
+
+	// The vault handles the manifest's files like any other tool's.
+	fileSet := getFileSet()
+	token := filepath.Join(home, ".aider", "oauth-keys.env")
+	if len(fileSet.Files) != 1 || fileSet.Files[0].Path != token {
+		t.Fatalf("manifest file set = %+v", fileSet)
+	}
+	if err := os.MkdirAll(filepath.Dir(token), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(token, []byte("KEY=work"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	vault = authfile.NewVault(filepath.Join(t.TempDir(), "vault"))
+	if err := vault.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if active, err := vault.ActiveProfile(fileSet); err != nil || active != "work" {
+		t.Errorf("ActiveProfile() = %q, %v; want work", active, err)
+	}
+
+	var out, errOut bytes.Buffer
+	providersCmd.SetOut(&out)
+	providersCmd.SetErr(&errOut)
+	t.Cleanup(func() {
+		providersCmd.SetOut(nil)
+		providersCmd.SetErr(nil)
+	})
+	if err := runProviders(providersCmd, []string{"aider"}); err != nil {
+		t.Fatalf("runProviders() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "aider (Aider)") || !strings.Contains(out.String(), "Manifest:") {
+		t.Errorf("providers output = %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "provider manifest skipped") {
+		t.Errorf("providers stderr = %q, want the skipped manifest", errOut.String())
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+var quarantineCmd = &cobra.Command{
+	Use:   "quarantine [tool] [profile]",
+	Short: "Keep a profile out of automatic selection",
+	Long: `Quarantines a profile so it is never selected automatically - not by
+'caam activate --auto', 'caam next', 'caam run' failover or 'caam robot
+next' - e.g. while an account is under review, or lent to someone for the
+week. It stays listed (caam ls marks it with the reason) and can still be
+activated by name. Use 'caam unquarantine' to release it.
+
+Without arguments, lists quarantined profiles.
+
+Examples:
+  caam quarantine claude shared --reason "lent to Sam until Friday"
+  caam quarantine codex old-work --reason "under review"
+  caam quarantine
+  caam unquarantine claude shared`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runQuarantine,
+}
+
+var unquarantineCmd = &cobra.Command{
+	Use:   "unquarantine <tool> <profile>",
+	Short: "Release a quarantined profile",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runUnquarantine,
+}
+
+func init() {
+	rootCmd.AddCommand(quarantineCmd)
+	rootCmd.AddCommand(unquarantineCmd)
+	quarantineCmd.Flags().String("reason", "", "why the profile is quarantined")
+	quarantineCmd.Flags().Bool("json", false, "output in JSON format")
+}
+
+// quarantineEntry is a quarantined profile in JSON output.
+type quarantineEntry struct {
+	Tool    string    `json:"tool"`
+	Profile string    `json:"profile"`
+	Reason  string    `json:"reason,omitempty"`
+	Since   time.Time `json:"since"`
+}
+
+func runQuarantine(cmd *cobra.Command, args []string) error {
+	reason, _ := cmd.Flags().GetString("reason")
+	jsonFlag, _ := cmd.Flags().GetBool("json")
+
+	c, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	out := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		keys := make([]string, 0, len(c.Quarantined))
+		for key := range c.Quarantined {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		entries := make([]quarantineEntry, 0, len(keys))
+		for _, key := range keys {
+			tool, profile, _ := strings.Cut(key, "/")
+			q := c.Quarantined[key]
+			entries = append(entries, quarantineEntry{Tool: tool, Profile: profile, Reason: q.Reason, Since: q.Since})
+		}
+		if jsonFlag {
+			data, _ := json.MarshalIndent(entries, "", "  ")
+			fmt.Fprintln(out, string(data))
+			return nil
+		}
+		if len(entries) == 0 {
+			fmt.Fprintln(out, "No quarantined profiles.")
+			return nil
+		}
+		for _, e := range entries {
+			fmt.Fprintf(out, "  %-40s since %s  %s\n", e.Tool+"/"+e.Profile, e.Since.Local().Format("2006-01-02 15:04"), e.Reason)
+		}
+		return nil
+	}
+
+	if len(args) < 2 {
+		return withExitCode(ExitUsage, fmt.Errorf("usage: caam quarantine <tool> <profile> [--reason text]"))
+	}
+	tool := strings.ToLower(args[0])
+	profile := args[1]
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+	if !vaultHasProfile(tool, profile) {
+		return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found", tool, profile))
+	}
+
+	q := config.Quarantine{Reason: strings.TrimSpace(reason), Since: time.Now()}
+	if existing, ok := c.GetQuarantine(tool, profile); ok {
+		q.Since = existing.Since
+		if q.Reason == "" {
+			q.Reason = existing.Reason
+		}
+	}
+	c.SetQuarantine(tool, profile, q)
+	if err := c.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+
+	if jsonFlag {
+		data, _ := json.MarshalIndent(quarantineEntry{Tool: tool, Profile: profile, Reason: q.Reason, Since: q.Since}, "", "  ")
+		fmt.Fprintln(out, string(data))
+		return nil
+	}
+	fmt.Fprintf(out, "[OK] Quarantined %s/%s; it won't be selected automatically\n", tool, profile)
+	if c.GetPin(tool) == profile {
+		fmt.Fprintf(out, "Note: %s/%s is also pinned; the quarantine wins until you run 'caam unquarantine %s %s'\n", tool, profile, tool, profile)
+	}
+	return nil
+}
+
+func runUnquarantine(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	profile := args[1]
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+
+	c, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if !c.RemoveQuarantine(tool, profile) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s/%s is not quarantined\n", tool, profile)
+		return nil
+	}
+	if err := c.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "[OK] Released %s/%s from quarantine\n", tool, profile)
+	return nil
+}
+
+// quarantinedProfiles returns tool's quarantined profiles, which automatic
+// selection must skip.
+func quarantinedProfiles(tool string) []string {
+	c, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return c.QuarantinedProfiles(tool)
+}
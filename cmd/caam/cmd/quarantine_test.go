@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+func TestQuarantineKeepsProfileOutOfSelection(t *testing.T) {
+	setupAccountsTest(t)
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+
+	originalHealth := healthStore
+	t.Cleanup(func() { healthStore = originalHealth })
+	healthStore = health.NewStorage(filepath.Join(tmpDir, "health.json"))
+
+	profiles := []string{"alpha", "beta"}
+	for _, name := range profiles {
+		dir := vault.ProfilePath("codex", name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"`+name+`"}`), 0600))
+	}
+
+	quarantineFlags := func(reason string) (*cobra.Command, *bytes.Buffer) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("reason", reason, "")
+		cmd.Flags().Bool("json", false, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		return cmd, &out
+	}
+	cmd, _ := quarantineFlags("")
+	assert.Equal(t, ExitAuthMissing, ExitCode(runQuarantine(cmd, []string{"codex", "missing"})))
+
+	// Pinned, but the quarantine wins.
+	c, err := config.Load()
+	require.NoError(t, err)
+	c.SetPin("codex", "beta")
+	require.NoError(t, c.Save())
+
+	cmd, out := quarantineFlags("lent to Sam")
+	require.NoError(t, runQuarantine(cmd, []string{"codex", "beta"}))
+	assert.Contains(t, out.String(), "also pinned")
+
+	scored := rankRobotNext(nil, "codex", profiles, "smart", true, time.Now())
+	require.Len(t, scored, 1)
+	assert.Equal(t, "alpha", scored[0].name)
+
+	for i := 0; i < 5; i++ {
+		selection, err := selectProfileWithRotation("codex", profiles, "alpha", config.DefaultSPMConfig(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "alpha", selection.Selected)
+	}
+
+	rows := collectLsRows("codex", profiles, "name")
+	require.Len(t, rows, 2)
+	require.NotNil(t, rows[1].quarantine)
+	assert.Equal(t, "lent to Sam", rows[1].quarantine.Reason)
+	assert.Equal(t, "  [quarantined: lent to Sam]", lsQuarantineNote(rows[1].quarantine))
+
+	cmd, out = quarantineFlags("")
+	require.NoError(t, runQuarantine(cmd, nil))
+	assert.Contains(t, out.String(), "codex/beta")
+	assert.Contains(t, out.String(), "lent to Sam")
+
+	require.NoError(t, runUnquarantine(&cobra.Command{}, []string{"codex", "beta"}))
+	assert.Empty(t, quarantinedProfiles("codex"))
+	scored = rankRobotNext(nil, "codex", profiles, "smart", true, time.Now())
+	assert.Equal(t, "beta", scored[0].name, "released and still pinned")
+}
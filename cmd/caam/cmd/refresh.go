@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/refresh"
 	"github.com/spf13/cobra"
@@ -65,7 +66,7 @@ func runRefresh(cmd *cobra.Command, args []string) error {
 
 	tool := strings.ToLower(args[0])
 	if _, ok := tools[tool]; !ok {
-		return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	if len(args) == 1 {
@@ -78,7 +79,7 @@ func runRefresh(cmd *cobra.Command, args []string) error {
 }
 
 func refreshAll(ctx context.Context, threshold time.Duration, dryRun, force, quiet bool) error {
-	toolsToCheck := []string{"codex", "claude", "gemini"}
+	toolsToCheck := knownTools()
 
 	var hadFailure bool
 	var refreshed, skipped, failed int
@@ -190,6 +191,7 @@ func refreshTool(ctx context.Context, tool string, threshold time.Duration, dryR
 		}
 
 		refreshed++
+		recordRefreshEvent(tool, profile)
 		if !quiet {
 			ttl := refreshedTTL(tool, profile)
 			if ttl != "" {
@@ -247,6 +249,7 @@ func refreshSingle(ctx context.Context, tool, profile string, threshold time.Dur
 		}
 		return err
 	}
+	recordRefreshEvent(tool, profile)
 
 	if !quiet {
 		ttl := refreshedTTL(tool, profile)
@@ -260,9 +263,29 @@ func refreshSingle(ctx context.Context, tool, profile string, threshold time.Dur
 	return nil
 }
 
+// recordRefreshEvent logs a successful refresh to the activity log when
+// analytics are enabled, so reports can count refreshes. Without analytics
+// it still stamps the login as verified: the provider just accepted it.
+func recordRefreshEvent(tool, profile string) {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil || !spmCfg.Analytics.Enabled {
+		stampLoginVerified(tool, profile)
+		return
+	}
+	db, err := getDB()
+	if err != nil {
+		return
+	}
+	_ = db.LogEvent(caamdb.Event{
+		Type:        caamdb.EventRefresh,
+		Provider:    tool,
+		ProfileName: profile,
+	})
+}
+
 func shouldRefreshProfile(tool, profile string, threshold time.Duration, force bool) (bool, string, error) {
 	if _, ok := tools[tool]; !ok {
-		return false, "", fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+		return false, "", fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	// Ensure profile exists.
@@ -304,15 +327,24 @@ func shouldRefreshProfile(tool, profile string, threshold time.Duration, force b
 }
 
 func loadExpiryInfo(tool, profile string) (*health.ExpiryInfo, error) {
-	vaultPath := vault.ProfilePath(tool, profile)
+	dir, cleanup, err := vault.PlainProfileDir(tool, profile)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+	return expiryInfoAt(tool, dir)
+}
 
+// expiryInfoAt parses the token expiry of tool's auth files stored flat in dir,
+// the way the vault stores a profile.
+func expiryInfoAt(tool, dir string) (*health.ExpiryInfo, error) {
 	switch tool {
 	case "claude":
-		return health.ParseClaudeExpiry(vaultPath)
+		return health.ParseClaudeExpiry(dir)
 	case "codex":
-		return health.ParseCodexExpiry(filepath.Join(vaultPath, "auth.json"))
+		return health.ParseCodexExpiry(filepath.Join(dir, "auth.json"))
 	case "gemini":
-		return health.ParseGeminiExpiry(vaultPath)
+		return health.ParseGeminiExpiry(dir)
 	default:
 		return nil, fmt.Errorf("refresh not supported for tool: %s", tool)
 	}
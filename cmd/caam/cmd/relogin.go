@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/devicecode"
+)
+
+var reloginCmd = &cobra.Command{
+	Use:   "relogin <tool> <profile-name>",
+	Short: "Re-authenticate an existing vault profile",
+	Long: `Re-run the tool's login flow for a profile that already lives in the vault.
+
+Unlike 'caam login' (which only works with isolated profiles), relogin operates
+on the swap-mode vault:
+  1. Stashes the current live auth (if it doesn't already match a vault profile)
+  2. Clears the live auth files
+  3. Launches the tool's login flow
+  4. Saves the fresh auth into the named vault profile
+  5. Restores whatever was active before
+
+If the profile being re-authenticated was the active one, the fresh auth stays
+active.
+
+Examples:
+  caam relogin claude work
+  caam relogin codex personal --device-code
+  caam relogin gemini team --timeout 10m`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRelogin,
+}
+
+func init() {
+	rootCmd.AddCommand(reloginCmd)
+	reloginCmd.Flags().Duration("timeout", 5*time.Minute, "timeout for login flow completion")
+	reloginCmd.Flags().Bool("force", false, "skip confirmation prompts")
+	reloginCmd.Flags().Bool("device-code", false, "use device code flow for codex (headless)")
+	reloginCmd.Flags().Bool("no-qr", false, "don't show the device code URL as a QR code")
+}
+
+func runRelogin(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	profileName := args[1]
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	force, _ := cmd.Flags().GetBool("force")
+	deviceCode, _ := cmd.Flags().GetBool("device-code")
+	noQR, _ := cmd.Flags().GetBool("no-qr")
+
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
+	}
+
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+
+	if authfile.IsSystemProfile(profileName) {
+		return fmt.Errorf("cannot relogin system profile %s/%s", tool, profileName)
+	}
+
+	if !vaultHasProfile(tool, profileName) {
+		return fmt.Errorf("profile %s/%s not found in vault (use 'caam add %s %s' to create it)", tool, profileName, tool, profileName)
+	}
+
+	fileSet := getFileSet()
+	hadAuth := authfile.HasAuthFiles(fileSet)
+	previousProfile, _ := vault.ActiveProfile(fileSet)
+
+	if !force && !assumeYes(cmd) {
+		fmt.Printf("Re-authenticate %s/%s?", tool, profileName)
+		if hadAuth && previousProfile != profileName {
+			fmt.Print(" Current auth will be restored afterwards.")
+		}
+		fmt.Print(" [Y/n]: ")
+		if !promptConfirm(true) {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	// Stash live auth that isn't already safe in the vault.
+	var stashName string
+	if hadAuth && previousProfile == "" {
+		var err error
+		stashName, err = vault.BackupCurrent(fileSet)
+		if err != nil {
+			return fmt.Errorf("stash current auth: %w", err)
+		}
+		if stashName != "" {
+			fmt.Printf("Stashed current %s auth to %s\n", tool, stashName)
+		}
+	}
+
+	// restorePrevious puts back whatever was live before relogin started.
+	restorePrevious := func() error {
+		switch {
+		case stashName != "":
+			return vault.Restore(fileSet, stashName)
+		case previousProfile != "":
+			return vault.Restore(fileSet, previousProfile)
+		default:
+			return authfile.ClearAuthFiles(fileSet)
+		}
+	}
+
+	fmt.Printf("Clearing %s auth files...\n", tool)
+	if err := authfile.ClearAuthFiles(fileSet); err != nil {
+		if restoreErr := restorePrevious(); restoreErr != nil {
+			fmt.Printf("Warning: could not restore previous auth: %v\n", restoreErr)
+		}
+		return fmt.Errorf("clear auth: %w", err)
+	}
+
+	loginErr := waitForToolLogin(devicecode.WithQR(context.Background(), !noQR), tool, fileSet, timeout, deviceCode)
+	if loginErr == nil && !authfile.HasAuthFiles(fileSet) {
+		fmt.Println("\nNo auth files detected after login.")
+		loginErr = errLoginNoAuthFiles
+	}
+	if loginErr != nil {
+		if restoreErr := restorePrevious(); restoreErr != nil {
+			fmt.Printf("Warning: could not restore previous auth: %v\n", restoreErr)
+		}
+		return loginErr
+	}
+
+	fmt.Println("\nLogin successful!")
+
+	fmt.Printf("Saving fresh auth to %s/%s...\n", tool, profileName)
+	if err := vault.Backup(fileSet, profileName); err != nil {
+		if restoreErr := restorePrevious(); restoreErr != nil {
+			fmt.Printf("Warning: could not restore previous auth: %v\n", restoreErr)
+		}
+		return fmt.Errorf("save profile: %w", err)
+	}
+	fmt.Printf("  Saved %s/%s\n", tool, profileName)
+	printSchemaWarnings(cmd.ErrOrStderr(), tool, profileName)
+
+	// Re-authenticating the active profile leaves the fresh auth live.
+	if previousProfile == profileName {
+		fmt.Printf("  %s/%s remains active\n", tool, profileName)
+		return nil
+	}
+
+	if err := restorePrevious(); err != nil {
+		return fmt.Errorf("restore previous auth: %w", err)
+	}
+	switch {
+	case stashName != "":
+		fmt.Printf("  Restored previous %s auth from %s\n", tool, stashName)
+	case previousProfile != "":
+		fmt.Printf("  Restored %s/%s\n", tool, previousProfile)
+	default:
+		fmt.Printf("  Cleared live %s auth (nothing was active before)\n", tool)
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupReloginTest(t *testing.T) string {
+	t.Helper()
+
+	rootDir := t.TempDir()
+	authPath := filepath.Join(rootDir, "home", "claude_auth.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(authPath), 0755))
+
+	originalVault := vault
+	originalTools := make(map[string]func() authfile.AuthFileSet)
+	for k, v := range tools {
+		originalTools[k] = v
+	}
+	originalExecCommand := execCommand
+	t.Cleanup(func() {
+		vault = originalVault
+		tools = originalTools
+		execCommand = originalExecCommand
+	})
+
+	vault = authfile.NewVault(filepath.Join(rootDir, "vault"))
+	tools["claude"] = func() authfile.AuthFileSet {
+		return authfile.AuthFileSet{
+			Tool: "claude",
+			Files: []authfile.AuthFileSpec{
+				{Tool: "claude", Path: authPath, Required: true, Description: "Main auth token"},
+			},
+		}
+	}
+	execCommand = func(ctx context.Context, name string, args ...string) *exec.Cmd {
+		cs := []string{"-test.run=TestAddHelperProcess", "--", name}
+		cs = append(cs, args...)
+		cmd := exec.CommandContext(ctx, os.Args[0], cs...)
+		cmd.Env = append(os.Environ(),
+			"GO_WANT_HELPER_PROCESS=1",
+			"MOCK_AUTH_PATH="+authPath,
+		)
+		return cmd
+	}
+
+	require.NoError(t, reloginCmd.Flags().Set("force", "true"))
+	t.Cleanup(func() { _ = reloginCmd.Flags().Set("force", "false") })
+
+	return authPath
+}
+
+func saveVaultProfile(t *testing.T, authPath, profileName, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(authPath, []byte(content), 0600))
+	require.NoError(t, vault.Backup(tools["claude"](), profileName))
+}
+
+func TestRelogin_RestoresPreviousProfile(t *testing.T) {
+	authPath := setupReloginTest(t)
+
+	saveVaultProfile(t, authPath, "work", `{"sessionKey": "stale-work-key"}`)
+	saveVaultProfile(t, authPath, "personal", `{"sessionKey": "personal-key"}`)
+
+	require.NoError(t, runRelogin(reloginCmd, []string{"claude", "work"}))
+
+	saved, err := os.ReadFile(filepath.Join(vault.ProfilePath("claude", "work"), "claude_auth.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), "new-session-key")
+
+	live, err := os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(live), "personal-key")
+}
+
+func TestRelogin_ActiveProfileStaysActive(t *testing.T) {
+	authPath := setupReloginTest(t)
+
+	saveVaultProfile(t, authPath, "work", `{"sessionKey": "stale-work-key"}`)
+
+	require.NoError(t, runRelogin(reloginCmd, []string{"claude", "work"}))
+
+	live, err := os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(live), "new-session-key")
+
+	active, err := vault.ActiveProfile(tools["claude"]())
+	require.NoError(t, err)
+	assert.Equal(t, "work", active)
+}
+
+func TestRelogin_StashesUnsavedAuth(t *testing.T) {
+	authPath := setupReloginTest(t)
+
+	saveVaultProfile(t, authPath, "work", `{"sessionKey": "stale-work-key"}`)
+	require.NoError(t, os.WriteFile(authPath, []byte(`{"sessionKey": "unsaved-key"}`), 0600))
+
+	require.NoError(t, runRelogin(reloginCmd, []string{"claude", "work"}))
+
+	live, err := os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(live), "unsaved-key")
+
+	profiles, err := vault.List("claude")
+	require.NoError(t, err)
+	var stashed bool
+	for _, p := range profiles {
+		if len(p) > len("_backup_") && p[:len("_backup_")] == "_backup_" {
+			stashed = true
+		}
+	}
+	assert.True(t, stashed, "unsaved live auth should be stashed in the vault")
+}
+
+func TestRelogin_RejectsUnknownProfile(t *testing.T) {
+	setupReloginTest(t)
+
+	err := runRelogin(reloginCmd, []string{"claude", "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found in vault")
+}
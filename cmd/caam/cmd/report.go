@@ -0,0 +1,464 @@
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate activity reports",
+}
+
+var reportWeeklyCmd = &cobra.Command{
+	Use:   "weekly",
+	Short: "Summarize the last week of account activity",
+	Long: `Builds a digest of the last seven days from caam's activity database:
+hours and sessions per account, profile switches, rate limit hits, time
+lost to cooldowns, token refreshes and the projects that took the most
+session time.
+
+Hours and projects come from sessions run through 'caam run'; switches and
+refreshes from the activity log (analytics.enabled); limit hits and
+cooldown time from recorded cooldowns. Use --md or --html for output that
+pastes cleanly into a team channel or wiki.
+
+Examples:
+  caam report weekly
+  caam report weekly --md
+  caam report weekly --html > week.html
+  caam report weekly --weeks-ago 1 --md`,
+	Args: cobra.NoArgs,
+	RunE: runReportWeekly,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportWeeklyCmd)
+	reportWeeklyCmd.Flags().Bool("md", false, "output Markdown")
+	reportWeeklyCmd.Flags().Bool("html", false, "output an HTML fragment")
+	reportWeeklyCmd.Flags().Int("weeks-ago", 0, "report on an earlier week (1 = the seven days before the last seven)")
+	reportWeeklyCmd.Flags().Int("top", 5, "number of projects to list")
+}
+
+// weeklyAccountRow is one account's activity in a weekly report.
+type weeklyAccountRow struct {
+	Provider        string
+	Profile         string
+	Sessions        int
+	SessionSeconds  int64
+	Switches        int
+	LimitHits       int
+	CooldownSeconds int64
+	Refreshes       int
+}
+
+// weeklyProjectRow is the session time spent in one working directory.
+type weeklyProjectRow struct {
+	Dir            string
+	Sessions       int
+	SessionSeconds int64
+}
+
+// weeklyReport is the digest rendered by 'caam report weekly'.
+type weeklyReport struct {
+	Start    time.Time
+	End      time.Time
+	Accounts []weeklyAccountRow
+	Projects []weeklyProjectRow
+	Totals   weeklyAccountRow
+}
+
+func runReportWeekly(cmd *cobra.Command, args []string) error {
+	asMarkdown, _ := cmd.Flags().GetBool("md")
+	asHTML, _ := cmd.Flags().GetBool("html")
+	weeksAgo, _ := cmd.Flags().GetInt("weeks-ago")
+	top, _ := cmd.Flags().GetInt("top")
+
+	if asMarkdown && asHTML {
+		return withExitCode(ExitUsage, fmt.Errorf("use either --md or --html, not both"))
+	}
+	if weeksAgo < 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--weeks-ago must be zero or more"))
+	}
+
+	end := time.Now().Add(-time.Duration(weeksAgo) * 7 * 24 * time.Hour)
+	start := end.Add(-7 * 24 * time.Hour)
+
+	db, err := caamdb.Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	report, err := buildWeeklyReport(db, start, end, top)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	switch {
+	case asMarkdown:
+		return renderWeeklyMarkdown(out, report)
+	case asHTML:
+		return renderWeeklyHTML(out, report)
+	default:
+		return renderWeeklyText(out, report)
+	}
+}
+
+// buildWeeklyReport aggregates activity between start and end. top limits
+// the number of projects listed.
+func buildWeeklyReport(db *caamdb.DB, start, end time.Time, top int) (*weeklyReport, error) {
+	if db == nil || db.Conn() == nil {
+		return nil, fmt.Errorf("db not available")
+	}
+
+	accounts := make(map[string]*weeklyAccountRow)
+	account := func(provider, profile string) *weeklyAccountRow {
+		key := provider + "/" + profile
+		row, ok := accounts[key]
+		if !ok {
+			row = &weeklyAccountRow{Provider: provider, Profile: profile}
+			accounts[key] = row
+		}
+		return row
+	}
+
+	if err := queryWeeklySessions(db, start, end, account); err != nil {
+		return nil, err
+	}
+	if err := queryWeeklyActivity(db, start, end, account); err != nil {
+		return nil, err
+	}
+	if err := queryWeeklyCooldowns(db, start, end, account); err != nil {
+		return nil, err
+	}
+	projects, err := queryWeeklyProjects(db, start, end, top)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &weeklyReport{Start: start, End: end, Projects: projects}
+	for _, row := range accounts {
+		report.Accounts = append(report.Accounts, *row)
+		report.Totals.Sessions += row.Sessions
+		report.Totals.SessionSeconds += row.SessionSeconds
+		report.Totals.Switches += row.Switches
+		report.Totals.LimitHits += row.LimitHits
+		report.Totals.CooldownSeconds += row.CooldownSeconds
+		report.Totals.Refreshes += row.Refreshes
+	}
+	sort.Slice(report.Accounts, func(i, j int) bool {
+		a, b := report.Accounts[i], report.Accounts[j]
+		if a.SessionSeconds != b.SessionSeconds {
+			return a.SessionSeconds > b.SessionSeconds
+		}
+		if a.Switches != b.Switches {
+			return a.Switches > b.Switches
+		}
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		return a.Profile < b.Profile
+	})
+
+	return report, nil
+}
+
+// queryWeeklySessions adds session counts and time per account.
+func queryWeeklySessions(db *caamdb.DB, start, end time.Time, account func(provider, profile string) *weeklyAccountRow) error {
+	rows, err := db.Conn().Query(
+		`SELECT provider, profile_name, COUNT(*), COALESCE(SUM(duration_seconds), 0)
+		   FROM wrap_sessions
+		  WHERE datetime(started_at) >= datetime(?) AND datetime(started_at) < datetime(?)
+		  GROUP BY provider, profile_name`,
+		formatSQLiteSince(start),
+		formatSQLiteSince(end),
+	)
+	if err != nil {
+		return fmt.Errorf("query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var provider, profile string
+		var sessions int
+		var seconds int64
+		if err := rows.Scan(&provider, &profile, &sessions, &seconds); err != nil {
+			return fmt.Errorf("scan sessions: %w", err)
+		}
+		row := account(provider, profile)
+		row.Sessions += sessions
+		row.SessionSeconds += seconds
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate sessions: %w", err)
+	}
+	return nil
+}
+
+// queryWeeklyActivity adds switches and token refreshes per account.
+func queryWeeklyActivity(db *caamdb.DB, start, end time.Time, account func(provider, profile string) *weeklyAccountRow) error {
+	rows, err := db.Conn().Query(
+		`SELECT provider,
+		        profile_name,
+		        SUM(CASE WHEN event_type IN (?, ?) THEN 1 ELSE 0 END),
+		        SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END)
+		   FROM activity_log
+		  WHERE datetime(timestamp) >= datetime(?) AND datetime(timestamp) < datetime(?)
+		    AND event_type IN (?, ?, ?)
+		  GROUP BY provider, profile_name`,
+		caamdb.EventActivate, caamdb.EventSwitch,
+		caamdb.EventRefresh,
+		formatSQLiteSince(start),
+		formatSQLiteSince(end),
+		caamdb.EventActivate, caamdb.EventSwitch, caamdb.EventRefresh,
+	)
+	if err != nil {
+		return fmt.Errorf("query activity: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var provider, profile string
+		var switches, refreshes int
+		if err := rows.Scan(&provider, &profile, &switches, &refreshes); err != nil {
+			return fmt.Errorf("scan activity: %w", err)
+		}
+		row := account(provider, profile)
+		row.Switches += switches
+		row.Refreshes += refreshes
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate activity: %w", err)
+	}
+	return nil
+}
+
+// queryWeeklyCooldowns adds limit hits within the window and the part of
+// every cooldown that overlaps it, including ones that started earlier.
+func queryWeeklyCooldowns(db *caamdb.DB, start, end time.Time, account func(provider, profile string) *weeklyAccountRow) error {
+	rows, err := db.Conn().Query(
+		`SELECT provider, profile_name, hit_at, cooldown_until
+		   FROM limit_events
+		  WHERE datetime(hit_at) < datetime(?) AND datetime(cooldown_until) > datetime(?)`,
+		formatSQLiteSince(end),
+		formatSQLiteSince(start),
+	)
+	if err != nil {
+		return fmt.Errorf("query cooldowns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var provider, profile, hitStr, untilStr string
+		if err := rows.Scan(&provider, &profile, &hitStr, &untilStr); err != nil {
+			return fmt.Errorf("scan cooldowns: %w", err)
+		}
+		hitAt, err := parseSQLiteTime(hitStr)
+		if err != nil {
+			continue
+		}
+		until, err := parseSQLiteTime(untilStr)
+		if err != nil {
+			continue
+		}
+
+		row := account(provider, profile)
+		if !hitAt.Before(start) {
+			row.LimitHits++
+		}
+		lostFrom, lostTo := hitAt, until
+		if lostFrom.Before(start) {
+			lostFrom = start
+		}
+		if lostTo.After(end) {
+			lostTo = end
+		}
+		if lostTo.After(lostFrom) {
+			row.CooldownSeconds += int64(lostTo.Sub(lostFrom).Seconds())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate cooldowns: %w", err)
+	}
+	return nil
+}
+
+// queryWeeklyProjects returns the top working directories by session time.
+func queryWeeklyProjects(db *caamdb.DB, start, end time.Time, top int) ([]weeklyProjectRow, error) {
+	if top <= 0 {
+		return nil, nil
+	}
+	rows, err := db.Conn().Query(
+		`SELECT work_dir, COUNT(*), COALESCE(SUM(duration_seconds), 0) AS seconds
+		   FROM wrap_sessions
+		  WHERE datetime(started_at) >= datetime(?) AND datetime(started_at) < datetime(?)
+		    AND COALESCE(work_dir, '') != ''
+		  GROUP BY work_dir
+		  ORDER BY seconds DESC, work_dir ASC
+		  LIMIT ?`,
+		formatSQLiteSince(start),
+		formatSQLiteSince(end),
+		top,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query projects: %w", err)
+	}
+	defer rows.Close()
+
+	var out []weeklyProjectRow
+	for rows.Next() {
+		var p weeklyProjectRow
+		if err := rows.Scan(&p.Dir, &p.Sessions, &p.SessionSeconds); err != nil {
+			return nil, fmt.Errorf("scan projects: %w", err)
+		}
+		out = append(out, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate projects: %w", err)
+	}
+	return out, nil
+}
+
+// weeklyHours formats seconds as hours with one decimal.
+func weeklyHours(seconds int64) string {
+	return fmt.Sprintf("%.1fh", float64(seconds)/3600)
+}
+
+// weeklyRange formats the report window, e.g. "2026-10-07 to 2026-10-14".
+func weeklyRange(r *weeklyReport) string {
+	return r.Start.Local().Format("2006-01-02") + " to " + r.End.Local().Format("2006-01-02")
+}
+
+// weeklySummary is the one-line overview shared by every format.
+func weeklySummary(r *weeklyReport) string {
+	t := r.Totals
+	return fmt.Sprintf("%s across %d account(s), %d session(s), %d switch(es), %d limit hit(s), %s lost to cooldowns, %d token refresh(es)",
+		weeklyHours(t.SessionSeconds), len(r.Accounts), t.Sessions, t.Switches, t.LimitHits,
+		formatDurationShort(time.Duration(t.CooldownSeconds)*time.Second), t.Refreshes)
+}
+
+// weeklyAccountCells returns a row's table cells in column order.
+func weeklyAccountCells(row weeklyAccountRow) []string {
+	return []string{
+		row.Provider + "/" + row.Profile,
+		weeklyHours(row.SessionSeconds),
+		fmt.Sprintf("%d", row.Sessions),
+		fmt.Sprintf("%d", row.Switches),
+		fmt.Sprintf("%d", row.LimitHits),
+		formatDurationShort(time.Duration(row.CooldownSeconds) * time.Second),
+		fmt.Sprintf("%d", row.Refreshes),
+	}
+}
+
+var weeklyAccountHeaders = []string{"Account", "Hours", "Sessions", "Switches", "Limit hits", "Cooldown lost", "Refreshes"}
+
+// weeklyProjectName shortens a project directory for display.
+func weeklyProjectName(dir string) string {
+	return shortenHomePath(filepath.Clean(dir))
+}
+
+func renderWeeklyText(w io.Writer, r *weeklyReport) error {
+	fmt.Fprintf(w, "Weekly report (%s)\n", weeklyRange(r))
+	fmt.Fprintf(w, "%s\n\n", weeklySummary(r))
+
+	if len(r.Accounts) == 0 {
+		fmt.Fprintln(w, "No activity recorded.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(weeklyAccountHeaders, "\t")))
+	for _, row := range r.Accounts {
+		fmt.Fprintln(tw, strings.Join(weeklyAccountCells(row), "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(r.Projects) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Top projects:")
+		tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, p := range r.Projects {
+			fmt.Fprintf(tw, "  %s\t%s\t%d session(s)\n", weeklyProjectName(p.Dir), weeklyHours(p.SessionSeconds), p.Sessions)
+		}
+		return tw.Flush()
+	}
+	return nil
+}
+
+func renderWeeklyMarkdown(w io.Writer, r *weeklyReport) error {
+	fmt.Fprintf(w, "## caam weekly report (%s)\n\n", weeklyRange(r))
+	fmt.Fprintf(w, "%s.\n", weeklySummary(r))
+
+	if len(r.Accounts) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "| %s |\n", strings.Join(weeklyAccountHeaders, " | "))
+		fmt.Fprintln(w, "|---|---:|---:|---:|---:|---:|---:|")
+		for _, row := range r.Accounts {
+			cells := weeklyAccountCells(row)
+			cells[0] = "`" + cells[0] + "`"
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	if len(r.Projects) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "### Top projects")
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "| Project | Hours | Sessions |")
+		fmt.Fprintln(w, "|---|---:|---:|")
+		for _, p := range r.Projects {
+			fmt.Fprintf(w, "| `%s` | %s | %d |\n", weeklyProjectName(p.Dir), weeklyHours(p.SessionSeconds), p.Sessions)
+		}
+	}
+	return nil
+}
+
+func renderWeeklyHTML(w io.Writer, r *weeklyReport) error {
+	esc := html.EscapeString
+	fmt.Fprintf(w, "<h2>caam weekly report (%s)</h2>\n", esc(weeklyRange(r)))
+	fmt.Fprintf(w, "<p>%s.</p>\n", esc(weeklySummary(r)))
+
+	if len(r.Accounts) > 0 {
+		fmt.Fprintln(w, "<table>")
+		fmt.Fprint(w, "<tr>")
+		for _, h := range weeklyAccountHeaders {
+			fmt.Fprintf(w, "<th>%s</th>", esc(h))
+		}
+		fmt.Fprintln(w, "</tr>")
+		for _, row := range r.Accounts {
+			fmt.Fprint(w, "<tr>")
+			for _, cell := range weeklyAccountCells(row) {
+				fmt.Fprintf(w, "<td>%s</td>", esc(cell))
+			}
+			fmt.Fprintln(w, "</tr>")
+		}
+		fmt.Fprintln(w, "</table>")
+	}
+
+	if len(r.Projects) > 0 {
+		fmt.Fprintln(w, "<h3>Top projects</h3>")
+		fmt.Fprintln(w, "<table>")
+		fmt.Fprintln(w, "<tr><th>Project</th><th>Hours</th><th>Sessions</th></tr>")
+		for _, p := range r.Projects {
+			fmt.Fprintf(w, "<tr><td>%s</td><td>%s</td><td>%d</td></tr>\n",
+				esc(weeklyProjectName(p.Dir)), esc(weeklyHours(p.SessionSeconds)), p.Sessions)
+		}
+		fmt.Fprintln(w, "</table>")
+	}
+	return nil
+}
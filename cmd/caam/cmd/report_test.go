@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+func seedWeeklyReportDB(t *testing.T, now time.Time) *caamdb.DB {
+	t.Helper()
+
+	db, err := caamdb.OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	sessions := []caamdb.WrapSession{
+		{Provider: "claude", ProfileName: "work", StartedAt: now.Add(-48 * time.Hour), EndedAt: now.Add(-46 * time.Hour), WorkDir: "/src/api"},
+		{Provider: "claude", ProfileName: "work", StartedAt: now.Add(-24 * time.Hour), EndedAt: now.Add(-23 * time.Hour), WorkDir: "/src/web"},
+		{Provider: "codex", ProfileName: "main", StartedAt: now.Add(-5 * time.Hour), EndedAt: now.Add(-4 * time.Hour), WorkDir: "/src/api"},
+		// Outside the window.
+		{Provider: "codex", ProfileName: "main", StartedAt: now.Add(-10 * 24 * time.Hour), EndedAt: now.Add(-9 * 24 * time.Hour), WorkDir: "/src/old"},
+	}
+	for _, s := range sessions {
+		if err := db.RecordWrapSession(s); err != nil {
+			t.Fatalf("RecordWrapSession() error = %v", err)
+		}
+	}
+
+	events := []caamdb.Event{
+		{Type: caamdb.EventActivate, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-48 * time.Hour)},
+		{Type: caamdb.EventActivate, Provider: "codex", ProfileName: "main", Timestamp: now.Add(-5 * time.Hour)},
+		{Type: caamdb.EventSwitch, Provider: "codex", ProfileName: "main", Timestamp: now.Add(-4 * time.Hour)},
+		{Type: caamdb.EventRefresh, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-30 * time.Hour)},
+		{Type: caamdb.EventActivate, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-9 * 24 * time.Hour)},
+	}
+	for _, ev := range events {
+		if err := db.LogEvent(ev); err != nil {
+			t.Fatalf("LogEvent() error = %v", err)
+		}
+	}
+
+	// One hit inside the window, one whose cooldown runs into it.
+	if _, err := db.SetCooldown("codex", "main", now.Add(-3*time.Hour), time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+	if _, err := db.SetCooldown("claude", "work", now.Add(-7*24*time.Hour-time.Hour), 3*time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+	return db
+}
+
+func TestBuildWeeklyReport(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	db := seedWeeklyReportDB(t, now)
+
+	report, err := buildWeeklyReport(db, now.Add(-7*24*time.Hour), now, 5)
+	if err != nil {
+		t.Fatalf("buildWeeklyReport() error = %v", err)
+	}
+
+	if len(report.Accounts) != 2 {
+		t.Fatalf("accounts = %+v, want 2", report.Accounts)
+	}
+	claude, codex := report.Accounts[0], report.Accounts[1]
+	if claude.Provider != "claude" || claude.Sessions != 2 || claude.SessionSeconds != 3*3600 {
+		t.Errorf("claude row = %+v, want 2 sessions over 3h first", claude)
+	}
+	if claude.Switches != 1 || claude.Refreshes != 1 {
+		t.Errorf("claude switches/refreshes = %d/%d, want 1/1", claude.Switches, claude.Refreshes)
+	}
+	if claude.LimitHits != 0 || claude.CooldownSeconds != 2*3600 {
+		t.Errorf("claude limits = %d hits, %ds lost; want 0 hits, 2h lost from the earlier cooldown", claude.LimitHits, claude.CooldownSeconds)
+	}
+	if codex.Switches != 2 || codex.LimitHits != 1 || codex.CooldownSeconds != 3600 {
+		t.Errorf("codex row = %+v, want 2 switches, 1 hit, 1h lost", codex)
+	}
+
+	if report.Totals.SessionSeconds != 4*3600 || report.Totals.LimitHits != 1 {
+		t.Errorf("totals = %+v", report.Totals)
+	}
+	if len(report.Projects) != 2 || report.Projects[0].Dir != "/src/api" || report.Projects[0].SessionSeconds != 3*3600 {
+		t.Errorf("projects = %+v, want /src/api first with 3h", report.Projects)
+	}
+}
+
+func TestRenderWeeklyReport_Formats(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	report, err := buildWeeklyReport(seedWeeklyReportDB(t, now), now.Add(-7*24*time.Hour), now, 1)
+	if err != nil {
+		t.Fatalf("buildWeeklyReport() error = %v", err)
+	}
+	if len(report.Projects) != 1 {
+		t.Fatalf("projects = %+v, want only the top one", report.Projects)
+	}
+
+	var md bytes.Buffer
+	if err := renderWeeklyMarkdown(&md, report); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## caam weekly report", "| `claude/work` | 3.0h | 2 |", "### Top projects", "| `/src/api` | 3.0h | 2 |"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+
+	report.Accounts[0].Profile = "<b>"
+	var html bytes.Buffer
+	if err := renderWeeklyHTML(&html, report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "<td>claude/&lt;b&gt;</td>") {
+		t.Errorf("html should escape cells:\n%s", html.String())
+	}
+
+	var text bytes.Buffer
+	if err := renderWeeklyText(&text, &weeklyReport{Start: now.Add(-time.Hour), End: now}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "No activity recorded.") {
+		t.Errorf("empty text report = %q", text.String())
+	}
+}
@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/version"
+)
+
+var reportSchemaCmd = &cobra.Command{
+	Use:   "report-schema [tool...]",
+	Short: "Print an anonymized auth file structure report for bug reports",
+	Long: `Prints the JSON key structure of each tool's auth files, with every value
+and identifying key (paths, emails, ids) stripped, compared against:
+
+  - the fields caam's parsers read ("-" lines: expected, missing)
+  - the structure recorded when the profile was backed up ("+"/"-" lines)
+
+Paste the output into an issue when caam warns that an auth file's format
+may have changed. It contains no tokens, emails or other secrets.
+
+By default the live auth files are checked against the active profile's
+backup (or the newest backup); --profile checks a vault profile instead.
+Profile names are left out of the report.
+
+Examples:
+  caam report-schema
+  caam report-schema claude
+  caam report-schema codex --profile work --full`,
+	RunE: runReportSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(reportSchemaCmd)
+	reportSchemaCmd.Flags().String("profile", "", "check this vault profile instead of the live auth files (one tool only)")
+	reportSchemaCmd.Flags().Bool("full", false, "also list the complete key structure")
+}
+
+func runReportSchema(cmd *cobra.Command, args []string) error {
+	profileName, _ := cmd.Flags().GetString("profile")
+	full, _ := cmd.Flags().GetBool("full")
+
+	toolNames := knownTools()
+	if len(args) > 0 {
+		toolNames = nil
+		for _, arg := range args {
+			tool := strings.ToLower(arg)
+			if _, ok := tools[tool]; !ok {
+				return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+			}
+			toolNames = append(toolNames, tool)
+		}
+	}
+	if profileName != "" && len(toolNames) != 1 {
+		return withExitCode(ExitUsage, fmt.Errorf("--profile needs exactly one tool"))
+	}
+	if profileName != "" && !vaultHasProfile(toolNames[0], profileName) {
+		return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", toolNames[0], profileName))
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "caam auth schema report (caam %s, %s/%s)\n", version.Short(), runtime.GOOS, runtime.GOARCH)
+	for _, tool := range toolNames {
+		writeSchemaReport(out, tool, profileName, full)
+	}
+	return nil
+}
+
+// writeSchemaReport prints one tool's section of the report.
+func writeSchemaReport(w io.Writer, tool, profileName string, full bool) {
+	fileSet := tools[tool]()
+	fmt.Fprintf(w, "\n## %s\n", tool)
+
+	var current map[string]authfile.FileSchema
+	baseline := profileName
+	if profileName != "" {
+		fmt.Fprintf(w, "source: vault profile\n")
+		dir, cleanup, err := vault.PlainProfileDir(tool, profileName)
+		if err != nil {
+			fmt.Fprintf(w, "cannot read profile: %v\n", err)
+			return
+		}
+		current = authfile.ReadFileSchemas(fileSet, dir)
+		cleanup()
+	} else {
+		fmt.Fprintf(w, "source: live auth files\n")
+		current = authfile.ReadFileSchemas(fileSet, "")
+		// A changed format no longer matches its backup, so fall back to
+		// the newest backup.
+		if baseline, _ = vault.ActiveProfile(fileSet); baseline == "" {
+			baseline = latestBackup(tool)
+		}
+	}
+
+	var recorded map[string]authfile.FileSchema
+	if baseline != "" {
+		recorded, _ = vault.RecordedSchemas(tool, baseline)
+	}
+
+	if len(current) == 0 {
+		fmt.Fprintln(w, "no JSON auth files found")
+		return
+	}
+
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		schema := current[name]
+		fmt.Fprintf(w, "%s  fingerprint %s  (%d keys)\n", name, schema.Fingerprint, len(schema.Keys))
+
+		drift := false
+		for _, key := range authfile.MissingSchemaKeys(tool, name, schema) {
+			fmt.Fprintf(w, "  - %-40s expected by caam, missing\n", key)
+			drift = true
+		}
+		if old, ok := recorded[name]; ok && old.Fingerprint != schema.Fingerprint {
+			added, removed := authfile.DiffSchemas(old, schema)
+			for _, key := range removed {
+				fmt.Fprintf(w, "  - %-40s since backup (%s)\n", key, old.Fingerprint)
+			}
+			for _, key := range added {
+				fmt.Fprintf(w, "  + %-40s since backup (%s)\n", key, old.Fingerprint)
+			}
+			drift = true
+		}
+		if !drift {
+			fmt.Fprintln(w, "  no drift")
+		}
+
+		if full {
+			for _, key := range schema.Keys {
+				fmt.Fprintf(w, "    %s\n", key)
+			}
+		}
+	}
+}
+
+// latestBackup returns tool's most recently written vault profile.
+func latestBackup(tool string) string {
+	profiles, err := vault.List(tool)
+	if err != nil {
+		return ""
+	}
+	var latest string
+	var latestTime time.Time
+	for _, name := range profiles {
+		info, err := os.Stat(filepath.Join(vault.ProfilePath(tool, name), "meta.json"))
+		if err != nil {
+			continue
+		}
+		if latest == "" || info.ModTime().After(latestTime) {
+			latest, latestTime = name, info.ModTime()
+		}
+	}
+	return latest
+}
+
+// schemaWarnings checks a freshly backed-up profile's recorded schemas
+// against the fields caam's parsers read.
+func schemaWarnings(tool, profileName string) []string {
+	schemas, err := vault.RecordedSchemas(tool, profileName)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		if missing := authfile.MissingSchemaKeys(tool, name, schemas[name]); len(missing) > 0 {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s %s lacks fields caam reads (%s); its format may have changed. Run 'caam report-schema %s' and paste the output into an issue",
+				tool, name, strings.Join(missing, ", "), tool))
+		}
+	}
+	return warnings
+}
+
+// printSchemaWarnings prints schemaWarnings to w.
+func printSchemaWarnings(w io.Writer, tool, profileName string) {
+	for _, warning := range schemaWarnings(tool, profileName) {
+		fmt.Fprintf(w, "Warning: %s\n", warning)
+	}
+}
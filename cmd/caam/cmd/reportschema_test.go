@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportSchema_LiveDriftSinceBackup(t *testing.T) {
+	setupAccountsTest(t)
+	codexHome := filepath.Join(t.TempDir(), "codex_home")
+	t.Setenv("CODEX_HOME", codexHome)
+	require.NoError(t, os.MkdirAll(codexHome, 0700))
+	authPath := filepath.Join(codexHome, "auth.json")
+
+	require.NoError(t, os.WriteFile(authPath, []byte(`{"tokens": {"access_token": "tok-secret", "refresh_token": "ref-secret"}}`), 0600))
+	require.NoError(t, vault.Backup(tools["codex"](), "work"))
+	assert.Empty(t, schemaWarnings("codex", "work"))
+
+	// The vendor renames refresh_token; the live file no longer matches the
+	// backup, and caam's parsers lose a field.
+	require.NoError(t, os.WriteFile(authPath, []byte(`{"tokens": {"access_token": "tok-secret", "renew": "ref-secret"}}`), 0600))
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("profile", "", "")
+	cmd.Flags().Bool("full", false, "")
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	require.NoError(t, runReportSchema(cmd, []string{"codex"}))
+	out := buf.String()
+
+	assert.Contains(t, out, "source: live auth files")
+	assert.Regexp(t, `- tokens\.refresh_token\s+expected by caam, missing`, out)
+	assert.Regexp(t, `\+ tokens\.renew:string\s+since backup`, out)
+	assert.Regexp(t, `- tokens\.refresh_token:string\s+since backup`, out)
+	assert.NotContains(t, out, "secret")
+
+	require.NoError(t, vault.Backup(tools["codex"](), "renamed"))
+	warnings := schemaWarnings("codex", "renamed")
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "tokens.refresh_token")
+	assert.Contains(t, warnings[0], "caam report-schema codex")
+}
+
+func TestReportSchema_ProfileNeedsOneTool(t *testing.T) {
+	setupAccountsTest(t)
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("profile", "", "")
+	cmd.Flags().Bool("full", false, "")
+	require.NoError(t, cmd.Flags().Set("profile", "work"))
+	err := runReportSchema(cmd, nil)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
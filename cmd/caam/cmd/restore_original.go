@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// originalProfile is the system profile caam captures the first time it
+// replaces a tool's login.
+const originalProfile = "_original"
+
+var restoreOriginalCmd = &cobra.Command{
+	Use:   "restore-original <tool>",
+	Short: "Restore the login you had before caam",
+	Long: `Restores the _original profile: the auth files caam saved automatically
+the first time it replaced this tool's login. Use it to get back to where you
+were before caam, without uninstalling.
+
+If the current login isn't saved in any vault profile, it is backed up as a
+timestamped _backup_* profile first; pass --no-backup to skip that.
+
+Examples:
+  caam restore-original claude
+  caam restore-original codex --force --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestoreOriginal,
+}
+
+// restoreOriginalOutput is the JSON output structure for restore-original.
+type restoreOriginalOutput struct {
+	jsonStatus
+	Tool     string   `json:"tool"`
+	Email    string   `json:"email,omitempty"`
+	Previous string   `json:"previous_profile,omitempty"`
+	Backup   string   `json:"backup,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(restoreOriginalCmd)
+	restoreOriginalCmd.Flags().Bool("force", false, "skip confirmation")
+	restoreOriginalCmd.Flags().Bool("no-backup", false, "don't back up an unsaved current login first")
+	restoreOriginalCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+func runRestoreOriginal(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+	force, _ := cmd.Flags().GetBool("force")
+	out := cmd.OutOrStdout()
+
+	output := restoreOriginalOutput{Tool: tool}
+	finish := func(err error) error {
+		if jsonOutput {
+			return writeJSONResult(cmd, &output, err)
+		}
+		return err
+	}
+
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return finish(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())))
+	}
+	fileSet := getFileSet()
+
+	has, err := vault.HasOriginalBackup(tool)
+	if err != nil {
+		return finish(fmt.Errorf("check %s/%s: %w", tool, originalProfile, err))
+	}
+	if !has {
+		return finish(withExitCode(ExitAuthMissing, fmt.Errorf(
+			"no %s backup for %s (caam saves one the first time it activates a profile over an existing login)", originalProfile, tool)))
+	}
+	output.Email = originalEmail(tool)
+
+	active, _ := vault.ActiveProfile(fileSet)
+	output.Previous = active
+	if active == originalProfile {
+		if jsonOutput {
+			return finish(nil)
+		}
+		fmt.Fprintf(out, "%s is already on its original login\n", tool)
+		return nil
+	}
+
+	who := "the login you had before caam"
+	if output.Email != "" {
+		who = fmt.Sprintf("the login you had before caam (%s)", output.Email)
+	}
+	if !force && !confirmPrompt(cmd, jsonOutput, fmt.Sprintf("Replace the current %s login with %s?", tool, who)) {
+		if jsonOutput {
+			return finish(errCancelled)
+		}
+		fmt.Fprintln(out, "Cancelled")
+		return nil
+	}
+
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		// Invalid config should not block getting the old login back.
+		spmCfg = config.DefaultSPMConfig()
+	}
+	opts := authfile.ActivateOptions{BackupMode: authfile.BackupSmart, MaxAutoBackups: spmCfg.Safety.MaxAutoBackups}
+	if noBackup {
+		opts.BackupMode = authfile.BackupNever
+	}
+	safety, err := vault.Activate(fileSet, originalProfile, opts)
+	if safety != nil {
+		output.Backup = safety.AutoBackup
+		output.Warnings = safety.Warnings
+	}
+	if err != nil {
+		return finish(fmt.Errorf("restore %s/%s: %w", tool, originalProfile, err))
+	}
+
+	if jsonOutput {
+		return finish(nil)
+	}
+	if output.Backup != "" {
+		fmt.Fprintf(out, "Backed up current login to %s/%s\n", tool, output.Backup)
+	}
+	for _, w := range output.Warnings {
+		fmt.Fprintf(out, "Warning: %s\n", w)
+	}
+	fmt.Fprintf(out, "Restored %s to %s\n", tool, who)
+	return nil
+}
+
+// originalEmail returns the account the _original profile is logged in as,
+// if its auth files name one.
+func originalEmail(tool string) string {
+	if id := getVaultIdentity(tool, originalProfile); id != nil {
+		return id.Email
+	}
+	return ""
+}
+
+// lsOriginalNote is the hint ls prints under a tool whose listed profiles
+// include _original, naming the account it holds.
+func lsOriginalNote(tool string, rows []lsRow) string {
+	for _, row := range rows {
+		if row.name != originalProfile {
+			continue
+		}
+		who := "your login from before caam"
+		if row.id != nil && row.id.Email != "" {
+			who = fmt.Sprintf("your login from before caam (%s)", row.id.Email)
+		}
+		return fmt.Sprintf("%s holds %s; restore it with: caam restore-original %s", originalProfile, who, tool)
+	}
+	return ""
+}
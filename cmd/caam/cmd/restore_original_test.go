@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/spf13/cobra"
+)
+
+func TestRestoreOriginal(t *testing.T) {
+	tmpDir, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+	t.Setenv("HOME", t.TempDir())
+
+	run := func(noBackup bool) (restoreOriginalOutput, error) {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("force", true, "")
+		cmd.Flags().Bool("no-backup", noBackup, "")
+		cmd.Flags().Bool("json", true, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		err := runRestoreOriginal(cmd, []string{"codex"})
+		var output restoreOriginalOutput
+		if jerr := json.Unmarshal(out.Bytes(), &output); jerr != nil {
+			t.Fatalf("unmarshal: %v\n%s", jerr, out.String())
+		}
+		return output, err
+	}
+
+	if missing, _ := run(false); missing.Success || missing.ExitCode != ExitAuthMissing {
+		t.Fatalf("without _original: %+v, want an auth-missing failure", missing)
+	}
+
+	fileSet := authfile.CodexAuthFiles()
+	authPath := filepath.Join(os.Getenv("CODEX_HOME"), "auth.json")
+	if err := os.WriteFile(authPath, []byte(`{"token":"pre-caam"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vault.BackupOriginal(fileSet); err != nil {
+		t.Fatalf("BackupOriginal() error = %v", err)
+	}
+	if err := os.WriteFile(authPath, []byte(`{"token":"unsaved"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := run(false)
+	if err != nil || !output.Success {
+		t.Fatalf("runRestoreOriginal() = %+v, %v", output, err)
+	}
+	if !strings.HasPrefix(output.Backup, "_backup_") {
+		t.Errorf("Backup = %q, want the unsaved login backed up", output.Backup)
+	}
+	if got, _ := os.ReadFile(authPath); string(got) != `{"token":"pre-caam"}` {
+		t.Errorf("auth.json = %q, want the original login", got)
+	}
+	saved, err := os.ReadFile(filepath.Join(tmpDir, "vault", "codex", output.Backup, "auth.json"))
+	if err != nil || string(saved) != `{"token":"unsaved"}` {
+		t.Errorf("backup auth.json = %q, %v; want the replaced login", saved, err)
+	}
+
+	// Running it again is a no-op.
+	if again, err := run(false); err != nil || again.Previous != "_original" || again.Backup != "" {
+		t.Errorf("second run = %+v, %v; want nothing to do", again, err)
+	}
+
+	// --no-backup replaces an unsaved login without keeping it.
+	if err := os.WriteFile(authPath, []byte(`{"token":"throwaway"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	skipped, err := run(true)
+	if err != nil || !skipped.Success || skipped.Backup != "" {
+		t.Fatalf("--no-backup run = %+v, %v; want restored without a backup", skipped, err)
+	}
+	if got, _ := os.ReadFile(authPath); string(got) != `{"token":"pre-caam"}` {
+		t.Errorf("auth.json = %q, want the original login", got)
+	}
+	if backups, _ := filepath.Glob(filepath.Join(tmpDir, "vault", "codex", "_backup_*")); len(backups) != 1 {
+		t.Errorf("backups = %v, want only the first one", backups)
+	}
+}
+
+func TestLsOriginalNote(t *testing.T) {
+	rows := []lsRow{{name: "work"}}
+	if note := lsOriginalNote("codex", rows); note != "" {
+		t.Errorf("note without _original = %q", note)
+	}
+	rows = append(rows, lsRow{name: "_original"})
+	if note := lsOriginalNote("codex", rows); !strings.Contains(note, "caam restore-original codex") {
+		t.Errorf("note = %q, want the restore command", note)
+	}
+}
@@ -3,7 +3,9 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -14,9 +16,12 @@ import (
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/version"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // ============================================================================
@@ -24,13 +29,14 @@ import (
 // ============================================================================
 //
 // Designed for coding agents (Claude, Codex, etc.) that need programmatic access
-// to caam functionality. All output is JSON. No interactive prompts.
+// to caam functionality. All output is JSON. No interactive prompts, except
+// confirmation of destructive actions when a human is at a terminal.
 //
 // Design principles:
 // - JSON output by default (no --json flag needed)
 // - Structured errors with error_code field
 // - Actionable suggestions in output
-// - Exit codes: 0=success, 1=error, 2=partial success
+// - Stable exit codes (see exitcodes.go)
 // - Compact but complete information
 
 // RobotOutput is the standard response wrapper for all robot commands.
@@ -49,6 +55,9 @@ type RobotError struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 	Details string `json:"details,omitempty"`
+
+	// ExitCode is the process exit code caam returns for this error.
+	ExitCode int `json:"exit_code"`
 }
 
 // RobotTiming tracks execution time for performance monitoring.
@@ -98,6 +107,9 @@ type RobotProfileInfo struct {
 	PlanType       string            `json:"plan_type,omitempty"`
 	Health         RobotHealthInfo   `json:"health"`
 	Cooldown       *RobotCooldown    `json:"cooldown,omitempty"`
+	// Headroom is the estimated share of quota left in the tightest limit
+	// window (0 to 1), from recorded usage; absent without usage history.
+	Headroom       *float64          `json:"headroom,omitempty"`
 	Recommendation string            `json:"recommendation,omitempty"`
 }
 
@@ -105,8 +117,10 @@ type RobotProfileInfo struct {
 type RobotHealthInfo struct {
 	Status       string `json:"status"` // healthy, warning, critical, unknown
 	Reason       string `json:"reason,omitempty"`
-	ExpiresAt    string `json:"expires_at,omitempty"`
-	ExpiresIn    string `json:"expires_in,omitempty"` // human-readable
+	ExpiresAt      string `json:"expires_at,omitempty"` // RFC 3339 in display.timezone
+	ExpiresAtUTC   string `json:"expires_at_utc,omitempty"`
+	ExpiresDisplay string `json:"expires_display,omitempty"` // e.g. "2026-03-14 17:30 CET (16:30 UTC)"
+	ExpiresIn      string `json:"expires_in,omitempty"`      // human-readable
 	ErrorCount1h int    `json:"error_count_1h"`
 }
 
@@ -114,6 +128,8 @@ type RobotHealthInfo struct {
 type RobotCooldown struct {
 	Active       bool   `json:"active"`
 	Until        string `json:"until,omitempty"`
+	UntilUTC     string `json:"until_utc,omitempty"`
+	UntilDisplay string `json:"until_display,omitempty"`
 	RemainingMs  int64  `json:"remaining_ms,omitempty"`
 	RemainingStr string `json:"remaining_str,omitempty"`
 	Reason       string `json:"reason,omitempty"`
@@ -163,6 +179,13 @@ type RobotActResult struct {
 	OldProfile  string `json:"old_profile,omitempty"`
 	Success     bool   `json:"success"`
 	Message     string `json:"message"`
+	// Safety reports what activate backed up before switching.
+	Safety *authfile.ActivationSafety `json:"safety,omitempty"`
+	// IdempotencyKey is the --idempotency-key the action ran under.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// Replayed is set when the result is that of an earlier run with the
+	// same idempotency key; nothing was done this time.
+	Replayed bool `json:"replayed,omitempty"`
 }
 
 var robotCmd = &cobra.Command{
@@ -224,8 +247,25 @@ Supported actions:
   uncooldown <provider> <profile>  - Clear cooldown
   refresh <provider> <profile>  - Refresh token
   backup <provider> <profile>   - Backup current auth
+  unlock <provider> <profile>   - Remove a stale profile lock (--force for a live one)
+
+All actions return structured results with success/failure status.
+
+activate goes through the same safety steps as 'caam activate' (first-use
+_original backup, auto-backup per safety.auto_backup_before_switch) and
+reports them in a "safety" section.
+
+Pass --idempotency-key to make a retry safe: the first run under a key is
+recorded, and running the same action with the same key again (say, after a
+timeout) returns the recorded result with "replayed": true instead of
+activating twice or stacking cooldowns. A key still running reports
+ACTION_IN_PROGRESS; a key reused for a different action reports
+IDEMPOTENCY_KEY_REUSED. Failed actions aren't recorded, so they can be
+retried under the same key. Keys are remembered for 24 hours.
 
-All actions return structured results with success/failure status.`,
+When stdin is a terminal, destructive actions (activating over a login that
+nothing will back up, force-unlocking a live lock) ask for confirmation first; pass
+--yes to skip it. Without a terminal, actions never prompt.`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: runRobotAct,
 }
@@ -258,7 +298,21 @@ Each line is a complete JSON object with the current status.
 Updates are emitted on changes or at the poll interval.
 
 Use --interval to set poll interval (default 5s).
-Use --provider to filter to a specific provider.`,
+Use --provider to filter to a specific provider.
+
+With --socket, several agents share one poller: the first watcher serves
+events on the Unix socket and later ones (run with the same --socket)
+subscribe to it instead of polling the vault themselves. The serving
+watcher's --interval applies to everyone. Each subscriber has a bounded
+buffer; a subscriber that falls behind skips the oldest events (each one
+is a full status snapshot), and one that stops reading is disconnected.
+If the serving watcher exits, a subscriber takes over.
+
+Any client can also read the socket directly, e.g. 'nc -U <socket>'.
+
+Examples:
+  caam robot watch
+  caam robot watch --socket "$XDG_RUNTIME_DIR/caam.sock"`,
 	RunE: runRobotWatch,
 }
 
@@ -276,14 +330,15 @@ func robotError(cmd *cobra.Command, command string, code, message string, detail
 		Success: false,
 		Command: command,
 		Error: &RobotError{
-			Code:    code,
-			Message: message,
-			Details: details,
+			Code:     code,
+			Message:  message,
+			Details:  details,
+			ExitCode: robotExitCode(code),
 		},
 		Suggestions: suggestions,
 	}
 	robotOutput(cmd, output)
-	return fmt.Errorf("%s: %s", code, message)
+	return withExitCode(robotExitCode(code), fmt.Errorf("%s: %s", code, message))
 }
 
 func runRobotStatus(cmd *cobra.Command, args []string) error {
@@ -293,27 +348,51 @@ func runRobotStatus(cmd *cobra.Command, args []string) error {
 	includeCoords, _ := cmd.Flags().GetBool("include-coordinators")
 
 	// Determine which providers to check
-	providersToCheck := []string{"codex", "claude", "gemini"}
+	providersToCheck := knownTools()
 	if len(args) > 0 {
 		providerFilter = strings.ToLower(args[0])
 	}
 	if providerFilter != "" {
-		validProviders := map[string]bool{"codex": true, "claude": true, "gemini": true}
-		if !validProviders[providerFilter] {
+		if _, ok := tools[providerFilter]; !ok {
 			return robotError(cmd, "status", "INVALID_PROVIDER",
 				fmt.Sprintf("unknown provider: %s", providerFilter),
-				"valid providers: codex, claude, gemini",
+				"valid providers: "+strings.Join(knownTools(), ", "),
 				[]string{"caam robot status claude", "caam robot status codex", "caam robot status gemini"})
 		}
 		providersToCheck = []string{providerFilter}
 	}
 
+	data, suggestions := collectRobotStatus(providersToCheck, compact)
+
+	// Check coordinators if requested
+	if includeCoords {
+		data.Coordinators = checkCoordinators()
+	}
+
+	duration := time.Since(start)
+	output := RobotOutput{
+		Success:     true,
+		Command:     "status",
+		Data:        data,
+		Suggestions: suggestions,
+		Timing: &RobotTiming{
+			StartedAt:  start.UTC().Format(time.RFC3339),
+			DurationMs: duration.Milliseconds(),
+		},
+	}
+
+	return robotOutput(cmd, output)
+}
+
+// collectRobotStatus builds the status overview of providers on this
+// machine, with suggestions for the caller.
+func collectRobotStatus(providers []string, compact bool) (RobotStatusData, []string) {
 	data := RobotStatusData{
 		Version:   version.Version,
 		OS:        runtime.GOOS,
 		Arch:      runtime.GOARCH,
 		VaultPath: authfile.DefaultVaultPath(),
-		Providers: make([]RobotProviderInfo, 0, len(providersToCheck)),
+		Providers: make([]RobotProviderInfo, 0, len(providers)),
 	}
 
 	if configDir, err := os.UserConfigDir(); err == nil {
@@ -323,7 +402,7 @@ func runRobotStatus(cmd *cobra.Command, args []string) error {
 	var suggestions []string
 
 	var usableProfiles int
-	for _, tool := range providersToCheck {
+	for _, tool := range providers {
 		provInfo := buildProviderInfo(tool, compact)
 		data.Providers = append(data.Providers, provInfo)
 
@@ -367,24 +446,7 @@ func runRobotStatus(cmd *cobra.Command, args []string) error {
 		suggestions = append(suggestions, fmt.Sprintf("%d profile(s) expiring within 24h. Consider refreshing tokens.", data.Summary.ExpiringSoon))
 	}
 
-	// Check coordinators if requested
-	if includeCoords {
-		data.Coordinators = checkCoordinators()
-	}
-
-	duration := time.Since(start)
-	output := RobotOutput{
-		Success:     true,
-		Command:     "status",
-		Data:        data,
-		Suggestions: suggestions,
-		Timing: &RobotTiming{
-			StartedAt:  start.UTC().Format(time.RFC3339),
-			DurationMs: duration.Milliseconds(),
-		},
-	}
-
-	return robotOutput(cmd, output)
+	return data, suggestions
 }
 
 func buildProviderInfo(tool string, compact bool) RobotProviderInfo {
@@ -458,7 +520,8 @@ func buildProfileInfo(tool, profileName, activeProfile string, db *caamdb.DB, co
 
 	if !compact {
 		if !ph.TokenExpiresAt.IsZero() {
-			pInfo.Health.ExpiresAt = ph.TokenExpiresAt.Format(time.RFC3339)
+			h := &pInfo.Health
+			h.ExpiresAt, h.ExpiresAtUTC, h.ExpiresDisplay = zonedTimestamps(ph.TokenExpiresAt, displayLocation())
 			remaining := time.Until(ph.TokenExpiresAt)
 			if remaining > 0 {
 				pInfo.Health.ExpiresIn = robotFormatDuration(remaining)
@@ -492,15 +555,21 @@ func buildProfileInfo(tool, profileName, activeProfile string, db *caamdb.DB, co
 			if remaining > 0 {
 				pInfo.Cooldown = &RobotCooldown{
 					Active:       true,
-					Until:        cooldown.CooldownUntil.Format(time.RFC3339),
 					RemainingMs:  remaining.Milliseconds(),
 					RemainingStr: robotFormatDuration(remaining),
 					Reason:       cooldown.Notes,
 				}
+				pInfo.Cooldown.Until, pInfo.Cooldown.UntilUTC, pInfo.Cooldown.UntilDisplay = zonedTimestamps(cooldown.CooldownUntil, displayLocation())
 			}
 		}
 	}
 
+	if !compact {
+		if est, ok := estimateProfileHeadroom(db, tool, profileName, time.Now()); ok && est.Samples > 0 {
+			pInfo.Headroom = &est.Headroom
+		}
+	}
+
 	// Generate recommendation (unless compact)
 	if !compact {
 		pInfo.Recommendation = generateRecommendation(pInfo)
@@ -628,7 +697,7 @@ func runRobotNext(cmd *cobra.Command, args []string) error {
 	if _, ok := tools[provider]; !ok {
 		return robotError(cmd, "next", "INVALID_PROVIDER",
 			fmt.Sprintf("unknown provider: %s", provider),
-			"valid providers: codex, claude, gemini",
+			"valid providers: "+strings.Join(knownTools(), ", "),
 			nil)
 	}
 
@@ -661,89 +730,7 @@ func runRobotNext(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	// Score each profile
-	type scoredProfile struct {
-		name    string
-		score   float64
-		reasons []string
-		info    RobotProfileInfo
-	}
-
-	var scored []scoredProfile
-	now := time.Now()
-
-	for _, profileName := range profiles {
-		pInfo := buildProfileInfo(provider, profileName, "", db, false)
-
-		// Skip profiles in cooldown unless requested
-		if !includeCooldown && pInfo.Cooldown != nil && pInfo.Cooldown.Active {
-			continue
-		}
-
-		sp := scoredProfile{
-			name:    profileName,
-			info:    pInfo,
-			reasons: []string{},
-		}
-
-		// Calculate score (higher is better)
-		switch pInfo.Health.Status {
-		case "healthy":
-			sp.score += 100
-			sp.reasons = append(sp.reasons, "healthy status")
-		case "warning":
-			sp.score += 50
-			sp.reasons = append(sp.reasons, "warning status")
-		case "critical":
-			sp.score += 10
-			sp.reasons = append(sp.reasons, "critical status (not recommended)")
-		default:
-			sp.score += 30
-		}
-
-		// Cooldown penalty
-		if pInfo.Cooldown != nil && pInfo.Cooldown.Active {
-			sp.score -= 200
-			sp.reasons = append(sp.reasons, fmt.Sprintf("in cooldown (%s remaining)", pInfo.Cooldown.RemainingStr))
-		}
-
-		// Error penalty
-		if pInfo.Health.ErrorCount1h > 0 {
-			sp.score -= float64(pInfo.Health.ErrorCount1h * 10)
-			sp.reasons = append(sp.reasons, fmt.Sprintf("%d recent errors", pInfo.Health.ErrorCount1h))
-		}
-
-		// Token expiry consideration
-		if pInfo.Health.ExpiresAt != "" {
-			if exp, err := time.Parse(time.RFC3339, pInfo.Health.ExpiresAt); err == nil {
-				remaining := exp.Sub(now)
-				if remaining > 7*24*time.Hour {
-					sp.score += 20
-					sp.reasons = append(sp.reasons, "token valid for >7d")
-				} else if remaining > 24*time.Hour {
-					sp.score += 10
-					sp.reasons = append(sp.reasons, fmt.Sprintf("token expires in %s", robotFormatDuration(remaining)))
-				} else if remaining > 0 {
-					sp.score -= 20
-					sp.reasons = append(sp.reasons, fmt.Sprintf("token expiring soon (%s)", robotFormatDuration(remaining)))
-				} else {
-					sp.score -= 100
-					sp.reasons = append(sp.reasons, "token expired")
-				}
-			}
-		}
-
-		// LRU bonus (strategy-dependent)
-		if strategy == "lru" || strategy == "smart" {
-			// Could check last used time here
-			// For now, just slightly favor non-active profiles
-			if !pInfo.Active {
-				sp.score += 5
-			}
-		}
-
-		scored = append(scored, sp)
-	}
+	scored := rankRobotNext(db, provider, profiles, strategy, includeCooldown, time.Now())
 
 	if len(scored) == 0 {
 		suggestions := []string{
@@ -758,20 +745,11 @@ func runRobotNext(cmd *cobra.Command, args []string) error {
 			suggestions)
 	}
 
-	// Sort by score (descending)
-	for i := 0; i < len(scored)-1; i++ {
-		for j := i + 1; j < len(scored); j++ {
-			if scored[j].score > scored[i].score {
-				scored[i], scored[j] = scored[j], scored[i]
-			}
-		}
-	}
-
 	best := scored[0]
 	data := RobotNextData{
 		Provider: provider,
 		Profile:  best.name,
-		Score:    best.score,
+		Score:    best.score.Score,
 		Reasons:  best.reasons,
 		Command:  fmt.Sprintf("caam activate %s %s", provider, best.name),
 	}
@@ -782,7 +760,7 @@ func runRobotNext(cmd *cobra.Command, args []string) error {
 		data.AlternateChoice = &RobotNextProfile{
 			Provider: provider,
 			Profile:  alt.name,
-			Score:    alt.score,
+			Score:    alt.score.Score,
 			Reason:   strings.Join(alt.reasons, "; "),
 		}
 	}
@@ -801,15 +779,272 @@ func runRobotNext(cmd *cobra.Command, args []string) error {
 	return robotOutput(cmd, output)
 }
 
+// robotNextCandidate is a profile scored by 'caam robot next'.
+type robotNextCandidate struct {
+	name    string
+	score   rotation.ProfileScore
+	reasons []string
+	info    RobotProfileInfo
+}
+
+// rankRobotNext scores profiles for 'caam robot next' with the configured
+// weights, best first. A pinned profile comes first unless it is in
+// cooldown. Quarantined profiles are left out, and so are profiles in
+// cooldown unless includeCooldown is set.
+func rankRobotNext(db *caamdb.DB, provider string, profiles []string, strategy string, includeCooldown bool, now time.Time) []robotNextCandidate {
+	weights := config.DefaultScoringConfig()
+	var pinned string
+	quarantined := make(map[string]bool)
+	if c, err := config.Load(); err == nil {
+		weights = c.Scoring.ForProvider(provider)
+		pinned = c.GetPin(provider)
+		for _, p := range c.QuarantinedProfiles(provider) {
+			quarantined[p] = true
+		}
+	}
+
+	var scored []robotNextCandidate
+	for _, profileName := range profiles {
+		if quarantined[profileName] {
+			continue
+		}
+		pInfo := buildProfileInfo(provider, profileName, "", db, false)
+
+		// Skip profiles in cooldown unless requested
+		if !includeCooldown && pInfo.Cooldown != nil && pInfo.Cooldown.Active {
+			continue
+		}
+
+		score, reasons := robotNextScore(db, provider, profileName, pInfo, strategy, weights, now)
+		scored = append(scored, robotNextCandidate{
+			name:    profileName,
+			score:   score,
+			reasons: reasons,
+			info:    pInfo,
+		})
+	}
+
+	// Sort by score (descending)
+	for i := 0; i < len(scored)-1; i++ {
+		for j := i + 1; j < len(scored); j++ {
+			if scored[j].score.Score > scored[i].score.Score {
+				scored[i], scored[j] = scored[j], scored[i]
+			}
+		}
+	}
+
+	for i, sp := range scored {
+		if sp.name != pinned || (sp.info.Cooldown != nil && sp.info.Cooldown.Active) {
+			continue
+		}
+		sp.reasons = append([]string{"pinned"}, sp.reasons...)
+		copy(scored[1:i+1], scored[:i])
+		scored[0] = sp
+		break
+	}
+	return scored
+}
+
+// robotNextScore scores a profile for 'caam robot next' (higher is better)
+// with weights w, returning the score broken down by factor and the reasons
+// behind it.
+func robotNextScore(db *caamdb.DB, provider, profileName string, pInfo RobotProfileInfo, strategy string, w config.ScoringConfig, now time.Time) (rotation.ProfileScore, []string) {
+	score := rotation.ProfileScore{Name: profileName}
+	reasons := []string{}
+
+	switch pInfo.Health.Status {
+	case "healthy":
+		score.Add("health", w.Healthy)
+		reasons = append(reasons, "healthy status")
+	case "warning":
+		score.Add("health", w.Warning)
+		reasons = append(reasons, "warning status")
+	case "critical":
+		score.Add("health", w.Critical)
+		reasons = append(reasons, "critical status (not recommended)")
+	default:
+		score.Add("health", w.Unknown)
+	}
+
+	// Cooldown penalty
+	if pInfo.Cooldown != nil && pInfo.Cooldown.Active {
+		score.Add("cooldown", w.Cooldown)
+		reasons = append(reasons, fmt.Sprintf("in cooldown (%s remaining)", pInfo.Cooldown.RemainingStr))
+	}
+
+	// Error penalty
+	if pInfo.Health.ErrorCount1h > 0 {
+		score.Add("errors", float64(pInfo.Health.ErrorCount1h)*w.Error)
+		reasons = append(reasons, fmt.Sprintf("%d recent errors", pInfo.Health.ErrorCount1h))
+	}
+
+	// Token expiry consideration
+	if pInfo.Health.ExpiresAt != "" {
+		if exp, err := time.Parse(time.RFC3339, pInfo.Health.ExpiresAt); err == nil {
+			remaining := exp.Sub(now)
+			if remaining > 7*24*time.Hour {
+				score.Add("expiry", w.ExpiryOver7d)
+				reasons = append(reasons, "token valid for >7d")
+			} else if remaining > 24*time.Hour {
+				score.Add("expiry", w.ExpiryOver1d)
+				reasons = append(reasons, fmt.Sprintf("token expires in %s", robotFormatDuration(remaining)))
+			} else if remaining > 0 {
+				score.Add("expiry", w.ExpirySoon)
+				reasons = append(reasons, fmt.Sprintf("token expiring soon (%s)", robotFormatDuration(remaining)))
+			} else {
+				score.Add("expiry", w.Expired)
+				reasons = append(reasons, "token expired")
+			}
+		}
+	}
+
+	// Estimated limit headroom from recorded usage (see 'caam limits --estimate')
+	if est, ok := estimateProfileHeadroom(db, provider, profileName, now); ok && (est.Samples > 0 || est.Headroom < 1) {
+		switch {
+		case est.Headroom <= 0:
+			score.Add("headroom", w.HeadroomExhausted)
+			reasons = append(reasons, "estimated limit window exhausted")
+		case est.Headroom < 0.2:
+			score.Add("headroom", w.HeadroomLow)
+			reasons = append(reasons, fmt.Sprintf("low estimated headroom (%d%%)", int(est.Headroom*100)))
+		case est.Headroom >= 0.7:
+			score.Add("headroom", w.HeadroomHigh)
+			reasons = append(reasons, fmt.Sprintf("estimated headroom %d%%", int(est.Headroom*100)))
+		}
+	}
+
+	// LRU bonus (strategy-dependent): favor profiles idle the longest
+	if strategy == "lru" || strategy == "smart" {
+		weight := w.IdleSmart
+		if strategy == "lru" {
+			weight = w.IdleLRU
+		}
+		var lastUsed time.Time
+		if db != nil {
+			lastUsed, _ = db.LastUsed(provider, profileName)
+		}
+		if lastUsed.IsZero() {
+			score.Add("lru", weight)
+			reasons = append(reasons, "never used")
+		} else {
+			idle := now.Sub(lastUsed)
+			score.Add("lru", weight*math.Min(idle.Hours()/24, 1))
+			reasons = append(reasons, fmt.Sprintf("last used %s ago", robotFormatDuration(idle)))
+		}
+	}
+
+	return score, reasons
+}
+
 func runRobotAct(cmd *cobra.Command, args []string) error {
 	start := time.Now()
+	key, _ := cmd.Flags().GetString("idempotency-key")
+	key = strings.TrimSpace(key)
+
+	var db *caamdb.DB
+	if key != "" {
+		var err error
+		db, err = caamdb.Open()
+		if err != nil {
+			return robotError(cmd, "act", "DB_ERROR",
+				"failed to open database",
+				err.Error(),
+				nil)
+		}
+		defer db.Close()
+
+		prev, err := db.ClaimRobotAction(key, robotActRequest(args), start)
+		if errors.Is(err, caamdb.ErrIdempotencyKeyReused) {
+			return robotError(cmd, "act", "IDEMPOTENCY_KEY_REUSED",
+				"idempotency key was already used for a different action",
+				err.Error(),
+				[]string{"use a new --idempotency-key for each distinct action"})
+		}
+		if err != nil {
+			return robotError(cmd, "act", "DB_ERROR",
+				"failed to record idempotency key",
+				err.Error(),
+				nil)
+		}
+		if prev.Pending() {
+			return robotError(cmd, "act", "ACTION_IN_PROGRESS",
+				"an action with this idempotency key is still running",
+				fmt.Sprintf("claimed at %s", prev.CreatedAt.UTC().Format(time.RFC3339)),
+				[]string{"retry shortly with the same --idempotency-key to get its result"})
+		}
+		if prev != nil {
+			var result RobotActResult
+			if err := json.Unmarshal([]byte(prev.Result), &result); err != nil {
+				return robotError(cmd, "act", "DB_ERROR",
+					"failed to read recorded result",
+					err.Error(),
+					nil)
+			}
+			result.Replayed = true
+			return robotOutput(cmd, RobotOutput{
+				Success: result.Success,
+				Command: "act",
+				Data:    result,
+				Timing: &RobotTiming{
+					StartedAt:  start.UTC().Format(time.RFC3339),
+					DurationMs: time.Since(start).Milliseconds(),
+				},
+			})
+		}
+	}
+
+	result, err := robotAct(cmd, args)
+	if err != nil {
+		// A failed action can be retried under the same key.
+		if db != nil {
+			_ = db.ReleaseRobotAction(key)
+		}
+		return err
+	}
+	if db != nil {
+		result.IdempotencyKey = key
+		if data, err := json.Marshal(result); err == nil {
+			_ = db.CompleteRobotAction(key, string(data), time.Now())
+		}
+	}
+
+	duration := time.Since(start)
+	output := RobotOutput{
+		Success: result.Success,
+		Command: "act",
+		Data:    result,
+		Timing: &RobotTiming{
+			StartedAt:  start.UTC().Format(time.RFC3339),
+			DurationMs: duration.Milliseconds(),
+		},
+	}
+
+	return robotOutput(cmd, output)
+}
+
+// robotActRequest identifies a robot action for its idempotency key, e.g.
+// "activate claude work".
+func robotActRequest(args []string) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		if i < 2 {
+			a = strings.ToLower(a)
+		}
+		parts[i] = a
+	}
+	return strings.Join(parts, " ")
+}
+
+// robotAct performs a robot action. Errors are already written as robot
+// output.
+func robotAct(cmd *cobra.Command, args []string) (RobotActResult, error) {
 	action := strings.ToLower(args[0])
 	provider := strings.ToLower(args[1])
 
 	if _, ok := tools[provider]; !ok {
-		return robotError(cmd, "act", "INVALID_PROVIDER",
+		return RobotActResult{}, robotError(cmd, "act", "INVALID_PROVIDER",
 			fmt.Sprintf("unknown provider: %s", provider),
-			"valid providers: codex, claude, gemini",
+			"valid providers: "+strings.Join(knownTools(), ", "),
 			nil)
 	}
 
@@ -820,7 +1055,7 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 	switch action {
 	case "activate":
 		if len(args) < 3 {
-			return robotError(cmd, "act", "MISSING_PROFILE",
+			return RobotActResult{}, robotError(cmd, "act", "MISSING_PROFILE",
 				"profile name required for activate",
 				"usage: caam robot act activate <provider> <profile>",
 				nil)
@@ -834,20 +1069,40 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 			result.OldProfile = oldProfile
 		}
 
-		// Activate the profile
-		if err := vault.Restore(fileSet, profile); err != nil {
-			return robotError(cmd, "act", "ACTIVATE_FAILED",
+		spmCfg, err := config.LoadSPMConfig()
+		if err != nil {
+			spmCfg = config.DefaultSPMConfig()
+		}
+		opts := activateOptions(spmCfg)
+
+		// The activation backs up an unsaved login unless backups are off
+		// and the first-use `_original` backup is already taken.
+		if result.OldProfile == "" && authfile.HasAuthFiles(fileSet) && opts.BackupMode == authfile.BackupNever {
+			if hasOriginal, _ := vault.HasOriginalBackup(provider); hasOriginal {
+				ok, err := robotConfirm(cmd, fmt.Sprintf("The current %s login isn't backed up and will be overwritten", provider))
+				if err != nil || !ok {
+					return RobotActResult{}, robotCancelled(cmd, err)
+				}
+			}
+		}
+
+		// Activate the profile the same way 'caam activate' does
+		safety, err := vault.Activate(fileSet, profile, opts)
+		result.Safety = safety
+		if err != nil {
+			return RobotActResult{}, robotError(cmd, "act", "ACTIVATE_FAILED",
 				fmt.Sprintf("failed to activate %s/%s", provider, profile),
 				err.Error(),
 				[]string{fmt.Sprintf("caam robot status %s", provider)})
 		}
+		recordActivationMachine(provider, profile)
 
 		result.Success = true
 		result.Message = fmt.Sprintf("activated %s/%s", provider, profile)
 
 	case "cooldown":
 		if len(args) < 3 {
-			return robotError(cmd, "act", "MISSING_PROFILE",
+			return RobotActResult{}, robotError(cmd, "act", "MISSING_PROFILE",
 				"profile name required for cooldown",
 				"usage: caam robot act cooldown <provider> <profile> [duration]",
 				nil)
@@ -857,24 +1112,26 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 
 		duration := 4 * time.Hour // default
 		if len(args) >= 4 {
-			if d, err := time.ParseDuration(args[3]); err == nil {
+			if d, err := durations.ParseFrom(args[3], time.Now().In(displayLocation())); err == nil {
 				duration = d
 			}
 		}
 
 		db, err := caamdb.Open()
 		if err != nil {
-			return robotError(cmd, "act", "DB_ERROR",
+			return RobotActResult{}, robotError(cmd, "act", "DB_ERROR",
 				"failed to open database",
 				err.Error(),
 				nil)
 		}
 		defer db.Close()
 
+		syncAccountLinks(db, provider)
+
 		hitAt := time.Now()
 		cooldownEvent, err := db.SetCooldown(provider, profile, hitAt, duration, "manual via robot act")
 		if err != nil {
-			return robotError(cmd, "act", "COOLDOWN_FAILED",
+			return RobotActResult{}, robotError(cmd, "act", "COOLDOWN_FAILED",
 				"failed to set cooldown",
 				err.Error(),
 				nil)
@@ -885,7 +1142,7 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 
 	case "uncooldown":
 		if len(args) < 3 {
-			return robotError(cmd, "act", "MISSING_PROFILE",
+			return RobotActResult{}, robotError(cmd, "act", "MISSING_PROFILE",
 				"profile name required for uncooldown",
 				"usage: caam robot act uncooldown <provider> <profile>",
 				nil)
@@ -895,7 +1152,7 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 
 		db, err := caamdb.Open()
 		if err != nil {
-			return robotError(cmd, "act", "DB_ERROR",
+			return RobotActResult{}, robotError(cmd, "act", "DB_ERROR",
 				"failed to open database",
 				err.Error(),
 				nil)
@@ -903,7 +1160,7 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 		defer db.Close()
 
 		if _, err := db.ClearCooldown(provider, profile); err != nil {
-			return robotError(cmd, "act", "UNCOOLDOWN_FAILED",
+			return RobotActResult{}, robotError(cmd, "act", "UNCOOLDOWN_FAILED",
 				"failed to clear cooldown",
 				err.Error(),
 				nil)
@@ -915,7 +1172,7 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 	case "backup":
 		fileSet := tools[provider]()
 		if !authfile.HasAuthFiles(fileSet) {
-			return robotError(cmd, "act", "NO_AUTH",
+			return RobotActResult{}, robotError(cmd, "act", "NO_AUTH",
 				fmt.Sprintf("no auth files found for %s", provider),
 				"login first using the tool's login command",
 				nil)
@@ -928,7 +1185,7 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 		result.Profile = profile
 
 		if err := vault.Backup(fileSet, profile); err != nil {
-			return robotError(cmd, "act", "BACKUP_FAILED",
+			return RobotActResult{}, robotError(cmd, "act", "BACKUP_FAILED",
 				"backup failed",
 				err.Error(),
 				nil)
@@ -937,30 +1194,102 @@ func runRobotAct(cmd *cobra.Command, args []string) error {
 		result.Success = true
 		result.Message = fmt.Sprintf("backed up to %s/%s", provider, profile)
 
+	case "unlock":
+		if len(args) < 3 {
+			return RobotActResult{}, robotError(cmd, "act", "MISSING_PROFILE",
+				"profile name required for unlock",
+				"usage: caam robot act unlock <provider> <profile> [--force]",
+				nil)
+		}
+		name := args[2]
+		result.Profile = name
+
+		prof, err := profileStore.Load(provider, name)
+		if err != nil {
+			return RobotActResult{}, robotError(cmd, "act", "NO_PROFILES",
+				fmt.Sprintf("profile %s/%s not found", provider, name),
+				err.Error(),
+				nil)
+		}
+
+		if !prof.IsLocked() {
+			result.Success = true
+			result.Message = fmt.Sprintf("%s/%s is not locked", provider, name)
+			break
+		}
+
+		stale, err := prof.IsLockStale()
+		if err != nil {
+			return RobotActResult{}, robotError(cmd, "act", "UNLOCK_FAILED",
+				"failed to check lock status",
+				err.Error(),
+				nil)
+		}
+		if !stale {
+			force, _ := cmd.Flags().GetBool("force")
+			if !force {
+				return RobotActResult{}, robotError(cmd, "act", "LOCK_ACTIVE",
+					fmt.Sprintf("%s/%s is locked by a running process", provider, name),
+					"force-unlocking an active session can corrupt it",
+					[]string{fmt.Sprintf("caam robot act unlock %s %s --force", provider, name)})
+			}
+			ok, err := robotConfirm(cmd, fmt.Sprintf("%s/%s is locked by a running process; force-unlocking can corrupt that session", provider, name))
+			if err != nil || !ok {
+				return RobotActResult{}, robotCancelled(cmd, err)
+			}
+		}
+
+		if err := prof.Unlock(); err != nil {
+			return RobotActResult{}, robotError(cmd, "act", "UNLOCK_FAILED",
+				"unlock failed",
+				err.Error(),
+				nil)
+		}
+
+		result.Success = true
+		result.Message = fmt.Sprintf("unlocked %s/%s", provider, name)
+
 	default:
-		return robotError(cmd, "act", "INVALID_ACTION",
+		return RobotActResult{}, robotError(cmd, "act", "INVALID_ACTION",
 			fmt.Sprintf("unknown action: %s", action),
-			"valid actions: activate, cooldown, uncooldown, backup",
+			"valid actions: activate, cooldown, uncooldown, backup, unlock",
 			[]string{
 				"caam robot act activate <provider> <profile>",
 				"caam robot act cooldown <provider> <profile> [duration]",
 				"caam robot act uncooldown <provider> <profile>",
 				"caam robot act backup <provider> [profile]",
+				"caam robot act unlock <provider> <profile> [--force]",
 			})
 	}
 
-	duration := time.Since(start)
-	output := RobotOutput{
-		Success: result.Success,
-		Command: "act",
-		Data:    result,
-		Timing: &RobotTiming{
-			StartedAt:  start.UTC().Format(time.RFC3339),
-			DurationMs: duration.Milliseconds(),
-		},
+	return result, nil
+}
+
+// robotStdinIsTerminal reports whether robot commands may prompt a human.
+var robotStdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// robotConfirm asks for confirmation of a destructive action. It only prompts
+// when stdin is a terminal and --yes wasn't given; otherwise it proceeds, so
+// agents are never blocked. The prompt goes to stderr to keep stdout JSON.
+func robotConfirm(cmd *cobra.Command, reason string) (bool, error) {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes || !robotStdinIsTerminal() {
+		return true, nil
 	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "%s. ", reason)
+	return confirmProceed(cmd.InOrStdin(), cmd.ErrOrStderr())
+}
 
-	return robotOutput(cmd, output)
+func robotCancelled(cmd *cobra.Command, err error) error {
+	details := ""
+	if err != nil {
+		details = err.Error()
+	}
+	return robotError(cmd, "act", "CANCELLED",
+		"action cancelled at confirmation prompt",
+		details,
+		[]string{"re-run with --yes to skip confirmation"})
 }
 
 func runRobotHealth(cmd *cobra.Command, args []string) error {
@@ -1021,7 +1350,7 @@ func runRobotHealth(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check each provider
-	for _, tool := range []string{"codex", "claude", "gemini"} {
+	for _, tool := range knownTools() {
 		profiles, err := vault.List(tool)
 		if err != nil {
 			continue
@@ -1094,7 +1423,7 @@ func runRobotWatch(cmd *cobra.Command, args []string) error {
 		if _, ok := tools[providerFilter]; !ok {
 			return robotError(cmd, "watch", "INVALID_PROVIDER",
 				fmt.Sprintf("unknown provider: %s", providerFilter),
-				"valid providers: codex, claude, gemini",
+				"valid providers: "+strings.Join(knownTools(), ", "),
 				nil)
 		}
 	}
@@ -1103,11 +1432,22 @@ func runRobotWatch(cmd *cobra.Command, args []string) error {
 		interval = 1
 	}
 
+	ctx, finish, err := startLongRunning(cmd)
+	if err != nil {
+		return robotError(cmd, "watch", "LOCK_ACTIVE",
+			"failed to claim pid file", err.Error(),
+			[]string{"check whether another watcher is using the same --pidfile"})
+	}
+	defer finish()
+	cmd.SetContext(ctx)
+
+	if socketPath, _ := cmd.Flags().GetString("socket"); socketPath != "" {
+		return runRobotWatchSocket(cmd, socketPath, time.Duration(interval)*time.Second, providerFilter)
+	}
+
 	ticker := time.NewTicker(time.Duration(interval) * time.Second)
 	defer ticker.Stop()
 
-	ctx := cmd.Context()
-
 	// Emit initial status
 	if err := emitWatchStatus(cmd, providerFilter); err != nil {
 		return nil // Exit gracefully if we can't write output
@@ -1126,17 +1466,23 @@ func runRobotWatch(cmd *cobra.Command, args []string) error {
 }
 
 func emitWatchStatus(cmd *cobra.Command, providerFilter string) error {
-	providersToCheck := []string{"codex", "claude", "gemini"}
+	enc := json.NewEncoder(cmd.OutOrStdout())
+	return enc.Encode(buildWatchEvent(providerFilter))
+}
+
+// robotWatchEvent is one line of robot watch output.
+type robotWatchEvent struct {
+	Timestamp string              `json:"timestamp"`
+	Providers []RobotProviderInfo `json:"providers"`
+}
+
+func buildWatchEvent(providerFilter string) robotWatchEvent {
+	providersToCheck := knownTools()
 	if providerFilter != "" {
 		providersToCheck = []string{providerFilter}
 	}
 
-	type WatchEvent struct {
-		Timestamp string              `json:"timestamp"`
-		Providers []RobotProviderInfo `json:"providers"`
-	}
-
-	event := WatchEvent{
+	event := robotWatchEvent{
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 		Providers: make([]RobotProviderInfo, 0),
 	}
@@ -1144,9 +1490,7 @@ func emitWatchStatus(cmd *cobra.Command, providerFilter string) error {
 	for _, tool := range providersToCheck {
 		event.Providers = append(event.Providers, buildProviderInfo(tool, true))
 	}
-
-	enc := json.NewEncoder(cmd.OutOrStdout())
-	return enc.Encode(event)
+	return event
 }
 
 // ============================================================================
@@ -1205,7 +1549,7 @@ All commands return:
 ` + "```" + `
 
 ## Error Codes
-- INVALID_PROVIDER: Unknown provider (use: claude, codex, gemini)
+- INVALID_PROVIDER: Unknown provider (see caam providers)
 - NO_PROFILES: No profiles exist for provider
 - ALL_BLOCKED: All profiles in cooldown/unhealthy
 - MISSING_PROFILE: Profile name required
@@ -1323,6 +1667,11 @@ func init() {
 	robotStatusCmd.Flags().Bool("compact", false, "minimal output")
 	robotStatusCmd.Flags().Bool("include-coordinators", false, "check coordinator status")
 
+	// Act flags
+	robotActCmd.Flags().Bool("yes", false, "skip confirmation of destructive actions")
+	robotActCmd.Flags().Bool("force", false, "unlock: remove the lock even if its process is running")
+	robotActCmd.Flags().String("idempotency-key", "", "replay the recorded result if an action with this key already ran")
+
 	// Next flags
 	robotNextCmd.Flags().String("strategy", "smart", "selection strategy: smart, lru, random")
 	robotNextCmd.Flags().Bool("include-cooldown", false, "include profiles in cooldown")
@@ -1330,6 +1679,8 @@ func init() {
 	// Watch flags
 	robotWatchCmd.Flags().Int("interval", 5, "poll interval in seconds")
 	robotWatchCmd.Flags().String("provider", "", "filter to specific provider")
+	addPIDFileFlag(robotWatchCmd)
+	robotWatchCmd.Flags().String("socket", "", "share one poller between watchers over this Unix socket")
 
 	// Limits flags
 	robotLimitsCmd.Flags().Bool("forecast", false, "include depletion forecasts")
@@ -1377,7 +1728,7 @@ func runRobotLimits(cmd *cobra.Command, args []string) error {
 	if _, ok := tools[provider]; !ok {
 		return robotError(cmd, "limits", "INVALID_PROVIDER",
 			fmt.Sprintf("unknown provider: %s", provider),
-			"valid providers: codex, claude, gemini",
+			"valid providers: "+strings.Join(knownTools(), ", "),
 			nil)
 	}
 
@@ -1501,12 +1852,13 @@ type RobotPrecheckCommands struct {
 
 func runRobotPrecheck(cmd *cobra.Command, args []string) error {
 	start := time.Now()
+	loc := displayLocation()
 	provider := strings.ToLower(args[0])
 
 	if _, ok := tools[provider]; !ok {
 		return robotError(cmd, "precheck", "INVALID_PROVIDER",
 			fmt.Sprintf("unknown provider: %s", provider),
-			"valid providers: codex, claude, gemini",
+			"valid providers: "+strings.Join(knownTools(), ", "),
 			nil)
 	}
 
@@ -1559,7 +1911,7 @@ func runRobotPrecheck(cmd *cobra.Command, args []string) error {
 					data.InCooldown = append(data.InCooldown, RobotCooldownProfile{
 						Name:      profileName,
 						Remaining: robotFormatDuration(remaining),
-						Until:     ev.CooldownUntil.Format(time.RFC3339),
+						Until:     ev.CooldownUntil.In(loc).Format(time.RFC3339),
 					})
 					data.Summary.InCooldown++
 					continue
@@ -1667,9 +2019,11 @@ type RobotValidateResult struct {
 	Provider  string `json:"provider"`
 	Profile   string `json:"profile"`
 	Valid     bool   `json:"valid"`
-	ExpiresAt string `json:"expires_at,omitempty"`
-	ExpiresIn string `json:"expires_in,omitempty"`
-	Error     string `json:"error,omitempty"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+	ExpiresAtUTC   string `json:"expires_at_utc,omitempty"`
+	ExpiresDisplay string `json:"expires_display,omitempty"`
+	ExpiresIn      string `json:"expires_in,omitempty"`
+	Error          string `json:"error,omitempty"`
 }
 
 // RobotValidateSummary contains validation summary.
@@ -1681,6 +2035,7 @@ type RobotValidateSummary struct {
 
 func runRobotValidate(cmd *cobra.Command, args []string) error {
 	start := time.Now()
+	loc := displayLocation()
 
 	var providersToCheck []string
 	var profileFilter string
@@ -1690,7 +2045,7 @@ func runRobotValidate(cmd *cobra.Command, args []string) error {
 		if _, ok := tools[provider]; !ok {
 			return robotError(cmd, "validate", "INVALID_PROVIDER",
 				fmt.Sprintf("unknown provider: %s", provider),
-				"valid providers: codex, claude, gemini",
+				"valid providers: "+strings.Join(knownTools(), ", "),
 				nil)
 		}
 		providersToCheck = []string{provider}
@@ -1698,7 +2053,7 @@ func runRobotValidate(cmd *cobra.Command, args []string) error {
 			profileFilter = args[1]
 		}
 	} else {
-		providersToCheck = []string{"codex", "claude", "gemini"}
+		providersToCheck = knownTools()
 	}
 
 	data := RobotValidateData{
@@ -1728,7 +2083,7 @@ func runRobotValidate(cmd *cobra.Command, args []string) error {
 			// Get health info for token expiry
 			ph, _ := getProfileHealthWithIdentity(provider, profileName)
 			if !ph.TokenExpiresAt.IsZero() {
-				result.ExpiresAt = ph.TokenExpiresAt.Format(time.RFC3339)
+				result.ExpiresAt, result.ExpiresAtUTC, result.ExpiresDisplay = zonedTimestamps(ph.TokenExpiresAt, loc)
 				remaining := time.Until(ph.TokenExpiresAt)
 				if remaining > 0 {
 					result.ExpiresIn = robotFormatDuration(remaining)
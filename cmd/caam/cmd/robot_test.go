@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+)
+
+func newRobotActTestCmd(t *testing.T, stdin string, tty bool) (*cobra.Command, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	oldTTY := robotStdinIsTerminal
+	robotStdinIsTerminal = func() bool { return tty }
+	t.Cleanup(func() { robotStdinIsTerminal = oldTTY })
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("yes", false, "")
+	cmd.Flags().Bool("force", false, "")
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader(stdin))
+	return cmd, &stdout, &stderr
+}
+
+func TestRobotConfirm(t *testing.T) {
+	t.Run("no terminal never prompts", func(t *testing.T) {
+		cmd, _, stderr := newRobotActTestCmd(t, "", false)
+		ok, err := robotConfirm(cmd, "danger")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, stderr.String())
+	})
+
+	t.Run("yes skips the prompt", func(t *testing.T) {
+		cmd, _, stderr := newRobotActTestCmd(t, "", true)
+		require.NoError(t, cmd.Flags().Set("yes", "true"))
+		ok, err := robotConfirm(cmd, "danger")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, stderr.String())
+	})
+
+	t.Run("terminal asks on stderr", func(t *testing.T) {
+		cmd, stdout, stderr := newRobotActTestCmd(t, "n\n", true)
+		ok, err := robotConfirm(cmd, "danger")
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Contains(t, stderr.String(), "danger. Proceed anyway? [y/N]")
+		assert.Empty(t, stdout.String(), "prompt must not corrupt JSON output")
+	})
+
+	t.Run("terminal accepts y", func(t *testing.T) {
+		cmd, _, _ := newRobotActTestCmd(t, "y\n", true)
+		ok, err := robotConfirm(cmd, "danger")
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestRobotActUnlock_LiveLock(t *testing.T) {
+	oldStore := profileStore
+	profileStore = profile.NewStore(filepath.Join(t.TempDir(), "profiles"))
+	t.Cleanup(func() { profileStore = oldStore })
+
+	prof, err := profileStore.Create("codex", "work", "oauth")
+	require.NoError(t, err)
+	// Locked by this (running) test process, so the lock isn't stale.
+	require.NoError(t, prof.Lock())
+	t.Cleanup(func() { _ = prof.Unlock() })
+
+	// Without --force the live lock is left alone.
+	cmd, stdout, _ := newRobotActTestCmd(t, "", true)
+	err = runRobotAct(cmd, []string{"unlock", "codex", "work"})
+	require.Error(t, err)
+	assert.Equal(t, ExitLockContention, ExitCode(err))
+	assert.Contains(t, stdout.String(), "LOCK_ACTIVE")
+
+	// --force at a terminal asks first; declining keeps the lock.
+	cmd, stdout, _ = newRobotActTestCmd(t, "n\n", true)
+	require.NoError(t, cmd.Flags().Set("force", "true"))
+	err = runRobotAct(cmd, []string{"unlock", "codex", "work"})
+	require.Error(t, err)
+	assert.Contains(t, stdout.String(), "CANCELLED")
+	assert.True(t, prof.IsLocked())
+
+	// --force without a terminal proceeds.
+	cmd, stdout, _ = newRobotActTestCmd(t, "", false)
+	require.NoError(t, cmd.Flags().Set("force", "true"))
+	require.NoError(t, runRobotAct(cmd, []string{"unlock", "codex", "work"}))
+	assert.False(t, prof.IsLocked())
+
+	var out RobotOutput
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	assert.True(t, out.Success)
+}
+
+func TestRobotActActivate_ConfirmsOverwritingUnsavedLogin(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	require.NoError(t, os.MkdirAll(os.Getenv("CODEX_HOME"), 0700))
+	authPath := filepath.Join(os.Getenv("CODEX_HOME"), "auth.json")
+	unsaved := []byte(`{"access_token":"unsaved","token_type":"Bearer"}`)
+	require.NoError(t, os.WriteFile(authPath, unsaved, 0600))
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+	require.NoError(t, os.MkdirAll(vault.ProfilePath("codex", "work"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(vault.ProfilePath("codex", "work"), "auth.json"),
+		[]byte(`{"access_token":"work","token_type":"Bearer"}`), 0600))
+
+	// Only with backups off and _original taken is the login at risk.
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+	require.NoError(t, os.MkdirAll(os.Getenv("CAAM_HOME"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(os.Getenv("CAAM_HOME"), "config.yaml"),
+		[]byte("version: 1\nsafety:\n  auto_backup_before_switch: never\n"), 0600))
+	require.NoError(t, os.MkdirAll(vault.ProfilePath("codex", "_original"), 0700))
+
+	cmd, stdout, stderr := newRobotActTestCmd(t, "n\n", true)
+	err := runRobotAct(cmd, []string{"activate", "codex", "work"})
+	require.Error(t, err)
+	assert.Contains(t, stderr.String(), "isn't backed up")
+	assert.Contains(t, stdout.String(), "CANCELLED")
+
+	got, err := os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Equal(t, unsaved, got, "declined activation must leave the login in place")
+
+	cmd, _, _ = newRobotActTestCmd(t, "y\n", true)
+	require.NoError(t, runRobotAct(cmd, []string{"activate", "codex", "work"}))
+	got, err = os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `"work"`)
+}
+
+func TestRobotActActivate_BacksUpUnsavedLogin(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	require.NoError(t, os.MkdirAll(os.Getenv("CODEX_HOME"), 0700))
+	authPath := filepath.Join(os.Getenv("CODEX_HOME"), "auth.json")
+	unsaved := []byte(`{"access_token":"unsaved","token_type":"Bearer"}`)
+	require.NoError(t, os.WriteFile(authPath, unsaved, 0600))
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+	require.NoError(t, os.MkdirAll(vault.ProfilePath("codex", "work"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(vault.ProfilePath("codex", "work"), "auth.json"),
+		[]byte(`{"access_token":"work","token_type":"Bearer"}`), 0600))
+
+	// No prompt: the first activation keeps the login as _original.
+	cmd, stdout, _ := newRobotActTestCmd(t, "", true)
+	require.NoError(t, runRobotAct(cmd, []string{"activate", "codex", "work"}))
+
+	var out struct {
+		Success bool           `json:"success"`
+		Data    RobotActResult `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+	require.True(t, out.Success)
+	require.NotNil(t, out.Data.Safety)
+	assert.Equal(t, "_original", out.Data.Safety.OriginalBackup)
+	assert.Equal(t, authfile.BackupSmart, out.Data.Safety.BackupMode)
+
+	saved, err := os.ReadFile(vault.BackupPath("codex", "_original", "auth.json"))
+	require.NoError(t, err)
+	assert.Equal(t, unsaved, saved)
+	got, err := os.ReadFile(authPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(got), `"work"`)
+}
+
+func TestRobotNext_LRUFavorsLeastRecentlyUsed(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	oldHealthStore := healthStore
+	healthStore = nil
+	t.Cleanup(func() { healthStore = oldHealthStore })
+
+	for _, name := range []string{"idle", "recent"} {
+		dir := vault.ProfilePath("codex", name)
+		require.NoError(t, os.MkdirAll(dir, 0700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"account":"`+name+`"}`), 0600))
+	}
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	now := time.Now()
+	require.NoError(t, db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "codex", ProfileName: "idle", Timestamp: now.Add(-72 * time.Hour)}))
+	require.NoError(t, db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "codex", ProfileName: "recent", Timestamp: now.Add(-72 * time.Hour)}))
+	require.NoError(t, db.RecordWrapSession(caamdb.WrapSession{Provider: "codex", ProfileName: "recent", StartedAt: now.Add(-20 * time.Minute), EndedAt: now.Add(-10 * time.Minute)}))
+	db.Close()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().String("strategy", "lru", "")
+	cmd.Flags().Bool("include-cooldown", false, "")
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+	require.NoError(t, runRobotNext(cmd, []string{"codex"}))
+
+	var out struct {
+		Data RobotNextData `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &out), stdout.String())
+	assert.Equal(t, "idle", out.Data.Profile)
+	assert.Contains(t, strings.Join(out.Data.Reasons, ","), "last used 3d ago")
+}
+
+func TestRobotAct_IdempotencyKeyReplaysResult(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+
+	run := func(key string, args ...string) (RobotOutput, error) {
+		t.Helper()
+		cmd, stdout, _ := newRobotActTestCmd(t, "", false)
+		cmd.Flags().String("idempotency-key", "", "")
+		require.NoError(t, cmd.Flags().Set("idempotency-key", key))
+		err := runRobotAct(cmd, args)
+		var out RobotOutput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &out))
+		return out, err
+	}
+
+	out, err := run("retry-1", "cooldown", "codex", "work", "1h")
+	require.NoError(t, err)
+	require.True(t, out.Success)
+
+	// The retry replays instead of stacking a second cooldown.
+	out, err = run("retry-1", "cooldown", "codex", "work", "1h")
+	require.NoError(t, err)
+	data, _ := json.Marshal(out.Data)
+	var result RobotActResult
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.True(t, result.Replayed)
+	assert.Equal(t, "retry-1", result.IdempotencyKey)
+
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	defer db.Close()
+	events, err := db.ListActiveCooldowns(time.Now())
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	// Reusing the key for something else is refused.
+	_, err = run("retry-1", "uncooldown", "codex", "work")
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// watchSubscriberBuffer is how many events a subscriber may fall
+	// behind before the oldest are skipped.
+	watchSubscriberBuffer = 16
+	// watchWriteTimeout is how long a write to a subscriber may block before
+	// the subscriber is considered gone.
+	watchWriteTimeout = 10 * time.Second
+)
+
+// runRobotWatchSocket streams watch events through the socket at path: it
+// subscribes when another watcher already serves there and becomes the
+// server otherwise, taking over when the serving watcher goes away.
+func runRobotWatchSocket(cmd *cobra.Command, path string, interval time.Duration, providerFilter string) error {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	out := cmd.OutOrStdout()
+
+	for ctx.Err() == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			if err := relayWatchEvents(ctx, conn, out, providerFilter); err != nil {
+				return nil // Exit gracefully if stdout is closed
+			}
+			continue // The server went away; take over or find the new one.
+		}
+
+		served, err := serveRobotWatch(ctx, path, interval, out, providerFilter)
+		if err != nil {
+			return robotError(cmd, "watch", "SOCKET_ERROR",
+				"failed to serve watch socket", err.Error(),
+				[]string{"check that the socket directory is writable"})
+		}
+		if served {
+			return nil
+		}
+	}
+	return nil
+}
+
+// relayWatchEvents copies events from the server to out until the server
+// closes the connection (nil) or out fails (error).
+func relayWatchEvents(ctx context.Context, conn net.Conn, out io.Writer, providerFilter string) error {
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := filterWatchLine(scanner.Bytes(), providerFilter)
+		if _, err := out.Write(append(line, '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterWatchLine narrows a served event to one provider. Events are always
+// served unfiltered so subscribers with different filters can share them.
+func filterWatchLine(line []byte, providerFilter string) []byte {
+	if providerFilter == "" {
+		return line
+	}
+	var event robotWatchEvent
+	if err := json.Unmarshal(line, &event); err != nil {
+		return line
+	}
+	kept := make([]RobotProviderInfo, 0, 1)
+	for _, p := range event.Providers {
+		if p.ID == providerFilter {
+			kept = append(kept, p)
+		}
+	}
+	event.Providers = kept
+	filtered, err := json.Marshal(event)
+	if err != nil {
+		return line
+	}
+	return filtered
+}
+
+// serveRobotWatch polls and publishes events on the socket at path until ctx
+// ends or out fails. It reports served=false, without error, when another
+// watcher became the server first.
+func serveRobotWatch(ctx context.Context, path string, interval time.Duration, out io.Writer, providerFilter string) (served bool, err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return false, fmt.Errorf("create socket dir: %w", err)
+	}
+
+	// Watchers racing to take over serialize on the lock; the winner holds
+	// it while serving, so a leftover socket file with no lock holder is
+	// safe to replace.
+	lockFile, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return false, fmt.Errorf("open socket lock: %w", err)
+	}
+	defer lockFile.Close()
+	if err := health.LockFile(lockFile); err != nil {
+		return false, fmt.Errorf("lock socket: %w", err)
+	}
+	defer func() { _ = health.UnlockFile(lockFile) }()
+
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return false, fmt.Errorf("listen: %w", err)
+	}
+	defer os.Remove(path)
+	defer listener.Close()
+	if err := os.Chmod(path, 0600); err != nil {
+		return false, fmt.Errorf("chmod socket: %w", err)
+	}
+
+	hub := newWatchHub()
+	defer hub.closeAll()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			hub.add(conn)
+		}
+	}()
+
+	publish := func() error {
+		event, err := json.Marshal(buildWatchEvent(""))
+		if err != nil {
+			return err
+		}
+		line := append(event, '\n')
+		hub.publish(line)
+		if providerFilter != "" {
+			// Subscribers share line, so the filtered copy is built anew.
+			line = append(filterWatchLine(event, providerFilter), '\n')
+		}
+		_, err = out.Write(line)
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	if err := publish(); err != nil {
+		return true, nil // Exit gracefully if stdout is closed
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case <-ticker.C:
+			if err := publish(); err != nil {
+				return true, nil
+			}
+		}
+	}
+}
+
+// watchHub fans events out to socket subscribers.
+type watchHub struct {
+	mu   sync.Mutex
+	subs map[*watchSubscriber]struct{}
+	last []byte
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{subs: make(map[*watchSubscriber]struct{})}
+}
+
+// watchSubscriber is one connected client with its own bounded queue, so a
+// slow reader never holds up the poller or the other subscribers.
+type watchSubscriber struct {
+	conn    net.Conn
+	queue   chan []byte
+	dropped int
+	once    sync.Once
+}
+
+// add registers conn and sends it the latest event right away.
+func (h *watchHub) add(conn net.Conn) {
+	s := &watchSubscriber{conn: conn, queue: make(chan []byte, watchSubscriberBuffer)}
+
+	h.mu.Lock()
+	h.subs[s] = struct{}{}
+	if h.last != nil {
+		s.offer(h.last)
+	}
+	h.mu.Unlock()
+
+	go func() {
+		for line := range s.queue {
+			_ = conn.SetWriteDeadline(time.Now().Add(watchWriteTimeout))
+			if _, err := conn.Write(line); err != nil {
+				h.remove(s)
+				return
+			}
+		}
+	}()
+	// Subscribers don't send anything; reading only notices when they hang
+	// up.
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		h.remove(s)
+	}()
+}
+
+// publish queues line for every subscriber.
+func (h *watchHub) publish(line []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.last = line
+	for s := range h.subs {
+		s.offer(line)
+	}
+}
+
+// offer queues line, skipping the oldest queued events when the subscriber
+// has fallen a full buffer behind. Callers hold the hub lock.
+func (s *watchSubscriber) offer(line []byte) {
+	for {
+		select {
+		case s.queue <- line:
+			return
+		default:
+		}
+		select {
+		case <-s.queue:
+			s.dropped++
+		default:
+		}
+	}
+}
+
+func (h *watchHub) remove(s *watchSubscriber) {
+	s.once.Do(func() {
+		h.mu.Lock()
+		delete(h.subs, s)
+		close(s.queue)
+		h.mu.Unlock()
+		_ = s.conn.Close()
+	})
+}
+
+func (h *watchHub) closeAll() {
+	h.mu.Lock()
+	subs := make([]*watchSubscriber, 0, len(h.subs))
+	for s := range h.subs {
+		subs = append(subs, s)
+	}
+	h.mu.Unlock()
+	for _, s := range subs {
+		h.remove(s)
+	}
+}
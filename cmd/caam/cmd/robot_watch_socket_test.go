@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// lockedBuffer is a bytes.Buffer safe for a watcher goroutine to write while
+// the test reads.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+func startSocketWatcher(t *testing.T, path, provider string) (*lockedBuffer, context.CancelFunc, <-chan struct{}) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := &cobra.Command{}
+	cmd.SetContext(ctx)
+	out := &lockedBuffer{}
+	cmd.SetOut(out)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = runRobotWatchSocket(cmd, path, 50*time.Millisecond, provider)
+	}()
+	return out, cancel, done
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRobotWatchSocket_SharesOnePollerAndTakesOver(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	path := filepath.Join(t.TempDir(), "w.sock")
+
+	serverOut, stopServer, serverDone := startSocketWatcher(t, path, "")
+	defer stopServer()
+	waitFor(t, "server socket", func() bool {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil
+	})
+
+	// A raw client gets full events straight away.
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	conn.Close()
+	if err != nil {
+		t.Fatalf("read event: %v", err)
+	}
+	var event robotWatchEvent
+	if err := json.Unmarshal(line, &event); err != nil || len(event.Providers) != 3 {
+		t.Fatalf("event = %s (err %v), want all three providers", line, err)
+	}
+
+	// A filtered watcher subscribes instead of polling.
+	subOut, stopSub, subDone := startSocketWatcher(t, path, "claude")
+	defer stopSub()
+	waitFor(t, "subscriber output", func() bool { return subOut.lines()[0] != "" })
+	if err := json.Unmarshal([]byte(subOut.lines()[0]), &event); err != nil || len(event.Providers) != 1 || event.Providers[0].ID != "claude" {
+		t.Fatalf("subscriber event = %s (err %v), want claude only", subOut.lines()[0], err)
+	}
+	if len(serverOut.lines()) == 0 {
+		t.Fatal("server should also print events")
+	}
+
+	// When the server exits the subscriber takes over the socket.
+	stopServer()
+	<-serverDone
+	seen := len(subOut.lines())
+	waitFor(t, "takeover", func() bool {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			conn.Close()
+		}
+		return err == nil && len(subOut.lines()) > seen+1
+	})
+
+	stopSub()
+	<-subDone
+}
+
+func TestWatchSubscriber_OfferDropsOldest(t *testing.T) {
+	s := &watchSubscriber{queue: make(chan []byte, watchSubscriberBuffer)}
+	total := watchSubscriberBuffer + 4
+	for i := 0; i < total; i++ {
+		s.offer([]byte{byte(i)})
+	}
+
+	if s.dropped != 4 {
+		t.Errorf("dropped = %d, want 4", s.dropped)
+	}
+	if len(s.queue) != watchSubscriberBuffer {
+		t.Fatalf("queue len = %d, want %d", len(s.queue), watchSubscriberBuffer)
+	}
+	if first := <-s.queue; first[0] != 4 {
+		t.Errorf("oldest queued = %d, want 4", first[0])
+	}
+}
@@ -12,14 +12,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/devicecode"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/exec"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
@@ -56,6 +61,7 @@ var tools = map[string]func() authfile.AuthFileSet{
 }
 
 // getDB returns the global database connection, initializing it if necessary.
+// A database that couldn't be opened isn't retried for the same path.
 func getDB() (*caamdb.DB, error) {
 	targetPath := filepath.Clean(caamdb.DefaultPath())
 	if globalDB != nil {
@@ -66,11 +72,39 @@ func getDB() (*caamdb.DB, error) {
 		globalDB.Close()
 		globalDB = nil
 	}
+	if globalDBErr != nil && globalDBErrPath == targetPath {
+		return nil, globalDBErr
+	}
 	var err error
 	globalDB, err = caamdb.Open()
+	globalDBErr, globalDBErrPath = err, targetPath
 	return globalDB, err
 }
 
+var (
+	globalDBErr     error
+	globalDBErrPath string
+
+	// dbWarnOnce makes the database warning appear once per run, however
+	// many commands and helpers try to open it.
+	dbWarnOnce sync.Once
+	dbWarnOut  io.Writer = os.Stderr
+)
+
+func init() {
+	caamdb.SetUnavailableHandler(warnDBUnavailable)
+}
+
+// warnDBUnavailable tells the user, once, that caam is running without its
+// database and what that turns off. It goes to stderr so JSON output stays
+// parseable.
+func warnDBUnavailable(err error) {
+	dbWarnOnce.Do(func() {
+		fmt.Fprintf(dbWarnOut, "warning: %v\n", err)
+		fmt.Fprintln(dbWarnOut, "  Cooldowns, usage history and analytics are off for this run; backup, activate and ls work normally.")
+	})
+}
+
 // rootCmd represents the base command.
 var rootCmd = &cobra.Command{
 	Use:   "caam",
@@ -97,18 +131,27 @@ Advanced: Profile isolation for simultaneous sessions:
   caam login codex work
   caam exec codex work -- "implement feature X"
 
-Run 'caam' without arguments to launch the interactive TUI.`,
+Run 'caam' without arguments to launch the interactive TUI. 'caam <n>'
+activates profile number n (as shown by 'caam ls') of the default tool.`,
+	Args: rootArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			n, _ := parseProfileIndex(args[0])
+			return runQuickActivate(cmd, n)
+		}
 		// If called with no subcommand, launch TUI
-		return tui.Run()
+		return tui.Run(activeContextName)
 	},
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		applyQuiet(cmd)
+
 		if _, err := config.MigrateDataToCAAMHome(); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: data migration skipped: %v\n", err)
 		}
 
 		// Initialize vault
 		vault = authfile.NewVault(authfile.DefaultVaultPath())
+		authfile.ApplyVaultSettings(vault)
 
 		// Initialize profile store
 		profileStore = profile.NewStore(profile.DefaultStorePath())
@@ -124,6 +167,7 @@ Run 'caam' without arguments to launch the interactive TUI.`,
 		registry.Register(codex.New())
 		registry.Register(claude.New())
 		registry.Register(gemini.New())
+		registerPlugins()
 
 		// Initialize runner
 		runner = exec.NewRunner(registry)
@@ -134,6 +178,18 @@ Run 'caam' without arguments to launch the interactive TUI.`,
 		if err != nil {
 			return fmt.Errorf("load config: %w", err)
 		}
+		if err := applyConfigContext(cmd, cfg); err != nil {
+			return err
+		}
+		// The shared vault is expected to belong to its administrator.
+		system := applySystemVault(cmd)
+		if !quietOutput(cmd) {
+			owned := []string{caamdb.DefaultPath()}
+			if !system {
+				owned = append(owned, vault.BasePath())
+			}
+			warnForeignOwnership(os.Stderr, owned...)
+		}
 
 		// Show token expiry warnings (skip for certain commands)
 		if shouldShowWarnings(cmd) {
@@ -151,7 +207,14 @@ Run 'caam' without arguments to launch the interactive TUI.`,
 
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	pendingExitCode = ExitOK
+	if err := rootCmd.Execute(); err != nil {
+		return err
+	}
+	if pendingExitCode != ExitOK {
+		return withExitCode(pendingExitCode, errReportedFailure)
+	}
+	return nil
 }
 
 // shouldShowWarnings returns true if the current command should display token warnings.
@@ -174,6 +237,15 @@ func shouldShowWarnings(cmd *cobra.Command) bool {
 		return false
 	}
 
+	// Shell hooks run on every prompt and directory change.
+	if cmd.HasParent() && cmd.Parent().Name() == "hook" {
+		return false
+	}
+
+	if quietOutput(cmd) {
+		return false
+	}
+
 	// Skip if --json flag is set (would corrupt JSON output)
 	if jsonFlag := cmd.Flags().Lookup("json"); jsonFlag != nil {
 		if jsonFlag.Value.String() == "true" {
@@ -189,6 +261,59 @@ func shouldShowWarnings(cmd *cobra.Command) bool {
 	return true
 }
 
+// assumeYes reports whether --yes was given, either the global flag or a
+// command's own.
+func assumeYes(cmd *cobra.Command) bool {
+	yes, _ := cmd.Flags().GetBool("yes")
+	return yes
+}
+
+// quietOutput reports whether the global --quiet flag applies to cmd.
+// Commands with their own --quiet flag handle it themselves.
+func quietOutput(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("quiet")
+	if flag == nil || flag != cmd.Root().PersistentFlags().Lookup("quiet") {
+		return false
+	}
+	return flag.Value.String() == "true"
+}
+
+// applyQuiet discards stdout under --quiet, leaving stderr for errors.
+// Commands whose stdout is their product (JSON, a wrapped tool's output,
+// generated scripts and exports) are left alone.
+func applyQuiet(cmd *cobra.Command) {
+	if !quietOutput(cmd) {
+		return
+	}
+	cmd.SilenceUsage = true
+	if jsonFlag := cmd.Flags().Lookup("json"); jsonFlag != nil && jsonFlag.Value.String() == "true" {
+		return
+	}
+	keepStdout := map[string]bool{
+		"exec":                 true,
+		"run":                  true,
+		"env":                  true,
+		"export":               true,
+		"completion":           true,
+		"shell":                true,
+		"hook":                 true,
+		"robot":                true,
+		exec.BindHelperCommand: true,
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if keepStdout[c.Name()] {
+			return
+		}
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	os.Stdout = devNull
+	cmd.SetOut(io.Discard)
+}
+
 // showTokenWarnings checks for expiring tokens and prints warnings to stderr.
 func showTokenWarnings(ctx context.Context) {
 	if vault == nil || registry == nil {
@@ -229,11 +354,14 @@ func buildProfileHealth(tool, profileName string) *health.ProfileHealth {
 	}
 
 	// Get auth files from vault profile
-	vaultPath := vault.ProfilePath(tool, profileName)
+	vaultPath, cleanup, err := vault.PlainProfileDir(tool, profileName)
+	if err != nil {
+		return ph
+	}
+	defer cleanup()
 
 	// Try to parse expiry based on tool type
 	var expInfo *health.ExpiryInfo
-	var err error
 
 	switch tool {
 	case "claude":
@@ -265,30 +393,37 @@ func getVaultIdentity(tool, profileName string) *identity.Identity {
 	if vault == nil {
 		return nil
 	}
-	vaultPath := vault.ProfilePath(tool, profileName)
+	vaultPath, cleanup, err := vault.PlainProfileDir(tool, profileName)
+	if err != nil {
+		return nil
+	}
+	defer cleanup()
+	return identityIn(tool, func(name string) string {
+		return filepath.Join(vaultPath, name)
+	})
+}
 
+// identityIn extracts the account identity from tool's auth files, locating
+// each file by name with path.
+func identityIn(tool string, path func(name string) string) *identity.Identity {
 	switch tool {
 	case "codex":
-		id, err := identity.ExtractFromCodexAuth(filepath.Join(vaultPath, "auth.json"))
+		id, err := identity.ExtractFromCodexAuth(path("auth.json"))
 		if err != nil {
 			return nil
 		}
 		normalizeIdentityPlan(id)
 		return id
 	case "claude":
-		id, err := identity.ExtractFromClaudeCredentials(filepath.Join(vaultPath, ".credentials.json"))
+		id, err := identity.ExtractFromClaudeCredentials(path(".credentials.json"))
 		if err != nil {
 			return nil
 		}
 		normalizeIdentityPlan(id)
 		return id
 	case "gemini":
-		candidates := []string{
-			filepath.Join(vaultPath, "settings.json"),
-			filepath.Join(vaultPath, "oauth_credentials.json"),
-		}
-		for _, path := range candidates {
-			id, err := identity.ExtractFromGeminiConfig(path)
+		for _, name := range []string{"settings.json", "oauth_credentials.json"} {
+			id, err := identity.ExtractFromGeminiConfig(path(name))
 			if err != nil {
 				continue
 			}
@@ -370,6 +505,49 @@ func formatIdentityDisplay(id *identity.Identity) (string, string) {
 	return email, plan
 }
 
+// projectShareWarning returns a warning when the project the current
+// directory is associated with for provider has used more than the
+// configured share (alerts.project_share_threshold) of profile's estimated
+// limit window. projectDirs maps providers to the associated project path.
+func projectShareWarning(projectDirs map[string]string, threshold int, provider, profile string) string {
+	dir := projectDirs[provider]
+	if dir == "" || threshold <= 0 {
+		return ""
+	}
+	db, err := getDB()
+	if err != nil {
+		return ""
+	}
+	use, ok := estimateProjectUse(db, provider, profile, dir, time.Now())
+	if !ok || use.Share*100 < float64(threshold) {
+		return ""
+	}
+	return formatProjectUse(use)
+}
+
+// currentProjectDirs returns the project path each provider's association
+// for the current directory comes from; global defaults are not projects.
+func currentProjectDirs() map[string]string {
+	if projectStore == nil {
+		return nil
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	resolved, err := projectStore.Resolve(cwd)
+	if err != nil {
+		return nil
+	}
+	dirs := make(map[string]string)
+	for provider, source := range resolved.Sources {
+		if source != "" && source != "<default>" {
+			dirs[provider] = source
+		}
+	}
+	return dirs
+}
+
 // getCooldownString returns a formatted string showing cooldown remaining time.
 // Returns empty string if no active cooldown or if db is unavailable.
 func getCooldownString(provider, profile string, opts health.FormatOptions) string {
@@ -487,6 +665,37 @@ func formatAllCooldownWarning(tool string, remaining time.Duration, nextProfile
 	return fmt.Sprintf("\033[33m%s: ⚠️  ALL profiles in cooldown (next available: %s in %s)\033[0m", tool, nextProfile, timeStr)
 }
 
+// vaultHasProfile reports whether the vault holds a profile with the given name.
+func vaultHasProfile(tool, profileName string) bool {
+	if vault == nil {
+		return false
+	}
+	profiles, err := vault.List(tool)
+	if err != nil {
+		return false
+	}
+	for _, p := range profiles {
+		if p == profileName {
+			return true
+		}
+	}
+	return false
+}
+
+// isolatedAuthFileSet returns the auth file set for a tool resolved inside an
+// isolated profile's pseudo-HOME, so vault operations can target it directly.
+func isolatedAuthFileSet(tool string, prof *profile.Profile) (authfile.AuthFileSet, error) {
+	fileSet, ok := authfile.AuthFileSetAt(tool, authfile.AuthDirs{
+		Home:      prof.HomePath(),
+		XDGConfig: prof.XDGConfigPath(),
+		CodexHome: prof.CodexHomePath(),
+	})
+	if !ok {
+		return authfile.AuthFileSet{}, fmt.Errorf("unknown tool: %s", tool)
+	}
+	return fileSet, nil
+}
+
 // truncateDescription truncates a description to maxLen characters, adding "..." if truncated.
 func truncateDescription(desc string, maxLen int) string {
 	if desc == "" {
@@ -502,6 +711,10 @@ func truncateDescription(desc string, maxLen int) string {
 }
 
 func init() {
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "suppress informational output; only errors are printed")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "assume yes for all confirmation prompts")
+	rootCmd.PersistentFlags().Bool("system", false, "manage the shared vault (CAAM_SHARED_VAULT, default /usr/local/share/caam/vault) instead of your own")
+
 	// Core commands (auth file swapping - PRIMARY)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(backupCmd)
@@ -535,11 +748,12 @@ var versionCmd = &cobra.Command{
 
 // backupOutput is the JSON output structure for backup command.
 type backupOutput struct {
-	Success bool   `json:"success"`
-	Tool    string `json:"tool"`
-	Profile string `json:"profile"`
-	Path    string `json:"path"`
-	Error   string `json:"error,omitempty"`
+	jsonStatus
+	Tool       string   `json:"tool"`
+	Profile    string   `json:"profile"`
+	Path       string   `json:"path"`
+	Categories []string `json:"categories,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
 }
 
 // backupCmd saves current auth files to the vault.
@@ -555,10 +769,20 @@ Use this after logging in to an account through the tool's normal login flow:
 The auth files are copied to $CAAM_HOME/data/vault/<tool>/<profile>/ (if CAAM_HOME is set)
 or ~/.local/share/caam/vault/<tool>/<profile>/
 
+Files fall into categories: credentials, settings (Claude's settings.json,
+Gemini's settings.json) and history (Claude's .claude.json session state).
+By default all are captured. --only captures just the listed categories and
+--include adds them to the credentials; files a tool requires are always
+captured. The choice is remembered, and activating the profile leaves the
+files it left out untouched.
+
 Examples:
   caam backup codex work-account
   caam backup claude personal-max
+  caam backup claude work --only credentials
+  caam backup claude work --include settings
   caam backup gemini team-ultra
+  caam backup codex "work account 1" --sanitize   # saves as work-account-1
   caam backup codex work --json`,
 	Args: cobra.ExactArgs(2),
 	RunE: runBackup,
@@ -566,6 +790,43 @@ Examples:
 
 func init() {
 	backupCmd.Flags().Bool("json", false, "output as JSON")
+	backupCmd.Flags().StringSlice("only", nil, "capture only these file categories (credentials, settings, history)")
+	backupCmd.Flags().StringSlice("include", nil, "capture credentials plus these file categories (settings, history)")
+	backupCmd.Flags().Bool("sanitize", false, "replace characters not allowed in profile names instead of failing")
+}
+
+// newProfileName validates a new vault profile name. An invalid one is
+// replaced by its sanitized form when sanitize is set, and otherwise
+// rejected with that form as a suggestion.
+func newProfileName(name string, sanitize bool) (string, error) {
+	err := authfile.ValidateProfileName(name)
+	if err == nil {
+		return name, nil
+	}
+	suggestion := authfile.SanitizeProfileName(name)
+	if suggestion == "" {
+		return "", withExitCode(ExitUsage, err)
+	}
+	if sanitize {
+		return suggestion, nil
+	}
+	return "", withExitCode(ExitUsage, fmt.Errorf("%w; pass --sanitize to use it", err))
+}
+
+// backupCategories resolves backup's --only and --include into the file
+// categories to capture; nil means all.
+func backupCategories(cmd *cobra.Command) ([]string, error) {
+	only, _ := cmd.Flags().GetStringSlice("only")
+	include, _ := cmd.Flags().GetStringSlice("include")
+	switch {
+	case len(only) > 0 && len(include) > 0:
+		return nil, fmt.Errorf("--only and --include cannot be combined")
+	case len(only) > 0:
+		return authfile.ParseFileCategories(only)
+	case len(include) > 0:
+		return authfile.ParseFileCategories(append([]string{authfile.CategoryCredentials}, include...))
+	}
+	return nil, nil
 }
 
 func runBackup(cmd *cobra.Command, args []string) error {
@@ -580,22 +841,33 @@ func runBackup(cmd *cobra.Command, args []string) error {
 
 	emitJSONError := func(err error) error {
 		if jsonOutput {
-			output.Success = false
-			output.Error = err.Error()
-			enc := json.NewEncoder(cmd.OutOrStdout())
-			enc.SetIndent("", "  ")
-			_ = enc.Encode(output)
-			return nil
+			return writeJSONResult(cmd, &output, err)
 		}
 		return err
 	}
 
 	getFileSet, ok := tools[tool]
 	if !ok {
-		return emitJSONError(fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool))
+		return emitJSONError(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())))
+	}
+
+	sanitize, _ := cmd.Flags().GetBool("sanitize")
+	profileName, err := newProfileName(profileName, sanitize)
+	if err != nil {
+		return emitJSONError(err)
 	}
+	if profileName != output.Profile && !jsonOutput {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using sanitized profile name '%s'\n", profileName)
+	}
+	output.Profile = profileName
 
 	fileSet := getFileSet()
+	categories, err := backupCategories(cmd)
+	if err != nil {
+		return emitJSONError(withExitCode(ExitUsage, err))
+	}
+	fileSet.Categories = categories
+	output.Categories = categories
 
 	// Check if auth files exist
 	if !authfile.HasAuthFiles(fileSet) {
@@ -607,25 +879,35 @@ func runBackup(cmd *cobra.Command, args []string) error {
 		return emitJSONError(fmt.Errorf("backup failed: %w", err))
 	}
 
-	output.Success = true
 	output.Path = vault.ProfilePath(tool, profileName)
+	output.Warnings = schemaWarnings(tool, profileName)
 
 	if jsonOutput {
-		enc := json.NewEncoder(cmd.OutOrStdout())
-		enc.SetIndent("", "  ")
-		return enc.Encode(output)
+		return writeJSONResult(cmd, &output, nil)
 	}
 
 	fmt.Printf("Backed up %s auth to profile '%s'\n", tool, profileName)
 	fmt.Printf("  Vault: %s\n", output.Path)
+	if len(categories) > 0 {
+		fmt.Printf("  Captured: %s\n", strings.Join(categories, ", "))
+	}
+	for _, warning := range output.Warnings {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", warning)
+	}
 	return nil
 }
 
 // statusOutput is the JSON output structure for status command.
 type statusOutput struct {
+	Context         string       `json:"context,omitempty"`
 	Tools           []statusTool `json:"tools"`
 	Warnings        []string     `json:"warnings,omitempty"`
 	Recommendations []string     `json:"recommendations,omitempty"`
+	// Alerts are suspicious auth changes pending 'caam ack'.
+	Alerts []health.Anomaly `json:"alerts,omitempty"`
+	// Elsewhere lists profiles active on other machines, when activity
+	// broadcast is on ('caam sync broadcast').
+	Elsewhere []remoteActivation `json:"elsewhere,omitempty"`
 }
 
 type statusTool struct {
@@ -635,12 +917,17 @@ type statusTool struct {
 	Error         string             `json:"error,omitempty"`
 	Health        *statusHealth      `json:"health,omitempty"`
 	Identity      *identity.Identity `json:"identity,omitempty"`
+
+	LoginVerifiedAt string `json:"login_verified_at,omitempty"`
+	StaleLogin      bool   `json:"stale_login,omitempty"`
 }
 
 type statusHealth struct {
 	Status            string `json:"status"`
 	Reason            string `json:"reason,omitempty"`
 	ExpiresAt         string `json:"expires_at,omitempty"`
+	ExpiresAtUTC      string `json:"expires_at_utc,omitempty"`
+	ExpiresDisplay    string `json:"expires_display,omitempty"`
 	ErrorCount        int    `json:"error_count"`
 	CooldownRemaining string `json:"cooldown_remaining,omitempty"`
 }
@@ -671,7 +958,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	formatOpts := health.FormatOptions{NoColor: noColor || !isTerminal()}
 
-	toolsToCheck := []string{"codex", "claude", "gemini"}
+	toolsToCheck := knownTools()
 	if len(args) > 0 {
 		tool := strings.ToLower(args[0])
 		if _, ok := tools[tool]; !ok {
@@ -680,11 +967,30 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		toolsToCheck = []string{tool}
 	}
 
-	var output statusOutput
+	output := statusOutput{Context: activeContextName}
+	projectDirs := currentProjectDirs()
+	loc := displayLocation()
+	shareThreshold := config.DefaultSPMConfig().Alerts.ProjectShareThreshold
+	staleDays := config.DefaultSPMConfig().Health.StaleLoginDays
+	if spmCfg, err := config.LoadSPMConfig(); err == nil {
+		shareThreshold = spmCfg.Alerts.ProjectShareThreshold
+		staleDays = spmCfg.Health.StaleLoginDays
+	}
+	freshDB, _ := getDB()
 	var warnings []string
 	var recommendations []string
+	var alerts []health.Anomaly
+	var elsewhere []remoteActivation
+	for _, r := range activeElsewhere() {
+		if slices.Contains(toolsToCheck, r.Tool) {
+			elsewhere = append(elsewhere, r)
+		}
+	}
 
 	if !jsonOutput {
+		if activeContextName != "" {
+			fmt.Printf("Context: %s\n\n", activeContextName)
+		}
 		fmt.Println("Active Profiles")
 		fmt.Println("───────────────────────────────────────────────────")
 		fmt.Printf("%-10s  %-20s  %-24s  %-10s  %s\n", "TOOL", "PROFILE", "EMAIL", "PLAN", "STATUS")
@@ -719,6 +1025,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			}
 			continue
 		}
+		alerts = append(alerts, detectAuthAnomalies(tool, fileSet, activeProfile)...)
 
 		if activeProfile == "" {
 			if jsonOutput {
@@ -735,6 +1042,7 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		// Get health and identity info
 		ph, id := getProfileHealthWithIdentity(tool, activeProfile)
 		status := health.CalculateStatus(ph)
+		freshness := checkLoginFreshness(freshDB, staleDays, tool, activeProfile, time.Now())
 
 		if jsonOutput {
 			st := statusTool{
@@ -747,8 +1055,12 @@ func runStatus(cmd *cobra.Command, args []string) error {
 					ErrorCount: ph.ErrorCount1h,
 				},
 			}
+			if !freshness.CheckedAt.IsZero() {
+				st.LoginVerifiedAt = freshness.CheckedAt.Format(time.RFC3339)
+				st.StaleLogin = freshness.Stale
+			}
 			if !ph.TokenExpiresAt.IsZero() {
-				st.Health.ExpiresAt = ph.TokenExpiresAt.Format(time.RFC3339)
+				st.Health.ExpiresAt, st.Health.ExpiresAtUTC, st.Health.ExpiresDisplay = zonedTimestamps(ph.TokenExpiresAt, loc)
 			}
 			// Get cooldown info
 			cooldownStr := getCooldownString(tool, activeProfile, health.FormatOptions{NoColor: true})
@@ -775,6 +1087,21 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			warnings = append(warnings, fmt.Sprintf("%s/%s: %s", tool, activeProfile, detailedStatus))
 		}
 
+		if freshness.Stale {
+			warnings = append(warnings, staleLoginWarning(tool, activeProfile, freshness))
+		}
+
+		if w := projectShareWarning(projectDirs, shareThreshold, tool, activeProfile); w != "" {
+			warnings = append(warnings, w)
+		}
+
+		// Warn when someone else is using the same account
+		for _, r := range elsewhere {
+			if r.Tool == tool && r.Profile == activeProfile {
+				warnings = append(warnings, fmt.Sprintf("%s/%s is also active on %s (since %s)", tool, activeProfile, r.Machine, formatTimeAgo(r.Since)))
+			}
+		}
+
 		// Collect recommendations
 		rec := health.FormatRecommendation(tool, activeProfile, ph)
 		if rec != "" {
@@ -792,11 +1119,34 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	if jsonOutput {
 		output.Warnings = warnings
 		output.Recommendations = recommendations
+		output.Alerts = alerts
+		output.Elsewhere = elsewhere
 		enc := json.NewEncoder(cmd.OutOrStdout())
 		enc.SetIndent("", "  ")
 		return enc.Encode(output)
 	}
 
+	// Show security alerts ahead of ordinary warnings
+	if len(alerts) > 0 {
+		fmt.Println()
+		fmt.Println("Security Alerts")
+		fmt.Println("───────────────────────────────────────────────────")
+		for _, a := range alerts {
+			fmt.Printf("  %s\n", formatAnomaly(a))
+		}
+		fmt.Println("  Review each, then run 'caam ack <id>' (or 'caam ack --all').")
+	}
+
+	// Show where profiles are in use on other machines
+	if len(elsewhere) > 0 {
+		fmt.Println()
+		fmt.Println("Active Elsewhere")
+		fmt.Println("───────────────────────────────────────────────────")
+		for _, r := range elsewhere {
+			fmt.Printf("  %-16s  %-8s  %-20s  since %s\n", r.Machine, r.Tool, r.Profile, formatTimeAgo(r.Since))
+		}
+	}
+
 	// Show warnings
 	if len(warnings) > 0 {
 		fmt.Println()
@@ -831,16 +1181,30 @@ type lsOutput struct {
 type lsProfile struct {
 	Tool     string             `json:"tool"`
 	Name     string             `json:"name"`
+	Index    int                `json:"index,omitempty"`
 	Active   bool               `json:"active"`
 	System   bool               `json:"system"`
+	Shared   bool               `json:"shared,omitempty"`
 	Health   lsHealth           `json:"health"`
+	LastUsed string             `json:"last_used,omitempty"`
 	Identity *identity.Identity `json:"identity,omitempty"`
+
+	Quarantined      bool   `json:"quarantined,omitempty"`
+	QuarantineReason string `json:"quarantine_reason,omitempty"`
+
+	// LoginVerifiedAt is when the login last verifiably worked (see
+	// checkLoginFreshness); StaleLogin is set past health.stale_login_days.
+	LoginVerifiedAt string `json:"login_verified_at,omitempty"`
+	StaleLogin      bool   `json:"stale_login,omitempty"`
 }
 
 type lsHealth struct {
-	Status     string `json:"status"`
-	ExpiresAt  string `json:"expires_at,omitempty"`
-	ErrorCount int    `json:"error_count"`
+	Status         string `json:"status"`
+	ExpiresAt      string `json:"expires_at,omitempty"`
+	ExpiresAtUTC   string `json:"expires_at_utc,omitempty"`
+	ExpiresDisplay string `json:"expires_display,omitempty"`
+	ExpiresIn      string `json:"expires_in,omitempty"`
+	ErrorCount     int    `json:"error_count"`
 }
 
 // lsCmd lists all stored profiles.
@@ -855,7 +1219,18 @@ Examples:
   caam ls claude       # List just Claude profiles
   caam ls --tag work   # List profiles with 'work' tag
   caam ls --no-color   # Without colors (for piping)
-  caam ls --json       # Output as JSON`,
+  caam ls --json       # Output as JSON
+  caam ls --expiry --sort expiry   # Show time to token expiry, soonest first
+  caam ls --filter cooldown        # Profiles currently in cooldown
+  caam ls --active-only --json     # Active profile per tool, for scripts
+
+--sort orders profiles by name (default), health (healthiest first), expiry
+(soonest first, unknown last) or last-used (most recently activated or run
+first).
+
+The # column numbers each tool's profiles by name, whatever the sort or
+filter, so 'caam activate claude 2' (or 'caam 2' for the default tool)
+activates the profile shown as 2.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runLs,
 }
@@ -864,12 +1239,61 @@ func init() {
 	lsCmd.Flags().Bool("no-color", false, "disable colored output")
 	lsCmd.Flags().Bool("json", false, "output as JSON")
 	lsCmd.Flags().String("tag", "", "filter profiles by tag")
+	lsCmd.Flags().Bool("expiry", false, "show time to token expiry")
+	lsCmd.Flags().String("sort", "name", "sort profiles by: name, health, expiry, last-used")
+	lsCmd.Flags().String("filter", "", "only list profiles that are: healthy, cooldown, expired")
+	lsCmd.Flags().Bool("active-only", false, "only list active profiles")
 }
 
 func runLs(cmd *cobra.Command, args []string) error {
 	noColor, _ := cmd.Flags().GetBool("no-color")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	tagFilter, _ := cmd.Flags().GetString("tag")
+	showExpiry, _ := cmd.Flags().GetBool("expiry")
+	loc := displayLocation()
+	sortBy, _ := cmd.Flags().GetString("sort")
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	switch sortBy {
+	case "", "name", "health", "expiry", "last-used":
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("invalid --sort %q (supported: name, health, expiry, last-used)", sortBy))
+	}
+	filter, _ := cmd.Flags().GetString("filter")
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	switch filter {
+	case "", "healthy", "cooldown", "expired":
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("invalid --filter %q (supported: healthy, cooldown, expired)", filter))
+	}
+	activeOnly, _ := cmd.Flags().GetBool("active-only")
+
+	var cooldownDB *caamdb.DB
+	if filter == "cooldown" {
+		if db, err := caamdb.Open(); err == nil {
+			cooldownDB = db
+			defer db.Close()
+		}
+	}
+
+	// Helper to check if a row passes --filter and --active-only
+	keepRow := func(tool string, row lsRow, active bool) bool {
+		if activeOnly && !active {
+			return false
+		}
+		switch filter {
+		case "healthy":
+			return row.status == health.StatusHealthy
+		case "expired":
+			return !row.health.TokenExpiresAt.IsZero() && row.health.TokenExpiresAt.Before(time.Now())
+		case "cooldown":
+			if cooldownDB == nil {
+				return false
+			}
+			ev, err := cooldownDB.ActiveCooldown(tool, row.name, time.Now())
+			return err == nil && ev != nil
+		}
+		return true
+	}
 	formatOpts := health.FormatOptions{NoColor: noColor || !isTerminal()}
 
 	// Helper to check if a profile has the specified tag
@@ -922,32 +1346,56 @@ func runLs(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		if !jsonOutput {
-			fmt.Printf("%-22s  %-24s  %-10s  %s\n", "PROFILE", "EMAIL", "PLAN", "STATUS")
-		}
-
 		// Check which is active
 		fileSet := tools[tool]()
 		activeProfile, _ := vault.ActiveProfile(fileSet)
 
-		for _, p := range profiles {
-			ph, id := getProfileHealthWithIdentity(tool, p)
-			status := health.CalculateStatus(ph)
+		rows := filterLsRows(tool, collectLsRows(tool, profiles, sortBy), keepRow, activeProfile)
+		if len(rows) == 0 && !jsonOutput {
+			fmt.Printf("No matching profiles for %s\n", tool)
+			return nil
+		}
+
+		indexes := profileIndexes(tool)
+		if !jsonOutput {
+			fmt.Printf("%-3s %-22s  %-24s  %-10s  %-13s  %s%s\n", "#", "PROFILE", "EMAIL", "PLAN", "LAST USED", lsExpiryHeader(showExpiry), "STATUS")
+		}
+
+		for _, row := range rows {
+			p, ph, id, status := row.name, row.health, row.id, row.status
+			lastUsed := "-"
+			if !row.lastUsed.IsZero() {
+				lastUsed = formatTimeAgo(row.lastUsed)
+			}
 
 			if jsonOutput {
 				lp := lsProfile{
 					Tool:   tool,
 					Name:   p,
+					Index:  indexes[p],
 					Active: p == activeProfile,
 					System: authfile.IsSystemProfile(p),
+					Shared: vault.IsShared(tool, p),
 					Health: lsHealth{
 						Status:     status.String(),
 						ErrorCount: ph.ErrorCount1h,
 					},
 					Identity: id,
 				}
+				if row.quarantine != nil {
+					lp.Quarantined = true
+					lp.QuarantineReason = row.quarantine.Reason
+				}
+				if !row.lastUsed.IsZero() {
+					lp.LastUsed = row.lastUsed.Format(time.RFC3339)
+				}
+				if !row.freshness.CheckedAt.IsZero() {
+					lp.LoginVerifiedAt = row.freshness.CheckedAt.Format(time.RFC3339)
+					lp.StaleLogin = row.freshness.Stale
+				}
 				if !ph.TokenExpiresAt.IsZero() {
-					lp.Health.ExpiresAt = ph.TokenExpiresAt.Format(time.RFC3339)
+					lp.Health.ExpiresAt, lp.Health.ExpiresAtUTC, lp.Health.ExpiresDisplay = zonedTimestamps(ph.TokenExpiresAt, loc)
+					lp.Health.ExpiresIn = formatExpiry(ph.TokenExpiresAt)
 				}
 				output.Profiles = append(output.Profiles, lp)
 			} else {
@@ -959,13 +1407,18 @@ func runLs(cmd *cobra.Command, args []string) error {
 				displayName := p
 				if authfile.IsSystemProfile(p) {
 					displayName = fmt.Sprintf("%s [system]", p)
+				} else if vault.IsShared(tool, p) {
+					displayName = fmt.Sprintf("%s [shared]", p)
 				}
 
 				email, plan := formatIdentityDisplay(id)
-				healthStr := health.FormatHealthStatus(status, ph, formatOpts)
-				fmt.Printf("%s%-20s  %-24s  %-10s  %s\n", marker, displayName, email, plan, healthStr)
+				healthStr := health.FormatHealthStatus(status, ph, formatOpts) + lsQuarantineNote(row.quarantine) + lsStaleNote(row.freshness, time.Now())
+				fmt.Printf("%-3s %s%-20s  %-24s  %-10s  %-13s  %s%s\n", lsIndexColumn(indexes, p), marker, displayName, email, plan, lastUsed, lsExpiryColumn(showExpiry, ph), healthStr)
 			}
 		}
+		if note := lsOriginalNote(tool, rows); note != "" && !jsonOutput {
+			fmt.Printf("\n%s\n", note)
+		}
 
 		if jsonOutput {
 			output.Count = len(output.Profiles)
@@ -1014,33 +1467,58 @@ func runLs(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	listed := 0
 	for tool, profiles := range allProfiles {
 		fileSet := tools[tool]()
 		activeProfile, _ := vault.ActiveProfile(fileSet)
 
+		rows := filterLsRows(tool, collectLsRows(tool, profiles, sortBy), keepRow, activeProfile)
+		if len(rows) == 0 {
+			continue
+		}
+		listed += len(rows)
+
+		indexes := profileIndexes(tool)
 		if !jsonOutput {
 			fmt.Printf("%s:\n", tool)
-			fmt.Printf("  %-20s  %-24s  %-10s  %s\n", "PROFILE", "EMAIL", "PLAN", "STATUS")
+			fmt.Printf("  %-3s %-22s  %-24s  %-10s  %-13s  %s%s\n", "#", "PROFILE", "EMAIL", "PLAN", "LAST USED", lsExpiryHeader(showExpiry), "STATUS")
 		}
 
-		for _, p := range profiles {
-			ph, id := getProfileHealthWithIdentity(tool, p)
-			status := health.CalculateStatus(ph)
+		for _, row := range rows {
+			p, ph, id, status := row.name, row.health, row.id, row.status
+			lastUsed := "-"
+			if !row.lastUsed.IsZero() {
+				lastUsed = formatTimeAgo(row.lastUsed)
+			}
 
 			if jsonOutput {
 				lp := lsProfile{
 					Tool:   tool,
 					Name:   p,
+					Index:  indexes[p],
 					Active: p == activeProfile,
 					System: authfile.IsSystemProfile(p),
+					Shared: vault.IsShared(tool, p),
 					Health: lsHealth{
 						Status:     status.String(),
 						ErrorCount: ph.ErrorCount1h,
 					},
 					Identity: id,
 				}
+				if row.quarantine != nil {
+					lp.Quarantined = true
+					lp.QuarantineReason = row.quarantine.Reason
+				}
+				if !row.lastUsed.IsZero() {
+					lp.LastUsed = row.lastUsed.Format(time.RFC3339)
+				}
+				if !row.freshness.CheckedAt.IsZero() {
+					lp.LoginVerifiedAt = row.freshness.CheckedAt.Format(time.RFC3339)
+					lp.StaleLogin = row.freshness.Stale
+				}
 				if !ph.TokenExpiresAt.IsZero() {
-					lp.Health.ExpiresAt = ph.TokenExpiresAt.Format(time.RFC3339)
+					lp.Health.ExpiresAt, lp.Health.ExpiresAtUTC, lp.Health.ExpiresDisplay = zonedTimestamps(ph.TokenExpiresAt, loc)
+					lp.Health.ExpiresIn = formatExpiry(ph.TokenExpiresAt)
 				}
 				output.Profiles = append(output.Profiles, lp)
 			} else {
@@ -1052,24 +1530,156 @@ func runLs(cmd *cobra.Command, args []string) error {
 				displayName := p
 				if authfile.IsSystemProfile(p) {
 					displayName = fmt.Sprintf("%s [system]", p)
+				} else if vault.IsShared(tool, p) {
+					displayName = fmt.Sprintf("%s [shared]", p)
 				}
 
 				email, plan := formatIdentityDisplay(id)
-				healthStr := health.FormatHealthStatus(status, ph, formatOpts)
-				fmt.Printf("  %s%-20s  %-24s  %-10s  %s\n", marker, displayName, email, plan, healthStr)
+				healthStr := health.FormatHealthStatus(status, ph, formatOpts) + lsQuarantineNote(row.quarantine) + lsStaleNote(row.freshness, time.Now())
+				fmt.Printf("  %-3s %s%-20s  %-24s  %-10s  %-13s  %s%s\n", lsIndexColumn(indexes, p), marker, displayName, email, plan, lastUsed, lsExpiryColumn(showExpiry, ph), healthStr)
 			}
 		}
+		if note := lsOriginalNote(tool, rows); note != "" && !jsonOutput {
+			fmt.Printf("  %s\n", note)
+		}
 	}
 
 	if jsonOutput {
 		output.Count = len(output.Profiles)
 		return encodeLsJSON(cmd, output)
 	}
+	if listed == 0 {
+		fmt.Println("No matching profiles")
+	}
 
 	return nil
 }
 
+// lsRow is one profile listed by caam ls, with the health data it is shown
+// and sorted by.
+type lsRow struct {
+	name   string
+	health *health.ProfileHealth
+	id     *identity.Identity
+	status health.HealthStatus
+	score  float64
+
+	lastUsed   time.Time
+	quarantine *config.Quarantine
+	freshness  loginFreshness
+}
+
+// collectLsRows loads health and identity for each profile and orders the
+// rows by sortBy (name, health, expiry or last-used).
+func collectLsRows(tool string, profiles []string, sortBy string) []lsRow {
+	db, err := caamdb.Open()
+	if err == nil {
+		defer db.Close()
+	}
+
+	globalCfg, cfgErr := config.Load()
+	staleDays := staleLoginDays()
+	now := time.Now()
+
+	rows := make([]lsRow, 0, len(profiles))
+	for _, p := range profiles {
+		ph, id := getProfileHealthWithIdentity(tool, p)
+		status, score := health.CalculateHealth(ph, health.DefaultHealthConfig())
+		row := lsRow{name: p, health: ph, id: id, status: status, score: score}
+		if err == nil {
+			row.lastUsed, _ = db.LastUsed(tool, p)
+			row.freshness = checkLoginFreshness(db, staleDays, tool, p, now)
+		} else {
+			row.freshness = checkLoginFreshness(nil, staleDays, tool, p, now)
+		}
+		if cfgErr == nil {
+			if q, ok := globalCfg.GetQuarantine(tool, p); ok {
+				row.quarantine = &q
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	switch sortBy {
+	case "health":
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].score > rows[j].score
+		})
+	case "expiry":
+		sort.SliceStable(rows, func(i, j int) bool {
+			a, b := rows[i].health.TokenExpiresAt, rows[j].health.TokenExpiresAt
+			if a.IsZero() || b.IsZero() {
+				return !a.IsZero() && b.IsZero()
+			}
+			return a.Before(b)
+		})
+	case "last-used":
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].lastUsed.After(rows[j].lastUsed)
+		})
+	}
+	return rows
+}
+
+// lsQuarantineNote returns the note caam ls appends to a quarantined
+// profile's status, or "" if it isn't quarantined.
+func lsQuarantineNote(q *config.Quarantine) string {
+	switch {
+	case q == nil:
+		return ""
+	case q.Reason == "":
+		return "  [quarantined]"
+	default:
+		return fmt.Sprintf("  [quarantined: %s]", q.Reason)
+	}
+}
+
+// filterLsRows returns the rows keep accepts, in order.
+func filterLsRows(tool string, rows []lsRow, keep func(tool string, row lsRow, active bool) bool, activeProfile string) []lsRow {
+	kept := rows[:0]
+	for _, row := range rows {
+		if keep(tool, row, row.name == activeProfile) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+// formatExpiry returns a compact time to expiry: "45m", "6h12m", "3d" or
+// "expired"; "-" when the expiry is unknown.
+func formatExpiry(expiresAt time.Time) string {
+	if expiresAt.IsZero() {
+		return "-"
+	}
+	ttl := time.Until(expiresAt)
+	switch {
+	case ttl <= 0:
+		return "expired"
+	case ttl < 24*time.Hour:
+		return formatDurationShort(ttl)
+	default:
+		return fmt.Sprintf("%dd", int(ttl.Hours()/24))
+	}
+}
+
+func lsExpiryHeader(show bool) string {
+	if !show {
+		return ""
+	}
+	return fmt.Sprintf("%-8s  ", "EXPIRES")
+}
+
+func lsExpiryColumn(show bool, ph *health.ProfileHealth) string {
+	if !show {
+		return ""
+	}
+	return fmt.Sprintf("%-8s  ", formatExpiry(ph.TokenExpiresAt))
+}
+
 func encodeLsJSON(cmd *cobra.Command, output lsOutput) error {
+	if output.Profiles == nil {
+		output.Profiles = []lsProfile{}
+	}
 	enc := json.NewEncoder(cmd.OutOrStdout())
 	enc.SetIndent("", "  ")
 	return enc.Encode(output)
@@ -1083,28 +1693,36 @@ var deleteCmd = &cobra.Command{
 	Long: `Removes a profile from the vault. This does not affect the current auth state.
 
 Examples:
-  caam delete claude old-account`,
+  caam delete claude old-account
+  caam delete claude old-account --force --json`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
 		profileName := args[1]
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		output := deleteOutput{Tool: tool, Profile: profileName}
+		finish := func(err error) error {
+			if jsonOutput {
+				return writeJSONResult(cmd, &output, err)
+			}
+			return err
+		}
 
 		if _, ok := tools[tool]; !ok {
-			return fmt.Errorf("unknown tool: %s", tool)
+			return finish(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s", tool)))
 		}
 
 		force, _ := cmd.Flags().GetBool("force")
 		if authfile.IsSystemProfile(profileName) && !force {
-			return fmt.Errorf("refusing to delete system profile %s/%s without --force", tool, profileName)
+			return finish(fmt.Errorf("refusing to delete system profile %s/%s without --force", tool, profileName))
 		}
-		if !force {
-			fmt.Printf("Delete profile %s/%s? [y/N]: ", tool, profileName)
-			var confirm string
-			fmt.Scanln(&confirm)
-			if strings.ToLower(confirm) != "y" {
-				fmt.Println("Cancelled")
-				return nil
+		if !force && !confirmPrompt(cmd, jsonOutput, fmt.Sprintf("Delete profile %s/%s?", tool, profileName)) {
+			if jsonOutput {
+				return finish(errCancelled)
 			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Cancelled")
+			return nil
 		}
 
 		var err error
@@ -1114,16 +1732,27 @@ Examples:
 			err = vault.Delete(tool, profileName)
 		}
 		if err != nil {
-			return fmt.Errorf("delete failed: %w", err)
+			return finish(fmt.Errorf("delete failed: %w", err))
 		}
 
-		fmt.Printf("Deleted %s/%s\n", tool, profileName)
+		if jsonOutput {
+			return finish(nil)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s/%s\n", tool, profileName)
 		return nil
 	},
 }
 
+// deleteOutput is the JSON output structure for delete and profile delete.
+type deleteOutput struct {
+	jsonStatus
+	Tool    string `json:"tool"`
+	Profile string `json:"profile"`
+}
+
 func init() {
 	deleteCmd.Flags().Bool("force", false, "skip confirmation (required to delete system profiles starting with '_')")
+	deleteCmd.Flags().Bool("json", false, "output as JSON")
 }
 
 // pathsCmd shows auth file paths for each tool.
@@ -1136,40 +1765,88 @@ Useful for understanding what caam is backing up and for manual troubleshooting.
 
 Examples:
   caam paths           # Show all tools
-  caam paths claude    # Show just Claude`,
+  caam paths claude    # Show just Claude
+  caam paths --json`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		toolsToShow := []string{"codex", "claude", "gemini"}
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		output := pathsOutput{Tools: []pathsTool{}}
+
+		toolsToShow := knownTools()
 		if len(args) > 0 {
 			tool := strings.ToLower(args[0])
 			if _, ok := tools[tool]; !ok {
-				return fmt.Errorf("unknown tool: %s", tool)
+				err := withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s", tool))
+				if jsonOutput {
+					return writeJSONResult(cmd, &output, err)
+				}
+				return err
 			}
 			toolsToShow = []string{tool}
 		}
 
 		for _, tool := range toolsToShow {
-			fileSet := tools[tool]()
-			fmt.Printf("%s:\n", tool)
-			for _, spec := range fileSet.Files {
+			entry := pathsTool{Tool: tool}
+			for _, spec := range tools[tool]().Files {
+				_, err := os.Stat(spec.Path)
+				entry.Files = append(entry.Files, pathsFile{
+					Path:        spec.Path,
+					Description: spec.Description,
+					Required:    spec.Required,
+					Exists:      err == nil,
+				})
+			}
+			output.Tools = append(output.Tools, entry)
+		}
+
+		if jsonOutput {
+			return writeJSONResult(cmd, &output, nil)
+		}
+
+		out := cmd.OutOrStdout()
+		for _, entry := range output.Tools {
+			fmt.Fprintf(out, "%s:\n", entry.Tool)
+			for _, f := range entry.Files {
 				exists := "missing"
-				if _, err := os.Stat(spec.Path); err == nil {
+				if f.Exists {
 					exists = "exists"
 				}
 				required := ""
-				if spec.Required {
+				if f.Required {
 					required = " (required)"
 				}
-				fmt.Printf("  [%s] %s%s\n", exists, spec.Path, required)
-				fmt.Printf("         %s\n", spec.Description)
+				fmt.Fprintf(out, "  [%s] %s%s\n", exists, f.Path, required)
+				fmt.Fprintf(out, "         %s\n", f.Description)
 			}
-			fmt.Println()
+			fmt.Fprintln(out)
 		}
 
 		return nil
 	},
 }
 
+// pathsOutput is the JSON output structure for paths command.
+type pathsOutput struct {
+	jsonStatus
+	Tools []pathsTool `json:"tools"`
+}
+
+type pathsTool struct {
+	Tool  string      `json:"tool"`
+	Files []pathsFile `json:"files"`
+}
+
+type pathsFile struct {
+	Path        string `json:"path"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Exists      bool   `json:"exists"`
+}
+
+func init() {
+	pathsCmd.Flags().Bool("json", false, "output as JSON")
+}
+
 // clearCmd removes auth files (logout).
 var clearCmd = &cobra.Command{
 	Use:   "clear <tool>",
@@ -1177,43 +1854,139 @@ var clearCmd = &cobra.Command{
 	Long: `Removes the auth files for a tool, effectively logging out.
 
 This is useful if you want to start fresh or test the login flow.
-Consider backing up first: caam backup <tool> <name>
+The current login is saved as a timestamped _backup_* profile first, so
+it can be restored with 'caam activate'; pass --no-backup to skip that.
+
+Use --dry-run to list the files that would be removed and whether each
+is also saved in a vault profile.
 
 Examples:
-  caam clear claude`,
+  caam clear claude --dry-run
+  caam clear claude
+  caam clear claude --force --no-backup --json`,
 	Args: cobra.ExactArgs(1),
-	RunE: func(cmd *cobra.Command, args []string) error {
-		tool := strings.ToLower(args[0])
+	RunE: runClear,
+}
 
-		getFileSet, ok := tools[tool]
-		if !ok {
-			return fmt.Errorf("unknown tool: %s", tool)
+// clearOutput is the JSON output structure for clear command.
+type clearOutput struct {
+	jsonStatus
+	Tool   string                  `json:"tool"`
+	DryRun bool                    `json:"dry_run,omitempty"`
+	Files  []authfile.ClearPreview `json:"files"`
+	Backup string                  `json:"backup,omitempty"`
+}
+
+func init() {
+	clearCmd.Flags().Bool("force", false, "skip confirmation")
+	clearCmd.Flags().Bool("dry-run", false, "list the files that would be removed without removing them")
+	clearCmd.Flags().Bool("no-backup", false, "don't back up the current login before clearing")
+	clearCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+func runClear(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noBackup, _ := cmd.Flags().GetBool("no-backup")
+	out := cmd.OutOrStdout()
+
+	output := clearOutput{Tool: tool, DryRun: dryRun, Files: []authfile.ClearPreview{}}
+	finish := func(err error) error {
+		if jsonOutput {
+			return writeJSONResult(cmd, &output, err)
 		}
+		return err
+	}
+
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return finish(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s", tool)))
+	}
 
-		fileSet := getFileSet()
+	fileSet := getFileSet()
 
-		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Clear auth for %s? This will log you out. [y/N]: ", tool)
-			var confirm string
-			fmt.Scanln(&confirm)
-			if strings.ToLower(confirm) != "y" {
-				fmt.Println("Cancelled")
-				return nil
-			}
+	previews, err := vault.PreviewClear(fileSet)
+	if err != nil {
+		return finish(fmt.Errorf("inspect auth files: %w", err))
+	}
+	if previews != nil {
+		output.Files = previews
+	}
+	unsaved := 0
+	for _, p := range previews {
+		if len(p.Profiles) == 0 {
+			unsaved++
 		}
+	}
 
-		if err := authfile.ClearAuthFiles(fileSet); err != nil {
-			return fmt.Errorf("clear failed: %w", err)
+	if len(previews) == 0 {
+		if jsonOutput {
+			return finish(nil)
 		}
+		fmt.Fprintf(out, "No auth files for %s; nothing to clear.\n", tool)
+		return nil
+	}
 
-		fmt.Printf("Cleared auth for %s\n", tool)
+	if dryRun {
+		if jsonOutput {
+			return finish(nil)
+		}
+		fmt.Fprintf(out, "Would remove %d file(s) for %s:\n", len(previews), tool)
+		for _, p := range previews {
+			where := "not in the vault - only the backup would keep it"
+			if noBackup {
+				where = "NOT in the vault - would be lost"
+			}
+			if len(p.Profiles) > 0 {
+				where = "saved in " + strings.Join(p.Profiles, ", ")
+			}
+			fmt.Fprintf(out, "  %s (%s)\n", p.Path, where)
+		}
+		if !noBackup {
+			fmt.Fprintln(out, "A timestamped _backup_* profile would be taken first.")
+		}
 		return nil
-	},
-}
+	}
 
-func init() {
-	clearCmd.Flags().Bool("force", false, "skip confirmation")
+	force, _ := cmd.Flags().GetBool("force")
+	question := fmt.Sprintf("Clear auth for %s? This will log you out.", tool)
+	if noBackup && unsaved > 0 {
+		question = fmt.Sprintf("Clear auth for %s? %d file(s) are not saved in the vault and will be lost.", tool, unsaved)
+	}
+	if !force && !confirmPrompt(cmd, jsonOutput, question) {
+		if jsonOutput {
+			return finish(errCancelled)
+		}
+		fmt.Fprintln(out, "Cancelled")
+		return nil
+	}
+
+	if !noBackup {
+		backup, err := vault.BackupCurrent(fileSet)
+		if err != nil {
+			return finish(fmt.Errorf("backup before clear failed (use --no-backup to clear anyway): %w", err))
+		}
+		output.Backup = backup
+		if backup != "" {
+			if spmCfg, err := config.LoadSPMConfig(); err == nil {
+				_ = vault.RotateAutoBackups(tool, spmCfg.Safety.MaxAutoBackups)
+			}
+		}
+	}
+
+	if err := authfile.ClearAuthFiles(fileSet); err != nil {
+		return finish(fmt.Errorf("clear failed: %w", err))
+	}
+
+	if jsonOutput {
+		return finish(nil)
+	}
+	if output.Backup != "" {
+		fmt.Fprintf(out, "Backed up current auth to %s/%s\n", tool, output.Backup)
+	}
+	fmt.Fprintf(out, "Cleared auth for %s\n", tool)
+	return nil
 }
 
 // =============================================================================
@@ -1244,21 +2017,33 @@ func init() {
 }
 
 var profileAddCmd = &cobra.Command{
-	Use:   "add <tool> <name> [--auth-mode oauth|api-key]",
+	Use:   "add <tool> <name> [--auth-mode oauth|api-key|vertex-adc]",
 	Short: "Create a new isolated profile",
 	Long: `Create a new isolated profile for running multiple sessions simultaneously.
 
 Options:
-  --auth-mode        Authentication mode (oauth, api-key)
+  --auth-mode        Authentication mode (oauth, api-key; gemini also vertex-adc)
   --description, -d  Free-form notes about this profile's purpose
   --browser          Browser command (chrome, firefox, or full path)
   --browser-profile  Browser profile name or directory
 
+Gemini Vertex AI options (--auth-mode vertex-adc):
+  --project          Google Cloud project ID
+  --location         Vertex AI region (default us-central1)
+  --service-account  Service account JSON key to copy into the profile
+                     (otherwise 'caam login' runs gcloud ADC login)
+
+Claude options:
+  --mcp-config       JSON file of MCP servers for this profile (see 'caam profile mcp')
+
 Examples:
   caam profile add codex work
   caam profile add claude personal -d "Personal consulting projects"
   caam profile add claude work --browser chrome --browser-profile "Profile 2"
-  caam profile add gemini team --browser firefox --browser-profile "work-firefox"`,
+  caam profile add gemini team --browser firefox --browser-profile "work-firefox"
+  caam profile add gemini key --auth-mode api-key
+  caam profile add gemini ci --auth-mode vertex-adc --project my-proj --service-account ./sa.json
+  caam profile add claude research --mcp-config ./research-mcp.json`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
@@ -1274,6 +2059,17 @@ Examples:
 			authMode = "oauth"
 		}
 
+		vertexMeta, err := vertexProfileMetadata(cmd, tool, authMode)
+		if err != nil {
+			return err
+		}
+		var mcpServers string
+		if mcpConfig, _ := cmd.Flags().GetString("mcp-config"); mcpConfig != "" {
+			if mcpServers, err = mcpServersMetadata(tool, mcpConfig); err != nil {
+				return err
+			}
+		}
+
 		// Create profile
 		prof, err := profileStore.Create(tool, name, authMode)
 		if err != nil {
@@ -1300,6 +2096,12 @@ Examples:
 		if browserName != "" {
 			prof.BrowserProfileName = browserName
 		}
+		for k, v := range vertexMeta {
+			prof.Metadata[k] = v
+		}
+		if mcpServers != "" {
+			prof.Metadata[claude.MetaMCPServers] = mcpServers
+		}
 
 		// Save updated profile with browser config
 		if err := prof.Save(); err != nil {
@@ -1331,11 +2133,69 @@ Examples:
 }
 
 func init() {
-	profileAddCmd.Flags().String("auth-mode", "oauth", "authentication mode (oauth, api-key)")
+	profileAddCmd.Flags().String("auth-mode", "oauth", "authentication mode (oauth, api-key, vertex-adc)")
 	profileAddCmd.Flags().StringP("description", "d", "", "free-form notes about this profile's purpose")
 	profileAddCmd.Flags().String("browser", "", "browser command (chrome, firefox, or full path)")
 	profileAddCmd.Flags().String("browser-profile", "", "browser profile name or directory")
 	profileAddCmd.Flags().String("browser-name", "", "human-friendly name for browser profile")
+	profileAddCmd.Flags().String("project", "", "Google Cloud project ID (gemini vertex-adc)")
+	profileAddCmd.Flags().String("location", "", "Vertex AI region (gemini vertex-adc, default us-central1)")
+	profileAddCmd.Flags().String("service-account", "", "service account JSON key to copy into the profile (gemini vertex-adc)")
+	profileAddCmd.Flags().String("mcp-config", "", "JSON file of MCP servers to render into the profile (claude)")
+}
+
+// vertexProfileMetadata returns the Gemini Vertex AI settings given to
+// 'profile add' as profile metadata.
+func vertexProfileMetadata(cmd *cobra.Command, tool, authMode string) (map[string]string, error) {
+	meta := map[string]string{}
+	flagKeys := map[string]string{
+		"project":         gemini.MetaVertexProject,
+		"location":        gemini.MetaVertexLocation,
+		"service-account": gemini.MetaServiceAccount,
+	}
+	for flag, key := range flagKeys {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		if tool != "gemini" || authMode != string(provider.AuthModeVertexADC) {
+			return nil, withExitCode(ExitUsage, fmt.Errorf("--%s requires gemini with --auth-mode vertex-adc", flag))
+		}
+		value, _ := cmd.Flags().GetString(flag)
+		meta[key] = value
+	}
+
+	if path := meta[gemini.MetaServiceAccount]; path != "" {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %w", path, err)
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return nil, fmt.Errorf("service account key: %w", err)
+		}
+		meta[gemini.MetaServiceAccount] = abs
+	}
+	return meta, nil
+}
+
+// mcpServersMetadata reads an MCP server list for a claude profile and
+// returns it as the compact JSON stored in the profile's metadata.
+func mcpServersMetadata(tool, path string) (string, error) {
+	if tool != "claude" {
+		return "", withExitCode(ExitUsage, fmt.Errorf("MCP server config is only supported for claude profiles"))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read MCP config: %w", err)
+	}
+	servers, err := claude.ParseMCPServers(data)
+	if err != nil {
+		return "", withExitCode(ExitUsage, fmt.Errorf("%s: %w", path, err))
+	}
+	raw, err := json.Marshal(servers)
+	if err != nil {
+		return "", fmt.Errorf("marshal MCP servers: %w", err)
+	}
+	return string(raw), nil
 }
 
 var profileLsCmd = &cobra.Command{
@@ -1344,6 +2204,10 @@ var profileLsCmd = &cobra.Command{
 	Short:   "List isolated profiles",
 	Args:    cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			return runProfileLsJSON(cmd, args)
+		}
+
 		if len(args) > 0 {
 			tool := strings.ToLower(args[0])
 			profiles, err := profileStore.List(tool)
@@ -1402,6 +2266,67 @@ var profileLsCmd = &cobra.Command{
 	},
 }
 
+// profileLsOutput is the JSON output structure for profile ls.
+type profileLsOutput struct {
+	jsonStatus
+	Profiles []profileLsEntry `json:"profiles"`
+	Count    int              `json:"count"`
+}
+
+type profileLsEntry struct {
+	Tool        string `json:"tool"`
+	Name        string `json:"name"`
+	AuthMode    string `json:"auth_mode"`
+	Path        string `json:"path"`
+	Locked      bool   `json:"locked"`
+	Description string `json:"description,omitempty"`
+}
+
+func runProfileLsJSON(cmd *cobra.Command, args []string) error {
+	output := profileLsOutput{Profiles: []profileLsEntry{}}
+
+	byTool := map[string][]*profile.Profile{}
+	if len(args) > 0 {
+		tool := strings.ToLower(args[0])
+		profiles, err := profileStore.List(tool)
+		if err != nil {
+			return writeJSONResult(cmd, &output, err)
+		}
+		byTool[tool] = profiles
+	} else {
+		all, err := profileStore.ListAll()
+		if err != nil {
+			return writeJSONResult(cmd, &output, err)
+		}
+		byTool = all
+	}
+
+	toolNames := make([]string, 0, len(byTool))
+	for tool := range byTool {
+		toolNames = append(toolNames, tool)
+	}
+	sort.Strings(toolNames)
+
+	for _, tool := range toolNames {
+		for _, p := range byTool[tool] {
+			output.Profiles = append(output.Profiles, profileLsEntry{
+				Tool:        p.Provider,
+				Name:        p.Name,
+				AuthMode:    p.AuthMode,
+				Path:        p.BasePath,
+				Locked:      p.IsLocked(),
+				Description: p.Description,
+			})
+		}
+	}
+	output.Count = len(output.Profiles)
+	return writeJSONResult(cmd, &output, nil)
+}
+
+func init() {
+	profileLsCmd.Flags().Bool("json", false, "output as JSON")
+}
+
 var profileDeleteCmd = &cobra.Command{
 	Use:     "delete <tool> <name>",
 	Aliases: []string{"rm"},
@@ -1410,29 +2335,40 @@ var profileDeleteCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
 		name := args[1]
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		output := deleteOutput{Tool: tool, Profile: name}
+		finish := func(err error) error {
+			if jsonOutput {
+				return writeJSONResult(cmd, &output, err)
+			}
+			return err
+		}
 
 		force, _ := cmd.Flags().GetBool("force")
-		if !force {
-			fmt.Printf("Delete isolated profile %s/%s? [y/N]: ", tool, name)
-			var confirm string
-			fmt.Scanln(&confirm)
-			if strings.ToLower(confirm) != "y" {
-				fmt.Println("Cancelled")
-				return nil
+		if !force && !confirmPrompt(cmd, jsonOutput, fmt.Sprintf("Delete isolated profile %s/%s?", tool, name)) {
+			if jsonOutput {
+				return finish(errCancelled)
 			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Cancelled")
+			return nil
 		}
 
 		if err := profileStore.Delete(tool, name); err != nil {
-			return fmt.Errorf("delete profile: %w", err)
+			return finish(fmt.Errorf("delete profile: %w", err))
 		}
 
-		fmt.Printf("Deleted %s/%s\n", tool, name)
+		if jsonOutput {
+			return finish(nil)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s/%s\n", tool, name)
 		return nil
 	},
 }
 
 func init() {
 	profileDeleteCmd.Flags().Bool("force", false, "skip confirmation")
+	profileDeleteCmd.Flags().Bool("json", false, "output as JSON")
 }
 
 var profileStatusCmd = &cobra.Command{
@@ -1442,21 +2378,43 @@ var profileStatusCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
 		name := args[1]
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		output := profileStatusOutput{Tool: tool, Name: name}
+		finish := func(err error) error {
+			if jsonOutput {
+				return writeJSONResult(cmd, &output, err)
+			}
+			return err
+		}
 
 		prov, ok := registry.Get(tool)
 		if !ok {
-			return fmt.Errorf("unknown provider: %s", tool)
+			return finish(withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s", tool)))
 		}
 
 		prof, err := profileStore.Load(tool, name)
 		if err != nil {
-			return err
+			return finish(err)
 		}
 
 		ctx := context.Background()
 		status, err := prov.Status(ctx, prof)
 		if err != nil {
-			return fmt.Errorf("get status: %w", err)
+			return finish(fmt.Errorf("get status: %w", err))
+		}
+
+		if jsonOutput {
+			output.Path = prof.BasePath
+			output.AuthMode = prof.AuthMode
+			output.LoggedIn = status.LoggedIn
+			output.Locked = status.HasLockFile
+			output.Account = prof.AccountLabel
+			output.Description = prof.Description
+			if prof.HasBrowserConfig() {
+				output.Browser = prof.BrowserDisplayName()
+			}
+			return finish(nil)
 		}
 
 		fmt.Printf("Profile: %s/%s\n", tool, name)
@@ -1478,6 +2436,24 @@ var profileStatusCmd = &cobra.Command{
 	},
 }
 
+// profileStatusOutput is the JSON output structure for profile status.
+type profileStatusOutput struct {
+	jsonStatus
+	Tool        string `json:"tool"`
+	Name        string `json:"name"`
+	Path        string `json:"path,omitempty"`
+	AuthMode    string `json:"auth_mode,omitempty"`
+	LoggedIn    bool   `json:"logged_in"`
+	Locked      bool   `json:"locked"`
+	Account     string `json:"account,omitempty"`
+	Description string `json:"description,omitempty"`
+	Browser     string `json:"browser,omitempty"`
+}
+
+func init() {
+	profileStatusCmd.Flags().Bool("json", false, "output as JSON")
+}
+
 var profileUnlockCmd = &cobra.Command{
 	Use:   "unlock <tool> <name>",
 	Short: "Unlock a locked profile",
@@ -1546,10 +2522,7 @@ Examples:
 
 		// Force unlock - user accepted the risk
 		fmt.Printf("WARNING: Force-unlocking profile locked by running process (PID %d)\n", lockInfo.PID)
-		fmt.Printf("Force unlock %s/%s? This may cause data corruption! [y/N]: ", tool, name)
-		var confirm string
-		fmt.Scanln(&confirm)
-		if strings.ToLower(confirm) != "y" {
+		if !confirmPrompt(cmd, false, fmt.Sprintf("Force unlock %s/%s? This may cause data corruption!", tool, name)) {
 			fmt.Println("Cancelled")
 			return nil
 		}
@@ -1623,6 +2596,91 @@ func init() {
 	profileCmd.AddCommand(profileDescribeCmd)
 }
 
+var profileMCPCmd = &cobra.Command{
+	Use:   "mcp <tool> <name> [config.json]",
+	Short: "Set or show a profile's MCP servers",
+	Long: `Set or show the MCP servers an isolated Claude profile runs with.
+
+The config file is a JSON object of server name -> server config, or a
+.mcp.json-style {"mcpServers": {...}} file. It is rendered into the profile's
+~/.claude.json, replacing its user-scoped MCP servers. Strings may reference
+{{.Profile}}, {{.Home}} (the profile's HOME) and {{.ProfileDir}}.
+
+Use --clear to render an empty server list.
+
+Examples:
+  caam profile mcp claude work                     # Show configured servers
+  caam profile mcp claude work ./work-mcp.json     # Set servers
+  caam profile mcp claude work --clear             # Run with no MCP servers`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: runProfileMCP,
+}
+
+func init() {
+	profileMCPCmd.Flags().Bool("clear", false, "remove the profile's MCP servers")
+	profileCmd.AddCommand(profileMCPCmd)
+}
+
+func runProfileMCP(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	name := args[1]
+	out := cmd.OutOrStdout()
+
+	clearFlag, _ := cmd.Flags().GetBool("clear")
+	if clearFlag && len(args) == 3 {
+		return withExitCode(ExitUsage, fmt.Errorf("give a config file or --clear, not both"))
+	}
+
+	prof, err := profileStore.Load(tool, name)
+	if err != nil {
+		return err
+	}
+
+	if !clearFlag && len(args) == 2 {
+		names, err := claude.MCPServerNames(prof)
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Fprintf(out, "%s/%s has no MCP servers configured\n", tool, name)
+			return nil
+		}
+		fmt.Fprintf(out, "%s/%s MCP servers: %s\n", tool, name, strings.Join(names, ", "))
+		return nil
+	}
+
+	servers := "{}"
+	if !clearFlag {
+		if servers, err = mcpServersMetadata(tool, args[2]); err != nil {
+			return err
+		}
+	}
+	if prof.Metadata == nil {
+		prof.Metadata = make(map[string]string)
+	}
+	prof.Metadata[claude.MetaMCPServers] = servers
+	if err := prof.Save(); err != nil {
+		return fmt.Errorf("save profile: %w", err)
+	}
+
+	// PrepareProfile is idempotent and renders the new server list.
+	prov, ok := registry.Get(tool)
+	if !ok {
+		return fmt.Errorf("unknown provider: %s", tool)
+	}
+	if err := prov.PrepareProfile(context.Background(), prof); err != nil {
+		return fmt.Errorf("prepare profile: %w", err)
+	}
+
+	if clearFlag {
+		fmt.Fprintf(out, "Cleared MCP servers for %s/%s\n", tool, name)
+		return nil
+	}
+	names, _ := claude.MCPServerNames(prof)
+	fmt.Fprintf(out, "Set MCP servers for %s/%s: %s\n", tool, name, strings.Join(names, ", "))
+	return nil
+}
+
 var profileCloneCmd = &cobra.Command{
 	Use:   "clone <tool> <source-profile> <target-profile>",
 	Short: "Clone an existing profile",
@@ -1697,6 +2755,240 @@ func init() {
 	profileCmd.AddCommand(profileCloneCmd)
 }
 
+var profilePromoteCmd = &cobra.Command{
+	Use:   "promote <tool> <name> <vault-profile>",
+	Short: "Copy an isolated profile's auth into the vault",
+	Long: `Copies the auth files from an isolated profile into the vault so the same
+account can be used with 'caam activate' (swap mode).
+
+The isolated profile is left untouched.
+
+Examples:
+  caam profile promote claude work work
+  caam profile promote codex team team@corp.com
+  caam profile promote gemini personal personal --force  # Overwrite vault profile`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tool := strings.ToLower(args[0])
+		name := args[1]
+		vaultProfile := args[2]
+
+		if _, ok := tools[tool]; !ok {
+			return fmt.Errorf("unknown tool: %s", tool)
+		}
+		if authfile.IsSystemProfile(vaultProfile) {
+			return fmt.Errorf("profile names starting with '_' are reserved for system use")
+		}
+
+		prof, err := profileStore.Load(tool, name)
+		if err != nil {
+			return err
+		}
+
+		fileSet, err := isolatedAuthFileSet(tool, prof)
+		if err != nil {
+			return err
+		}
+		if !authfile.HasAuthFiles(fileSet) {
+			return fmt.Errorf("isolated profile %s/%s has no auth files (run 'caam login %s %s' first)", tool, name, tool, name)
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		if vaultHasProfile(tool, vaultProfile) && !force {
+			return fmt.Errorf("vault profile %s/%s already exists (use --force to overwrite)", tool, vaultProfile)
+		}
+
+		if err := vault.Backup(fileSet, vaultProfile); err != nil {
+			return fmt.Errorf("promote failed: %w", err)
+		}
+
+		fmt.Printf("Promoted isolated %s/%s → vault %s/%s\n", tool, name, tool, vaultProfile)
+		fmt.Printf("  Vault: %s\n", vault.ProfilePath(tool, vaultProfile))
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("  caam activate %s %s    # Switch to this account\n", tool, vaultProfile)
+		return nil
+	},
+}
+
+func init() {
+	profilePromoteCmd.Flags().Bool("force", false, "overwrite an existing vault profile")
+	profileCmd.AddCommand(profilePromoteCmd)
+}
+
+var profileSeedCmd = &cobra.Command{
+	Use:   "seed <tool> <name> --from-vault <profile>",
+	Short: "Initialize an isolated profile from vault auth",
+	Long: `Copies the auth files of a vault profile into an isolated profile so the same
+account can be used with 'caam exec' (parallel mode) without logging in again.
+
+The isolated profile is created if it doesn't exist yet.
+
+Examples:
+  caam profile seed claude work --from-vault work
+  caam profile seed codex team --from-vault team@corp.com
+  caam profile seed gemini personal --from-vault personal --force  # Overwrite existing auth`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tool := strings.ToLower(args[0])
+		name := args[1]
+
+		vaultProfile, _ := cmd.Flags().GetString("from-vault")
+		if strings.TrimSpace(vaultProfile) == "" {
+			return fmt.Errorf("--from-vault is required")
+		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		created, err := seedIsolatedProfile(cmd.Context(), tool, name, vaultProfile, force)
+		if err != nil {
+			return err
+		}
+
+		if created {
+			fmt.Printf("Created isolated profile %s/%s\n", tool, name)
+		}
+		fmt.Printf("Seeded isolated %s/%s from vault %s/%s\n", tool, name, tool, vaultProfile)
+		fmt.Printf("\nNext steps:\n")
+		fmt.Printf("  caam exec %s %s     # Run with this profile\n", tool, name)
+		return nil
+	},
+}
+
+func init() {
+	profileSeedCmd.Flags().String("from-vault", "", "vault profile to copy auth from (required)")
+	profileSeedCmd.Flags().Bool("force", false, "overwrite existing auth in the isolated profile")
+	profileCmd.AddCommand(profileSeedCmd)
+}
+
+var profileMirrorCmd = &cobra.Command{
+	Use:   "mirror <tool>",
+	Short: "Ensure an isolated profile exists for every vault profile",
+	Long: `Creates an isolated profile (same name) for every vault profile of a tool and
+seeds it with the vault auth, so any account can be used in swap mode
+('caam activate') or parallel mode ('caam exec') without logging in again.
+
+Isolated profiles that already have auth are left alone unless --force is given.
+System profiles (names starting with '_') are skipped.
+
+Examples:
+  caam profile mirror claude
+  caam profile mirror codex --force   # Re-seed existing isolated profiles`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tool := strings.ToLower(args[0])
+		if _, ok := tools[tool]; !ok {
+			return fmt.Errorf("unknown tool: %s", tool)
+		}
+		force, _ := cmd.Flags().GetBool("force")
+
+		profiles, err := vault.List(tool)
+		if err != nil {
+			return fmt.Errorf("list profiles: %w", err)
+		}
+
+		var created, seeded, skipped, failed int
+		for _, name := range profiles {
+			if authfile.IsSystemProfile(name) {
+				continue
+			}
+
+			if profileStore.Exists(tool, name) && !force {
+				prof, err := profileStore.Load(tool, name)
+				if err == nil {
+					if fileSet, err := isolatedAuthFileSet(tool, prof); err == nil && authfile.HasAuthFiles(fileSet) {
+						fmt.Printf("  %-24s skipped (already has auth)\n", name)
+						skipped++
+						continue
+					}
+				}
+			}
+
+			wasCreated, err := seedIsolatedProfile(cmd.Context(), tool, name, name, true)
+			if err != nil {
+				fmt.Printf("  %-24s failed: %v\n", name, err)
+				failed++
+				continue
+			}
+			if wasCreated {
+				fmt.Printf("  %-24s created\n", name)
+				created++
+			} else {
+				fmt.Printf("  %-24s seeded\n", name)
+				seeded++
+			}
+		}
+
+		if created+seeded+skipped+failed == 0 {
+			fmt.Printf("No vault profiles for %s\n", tool)
+			return nil
+		}
+
+		fmt.Printf("\nMirrored %s: %d created, %d seeded, %d skipped", tool, created, seeded, skipped)
+		if failed > 0 {
+			fmt.Printf(", %d failed\n", failed)
+			return fmt.Errorf("%d profile(s) could not be mirrored", failed)
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+func init() {
+	profileMirrorCmd.Flags().Bool("force", false, "re-seed isolated profiles that already have auth")
+	profileCmd.AddCommand(profileMirrorCmd)
+}
+
+// seedIsolatedProfile copies a vault profile's auth into an isolated profile,
+// creating the isolated profile first if needed. It returns true if the
+// isolated profile was created.
+func seedIsolatedProfile(ctx context.Context, tool, name, vaultProfile string, force bool) (bool, error) {
+	prov, ok := registry.Get(tool)
+	if !ok {
+		return false, fmt.Errorf("unknown provider: %s", tool)
+	}
+	if !vaultHasProfile(tool, vaultProfile) {
+		return false, fmt.Errorf("profile %s/%s not found in vault", tool, vaultProfile)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var prof *profile.Profile
+	var err error
+	created := !profileStore.Exists(tool, name)
+	if created {
+		prof, err = profileStore.Create(tool, name, "oauth")
+		if err != nil {
+			return false, fmt.Errorf("create profile: %w", err)
+		}
+		if err := prov.PrepareProfile(ctx, prof); err != nil {
+			profileStore.Delete(tool, name)
+			return false, fmt.Errorf("prepare profile: %w", err)
+		}
+	} else {
+		prof, err = profileStore.Load(tool, name)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	fileSet, err := isolatedAuthFileSet(tool, prof)
+	if err != nil {
+		return false, err
+	}
+	if !created && authfile.HasAuthFiles(fileSet) && !force {
+		return false, fmt.Errorf("isolated profile %s/%s already has auth (use --force to overwrite)", tool, name)
+	}
+
+	if err := vault.Restore(fileSet, vaultProfile); err != nil {
+		if created {
+			profileStore.Delete(tool, name)
+		}
+		return false, fmt.Errorf("seed failed: %w", err)
+	}
+
+	return created, nil
+}
+
 // loginCmd initiates login for an isolated profile.
 var loginCmd = &cobra.Command{
 	Use:   "login <tool> <profile>",
@@ -1709,45 +3001,82 @@ so the auth credentials are stored in the profile's directory.
 Examples:
   caam login codex work     # Login to work profile
   caam login claude home    # Login to home profile
-  caam login codex work --device-code  # Device code flow (if supported)`,
+  caam login codex work --device-code  # Device code flow (if supported)
+
+The device code flow shows its verification URL as a QR code, to finish the
+login on a phone when working over SSH; --no-qr turns that off.
+
+With --json, the tool's own login prompts are sent to stderr and stdout
+carries only the JSON result.`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
 		name := args[1]
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		deviceCode, _ := cmd.Flags().GetBool("device-code")
+
+		output := loginOutput{Tool: tool, Profile: name, DeviceCode: deviceCode}
+		finish := func(err error) error {
+			if jsonOutput {
+				return writeJSONResult(cmd, &output, err)
+			}
+			return err
+		}
 
 		prov, ok := registry.Get(tool)
 		if !ok {
-			return fmt.Errorf("unknown provider: %s", tool)
+			return finish(withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s", tool)))
 		}
 
 		prof, err := profileStore.Load(tool, name)
 		if err != nil {
-			return err
+			return finish(err)
 		}
 
-		ctx := context.Background()
-		deviceCode, _ := cmd.Flags().GetBool("device-code")
+		if jsonOutput {
+			// Providers write the login flow to os.Stdout; keep it off the JSON.
+			cmd.SetOut(cmd.OutOrStdout())
+			stdout := os.Stdout
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = stdout }()
+		}
+
+		noQR, _ := cmd.Flags().GetBool("no-qr")
+		ctx := devicecode.WithQR(context.Background(), !noQR)
 		if deviceCode {
 			deviceCodeProv, ok := prov.(provider.DeviceCodeProvider)
 			if !ok || !deviceCodeProv.SupportsDeviceCode() {
-				return fmt.Errorf("%s does not support --device-code", tool)
+				return finish(withExitCode(ExitUsage, fmt.Errorf("%s does not support --device-code", tool)))
 			}
 			if err := deviceCodeProv.LoginWithDeviceCode(ctx, prof); err != nil {
-				return fmt.Errorf("device-code login failed: %w", err)
+				return finish(fmt.Errorf("device-code login failed: %w", err))
 			}
 		} else {
 			if err := prov.Login(ctx, prof); err != nil {
-				return fmt.Errorf("login failed: %w", err)
+				return finish(fmt.Errorf("login failed: %w", err))
 			}
 		}
 
+		if jsonOutput {
+			return finish(nil)
+		}
 		fmt.Printf("\nLogin complete for %s/%s\n", tool, name)
 		return nil
 	},
 }
 
+// loginOutput is the JSON output structure for login command.
+type loginOutput struct {
+	jsonStatus
+	Tool       string `json:"tool"`
+	Profile    string `json:"profile"`
+	DeviceCode bool   `json:"device_code,omitempty"`
+}
+
 func init() {
 	loginCmd.Flags().Bool("device-code", false, "use device code flow (if supported)")
+	loginCmd.Flags().Bool("no-qr", false, "don't show the device code URL as a QR code")
+	loginCmd.Flags().Bool("json", false, "output as JSON")
 }
 
 // execCmd runs the CLI with an isolated profile.
@@ -1759,10 +3088,38 @@ var execCmd = &cobra.Command{
 This sets up HOME/CODEX_HOME/etc to use the profile's directory, then runs
 the tool with any additional arguments.
 
+With --bind (Linux only), <profile> is a vault profile instead: its auth files
+are bind-mounted over the real auth paths inside a private mount namespace
+that only the spawned tool sees. HOME stays as it is, the global auth files
+are never touched, and parallel sessions can use different accounts. This
+needs unprivileged user namespaces, and the real auth files must already
+exist (the mounts cover them). Tools that replace their auth file on refresh,
+instead of writing it in place, fail to do so under --bind.
+
+With --forwarded, <profile> is borrowed from the caam that serves
+$CAAM_AUTH_SOCK (usually your own machine over 'ssh -R'; see 'caam forward').
+Its auth files are written to a tmpfs profile and wiped when the tool exits.
+
 Examples:
   caam exec codex work                        # Interactive session
   caam exec codex work -- "implement feature"  # With prompt
-  caam exec claude home -- -p "fix bug"        # With flags`,
+  caam exec claude home -- -p "fix bug"        # With flags
+  caam exec --bind claude work                 # Vault profile, global HOME
+  caam exec --forwarded claude work            # Borrowed over SSH, see 'caam forward'
+
+With analytics.exec_history set to hash or truncate, each run is recorded
+per profile (arguments redacted); see 'caam history exec'.
+
+Pacing keeps batch jobs from spending an account's usage window at once:
+  caam config set pacing.max_sessions_per_hour 6
+  caam config set pacing.min_gap 5m
+A run over those limits waits for a slot (up to pacing.max_wait), moves to
+another profile of the same tool that is free and not in cooldown
+(--pace rotate), or fails with exit code 4 (--pace fail). --pace off skips
+pacing for one run. --forwarded runs can only wait or fail.
+
+With display.terminal_title on, the terminal title (or tmux window name)
+reads tool:profile while the tool runs and goes back when it exits.`,
 	Args: cobra.MinimumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tool := strings.ToLower(args[0])
@@ -1776,26 +3133,184 @@ Examples:
 
 		prov, ok := registry.Get(tool)
 		if !ok {
-			return fmt.Errorf("unknown provider: %s", tool)
+			return withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s", tool))
 		}
 
+		ctx := context.Background()
+		noLock, _ := cmd.Flags().GetBool("no-lock")
+		pace, _ := cmd.Flags().GetString("pace")
+		switch pace {
+		case "", config.PaceWait, config.PaceRotate, config.PaceFail, paceOff:
+		default:
+			return withExitCode(ExitUsage, fmt.Errorf("invalid --pace %q (supported: wait, rotate, fail, off)", pace))
+		}
+
+		if forwarded, _ := cmd.Flags().GetBool("forwarded"); forwarded {
+			name, err := paceExec(ctx, cmd.ErrOrStderr(), pace, tool, name, nil)
+			if err != nil {
+				return err
+			}
+			defer labelTerminal(tool, name)()
+			return runForwardedExec(prov, tool, name, toolArgs)
+		}
+
+		if bind, _ := cmd.Flags().GetBool("bind"); bind {
+			if !vaultHasProfile(tool, name) {
+				return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", tool, name))
+			}
+			var candidates []string
+			if names, err := vault.List(tool); err == nil {
+				for _, n := range names {
+					if !authfile.IsSystemProfile(n) {
+						candidates = append(candidates, n)
+					}
+				}
+			}
+			name, err := paceExec(ctx, cmd.ErrOrStderr(), pace, tool, name, candidates)
+			if err != nil {
+				return err
+			}
+			mounts, release, err := bindMountsFor(tool, name)
+			if err != nil {
+				return err
+			}
+			defer release()
+			defer labelTerminal(tool, name)()
+			started := time.Now()
+			err = runner.Run(ctx, exec.RunOptions{
+				Profile:      transientProfile(tool, name),
+				Provider:     prov,
+				Args:         toolArgs,
+				NoLock:       noLock,
+				UseGlobalEnv: true,
+				BindMounts:   mounts,
+			})
+			recordExecRun(tool, name, toolArgs, started, err)
+			if err == nil {
+				stampLoginVerified(tool, name)
+			}
+			return err
+		}
+
+		if !profileStore.Exists(tool, name) {
+			return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found", tool, name))
+		}
+		var candidates []string
+		if profiles, err := profileStore.List(tool); err == nil {
+			for _, p := range profiles {
+				candidates = append(candidates, p.Name)
+			}
+		}
+		name, err := paceExec(ctx, cmd.ErrOrStderr(), pace, tool, name, candidates)
+		if err != nil {
+			return err
+		}
 		prof, err := profileStore.Load(tool, name)
 		if err != nil {
 			return err
 		}
 
-		ctx := context.Background()
-		noLock, _ := cmd.Flags().GetBool("no-lock")
-
-		return runner.Run(ctx, exec.RunOptions{
+		defer labelTerminal(tool, name)()
+		started := time.Now()
+		err = runner.Run(ctx, exec.RunOptions{
 			Profile:  prof,
 			Provider: prov,
 			Args:     toolArgs,
 			NoLock:   noLock,
 		})
+		recordExecRun(tool, name, toolArgs, started, err)
+		if err == nil {
+			stampLoginVerified(tool, name)
+		}
+		return err
 	},
 }
 
 func init() {
 	execCmd.Flags().Bool("no-lock", false, "don't lock the profile during execution")
+	execCmd.Flags().Bool("bind", false, "bind-mount a vault profile's auth files in a private mount namespace (Linux)")
+	execCmd.Flags().Bool("forwarded", false, "borrow the profile from the forwarding agent at $CAAM_AUTH_SOCK (see 'caam forward')")
+	execCmd.MarkFlagsMutuallyExclusive("forwarded", "bind")
+	execCmd.Flags().String("pace", "", "when pacing limits are hit: wait, rotate, fail, or off (default pacing.when_limited)")
+	rootCmd.AddCommand(bindExecCmd)
+}
+
+// bindExecCmd runs inside the namespaces set up by 'caam exec --bind'.
+var bindExecCmd = &cobra.Command{
+	Use:                exec.BindHelperCommand,
+	Short:              "Internal helper for 'caam exec --bind'",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	// Skip the root setup: nothing here needs the stores or config.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exec.RunBindHelper(args)
+	},
+}
+
+// bindMountsFor maps a vault profile's files onto the tool's real auth paths.
+// An encrypted or keychain-stored profile is mounted from a plain copy;
+// release writes back what the tool changed there, such as a refreshed
+// token, and removes the copy. release is never nil.
+func bindMountsFor(tool, name string) (mounts []exec.BindMount, release func(), err error) {
+	if !vaultHasProfile(tool, name) {
+		return nil, func() {}, withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", tool, name))
+	}
+
+	profileDir, cleanup, err := vault.PlainProfileDir(tool, name)
+	if err != nil {
+		return nil, cleanup, err
+	}
+	mounts, err = bindMountsFrom(tool, name, profileDir)
+	if err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	release = func() {
+		if err := vault.SavePlainProfileDir(tool, name, profileDir); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not save %s/%s back to the vault: %v\n", tool, name, err)
+		}
+		cleanup()
+	}
+	return mounts, release, nil
+}
+
+func bindMountsFrom(tool, name, profileDir string) ([]exec.BindMount, error) {
+	fileSet := tools[tool]()
+	var mounts []exec.BindMount
+	for _, spec := range fileSet.Files {
+		src := filepath.Join(profileDir, filepath.Base(spec.Path))
+		if _, err := os.Stat(src); err != nil {
+			if spec.Required {
+				return nil, withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s is missing %s", tool, name, filepath.Base(spec.Path)))
+			}
+			continue
+		}
+		if _, err := os.Stat(spec.Path); err != nil {
+			if spec.Required {
+				return nil, fmt.Errorf("%s doesn't exist; --bind can only cover existing auth files (log in once, or use 'caam activate')", spec.Path)
+			}
+			fmt.Fprintf(os.Stderr, "warning: %s doesn't exist; not binding %s\n", spec.Path, filepath.Base(spec.Path))
+			continue
+		}
+		mounts = append(mounts, exec.BindMount{Source: src, Target: spec.Path})
+	}
+	if len(mounts) == 0 {
+		return nil, withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s has no auth files to bind", tool, name))
+	}
+	return mounts, nil
+}
+
+// transientProfile returns the isolated profile for tool/name, or a transient
+// one for vault-only profiles so locking and metadata still work.
+func transientProfile(tool, name string) *profile.Profile {
+	if prof, err := profileStore.Load(tool, name); err == nil {
+		return prof
+	}
+	return &profile.Profile{
+		Name:     name,
+		Provider: tool,
+		AuthMode: "oauth",
+		BasePath: profileStore.ProfilePath(tool, name),
+	}
 }
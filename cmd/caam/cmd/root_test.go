@@ -3,12 +3,16 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 	"github.com/spf13/cobra"
 )
@@ -156,6 +160,41 @@ func TestBackupCommandFlags(t *testing.T) {
 	}
 }
 
+// TestBackupCategories tests how --only and --include pick file categories.
+func TestBackupCategories(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{name: "default captures all", want: ""},
+		{name: "only", flags: map[string]string{"only": "credentials"}, want: "credentials"},
+		{name: "include adds to credentials", flags: map[string]string{"include": "history,settings"}, want: "credentials,settings,history"},
+		{name: "unknown category", flags: map[string]string{"only": "tokens"}, wantErr: true},
+		{name: "both flags", flags: map[string]string{"only": "credentials", "include": "settings"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().StringSlice("only", nil, "")
+			cmd.Flags().StringSlice("include", nil, "")
+			for name, value := range tt.flags {
+				if err := cmd.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := backupCategories(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("backupCategories() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if strings.Join(got, ",") != tt.want {
+				t.Errorf("backupCategories() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestActivateCommandFlags tests the activate command flags and aliases.
 func TestActivateCommandFlags(t *testing.T) {
 	if activateCmd.Use != "activate <tool> [profile-name]" {
@@ -365,6 +404,79 @@ func TestExecCommandFlags(t *testing.T) {
 	}
 }
 
+// TestBindMountsFor tests mapping a vault profile onto real auth paths for exec --bind.
+func TestBindMountsFor(t *testing.T) {
+	setupAccountsTest(t)
+	codexHome := filepath.Join(t.TempDir(), "codex_home")
+	t.Setenv("CODEX_HOME", codexHome)
+
+	writeVaultFile(t, "codex", "work", "auth.json", `{"access_token":"work"}`)
+
+	// The bind target must exist.
+	if _, _, err := bindMountsFor("codex", "work"); err == nil || !strings.Contains(err.Error(), "doesn't exist") {
+		t.Fatalf("bindMountsFor() error = %v, want missing target error", err)
+	}
+
+	if err := os.MkdirAll(codexHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(codexHome, "auth.json")
+	if err := os.WriteFile(target, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	mounts, release, err := bindMountsFor("codex", "work")
+	if err != nil {
+		t.Fatalf("bindMountsFor() error = %v", err)
+	}
+	release()
+	if len(mounts) != 1 || mounts[0].Target != target || mounts[0].Source != filepath.Join(vault.ProfilePath("codex", "work"), "auth.json") {
+		t.Errorf("bindMountsFor() = %+v", mounts)
+	}
+
+	if _, _, err := bindMountsFor("codex", "nope"); ExitCode(err) != ExitAuthMissing {
+		t.Errorf("missing profile exit code = %d, want %d", ExitCode(err), ExitAuthMissing)
+	}
+}
+
+// TestBindMountsFor_EncryptedVault tests that exec --bind mounts plaintext
+// and saves what the tool changed back, sealed.
+func TestBindMountsFor_EncryptedVault(t *testing.T) {
+	setupAccountsTest(t)
+	codexHome := filepath.Join(t.TempDir(), "codex_home")
+	t.Setenv("CODEX_HOME", codexHome)
+	if err := os.MkdirAll(codexHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(codexHome, "auth.json"), []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeVaultFile(t, "codex", "work", "auth.json", `{"access_token":"work"}`)
+	encryptTestVault(t)
+
+	mounts, release, err := bindMountsFor("codex", "work")
+	if err != nil {
+		t.Fatalf("bindMountsFor() error = %v", err)
+	}
+	if got, _ := os.ReadFile(mounts[0].Source); string(got) != `{"access_token":"work"}` {
+		t.Fatalf("mount source = %q, want plaintext", got)
+	}
+	if err := os.WriteFile(mounts[0].Source, []byte(`{"access_token":"refreshed"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	release()
+
+	vaultFile := filepath.Join(vault.ProfilePath("codex", "work"), "auth.json")
+	if got, err := vault.ReadFile(vaultFile); err != nil || string(got) != `{"access_token":"refreshed"}` {
+		t.Errorf("vault file = %q, %v; want the refreshed token", got, err)
+	}
+	if raw, _ := os.ReadFile(vaultFile); strings.Contains(string(raw), "refreshed") {
+		t.Error("the saved file should be sealed")
+	}
+	if _, err := os.Stat(mounts[0].Source); !os.IsNotExist(err) {
+		t.Error("release should remove the plain copy")
+	}
+}
+
 // TestToolsMap verifies the tools map contains expected providers.
 func TestToolsMap(t *testing.T) {
 	expectedTools := []string{"codex", "claude", "gemini"}
@@ -534,3 +646,85 @@ func TestCommandUsageStrings(t *testing.T) {
 		}
 	}
 }
+
+func TestNewProfileName(t *testing.T) {
+	if got, err := newProfileName("work", false); err != nil || got != "work" {
+		t.Errorf("newProfileName(work) = %q, %v", got, err)
+	}
+	_, err := newProfileName("work account", false)
+	if err == nil || !strings.Contains(err.Error(), "--sanitize") || ExitCode(err) != ExitUsage {
+		t.Errorf("newProfileName without --sanitize error = %v, want a usage error suggesting --sanitize", err)
+	}
+	if got, err := newProfileName("work account", true); err != nil || got != "work-account" {
+		t.Errorf("newProfileName with --sanitize = %q, %v; want work-account", got, err)
+	}
+	if _, err := newProfileName("!!!", true); err == nil {
+		t.Error("newProfileName(!!!) should fail even with --sanitize")
+	}
+}
+
+func TestDBUnavailable_WarnsOnceAndActivateStillWorks(t *testing.T) {
+	tmpDir := t.TempDir()
+	caamHome := filepath.Join(tmpDir, "caam_home")
+	codexHome := filepath.Join(tmpDir, "codex_home")
+	t.Setenv("CAAM_HOME", caamHome)
+	t.Setenv("CODEX_HOME", codexHome)
+	for _, dir := range []string{caamHome, codexHome} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file where the data directory belongs makes the database unopenable.
+	if err := os.WriteFile(filepath.Join(caamHome, "data"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	spmCfg := []byte("version: 1\nstealth:\n  cooldown:\n    enabled: true\n")
+	if err := os.WriteFile(filepath.Join(caamHome, "config.yaml"), spmCfg, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings bytes.Buffer
+	oldOut := dbWarnOut
+	dbWarnOut = &warnings
+	dbWarnOnce = sync.Once{}
+	t.Cleanup(func() {
+		dbWarnOut = oldOut
+		dbWarnOnce = sync.Once{}
+	})
+
+	oldVault := vault
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	t.Cleanup(func() { vault = oldVault })
+	profileDir := vault.ProfilePath("codex", "work")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	target := []byte(`{"access_token":"work"}`)
+	if err := os.WriteFile(filepath.Join(profileDir, "auth.json"), target, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := getDB(); !errors.Is(err, caamdb.ErrUnavailable) {
+		t.Fatalf("getDB() error = %v, want ErrUnavailable", err)
+	}
+	cmd := &cobra.Command{}
+	cmd.SetOut(ioDiscard{})
+	cmd.Flags().Bool("backup-current", false, "")
+	cmd.Flags().Bool("force", false, "")
+	if err := runActivate(cmd, []string{"codex", "work"}); err != nil {
+		t.Fatalf("runActivate() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(codexHome, "auth.json")); string(got) != string(target) {
+		t.Errorf("active auth = %q, want %q", got, target)
+	}
+	if profiles, err := vault.List("codex"); err != nil || len(profiles) != 1 {
+		t.Errorf("List() = %v, %v; want the work profile", profiles, err)
+	}
+
+	if n := strings.Count(warnings.String(), "warning:"); n != 1 {
+		t.Errorf("got %d warnings, want 1:\n%s", n, warnings.String())
+	}
+	if !strings.Contains(warnings.String(), "Cooldowns, usage history and analytics are off") {
+		t.Errorf("warning should say what is off, got:\n%s", warnings.String())
+	}
+}
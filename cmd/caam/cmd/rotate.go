@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+// rotateCmd switches to the best profile other than the live one.
+var rotateCmd = &cobra.Command{
+	Use:   "rotate <tool>",
+	Short: "Switch to the next best profile, skipping cooldowns",
+	Long: `Moves a tool off its current profile onto the best other one, for when an
+account has hit its usage limit.
+
+Profiles are ranked with the same scoring as 'caam robot next' (health,
+token expiry, recent errors, estimated limit headroom, recency; see
+'caam explain next'). Profiles in cooldown, quarantined profiles, system
+profiles and profiles of another class than this directory allows are
+skipped, and a pin wins unless it is the current profile.
+
+The switch goes through the usual activation safety: unsaved live auth is
+backed up first (safety.auto_backup_before_switch). The activation is
+recorded in the activity database like 'caam activate'.
+
+Examples:
+  caam rotate claude
+  caam rotate codex --dry-run
+  caam rotate claude --strategy lru --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRotate,
+}
+
+// rotateOutput is the JSON output structure for rotate command.
+type rotateOutput struct {
+	jsonStatus
+	Tool     string `json:"tool"`
+	Previous string `json:"previous_profile,omitempty"`
+	// Profile is the profile now live, or the one a dry run would pick.
+	Profile    string   `json:"profile,omitempty"`
+	Score      float64  `json:"score"`
+	Reasons    []string `json:"reasons,omitempty"`
+	DryRun     bool     `json:"dry_run,omitempty"`
+	AutoBackup string   `json:"auto_backup,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(rotateCmd)
+	rotateCmd.Flags().Bool("dry-run", false, "show the profile that would be picked without switching")
+	rotateCmd.Flags().String("strategy", "smart", "selection strategy: smart, lru, random")
+	rotateCmd.Flags().Bool("json", false, "output as JSON")
+	registerValueCompletion(rotateCmd, "strategy", "smart", "lru", "random")
+}
+
+func runRotate(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	strategy, _ := cmd.Flags().GetString("strategy")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	out := cmd.OutOrStdout()
+
+	output := rotateOutput{Tool: tool, DryRun: dryRun}
+	finish := func(err error) error {
+		if jsonOutput {
+			return writeJSONResult(cmd, &output, err)
+		}
+		return err
+	}
+
+	getFileSet, ok := tools[tool]
+	if !ok {
+		return finish(withErrorCode("INVALID_PROVIDER", fmt.Errorf("unknown tool: %s (supported: %s)", tool, strings.Join(knownTools(), ", "))))
+	}
+	fileSet := getFileSet()
+	current, _ := vault.ActiveProfile(fileSet)
+	output.Previous = current
+
+	all, err := vault.List(tool)
+	if err != nil {
+		return finish(fmt.Errorf("list profiles: %w", err))
+	}
+	var profiles []string
+	for _, name := range all {
+		if !authfile.IsSystemProfile(name) && name != current {
+			profiles = append(profiles, name)
+		}
+	}
+	if len(profiles) == 0 {
+		if current != "" {
+			return finish(withExitCode(ExitNoHealthyProfile, fmt.Errorf("no %s profile to rotate to besides %s", tool, current)))
+		}
+		return finish(withErrorCode("NO_PROFILES", fmt.Errorf("no profiles found for %s; create one with 'caam backup %s <name>'", tool, tool)))
+	}
+	profiles, required := filterProfilesForClass(tool, profiles)
+	if len(profiles) == 0 {
+		return finish(withExitCode(ExitNoHealthyProfile, fmt.Errorf("no other %s profiles of class %s for this directory", tool, required)))
+	}
+
+	db, _ := getDB()
+	ranked := rankRobotNext(db, tool, profiles, strategy, false, time.Now())
+	if len(ranked) == 0 {
+		return finish(withExitCode(ExitAllInCooldown, fmt.Errorf("every other %s profile is in cooldown or quarantined", tool)))
+	}
+	best := ranked[0]
+	output.Profile = best.name
+	output.Score = best.score.Score
+	output.Reasons = best.reasons
+
+	if dryRun {
+		if jsonOutput {
+			return finish(nil)
+		}
+		fmt.Fprintf(out, "Would switch %s to '%s' (score %.0f: %s)\n", tool, best.name, best.score.Score, strings.Join(best.reasons, "; "))
+		return nil
+	}
+
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		spmCfg = config.DefaultSPMConfig()
+	}
+	safety, err := vault.Activate(fileSet, best.name, activateOptions(spmCfg))
+	output.AutoBackup = safety.AutoBackup
+	if !jsonOutput {
+		printActivationSafety(tool, safety)
+	}
+	if err != nil {
+		return finish(fmt.Errorf("activate failed: %w", err))
+	}
+	recordActivationMachine(tool, best.name)
+
+	if spmCfg.Analytics.Enabled && db != nil {
+		_ = db.LogEvent(caamdb.Event{
+			Type:        caamdb.EventActivate,
+			Provider:    tool,
+			ProfileName: best.name,
+			Details: map[string]any{
+				"previous_profile": current,
+				"selection_source": "rotate",
+				"score":            best.score.Score,
+			},
+		})
+	}
+
+	if jsonOutput {
+		return finish(nil)
+	}
+	if current != "" {
+		fmt.Fprintf(out, "Rotated %s from '%s' to '%s'\n", tool, current, best.name)
+	} else {
+		fmt.Fprintf(out, "Activated %s profile '%s'\n", tool, best.name)
+	}
+	if len(best.reasons) > 0 {
+		fmt.Fprintf(out, "  Picked for: %s\n", strings.Join(best.reasons, "; "))
+	}
+	return nil
+}
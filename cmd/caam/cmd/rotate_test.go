@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+func runRotateJSON(t *testing.T, tool string, dryRun bool) (rotateOutput, error) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("dry-run", dryRun, "")
+	cmd.Flags().String("strategy", "smart", "")
+	cmd.Flags().Bool("json", true, "")
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	err := runRotate(cmd, []string{tool})
+	var out rotateOutput
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out), buf.String())
+	return out, err
+}
+
+func TestRotate_SwitchesPastCooldownAndRecords(t *testing.T) {
+	livePath := setupDeactivateTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "busy", "bob@example.com")
+	writeClaudeVaultProfile(t, "spare", "carol@example.com")
+	require.NoError(t, vault.Restore(tools["claude"](), "work"))
+
+	db, err := getDB()
+	require.NoError(t, err)
+	_, err = db.SetCooldown("claude", "busy", time.Now().UTC(), time.Hour, "")
+	require.NoError(t, err)
+
+	preview, err := runRotateJSON(t, "claude", true)
+	require.NoError(t, err)
+	assert.Equal(t, "spare", preview.Profile)
+	assert.True(t, preview.DryRun)
+	live, err := os.ReadFile(livePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(live), "tok-work", "dry run must not switch")
+
+	out, err := runRotateJSON(t, "claude", false)
+	require.NoError(t, err)
+	assert.True(t, out.Success)
+	assert.Equal(t, "work", out.Previous)
+	assert.Equal(t, "spare", out.Profile)
+	live, err = os.ReadFile(livePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(live), "tok-spare")
+
+	events, err := db.ListRecentEvents(10)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.Equal(t, caamdb.EventActivate, events[0].Type)
+	assert.Equal(t, "spare", events[0].ProfileName)
+}
+
+func TestRotate_AllOthersInCooldown(t *testing.T) {
+	setupDeactivateTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	writeClaudeVaultProfile(t, "busy", "bob@example.com")
+	require.NoError(t, vault.Restore(tools["claude"](), "work"))
+
+	db, err := getDB()
+	require.NoError(t, err)
+	_, err = db.SetCooldown("claude", "busy", time.Now().UTC(), time.Hour, "")
+	require.NoError(t, err)
+
+	out, _ := runRotateJSON(t, "claude", false)
+	assert.False(t, out.Success)
+	assert.Equal(t, "ALL_BLOCKED", out.ErrorCode)
+	assert.Equal(t, ExitAllInCooldown, out.ExitCode)
+}
+
+func TestRotate_OnlyCurrentProfile(t *testing.T) {
+	setupDeactivateTest(t)
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	require.NoError(t, vault.Restore(tools["claude"](), "work"))
+
+	out, _ := runRotateJSON(t, "claude", false)
+	assert.False(t, out.Success)
+	assert.Equal(t, ExitNoHealthyProfile, out.ExitCode)
+}
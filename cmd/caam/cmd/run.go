@@ -15,6 +15,7 @@ import (
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authpool"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/exec"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/notify"
@@ -71,11 +72,13 @@ And rate limits will be handled automatically!`,
 func init() {
 	rootCmd.AddCommand(runCmd)
 	runCmd.Flags().Int("max-retries", 1, "maximum retry attempts on rate limit (0 = no retries)")
-	runCmd.Flags().Duration("cooldown", 60*time.Minute, "cooldown duration after rate limit")
+	runCmd.Flags().Var(durations.NewValue(60*time.Minute), "cooldown", "cooldown duration after rate limit (e.g. 90m, 1d)")
 	runCmd.Flags().Bool("quiet", false, "suppress profile switch notifications")
 	runCmd.Flags().String("algorithm", "smart", "rotation algorithm (smart, round_robin, random)")
 	runCmd.Flags().Bool("precheck", false, "check usage levels before running and switch if near limit")
 	runCmd.Flags().Float64("precheck-threshold", 0.8, "usage threshold for precheck switching (0-1)")
+	registerDurationCompletion(runCmd, "cooldown")
+	registerValueCompletion(runCmd, "algorithm", rotationAlgorithms...)
 }
 
 func runWrap(cmd *cobra.Command, args []string) error {
@@ -87,7 +90,7 @@ func runWrap(cmd *cobra.Command, args []string) error {
 
 	// Validate tool
 	if _, ok := tools[tool]; !ok {
-		return fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+		return fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 	}
 
 	// Parse CLI args (everything after the tool name)
@@ -99,6 +102,9 @@ func runWrap(cmd *cobra.Command, args []string) error {
 	// Get flags
 	quiet, _ := cmd.Flags().GetBool("quiet")
 	algorithmStr, _ := cmd.Flags().GetString("algorithm")
+	if !cmd.Flags().Changed("algorithm") && activeContext != nil && activeContext.Strategy != "" {
+		algorithmStr = activeContext.Strategy
+	}
 	cooldownDur, _ := cmd.Flags().GetDuration("cooldown")
 
 	// Parse algorithm
@@ -120,12 +126,14 @@ func runWrap(cmd *cobra.Command, args []string) error {
 	}
 
 	// Initialize database
+	// Non-fatal: cooldowns won't be recorded but execution can continue.
+	// getDB has already warned about it.
 	db, err := getDB()
 	if err != nil {
-		// Non-fatal: cooldowns won't be recorded but execution can continue
-		fmt.Fprintf(os.Stderr, "warning: database unavailable, cooldowns will not be recorded\n")
 		db = nil
 	}
+	// Link profiles to accounts so a limit hit also cools down same-account profiles.
+	syncAccountLinks(db, tool)
 
 	// Initialize health storage
 	healthStore := health.NewStorage("")
@@ -162,6 +170,7 @@ func runWrap(cmd *cobra.Command, args []string) error {
 
 	// Initialize Rotation Selector
 	selector := rotation.NewSelector(algorithm, healthStore, db)
+	selector.SetExcluded(quarantinedProfiles(tool))
 
 	// Initialize Runner
 	if runner == nil {
@@ -365,6 +374,7 @@ func runPrecheck(tool string, threshold float64, quiet bool, db *caamdb.DB, algo
 	// Use rotation selector with usage data
 	selector := rotation.NewSelector(algorithm, nil, db)
 	selector.SetUsageData(usageData)
+	selector.SetExcluded(quarantinedProfiles(tool))
 
 	result, err := selector.Select(tool, allProfiles, currentProfile)
 	if err != nil || result.Selected == currentProfile {
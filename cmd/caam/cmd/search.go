@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <term>",
+	Short: "Search profiles across every provider",
+	Long: `Searches profile names, account emails, tags, notes (profile descriptions)
+and project associations across all providers, case-insensitively.
+
+Examples:
+  caam search acme             # profiles named, tagged or noted "acme"
+  caam search @company.com     # every login on the company domain
+  caam search ~/src/client     # profiles associated with that project
+  caam search work --provider claude --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().String("provider", "", "only search this provider")
+	searchCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// searchMatch is one field of a profile that contains the search term.
+type searchMatch struct {
+	Field string `json:"field"` // name, email, tag, notes or project
+	Value string `json:"value"`
+}
+
+// searchResult is a profile with at least one matching field.
+type searchResult struct {
+	Provider string        `json:"provider"`
+	Profile  string        `json:"profile"`
+	Vault    bool          `json:"vault"`
+	Isolated bool          `json:"isolated"`
+	Email    string        `json:"email,omitempty"`
+	Matches  []searchMatch `json:"matches"`
+}
+
+type searchOutput struct {
+	jsonStatus
+	Query   string         `json:"query"`
+	Results []searchResult `json:"results"`
+	Count   int            `json:"count"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	providerFilter, _ := cmd.Flags().GetString("provider")
+
+	query := strings.TrimSpace(strings.Join(args, " "))
+	output := searchOutput{Query: query, Results: []searchResult{}}
+	var err error
+	switch {
+	case query == "":
+		err = withExitCode(ExitUsage, fmt.Errorf("search term is empty"))
+	default:
+		providers := knownTools()
+		if providerFilter != "" {
+			tool := strings.ToLower(providerFilter)
+			if _, ok := tools[tool]; !ok {
+				err = withExitCode(ExitUsage, fmt.Errorf("unknown provider: %s (supported: %s)", providerFilter, supportedTools()))
+				break
+			}
+			providers = []string{tool}
+		}
+		output.Results, err = searchProfiles(providers, query)
+		output.Count = len(output.Results)
+	}
+	if jsonOutput {
+		return writeJSONResult(cmd, &output, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if output.Count == 0 {
+		fmt.Fprintf(out, "No profiles match %q.\n", query)
+		return nil
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tKIND\tEMAIL\tMATCHED")
+	for _, r := range output.Results {
+		var matched []string
+		for _, m := range r.Matches {
+			if m.Field == "name" {
+				matched = append(matched, "name")
+				continue
+			}
+			matched = append(matched, m.Field+": "+m.Value)
+		}
+		email := r.Email
+		if email == "" {
+			email = "-"
+		}
+		fmt.Fprintf(w, "%s/%s\t%s\t%s\t%s\n", r.Provider, r.Profile, searchKind(r), email, strings.Join(matched, "; "))
+	}
+	return w.Flush()
+}
+
+// searchProfiles returns the vault and isolated profiles of providers that
+// contain query in any searchable field, ordered by provider and name.
+func searchProfiles(providers []string, query string) ([]searchResult, error) {
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+	needle := strings.ToLower(query)
+	contains := func(s string) bool {
+		return s != "" && strings.Contains(strings.ToLower(s), needle)
+	}
+
+	byKey := make(map[string]*searchResult)
+	entry := func(tool, name string) *searchResult {
+		key := tool + "/" + name
+		if r, ok := byKey[key]; ok {
+			return r
+		}
+		r := &searchResult{Provider: tool, Profile: name}
+		byKey[key] = r
+		return r
+	}
+	var matched []*searchResult
+	seen := make(map[*searchResult]bool)
+	add := func(r *searchResult, field, value string) {
+		for _, m := range r.Matches {
+			if m.Field == field && m.Value == value {
+				return
+			}
+		}
+		r.Matches = append(r.Matches, searchMatch{Field: field, Value: value})
+		if !seen[r] {
+			seen[r] = true
+			matched = append(matched, r)
+		}
+	}
+
+	for _, tool := range providers {
+		names, err := vault.List(tool)
+		if err != nil {
+			return nil, fmt.Errorf("list %s vault profiles: %w", tool, err)
+		}
+		for _, name := range names {
+			if authfile.IsSystemProfile(name) {
+				continue
+			}
+			r := entry(tool, name)
+			r.Vault = true
+			if id := getVaultIdentity(tool, name); id != nil && id.Email != "" {
+				r.Email = id.Email
+			}
+		}
+
+		if profileStore != nil {
+			profs, err := profileStore.List(tool)
+			if err == nil {
+				for _, prof := range profs {
+					r := entry(tool, prof.Name)
+					r.Isolated = true
+					if r.Email == "" && prof.Identity != nil {
+						r.Email = prof.Identity.Email
+					}
+					for _, tag := range prof.Tags {
+						if contains(tag) {
+							add(r, "tag", tag)
+						}
+					}
+					if contains(prof.Description) {
+						add(r, "notes", prof.Description)
+					}
+				}
+			}
+		}
+	}
+
+	for _, r := range byKey {
+		if contains(r.Profile) {
+			add(r, "name", r.Profile)
+		}
+		if contains(r.Email) {
+			add(r, "email", r.Email)
+		}
+	}
+
+	if projectStore != nil {
+		if data, err := projectStore.Load(); err == nil {
+			wanted := make(map[string]bool, len(providers))
+			for _, tool := range providers {
+				wanted[tool] = true
+			}
+			for projectPath, assoc := range data.Associations {
+				if !contains(projectPath) {
+					continue
+				}
+				for tool, name := range assoc {
+					if wanted[tool] {
+						add(entry(tool, name), "project", projectPath)
+					}
+				}
+			}
+		}
+	}
+
+	results := make([]searchResult, 0, len(matched))
+	for _, r := range matched {
+		sort.SliceStable(r.Matches, func(i, j int) bool {
+			return searchFieldOrder(r.Matches[i].Field) < searchFieldOrder(r.Matches[j].Field)
+		})
+		results = append(results, *r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Provider != results[j].Provider {
+			return results[i].Provider < results[j].Provider
+		}
+		return results[i].Profile < results[j].Profile
+	})
+	return results, nil
+}
+
+func searchFieldOrder(field string) int {
+	switch field {
+	case "name":
+		return 0
+	case "email":
+		return 1
+	case "tag":
+		return 2
+	case "notes":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// searchKind says where a result's profile lives.
+func searchKind(r searchResult) string {
+	switch {
+	case r.Vault && r.Isolated:
+		return "vault+isolated"
+	case r.Vault:
+		return "vault"
+	case r.Isolated:
+		return "isolated"
+	default:
+		return "project only"
+	}
+}
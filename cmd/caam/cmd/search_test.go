@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+)
+
+func newSearchTestCmd(jsonOutput bool, provider string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("provider", provider, "")
+	cmd.Flags().Bool("json", jsonOutput, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func setupSearchTest(t *testing.T) {
+	t.Helper()
+	setupAccountsTest(t)
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+	originalProjects := projectStore
+	projectStore = project.NewStore(filepath.Join(t.TempDir(), "projects.json"))
+	t.Cleanup(func() { projectStore = originalProjects })
+
+	writeClaudeVaultProfile(t, "work", "alice@acme.com")
+	writeClaudeVaultProfile(t, "home", "bob@example.com")
+
+	prof, err := profileStore.Create("codex", "client", "oauth")
+	require.NoError(t, err)
+	require.NoError(t, prof.AddTag("acme-billing"))
+	prof.Description = "Contract work for Globex"
+	require.NoError(t, prof.Save())
+
+	require.NoError(t, projectStore.SetAssociation("/src/acme-site", "claude", "home"))
+}
+
+func runSearchJSON(t *testing.T, provider string, args ...string) searchOutput {
+	t.Helper()
+	cmd, out := newSearchTestCmd(true, provider)
+	require.NoError(t, runSearch(cmd, args))
+	var output searchOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &output))
+	return output
+}
+
+func TestSearch_MatchesEveryField(t *testing.T) {
+	setupSearchTest(t)
+
+	output := runSearchJSON(t, "", "ACME")
+	assert.True(t, output.Success, output.Error)
+	require.Equal(t, 3, output.Count)
+
+	assert.Equal(t, "claude", output.Results[0].Provider)
+	assert.Equal(t, "home", output.Results[0].Profile)
+	assert.Equal(t, []searchMatch{{Field: "project", Value: "/src/acme-site"}}, output.Results[0].Matches)
+
+	assert.Equal(t, "work", output.Results[1].Profile)
+	assert.True(t, output.Results[1].Vault)
+	assert.Equal(t, []searchMatch{{Field: "email", Value: "alice@acme.com"}}, output.Results[1].Matches)
+
+	assert.Equal(t, "codex", output.Results[2].Provider)
+	assert.True(t, output.Results[2].Isolated)
+	assert.Equal(t, []searchMatch{{Field: "tag", Value: "acme-billing"}}, output.Results[2].Matches)
+
+	notes := runSearchJSON(t, "", "globex")
+	require.Equal(t, 1, notes.Count)
+	assert.Equal(t, "notes", notes.Results[0].Matches[0].Field)
+}
+
+func TestSearch_ProviderFilter(t *testing.T) {
+	setupSearchTest(t)
+
+	output := runSearchJSON(t, "codex", "acme")
+	require.Equal(t, 1, output.Count)
+	assert.Equal(t, "client", output.Results[0].Profile)
+
+	cmd, _ := newSearchTestCmd(false, "nope")
+	err := runSearch(cmd, []string{"acme"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
+
+func TestSearch_TextOutput(t *testing.T) {
+	setupSearchTest(t)
+
+	cmd, out := newSearchTestCmd(false, "")
+	require.NoError(t, runSearch(cmd, []string{"work"}))
+	assert.Contains(t, out.String(), "claude/work")
+	assert.Contains(t, out.String(), "alice@acme.com")
+	assert.Contains(t, out.String(), "codex/client")
+	assert.Contains(t, out.String(), "notes: Contract work for Globex")
+
+	cmd, out = newSearchTestCmd(false, "")
+	require.NoError(t, runSearch(cmd, []string{"zzz"}))
+	assert.Contains(t, out.String(), `No profiles match "zzz".`)
+}
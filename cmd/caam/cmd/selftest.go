@@ -0,0 +1,508 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check that vault and database writes hold up when steps fail",
+	Long: `Runs backup, activate, import and database loops against a throwaway
+vault, auth layout and database in a temp directory, and checks caam's
+guarantees after every step:
+
+  backup    a failed backup leaves the profile as it was (a new one is not created)
+  activate  a failed activation leaves the live login as it was, never a mix
+  import    a failed import leaves every profile as it was
+  event     an activation is logged together with its stats, or not at all
+  cooldown  a cooldown is set on an account's linked profiles together, or not at all
+
+and that no temp files are left behind.
+
+With --chaos, file copies, renames and database writes fail at random while
+it runs (the same faults CAAM_FAULT_INJECT injects), so the rollback paths
+are what gets tested. Without it the loops run clean. Your real vault, auth
+files and database are never touched. Rerun with the printed --seed to
+reproduce a failure.
+
+Examples:
+  caam selftest --chaos
+  caam selftest --chaos --iterations 1000 --seed 42
+  caam selftest --chaos --rate 0.3 --json`,
+	Args: cobra.NoArgs,
+	RunE: runSelftest,
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+	selftestCmd.Flags().Bool("chaos", false, "inject random copy, rename and database failures")
+	selftestCmd.Flags().Int("iterations", 200, "number of steps to run")
+	selftestCmd.Flags().Int64("seed", 0, "random seed (0 picks one)")
+	selftestCmd.Flags().Float64("rate", 0.05, "with --chaos, probability that each copy, rename or database write fails")
+	selftestCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// selftestSteps lists the step kinds in the order they're reported.
+var selftestSteps = []string{"backup", "activate", "import", "event", "cooldown"}
+
+// selftestStep counts one kind of step.
+type selftestStep struct {
+	Runs   int `json:"runs"`
+	Failed int `json:"failed"`
+}
+
+type selftestOutput struct {
+	jsonStatus
+	Chaos      bool                     `json:"chaos"`
+	Seed       int64                    `json:"seed"`
+	Rate       float64                  `json:"rate,omitempty"`
+	Iterations int                      `json:"iterations"`
+	Steps      map[string]*selftestStep `json:"steps"`
+	Faults     map[string]int           `json:"faults"`
+	Violations []string                 `json:"violations"`
+}
+
+// maxSelftestViolations caps how many violations are listed; the count
+// still covers all of them.
+const maxSelftestViolations = 20
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	chaos, _ := cmd.Flags().GetBool("chaos")
+	iterations, _ := cmd.Flags().GetInt("iterations")
+	seed, _ := cmd.Flags().GetInt64("seed")
+	rate, _ := cmd.Flags().GetFloat64("rate")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if iterations <= 0 {
+		return withExitCode(ExitUsage, fmt.Errorf("--iterations must be positive"))
+	}
+	if rate < 0 || rate > 1 {
+		return withExitCode(ExitUsage, fmt.Errorf("--rate must be between 0 and 1"))
+	}
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	output := selftestOutput{Chaos: chaos, Seed: seed, Iterations: iterations}
+	if chaos {
+		output.Rate = rate
+	}
+	violations, err := runSelftestLoops(&output, rate)
+	if err == nil && violations > 0 {
+		err = fmt.Errorf("%d guarantee violation(s); rerun with --seed %d to reproduce", violations, seed)
+	}
+	if jsonOutput {
+		return writeJSONResult(cmd, &output, err)
+	}
+
+	out := cmd.OutOrStdout()
+	mode := "clean"
+	if chaos {
+		mode = fmt.Sprintf("chaos, fault rate %g", rate)
+	}
+	fmt.Fprintf(out, "Self-test (%s, seed %d, %d iterations)\n", mode, seed, iterations)
+	for _, name := range selftestSteps {
+		if s := output.Steps[name]; s != nil {
+			fmt.Fprintf(out, "  %-9s %4d runs, %d failed\n", name, s.Runs, s.Failed)
+		}
+	}
+	if chaos {
+		var faults []string
+		for _, op := range faultinject.Ops {
+			faults = append(faults, fmt.Sprintf("%s %d", op, output.Faults[string(op)]))
+		}
+		fmt.Fprintf(out, "Injected faults: %s\n", strings.Join(faults, ", "))
+	}
+	for _, v := range output.Violations {
+		fmt.Fprintf(out, "  [!] %s\n", v)
+	}
+	if err != nil {
+		return err
+	}
+	if chaos {
+		fmt.Fprintln(out, "All guarantees held: every failed step left the vault, live auth and database as they were.")
+	} else {
+		fmt.Fprintln(out, "All guarantees held.")
+	}
+	return nil
+}
+
+// runSelftestLoops builds a scratch world, runs output.Iterations random
+// steps in it and returns the number of violations found.
+func runSelftestLoops(output *selftestOutput, rate float64) (int, error) {
+	root, err := os.MkdirTemp("", "caam-selftest-")
+	if err != nil {
+		return 0, fmt.Errorf("create scratch dir: %w", err)
+	}
+	defer os.RemoveAll(root)
+
+	w, err := newSelftestWorld(root, output.Seed)
+	if err != nil {
+		return 0, err
+	}
+	defer w.db.Close()
+
+	output.Steps = make(map[string]*selftestStep)
+	for _, name := range selftestSteps {
+		output.Steps[name] = &selftestStep{}
+	}
+	output.Faults = make(map[string]int)
+	output.Violations = []string{}
+	violations := 0
+	violate := func(i int, format string, a ...any) {
+		violations++
+		if len(output.Violations) < maxSelftestViolations {
+			output.Violations = append(output.Violations, fmt.Sprintf("step %d: ", i)+fmt.Sprintf(format, a...))
+		}
+	}
+
+	if output.Chaos {
+		rates := make(map[faultinject.Op]float64)
+		for _, op := range faultinject.Ops {
+			rates[op] = rate
+		}
+		restore := faultinject.Enable(faultinject.Config{Rates: rates, Seed: output.Seed})
+		defer func() {
+			for op, n := range faultinject.Counts() {
+				output.Faults[string(op)] = n
+			}
+			restore()
+		}()
+	}
+
+	for i := 1; i <= output.Iterations; i++ {
+		name := selftestSteps[w.rng.Intn(len(selftestSteps))]
+		step := output.Steps[name]
+		step.Runs++
+		err := w.step(name, func(format string, a ...any) { violate(i, "%s: "+format, append([]any{name}, a...)...) })
+		if err != nil {
+			step.Failed++
+			if !errors.Is(err, faultinject.ErrInjected) {
+				violate(i, "%s failed without an injected fault: %v", name, err)
+			}
+		}
+		for _, leftover := range w.leftovers() {
+			violate(i, "%s left %s behind", name, leftover)
+		}
+	}
+	return violations, nil
+}
+
+// selftestWorld is the scratch vault, live auth layout and database a
+// self-test runs in, with the logins each is expected to hold.
+type selftestWorld struct {
+	root    string
+	fileSet authfile.AuthFileSet
+	vault   *authfile.Vault
+	db      *caamdb.DB
+	rng     *rand.Rand
+	gen     int
+
+	// saved maps each profile to the login its vault files should hold;
+	// live is the login the live files should hold.
+	saved map[string]string
+	live  string
+}
+
+// selftestProfiles are the profiles a self-test works on. The first two are
+// linked as logins of the same account, so cooldowns propagate between them.
+var selftestProfiles = []string{"alpha", "beta", "gamma", "delta"}
+
+func newSelftestWorld(root string, seed int64) (*selftestWorld, error) {
+	home := filepath.Join(root, "home")
+	fileSet, _ := authfile.AuthFileSetAt("claude", authfile.AuthDirs{Home: home, XDGConfig: filepath.Join(home, ".config")})
+	db, err := caamdb.OpenAt(filepath.Join(root, "caam.db"))
+	if err != nil {
+		return nil, fmt.Errorf("open scratch db: %w", err)
+	}
+	w := &selftestWorld{
+		root:    root,
+		fileSet: fileSet,
+		vault:   authfile.NewVault(filepath.Join(root, "vault")),
+		db:      db,
+		rng:     rand.New(rand.NewSource(seed)),
+		saved:   make(map[string]string),
+	}
+	for _, name := range selftestProfiles {
+		login, err := w.login(name)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+		if err := w.vault.Backup(fileSet, name); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("set up %s: %w", name, err)
+		}
+		w.saved[name] = login
+	}
+	for _, name := range selftestProfiles[:2] {
+		if err := db.LinkAccountProfile(fileSet.Tool, name, "selftest-account"); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("link %s: %w", name, err)
+		}
+	}
+	return w, nil
+}
+
+// login writes a fresh login for name into the live files, replacing any
+// links as a real login would, and returns it.
+func (w *selftestWorld) login(name string) (string, error) {
+	w.gen++
+	login := fmt.Sprintf("%s#%d", name, w.gen)
+	if err := writeSelftestLogin(w.fileSet, "", login); err != nil {
+		return "", fmt.Errorf("write live login: %w", err)
+	}
+	w.live = login
+	return login, nil
+}
+
+// writeSelftestLogin writes login into every file of fileSet, under dir
+// (by base name) or at the live paths when dir is empty.
+func writeSelftestLogin(fileSet authfile.AuthFileSet, dir, login string) error {
+	for _, spec := range fileSet.Files {
+		path := spec.Path
+		if dir != "" {
+			path = filepath.Join(dir, filepath.Base(spec.Path))
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		data, err := json.Marshal(map[string]string{"login": login, "file": filepath.Base(path)})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSelftestLogin returns the login every file holds, under dir or at the
+// live paths, or a description of how they disagree.
+func readSelftestLogin(fileSet authfile.AuthFileSet, dir string) string {
+	var logins []string
+	for _, spec := range fileSet.Files {
+		path := spec.Path
+		if dir != "" {
+			path = filepath.Join(dir, filepath.Base(spec.Path))
+		}
+		var content map[string]string
+		data, err := os.ReadFile(path)
+		switch {
+		case err != nil:
+			logins = append(logins, "missing")
+		case json.Unmarshal(data, &content) != nil:
+			logins = append(logins, "unreadable")
+		default:
+			logins = append(logins, content["login"])
+		}
+	}
+	for _, login := range logins[1:] {
+		if login != logins[0] {
+			return "mixed(" + strings.Join(logins, ",") + ")"
+		}
+	}
+	return logins[0]
+}
+
+func (w *selftestWorld) pick() string {
+	return selftestProfiles[w.rng.Intn(len(selftestProfiles))]
+}
+
+// step runs one step and reports broken guarantees through violate. The
+// returned error is the step's own failure, which chaos mode expects.
+func (w *selftestWorld) step(name string, violate func(format string, a ...any)) error {
+	switch name {
+	case "backup":
+		return w.stepBackup(violate)
+	case "activate":
+		return w.stepActivate(violate)
+	case "import":
+		return w.stepImport(violate)
+	case "event":
+		return w.stepEvent(violate)
+	case "cooldown":
+		return w.stepCooldown(violate)
+	}
+	return fmt.Errorf("unknown step %q", name)
+}
+
+func (w *selftestWorld) stepBackup(violate func(string, ...any)) error {
+	name := w.pick()
+	login, err := w.login(name)
+	if err != nil {
+		return err
+	}
+	err = w.vault.Backup(w.fileSet, name)
+	if err == nil {
+		w.saved[name] = login
+	}
+	w.checkVault(violate)
+	return err
+}
+
+func (w *selftestWorld) stepActivate(violate func(string, ...any)) error {
+	name := w.pick()
+	mode := authfile.ActivationCopy
+	if w.rng.Intn(2) == 0 {
+		mode = authfile.ActivationSymlink
+	}
+	w.vault.SetActivationMode(mode)
+
+	_, err := w.vault.Activate(w.fileSet, name, authfile.ActivateOptions{BackupMode: authfile.BackupNever})
+	if err == nil {
+		w.live = w.saved[name]
+	}
+	if got := readSelftestLogin(w.fileSet, ""); got != w.live {
+		if err != nil {
+			violate("failed activation of %s (%v) left live auth as %s, want %s", name, err, got, w.live)
+		} else {
+			violate("activated %s but live auth is %s, want %s", name, got, w.live)
+		}
+		w.live = got
+	}
+	w.checkVault(violate)
+	return err
+}
+
+// stepImport imports an archive replacing one or two profiles with new
+// logins, which must land together or not at all.
+func (w *selftestWorld) stepImport(violate func(string, ...any)) error {
+	staging := authfile.NewVault(filepath.Join(w.root, "staging", fmt.Sprint(w.gen)))
+	defer os.RemoveAll(filepath.Join(w.root, "staging"))
+
+	replaced := map[string]string{}
+	for n := 1 + w.rng.Intn(2); len(replaced) < n; {
+		name := w.pick()
+		w.gen++
+		replaced[name] = fmt.Sprintf("%s#%d", name, w.gen)
+	}
+	for name, login := range replaced {
+		if err := writeSelftestLogin(w.fileSet, staging.ProfilePath(w.fileSet.Tool, name), login); err != nil {
+			return fmt.Errorf("stage %s: %w", name, err)
+		}
+	}
+	targets, err := resolveExportTargets(staging, exportRequest{ToolAll: true, Tool: w.fileSet.Tool})
+	if err != nil {
+		return fmt.Errorf("stage export: %w", err)
+	}
+	manifest, files, err := buildExportManifest(targets)
+	if err != nil {
+		return fmt.Errorf("stage export: %w", err)
+	}
+	var archive bytes.Buffer
+	if err := writeExportArchive(&archive, manifest, files); err != nil {
+		return fmt.Errorf("stage export: %w", err)
+	}
+
+	_, err = importArchive(&archive, w.vault, importOptions{Force: true})
+	if err == nil {
+		for name, login := range replaced {
+			w.saved[name] = login
+			// Live files linked into a replaced profile follow it.
+			target, linkErr := os.Readlink(w.fileSet.Files[0].Path)
+			if linkErr == nil && strings.HasPrefix(target, w.vault.ProfilePath(w.fileSet.Tool, name)+string(filepath.Separator)) {
+				w.live = login
+			}
+		}
+	}
+	w.checkVault(violate)
+	return err
+}
+
+func (w *selftestWorld) stepEvent(violate func(string, ...any)) error {
+	name := w.pick()
+	err := w.db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: w.fileSet.Tool, ProfileName: name})
+
+	events, qerr := w.db.GetEvents(w.fileSet.Tool, name, time.Time{}, 1<<20)
+	if qerr != nil {
+		return fmt.Errorf("read events: %w", qerr)
+	}
+	activations := 0
+	for _, e := range events {
+		if e.Type == caamdb.EventActivate {
+			activations++
+		}
+	}
+	stats, qerr := w.db.GetStats(w.fileSet.Tool, name)
+	if qerr != nil {
+		return fmt.Errorf("read stats: %w", qerr)
+	}
+	counted := 0
+	if stats != nil {
+		counted = stats.TotalActivations
+	}
+	if activations != counted {
+		violate("%s has %d logged activations but its stats count %d", name, activations, counted)
+	}
+	return err
+}
+
+func (w *selftestWorld) stepCooldown(violate func(string, ...any)) error {
+	name := selftestProfiles[w.rng.Intn(2)]
+	_, err := w.db.SetCooldown(w.fileSet.Tool, name, time.Now(), time.Minute, "selftest")
+
+	var counts []int
+	for _, linked := range selftestProfiles[:2] {
+		history, qerr := w.db.CooldownHistory(w.fileSet.Tool, linked, time.Time{})
+		if qerr != nil {
+			return fmt.Errorf("read cooldowns: %w", qerr)
+		}
+		counts = append(counts, len(history))
+	}
+	if counts[0] != counts[1] {
+		violate("linked profiles %s and %s have %d and %d cooldowns", selftestProfiles[0], selftestProfiles[1], counts[0], counts[1])
+	}
+	return err
+}
+
+// checkVault compares every profile's vault files with the login it should
+// hold.
+func (w *selftestWorld) checkVault(violate func(string, ...any)) {
+	for _, name := range selftestProfiles {
+		got := readSelftestLogin(w.fileSet, w.vault.ProfilePath(w.fileSet.Tool, name))
+		if got != w.saved[name] {
+			violate("profile %s holds %s, want %s", name, got, w.saved[name])
+			w.saved[name] = got
+		}
+	}
+}
+
+// leftovers returns temp files and directories left in the scratch world.
+func (w *selftestWorld) leftovers() []string {
+	var found []string
+	_ = filepath.WalkDir(w.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		base := d.Name()
+		if strings.Contains(base, ".tmp") || strings.HasPrefix(base, ".import_tmp_") {
+			rel, _ := filepath.Rel(w.root, path)
+			found = append(found, rel)
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+		}
+		return nil
+	})
+	return found
+}
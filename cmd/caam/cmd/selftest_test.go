@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
+)
+
+func newSelftestTestCmd(args ...string) (*cobra.Command, *bytes.Buffer) {
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("chaos", false, "")
+	cmd.Flags().Int("iterations", 200, "")
+	cmd.Flags().Int64("seed", 0, "")
+	cmd.Flags().Float64("rate", 0.05, "")
+	cmd.Flags().Bool("json", false, "")
+	_ = cmd.Flags().Parse(args)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestSelftest_ChaosHoldsGuarantees(t *testing.T) {
+	t.Cleanup(func() { pendingExitCode = ExitOK })
+	cmd, out := newSelftestTestCmd("--chaos", "--iterations", "150", "--seed", "7", "--rate", "0.1", "--json")
+	require.NoError(t, runSelftest(cmd, nil))
+
+	var output selftestOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &output))
+	assert.True(t, output.Success, "violations: %v", output.Violations)
+	assert.Empty(t, output.Violations)
+	assert.Equal(t, int64(7), output.Seed)
+
+	faults, failed := 0, 0
+	for _, n := range output.Faults {
+		faults += n
+	}
+	for _, name := range selftestSteps {
+		require.Contains(t, output.Steps, name)
+		failed += output.Steps[name].Failed
+	}
+	assert.Positive(t, faults, "no faults were injected")
+	assert.Positive(t, failed, "no step failed")
+	assert.False(t, faultinject.Enabled(), "injection should be off once the self-test ends")
+}
+
+func TestSelftest_CleanRunHasNoFailures(t *testing.T) {
+	cmd, out := newSelftestTestCmd("--iterations", "60", "--seed", "3")
+	require.NoError(t, runSelftest(cmd, nil))
+
+	text := out.String()
+	assert.Contains(t, text, "Self-test (clean, seed 3, 60 iterations)")
+	assert.Contains(t, text, "All guarantees held.")
+	assert.NotContains(t, text, "Injected faults")
+	for _, line := range strings.Split(text, "\n") {
+		if strings.Contains(line, " runs, ") {
+			assert.True(t, strings.HasSuffix(line, " 0 failed"), "unexpected failure: %s", line)
+		}
+	}
+}
+
+func TestSelftest_RejectsBadFlags(t *testing.T) {
+	for _, args := range [][]string{{"--iterations", "0"}, {"--rate", "1.5"}} {
+		cmd, _ := newSelftestTestCmd(args...)
+		err := runSelftest(cmd, nil)
+		require.Error(t, err, "args %v", args)
+		assert.Equal(t, ExitUsage, ExitCode(err))
+	}
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// shimMarker identifies wrapper scripts written by 'caam shim install'.
+const shimMarker = "# caam-shim"
+
+// shimCmd is the parent command for PATH shims.
+var shimCmd = &cobra.Command{
+	Use:   "shim",
+	Short: "Manage PATH wrappers that rotate profiles on failure",
+	Long: `Installs wrapper scripts named after each AI CLI into a directory that
+comes before the real tool in PATH. Calling the tool then goes through
+'caam run', which detects auth/rate-limit failures, puts the profile in
+cooldown, rotates to the next available profile, and retries once.
+
+No workflow change: keep typing 'claude', 'codex' or 'gemini'.`,
+}
+
+var shimInstallCmd = &cobra.Command{
+	Use:   "install <tool>...",
+	Short: "Install wrapper scripts for tools",
+	Long: `Writes a wrapper script for each tool into the shim directory
+(default: the caam data directory's "shims" folder).
+
+The shim directory must come before the real tool in PATH; caam prints the
+line to add to your shell rc file if it doesn't.
+
+Examples:
+  caam shim install claude
+  caam shim install claude codex gemini
+  caam shim install codex --dir ~/bin`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runShimInstall,
+}
+
+var shimUninstallCmd = &cobra.Command{
+	Use:   "uninstall <tool>...",
+	Short: "Remove wrapper scripts for tools",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runShimUninstall,
+}
+
+func init() {
+	shimInstallCmd.Flags().String("dir", "", "directory to install shims into (default: <caam data>/shims)")
+	shimInstallCmd.Flags().Bool("force", false, "overwrite an existing file that isn't a caam shim")
+	shimUninstallCmd.Flags().String("dir", "", "directory the shims were installed into (default: <caam data>/shims)")
+
+	shimCmd.AddCommand(shimInstallCmd)
+	shimCmd.AddCommand(shimUninstallCmd)
+	rootCmd.AddCommand(shimCmd)
+}
+
+func defaultShimDir() string {
+	return filepath.Join(config.DefaultDataPath(), "shims")
+}
+
+// resolveShimDir returns the absolute shim directory from the --dir flag.
+func resolveShimDir(cmd *cobra.Command) (string, error) {
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir = defaultShimDir()
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolve shim dir: %w", err)
+	}
+	return abs, nil
+}
+
+func runShimInstall(cmd *cobra.Command, args []string) error {
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("shims require a POSIX shell; on Windows use 'caam hook powershell'")
+	}
+
+	force, _ := cmd.Flags().GetBool("force")
+	shimDir, err := resolveShimDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	for _, arg := range args {
+		tool := strings.ToLower(arg)
+		if _, ok := tools[tool]; !ok {
+			return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+		}
+	}
+
+	caamPath, err := findCaamPath()
+	if err != nil {
+		caamPath = "caam" // Fallback to PATH lookup
+	}
+
+	if err := os.MkdirAll(shimDir, 0755); err != nil {
+		return fmt.Errorf("create shim dir: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	needsPath := false
+	for _, arg := range args {
+		tool := strings.ToLower(arg)
+		shimPath := filepath.Join(shimDir, tool)
+
+		if _, err := os.Stat(shimPath); err == nil && !isCaamShim(shimPath) && !force {
+			return fmt.Errorf("%s exists and is not a caam shim (use --force to overwrite)", shimPath)
+		}
+
+		if err := os.WriteFile(shimPath, []byte(generateShimScript(caamPath, shimDir, tool)), 0755); err != nil {
+			return fmt.Errorf("write %s shim: %w", tool, err)
+		}
+		fmt.Fprintf(out, "Installed %s shim: %s\n", tool, shimPath)
+
+		realPath, err := lookPathExcluding(tool, shimDir)
+		if err != nil {
+			fmt.Fprintf(out, "  Warning: %s not found in PATH; the shim will fail until it is installed\n", tool)
+			continue
+		}
+		if !pathDirPrecedes(shimDir, filepath.Dir(realPath)) {
+			needsPath = true
+		}
+	}
+
+	if needsPath {
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "The shim directory must come before the real tools in PATH. Add to your shell rc file:")
+		fmt.Fprintf(out, "  export PATH=%s:\"$PATH\"\n", shellQuote(shimDir))
+	}
+	return nil
+}
+
+func runShimUninstall(cmd *cobra.Command, args []string) error {
+	shimDir, err := resolveShimDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	for _, arg := range args {
+		tool := strings.ToLower(arg)
+		if _, ok := tools[tool]; !ok {
+			return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+		}
+
+		shimPath := filepath.Join(shimDir, tool)
+		if _, err := os.Stat(shimPath); os.IsNotExist(err) {
+			fmt.Fprintf(out, "No %s shim in %s\n", tool, shimDir)
+			continue
+		}
+		// Only remove files we wrote.
+		if !isCaamShim(shimPath) {
+			return fmt.Errorf("%s is not a caam shim; not removing it", shimPath)
+		}
+		if err := os.Remove(shimPath); err != nil {
+			return fmt.Errorf("remove %s shim: %w", tool, err)
+		}
+		fmt.Fprintf(out, "Removed %s shim: %s\n", tool, shimPath)
+	}
+	return nil
+}
+
+// generateShimScript returns a POSIX sh wrapper that drops the shim directory
+// from PATH (so 'caam run' finds the real tool, not the shim) and hands off to
+// 'caam run'.
+func generateShimScript(caamPath, shimDir, tool string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+# %s wrapper generated by 'caam shim install'; remove with 'caam shim uninstall %s'.
+# Runs the real %s through 'caam run', which rotates to the next profile and
+# retries once on auth/rate-limit failures.
+
+shim_dir=%s
+
+set -f
+new_path=
+IFS=:
+for dir in $PATH; do
+  [ "$dir" = "$shim_dir" ] && continue
+  new_path="${new_path:+$new_path:}$dir"
+done
+unset IFS
+set +f
+PATH=$new_path
+export PATH
+
+exec %s run %s -- "$@"
+`, shimMarker, tool, tool, tool, shellQuote(shimDir), shellQuote(caamPath), tool)
+}
+
+// isCaamShim reports whether path is a wrapper written by 'caam shim install'.
+func isCaamShim(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	lines := strings.SplitN(string(data), "\n", 3)
+	return len(lines) >= 2 && strings.TrimSpace(lines[1]) == shimMarker
+}
+
+// lookPathExcluding finds name in PATH, skipping excludeDir.
+func lookPathExcluding(name, excludeDir string) (string, error) {
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || samePath(dir, excludeDir) {
+			continue
+		}
+		candidate := filepath.Join(dir, name)
+		if path, err := osexec.LookPath(candidate); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in PATH", name)
+}
+
+// pathDirPrecedes reports whether dir appears in PATH before other.
+func pathDirPrecedes(dir, other string) bool {
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if samePath(entry, dir) {
+			return true
+		}
+		if samePath(entry, other) {
+			return false
+		}
+	}
+	return false
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
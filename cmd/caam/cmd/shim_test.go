@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newShimTestCmd(t *testing.T, dir string, force bool) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().String("dir", dir, "")
+	cmd.Flags().Bool("force", force, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	return cmd, &out
+}
+
+func TestGenerateShimScript_RunsCaamWithoutShimDirInPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shims are POSIX sh scripts")
+	}
+	tmpDir := t.TempDir()
+	shimDir := filepath.Join(tmpDir, "shim dir")
+	binDir := filepath.Join(tmpDir, "bin")
+	for _, dir := range []string{shimDir, binDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatalf("MkdirAll(%s) error = %v", dir, err)
+		}
+	}
+
+	// A fake caam that records how it was invoked.
+	fakeCaam := filepath.Join(binDir, "fake-caam")
+	fake := "#!/bin/sh\necho \"args: $*\"\necho \"path: $PATH\"\n"
+	if err := os.WriteFile(fakeCaam, []byte(fake), 0755); err != nil {
+		t.Fatalf("WriteFile(fake caam) error = %v", err)
+	}
+
+	shimPath := filepath.Join(shimDir, "claude")
+	if err := os.WriteFile(shimPath, []byte(generateShimScript(fakeCaam, shimDir, "claude")), 0755); err != nil {
+		t.Fatalf("WriteFile(shim) error = %v", err)
+	}
+
+	run := osexec.Command(shimPath, "-p", "fix bug")
+	run.Env = append(os.Environ(), "PATH="+shimDir+":"+binDir+":/usr/bin:/bin")
+	output, err := run.CombinedOutput()
+	if err != nil {
+		t.Fatalf("shim run error = %v (%s)", err, output)
+	}
+
+	if !strings.Contains(string(output), "args: run claude -- -p fix bug") {
+		t.Errorf("shim should hand off to 'caam run', got:\n%s", output)
+	}
+	if strings.Contains(string(output), shimDir) {
+		t.Errorf("shim dir should be removed from PATH, got:\n%s", output)
+	}
+	if !strings.Contains(string(output), "path: "+binDir+":") {
+		t.Errorf("other PATH entries should be kept, got:\n%s", output)
+	}
+}
+
+func TestShimInstallUninstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shims are POSIX sh scripts")
+	}
+	shimDir := filepath.Join(t.TempDir(), "shims")
+
+	cmd, out := newShimTestCmd(t, shimDir, false)
+	if err := runShimInstall(cmd, []string{"claude", "codex"}); err != nil {
+		t.Fatalf("runShimInstall() error = %v", err)
+	}
+	for _, tool := range []string{"claude", "codex"} {
+		path := filepath.Join(shimDir, tool)
+		if !isCaamShim(path) {
+			t.Fatalf("%s is not a caam shim", path)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat(%s) error = %v", path, err)
+		}
+		if info.Mode().Perm()&0100 == 0 {
+			t.Errorf("%s should be executable, mode %v", path, info.Mode())
+		}
+	}
+	if !strings.Contains(out.String(), "Installed claude shim") {
+		t.Errorf("output = %q, want install notice", out.String())
+	}
+
+	// Reinstalling over our own shim is fine.
+	if err := runShimInstall(cmd, []string{"claude"}); err != nil {
+		t.Fatalf("reinstall error = %v", err)
+	}
+
+	cmd, _ = newShimTestCmd(t, shimDir, false)
+	if err := runShimUninstall(cmd, []string{"claude"}); err != nil {
+		t.Fatalf("runShimUninstall() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(shimDir, "claude")); !os.IsNotExist(err) {
+		t.Errorf("claude shim should be removed, stat err = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(shimDir, "codex")); err != nil {
+		t.Errorf("codex shim should remain, stat err = %v", err)
+	}
+}
+
+func TestShimInstall_RefusesToOverwriteForeignFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shims are POSIX sh scripts")
+	}
+	shimDir := t.TempDir()
+	foreign := filepath.Join(shimDir, "gemini")
+	if err := os.WriteFile(foreign, []byte("#!/bin/sh\necho real\n"), 0755); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cmd, _ := newShimTestCmd(t, shimDir, false)
+	if err := runShimInstall(cmd, []string{"gemini"}); err == nil {
+		t.Fatal("expected error when overwriting a non-shim file")
+	}
+	if err := runShimUninstall(cmd, []string{"gemini"}); err == nil {
+		t.Fatal("expected uninstall to refuse removing a non-shim file")
+	}
+
+	cmd, _ = newShimTestCmd(t, shimDir, true)
+	if err := runShimInstall(cmd, []string{"gemini"}); err != nil {
+		t.Fatalf("runShimInstall(--force) error = %v", err)
+	}
+	if !isCaamShim(foreign) {
+		t.Error("--force should replace the file with a shim")
+	}
+}
+
+func TestPathDirPrecedes(t *testing.T) {
+	t.Setenv("PATH", "/a:/shims:/usr/bin")
+
+	if !pathDirPrecedes("/shims", "/usr/bin") {
+		t.Error("/shims should precede /usr/bin")
+	}
+	if pathDirPrecedes("/shims", "/a") {
+		t.Error("/shims should not precede /a")
+	}
+	if pathDirPrecedes("/missing", "/usr/bin") {
+		t.Error("a directory not in PATH never precedes")
+	}
+}
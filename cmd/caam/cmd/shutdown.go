@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/daemon"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+// shutdownGrace bounds how long a long-running command may spend cleaning up
+// after the first SIGINT/SIGTERM before the process exits anyway.
+const shutdownGrace = 30 * time.Second
+
+// Tests replace these to deliver signals and observe forced exits.
+var (
+	notifyShutdownSignals = func(c chan<- os.Signal) {
+		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	}
+	stopShutdownSignals = func(c chan<- os.Signal) { signal.Stop(c) }
+	forceExit           = os.Exit
+)
+
+// addPIDFileFlag registers --pidfile on a long-running command.
+func addPIDFileFlag(c *cobra.Command) {
+	c.Flags().String("pidfile", "", "write the process ID to this file while running (for supervisors)")
+}
+
+// startLongRunning prepares a long-running command: the returned context
+// is canceled on the first SIGINT or SIGTERM so the command can flush and
+// release what it holds, and the --pidfile, if given, is written and locked.
+//
+// A second signal, or cleanup outlasting shutdownGrace, exits the process
+// with ExitForcedShutdown. finish must be deferred; it removes the PID
+// file and stops listening for signals.
+func startLongRunning(cmd *cobra.Command) (ctx context.Context, finish func(), err error) {
+	var releasePID func()
+	if cmd.Flags().Lookup("pidfile") != nil {
+		if path, _ := cmd.Flags().GetString("pidfile"); path != "" {
+			if releasePID, err = writePIDFile(path); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	parent := cmd.Context()
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, stop := shutdownContext(parent, cmd.ErrOrStderr())
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			stop()
+			if releasePID != nil {
+				releasePID()
+			}
+		})
+	}, nil
+}
+
+// shutdownContext returns a context canceled on the first SIGINT or SIGTERM.
+// A second signal, or cleanup outlasting shutdownGrace, calls forceExit with
+// ExitForcedShutdown.
+func shutdownContext(parent context.Context, errOut io.Writer) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(parent)
+	sigCh := make(chan os.Signal, 2)
+	notifyShutdownSignals(sigCh)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-sigCh:
+		case <-done:
+			return
+		}
+		cancel()
+
+		timer := time.NewTimer(shutdownGrace)
+		defer timer.Stop()
+		select {
+		case sig := <-sigCh:
+			fmt.Fprintf(errOut, "received %s during shutdown, exiting immediately\n", sig)
+		case <-timer.C:
+			fmt.Fprintf(errOut, "shutdown did not finish within %s, exiting\n", shutdownGrace)
+		case <-done:
+			return
+		}
+		forceExit(ExitForcedShutdown)
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			stopShutdownSignals(sigCh)
+			close(done)
+			cancel()
+		})
+	}
+}
+
+// writePIDFile writes this process's ID to path and holds a lock on it, so
+// a second instance pointed at the same file refuses to start. The returned
+// release removes the file.
+func writePIDFile(path string) (release func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create pid dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open pid file: %w", err)
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil && pid != os.Getpid() && daemon.IsProcessRunning(pid) {
+			f.Close()
+			return nil, withExitCode(ExitLockContention, fmt.Errorf("pid file %s belongs to running process %d", path, pid))
+		}
+	}
+	if err := health.LockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("lock pid file: %w", err)
+	}
+
+	writeErr := func() error {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(f, "%d\n", os.Getpid()); err != nil {
+			return err
+		}
+		return f.Sync()
+	}()
+	if writeErr != nil {
+		_ = health.UnlockFile(f)
+		f.Close()
+		return nil, fmt.Errorf("write pid file: %w", writeErr)
+	}
+
+	return func() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "Warning: could not remove pid file %s: %v\n", path, err)
+		}
+		_ = health.UnlockFile(f)
+		f.Close()
+	}, nil
+}
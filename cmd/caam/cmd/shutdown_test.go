@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeShutdownSignals captures the channel shutdownContext listens on and
+// records forced exits instead of exiting.
+func fakeShutdownSignals(t *testing.T) (signals func() chan<- os.Signal, exits <-chan int) {
+	t.Helper()
+	origNotify, origStop, origExit := notifyShutdownSignals, stopShutdownSignals, forceExit
+	t.Cleanup(func() {
+		notifyShutdownSignals, stopShutdownSignals, forceExit = origNotify, origStop, origExit
+	})
+
+	registered := make(chan chan<- os.Signal, 1)
+	notifyShutdownSignals = func(c chan<- os.Signal) { registered <- c }
+	stopShutdownSignals = func(chan<- os.Signal) {}
+	codes := make(chan int, 1)
+	forceExit = func(code int) { codes <- code }
+
+	var ch chan<- os.Signal
+	return func() chan<- os.Signal {
+		if ch == nil {
+			ch = <-registered
+		}
+		return ch
+	}, codes
+}
+
+func TestShutdownContext_FirstSignalCancelsSecondForcesExit(t *testing.T) {
+	signals, exits := fakeShutdownSignals(t)
+	var errOut bytes.Buffer
+	ctx, stop := shutdownContext(t.Context(), &errOut)
+	defer stop()
+
+	signals() <- syscall.SIGTERM
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not canceled after first signal")
+	}
+	select {
+	case code := <-exits:
+		t.Fatalf("forced exit %d after a single signal", code)
+	default:
+	}
+
+	signals() <- os.Interrupt
+	select {
+	case code := <-exits:
+		if code != ExitForcedShutdown {
+			t.Errorf("exit code = %d, want %d", code, ExitForcedShutdown)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal did not force an exit")
+	}
+	if !strings.Contains(errOut.String(), "exiting immediately") {
+		t.Errorf("stderr = %q, want forced-exit notice", errOut.String())
+	}
+}
+
+func TestShutdownContext_StopAfterCleanDoesNotExit(t *testing.T) {
+	signals, exits := fakeShutdownSignals(t)
+	ctx, stop := shutdownContext(t.Context(), &bytes.Buffer{})
+
+	signals() <- syscall.SIGTERM
+	<-ctx.Done()
+	stop()
+	stop() // idempotent
+
+	select {
+	case code := <-exits:
+		t.Fatalf("forced exit %d after clean shutdown", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWritePIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "caam.pid")
+
+	release, err := writePIDFile(path)
+	if err != nil {
+		t.Fatalf("writePIDFile: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read pid file: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != strconv.Itoa(os.Getpid()) {
+		t.Errorf("pid file = %q, want %d", got, os.Getpid())
+	}
+	release()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("pid file should be removed on release, stat err = %v", err)
+	}
+
+	// A file naming another live process is refused.
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := writePIDFile(path); err == nil {
+		t.Fatal("expected error for pid file held by a running process")
+	} else if ExitCode(err) != ExitLockContention {
+		t.Errorf("exit code = %d, want %d", ExitCode(err), ExitLockContention)
+	}
+}
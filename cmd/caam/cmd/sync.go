@@ -13,7 +13,9 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/version"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // syncCmd is the parent command for sync operations.
@@ -35,14 +37,25 @@ Machine management:
   caam sync add <name> <address>   # Add machine to pool
   caam sync remove <name>          # Remove machine from pool
   caam sync test [name]            # Test connectivity
+  caam sync import-csv             # Add machines from ~/.caam/sync_machines.csv
+  caam sync export-csv             # Write pool machines to the CSV
 
 Auto-sync:
   caam sync enable      # Enable auto-sync after backup/refresh
   caam sync disable     # Disable auto-sync
+  caam sync broadcast on  # Share active profiles with the pool
 
 Troubleshooting:
   caam sync log         # View sync history
-  caam sync queue       # View/manage retry queue`,
+  caam sync queue       # View/manage retry queue
+  caam sync retry       # Show the retry schedule ('--now' to retry all)
+
+Conflicts:
+  A profile that changed both here and on the remote since their last sync
+  is a conflict. 'caam sync' shows the expiry and account of both copies and
+  asks which to keep; without a terminal it leaves the profile alone.
+  --prefer local|remote|fresher settles conflicts without asking ('fresher'
+  applies the usual freshness rules).`,
 	RunE: runSync,
 }
 
@@ -73,7 +86,11 @@ Examples:
   caam sync add work-laptop 192.168.1.100
   caam sync add home-desktop jeff@10.0.0.50
   caam sync add dev-server admin@dev.example.com:2222
-  caam sync add cloud-vm 34.123.45.67 --key ~/.ssh/cloud_key`,
+  caam sync add cloud-vm 34.123.45.67 --key ~/.ssh/cloud_key
+  caam sync add inner-box 10.1.2.3 --proxy-jump admin@bastion.example.com
+
+Connections honour ssh-agent; --key is offered first, and machines that
+match a ~/.ssh/config Host use its IdentityFile and ProxyJump.`,
 	Args: cobra.ExactArgs(2),
 	RunE: runSyncAdd,
 }
@@ -121,6 +138,30 @@ var syncDisableCmd = &cobra.Command{
 	RunE:  runSyncDisable,
 }
 
+// syncBroadcastCmd turns activity broadcast on or off.
+var syncBroadcastCmd = &cobra.Command{
+	Use:   "broadcast [on|off]",
+	Short: "Share which profile each machine has active",
+	Long: `When broadcast is on, each activation ("this machine activated claude/work-2
+at 14:05") is recorded and handed to the other machines on every sync.
+'caam status' then shows which profiles are in use on other machines, and
+warns when the one active here is also active elsewhere, so people sharing
+accounts can avoid trampling each other's sessions.
+
+Activity travels with syncs, so it is only as current as the last sync.
+Machines relay each other's activity, so it reaches machines this one
+doesn't sync with directly. Off by default.
+
+Without an argument, shows whether broadcast is on.
+
+Examples:
+  caam sync broadcast on
+  caam sync broadcast off`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"on", "off"},
+	RunE:      runSyncBroadcast,
+}
+
 // syncLogCmd shows sync history.
 var syncLogCmd = &cobra.Command{
 	Use:   "log",
@@ -143,7 +184,9 @@ var syncDiscoverCmd = &cobra.Command{
 This filters out:
   - Known code hosting services (github.com, gitlab.com, etc.)
   - Wildcard hosts (Host *)
-  - Hosts with ProxyJump (complex setups)`,
+  - Hosts with ProxyCommand
+
+Hosts reached through ProxyJump are kept and synced through their jump hosts.`,
 	RunE: runSyncDiscover,
 }
 
@@ -160,6 +203,31 @@ Examples:
 	RunE: runSyncQueue,
 }
 
+// syncRetryCmd shows or forces retries of the queue.
+var syncRetryCmd = &cobra.Command{
+	Use:   "retry",
+	Short: "Show the retry schedule, or retry the queue now",
+	Long: `Show when each queued sync will be retried, or retry them all now.
+
+The daemon retries failed syncs with exponential backoff, following the
+"retry" policy in the sync pool file (pool.json):
+
+  max_attempts  failed attempts before giving up (default 8, 0 = never)
+  backoff_base  wait after the first failure, doubled after each (1m)
+  backoff_max   longest wait between attempts (6h)
+  jitter        stretch each wait by up to this fraction (0.2)
+  give_up       "drop" the entry, or "keep" it for --now (drop)
+  notify        desktop notification when giving up (true)
+
+--now retries every queued entry immediately, including ones the policy
+has given up on.
+
+Examples:
+  caam sync retry          # Show next retry times
+  caam sync retry --now    # Retry everything now`,
+	RunE: runSyncRetry,
+}
+
 // syncEditCmd opens the sync config in an editor.
 var syncEditCmd = &cobra.Command{
 	Use:   "edit",
@@ -187,6 +255,32 @@ Examples:
 	RunE: runSyncInit,
 }
 
+// syncImportCSVCmd merges the legacy CSV machine list into the sync pool.
+var syncImportCSVCmd = &cobra.Command{
+	Use:   "import-csv",
+	Short: "Import machines from the CSV file into the sync pool",
+	Long: `Read the machines listed in ~/.caam/sync_machines.csv and add them to the
+sync pool. Machines already in the pool (by name) are kept as they are and
+reported as skipped.
+
+Examples:
+  caam sync import-csv            # Merge CSV machines into the pool
+  caam sync import-csv --delete   # ...and remove the CSV afterwards`,
+	Args: cobra.NoArgs,
+	RunE: runSyncImportCSV,
+}
+
+// syncExportCSVCmd writes the sync pool's machines to the CSV file.
+var syncExportCSVCmd = &cobra.Command{
+	Use:   "export-csv",
+	Short: "Export sync pool machines to the CSV file",
+	Long: `Add the sync pool's machines to ~/.caam/sync_machines.csv, creating it if
+needed. Machines already in the CSV (by name) are left as they are, and the
+file's comments are preserved.`,
+	Args: cobra.NoArgs,
+	RunE: runSyncExportCSV,
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
 
@@ -198,16 +292,21 @@ func init() {
 	syncCmd.AddCommand(syncTestCmd)
 	syncCmd.AddCommand(syncEnableCmd)
 	syncCmd.AddCommand(syncDisableCmd)
+	syncCmd.AddCommand(syncBroadcastCmd)
 	syncCmd.AddCommand(syncLogCmd)
 	syncCmd.AddCommand(syncDiscoverCmd)
 	syncCmd.AddCommand(syncQueueCmd)
+	syncCmd.AddCommand(syncRetryCmd)
 	syncCmd.AddCommand(syncEditCmd)
+	syncCmd.AddCommand(syncImportCSVCmd)
+	syncCmd.AddCommand(syncExportCSVCmd)
 
 	// Sync command flags
 	syncCmd.Flags().String("machine", "", "sync only with specific machine")
 	syncCmd.Flags().String("provider", "", "sync only specific provider")
 	syncCmd.Flags().String("profile", "", "sync only specific profile")
 	syncCmd.Flags().Bool("dry-run", false, "show what would sync without doing it")
+	syncCmd.Flags().String("prefer", "", "resolve conflicts without asking: local, remote or fresher")
 	syncCmd.Flags().Bool("force", false, "force sync even if recently synced")
 	syncCmd.Flags().Bool("json", false, "output results as JSON")
 
@@ -215,6 +314,7 @@ func init() {
 	syncAddCmd.Flags().String("key", "", "path to SSH private key")
 	syncAddCmd.Flags().String("user", "", "SSH username")
 	syncAddCmd.Flags().String("remote-path", "", "path to caam data on remote")
+	syncAddCmd.Flags().String("proxy-jump", "", "jump host(s) to connect through, as for ssh -J")
 	syncAddCmd.Flags().Bool("test", true, "test connectivity after adding")
 
 	// Remove command flags
@@ -241,10 +341,18 @@ func init() {
 	// Status command flags
 	syncStatusCmd.Flags().Bool("json", false, "output as JSON")
 
+	// CSV import/export flags
+	syncImportCSVCmd.Flags().Bool("delete", false, "delete the CSV file after importing")
+	syncImportCSVCmd.Flags().Bool("json", false, "output as JSON")
+	syncExportCSVCmd.Flags().Bool("json", false, "output as JSON")
+
 	// Queue command flags
 	syncQueueCmd.Flags().Bool("clear", false, "clear all pending retries")
 	syncQueueCmd.Flags().Bool("process", false, "process pending retries now")
 	syncQueueCmd.Flags().Bool("json", false, "output as JSON")
+
+	// Retry command flags
+	syncRetryCmd.Flags().Bool("now", false, "retry all queued syncs immediately")
 }
 
 // loadSyncState loads the sync state, handling the case where sync isn't configured yet.
@@ -277,6 +385,10 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	machineName, _ := cmd.Flags().GetString("machine")
+	prefer, _ := cmd.Flags().GetString("prefer")
+	if err := sync.ValidatePrefer(prefer); err != nil {
+		return withExitCode(ExitUsage, err)
+	}
 
 	machines := state.Pool.ListMachines()
 	if machineName != "" {
@@ -299,7 +411,9 @@ func runSync(cmd *cobra.Command, args []string) error {
 	fmt.Fprintf(cmd.OutOrStdout(), "Syncing with %d machine(s)...\n\n", len(machines))
 
 	// Create syncer with configuration
-	syncer, err := sync.NewSyncer(sync.DefaultSyncerConfig())
+	syncerConfig := sync.DefaultSyncerConfig()
+	syncerConfig.Prefer = prefer
+	syncer, err := sync.NewSyncer(syncerConfig)
 	if err != nil {
 		return fmt.Errorf("create syncer: %w", err)
 	}
@@ -307,10 +421,17 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 	// Build context
 	ctx := cmd.Context()
+	var reader *bufio.Reader
+	if syncStdinIsTerminal() {
+		reader = bufio.NewReader(cmd.InOrStdin())
+	}
 
 	var allResults []*sync.SyncResult
 	for _, m := range machines {
 		fmt.Fprintf(cmd.OutOrStdout(), "  %s (%s):\n", m.Name, m.Address)
+		if h := m.Health; h != nil && h.CAAMVersion != "" && !sync.VersionsCompatible(version.Short(), h.CAAMVersion) {
+			fmt.Fprintf(cmd.OutOrStdout(), "    ⚠️  remote runs caam %s, incompatible with local %s (checked %s)\n", h.CAAMVersion, version.Short(), formatTimeAgo(h.CheckedAt))
+		}
 
 		results, err := syncer.SyncWithMachine(ctx, m)
 		if err != nil {
@@ -324,14 +445,18 @@ func runSync(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		for _, r := range results {
+		for i, r := range results {
 			profile := fmt.Sprintf("%s/%s", r.Operation.Provider, r.Operation.Profile)
+			if r.Operation.Direction == sync.SyncConflict {
+				results[i] = resolveSyncConflict(ctx, cmd.OutOrStdout(), reader, syncer, r)
+				continue
+			}
 			if r.Success {
 				switch r.Operation.Direction {
 				case sync.SyncPush:
-					fmt.Fprintf(cmd.OutOrStdout(), "    ✓ %s: pushed (local fresher)\n", profile)
+					fmt.Fprintf(cmd.OutOrStdout(), "    ✓ %s: pushed (local fresher, %s)\n", profile, transferSummary(r.BytesSent, r.FilesTransferred, r.FilesUnchanged))
 				case sync.SyncPull:
-					fmt.Fprintf(cmd.OutOrStdout(), "    ✓ %s: pulled (remote fresher)\n", profile)
+					fmt.Fprintf(cmd.OutOrStdout(), "    ✓ %s: pulled (remote fresher, %s)\n", profile, transferSummary(r.BytesReceived, r.FilesTransferred, r.FilesUnchanged))
 				case sync.SyncSkip:
 					fmt.Fprintf(cmd.OutOrStdout(), "    ✓ %s: up to date\n", profile)
 				}
@@ -346,12 +471,112 @@ func runSync(cmd *cobra.Command, args []string) error {
 
 	// Print summary
 	stats := sync.AggregateResults(allResults)
-	fmt.Fprintf(cmd.OutOrStdout(), "Sync complete: %d pushed, %d pulled, %d up to date, %d errors\n",
-		stats.Pushed, stats.Pulled, stats.Skipped, stats.Failed)
+	fmt.Fprintf(cmd.OutOrStdout(), "Sync complete: %d pushed, %d pulled, %d up to date, %d errors (%s sent, %s received)\n",
+		stats.Pushed, stats.Pulled, stats.Skipped, stats.Failed, formatBytes(stats.BytesSent), formatBytes(stats.BytesRecv))
+	if stats.Conflicts > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "%d conflict(s) left unresolved; re-run with --prefer local|remote|fresher to settle them\n", stats.Conflicts)
+	}
 
 	return nil
 }
 
+// syncStdinIsTerminal reports whether 'caam sync' may ask which side of a
+// conflict to keep.
+var syncStdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// resolveSyncConflict shows both copies of a conflicting profile and, when
+// reader is set, asks which to keep. It returns the result to report: the
+// resolved sync, or r itself if the conflict stays unresolved.
+func resolveSyncConflict(ctx context.Context, out io.Writer, reader *bufio.Reader, syncer *sync.Syncer, r *sync.SyncResult) *sync.SyncResult {
+	op := r.Operation
+	fmt.Fprintf(out, "    ⚠️  %s/%s: changed here and on %s since the last sync\n", op.Provider, op.Profile, op.Machine.Name)
+	fmt.Fprintf(out, "       local:  %s\n", describeConflictSide(op.Conflict.Local))
+	fmt.Fprintf(out, "       remote: %s\n", describeConflictSide(op.Conflict.Remote))
+	if reader == nil {
+		return r
+	}
+
+	var prefer string
+	for {
+		fmt.Fprint(out, "       Keep [l]ocal, [r]emote, [f]resher, or [s]kip? ")
+		choice, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(choice)) {
+		case "l", "local":
+			prefer = sync.PreferLocal
+		case "r", "remote":
+			prefer = sync.PreferRemote
+		case "f", "fresher":
+			prefer = sync.PreferFresher
+		case "s", "skip":
+			return r
+		}
+		if prefer != "" {
+			break
+		}
+		if err != nil {
+			return r
+		}
+	}
+
+	resolved, err := syncer.ResolveConflict(ctx, op.Provider, op.Profile, op.Machine, prefer)
+	if err != nil {
+		fmt.Fprintf(out, "    ✗ %s/%s: %v\n", op.Provider, op.Profile, err)
+		return r
+	}
+	profile := fmt.Sprintf("%s/%s", op.Provider, op.Profile)
+	switch {
+	case !resolved.Success:
+		fmt.Fprintf(out, "    ✗ %s: %v\n", profile, resolved.Error)
+	case resolved.Operation.Direction == sync.SyncPush:
+		fmt.Fprintf(out, "    ✓ %s: kept local copy, pushed (%s)\n", profile, transferSummary(resolved.BytesSent, resolved.FilesTransferred, resolved.FilesUnchanged))
+	case resolved.Operation.Direction == sync.SyncPull:
+		fmt.Fprintf(out, "    ✓ %s: kept remote copy, pulled (%s)\n", profile, transferSummary(resolved.BytesReceived, resolved.FilesTransferred, resolved.FilesUnchanged))
+	default:
+		fmt.Fprintf(out, "    ✓ %s: up to date\n", profile)
+	}
+	return resolved
+}
+
+// describeConflictSide summarizes one copy of a conflicting profile, e.g.
+// "alice@example.com, expires in 3h20m (modified 2 mins ago)".
+func describeConflictSide(side sync.ConflictSide) string {
+	account := side.Account
+	if account == "" {
+		account = "unknown account"
+	}
+	f := side.Freshness
+	if f == nil {
+		return account
+	}
+	expiry := "expiry unknown"
+	switch {
+	case f.ExpiresAt.IsZero():
+	case time.Until(f.ExpiresAt) <= 0:
+		expiry = "expired " + formatTimeAgo(f.ExpiresAt)
+	default:
+		expiry = "expires in " + formatDurationShort(time.Until(f.ExpiresAt))
+	}
+	if f.ModifiedAt.IsZero() {
+		return fmt.Sprintf("%s, %s", account, expiry)
+	}
+	return fmt.Sprintf("%s, %s (modified %s)", account, expiry, formatTimeAgo(f.ModifiedAt))
+}
+
+// transferSummary describes what a push or pull copied, e.g.
+// "2.1 KB in 1 file, 3 unchanged".
+func transferSummary(bytes int64, files, unchanged int) string {
+	summary := fmt.Sprintf("%s in %d file", formatBytes(bytes), files)
+	if files != 1 {
+		summary += "s"
+	}
+	if unchanged > 0 {
+		summary += fmt.Sprintf(", %d unchanged", unchanged)
+	}
+	return summary
+}
+
 // runSyncStatus shows the sync pool status.
 func runSyncStatus(cmd *cobra.Command, args []string) error {
 	state, err := loadSyncState()
@@ -443,6 +668,7 @@ func runSyncAdd(cmd *cobra.Command, args []string) error {
 	sshUser, _ := cmd.Flags().GetString("user")
 	sshKeyPath, _ := cmd.Flags().GetString("key")
 	remotePath, _ := cmd.Flags().GetString("remote-path")
+	proxyJump, _ := cmd.Flags().GetString("proxy-jump")
 	testAfter, _ := cmd.Flags().GetBool("test")
 
 	// Parse user from address if present
@@ -476,6 +702,7 @@ func runSyncAdd(cmd *cobra.Command, args []string) error {
 	machine.SSHUser = sshUser
 	machine.SSHKeyPath = sshKeyPath
 	machine.RemotePath = remotePath
+	machine.ProxyJump = proxyJump
 	machine.Source = sync.SourceManual
 
 	if err := state.Pool.AddMachine(machine); err != nil {
@@ -514,14 +741,9 @@ func runSyncRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	force, _ := cmd.Flags().GetBool("force")
-	if !force {
-		fmt.Fprintf(cmd.OutOrStdout(), "Remove machine %q from sync pool? (y/N): ", name)
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-			fmt.Fprintln(cmd.OutOrStdout(), "Cancelled")
-			return nil
-		}
+	if !force && !confirmPrompt(cmd, false, fmt.Sprintf("Remove machine %q from sync pool?", name)) {
+		fmt.Fprintln(cmd.OutOrStdout(), "Cancelled")
+		return nil
 	}
 
 	if err := state.Pool.RemoveMachine(machine.ID); err != nil {
@@ -667,6 +889,93 @@ func runSyncDisable(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runSyncBroadcast shows or sets whether activations are broadcast.
+func runSyncBroadcast(cmd *cobra.Command, args []string) error {
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		if state.Pool.BroadcastActivity {
+			fmt.Fprintln(out, "Activity broadcast is on.")
+		} else {
+			fmt.Fprintln(out, "Activity broadcast is off.")
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "on":
+		state.Pool.BroadcastActivity = true
+	case "off":
+		state.Pool.BroadcastActivity = false
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("invalid argument %q (want on or off)", args[0]))
+	}
+	if err := state.Save(); err != nil {
+		return fmt.Errorf("save state: %w", err)
+	}
+
+	if state.Pool.BroadcastActivity {
+		fmt.Fprintln(out, "Activity broadcast is now on.")
+		fmt.Fprintln(out, "Activations are shared with the pool on each sync; see them in 'caam status'.")
+	} else {
+		fmt.Fprintln(out, "Activity broadcast is now off.")
+	}
+	return nil
+}
+
+// recordBroadcastActivation records an activation for the pool when
+// activity broadcast is on. Errors are ignored: activation must not fail
+// because of it.
+func recordBroadcastActivation(local *sync.LocalIdentity, tool, profile string, at time.Time) {
+	pool, err := sync.LoadSyncPool()
+	if err != nil || !pool.BroadcastActivity {
+		return
+	}
+	_ = sync.RecordActivation(sync.ActivityDir(), local, tool, profile, at)
+}
+
+// remoteActivation is a profile active on another machine.
+type remoteActivation struct {
+	Machine string    `json:"machine"`
+	Tool    string    `json:"tool"`
+	Profile string    `json:"profile"`
+	Since   time.Time `json:"since"`
+}
+
+// activeElsewhere lists the profiles other machines last reported active,
+// when activity broadcast is on.
+func activeElsewhere() []remoteActivation {
+	pool, err := sync.LoadSyncPool()
+	if err != nil || !pool.BroadcastActivity {
+		return nil
+	}
+	all, err := sync.LoadActivity(sync.ActivityDir())
+	if err != nil {
+		return nil
+	}
+	localID := ""
+	if local, err := sync.GetOrCreateLocalIdentity(); err == nil && local != nil {
+		localID = local.ID
+	}
+
+	var result []remoteActivation
+	for _, a := range all {
+		if a.MachineID == localID {
+			continue
+		}
+		for _, tool := range knownTools() {
+			if act, ok := a.Active[tool]; ok && act.Profile != "" {
+				result = append(result, remoteActivation{Machine: a.Machine, Tool: tool, Profile: act.Profile, Since: act.At})
+			}
+		}
+	}
+	return result
+}
+
 // runSyncLog shows sync history.
 func runSyncLog(cmd *cobra.Command, args []string) error {
 	state, err := loadSyncState()
@@ -718,6 +1027,8 @@ func runSyncLog(cmd *cobra.Command, args []string) error {
 		status := "✓"
 		if !e.Success {
 			status = "✗ " + e.Error
+		} else if e.FilesTransferred > 0 || e.FilesUnchanged > 0 {
+			status += " " + transferSummary(e.BytesTransferred, e.FilesTransferred, e.FilesUnchanged)
 		}
 		profile := fmt.Sprintf("%s/%s", e.Provider, e.Profile)
 		fmt.Fprintf(cmd.OutOrStdout(), "%-20s %-15s %-25s %-8s %s\n",
@@ -795,6 +1106,154 @@ func runSyncDiscover(cmd *cobra.Command, args []string) error {
 }
 
 // runSyncQueue manages the retry queue.
+// csvTransferResult reports what a CSV import or export did.
+type csvTransferResult struct {
+	Path    string           `json:"path"`
+	Added   []string         `json:"added"`
+	Skipped []csvSkippedItem `json:"skipped"`
+	Deleted bool             `json:"deleted,omitempty"`
+}
+
+// csvSkippedItem is a machine a CSV import or export left out.
+type csvSkippedItem struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// mergeMachinesByName merges incoming into existing with
+// sync.MergeDiscoveredMachines, returning the machines that are new and a
+// skip entry for each one that is already present.
+func mergeMachinesByName(existing, incoming []*sync.Machine, present string) ([]*sync.Machine, []csvSkippedItem) {
+	merged := sync.MergeDiscoveredMachines(existing, incoming)
+	added := merged[len(existing):]
+
+	isAdded := make(map[*sync.Machine]bool, len(added))
+	for _, m := range added {
+		isAdded[m] = true
+	}
+	var skipped []csvSkippedItem
+	for _, m := range incoming {
+		if !isAdded[m] {
+			skipped = append(skipped, csvSkippedItem{Name: m.Name, Reason: present})
+		}
+	}
+	return added, skipped
+}
+
+func runSyncImportCSV(cmd *cobra.Command, args []string) error {
+	deleteCSV, _ := cmd.Flags().GetBool("delete")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	out := cmd.OutOrStdout()
+
+	csvPath := sync.CSVPath()
+	machines, err := sync.LoadFromCSV()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", csvPath, err)
+	}
+	if machines == nil {
+		if _, statErr := os.Stat(csvPath); os.IsNotExist(statErr) {
+			return fmt.Errorf("no CSV file at %s", csvPath)
+		}
+	}
+
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+
+	result := csvTransferResult{Path: csvPath, Added: []string{}}
+	candidates, skipped := mergeMachinesByName(state.Pool.ListMachines(), machines, "already in pool")
+	result.Skipped = skipped
+	for _, m := range candidates {
+		if err := state.Pool.AddMachine(m); err != nil {
+			result.Skipped = append(result.Skipped, csvSkippedItem{Name: m.Name, Reason: err.Error()})
+			continue
+		}
+		result.Added = append(result.Added, m.Name)
+	}
+	if result.Skipped == nil {
+		result.Skipped = []csvSkippedItem{}
+	}
+
+	if len(result.Added) > 0 {
+		if err := state.Save(); err != nil {
+			return fmt.Errorf("save state: %w", err)
+		}
+	}
+	if deleteCSV {
+		if err := os.Remove(csvPath); err != nil {
+			return fmt.Errorf("delete %s: %w", csvPath, err)
+		}
+		result.Deleted = true
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	printCSVTransfer(out, result, fmt.Sprintf("Imported %d machine(s) from %s into the sync pool", len(result.Added), csvPath))
+	if result.Deleted {
+		fmt.Fprintf(out, "Deleted %s\n", csvPath)
+	}
+	return nil
+}
+
+func runSyncExportCSV(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	out := cmd.OutOrStdout()
+
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+
+	csvPath := sync.CSVPath()
+	existing, err := sync.LoadFromCSV()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", csvPath, err)
+	}
+
+	result := csvTransferResult{Path: csvPath, Added: []string{}}
+	added, skipped := mergeMachinesByName(existing, state.Pool.ListMachines(), "already in CSV")
+	result.Skipped = skipped
+	if result.Skipped == nil {
+		result.Skipped = []csvSkippedItem{}
+	}
+	for _, m := range added {
+		result.Added = append(result.Added, m.Name)
+	}
+
+	if len(added) > 0 {
+		if _, err := sync.EnsureCSVFile(); err != nil {
+			return fmt.Errorf("create %s: %w", csvPath, err)
+		}
+		if err := sync.SaveToCSV(append(existing, added...)); err != nil {
+			return fmt.Errorf("write %s: %w", csvPath, err)
+		}
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+	printCSVTransfer(out, result, fmt.Sprintf("Exported %d machine(s) from the sync pool to %s", len(result.Added), csvPath))
+	return nil
+}
+
+// printCSVTransfer prints the added and skipped machines of a CSV import
+// or export under summary.
+func printCSVTransfer(out io.Writer, result csvTransferResult, summary string) {
+	fmt.Fprintln(out, summary)
+	for _, name := range result.Added {
+		fmt.Fprintf(out, "  + %s\n", name)
+	}
+	for _, item := range result.Skipped {
+		fmt.Fprintf(out, "  - %s (skipped: %s)\n", item.Name, item.Reason)
+	}
+}
+
 func runSyncQueue(cmd *cobra.Command, args []string) error {
 	state, err := loadSyncState()
 	if err != nil {
@@ -844,6 +1303,55 @@ func runSyncQueue(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runSyncRetry shows the retry schedule or forces a retry of the queue.
+func runSyncRetry(cmd *cobra.Command, args []string) error {
+	state, err := loadSyncState()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if now, _ := cmd.Flags().GetBool("now"); now {
+		return runSyncQueueProcess(state, out)
+	}
+
+	if state.Queue == nil || len(state.Queue.Entries) == 0 {
+		fmt.Fprintln(out, "Sync queue is empty.")
+		return nil
+	}
+
+	policy := state.Pool.Retry
+	attempts := "unlimited attempts"
+	if policy.MaxAttempts > 0 {
+		attempts = fmt.Sprintf("up to %d attempts", policy.MaxAttempts)
+	}
+	fmt.Fprintf(out, "Retry policy: %s, backoff %s doubling to %s, then %s\n\n",
+		attempts, policy.BackoffBase, policy.BackoffMax, policy.GiveUp)
+	fmt.Fprintf(out, "  %-25s %-15s %-10s %s\n", "PROFILE", "MACHINE", "ATTEMPTS", "NEXT RETRY")
+
+	now := time.Now()
+	for _, e := range state.Queue.Entries {
+		profile := fmt.Sprintf("%s/%s", e.Provider, e.Profile)
+		machine := e.Machine
+		if m := state.Pool.GetMachine(e.Machine); m != nil {
+			machine = m.Name
+		}
+
+		next := "due now"
+		switch {
+		case e.GaveUp || policy.Exhausted(e):
+			next = "gave up (retry with --now)"
+		case e.NextAttempt.After(now):
+			next = "in " + formatDurationShort(e.NextAttempt.Sub(now))
+		}
+		fmt.Fprintf(out, "  %-25s %-15s %-10d %s\n", profile, machine, e.Attempts, next)
+	}
+
+	fmt.Fprintln(out, "")
+	fmt.Fprintln(out, "Use 'caam sync retry --now' to retry everything now.")
+	return nil
+}
+
 // runSyncEdit opens the sync config in an editor.
 func runSyncEdit(cmd *cobra.Command, args []string) error {
 	csvPath := sync.CSVPath()
@@ -1240,6 +1748,9 @@ func runSyncQueueProcess(state *sync.SyncState, out io.Writer) error {
 			fmt.Fprintln(out, " ✓ OK")
 			toRemove = append(toRemove, entryKey{entry.Provider, entry.Profile, entry.Machine})
 			processed++
+		} else if result.Operation != nil && result.Operation.Direction == sync.SyncConflict {
+			fmt.Fprintf(out, " ⚠️  changed on both sides; run 'caam sync --machine %s' to pick one\n", machine.Name)
+			toRemove = append(toRemove, entryKey{entry.Provider, entry.Profile, entry.Machine})
 		} else {
 			errMsg := "sync failed"
 			if result.Error != nil {
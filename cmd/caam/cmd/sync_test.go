@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/spf13/cobra"
+
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/version"
 )
 
 // Test helper functions that are exported
@@ -71,10 +76,14 @@ func TestSyncCommands(t *testing.T) {
 		"test",
 		"enable",
 		"disable",
+		"broadcast",
 		"log",
 		"discover",
 		"queue",
+		"retry",
 		"edit",
+		"import-csv",
+		"export-csv",
 	}
 
 	for _, name := range subcommands {
@@ -218,3 +227,195 @@ func TestRemoteVaultPath(t *testing.T) {
 		t.Fatalf("remoteVaultPath(custom) = %q, want %q", got, "/data/caam/vault")
 	}
 }
+
+func TestSyncImportExportCSV(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	csvPath := sync.CSVPath()
+	if err := os.MkdirAll(filepath.Dir(csvPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	csv := "machine_name,address,ssh_key_path\nwork-laptop,jeff@192.168.1.100,~/.ssh/id_ed25519\nhome-desktop,10.0.0.50:2222,\n"
+	if err := os.WriteFile(csvPath, []byte(csv), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := loadSyncState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Pool.AddMachine(sync.NewMachine("home-desktop", "10.0.0.99")); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Pool.AddMachine(sync.NewMachine("cloud-vm", "34.123.45.67")); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	importCmd := &cobra.Command{}
+	importCmd.Flags().Bool("delete", false, "")
+	importCmd.Flags().Bool("json", false, "")
+	var out bytes.Buffer
+	importCmd.SetOut(&out)
+	if err := runSyncImportCSV(importCmd, nil); err != nil {
+		t.Fatalf("import-csv error = %v", err)
+	}
+	if !strings.Contains(out.String(), "+ work-laptop") || !strings.Contains(out.String(), "- home-desktop (skipped: already in pool)") {
+		t.Errorf("import-csv output = %q", out.String())
+	}
+
+	state, err = loadSyncState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := state.Pool.GetMachineByName("work-laptop")
+	if m == nil || m.SSHUser != "jeff" || m.Address != "192.168.1.100" {
+		t.Fatalf("imported machine = %+v", m)
+	}
+	if got := state.Pool.GetMachineByName("home-desktop"); got == nil || got.Address != "10.0.0.99" {
+		t.Errorf("existing pool machine should be kept, got %+v", got)
+	}
+
+	exportCmd := &cobra.Command{}
+	exportCmd.Flags().Bool("json", false, "")
+	out.Reset()
+	exportCmd.SetOut(&out)
+	if err := runSyncExportCSV(exportCmd, nil); err != nil {
+		t.Fatalf("export-csv error = %v", err)
+	}
+	machines, err := sync.LoadFromCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range machines {
+		names = append(names, m.Name)
+	}
+	if strings.Join(names, ",") != "work-laptop,home-desktop,cloud-vm" {
+		t.Errorf("CSV machines after export = %v", names)
+	}
+
+	if err := importCmd.Flags().Set("delete", "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSyncImportCSV(importCmd, nil); err != nil {
+		t.Fatalf("import-csv --delete error = %v", err)
+	}
+	if _, err := os.Stat(csvPath); !os.IsNotExist(err) {
+		t.Errorf("CSV should be deleted, stat err = %v", err)
+	}
+	if err := runSyncImportCSV(importCmd, nil); err == nil {
+		t.Error("import-csv without a CSV file should fail")
+	}
+}
+
+func TestMachinesStatusShowsRecordedHealth(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	originalVersion := version.Version
+	t.Cleanup(func() { version.Version = originalVersion })
+	version.Version = "v1.5.0"
+
+	state, err := loadSyncState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := sync.NewMachine("work-laptop", "192.168.1.100")
+	m.Health = &sync.MachineHealth{
+		CheckedAt:      time.Now(),
+		ClockSkew:      -3 * time.Minute,
+		ClockSkewKnown: true,
+		CAAMVersion:    "v2.0.1",
+		Profiles:       map[string]int{"claude": 2, "codex": 1},
+	}
+	if err := state.Pool.AddMachine(m); err != nil {
+		t.Fatal(err)
+	}
+	state.AddToHistory(sync.HistoryEntry{Timestamp: time.Now().Add(-2 * time.Hour), Provider: "claude", Machine: "work-laptop", Action: "push", Success: true})
+	state.AddToHistory(sync.HistoryEntry{Timestamp: time.Now(), Provider: "codex", Machine: "work-laptop", Action: "pull", Success: false})
+	if err := state.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", false, "")
+	cmd.Flags().Bool("no-check", true, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := runMachinesStatus(cmd, nil); err != nil {
+		t.Fatalf("runMachinesStatus() error = %v", err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"caam: v2.0.1",
+		"Clock skew: 3m0s behind",
+		"Profiles: codex 1, claude 2",
+		"claude   2 hours ago",
+		"incompatible with local v1.5.0",
+		"clock is 3m0s behind",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "codex    ") {
+		t.Errorf("failed syncs should not count as the last sync:\n%s", output)
+	}
+
+	if err := runMachinesStatus(cmd, []string{"missing"}); ExitCode(err) != ExitUsage {
+		t.Errorf("unknown machine: exit code = %d, want %d", ExitCode(err), ExitUsage)
+	}
+}
+
+func TestSyncBroadcastActiveElsewhere(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	local, err := sync.GetOrCreateLocalIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := &sync.LocalIdentity{ID: "other-id", Hostname: "laptop"}
+	if err := sync.RecordActivation(sync.ActivityDir(), other, "claude", "work-2", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is recorded or shown until broadcast is on.
+	recordBroadcastActivation(local, "codex", "main", time.Now())
+	if got := activeElsewhere(); len(got) != 0 {
+		t.Errorf("activeElsewhere() with broadcast off = %v, want none", got)
+	}
+
+	cmd := &cobra.Command{}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := runSyncBroadcast(cmd, []string{"on"}); err != nil {
+		t.Fatalf("runSyncBroadcast(on) error = %v", err)
+	}
+	if !strings.Contains(out.String(), "now on") {
+		t.Errorf("output = %q, want confirmation", out.String())
+	}
+	if err := runSyncBroadcast(cmd, []string{"maybe"}); ExitCode(err) != ExitUsage {
+		t.Errorf("runSyncBroadcast(maybe) exit code = %d, want %d", ExitCode(err), ExitUsage)
+	}
+
+	recordBroadcastActivation(local, "codex", "main", time.Now())
+	all, err := sync.LoadActivity(sync.ActivityDir())
+	if err != nil || len(all) != 2 {
+		t.Fatalf("LoadActivity() = %v, %v; want both machines", all, err)
+	}
+
+	// Only other machines are reported.
+	got := activeElsewhere()
+	if len(got) != 1 || got[0].Machine != "laptop" || got[0].Tool != "claude" || got[0].Profile != "work-2" {
+		t.Errorf("activeElsewhere() = %+v, want laptop claude/work-2", got)
+	}
+}
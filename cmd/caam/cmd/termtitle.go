@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/termtitle"
+)
+
+// newTermLabeler builds the labeler for the current terminal; tests replace it.
+var newTermLabeler = func() *termtitle.Labeler { return termtitle.New(os.Stderr) }
+
+// labelTerminal shows tool:profile in the terminal title or tmux window name
+// when display.terminal_title is on, and returns the function that puts the
+// old label back. It's a no-op when the option is off.
+func labelTerminal(tool, profile string) (restore func()) {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil || !spmCfg.Display.TerminalTitle {
+		return func() {}
+	}
+	return newTermLabeler().Set(termtitle.Label(tool, profile))
+}
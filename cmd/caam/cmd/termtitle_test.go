@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/termtitle"
+)
+
+func stubTermLabeler(t *testing.T, configYAML string) *bytes.Buffer {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("CAAM_HOME", home)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.yaml"), []byte(configYAML), 0600))
+
+	var out bytes.Buffer
+	original := newTermLabeler
+	newTermLabeler = func() *termtitle.Labeler { return &termtitle.Labeler{Out: &out} }
+	t.Cleanup(func() { newTermLabeler = original })
+	return &out
+}
+
+func TestLabelTerminal_OffByDefault(t *testing.T) {
+	out := stubTermLabeler(t, "version: 1\n")
+
+	labelTerminal("claude", "work-2")()
+	assert.Empty(t, out.String())
+}
+
+func TestLabelTerminal_SetsAndRestores(t *testing.T) {
+	out := stubTermLabeler(t, "version: 1\ndisplay:\n  terminal_title: true\n")
+
+	restore := labelTerminal("claude", "work-2")
+	assert.Contains(t, out.String(), "\x1b]0;claude:work-2\x07")
+	out.Reset()
+	restore()
+	assert.Equal(t, "\x1b[23;0t", out.String())
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Show the clocks and time zones caam uses",
+	Long: `Diagnoses time handling: the system clock with its zone and where that zone
+comes from, the display zone (display.timezone in config.yaml), the
+database's clock and its skew from the system clock, and the next
+daylight-saving change in the display zone.
+
+Cooldowns, expiries and schedules are stored and compared in UTC. The
+display zone only changes how times are shown and how wall-clock input such
+as "--for 5pm" is read, so a DST change never alters how long a cooldown
+lasts. A large database skew (a shared Postgres server with a wrong clock)
+does: rows it stamps will look older or newer than they are.
+
+Examples:
+  caam time
+  caam time --json`,
+	Args: cobra.NoArgs,
+	RunE: runTime,
+}
+
+func init() {
+	rootCmd.AddCommand(timeCmd)
+	timeCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+type timeTransitionJSON struct {
+	At         time.Time `json:"at"`
+	FromZone   string    `json:"from_zone"`
+	ToZone     string    `json:"to_zone"`
+	ShiftHours float64   `json:"shift_hours"`
+}
+
+type timeOutput struct {
+	System          time.Time           `json:"system"`
+	UTC             time.Time           `json:"utc"`
+	LocalZone       string              `json:"local_zone"`
+	LocalZoneSource string              `json:"local_zone_source"`
+	DisplayZone     string              `json:"display_zone"`
+	DisplaySetting  string              `json:"display_setting"`
+	DatabaseBackend string              `json:"database_backend,omitempty"`
+	Database        *time.Time          `json:"database,omitempty"`
+	DatabaseSkewSec *float64            `json:"database_skew_seconds,omitempty"`
+	DatabaseError   string              `json:"database_error,omitempty"`
+	NextTransition  *timeTransitionJSON `json:"next_dst_change,omitempty"`
+}
+
+func runTime(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	out := collectTimeInfo(time.Now())
+	if jsonOutput {
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		return enc.Encode(out)
+	}
+	printTimeInfo(cmd.OutOrStdout(), out)
+	return nil
+}
+
+func collectTimeInfo(now time.Time) *timeOutput {
+	out := &timeOutput{System: now.Round(0), UTC: now.UTC().Round(0)}
+	out.LocalZone, out.LocalZoneSource = localZoneName()
+
+	loc := displayLocation()
+	out.DisplayZone = loc.String()
+	out.DisplaySetting = "local"
+	if spmCfg, err := config.LoadSPMConfig(); err == nil && strings.TrimSpace(spmCfg.Display.Timezone) != "" {
+		out.DisplaySetting = spmCfg.Display.Timezone
+	}
+	if loc == time.Local {
+		out.DisplayZone = out.LocalZone
+	}
+
+	if db, err := caamdb.Open(); err != nil {
+		out.DatabaseError = err.Error()
+	} else {
+		out.DatabaseBackend = db.Backend()
+		dbNow, err := db.Now()
+		if err != nil {
+			out.DatabaseError = err.Error()
+		} else {
+			// The database clock has whole seconds; compare at that precision.
+			skew := dbNow.Sub(time.Now().UTC().Truncate(time.Second)).Seconds()
+			out.Database, out.DatabaseSkewSec = &dbNow, &skew
+		}
+		db.Close()
+	}
+
+	if at, ok := nextZoneTransition(loc, now, 366*24*time.Hour); ok {
+		fromName, fromOffset := at.Add(-time.Minute).In(loc).Zone()
+		toName, toOffset := at.In(loc).Zone()
+		out.NextTransition = &timeTransitionJSON{
+			At:         at.UTC(),
+			FromZone:   fromName,
+			ToZone:     toName,
+			ShiftHours: float64(toOffset-fromOffset) / 3600,
+		}
+	}
+	return out
+}
+
+func printTimeInfo(w io.Writer, out *timeOutput) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "System clock\t%s\n", out.System.Format("2006-01-02 15:04:05 MST (UTC-07:00)"))
+	fmt.Fprintf(tw, "UTC\t%s\n", out.UTC.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(tw, "Local zone\t%s (%s)\n", out.LocalZone, out.LocalZoneSource)
+	fmt.Fprintf(tw, "Display zone\t%s (display.timezone: %s)\n", out.DisplayZone, out.DisplaySetting)
+	switch {
+	case out.DatabaseError != "":
+		fmt.Fprintf(tw, "Database clock\tunavailable: %s\n", out.DatabaseError)
+	case out.Database != nil:
+		fmt.Fprintf(tw, "Database clock\t%s UTC (%s, %+.0fs from system)\n",
+			out.Database.Format("2006-01-02 15:04:05"), out.DatabaseBackend, *out.DatabaseSkewSec)
+	}
+	if tr := out.NextTransition; tr != nil {
+		loc := displayLocation()
+		// Show the instant on both sides: 02:00 EST -> 03:00 EDT.
+		_, fromOffset := tr.At.Add(-time.Minute).In(loc).Zone()
+		before := tr.At.In(time.FixedZone(tr.FromZone, fromOffset))
+		fmt.Fprintf(tw, "Next DST change\t%s -> %s (clocks %+gh, in %s)\n",
+			before.Format("2006-01-02 15:04 MST"), tr.At.In(loc).Format("15:04 MST"), tr.ShiftHours,
+			robotFormatDuration(time.Until(tr.At)))
+	} else {
+		fmt.Fprintf(tw, "Next DST change\tnone within a year\n")
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Cooldowns, expiries and schedules are stored and compared in UTC; the display")
+	fmt.Fprintln(w, "zone only affects how times are shown and how input like \"5pm\" is read.")
+	if out.DatabaseSkewSec != nil && (*out.DatabaseSkewSec > 60 || *out.DatabaseSkewSec < -60) {
+		fmt.Fprintln(w, "Warning: the database clock is more than a minute off; fix the server's time.")
+	}
+}
+
+// localZoneName names the zone Go uses for local time and where it comes
+// from: $TZ, the /etc/localtime link, or neither.
+func localZoneName() (string, string) {
+	if tz, ok := os.LookupEnv("TZ"); ok {
+		if tz == "" {
+			return "UTC", "$TZ is empty"
+		}
+		return strings.TrimPrefix(tz, ":"), "$TZ"
+	}
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(filepath.ToSlash(target), "zoneinfo/"); ok {
+			return name, "/etc/localtime"
+		}
+	}
+	name, _ := time.Now().Zone()
+	return name, "system default"
+}
+
+// nextZoneTransition returns the first instant within limit after from at
+// which loc's UTC offset changes, to the minute.
+func nextZoneTransition(loc *time.Location, from time.Time, limit time.Duration) (time.Time, bool) {
+	_, offset := from.In(loc).Zone()
+	lo := from
+	for step := time.Duration(0); step < limit; step += time.Hour {
+		hi := from.Add(step + time.Hour)
+		if _, o := hi.In(loc).Zone(); o == offset {
+			lo = hi
+			continue
+		}
+		for hi.Sub(lo) > time.Minute {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.In(loc).Zone(); o == offset {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return hi.Truncate(time.Minute), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextZoneTransition(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+
+	at, ok := nextZoneTransition(ny, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 366*24*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), at, "2:00 EST")
+
+	at, ok = nextZoneTransition(ny, at, 366*24*time.Hour)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 11, 1, 6, 0, 0, 0, time.UTC), at, "2:00 EDT")
+
+	_, ok = nextZoneTransition(time.UTC, at, 366*24*time.Hour)
+	assert.False(t, ok)
+}
+
+func TestTime_JSON(t *testing.T) {
+	t.Setenv("CAAM_HOME", t.TempDir())
+	t.Setenv("TZ", "America/New_York")
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("json", true, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	require.NoError(t, runTime(cmd, nil))
+
+	var got timeOutput
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "America/New_York", got.LocalZone)
+	assert.Equal(t, "$TZ", got.LocalZoneSource)
+	assert.Equal(t, "local", got.DisplaySetting)
+	require.NotNil(t, got.Database, got.DatabaseError)
+	assert.InDelta(t, 0, *got.DatabaseSkewSec, 2)
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live dashboard of all profiles",
+	Long: `Shows an auto-refreshing table of every profile across providers with its
+health, cooldown countdown, token expiry, last use and active marker.
+
+Keyboard shortcuts:
+  n - Sort by name          h - Sort by health
+  e - Sort by expiry        c - Sort by cooldown
+  l - Sort by last used     r - Refresh now
+  q - Quit
+
+Examples:
+  caam top                  # Refresh every 5s
+  caam top --interval 1s    # Faster refresh
+  caam top --sort cooldown  # Start sorted by cooldown remaining
+  caam top --once           # Print the table once and exit`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	rootCmd.AddCommand(topCmd)
+	topCmd.Flags().DurationP("interval", "i", 5*time.Second, "refresh interval")
+	topCmd.Flags().String("sort", "name", "initial sort: name, health, expiry, cooldown, last-used")
+	topCmd.Flags().Bool("once", false, "print the table once and exit")
+}
+
+// topSortKeys maps keypresses to sort orders.
+var topSortKeys = map[byte]string{
+	'n': "name",
+	'h': "health",
+	'e': "expiry",
+	'c': "cooldown",
+	'l': "last-used",
+}
+
+// topRow is one profile shown by caam top.
+type topRow struct {
+	Tool          string
+	Profile       string
+	Active        bool
+	Status        health.HealthStatus
+	Score         float64
+	ExpiresAt     time.Time
+	CooldownUntil time.Time
+	LastUsed      time.Time
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	sortBy, _ := cmd.Flags().GetString("sort")
+	once, _ := cmd.Flags().GetBool("once")
+
+	sortBy = strings.ToLower(strings.TrimSpace(sortBy))
+	switch sortBy {
+	case "name", "health", "expiry", "cooldown", "last-used":
+	default:
+		return withExitCode(ExitUsage, fmt.Errorf("invalid --sort %q (supported: name, health, expiry, cooldown, last-used)", sortBy))
+	}
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+
+	db, err := caamdb.Open()
+	if err != nil {
+		db = nil
+	} else {
+		defer db.Close()
+	}
+
+	out := cmd.OutOrStdout()
+	if once {
+		rows, err := collectTopRows(db, time.Now())
+		if err != nil {
+			return err
+		}
+		return renderTop(out, rows, sortBy, time.Now())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// In raw mode the terminal no longer turns "\n" into a carriage return.
+	var inputCh <-chan byte
+	newline := "\n"
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		var restore func()
+		inputCh, restore = setupKeyboardInput()
+		defer restore()
+		newline = "\r\n"
+	}
+
+	var rows []topRow
+	refresh := func() {
+		if fresh, err := collectTopRows(db, time.Now()); err == nil {
+			rows = fresh
+		}
+	}
+	show := func() {
+		var buf strings.Builder
+		_ = renderTop(&buf, rows, sortBy, time.Now())
+		clearScreen(out)
+		fmt.Fprint(out, strings.ReplaceAll(buf.String(), "\n", newline))
+	}
+
+	refresh()
+	show()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	// Countdowns tick every second between refreshes.
+	clock := time.NewTicker(time.Second)
+	defer clock.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			refresh()
+			show()
+		case <-clock.C:
+			show()
+		case key := <-inputCh:
+			switch key {
+			case 'q', 'Q', 3: // 3 is Ctrl+C in raw mode
+				fmt.Fprint(out, newline)
+				return nil
+			case 'r', 'R':
+				refresh()
+				show()
+			default:
+				if order, ok := topSortKeys[key|0x20]; ok {
+					sortBy = order
+					show()
+				}
+			}
+		}
+	}
+}
+
+// collectTopRows gathers every vault profile with its health, expiry,
+// active cooldown and last use. db may be nil.
+func collectTopRows(db *caamdb.DB, now time.Time) ([]topRow, error) {
+	allProfiles, err := vault.ListAll()
+	if err != nil {
+		return nil, err
+	}
+
+	cooldowns := make(map[string]time.Time)
+	if db != nil {
+		if events, err := db.ListActiveCooldowns(now); err == nil {
+			for _, ev := range events {
+				cooldowns[ev.Provider+"/"+ev.ProfileName] = ev.CooldownUntil
+			}
+		}
+	}
+
+	var rows []topRow
+	for _, tool := range knownTools() {
+		profiles := allProfiles[tool]
+		if len(profiles) == 0 {
+			continue
+		}
+		activeProfile, _ := vault.ActiveProfile(tools[tool]())
+		for _, ls := range collectLsRows(tool, profiles, "name") {
+			row := topRow{
+				Tool:          tool,
+				Profile:       ls.name,
+				Active:        ls.name == activeProfile,
+				Status:        ls.status,
+				Score:         ls.score,
+				ExpiresAt:     ls.health.TokenExpiresAt,
+				CooldownUntil: cooldowns[tool+"/"+ls.name],
+				LastUsed:      ls.lastUsed,
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// sortTopRows orders rows by sortBy, falling back to tool and profile name.
+func sortTopRows(rows []topRow, sortBy string) {
+	byName := func(a, b topRow) bool {
+		if a.Tool != b.Tool {
+			return a.Tool < b.Tool
+		}
+		return a.Profile < b.Profile
+	}
+	// laterFirst orders set times newest first and unset times last.
+	laterFirst := func(a, b time.Time) (less, decided bool) {
+		if a.Equal(b) {
+			return false, false
+		}
+		return a.After(b), true
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		switch sortBy {
+		case "health":
+			if a.Score != b.Score {
+				return a.Score > b.Score
+			}
+		case "expiry":
+			if !a.ExpiresAt.Equal(b.ExpiresAt) {
+				if a.ExpiresAt.IsZero() || b.ExpiresAt.IsZero() {
+					return b.ExpiresAt.IsZero()
+				}
+				return a.ExpiresAt.Before(b.ExpiresAt)
+			}
+		case "cooldown":
+			if less, ok := laterFirst(a.CooldownUntil, b.CooldownUntil); ok {
+				return less
+			}
+		case "last-used":
+			if less, ok := laterFirst(a.LastUsed, b.LastUsed); ok {
+				return less
+			}
+		}
+		return byName(a, b)
+	})
+}
+
+// renderTop writes the dashboard for rows sorted by sortBy.
+func renderTop(w io.Writer, rows []topRow, sortBy string, now time.Time) error {
+	sorted := append([]topRow(nil), rows...)
+	sortTopRows(sorted, sortBy)
+
+	fmt.Fprintf(w, "caam top - %s  (sort: %s)\n", now.Format("2006-01-02 15:04:05"), sortBy)
+	fmt.Fprintln(w, "[n]ame [h]ealth [e]xpiry [c]ooldown [l]ast-used  [r]efresh [q]uit")
+	fmt.Fprintln(w)
+
+	if len(sorted) == 0 {
+		fmt.Fprintln(w, "No profiles saved yet.")
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(tw, "  TOOL\tPROFILE\tHEALTH\tEXPIRES\tCOOLDOWN\tLAST USED")
+	for _, row := range sorted {
+		marker := "  "
+		if row.Active {
+			marker = "● "
+		}
+		cooldown := "-"
+		if row.CooldownUntil.After(now) {
+			cooldown = formatDurationShort(row.CooldownUntil.Sub(now))
+		}
+		lastUsed := "-"
+		if !row.LastUsed.IsZero() {
+			lastUsed = formatTimeAgo(row.LastUsed)
+		}
+		fmt.Fprintf(tw, "%s%s\t%s\t%s %s\t%s\t%s\t%s\n",
+			marker, row.Tool, row.Profile, row.Status.Icon(), row.Status, formatExpiry(row.ExpiresAt), cooldown, lastUsed)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/spf13/cobra"
+)
+
+func TestSortTopRows(t *testing.T) {
+	now := time.Now()
+	rows := []topRow{
+		{Tool: "codex", Profile: "b", Score: 1.3, ExpiresAt: now.Add(time.Hour)},
+		{Tool: "claude", Profile: "z", Score: 0.3, CooldownUntil: now.Add(30 * time.Minute), LastUsed: now.Add(-time.Hour)},
+		{Tool: "claude", Profile: "a", Score: -1, ExpiresAt: now.Add(-time.Hour), CooldownUntil: now.Add(2 * time.Hour), LastUsed: now.Add(-time.Minute)},
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{"name", []string{"claude/a", "claude/z", "codex/b"}},
+		{"health", []string{"codex/b", "claude/z", "claude/a"}},
+		{"expiry", []string{"claude/a", "codex/b", "claude/z"}},
+		{"cooldown", []string{"claude/a", "claude/z", "codex/b"}},
+		{"last-used", []string{"claude/a", "claude/z", "codex/b"}},
+	}
+	for _, tt := range tests {
+		sorted := append([]topRow(nil), rows...)
+		sortTopRows(sorted, tt.sortBy)
+		var got []string
+		for _, row := range sorted {
+			got = append(got, row.Tool+"/"+row.Profile)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("sortTopRows(%s) = %v, want %v", tt.sortBy, got, tt.want)
+		}
+	}
+}
+
+func TestTopOnce(t *testing.T) {
+	_, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+	t.Setenv("HOME", t.TempDir())
+	oldHealthStore := healthStore
+	healthStore = nil
+	defer func() { healthStore = oldHealthStore }()
+
+	for _, name := range []string{"alt", "work"} {
+		dir := vault.ProfilePath("codex", name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"account":"`+name+`"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(os.Getenv("CODEX_HOME"), "auth.json"), []byte(`{"account":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := caamdb.Open()
+	if err != nil {
+		t.Fatalf("db.Open() error = %v", err)
+	}
+	if _, err := db.SetCooldown("codex", "alt", time.Now(), 90*time.Minute, ""); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+	if err := db.LogEvent(caamdb.Event{Type: caamdb.EventActivate, Provider: "codex", ProfileName: "work", Timestamp: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("LogEvent() error = %v", err)
+	}
+	db.Close()
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Duration("interval", 5*time.Second, "")
+	cmd.Flags().String("sort", "cooldown", "")
+	cmd.Flags().Bool("once", true, "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := runTop(cmd, nil); err != nil {
+		t.Fatalf("runTop() error = %v", err)
+	}
+
+	lines := strings.Split(out.String(), "\n")
+	var alt, work int
+	for i, line := range lines {
+		switch {
+		case strings.Contains(line, " alt "):
+			alt = i
+			if !strings.Contains(line, "1h30m") && !strings.Contains(line, "1h29m") {
+				t.Errorf("alt row missing cooldown countdown: %q", line)
+			}
+		case strings.Contains(line, " work "):
+			work = i
+			if !strings.HasPrefix(line, "● codex") || !strings.Contains(line, "2 hours ago") {
+				t.Errorf("work row = %q, want active marker and last use", line)
+			}
+		}
+	}
+	if alt == 0 || work == 0 || alt > work {
+		t.Fatalf("output not sorted by cooldown:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "(sort: cooldown)") {
+		t.Errorf("output missing sort header:\n%s", out.String())
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/version"
 )
 
@@ -64,6 +65,9 @@ type exportTarget struct {
 	Tool    string
 	Profile string
 	DirPath string
+	// Vault decrypts the profile's files, so the archive carries plaintext
+	// that the importing machine can use without this vault's key.
+	Vault *authfile.Vault
 }
 
 type exportFileSpec struct {
@@ -72,6 +76,7 @@ type exportFileSpec struct {
 	Mode    int64
 	Size    int64
 	ModTime time.Time
+	Data    []byte
 }
 
 type importOptions struct {
@@ -128,6 +133,7 @@ func resolveExportTargets(v *authfile.Vault, req exportRequest) ([]exportTarget,
 					Tool:    tool,
 					Profile: profile,
 					DirPath: v.ProfilePath(tool, profile),
+					Vault:   v,
 				})
 			}
 		}
@@ -139,7 +145,7 @@ func resolveExportTargets(v *authfile.Vault, req exportRequest) ([]exportTarget,
 			return nil, fmt.Errorf("tool cannot be empty")
 		}
 		if _, ok := tools[tool]; !ok {
-			return nil, fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+			return nil, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 		}
 
 		profiles, err := v.List(tool)
@@ -154,6 +160,7 @@ func resolveExportTargets(v *authfile.Vault, req exportRequest) ([]exportTarget,
 				Tool:    tool,
 				Profile: profile,
 				DirPath: v.ProfilePath(tool, profile),
+				Vault:   v,
 			})
 		}
 		return targets, nil
@@ -165,7 +172,7 @@ func resolveExportTargets(v *authfile.Vault, req exportRequest) ([]exportTarget,
 			return nil, fmt.Errorf("tool and profile are required")
 		}
 		if _, ok := tools[tool]; !ok {
-			return nil, fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", tool)
+			return nil, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools())
 		}
 
 		dirPath := v.ProfilePath(tool, profile)
@@ -179,7 +186,7 @@ func resolveExportTargets(v *authfile.Vault, req exportRequest) ([]exportTarget,
 			return nil, fmt.Errorf("profile %s/%s is not a directory", tool, profile)
 		}
 
-		return []exportTarget{{Tool: tool, Profile: profile, DirPath: dirPath}}, nil
+		return []exportTarget{{Tool: tool, Profile: profile, DirPath: dirPath, Vault: v}}, nil
 	}
 }
 
@@ -230,10 +237,12 @@ func buildExportManifest(targets []exportTarget) (*vaultExportManifest, []export
 			rel = filepath.ToSlash(rel)
 			tarPath := path.Join(strings.TrimSuffix(tarVaultPrefix, "/"), t.Tool, t.Profile, rel)
 
-			hash, size, mode, modTime, err := sha256File(p)
+			data, mode, modTime, err := readExportFile(t.Vault, p)
 			if err != nil {
 				return err
 			}
+			sum := sha256.Sum256(data)
+			hash, size := hex.EncodeToString(sum[:]), int64(len(data))
 
 			item.Files = append(item.Files, vaultExportFile{
 				Path:   tarPath,
@@ -247,6 +256,7 @@ func buildExportManifest(targets []exportTarget) (*vaultExportManifest, []export
 				Mode:    int64(mode.Perm()),
 				Size:    size,
 				ModTime: modTime,
+				Data:    data,
 			})
 			return nil
 		})
@@ -293,11 +303,6 @@ func writeExportArchive(w io.Writer, manifest *vaultExportManifest, files []expo
 			return fmt.Errorf("refusing to export unusually large file (%d bytes): %s", f.Size, f.SrcPath)
 		}
 
-		src, err := os.Open(f.SrcPath)
-		if err != nil {
-			return fmt.Errorf("open %s: %w", f.SrcPath, err)
-		}
-
 		hdr := &tar.Header{
 			Name:    f.TarPath,
 			Mode:    f.Mode,
@@ -305,16 +310,11 @@ func writeExportArchive(w io.Writer, manifest *vaultExportManifest, files []expo
 			ModTime: f.ModTime,
 		}
 		if err := tw.WriteHeader(hdr); err != nil {
-			src.Close()
 			return fmt.Errorf("write tar header: %w", err)
 		}
-		if _, err := io.Copy(tw, src); err != nil {
-			src.Close()
+		if _, err := tw.Write(f.Data); err != nil {
 			return fmt.Errorf("write tar body: %w", err)
 		}
-		if err := src.Close(); err != nil {
-			return fmt.Errorf("close %s: %w", f.SrcPath, err)
-		}
 	}
 
 	if err := tw.Close(); err != nil {
@@ -431,7 +431,7 @@ func importArchive(r io.Reader, v *authfile.Vault, opt importOptions) (*vaultExp
 			return nil, err
 		}
 		if _, ok := tools[opt.AsTool]; !ok {
-			return nil, fmt.Errorf("unknown tool: %s (supported: codex, claude, gemini)", opt.AsTool)
+			return nil, fmt.Errorf("unknown tool: %s (supported: %s)", opt.AsTool, supportedTools())
 		}
 		if err := validateVaultSegment("profile", opt.AsProfile); err != nil {
 			return nil, err
@@ -593,30 +593,64 @@ func importArchive(r io.Reader, v *authfile.Vault, opt importOptions) (*vaultExp
 		return nil, fmt.Errorf("archive incomplete: extracted %d/%d files", len(extracted), len(expected))
 	}
 
-	// Promote temp dirs to final location atomically per profile.
+	// Promote temp dirs to their final locations. Profiles being replaced are
+	// moved aside first rather than deleted, so a failure partway puts every
+	// profile back and the import is all or nothing.
+	var promoted []*importTarget
+	asides := make(map[*importTarget]string)
+	undo := func() {
+		for i := len(promoted) - 1; i >= 0; i-- {
+			_ = os.RemoveAll(promoted[i].FinalDir)
+		}
+		for tgt, aside := range asides {
+			_ = os.Rename(aside, tgt.FinalDir)
+		}
+	}
 	for _, tgt := range targets {
 		if tgt.TempDir == "" {
 			continue
 		}
 
-		if opt.Force {
-			_ = os.RemoveAll(tgt.FinalDir)
-		}
 		if err := os.MkdirAll(filepath.Dir(tgt.FinalDir), 0700); err != nil {
+			undo()
 			cleanup()
 			return nil, fmt.Errorf("create final parent dir: %w", err)
 		}
-		if err := os.Rename(tgt.TempDir, tgt.FinalDir); err != nil {
+		if opt.Force {
+			if _, err := os.Stat(tgt.FinalDir); err == nil {
+				aside := tgt.TempDir + ".replaced"
+				if err := renameDir(tgt.FinalDir, aside); err != nil {
+					undo()
+					cleanup()
+					return nil, fmt.Errorf("move aside %s/%s: %w", tgt.Tool, tgt.Profile, err)
+				}
+				asides[tgt] = aside
+			}
+		}
+		if err := renameDir(tgt.TempDir, tgt.FinalDir); err != nil {
+			undo()
 			cleanup()
 			return nil, fmt.Errorf("finalize %s/%s: %w", tgt.Tool, tgt.Profile, err)
 		}
 		tgt.TempDir = ""
+		promoted = append(promoted, tgt)
+	}
+	for _, aside := range asides {
+		_ = os.RemoveAll(aside)
 	}
 
 	cleanup()
 	return manifest, nil
 }
 
+// renameDir renames a directory, subject to fault injection.
+func renameDir(from, to string) error {
+	if err := faultinject.Check(faultinject.OpRename); err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
 func splitVaultTarPath(name string) (tool, profile, rel string, err error) {
 	clean, err := cleanTarName(name)
 	if err != nil {
@@ -735,26 +769,29 @@ func writeTarBytes(tw *tar.Writer, name string, mode int64, modTime time.Time, d
 	return nil
 }
 
-func sha256File(p string) (hash string, size int64, mode fs.FileMode, modTime time.Time, err error) {
+// readExportFile returns the plaintext of a vault file, decrypted and
+// fetched from the keychain as needed, with its mode and modification time.
+func readExportFile(v *authfile.Vault, p string) (data []byte, mode fs.FileMode, modTime time.Time, err error) {
 	st, err := os.Stat(p)
 	if err != nil {
-		return "", 0, 0, time.Time{}, err
+		return nil, 0, time.Time{}, err
 	}
 	if !st.Mode().IsRegular() {
-		return "", 0, 0, time.Time{}, fmt.Errorf("not a regular file: %s", p)
+		return nil, 0, time.Time{}, fmt.Errorf("not a regular file: %s", p)
 	}
-
-	f, err := os.Open(p)
-	if err != nil {
-		return "", 0, 0, time.Time{}, err
+	if st.Size() > maxFileBytes {
+		return nil, 0, time.Time{}, fmt.Errorf("refusing to export unusually large file (%d bytes): %s", st.Size(), p)
 	}
-	defer f.Close()
 
-	h := sha256.New()
-	if _, err := io.Copy(h, f); err != nil {
-		return "", 0, 0, time.Time{}, err
+	if v != nil {
+		data, err = v.ReadFile(p)
+	} else {
+		data, err = os.ReadFile(p)
 	}
-	return hex.EncodeToString(h.Sum(nil)), st.Size(), st.Mode(), st.ModTime(), nil
+	if err != nil {
+		return nil, 0, time.Time{}, err
+	}
+	return data, st.Mode(), st.ModTime(), nil
 }
 
 func writeFileAtomicWithHash(dst string, r io.Reader, size int64, mode os.FileMode) (string, error) {
@@ -775,6 +812,9 @@ func writeFileAtomicWithHash(dst string, r io.Reader, size int64, mode os.FileMo
 	w := io.MultiWriter(f, h)
 
 	n, err := io.CopyN(w, r, size)
+	if err == nil {
+		err = faultinject.Check(faultinject.OpCopy)
+	}
 	if err != nil {
 		f.Close()
 		_ = os.Remove(tmp)
@@ -789,6 +829,10 @@ func writeFileAtomicWithHash(dst string, r io.Reader, size int64, mode os.FileMo
 		_ = os.Remove(tmp)
 		return "", err
 	}
+	if err := faultinject.Check(faultinject.OpRename); err != nil {
+		_ = os.Remove(tmp)
+		return "", err
+	}
 	if err := os.Rename(tmp, dst); err != nil {
 		_ = os.Remove(tmp)
 		return "", err
@@ -53,6 +53,53 @@ func TestExportImport_RoundTripSingleProfile(t *testing.T) {
 	}
 }
 
+func TestExport_EncryptedVaultCarriesPlaintext(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	srcVault := authfile.NewVault(filepath.Join(tmpDir, "src-vault"))
+	profileDir := srcVault.ProfilePath("codex", "work")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	wantAuth := `{"access_token":"test-token"}`
+	if err := os.WriteFile(filepath.Join(profileDir, "auth.json"), []byte(wantAuth), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srcVault.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := srcVault.EncryptAll(); err != nil || n != 1 {
+		t.Fatalf("EncryptAll() = %d, %v", n, err)
+	}
+
+	targets, err := resolveExportTargets(srcVault, exportRequest{Tool: "codex", Profile: "work"})
+	if err != nil {
+		t.Fatalf("resolveExportTargets() error = %v", err)
+	}
+	manifest, files, err := buildExportManifest(targets)
+	if err != nil {
+		t.Fatalf("buildExportManifest() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := writeExportArchive(&buf, manifest, files); err != nil {
+		t.Fatalf("writeExportArchive() error = %v", err)
+	}
+
+	// A machine without the key imports a usable login; the hash check in
+	// importArchive passes only if the manifest hashes the plaintext.
+	dstVault := authfile.NewVault(filepath.Join(tmpDir, "dst-vault"))
+	if _, err := importArchive(bytes.NewReader(buf.Bytes()), dstVault, importOptions{}); err != nil {
+		t.Fatalf("importArchive() error = %v", err)
+	}
+	gotAuth, err := os.ReadFile(filepath.Join(dstVault.ProfilePath("codex", "work"), "auth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotAuth) != wantAuth {
+		t.Fatalf("auth.json = %q, want %q", gotAuth, wantAuth)
+	}
+}
+
 func TestImport_WithAsRenamesProfile(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -81,10 +81,8 @@ func runUninstall(cmd *cobra.Command, args []string) error {
 	}
 
 	if !force {
-		fmt.Printf("\nProceed? [y/N]: ")
-		var confirm string
-		fmt.Scanln(&confirm)
-		if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
+		fmt.Println()
+		if !confirmPrompt(cmd, false, "Proceed?") {
 			fmt.Println("Cancelled")
 			return nil
 		}
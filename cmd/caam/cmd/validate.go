@@ -14,6 +14,7 @@ import (
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/claude"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/codex"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/gemini"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/plugin"
 )
 
 var validateCmd = &cobra.Command{
@@ -75,6 +76,7 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	registry.Register(claude.New())
 	registry.Register(codex.New())
 	registry.Register(gemini.New())
+	plugin.Register(registry)
 
 	var results []ValidationOutput
 	var err error
@@ -186,6 +188,10 @@ func validateProfile(ctx context.Context, prov provider.Provider, prof *profile.
 		return nil, err
 	}
 
+	if !passive && result.Valid {
+		stampLoginVerified(prov.ID(), prof.Name)
+	}
+
 	output := &ValidationOutput{
 		Provider:  result.Provider,
 		Profile:   result.Profile,
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/bundle"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Inspect the profile vault",
+}
+
+var vaultStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show vault size, backups and growth",
+	Long: `Report what the vault holds on disk: profiles and auto-backups per tool,
+total size, the largest files, import snapshots, and the oldest and newest
+auto-backup.
+
+Each run records the vault's size in the database (at most once a day), and
+growth is reported against the oldest sample inside --days. Use 'caam gc' to
+prune old auto-backups when they dominate the total.
+
+Examples:
+  caam vault stats
+  caam vault stats --top 20 --days 90
+  caam vault stats --json`,
+	Args: cobra.NoArgs,
+	RunE: runVaultStats,
+}
+
+func init() {
+	rootCmd.AddCommand(vaultCmd)
+	vaultCmd.AddCommand(vaultStatsCmd)
+	vaultStatsCmd.Flags().Int("top", 5, "number of largest files to list")
+	vaultStatsCmd.Flags().Int("days", 30, "window for growth, in days")
+	vaultStatsCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+type vaultProviderJSON struct {
+	Provider     string     `json:"provider"`
+	Profiles     int        `json:"profiles"`
+	Backups      int        `json:"backups"`
+	Bytes        int64      `json:"bytes"`
+	BackupBytes  int64      `json:"backup_bytes"`
+	OldestBackup *time.Time `json:"oldest_backup,omitempty"`
+	NewestBackup *time.Time `json:"newest_backup,omitempty"`
+	GrowthBytes  *int64     `json:"growth_bytes,omitempty"`
+}
+
+type vaultFileJSON struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+}
+
+type vaultGrowthJSON struct {
+	Since      time.Time `json:"since"`
+	FromBytes  int64     `json:"from_bytes"`
+	ToBytes    int64     `json:"to_bytes"`
+	DeltaBytes int64     `json:"delta_bytes"`
+}
+
+type vaultStatsOutput struct {
+	jsonStatus
+	Path          string              `json:"path"`
+	TotalBytes    int64               `json:"total_bytes"`
+	Profiles      int                 `json:"profiles"`
+	Backups       int                 `json:"backups"`
+	Providers     []vaultProviderJSON `json:"providers"`
+	Largest       []vaultFileJSON     `json:"largest"`
+	Snapshots     int                 `json:"import_snapshots"`
+	SnapshotBytes int64               `json:"import_snapshot_bytes"`
+	Growth        *vaultGrowthJSON    `json:"growth,omitempty"`
+}
+
+func runVaultStats(cmd *cobra.Command, args []string) error {
+	top, _ := cmd.Flags().GetInt("top")
+	days, _ := cmd.Flags().GetInt("days")
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	if days < 1 {
+		days = 1
+	}
+
+	output, err := collectVaultStats(vault, top, days, time.Now())
+	if jsonOut {
+		return writeJSONResult(cmd, output, err)
+	}
+	if err != nil {
+		return err
+	}
+	printVaultStats(cmd.OutOrStdout(), output, days)
+	return nil
+}
+
+// collectVaultStats measures v, records today's sample and compares it with
+// the oldest sample in the last days.
+func collectVaultStats(v *authfile.Vault, top, days int, now time.Time) (*vaultStatsOutput, error) {
+	output := &vaultStatsOutput{Path: v.BasePath()}
+	stats, err := v.Stats(top)
+	if err != nil {
+		return output, fmt.Errorf("measure vault: %w", err)
+	}
+
+	output.TotalBytes = stats.TotalBytes
+	output.Providers = make([]vaultProviderJSON, 0, len(stats.Providers))
+	for _, p := range stats.Providers {
+		entry := vaultProviderJSON{
+			Provider:    p.Provider,
+			Profiles:    p.Profiles,
+			Backups:     p.Backups,
+			Bytes:       p.Bytes,
+			BackupBytes: p.BackupBytes,
+		}
+		if !p.OldestBackup.IsZero() {
+			oldest, newest := p.OldestBackup, p.NewestBackup
+			entry.OldestBackup, entry.NewestBackup = &oldest, &newest
+		}
+		output.Profiles += p.Profiles
+		output.Backups += p.Backups
+		output.Providers = append(output.Providers, entry)
+	}
+	output.Largest = make([]vaultFileJSON, 0, len(stats.Largest))
+	for _, f := range stats.Largest {
+		output.Largest = append(output.Largest, vaultFileJSON{
+			Path:  f.Provider + "/" + f.Profile + "/" + f.Name,
+			Bytes: f.Bytes,
+		})
+	}
+	output.Snapshots, output.SnapshotBytes = dirUsage(bundle.SnapshotDir(v.BasePath()))
+
+	// Growth history is best effort: the vault numbers stand on their own.
+	db, err := caamdb.Open()
+	if err != nil {
+		return output, nil
+	}
+	defer db.Close()
+
+	history, err := db.VaultSizes(now.AddDate(0, 0, -days))
+	if err != nil {
+		return output, nil
+	}
+	output.Growth = vaultGrowth(history, output)
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if len(history) == 0 || history[len(history)-1].RecordedAt.Before(startOfDay) {
+		samples := make([]caamdb.VaultSize, 0, len(stats.Providers))
+		for _, p := range stats.Providers {
+			samples = append(samples, caamdb.VaultSize{Provider: p.Provider, Profiles: p.Profiles, Bytes: p.Bytes})
+		}
+		_ = db.RecordVaultSizes(now, samples)
+	}
+	return output, nil
+}
+
+// vaultGrowth compares the current sizes with the oldest recorded sample.
+func vaultGrowth(history []caamdb.VaultSize, output *vaultStatsOutput) *vaultGrowthJSON {
+	if len(history) == 0 {
+		return nil
+	}
+	first := history[0].RecordedAt
+	before := make(map[string]int64)
+	var from int64
+	for _, s := range history {
+		if !s.RecordedAt.Equal(first) {
+			break
+		}
+		before[s.Provider] = s.Bytes
+		from += s.Bytes
+	}
+	for i := range output.Providers {
+		delta := output.Providers[i].Bytes - before[output.Providers[i].Provider]
+		output.Providers[i].GrowthBytes = &delta
+	}
+	return &vaultGrowthJSON{
+		Since:      first,
+		FromBytes:  from,
+		ToBytes:    output.TotalBytes,
+		DeltaBytes: output.TotalBytes - from,
+	}
+}
+
+// dirUsage counts the entries directly under dir and the bytes beneath it.
+func dirUsage(dir string) (entries int, bytes int64) {
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0
+	}
+	entries = len(list)
+	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && info.Mode().IsRegular() {
+			bytes += info.Size()
+		}
+		return nil
+	})
+	return entries, bytes
+}
+
+func printVaultStats(out io.Writer, s *vaultStatsOutput, days int) {
+	fmt.Fprintf(out, "Vault: %s\n", s.Path)
+	fmt.Fprintf(out, "Total: %s in %d profile(s), %d auto-backup(s)\n\n", formatBytes(s.TotalBytes), s.Profiles, s.Backups)
+
+	if len(s.Providers) > 0 {
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "PROVIDER\tPROFILES\tBACKUPS\tSIZE\tOLDEST BACKUP\tNEWEST BACKUP")
+		for _, p := range s.Providers {
+			oldest, newest := "-", "-"
+			if p.OldestBackup != nil {
+				oldest = p.OldestBackup.Format("2006-01-02 15:04")
+				newest = p.NewestBackup.Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", p.Provider, p.Profiles, p.Backups, formatBytes(p.Bytes), oldest, newest)
+		}
+		w.Flush()
+		fmt.Fprintln(out)
+	}
+
+	if len(s.Largest) > 0 {
+		fmt.Fprintln(out, "Largest files:")
+		for _, f := range s.Largest {
+			fmt.Fprintf(out, "  %9s  %s\n", formatBytes(f.Bytes), f.Path)
+		}
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintf(out, "Import snapshots: %d (%s)\n", s.Snapshots, formatBytes(s.SnapshotBytes))
+
+	if s.Growth == nil {
+		fmt.Fprintln(out, "Growth: no earlier samples yet (recorded once a day by this command)")
+	} else {
+		fmt.Fprintf(out, "Growth (last %d days): %s since %s (%s → %s)\n",
+			days, formatSignedBytes(s.Growth.DeltaBytes), s.Growth.Since.Local().Format("2006-01-02"),
+			formatBytes(s.Growth.FromBytes), formatBytes(s.Growth.ToBytes))
+		var parts []string
+		for _, p := range s.Providers {
+			if p.GrowthBytes != nil && *p.GrowthBytes != 0 {
+				parts = append(parts, p.Provider+" "+formatSignedBytes(*p.GrowthBytes))
+			}
+		}
+		sort.Strings(parts)
+		if len(parts) > 0 {
+			fmt.Fprintf(out, "  %s\n", strings.Join(parts, ", "))
+		}
+	}
+
+	var backupBytes int64
+	for _, p := range s.Providers {
+		backupBytes += p.BackupBytes
+	}
+	if s.TotalBytes > 0 && backupBytes*2 > s.TotalBytes {
+		fmt.Fprintf(out, "\nAuto-backups take %s of %s; 'caam gc --dry-run' shows what pruning would reclaim.\n",
+			formatBytes(backupBytes), formatBytes(s.TotalBytes))
+	}
+}
+
+// formatSignedBytes is formatBytes with an explicit sign.
+func formatSignedBytes(b int64) string {
+	if b < 0 {
+		return "-" + formatBytes(-b)
+	}
+	return "+" + formatBytes(b)
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+var vaultEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the vault at rest",
+	Long: `Turns on encryption for the vault and encrypts the profiles already in it,
+in place. From then on every auth and config file caam saves is encrypted
+with AES-256-GCM under a key derived from your passphrase (Argon2id), and
+decrypted only when a profile is activated or inspected. Profile names and
+metadata stay readable.
+
+The key is stored in the OS keyring (macOS keychain, or the Secret Service
+via secret-tool on Linux) so caam can use the vault without asking. With
+--no-keyring, or where no keyring is available, caam asks for the passphrase
+once per command instead; set $CAAM_VAULT_PASSPHRASE or
+runtime.passphrase_command to supply it non-interactively.
+
+Encrypted profiles are always activated by copy, even with the symlink
+activation mode, and live links into the vault are converted to copies
+first. Running it again encrypts any files still in plaintext and stores the
+key in the keyring if it isn't there.
+
+Examples:
+  caam vault encrypt
+  caam vault encrypt --no-keyring`,
+	Args: cobra.NoArgs,
+	RunE: runVaultEncrypt,
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultEncryptCmd)
+	vaultEncryptCmd.Flags().Bool("no-keyring", false, "don't store the key in the OS keyring; ask for the passphrase each session")
+	authfile.SetDefaultKeySource(vaultKeySource)
+	authfile.SetDefaultKeyring(func() keyring.Backend { return vaultKeyring() })
+}
+
+// vaultKeyring returns the credential store holding vault keys. Tests swap it.
+var vaultKeyring = keyring.System
+
+// vaultKeyringService names vault keys in the keyring; the account is the
+// vault path, so each config context's vault has its own key.
+const vaultKeyringService = "caam-vault"
+
+func runVaultEncrypt(cmd *cobra.Command, args []string) error {
+	noKeyring, _ := cmd.Flags().GetBool("no-keyring")
+	out := cmd.OutOrStdout()
+
+	var key []byte
+	if !vault.Encrypted() {
+		passphrase, err := vaultPassphrase(true)
+		if err != nil {
+			return err
+		}
+		if key, err = vault.EnableEncryption(passphrase); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Enabled encryption for %s\n", vault.BasePath())
+	} else {
+		var err error
+		if key, err = vaultKeySource(vault); err != nil {
+			return err
+		}
+		if err := vault.Unlock(key); err != nil {
+			return err
+		}
+	}
+
+	// A live link into the vault would point at ciphertext once it is sealed.
+	for _, tool := range knownTools() {
+		if n, err := vault.Unlink(tools[tool]()); err != nil {
+			return fmt.Errorf("convert %s links to copies: %w", tool, err)
+		} else if n > 0 {
+			fmt.Fprintf(out, "Converted %d linked %s file(s) to copies\n", n, tool)
+		}
+	}
+	n, err := vault.EncryptAll()
+	if err != nil {
+		return fmt.Errorf("encrypt vault: %w (already encrypted files are unaffected; run again to finish)", err)
+	}
+	fmt.Fprintf(out, "Encrypted %d file(s)\n", n)
+	if vault.ActivationMode() == authfile.ActivationSymlink {
+		fmt.Fprintln(out, "Note: activations copy files while the vault is encrypted.")
+	}
+
+	if noKeyring {
+		fmt.Fprintln(out, "Key not stored: caam will ask for the passphrase (or read $CAAM_VAULT_PASSPHRASE) each session.")
+		return nil
+	}
+	kr := vaultKeyring()
+	if err := kr.Set(vaultKeyringService, vault.BasePath(), base64.StdEncoding.EncodeToString(key)); err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: could not store the key in the OS keyring (%v); caam will ask for the passphrase each session.\n", err)
+		return nil
+	}
+	fmt.Fprintf(out, "Stored the vault key in the %s\n", kr.Name())
+	return nil
+}
+
+// vaultKeySource unlocks an encrypted vault with the key in the OS keyring,
+// falling back to the passphrase.
+func vaultKeySource(v *authfile.Vault) ([]byte, error) {
+	if stored, err := vaultKeyring().Get(vaultKeyringService, v.BasePath()); err == nil {
+		if key, err := base64.StdEncoding.DecodeString(stored); err == nil && v.Unlock(key) == nil {
+			return key, nil
+		}
+	}
+	passphrase, err := vaultPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	return v.DeriveKey(passphrase)
+}
+
+// vaultPassphrase returns the vault passphrase from $CAAM_VAULT_PASSPHRASE,
+// runtime.passphrase_command or a prompt. A new passphrase is asked twice.
+func vaultPassphrase(isNew bool) (string, error) {
+	if p := os.Getenv("CAAM_VAULT_PASSPHRASE"); p != "" {
+		return p, nil
+	}
+	if isNew {
+		return encryptionPassword("")
+	}
+	p, err := passphraseFromCommand()
+	if err != nil || p != "" {
+		return p, err
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("%w: set CAAM_VAULT_PASSPHRASE, or run 'caam vault encrypt' in a terminal to store the key in the keyring", authfile.ErrVaultLocked)
+	}
+	return promptPassword("Vault passphrase: ")
+}
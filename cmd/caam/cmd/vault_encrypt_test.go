@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+func TestVaultEncrypt_MigratesAndStoresKey(t *testing.T) {
+	setupAccountsTest(t)
+	t.Setenv("CAAM_VAULT_PASSPHRASE", "correct horse")
+	kr := keyring.NewMemory()
+	original := vaultKeyring
+	vaultKeyring = func() keyring.Backend { return kr }
+	t.Cleanup(func() { vaultKeyring = original })
+
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	credsPath := filepath.Join(vault.ProfilePath("claude", "work"), ".credentials.json")
+
+	run := func() string {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("no-keyring", false, "")
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&out)
+		require.NoError(t, runVaultEncrypt(cmd, nil))
+		return out.String()
+	}
+
+	out := run()
+	assert.Contains(t, out, "Encrypted 1 file(s)")
+	raw, err := os.ReadFile(credsPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "alice@example.com", "the vault copy is encrypted")
+
+	_, err = kr.Get(vaultKeyringService, vault.BasePath())
+	require.NoError(t, err, "the key is stored in the keyring")
+
+	// A new process finds the key in the keyring, without the passphrase.
+	t.Setenv("CAAM_VAULT_PASSPHRASE", "")
+	vault = authfile.NewVault(vault.BasePath())
+	id := getVaultIdentity("claude", "work")
+	require.NotNil(t, id)
+	assert.Equal(t, "alice@example.com", id.Email)
+
+	assert.Contains(t, run(), "Encrypted 0 file(s)", "running again is a no-op")
+}
+
+// encryptTestVault seals every file already in the test vault.
+func encryptTestVault(t *testing.T) {
+	t.Helper()
+	_, err := vault.EnableEncryption("correct horse")
+	require.NoError(t, err)
+	n, err := vault.EncryptAll()
+	require.NoError(t, err)
+	require.NotZero(t, n)
+}
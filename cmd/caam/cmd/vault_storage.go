@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+var vaultStorageCmd = &cobra.Command{
+	Use:   "storage [file|keychain]",
+	Short: "Show or change where vault credentials are stored",
+	Long: `Shows or changes where the vault keeps the contents of saved auth and
+config files.
+
+  file      In the profile directories under the vault (default)
+  keychain  In the OS credential store: the macOS keychain, or the Secret
+            Service via secret-tool on Linux. Each profile directory keeps a
+            small stub per file naming its keychain item.
+
+Profile names, meta.json and the rest of the vault stay on disk either way,
+so listing and inspecting profiles works the same. Activation always copies
+files out of the keychain, even with the symlink activation mode.
+
+Changing the backend saves runtime.storage and moves the files already in
+the vault. Deleting a profile removes its keychain items. Bundles made with
+'caam bundle export' carry the stubs, not the credentials; switch back to
+file storage before exporting.
+
+Examples:
+  caam vault storage
+  caam vault storage keychain
+  caam vault storage file`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVaultStorage,
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultStorageCmd)
+}
+
+func runVaultStorage(cmd *cobra.Command, args []string) error {
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+		authfile.ApplyVaultSettings(vault)
+	}
+	out := cmd.OutOrStdout()
+
+	if len(args) == 0 {
+		fmt.Fprintf(out, "Storage: %s\n", describeStorage(vault.Storage()))
+		return nil
+	}
+
+	storage, err := authfile.ParseStorage(args[0], vaultKeyring())
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+	if kb, ok := storage.(*authfile.KeychainBackend); ok {
+		if _, err := kb.Keyring.Get(vaultKeyringService, vault.BasePath()); errors.Is(err, keyring.ErrUnavailable) {
+			return fmt.Errorf("keychain storage: %w (install secret-tool on Linux)", err)
+		}
+	}
+
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	spmCfg.Runtime.Storage = storage.Name()
+	if err := spmCfg.Save(); err != nil {
+		return fmt.Errorf("save config: %w", err)
+	}
+	vault.SetStorage(storage)
+	fmt.Fprintf(out, "Storage: %s\n", describeStorage(storage))
+
+	n, err := vault.MigrateStorage()
+	if err != nil {
+		return fmt.Errorf("move vault files: %w (moved files are unaffected; run again to finish)", err)
+	}
+	if n > 0 {
+		fmt.Fprintf(out, "  moved %d file(s)\n", n)
+	}
+	return nil
+}
+
+func describeStorage(storage authfile.StorageBackend) string {
+	if kb, ok := storage.(*authfile.KeychainBackend); ok && kb.Keyring != nil {
+		return fmt.Sprintf("%s (%s)", kb.Name(), kb.Keyring.Name())
+	}
+	return storage.Name()
+}
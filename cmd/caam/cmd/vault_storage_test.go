@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+func TestVaultStorage_MovesProfilesBothWays(t *testing.T) {
+	setupAccountsTest(t)
+	kr := keyring.NewMemory()
+	original := vaultKeyring
+	vaultKeyring = func() keyring.Backend { return kr }
+	t.Cleanup(func() { vaultKeyring = original })
+
+	writeClaudeVaultProfile(t, "work", "alice@example.com")
+	credsPath := filepath.Join(vault.ProfilePath("claude", "work"), ".credentials.json")
+
+	run := func(args ...string) string {
+		var out bytes.Buffer
+		cmd := &cobra.Command{}
+		cmd.SetOut(&out)
+		require.NoError(t, runVaultStorage(cmd, args))
+		return out.String()
+	}
+
+	out := run("keychain")
+	assert.Contains(t, out, "Storage: keychain (memory)")
+	assert.Contains(t, out, "moved 1 file(s)")
+	raw, err := os.ReadFile(credsPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "alice@example.com", "the vault keeps only a stub")
+
+	spmCfg, err := config.LoadSPMConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "keychain", spmCfg.Runtime.Storage)
+
+	id := getVaultIdentity("claude", "work")
+	require.NotNil(t, id)
+	assert.Equal(t, "alice@example.com", id.Email)
+
+	assert.Contains(t, run("file"), "moved 1 file(s)")
+	raw, err = os.ReadFile(credsPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "alice@example.com")
+
+	err = runVaultStorage(&cobra.Command{}, []string{"s3"})
+	require.Error(t, err)
+	assert.Equal(t, ExitUsage, ExitCode(err))
+}
@@ -2,13 +2,16 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/spf13/cobra"
 )
 
 // TestBackupCommand_UnknownTool tests backup command rejects unknown tools.
@@ -569,3 +572,126 @@ func TestVaultDelete(t *testing.T) {
 		t.Errorf("Expected 0 profiles after delete, got %d", len(profiles))
 	}
 }
+
+// TestClearCommand_DryRunAndBackup tests clear previews files and backs up
+// the login before removing it.
+func TestClearCommand_DryRunAndBackup(t *testing.T) {
+	tmpDir, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+
+	authPath := filepath.Join(os.Getenv("CODEX_HOME"), "auth.json")
+	if err := os.WriteFile(authPath, []byte(`{"token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := vault.Backup(authfile.CodexAuthFiles(), "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	run := func(flags ...string) clearOutput {
+		t.Helper()
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("force", true, "")
+		cmd.Flags().Bool("dry-run", false, "")
+		cmd.Flags().Bool("no-backup", false, "")
+		cmd.Flags().Bool("json", true, "")
+		for _, f := range flags {
+			if err := cmd.Flags().Set(f, "true"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		if err := runClear(cmd, []string{"codex"}); err != nil {
+			t.Fatalf("runClear(%v) error = %v", flags, err)
+		}
+		var output clearOutput
+		if err := json.Unmarshal(out.Bytes(), &output); err != nil {
+			t.Fatalf("unmarshal: %v\n%s", err, out.String())
+		}
+		return output
+	}
+
+	preview := run("dry-run")
+	if len(preview.Files) != 1 || preview.Files[0].Path != authPath {
+		t.Fatalf("dry run files = %+v, want %s", preview.Files, authPath)
+	}
+	if got := preview.Files[0].Profiles; len(got) != 1 || got[0] != "work" {
+		t.Errorf("dry run vault profiles = %v, want [work]", got)
+	}
+	if _, err := os.Stat(authPath); err != nil {
+		t.Fatalf("dry run removed the auth file: %v", err)
+	}
+
+	// An unsaved login is reported as such.
+	if err := os.WriteFile(authPath, []byte(`{"token":"fresh"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if preview := run("dry-run"); len(preview.Files[0].Profiles) != 0 {
+		t.Errorf("unsaved file matched %v", preview.Files[0].Profiles)
+	}
+
+	cleared := run()
+	if !strings.HasPrefix(cleared.Backup, "_backup_") {
+		t.Fatalf("Backup = %q, want a _backup_ profile", cleared.Backup)
+	}
+	if _, err := os.Stat(authPath); !os.IsNotExist(err) {
+		t.Fatalf("auth file still present after clear")
+	}
+	saved, err := os.ReadFile(filepath.Join(tmpDir, "vault", "codex", cleared.Backup, "auth.json"))
+	if err != nil || string(saved) != `{"token":"fresh"}` {
+		t.Fatalf("backup auth.json = %q, %v; want the cleared login", saved, err)
+	}
+}
+
+func TestCollectVaultStats_CountsBackupsAndTracksGrowth(t *testing.T) {
+	tmpDir, cleanup := setupCooldownTestEnv(t)
+	defer cleanup()
+
+	write := func(rel string, size int) {
+		t.Helper()
+		path := filepath.Join(tmpDir, "vault", rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, bytes.Repeat([]byte("x"), size), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("codex/work/auth.json", 100)
+	write("codex/_backup_20260101_120000/auth.json", 300)
+
+	now := time.Now()
+	first, err := collectVaultStats(vault, 5, 30, now)
+	if err != nil {
+		t.Fatalf("collectVaultStats: %v", err)
+	}
+	if first.TotalBytes != 400 || first.Profiles != 1 || first.Backups != 1 {
+		t.Fatalf("stats = %d bytes, %d profiles, %d backups; want 400, 1, 1", first.TotalBytes, first.Profiles, first.Backups)
+	}
+	if len(first.Largest) == 0 || first.Largest[0].Path != "codex/_backup_20260101_120000/auth.json" {
+		t.Errorf("largest = %+v, want the backup first", first.Largest)
+	}
+	if p := first.Providers[0]; p.OldestBackup == nil || p.OldestBackup.Year() != 2026 {
+		t.Errorf("oldest backup = %v, want 2026-01-01", p.OldestBackup)
+	}
+	if first.Growth != nil {
+		t.Errorf("growth = %+v, want none before any sample", first.Growth)
+	}
+
+	write("claude/home/.claude.json", 50)
+	second, err := collectVaultStats(vault, 5, 30, now.Add(25*time.Hour))
+	if err != nil {
+		t.Fatalf("collectVaultStats: %v", err)
+	}
+	if second.Growth == nil || second.Growth.DeltaBytes != 50 || second.Growth.FromBytes != 400 {
+		t.Fatalf("growth = %+v, want +50 from 400", second.Growth)
+	}
+
+	var out bytes.Buffer
+	printVaultStats(&out, second, 30)
+	for _, want := range []string{"Total: 450 B in 2 profile(s), 1 auto-backup(s)", "Growth (last 30 days): +50 B", "claude +50 B", "caam gc --dry-run"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+)
+
+var verifyRestoreCmd = &cobra.Command{
+	Use:   "verify-restore <tool> <profile>",
+	Short: "Fire-drill a backup by restoring it into a scratch directory",
+	Long: `Restores a vault profile exactly as 'caam activate' would, but into a
+throwaway directory layout instead of the live auth paths, and checks the
+result:
+
+  restore     the vault files can be put in place
+  auth files  the tool's required auth files are present
+  detection   caam recognizes the restored files as this profile
+  validation  the provider's passive token check passes (no network calls)
+  expiry      the token hasn't expired without a refresh token
+
+Nothing outside the scratch directory is touched, so it is safe to run
+against the active profile.
+
+Examples:
+  caam verify-restore claude work
+  caam verify-restore codex personal --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runVerifyRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyRestoreCmd)
+	verifyRestoreCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// verifyRestoreCheck is the outcome of one verify-restore step.
+type verifyRestoreCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+type verifyRestoreOutput struct {
+	jsonStatus
+	Tool      string               `json:"tool"`
+	Profile   string               `json:"profile"`
+	Files     []string             `json:"files"`
+	Checks    []verifyRestoreCheck `json:"checks"`
+	ExpiresAt *time.Time           `json:"expires_at,omitempty"`
+}
+
+func runVerifyRestore(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	name := args[1]
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	output := verifyRestoreOutput{Tool: tool, Profile: name, Files: []string{}, Checks: []verifyRestoreCheck{}}
+	err := verifyRestore(&output)
+	if jsonOutput {
+		return writeJSONResult(cmd, &output, err)
+	}
+
+	out := cmd.OutOrStdout()
+	for _, c := range output.Checks {
+		mark := "[OK]"
+		if !c.OK {
+			mark = "[!]"
+		}
+		fmt.Fprintf(out, "  %-4s %-11s %s\n", mark, c.Name, c.Detail)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Restore verified: activating %s/%s would produce a working auth set.\n", tool, name)
+	return nil
+}
+
+// verifyRestore restores tool/profile into a scratch layout and records each
+// check in output. It returns an error if activation would not work.
+func verifyRestore(output *verifyRestoreOutput) error {
+	tool, name := output.Tool, output.Profile
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s", tool))
+	}
+	if !vaultHasProfile(tool, name) {
+		return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", tool, name))
+	}
+
+	scratch, err := os.MkdirTemp("", "caam-verify-restore-")
+	if err != nil {
+		return fmt.Errorf("create scratch dir: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	// Lay the scratch dir out like an isolated profile so the provider's
+	// passive validation reads the restored files.
+	prof := &profile.Profile{Name: name, Provider: tool, BasePath: scratch}
+	fileSet, ok := authfile.AuthFileSetAt(tool, authfile.AuthDirs{
+		Home:      prof.HomePath(),
+		XDGConfig: prof.XDGConfigPath(),
+		CodexHome: prof.CodexHomePath(),
+	})
+	if !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("verify-restore does not support %s (supported: claude, codex, gemini)", tool))
+	}
+
+	failed := false
+	check := func(name string, ok bool, format string, a ...any) {
+		output.Checks = append(output.Checks, verifyRestoreCheck{Name: name, OK: ok, Detail: fmt.Sprintf(format, a...)})
+		failed = failed || !ok
+	}
+
+	if err := vault.Restore(fileSet, name); err != nil {
+		check("restore", false, "%v", err)
+		return withExitCode(ExitNoHealthyProfile, fmt.Errorf("backup %s/%s would not restore: %w", tool, name, err))
+	}
+	authDir := ""
+	for _, spec := range fileSet.Files {
+		if _, err := os.Stat(spec.Path); err != nil {
+			continue
+		}
+		if authDir == "" || spec.Required {
+			authDir = filepath.Dir(spec.Path)
+		}
+		rel, _ := filepath.Rel(scratch, spec.Path)
+		output.Files = append(output.Files, filepath.ToSlash(rel))
+	}
+	check("restore", true, "%d file(s) restored", len(output.Files))
+
+	if authfile.HasAuthFiles(fileSet) {
+		check("auth files", true, "required auth files present")
+	} else {
+		check("auth files", false, "required auth files missing")
+	}
+
+	active, err := vault.ActiveProfile(fileSet)
+	switch {
+	case err != nil:
+		check("detection", false, "%v", err)
+	case active == name:
+		check("detection", true, "recognized as %s/%s", tool, name)
+	case active == "":
+		check("detection", false, "restored files match no vault profile")
+	default:
+		check("detection", false, "restored files are recognized as %s/%s instead", tool, active)
+	}
+
+	if registry != nil {
+		if prov, ok := registry.Get(tool); ok {
+			result, err := prov.ValidateToken(context.Background(), prof, true)
+			switch {
+			case err != nil:
+				check("validation", false, "%v", err)
+			case !result.Valid:
+				check("validation", false, "%s", result.Error)
+			default:
+				check("validation", true, "passive token check passed")
+			}
+		}
+	}
+
+	info, err := expiryInfoAt(tool, authDir)
+	switch {
+	case errors.Is(err, health.ErrNoExpiry):
+		check("expiry", true, "no expiry recorded")
+	case err != nil:
+		check("expiry", false, "%v", err)
+	case info.ExpiresAt.IsZero():
+		check("expiry", true, "no expiry recorded (refresh token present)")
+	default:
+		expiresAt := info.ExpiresAt
+		output.ExpiresAt = &expiresAt
+		switch {
+		case time.Now().Before(expiresAt):
+			check("expiry", true, "access token expires in %s", formatExpiry(expiresAt))
+		case info.HasRefreshToken:
+			check("expiry", true, "access token expired; the tool can renew it with the refresh token")
+		default:
+			check("expiry", false, "access token expired %s and there is no refresh token", expiresAt.Local().Format(time.RFC3339))
+		}
+	}
+
+	if failed {
+		return withExitCode(ExitNoHealthyProfile, fmt.Errorf("backup %s/%s would not produce a working auth set", tool, name))
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/claude"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/codex"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/gemini"
+)
+
+func setupVerifyRestoreTest(t *testing.T) {
+	t.Helper()
+	setupAccountsTest(t)
+	t.Setenv("HOME", t.TempDir())
+	originalRegistry := registry
+	t.Cleanup(func() { registry = originalRegistry })
+	registry = provider.NewRegistry()
+	registry.Register(claude.New())
+	registry.Register(codex.New())
+	registry.Register(gemini.New())
+}
+
+func checksByName(output verifyRestoreOutput) map[string]verifyRestoreCheck {
+	checks := make(map[string]verifyRestoreCheck)
+	for _, c := range output.Checks {
+		checks[c.Name] = c
+	}
+	return checks
+}
+
+func TestVerifyRestore_HealthyProfiles(t *testing.T) {
+	setupVerifyRestoreTest(t)
+
+	for _, tool := range []string{"claude", "codex", "gemini"} {
+		t.Run(tool, func(t *testing.T) {
+			cmd := newAddTokenTestCmd()
+			require.NoError(t, cmd.Flags().Set("access-token", "access-"+tool))
+			require.NoError(t, cmd.Flags().Set("refresh-token", "refresh-"+tool))
+			require.NoError(t, cmd.Flags().Set("expires", "8h"))
+			require.NoError(t, runAddToken(cmd, []string{tool, "drill"}))
+
+			output := verifyRestoreOutput{Tool: tool, Profile: "drill"}
+			require.NoError(t, verifyRestore(&output))
+			assert.NotEmpty(t, output.Files)
+			require.NotNil(t, output.ExpiresAt)
+			assert.WithinDuration(t, time.Now().Add(8*time.Hour), *output.ExpiresAt, time.Minute)
+			for _, name := range []string{"restore", "auth files", "detection", "validation", "expiry"} {
+				c, ok := checksByName(output)[name]
+				if assert.True(t, ok, "missing %s check", name) {
+					assert.True(t, c.OK, "%s: %s", name, c.Detail)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyRestore_ReportsBrokenBackup(t *testing.T) {
+	setupVerifyRestoreTest(t)
+
+	dir := vault.ProfilePath("claude", "broken")
+	require.NoError(t, os.MkdirAll(dir, 0700))
+	creds := `{"claudeAiOauth": {"accessToken": "tok", "expiresAt": 1000000000000}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".credentials.json"), []byte(creds), 0600))
+
+	output := verifyRestoreOutput{Tool: "claude", Profile: "broken"}
+	err := verifyRestore(&output)
+	require.Error(t, err)
+	assert.Equal(t, ExitNoHealthyProfile, ExitCode(err))
+	assert.False(t, checksByName(output)["expiry"].OK)
+	assert.True(t, checksByName(output)["detection"].OK)
+}
+
+func TestVerifyRestore_MissingProfile(t *testing.T) {
+	setupVerifyRestoreTest(t)
+
+	output := verifyRestoreOutput{Tool: "claude", Profile: "nope"}
+	err := verifyRestore(&output)
+	require.Error(t, err)
+	assert.Equal(t, ExitAuthMissing, ExitCode(err))
+}
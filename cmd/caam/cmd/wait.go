@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/durations"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait <tool>",
+	Short: "Block until a profile is out of cooldown",
+	Long: `Waits until a profile can be used again, printing a live countdown.
+
+With --any (the default), waits until any profile is out of cooldown and not
+in critical health. With --profile, waits for that specific profile's cooldown
+to expire. Exits 0 once a profile is ready, so it composes with other commands;
+on timeout it exits 4 if profiles are still cooling down, 3 otherwise.
+
+Examples:
+  caam wait claude --any && caam activate claude --auto && run-my-job
+  caam wait codex --profile work
+  caam wait claude --max 30m
+  caam wait claude --max "tomorrow 9am"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWait,
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+	waitCmd.Flags().Bool("any", false, "wait for any profile to become available (default)")
+	waitCmd.Flags().String("profile", "", "wait for a specific profile")
+	waitCmd.Flags().Var(durations.NewValue(2*time.Hour), "max", "give up after this long, e.g. 30m, 1d or friday 9am (0 = wait forever)")
+	waitCmd.Flags().Var(durations.NewValue(10*time.Second), "interval", "how often to re-check cooldowns")
+	waitCmd.Flags().Bool("quiet", false, "don't print the countdown")
+	registerDurationCompletion(waitCmd, "max", "interval")
+}
+
+func runWait(cmd *cobra.Command, args []string) error {
+	tool := strings.ToLower(args[0])
+	anyProfile, _ := cmd.Flags().GetBool("any")
+	profileName, _ := cmd.Flags().GetString("profile")
+	maxWait, _ := cmd.Flags().GetDuration("max")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	quiet, _ := cmd.Flags().GetBool("quiet")
+
+	if _, ok := tools[tool]; !ok {
+		return withExitCode(ExitUsage, fmt.Errorf("unknown tool: %s (supported: %s)", tool, supportedTools()))
+	}
+	if anyProfile && profileName != "" {
+		return withExitCode(ExitUsage, fmt.Errorf("--any cannot be used with --profile"))
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	if vault == nil {
+		vault = authfile.NewVault(authfile.DefaultVaultPath())
+	}
+
+	if profileName != "" && !vaultHasProfile(tool, profileName) {
+		return withExitCode(ExitAuthMissing, fmt.Errorf("profile %s/%s not found in vault", tool, profileName))
+	}
+
+	db, err := caamdb.Open()
+	if err != nil {
+		return fmt.Errorf("open database: %w", err)
+	}
+	defer db.Close()
+
+	out := cmd.OutOrStdout()
+	errOut := cmd.ErrOrStderr()
+	start := time.Now()
+	var deadline time.Time
+	if maxWait > 0 {
+		deadline = start.Add(maxWait)
+	}
+
+	var (
+		lastCheck time.Time
+		nextName  string
+		nextAt    time.Time
+	)
+	for {
+		now := time.Now()
+		due := lastCheck.IsZero() || now.Sub(lastCheck) >= interval || (!nextAt.IsZero() && !now.Before(nextAt))
+		if due {
+			var ready string
+			var err error
+			ready, nextName, nextAt, err = waitCheck(db, tool, profileName, now)
+			if err != nil {
+				return err
+			}
+			lastCheck = now
+			if ready != "" {
+				if !quiet {
+					fmt.Fprint(errOut, "\r\033[K")
+				}
+				fmt.Fprintf(out, "%s/%s is ready\n", tool, ready)
+				return nil
+			}
+		}
+
+		if !deadline.IsZero() && !now.Before(deadline) {
+			if !quiet {
+				fmt.Fprintln(errOut)
+			}
+			code := ExitNoHealthyProfile
+			if !nextAt.IsZero() {
+				code = ExitAllInCooldown
+			}
+			return withExitCode(code, fmt.Errorf("timed out after %s waiting for a %s profile", formatDurationShort(maxWait), tool))
+		}
+
+		if !quiet {
+			if nextAt.IsZero() {
+				fmt.Fprintf(errOut, "\r\033[KWaiting for a healthy %s profile (elapsed %s)", tool, formatCountdown(now.Sub(start)))
+			} else {
+				fmt.Fprintf(errOut, "\r\033[KWaiting for %s/%s: ready in %s", tool, nextName, formatCountdown(nextAt.Sub(now)))
+			}
+		}
+
+		// Wake for the next re-check, cooldown expiry, or deadline, and at
+		// least once a second so the countdown stays live.
+		sleep := lastCheck.Add(interval).Sub(now)
+		if !quiet && sleep > time.Second {
+			sleep = time.Second
+		}
+		if !nextAt.IsZero() {
+			if untilNext := nextAt.Sub(now); untilNext < sleep {
+				sleep = untilNext
+			}
+		}
+		if !deadline.IsZero() {
+			if untilDeadline := deadline.Sub(now); untilDeadline < sleep {
+				sleep = untilDeadline
+			}
+		}
+		if sleep < 100*time.Millisecond {
+			sleep = 100 * time.Millisecond
+		}
+		time.Sleep(sleep)
+	}
+}
+
+// waitCheck reports the first ready profile, or if none is ready, the profile
+// whose cooldown ends soonest and when. With profileName set only that
+// profile is considered and health is ignored.
+func waitCheck(db *caamdb.DB, tool, profileName string, now time.Time) (ready, nextName string, nextAt time.Time, err error) {
+	candidates := []string{profileName}
+	if profileName == "" {
+		names, err := vault.List(tool)
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("list %s profiles: %w", tool, err)
+		}
+		candidates = candidates[:0]
+		for _, name := range names {
+			if !authfile.IsSystemProfile(name) {
+				candidates = append(candidates, name)
+			}
+		}
+		if len(candidates) == 0 {
+			return "", "", time.Time{}, withExitCode(ExitAuthMissing, fmt.Errorf("no %s profiles in vault", tool))
+		}
+	}
+
+	for _, name := range candidates {
+		ev, err := db.ActiveCooldown(tool, name, now)
+		if err != nil {
+			return "", "", time.Time{}, fmt.Errorf("check cooldown for %s/%s: %w", tool, name, err)
+		}
+		if ev != nil {
+			if nextAt.IsZero() || ev.CooldownUntil.Before(nextAt) {
+				nextName, nextAt = name, ev.CooldownUntil
+			}
+			continue
+		}
+		if profileName == "" && health.CalculateStatus(buildProfileHealth(tool, name)) == health.StatusCritical {
+			continue
+		}
+		return name, "", time.Time{}, nil
+	}
+	return "", nextName, nextAt, nil
+}
+
+// formatCountdown formats a remaining duration as a clock-style countdown.
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
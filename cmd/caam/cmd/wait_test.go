@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+)
+
+func setupWaitTest(t *testing.T, profiles ...string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam_home"))
+
+	originalVault := vault
+	t.Cleanup(func() { vault = originalVault })
+	vault = authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	for _, name := range profiles {
+		require.NoError(t, os.MkdirAll(vault.ProfilePath("claude", name), 0700))
+	}
+}
+
+func newWaitTestCmd(t *testing.T, flags map[string]string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("any", false, "")
+	cmd.Flags().String("profile", "", "")
+	cmd.Flags().Duration("max", 2*time.Hour, "")
+	cmd.Flags().Duration("interval", 10*time.Second, "")
+	cmd.Flags().Bool("quiet", true, "")
+	for k, v := range flags {
+		require.NoError(t, cmd.Flags().Set(k, v))
+	}
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	return cmd, &out
+}
+
+func setWaitCooldown(t *testing.T, profile string, until time.Duration) {
+	t.Helper()
+	db, err := caamdb.Open()
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.SetCooldown("claude", profile, time.Now().Add(-time.Minute), time.Minute+until, "")
+	require.NoError(t, err)
+}
+
+func TestWait_AnyReturnsAvailableProfile(t *testing.T) {
+	setupWaitTest(t, "busy", "free")
+	setWaitCooldown(t, "busy", time.Hour)
+
+	cmd, out := newWaitTestCmd(t, map[string]string{"any": "true"})
+	require.NoError(t, runWait(cmd, []string{"claude"}))
+	assert.Equal(t, "claude/free is ready\n", out.String())
+}
+
+func TestWait_ProfileWaitsForCooldownToExpire(t *testing.T) {
+	setupWaitTest(t, "work")
+	setWaitCooldown(t, "work", 1500*time.Millisecond)
+
+	cmd, out := newWaitTestCmd(t, map[string]string{"profile": "work", "interval": "200ms", "max": "10s"})
+	start := time.Now()
+	require.NoError(t, runWait(cmd, []string{"claude"}))
+	assert.Greater(t, time.Since(start), 200*time.Millisecond, "should have waited for the cooldown")
+	assert.Contains(t, out.String(), "claude/work is ready")
+}
+
+func TestWait_TimesOut(t *testing.T) {
+	setupWaitTest(t, "work")
+	setWaitCooldown(t, "work", time.Hour)
+
+	cmd, _ := newWaitTestCmd(t, map[string]string{"max": "300ms", "interval": "100ms"})
+	err := runWait(cmd, []string{"claude"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.Equal(t, ExitAllInCooldown, ExitCode(err))
+}
+
+func TestWait_RejectsUnknownProfile(t *testing.T) {
+	setupWaitTest(t, "work")
+
+	cmd, _ := newWaitTestCmd(t, map[string]string{"profile": "missing"})
+	err := runWait(cmd, []string{"claude"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.Equal(t, ExitAuthMissing, ExitCode(err))
+}
+
+func TestFormatCountdown(t *testing.T) {
+	assert.Equal(t, "00:00", formatCountdown(-time.Second))
+	assert.Equal(t, "02:05", formatCountdown(2*time.Minute+5*time.Second))
+	assert.Equal(t, "1:00:30", formatCountdown(time.Hour+30*time.Second))
+}
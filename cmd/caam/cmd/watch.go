@@ -5,9 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/discovery"
@@ -52,6 +50,7 @@ func init() {
 	watchCmd.Flags().BoolVar(&watchOnce, "once", false, "Scan once and exit (no daemon)")
 	watchCmd.Flags().StringSliceVar(&watchProviders, "providers", nil, "Providers to watch (default: all)")
 	watchCmd.Flags().BoolVar(&watchVerbose, "verbose", false, "Verbose output")
+	addPIDFileFlag(watchCmd)
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
@@ -82,7 +81,12 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		return runWatchOnce(providers, logger)
 	}
 
-	return runWatchDaemon(cmd.Context(), providers, logger)
+	ctx, finish, err := startLongRunning(cmd)
+	if err != nil {
+		return err
+	}
+	defer finish()
+	return runWatchDaemon(ctx, providers, logger)
 }
 
 func runWatchOnce(providers []string, logger *slog.Logger) error {
@@ -150,10 +154,6 @@ func runWatchDaemon(ctx context.Context, providers []string, logger *slog.Logger
 		return fmt.Errorf("create watcher: %w", err)
 	}
 
-	// Handle signals
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
 	// Start watcher
 	if err := watcher.Start(ctx); err != nil {
 		return fmt.Errorf("start watcher: %w", err)
@@ -161,12 +161,9 @@ func runWatchDaemon(ctx context.Context, providers []string, logger *slog.Logger
 
 	fmt.Println("Watching for auth file changes...")
 
-	// Wait for signal or context cancellation
-	select {
-	case <-ctx.Done():
-	case sig := <-sigCh:
-		fmt.Printf("\nReceived %s, stopping...\n", sig)
-	}
+	// Wait for a shutdown signal
+	<-ctx.Done()
+	fmt.Println("\nStopping...")
 
 	if err := watcher.Stop(); err != nil {
 		logger.Warn("error stopping watcher", "error", err)
@@ -3,6 +3,10 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -24,6 +28,7 @@ Examples:
   caam workspace              # List all workspaces
   caam workspace work         # Switch to the 'work' workspace
   caam workspace create work --claude=work-claude --codex=work-codex
+  eval "$(caam workspace enter work)"
   caam workspace delete old-workspace
   caam workspace list --json`,
 	Args: cobra.MaximumNArgs(1),
@@ -35,9 +40,15 @@ var workspaceCreateCmd = &cobra.Command{
 	Short: "Create a new workspace",
 	Long: `Create a workspace with profile mappings for each tool.
 
+A workspace can also carry a project directory, environment variables and
+default arguments per tool, which 'caam workspace enter' applies. Creating
+an existing workspace replaces all of its settings.
+
 Examples:
   caam workspace create work --claude=work-claude --codex=work-codex --gemini=work-gemini
-  caam workspace create home --claude=personal --codex=personal`,
+  caam workspace create home --claude=personal --codex=personal
+  caam workspace create acme --claude=acme --codex=acme-codex \
+    --dir ~/src/acme --env AWS_PROFILE=acme --args "codex=--model o3" --tmux`,
 	Args: cobra.ExactArgs(1),
 	RunE: runWorkspaceCreate,
 }
@@ -55,6 +66,27 @@ Examples:
 	RunE: runWorkspaceDelete,
 }
 
+var workspaceEnterCmd = &cobra.Command{
+	Use:   "enter <name>",
+	Short: "Activate a workspace and enter its directory",
+	Long: `Activate the workspace's profiles, point its directory's project
+associations at them (so --auto, run and robot next stay on them there),
+and print the shell commands that enter it: cd to the directory, export its
+environment and alias each tool to its default arguments. Status messages
+go to stderr, so the output can be evaluated directly.
+
+With --tmux (or a workspace created with --tmux), a tmux session named
+caam-<name> is started instead, with one pane per tool running it with its
+default arguments; an existing session is reattached.
+
+Examples:
+  eval "$(caam workspace enter work)"
+  caam workspace enter acme --tmux
+  caam workspace enter acme --tmux=false   # Skip the template's tmux session`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWorkspaceEnter,
+}
+
 var workspaceListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all workspaces",
@@ -70,12 +102,20 @@ func init() {
 	rootCmd.AddCommand(workspaceCmd)
 	workspaceCmd.AddCommand(workspaceCreateCmd)
 	workspaceCmd.AddCommand(workspaceDeleteCmd)
+	workspaceCmd.AddCommand(workspaceEnterCmd)
 	workspaceCmd.AddCommand(workspaceListCmd)
 
 	// Create command flags
 	workspaceCreateCmd.Flags().String("claude", "", "Claude profile for this workspace")
 	workspaceCreateCmd.Flags().String("codex", "", "Codex profile for this workspace")
 	workspaceCreateCmd.Flags().String("gemini", "", "Gemini profile for this workspace")
+	workspaceCreateCmd.Flags().String("dir", "", "project directory to enter")
+	workspaceCreateCmd.Flags().StringArray("env", nil, "environment variable to export on enter (KEY=VALUE, repeatable)")
+	workspaceCreateCmd.Flags().StringArray("args", nil, "default arguments for a tool (tool=\"args\", repeatable)")
+	workspaceCreateCmd.Flags().Bool("tmux", false, "start a tmux session with a pane per tool on enter")
+
+	// Enter command flags
+	workspaceEnterCmd.Flags().Bool("tmux", false, "start a tmux session with a pane per tool (default from the workspace)")
 
 	// List command flags
 	workspaceListCmd.Flags().Bool("json", false, "Output in JSON format")
@@ -96,7 +136,7 @@ func runWorkspace(cmd *cobra.Command, args []string) error {
 
 	// Switch to workspace
 	workspaceName := args[0]
-	return switchWorkspace(cfg, workspaceName)
+	return switchWorkspace(cmd.OutOrStdout(), cfg, workspaceName)
 }
 
 func runWorkspaceCreate(cmd *cobra.Command, args []string) error {
@@ -135,6 +175,11 @@ func runWorkspaceCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("at least one profile mapping is required (--claude, --codex, or --gemini)")
 	}
 
+	tpl, err := workspaceTemplateFromFlags(cmd, profiles)
+	if err != nil {
+		return withExitCode(ExitUsage, err)
+	}
+
 	// Validate that profiles exist
 	if vault == nil {
 		vault = authfile.NewVault(authfile.DefaultVaultPath())
@@ -165,6 +210,7 @@ func runWorkspaceCreate(cmd *cobra.Command, args []string) error {
 
 	// Create workspace
 	cfg.CreateWorkspace(workspaceName, profiles)
+	cfg.SetWorkspaceTemplate(workspaceName, tpl)
 
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("save config: %w", err)
@@ -180,10 +226,234 @@ func runWorkspaceCreate(cmd *cobra.Command, args []string) error {
 	for _, tool := range sortedTools {
 		fmt.Printf("  %s: %s\n", tool, profiles[tool])
 	}
+	if tpl.Dir != "" {
+		fmt.Printf("  dir: %s\n", tpl.Dir)
+	}
 
 	return nil
 }
 
+// workspaceTemplateFromFlags reads create's --dir, --env, --args and --tmux.
+func workspaceTemplateFromFlags(cmd *cobra.Command, profiles map[string]string) (config.WorkspaceTemplate, error) {
+	var tpl config.WorkspaceTemplate
+
+	if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+		if strings.HasPrefix(dir, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(home, dir[2:])
+			}
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return tpl, fmt.Errorf("resolve --dir: %w", err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return tpl, fmt.Errorf("--dir: %w", err)
+		}
+		if !info.IsDir() {
+			return tpl, fmt.Errorf("--dir %s is not a directory", abs)
+		}
+		tpl.Dir = abs
+	}
+
+	envList, _ := cmd.Flags().GetStringArray("env")
+	for _, kv := range envList {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !validEnvName(key) {
+			return tpl, fmt.Errorf("invalid --env %q (want KEY=VALUE)", kv)
+		}
+		if tpl.Env == nil {
+			tpl.Env = make(map[string]string)
+		}
+		tpl.Env[key] = value
+	}
+
+	argsList, _ := cmd.Flags().GetStringArray("args")
+	for _, ta := range argsList {
+		tool, args, ok := strings.Cut(ta, "=")
+		tool = strings.ToLower(strings.TrimSpace(tool))
+		if !ok || tool == "" {
+			return tpl, fmt.Errorf("invalid --args %q (want tool=\"args\")", ta)
+		}
+		if _, mapped := profiles[tool]; !mapped {
+			return tpl, fmt.Errorf("--args for %s, which has no profile in this workspace", tool)
+		}
+		if tpl.Args == nil {
+			tpl.Args = make(map[string][]string)
+		}
+		tpl.Args[tool] = strings.Fields(args)
+	}
+
+	tpl.Tmux, _ = cmd.Flags().GetBool("tmux")
+	return tpl, nil
+}
+
+// validEnvName reports whether name can be exported by a POSIX shell.
+func validEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func runWorkspaceEnter(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	profiles := cfg.GetWorkspace(name)
+	if profiles == nil {
+		return fmt.Errorf("workspace '%s' not found", name)
+	}
+	tpl := cfg.GetWorkspaceTemplate(name)
+	useTmux := tpl.Tmux
+	if cmd.Flags().Changed("tmux") {
+		useTmux, _ = cmd.Flags().GetBool("tmux")
+	}
+
+	// Everything but the shell commands goes to stderr so that
+	// eval "$(caam workspace enter x)" only sees the commands.
+	errOut := cmd.ErrOrStderr()
+	if err := switchWorkspace(errOut, cfg, name); err != nil {
+		return err
+	}
+
+	if tpl.Dir != "" {
+		if projectStore == nil {
+			fmt.Fprintln(errOut, "Warning: project store not initialized; directory not locked to the workspace")
+		} else {
+			for _, tool := range sortedKeys(profiles) {
+				if err := projectStore.SetAssociation(tpl.Dir, tool, profiles[tool]); err != nil {
+					return fmt.Errorf("associate %s with %s/%s: %w", tpl.Dir, tool, profiles[tool], err)
+				}
+			}
+			fmt.Fprintf(errOut, "Locked %s to the workspace's profiles\n", tpl.Dir)
+		}
+	}
+
+	if useTmux {
+		if _, err := exec.LookPath("tmux"); err != nil {
+			return fmt.Errorf("tmux not found in PATH: %w", err)
+		}
+		return startWorkspaceTmux(name, tpl, profiles, errOut)
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), workspaceShellCommands(name, tpl, profiles))
+	return nil
+}
+
+// workspaceShellCommands returns the bash/zsh commands that enter a
+// workspace: cd, exports, and an alias per tool with default arguments.
+func workspaceShellCommands(name string, tpl config.WorkspaceTemplate, profiles map[string]string) string {
+	var sb strings.Builder
+	if tpl.Dir != "" {
+		fmt.Fprintf(&sb, "cd %s\n", shellQuote(tpl.Dir))
+	}
+	for _, key := range sortedKeys(tpl.Env) {
+		fmt.Fprintf(&sb, "export %s=%s\n", key, shellQuote(tpl.Env[key]))
+	}
+	fmt.Fprintf(&sb, "export CAAM_WORKSPACE=%s\n", shellQuote(name))
+	for _, tool := range sortedKeys(profiles) {
+		if args := tpl.Args[tool]; len(args) > 0 {
+			fmt.Fprintf(&sb, "alias %s=%s\n", tool, shellQuote(workspaceToolCommand(tool, args)))
+		}
+	}
+	return sb.String()
+}
+
+// workspaceToolCommand is the shell command line running tool with args.
+func workspaceToolCommand(tool string, args []string) string {
+	parts := []string{shellQuote(tool)}
+	for _, a := range args {
+		parts = append(parts, shellQuote(a))
+	}
+	return strings.Join(parts, " ")
+}
+
+// workspaceTmuxSession is the tmux session name for a workspace; tmux
+// reserves '.' and ':' in target names.
+func workspaceTmuxSession(name string) string {
+	return "caam-" + strings.NewReplacer(".", "_", ":", "_").Replace(name)
+}
+
+// workspaceTmuxCommands returns the tmux invocations that lay out a
+// workspace session: one pane per tool, tiled. Each pane drops to a shell
+// when its tool exits so the pane stays usable.
+func workspaceTmuxCommands(name string, tpl config.WorkspaceTemplate, profiles map[string]string) [][]string {
+	session := workspaceTmuxSession(name)
+	dirArgs := func() []string {
+		if tpl.Dir == "" {
+			return nil
+		}
+		return []string{"-c", tpl.Dir}
+	}
+
+	var cmds [][]string
+	for i, tool := range sortedKeys(profiles) {
+		paneCmd := workspaceToolCommand(tool, tpl.Args[tool]) + `; exec "${SHELL:-sh}"`
+		if i == 0 {
+			args := append([]string{"new-session", "-d", "-s", session, "-n", name}, dirArgs()...)
+			for _, key := range sortedKeys(tpl.Env) {
+				args = append(args, "-e", key+"="+tpl.Env[key])
+			}
+			args = append(args, "-e", "CAAM_WORKSPACE="+name, paneCmd)
+			cmds = append(cmds, args)
+			continue
+		}
+		args := append([]string{"split-window", "-t", session}, dirArgs()...)
+		cmds = append(cmds, append(args, paneCmd))
+	}
+	if len(cmds) > 1 {
+		cmds = append(cmds, []string{"select-layout", "-t", session, "tiled"})
+	}
+	return cmds
+}
+
+// startWorkspaceTmux creates the workspace's tmux session unless it is
+// already running, then attaches to it (or switches to it inside tmux).
+func startWorkspaceTmux(name string, tpl config.WorkspaceTemplate, profiles map[string]string, errOut io.Writer) error {
+	session := workspaceTmuxSession(name)
+	if exec.Command("tmux", "has-session", "-t", "="+session).Run() == nil {
+		fmt.Fprintf(errOut, "Reattaching to tmux session %s\n", session)
+	} else {
+		for _, args := range workspaceTmuxCommands(name, tpl, profiles) {
+			if out, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+				return fmt.Errorf("tmux %s: %w (%s)", args[0], err, strings.TrimSpace(string(out)))
+			}
+		}
+		fmt.Fprintf(errOut, "Started tmux session %s\n", session)
+	}
+
+	verb := "attach-session"
+	if os.Getenv("TMUX") != "" {
+		verb = "switch-client"
+	}
+	attach := exec.Command("tmux", verb, "-t", session)
+	attach.Stdin, attach.Stdout, attach.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return attach.Run()
+}
+
+// sortedKeys returns m's keys in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func runWorkspaceDelete(cmd *cobra.Command, args []string) error {
 	workspaceName := args[0]
 
@@ -219,18 +489,23 @@ func runWorkspaceList(cmd *cobra.Command, args []string) error {
 
 	if jsonOutput {
 		type workspaceInfo struct {
-			Name     string            `json:"name"`
-			Current  bool              `json:"current"`
-			Profiles map[string]string `json:"profiles"`
+			Name     string                    `json:"name"`
+			Current  bool                      `json:"current"`
+			Profiles map[string]string         `json:"profiles"`
+			Template *config.WorkspaceTemplate `json:"template,omitempty"`
 		}
 		// Initialize as empty slice (not nil) to output [] instead of null
 		output := make([]workspaceInfo, 0, len(workspaces))
 		for _, name := range workspaces {
-			output = append(output, workspaceInfo{
+			info := workspaceInfo{
 				Name:     name,
 				Current:  name == current,
 				Profiles: cfg.GetWorkspace(name),
-			})
+			}
+			if tpl, ok := cfg.WorkspaceTemplates[name]; ok {
+				info.Template = &tpl
+			}
+			output = append(output, info)
 		}
 		data, err := json.MarshalIndent(output, "", "  ")
 		if err != nil {
@@ -265,6 +540,9 @@ func runWorkspaceList(cmd *cobra.Command, args []string) error {
 		for _, tool := range sortedTools {
 			fmt.Printf("    %s: %s\n", tool, profiles[tool])
 		}
+		if dir := cfg.GetWorkspaceTemplate(name).Dir; dir != "" {
+			fmt.Printf("    dir: %s\n", dir)
+		}
 	}
 
 	if current != "" {
@@ -274,7 +552,7 @@ func runWorkspaceList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func switchWorkspace(cfg *config.Config, workspaceName string) error {
+func switchWorkspace(out io.Writer, cfg *config.Config, workspaceName string) error {
 	profiles := cfg.GetWorkspace(workspaceName)
 	if profiles == nil {
 		return fmt.Errorf("workspace '%s' not found", workspaceName)
@@ -285,7 +563,12 @@ func switchWorkspace(cfg *config.Config, workspaceName string) error {
 		vault = authfile.NewVault(authfile.DefaultVaultPath())
 	}
 
-	fmt.Printf("Switching to workspace '%s'\n", workspaceName)
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		spmCfg = config.DefaultSPMConfig()
+	}
+
+	fmt.Fprintf(out, "Switching to workspace '%s'\n", workspaceName)
 
 	// Sort tools for consistent activation order
 	sortedTools := make([]string, 0, len(profiles))
@@ -300,22 +583,16 @@ func switchWorkspace(cfg *config.Config, workspaceName string) error {
 		profile := profiles[tool]
 		getFileSet, ok := tools[tool]
 		if !ok {
-			fmt.Printf("  Warning: unknown tool '%s', skipping\n", tool)
+			fmt.Fprintf(out, "  Warning: unknown tool '%s', skipping\n", tool)
 			continue
 		}
 
 		fileSet := getFileSet()
 
-		// Backup original on first use
-		if did, err := vault.BackupOriginal(fileSet); err != nil {
-			fmt.Printf("  Warning: could not backup original %s auth: %v\n", tool, err)
-		} else if did {
-			fmt.Printf("  Backed up original %s auth\n", tool)
-		}
-
-		// Restore profile
-		if err := vault.Restore(fileSet, profile); err != nil {
-			fmt.Printf("  Error activating %s/%s: %v\n", tool, profile, err)
+		safety, err := vault.Activate(fileSet, profile, activateOptions(spmCfg))
+		fprintActivationSafety(out, tool, safety)
+		if err != nil {
+			fmt.Fprintf(out, "  Error activating %s/%s: %v\n", tool, profile, err)
 			continue
 		}
 
@@ -325,13 +602,13 @@ func switchWorkspace(cfg *config.Config, workspaceName string) error {
 	// Update current workspace in config
 	cfg.SetCurrentWorkspace(workspaceName)
 	if err := cfg.Save(); err != nil {
-		fmt.Printf("Warning: could not save current workspace: %v\n", err)
+		fmt.Fprintf(out, "Warning: could not save current workspace: %v\n", err)
 	}
 
-	fmt.Println()
-	fmt.Printf("Switched to workspace '%s':\n", workspaceName)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Switched to workspace '%s':\n", workspaceName)
 	for _, a := range activated {
-		fmt.Printf("  %s\n", a)
+		fmt.Fprintf(out, "  %s\n", a)
 	}
 
 	return nil
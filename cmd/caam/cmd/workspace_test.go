@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 )
@@ -214,7 +219,7 @@ func TestSwitchWorkspace(t *testing.T) {
 	defer func() { vault = oldVault }()
 
 	// Test switchWorkspace
-	err := switchWorkspace(cfg, "work")
+	err := switchWorkspace(io.Discard, cfg, "work")
 	if err != nil {
 		t.Errorf("switchWorkspace failed: %v", err)
 	}
@@ -228,7 +233,7 @@ func TestSwitchWorkspace(t *testing.T) {
 func TestSwitchWorkspaceNotFound(t *testing.T) {
 	cfg := config.DefaultConfig()
 
-	err := switchWorkspace(cfg, "nonexistent")
+	err := switchWorkspace(io.Discard, cfg, "nonexistent")
 	if err == nil {
 		t.Error("Expected error for non-existent workspace")
 	}
@@ -285,3 +290,115 @@ func TestWorkspaceEmptyConfig(t *testing.T) {
 		t.Error("Expected DeleteWorkspace to return false on nil map")
 	}
 }
+
+func TestWorkspaceTemplateFromFlags(t *testing.T) {
+	dir := t.TempDir()
+	cmd := &cobra.Command{}
+	cmd.Flags().AddFlagSet(workspaceCreateCmd.Flags())
+	defer func() {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				_ = sv.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	}()
+	for _, kv := range [][2]string{
+		{"dir", dir},
+		{"env", "AWS_PROFILE=acme"},
+		{"env", "EMPTY="},
+		{"args", "codex=--model o3"},
+		{"tmux", "true"},
+	} {
+		if err := cmd.Flags().Set(kv[0], kv[1]); err != nil {
+			t.Fatalf("set --%s: %v", kv[0], err)
+		}
+	}
+
+	tpl, err := workspaceTemplateFromFlags(cmd, map[string]string{"codex": "acme-codex"})
+	if err != nil {
+		t.Fatalf("workspaceTemplateFromFlags: %v", err)
+	}
+	if tpl.Dir != dir || !tpl.Tmux {
+		t.Errorf("template = %+v", tpl)
+	}
+	if tpl.Env["AWS_PROFILE"] != "acme" || tpl.Env["EMPTY"] != "" || len(tpl.Env) != 2 {
+		t.Errorf("Env = %v", tpl.Env)
+	}
+	if got := strings.Join(tpl.Args["codex"], " "); got != "--model o3" {
+		t.Errorf("Args[codex] = %q", got)
+	}
+
+	if _, err := workspaceTemplateFromFlags(cmd, map[string]string{"claude": "x"}); err == nil {
+		t.Error("--args for a tool without a profile should fail")
+	}
+	_ = cmd.Flags().Set("env", "1BAD=x")
+	if _, err := workspaceTemplateFromFlags(cmd, map[string]string{"codex": "acme-codex"}); err == nil {
+		t.Error("invalid env name should fail")
+	}
+}
+
+func TestWorkspaceShellCommands(t *testing.T) {
+	tpl := config.WorkspaceTemplate{
+		Dir:  "/src/my project",
+		Env:  map[string]string{"B": "two words", "A": "1"},
+		Args: map[string][]string{"codex": {"--model", "o3"}},
+	}
+	got := workspaceShellCommands("acme", tpl, map[string]string{"codex": "c", "claude": "a"})
+	want := "cd '/src/my project'\n" +
+		"export A=1\n" +
+		"export B='two words'\n" +
+		"export CAAM_WORKSPACE=acme\n" +
+		"alias codex='codex --model o3'\n"
+	if got != want {
+		t.Errorf("workspaceShellCommands() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWorkspaceTmuxCommands(t *testing.T) {
+	tpl := config.WorkspaceTemplate{
+		Dir:  "/src/acme",
+		Env:  map[string]string{"AWS_PROFILE": "acme"},
+		Args: map[string][]string{"codex": {"--model", "o3"}},
+	}
+	cmds := workspaceTmuxCommands("acme.v2", tpl, map[string]string{"codex": "c", "claude": "a"})
+	if len(cmds) != 3 {
+		t.Fatalf("got %d commands, want 3: %q", len(cmds), cmds)
+	}
+
+	first := strings.Join(cmds[0], " ")
+	for _, part := range []string{"new-session -d -s caam-acme_v2", "-c /src/acme", "-e AWS_PROFILE=acme", "-e CAAM_WORKSPACE=acme.v2"} {
+		if !strings.Contains(first, part) {
+			t.Errorf("new-session %q missing %q", first, part)
+		}
+	}
+	if last := cmds[0][len(cmds[0])-1]; !strings.HasPrefix(last, "claude;") {
+		t.Errorf("first pane runs %q, want claude", last)
+	}
+	if last := cmds[1][len(cmds[1])-1]; !strings.HasPrefix(last, "codex --model o3;") || cmds[1][0] != "split-window" {
+		t.Errorf("second pane = %q", cmds[1])
+	}
+	if strings.Join(cmds[2], " ") != "select-layout -t caam-acme_v2 tiled" {
+		t.Errorf("layout = %q", cmds[2])
+	}
+}
+
+func TestDeleteWorkspaceRemovesTemplate(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.CreateWorkspace("acme", map[string]string{"codex": "c"})
+	cfg.SetWorkspaceTemplate("acme", config.WorkspaceTemplate{Dir: "/src/acme"})
+	if cfg.GetWorkspaceTemplate("acme").Dir != "/src/acme" {
+		t.Fatal("template not stored")
+	}
+	cfg.DeleteWorkspace("acme")
+	if _, ok := cfg.WorkspaceTemplates["acme"]; ok {
+		t.Error("DeleteWorkspace should drop the template")
+	}
+
+	cfg.SetWorkspaceTemplate("home", config.WorkspaceTemplate{})
+	if _, ok := cfg.WorkspaceTemplates["home"]; ok {
+		t.Error("an empty template should not be stored")
+	}
+}
@@ -0,0 +1,186 @@
+// Package account groups profiles that belong to the same underlying provider
+// account.
+//
+// Profile names are user-chosen labels: the same login can be saved as two
+// vault profiles, mirrored into an isolated profile, and so on. The registry
+// keys every profile by provider + a hash of the account email so callers can
+// aggregate usage and cooldowns per account instead of per label.
+package account
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+// Profile kinds tracked by the registry.
+const (
+	KindVault    = "vault"
+	KindIsolated = "isolated"
+)
+
+// Member is a single profile that maps to an account.
+type Member struct {
+	Kind    string `json:"kind"`
+	Profile string `json:"profile"`
+}
+
+// Account is one underlying provider account and every profile linked to it.
+type Account struct {
+	Key      string   `json:"key"`
+	Provider string   `json:"provider"`
+	Email    string   `json:"email"`
+	Members  []Member `json:"members"`
+}
+
+// ProfileNames returns the distinct profile names linked to the account,
+// regardless of kind, in sorted order.
+func (a *Account) ProfileNames() []string {
+	if a == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(a.Members))
+	names := make([]string, 0, len(a.Members))
+	for _, m := range a.Members {
+		if seen[m.Profile] {
+			continue
+		}
+		seen[m.Profile] = true
+		names = append(names, m.Profile)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Key returns the registry key for a provider account, or "" when the email
+// is unknown. The email is normalized (trimmed, lowercased) and hashed so the
+// key can be logged or stored without exposing the address.
+func Key(provider, email string) string {
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	email = strings.ToLower(strings.TrimSpace(email))
+	if provider == "" || email == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(email))
+	return provider + ":" + hex.EncodeToString(sum[:])[:16]
+}
+
+// Registry links profiles to accounts.
+type Registry struct {
+	accounts  map[string]*Account
+	byProfile map[string]string // provider/kind/profile -> account key
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		accounts:  make(map[string]*Account),
+		byProfile: make(map[string]string),
+	}
+}
+
+// Add links a profile to the account identified by provider and email.
+// Profiles without an email cannot be linked and are ignored; Add reports
+// whether the profile was registered.
+func (r *Registry) Add(provider, email, kind, profileName string) bool {
+	key := Key(provider, email)
+	if key == "" || profileName == "" {
+		return false
+	}
+	provider = strings.ToLower(strings.TrimSpace(provider))
+
+	memberKey := memberKey(provider, kind, profileName)
+	if existing, ok := r.byProfile[memberKey]; ok {
+		return existing == key
+	}
+
+	acct, ok := r.accounts[key]
+	if !ok {
+		acct = &Account{
+			Key:      key,
+			Provider: provider,
+			Email:    strings.TrimSpace(email),
+		}
+		r.accounts[key] = acct
+	}
+	acct.Members = append(acct.Members, Member{Kind: kind, Profile: profileName})
+	r.byProfile[memberKey] = key
+	return true
+}
+
+// Lookup returns the account a profile belongs to, or nil if it is unlinked.
+func (r *Registry) Lookup(provider, kind, profileName string) *Account {
+	key, ok := r.byProfile[memberKey(strings.ToLower(provider), kind, profileName)]
+	if !ok {
+		return nil
+	}
+	return r.accounts[key]
+}
+
+// ByEmail returns the provider account with the given email, or nil if no
+// profile is linked to it. The email is matched case-insensitively.
+func (r *Registry) ByEmail(provider, email string) *Account {
+	key := Key(provider, email)
+	if key == "" {
+		return nil
+	}
+	return r.accounts[key]
+}
+
+// ProfilesOfKind returns the account's profile names of one kind, sorted.
+func (a *Account) ProfilesOfKind(kind string) []string {
+	if a == nil {
+		return nil
+	}
+	var out []string
+	for _, m := range a.Members {
+		if m.Kind == kind {
+			out = append(out, m.Profile)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Siblings returns the other profiles of the given kind that share an account
+// with provider/profileName. The profile itself is not included.
+func (r *Registry) Siblings(provider, kind, profileName string) []string {
+	acct := r.Lookup(provider, kind, profileName)
+	if acct == nil {
+		return nil
+	}
+	var out []string
+	for _, m := range acct.Members {
+		if m.Kind == kind && m.Profile != profileName {
+			out = append(out, m.Profile)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Accounts returns all registered accounts sorted by provider, then email.
+func (r *Registry) Accounts() []*Account {
+	out := make([]*Account, 0, len(r.accounts))
+	for _, acct := range r.accounts {
+		sort.Slice(acct.Members, func(i, j int) bool {
+			if acct.Members[i].Kind != acct.Members[j].Kind {
+				return acct.Members[i].Kind > acct.Members[j].Kind
+			}
+			return acct.Members[i].Profile < acct.Members[j].Profile
+		})
+		out = append(out, acct)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return strings.ToLower(out[i].Email) < strings.ToLower(out[j].Email)
+	})
+	return out
+}
+
+func memberKey(provider, kind, profileName string) string {
+	return provider + "/" + kind + "/" + profileName
+}
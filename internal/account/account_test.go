@@ -0,0 +1,90 @@
+package account
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	k1 := Key("claude", "Alice@Example.com")
+	k2 := Key("Claude", "  alice@example.com ")
+	if k1 == "" {
+		t.Fatal("Key() returned empty key for valid input")
+	}
+	if k1 != k2 {
+		t.Errorf("Key() not normalized: %q != %q", k1, k2)
+	}
+	if !strings.HasPrefix(k1, "claude:") {
+		t.Errorf("Key() = %q, want claude: prefix", k1)
+	}
+	if strings.Contains(k1, "alice") {
+		t.Errorf("Key() = %q leaks the email", k1)
+	}
+	if Key("codex", "alice@example.com") == k1 {
+		t.Error("Key() should differ across providers")
+	}
+	if Key("claude", "") != "" {
+		t.Error("Key() should be empty without an email")
+	}
+}
+
+func TestRegistryGroupsProfiles(t *testing.T) {
+	r := NewRegistry()
+	r.Add("claude", "alice@example.com", KindVault, "work")
+	r.Add("claude", "ALICE@example.com", KindVault, "work-copy")
+	r.Add("claude", "alice@example.com", KindIsolated, "work")
+	r.Add("claude", "bob@example.com", KindVault, "personal")
+	if r.Add("claude", "", KindVault, "unknown") {
+		t.Error("Add() should reject profiles without an email")
+	}
+
+	accounts := r.Accounts()
+	if len(accounts) != 2 {
+		t.Fatalf("Accounts() len = %d, want 2", len(accounts))
+	}
+	alice := accounts[0]
+	if alice.Email != "alice@example.com" {
+		t.Fatalf("accounts[0].Email = %q, want alice@example.com", alice.Email)
+	}
+	if len(alice.Members) != 3 {
+		t.Errorf("alice members = %d, want 3", len(alice.Members))
+	}
+	if alice.Members[0].Kind != KindVault {
+		t.Errorf("vault members should sort first, got %+v", alice.Members)
+	}
+	if got := alice.ProfileNames(); len(got) != 2 || got[0] != "work" || got[1] != "work-copy" {
+		t.Errorf("ProfileNames() = %v, want [work work-copy]", got)
+	}
+
+	if got := r.Siblings("claude", KindVault, "work"); len(got) != 1 || got[0] != "work-copy" {
+		t.Errorf("Siblings() = %v, want [work-copy]", got)
+	}
+	if got := r.Siblings("claude", KindVault, "personal"); len(got) != 0 {
+		t.Errorf("Siblings() for single-profile account = %v, want none", got)
+	}
+	if r.Lookup("claude", KindVault, "unknown") != nil {
+		t.Error("Lookup() should return nil for unlinked profiles")
+	}
+}
+
+func TestRegistryByEmail(t *testing.T) {
+	r := NewRegistry()
+	r.Add("claude", "alice@example.com", KindVault, "work")
+	r.Add("claude", "alice@example.com", KindVault, "backup")
+	r.Add("claude", "alice@example.com", KindIsolated, "sandbox")
+	r.Add("codex", "alice@example.com", KindVault, "codex-work")
+
+	acct := r.ByEmail("claude", " Alice@Example.com ")
+	if acct == nil {
+		t.Fatal("ByEmail() = nil, want alice's claude account")
+	}
+	if got := acct.ProfilesOfKind(KindVault); len(got) != 2 || got[0] != "backup" || got[1] != "work" {
+		t.Errorf("ProfilesOfKind(vault) = %v, want [backup work]", got)
+	}
+	if got := acct.ProfilesOfKind(KindIsolated); len(got) != 1 || got[0] != "sandbox" {
+		t.Errorf("ProfilesOfKind(isolated) = %v, want [sandbox]", got)
+	}
+	if r.ByEmail("gemini", "alice@example.com") != nil || r.ByEmail("claude", "") != nil {
+		t.Error("ByEmail() should return nil for unknown accounts")
+	}
+}
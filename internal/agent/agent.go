@@ -12,6 +12,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
 )
 
 // Config configures the auth agent.
@@ -22,6 +24,14 @@ type Config struct {
 	// CoordinatorURL is the URL of the remote coordinator.
 	CoordinatorURL string
 
+	// CoordinatorToken is the API token sent to the coordinator; it needs
+	// the activate scope to complete logins.
+	CoordinatorToken string
+
+	// APITokens, when set, are the only tokens this agent's own API
+	// accepts.
+	APITokens []apitoken.Token
+
 	// PollInterval is how often to poll for pending requests.
 	PollInterval time.Duration
 
@@ -139,10 +149,11 @@ func (a *Agent) Start(ctx context.Context) error {
 	})
 
 	// Set up HTTP server
+	guard := apitoken.NewGuard(a.config.APITokens, a.logger)
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /status", a.handleStatus)
-	mux.HandleFunc("POST /auth", a.handleAuth)
-	mux.HandleFunc("GET /accounts", a.handleAccounts)
+	mux.HandleFunc("GET /status", guard.Require(apitoken.ScopeRead, a.handleStatus))
+	mux.HandleFunc("POST /auth", guard.Require(apitoken.ScopeActivate, a.handleAuth))
+	mux.HandleFunc("GET /accounts", guard.Require(apitoken.ScopeRead, a.handleAccounts))
 
 	a.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", a.config.Port),
@@ -233,6 +244,7 @@ func (a *Agent) checkPendingRequests(ctx context.Context) {
 	if err != nil {
 		return
 	}
+	apitoken.SetAuthorization(req, a.config.CoordinatorToken)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
@@ -322,6 +334,7 @@ func (a *Agent) sendAuthComplete(ctx context.Context, requestID, code, account,
 		return
 	}
 	req.Header.Set("Content-Type", "application/json")
+	apitoken.SetAuthorization(req, a.config.CoordinatorToken)
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
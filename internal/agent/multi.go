@@ -10,13 +10,16 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
 )
 
 // CoordinatorEndpoint represents a remote coordinator to poll.
 type CoordinatorEndpoint struct {
-	Name        string    `json:"name"`         // Short name: "csd", "css", "trj"
-	URL         string    `json:"url"`          // Base URL: http://100.x.x.x:7890
-	DisplayName string    `json:"display_name"` // Human-friendly name
+	Name        string    `json:"name"`            // Short name: "csd", "css", "trj"
+	URL         string    `json:"url"`             // Base URL: http://100.x.x.x:7890
+	DisplayName string    `json:"display_name"`    // Human-friendly name
+	Token       string    `json:"token,omitempty"` // API token for the coordinator (activate scope)
 	LastCheck   time.Time `json:"-"`
 	IsHealthy   bool      `json:"-"`
 	LastError   string    `json:"-"`
@@ -62,6 +65,10 @@ type MultiConfig struct {
 	// Accounts is the list of account emails to cycle through.
 	Accounts []string `json:"accounts"`
 
+	// APITokens, when set, are the only tokens this agent's own API
+	// accepts.
+	APITokens []apitoken.Token `json:"-"`
+
 	// Logger for structured logging.
 	Logger *slog.Logger `json:"-"`
 }
@@ -146,11 +153,12 @@ func (a *MultiAgent) Start(ctx context.Context) error {
 	})
 
 	// Set up HTTP server
+	guard := apitoken.NewGuard(a.config.APITokens, a.logger)
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /status", a.handleStatus)
-	mux.HandleFunc("GET /coordinators", a.handleCoordinators)
-	mux.HandleFunc("GET /accounts", a.handleAccounts)
-	mux.HandleFunc("POST /auth", a.handleAuth)
+	mux.HandleFunc("GET /status", guard.Require(apitoken.ScopeRead, a.handleStatus))
+	mux.HandleFunc("GET /coordinators", guard.Require(apitoken.ScopeRead, a.handleCoordinators))
+	mux.HandleFunc("GET /accounts", guard.Require(apitoken.ScopeRead, a.handleAccounts))
+	mux.HandleFunc("POST /auth", guard.Require(apitoken.ScopeActivate, a.handleAuth))
 
 	a.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", a.config.Port),
@@ -254,6 +262,7 @@ func (a *MultiAgent) checkCoordinator(ctx context.Context, coord *CoordinatorEnd
 		coord.SetHealth(false, err.Error())
 		return
 	}
+	apitoken.SetAuthorization(req, coord.Token)
 
 	client := &http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Do(req)
@@ -372,6 +381,7 @@ func (a *MultiAgent) sendAuthComplete(ctx context.Context, coord *CoordinatorEnd
 		return
 	}
 	req.Header.Set("Content-Type", "application/json")
+	apitoken.SetAuthorization(req, coord.Token)
 
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
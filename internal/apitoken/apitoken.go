@@ -0,0 +1,190 @@
+// Package apitoken implements scoped bearer tokens for caam's HTTP APIs (the
+// auth coordinator and auth agent). Each token carries a scope, so a process
+// holding a read-only token can query status but not drive logins, and every
+// authorized request is logged with the name of the token that made it.
+//
+// Only a SHA-256 hash of each secret is kept in the config. With no tokens
+// configured the APIs stay open, as before tokens existed.
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope is what a token may do. Each scope includes the ones below it.
+type Scope string
+
+const (
+	// ScopeRead allows status and listing endpoints.
+	ScopeRead Scope = "read"
+	// ScopeActivate also allows actions that switch accounts or complete
+	// logins.
+	ScopeActivate Scope = "activate"
+	// ScopeAdmin allows everything, including destructive actions.
+	ScopeAdmin Scope = "admin"
+)
+
+var scopeRank = map[Scope]int{ScopeRead: 1, ScopeActivate: 2, ScopeAdmin: 3}
+
+// Scopes returns the scopes from least to most privileged.
+func Scopes() []Scope {
+	return []Scope{ScopeRead, ScopeActivate, ScopeAdmin}
+}
+
+// ParseScope parses a scope name.
+func ParseScope(s string) (Scope, error) {
+	scope := Scope(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := scopeRank[scope]; !ok {
+		return "", fmt.Errorf("invalid scope %q (use read, activate or admin)", s)
+	}
+	return scope, nil
+}
+
+// Allows reports whether s grants need.
+func (s Scope) Allows(need Scope) bool {
+	have, ok := scopeRank[s]
+	return ok && have >= scopeRank[need]
+}
+
+// Token is a configured API token.
+type Token struct {
+	Name      string    `json:"name"`
+	Scope     Scope     `json:"scope"`
+	SHA256    string    `json:"sha256"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// secretPrefix marks caam API secrets so they are easy to spot in logs and
+// secret scanners.
+const secretPrefix = "caam_"
+
+// Generate creates a token named name. The secret is returned once and only
+// its hash is kept in the Token.
+func Generate(name string, scope Scope, now time.Time) (string, Token, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", Token{}, fmt.Errorf("generate token: %w", err)
+	}
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return secret, Token{Name: name, Scope: scope, SHA256: Hash(secret), CreatedAt: now.UTC()}, nil
+}
+
+// Hash returns the hex SHA-256 of secret, as stored in Token.SHA256.
+func Hash(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// Match returns the token whose hash matches secret.
+func Match(tokens []Token, secret string) (Token, bool) {
+	if secret == "" {
+		return Token{}, false
+	}
+	h := []byte(Hash(secret))
+	var found Token
+	ok := false
+	// Compare against every token so timing doesn't reveal which matched.
+	for _, t := range tokens {
+		if subtle.ConstantTimeCompare(h, []byte(strings.ToLower(t.SHA256))) == 1 {
+			found, ok = t, true
+		}
+	}
+	return found, ok
+}
+
+// SetAuthorization adds secret to req as a bearer token; an empty secret
+// leaves req alone.
+func SetAuthorization(req *http.Request, secret string) {
+	if secret != "" {
+		req.Header.Set("Authorization", "Bearer "+secret)
+	}
+}
+
+type tokenKey struct{}
+
+// FromContext returns the token that authorized the request, if any.
+func FromContext(ctx context.Context) (Token, bool) {
+	t, ok := ctx.Value(tokenKey{}).(Token)
+	return t, ok
+}
+
+// Guard checks the bearer token of API requests against the configured
+// tokens.
+type Guard struct {
+	mu     sync.RWMutex
+	tokens []Token
+	logger *slog.Logger
+}
+
+// NewGuard returns a Guard accepting tokens. A nil logger uses the default.
+func NewGuard(tokens []Token, logger *slog.Logger) *Guard {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Guard{tokens: tokens, logger: logger}
+}
+
+// SetTokens replaces the accepted tokens.
+func (g *Guard) SetTokens(tokens []Token) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tokens = tokens
+}
+
+// Enabled reports whether requests must carry a token.
+func (g *Guard) Enabled() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return len(g.tokens) > 0
+}
+
+// Require wraps next so it only runs for requests whose token grants scope.
+// While no tokens are configured every request is let through.
+func (g *Guard) Require(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		g.mu.RLock()
+		tokens := g.tokens
+		g.mu.RUnlock()
+		if len(tokens) == 0 {
+			next(w, r)
+			return
+		}
+
+		secret, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		tok, ok := Match(tokens, strings.TrimSpace(secret))
+		if !ok {
+			g.logger.Warn("api request rejected: missing or unknown token",
+				"method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="caam"`)
+			http.Error(w, "missing or invalid API token", http.StatusUnauthorized)
+			return
+		}
+		if !tok.Scope.Allows(scope) {
+			g.logger.Warn("api request rejected: insufficient scope",
+				"token", tok.Name, "scope", tok.Scope, "required", scope,
+				"method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+			http.Error(w, fmt.Sprintf("token %q lacks the %s scope", tok.Name, scope), http.StatusForbidden)
+			return
+		}
+
+		// Reads are frequent polls; only actions make the audit log.
+		level := slog.LevelInfo
+		if scope == ScopeRead {
+			level = slog.LevelDebug
+		}
+		g.logger.Log(r.Context(), level, "api request",
+			"token", tok.Name, "scope", scope, "method", r.Method, "path", r.URL.Path, "remote", r.RemoteAddr)
+		next(w, r.WithContext(context.WithValue(r.Context(), tokenKey{}, tok)))
+	}
+}
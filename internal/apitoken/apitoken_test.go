@@ -0,0 +1,100 @@
+package apitoken
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScopeAllows(t *testing.T) {
+	tests := []struct {
+		have, need Scope
+		want       bool
+	}{
+		{ScopeRead, ScopeRead, true},
+		{ScopeRead, ScopeActivate, false},
+		{ScopeActivate, ScopeRead, true},
+		{ScopeActivate, ScopeAdmin, false},
+		{ScopeAdmin, ScopeActivate, true},
+		{Scope("bogus"), ScopeRead, false},
+	}
+	for _, tt := range tests {
+		if got := tt.have.Allows(tt.need); got != tt.want {
+			t.Errorf("%s.Allows(%s) = %v, want %v", tt.have, tt.need, got, tt.want)
+		}
+	}
+
+	if s, err := ParseScope(" Admin "); err != nil || s != ScopeAdmin {
+		t.Errorf("ParseScope(Admin) = %q, %v", s, err)
+	}
+	if _, err := ParseScope("root"); err == nil {
+		t.Error("ParseScope(root) should fail")
+	}
+}
+
+func TestGenerateAndMatch(t *testing.T) {
+	secret, tok, err := Generate("ci", ScopeRead, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.SHA256 == "" || tok.SHA256 == secret {
+		t.Fatalf("token stores %q, want only a hash", tok.SHA256)
+	}
+
+	other := Token{Name: "ops", Scope: ScopeAdmin, SHA256: Hash("another-secret")}
+	got, ok := Match([]Token{other, tok}, secret)
+	if !ok || got.Name != "ci" {
+		t.Errorf("Match() = %+v, %v", got, ok)
+	}
+	if _, ok := Match([]Token{other, tok}, "wrong"); ok {
+		t.Error("Match() accepted a wrong secret")
+	}
+	if _, ok := Match([]Token{other}, ""); ok {
+		t.Error("Match() accepted an empty secret")
+	}
+}
+
+func TestGuardRequire(t *testing.T) {
+	readSecret, readTok, _ := Generate("dashboard", ScopeRead, time.Now())
+	actSecret, actTok, _ := Generate("agent", ScopeActivate, time.Now())
+	guard := NewGuard(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var seen string
+	handler := guard.Require(ScopeActivate, func(w http.ResponseWriter, r *http.Request) {
+		if tok, ok := FromContext(r.Context()); ok {
+			seen = tok.Name
+		}
+	})
+	call := func(secret string) int {
+		req := httptest.NewRequest(http.MethodPost, "/auth/complete", nil)
+		SetAuthorization(req, secret)
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	// No tokens configured: the API stays open.
+	if code := call(""); code != http.StatusOK {
+		t.Errorf("open API = %d, want 200", code)
+	}
+
+	guard.SetTokens([]Token{readTok, actTok})
+	if !guard.Enabled() {
+		t.Fatal("Enabled() = false with tokens set")
+	}
+	if code := call(""); code != http.StatusUnauthorized {
+		t.Errorf("no token = %d, want 401", code)
+	}
+	if code := call("caam_wrong"); code != http.StatusUnauthorized {
+		t.Errorf("unknown token = %d, want 401", code)
+	}
+	if code := call(readSecret); code != http.StatusForbidden {
+		t.Errorf("read token on activate endpoint = %d, want 403", code)
+	}
+	if code := call(actSecret); code != http.StatusOK || seen != "agent" {
+		t.Errorf("activate token = %d (token %q), want 200 attributed to agent", code, seen)
+	}
+}
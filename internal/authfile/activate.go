@@ -0,0 +1,106 @@
+package authfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Auto-backup modes for ActivateOptions.BackupMode, as in the
+// safety.auto_backup_before_switch setting.
+const (
+	BackupAlways = "always"
+	BackupSmart  = "smart"
+	BackupNever  = "never"
+)
+
+// ActivateOptions controls the safety steps of Activate.
+type ActivateOptions struct {
+	// BackupMode says when the live login is backed up before the switch:
+	// BackupAlways when switching to another profile, BackupSmart (the
+	// default) only when it matches no vault profile and would be lost, or
+	// BackupNever.
+	BackupMode string
+
+	// MaxAutoBackups is how many timestamped auto-backups to keep per tool;
+	// 0 keeps all of them.
+	MaxAutoBackups int
+
+	// BeforeRestore, if set, runs after the backups and right before the
+	// profile is put in place. An error aborts the activation.
+	BeforeRestore func() error
+}
+
+// ActivationSafety reports what Activate did to keep the previous login.
+type ActivationSafety struct {
+	// OriginalBackup is the `_original` profile when this first activation
+	// preserved the pre-caam login in it.
+	OriginalBackup string `json:"original_backup,omitempty"`
+
+	// BackupMode is the auto-backup mode that applied.
+	BackupMode string `json:"backup_mode"`
+
+	// AutoBackup is the timestamped backup of the live login, if one was
+	// made.
+	AutoBackup string `json:"auto_backup,omitempty"`
+
+	// Warnings are safety steps that failed without stopping the switch.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Activate switches tool's live auth to profile the safe way: the pre-caam
+// login is preserved as `_original` on first use, the live login is backed
+// up per opts.BackupMode (rotating old auto-backups), and only then is the
+// profile restored. Every entry point that switches profiles should go
+// through it.
+//
+// The returned report is non-nil even on error, describing what was backed
+// up before the failure.
+func (v *Vault) Activate(fileSet AuthFileSet, profile string, opts ActivateOptions) (*ActivationSafety, error) {
+	mode := strings.TrimSpace(opts.BackupMode)
+	if mode == "" {
+		mode = BackupSmart
+	}
+	safety := &ActivationSafety{BackupMode: mode}
+
+	did, err := v.BackupOriginal(fileSet)
+	if err != nil {
+		return safety, fmt.Errorf("backup original auth: %w", err)
+	}
+	if did {
+		safety.OriginalBackup = originalProfileName
+	}
+
+	if mode != BackupNever {
+		current, _ := v.ActiveProfile(fileSet)
+		shouldBackup := false
+		switch mode {
+		case BackupAlways:
+			shouldBackup = current != profile
+		case BackupSmart:
+			shouldBackup = current == "" && HasAuthFiles(fileSet)
+		}
+
+		if shouldBackup {
+			name, err := v.BackupCurrent(fileSet)
+			if err != nil {
+				safety.Warnings = append(safety.Warnings, fmt.Sprintf("could not auto-backup current state: %v", err))
+			} else if name != "" {
+				safety.AutoBackup = name
+				if err := v.RotateAutoBackups(fileSet.Tool, opts.MaxAutoBackups); err != nil {
+					safety.Warnings = append(safety.Warnings, fmt.Sprintf("could not rotate old backups: %v", err))
+				}
+			}
+		}
+	}
+
+	if opts.BeforeRestore != nil {
+		if err := opts.BeforeRestore(); err != nil {
+			return safety, err
+		}
+	}
+
+	if err := v.Restore(fileSet, profile); err != nil {
+		return safety, err
+	}
+	return safety, nil
+}
@@ -18,9 +18,13 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
 )
 
 // AuthFileSpec defines where a tool stores its auth credentials.
@@ -36,6 +40,10 @@ type AuthFileSpec struct {
 
 	// Required indicates if this file must exist for auth to work.
 	Required bool
+
+	// Category groups the file for selective backups (see
+	// CategoryCredentials); empty means credentials.
+	Category string
 }
 
 // AuthFileSet is a collection of auth files that together represent
@@ -46,6 +54,13 @@ type AuthFileSet struct {
 	// AllowOptionalOnly permits auth states that rely solely on optional files
 	// (e.g., API key or helper-based auth that doesn't create OAuth artifacts).
 	AllowOptionalOnly bool
+	// ConfigFiles are non-credential config files handled per the vault's
+	// ConfigPolicy (see configfile.go); they never count as auth files.
+	ConfigFiles []ConfigFileSpec
+	// Categories limits Backup to files in these categories (plus required
+	// files); empty captures everything. The choice is recorded in meta.json
+	// and Restore leaves files outside it alone.
+	Categories []string
 }
 
 // CodexAuthFiles returns the auth files for Codex CLI.
@@ -57,16 +72,27 @@ func CodexAuthFiles() AuthFileSet {
 		home = filepath.Join(homeDir, ".codex")
 	}
 
+	return codexAuthFilesIn(home)
+}
+
+func codexAuthFilesIn(codexHome string) AuthFileSet {
 	return AuthFileSet{
 		Tool: "codex",
 		Files: []AuthFileSpec{
 			{
 				Tool:        "codex",
-				Path:        filepath.Join(home, "auth.json"),
+				Path:        filepath.Join(codexHome, "auth.json"),
 				Description: "Codex CLI OAuth token (GPT Pro subscription)",
 				Required:    true,
 			},
 		},
+		ConfigFiles: []ConfigFileSpec{
+			{
+				Tool:        "codex",
+				Path:        filepath.Join(codexHome, "config.toml"),
+				Description: "Codex CLI settings (model, approval mode)",
+			},
+		},
 	}
 }
 
@@ -83,6 +109,10 @@ func ClaudeAuthFiles() AuthFileSet {
 		xdgConfig = filepath.Join(homeDir, ".config")
 	}
 
+	return claudeAuthFilesIn(homeDir, xdgConfig)
+}
+
+func claudeAuthFilesIn(homeDir, xdgConfig string) AuthFileSet {
 	return AuthFileSet{
 		Tool: "claude",
 		Files: []AuthFileSpec{
@@ -97,6 +127,7 @@ func ClaudeAuthFiles() AuthFileSet {
 				Path:        filepath.Join(homeDir, ".claude.json"),
 				Description: "Claude Code settings and session state",
 				Required:    false, // This is a settings file, not strictly required for auth
+				Category:    CategoryHistory,
 			},
 			{
 				Tool:        "claude",
@@ -109,6 +140,7 @@ func ClaudeAuthFiles() AuthFileSet {
 				Path:        filepath.Join(homeDir, ".claude", "settings.json"),
 				Description: "Claude Code user settings (apiKeyHelper / API key mode)",
 				Required:    false,
+				Category:    CategorySettings,
 			},
 		},
 		AllowOptionalOnly: true,
@@ -126,6 +158,10 @@ func GeminiAuthFiles() AuthFileSet {
 		geminiHome = filepath.Join(homeDir, ".gemini")
 	}
 
+	return geminiAuthFilesIn(geminiHome)
+}
+
+func geminiAuthFilesIn(geminiHome string) AuthFileSet {
 	return AuthFileSet{
 		Tool: "gemini",
 		Files: []AuthFileSpec{
@@ -134,6 +170,7 @@ func GeminiAuthFiles() AuthFileSet {
 				Path:        filepath.Join(geminiHome, "settings.json"),
 				Description: "Gemini CLI settings with Google OAuth state (Gemini Ultra subscription)",
 				Required:    true,
+				Category:    CategorySettings,
 			},
 			// Additional auth files that may store tokens
 			{
@@ -162,6 +199,51 @@ func GetAuthFileSet(provider string) (AuthFileSet, bool) {
 		return CodexAuthFiles(), true
 	case "gemini":
 		return GeminiAuthFiles(), true
+	default:
+		registeredMu.RLock()
+		fileSet, ok := registered[strings.ToLower(provider)]
+		registeredMu.RUnlock()
+		if !ok {
+			return AuthFileSet{}, false
+		}
+		return fileSet(), true
+	}
+}
+
+var (
+	registeredMu sync.RWMutex
+	registered   = map[string]func() AuthFileSet{}
+)
+
+// RegisterAuthFileSet makes GetAuthFileSet resolve tool, which is not one of
+// the built-in tools, with fileSet. Provider plugins register their auth
+// files this way so code that only knows the tool name (such as scheduled
+// reverts) can find them. Registering a tool again replaces its file set.
+func RegisterAuthFileSet(tool string, fileSet func() AuthFileSet) {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registered[strings.ToLower(tool)] = fileSet
+}
+
+// AuthDirs describes the home-like directories a tool resolves its auth files
+// against. Isolated profiles use their own pseudo-HOME, XDG_CONFIG_HOME and
+// CODEX_HOME instead of the user's real ones.
+type AuthDirs struct {
+	Home      string
+	XDGConfig string
+	CodexHome string
+}
+
+// AuthFileSetAt returns the AuthFileSet for the given provider resolved against
+// explicit directories rather than the current environment.
+func AuthFileSetAt(provider string, dirs AuthDirs) (AuthFileSet, bool) {
+	switch strings.ToLower(provider) {
+	case "claude":
+		return claudeAuthFilesIn(dirs.Home, dirs.XDGConfig), true
+	case "codex":
+		return codexAuthFilesIn(dirs.CodexHome), true
+	case "gemini":
+		return geminiAuthFilesIn(filepath.Join(dirs.Home, ".gemini")), true
 	default:
 		return AuthFileSet{}, false
 	}
@@ -169,7 +251,64 @@ func GetAuthFileSet(provider string) (AuthFileSet, bool) {
 
 // Vault manages stored auth file backups.
 type Vault struct {
-	basePath string // ~/.local/share/caam/vault
+	basePath     string // ~/.local/share/caam/vault
+	sharedPath   string // /usr/local/share/caam/vault; read-only fallback
+	mode         ActivationMode
+	configPolicy ConfigPolicy
+	durability   Durability
+	shareable    bool
+	key          []byte // set once an encrypted vault is unlocked
+	storage      StorageBackend
+}
+
+// ActivationMode controls how Restore puts a profile's files in place.
+type ActivationMode string
+
+const (
+	// ActivationCopy copies vault files over the live auth paths (default).
+	ActivationCopy ActivationMode = "copy"
+
+	// ActivationSymlink makes the live auth paths symlinks into the vault
+	// profile, so switches are O(1) and token refreshes written through the
+	// link land in the vault automatically.
+	ActivationSymlink ActivationMode = "symlink"
+)
+
+// ParseActivationMode parses an activation mode name. Empty means copy.
+func ParseActivationMode(s string) (ActivationMode, error) {
+	switch ActivationMode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ActivationCopy:
+		return ActivationCopy, nil
+	case ActivationSymlink:
+		return ActivationSymlink, nil
+	default:
+		return "", fmt.Errorf("unknown activation mode %q (supported: copy, symlink)", s)
+	}
+}
+
+// Durability controls whether vault writes wait for the data to reach disk.
+type Durability string
+
+const (
+	// DurabilitySafe fsyncs each written file and its directory before
+	// reporting success (default).
+	DurabilitySafe Durability = "safe"
+
+	// DurabilityFast skips the fsyncs. Writes are still atomic renames, but
+	// a crash or power loss shortly after may lose them.
+	DurabilityFast Durability = "fast"
+)
+
+// ParseDurability parses a durability level name. Empty means safe.
+func ParseDurability(s string) (Durability, error) {
+	switch Durability(strings.ToLower(strings.TrimSpace(s))) {
+	case "", DurabilitySafe:
+		return DurabilitySafe, nil
+	case DurabilityFast:
+		return DurabilityFast, nil
+	default:
+		return "", fmt.Errorf("unknown durability %q (supported: safe, fast)", s)
+	}
 }
 
 const originalProfileName = "_original"
@@ -184,9 +323,36 @@ func IsSystemProfile(name string) bool {
 
 var errProtectedSystemProfile = fmt.Errorf("protected system profile")
 
-// NewVault creates a new vault at the given path.
+// NewVault creates a new vault at the given path. The vault falls back to the
+// shared vault at DefaultSharedVaultPath for profiles it doesn't have.
 func NewVault(basePath string) *Vault {
-	return &Vault{basePath: basePath}
+	return &Vault{basePath: basePath, sharedPath: DefaultSharedVaultPath()}
+}
+
+// SetActivationMode sets how Restore activates profiles.
+func (v *Vault) SetActivationMode(mode ActivationMode) {
+	v.mode = mode
+}
+
+// ActivationMode returns how Restore activates profiles.
+func (v *Vault) ActivationMode() ActivationMode {
+	if v.mode == "" {
+		return ActivationCopy
+	}
+	return v.mode
+}
+
+// SetDurability sets whether vault writes fsync before reporting success.
+func (v *Vault) SetDurability(d Durability) {
+	v.durability = d
+}
+
+// Durability returns whether vault writes fsync before reporting success.
+func (v *Vault) Durability() Durability {
+	if v.durability == "" {
+		return DurabilitySafe
+	}
+	return v.durability
 }
 
 // BasePath returns the on-disk path to the vault root directory.
@@ -194,9 +360,22 @@ func (v *Vault) BasePath() string {
 	return v.basePath
 }
 
+// vaultPathOverride replaces the default vault location when set.
+var vaultPathOverride string
+
+// SetVaultPathOverride makes DefaultVaultPath return path for the rest of the
+// process; an empty path restores the default. caam uses it for the vault of
+// the active config context.
+func SetVaultPathOverride(path string) {
+	vaultPathOverride = path
+}
+
 // DefaultVaultPath returns the default vault location.
 // Falls back to current directory if home directory cannot be determined.
 func DefaultVaultPath() string {
+	if vaultPathOverride != "" {
+		return vaultPathOverride
+	}
 	if caamHome := os.Getenv("CAAM_HOME"); caamHome != "" {
 		return filepath.Join(caamHome, "data", "vault")
 	}
@@ -211,9 +390,13 @@ func DefaultVaultPath() string {
 	return filepath.Join(homeDir, ".local", "share", "caam", "vault")
 }
 
-// ProfilePath returns the path to a profile's backup directory.
+// ProfilePath returns the path to a profile's backup directory, in the shared
+// vault for shared profiles.
 // Structure: vault/<tool>/<profile>/
 func (v *Vault) ProfilePath(tool, profile string) string {
+	if dir, shared, err := v.readProfileDir(tool, profile); err == nil && shared {
+		return dir
+	}
 	return filepath.Join(v.basePath, tool, profile)
 }
 
@@ -223,8 +406,9 @@ func (v *Vault) BackupPath(tool, profile, filename string) string {
 	return filepath.Join(v.ProfilePath(tool, profile), filename)
 }
 
-// Backup saves the current auth files to the vault.
-func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
+// Backup saves the current auth files to the vault. A backup that fails
+// partway leaves the profile as it was, and a new profile is not created.
+func (v *Vault) Backup(fileSet AuthFileSet, profile string) (err error) {
 	profileDir, err := v.safeProfileDir(fileSet.Tool, profile)
 	if err != nil {
 		return err
@@ -233,6 +417,10 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 	tool := strings.TrimSpace(fileSet.Tool)
 	profile = strings.TrimSpace(profile)
 
+	if err := v.checkWritable(tool, profile); err != nil {
+		return err
+	}
+
 	// System profiles are immutable safety artifacts; never overwrite them.
 	if IsSystemProfile(profile) {
 		st, err := os.Stat(profileDir)
@@ -247,10 +435,39 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 		}
 	}
 
+	// Snapshot what this backup may overwrite so a failure can be undone.
+	_, statErr := os.Stat(profileDir)
+	created := os.IsNotExist(statErr)
+	var touched []string
+	for _, spec := range fileSet.Files {
+		touched = append(touched, filepath.Join(profileDir, filepath.Base(spec.Path)))
+	}
+	for _, spec := range fileSet.ConfigFiles {
+		touched = append(touched, filepath.Join(profileDir, filepath.Base(spec.Path)))
+	}
+	touched = append(touched, filepath.Join(profileDir, "meta.json"))
+	var snaps []pathSnapshot
+	if !created {
+		if snaps, err = snapshotPaths(touched); err != nil {
+			return err
+		}
+	}
+
 	// Create profile directory
 	if err := os.MkdirAll(profileDir, 0700); err != nil {
 		return fmt.Errorf("create profile dir: %w", err)
 	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		err = withRollback(err, func() error {
+			if created {
+				return os.RemoveAll(profileDir)
+			}
+			return restoreSnapshots(snaps, v.Durability() == DurabilitySafe)
+		})
+	}()
 
 	backedUp := 0
 	requiredFound := false
@@ -258,6 +475,14 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 	var missingRequired []string
 	var originalPaths []string
 	for _, spec := range fileSet.Files {
+		if !includes(fileSet.Categories, spec) {
+			// Drop what an earlier, fuller backup captured so the profile
+			// holds exactly what this one chose.
+			if err := v.removeStored(filepath.Join(profileDir, filepath.Base(spec.Path))); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("remove excluded %s: %w", filepath.Base(spec.Path), err)
+			}
+			continue
+		}
 		if _, err := os.Stat(spec.Path); os.IsNotExist(err) {
 			if spec.Required {
 				missingRequired = append(missingRequired, spec.Path)
@@ -269,7 +494,7 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 		filename := filepath.Base(spec.Path)
 		destPath := filepath.Join(profileDir, filename)
 
-		if err := copyFile(spec.Path, destPath); err != nil {
+		if err := v.storeFile(spec.Path, destPath); err != nil {
 			return fmt.Errorf("backup %s: %w", spec.Path, err)
 		}
 		backedUp++
@@ -289,6 +514,9 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 			return fmt.Errorf("required auth file not found: %s", missingRequired[0])
 		}
 	}
+	if err := v.backupConfigFiles(fileSet, profileDir); err != nil {
+		return err
+	}
 
 	// Write metadata
 	metaPath := filepath.Join(profileDir, "meta.json")
@@ -301,6 +529,11 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 		Type          string   `json:"type,omitempty"`       // user|system
 		CreatedBy     string   `json:"created_by,omitempty"` // user|auto|first-activate
 		OriginalPaths []string `json:"original_paths,omitempty"`
+		// Categories lists the file categories captured; empty means all.
+		Categories []string `json:"categories,omitempty"`
+		// Schemas records the JSON key structure of each file, so vendor
+		// format changes can be spotted (see ReadFileSchemas).
+		Schemas map[string]FileSchema `json:"schemas,omitempty"`
 	}{
 		Tool:          tool,
 		Profile:       profile,
@@ -309,6 +542,8 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 		Type:          "user",
 		CreatedBy:     "user",
 		OriginalPaths: originalPaths,
+		Categories:    fileSet.Categories,
+		Schemas:       v.readFileSchemas(fileSet, profileDir),
 	}
 	if IsSystemProfile(profile) {
 		meta.Type = "system"
@@ -341,22 +576,66 @@ func (v *Vault) Backup(fileSet AuthFileSet, profile string) error {
 		return fmt.Errorf("chmod temp metadata file: %w", err)
 	}
 
-	if err := f.Sync(); err != nil {
-		f.Close()
-		return fmt.Errorf("sync temp metadata file: %w", err)
+	durable := v.Durability() == DurabilitySafe
+	if durable {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return fmt.Errorf("sync temp metadata file: %w", err)
+		}
 	}
 
 	if err := f.Close(); err != nil {
 		return fmt.Errorf("close temp metadata file: %w", err)
 	}
 
+	if err := faultinject.Check(faultinject.OpRename); err != nil {
+		return fmt.Errorf("rename metadata file: %w", err)
+	}
 	if err := os.Rename(tmpPath, metaPath); err != nil {
 		return fmt.Errorf("rename metadata file: %w", err)
 	}
+	if durable {
+		if err := syncDir(dir); err != nil {
+			return fmt.Errorf("sync profile dir: %w", err)
+		}
+	}
 
+	if v.shareable {
+		return v.shareProfile(profileDir)
+	}
 	return nil
 }
 
+// BackedUpAt returns when a profile's auth files were last saved into the
+// vault, from its meta.json. Profiles without a recorded time return zero.
+func (v *Vault) BackedUpAt(tool, profile string) (time.Time, error) {
+	profileDir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	raw, err := os.ReadFile(filepath.Join(profileDir, "meta.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("read metadata: %w", err)
+	}
+	var meta struct {
+		BackedUpAt string `json:"backed_up_at"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return time.Time{}, fmt.Errorf("parse metadata: %w", err)
+	}
+	if meta.BackedUpAt == "" {
+		return time.Time{}, nil
+	}
+	ts, err := time.Parse(time.RFC3339, meta.BackedUpAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse backed_up_at: %w", err)
+	}
+	return ts, nil
+}
+
 // HasOriginalBackup reports whether the system-managed `_original` profile exists
 // for the given tool.
 func (v *Vault) HasOriginalBackup(tool string) (bool, error) {
@@ -438,6 +717,39 @@ func (v *Vault) RotateAutoBackups(tool string, maxBackups int) error {
 	return nil
 }
 
+// StaleAutoBackups returns the auto-backup profiles of a tool taken before
+// cutoff, oldest first. The newest auto-backup is never included, so one
+// rollback point always survives cleanup.
+func (v *Vault) StaleAutoBackups(tool string, cutoff time.Time) ([]string, error) {
+	profiles, err := v.listOwn(tool)
+	if err != nil {
+		return nil, fmt.Errorf("list profiles: %w", err)
+	}
+
+	var backups []string
+	for _, p := range profiles {
+		if strings.HasPrefix(p, "_backup_") {
+			backups = append(backups, p)
+		}
+	}
+	if len(backups) < 2 {
+		return nil, nil
+	}
+	sort.Strings(backups)
+
+	var stale []string
+	for _, b := range backups[:len(backups)-1] {
+		takenAt, err := time.ParseInLocation("20060102_150405", strings.TrimPrefix(b, "_backup_"), time.Local)
+		if err != nil {
+			continue // not one of ours
+		}
+		if takenAt.Before(cutoff) {
+			stale = append(stale, b)
+		}
+	}
+	return stale, nil
+}
+
 // BackupOriginal creates the system-managed `_original` profile for a tool if
 // needed. This is intended to preserve a user's pre-caam auth state.
 //
@@ -476,9 +788,11 @@ func (v *Vault) BackupOriginal(fileSet AuthFileSet) (bool, error) {
 	return true, nil
 }
 
-// Restore copies backed-up auth files to their original locations.
-func (v *Vault) Restore(fileSet AuthFileSet, profile string) error {
-	profileDir, err := v.safeProfileDir(fileSet.Tool, profile)
+// Restore copies backed-up auth files to their original locations. If any
+// file fails, the live files are put back as they were, so a failed restore
+// never leaves a mix of two logins.
+func (v *Vault) Restore(fileSet AuthFileSet, profile string) (err error) {
+	profileDir, shared, err := v.readProfileDir(fileSet.Tool, profile)
 	if err != nil {
 		return err
 	}
@@ -487,11 +801,38 @@ func (v *Vault) Restore(fileSet AuthFileSet, profile string) error {
 		return fmt.Errorf("profile %s/%s not found in vault", fileSet.Tool, profile)
 	}
 
+	// Files the backup chose not to capture keep their live contents.
+	categories := recordedCategories(profileDir)
+
+	var live []string
+	for _, spec := range fileSet.Files {
+		if includes(categories, spec) {
+			live = append(live, spec.Path)
+		}
+	}
+	for _, spec := range fileSet.ConfigFiles {
+		live = append(live, spec.Path)
+	}
+	snaps, err := snapshotPaths(live)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = withRollback(err, func() error {
+				return restoreSnapshots(snaps, v.Durability() == DurabilitySafe)
+			})
+		}
+	}()
+
 	restored := 0
 	requiredFound := false
 	optionalFound := false
 	var missingRequired []string
 	for _, spec := range fileSet.Files {
+		if !includes(categories, spec) {
+			continue
+		}
 		filename := filepath.Base(spec.Path)
 		srcPath := filepath.Join(profileDir, filename)
 
@@ -508,8 +849,13 @@ func (v *Vault) Restore(fileSet AuthFileSet, profile string) error {
 			return fmt.Errorf("create parent dir for %s: %w", spec.Path, err)
 		}
 
-		// Copy from vault to original location
-		if err := copyFile(srcPath, spec.Path); err != nil {
+		// Copy (or link) from vault to original location. Shared profiles
+		// are always copied: a link would let the tool write into them.
+		place := v.place
+		if shared {
+			place = v.loadFile
+		}
+		if err := place(srcPath, spec.Path); err != nil {
 			return fmt.Errorf("restore %s: %w", spec.Path, err)
 		}
 		restored++
@@ -529,11 +875,20 @@ func (v *Vault) Restore(fileSet AuthFileSet, profile string) error {
 		}
 	}
 
-	return nil
+	return v.restoreConfigFiles(fileSet, profileDir)
 }
 
-// List returns all profiles stored for a tool.
+// List returns all profiles stored for a tool, including shared ones.
 func (v *Vault) List(tool string) ([]string, error) {
+	profiles, err := v.listOwn(tool)
+	if err != nil {
+		return nil, err
+	}
+	return v.mergeShared(tool, profiles), nil
+}
+
+// listOwn returns the profiles stored for a tool in this vault alone.
+func (v *Vault) listOwn(tool string) ([]string, error) {
 	toolDir, err := v.safeToolDir(tool)
 	if err != nil {
 		return nil, err
@@ -555,19 +910,24 @@ func (v *Vault) List(tool string) ([]string, error) {
 	return profiles, nil
 }
 
-// ListAll returns all profiles for all tools.
+// ListAll returns all profiles for all tools, including shared ones.
 func (v *Vault) ListAll() (map[string][]string, error) {
 	result := make(map[string][]string)
 
 	entries, err := os.ReadDir(v.basePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return result, nil
-		}
+	if err != nil && !os.IsNotExist(err) {
 		return nil, err
 	}
+	if shared := v.sharedVault(); shared != nil {
+		if sharedEntries, err := os.ReadDir(shared.basePath); err == nil {
+			entries = append(entries, sharedEntries...)
+		}
+	}
 
 	for _, e := range entries {
+		if _, done := result[e.Name()]; done {
+			continue
+		}
 		if e.IsDir() {
 			profiles, err := v.List(e.Name())
 			if err != nil {
@@ -596,9 +956,173 @@ func (v *Vault) DeleteForce(tool, profile string) error {
 	if err != nil {
 		return err
 	}
+	if err := v.checkWritable(tool, profile); err != nil {
+		return err
+	}
+	// Don't leave live auth paths dangling into the removed profile.
+	if fileSet, ok := GetAuthFileSet(tool); ok {
+		if _, err := v.unlinkInto(fileSet, profileDir); err != nil {
+			return fmt.Errorf("unlink live auth from %s/%s: %w", tool, profile, err)
+		}
+	}
+	if err := v.removeStoredDir(profileDir); err != nil {
+		return err
+	}
 	return os.RemoveAll(profileDir)
 }
 
+// LinkState describes a live auth path in relation to the vault.
+type LinkState struct {
+	Path string `json:"path"`
+
+	// Linked is true when the path is a symlink into the vault.
+	Linked bool `json:"linked"`
+
+	// Profile is the vault profile a linked path points into.
+	Profile string `json:"profile,omitempty"`
+
+	// Broken is true when the path is a symlink whose target is missing.
+	Broken bool `json:"broken,omitempty"`
+}
+
+// LinkStates reports how each existing live auth path relates to the vault.
+func (v *Vault) LinkStates(fileSet AuthFileSet) []LinkState {
+	var states []LinkState
+	for _, spec := range fileSet.Files {
+		info, err := os.Lstat(spec.Path)
+		if err != nil {
+			continue
+		}
+		state := LinkState{Path: spec.Path}
+		if info.Mode()&os.ModeSymlink != 0 {
+			if _, err := os.Stat(spec.Path); err != nil {
+				state.Broken = true
+			}
+			if profile, ok := v.linkProfile(fileSet.Tool, spec.Path); ok {
+				state.Linked = true
+				state.Profile = profile
+			}
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// Unlink replaces live auth paths that are symlinks into the vault with
+// regular copies of their targets, converting a symlink activation back to a
+// copy activation. It returns the number of paths converted.
+func (v *Vault) Unlink(fileSet AuthFileSet) (int, error) {
+	toolDir, err := v.safeToolDir(fileSet.Tool)
+	if err != nil {
+		return 0, err
+	}
+	return v.unlinkInto(fileSet, toolDir)
+}
+
+// unlinkInto materializes live symlinks whose targets are under dir.
+func (v *Vault) unlinkInto(fileSet AuthFileSet, dir string) (int, error) {
+	converted := 0
+	for _, spec := range fileSet.Files {
+		target, ok := readLink(spec.Path)
+		if !ok || !pathWithin(target, dir) {
+			continue
+		}
+		if _, err := os.Stat(target); err != nil {
+			// Broken link: nothing to copy, just drop it.
+			if err := os.Remove(spec.Path); err != nil {
+				return converted, err
+			}
+			converted++
+			continue
+		}
+		// loadFile renames over the link, replacing it rather than its target.
+		if err := v.loadFile(target, spec.Path); err != nil {
+			return converted, fmt.Errorf("copy %s: %w", target, err)
+		}
+		converted++
+	}
+	return converted, nil
+}
+
+// linkProfile returns the vault profile a live symlink points into.
+func (v *Vault) linkProfile(tool, path string) (string, bool) {
+	target, ok := readLink(path)
+	if !ok {
+		return "", false
+	}
+	toolDir, err := v.safeToolDir(tool)
+	if err != nil || !pathWithin(target, toolDir) {
+		return "", false
+	}
+	rel, err := filepath.Rel(toolDir, target)
+	if err != nil {
+		return "", false
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")[0], true
+}
+
+// place puts a vault file at a live auth path according to the activation
+// mode. Encrypted vaults and keychain storage are always copied: the tools
+// can't read a link to ciphertext or to a keychain stub.
+func (v *Vault) place(src, dst string) error {
+	if v.ActivationMode() != ActivationSymlink || v.Encrypted() || v.Storage().Name() == StorageKeychain || hasStub(src) {
+		return v.loadFile(src, dst)
+	}
+
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	// Create the link under a temp name and rename it into place so the live
+	// path is never missing.
+	tmp := dst + ".caam-link.tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(absSrc, tmp); err != nil {
+		return err
+	}
+	if err := faultinject.Check(faultinject.OpRename); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// readLink returns the absolute target of path if it is a symlink.
+func readLink(path string) (string, bool) {
+	info, err := os.Lstat(path)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", false
+	}
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", false
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	return filepath.Clean(target), true
+}
+
+// pathWithin reports whether path is dir or inside it.
+func pathWithin(path, dir string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
 // ActiveProfile returns which profile is currently active (if any).
 // It compares the current auth files with vault backups using content hashing.
 func (v *Vault) ActiveProfile(fileSet AuthFileSet) (string, error) {
@@ -647,7 +1171,7 @@ func (v *Vault) ActiveProfile(fileSet AuthFileSet) (string, error) {
 
 		for filename, currentHash := range currentHashes {
 			backupPath := filepath.Join(profileDir, filename)
-			backupHash, err := hashFile(backupPath)
+			backupHash, err := v.hashStored(backupPath)
 			if err != nil {
 				matches = false
 				break
@@ -683,6 +1207,44 @@ func HasAuthFiles(fileSet AuthFileSet) bool {
 	return false
 }
 
+// ClearPreview describes a live auth file ClearAuthFiles would remove.
+type ClearPreview struct {
+	Path     string `json:"path"`
+	Required bool   `json:"required"`
+
+	// Profiles are the vault profiles holding an identical copy; empty means
+	// the file exists nowhere else.
+	Profiles []string `json:"vault_profiles,omitempty"`
+}
+
+// PreviewClear lists the live auth files of fileSet that exist, each with
+// the vault profiles it could be recovered from.
+func (v *Vault) PreviewClear(fileSet AuthFileSet) ([]ClearPreview, error) {
+	profiles, err := v.List(fileSet.Tool)
+	if err != nil {
+		return nil, err
+	}
+
+	var previews []ClearPreview
+	for _, spec := range fileSet.Files {
+		if _, err := os.Lstat(spec.Path); err != nil {
+			continue
+		}
+		preview := ClearPreview{Path: spec.Path, Required: spec.Required}
+		if hash, err := hashFile(spec.Path); err == nil {
+			base := filepath.Base(spec.Path)
+			for _, profile := range profiles {
+				saved, err := v.hashStored(filepath.Join(v.ProfilePath(fileSet.Tool, profile), base))
+				if err == nil && saved == hash {
+					preview.Profiles = append(preview.Profiles, profile)
+				}
+			}
+		}
+		previews = append(previews, preview)
+	}
+	return previews, nil
+}
+
 // ClearAuthFiles removes all auth files for a tool (logout).
 func ClearAuthFiles(fileSet AuthFileSet) error {
 	for _, spec := range fileSet.Files {
@@ -695,7 +1257,18 @@ func ClearAuthFiles(fileSet AuthFileSet) error {
 
 // Helper functions
 
+// copyFile copies src to dst with the vault's durability.
+func (v *Vault) copyFile(src, dst string) error {
+	return copyFileDurable(src, dst, v.Durability() == DurabilitySafe)
+}
+
 func copyFile(src, dst string) error {
+	return copyFileDurable(src, dst, true)
+}
+
+// copyFileDurable atomically replaces dst with a copy of src. When durable,
+// the copy and the directory entry are fsynced before it returns.
+func copyFileDurable(src, dst string, durable bool) error {
 	// Ensure parent directory exists
 	dir := filepath.Dir(dst)
 	if err := os.MkdirAll(dir, 0700); err != nil {
@@ -724,6 +1297,10 @@ func copyFile(src, dst string) error {
 		dstFile.Close()
 		return err
 	}
+	if err := faultinject.Check(faultinject.OpCopy); err != nil {
+		dstFile.Close()
+		return err
+	}
 
 	// Enforce 0600 permissions for all auth files
 	if err := dstFile.Chmod(0600); err != nil {
@@ -731,9 +1308,11 @@ func copyFile(src, dst string) error {
 		return err
 	}
 
-	if err := dstFile.Sync(); err != nil {
-		dstFile.Close()
-		return err
+	if durable {
+		if err := dstFile.Sync(); err != nil {
+			dstFile.Close()
+			return err
+		}
 	}
 
 	if err := dstFile.Close(); err != nil {
@@ -741,7 +1320,30 @@ func copyFile(src, dst string) error {
 	}
 
 	// Atomic rename
-	return os.Rename(tmpPath, dst)
+	if err := faultinject.Check(faultinject.OpRename); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, dst); err != nil {
+		return err
+	}
+	if durable {
+		return syncDir(dir)
+	}
+	return nil
+}
+
+// syncDir fsyncs a directory so a rename into it survives a crash. Windows
+// can't fsync directories, and NTFS journals renames itself.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
 }
 
 func hashFile(path string) (string, error) {
@@ -759,6 +1361,11 @@ func hashFile(path string) (string, error) {
 	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
 func (v *Vault) safeToolDir(tool string) (string, error) {
 	if v == nil || strings.TrimSpace(v.basePath) == "" {
 		return "", fmt.Errorf("vault base path is empty")
@@ -816,15 +1423,16 @@ func validateVaultSegment(kind, val string) (string, error) {
 	if val == "." || val == ".." {
 		return "", fmt.Errorf("invalid %s: %q", kind, val)
 	}
-	// Only allow safe characters: alphanumeric, underscore, hyphen, period, and @.
-	// This prevents shell injection when profile names are used in shell scripts
-	// (e.g., claude.go's setupAPIKeyHelper embeds profile name in bash script).
-	// The @ and + characters are safe (no special shell meaning) and useful for email-based profile names.
-	// Also prevents filesystem issues and unexpected behavior.
+	// Only allow safe characters (see profileNameRune). This prevents shell
+	// injection when profile names are used in shell scripts, filesystem
+	// issues and unexpected behavior.
 	for _, r := range val {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
-			(r >= '0' && r <= '9') || r == '_' || r == '-' || r == '.' || r == '@' || r == '+') {
-			return "", fmt.Errorf("invalid %s: %q (only alphanumeric, underscore, hyphen, period, @, and + allowed)", kind, val)
+		if !profileNameRune(r) {
+			hint := ""
+			if kind == "profile" {
+				hint = profileNameSuggestion(val)
+			}
+			return "", fmt.Errorf("invalid %s: %q (only alphanumeric, underscore, hyphen, period, @, and + allowed)%s", kind, val, hint)
 		}
 	}
 	if filepath.IsAbs(val) || filepath.VolumeName(val) != "" {
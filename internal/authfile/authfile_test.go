@@ -3,9 +3,12 @@ package authfile
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewVault(t *testing.T) {
@@ -823,6 +826,26 @@ func TestVaultActiveProfile(t *testing.T) {
 	})
 }
 
+func TestRegisterAuthFileSet(t *testing.T) {
+	if _, ok := GetAuthFileSet("plugin-tool"); ok {
+		t.Fatal("unregistered tool should not resolve")
+	}
+	RegisterAuthFileSet("plugin-tool", func() AuthFileSet {
+		return AuthFileSet{Tool: "plugin-tool", Files: []AuthFileSpec{{Tool: "plugin-tool", Path: "/tmp/plugin-tool/auth.json", Required: true}}}
+	})
+
+	fileSet, ok := GetAuthFileSet("Plugin-Tool")
+	if !ok || fileSet.Tool != "plugin-tool" || len(fileSet.Files) != 1 {
+		t.Fatalf("GetAuthFileSet() = %+v, %v; want the registered set", fileSet, ok)
+	}
+
+	// Built-in tools cannot be replaced.
+	RegisterAuthFileSet("codex", func() AuthFileSet { return AuthFileSet{Tool: "impostor"} })
+	if fileSet, _ := GetAuthFileSet("codex"); fileSet.Tool != "codex" {
+		t.Errorf("GetAuthFileSet(codex).Tool = %q, want codex", fileSet.Tool)
+	}
+}
+
 func TestHasAuthFiles(t *testing.T) {
 	t.Run("returns true when required file exists", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -1224,6 +1247,40 @@ func TestVaultBackupCurrent(t *testing.T) {
 	})
 }
 
+func TestVaultStaleAutoBackups(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewVault(tmpDir)
+
+	for _, name := range []string{
+		"_backup_20251201_100000",
+		"_backup_20251202_100000",
+		"_backup_20251203_100000",
+		"work",
+	} {
+		if err := os.MkdirAll(v.ProfilePath("testtool", name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cutoff := time.Date(2025, 12, 2, 12, 0, 0, 0, time.Local)
+	stale, err := v.StaleAutoBackups("testtool", cutoff)
+	if err != nil {
+		t.Fatalf("StaleAutoBackups() error = %v", err)
+	}
+	if len(stale) != 2 || stale[0] != "_backup_20251201_100000" || stale[1] != "_backup_20251202_100000" {
+		t.Errorf("StaleAutoBackups() = %v, want the two before the cutoff", stale)
+	}
+
+	// The newest backup survives even when it is past the cutoff.
+	stale, err = v.StaleAutoBackups("testtool", time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local))
+	if err != nil {
+		t.Fatalf("StaleAutoBackups() error = %v", err)
+	}
+	if len(stale) != 2 {
+		t.Errorf("StaleAutoBackups() = %v, want the newest backup kept", stale)
+	}
+}
+
 func TestVaultRotateAutoBackups(t *testing.T) {
 	t.Run("deletes oldest when over limit", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -1361,3 +1418,764 @@ func TestVaultRotateAutoBackups(t *testing.T) {
 		}
 	})
 }
+
+func TestAuthFileSetAt(t *testing.T) {
+	dirs := AuthDirs{
+		Home:      "/profiles/claude/work/home",
+		XDGConfig: "/profiles/claude/work/xdg_config",
+		CodexHome: "/profiles/claude/work/codex_home",
+	}
+
+	tests := []struct {
+		tool     string
+		wantPath string
+	}{
+		{"codex", filepath.Join(dirs.CodexHome, "auth.json")},
+		{"claude", filepath.Join(dirs.Home, ".claude", ".credentials.json")},
+		{"gemini", filepath.Join(dirs.Home, ".gemini", "settings.json")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			set, ok := AuthFileSetAt(tt.tool, dirs)
+			if !ok {
+				t.Fatalf("AuthFileSetAt(%q) not found", tt.tool)
+			}
+			if set.Tool != tt.tool {
+				t.Errorf("Tool = %q, want %q", set.Tool, tt.tool)
+			}
+			if set.Files[0].Path != tt.wantPath {
+				t.Errorf("Files[0].Path = %q, want %q", set.Files[0].Path, tt.wantPath)
+			}
+			for _, spec := range set.Files {
+				if !strings.HasPrefix(spec.Path, "/profiles/") {
+					t.Errorf("path %q escapes the provided dirs", spec.Path)
+				}
+			}
+		})
+	}
+
+	if _, ok := AuthFileSetAt("unknown", dirs); ok {
+		t.Error("AuthFileSetAt(unknown) should not be found")
+	}
+}
+
+func TestParseActivationMode(t *testing.T) {
+	for input, want := range map[string]ActivationMode{"": ActivationCopy, "copy": ActivationCopy, " Symlink ": ActivationSymlink} {
+		got, err := ParseActivationMode(input)
+		if err != nil || got != want {
+			t.Errorf("ParseActivationMode(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseActivationMode("hardlink"); err == nil {
+		t.Error("ParseActivationMode(hardlink) should fail")
+	}
+}
+
+func TestParseDurability(t *testing.T) {
+	for input, want := range map[string]Durability{"": DurabilitySafe, "safe": DurabilitySafe, " FAST ": DurabilityFast} {
+		got, err := ParseDurability(input)
+		if err != nil || got != want {
+			t.Errorf("ParseDurability(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseDurability("paranoid"); err == nil {
+		t.Error("ParseDurability(paranoid) should fail")
+	}
+}
+
+func TestVaultBackupRestore_FastDurability(t *testing.T) {
+	tmpDir := t.TempDir()
+	authFile := filepath.Join(tmpDir, "auth", "auth.json")
+	if err := os.MkdirAll(filepath.Dir(authFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(authFile, []byte(`{"token":"fast"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := AuthFileSet{
+		Tool:  "testtool",
+		Files: []AuthFileSpec{{Tool: "testtool", Path: authFile, Required: true}},
+	}
+
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	if v.Durability() != DurabilitySafe {
+		t.Fatalf("default Durability() = %q, want safe", v.Durability())
+	}
+	v.SetDurability(DurabilityFast)
+	if err := v.Backup(fileSet, "fast"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if err := os.WriteFile(authFile, []byte(`{"token":"other"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Restore(fileSet, "fast"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	got, err := os.ReadFile(authFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != `{"token":"fast"}` {
+		t.Errorf("restored content = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(v.ProfilePath("testtool", "fast"), "meta.json")); err != nil {
+		t.Errorf("meta.json missing: %v", err)
+	}
+}
+
+func TestVaultRestore_SymlinkMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	v.SetActivationMode(ActivationSymlink)
+
+	authFile := filepath.Join(tmpDir, "auth", "auth.json")
+	fileSet := AuthFileSet{
+		Tool:  "testtool",
+		Files: []AuthFileSpec{{Tool: "testtool", Path: authFile, Required: true}},
+	}
+
+	for _, name := range []string{"a", "b"} {
+		dir := v.ProfilePath("testtool", name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"token":"`+name+`"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A pre-existing regular file is replaced by a link.
+	if err := os.MkdirAll(filepath.Dir(authFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(authFile, []byte(`{"token":"old"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Restore(fileSet, "a"); err != nil {
+		t.Fatalf("Restore(a) error = %v", err)
+	}
+	states := v.LinkStates(fileSet)
+	if len(states) != 1 || !states[0].Linked || states[0].Profile != "a" {
+		t.Fatalf("LinkStates() = %+v, want linked to a", states)
+	}
+	if active, _ := v.ActiveProfile(fileSet); active != "a" {
+		t.Errorf("ActiveProfile() = %q, want a", active)
+	}
+
+	// Writes through the link (e.g. a token refresh) land in the vault.
+	if err := os.WriteFile(authFile, []byte(`{"token":"refreshed"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(v.BackupPath("testtool", "a", "auth.json"))
+	if string(got) != `{"token":"refreshed"}` {
+		t.Errorf("vault file = %s, want refreshed token", got)
+	}
+
+	if err := v.Restore(fileSet, "b"); err != nil {
+		t.Fatalf("Restore(b) error = %v", err)
+	}
+	if states := v.LinkStates(fileSet); states[0].Profile != "b" {
+		t.Errorf("LinkStates() profile = %q, want b", states[0].Profile)
+	}
+
+	// Back to copy mode: the link becomes a regular file with the same contents.
+	n, err := v.Unlink(fileSet)
+	if err != nil || n != 1 {
+		t.Fatalf("Unlink() = %d, %v; want 1, nil", n, err)
+	}
+	info, err := os.Lstat(authFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		t.Error("auth file should no longer be a symlink")
+	}
+	got, _ = os.ReadFile(authFile)
+	if string(got) != `{"token":"b"}` {
+		t.Errorf("auth file = %s, want profile b contents", got)
+	}
+	if _, err := os.Stat(v.BackupPath("testtool", "b", "auth.json")); err != nil {
+		t.Errorf("vault file should be untouched, stat err = %v", err)
+	}
+}
+
+func TestVaultCopyRestoreReplacesLink(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+
+	dir := v.ProfilePath("testtool", "a")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	vaultFile := filepath.Join(dir, "auth.json")
+	if err := os.WriteFile(vaultFile, []byte(`{"token":"a"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	authFile := filepath.Join(tmpDir, "auth.json")
+	if err := os.Symlink(vaultFile, authFile); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := AuthFileSet{
+		Tool:  "testtool",
+		Files: []AuthFileSpec{{Tool: "testtool", Path: authFile, Required: true}},
+	}
+
+	if err := v.Restore(fileSet, "a"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	info, err := os.Lstat(authFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		t.Error("copy-mode restore should replace the link with a regular file")
+	}
+}
+
+func TestParseFileSchema_Anonymizes(t *testing.T) {
+	data := []byte(`{
+		"claudeAiOauth": {"accessToken": "secret", "expiresAt": 1700000000000, "scopes": ["user:inference"]},
+		"projects": {"/home/alice/src": {"allowedTools": []}},
+		"alice@example.com": true,
+		"oauth2Token": null
+	}`)
+	schema, err := ParseFileSchema(data)
+	if err != nil {
+		t.Fatalf("ParseFileSchema() error = %v", err)
+	}
+
+	want := []string{
+		"*:bool",
+		"claudeAiOauth.accessToken:string",
+		"claudeAiOauth.expiresAt:number",
+		"claudeAiOauth.scopes:array",
+		"claudeAiOauth.scopes[]:string",
+		"claudeAiOauth:object",
+		"oauth2Token:null",
+		"projects.*.allowedTools:array",
+		"projects.*:object",
+		"projects:object",
+	}
+	if strings.Join(schema.Keys, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Keys = %v, want %v", schema.Keys, want)
+	}
+	joined := strings.Join(schema.Keys, " ")
+	for _, leak := range []string{"secret", "alice", "/home"} {
+		if strings.Contains(joined, leak) {
+			t.Errorf("schema leaks %q: %v", leak, schema.Keys)
+		}
+	}
+
+	// Same structure, different values: same fingerprint.
+	other, _ := ParseFileSchema([]byte(`{"claudeAiOauth": {"accessToken": "x", "expiresAt": 1, "scopes": ["a", "b"]}, "projects": {"/tmp": {"allowedTools": []}}, "bob@example.com": false, "oauth2Token": null}`))
+	if other.Fingerprint != schema.Fingerprint {
+		t.Errorf("fingerprints differ for the same structure: %s vs %s", other.Fingerprint, schema.Fingerprint)
+	}
+}
+
+func TestMissingSchemaKeys(t *testing.T) {
+	current, _ := ParseFileSchema([]byte(`{"claudeAiOauth": {"accessToken": "a", "refreshToken": "r", "expiresAt": 1}}`))
+	if missing := MissingSchemaKeys("claude", ".credentials.json", current); len(missing) != 0 {
+		t.Errorf("MissingSchemaKeys(current format) = %v, want none", missing)
+	}
+
+	// Flat legacy format satisfies the alternatives.
+	legacy, _ := ParseFileSchema([]byte(`{"access_token": "a", "refresh_token": "r", "expires_at": "2026-01-01T00:00:00Z"}`))
+	if missing := MissingSchemaKeys("claude", ".credentials.json", legacy); len(missing) != 0 {
+		t.Errorf("MissingSchemaKeys(legacy format) = %v, want none", missing)
+	}
+
+	changed, _ := ParseFileSchema([]byte(`{"claudeAiOauth": {"accessToken": "a", "refreshToken": "r", "expiry": 1}}`))
+	missing := MissingSchemaKeys("claude", ".credentials.json", changed)
+	if len(missing) != 1 || missing[0] != "claudeAiOauth.expiresAt" {
+		t.Errorf("MissingSchemaKeys(changed format) = %v, want [claudeAiOauth.expiresAt]", missing)
+	}
+
+	added, removed := DiffSchemas(current, changed)
+	if len(added) != 1 || added[0] != "claudeAiOauth.expiry:number" {
+		t.Errorf("DiffSchemas added = %v", added)
+	}
+	if len(removed) != 1 || removed[0] != "claudeAiOauth.expiresAt:number" {
+		t.Errorf("DiffSchemas removed = %v", removed)
+	}
+
+	if missing := MissingSchemaKeys("claude", ".claude.json", changed); len(missing) != 0 {
+		t.Errorf("files caam doesn't parse have no expectations, got %v", missing)
+	}
+}
+
+func TestVaultBackedUpAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	authFile := filepath.Join(tmpDir, "auth.json")
+	if err := os.WriteFile(authFile, []byte(`{"tokens": {}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	fileSet := AuthFileSet{Tool: "codex", Files: []AuthFileSpec{{Tool: "codex", Path: authFile, Required: true}}}
+
+	if got, err := v.BackedUpAt("codex", "work"); err != nil || !got.IsZero() {
+		t.Fatalf("BackedUpAt() before backup = %v, %v; want zero time", got, err)
+	}
+	before := time.Now().Add(-time.Second)
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	got, err := v.BackedUpAt("codex", "work")
+	if err != nil {
+		t.Fatalf("BackedUpAt() error = %v", err)
+	}
+	if got.Before(before) || got.After(time.Now()) {
+		t.Errorf("BackedUpAt() = %v, want about now", got)
+	}
+}
+
+func TestVaultBackup_RecordsSchemas(t *testing.T) {
+	tmpDir := t.TempDir()
+	authFile := filepath.Join(tmpDir, "auth.json")
+	if err := os.WriteFile(authFile, []byte(`{"tokens": {"access_token": "secret"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	envFile := filepath.Join(tmpDir, ".env")
+	if err := os.WriteFile(envFile, []byte("KEY=secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	fileSet := AuthFileSet{
+		Tool: "codex",
+		Files: []AuthFileSpec{
+			{Tool: "codex", Path: authFile, Required: true},
+			{Tool: "codex", Path: envFile},
+		},
+	}
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	schemas, err := v.RecordedSchemas("codex", "work")
+	if err != nil {
+		t.Fatalf("RecordedSchemas() error = %v", err)
+	}
+	if len(schemas) != 1 {
+		t.Fatalf("RecordedSchemas() = %v, want only auth.json", schemas)
+	}
+	if !schemas["auth.json"].Has("tokens.access_token") {
+		t.Errorf("auth.json schema = %v", schemas["auth.json"].Keys)
+	}
+	if missing := MissingSchemaKeys("codex", "auth.json", schemas["auth.json"]); len(missing) != 1 || missing[0] != "tokens.refresh_token" {
+		t.Errorf("MissingSchemaKeys() = %v, want [tokens.refresh_token]", missing)
+	}
+}
+
+func TestVaultBackup_Categories(t *testing.T) {
+	tmpDir := t.TempDir()
+	home := filepath.Join(tmpDir, "home")
+	fileSet := claudeAuthFilesIn(home, filepath.Join(home, ".config"))
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	credentials := filepath.Join(home, ".claude", ".credentials.json")
+	history := filepath.Join(home, ".claude.json")
+	settings := filepath.Join(home, ".claude", "settings.json")
+	write(credentials, `{"claudeAiOauth":{}}`)
+	write(history, `{"projects":"old"}`)
+	write(settings, `{"theme":"dark"}`)
+
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("full Backup() error = %v", err)
+	}
+
+	categories, err := ParseFileCategories([]string{"credentials,settings"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileSet.Categories = categories
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup(--include settings) error = %v", err)
+	}
+	profileDir := v.ProfilePath("claude", "work")
+	if _, err := os.Stat(filepath.Join(profileDir, ".claude.json")); !os.IsNotExist(err) {
+		t.Errorf("history from the earlier full backup should be dropped, stat err = %v", err)
+	}
+	if got, _ := v.RecordedCategories("claude", "work"); strings.Join(got, ",") != "credentials,settings" {
+		t.Errorf("RecordedCategories() = %v", got)
+	}
+
+	// Restore (with a plain file set, as activate uses) keeps live history.
+	write(history, `{"projects":"live"}`)
+	write(settings, `{"theme":"light"}`)
+	if err := v.Restore(claudeAuthFilesIn(home, filepath.Join(home, ".config")), "work"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if data, _ := os.ReadFile(history); string(data) != `{"projects":"live"}` {
+		t.Errorf("history = %s, want live contents kept", data)
+	}
+	if data, _ := os.ReadFile(settings); string(data) != `{"theme":"dark"}` {
+		t.Errorf("settings = %s, want restored from the vault", data)
+	}
+
+	if _, err := ParseFileCategories([]string{"tokens"}); err == nil {
+		t.Error("ParseFileCategories() should reject unknown categories")
+	}
+}
+
+func TestMergeTOMLOverrides(t *testing.T) {
+	data := []byte(`# local settings
+model = "gpt-5"
+sandbox_mode = "workspace-write"
+trusted = [
+  "a",
+  "b",
+]
+
+[mcp_servers.docs]
+model = "keep-me"
+`)
+	keys := []string{"model", "trusted", "approval_policy"}
+	overrides := map[string]string{
+		"model":           `"o3"`,
+		"trusted":         `["c"]`,
+		"approval_policy": `"never"`,
+	}
+
+	got := string(mergeTOMLOverrides(data, keys, overrides))
+	want := `# local settings
+model = "o3"
+sandbox_mode = "workspace-write"
+trusted = ["c"]
+
+approval_policy = "never"
+[mcp_servers.docs]
+model = "keep-me"
+`
+	if got != want {
+		t.Errorf("mergeTOMLOverrides() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := string(mergeTOMLOverrides(nil, []string{"model"}, map[string]string{"model": `"o3"`})); got != "model = \"o3\"\n" {
+		t.Errorf("mergeTOMLOverrides(empty) = %q", got)
+	}
+}
+
+func TestVaultConfigPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	codexHome := filepath.Join(tmpDir, "codex")
+	if err := os.MkdirAll(codexHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := codexAuthFilesIn(codexHome)
+	authPath := filepath.Join(codexHome, "auth.json")
+	configPath := filepath.Join(codexHome, "config.toml")
+	if err := os.WriteFile(authPath, []byte(`{"tokens": {}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("model = \"work-model\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	if v.ConfigPolicy() != ConfigPreserve {
+		t.Fatalf("default ConfigPolicy() = %q, want preserve", v.ConfigPolicy())
+	}
+
+	// preserve: config.toml stays out of the vault and survives activation.
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(v.ProfilePath("codex", "work"), "config.toml")); !os.IsNotExist(err) {
+		t.Fatalf("preserve policy backed up config.toml (stat err = %v)", err)
+	}
+	if err := v.SetConfigOverrides("codex", "work", "config.toml", map[string]string{"approval_policy": `"never"`}); err != nil {
+		t.Fatalf("SetConfigOverrides() error = %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("model = \"local\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Restore(fileSet, "work"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	got, _ := os.ReadFile(configPath)
+	if string(got) != "model = \"local\"\napproval_policy = \"never\"\n" {
+		t.Errorf("config.toml after preserve restore = %q", got)
+	}
+
+	// profile: config.toml is backed up and restored, then overrides merged.
+	v.SetConfigPolicy(ConfigProfile)
+	if err := os.WriteFile(configPath, []byte("model = \"work-model\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("model = \"other\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Restore(fileSet, "work"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	got, _ = os.ReadFile(configPath)
+	if string(got) != "model = \"work-model\"\napproval_policy = \"never\"\n" {
+		t.Errorf("config.toml after profile restore = %q", got)
+	}
+
+	overrides, err := v.ConfigOverrides("codex", "work", "config.toml")
+	if err != nil || overrides["approval_policy"] != `"never"` {
+		t.Errorf("ConfigOverrides() = %v, %v", overrides, err)
+	}
+	if err := v.SetConfigOverrides("codex", "work", "config.toml", map[string]string{"a.b": "1"}); err == nil {
+		t.Error("SetConfigOverrides() accepted a dotted key")
+	}
+	if err := v.SetConfigOverrides("codex", "work", "config.toml", nil); err != nil {
+		t.Fatalf("SetConfigOverrides(nil) error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(v.ProfilePath("codex", "work"), "config.overrides.toml")); !os.IsNotExist(err) {
+		t.Errorf("overrides file not removed (stat err = %v)", err)
+	}
+}
+
+func TestVaultSharedProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	authFile := filepath.Join(tmpDir, "auth", "auth.json")
+	fileSet := AuthFileSet{
+		Tool:  "testtool",
+		Files: []AuthFileSpec{{Tool: "testtool", Path: authFile, Required: true}},
+	}
+	writeAuth := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(authFile), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(authFile, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	shared := NewVault(filepath.Join(tmpDir, "shared"))
+	shared.SetShareable(true)
+	writeAuth(`{"token":"team"}`)
+	if err := shared.Backup(fileSet, "team"); err != nil {
+		t.Fatalf("shared Backup() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Join(tmpDir, "shared", "testtool", "team", "auth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0640 {
+		t.Errorf("shared file mode = %v, want 0640", info.Mode().Perm())
+	}
+
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	v.SetSharedPath(shared.BasePath())
+	v.SetActivationMode(ActivationSymlink)
+	writeAuth(`{"token":"mine"}`)
+	if err := v.Backup(fileSet, "mine"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	profiles, err := v.List("testtool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(profiles, ",") != "mine,team" {
+		t.Errorf("List() = %v, want [mine team]", profiles)
+	}
+	all, err := v.ListAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all["testtool"]) != 2 {
+		t.Errorf("ListAll()[testtool] = %v, want 2 profiles", all["testtool"])
+	}
+	if !v.IsShared("testtool", "team") || v.IsShared("testtool", "mine") {
+		t.Error("IsShared() should be true only for the shared profile")
+	}
+	if got := v.ProfilePath("testtool", "team"); got != filepath.Join(shared.BasePath(), "testtool", "team") {
+		t.Errorf("ProfilePath() = %q, want the shared profile dir", got)
+	}
+
+	// Shared profiles are always copied, even in symlink mode.
+	if err := v.Restore(fileSet, "team"); err != nil {
+		t.Fatalf("Restore(shared) error = %v", err)
+	}
+	if fi, err := os.Lstat(authFile); err != nil || fi.Mode()&os.ModeSymlink != 0 {
+		t.Fatalf("restored shared auth file should be a regular file (err=%v)", err)
+	}
+	if active, _ := v.ActiveProfile(fileSet); active != "team" {
+		t.Errorf("ActiveProfile() = %q, want team", active)
+	}
+
+	if err := v.Backup(fileSet, "team"); err == nil {
+		t.Error("Backup() over a shared profile should fail")
+	}
+	if err := v.Delete("testtool", "team"); err == nil {
+		t.Error("Delete() of a shared profile should fail")
+	}
+	if err := v.SetConfigOverrides("testtool", "team", "config.toml", map[string]string{"model": `"x"`}); err == nil {
+		t.Error("SetConfigOverrides() on a shared profile should fail")
+	}
+	if _, err := os.Stat(filepath.Join(shared.BasePath(), "testtool", "team", "auth.json")); err != nil {
+		t.Errorf("shared profile should be untouched: %v", err)
+	}
+
+	// A vault without a shared path sees only its own profiles.
+	v.SetSharedPath("")
+	profiles, _ = v.List("testtool")
+	if strings.Join(profiles, ",") != "mine" {
+		t.Errorf("List() without shared vault = %v, want [mine]", profiles)
+	}
+}
+
+func TestDefaultSharedVaultPath(t *testing.T) {
+	t.Setenv("CAAM_SHARED_VAULT", "/srv/caam/vault")
+	if got := DefaultSharedVaultPath(); got != "/srv/caam/vault" {
+		t.Errorf("DefaultSharedVaultPath() = %q, want /srv/caam/vault", got)
+	}
+	t.Setenv("CAAM_SHARED_VAULT", "")
+	if got := DefaultSharedVaultPath(); got != filepath.Join(string(filepath.Separator), "usr", "local", "share", "caam", "vault") {
+		t.Errorf("DefaultSharedVaultPath() = %q, want /usr/local/share/caam/vault", got)
+	}
+}
+
+func TestVaultRecordMachine(t *testing.T) {
+	v := NewVault(t.TempDir())
+	if err := os.MkdirAll(v.ProfilePath("claude", "work"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	first := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	if err := v.RecordMachine("claude", "work", "m2", "desk", first.Add(time.Hour)); err != nil {
+		t.Fatalf("RecordMachine() error = %v", err)
+	}
+	if err := v.RecordMachine("claude", "work", "m1", "laptop", first); err != nil {
+		t.Fatalf("RecordMachine() error = %v", err)
+	}
+	if err := v.RecordMachine("claude", "work", "m2", "", first.Add(2*time.Hour)); err != nil {
+		t.Fatalf("RecordMachine() error = %v", err)
+	}
+
+	records, err := v.Machines("claude", "work")
+	if err != nil {
+		t.Fatalf("Machines() error = %v", err)
+	}
+	if len(records) != 2 || records[0].ID != "m1" || records[1].ID != "m2" {
+		t.Fatalf("Machines() = %+v, want m1 then m2", records)
+	}
+	if records[1].Hostname != "desk" || !records[1].LastSeen.Equal(first.Add(2*time.Hour)) {
+		t.Errorf("m2 = %+v, want hostname kept and last seen updated", records[1])
+	}
+
+	if err := v.RecordMachine("claude", "missing", "m1", "laptop", first); err == nil {
+		t.Error("RecordMachine() on a missing profile should fail")
+	}
+	if records, err := v.Machines("codex", "none"); err != nil || records != nil {
+		t.Errorf("Machines() for an unrecorded profile = %v, %v; want nil", records, err)
+	}
+}
+
+func TestVaultActivate(t *testing.T) {
+	setup := func(t *testing.T) (*Vault, AuthFileSet, string) {
+		t.Helper()
+		tmpDir := t.TempDir()
+		authFile := filepath.Join(tmpDir, "auth", "auth.json")
+		if err := os.MkdirAll(filepath.Dir(authFile), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(authFile, []byte(`{"token":"live"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		v := NewVault(filepath.Join(tmpDir, "vault"))
+		if err := os.MkdirAll(v.ProfilePath("testtool", "target"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(v.BackupPath("testtool", "target", "auth.json"), []byte(`{"token":"target"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		fileSet := AuthFileSet{
+			Tool:  "testtool",
+			Files: []AuthFileSpec{{Tool: "testtool", Path: authFile, Required: true}},
+		}
+		return v, fileSet, authFile
+	}
+
+	t.Run("first activation keeps the original login", func(t *testing.T) {
+		v, fileSet, authFile := setup(t)
+
+		safety, err := v.Activate(fileSet, "target", ActivateOptions{})
+		if err != nil {
+			t.Fatalf("Activate() error = %v", err)
+		}
+		if safety.OriginalBackup != "_original" || safety.BackupMode != BackupSmart || safety.AutoBackup != "" {
+			t.Errorf("safety = %+v, want _original backup only", safety)
+		}
+		if got, _ := os.ReadFile(authFile); string(got) != `{"token":"target"}` {
+			t.Errorf("live auth = %q, want target", got)
+		}
+	})
+
+	t.Run("unsaved login is auto-backed up", func(t *testing.T) {
+		v, fileSet, _ := setup(t)
+		if err := os.MkdirAll(v.ProfilePath("testtool", "_original"), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		var order []string
+		safety, err := v.Activate(fileSet, "target", ActivateOptions{
+			BeforeRestore: func() error {
+				order = append(order, "before")
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("Activate() error = %v", err)
+		}
+		if safety.OriginalBackup != "" || !strings.HasPrefix(safety.AutoBackup, "_backup_") {
+			t.Errorf("safety = %+v, want an auto-backup", safety)
+		}
+		if got, _ := os.ReadFile(v.BackupPath("testtool", safety.AutoBackup, "auth.json")); string(got) != `{"token":"live"}` {
+			t.Errorf("auto-backup = %q, want the live login", got)
+		}
+		if len(order) != 1 {
+			t.Errorf("BeforeRestore ran %d times, want 1", len(order))
+		}
+	})
+
+	t.Run("never skips the auto-backup", func(t *testing.T) {
+		v, fileSet, _ := setup(t)
+		if err := os.MkdirAll(v.ProfilePath("testtool", "_original"), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		safety, err := v.Activate(fileSet, "target", ActivateOptions{BackupMode: BackupNever})
+		if err != nil {
+			t.Fatalf("Activate() error = %v", err)
+		}
+		if safety.AutoBackup != "" || safety.BackupMode != BackupNever {
+			t.Errorf("safety = %+v, want no backup", safety)
+		}
+	})
+
+	t.Run("BeforeRestore error aborts the switch", func(t *testing.T) {
+		v, fileSet, authFile := setup(t)
+
+		wantErr := errors.New("cancelled")
+		safety, err := v.Activate(fileSet, "target", ActivateOptions{BeforeRestore: func() error { return wantErr }})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Activate() error = %v, want %v", err, wantErr)
+		}
+		if safety == nil || safety.OriginalBackup != "_original" {
+			t.Errorf("safety = %+v, want the backup made before the abort", safety)
+		}
+		if got, _ := os.ReadFile(authFile); string(got) != `{"token":"live"}` {
+			t.Errorf("live auth = %q, want it untouched", got)
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package authfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// File categories group a tool's auth files so a backup can leave out the
+// heavyweight optional ones. A spec without a category is a credential.
+const (
+	CategoryCredentials = "credentials"
+	CategorySettings    = "settings"
+	CategoryHistory     = "history"
+)
+
+// FileCategories lists the known categories.
+var FileCategories = []string{CategoryCredentials, CategorySettings, CategoryHistory}
+
+// ParseFileCategories parses a comma-separated category list, returning the
+// known categories in canonical order without duplicates.
+func ParseFileCategories(list []string) ([]string, error) {
+	seen := make(map[string]bool)
+	for _, item := range list {
+		for _, name := range strings.Split(item, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if !slices.Contains(FileCategories, name) {
+				return nil, fmt.Errorf("unknown file category %q (use %s)", name, strings.Join(FileCategories, ", "))
+			}
+			seen[name] = true
+		}
+	}
+	var out []string
+	for _, c := range FileCategories {
+		if seen[c] {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// FileCategory returns the spec's category.
+func (s AuthFileSpec) FileCategory() string {
+	if s.Category == "" {
+		return CategoryCredentials
+	}
+	return s.Category
+}
+
+// includes reports whether spec is captured under categories. Required files
+// always are: a profile is useless without them. No categories means all.
+func includes(categories []string, spec AuthFileSpec) bool {
+	return len(categories) == 0 || spec.Required || slices.Contains(categories, spec.FileCategory())
+}
+
+// recordedCategories returns the categories a profile was backed up with,
+// or nil for a full backup (or one made before categories were recorded).
+func recordedCategories(profileDir string) []string {
+	raw, err := os.ReadFile(filepath.Join(profileDir, "meta.json"))
+	if err != nil {
+		return nil
+	}
+	var meta struct {
+		Categories []string `json:"categories"`
+	}
+	if json.Unmarshal(raw, &meta) != nil {
+		return nil
+	}
+	return meta.Categories
+}
+
+// RecordedCategories returns the file categories a profile was backed up
+// with; nil means every file was captured.
+func (v *Vault) RecordedCategories(tool, profile string) ([]string, error) {
+	profileDir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return nil, err
+	}
+	return recordedCategories(profileDir), nil
+}
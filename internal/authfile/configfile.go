@@ -0,0 +1,339 @@
+package authfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ConfigFileSpec describes a tool's non-credential config file that lives
+// next to its auth files (e.g. Codex's config.toml). Config files are handled
+// only by Backup and Restore, following the vault's ConfigPolicy, and a
+// profile can carry overrides that Restore merges into the live file.
+type ConfigFileSpec struct {
+	Tool        string
+	Path        string
+	Description string
+}
+
+// ConfigPolicy controls what Backup and Restore do with config files.
+type ConfigPolicy string
+
+const (
+	// ConfigPreserve keeps the local config file when activating a profile
+	// (default); only the profile's overrides are merged in.
+	ConfigPreserve ConfigPolicy = "preserve"
+
+	// ConfigProfile backs the config file up with each profile and restores
+	// it on activation, before the overrides are merged.
+	ConfigProfile ConfigPolicy = "profile"
+)
+
+// ParseConfigPolicy parses a config policy name. Empty means preserve.
+func ParseConfigPolicy(s string) (ConfigPolicy, error) {
+	switch ConfigPolicy(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ConfigPreserve:
+		return ConfigPreserve, nil
+	case ConfigProfile:
+		return ConfigProfile, nil
+	default:
+		return "", fmt.Errorf("unknown config policy %q (supported: preserve, profile)", s)
+	}
+}
+
+// SetConfigPolicy sets how Backup and Restore treat config files.
+func (v *Vault) SetConfigPolicy(policy ConfigPolicy) {
+	v.configPolicy = policy
+}
+
+// ConfigPolicy returns how Backup and Restore treat config files.
+func (v *Vault) ConfigPolicy() ConfigPolicy {
+	if v.configPolicy == "" {
+		return ConfigPreserve
+	}
+	return v.configPolicy
+}
+
+// OverridesFileName returns the vault file holding a profile's overrides for
+// a config file: config.toml -> config.overrides.toml.
+func OverridesFileName(configFile string) string {
+	ext := filepath.Ext(configFile)
+	return strings.TrimSuffix(configFile, ext) + ".overrides" + ext
+}
+
+// backupConfigFiles copies fileSet's config files into profileDir under the
+// profile policy.
+func (v *Vault) backupConfigFiles(fileSet AuthFileSet, profileDir string) error {
+	if v.ConfigPolicy() != ConfigProfile {
+		return nil
+	}
+	for _, spec := range fileSet.ConfigFiles {
+		if _, err := os.Stat(spec.Path); err != nil {
+			continue
+		}
+		if err := v.storeFile(spec.Path, filepath.Join(profileDir, filepath.Base(spec.Path))); err != nil {
+			return fmt.Errorf("backup %s: %w", spec.Path, err)
+		}
+	}
+	return nil
+}
+
+// restoreConfigFiles restores fileSet's config files from profileDir under
+// the profile policy, then merges the profile's overrides.
+func (v *Vault) restoreConfigFiles(fileSet AuthFileSet, profileDir string) error {
+	for _, spec := range fileSet.ConfigFiles {
+		name := filepath.Base(spec.Path)
+		if v.ConfigPolicy() == ConfigProfile {
+			src := filepath.Join(profileDir, name)
+			if _, err := os.Stat(src); err == nil {
+				if err := v.loadFile(src, spec.Path); err != nil {
+					return fmt.Errorf("restore %s: %w", spec.Path, err)
+				}
+			}
+		}
+
+		keys, overrides, err := readTOMLOverrides(filepath.Join(profileDir, OverridesFileName(name)))
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		data, err := os.ReadFile(spec.Path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("read %s: %w", spec.Path, err)
+		}
+		if err := v.writeFileAtomic(spec.Path, mergeTOMLOverrides(data, keys, overrides)); err != nil {
+			return fmt.Errorf("merge overrides into %s: %w", spec.Path, err)
+		}
+	}
+	return nil
+}
+
+// ConfigOverrides returns a profile's overrides for one of the tool's config
+// files as raw TOML values keyed by top-level key.
+func (v *Vault) ConfigOverrides(tool, profile, configFile string) (map[string]string, error) {
+	profileDir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return nil, err
+	}
+	_, overrides, err := readTOMLOverrides(filepath.Join(profileDir, OverridesFileName(configFile)))
+	return overrides, err
+}
+
+// SetConfigOverrides replaces a profile's overrides for a config file. Values
+// are raw TOML values; an empty map removes the overrides file.
+func (v *Vault) SetConfigOverrides(tool, profile, configFile string, overrides map[string]string) error {
+	profileDir, err := v.safeProfileDir(tool, profile)
+	if err != nil {
+		return err
+	}
+	if err := v.checkWritable(tool, profile); err != nil {
+		return err
+	}
+	if _, err := os.Stat(profileDir); err != nil {
+		return fmt.Errorf("profile %s/%s not found in vault", tool, profile)
+	}
+	path := filepath.Join(profileDir, OverridesFileName(configFile))
+
+	if len(overrides) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		if !tomlBareKey.MatchString(key) {
+			return fmt.Errorf("invalid config key %q (top-level bare keys only)", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# caam overrides merged into %s when %s/%s is activated\n", configFile, tool, profile)
+	for _, key := range keys {
+		fmt.Fprintf(&buf, "%s = %s\n", key, overrides[key])
+	}
+	if err := v.writeFileAtomic(path, buf.Bytes()); err != nil {
+		return err
+	}
+	if v.shareable {
+		return v.shareProfile(profileDir)
+	}
+	return nil
+}
+
+// tomlBareKey matches the top-level keys overrides may set.
+var tomlBareKey = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// tomlAssignment matches a "key = value" line, capturing key and value.
+var tomlAssignment = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)\s*=\s*(.*?)\s*$`)
+
+// readTOMLOverrides reads the "key = value" lines of an overrides file, in
+// file order. A missing file has no overrides.
+func readTOMLOverrides(path string) ([]string, map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("read overrides: %w", err)
+	}
+
+	var keys []string
+	overrides := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := tomlAssignment.FindStringSubmatch(line)
+		if m == nil {
+			return nil, nil, fmt.Errorf("%s: unsupported line %q (top-level key = value only)", path, line)
+		}
+		if _, seen := overrides[m[1]]; !seen {
+			keys = append(keys, m[1])
+		}
+		overrides[m[1]] = m[2]
+	}
+	return keys, overrides, scanner.Err()
+}
+
+// mergeTOMLOverrides sets each top-level key of overrides in the TOML
+// document data. Existing top-level assignments are replaced in place (a
+// multi-line array value is replaced whole); new keys go before the first
+// table header, since TOML reads keys after a header as part of that table.
+func mergeTOMLOverrides(data []byte, keys []string, overrides map[string]string) []byte {
+	lines := strings.Split(string(data), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	done := make(map[string]bool, len(keys))
+
+	var out []string
+	topLevel := true
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if topLevel && strings.HasPrefix(trimmed, "[") {
+			topLevel = false
+			out = append(out, pendingAssignments(keys, overrides, done)...)
+		}
+		if topLevel {
+			if m := tomlAssignment.FindStringSubmatch(line); m != nil {
+				if value, ok := overrides[m[1]]; ok {
+					// Skip the continuation lines of a multi-line array.
+					for depth := bracketDepth(m[2]); depth > 0 && i+1 < len(lines); {
+						i++
+						depth += bracketDepth(lines[i])
+					}
+					if !done[m[1]] {
+						out = append(out, m[1]+" = "+value)
+						done[m[1]] = true
+					}
+					continue
+				}
+			}
+		}
+		out = append(out, line)
+	}
+	if topLevel {
+		// No table headers: append, keeping a single trailing newline.
+		for len(out) > 0 && out[len(out)-1] == "" {
+			out = out[:len(out)-1]
+		}
+		out = append(out, pendingAssignments(keys, overrides, done)...)
+		out = append(out, "")
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+func pendingAssignments(keys []string, overrides map[string]string, done map[string]bool) []string {
+	var lines []string
+	for _, key := range keys {
+		if !done[key] {
+			lines = append(lines, key+" = "+overrides[key])
+			done[key] = true
+		}
+	}
+	return lines
+}
+
+// bracketDepth returns the net count of '[' over ']' outside strings.
+func bracketDepth(s string) int {
+	depth := 0
+	var quote rune
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return depth
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		}
+	}
+	return depth
+}
+
+// writeFileAtomic writes data to path with 0600 permissions via a temp file
+// and rename.
+// writeFileAtomic writes data to path with the vault's durability.
+func (v *Vault) writeFileAtomic(path string, data []byte) error {
+	return writeFileAtomicDurable(path, data, v.Durability() == DurabilitySafe)
+}
+
+// writeFileAtomicDurable atomically replaces path with data. When durable,
+// the file and the directory entry are fsynced before it returns.
+func writeFileAtomicDurable(path string, data []byte, durable bool) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp.*")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if durable {
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	if durable {
+		return syncDir(dir)
+	}
+	return nil
+}
@@ -0,0 +1,476 @@
+package authfile
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// An encrypted vault keeps every auth and config file it stores sealed with
+// AES-256-GCM under a key derived from a passphrase with Argon2id. The KDF
+// parameters and a sealed check value live in EncryptionFile at the vault
+// root; meta.json and other bookkeeping files stay readable so profiles can
+// be listed without the key.
+//
+// A sealed file is sealedMagic, a 12-byte nonce and the GCM ciphertext, and
+// keeps its plaintext name. Files without the magic are read as they are, so
+// a vault can hold a mix while it is being migrated.
+
+// EncryptionFile is the file at the vault root that marks it encrypted.
+const EncryptionFile = ".encryption.json"
+
+// ErrVaultLocked is returned when an encrypted vault's files are needed but
+// no key is available.
+var ErrVaultLocked = errors.New("vault is encrypted and no key is available")
+
+var sealedMagic = []byte("CAAMENC1")
+
+// keyCheck is sealed with the vault key so a wrong passphrase is detected
+// before anything is decrypted with it.
+const keyCheck = "caam vault key check"
+
+const vaultNonceSize = 12
+
+type vaultEncryption struct {
+	Version   int    `json:"version"`
+	Algorithm string `json:"algorithm"`
+	KDF       string `json:"kdf"`
+	Salt      string `json:"salt"`
+	Time      uint32 `json:"time"`
+	Memory    uint32 `json:"memory"`
+	Threads   uint8  `json:"threads"`
+	Check     string `json:"check"`
+}
+
+// KeySource supplies the key of an encrypted vault, typically from the OS
+// keyring or by deriving it from a passphrase with DeriveKey.
+type KeySource func(v *Vault) ([]byte, error)
+
+var (
+	keySourceMu      sync.Mutex
+	defaultKeySource KeySource
+)
+
+// SetDefaultKeySource sets how vaults without a key obtain one when they first
+// need it. caam installs a source that tries the keyring, then prompts.
+func SetDefaultKeySource(source KeySource) {
+	keySourceMu.Lock()
+	defer keySourceMu.Unlock()
+	defaultKeySource = source
+}
+
+// Encrypted reports whether the vault stores its files encrypted.
+func (v *Vault) Encrypted() bool {
+	_, err := os.Stat(filepath.Join(v.basePath, EncryptionFile))
+	return err == nil
+}
+
+func (v *Vault) readEncryption() (*vaultEncryption, error) {
+	raw, err := os.ReadFile(filepath.Join(v.basePath, EncryptionFile))
+	if err != nil {
+		return nil, err
+	}
+	var enc vaultEncryption
+	if err := json.Unmarshal(raw, &enc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", EncryptionFile, err)
+	}
+	if enc.Version != 1 || enc.KDF != "argon2id" || enc.Algorithm != "aes-256-gcm" {
+		return nil, fmt.Errorf("unsupported vault encryption (version %d, %s, %s)", enc.Version, enc.KDF, enc.Algorithm)
+	}
+	return &enc, nil
+}
+
+// DeriveKey derives the vault key from passphrase using the vault's salt. It
+// does not check the key; Unlock does.
+func (v *Vault) DeriveKey(passphrase string) ([]byte, error) {
+	enc, err := v.readEncryption()
+	if err != nil {
+		return nil, err
+	}
+	salt, err := base64.StdEncoding.DecodeString(enc.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decode salt: %w", err)
+	}
+	return argon2.IDKey([]byte(passphrase), salt, enc.Time, enc.Memory, enc.Threads, 32), nil
+}
+
+// Unlock makes key the vault's key after checking it against the vault.
+func (v *Vault) Unlock(key []byte) error {
+	enc, err := v.readEncryption()
+	if err != nil {
+		return err
+	}
+	check, err := base64.StdEncoding.DecodeString(enc.Check)
+	if err != nil {
+		return fmt.Errorf("decode key check: %w", err)
+	}
+	plain, err := openSealed(key, check)
+	if err != nil || string(plain) != keyCheck {
+		return fmt.Errorf("wrong vault passphrase or key")
+	}
+	v.key = append([]byte(nil), key...)
+	return nil
+}
+
+// Lock forgets the vault's key.
+func (v *Vault) Lock() {
+	clear(v.key)
+	v.key = nil
+}
+
+// EnableEncryption marks the vault encrypted under passphrase and unlocks it,
+// returning the key. Files already in the vault stay plaintext until
+// EncryptAll seals them.
+func (v *Vault) EnableEncryption(passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("passphrase is required")
+	}
+	if v.Encrypted() {
+		return nil, fmt.Errorf("vault %s is already encrypted", v.basePath)
+	}
+	salt := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	enc := vaultEncryption{
+		Version:   1,
+		Algorithm: "aes-256-gcm",
+		KDF:       "argon2id",
+		Salt:      base64.StdEncoding.EncodeToString(salt),
+		Time:      3,
+		Memory:    64 * 1024,
+		Threads:   4,
+	}
+	key := argon2.IDKey([]byte(passphrase), salt, enc.Time, enc.Memory, enc.Threads, 32)
+	check, err := seal(key, []byte(keyCheck))
+	if err != nil {
+		return nil, err
+	}
+	enc.Check = base64.StdEncoding.EncodeToString(check)
+
+	raw, err := json.MarshalIndent(enc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := v.writeFileAtomic(filepath.Join(v.basePath, EncryptionFile), raw); err != nil {
+		return nil, fmt.Errorf("write %s: %w", EncryptionFile, err)
+	}
+	v.key = key
+	return key, nil
+}
+
+// EncryptAll seals every plaintext auth and config file in the vault's
+// profiles, in place, and returns how many it sealed. Live auth paths that
+// are symlinks into the vault must be converted with Unlink first, or the
+// tools would read ciphertext.
+func (v *Vault) EncryptAll() (int, error) {
+	if !v.Encrypted() {
+		return 0, fmt.Errorf("vault %s is not encrypted", v.basePath)
+	}
+	key, err := v.vaultKey()
+	if err != nil {
+		return 0, err
+	}
+	sealed := 0
+	err = v.walkStoredFiles(func(path string, onDisk []byte) error {
+		data, err := v.backendFor(onDisk).Get(path, onDisk)
+		if err != nil {
+			return err
+		}
+		if isSealed(data) {
+			return nil
+		}
+		ciphertext, err := seal(key, data)
+		if err != nil {
+			return err
+		}
+		if err := v.writeStored(path, ciphertext); err != nil {
+			return fmt.Errorf("encrypt %s: %w", path, err)
+		}
+		sealed++
+		return nil
+	})
+	return sealed, err
+}
+
+// ReadFile returns the contents of a file stored in the vault, decrypting it
+// if it is sealed.
+func (v *Vault) ReadFile(path string) ([]byte, error) {
+	data, err := v.readStored(path)
+	if err != nil {
+		return nil, err
+	}
+	return v.unseal(path, data)
+}
+
+// PlainProfileDir returns a directory holding a profile's files in plaintext,
+// for code that parses them by path. For a plaintext vault it is the profile
+// directory itself; for an encrypted one, or a profile kept in the keychain,
+// it is a private temporary copy that cleanup removes. cleanup is never nil.
+func (v *Vault) PlainProfileDir(tool, profile string) (string, func(), error) {
+	dir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return "", func() {}, err
+	}
+	owner := v.ownerOf(dir)
+	if !owner.Encrypted() && !hasStubs(dir) {
+		return dir, func() {}, nil
+	}
+
+	tmp, err := os.MkdirTemp("", "caam-profile-*")
+	if err != nil {
+		return "", func() {}, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		data, err := v.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			cleanup()
+			return "", func() {}, fmt.Errorf("decrypt %s: %w", entry.Name(), err)
+		}
+		if err := writeFileAtomicDurable(filepath.Join(tmp, entry.Name()), data, false); err != nil {
+			cleanup()
+			return "", func() {}, err
+		}
+	}
+	return tmp, cleanup, nil
+}
+
+// SavePlainProfileDir writes the files changed in dir, a directory returned by
+// PlainProfileDir, back into the profile, sealing those that were sealed.
+// For a plaintext vault dir is the profile itself and there is nothing to do.
+func (v *Vault) SavePlainProfileDir(tool, profile, dir string) error {
+	profileDir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return err
+	}
+	if filepath.Clean(dir) == filepath.Clean(profileDir) {
+		return nil
+	}
+	if err := v.checkWritable(tool, profile); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		src := filepath.Join(dir, entry.Name())
+		dst := filepath.Join(profileDir, entry.Name())
+		plain, err := os.ReadFile(src)
+		if err != nil {
+			return err
+		}
+		onDisk, err := os.ReadFile(dst)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		backend := v.backendFor(onDisk)
+		var stored []byte
+		if onDisk != nil {
+			if stored, err = backend.Get(dst, onDisk); err != nil {
+				return err
+			}
+		}
+		if before, err := v.unseal(dst, stored); err == nil && bytes.Equal(before, plain) {
+			continue
+		}
+		switch {
+		case backend.Name() == StorageKeychain:
+			// A stub stays a stub whatever backend this vault is configured
+			// with; only a storage migration moves it back to disk.
+			err = v.putKept(backend, dst, plain, isSealed(stored))
+		case stored == nil || isSealed(stored) || v.Storage().Name() == StorageKeychain:
+			err = v.storeFile(src, dst)
+		default:
+			err = v.writeFileAtomic(dst, plain)
+		}
+		if err != nil {
+			return fmt.Errorf("save %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// putKept stores plain as the contents of the vault file at dst with
+// backend, the one already holding it, sealing it again if it was sealed.
+func (v *Vault) putKept(backend StorageBackend, dst string, plain []byte, sealed bool) error {
+	data := plain
+	if sealed {
+		key, err := v.vaultKey()
+		if err != nil {
+			return err
+		}
+		if data, err = seal(key, plain); err != nil {
+			return err
+		}
+	}
+	return backend.Put(v, dst, data)
+}
+
+// storeFile copies a live file into the vault with the configured storage
+// backend, sealing it when the vault is encrypted.
+func (v *Vault) storeFile(src, dst string) error {
+	if !v.Encrypted() && v.Storage().Name() == StorageFile {
+		before, _ := os.ReadFile(dst)
+		if err := v.copyFile(src, dst); err != nil {
+			return err
+		}
+		return v.backendFor(before).Remove(dst, before)
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if v.Encrypted() {
+		key, err := v.vaultKey()
+		if err != nil {
+			return err
+		}
+		if data, err = seal(key, data); err != nil {
+			return err
+		}
+	}
+	return v.writeStored(dst, data)
+}
+
+// loadFile copies a vault file to a live path, fetching it from the keychain
+// and decrypting it as needed.
+func (v *Vault) loadFile(src, dst string) error {
+	onDisk, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	data, err := v.backendFor(onDisk).Get(src, onDisk)
+	if err != nil {
+		return err
+	}
+	if !isSealed(data) && bytes.Equal(data, onDisk) {
+		return v.copyFile(src, dst)
+	}
+	plain, err := v.unseal(src, data)
+	if err != nil {
+		return err
+	}
+	return v.writeFileAtomic(dst, plain)
+}
+
+// hashStored hashes the plaintext of a vault file.
+func (v *Vault) hashStored(path string) (string, error) {
+	data, err := v.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return hashBytes(data), nil
+}
+
+// unseal decrypts data read from path if it is sealed, with the key of the
+// vault holding path.
+func (v *Vault) unseal(path string, data []byte) ([]byte, error) {
+	if !isSealed(data) {
+		return data, nil
+	}
+	key, err := v.ownerOf(path).vaultKey()
+	if err != nil {
+		return nil, err
+	}
+	plain, err := openSealed(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: %w", filepath.Base(path), err)
+	}
+	return plain, nil
+}
+
+// ownerOf returns the vault path is stored in: the shared vault for its
+// profiles, otherwise v.
+func (v *Vault) ownerOf(path string) *Vault {
+	if shared := v.sharedVault(); shared != nil && pathWithin(path, shared.basePath) {
+		return shared
+	}
+	return v
+}
+
+// vaultKey returns the vault's key, asking the default key source the first
+// time it is needed.
+func (v *Vault) vaultKey() ([]byte, error) {
+	if v.key != nil {
+		return v.key, nil
+	}
+	keySourceMu.Lock()
+	source := defaultKeySource
+	keySourceMu.Unlock()
+	if source == nil {
+		return nil, ErrVaultLocked
+	}
+	key, err := source(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := v.Unlock(key); err != nil {
+		return nil, err
+	}
+	return v.key, nil
+}
+
+func isSealed(data []byte) bool {
+	return bytes.HasPrefix(data, sealedMagic)
+}
+
+func seal(key, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, vaultNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	out := append(append([]byte(nil), sealedMagic...), nonce...)
+	return gcm.Seal(out, nonce, plain, sealedMagic), nil
+}
+
+func openSealed(key, data []byte) ([]byte, error) {
+	if !isSealed(data) || len(data) < len(sealedMagic)+vaultNonceSize {
+		return nil, fmt.Errorf("not an encrypted vault file")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	body := data[len(sealedMagic):]
+	plain, err := gcm.Open(nil, body[:vaultNonceSize], body[vaultNonceSize:], sealedMagic)
+	if err != nil {
+		return nil, fmt.Errorf("wrong key or corrupted file")
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
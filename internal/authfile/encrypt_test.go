@@ -0,0 +1,162 @@
+package authfile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptedVault_BackupRestoreRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	v.SetActivationMode(ActivationSymlink)
+
+	authFile := filepath.Join(tmpDir, "codex", "auth.json")
+	fileSet := AuthFileSet{
+		Tool:  "codex",
+		Files: []AuthFileSpec{{Tool: "codex", Path: authFile, Required: true}},
+	}
+	writeLive := func(content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(authFile), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(authFile, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := v.EnableEncryption("correct horse"); err != nil {
+		t.Fatalf("EnableEncryption() error = %v", err)
+	}
+	for _, name := range []string{"work", "home"} {
+		writeLive(`{"tokens":{"access_token":"` + name + `"}}`)
+		if err := v.Backup(fileSet, name); err != nil {
+			t.Fatalf("Backup(%s) error = %v", name, err)
+		}
+	}
+
+	stored, err := os.ReadFile(v.BackupPath("codex", "work", "auth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isSealed(stored) || bytes.Contains(stored, []byte("access_token")) {
+		t.Fatalf("vault file is not encrypted: %q", stored)
+	}
+	if schemas, _ := v.RecordedSchemas("codex", "work"); len(schemas["auth.json"].Keys) == 0 {
+		t.Error("schema should be recorded from the plaintext")
+	}
+
+	// Symlink mode can't link to ciphertext, so the profile is copied out.
+	if err := v.Restore(fileSet, "work"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if info, _ := os.Lstat(authFile); info.Mode()&os.ModeSymlink != 0 {
+		t.Error("encrypted vault should activate by copy")
+	}
+	if got, _ := os.ReadFile(authFile); string(got) != `{"tokens":{"access_token":"work"}}` {
+		t.Errorf("restored auth = %s", got)
+	}
+	if active, _ := v.ActiveProfile(fileSet); active != "work" {
+		t.Errorf("ActiveProfile() = %q, want work", active)
+	}
+
+	dir, cleanup, err := v.PlainProfileDir("codex", "home")
+	if err != nil {
+		t.Fatalf("PlainProfileDir() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "auth.json")); string(got) != `{"tokens":{"access_token":"home"}}` {
+		t.Errorf("plain copy = %s", got)
+	}
+	cleanup()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("cleanup should remove the plaintext copy")
+	}
+}
+
+func TestEncryptedVault_KeySource(t *testing.T) {
+	tmpDir := t.TempDir()
+	base := filepath.Join(tmpDir, "vault")
+	authFile := filepath.Join(tmpDir, "auth.json")
+	if err := os.WriteFile(authFile, []byte(`{"token":"x"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := AuthFileSet{Tool: "codex", Files: []AuthFileSpec{{Tool: "codex", Path: authFile, Required: true}}}
+
+	key, err := NewVault(base).EnableEncryption("pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetDefaultKeySource(nil) })
+
+	// A fresh vault has no key until a source supplies one.
+	if err := NewVault(base).Backup(fileSet, "a"); !errors.Is(err, ErrVaultLocked) {
+		t.Fatalf("Backup() without key = %v, want ErrVaultLocked", err)
+	}
+
+	SetDefaultKeySource(func(v *Vault) ([]byte, error) { return v.DeriveKey("wrong") })
+	if err := NewVault(base).Backup(fileSet, "a"); err == nil {
+		t.Fatal("Backup() with a wrong passphrase should fail")
+	}
+
+	calls := 0
+	SetDefaultKeySource(func(*Vault) ([]byte, error) {
+		calls++
+		return key, nil
+	})
+	v := NewVault(base)
+	if err := v.Backup(fileSet, "a"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if err := v.Restore(fileSet, "a"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("key source called %d times, want once per vault", calls)
+	}
+}
+
+func TestEncryptAll_MigratesInPlace(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+
+	dir := v.ProfilePath("codex", "work")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	plain := []byte(`{"tokens":{"refresh_token":"r"}}`)
+	if err := os.WriteFile(filepath.Join(dir, "auth.json"), plain, 0600); err != nil {
+		t.Fatal(err)
+	}
+	meta := []byte(`{"tool":"codex","profile":"work"}`)
+	if err := os.WriteFile(filepath.Join(dir, "meta.json"), meta, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := v.EncryptAll(); err == nil {
+		t.Fatal("EncryptAll() on a plaintext vault should fail")
+	}
+	if _, err := v.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	n, err := v.EncryptAll()
+	if err != nil || n != 1 {
+		t.Fatalf("EncryptAll() = %d, %v; want 1, nil", n, err)
+	}
+	if n, _ := v.EncryptAll(); n != 0 {
+		t.Errorf("second EncryptAll() sealed %d files, want 0", n)
+	}
+
+	if got, _ := os.ReadFile(filepath.Join(dir, "meta.json")); !bytes.Equal(got, meta) {
+		t.Error("meta.json should stay plaintext")
+	}
+	got, err := v.ReadFile(filepath.Join(dir, "auth.json"))
+	if err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("ReadFile() = %s, %v", got, err)
+	}
+	if _, err := v.EnableEncryption("again"); err == nil {
+		t.Error("EnableEncryption() twice should fail")
+	}
+}
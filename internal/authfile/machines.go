@@ -0,0 +1,109 @@
+package authfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// MachinesFile is the vault profile file listing the machines that have
+// activated the profile. It travels with the profile on sync and export, so
+// an activation elsewhere shows up on every machine sharing the vault.
+const MachinesFile = "machines.json"
+
+// MachineRecord is one machine that activated a profile.
+type MachineRecord struct {
+	ID        string    `json:"id"`
+	Hostname  string    `json:"hostname,omitempty"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// Machines returns the machines recorded as having activated tool/profile,
+// oldest first. Profiles never activated since caam started recording
+// return nil.
+func (v *Vault) Machines(tool, profile string) ([]MachineRecord, error) {
+	profileDir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(filepath.Join(profileDir, MachinesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", MachinesFile, err)
+	}
+	var records []MachineRecord
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", MachinesFile, err)
+	}
+	return records, nil
+}
+
+// RecordMachine notes that the machine id activated tool/profile at the
+// given time. Shared-vault profiles are read-only and left unchanged.
+func (v *Vault) RecordMachine(tool, profile, id, hostname string, at time.Time) error {
+	if id == "" || v.IsShared(tool, profile) {
+		return nil
+	}
+	profileDir, err := v.safeProfileDir(tool, profile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(profileDir); err != nil {
+		return fmt.Errorf("profile %s/%s: %w", tool, profile, err)
+	}
+
+	records, err := v.Machines(tool, profile)
+	if err != nil {
+		records = nil // rewrite an unreadable list rather than fail activation
+	}
+	found := false
+	for i := range records {
+		if records[i].ID == id {
+			records[i].LastSeen = at
+			if hostname != "" {
+				records[i].Hostname = hostname
+			}
+			found = true
+			break
+		}
+	}
+	if !found {
+		records = append(records, MachineRecord{ID: id, Hostname: hostname, FirstSeen: at, LastSeen: at})
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].FirstSeen.Before(records[j].FirstSeen)
+	})
+
+	raw, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", MachinesFile, err)
+	}
+	f, err := os.CreateTemp(profileDir, MachinesFile+".tmp.*")
+	if err != nil {
+		return fmt.Errorf("create temp %s: %w", MachinesFile, err)
+	}
+	tmpPath := f.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := f.Write(raw); err != nil {
+		f.Close()
+		return fmt.Errorf("write temp %s: %w", MachinesFile, err)
+	}
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return fmt.Errorf("chmod temp %s: %w", MachinesFile, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close temp %s: %w", MachinesFile, err)
+	}
+	if err := os.Rename(tmpPath, filepath.Join(profileDir, MachinesFile)); err != nil {
+		return fmt.Errorf("rename %s: %w", MachinesFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package authfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// profileNameRune reports whether r may appear in a vault profile name.
+// Names end up in directory paths and in generated shell scripts (see
+// claude.go's setupAPIKeyHelper), so only characters with no special
+// meaning to either are allowed; @ and + are kept for email-style names.
+func profileNameRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9') || r == '_' || r == '-' || r == '.' || r == '@' || r == '+'
+}
+
+// ValidateProfileName reports whether name is usable as a vault profile
+// name. When it isn't but can be fixed, the error suggests the
+// SanitizeProfileName form.
+func ValidateProfileName(name string) error {
+	_, err := validateVaultSegment("profile", name)
+	return err
+}
+
+// SanitizeProfileName turns name into a valid profile name: each run of
+// disallowed characters (spaces, shell metacharacters, slashes) becomes a
+// single hyphen, so "work account #1" becomes "work-account-1". Valid names
+// are returned unchanged; "" means nothing usable is left.
+func SanitizeProfileName(name string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.TrimSpace(name) {
+		if !profileNameRune(r) {
+			pendingDash = b.Len() > 0
+			continue
+		}
+		if pendingDash && r != '-' && !strings.HasSuffix(b.String(), "-") {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteRune(r)
+	}
+	out := b.String()
+	if out == "." || out == ".." {
+		return ""
+	}
+	return out
+}
+
+// profileNameSuggestion returns ` (did you mean "x"?)` for a rejected name
+// with a usable sanitized form, or "".
+func profileNameSuggestion(name string) string {
+	if s := SanitizeProfileName(name); s != "" && s != name {
+		return fmt.Sprintf(" (did you mean %q?)", s)
+	}
+	return ""
+}
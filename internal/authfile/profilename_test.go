@@ -0,0 +1,44 @@
+package authfile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeProfileName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"work", "work"},
+		{"me@example.com", "me@example.com"},
+		{"work account 1", "work-account-1"},
+		{"work account #1", "work-account-1"},
+		{"a - b", "a-b"},
+		{"$(rm -rf)", "rm-rf"},
+		{"client/x", "client-x"},
+		{"  spaced  ", "spaced"},
+		{"!!!", ""},
+		{"..", ""},
+	}
+	for _, tt := range tests {
+		got := SanitizeProfileName(tt.in)
+		if got != tt.want {
+			t.Errorf("SanitizeProfileName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if got != "" {
+			if err := ValidateProfileName(got); err != nil {
+				t.Errorf("sanitized %q is invalid: %v", got, err)
+			}
+		}
+	}
+}
+
+func TestValidateProfileName_Suggests(t *testing.T) {
+	err := ValidateProfileName("work account")
+	if err == nil || !strings.Contains(err.Error(), `did you mean "work-account"?`) {
+		t.Errorf("ValidateProfileName() error = %v, want a suggestion", err)
+	}
+	if err := ValidateProfileName("!!!"); err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("ValidateProfileName(%q) error = %v, want no suggestion", "!!!", err)
+	}
+}
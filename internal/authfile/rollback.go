@@ -0,0 +1,103 @@
+package authfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// pathSnapshot is what was at one path before a multi-file write, so the
+// write can be undone if it fails partway.
+type pathSnapshot struct {
+	path   string
+	exists bool
+	link   string // symlink target, when the path was a link
+	data   []byte
+	mode   os.FileMode
+}
+
+// snapshotPaths records the current state of each path. Links are recorded
+// as links, not followed, so a symlink-mode activation is undone as one.
+func snapshotPaths(paths []string) ([]pathSnapshot, error) {
+	snaps := make([]pathSnapshot, 0, len(paths))
+	seen := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		snap := pathSnapshot{path: path}
+		info, err := os.Lstat(path)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return nil, fmt.Errorf("snapshot %s: %w", path, err)
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot %s: %w", path, err)
+			}
+			snap.exists, snap.link = true, target
+		case info.IsDir():
+			return nil, fmt.Errorf("snapshot %s: is a directory", path)
+		default:
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("snapshot %s: %w", path, err)
+			}
+			snap.exists, snap.data, snap.mode = true, data, info.Mode().Perm()
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
+
+// restoreSnapshots puts every path back the way snapshotPaths found it,
+// carrying on past failures so as much as possible is undone.
+func restoreSnapshots(snaps []pathSnapshot, durable bool) error {
+	var errs []error
+	for _, snap := range snaps {
+		if err := snap.restore(durable); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", snap.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s pathSnapshot) restore(durable bool) error {
+	if !s.exists {
+		if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if s.link != "" {
+		tmp := s.path + ".caam-rollback.tmp"
+		_ = os.Remove(tmp)
+		if err := os.Symlink(s.link, tmp); err != nil {
+			return err
+		}
+		if err := os.Rename(tmp, s.path); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		return nil
+	}
+	if err := writeFileAtomicDurable(s.path, s.data, durable); err != nil {
+		return err
+	}
+	if s.mode != 0600 {
+		return os.Chmod(s.path, s.mode)
+	}
+	return nil
+}
+
+// withRollback runs undo after a failed write and keeps the write's error,
+// noting when the undo failed too.
+func withRollback(err error, undo func() error) error {
+	if undoErr := undo(); undoErr != nil {
+		return fmt.Errorf("%w (rollback failed, files may be left partly written: %v)", err, undoErr)
+	}
+	return err
+}
@@ -0,0 +1,180 @@
+package authfile
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
+)
+
+// threeFileSet returns a file set of three required files under dir.
+func threeFileSet(dir string) AuthFileSet {
+	fileSet := AuthFileSet{Tool: "multi"}
+	for _, name := range []string{"a.json", "b.json", "c.json"} {
+		fileSet.Files = append(fileSet.Files, AuthFileSpec{Tool: "multi", Path: filepath.Join(dir, name), Required: true})
+	}
+	return fileSet
+}
+
+func writeLogin(t *testing.T, fileSet AuthFileSet, login string) {
+	t.Helper()
+	for _, spec := range fileSet.Files {
+		if err := os.WriteFile(spec.Path, []byte(login+":"+filepath.Base(spec.Path)), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// loginOf returns the login every file in dir holds, or "" if they differ.
+func loginOf(t *testing.T, fileSet AuthFileSet, dir string) string {
+	t.Helper()
+	login := ""
+	for i, spec := range fileSet.Files {
+		name := filepath.Base(spec.Path)
+		path := spec.Path
+		if dir != "" {
+			path = filepath.Join(dir, name)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		got, ok := strings.CutSuffix(string(data), ":"+name)
+		if !ok || (i > 0 && got != login) {
+			return ""
+		}
+		login = got
+	}
+	return login
+}
+
+func TestRestore_RollsBackPartialFailure(t *testing.T) {
+	tmp := t.TempDir()
+	live := filepath.Join(tmp, "live")
+	if err := os.MkdirAll(live, 0700); err != nil {
+		t.Fatal(err)
+	}
+	vault := NewVault(filepath.Join(tmp, "vault"))
+	fileSet := threeFileSet(live)
+	for _, login := range []string{"work", "home"} {
+		writeLogin(t, fileSet, login)
+		if err := vault.Backup(fileSet, login); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restore := faultinject.Enable(faultinject.Config{Rates: map[faultinject.Op]float64{faultinject.OpCopy: 0.2, faultinject.OpRename: 0.2}, Seed: 3})
+	defer restore()
+
+	current, failures := "home", 0
+	for i := 0; i < 60; i++ {
+		target := map[string]string{"home": "work", "work": "home"}[current]
+		err := vault.Restore(fileSet, target)
+		got := loginOf(t, fileSet, "")
+		switch {
+		case err == nil && got != target:
+			t.Fatalf("restore %d succeeded but live auth is %q, want %s", i, got, target)
+		case err != nil && !errors.Is(err, faultinject.ErrInjected):
+			t.Fatalf("restore %d: unexpected error %v", i, err)
+		case err != nil && got != current:
+			t.Fatalf("restore %d failed (%v) and left live auth as %q, want %s", i, err, got, current)
+		}
+		if err != nil {
+			failures++
+		} else {
+			current = target
+		}
+	}
+	if failures == 0 {
+		t.Fatal("no restore failed; the test injected nothing")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(live, "*.tmp*")); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
+
+func TestRestore_RollsBackSymlinks(t *testing.T) {
+	tmp := t.TempDir()
+	live := filepath.Join(tmp, "live")
+	if err := os.MkdirAll(live, 0700); err != nil {
+		t.Fatal(err)
+	}
+	vault := NewVault(filepath.Join(tmp, "vault"))
+	vault.SetActivationMode(ActivationSymlink)
+	fileSet := threeFileSet(live)
+	for _, login := range []string{"work", "home"} {
+		writeLogin(t, fileSet, login)
+		if err := vault.Backup(fileSet, login); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := vault.Restore(fileSet, "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	restore := faultinject.Enable(faultinject.Config{Rates: map[faultinject.Op]float64{faultinject.OpRename: 1}})
+	defer restore()
+	if err := vault.Restore(fileSet, "home"); !errors.Is(err, faultinject.ErrInjected) {
+		t.Fatalf("Restore() error = %v, want an injected fault", err)
+	}
+	for _, spec := range fileSet.Files {
+		target, ok := readLink(spec.Path)
+		if want := vault.BackupPath("multi", "work", filepath.Base(spec.Path)); !ok || target != want {
+			t.Errorf("%s links to %q, want %q", spec.Path, target, want)
+		}
+	}
+}
+
+func TestBackup_RollsBackPartialFailure(t *testing.T) {
+	tmp := t.TempDir()
+	live := filepath.Join(tmp, "live")
+	if err := os.MkdirAll(live, 0700); err != nil {
+		t.Fatal(err)
+	}
+	vault := NewVault(filepath.Join(tmp, "vault"))
+	fileSet := threeFileSet(live)
+	writeLogin(t, fileSet, "v0")
+	if err := vault.Backup(fileSet, "work"); err != nil {
+		t.Fatal(err)
+	}
+	profileDir := vault.ProfilePath("multi", "work")
+
+	restore := faultinject.Enable(faultinject.Config{Rates: map[faultinject.Op]float64{faultinject.OpCopy: 0.15, faultinject.OpRename: 0.15}, Seed: 5})
+	defer restore()
+
+	saved, failures := "v0", 0
+	for i := 1; i <= 40; i++ {
+		login := fmt.Sprintf("v%d", i)
+		writeLogin(t, fileSet, login)
+		err := vault.Backup(fileSet, "work")
+		got := loginOf(t, fileSet, profileDir)
+		if err == nil {
+			if got != login {
+				t.Fatalf("backup %d succeeded but the profile holds %q", i, got)
+			}
+			saved = login
+			continue
+		}
+		failures++
+		if got != saved {
+			t.Fatalf("backup %d failed (%v) and left the profile as %q, want %s", i, err, got, saved)
+		}
+
+		// A first backup that fails leaves no profile behind.
+		if err := vault.Backup(fileSet, fmt.Sprintf("new%d", i)); err != nil {
+			if _, statErr := os.Stat(vault.ProfilePath("multi", fmt.Sprintf("new%d", i))); !os.IsNotExist(statErr) {
+				t.Fatalf("failed first backup left a profile dir (stat: %v)", statErr)
+			}
+		}
+	}
+	if failures == 0 {
+		t.Fatal("no backup failed; the test injected nothing")
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(profileDir, "*.tmp*")); len(leftovers) > 0 {
+		t.Errorf("temp files left behind: %v", leftovers)
+	}
+}
@@ -0,0 +1,222 @@
+package authfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FileSchema is the anonymized JSON key structure of an auth file: sorted
+// "path:type" entries, with values dropped and identifying keys (paths,
+// emails, ids) replaced by "*".
+type FileSchema struct {
+	Fingerprint string   `json:"fingerprint"`
+	Keys        []string `json:"keys"`
+}
+
+// maxSchemaDepth bounds how deep the structure of large files like
+// ~/.claude.json is recorded.
+const maxSchemaDepth = 6
+
+// schemaKeyPattern matches keys kept verbatim; anything else (project paths,
+// emails, UUIDs) is anonymized.
+var schemaKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z_-]*[0-9]{0,3}[A-Za-z_-]*$`)
+
+// expectedSchemas lists the key paths caam's parsers (identity, refresh) read
+// from each auth file, keyed by "tool/filename". Each entry is a group of
+// alternatives: the file matches if any of them is present.
+var expectedSchemas = map[string][][]string{
+	"claude/.credentials.json": {
+		{"claudeAiOauth.accessToken", "accessToken", "access_token"},
+		{"claudeAiOauth.refreshToken", "refreshToken", "refresh_token"},
+		{"claudeAiOauth.expiresAt", "expiresAt", "expires_at"},
+	},
+	"codex/auth.json": {
+		{"tokens.access_token", "tokens.accessToken", "access_token", "accessToken", "token"},
+		{"tokens.refresh_token", "refresh_token"},
+	},
+	"gemini/oauth_credentials.json": {
+		{"access_token", "accessToken"},
+		{"refresh_token", "refreshToken"},
+	},
+}
+
+// ParseFileSchema returns the key structure of a JSON document.
+func ParseFileSchema(data []byte) (FileSchema, error) {
+	var root interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		return FileSchema{}, err
+	}
+
+	set := make(map[string]bool)
+	collectSchemaKeys("", root, 0, set)
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sum := sha256.Sum256([]byte(strings.Join(keys, "\n")))
+	return FileSchema{Fingerprint: hex.EncodeToString(sum[:])[:12], Keys: keys}, nil
+}
+
+func collectSchemaKeys(prefix string, v interface{}, depth int, out map[string]bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if prefix != "" {
+			out[prefix+":object"] = true
+		}
+		if depth >= maxSchemaDepth {
+			return
+		}
+		for key, child := range val {
+			if !schemaKeyPattern.MatchString(key) {
+				key = "*"
+			}
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			collectSchemaKeys(path, child, depth+1, out)
+		}
+	case []interface{}:
+		out[prefix+":array"] = true
+		if depth >= maxSchemaDepth {
+			return
+		}
+		for _, child := range val {
+			collectSchemaKeys(prefix+"[]", child, depth+1, out)
+		}
+	default:
+		if prefix != "" {
+			out[prefix+":"+schemaType(val)] = true
+		}
+	}
+}
+
+func schemaType(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
+
+// Has reports whether the schema contains the key path, of any type.
+func (s FileSchema) Has(path string) bool {
+	for _, key := range s.Keys {
+		if i := strings.LastIndex(key, ":"); i >= 0 && key[:i] == path {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingSchemaKeys returns the key paths caam's parsers expect in tool's
+// file but that schema lacks, one (the preferred alternative) per unmet group.
+func MissingSchemaKeys(tool, filename string, schema FileSchema) []string {
+	var missing []string
+	for _, group := range expectedSchemas[tool+"/"+filename] {
+		found := false
+		for _, path := range group {
+			if schema.Has(path) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, group[0])
+		}
+	}
+	return missing
+}
+
+// DiffSchemas returns the keys added and removed going from old to new.
+func DiffSchemas(old, new FileSchema) (added, removed []string) {
+	oldSet := make(map[string]bool, len(old.Keys))
+	for _, key := range old.Keys {
+		oldSet[key] = true
+	}
+	newSet := make(map[string]bool, len(new.Keys))
+	for _, key := range new.Keys {
+		newSet[key] = true
+		if !oldSet[key] {
+			added = append(added, key)
+		}
+	}
+	for _, key := range old.Keys {
+		if !newSet[key] {
+			removed = append(removed, key)
+		}
+	}
+	return added, removed
+}
+
+// ReadFileSchemas returns the schemas of fileSet's JSON files in dir, keyed
+// by file name; an empty dir reads the live auth files. Missing and
+// unparseable files are skipped.
+func ReadFileSchemas(fileSet AuthFileSet, dir string) map[string]FileSchema {
+	return readFileSchemas(fileSet, dir, os.ReadFile)
+}
+
+// readFileSchemas is ReadFileSchemas for a vault profile directory, reading
+// sealed files through the vault.
+func (v *Vault) readFileSchemas(fileSet AuthFileSet, dir string) map[string]FileSchema {
+	return readFileSchemas(fileSet, dir, v.ReadFile)
+}
+
+func readFileSchemas(fileSet AuthFileSet, dir string, read func(string) ([]byte, error)) map[string]FileSchema {
+	schemas := make(map[string]FileSchema)
+	for _, spec := range fileSet.Files {
+		name := filepath.Base(spec.Path)
+		if filepath.Ext(name) != ".json" {
+			continue
+		}
+		path := spec.Path
+		if dir != "" {
+			path = filepath.Join(dir, name)
+		}
+		data, err := read(path)
+		if err != nil {
+			continue
+		}
+		if schema, err := ParseFileSchema(data); err == nil {
+			schemas[name] = schema
+		}
+	}
+	return schemas
+}
+
+// RecordedSchemas returns the schemas recorded in a profile's meta.json at
+// backup time. Profiles backed up before schemas were recorded return nil.
+func (v *Vault) RecordedSchemas(tool, profile string) (map[string]FileSchema, error) {
+	profileDir, _, err := v.readProfileDir(tool, profile)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(filepath.Join(profileDir, "meta.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read metadata: %w", err)
+	}
+	var meta struct {
+		Schemas map[string]FileSchema `json:"schemas"`
+	}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, fmt.Errorf("parse metadata: %w", err)
+	}
+	return meta.Schemas, nil
+}
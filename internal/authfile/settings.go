@@ -0,0 +1,32 @@
+package authfile
+
+import (
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// ApplyVaultSettings configures v from the runtime config: activation_mode,
+// config_policy, durability and storage. Every process that writes the vault
+// (the CLI, the daemon, the TUI) applies them, so a background refresh keeps
+// the activation mode and storage the user chose. An invalid or unreadable
+// config leaves the defaults (copy, preserve, safe, file).
+func ApplyVaultSettings(v *Vault) {
+	spmCfg, err := config.LoadSPMConfig()
+	if err != nil {
+		return
+	}
+	if mode, err := ParseActivationMode(spmCfg.Runtime.ActivationMode); err == nil {
+		v.SetActivationMode(mode)
+	}
+	if policy, err := ParseConfigPolicy(spmCfg.Runtime.ConfigPolicy); err == nil {
+		v.SetConfigPolicy(policy)
+	}
+	if durability, err := ParseDurability(spmCfg.Runtime.Durability); err == nil {
+		v.SetDurability(durability)
+	}
+	keyringMu.Lock()
+	kr := defaultKeyring
+	keyringMu.Unlock()
+	if storage, err := ParseStorage(spmCfg.Runtime.Storage, kr()); err == nil {
+		v.SetStorage(storage)
+	}
+}
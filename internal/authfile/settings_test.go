@@ -0,0 +1,37 @@
+package authfile
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+func TestApplyVaultSettings(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	ApplyVaultSettings(v)
+	if v.Durability() != DurabilitySafe || v.ActivationMode() != ActivationCopy {
+		t.Errorf("without a config: durability %s, mode %s; want the defaults", v.Durability(), v.ActivationMode())
+	}
+
+	spmCfg := config.DefaultSPMConfig()
+	spmCfg.Runtime.ActivationMode = "symlink"
+	spmCfg.Runtime.Durability = "fast"
+	if err := spmCfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	v = NewVault(filepath.Join(tmpDir, "vault"))
+	ApplyVaultSettings(v)
+	if v.Durability() != DurabilityFast {
+		t.Errorf("Durability() = %s, want fast from the config", v.Durability())
+	}
+	if v.ActivationMode() != ActivationSymlink {
+		t.Errorf("ActivationMode() = %s, want symlink from the config", v.ActivationMode())
+	}
+	if v.Storage().Name() != StorageFile {
+		t.Errorf("Storage() = %s, want file", v.Storage().Name())
+	}
+}
@@ -0,0 +1,149 @@
+package authfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Shared profiles live in a system-wide vault that an administrator maintains
+// with `caam --system`. A user's vault falls back to it for profiles the user
+// hasn't saved, so shared profiles can be activated by everyone but modified
+// by no one except through the shared vault itself.
+
+var errSharedProfile = fmt.Errorf("shared profile is read-only")
+
+// DefaultSharedVaultPath returns the location of the system-wide shared
+// vault: $CAAM_SHARED_VAULT, else /usr/local/share/caam/vault.
+func DefaultSharedVaultPath() string {
+	if p := os.Getenv("CAAM_SHARED_VAULT"); p != "" {
+		return p
+	}
+	return filepath.Join(string(filepath.Separator), "usr", "local", "share", "caam", "vault")
+}
+
+// SetSharedPath sets the shared vault this vault falls back to for profiles
+// it doesn't have itself. An empty path disables the fallback.
+func (v *Vault) SetSharedPath(path string) {
+	v.sharedPath = path
+}
+
+// SharedPath returns the shared vault this vault falls back to, if any.
+func (v *Vault) SharedPath() string {
+	return v.sharedPath
+}
+
+// SetShareable makes Backup leave profiles readable by the vault's group
+// (directories 0750, files 0640) instead of private to the owner. The shared
+// vault uses it so other users can activate its profiles.
+func (v *Vault) SetShareable(shareable bool) {
+	v.shareable = shareable
+}
+
+// IsShared reports whether tool/profile comes from the shared vault, i.e. it
+// exists there and not in this vault.
+func (v *Vault) IsShared(tool, profile string) bool {
+	_, shared, err := v.readProfileDir(tool, profile)
+	return err == nil && shared
+}
+
+// readProfileDir returns the directory to read tool/profile from: this
+// vault's, or the shared vault's when only the shared vault has it.
+func (v *Vault) readProfileDir(tool, profile string) (string, bool, error) {
+	own, err := v.safeProfileDir(tool, profile)
+	if err != nil {
+		return "", false, err
+	}
+	shared := v.sharedVault()
+	if shared == nil {
+		return own, false, nil
+	}
+	if _, err := os.Stat(own); err == nil {
+		return own, false, nil
+	}
+	dir, err := shared.safeProfileDir(tool, profile)
+	if err != nil {
+		return own, false, nil
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir, true, nil
+	}
+	return own, false, nil
+}
+
+// sharedVault returns the shared vault as a Vault, or nil when there is none
+// or it is this vault.
+func (v *Vault) sharedVault() *Vault {
+	if v == nil || v.sharedPath == "" {
+		return nil
+	}
+	sharedAbs, err := filepath.Abs(v.sharedPath)
+	if err != nil {
+		return nil
+	}
+	if baseAbs, err := filepath.Abs(v.basePath); err == nil && baseAbs == sharedAbs {
+		return nil
+	}
+	return NewVault(sharedAbs)
+}
+
+// checkWritable refuses changes to profiles that come from the shared vault.
+func (v *Vault) checkWritable(tool, profile string) error {
+	if v.IsShared(tool, profile) {
+		return fmt.Errorf("%w: %s/%s belongs to the shared vault %s", errSharedProfile, tool, profile, v.sharedPath)
+	}
+	return nil
+}
+
+// mergeShared adds the shared vault's profiles for tool to profiles.
+func (v *Vault) mergeShared(tool string, profiles []string) []string {
+	shared := v.sharedVault()
+	if shared == nil {
+		return profiles
+	}
+	extra, err := shared.List(tool)
+	if err != nil || len(extra) == 0 {
+		return profiles
+	}
+	seen := make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		seen[p] = true
+	}
+	for _, p := range extra {
+		if !seen[p] {
+			profiles = append(profiles, p)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// shareProfile opens a freshly written profile to the vault's group: the
+// vault, tool and profile directories gain 0750 (keeping existing bits such as
+// setgid, so new files inherit the group) and the profile's files become 0640.
+func (v *Vault) shareProfile(profileDir string) error {
+	toolDir := filepath.Dir(profileDir)
+	for _, dir := range []string{filepath.Dir(toolDir), toolDir, profileDir} {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("share %s: %w", dir, err)
+		}
+		if err := os.Chmod(dir, info.Mode()&(os.ModePerm|os.ModeSetgid)|0750); err != nil {
+			return fmt.Errorf("share %s: %w", dir, err)
+		}
+	}
+	entries, err := os.ReadDir(profileDir)
+	if err != nil {
+		return fmt.Errorf("share %s: %w", profileDir, err)
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if err := os.Chmod(filepath.Join(profileDir, e.Name()), 0640); err != nil {
+			return fmt.Errorf("share %s: %w", e.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package authfile
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// VaultStats summarizes what a vault holds on disk.
+type VaultStats struct {
+	Providers  []ProviderVaultStats
+	TotalBytes int64
+	// Largest lists the biggest files, largest first.
+	Largest []VaultFile
+}
+
+// ProviderVaultStats is one tool's share of the vault. Auto-backups
+// (_backup_<timestamp>) are counted apart from the profiles users manage.
+type ProviderVaultStats struct {
+	Provider     string
+	Profiles     int
+	Backups      int
+	Bytes        int64
+	BackupBytes  int64
+	OldestBackup time.Time // zero without backups
+	NewestBackup time.Time
+}
+
+// VaultFile is one file stored in the vault.
+type VaultFile struct {
+	Provider string
+	Profile  string
+	Name     string // path relative to the profile directory
+	Bytes    int64
+}
+
+// Stats walks the vault's own profiles (not the shared vault) and reports
+// per-tool counts and sizes along with the largest files, up to top.
+func (v *Vault) Stats(top int) (*VaultStats, error) {
+	entries, err := os.ReadDir(v.basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &VaultStats{}, nil
+		}
+		return nil, fmt.Errorf("read vault: %w", err)
+	}
+
+	stats := &VaultStats{}
+	var files []VaultFile
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		tool := e.Name()
+		profiles, err := v.listOwn(tool)
+		if err != nil {
+			return nil, fmt.Errorf("list %s profiles: %w", tool, err)
+		}
+
+		ps := ProviderVaultStats{Provider: tool}
+		for _, profile := range profiles {
+			if strings.HasPrefix(profile, ".") {
+				continue
+			}
+			profileFiles, size, err := profileFiles(filepath.Join(v.basePath, tool, profile))
+			if err != nil {
+				return nil, fmt.Errorf("size %s/%s: %w", tool, profile, err)
+			}
+			ps.Bytes += size
+
+			if name, ok := strings.CutPrefix(profile, "_backup_"); ok {
+				ps.Backups++
+				ps.BackupBytes += size
+				if takenAt, err := time.ParseInLocation("20060102_150405", name, time.Local); err == nil {
+					if ps.OldestBackup.IsZero() || takenAt.Before(ps.OldestBackup) {
+						ps.OldestBackup = takenAt
+					}
+					if takenAt.After(ps.NewestBackup) {
+						ps.NewestBackup = takenAt
+					}
+				}
+			} else {
+				ps.Profiles++
+			}
+
+			for _, f := range profileFiles {
+				f.Provider, f.Profile = tool, profile
+				files = append(files, f)
+			}
+		}
+		stats.TotalBytes += ps.Bytes
+		stats.Providers = append(stats.Providers, ps)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Bytes != files[j].Bytes {
+			return files[i].Bytes > files[j].Bytes
+		}
+		return files[i].Provider+"/"+files[i].Profile+"/"+files[i].Name <
+			files[j].Provider+"/"+files[j].Profile+"/"+files[j].Name
+	})
+	if top >= 0 && len(files) > top {
+		files = files[:top]
+	}
+	stats.Largest = files
+	return stats, nil
+}
+
+// profileFiles lists the regular files under dir with their total size.
+// Symlinks are skipped: their targets live outside the vault.
+func profileFiles(dir string) ([]VaultFile, int64, error) {
+	var files []VaultFile
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, VaultFile{Name: filepath.ToSlash(rel), Bytes: info.Size()})
+		total += info.Size()
+		return nil
+	})
+	return files, total, err
+}
@@ -0,0 +1,464 @@
+package authfile
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+// A storage backend holds the contents of the auth and config files in a
+// vault's profiles. The profile directories, meta.json and other bookkeeping
+// stay on disk with either backend, so listing profiles never needs the
+// backend; only the credential bytes move.
+//
+// The keychain backend leaves a stub in place of each file: keychainMagic
+// followed by the keychain account holding the contents. Reads recognize the
+// stub whatever backend is configured, so a vault can hold a mix while it is
+// being migrated and switching back is lossless.
+
+// StorageBackend stores the contents of vault files.
+type StorageBackend interface {
+	// Name identifies the backend in config and messages: file or keychain.
+	Name() string
+
+	// Put stores data as the contents of the vault file at path.
+	Put(v *Vault, path string, data []byte) error
+
+	// Get returns the contents of the vault file at path, given the bytes
+	// on disk there.
+	Get(path string, onDisk []byte) ([]byte, error)
+
+	// Remove deletes what the backend holds for the file at path beyond the
+	// file itself.
+	Remove(path string, onDisk []byte) error
+}
+
+// Storage backend names, as used by runtime.storage.
+const (
+	StorageFile     = "file"
+	StorageKeychain = "keychain"
+)
+
+// keychainService names vault file contents in the OS keychain.
+const keychainService = "caam-vault-file"
+
+var keychainMagic = []byte("CAAMKEYCHAIN1\n")
+
+var (
+	keyringMu      sync.Mutex
+	defaultKeyring = keyring.System
+)
+
+// SetDefaultKeyring sets the credential store holding the contents of
+// keychain stubs when the vault isn't configured with a KeychainBackend, as
+// while it is being migrated back to files.
+func SetDefaultKeyring(kr func() keyring.Backend) {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	defaultKeyring = kr
+}
+
+// FileBackend keeps file contents in the vault files themselves (default).
+type FileBackend struct{}
+
+// Name implements StorageBackend.
+func (FileBackend) Name() string { return StorageFile }
+
+// Put implements StorageBackend.
+func (FileBackend) Put(v *Vault, path string, data []byte) error {
+	return v.writeFileAtomic(path, data)
+}
+
+// Get implements StorageBackend.
+func (FileBackend) Get(path string, onDisk []byte) ([]byte, error) {
+	return onDisk, nil
+}
+
+// Remove implements StorageBackend.
+func (FileBackend) Remove(path string, onDisk []byte) error {
+	return nil
+}
+
+// KeychainBackend keeps file contents in the OS credential store (the macOS
+// keychain or the Secret Service), one item per file, and a stub on disk.
+type KeychainBackend struct {
+	Keyring keyring.Backend
+}
+
+// NewKeychainBackend returns a backend storing contents in kr.
+func NewKeychainBackend(kr keyring.Backend) *KeychainBackend {
+	return &KeychainBackend{Keyring: kr}
+}
+
+// Name implements StorageBackend.
+func (b *KeychainBackend) Name() string { return StorageKeychain }
+
+// Put implements StorageBackend.
+func (b *KeychainBackend) Put(v *Vault, path string, data []byte) error {
+	if v.shareable {
+		return fmt.Errorf("the shared vault can't use keychain storage: other users can't read your keychain")
+	}
+	account, err := keychainAccount(path)
+	if err != nil {
+		return err
+	}
+	if err := b.setItem(account, base64.StdEncoding.EncodeToString(data)); err != nil {
+		return fmt.Errorf("store %s in the %s: %w", filepath.Base(path), b.Keyring.Name(), err)
+	}
+	stub := append(append([]byte(nil), keychainMagic...), account...)
+	return v.writeFileAtomic(path, stub)
+}
+
+// Get implements StorageBackend.
+func (b *KeychainBackend) Get(path string, onDisk []byte) ([]byte, error) {
+	account, ok := stubAccount(onDisk)
+	if !ok {
+		return onDisk, nil
+	}
+	encoded, err := b.getItem(account)
+	if err != nil {
+		if errors.Is(err, keyring.ErrNotFound) {
+			return nil, fmt.Errorf("%s: contents missing from the %s", filepath.Base(path), b.Keyring.Name())
+		}
+		return nil, fmt.Errorf("read %s from the %s: %w", filepath.Base(path), b.Keyring.Name(), err)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("read %s from the %s: %w", filepath.Base(path), b.Keyring.Name(), err)
+	}
+	return data, nil
+}
+
+// Remove implements StorageBackend. Only the item a stub was written for is
+// deleted: a stub copied or moved elsewhere in the vault still refers to the
+// original's item and leaves it alone.
+func (b *KeychainBackend) Remove(path string, onDisk []byte) error {
+	account, ok := stubAccount(onDisk)
+	if !ok {
+		return nil
+	}
+	if own, err := keychainAccount(path); err != nil || own != account {
+		return err
+	}
+	return b.deleteItem(account)
+}
+
+// keychainChunk is the most base64 one keychain item holds, which keeps each
+// write within what security -i reads on one line. Longer contents are
+// split over items account#1 to account#N, and the item at account reads
+// "parts:N"; base64 has no colon, so that can't be mistaken for contents.
+const keychainChunk = 3000
+
+const keychainPartsPrefix = "parts:"
+
+func (b *KeychainBackend) setItem(account, encoded string) error {
+	oldParts := b.itemParts(account)
+	parts := 0
+	if len(encoded) > keychainChunk {
+		for rest := encoded; rest != ""; parts++ {
+			n := min(len(rest), keychainChunk)
+			if err := b.Keyring.Set(keychainService, partAccount(account, parts+1), rest[:n]); err != nil {
+				return err
+			}
+			rest = rest[n:]
+		}
+		encoded = keychainPartsPrefix + strconv.Itoa(parts)
+	}
+	if err := b.Keyring.Set(keychainService, account, encoded); err != nil {
+		return err
+	}
+	for i := parts + 1; i <= oldParts; i++ {
+		if err := b.Keyring.Delete(keychainService, partAccount(account, i)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *KeychainBackend) getItem(account string) (string, error) {
+	head, err := b.Keyring.Get(keychainService, account)
+	if err != nil {
+		return "", err
+	}
+	parts, ok := parseParts(head)
+	if !ok {
+		return head, nil
+	}
+	var sb strings.Builder
+	for i := 1; i <= parts; i++ {
+		part, err := b.Keyring.Get(keychainService, partAccount(account, i))
+		if err != nil {
+			return "", fmt.Errorf("part %d of %d: %w", i, parts, err)
+		}
+		sb.WriteString(part)
+	}
+	return sb.String(), nil
+}
+
+func (b *KeychainBackend) deleteItem(account string) error {
+	parts := b.itemParts(account)
+	if err := b.Keyring.Delete(keychainService, account); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	for i := 1; i <= parts; i++ {
+		if err := b.Keyring.Delete(keychainService, partAccount(account, i)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// itemParts returns how many parts the item at account is split over now.
+func (b *KeychainBackend) itemParts(account string) int {
+	head, err := b.Keyring.Get(keychainService, account)
+	if err != nil {
+		return 0
+	}
+	parts, _ := parseParts(head)
+	return parts
+}
+
+func parseParts(head string) (int, bool) {
+	rest, ok := strings.CutPrefix(head, keychainPartsPrefix)
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	return n, err == nil && n > 0
+}
+
+func partAccount(account string, i int) string {
+	return account + "#" + strconv.Itoa(i)
+}
+
+// ParseStorage returns the storage backend with the given name, storing
+// keychain contents in kr. Empty means file.
+func ParseStorage(s string, kr keyring.Backend) (StorageBackend, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", StorageFile:
+		return FileBackend{}, nil
+	case StorageKeychain:
+		return NewKeychainBackend(kr), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (supported: file, keychain)", s)
+	}
+}
+
+// SetStorage sets where the vault stores the contents of files it writes.
+// Files already stored stay readable wherever they are; MigrateStorage moves
+// them.
+func (v *Vault) SetStorage(b StorageBackend) {
+	v.storage = b
+}
+
+// Storage returns where the vault stores the contents of files it writes.
+func (v *Vault) Storage() StorageBackend {
+	if v.storage == nil {
+		return FileBackend{}
+	}
+	return v.storage
+}
+
+// MigrateStorage moves every auth and config file in the vault's profiles to
+// the configured backend and returns how many it moved.
+func (v *Vault) MigrateStorage() (int, error) {
+	moved := 0
+	err := v.walkStoredFiles(func(path string, onDisk []byte) error {
+		_, isStub := stubAccount(onDisk)
+		if isStub == (v.Storage().Name() == StorageKeychain) {
+			return nil
+		}
+		data, err := v.backendFor(onDisk).Get(path, onDisk)
+		if err != nil {
+			return err
+		}
+		if err := v.writeStored(path, data); err != nil {
+			return fmt.Errorf("migrate %s: %w", path, err)
+		}
+		moved++
+		return nil
+	})
+	return moved, err
+}
+
+// walkStoredFiles calls fn with each existing auth and config file in the
+// vault's profiles and its bytes on disk.
+func (v *Vault) walkStoredFiles(fn func(path string, onDisk []byte) error) error {
+	tools, err := os.ReadDir(v.basePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, toolEntry := range tools {
+		if !toolEntry.IsDir() {
+			continue
+		}
+		fileSet, ok := GetAuthFileSet(toolEntry.Name())
+		if !ok {
+			continue
+		}
+		names := make(map[string]bool)
+		for _, spec := range fileSet.Files {
+			names[filepath.Base(spec.Path)] = true
+		}
+		for _, spec := range fileSet.ConfigFiles {
+			names[filepath.Base(spec.Path)] = true
+		}
+
+		toolDir := filepath.Join(v.basePath, toolEntry.Name())
+		profiles, err := os.ReadDir(toolDir)
+		if err != nil {
+			return err
+		}
+		for _, profileEntry := range profiles {
+			if !profileEntry.IsDir() {
+				continue
+			}
+			for name := range names {
+				path := filepath.Join(toolDir, profileEntry.Name(), name)
+				data, err := os.ReadFile(path)
+				if err != nil {
+					if os.IsNotExist(err) {
+						continue
+					}
+					return err
+				}
+				if err := fn(path, data); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// readStored returns the stored bytes of a vault file, fetching them from
+// the keychain for a stub. Sealed files stay sealed.
+func (v *Vault) readStored(path string) ([]byte, error) {
+	onDisk, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return v.backendFor(onDisk).Get(path, onDisk)
+}
+
+// writeStored stores data as the contents of a vault file with the
+// configured backend, releasing what another backend held for it.
+func (v *Vault) writeStored(path string, data []byte) error {
+	before, _ := os.ReadFile(path)
+	if err := v.Storage().Put(v, path, data); err != nil {
+		return err
+	}
+	if _, ok := stubAccount(before); ok {
+		after, _ := os.ReadFile(path)
+		if !bytes.Equal(before, after) {
+			return v.backendFor(before).Remove(path, before)
+		}
+	}
+	return nil
+}
+
+// removeStored deletes a vault file and what its backend holds for it.
+func (v *Vault) removeStored(path string) error {
+	if onDisk, err := os.ReadFile(path); err == nil {
+		if err := v.backendFor(onDisk).Remove(path, onDisk); err != nil {
+			return err
+		}
+	}
+	return os.Remove(path)
+}
+
+// removeStoredDir releases the backend items of every file in dir, before
+// the directory is removed.
+func (v *Vault) removeStoredDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		onDisk, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := v.backendFor(onDisk).Remove(path, onDisk); err != nil {
+			return fmt.Errorf("remove %s from the keychain: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// backendFor returns the backend holding a file's contents, judged by the
+// bytes on disk: a stub belongs to the keychain whatever is configured.
+func (v *Vault) backendFor(onDisk []byte) StorageBackend {
+	if _, ok := stubAccount(onDisk); !ok {
+		return FileBackend{}
+	}
+	if kb, ok := v.Storage().(*KeychainBackend); ok {
+		return kb
+	}
+	keyringMu.Lock()
+	kr := defaultKeyring
+	keyringMu.Unlock()
+	return NewKeychainBackend(kr())
+}
+
+// hasStubs reports whether any file in dir keeps its contents in the keychain.
+func hasStubs(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if hasStub(filepath.Join(dir, entry.Name())) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasStub reports whether the vault file at path is a keychain stub.
+func hasStub(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	_, ok := stubAccount(data)
+	return ok
+}
+
+func stubAccount(data []byte) (string, bool) {
+	if !bytes.HasPrefix(data, keychainMagic) {
+		return "", false
+	}
+	return string(data[len(keychainMagic):]), true
+}
+
+// keychainAccount names the keychain item of the vault file at path: its
+// absolute path, which is unique across vaults and config contexts.
+func keychainAccount(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(filepath.Clean(abs)), nil
+}
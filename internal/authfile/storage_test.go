@@ -0,0 +1,221 @@
+package authfile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+func TestKeychainStorage_BackupRestoreMigrate(t *testing.T) {
+	tmpDir := t.TempDir()
+	kr := keyring.NewMemory()
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	v.SetActivationMode(ActivationSymlink)
+	v.SetStorage(NewKeychainBackend(kr))
+
+	authFile := filepath.Join(tmpDir, "codex", "auth.json")
+	fileSet := AuthFileSet{
+		Tool:  "codex",
+		Files: []AuthFileSpec{{Tool: "codex", Path: authFile, Required: true}},
+	}
+	if err := os.MkdirAll(filepath.Dir(authFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"work", "home"} {
+		if err := os.WriteFile(authFile, []byte(`{"tokens":{"access_token":"`+name+`"}}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.Backup(fileSet, name); err != nil {
+			t.Fatalf("Backup(%s) error = %v", name, err)
+		}
+	}
+
+	workPath := v.BackupPath("codex", "work", "auth.json")
+	stub, err := os.ReadFile(workPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, ok := stubAccount(stub)
+	if !ok || bytes.Contains(stub, []byte("access_token")) {
+		t.Fatalf("vault file should be a keychain stub, got %q", stub)
+	}
+	if _, err := kr.Get(keychainService, account); err != nil {
+		t.Fatalf("keychain item missing: %v", err)
+	}
+	if profiles, _ := v.List("codex"); len(profiles) != 2 {
+		t.Errorf("List() = %v, want both profiles", profiles)
+	}
+
+	// Symlink mode can't link to a stub, so the profile is copied out.
+	if err := v.Restore(fileSet, "work"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if info, _ := os.Lstat(authFile); info.Mode()&os.ModeSymlink != 0 {
+		t.Error("keychain storage should activate by copy")
+	}
+	if got, _ := os.ReadFile(authFile); string(got) != `{"tokens":{"access_token":"work"}}` {
+		t.Errorf("restored auth = %s", got)
+	}
+	if active, _ := v.ActiveProfile(fileSet); active != "work" {
+		t.Errorf("ActiveProfile() = %q, want work", active)
+	}
+
+	dir, cleanup, err := v.PlainProfileDir("codex", "home")
+	if err != nil {
+		t.Fatalf("PlainProfileDir() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "auth.json")); string(got) != `{"tokens":{"access_token":"home"}}` {
+		t.Errorf("plain copy = %s", got)
+	}
+	cleanup()
+
+	// Deleting a profile removes its keychain items.
+	homeStub, _ := os.ReadFile(v.BackupPath("codex", "home", "auth.json"))
+	homeAccount, _ := stubAccount(homeStub)
+	if err := v.Delete("codex", "home"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := kr.Get(keychainService, homeAccount); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("keychain item of a deleted profile should be gone, got %v", err)
+	}
+
+	// Switching back to files moves the contents out of the keychain.
+	SetDefaultKeyring(func() keyring.Backend { return kr })
+	t.Cleanup(func() { SetDefaultKeyring(keyring.System) })
+	v.SetStorage(FileBackend{})
+	n, err := v.MigrateStorage()
+	if err != nil || n != 1 {
+		t.Fatalf("MigrateStorage() = %d, %v; want 1 file", n, err)
+	}
+	if got, _ := os.ReadFile(workPath); string(got) != `{"tokens":{"access_token":"work"}}` {
+		t.Errorf("migrated file = %s", got)
+	}
+	if _, err := kr.Get(keychainService, account); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("keychain item should be removed after migrating, got %v", err)
+	}
+	if n, _ := v.MigrateStorage(); n != 0 {
+		t.Errorf("second MigrateStorage() moved %d files, want 0", n)
+	}
+}
+
+func TestKeychainStorage_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	v := NewVault(filepath.Join(tmpDir, "vault"))
+	v.SetStorage(NewKeychainBackend(keyring.NewMemory()))
+
+	authFile := filepath.Join(tmpDir, "auth.json")
+	if err := os.WriteFile(authFile, []byte(`{}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := AuthFileSet{Tool: "codex", Files: []AuthFileSpec{{Tool: "codex", Path: authFile, Required: true}}}
+	if err := v.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// A vault read by another keyring finds the stub but not the contents.
+	other := NewVault(v.BasePath())
+	other.SetStorage(NewKeychainBackend(keyring.NewMemory()))
+	if _, err := other.ReadFile(v.BackupPath("codex", "work", "auth.json")); err == nil {
+		t.Error("ReadFile() should fail when the keychain item is missing")
+	}
+
+	shared := NewVault(filepath.Join(tmpDir, "shared"))
+	shared.SetShareable(true)
+	shared.SetStorage(NewKeychainBackend(keyring.NewMemory()))
+	if err := shared.Backup(fileSet, "work"); err == nil {
+		t.Error("the shared vault should refuse keychain storage")
+	}
+
+	if _, err := ParseStorage("s3", nil); err == nil {
+		t.Error("ParseStorage() should reject unknown backends")
+	}
+	if b, err := ParseStorage("", nil); err != nil || b.Name() != StorageFile {
+		t.Errorf("ParseStorage(\"\") = %v, %v; want file", b, err)
+	}
+}
+
+func TestKeychainStorage_SplitsLongContents(t *testing.T) {
+	kr := keyring.NewMemory()
+	b := NewKeychainBackend(kr)
+	v := NewVault(t.TempDir())
+	path := filepath.Join(v.BasePath(), "codex", "work", "auth.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	long := bytes.Repeat([]byte("x"), 2*keychainChunk) // 8000 base64 characters
+	if err := b.Put(v, path, long); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	stub, _ := os.ReadFile(path)
+	account, _ := stubAccount(stub)
+	head, _ := kr.Get(keychainService, account)
+	if head != "parts:3" {
+		t.Fatalf("head item = %q, want parts:3", head)
+	}
+	if got, err := b.Get(path, stub); err != nil || !bytes.Equal(got, long) {
+		t.Fatalf("Get() = %d bytes, %v; want the long contents", len(got), err)
+	}
+
+	// Shorter contents drop the stale parts.
+	if err := b.Put(v, path, []byte(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kr.Get(keychainService, partAccount(account, 1)); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("part 1 should be gone, got %v", err)
+	}
+	if got, _ := b.Get(path, stub); string(got) != `{}` {
+		t.Errorf("Get() = %q", got)
+	}
+	if err := b.Remove(path, stub); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kr.Get(keychainService, account); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("Remove() left the item, got %v", err)
+	}
+}
+
+func TestSavePlainProfileDir_KeepsKeychainStubs(t *testing.T) {
+	tmpDir := t.TempDir()
+	kr := keyring.NewMemory()
+	SetDefaultKeyring(func() keyring.Backend { return kr })
+	t.Cleanup(func() { SetDefaultKeyring(keyring.System) })
+
+	keychain := NewVault(filepath.Join(tmpDir, "vault"))
+	keychain.SetStorage(NewKeychainBackend(kr))
+	authFile := filepath.Join(tmpDir, "auth.json")
+	if err := os.WriteFile(authFile, []byte(`{"access_token":"old"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := AuthFileSet{Tool: "codex", Files: []AuthFileSpec{{Tool: "codex", Path: authFile, Required: true}}}
+	if err := keychain.Backup(fileSet, "work"); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// A vault left on the default file backend, like a bare NewVault.
+	v := NewVault(keychain.BasePath())
+	dir, cleanup, err := v.PlainProfileDir("codex", "work")
+	if err != nil {
+		t.Fatalf("PlainProfileDir() error = %v", err)
+	}
+	defer cleanup()
+	if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"new"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.SavePlainProfileDir("codex", "work", dir); err != nil {
+		t.Fatalf("SavePlainProfileDir() error = %v", err)
+	}
+
+	path := v.BackupPath("codex", "work", "auth.json")
+	if !hasStub(path) {
+		onDisk, _ := os.ReadFile(path)
+		t.Fatalf("vault file = %q, want it still a keychain stub", onDisk)
+	}
+	if got, err := v.ReadFile(path); err != nil || string(got) != `{"access_token":"new"}` {
+		t.Errorf("ReadFile() = %q, %v; want the saved token", got, err)
+	}
+}
@@ -0,0 +1,323 @@
+// Package authforward lends vault logins to caam on another machine over a
+// forwarded Unix socket, the way ssh-agent lends keys. The local side serves
+// a profile's auth files on request; the remote side writes them into a
+// profile in an in-memory directory for one command and wipes it when the
+// command exits, so the tokens never reach the remote disk.
+//
+// The protocol is one JSON request line and one JSON response line per
+// connection:
+//
+//	{"op":"fetch","tool":"claude","profile":"work"}
+//	{"ok":true,"tool":"claude","profile":"work","files":[{"name":".credentials.json","data":"..."}]}
+package authforward
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+)
+
+// SocketEnv names the environment variable the remote side reads the
+// forwarded socket's path from, like SSH_AUTH_SOCK.
+const SocketEnv = "CAAM_AUTH_SOCK"
+
+// Request ops.
+const (
+	OpFetch = "fetch"
+	OpList  = "list"
+)
+
+const (
+	// maxRequestBytes bounds a request line.
+	maxRequestBytes = 64 << 10
+	// maxResponseBytes bounds a response line; auth files are small.
+	maxResponseBytes = 16 << 20
+	// ioTimeout bounds each connection on both sides.
+	ioTimeout = 30 * time.Second
+)
+
+// Request asks the serving caam for something.
+type Request struct {
+	Op      string `json:"op"`
+	Tool    string `json:"tool,omitempty"`
+	Profile string `json:"profile,omitempty"`
+}
+
+// File is one auth file, by the base name the vault stores it under.
+type File struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// Response answers a Request.
+type Response struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error,omitempty"`
+	Tool    string `json:"tool,omitempty"`
+	Profile string `json:"profile,omitempty"`
+	Files   []File `json:"files,omitempty"`
+	// Profiles lists what the server will lend, by tool (OpList).
+	Profiles map[string][]string `json:"profiles,omitempty"`
+}
+
+// Server lends vault profiles to whoever can connect to its socket.
+type Server struct {
+	// Vault is where profiles are read from.
+	Vault *authfile.Vault
+	// FileSet returns a tool's auth file set, or false for unknown tools.
+	FileSet func(tool string) (authfile.AuthFileSet, bool)
+	// Tools lists the tools OpList reports on.
+	Tools []string
+	// Allow restricts what is lent to "tool/profile" patterns (path.Match
+	// syntax, e.g. "claude/*"); empty allows every profile.
+	Allow []string
+	// Logf, if set, is told about each request.
+	Logf func(format string, a ...any)
+}
+
+// Allowed reports whether tool/profile may be lent.
+func (s *Server) Allowed(tool, profile string) bool {
+	if len(s.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range s.Allow {
+		if ok, _ := path.Match(pattern, tool+"/"+profile); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Serve answers connections on l until ctx is done or l fails.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	stop := context.AfterFunc(ctx, func() { _ = l.Close() })
+	defer stop()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	var resp Response
+	reader := bufio.NewReader(&limitedReader{r: conn, n: maxRequestBytes})
+	line, err := reader.ReadBytes('\n')
+	var req Request
+	switch {
+	case err != nil && len(line) == 0:
+		return
+	case json.Unmarshal(line, &req) != nil:
+		resp = Response{Error: "malformed request"}
+	default:
+		resp = s.Answer(req)
+	}
+	if s.Logf != nil {
+		what := req.Op
+		if req.Op == OpFetch {
+			what = fmt.Sprintf("%s %s/%s", req.Op, req.Tool, req.Profile)
+		}
+		if resp.OK {
+			s.Logf("%s: ok", what)
+		} else {
+			s.Logf("%s: refused: %s", what, resp.Error)
+		}
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	_, _ = conn.Write(append(data, '\n'))
+}
+
+// Answer handles one request.
+func (s *Server) Answer(req Request) Response {
+	switch req.Op {
+	case OpFetch:
+		files, err := s.fetch(req.Tool, req.Profile)
+		if err != nil {
+			return Response{Error: err.Error(), Tool: req.Tool, Profile: req.Profile}
+		}
+		return Response{OK: true, Tool: req.Tool, Profile: req.Profile, Files: files}
+	case OpList:
+		profiles := make(map[string][]string)
+		for _, tool := range s.Tools {
+			names, err := s.Vault.List(tool)
+			if err != nil {
+				continue
+			}
+			for _, name := range names {
+				if !authfile.IsSystemProfile(name) && s.Allowed(tool, name) {
+					profiles[tool] = append(profiles[tool], name)
+				}
+			}
+			sort.Strings(profiles[tool])
+		}
+		return Response{OK: true, Profiles: profiles}
+	default:
+		return Response{Error: fmt.Sprintf("unknown op %q", req.Op)}
+	}
+}
+
+func (s *Server) fetch(tool, profile string) ([]File, error) {
+	tool = strings.ToLower(strings.TrimSpace(tool))
+	profile = strings.TrimSpace(profile)
+	if tool == "" || profile == "" {
+		return nil, errors.New("tool and profile are required")
+	}
+	fileSet, ok := s.FileSet(tool)
+	if !ok {
+		return nil, fmt.Errorf("unknown tool: %s", tool)
+	}
+	if err := authfile.ValidateProfileName(profile); err != nil {
+		return nil, err
+	}
+	if !s.Allowed(tool, profile) {
+		return nil, fmt.Errorf("%s/%s is not lent by this agent", tool, profile)
+	}
+
+	dir := s.Vault.ProfilePath(tool, profile)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("profile %s/%s not found in vault", tool, profile)
+	}
+	var files []File
+	for _, spec := range fileSet.Files {
+		name := filepath.Base(spec.Path)
+		data, err := s.Vault.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if os.IsNotExist(err) && (!spec.Required || fileSet.AllowOptionalOnly) {
+				continue
+			}
+			return nil, fmt.Errorf("read %s: %w", name, err)
+		}
+		files = append(files, File{Name: name, Data: data})
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("profile %s/%s has no auth files", tool, profile)
+	}
+	return files, nil
+}
+
+// limitedReader fails reads past n bytes so a client can't make the server
+// buffer without bound.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		return 0, errors.New("request too large")
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
+
+// Call sends req to the agent at socket and returns its response. A
+// response with OK false is returned as an error.
+func Call(socket string, req Request) (*Response, error) {
+	if strings.TrimSpace(socket) == "" {
+		return nil, fmt.Errorf("no forwarding socket: set %s or pass --socket", SocketEnv)
+	}
+	conn, err := net.DialTimeout("unix", socket, ioTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connect to forwarding agent at %s: %w", socket, err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(ioTimeout))
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	line, err := bufio.NewReader(&limitedReader{r: conn, n: maxResponseBytes}).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return nil, fmt.Errorf("parse response: %w", err)
+	}
+	if !resp.OK {
+		return nil, fmt.Errorf("forwarding agent: %s", resp.Error)
+	}
+	return &resp, nil
+}
+
+// Fetch asks the agent at socket for tool/profile's auth files.
+func Fetch(socket, tool, profile string) ([]File, error) {
+	resp, err := Call(socket, Request{Op: OpFetch, Tool: tool, Profile: profile})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Files, nil
+}
+
+// List asks the agent at socket which profiles it lends.
+func List(socket string) (map[string][]string, error) {
+	resp, err := Call(socket, Request{Op: OpList})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Profiles, nil
+}
+
+// DefaultSocketPath is where 'caam forward serve' listens unless told
+// otherwise.
+func DefaultSocketPath() string {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return filepath.Join(dir, "caam-forward.sock")
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("caam-%d", os.Getuid()), "forward.sock")
+}
+
+// Listen opens the agent socket at path, readable only by the current user.
+// A leftover socket nobody answers on is replaced; a live one is an error.
+func Listen(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create socket dir: %w", err)
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("a forwarding agent is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = l.Close()
+		return nil, fmt.Errorf("chmod socket: %w", err)
+	}
+	return l, nil
+}
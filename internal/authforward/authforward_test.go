@@ -0,0 +1,234 @@
+package authforward
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
+)
+
+// testFileSet is a two-file layout under home, the second file optional.
+func testFileSet(home string) authfile.AuthFileSet {
+	return authfile.AuthFileSet{
+		Tool: "claude",
+		Files: []authfile.AuthFileSpec{
+			{Tool: "claude", Path: filepath.Join(home, ".claude", ".credentials.json"), Required: true},
+			{Tool: "claude", Path: filepath.Join(home, ".claude.json")},
+		},
+	}
+}
+
+// startServer serves a vault holding claude/work and claude/_original on a
+// temp socket and returns the socket path.
+func startServer(t *testing.T, allow ...string) (string, *authfile.Vault) {
+	t.Helper()
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live")
+	fileSet := testFileSet(live)
+	if err := os.MkdirAll(filepath.Join(live, ".claude"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fileSet.Files[0].Path, []byte(`{"token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	vault := authfile.NewVault(filepath.Join(dir, "vault"))
+	for _, name := range []string{"work", "_original"} {
+		if err := vault.Backup(fileSet, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	socket := filepath.Join(dir, "agent.sock")
+	l, err := Listen(socket)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	server := &Server{
+		Vault: vault,
+		FileSet: func(tool string) (authfile.AuthFileSet, bool) {
+			if tool != "claude" {
+				return authfile.AuthFileSet{}, false
+			}
+			return fileSet, true
+		},
+		Tools: []string{"claude"},
+		Allow: allow,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.Serve(ctx, l) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Serve() error = %v", err)
+		}
+	})
+	return socket, vault
+}
+
+func TestFetch(t *testing.T) {
+	socket, _ := startServer(t)
+
+	files, err := Fetch(socket, "claude", "work")
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Name != ".credentials.json" || string(files[0].Data) != `{"token":"work"}` {
+		t.Errorf("Fetch() = %+v", files)
+	}
+
+	for _, tc := range []struct{ tool, profile, want string }{
+		{"claude", "missing", "not found"},
+		{"claude", "../work", "profile"},
+		{"codex", "work", "unknown tool"},
+		{"claude", "", "required"},
+	} {
+		if _, err := Fetch(socket, tc.tool, tc.profile); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("Fetch(%s, %q) error = %v, want it to mention %q", tc.tool, tc.profile, err, tc.want)
+		}
+	}
+}
+
+func TestFetch_Allow(t *testing.T) {
+	socket, _ := startServer(t, "codex/*")
+	if _, err := Fetch(socket, "claude", "work"); err == nil || !strings.Contains(err.Error(), "not lent") {
+		t.Errorf("Fetch() of a profile outside --allow: err = %v", err)
+	}
+	profiles, err := List(socket)
+	if err != nil || len(profiles) != 0 {
+		t.Errorf("List() = %v, %v; want nothing lent", profiles, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	socket, _ := startServer(t)
+	profiles, err := List(socket)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := profiles["claude"]; len(got) != 1 || got[0] != "work" {
+		t.Errorf("List() = %v, want claude: [work] (system profiles hidden)", profiles)
+	}
+}
+
+func TestCall_NoSocket(t *testing.T) {
+	if _, err := List(""); err == nil || !strings.Contains(err.Error(), SocketEnv) {
+		t.Errorf("List(\"\") error = %v, want a hint about %s", err, SocketEnv)
+	}
+	if _, err := List(filepath.Join(t.TempDir(), "nobody.sock")); err == nil {
+		t.Error("List() on a missing socket should fail")
+	}
+}
+
+func TestListen_RefusesLiveSocket(t *testing.T) {
+	socket, _ := startServer(t)
+	if _, err := Listen(socket); err == nil {
+		t.Error("Listen() on a live socket should fail")
+	}
+	info, err := os.Stat(socket)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("socket mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+}
+
+func TestScratchMaterializeWipe(t *testing.T) {
+	t.Setenv(DirEnv, t.TempDir())
+	dir, err := NewScratchDir()
+	if err != nil {
+		t.Fatalf("NewScratchDir() error = %v", err)
+	}
+	fileSet := testFileSet(filepath.Join(dir, "home"))
+
+	written, err := Materialize([]File{{Name: ".credentials.json", Data: []byte("secret")}}, fileSet)
+	if err != nil || len(written) != 1 || written[0] != fileSet.Files[0].Path {
+		t.Fatalf("Materialize() = %v, %v", written, err)
+	}
+	if data, _ := os.ReadFile(fileSet.Files[0].Path); string(data) != "secret" {
+		t.Errorf("materialized content = %q", data)
+	}
+	if _, err := Materialize([]File{{Name: "../../escape", Data: []byte("x")}}, fileSet); err == nil {
+		t.Error("Materialize() should refuse files the tool doesn't have")
+	}
+
+	if err := Wipe(dir); err != nil {
+		t.Fatalf("Wipe() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("scratch dir still exists after Wipe(): %v", err)
+	}
+}
+
+func TestNewScratchDir_NoMemoryDir(t *testing.T) {
+	t.Setenv(DirEnv, filepath.Join(t.TempDir(), "missing"))
+	if _, err := NewScratchDir(); !errors.Is(err, ErrNoMemoryDir) {
+		t.Errorf("NewScratchDir() error = %v, want ErrNoMemoryDir", err)
+	}
+}
+
+func TestFetch_EncryptedVaultLendsPlaintext(t *testing.T) {
+	dir := t.TempDir()
+	fileSet := testFileSet(filepath.Join(dir, "live"))
+	if err := os.MkdirAll(filepath.Dir(fileSet.Files[0].Path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fileSet.Files[0].Path, []byte(`{"token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	vault := authfile.NewVault(filepath.Join(dir, "vault"))
+	if _, err := vault.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	if err := vault.Backup(fileSet, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if raw, _ := os.ReadFile(vault.BackupPath("claude", "work", ".credentials.json")); strings.Contains(string(raw), "work") {
+		t.Fatal("vault copy should be sealed")
+	}
+
+	server := &Server{
+		Vault:   vault,
+		FileSet: func(string) (authfile.AuthFileSet, bool) { return fileSet, true },
+		Tools:   []string{"claude"},
+	}
+	files, err := server.fetch("claude", "work")
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+	if len(files) != 1 || string(files[0].Data) != `{"token":"work"}` {
+		t.Errorf("fetch() = %+v, want the decrypted login", files)
+	}
+}
+
+func TestFetch_KeychainStorageLendsContents(t *testing.T) {
+	dir := t.TempDir()
+	fileSet := testFileSet(filepath.Join(dir, "live"))
+	if err := os.MkdirAll(filepath.Dir(fileSet.Files[0].Path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fileSet.Files[0].Path, []byte(`{"token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	vault := authfile.NewVault(filepath.Join(dir, "vault"))
+	vault.SetStorage(authfile.NewKeychainBackend(keyring.NewMemory()))
+	if err := vault.Backup(fileSet, "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{
+		Vault:   vault,
+		FileSet: func(string) (authfile.AuthFileSet, bool) { return fileSet, true },
+		Tools:   []string{"claude"},
+	}
+	files, err := server.fetch("claude", "work")
+	if err != nil {
+		t.Fatalf("fetch() error = %v", err)
+	}
+	if len(files) != 1 || string(files[0].Data) != `{"token":"work"}` {
+		t.Errorf("fetch() = %+v, want the contents, not the keychain stub", files)
+	}
+}
@@ -0,0 +1,104 @@
+package authforward
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+)
+
+// DirEnv names an environment variable that overrides where borrowed
+// profiles are written. Whoever sets it vouches that it is memory-backed.
+const DirEnv = "CAAM_FORWARD_DIR"
+
+// ErrNoMemoryDir is returned by NewScratchDir when no memory-backed
+// directory is known.
+var ErrNoMemoryDir = errors.New("no memory-backed directory for borrowed auth")
+
+// memoryDirs returns the directories to try for borrowed profiles, best
+// first: DirEnv, then the per-user runtime dir and /dev/shm, which are tmpfs
+// on Linux.
+func memoryDirs() []string {
+	var dirs []string
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return []string{dir}
+	}
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	return append(dirs, "/dev/shm")
+}
+
+// NewScratchDir creates a private directory for one borrowed profile in
+// memory-backed storage. It fails with ErrNoMemoryDir rather than fall back
+// to disk.
+func NewScratchDir() (string, error) {
+	for _, base := range memoryDirs() {
+		if info, err := os.Stat(base); err != nil || !info.IsDir() {
+			continue
+		}
+		dir, err := os.MkdirTemp(base, "caam-forward-")
+		if err != nil {
+			continue
+		}
+		if err := os.Chmod(dir, 0700); err != nil {
+			_ = os.RemoveAll(dir)
+			continue
+		}
+		return dir, nil
+	}
+	return "", fmt.Errorf("%w (tried $XDG_RUNTIME_DIR and /dev/shm; set %s to a tmpfs path)", ErrNoMemoryDir, DirEnv)
+}
+
+// Materialize writes borrowed files to the paths fileSet gives them, which
+// should point into a scratch dir. Files the set doesn't know are refused,
+// so a server can't write outside it.
+func Materialize(files []File, fileSet authfile.AuthFileSet) ([]string, error) {
+	paths := make(map[string]string, len(fileSet.Files))
+	for _, spec := range fileSet.Files {
+		paths[filepath.Base(spec.Path)] = spec.Path
+	}
+	var written []string
+	for _, f := range files {
+		dst, ok := paths[f.Name]
+		if !ok {
+			return written, fmt.Errorf("unexpected auth file %q for %s", f.Name, fileSet.Tool)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(dst, f.Data, 0600); err != nil {
+			return written, err
+		}
+		written = append(written, dst)
+	}
+	return written, nil
+}
+
+// Wipe overwrites every regular file under dir with zeros and removes dir.
+// Overwriting matters little on tmpfs but costs nothing, and protects the
+// DirEnv case where the directory turned out not to be.
+func Wipe(dir string) error {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			_, _ = f.Write(make([]byte, info.Size()))
+			_ = f.Close()
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
@@ -292,7 +292,8 @@ func (t *Tracker) FindMatchingProfile(provider string) (string, error) {
 	return "", nil
 }
 
-// getProfileHash computes the content hash of a saved profile.
+// getProfileHash computes the content hash of a saved profile's plaintext,
+// so it matches the live files however the vault stores them.
 func (t *Tracker) getProfileHash(provider, profile string) (string, error) {
 	profilePath := t.vault.ProfilePath(provider, profile)
 	fileSet := getFileSet(provider)
@@ -307,7 +308,7 @@ func (t *Tracker) getProfileHash(provider, profile string) (string, error) {
 		fileName := filepath.Base(spec.Path)
 		profileFilePath := filepath.Join(profilePath, fileName)
 
-		content, err := os.ReadFile(profileFilePath)
+		content, err := t.vault.ReadFile(profileFilePath)
 		if err != nil {
 			if os.IsNotExist(err) {
 				if spec.Required {
@@ -1177,3 +1177,32 @@ func TestGetStatusWithMatchingProfile(t *testing.T) {
 		t.Error("expected no SuggestedAction when profile matches")
 	}
 }
+
+func TestFindMatchingProfile_EncryptedVault(t *testing.T) {
+	tmpDir := t.TempDir()
+	codexDir := filepath.Join(tmpDir, "codex")
+	t.Setenv("CODEX_HOME", codexDir)
+	if err := os.MkdirAll(codexDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(codexDir, "auth.json"), []byte(`{"token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	vault := authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	if _, err := vault.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	if err := vault.Backup(authfile.CodexAuthFiles(), "work"); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := NewTracker(vault)
+	if _, err := tracker.Capture("codex"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := tracker.FindMatchingProfile("codex")
+	if err != nil || got != "work" {
+		t.Errorf("FindMatchingProfile() = %q, %v; want work", got, err)
+	}
+}
@@ -3,10 +3,12 @@ package browser
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -168,11 +170,15 @@ func BrowserHelperScript(caamBinary, profileName string) string {
 	return `sh -c '"` + shellEscape(caamBinary) + `" browser-open --profile="` + shellEscape(profileName) + `" "$1"' _`
 }
 
-// WriteBrowserHelper writes a browser helper script to a temporary file.
-// Returns the path to the script. Caller is responsible for cleanup.
+// WriteBrowserHelper writes a browser helper script to a temporary file
+// private to the current user. Returns the path to the script. Caller is
+// responsible for cleanup.
 // Note: Both caamBinary and profileName are escaped to prevent shell injection.
 func WriteBrowserHelper(caamBinary, profileName string) (string, error) {
-	tmpDir := os.TempDir()
+	tmpDir, err := userTempDir()
+	if err != nil {
+		return "", err
+	}
 	scriptPath := filepath.Join(tmpDir, "caam-browser-helper.sh")
 
 	// Escape inputs to prevent shell injection attacks
@@ -181,13 +187,41 @@ func WriteBrowserHelper(caamBinary, profileName string) (string, error) {
 exec "` + shellEscape(caamBinary) + `" browser-open --profile="` + shellEscape(profileName) + `" "$1"
 `
 
-	if err := os.WriteFile(scriptPath, []byte(script), 0755); err != nil {
+	if err := os.WriteFile(scriptPath, []byte(script), 0700); err != nil {
 		return "", err
 	}
 
 	return scriptPath, nil
 }
 
+// userTempDir returns a directory under the system temp dir that only the
+// current user can access, so users sharing a host never run each other's
+// helper scripts.
+func userTempDir() (string, error) {
+	uid := os.Getuid()
+	if uid < 0 {
+		// Windows: the temp dir is already per-user.
+		return os.TempDir(), nil
+	}
+	dir := filepath.Join(os.TempDir(), "caam-"+strconv.Itoa(uid))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s is not a directory", dir)
+	}
+	// Chmod fails unless we own the directory, which rejects one pre-created
+	// by another user.
+	if err := os.Chmod(dir, 0700); err != nil {
+		return "", fmt.Errorf("secure %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
 // DetectedURL represents a URL found in command output.
 type DetectedURL struct {
 	URL    string
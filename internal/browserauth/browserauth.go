@@ -0,0 +1,255 @@
+// Package browserauth runs a provider's OAuth authorization-code login in a
+// local browser. The authorize URL is opened with PKCE (RFC 7636), the
+// redirect is caught on a localhost listener and the code is exchanged for
+// tokens, so an account can be onboarded without launching the vendor CLI.
+package browserauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Spec describes a provider's OAuth client.
+type Spec struct {
+	AuthorizeURL string
+	TokenURL     string
+	ClientID     string
+	Scopes       []string
+
+	// Port and CallbackPath make up the redirect URI,
+	// http://localhost:<Port><CallbackPath>, which must be the one registered
+	// for ClientID. Port 0 picks a free port (tests only: providers reject
+	// unregistered redirect URIs).
+	Port         int
+	CallbackPath string
+
+	// Params are extra query parameters for the authorize URL.
+	Params map[string]string
+}
+
+// specs holds the providers whose browser login is known. Codex's public
+// client accepts a localhost redirect on port 1455, as its CLI uses.
+var specs = map[string]Spec{
+	"codex": {
+		AuthorizeURL: "https://auth.openai.com/oauth/authorize",
+		TokenURL:     "https://auth.openai.com/oauth/token",
+		ClientID:     "app_EMoamEEZ73f0CkXaXp7hrann",
+		Scopes:       []string{"openid", "profile", "email", "offline_access"},
+		Port:         1455,
+		CallbackPath: "/auth/callback",
+		Params: map[string]string{
+			"id_token_add_organizations": "true",
+			"codex_cli_simplified_flow":  "true",
+		},
+	},
+}
+
+// SpecFor returns tool's OAuth client, if its browser login is supported.
+func SpecFor(tool string) (Spec, bool) {
+	spec, ok := specs[tool]
+	return spec, ok
+}
+
+// Supported returns the tools with a browser login, sorted.
+func Supported() []string {
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tokens is the token endpoint's answer.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int    `json:"expires_in,omitempty"` // Seconds
+}
+
+// ExpiresAt returns when the access token expires, or the zero time when the
+// endpoint didn't say.
+func (t *Tokens) ExpiresAt(issued time.Time) time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return issued.Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// ErrDenied is returned when the user or provider refused the consent.
+var ErrDenied = errors.New("authorization denied")
+
+// maxTokenBody bounds how much of the token response is read.
+const maxTokenBody = 1 << 20
+
+// Login runs spec's authorization-code flow. open is handed the authorize URL
+// once the callback listener is up; Login then waits for the redirect until
+// ctx is done.
+func Login(ctx context.Context, spec Spec, open func(authURL string) error) (*Tokens, error) {
+	verifier, err := randomString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString(16)
+	if err != nil {
+		return nil, err
+	}
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", spec.Port))
+	if err != nil {
+		return nil, fmt.Errorf("listen for the OAuth callback: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://localhost:%d%s", ln.Addr().(*net.TCPAddr).Port, spec.CallbackPath)
+
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(spec.CallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res result
+		switch {
+		case subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(state)) != 1:
+			// Not our redirect; keep waiting for the real one.
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("%w: %s", ErrDenied, strings.TrimSpace(q.Get("error")+" "+q.Get("error_description")))
+		case q.Get("code") == "":
+			res.err = errors.New("callback carried no authorization code")
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			writePage(w, http.StatusBadRequest, "Login failed", res.err.Error())
+		} else {
+			writePage(w, http.StatusOK, "Login complete", "You can close this tab and return to the terminal.")
+		}
+		select {
+		case results <- res:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	if err := open(authorizeURL(spec, redirectURI, state, challenge(verifier))); err != nil {
+		return nil, err
+	}
+
+	var res result
+	select {
+	case res = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for the browser login: %w", ctx.Err())
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+	return exchange(ctx, spec, res.code, redirectURI, verifier)
+}
+
+// authorizeURL builds the consent page URL.
+func authorizeURL(spec Spec, redirectURI, state, codeChallenge string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", spec.ClientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("scope", strings.Join(spec.Scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	for k, v := range spec.Params {
+		q.Set(k, v)
+	}
+	sep := "?"
+	if strings.Contains(spec.AuthorizeURL, "?") {
+		sep = "&"
+	}
+	return spec.AuthorizeURL + sep + q.Encode()
+}
+
+// exchange trades the authorization code for tokens.
+func exchange(ctx context.Context, spec Spec, code, redirectURI, verifier string) (*Tokens, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", spec.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, spec.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenBody))
+	if err != nil {
+		return nil, fmt.Errorf("read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var tok Tokens
+	if err := json.Unmarshal(body, &tok); err != nil {
+		return nil, fmt.Errorf("decode token response: %w", err)
+	}
+	if tok.AccessToken == "" && tok.RefreshToken == "" {
+		return nil, errors.New("token response carried no tokens")
+	}
+	return &tok, nil
+}
+
+// randomString returns n random bytes, base64url encoded without padding.
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// challenge is the S256 code challenge for verifier.
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func writePage(w http.ResponseWriter, status int, title, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<!doctype html><title>caam: %s</title><h1>%s</h1><p>%s</p>\n",
+		html.EscapeString(title), html.EscapeString(title), html.EscapeString(message))
+}
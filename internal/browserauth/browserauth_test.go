@@ -0,0 +1,140 @@
+package browserauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// tokenServer answers authorization-code exchanges whose code_verifier
+// matches the challenge the consent page was opened with.
+func tokenServer(t *testing.T, challenges map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		if r.Form.Get("grant_type") != "authorization_code" || r.Form.Get("client_id") != "client-1" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if challenge(r.Form.Get("code_verifier")) != challenges[r.Form.Get("code")] {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access-1",
+			"refresh_token": "refresh-1",
+			"id_token":      "id-1",
+			"expires_in":    3600,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testSpec(tokenURL string) Spec {
+	return Spec{
+		AuthorizeURL: "https://auth.example.com/authorize",
+		TokenURL:     tokenURL,
+		ClientID:     "client-1",
+		Scopes:       []string{"openid", "offline_access"},
+		CallbackPath: "/auth/callback",
+		Params:       map[string]string{"prompt": "login"},
+	}
+}
+
+// consent returns an opener standing in for the browser: it checks the
+// authorize URL and follows the redirect with the given callback query.
+func consent(t *testing.T, challenges map[string]string, callback func(state string) url.Values) func(string) error {
+	return func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		q := u.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("scope") != "openid offline_access" || q.Get("prompt") != "login" {
+			t.Errorf("authorize URL = %s", authURL)
+		}
+		challenges["code-1"] = q.Get("code_challenge")
+		go func() {
+			resp, err := http.Get(q.Get("redirect_uri") + "?" + callback(q.Get("state")).Encode())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+		return nil
+	}
+}
+
+func TestLogin(t *testing.T) {
+	challenges := map[string]string{}
+	spec := testSpec(tokenServer(t, challenges).URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	tok, err := Login(ctx, spec, consent(t, challenges, func(state string) url.Values {
+		return url.Values{"code": {"code-1"}, "state": {state}}
+	}))
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if tok.AccessToken != "access-1" || tok.RefreshToken != "refresh-1" || tok.IDToken != "id-1" {
+		t.Errorf("tokens = %+v", tok)
+	}
+	issued := time.Unix(1000, 0)
+	if got := tok.ExpiresAt(issued); !got.Equal(issued.Add(time.Hour)) {
+		t.Errorf("ExpiresAt() = %v", got)
+	}
+}
+
+func TestLogin_Denied(t *testing.T) {
+	challenges := map[string]string{}
+	spec := testSpec(tokenServer(t, challenges).URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := Login(ctx, spec, consent(t, challenges, func(state string) url.Values {
+		return url.Values{"error": {"access_denied"}, "state": {state}}
+	}))
+	if !errors.Is(err, ErrDenied) || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("Login() error = %v, want ErrDenied", err)
+	}
+}
+
+func TestLogin_IgnoresForeignState(t *testing.T) {
+	challenges := map[string]string{}
+	spec := testSpec(tokenServer(t, challenges).URL)
+
+	// A redirect with the wrong state is turned away and the login keeps
+	// waiting, here until the context runs out.
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err := Login(ctx, spec, consent(t, challenges, func(string) url.Values {
+		return url.Values{"code": {"code-1"}, "state": {"forged"}}
+	}))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Login() error = %v, want a timeout", err)
+	}
+}
+
+func TestSpecFor(t *testing.T) {
+	spec, ok := SpecFor("codex")
+	if !ok || spec.Port != 1455 || !strings.HasPrefix(spec.TokenURL, "https://auth.openai.com/") {
+		t.Errorf("SpecFor(codex) = %+v, %v", spec, ok)
+	}
+	if _, ok := SpecFor("claude"); ok {
+		t.Error("claude has no verified browser login")
+	}
+	if got := strings.Join(Supported(), ","); got != "codex" {
+		t.Errorf("Supported() = %s", got)
+	}
+}
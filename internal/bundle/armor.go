@@ -0,0 +1,78 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ArmorPrefix starts every armored payload and names its format version.
+const ArmorPrefix = "caam-profile-v1:"
+
+// MaxArmorSize caps an armored payload. Armor is meant for a single profile
+// pasted through a clipboard or terminal; larger transfers should use a
+// bundle.
+const MaxArmorSize = 256 << 10
+
+// Armor encrypts data with password and encodes it as one line of text:
+// ArmorPrefix, the base64 encryption metadata, a dot and the base64
+// ciphertext. Unlike an encrypted bundle, which keeps its metadata in a
+// .meta file beside it, an armored payload is self-contained.
+func Armor(data []byte, password string) (string, error) {
+	ciphertext, meta, err := EncryptBundle(data, password)
+	if err != nil {
+		return "", err
+	}
+	rawMeta, err := json.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("marshal encryption metadata: %w", err)
+	}
+	text := ArmorPrefix + base64.RawURLEncoding.EncodeToString(rawMeta) + "." + base64.RawURLEncoding.EncodeToString(ciphertext)
+	if len(text) > MaxArmorSize {
+		return "", fmt.Errorf("armored payload is %d bytes, over the %d byte limit; use 'caam bundle export' instead", len(text), MaxArmorSize)
+	}
+	return text, nil
+}
+
+// IsArmored reports whether text starts like an armored payload.
+func IsArmored(text []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeftFunc(text, unicode.IsSpace), []byte(ArmorPrefix))
+}
+
+// Dearmor decodes and decrypts an armored payload. Whitespace anywhere in
+// text is ignored, so a payload wrapped by a terminal still decodes.
+func Dearmor(text []byte, password string) ([]byte, error) {
+	if len(text) > MaxArmorSize {
+		return nil, fmt.Errorf("armored payload is over the %d byte limit", MaxArmorSize)
+	}
+	compact := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(text))
+	body, ok := strings.CutPrefix(compact, ArmorPrefix)
+	if !ok {
+		return nil, fmt.Errorf("not an armored caam payload (expected it to start with %q)", ArmorPrefix)
+	}
+	encMeta, encData, ok := strings.Cut(body, ".")
+	if !ok {
+		return nil, fmt.Errorf("armored payload is truncated")
+	}
+	rawMeta, err := base64.RawURLEncoding.DecodeString(encMeta)
+	if err != nil {
+		return nil, fmt.Errorf("decode armored metadata: %w", err)
+	}
+	ciphertext, err := base64.RawURLEncoding.DecodeString(encData)
+	if err != nil {
+		return nil, fmt.Errorf("decode armored data: %w", err)
+	}
+	var meta EncryptionMetadata
+	if err := json.Unmarshal(rawMeta, &meta); err != nil {
+		return nil, fmt.Errorf("parse armored metadata: %w", err)
+	}
+	return DecryptBundle(ciphertext, &meta, password)
+}
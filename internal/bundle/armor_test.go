@@ -0,0 +1,52 @@
+package bundle
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArmorRoundTrip(t *testing.T) {
+	data := []byte("archive bytes\x00\x01")
+	text, err := Armor(data, "hunter2")
+	if err != nil {
+		t.Fatalf("Armor() error = %v", err)
+	}
+	if !strings.HasPrefix(text, ArmorPrefix) || strings.ContainsAny(text, " \n") {
+		t.Fatalf("Armor() = %q, want one prefixed line", text)
+	}
+	if !IsArmored([]byte("\n  " + text)) {
+		t.Error("IsArmored() = false for an armored payload")
+	}
+
+	// Terminals wrap long lines; the payload must survive that.
+	wrapped := text[:40] + "\n" + text[40:80] + "\r\n  " + text[80:] + "\n"
+	got, err := Dearmor([]byte(wrapped), "hunter2")
+	if err != nil {
+		t.Fatalf("Dearmor() error = %v", err)
+	}
+	if string(got) != string(data) {
+		t.Errorf("Dearmor() = %q, want %q", got, data)
+	}
+
+	if _, err := Dearmor([]byte(text), "wrong"); err == nil || !strings.Contains(err.Error(), "wrong password") {
+		t.Errorf("Dearmor() with the wrong password error = %v", err)
+	}
+}
+
+func TestDearmorRejectsOtherInput(t *testing.T) {
+	for name, input := range map[string]string{
+		"not armored": "PK\x03\x04zipdata",
+		"truncated":   ArmorPrefix + "abc",
+		"bad base64":  ArmorPrefix + "!!!.???",
+	} {
+		if _, err := Dearmor([]byte(input), "pw"); err == nil {
+			t.Errorf("%s: Dearmor() should fail", name)
+		}
+	}
+	if IsArmored([]byte("caam-profile-v0:xyz")) {
+		t.Error("IsArmored() accepted another version's prefix")
+	}
+	if _, err := Dearmor([]byte(ArmorPrefix+strings.Repeat("a", MaxArmorSize)), "pw"); err == nil {
+		t.Error("Dearmor() should refuse payloads over MaxArmorSize")
+	}
+}
@@ -562,6 +562,8 @@ func (e *VaultExporter) populateSourceInfo(manifest *ManifestV1) error {
 	manifest.Source.Platform = runtime.GOOS
 	manifest.Source.Arch = runtime.GOARCH
 	manifest.Source.CAAMDataPath = e.DataPath
+	layout := CurrentPathLayout()
+	manifest.Source.Layout = &layout
 
 	if u, err := user.Current(); err == nil {
 		manifest.Source.Username = u.Username
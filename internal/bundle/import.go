@@ -82,6 +82,25 @@ type ImportOptions struct {
 
 	// SyncPath is the local sync configuration path.
 	SyncPath string
+
+	// SkipPathRemap keeps absolute paths embedded in auth files (such as
+	// Claude's apiKeyHelper) as they were on the exporting machine.
+	SkipPathRemap bool
+
+	// LocalLayout is the layout embedded paths are remapped to (nil = this
+	// machine's).
+	LocalLayout *PathLayout
+
+	// SnapshotDir is where profiles are snapshotted before they're
+	// overwritten (empty = SnapshotDir(VaultPath)).
+	SnapshotDir string
+}
+
+func (o *ImportOptions) snapshotDir() string {
+	if o.SnapshotDir != "" {
+		return o.SnapshotDir
+	}
+	return SnapshotDir(o.VaultPath)
 }
 
 // DefaultImportOptions returns sensible defaults for import.
@@ -113,6 +132,15 @@ type ImportResult struct {
 	UpdatedProfiles int
 	SkippedProfiles int
 	Errors          []string
+
+	// RemappedFiles lists the files (provider/profile/file) whose embedded
+	// paths were rewritten to the local layout.
+	RemappedFiles []string
+
+	// TransactionID identifies the pre-import snapshot; pass it to
+	// RollbackImport to undo the import. Empty for dry runs and imports
+	// that changed no profiles.
+	TransactionID string
 }
 
 // ProfileAction describes what happened to a single profile during import.
@@ -145,84 +173,121 @@ func (i *VaultImporter) Import(opts *ImportOptions) (*ImportResult, error) {
 		opts = DefaultImportOptions()
 	}
 
-	result := &ImportResult{
+	result := newImportResult()
+	tempDir, manifest, cleanup, err := i.openBundle(opts, result)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return result.orNil(), err
+	}
+
+	// If dry run, determine what would happen without doing it
+	if opts.DryRun {
+		i.previewImport(tempDir, manifest, opts, result)
+		return result, nil
+	}
+
+	// Snapshot the profiles the import will touch before changing any
+	actions := i.planProfileActions(tempDir, manifest, opts)
+	var tx *ImportTransaction
+	if changesProfiles(actions) {
+		tx, err = snapshotProfiles(opts.snapshotDir(), i.BundlePath, opts, actions)
+		if err != nil {
+			return result, fmt.Errorf("snapshot vault: %w", err)
+		}
+		result.TransactionID = tx.ID
+	}
+
+	// Import vault profiles
+	if err := i.importVault(tempDir, manifest, opts, actions, tx, result); err != nil {
+		return result, fmt.Errorf("import vault: %w", err)
+	}
+
+	// Import optional files
+	i.importOptionalFiles(tempDir, manifest, opts, result)
+
+	return result, nil
+}
+
+func newImportResult() *ImportResult {
+	return &ImportResult{
 		ProfileActions:  make([]ProfileAction, 0),
 		OptionalActions: make([]OptionalAction, 0),
 		Errors:          make([]string, 0),
 	}
+}
 
+// orNil drops a result that holds nothing worth showing, so callers keep
+// getting nil for failures before the manifest was read.
+func (r *ImportResult) orNil() *ImportResult {
+	if r.VerificationResult == nil {
+		return nil
+	}
+	return r
+}
+
+// openBundle extracts the bundle to a temporary directory, then loads and
+// verifies its manifest. cleanup, when non-nil, removes the extraction.
+func (i *VaultImporter) openBundle(opts *ImportOptions, result *ImportResult) (dir string, manifest *ManifestV1, cleanup func(), err error) {
 	// Check bundle exists
 	if _, err := os.Stat(i.BundlePath); os.IsNotExist(err) {
-		return nil, fmt.Errorf("bundle not found: %s", i.BundlePath)
+		return "", nil, nil, fmt.Errorf("bundle not found: %s", i.BundlePath)
 	}
 
 	// Check if encrypted
 	encrypted, err := IsEncrypted(i.BundlePath)
 	if err != nil {
-		return nil, fmt.Errorf("check encryption: %w", err)
+		return "", nil, nil, fmt.Errorf("check encryption: %w", err)
 	}
 	result.Encrypted = encrypted
 
 	if encrypted && opts.Password == "" {
-		return nil, fmt.Errorf("encrypted bundle requires password")
+		return "", nil, nil, fmt.Errorf("encrypted bundle requires password")
 	}
 
 	// Extract to temp directory
 	tempDir, err := os.MkdirTemp("", "caam-import-*")
 	if err != nil {
-		return nil, fmt.Errorf("create temp dir: %w", err)
+		return "", nil, nil, fmt.Errorf("create temp dir: %w", err)
 	}
-	defer os.RemoveAll(tempDir)
+	cleanup = func() { os.RemoveAll(tempDir) }
 
 	// Extract bundle
 	if encrypted {
 		if err := i.extractEncryptedBundle(tempDir, opts.Password); err != nil {
-			return nil, fmt.Errorf("extract encrypted bundle: %w", err)
+			return "", nil, cleanup, fmt.Errorf("extract encrypted bundle: %w", err)
 		}
 	} else {
 		if err := i.extractBundle(tempDir); err != nil {
-			return nil, fmt.Errorf("extract bundle: %w", err)
+			return "", nil, cleanup, fmt.Errorf("extract bundle: %w", err)
 		}
 	}
 
 	// Load and validate manifest
-	manifest, err := LoadManifest(tempDir)
+	manifest, err = LoadManifest(tempDir)
 	if err != nil {
-		return nil, fmt.Errorf("load manifest: %w", err)
+		return "", nil, cleanup, fmt.Errorf("load manifest: %w", err)
 	}
 	result.Manifest = manifest
 
 	// Check version compatibility
 	if err := IsCompatibleVersion(manifest); err != nil {
-		return nil, fmt.Errorf("version incompatible: %w", err)
+		return "", nil, cleanup, fmt.Errorf("version incompatible: %w", err)
 	}
 
 	// Verify checksums
 	verifyResult, err := VerifyChecksums(tempDir, manifest)
 	if err != nil {
-		return nil, fmt.Errorf("verify checksums: %w", err)
+		return "", nil, cleanup, fmt.Errorf("verify checksums: %w", err)
 	}
 	result.VerificationResult = verifyResult
 
 	if !verifyResult.Valid && !opts.Force {
-		return result, fmt.Errorf("checksum verification failed: %s", verifyResult.Summary())
-	}
-
-	// If dry run, determine what would happen without doing it
-	if opts.DryRun {
-		i.previewImport(tempDir, manifest, opts, result)
-		return result, nil
-	}
-
-	// Import vault profiles
-	if err := i.importVault(tempDir, manifest, opts, result); err != nil {
-		return result, fmt.Errorf("import vault: %w", err)
+		return "", nil, cleanup, fmt.Errorf("checksum verification failed: %s", verifyResult.Summary())
 	}
 
-	// Import optional files
-	i.importOptionalFiles(tempDir, manifest, opts, result)
-
-	return result, nil
+	return tempDir, manifest, cleanup, nil
 }
 
 // extractBundle extracts a regular (unencrypted) zip bundle.
@@ -355,6 +420,27 @@ func extractZipFile(f *zip.File, destDir string) error {
 // previewImport determines what would happen during import without making changes.
 func (i *VaultImporter) previewImport(bundleDir string, manifest *ManifestV1, opts *ImportOptions, result *ImportResult) {
 	// Preview vault profiles
+	for _, action := range i.planProfileActions(bundleDir, manifest, opts) {
+		result.ProfileActions = append(result.ProfileActions, action)
+
+		switch action.Action {
+		case "add":
+			result.NewProfiles++
+		case "update":
+			result.UpdatedProfiles++
+		case "skip":
+			result.SkippedProfiles++
+		}
+	}
+
+	// Preview optional files
+	i.previewOptionalFiles(manifest, opts, result)
+}
+
+// planProfileActions decides what happens to each bundle profile that passes
+// the provider and profile filters.
+func (i *VaultImporter) planProfileActions(bundleDir string, manifest *ManifestV1, opts *ImportOptions) []ProfileAction {
+	var actions []ProfileAction
 	for provider, profiles := range manifest.Contents.Vault.Profiles {
 		// Check provider filter
 		if len(opts.ProviderFilter) > 0 && !containsIgnoreCase(opts.ProviderFilter, provider) {
@@ -366,23 +452,20 @@ func (i *VaultImporter) previewImport(bundleDir string, manifest *ManifestV1, op
 			if len(opts.ProfileFilter) > 0 && !matchesAnyPattern(profile, opts.ProfileFilter) {
 				continue
 			}
-
-			action := i.determineProfileAction(bundleDir, opts, provider, profile)
-			result.ProfileActions = append(result.ProfileActions, action)
-
-			switch action.Action {
-			case "add":
-				result.NewProfiles++
-			case "update":
-				result.UpdatedProfiles++
-			case "skip":
-				result.SkippedProfiles++
-			}
+			actions = append(actions, i.determineProfileAction(bundleDir, opts, provider, profile))
 		}
 	}
+	return actions
+}
 
-	// Preview optional files
-	i.previewOptionalFiles(manifest, opts, result)
+// changesProfiles reports whether any action adds or overwrites a profile.
+func changesProfiles(actions []ProfileAction) bool {
+	for _, action := range actions {
+		if action.Action != "skip" {
+			return true
+		}
+	}
+	return false
 }
 
 // determineProfileAction determines what action to take for a profile.
@@ -579,45 +662,68 @@ func (i *VaultImporter) previewOptionalFiles(manifest *ManifestV1, opts *ImportO
 }
 
 // importVault imports vault profiles from the bundle.
-func (i *VaultImporter) importVault(bundleDir string, manifest *ManifestV1, opts *ImportOptions, result *ImportResult) error {
-	for provider, profiles := range manifest.Contents.Vault.Profiles {
-		// Check provider filter
-		if len(opts.ProviderFilter) > 0 && !containsIgnoreCase(opts.ProviderFilter, provider) {
-			continue
+// importVault copies the profiles actions don't skip into the vault. With a
+// transaction, each profile is journaled as it lands so an interrupted import
+// can be resumed; the transaction is marked complete when none failed.
+func (i *VaultImporter) importVault(bundleDir string, manifest *ManifestV1, opts *ImportOptions, actions []ProfileAction, tx *ImportTransaction, result *ImportResult) error {
+	var remapper *pathRemapper
+	if !opts.SkipPathRemap {
+		local := CurrentPathLayout()
+		if opts.LocalLayout != nil {
+			local = *opts.LocalLayout
 		}
+		remapper = newPathRemapper(sourceLayout(manifest), local)
+	}
 
-		for _, profile := range profiles {
-			// Check profile filter
-			if len(opts.ProfileFilter) > 0 && !matchesAnyPattern(profile, opts.ProfileFilter) {
-				continue
-			}
+	for _, action := range actions {
+		provider, profile := action.Provider, action.Profile
+		result.ProfileActions = append(result.ProfileActions, action)
 
-			action := i.determineProfileAction(bundleDir, opts, provider, profile)
-			result.ProfileActions = append(result.ProfileActions, action)
+		if action.Action == "skip" {
+			result.SkippedProfiles++
+			continue
+		}
 
-			if action.Action == "skip" {
-				result.SkippedProfiles++
+		// Import the profile
+		bundleProfilePath := filepath.Join(bundleDir, "vault", provider, profile)
+		localProfilePath := filepath.Join(opts.VaultPath, provider, profile)
+
+		// Rewrite embedded paths in the extracted copy before it lands
+		// in the vault.
+		if remapper != nil {
+			changed, err := remapper.remapProfilePaths(provider, bundleProfilePath)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s/%s: remap paths: %v", provider, profile, err))
 				continue
 			}
-
-			// Import the profile
-			bundleProfilePath := filepath.Join(bundleDir, "vault", provider, profile)
-			localProfilePath := filepath.Join(opts.VaultPath, provider, profile)
-
-			if err := copyProfileDirectory(bundleProfilePath, localProfilePath); err != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("%s/%s: %v", provider, profile, err))
-				continue
+			for _, name := range changed {
+				result.RemappedFiles = append(result.RemappedFiles, provider+"/"+profile+"/"+name)
 			}
+		}
 
-			switch action.Action {
-			case "add":
-				result.NewProfiles++
-			case "update":
-				result.UpdatedProfiles++
+		if err := copyProfileDirectory(bundleProfilePath, localProfilePath); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s/%s: %v", provider, profile, err))
+			continue
+		}
+		if tx != nil {
+			if err := tx.recordImported(provider, profile, localProfilePath); err != nil {
+				return fmt.Errorf("journal %s/%s: %w", provider, profile, err)
 			}
 		}
+
+		switch action.Action {
+		case "add":
+			result.NewProfiles++
+		case "update":
+			result.UpdatedProfiles++
+		}
 	}
 
+	if tx != nil && len(result.Errors) == 0 {
+		if err := tx.markCompleted(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -639,6 +640,67 @@ func TestVaultImporter_Import_ProviderFilter(t *testing.T) {
 	}
 }
 
+func TestVaultImporter_Import_RemapsEmbeddedPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	vaultDir := filepath.Join(tempDir, "vault")
+	outputDir := filepath.Join(tempDir, "output")
+	home := filepath.Join(tempDir, "exporter_home")
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("CODEX_HOME", "")
+
+	profileDir := filepath.Join(vaultDir, "claude", "work")
+	if err := os.MkdirAll(profileDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	helper := filepath.Join(home, "bin", "key-helper.sh")
+	settings, _ := json.Marshal(map[string]string{"apiKeyHelper": helper})
+	if err := os.WriteFile(filepath.Join(profileDir, "settings.json"), settings, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := &VaultExporter{VaultPath: vaultDir, DataPath: tempDir}
+	exportOpts := DefaultExportOptions()
+	exportOpts.OutputDir = outputDir
+	exportResult, err := exporter.Export(exportOpts)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	importFor := func(skip bool) (string, *ImportResult) {
+		importVaultDir := t.TempDir()
+		importOpts := DefaultImportOptions()
+		importOpts.VaultPath = importVaultDir
+		importOpts.SkipPathRemap = skip
+		importOpts.LocalLayout = &PathLayout{Home: "/Users/bob"}
+		result, err := (&VaultImporter{BundlePath: exportResult.OutputPath}).Import(importOpts)
+		if err != nil {
+			t.Fatalf("import failed: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(importVaultDir, "claude", "work", "settings.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var imported map[string]string
+		if err := json.Unmarshal(data, &imported); err != nil {
+			t.Fatal(err)
+		}
+		return imported["apiKeyHelper"], result
+	}
+
+	got, result := importFor(false)
+	if want := "/Users/bob" + strings.TrimPrefix(helper, home); got != want {
+		t.Errorf("apiKeyHelper = %q, want %q", got, want)
+	}
+	if len(result.RemappedFiles) != 1 || result.RemappedFiles[0] != "claude/work/settings.json" {
+		t.Errorf("RemappedFiles = %v, want [claude/work/settings.json]", result.RemappedFiles)
+	}
+
+	if got, _ := importFor(true); got != helper {
+		t.Errorf("with SkipPathRemap apiKeyHelper = %q, want %q", got, helper)
+	}
+}
+
 func TestVaultImporter_Import_WithEncryption(t *testing.T) {
 	tempDir := t.TempDir()
 	vaultDir := filepath.Join(tempDir, "vault")
@@ -68,6 +68,10 @@ type SourceInfo struct {
 
 	// CAAMDataPath is the local path to caam data.
 	CAAMDataPath string `json:"caam_data_path"`
+
+	// Layout is where the machine keeps the tools' home directories, used to
+	// remap absolute paths embedded in auth files on import.
+	Layout *PathLayout `json:"layout,omitempty"`
 }
 
 // ContentsInfo describes what is included in the bundle.
@@ -0,0 +1,197 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PathLayout records where a machine keeps the directories that auth and
+// settings files embed absolute paths into.
+type PathLayout struct {
+	Home      string `json:"home,omitempty"`
+	XDGConfig string `json:"xdg_config_home,omitempty"`
+	CodexHome string `json:"codex_home,omitempty"`
+}
+
+// CurrentPathLayout returns this machine's layout, honoring XDG_CONFIG_HOME
+// and CODEX_HOME the way the tools do.
+func CurrentPathLayout() PathLayout {
+	home, _ := os.UserHomeDir()
+	layout := PathLayout{
+		Home:      home,
+		XDGConfig: os.Getenv("XDG_CONFIG_HOME"),
+		CodexHome: os.Getenv("CODEX_HOME"),
+	}
+	if layout.XDGConfig == "" && home != "" {
+		layout.XDGConfig = filepath.Join(home, ".config")
+	}
+	if layout.CodexHome == "" && home != "" {
+		layout.CodexHome = filepath.Join(home, ".codex")
+	}
+	return layout
+}
+
+// sourceLayout returns the exporting machine's layout. Bundles from caam
+// versions that didn't record it fall back to the home directory implied by
+// the default data path (~/.local/share/caam).
+func sourceLayout(manifest *ManifestV1) PathLayout {
+	if manifest.Source.Layout != nil {
+		return *manifest.Source.Layout
+	}
+	data := filepath.ToSlash(manifest.Source.CAAMDataPath)
+	if home, ok := strings.CutSuffix(data, "/.local/share/caam"); ok && home != "" {
+		return PathLayout{Home: home}
+	}
+	return PathLayout{}
+}
+
+// remapFormat says how paths are quoted inside a file.
+type remapFormat int
+
+const (
+	remapText remapFormat = iota // plain text (TOML, .env)
+	remapJSON                    // JSON, where backslashes are escaped
+)
+
+// remapFiles lists, per provider, the vault files known to embed absolute
+// paths: Claude's apiKeyHelper and project keys, Codex's trusted project
+// tables and instruction files, Gemini's service-account credentials path.
+var remapFiles = map[string]map[string]remapFormat{
+	"claude": {
+		"settings.json": remapJSON,
+		".claude.json":  remapJSON,
+	},
+	"codex": {
+		"config.toml": remapText,
+	},
+	"gemini": {
+		"settings.json": remapJSON,
+		".env":          remapText,
+	},
+}
+
+// pathRule rewrites one directory prefix.
+type pathRule struct {
+	from, to string
+}
+
+// pathRemapper rewrites paths under the source machine's directories to the
+// local ones.
+type pathRemapper struct {
+	rules []pathRule
+}
+
+// newPathRemapper maps each directory of from to its counterpart in to. It
+// returns nil when there is nothing to remap.
+func newPathRemapper(from, to PathLayout) *pathRemapper {
+	pairs := [][2]string{
+		{from.CodexHome, to.CodexHome},
+		{from.XDGConfig, to.XDGConfig},
+		{from.Home, to.Home},
+	}
+	r := &pathRemapper{}
+	for _, p := range pairs {
+		if p[0] == "" || p[1] == "" || p[0] == p[1] {
+			continue
+		}
+		r.rules = append(r.rules, pathRule{from: strings.TrimRight(p[0], `/\`), to: strings.TrimRight(p[1], `/\`)})
+	}
+	if len(r.rules) == 0 {
+		return nil
+	}
+	// The most specific directory wins, e.g. ~/.codex before ~.
+	sort.SliceStable(r.rules, func(i, j int) bool {
+		return len(r.rules[i].from) > len(r.rules[j].from)
+	})
+	return r
+}
+
+// remap rewrites, in a single pass, every occurrence of a source directory
+// in data that stands as a whole path: preceded by a quote, whitespace or
+// delimiter and followed by a separator, quote, whitespace or the end.
+func (r *pathRemapper) remap(data []byte, format remapFormat) ([]byte, int) {
+	rules := r.rules
+	if format == remapJSON {
+		rules = make([]pathRule, len(r.rules))
+		for i, rule := range r.rules {
+			rules[i] = pathRule{from: strings.ReplaceAll(rule.from, `\`, `\\`), to: strings.ReplaceAll(rule.to, `\`, `\\`)}
+		}
+	}
+
+	s := string(data)
+	var b strings.Builder
+	count := 0
+	for i := 0; i < len(s); {
+		matched := false
+		if i == 0 || strings.IndexByte(pathLeadDelims, s[i-1]) >= 0 {
+			for _, rule := range rules {
+				end := i + len(rule.from)
+				if strings.HasPrefix(s[i:], rule.from) && (end == len(s) || strings.IndexByte(pathTrailDelims, s[end]) >= 0) {
+					b.WriteString(rule.to)
+					i = end
+					count++
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return []byte(b.String()), count
+}
+
+// Characters that may surround an embedded path.
+const (
+	pathLeadDelims  = "\"' \t\r\n=:;,([{"
+	pathTrailDelims = "/\\\"' \t\r\n:;,)]}"
+)
+
+// remapProfilePaths rewrites the known path-embedding files in profileDir.
+// It returns the names of the files it changed.
+func (r *pathRemapper) remapProfilePaths(provider, profileDir string) ([]string, error) {
+	files := remapFiles[strings.ToLower(provider)]
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var changed []string
+	for _, name := range names {
+		format := files[name]
+		path := filepath.Join(profileDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return changed, fmt.Errorf("read %s: %w", name, err)
+		}
+
+		remapped, n := r.remap(data, format)
+		if n == 0 || bytes.Equal(remapped, data) {
+			continue
+		}
+		// Never turn a valid JSON file into an invalid one.
+		if format == remapJSON && json.Valid(data) && !json.Valid(remapped) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return changed, fmt.Errorf("stat %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, remapped, info.Mode().Perm()); err != nil {
+			return changed, fmt.Errorf("write %s: %w", name, err)
+		}
+		changed = append(changed, name)
+	}
+	return changed, nil
+}
@@ -0,0 +1,106 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathRemapper_Remap(t *testing.T) {
+	r := newPathRemapper(
+		PathLayout{Home: "/home/alice", CodexHome: "/home/alice/.codex"},
+		PathLayout{Home: "/Users/bob", CodexHome: "/opt/codex"},
+	)
+	if r == nil {
+		t.Fatal("expected a remapper")
+	}
+
+	tests := []struct {
+		name   string
+		in     string
+		format remapFormat
+		want   string
+	}{
+		{"json helper", `{"apiKeyHelper": "/home/alice/bin/key.sh"}`, remapJSON, `{"apiKeyHelper": "/Users/bob/bin/key.sh"}`},
+		{"command args", `{"apiKeyHelper": "sh /home/alice/key.sh --x"}`, remapJSON, `{"apiKeyHelper": "sh /Users/bob/key.sh --x"}`},
+		{"most specific wins", `model_instructions_file = "/home/alice/.codex/AGENTS.md"`, remapText, `model_instructions_file = "/opt/codex/AGENTS.md"`},
+		{"toml table key", `[projects."/home/alice/src"]`, remapText, `[projects."/Users/bob/src"]`},
+		{"env value", "GOOGLE_APPLICATION_CREDENTIALS=/home/alice/sa.json\n", remapText, "GOOGLE_APPLICATION_CREDENTIALS=/Users/bob/sa.json\n"},
+		{"bare dir", `{"cwd": "/home/alice"}`, remapJSON, `{"cwd": "/Users/bob"}`},
+		{"longer name untouched", `{"p": "/home/alice2/x"}`, remapJSON, `{"p": "/home/alice2/x"}`},
+		{"nested untouched", `{"p": "/srv/home/alice/x"}`, remapJSON, `{"p": "/srv/home/alice/x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := r.remap([]byte(tt.in), tt.format)
+			if string(got) != tt.want {
+				t.Errorf("remap(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathRemapper_WindowsSourceInJSON(t *testing.T) {
+	r := newPathRemapper(PathLayout{Home: `C:\Users\alice`}, PathLayout{Home: `C:\Users\bob`})
+	got, n := r.remap([]byte(`{"apiKeyHelper": "C:\\Users\\alice\\key.cmd"}`), remapJSON)
+	if n != 1 || string(got) != `{"apiKeyHelper": "C:\\Users\\bob\\key.cmd"}` {
+		t.Errorf("remap = %q (%d), want the escaped home rewritten", got, n)
+	}
+}
+
+func TestNewPathRemapper_NothingToRemap(t *testing.T) {
+	same := PathLayout{Home: "/home/alice"}
+	if r := newPathRemapper(same, same); r != nil {
+		t.Error("identical layouts should not need a remapper")
+	}
+	if r := newPathRemapper(PathLayout{}, same); r != nil {
+		t.Error("an unknown source layout should not need a remapper")
+	}
+}
+
+func TestSourceLayout_FallsBackToDataPath(t *testing.T) {
+	m := &ManifestV1{Source: SourceInfo{CAAMDataPath: "/home/alice/.local/share/caam"}}
+	if got := sourceLayout(m); got.Home != "/home/alice" {
+		t.Errorf("sourceLayout home = %q, want /home/alice", got.Home)
+	}
+
+	m.Source.CAAMDataPath = "/custom/caam"
+	if got := sourceLayout(m); got != (PathLayout{}) {
+		t.Errorf("sourceLayout = %+v, want empty for a custom data path", got)
+	}
+}
+
+func TestPathRemapper_RemapProfilePaths(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"settings.json":     `{"apiKeyHelper": "/home/alice/key.sh"}`,
+		".credentials.json": `{"note": "/home/alice/untouched"}`,
+		".claude.json":      `{"projects": {"/home/alice/src": {}}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := newPathRemapper(PathLayout{Home: "/home/alice"}, PathLayout{Home: "/Users/bob"})
+	changed, err := r.remapProfilePaths("claude", dir)
+	if err != nil {
+		t.Fatalf("remapProfilePaths() error = %v", err)
+	}
+	if len(changed) != 2 || changed[0] != ".claude.json" || changed[1] != "settings.json" {
+		t.Errorf("changed = %v, want [.claude.json settings.json]", changed)
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".credentials.json"))
+	if string(data) != files[".credentials.json"] {
+		t.Errorf("credentials should not be remapped, got %s", data)
+	}
+	info, err := os.Stat(filepath.Join(dir, "settings.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("settings.json mode = %v, want 0600", info.Mode().Perm())
+	}
+}
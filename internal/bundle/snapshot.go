@@ -0,0 +1,353 @@
+package bundle
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// transactionFile is the record kept in each import snapshot directory.
+	transactionFile = "transaction.json"
+	// journalFile lists, one JSON line per profile, what has been imported.
+	journalFile = "journal.jsonl"
+)
+
+// ImportTransaction records the vault profiles a bundle import was about to
+// change, so the whole import can be rolled back, or resumed if it stopped
+// partway.
+type ImportTransaction struct {
+	ID         string            `json:"id"`
+	CreatedAt  time.Time         `json:"created_at"`
+	BundlePath string            `json:"bundle_path"`
+	VaultPath  string            `json:"vault_path"`
+	Profiles   []SnapshotProfile `json:"profiles"`
+
+	// SkipPathRemap repeats the import's option so a resume writes the
+	// same files.
+	SkipPathRemap bool `json:"skip_path_remap,omitempty"`
+
+	// CompletedAt is set once every profile has been imported.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// RolledBackAt is set once the import has been rolled back.
+	RolledBackAt *time.Time `json:"rolled_back_at,omitempty"`
+
+	dir string
+}
+
+// SnapshotProfile is one profile an import adds or overwrites. Existed
+// profiles have their pre-import files under the snapshot's vault/
+// directory; the others are removed on rollback.
+type SnapshotProfile struct {
+	Provider string `json:"provider"`
+	Profile  string `json:"profile"`
+	Existed  bool   `json:"existed"`
+}
+
+// SnapshotDir returns where import snapshots for the vault at vaultPath are
+// kept: next to the vault, so they share its permissions and filesystem.
+func SnapshotDir(vaultPath string) string {
+	return filepath.Join(filepath.Dir(filepath.Clean(vaultPath)), "import_snapshots")
+}
+
+// newTransactionID returns a sortable, unique import transaction ID.
+func newTransactionID(now time.Time) (string, error) {
+	var suffix [3]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return "", fmt.Errorf("generate transaction id: %w", err)
+	}
+	return now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix[:]), nil
+}
+
+// snapshotProfiles copies the local profiles that actions will overwrite into
+// a new snapshot under dir and records the transaction.
+func snapshotProfiles(dir, bundlePath string, opts *ImportOptions, actions []ProfileAction) (*ImportTransaction, error) {
+	id, err := newTransactionID(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	vaultPath := opts.VaultPath
+	if abs, err := filepath.Abs(bundlePath); err == nil {
+		bundlePath = abs
+	}
+	txDir := filepath.Join(dir, id)
+	tx := &ImportTransaction{
+		ID:            id,
+		CreatedAt:     time.Now().UTC(),
+		BundlePath:    bundlePath,
+		VaultPath:     vaultPath,
+		Profiles:      make([]SnapshotProfile, 0, len(actions)),
+		SkipPathRemap: opts.SkipPathRemap,
+		dir:           txDir,
+	}
+
+	if err := os.MkdirAll(txDir, 0700); err != nil {
+		return nil, fmt.Errorf("create snapshot dir: %w", err)
+	}
+
+	for _, action := range actions {
+		if action.Action == "skip" {
+			continue
+		}
+		localPath := filepath.Join(vaultPath, action.Provider, action.Profile)
+		entry := SnapshotProfile{
+			Provider: action.Provider,
+			Profile:  action.Profile,
+			Existed:  directoryExists(localPath),
+		}
+		if entry.Existed {
+			dst := filepath.Join(txDir, "vault", action.Provider, action.Profile)
+			if err := copyDirectory(localPath, dst); err != nil {
+				return nil, fmt.Errorf("snapshot %s/%s: %w", action.Provider, action.Profile, err)
+			}
+		}
+		tx.Profiles = append(tx.Profiles, entry)
+	}
+
+	if err := saveTransaction(txDir, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// journalEntry records one profile the import has finished, with the
+// checksums of the files as written to the vault.
+type journalEntry struct {
+	Provider   string            `json:"provider"`
+	Profile    string            `json:"profile"`
+	Checksums  map[string]string `json:"checksums"`
+	ImportedAt time.Time         `json:"imported_at"`
+}
+
+// recordImported journals that the profile at localPath is fully imported.
+func (tx *ImportTransaction) recordImported(provider, profile, localPath string) error {
+	sums, err := ComputeDirectoryChecksums(localPath, DefaultAlgorithm)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(journalEntry{
+		Provider:   provider,
+		Profile:    profile,
+		Checksums:  sums,
+		ImportedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filepath.Join(tx.dir, journalFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// loadJournal returns the journaled profiles keyed by provider/profile. A
+// torn last line from an interrupted write is ignored.
+func loadJournal(txDir string) (map[string]journalEntry, error) {
+	data, err := os.ReadFile(filepath.Join(txDir, journalFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]journalEntry{}, nil
+		}
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	entries := make(map[string]journalEntry)
+	for _, line := range strings.Split(string(data), "\n") {
+		var e journalEntry
+		if strings.TrimSpace(line) == "" || json.Unmarshal([]byte(line), &e) != nil {
+			continue
+		}
+		entries[e.Provider+"/"+e.Profile] = e
+	}
+	return entries, nil
+}
+
+// verify reports whether the profile at localPath still holds exactly the
+// files journaled for it.
+func (e journalEntry) verify(localPath string) bool {
+	sums, err := ComputeDirectoryChecksums(localPath, DefaultAlgorithm)
+	if err != nil || len(sums) != len(e.Checksums) {
+		return false
+	}
+	for name, sum := range e.Checksums {
+		if sums[name] != sum {
+			return false
+		}
+	}
+	return true
+}
+
+func (tx *ImportTransaction) markCompleted() error {
+	now := time.Now().UTC()
+	tx.CompletedAt = &now
+	return saveTransaction(tx.dir, tx)
+}
+
+// LatestIncompleteImport returns the most recent import in dir that neither
+// finished nor was rolled back.
+func LatestIncompleteImport(dir string) (*ImportTransaction, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read snapshot dir: %w", err)
+	}
+	// IDs start with their UTC timestamp, so name order is age order.
+	for idx := len(entries) - 1; idx >= 0; idx-- {
+		if !entries[idx].IsDir() {
+			continue
+		}
+		tx, err := LoadImportTransaction(dir, entries[idx].Name())
+		if err != nil {
+			continue
+		}
+		if tx.CompletedAt == nil && tx.RolledBackAt == nil {
+			return tx, nil
+		}
+	}
+	return nil, fmt.Errorf("no interrupted import to resume in %s", dir)
+}
+
+// Resume continues the interrupted import recorded by transaction id.
+// Profiles the journal lists as imported are skipped when their files still
+// match the journaled checksums and imported again otherwise. The bundle is
+// re-read from the path recorded in the transaction unless BundlePath is set.
+func (i *VaultImporter) Resume(opts *ImportOptions, id string) (*ImportResult, error) {
+	if opts == nil {
+		opts = DefaultImportOptions()
+	}
+	tx, err := LoadImportTransaction(opts.snapshotDir(), id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tx.RolledBackAt != nil:
+		return nil, fmt.Errorf("import %s was rolled back", id)
+	case tx.CompletedAt != nil:
+		return nil, fmt.Errorf("import %s already completed", id)
+	}
+	if i.BundlePath == "" {
+		i.BundlePath = tx.BundlePath
+	}
+	opts.VaultPath = tx.VaultPath
+	opts.SkipPathRemap = tx.SkipPathRemap
+
+	result := newImportResult()
+	result.TransactionID = tx.ID
+	tempDir, manifest, cleanup, err := i.openBundle(opts, result)
+	if cleanup != nil {
+		defer cleanup()
+	}
+	if err != nil {
+		return result.orNil(), err
+	}
+
+	journal, err := loadJournal(tx.dir)
+	if err != nil {
+		return result, err
+	}
+	actions := make([]ProfileAction, 0, len(tx.Profiles))
+	for _, p := range tx.Profiles {
+		action := ProfileAction{Provider: p.Provider, Profile: p.Profile, Action: "add", Reason: "resumed"}
+		if p.Existed {
+			action.Action = "update"
+		}
+		if entry, ok := journal[p.Provider+"/"+p.Profile]; ok {
+			if entry.verify(filepath.Join(tx.VaultPath, p.Provider, p.Profile)) {
+				action.Action = "skip"
+				action.Reason = "already imported (checksums verified)"
+			} else {
+				action.Reason = "changed since it was imported, importing again"
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	if err := i.importVault(tempDir, manifest, opts, actions, tx, result); err != nil {
+		return result, fmt.Errorf("import vault: %w", err)
+	}
+	return result, nil
+}
+
+func saveTransaction(txDir string, tx *ImportTransaction) error {
+	data, err := json.MarshalIndent(tx, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal transaction: %w", err)
+	}
+	path := filepath.Join(txDir, transactionFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write transaction: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write transaction: %w", err)
+	}
+	return nil
+}
+
+// LoadImportTransaction reads the transaction with the given ID from dir.
+func LoadImportTransaction(dir, id string) (*ImportTransaction, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return nil, fmt.Errorf("invalid import transaction id %q", id)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, id, transactionFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no import transaction %q in %s", id, dir)
+		}
+		return nil, fmt.Errorf("read transaction: %w", err)
+	}
+	var tx ImportTransaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("parse transaction %s: %w", id, err)
+	}
+	tx.dir = filepath.Join(dir, id)
+	return &tx, nil
+}
+
+// RollbackImport restores the vault profiles snapshotted by transaction id:
+// overwritten profiles get their pre-import files back and profiles the
+// import added are removed. A transaction can be rolled back once.
+func RollbackImport(dir, id string) (*ImportTransaction, error) {
+	tx, err := LoadImportTransaction(dir, id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.RolledBackAt != nil {
+		return nil, fmt.Errorf("import %s was already rolled back at %s", id, tx.RolledBackAt.Local().Format("2006-01-02 15:04"))
+	}
+
+	var errs []string
+	for _, p := range tx.Profiles {
+		localPath := filepath.Join(tx.VaultPath, p.Provider, p.Profile)
+		if p.Existed {
+			src := filepath.Join(tx.dir, "vault", p.Provider, p.Profile)
+			if err := copyProfileDirectory(src, localPath); err != nil {
+				errs = append(errs, fmt.Sprintf("%s/%s: %v", p.Provider, p.Profile, err))
+			}
+			continue
+		}
+		if err := os.RemoveAll(localPath); err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", p.Provider, p.Profile, err))
+		}
+	}
+	if len(errs) > 0 {
+		return tx, fmt.Errorf("rollback incomplete: %s", strings.Join(errs, "; "))
+	}
+
+	now := time.Now().UTC()
+	tx.RolledBackAt = &now
+	if err := saveTransaction(tx.dir, tx); err != nil {
+		return tx, err
+	}
+	return tx, nil
+}
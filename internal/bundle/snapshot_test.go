@@ -0,0 +1,194 @@
+package bundle
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVaultImporter_Import_SnapshotAndRollback(t *testing.T) {
+	tempDir := t.TempDir()
+	vaultDir := filepath.Join(tempDir, "vault")
+	outputDir := filepath.Join(tempDir, "output")
+	importVaultDir := filepath.Join(tempDir, "import_vault")
+
+	for _, name := range []string{"work@example.com", "new@example.com"} {
+		dir := filepath.Join(vaultDir, "codex", name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"token":"bundle"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exporter := &VaultExporter{VaultPath: vaultDir, DataPath: tempDir}
+	exportOpts := DefaultExportOptions()
+	exportOpts.OutputDir = outputDir
+	exportResult, err := exporter.Export(exportOpts)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	existing := filepath.Join(importVaultDir, "codex", "work@example.com")
+	if err := os.MkdirAll(existing, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(existing, "auth.json"), []byte(`{"token":"local"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	importer := &VaultImporter{BundlePath: exportResult.OutputPath}
+
+	// A dry run leaves no snapshot behind.
+	dryOpts := DefaultImportOptions()
+	dryOpts.Mode = ImportModeReplace
+	dryOpts.VaultPath = importVaultDir
+	dryOpts.DryRun = true
+	dry, err := importer.Import(dryOpts)
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if dry.TransactionID != "" {
+		t.Errorf("dry run TransactionID = %q, want empty", dry.TransactionID)
+	}
+
+	opts := DefaultImportOptions()
+	opts.Mode = ImportModeReplace
+	opts.VaultPath = importVaultDir
+	result, err := importer.Import(opts)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if result.TransactionID == "" {
+		t.Fatal("import should record a transaction ID")
+	}
+	if data, _ := os.ReadFile(filepath.Join(existing, "auth.json")); string(data) != `{"token":"bundle"}` {
+		t.Fatalf("import did not overwrite profile: %s", data)
+	}
+
+	snapshotDir := SnapshotDir(importVaultDir)
+	tx, err := RollbackImport(snapshotDir, result.TransactionID)
+	if err != nil {
+		t.Fatalf("RollbackImport: %v", err)
+	}
+	if len(tx.Profiles) != 2 {
+		t.Errorf("transaction profiles = %+v, want 2", tx.Profiles)
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(existing, "auth.json")); string(data) != `{"token":"local"}` {
+		t.Errorf("overwritten profile not restored: %s", data)
+	}
+	if _, err := os.Stat(filepath.Join(importVaultDir, "codex", "new@example.com")); !os.IsNotExist(err) {
+		t.Errorf("profile added by import should be removed, stat err = %v", err)
+	}
+
+	if _, err := RollbackImport(snapshotDir, result.TransactionID); err == nil || !strings.Contains(err.Error(), "already rolled back") {
+		t.Errorf("second rollback err = %v, want already rolled back", err)
+	}
+}
+
+func TestLoadImportTransaction_RejectsPathIDs(t *testing.T) {
+	dir := t.TempDir()
+	for _, id := range []string{"", "..", "../escape", `a\b`} {
+		if _, err := LoadImportTransaction(dir, id); err == nil {
+			t.Errorf("LoadImportTransaction(%q) should fail", id)
+		}
+	}
+}
+
+func TestVaultImporter_Resume_SkipsVerifiedProfiles(t *testing.T) {
+	tempDir := t.TempDir()
+	vaultDir := filepath.Join(tempDir, "vault")
+	outputDir := filepath.Join(tempDir, "output")
+	importVaultDir := filepath.Join(tempDir, "import_vault")
+
+	names := []string{"done@example.com", "changed@example.com", "pending@example.com"}
+	for _, name := range names {
+		dir := filepath.Join(vaultDir, "codex", name)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"token":"bundle"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exporter := &VaultExporter{VaultPath: vaultDir, DataPath: tempDir}
+	exportOpts := DefaultExportOptions()
+	exportOpts.OutputDir = outputDir
+	exportResult, err := exporter.Export(exportOpts)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+
+	opts := DefaultImportOptions()
+	opts.VaultPath = importVaultDir
+	result, err := (&VaultImporter{BundlePath: exportResult.OutputPath}).Import(opts)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	// Rewind to a crash after the first two profiles: the journal lists only
+	// those, the third never landed, and one of the two was edited since.
+	snapshotDir := SnapshotDir(importVaultDir)
+	tx, err := LoadImportTransaction(snapshotDir, result.TransactionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.CompletedAt = nil
+	if err := saveTransaction(tx.dir, tx); err != nil {
+		t.Fatal(err)
+	}
+	journal, err := loadJournal(tx.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []byte
+	for _, key := range []string{"codex/done@example.com", "codex/changed@example.com"} {
+		line, _ := json.Marshal(journal[key])
+		kept = append(append(kept, line...), '\n')
+	}
+	if err := os.WriteFile(filepath.Join(tx.dir, journalFile), kept, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(importVaultDir, "codex", "pending@example.com")); err != nil {
+		t.Fatal(err)
+	}
+	changed := filepath.Join(importVaultDir, "codex", "changed@example.com", "auth.json")
+	if err := os.WriteFile(changed, []byte(`{"token":"torn"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := LatestIncompleteImport(snapshotDir)
+	if err != nil || latest.ID != tx.ID {
+		t.Fatalf("LatestIncompleteImport = %v, %v; want %s", latest, err, tx.ID)
+	}
+
+	resumeOpts := DefaultImportOptions()
+	resumeOpts.VaultPath = importVaultDir
+	resumed, err := (&VaultImporter{}).Resume(resumeOpts, tx.ID)
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+
+	got := map[string]string{}
+	for _, a := range resumed.ProfileActions {
+		got[a.Profile] = a.Action
+	}
+	if got["done@example.com"] != "skip" || got["changed@example.com"] != "add" || got["pending@example.com"] != "add" {
+		t.Errorf("resume actions = %v, want done skipped and the others imported", got)
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(importVaultDir, "codex", name, "auth.json"))
+		if err != nil || string(data) != `{"token":"bundle"}` {
+			t.Errorf("%s after resume = %q (err %v)", name, data, err)
+		}
+	}
+
+	if _, err := (&VaultImporter{}).Resume(resumeOpts, tx.ID); err == nil || !strings.Contains(err.Error(), "already completed") {
+		t.Errorf("second resume err = %v, want already completed", err)
+	}
+}
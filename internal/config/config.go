@@ -9,7 +9,11 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
 )
 
 // Config holds the global caam configuration.
@@ -52,18 +56,91 @@ type Config struct {
 	// Example: {"claude": ["work", "personal"]}
 	Favorites map[string][]string `json:"favorites,omitempty"`
 
+	// ProfileClasses maps profile keys (provider/profile) to their policy
+	// class, which project directories can require (see ProfileClassWork).
+	// Example: {"claude/me@gmail.com": "personal"}
+	ProfileClasses map[string]string `json:"profile_classes,omitempty"`
+
 	// Workspaces maps workspace names to provider-profile mappings.
 	// Example: {"work": {"claude": "work-claude", "codex": "work-codex"}}
 	Workspaces map[string]map[string]string `json:"workspaces,omitempty"`
 
+	// WorkspaceTemplates holds the optional directory, environment and
+	// launch settings of workspaces, keyed like Workspaces.
+	WorkspaceTemplates map[string]WorkspaceTemplate `json:"workspace_templates,omitempty"`
+
 	// CurrentWorkspace is the name of the currently active workspace.
 	CurrentWorkspace string `json:"current_workspace,omitempty"`
 
+	// Contexts maps context names to bundled settings, like kubectl contexts.
+	// Example: {"work": {"vault_path": "~/work-vault", "default_provider": "claude"}}
+	Contexts map[string]ContextConfig `json:"contexts,omitempty"`
+
+	// CurrentContext is the name of the active context ("" for none).
+	CurrentContext string `json:"current_context,omitempty"`
+
 	// Wrap configures retry and backoff behavior for the wrap command.
 	Wrap WrapConfig `json:"wrap,omitempty"`
 
 	// Backup configures automatic backup scheduling.
 	Backup BackupConfig `json:"backup,omitempty"`
+
+	// Scoring holds the weights 'caam robot next' scores profiles with.
+	Scoring ScoringConfig `json:"scoring"`
+
+	// Pins maps providers to a profile that always wins automatic selection
+	// unless it is in cooldown.
+	// Example: {"claude": "org-account"}
+	Pins map[string]string `json:"pins,omitempty"`
+
+	// Quarantined maps profile keys (provider/profile) to profiles that are
+	// never selected automatically, with why.
+	Quarantined map[string]Quarantine `json:"quarantined,omitempty"`
+
+	// APITokens are the scoped bearer tokens the coordinator and agent
+	// APIs accept (see 'caam api-token'). Empty leaves the APIs open.
+	APITokens []apitoken.Token `json:"api_tokens,omitempty"`
+}
+
+// Quarantine records why and since when a profile is kept out of automatic
+// selection.
+type Quarantine struct {
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// WorkspaceTemplate is what 'caam workspace enter' sets up besides
+// activating the workspace's profiles.
+type WorkspaceTemplate struct {
+	// Dir is the project directory to enter. Its project associations are
+	// pointed at the workspace's profiles.
+	Dir string `json:"dir,omitempty"`
+
+	// Env holds variables exported on enter.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Args maps tools to default arguments for their tmux pane.
+	// Example: {"codex": ["--model", "o3"]}
+	Args map[string][]string `json:"args,omitempty"`
+
+	// Tmux starts a tmux session with a pane per tool on enter.
+	Tmux bool `json:"tmux,omitempty"`
+}
+
+// ContextConfig is a named bundle of settings that can be switched as a unit.
+// Empty fields fall back to the regular configuration.
+type ContextConfig struct {
+	// VaultPath is the vault directory to use instead of the default.
+	VaultPath string `json:"vault_path,omitempty"`
+
+	// DefaultProvider overrides Config.DefaultProvider.
+	DefaultProvider string `json:"default_provider,omitempty"`
+
+	// Strategy overrides the rotation algorithm (smart, round_robin, random).
+	Strategy string `json:"strategy,omitempty"`
+
+	// CoordinatorURL is the default coordinator for 'caam auth-agent'.
+	CoordinatorURL string `json:"coordinator_url,omitempty"`
 }
 
 // DefaultConfig returns the default configuration.
@@ -74,6 +151,7 @@ func DefaultConfig() *Config {
 		AutoLock:        true,
 		Wrap:            DefaultWrapConfig(),
 		Backup:          DefaultBackupConfig(),
+		Scoring:         DefaultScoringConfig(),
 	}
 }
 
@@ -91,6 +169,12 @@ func ConfigPath() string {
 	return filepath.Join(homeDir, ".config", "caam", "config.json")
 }
 
+// ProvidersDir returns the directory of provider manifests, next to the
+// config file.
+func ProvidersDir() string {
+	return filepath.Join(filepath.Dir(ConfigPath()), "providers.d")
+}
+
 // DefaultDataPath returns the base caam data directory path.
 // If CAAM_HOME is set, data is stored under CAAM_HOME/data.
 // Otherwise, it follows XDG Base Directory Specification.
@@ -308,6 +392,9 @@ func Load() (*Config, error) {
 	if err := json.Unmarshal(data, config); err != nil {
 		return nil, fmt.Errorf("parse config: %w", err)
 	}
+	if err := config.Scoring.Validate(); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
 
 	return config, nil
 }
@@ -374,6 +461,89 @@ func (c *Config) GetDefault(provider string) string {
 	return c.DefaultProfiles[provider]
 }
 
+// SetPin pins a profile for a provider. An empty profile removes the pin.
+func (c *Config) SetPin(provider, profile string) {
+	if profile == "" {
+		delete(c.Pins, provider)
+		return
+	}
+	if c.Pins == nil {
+		c.Pins = make(map[string]string)
+	}
+	c.Pins[provider] = profile
+}
+
+// GetPin returns the pinned profile for a provider, or "" if none.
+func (c *Config) GetPin(provider string) string {
+	if c.Pins == nil {
+		return ""
+	}
+	return c.Pins[provider]
+}
+
+// SetQuarantine quarantines a profile, keeping it out of automatic
+// selection.
+func (c *Config) SetQuarantine(provider, profile string, q Quarantine) {
+	if c.Quarantined == nil {
+		c.Quarantined = make(map[string]Quarantine)
+	}
+	c.Quarantined[ProfileKey(provider, profile)] = q
+}
+
+// RemoveQuarantine releases a quarantined profile, reporting whether it was
+// quarantined.
+func (c *Config) RemoveQuarantine(provider, profile string) bool {
+	key := ProfileKey(provider, profile)
+	if _, ok := c.Quarantined[key]; !ok {
+		return false
+	}
+	delete(c.Quarantined, key)
+	return true
+}
+
+// GetQuarantine returns a profile's quarantine, if it is quarantined.
+func (c *Config) GetQuarantine(provider, profile string) (Quarantine, bool) {
+	q, ok := c.Quarantined[ProfileKey(provider, profile)]
+	return q, ok
+}
+
+// QuarantinedProfiles returns the quarantined profiles of a provider,
+// sorted.
+func (c *Config) QuarantinedProfiles(provider string) []string {
+	prefix := provider + "/"
+	var profiles []string
+	for key := range c.Quarantined {
+		if strings.HasPrefix(key, prefix) {
+			profiles = append(profiles, key[len(prefix):])
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// AddAPIToken adds an API token; names must be unique.
+func (c *Config) AddAPIToken(tok apitoken.Token) error {
+	for _, t := range c.APITokens {
+		if t.Name == tok.Name {
+			return fmt.Errorf("API token %q already exists", tok.Name)
+		}
+	}
+	c.APITokens = append(c.APITokens, tok)
+	return nil
+}
+
+// RemoveAPIToken revokes the API token called name, reporting whether it
+// existed.
+func (c *Config) RemoveAPIToken(name string) bool {
+	for i, t := range c.APITokens {
+		if t.Name == name {
+			c.APITokens = append(c.APITokens[:i], c.APITokens[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // AddPassthrough adds a passthrough path.
 func (c *Config) AddPassthrough(path string) {
 	for _, p := range c.Passthroughs {
@@ -509,6 +679,48 @@ func (c *Config) IsFavorite(provider, profile string) bool {
 	return false
 }
 
+// Policy classes a profile can be marked with and a project directory can
+// require.
+const (
+	ProfileClassWork     = "work"
+	ProfileClassPersonal = "personal"
+)
+
+// ProfileClasses lists the valid policy classes.
+var ProfileClasses = []string{ProfileClassWork, ProfileClassPersonal}
+
+// ValidateProfileClass checks that class is a known policy class.
+func ValidateProfileClass(class string) error {
+	for _, c := range ProfileClasses {
+		if class == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid class %q (valid: %s)", class, strings.Join(ProfileClasses, ", "))
+}
+
+// SetProfileClass marks a profile with a policy class. An empty class
+// removes the mark.
+func (c *Config) SetProfileClass(provider, profile, class string) {
+	key := ProfileKey(provider, profile)
+	if class == "" {
+		delete(c.ProfileClasses, key)
+		return
+	}
+	if c.ProfileClasses == nil {
+		c.ProfileClasses = make(map[string]string)
+	}
+	c.ProfileClasses[key] = class
+}
+
+// GetProfileClass returns a profile's policy class, or "" if unmarked.
+func (c *Config) GetProfileClass(provider, profile string) string {
+	if c.ProfileClasses == nil {
+		return ""
+	}
+	return c.ProfileClasses[ProfileKey(provider, profile)]
+}
+
 // CreateWorkspace creates or updates a workspace with the given profile mappings.
 func (c *Config) CreateWorkspace(name string, profiles map[string]string) {
 	if c.Workspaces == nil {
@@ -526,6 +738,7 @@ func (c *Config) DeleteWorkspace(name string) bool {
 		return false
 	}
 	delete(c.Workspaces, name)
+	delete(c.WorkspaceTemplates, name)
 	// Clear current workspace if it was deleted
 	if c.CurrentWorkspace == name {
 		c.CurrentWorkspace = ""
@@ -561,6 +774,24 @@ func (c *Config) ListWorkspaces() []string {
 	return names
 }
 
+// SetWorkspaceTemplate stores a workspace's template, removing it when tpl
+// is empty.
+func (c *Config) SetWorkspaceTemplate(name string, tpl WorkspaceTemplate) {
+	if tpl.Dir == "" && len(tpl.Env) == 0 && len(tpl.Args) == 0 && !tpl.Tmux {
+		delete(c.WorkspaceTemplates, name)
+		return
+	}
+	if c.WorkspaceTemplates == nil {
+		c.WorkspaceTemplates = make(map[string]WorkspaceTemplate)
+	}
+	c.WorkspaceTemplates[name] = tpl
+}
+
+// GetWorkspaceTemplate returns a workspace's template (zero if it has none).
+func (c *Config) GetWorkspaceTemplate(name string) WorkspaceTemplate {
+	return c.WorkspaceTemplates[name]
+}
+
 // SetCurrentWorkspace sets the active workspace.
 func (c *Config) SetCurrentWorkspace(name string) {
 	c.CurrentWorkspace = name
@@ -571,6 +802,42 @@ func (c *Config) GetCurrentWorkspace() string {
 	return c.CurrentWorkspace
 }
 
+// SetContext creates or replaces a context.
+func (c *Config) SetContext(name string, ctx ContextConfig) {
+	if c.Contexts == nil {
+		c.Contexts = make(map[string]ContextConfig)
+	}
+	c.Contexts[name] = ctx
+}
+
+// DeleteContext removes a context, clearing it as current if needed.
+func (c *Config) DeleteContext(name string) bool {
+	if _, exists := c.Contexts[name]; !exists {
+		return false
+	}
+	delete(c.Contexts, name)
+	if c.CurrentContext == name {
+		c.CurrentContext = ""
+	}
+	return true
+}
+
+// GetContext returns the named context.
+func (c *Config) GetContext(name string) (ContextConfig, bool) {
+	ctx, ok := c.Contexts[name]
+	return ctx, ok
+}
+
+// ListContexts returns all context names, sorted.
+func (c *Config) ListContexts() []string {
+	names := make([]string, 0, len(c.Contexts))
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // FuzzyMatch finds profiles that match the given query using fuzzy matching.
 // It checks aliases first, then profile name prefixes, then substring matches.
 // Returns matching profile names sorted by match quality.
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -796,6 +797,34 @@ func TestFavorites(t *testing.T) {
 	}
 }
 
+func TestProfileClasses(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if class := cfg.GetProfileClass("claude", "me"); class != "" {
+		t.Errorf("GetProfileClass() = %q, want empty", class)
+	}
+
+	cfg.SetProfileClass("claude", "me", ProfileClassPersonal)
+	if class := cfg.GetProfileClass("claude", "me"); class != ProfileClassPersonal {
+		t.Errorf("GetProfileClass() = %q, want %q", class, ProfileClassPersonal)
+	}
+	if class := cfg.GetProfileClass("codex", "me"); class != "" {
+		t.Errorf("GetProfileClass(codex) = %q, want empty (different provider)", class)
+	}
+
+	cfg.SetProfileClass("claude", "me", "")
+	if _, ok := cfg.ProfileClasses["claude/me"]; ok {
+		t.Error("SetProfileClass with an empty class should remove the mark")
+	}
+
+	if err := ValidateProfileClass(ProfileClassWork); err != nil {
+		t.Errorf("ValidateProfileClass(work) error = %v", err)
+	}
+	if err := ValidateProfileClass("secret"); err == nil {
+		t.Error("ValidateProfileClass(secret) should fail")
+	}
+}
+
 func TestFuzzyMatch(t *testing.T) {
 	profiles := []string{
 		"work-account-1",
@@ -846,3 +875,75 @@ func TestFuzzyMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestContexts(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if names := cfg.ListContexts(); len(names) != 0 {
+		t.Errorf("ListContexts() on new config = %v, want empty", names)
+	}
+
+	cfg.SetContext("work", ContextConfig{VaultPath: "/work/vault", DefaultProvider: "claude"})
+	cfg.SetContext("personal", ContextConfig{Strategy: "round_robin"})
+	cfg.CurrentContext = "work"
+
+	if names := cfg.ListContexts(); strings.Join(names, ",") != "personal,work" {
+		t.Errorf("ListContexts() = %v, want [personal work]", names)
+	}
+	ctx, ok := cfg.GetContext("work")
+	if !ok || ctx.VaultPath != "/work/vault" || ctx.DefaultProvider != "claude" {
+		t.Errorf("GetContext(work) = %+v, %v", ctx, ok)
+	}
+	if _, ok := cfg.GetContext("nope"); ok {
+		t.Error("GetContext(nope) should not be found")
+	}
+
+	// Round-trip through JSON.
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var loaded Config
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if loaded.CurrentContext != "work" || loaded.Contexts["personal"].Strategy != "round_robin" {
+		t.Errorf("round-trip lost contexts: %+v", loaded)
+	}
+
+	// Deleting the current context clears it.
+	if !cfg.DeleteContext("work") {
+		t.Error("DeleteContext(work) should return true")
+	}
+	if cfg.CurrentContext != "" {
+		t.Errorf("CurrentContext after delete = %q, want empty", cfg.CurrentContext)
+	}
+	if cfg.DeleteContext("work") {
+		t.Error("DeleteContext(work) twice should return false")
+	}
+}
+
+func TestQuarantine(t *testing.T) {
+	cfg := DefaultConfig()
+
+	if _, ok := cfg.GetQuarantine("claude", "shared"); ok {
+		t.Error("GetQuarantine() on a fresh config should report not quarantined")
+	}
+	since := time.Now()
+	cfg.SetQuarantine("claude", "shared", Quarantine{Reason: "lent out", Since: since})
+	cfg.SetQuarantine("claude", "old", Quarantine{Since: since})
+	cfg.SetQuarantine("codex", "shared", Quarantine{Since: since})
+
+	if q, ok := cfg.GetQuarantine("claude", "shared"); !ok || q.Reason != "lent out" {
+		t.Errorf("GetQuarantine() = %+v, %v", q, ok)
+	}
+	if got := cfg.QuarantinedProfiles("claude"); len(got) != 2 || got[0] != "old" || got[1] != "shared" {
+		t.Errorf("QuarantinedProfiles(claude) = %v, want [old shared]", got)
+	}
+	if !cfg.RemoveQuarantine("claude", "shared") || cfg.RemoveQuarantine("claude", "shared") {
+		t.Error("RemoveQuarantine should report true once, then false")
+	}
+	if got := cfg.QuarantinedProfiles("codex"); len(got) != 1 {
+		t.Errorf("QuarantinedProfiles(codex) = %v, want [shared]", got)
+	}
+}
@@ -0,0 +1,95 @@
+// Package config scoring configuration for 'caam robot next'.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ScoringConfig holds the points 'caam robot next' gives profiles. Each
+// weight is added to a profile's score when its condition applies; use
+// negative values for penalties and 0 to ignore a condition.
+type ScoringConfig struct {
+	// Healthy, Warning, Critical and Unknown score the profile's health
+	// status. Defaults: 100, 50, 10, 30
+	Healthy  float64 `json:"healthy"`
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+	Unknown  float64 `json:"unknown"`
+
+	// Cooldown applies while the profile is in cooldown (only scored with
+	// --include-cooldown). Default: -200
+	Cooldown float64 `json:"cooldown"`
+
+	// Error applies per error recorded in the last hour. Default: -10
+	Error float64 `json:"error"`
+
+	// ExpiryOver7d, ExpiryOver1d, ExpirySoon and Expired score how long the
+	// token has left. Defaults: 20, 10, -20, -100
+	ExpiryOver7d float64 `json:"expiry_over_7d"`
+	ExpiryOver1d float64 `json:"expiry_over_1d"`
+	ExpirySoon   float64 `json:"expiry_soon"`
+	Expired      float64 `json:"expired"`
+
+	// HeadroomExhausted, HeadroomLow (<20%) and HeadroomHigh (>=70%) score
+	// the estimated limit headroom. Defaults: -150, -40, 15
+	HeadroomExhausted float64 `json:"headroom_exhausted"`
+	HeadroomLow       float64 `json:"headroom_low"`
+	HeadroomHigh      float64 `json:"headroom_high"`
+
+	// IdleSmart and IdleLRU are the bonus for a profile idle a day or more
+	// (scaled down for shorter idle times) with the smart and lru
+	// strategies. Defaults: 15, 50
+	IdleSmart float64 `json:"idle_smart"`
+	IdleLRU   float64 `json:"idle_lru"`
+
+	// Providers contains per-provider overrides. Only the weights given are
+	// overridden, so they are kept as raw JSON until merged.
+	// Example: {"claude": {"healthy": 150, "idle_smart": 0}}
+	Providers map[string]json.RawMessage `json:"providers,omitempty"`
+}
+
+// DefaultScoringConfig returns the weights 'caam robot next' uses by default.
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		Healthy:           100,
+		Warning:           50,
+		Critical:          10,
+		Unknown:           30,
+		Cooldown:          -200,
+		Error:             -10,
+		ExpiryOver7d:      20,
+		ExpiryOver1d:      10,
+		ExpirySoon:        -20,
+		Expired:           -100,
+		HeadroomExhausted: -150,
+		HeadroomLow:       -40,
+		HeadroomHigh:      15,
+		IdleSmart:         15,
+		IdleLRU:           50,
+	}
+}
+
+// ForProvider returns the effective weights for a specific provider: the
+// base weights with the provider's overrides applied.
+func (c *ScoringConfig) ForProvider(provider string) ScoringConfig {
+	result := *c
+	result.Providers = nil
+	if override, ok := c.Providers[provider]; ok {
+		// Overrides are checked by Validate on load.
+		_ = json.Unmarshal(override, &result)
+		result.Providers = nil
+	}
+	return result
+}
+
+// Validate checks that every provider override parses as weights.
+func (c *ScoringConfig) Validate() error {
+	for provider, override := range c.Providers {
+		var weights ScoringConfig
+		if err := json.Unmarshal(override, &weights); err != nil {
+			return fmt.Errorf("scoring override for %s: %w", provider, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScoringConfig_ForProvider(t *testing.T) {
+	cfg := DefaultScoringConfig()
+	cfg.Healthy = 120
+	cfg.Providers = map[string]json.RawMessage{
+		"claude": json.RawMessage(`{"idle_smart": 0, "cooldown": -500}`),
+	}
+
+	claude := cfg.ForProvider("claude")
+	if claude.IdleSmart != 0 {
+		t.Errorf("IdleSmart = %v, want 0 (overrides can zero a weight)", claude.IdleSmart)
+	}
+	if claude.Cooldown != -500 {
+		t.Errorf("Cooldown = %v, want -500", claude.Cooldown)
+	}
+	if claude.Healthy != 120 || claude.Expired != -100 {
+		t.Errorf("weights not overridden should come from the base, got healthy %v expired %v", claude.Healthy, claude.Expired)
+	}
+	if claude.Providers != nil {
+		t.Error("ForProvider should not copy nested providers")
+	}
+
+	if codex := cfg.ForProvider("codex"); codex.IdleSmart != 15 || codex.Healthy != 120 {
+		t.Errorf("codex weights = %+v, want the base", codex)
+	}
+}
+
+func TestLoad_ScoringConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+	path := ConfigPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"scoring": {"warning": 70}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Scoring.Warning != 70 || cfg.Scoring.Healthy != 100 {
+		t.Errorf("scoring = %+v, want warning 70 and default healthy", cfg.Scoring)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"scoring": {"providers": {"claude": {"healthy": "lots"}}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("Load() should reject an override with invalid weights")
+	}
+}
@@ -522,6 +522,24 @@ safety:
 `,
 			wantErr: "safety.max_auto_backups cannot be negative",
 		},
+		{
+			name: "postgres without dsn",
+			yaml: `
+version: 1
+database:
+  backend: postgres
+`,
+			wantErr: "database.dsn is required",
+		},
+		{
+			name: "unknown database backend",
+			yaml: `
+version: 1
+database:
+  backend: mysql
+`,
+			wantErr: "database.backend must be sqlite or postgres",
+		},
 	}
 
 	for _, tc := range tests {
@@ -1025,6 +1043,21 @@ subscriptions:
 `,
 			wantErr: "monthly_cost cannot be negative",
 		},
+		{
+			name: "unknown pacing policy",
+			yaml: `
+version: 1
+health:
+  refresh_threshold: 10m
+  warning_threshold: 1h
+  penalty_decay_rate: 0.8
+  penalty_decay_interval: 5m
+pacing:
+  max_sessions_per_hour: 4
+  when_limited: queue
+`,
+			wantErr: "pacing.when_limited must be wait, rotate or fail",
+		},
 	}
 
 	for _, tc := range tests {
@@ -1106,3 +1139,64 @@ func TestNewConfigSectionLoadAndSave(t *testing.T) {
 		t.Errorf("Loaded Subscriptions[gemini] = %+v, want monthly_cost 275", sub)
 	}
 }
+
+func TestDisplayConfigLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{"", time.Local.String(), false},
+		{"local", time.Local.String(), false},
+		{"UTC", "UTC", false},
+		{"utc", "UTC", false},
+		{"Europe/Berlin", "Europe/Berlin", false},
+		{"Mars/Olympus", "", true},
+	}
+	for _, tt := range tests {
+		loc, err := DisplayConfig{Timezone: tt.timezone}.Location()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Location(%q) error = nil, want error", tt.timezone)
+			}
+			cfg := DefaultSPMConfig()
+			cfg.Display.Timezone = tt.timezone
+			if cfg.Validate() == nil {
+				t.Errorf("Validate() accepted display.timezone %q", tt.timezone)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Location(%q) error = %v", tt.timezone, err)
+			continue
+		}
+		if loc.String() != tt.want {
+			t.Errorf("Location(%q) = %s, want %s", tt.timezone, loc, tt.want)
+		}
+	}
+}
+
+func TestFleetConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []FleetMember
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"machine and coordinator", []FleetMember{
+			{Name: "box", Machine: "box"},
+			{Name: "coord", Coordinator: "http://100.64.0.7:7890", Token: "t"},
+		}, false},
+		{"missing name", []FleetMember{{Machine: "box"}}, true},
+		{"duplicate name", []FleetMember{{Name: "a", Machine: "a"}, {Name: "a", Machine: "b"}}, true},
+		{"neither", []FleetMember{{Name: "a"}}, true},
+		{"both", []FleetMember{{Name: "a", Machine: "a", Coordinator: "http://a"}}, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultSPMConfig()
+		cfg.Fleet.Members = tt.members
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
 )
 
 // APIServer exposes the coordinator's HTTP API.
@@ -14,6 +16,7 @@ type APIServer struct {
 	coordinator *Coordinator
 	server      *http.Server
 	logger      *slog.Logger
+	guard       *apitoken.Guard
 }
 
 // NewAPIServer creates a new API server.
@@ -25,13 +28,14 @@ func NewAPIServer(coordinator *Coordinator, port int, logger *slog.Logger) *APIS
 	api := &APIServer{
 		coordinator: coordinator,
 		logger:      logger,
+		guard:       apitoken.NewGuard(nil, logger),
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /status", api.handleStatus)
-	mux.HandleFunc("GET /auth/pending", api.handleGetPending)
-	mux.HandleFunc("POST /auth/complete", api.handleComplete)
-	mux.HandleFunc("GET /panes", api.handleListPanes)
+	mux.HandleFunc("GET /status", api.guard.Require(apitoken.ScopeRead, api.handleStatus))
+	mux.HandleFunc("GET /auth/pending", api.guard.Require(apitoken.ScopeRead, api.handleGetPending))
+	mux.HandleFunc("POST /auth/complete", api.guard.Require(apitoken.ScopeActivate, api.handleComplete))
+	mux.HandleFunc("GET /panes", api.guard.Require(apitoken.ScopeRead, api.handleListPanes))
 
 	api.server = &http.Server{
 		Addr:         fmt.Sprintf(":%d", port),
@@ -43,6 +47,13 @@ func NewAPIServer(coordinator *Coordinator, port int, logger *slog.Logger) *APIS
 	return api
 }
 
+// RequireTokens makes the API accept only requests carrying one of tokens
+// with the scope each endpoint needs: read for status and listings, activate
+// for completing a login. With no tokens the API is open.
+func (a *APIServer) RequireTokens(tokens []apitoken.Token) {
+	a.guard.SetTokens(tokens)
+}
+
 // Start begins serving the API.
 func (a *APIServer) Start() error {
 	a.logger.Info("starting API server", "addr", a.server.Addr)
@@ -158,9 +169,14 @@ func (a *APIServer) handleComplete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tokenName := ""
+	if tok, ok := apitoken.FromContext(r.Context()); ok {
+		tokenName = tok.Name
+	}
 	a.logger.Info("auth response received",
 		"request_id", req.RequestID,
-		"account", req.Account)
+		"account", req.Account,
+		"token", tokenName)
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
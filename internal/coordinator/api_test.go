@@ -0,0 +1,47 @@
+package coordinator
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/apitoken"
+)
+
+func TestAPIServer_TokenScopes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := DefaultConfig()
+	cfg.Backend = BackendTmux
+	cfg.Logger = logger
+	api := NewAPIServer(New(cfg), 0, logger)
+
+	readSecret, readTok, _ := apitoken.Generate("dashboard", apitoken.ScopeRead, time.Now())
+	actSecret, actTok, _ := apitoken.Generate("agent", apitoken.ScopeActivate, time.Now())
+	api.RequireTokens([]apitoken.Token{readTok, actTok})
+
+	call := func(method, path, secret string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{"request_id":"missing","code":"x"}`))
+		apitoken.SetAuthorization(req, secret)
+		rec := httptest.NewRecorder()
+		api.server.Handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := call(http.MethodGet, "/auth/pending", ""); code != http.StatusUnauthorized {
+		t.Errorf("pending without token = %d, want 401", code)
+	}
+	if code := call(http.MethodGet, "/auth/pending", readSecret); code != http.StatusOK {
+		t.Errorf("pending with read token = %d, want 200", code)
+	}
+	if code := call(http.MethodPost, "/auth/complete", readSecret); code != http.StatusForbidden {
+		t.Errorf("complete with read token = %d, want 403", code)
+	}
+	// Past the token check, the unknown request is what fails.
+	if code := call(http.MethodPost, "/auth/complete", actSecret); code != http.StatusNotFound {
+		t.Errorf("complete with activate token = %d, want 404", code)
+	}
+}
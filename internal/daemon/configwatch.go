@@ -0,0 +1,149 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/fsnotify/fsnotify"
+)
+
+// configDebounce is how long the config watcher waits for writes to settle,
+// so one save (often several writes, or a rename over the old file) causes
+// one reload.
+const configDebounce = 500 * time.Millisecond
+
+// watchConfig applies edits to config.yaml (daemon settings) and config.json
+// (the backup schedule) while the daemon runs. It watches the directories
+// rather than the files, because editors often save by replacing the file.
+func (d *Daemon) watchConfig() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		d.logger.Printf("Config watching unavailable: %v", err)
+		return
+	}
+	defer watcher.Close()
+
+	files := make(map[string]bool)
+	for _, path := range []string{config.SPMConfigPath(), config.ConfigPath()} {
+		path = filepath.Clean(path)
+		files[path] = true
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			if d.isVerbose() || !os.IsNotExist(err) {
+				d.logger.Printf("Not watching %s for config changes: %v", path, err)
+			}
+			continue
+		}
+		if d.isVerbose() {
+			d.logger.Printf("Watching %s for config changes", path)
+		}
+	}
+
+	debounce := time.NewTimer(configDebounce)
+	debounce.Stop()
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if files[filepath.Clean(event.Name)] && !event.Has(fsnotify.Chmod) {
+				debounce.Reset(configDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			d.logger.Printf("Config watcher error: %v", err)
+		case <-debounce.C:
+			spmCfg, err := config.LoadSPMConfig()
+			if err != nil {
+				d.logger.Printf("Config change rejected, keeping current settings: %v", err)
+				continue
+			}
+			d.applyConfig(spmCfg, "file changed", false)
+		}
+	}
+}
+
+// applyConfig applies spmCfg's daemon settings and config.json's backup
+// schedule, logging each change. Without force, only settings that differ
+// from the file values last applied are touched.
+func (d *Daemon) applyConfig(spmCfg *config.SPMConfig, reason string, force bool) {
+	globalCfg, globalErr := config.Load()
+
+	var changes []string
+	intervalsChanged := false
+	d.configMu.Lock()
+	prev, next := d.fileDaemon, spmCfg.Daemon
+	if force || next.CheckInterval != prev.CheckInterval {
+		interval := next.CheckInterval.Duration()
+		if interval <= 0 {
+			interval = DefaultCheckInterval
+		}
+		if interval != d.config.CheckInterval {
+			changes = append(changes, fmt.Sprintf("check_interval %v -> %v", d.config.CheckInterval, interval))
+			d.config.CheckInterval = interval
+			intervalsChanged = true
+		}
+	}
+	if force || next.RefreshThreshold != prev.RefreshThreshold {
+		threshold := next.RefreshThreshold.Duration()
+		if threshold <= 0 {
+			threshold = DefaultRefreshThreshold
+		}
+		if threshold != d.config.RefreshThreshold {
+			changes = append(changes, fmt.Sprintf("refresh_threshold %v -> %v", d.config.RefreshThreshold, threshold))
+			d.config.RefreshThreshold = threshold
+			intervalsChanged = true
+		}
+	}
+	if (force || next.Verbose != prev.Verbose) && next.Verbose != d.config.Verbose {
+		changes = append(changes, fmt.Sprintf("verbose %v -> %v", d.config.Verbose, next.Verbose))
+		d.config.Verbose = next.Verbose
+	}
+	if next.WatchConfig != prev.WatchConfig {
+		changes = append(changes, "watch_config takes effect on restart")
+	}
+	d.fileDaemon = next
+
+	if globalErr == nil && globalCfg.Backup != d.fileBackup {
+		backup := globalCfg.Backup
+		d.fileBackup = backup
+		if backup.IsEnabled() {
+			d.backupScheduler = NewBackupScheduler(&backup, d.vault.BasePath(), d.logger)
+			if err := d.backupScheduler.LoadState(); err != nil {
+				d.logger.Printf("Warning: failed to load backup state: %v", err)
+			}
+			changes = append(changes, fmt.Sprintf("backups every %v, keeping %d, in %s", backup.GetInterval(), backup.GetKeepLast(), backup.GetLocation()))
+		} else {
+			d.backupScheduler = nil
+			changes = append(changes, "backups disabled")
+		}
+	}
+	d.configMu.Unlock()
+
+	if globalErr != nil {
+		d.logger.Printf("Backup schedule unchanged: %v", globalErr)
+	}
+	if len(changes) == 0 {
+		d.logger.Printf("Config reloaded (%s): no daemon settings changed", reason)
+		return
+	}
+	d.logger.Printf("Config reloaded (%s): %s", reason, strings.Join(changes, "; "))
+	if intervalsChanged && d.poolMonitor != nil {
+		d.logger.Println("Auth pool monitor keeps its check interval and threshold until restart")
+	}
+
+	// Signal runLoop to update ticker
+	select {
+	case d.configChanged <- struct{}{}:
+	default:
+		// Already signaled
+	}
+}
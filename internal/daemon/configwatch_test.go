@@ -0,0 +1,169 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+)
+
+// lockedBuffer is a log sink safe to read while the daemon writes to it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newConfigTestDaemon returns a daemon started with a 1m check interval
+// (as if from a flag) and empty config dirs, logging to the returned buffer.
+func newConfigTestDaemon(t *testing.T) (*Daemon, *lockedBuffer, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	caamHome := filepath.Join(tmpDir, "caam-home")
+	if err := os.MkdirAll(caamHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CAAM_HOME", caamHome)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "xdg"))
+
+	v := authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	hs := health.NewStorage(filepath.Join(tmpDir, "health.json"))
+	d := New(v, hs, &Config{CheckInterval: time.Minute, RefreshThreshold: 10 * time.Minute})
+
+	logs := &lockedBuffer{}
+	d.logger = log.New(logs, "", 0)
+	return d, logs, caamHome
+}
+
+func TestApplyConfig_OnlyChangedSettings(t *testing.T) {
+	d, logs, _ := newConfigTestDaemon(t)
+
+	spmCfg := config.DefaultSPMConfig()
+	spmCfg.Daemon.Verbose = true
+	d.applyConfig(spmCfg, "file changed", false)
+
+	if !d.isVerbose() {
+		t.Error("verbose should follow the edited file")
+	}
+	if got := d.getCheckInterval(); got != time.Minute {
+		t.Errorf("check interval = %v, want the 1m it started with (the file's didn't change)", got)
+	}
+	if !strings.Contains(logs.String(), "Config reloaded (file changed): verbose false -> true") {
+		t.Errorf("log = %q", logs.String())
+	}
+
+	spmCfg.Daemon.CheckInterval = config.Duration(2 * time.Minute)
+	d.applyConfig(spmCfg, "file changed", false)
+	if got := d.getCheckInterval(); got != 2*time.Minute {
+		t.Errorf("check interval = %v, want 2m after the edit", got)
+	}
+	if got := d.getRefreshThreshold(); got != 10*time.Minute {
+		t.Errorf("refresh threshold = %v, want 10m (unchanged in the file)", got)
+	}
+
+	d.applyConfig(spmCfg, "SIGHUP", true)
+	if got := d.getRefreshThreshold(); got != 30*time.Minute {
+		t.Errorf("refresh threshold = %v, want the file's 30m after a forced reload", got)
+	}
+
+	d.applyConfig(spmCfg, "file changed", false)
+	if !strings.Contains(logs.String(), "Config reloaded (file changed): no daemon settings changed") {
+		t.Errorf("an unchanged file should be logged as such, log = %q", logs.String())
+	}
+}
+
+func TestApplyConfig_BackupSchedule(t *testing.T) {
+	d, logs, _ := newConfigTestDaemon(t)
+	if d.getBackupScheduler() != nil {
+		t.Fatal("backups should start disabled")
+	}
+
+	globalCfg := config.DefaultConfig()
+	globalCfg.Backup.Enabled = true
+	globalCfg.Backup.Interval = config.Duration(24 * time.Hour)
+	globalCfg.Backup.Location = t.TempDir()
+	if err := globalCfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	d.applyConfig(config.DefaultSPMConfig(), "file changed", false)
+	if d.getBackupScheduler() == nil {
+		t.Fatal("enabling backups in config.json should start the scheduler")
+	}
+	if !strings.Contains(logs.String(), "backups every 24h0m0s") {
+		t.Errorf("log = %q", logs.String())
+	}
+
+	globalCfg.Backup.Enabled = false
+	if err := globalCfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	d.applyConfig(config.DefaultSPMConfig(), "file changed", false)
+	if d.getBackupScheduler() != nil {
+		t.Error("disabling backups should stop the scheduler")
+	}
+}
+
+func TestWatchConfig_AppliesEditsAndRejectsInvalid(t *testing.T) {
+	d, logs, caamHome := newConfigTestDaemon(t)
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.watchConfig()
+	}()
+	t.Cleanup(func() {
+		d.cancel()
+		<-done
+	})
+
+	path := filepath.Join(caamHome, "config.yaml")
+	waitFor := func(what string, write []byte, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s; log = %q", what, logs.String())
+			}
+			// Rewrite until seen: the watch may not be in place yet.
+			if err := os.WriteFile(path, write, 0600); err != nil {
+				t.Fatal(err)
+			}
+			time.Sleep(2 * configDebounce)
+		}
+	}
+
+	waitFor("invalid config to be rejected", []byte("daemon: [not, a, map]\n"), func() bool {
+		return strings.Contains(logs.String(), "Config change rejected")
+	})
+	if got := d.getCheckInterval(); got != time.Minute {
+		t.Errorf("check interval = %v after a rejected edit, want 1m", got)
+	}
+
+	waitFor("check interval edit", []byte("daemon:\n  check_interval: 3m\n"), func() bool {
+		return d.getCheckInterval() == 3*time.Minute
+	})
+	if !strings.Contains(logs.String(), "check_interval 1m0s -> 3m0s") {
+		t.Errorf("log = %q", logs.String())
+	}
+}
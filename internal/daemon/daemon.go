@@ -17,7 +17,9 @@ import (
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authpool"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/notify"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/refresh"
+	syncstate "github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
 )
 
 // DefaultCheckInterval is the default time between refresh checks.
@@ -86,6 +88,12 @@ type Daemon struct {
 	stats   Stats
 
 	configMu sync.RWMutex // Protects config access during runtime reloads
+
+	// The config file values last applied, so a reload only touches the
+	// settings an edit changed (flags passed at start keep winning until
+	// then). Protected by configMu, as is backupScheduler after Start.
+	fileDaemon config.DaemonConfig
+	fileBackup config.BackupConfig
 }
 
 // Stats tracks daemon activity.
@@ -124,6 +132,14 @@ func (d *Daemon) getRefreshThreshold() time.Duration {
 	return d.config.RefreshThreshold
 }
 
+// getBackupScheduler returns the backup scheduler (nil if backups are off)
+// with proper locking.
+func (d *Daemon) getBackupScheduler() *BackupScheduler {
+	d.configMu.RLock()
+	defer d.configMu.RUnlock()
+	return d.backupScheduler
+}
+
 // isVerbose returns the verbose setting with proper locking.
 func (d *Daemon) isVerbose() bool {
 	d.configMu.RLock()
@@ -163,12 +179,19 @@ func New(vault *authfile.Vault, healthStore *health.Storage, cfg *Config) *Daemo
 
 	// Initialize backup scheduler from global config
 	globalCfg, err := config.Load()
+	if err == nil {
+		d.fileBackup = globalCfg.Backup
+	}
 	if err == nil && globalCfg.Backup.IsEnabled() {
 		d.backupScheduler = NewBackupScheduler(&globalCfg.Backup, vault.BasePath(), logger)
 		if loadErr := d.backupScheduler.LoadState(); loadErr != nil {
 			logger.Printf("Warning: failed to load backup state: %v", loadErr)
 		}
 	}
+	d.fileDaemon = config.DefaultSPMConfig().Daemon
+	if spmCfg, err := config.LoadSPMConfig(); err == nil {
+		d.fileDaemon = spmCfg.Daemon
+	}
 
 	// Initialize auth pool if enabled
 	if cfg.UseAuthPool {
@@ -283,6 +306,14 @@ func (d *Daemon) Start() error {
 		d.runLoop()
 	}()
 
+	if d.fileDaemon.WatchConfig {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.watchConfig()
+		}()
+	}
+
 	// Wait for signal
 	for {
 		select {
@@ -293,8 +324,7 @@ func (d *Daemon) Start() error {
 				continue
 			}
 			d.logger.Printf("Received signal %v, shutting down...", sig)
-			signal.Stop(sigCh) // Clean up signal handler before stopping
-			return d.Stop()
+			return d.stopUnlessForced(sigCh)
 		case <-d.ctx.Done():
 			signal.Stop(sigCh) // Clean up signal handler before stopping
 			return d.Stop()
@@ -302,7 +332,35 @@ func (d *Daemon) Start() error {
 	}
 }
 
-// ReloadConfig reloads the configuration from disk.
+// ErrForcedShutdown is returned by Start when shutdown was cut short: a
+// second signal arrived, or the main loop outlasted the stop timeout.
+var ErrForcedShutdown = errors.New("daemon shutdown forced")
+
+// stopUnlessForced runs Stop while still listening on sigCh, so a second
+// SIGINT/SIGTERM abandons the graceful stop. The PID file is released
+// either way.
+func (d *Daemon) stopUnlessForced(sigCh chan os.Signal) error {
+	defer signal.Stop(sigCh)
+
+	done := make(chan error, 1)
+	go func() { done <- d.Stop() }()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case sig := <-sigCh:
+			if sig == syscall.SIGHUP {
+				continue
+			}
+			d.logger.Printf("Received signal %v during shutdown, exiting immediately", sig)
+			d.releasePIDLock()
+			return ErrForcedShutdown
+		}
+	}
+}
+
+// ReloadConfig reloads the configuration from disk (on SIGHUP). Unlike a
+// file change, it applies every daemon setting from the file.
 func (d *Daemon) ReloadConfig() {
 	// Load global config
 	globalCfg, err := config.LoadSPMConfig()
@@ -317,27 +375,7 @@ func (d *Daemon) ReloadConfig() {
 		return
 	}
 
-	// Apply updates with proper locking
-	d.configMu.Lock()
-	d.config.Verbose = globalCfg.Daemon.Verbose
-	d.config.CheckInterval = globalCfg.Daemon.CheckInterval.Duration()
-	if d.config.CheckInterval <= 0 {
-		d.config.CheckInterval = DefaultCheckInterval
-	}
-	d.config.RefreshThreshold = globalCfg.Daemon.RefreshThreshold.Duration()
-	if d.config.RefreshThreshold <= 0 {
-		d.config.RefreshThreshold = DefaultRefreshThreshold
-	}
-	d.configMu.Unlock()
-
-	d.logger.Println("Config reloaded (runtime settings applied)")
-	
-	// Signal runLoop to update ticker
-	select {
-	case d.configChanged <- struct{}{}:
-	default:
-		// Already signaled
-	}
+	d.applyConfig(globalCfg, "SIGHUP", true)
 }
 
 // Stop gracefully stops the daemon.
@@ -375,11 +413,13 @@ func (d *Daemon) Stop() error {
 		close(done)
 	}()
 
+	var stopErr error
 	select {
 	case <-done:
 		d.logger.Println("Daemon stopped gracefully")
 	case <-time.After(10 * time.Second):
 		d.logger.Println("Daemon stop timed out")
+		stopErr = ErrForcedShutdown
 	}
 
 	// Close log file if we opened one
@@ -388,17 +428,21 @@ func (d *Daemon) Stop() error {
 		d.logFile = nil
 	}
 
-	// Release PID lock
+	d.releasePIDLock()
+
+	return stopErr
+}
+
+// releasePIDLock unlocks and removes the PID file if this daemon holds it.
+func (d *Daemon) releasePIDLock() {
 	d.mu.Lock()
+	defer d.mu.Unlock()
 	if d.pidFile != nil {
 		health.UnlockFile(d.pidFile)
 		d.pidFile.Close()
 		os.Remove(d.pidFile.Name()) // Clean up file
 		d.pidFile = nil
 	}
-	d.mu.Unlock()
-
-	return nil
 }
 
 // IsRunning returns whether the daemon is currently running.
@@ -416,12 +460,12 @@ func (d *Daemon) GetStats() Stats {
 	stats := d.stats
 
 	// Add backup stats if scheduler is enabled
-	if d.backupScheduler != nil {
+	if scheduler := d.getBackupScheduler(); scheduler != nil {
 		stats.BackupEnabled = true
-		state := d.backupScheduler.GetState()
+		state := scheduler.GetState()
 		stats.LastBackup = state.LastBackup
 		stats.BackupCount = state.BackupCount
-		stats.NextBackup = d.backupScheduler.NextBackupTime()
+		stats.NextBackup = scheduler.NextBackupTime()
 	}
 
 	// Add pool stats if enabled
@@ -467,10 +511,16 @@ func (d *Daemon) runLoop() {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	d.checkReverts()
+	revertTicker := time.NewTicker(RevertCheckInterval)
+	defer revertTicker.Stop()
+
 	for {
 		select {
 		case <-d.ctx.Done():
 			return
+		case <-revertTicker.C:
+			d.checkReverts()
 		case <-d.configChanged:
 			newInterval := d.getCheckInterval()
 			if newInterval <= 0 {
@@ -487,6 +537,7 @@ func (d *Daemon) runLoop() {
 				d.checkAndRefresh()
 			}
 			d.checkAndBackup()
+			d.checkSyncQueue()
 		}
 	}
 }
@@ -560,15 +611,38 @@ func (d *Daemon) acquirePIDLock() error {
 	return nil
 }
 
+// checkReverts switches back time-boxed activations whose time is up.
+func (d *Daemon) checkReverts() {
+	outcomes, err := ApplyDueReverts(d.vault, time.Now())
+	if err != nil {
+		d.logger.Printf("Could not check scheduled reverts: %v", err)
+		return
+	}
+	for _, o := range outcomes {
+		r := o.Revert
+		switch {
+		case o.Err != nil:
+			d.logger.Printf("%s/%s: revert failed: %v", r.Tool, r.Profile, o.Err)
+		case !o.Applied:
+			d.logger.Printf("%s/%s: no longer active, nothing to revert", r.Tool, r.Profile)
+		case r.RevertTo == "":
+			d.logger.Printf("%s/%s: time is up, logged out", r.Tool, r.Profile)
+		default:
+			d.logger.Printf("%s/%s: time is up, reverted to %s", r.Tool, r.Profile, r.RevertTo)
+		}
+	}
+}
+
 // checkAndBackup creates a backup if one is due.
 func (d *Daemon) checkAndBackup() {
-	if d.backupScheduler == nil {
+	scheduler := d.getBackupScheduler()
+	if scheduler == nil {
 		return
 	}
 
-	if !d.backupScheduler.ShouldBackup() {
+	if !scheduler.ShouldBackup() {
 		if d.isVerbose() {
-			next := d.backupScheduler.NextBackupTime()
+			next := scheduler.NextBackupTime()
 			if !next.IsZero() {
 				d.logger.Printf("Next backup scheduled for %v", next.Format(time.RFC3339))
 			}
@@ -576,7 +650,7 @@ func (d *Daemon) checkAndBackup() {
 		return
 	}
 
-	backupPath, err := d.backupScheduler.CreateBackup()
+	backupPath, err := scheduler.CreateBackup()
 	if err != nil {
 		d.mu.Lock()
 		d.stats.BackupErrors++
@@ -590,6 +664,42 @@ func (d *Daemon) checkAndBackup() {
 	}
 }
 
+// checkSyncQueue retries failed syncs that are due under the sync pool's
+// retry policy.
+func (d *Daemon) checkSyncQueue() {
+	cfg := syncstate.DefaultAutoSyncConfig()
+	cfg.Verbose = d.isVerbose()
+	cfg.OnGiveUp = func(e syncstate.QueueEntry) {
+		notifier := notify.NewDesktopNotifier()
+		if !notifier.Available() {
+			return
+		}
+		_ = notifier.Notify(&notify.Alert{
+			Level:     notify.Warning,
+			Title:     "caam sync gave up",
+			Message:   fmt.Sprintf("%s/%s could not be synced to %s after %d attempts: %s", e.Provider, e.Profile, e.Machine, e.Attempts, e.LastError),
+			Profile:   e.Provider + "/" + e.Profile,
+			Timestamp: time.Now(),
+			Action:    "caam sync retry --now",
+		})
+	}
+
+	report, err := syncstate.ProcessQueue(cfg)
+	if err != nil {
+		d.logger.Printf("Sync queue: %v", err)
+		return
+	}
+	if report.Retried > 0 {
+		d.logger.Printf("Sync queue: retried %d, %d succeeded, %d waiting", report.Retried, report.Succeeded, report.Waiting)
+	}
+	for _, e := range report.GaveUp {
+		d.logger.Printf("Sync queue: gave up on %s/%s to %s after %d attempts: %s", e.Provider, e.Profile, e.Machine, e.Attempts, e.LastError)
+	}
+	for _, e := range report.Conflicts {
+		d.logger.Printf("Sync queue: %s/%s changed both here and on %s; run 'caam sync' to pick a side", e.Provider, e.Profile, e.Machine)
+	}
+}
+
 // checkAndRefresh checks all profiles and refreshes those that need it.
 func (d *Daemon) checkAndRefresh() {
 	d.mu.Lock()
@@ -697,9 +807,12 @@ func (d *Daemon) getProfileHealth(provider, profile string) *health.ProfileHealt
 	}
 
 	// Fall back to parsing the auth files directly
-	vaultPath := d.vault.ProfilePath(provider, profile)
+	vaultPath, cleanup, err := d.vault.PlainProfileDir(provider, profile)
+	if err != nil {
+		return nil
+	}
+	defer cleanup()
 	var expiryInfo *health.ExpiryInfo
-	var err error
 
 	switch provider {
 	case "claude":
@@ -745,21 +858,19 @@ func SetPIDFilePath(path string) {
 	pidFilePath = path
 }
 
-// PIDFilePath returns the path to the daemon's PID file.
+// PIDFilePath returns the path to the daemon's PID file. It lives in the
+// user's data directory so daemons of different users on a shared host don't
+// contend for one file in the system temp directory.
 func PIDFilePath() string {
 	if pidFilePath != "" {
 		return pidFilePath
 	}
-	return filepath.Join(os.TempDir(), "caam-daemon.pid")
+	return filepath.Join(config.DefaultDataPath(), "caam-daemon.pid")
 }
 
 // LogFilePath returns the default path for daemon logs.
 func LogFilePath() string {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return filepath.Join(os.TempDir(), "caam-daemon.log")
-	}
-	return filepath.Join(homeDir, ".local", "share", "caam", "daemon.log")
+	return filepath.Join(config.DefaultDataPath(), "daemon.log")
 }
 
 // RemovePIDFile removes the PID file.
@@ -45,10 +45,13 @@ func (r *PoolRefresher) Refresh(ctx context.Context, provider, profile string) (
 
 // getTokenExpiry reads the token expiry for a profile.
 func (r *PoolRefresher) getTokenExpiry(provider, profile string) (time.Time, error) {
-	vaultPath := r.vault.ProfilePath(provider, profile)
+	vaultPath, cleanup, err := r.vault.PlainProfileDir(provider, profile)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer cleanup()
 
 	var expiryInfo *health.ExpiryInfo
-	var err error
 
 	switch provider {
 	case "claude":
@@ -0,0 +1,328 @@
+// Package daemon revert provides time-boxed activations that switch back
+// automatically when their time is up.
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
+)
+
+// RevertCheckInterval is how often the daemon looks for time-boxed
+// activations that are due to be reverted.
+const RevertCheckInterval = 30 * time.Second
+
+// ScheduledRevert is a time-boxed activation waiting to be switched back.
+type ScheduledRevert struct {
+	// Tool is the provider the activation applies to.
+	Tool string `json:"tool"`
+
+	// Profile is the profile activated for a limited time.
+	Profile string `json:"profile"`
+
+	// RevertTo is the profile restored when the time is up. Empty means the
+	// tool had no auth before, so it is logged out again.
+	RevertTo string `json:"revert_to,omitempty"`
+
+	// Account is the account the time-boxed profile logs in as. The tool
+	// may refresh its token while the profile is live, so the files no
+	// longer match the vault copy; the account still tells them apart.
+	Account string `json:"account,omitempty"`
+
+	// RevertAt is when the activation ends.
+	RevertAt time.Time `json:"revert_at"`
+
+	// CreatedAt is when the activation was made.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RevertOutcome reports what happened to a scheduled revert.
+type RevertOutcome struct {
+	Revert ScheduledRevert
+
+	// Applied is false when the time-boxed profile was no longer active,
+	// because something else was activated in the meantime.
+	Applied bool
+
+	// Kept is true when caam couldn't tell whether the time-boxed profile is
+	// still live. The revert stays scheduled, and Err says why.
+	Kept bool
+
+	Err error
+}
+
+// revertsMu serializes read-modify-write cycles on the reverts file within
+// a process.
+var revertsMu sync.Mutex
+
+// RevertsPath returns the path to the scheduled reverts file.
+func RevertsPath() string {
+	return filepath.Join(config.DefaultDataPath(), "reverts.json")
+}
+
+// LoadReverts returns the pending reverts, soonest first.
+func LoadReverts() ([]ScheduledRevert, error) {
+	revertsMu.Lock()
+	defer revertsMu.Unlock()
+	return loadRevertsLocked()
+}
+
+// PendingRevert returns the pending revert for tool, or nil if none.
+func PendingRevert(tool string) (*ScheduledRevert, error) {
+	reverts, err := LoadReverts()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reverts {
+		if r.Tool == tool {
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+// ScheduleRevert records r, replacing any pending revert for the same tool.
+// It is called right after r.Profile is activated, so an empty r.Account is
+// filled in from the live auth files.
+func ScheduleRevert(r ScheduledRevert) error {
+	if r.Tool == "" || r.Profile == "" {
+		return fmt.Errorf("schedule revert: tool and profile are required")
+	}
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+	if r.Account == "" {
+		if fileSet, ok := authfile.GetAuthFileSet(r.Tool); ok {
+			r.Account = liveAccount(fileSet)
+		}
+	}
+
+	revertsMu.Lock()
+	defer revertsMu.Unlock()
+
+	reverts, err := loadRevertsLocked()
+	if err != nil {
+		return err
+	}
+	reverts = withoutTool(reverts, r.Tool)
+	reverts = append(reverts, r)
+	return saveRevertsLocked(reverts)
+}
+
+// CancelRevert drops the pending revert for tool without applying it and
+// returns it, or nil if there was none.
+func CancelRevert(tool string) (*ScheduledRevert, error) {
+	revertsMu.Lock()
+	defer revertsMu.Unlock()
+
+	reverts, err := loadRevertsLocked()
+	if err != nil {
+		return nil, err
+	}
+	var cancelled *ScheduledRevert
+	for _, r := range reverts {
+		if r.Tool == tool {
+			cancelled = &r
+			break
+		}
+	}
+	if cancelled == nil {
+		return nil, nil
+	}
+	return cancelled, saveRevertsLocked(withoutTool(reverts, tool))
+}
+
+// ApplyDueReverts reverts every time-boxed activation due at now. Due
+// reverts are dropped afterwards so a failure is reported once rather than
+// retried forever, except those caam couldn't check (see RevertOutcome.Kept),
+// which stay scheduled until it can.
+func ApplyDueReverts(v *authfile.Vault, now time.Time) ([]RevertOutcome, error) {
+	return applyReverts(v, func(r ScheduledRevert) bool {
+		return !r.RevertAt.After(now)
+	})
+}
+
+// RevertNow reverts tool's time-boxed activation immediately, whether or
+// not it is due. It returns nil if tool has no pending revert.
+func RevertNow(v *authfile.Vault, tool string) (*RevertOutcome, error) {
+	outcomes, err := applyReverts(v, func(r ScheduledRevert) bool {
+		return r.Tool == tool
+	})
+	if err != nil || len(outcomes) == 0 {
+		return nil, err
+	}
+	return &outcomes[0], nil
+}
+
+func applyReverts(v *authfile.Vault, match func(ScheduledRevert) bool) ([]RevertOutcome, error) {
+	revertsMu.Lock()
+	defer revertsMu.Unlock()
+
+	reverts, err := loadRevertsLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	var outcomes []RevertOutcome
+	var remaining []ScheduledRevert
+	for _, r := range reverts {
+		if !match(r) {
+			remaining = append(remaining, r)
+			continue
+		}
+		outcome := applyRevert(v, r)
+		if outcome.Kept {
+			remaining = append(remaining, r)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	if len(outcomes) == 0 {
+		return nil, nil
+	}
+	return outcomes, saveRevertsLocked(remaining)
+}
+
+// applyRevert switches tool back to r.RevertTo if r.Profile is still the
+// active profile. The live files may have moved on from the vault copy
+// because the tool refreshed its token; then the account decides.
+func applyRevert(v *authfile.Vault, r ScheduledRevert) RevertOutcome {
+	outcome := RevertOutcome{Revert: r}
+	fileSet, ok := authfile.GetAuthFileSet(r.Tool)
+	if !ok {
+		outcome.Err = fmt.Errorf("unknown tool: %s", r.Tool)
+		return outcome
+	}
+	active, err := v.ActiveProfile(fileSet)
+	if err != nil {
+		outcome.Err = fmt.Errorf("detect active %s profile: %w", r.Tool, err)
+		return outcome
+	}
+
+	switch {
+	case active == r.Profile:
+	case active != "" || !authfile.HasAuthFiles(fileSet):
+		// Another profile was activated, or the tool was logged out.
+		return outcome
+	default:
+		account := liveAccount(fileSet)
+		switch {
+		case account == "" || r.Account == "":
+			outcome.Kept = true
+			outcome.Err = fmt.Errorf("live %s auth matches no profile and its account is unknown; left in place, revert still scheduled", r.Tool)
+			return outcome
+		case account != r.Account:
+			// Logged in as someone else outside caam.
+			return outcome
+		}
+		// Same account with a refreshed token: keep the refresh before
+		// switching away, or the profile is left holding a stale one.
+		if err := v.Backup(fileSet, r.Profile); err != nil {
+			outcome.Kept = true
+			outcome.Err = fmt.Errorf("save refreshed %s/%s: %w; revert still scheduled", r.Tool, r.Profile, err)
+			return outcome
+		}
+	}
+
+	if r.RevertTo == "" {
+		if err := authfile.ClearAuthFiles(fileSet); err != nil {
+			outcome.Err = fmt.Errorf("clear %s auth: %w", r.Tool, err)
+			return outcome
+		}
+		outcome.Applied = true
+		return outcome
+	}
+	if err := v.Restore(fileSet, r.RevertTo); err != nil {
+		outcome.Err = fmt.Errorf("restore %s/%s: %w", r.Tool, r.RevertTo, err)
+		return outcome
+	}
+	outcome.Applied = true
+	return outcome
+}
+
+// liveAccount returns the account tool's live auth files log in as, or ""
+// if they don't say.
+func liveAccount(fileSet authfile.AuthFileSet) string {
+	for _, spec := range fileSet.Files {
+		var id *identity.Identity
+		var err error
+		switch filepath.Base(spec.Path) {
+		case "auth.json":
+			id, err = identity.ExtractFromCodexAuth(spec.Path)
+		case ".credentials.json":
+			id, err = identity.ExtractFromClaudeCredentials(spec.Path)
+		case "settings.json", "oauth_credentials.json":
+			id, err = identity.ExtractFromGeminiConfig(spec.Path)
+		default:
+			continue
+		}
+		if err != nil || id == nil {
+			continue
+		}
+		if id.Email != "" {
+			return id.Email
+		}
+		if id.AccountID != "" {
+			return id.AccountID
+		}
+	}
+	return ""
+}
+
+func withoutTool(reverts []ScheduledRevert, tool string) []ScheduledRevert {
+	out := reverts[:0]
+	for _, r := range reverts {
+		if r.Tool != tool {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func loadRevertsLocked() ([]ScheduledRevert, error) {
+	data, err := os.ReadFile(RevertsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read reverts: %w", err)
+	}
+	var reverts []ScheduledRevert
+	if err := json.Unmarshal(data, &reverts); err != nil {
+		return nil, fmt.Errorf("parse reverts: %w", err)
+	}
+	sort.SliceStable(reverts, func(i, j int) bool {
+		return reverts[i].RevertAt.Before(reverts[j].RevertAt)
+	})
+	return reverts, nil
+}
+
+func saveRevertsLocked(reverts []ScheduledRevert) error {
+	path := RevertsPath()
+	if reverts == nil {
+		reverts = []ScheduledRevert{}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create reverts dir: %w", err)
+	}
+	data, err := json.MarshalIndent(reverts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal reverts: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("write reverts: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("rename reverts: %w", err)
+	}
+	return nil
+}
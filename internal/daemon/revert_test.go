@@ -0,0 +1,188 @@
+package daemon
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+)
+
+// setupRevertTest isolates caam's data dir and Codex's auth location and
+// returns a vault holding codex profiles "work" and "demo", with "demo" live.
+func setupRevertTest(t *testing.T) (*authfile.Vault, string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	if err := os.MkdirAll(os.Getenv("CODEX_HOME"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	v := authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	for _, profile := range []string{"work", "demo"} {
+		dir := v.ProfilePath("codex", profile)
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"`+profile+`"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fileSet := authfile.CodexAuthFiles()
+	if err := v.Restore(fileSet, "demo"); err != nil {
+		t.Fatal(err)
+	}
+	return v, fileSet.Files[0].Path
+}
+
+func TestApplyDueReverts(t *testing.T) {
+	v, live := setupRevertTest(t)
+	now := time.Now()
+
+	if err := ScheduleRevert(ScheduledRevert{Tool: "codex", Profile: "demo", RevertTo: "work", RevertAt: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("ScheduleRevert() error = %v", err)
+	}
+	if pending, err := PendingRevert("codex"); err != nil || pending == nil || pending.RevertTo != "work" {
+		t.Fatalf("PendingRevert() = %+v, %v", pending, err)
+	}
+
+	outcomes, err := ApplyDueReverts(v, now)
+	if err != nil || len(outcomes) != 0 {
+		t.Fatalf("ApplyDueReverts(before due) = %+v, %v; want nothing", outcomes, err)
+	}
+
+	outcomes, err = ApplyDueReverts(v, now.Add(2*time.Hour))
+	if err != nil || len(outcomes) != 1 || !outcomes[0].Applied || outcomes[0].Err != nil {
+		t.Fatalf("ApplyDueReverts(after due) = %+v, %v; want one applied", outcomes, err)
+	}
+	if data, _ := os.ReadFile(live); string(data) != `{"access_token":"work"}` {
+		t.Errorf("live auth = %s, want work restored", data)
+	}
+	if pending, _ := PendingRevert("codex"); pending != nil {
+		t.Errorf("pending after revert = %+v, want none", pending)
+	}
+}
+
+func TestApplyDueReverts_SkipsWhenProfileNoLongerActive(t *testing.T) {
+	v, live := setupRevertTest(t)
+
+	if err := ScheduleRevert(ScheduledRevert{Tool: "codex", Profile: "work", RevertTo: "demo", RevertAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	outcomes, err := ApplyDueReverts(v, time.Now().Add(time.Minute))
+	if err != nil || len(outcomes) != 1 || outcomes[0].Applied {
+		t.Fatalf("ApplyDueReverts() = %+v, %v; want one skipped", outcomes, err)
+	}
+	if data, _ := os.ReadFile(live); string(data) != `{"access_token":"demo"}` {
+		t.Errorf("live auth = %s, want it left alone", data)
+	}
+}
+
+// codexAuth returns a Codex auth.json logging in as email with token.
+func codexAuth(t *testing.T, email, token string) []byte {
+	t.Helper()
+	claims, err := json.Marshal(map[string]any{"email": email})
+	if err != nil {
+		t.Fatal(err)
+	}
+	idToken := "e30." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+	data, err := json.Marshal(map[string]any{"id_token": idToken, "access_token": token})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestApplyDueReverts_FollowsRefreshedToken(t *testing.T) {
+	v, live := setupRevertTest(t)
+	profileAuth := filepath.Join(v.ProfilePath("codex", "work"), "auth.json")
+	if err := os.WriteFile(profileAuth, codexAuth(t, "work@example.com", "old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Restore(authfile.CodexAuthFiles(), "work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ScheduleRevert(ScheduledRevert{Tool: "codex", Profile: "work", RevertTo: "demo", RevertAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Codex refreshes its token before the time is up.
+	refreshed := codexAuth(t, "work@example.com", "new")
+	if err := os.WriteFile(live, refreshed, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	outcomes, err := ApplyDueReverts(v, time.Now().Add(time.Minute))
+	if err != nil || len(outcomes) != 1 || !outcomes[0].Applied {
+		t.Fatalf("ApplyDueReverts() = %+v, %v; want one applied", outcomes, err)
+	}
+	if data, _ := os.ReadFile(live); string(data) != `{"access_token":"demo"}` {
+		t.Errorf("live auth = %s, want demo restored", data)
+	}
+	if data, _ := os.ReadFile(profileAuth); string(data) != string(refreshed) {
+		t.Errorf("work profile = %s, want the refreshed token kept", data)
+	}
+}
+
+func TestApplyDueReverts_KeepsRevertWhenAccountUnknown(t *testing.T) {
+	v, live := setupRevertTest(t)
+
+	if err := ScheduleRevert(ScheduledRevert{Tool: "codex", Profile: "demo", RevertTo: "work", RevertAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(live, []byte(`{"access_token":"refreshed"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	outcomes, err := ApplyDueReverts(v, time.Now().Add(time.Minute))
+	if err != nil || len(outcomes) != 1 || outcomes[0].Applied || !outcomes[0].Kept || outcomes[0].Err == nil {
+		t.Fatalf("ApplyDueReverts() = %+v, %v; want one kept with an error", outcomes, err)
+	}
+	if data, _ := os.ReadFile(live); string(data) != `{"access_token":"refreshed"}` {
+		t.Errorf("live auth = %s, want it left alone", data)
+	}
+	if pending, _ := PendingRevert("codex"); pending == nil {
+		t.Error("revert should still be scheduled")
+	}
+}
+
+func TestRevertNow_LogsOutWhenNothingBefore(t *testing.T) {
+	v, live := setupRevertTest(t)
+
+	if err := ScheduleRevert(ScheduledRevert{Tool: "codex", Profile: "demo", RevertAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	outcome, err := RevertNow(v, "codex")
+	if err != nil || outcome == nil || !outcome.Applied {
+		t.Fatalf("RevertNow() = %+v, %v; want applied", outcome, err)
+	}
+	if _, err := os.Stat(live); !os.IsNotExist(err) {
+		t.Errorf("live auth should be cleared, stat err = %v", err)
+	}
+	if outcome, err := RevertNow(v, "codex"); err != nil || outcome != nil {
+		t.Errorf("second RevertNow() = %+v, %v; want nil", outcome, err)
+	}
+}
+
+func TestCancelRevert(t *testing.T) {
+	setupRevertTest(t)
+
+	if cancelled, err := CancelRevert("codex"); err != nil || cancelled != nil {
+		t.Fatalf("CancelRevert(none) = %+v, %v", cancelled, err)
+	}
+	_ = ScheduleRevert(ScheduledRevert{Tool: "codex", Profile: "demo", RevertTo: "work", RevertAt: time.Now().Add(time.Hour)})
+	_ = ScheduleRevert(ScheduledRevert{Tool: "claude", Profile: "demo", RevertAt: time.Now().Add(time.Hour)})
+
+	cancelled, err := CancelRevert("codex")
+	if err != nil || cancelled == nil || cancelled.Profile != "demo" {
+		t.Fatalf("CancelRevert() = %+v, %v", cancelled, err)
+	}
+	reverts, _ := LoadReverts()
+	if len(reverts) != 1 || reverts[0].Tool != "claude" {
+		t.Errorf("reverts = %+v, want only claude's", reverts)
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// LinkAccountProfile records that provider/profile authenticates as the
+// account identified by accountKey (see account.Key). An empty key removes
+// any existing link.
+func (d *DB) LinkAccountProfile(provider, profile, accountKey string) error {
+	if d == nil || d.conn == nil {
+		return fmt.Errorf("db is not open")
+	}
+
+	provider = strings.TrimSpace(provider)
+	profile = strings.TrimSpace(profile)
+	accountKey = strings.TrimSpace(accountKey)
+	if provider == "" {
+		return fmt.Errorf("provider is required")
+	}
+	if profile == "" {
+		return fmt.Errorf("profile name is required")
+	}
+
+	if accountKey == "" {
+		if _, err := d.conn.Exec(`DELETE FROM account_profiles WHERE provider = ? AND profile_name = ?`, provider, profile); err != nil {
+			return fmt.Errorf("delete account_profiles: %w", err)
+		}
+		return nil
+	}
+
+	_, err := d.conn.Exec(
+		`INSERT INTO account_profiles (provider, profile_name, account_key, updated_at)
+		 VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		 ON CONFLICT(provider, profile_name) DO UPDATE SET
+		   account_key = excluded.account_key,
+		   updated_at = excluded.updated_at`,
+		provider,
+		profile,
+		accountKey,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert account_profiles: %w", err)
+	}
+	return nil
+}
+
+// AccountSiblings returns the other profile names linked to the same account
+// as provider/profile, in sorted order. Unlinked profiles have no siblings.
+func (d *DB) AccountSiblings(provider, profile string) ([]string, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+	return accountSiblings(d.conn, strings.TrimSpace(provider), strings.TrimSpace(profile))
+}
+
+func accountSiblings(query sqlRowsQueryer, provider, profile string) ([]string, error) {
+	rows, err := query.Query(
+		`SELECT ap.profile_name
+		   FROM account_profiles ap
+		   JOIN account_profiles self
+		     ON self.provider = ap.provider AND self.account_key = ap.account_key
+		  WHERE self.provider = ? AND self.profile_name = ? AND ap.profile_name != self.profile_name
+		  ORDER BY ap.profile_name ASC`,
+		provider,
+		profile,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query account_profiles: %w", err)
+	}
+	defer rows.Close()
+
+	var out []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan account_profiles: %w", err)
+		}
+		out = append(out, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate account_profiles: %w", err)
+	}
+	return out, nil
+}
+
+type sqlRowsQueryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// Backend is a database engine caam can keep its records in. Queries are
+// written once, in SQLite's dialect; a backend for another engine translates
+// them on their way to the server, so callers of Conn() work unchanged.
+type Backend interface {
+	// Name is the value selecting the backend in config ("sqlite", "postgres").
+	Name() string
+	// Open connects and brings the schema up to date.
+	Open() (*sql.DB, error)
+}
+
+// NewBackend returns the backend for a database config section. An empty
+// name means the local SQLite file.
+func NewBackend(cfg config.DatabaseConfig) (Backend, error) {
+	switch cfg.Backend {
+	case "", "sqlite":
+		return SQLiteBackend{Path: DefaultPath()}, nil
+	case "postgres":
+		if strings.TrimSpace(cfg.DSN) == "" {
+			return nil, fmt.Errorf("database.dsn is required for the postgres backend")
+		}
+		return PostgresBackend{DSN: cfg.DSN}, nil
+	default:
+		return nil, fmt.Errorf("unknown database backend %q (supported: sqlite, postgres)", cfg.Backend)
+	}
+}
+
+// SQLiteBackend keeps the database in a local file.
+type SQLiteBackend struct {
+	Path string
+}
+
+// Name implements Backend.
+func (b SQLiteBackend) Name() string { return "sqlite" }
+
+// Open implements Backend. A corrupt file is moved aside (with its WAL
+// sidecars) and a fresh database created in its place.
+func (b SQLiteBackend) Open() (*sql.DB, error) {
+	clean := filepath.Clean(b.Path)
+	if err := os.MkdirAll(filepath.Dir(clean), 0700); err != nil {
+		return nil, fmt.Errorf("create db dir: %w", err)
+	}
+
+	conn, err := openAndInit(clean)
+	if err == nil {
+		return conn, nil
+	}
+
+	// Graceful handling: if the database is corrupt, preserve it and recreate.
+	if !isCorruptSQLiteError(err) {
+		return nil, err
+	}
+
+	if _, statErr := os.Stat(clean); statErr == nil {
+		backupPath := clean + ".corrupt." + time.Now().UTC().Format("20060102T150405Z")
+		if renameErr := os.Rename(clean, backupPath); renameErr != nil {
+			return nil, fmt.Errorf("db appears corrupt (%v), and rename failed: %w", err, renameErr)
+		}
+		if sidecarErr := renameSQLiteSidecars(clean, backupPath); sidecarErr != nil {
+			return nil, fmt.Errorf("db appears corrupt (%v), and sidecar rename failed: %w", err, sidecarErr)
+		}
+	}
+
+	return openAndInit(clean)
+}
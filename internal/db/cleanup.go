@@ -27,12 +27,15 @@ func DefaultCleanupConfig() CleanupConfig {
 type CleanupResult struct {
 	ActivityLogsDeleted int
 	StatsEntriesDeleted int
+	CooldownsDeleted    int
+	ExecHistoryDeleted  int
 	VacuumRan           bool
 }
 
 // Cleanup removes old records based on the retention configuration.
-// It deletes activity_log entries older than RetentionDays and
-// profile_stats entries for profiles with no recent activity.
+// It deletes activity_log entries and expired cooldowns older than
+// RetentionDays, and profile_stats entries for profiles with no recent
+// activity.
 // If RetentionDays or AggregateRetentionDays is <= 0, that cleanup is skipped
 // (treated as "keep forever").
 func (d *DB) Cleanup(cfg CleanupConfig) (*CleanupResult, error) {
@@ -55,6 +58,26 @@ func (d *DB) Cleanup(cfg CleanupConfig) (*CleanupResult, error) {
 		}
 		deleted, _ := activityResult.RowsAffected()
 		result.ActivityLogsDeleted = int(deleted)
+
+		cooldownResult, err := d.conn.Exec(`
+			DELETE FROM limit_events
+			WHERE datetime(cooldown_until) < datetime(?)
+		`, formatSQLiteTime(activityCutoff))
+		if err != nil {
+			return nil, fmt.Errorf("delete expired cooldowns: %w", err)
+		}
+		cooldowns, _ := cooldownResult.RowsAffected()
+		result.CooldownsDeleted = int(cooldowns)
+
+		execResult, err := d.conn.Exec(`
+			DELETE FROM exec_history
+			WHERE datetime(started_at) < datetime(?)
+		`, formatSQLiteTime(activityCutoff))
+		if err != nil {
+			return nil, fmt.Errorf("delete old exec history: %w", err)
+		}
+		execDeleted, _ := execResult.RowsAffected()
+		result.ExecHistoryDeleted = int(execDeleted)
 	}
 
 	// Delete stale profile_stats (skip if aggregate retention <= 0)
@@ -113,6 +136,26 @@ func (d *DB) CleanupDryRun(cfg CleanupConfig) (*CleanupResult, error) {
 			return nil, fmt.Errorf("count old activity logs: %w", err)
 		}
 		result.ActivityLogsDeleted = activityCount
+
+		var cooldownCount int
+		err = d.conn.QueryRow(`
+			SELECT COUNT(*) FROM limit_events
+			WHERE datetime(cooldown_until) < datetime(?)
+		`, formatSQLiteTime(activityCutoff)).Scan(&cooldownCount)
+		if err != nil {
+			return nil, fmt.Errorf("count expired cooldowns: %w", err)
+		}
+		result.CooldownsDeleted = cooldownCount
+
+		var execCount int
+		err = d.conn.QueryRow(`
+			SELECT COUNT(*) FROM exec_history
+			WHERE datetime(started_at) < datetime(?)
+		`, formatSQLiteTime(activityCutoff)).Scan(&execCount)
+		if err != nil {
+			return nil, fmt.Errorf("count old exec history: %w", err)
+		}
+		result.ExecHistoryDeleted = execCount
 	}
 
 	// Count profile_stats that would be deleted (skip if aggregate retention <= 0)
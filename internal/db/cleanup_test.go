@@ -332,3 +332,43 @@ func TestDB_CleanupZeroRetention(t *testing.T) {
 		t.Errorf("CleanupDryRun ActivityLogsDeleted = %d, want 0", dryResult.ActivityLogsDeleted)
 	}
 }
+
+func TestDB_CleanupExpiredCooldowns(t *testing.T) {
+	db, err := OpenAt(filepath.Join(t.TempDir(), "test_cleanup.db"))
+	if err != nil {
+		t.Fatalf("OpenAt: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	if _, err := db.SetCooldown("claude", "old", now.AddDate(0, 0, -100), time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown (old): %v", err)
+	}
+	if _, err := db.SetCooldown("claude", "recent", now.AddDate(0, 0, -1), time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown (recent): %v", err)
+	}
+
+	cfg := CleanupConfig{RetentionDays: 90, AggregateRetentionDays: 365}
+	dry, err := db.CleanupDryRun(cfg)
+	if err != nil {
+		t.Fatalf("CleanupDryRun: %v", err)
+	}
+	if dry.CooldownsDeleted != 1 {
+		t.Errorf("dry run CooldownsDeleted = %d, want 1", dry.CooldownsDeleted)
+	}
+
+	result, err := db.Cleanup(cfg)
+	if err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+	if result.CooldownsDeleted != 1 {
+		t.Errorf("CooldownsDeleted = %d, want 1", result.CooldownsDeleted)
+	}
+	remaining, err := db.ListRecentCooldowns(10)
+	if err != nil {
+		t.Fatalf("ListRecentCooldowns: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ProfileName != "recent" {
+		t.Errorf("remaining cooldowns = %+v, want only recent", remaining)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
 )
 
 // CooldownEvent records a provider/profile limit hit and its enforced cooldown.
@@ -15,10 +17,18 @@ type CooldownEvent struct {
 	HitAt         time.Time
 	CooldownUntil time.Time
 	Notes         string
+	// PropagatedTo lists sibling profiles of the same account that received
+	// the same cooldown (only populated by SetCooldown).
+	PropagatedTo []string
 }
 
 // SetCooldown records a limit hit and cooldown duration for a provider/profile.
 // It inserts a new limit_events row (keeping history).
+//
+// The cooldown is a property of the underlying account, so it is also
+// recorded for every sibling profile linked to the same account via
+// LinkAccountProfile. Otherwise rotation could dodge a rate limit by switching
+// to another profile name for the same login.
 func (d *DB) SetCooldown(provider, profile string, hitAt time.Time, duration time.Duration, notes string) (*CooldownEvent, error) {
 	if d == nil || d.conn == nil {
 		return nil, fmt.Errorf("db is not open")
@@ -54,19 +64,56 @@ func (d *DB) SetCooldown(provider, profile string, hitAt time.Time, duration tim
 		notesStr = sql.NullString{String: notes, Valid: true}
 	}
 
-	res, err := d.conn.Exec(
-		`INSERT INTO limit_events (provider, profile_name, hit_at, cooldown_until, notes) VALUES (?, ?, ?, ?, ?)`,
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// RETURNING rather than LastInsertId, which Postgres doesn't support.
+	const insertSQL = `INSERT INTO limit_events (provider, profile_name, hit_at, cooldown_until, notes) VALUES (?, ?, ?, ?, ?) RETURNING id`
+
+	var id int64
+	err = tx.QueryRow(insertSQL,
 		provider,
 		profile,
 		formatSQLiteTime(hitAt),
 		formatSQLiteTime(cooldownUntil),
 		notesStr,
-	)
+	).Scan(&id)
 	if err != nil {
 		return nil, fmt.Errorf("insert limit_events: %w", err)
 	}
 
-	id, _ := res.LastInsertId()
+	siblings, err := accountSiblings(tx, provider, profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := faultinject.Check(faultinject.OpDB); err != nil {
+		return nil, fmt.Errorf("insert limit_events for siblings: %w", err)
+	}
+	propagatedNotes := sql.NullString{String: fmt.Sprintf("propagated from %s/%s", provider, profile), Valid: true}
+	if notes != "" {
+		propagatedNotes.String += ": " + notes
+	}
+	for _, sibling := range siblings {
+		if _, err := tx.Exec(insertSQL,
+			provider,
+			sibling,
+			formatSQLiteTime(hitAt),
+			formatSQLiteTime(cooldownUntil),
+			propagatedNotes,
+		); err != nil {
+			return nil, fmt.Errorf("insert limit_events for %s/%s: %w", provider, sibling, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+
 	return &CooldownEvent{
 		ID:            id,
 		Provider:      provider,
@@ -74,6 +121,7 @@ func (d *DB) SetCooldown(provider, profile string, hitAt time.Time, duration tim
 		HitAt:         hitAt,
 		CooldownUntil: cooldownUntil,
 		Notes:         notes,
+		PropagatedTo:  siblings,
 	}, nil
 }
 
@@ -207,7 +255,126 @@ func (d *DB) ListActiveCooldowns(now time.Time) ([]CooldownEvent, error) {
 	return out, nil
 }
 
-// ClearCooldown deletes cooldown history for a specific provider/profile.
+// CooldownHistory returns every limit hit recorded for provider/profile since
+// the given time, oldest first.
+func (d *DB) CooldownHistory(provider, profile string, since time.Time) ([]CooldownEvent, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+
+	provider = strings.TrimSpace(provider)
+	profile = strings.TrimSpace(profile)
+	if provider == "" {
+		return nil, fmt.Errorf("provider is required")
+	}
+	if profile == "" {
+		return nil, fmt.Errorf("profile name is required")
+	}
+
+	rows, err := d.conn.Query(
+		`SELECT id, provider, profile_name, hit_at, cooldown_until, notes
+		   FROM limit_events
+		  WHERE provider = ? AND profile_name = ? AND datetime(hit_at) >= datetime(?)
+		  ORDER BY datetime(hit_at) ASC, id ASC`,
+		provider,
+		profile,
+		formatSQLiteTime(since.UTC()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query limit_events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CooldownEvent
+	for rows.Next() {
+		var (
+			ev               CooldownEvent
+			hitAtStr         string
+			cooldownUntilStr string
+			notes            sql.NullString
+		)
+		if err := rows.Scan(&ev.ID, &ev.Provider, &ev.ProfileName, &hitAtStr, &cooldownUntilStr, &notes); err != nil {
+			return nil, fmt.Errorf("scan limit_events: %w", err)
+		}
+		hitAt, err := parseSQLiteTime(hitAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse hit_at %q: %w", hitAtStr, err)
+		}
+		cooldownUntil, err := parseSQLiteTime(cooldownUntilStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse cooldown_until %q: %w", cooldownUntilStr, err)
+		}
+		ev.HitAt = hitAt
+		ev.CooldownUntil = cooldownUntil
+		if notes.Valid {
+			ev.Notes = notes.String
+		}
+		out = append(out, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate limit_events: %w", err)
+	}
+	return out, nil
+}
+
+// ListRecentCooldowns returns the most recent limit hits across all
+// profiles, newest first.
+func (d *DB) ListRecentCooldowns(limit int) ([]CooldownEvent, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	rows, err := d.conn.Query(
+		`SELECT id, provider, profile_name, hit_at, cooldown_until, notes
+		   FROM limit_events
+		  ORDER BY datetime(hit_at) DESC, id DESC
+		  LIMIT ?`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query limit_events: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CooldownEvent
+	for rows.Next() {
+		var (
+			ev               CooldownEvent
+			hitAtStr         string
+			cooldownUntilStr string
+			notes            sql.NullString
+		)
+		if err := rows.Scan(&ev.ID, &ev.Provider, &ev.ProfileName, &hitAtStr, &cooldownUntilStr, &notes); err != nil {
+			return nil, fmt.Errorf("scan limit_events: %w", err)
+		}
+		hitAt, err := parseSQLiteTime(hitAtStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse hit_at %q: %w", hitAtStr, err)
+		}
+		cooldownUntil, err := parseSQLiteTime(cooldownUntilStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse cooldown_until %q: %w", cooldownUntilStr, err)
+		}
+		ev.HitAt = hitAt
+		ev.CooldownUntil = cooldownUntil
+		if notes.Valid {
+			ev.Notes = notes.String
+		}
+		out = append(out, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate limit_events: %w", err)
+	}
+	return out, nil
+}
+
+// ClearCooldown deletes cooldown history for a specific provider/profile and
+// for any sibling profiles linked to the same account, since those share the
+// cooldown (see SetCooldown).
 func (d *DB) ClearCooldown(provider, profile string) (int64, error) {
 	if d == nil || d.conn == nil {
 		return 0, fmt.Errorf("db is not open")
@@ -222,11 +389,20 @@ func (d *DB) ClearCooldown(provider, profile string) (int64, error) {
 		return 0, fmt.Errorf("profile name is required")
 	}
 
-	res, err := d.conn.Exec(`DELETE FROM limit_events WHERE provider = ? AND profile_name = ?`, provider, profile)
+	siblings, err := accountSiblings(d.conn, provider, profile)
 	if err != nil {
-		return 0, fmt.Errorf("delete limit_events: %w", err)
+		return 0, err
+	}
+
+	var affected int64
+	for _, name := range append([]string{profile}, siblings...) {
+		res, err := d.conn.Exec(`DELETE FROM limit_events WHERE provider = ? AND profile_name = ?`, provider, name)
+		if err != nil {
+			return affected, fmt.Errorf("delete limit_events: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		affected += n
 	}
-	affected, _ := res.RowsAffected()
 	return affected, nil
 }
 
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"path/filepath"
 	"testing"
 	"time"
@@ -161,3 +162,228 @@ func TestCooldown_Clear(t *testing.T) {
 		t.Fatalf("ClearAllCooldowns() deleted = %d, want > 0", allDeleted)
 	}
 }
+
+func TestCooldown_PropagatesToAccountSiblings(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	for _, name := range []string{"work", "work-old"} {
+		if err := d.LinkAccountProfile("claude", name, "claude:alice"); err != nil {
+			t.Fatalf("LinkAccountProfile(%s) error = %v", name, err)
+		}
+	}
+	if err := d.LinkAccountProfile("claude", "personal", "claude:bob"); err != nil {
+		t.Fatalf("LinkAccountProfile(personal) error = %v", err)
+	}
+	// Same profile name under another provider must not be affected.
+	if err := d.LinkAccountProfile("codex", "work-old", "claude:alice"); err != nil {
+		t.Fatalf("LinkAccountProfile(codex) error = %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	created, err := d.SetCooldown("claude", "work", now, time.Hour, "rate limit")
+	if err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+	if len(created.PropagatedTo) != 1 || created.PropagatedTo[0] != "work-old" {
+		t.Fatalf("PropagatedTo = %v, want [work-old]", created.PropagatedTo)
+	}
+
+	sibling, err := d.ActiveCooldown("claude", "work-old", now)
+	if err != nil {
+		t.Fatalf("ActiveCooldown(work-old) error = %v", err)
+	}
+	if sibling == nil {
+		t.Fatal("sibling profile should share the cooldown")
+	}
+	if !sibling.CooldownUntil.Equal(created.CooldownUntil) {
+		t.Fatalf("sibling cooldownUntil = %s, want %s", sibling.CooldownUntil, created.CooldownUntil)
+	}
+	if sibling.Notes != "propagated from claude/work: rate limit" {
+		t.Fatalf("sibling notes = %q", sibling.Notes)
+	}
+
+	for _, target := range [][2]string{{"claude", "personal"}, {"codex", "work-old"}} {
+		ev, err := d.ActiveCooldown(target[0], target[1], now)
+		if err != nil {
+			t.Fatalf("ActiveCooldown(%s/%s) error = %v", target[0], target[1], err)
+		}
+		if ev != nil {
+			t.Fatalf("%s/%s should not receive the cooldown", target[0], target[1])
+		}
+	}
+
+	deleted, err := d.ClearCooldown("claude", "work-old")
+	if err != nil {
+		t.Fatalf("ClearCooldown() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("ClearCooldown() deleted = %d, want 2", deleted)
+	}
+	if ev, _ := d.ActiveCooldown("claude", "work", now); ev != nil {
+		t.Fatal("clearing a sibling should clear the whole account")
+	}
+
+	if err := d.LinkAccountProfile("claude", "work-old", ""); err != nil {
+		t.Fatalf("LinkAccountProfile(unlink) error = %v", err)
+	}
+	siblings, err := d.AccountSiblings("claude", "work")
+	if err != nil {
+		t.Fatalf("AccountSiblings() error = %v", err)
+	}
+	if len(siblings) != 0 {
+		t.Fatalf("AccountSiblings() after unlink = %v, want none", siblings)
+	}
+}
+
+func TestCooldown_History(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, ago := range []time.Duration{48 * time.Hour, 3 * time.Hour, time.Hour} {
+		if _, err := d.SetCooldown("claude", "work", now.Add(-ago), 30*time.Minute, ""); err != nil {
+			t.Fatalf("SetCooldown() error = %v", err)
+		}
+	}
+	if _, err := d.SetCooldown("claude", "other", now, 30*time.Minute, ""); err != nil {
+		t.Fatalf("SetCooldown(other) error = %v", err)
+	}
+
+	events, err := d.CooldownHistory("claude", "work", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("CooldownHistory() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("CooldownHistory() len = %d, want 2", len(events))
+	}
+	if !events[0].HitAt.Equal(now.Add(-3 * time.Hour)) {
+		t.Fatalf("events[0].HitAt = %s, want oldest first", events[0].HitAt)
+	}
+}
+
+func TestCooldown_ListRecent(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if _, err := d.SetCooldown("claude", "work", now.Add(-2*time.Hour), time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+	if _, err := d.SetCooldown("codex", "main", now.Add(-time.Hour), time.Hour, "batch"); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+	if _, err := d.SetCooldown("claude", "other", now.Add(-3*time.Hour), time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+
+	events, err := d.ListRecentCooldowns(2)
+	if err != nil {
+		t.Fatalf("ListRecentCooldowns() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("ListRecentCooldowns() len = %d, want 2", len(events))
+	}
+	if events[0].Provider != "codex" || events[0].Notes != "batch" {
+		t.Fatalf("events[0] = %+v, want newest codex/main", events[0])
+	}
+	if events[1].ProfileName != "work" {
+		t.Fatalf("events[1].ProfileName = %q, want work", events[1].ProfileName)
+	}
+}
+
+func TestCooldown_AcrossDSTTransitions(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	tests := []struct {
+		name    string
+		hitAt   time.Time
+		until   string // wall clock in New York
+		checkAt time.Time
+		left    time.Duration
+	}{
+		{
+			// Clocks jump from 2:00 EST to 3:00 EDT.
+			name:    "spring forward",
+			hitAt:   time.Date(2026, 3, 8, 1, 30, 0, 0, ny),
+			until:   "03:30 EDT",
+			checkAt: time.Date(2026, 3, 8, 1, 45, 0, 0, ny),
+			left:    45 * time.Minute,
+		},
+		{
+			// Clocks fall back from 2:00 EDT to 1:00 EST; 1:45 happens twice.
+			name:    "fall back",
+			hitAt:   time.Date(2026, 11, 1, 1, 30, 0, 0, ny), // first 1:30, EDT
+			until:   "01:30 EST",
+			checkAt: time.Date(2026, 11, 1, 1, 45, 0, 0, ny), // first 1:45, EDT
+			left:    45 * time.Minute,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := fmt.Sprintf("p%d", i)
+			if _, err := d.SetCooldown("claude", profile, tt.hitAt, time.Hour, ""); err != nil {
+				t.Fatalf("SetCooldown() error = %v", err)
+			}
+
+			active, err := d.ActiveCooldown("claude", profile, tt.checkAt)
+			if err != nil || active == nil {
+				t.Fatalf("ActiveCooldown() = %v, %v; want active", active, err)
+			}
+			if got := active.CooldownUntil.Sub(active.HitAt); got != time.Hour {
+				t.Errorf("stored cooldown = %v, want 1h of elapsed time", got)
+			}
+			if got := active.CooldownUntil.Sub(tt.checkAt); got != tt.left {
+				t.Errorf("remaining = %v, want %v", got, tt.left)
+			}
+			if got := active.CooldownUntil.In(ny).Format("15:04 MST"); got != tt.until {
+				t.Errorf("until = %s, want %s", got, tt.until)
+			}
+
+			// Just past the end, an hour of elapsed time later, it's over.
+			after := tt.hitAt.Add(time.Hour + time.Minute)
+			if active, err := d.ActiveCooldown("claude", profile, after); err != nil || active != nil {
+				t.Errorf("ActiveCooldown(%s) = %v, %v; want none", after.In(ny).Format("15:04 MST"), active, err)
+			}
+		})
+	}
+}
+
+func TestDB_Now(t *testing.T) {
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	got, err := d.Now()
+	if err != nil {
+		t.Fatalf("Now() error = %v", err)
+	}
+	if got.Location() != time.UTC {
+		t.Errorf("Now() location = %v, want UTC", got.Location())
+	}
+	if skew := time.Since(got); skew < -2*time.Second || skew > 2*time.Second {
+		t.Errorf("Now() = %v, %v from the system clock", got, skew)
+	}
+}
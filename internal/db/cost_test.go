@@ -22,6 +22,7 @@ func TestRecordWrapSession(t *testing.T) {
 		ExitCode:     0,
 		RateLimitHit: false,
 		Notes:        "test session",
+		WorkDir:      "/home/alice/src/app",
 	}
 
 	if err := db.RecordWrapSession(session); err != nil {
@@ -47,6 +48,9 @@ func TestRecordWrapSession(t *testing.T) {
 	if sessions[0].DurationSeconds < 299 || sessions[0].DurationSeconds > 301 {
 		t.Errorf("DurationSeconds = %d, want ~300", sessions[0].DurationSeconds)
 	}
+	if sessions[0].WorkDir != "/home/alice/src/app" {
+		t.Errorf("WorkDir = %q, want /home/alice/src/app", sessions[0].WorkDir)
+	}
 }
 
 func TestRecordWrapSession_RateLimitHit(t *testing.T) {
@@ -7,55 +7,107 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 )
 
+// DB is caam's SQLite database. The watchdog, TUI, prompt hook and robot
+// commands may all have it open at once, so access follows a single-writer
+// convention: each process uses one connection, the database runs in WAL mode
+// so readers never block the writer, transactions take the write lock up front
+// (BEGIN IMMEDIATE), and a busy timeout makes a writer wait for another
+// process's write instead of failing with "database is locked".
+//
+// The database may instead live on a shared Postgres server (see Backend),
+// in which case path is empty.
 type DB struct {
-	path string
-	conn *sql.DB
+	path    string
+	conn    *sql.DB
+	backend string
 }
 
-func Open() (*DB, error) {
-	return OpenAt(DefaultPath())
+// ErrUnavailable is wrapped by the errors Open returns when the database
+// can't be opened at all, e.g. on a read-only filesystem or with a database
+// server that is down. caam keeps working without it: backup, activate and
+// ls don't need it, while cooldowns, usage history and analytics are off.
+var ErrUnavailable = errors.New("database unavailable")
+
+var (
+	unavailableMu      sync.Mutex
+	unavailableHandler func(error)
+)
+
+// SetUnavailableHandler sets a function called with the error each time
+// Open fails; caam uses it to warn once per run that database-backed
+// features are off.
+func SetUnavailableHandler(fn func(error)) {
+	unavailableMu.Lock()
+	defer unavailableMu.Unlock()
+	unavailableHandler = fn
 }
 
-func OpenAt(path string) (*DB, error) {
-	if strings.TrimSpace(path) == "" {
-		return nil, fmt.Errorf("path is required")
+// unavailable wraps err in ErrUnavailable and reports it to the handler.
+func unavailable(where string, err error) error {
+	err = fmt.Errorf("%w (%s): %w", ErrUnavailable, where, err)
+	unavailableMu.Lock()
+	fn := unavailableHandler
+	unavailableMu.Unlock()
+	if fn != nil {
+		fn(err)
 	}
+	return err
+}
 
-	clean := filepath.Clean(path)
-	if err := os.MkdirAll(filepath.Dir(clean), 0700); err != nil {
-		return nil, fmt.Errorf("create db dir: %w", err)
-	}
+// busyTimeout is how long a statement waits for another process's write lock
+// before failing.
+const busyTimeout = 10 * time.Second
 
-	conn, err := openAndInit(clean)
-	if err == nil {
-		return &DB{path: clean, conn: conn}, nil
+// Open opens the database selected by the database section of the config:
+// the local SQLite file unless a shared Postgres server is configured. An
+// unreadable config falls back to the local file, so a typo elsewhere in it
+// never stops caam from recording activity.
+func Open() (*DB, error) {
+	var dbCfg config.DatabaseConfig
+	if spmCfg, err := config.LoadSPMConfig(); err == nil {
+		dbCfg = spmCfg.Database
 	}
-
-	// Graceful handling: if the database is corrupt, preserve it and recreate.
-	if !isCorruptSQLiteError(err) {
-		return nil, err
+	backend, err := NewBackend(dbCfg)
+	if err != nil {
+		return nil, unavailable("database config", err)
 	}
+	return OpenBackend(backend)
+}
 
-	if _, statErr := os.Stat(clean); statErr == nil {
-		backupPath := clean + ".corrupt." + time.Now().UTC().Format("20060102T150405Z")
-		if renameErr := os.Rename(clean, backupPath); renameErr != nil {
-			return nil, fmt.Errorf("db appears corrupt (%v), and rename failed: %w", err, renameErr)
-		}
-		if sidecarErr := renameSQLiteSidecars(clean, backupPath); sidecarErr != nil {
-			return nil, fmt.Errorf("db appears corrupt (%v), and sidecar rename failed: %w", err, sidecarErr)
-		}
+// OpenAt opens (creating if needed) the SQLite database at path.
+func OpenAt(path string) (*DB, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("path is required")
 	}
+	return OpenBackend(SQLiteBackend{Path: filepath.Clean(path)})
+}
 
-	conn, err = openAndInit(clean)
+// OpenBackend opens the database kept by backend.
+func OpenBackend(backend Backend) (*DB, error) {
+	if backend == nil {
+		return nil, fmt.Errorf("backend is required")
+	}
+	conn, err := backend.Open()
 	if err != nil {
-		return nil, err
+		where := backend.Name()
+		if b, ok := backend.(SQLiteBackend); ok {
+			where = filepath.Clean(b.Path)
+		}
+		return nil, unavailable(where, err)
 	}
-	return &DB{path: clean, conn: conn}, nil
+	d := &DB{conn: conn, backend: backend.Name()}
+	if b, ok := backend.(SQLiteBackend); ok {
+		d.path = filepath.Clean(b.Path)
+	}
+	return d, nil
 }
 
 func (d *DB) Close() error {
@@ -89,9 +141,6 @@ func Checkpoint(path string) error {
 	conn.SetMaxOpenConns(1)
 	conn.SetMaxIdleConns(1)
 
-	if _, err := conn.Exec(`PRAGMA busy_timeout=5000;`); err != nil {
-		return fmt.Errorf("set busy_timeout: %w", err)
-	}
 	if _, err := conn.Exec(`PRAGMA wal_checkpoint(TRUNCATE);`); err != nil {
 		return fmt.Errorf("wal checkpoint: %w", err)
 	}
@@ -105,6 +154,7 @@ func (d *DB) Conn() *sql.DB {
 	return d.conn
 }
 
+// Path returns the SQLite file, or "" for a database on a server.
 func (d *DB) Path() string {
 	if d == nil {
 		return ""
@@ -112,6 +162,14 @@ func (d *DB) Path() string {
 	return d.path
 }
 
+// Backend returns the name of the backend the database runs on.
+func (d *DB) Backend() string {
+	if d == nil {
+		return ""
+	}
+	return d.backend
+}
+
 func DefaultPath() string {
 	if caamHome := os.Getenv("CAAM_HOME"); caamHome != "" {
 		return filepath.Join(caamHome, "data", "caam.db")
@@ -157,8 +215,13 @@ func openAndInit(path string) (*sql.DB, error) {
 }
 
 func dsn(path string) string {
-	// Use an explicit file: DSN so we can pass mode=rwc for auto-create.
-	return "file:" + filepath.ToSlash(path) + "?mode=rwc"
+	// Use an explicit file: DSN so we can pass mode=rwc for auto-create. The
+	// busy timeout is set through the DSN so it applies to every connection
+	// the pool opens, and _txlock=immediate makes Begin take the write lock
+	// at once: a deferred transaction that reads and then writes can't wait
+	// for the lock and fails immediately when another process holds it.
+	return fmt.Sprintf("file:%s?mode=rwc&_pragma=busy_timeout(%d)&_txlock=immediate",
+		filepath.ToSlash(path), busyTimeout.Milliseconds())
 }
 
 func enableWAL(conn *sql.DB) error {
@@ -213,3 +276,17 @@ func renameSQLiteSidecars(path, backupPath string) error {
 	}
 	return nil
 }
+
+// Now returns the database's clock (CURRENT_TIMESTAMP, in UTC), which stamps
+// rows whose time column defaults to it. On a shared server it can disagree
+// with the local clock.
+func (d *DB) Now() (time.Time, error) {
+	if d == nil || d.conn == nil {
+		return time.Time{}, fmt.Errorf("db is not open")
+	}
+	var s string
+	if err := d.conn.QueryRow(`SELECT CURRENT_TIMESTAMP`).Scan(&s); err != nil {
+		return time.Time{}, fmt.Errorf("query clock: %w", err)
+	}
+	return parseSQLiteTime(s)
+}
@@ -1,10 +1,14 @@
 package db
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestOpenAt_CreatesDBAndRunsMigrations(t *testing.T) {
@@ -22,7 +26,7 @@ func TestOpenAt_CreatesDBAndRunsMigrations(t *testing.T) {
 	}
 
 	// Migration-created tables should exist.
-	for _, table := range []string{"schema_version", "activity_log", "profile_stats", "limit_events"} {
+	for _, table := range []string{"schema_version", "activity_log", "profile_stats", "limit_events", "account_profiles", "robot_actions", "vault_sizes"} {
 		var name string
 		if err := d.Conn().QueryRow(`SELECT name FROM sqlite_master WHERE type='table' AND name=?`, table).Scan(&name); err != nil {
 			t.Fatalf("table %s missing: %v", table, err)
@@ -38,8 +42,33 @@ func TestOpenAt_CreatesDBAndRunsMigrations(t *testing.T) {
 	if err := d.Conn().QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version); err != nil {
 		t.Fatalf("read schema_version error = %v", err)
 	}
-	if version != 3 {
-		t.Fatalf("schema_version max = %d, want 3", version)
+	if version != 8 {
+		t.Fatalf("schema_version max = %d, want 8", version)
+	}
+}
+
+func TestOpenAt_UnavailableIsReported(t *testing.T) {
+	tmpDir := t.TempDir()
+	// A regular file where the data directory should be stands in for a
+	// read-only filesystem, which root-run tests can't simulate with modes.
+	blocker := filepath.Join(tmpDir, "data")
+	if err := os.WriteFile(blocker, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported []error
+	SetUnavailableHandler(func(err error) { reported = append(reported, err) })
+	t.Cleanup(func() { SetUnavailableHandler(nil) })
+
+	_, err := OpenAt(filepath.Join(blocker, "caam.db"))
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("OpenAt() error = %v, want ErrUnavailable", err)
+	}
+	if !strings.Contains(err.Error(), blocker) {
+		t.Errorf("error %q should name the database path", err)
+	}
+	if len(reported) != 1 || reported[0] != err {
+		t.Errorf("handler got %v, want the returned error once", reported)
 	}
 }
 
@@ -62,6 +91,73 @@ func TestOpenAt_EnablesWALMode(t *testing.T) {
 	}
 }
 
+func TestOpenAt_SetsBusyTimeout(t *testing.T) {
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	var timeout int64
+	if err := d.Conn().QueryRow(`PRAGMA busy_timeout;`).Scan(&timeout); err != nil {
+		t.Fatalf("PRAGMA busy_timeout error = %v", err)
+	}
+	if timeout != busyTimeout.Milliseconds() {
+		t.Fatalf("busy_timeout = %d, want %d", timeout, busyTimeout.Milliseconds())
+	}
+}
+
+// TestOpenAt_ConcurrentWriters simulates several processes (each with its own
+// connection) opening the database and writing at the same time.
+func TestOpenAt_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "caam.db")
+
+	const writers, writes = 6, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			d, err := OpenAt(path)
+			if err != nil {
+				errs <- fmt.Errorf("writer %d open: %w", w, err)
+				return
+			}
+			defer d.Close()
+			profile := fmt.Sprintf("p%d", w)
+			for i := 0; i < writes; i++ {
+				if err := d.LogEvent(Event{Type: EventActivate, Provider: "claude", ProfileName: profile}); err != nil {
+					errs <- fmt.Errorf("writer %d log: %w", w, err)
+					return
+				}
+				if _, err := d.SetCooldown("claude", profile, time.Now(), time.Minute, ""); err != nil {
+					errs <- fmt.Errorf("writer %d cooldown: %w", w, err)
+					return
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	d, err := OpenAt(path)
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	defer d.Close()
+	var count int
+	if err := d.Conn().QueryRow(`SELECT COUNT(*) FROM activity_log`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != writers*writes {
+		t.Fatalf("activity_log rows = %d, want %d", count, writers*writes)
+	}
+}
+
 func TestOpenAt_CorruptDB_RenamedAndRecreated(t *testing.T) {
 	tmpDir := t.TempDir()
 	path := filepath.Join(tmpDir, "caam.db")
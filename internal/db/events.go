@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/faultinject"
 )
 
 const (
@@ -15,6 +17,8 @@ const (
 	EventError       = "error"
 	EventSwitch      = "switch"
 	EventDeactivate  = "deactivate"
+	EventExec        = "exec"     // a 'caam exec' session started
+	EventVerified    = "verified" // the provider accepted the profile's login
 	sqliteTimeLayout = "2006-01-02 15:04:05"
 )
 
@@ -105,6 +109,9 @@ func (d *DB) LogEvent(event Event) error {
 	); err != nil {
 		return fmt.Errorf("insert activity_log: %w", err)
 	}
+	if err := faultinject.Check(faultinject.OpDB); err != nil {
+		return fmt.Errorf("update profile_stats: %w", err)
+	}
 
 	if err := updateProfileStats(tx, eventType, provider, profile, tsStr, durationSeconds); err != nil {
 		return err
@@ -187,6 +194,78 @@ func (d *DB) GetEvents(provider, profile string, since time.Time, limit int) ([]
 	return out, nil
 }
 
+// EventTimes returns when events of one type were logged for a profile
+// since the given time, oldest first and without GetEvents' row limit.
+func (d *DB) EventTimes(eventType, provider, profile string, since time.Time) ([]time.Time, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+
+	rows, err := d.conn.Query(
+		`SELECT timestamp
+		 FROM activity_log
+		 WHERE event_type = ? AND provider = ? AND profile_name = ? AND datetime(timestamp) >= datetime(?)
+		 ORDER BY timestamp ASC`,
+		strings.TrimSpace(eventType),
+		strings.TrimSpace(provider),
+		strings.TrimSpace(profile),
+		formatSQLiteTime(since),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query activity_log: %w", err)
+	}
+	defer rows.Close()
+
+	var out []time.Time
+	for rows.Next() {
+		var tsStr string
+		if err := rows.Scan(&tsStr); err != nil {
+			return nil, fmt.Errorf("scan activity_log: %w", err)
+		}
+		ts, err := parseSQLiteTime(tsStr)
+		if err != nil {
+			return nil, fmt.Errorf("parse timestamp %q: %w", tsStr, err)
+		}
+		out = append(out, ts)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate activity_log: %w", err)
+	}
+	return out, nil
+}
+
+// LastVerified returns when the provider last accepted a profile's login:
+// the latest verified or refresh event. Returns zero time if there is none.
+func (d *DB) LastVerified(provider, profile string) (time.Time, error) {
+	if d == nil || d.conn == nil {
+		return time.Time{}, fmt.Errorf("db is not open")
+	}
+
+	var tsStr string
+	err := d.conn.QueryRow(
+		`SELECT timestamp
+		 FROM activity_log
+		 WHERE provider = ? AND profile_name = ? AND event_type IN (?, ?)
+		 ORDER BY datetime(timestamp) DESC
+		 LIMIT 1`,
+		strings.TrimSpace(provider),
+		strings.TrimSpace(profile),
+		EventVerified,
+		EventRefresh,
+	).Scan(&tsStr)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query last verified: %w", err)
+	}
+	ts, err := parseSQLiteTime(tsStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse timestamp %q: %w", tsStr, err)
+	}
+	return ts, nil
+}
+
 // ListRecentEvents returns recent events across all profiles.
 // Unlike GetEvents, provider and profile are optional filters.
 // If empty, all events are returned.
@@ -359,8 +438,10 @@ func updateProfileStats(tx *sql.Tx, eventType, provider, profile, ts string, dur
 			`INSERT INTO profile_stats (provider, profile_name, total_activations, last_activated)
 			 VALUES (?, ?, 1, ?)
 			 ON CONFLICT(provider, profile_name) DO UPDATE SET
-			   total_activations = total_activations + 1,
-			   last_activated = MAX(COALESCE(last_activated, excluded.last_activated), excluded.last_activated)`,
+			   total_activations = profile_stats.total_activations + 1,
+			   last_activated = CASE
+			     WHEN profile_stats.last_activated IS NULL OR excluded.last_activated > profile_stats.last_activated
+			     THEN excluded.last_activated ELSE profile_stats.last_activated END`,
 			provider,
 			profile,
 			ts,
@@ -373,8 +454,10 @@ func updateProfileStats(tx *sql.Tx, eventType, provider, profile, ts string, dur
 			`INSERT INTO profile_stats (provider, profile_name, total_errors, last_error)
 			 VALUES (?, ?, 1, ?)
 			 ON CONFLICT(provider, profile_name) DO UPDATE SET
-			   total_errors = total_errors + 1,
-			   last_error = MAX(COALESCE(last_error, excluded.last_error), excluded.last_error)`,
+			   total_errors = profile_stats.total_errors + 1,
+			   last_error = CASE
+			     WHEN profile_stats.last_error IS NULL OR excluded.last_error > profile_stats.last_error
+			     THEN excluded.last_error ELSE profile_stats.last_error END`,
 			provider,
 			profile,
 			ts,
@@ -390,7 +473,7 @@ func updateProfileStats(tx *sql.Tx, eventType, provider, profile, ts string, dur
 			`INSERT INTO profile_stats (provider, profile_name, total_active_seconds)
 			 VALUES (?, ?, ?)
 			 ON CONFLICT(provider, profile_name) DO UPDATE SET
-			   total_active_seconds = total_active_seconds + excluded.total_active_seconds`,
+			   total_active_seconds = profile_stats.total_active_seconds + excluded.total_active_seconds`,
 			provider,
 			profile,
 			durationSeconds,
@@ -427,3 +510,69 @@ func parseSQLiteTime(s string) (time.Time, error) {
 	}
 	return time.Time{}, fmt.Errorf("unsupported time format")
 }
+
+// LastUsed returns when a provider/profile was last used: the later of its
+// most recent activation and the end of its most recent wrapped run (caam run,
+// caam exec). Returns zero time if it was never used.
+func (d *DB) LastUsed(provider, profile string) (time.Time, error) {
+	lastUsed, err := d.LastActivation(provider, profile)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var lastRun sql.NullString
+	err = d.conn.QueryRow(
+		`SELECT MAX(COALESCE(ended_at, started_at))
+		 FROM wrap_sessions
+		 WHERE provider = ? AND profile_name = ?`,
+		strings.TrimSpace(provider),
+		strings.TrimSpace(profile),
+	).Scan(&lastRun)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query last run: %w", err)
+	}
+	if lastRun.Valid && lastRun.String != "" {
+		ts, err := parseSQLiteTime(lastRun.String)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse timestamp %q: %w", lastRun.String, err)
+		}
+		if ts.After(lastUsed) {
+			lastUsed = ts
+		}
+	}
+	return lastUsed, nil
+}
+
+// ProfileActiveAt returns the profile most recently activated for provider at
+// or before at, according to activate and switch events in the activity log.
+// Returns "" if no activation precedes at.
+func (d *DB) ProfileActiveAt(provider string, at time.Time) (string, error) {
+	if d == nil || d.conn == nil {
+		return "", fmt.Errorf("db is not open")
+	}
+
+	provider = strings.TrimSpace(provider)
+	if provider == "" {
+		return "", fmt.Errorf("provider is required")
+	}
+
+	var profile string
+	err := d.conn.QueryRow(
+		`SELECT profile_name
+		 FROM activity_log
+		 WHERE provider = ? AND event_type IN (?, ?) AND datetime(timestamp) <= datetime(?)
+		 ORDER BY datetime(timestamp) DESC, id DESC
+		 LIMIT 1`,
+		provider,
+		EventActivate,
+		EventSwitch,
+		formatSQLiteTime(at),
+	).Scan(&profile)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("query active profile: %w", err)
+	}
+	return profile, nil
+}
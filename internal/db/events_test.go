@@ -221,3 +221,150 @@ func TestDB_ProfileStats_LastErrorIsMonotonic(t *testing.T) {
 		t.Fatalf("LastError = %s, want %s", stats.LastError.Format(time.RFC3339Nano), newer.Format(time.RFC3339Nano))
 	}
 }
+
+func TestDB_LastUsed(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(tmpDir + "/caam.db")
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	got, err := d.LastUsed("claude", "work")
+	if err != nil {
+		t.Fatalf("LastUsed(empty) error = %v", err)
+	}
+	if !got.IsZero() {
+		t.Fatalf("LastUsed(empty) = %s, want zero", got)
+	}
+
+	activated := time.Now().UTC().Truncate(time.Second).Add(-2 * time.Hour)
+	if err := d.LogEvent(Event{Type: EventActivate, Provider: "claude", ProfileName: "work", Timestamp: activated}); err != nil {
+		t.Fatalf("LogEvent() error = %v", err)
+	}
+	if got, _ = d.LastUsed("claude", "work"); !got.Equal(activated) {
+		t.Fatalf("LastUsed(activation) = %s, want %s", got, activated)
+	}
+
+	ended := activated.Add(time.Hour)
+	if err := d.RecordWrapSession(WrapSession{
+		Provider:    "claude",
+		ProfileName: "work",
+		StartedAt:   activated.Add(30 * time.Minute),
+		EndedAt:     ended,
+	}); err != nil {
+		t.Fatalf("RecordWrapSession() error = %v", err)
+	}
+	if got, _ = d.LastUsed("claude", "work"); !got.Equal(ended) {
+		t.Fatalf("LastUsed(run) = %s, want %s", got, ended)
+	}
+}
+
+func TestDB_ProfileActiveAt(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(tmpDir + "/caam.db")
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	base := time.Now().UTC().Truncate(time.Second).Add(-3 * time.Hour)
+	for _, e := range []Event{
+		{Type: EventActivate, Provider: "claude", ProfileName: "work", Timestamp: base},
+		{Type: EventError, Provider: "claude", ProfileName: "other", Timestamp: base.Add(30 * time.Minute)},
+		{Type: EventSwitch, Provider: "claude", ProfileName: "personal", Timestamp: base.Add(time.Hour)},
+		{Type: EventActivate, Provider: "codex", ProfileName: "main", Timestamp: base.Add(2 * time.Hour)},
+	} {
+		if err := d.LogEvent(e); err != nil {
+			t.Fatalf("LogEvent(%s) error = %v", e.Type, err)
+		}
+	}
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{base.Add(-time.Minute), ""},
+		{base, "work"},
+		{base.Add(45 * time.Minute), "work"},
+		{base.Add(90 * time.Minute), "personal"},
+		{base.Add(3 * time.Hour), "personal"},
+	}
+	for _, tt := range tests {
+		got, err := d.ProfileActiveAt("claude", tt.at)
+		if err != nil {
+			t.Fatalf("ProfileActiveAt(%s) error = %v", tt.at, err)
+		}
+		if got != tt.want {
+			t.Errorf("ProfileActiveAt(%s) = %q, want %q", tt.at.Sub(base), got, tt.want)
+		}
+	}
+}
+
+func TestDB_EventTimes(t *testing.T) {
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, e := range []Event{
+		{Type: EventExec, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-2 * time.Hour)},
+		{Type: EventExec, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-10 * time.Minute)},
+		{Type: EventExec, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-30 * time.Minute)},
+		{Type: EventActivate, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-5 * time.Minute)},
+		{Type: EventExec, Provider: "claude", ProfileName: "home", Timestamp: now.Add(-time.Minute)},
+	} {
+		if err := d.LogEvent(e); err != nil {
+			t.Fatalf("LogEvent() error = %v", err)
+		}
+	}
+
+	got, err := d.EventTimes(EventExec, "claude", "work", now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("EventTimes() error = %v", err)
+	}
+	want := []time.Time{now.Add(-30 * time.Minute), now.Add(-10 * time.Minute)}
+	if len(got) != len(want) {
+		t.Fatalf("EventTimes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("EventTimes()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDB_LastVerified(t *testing.T) {
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	got, err := d.LastVerified("claude", "work")
+	if err != nil || !got.IsZero() {
+		t.Fatalf("LastVerified() on empty db = %v, %v; want zero time", got, err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, e := range []Event{
+		{Type: EventVerified, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-48 * time.Hour)},
+		{Type: EventRefresh, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-3 * time.Hour)},
+		{Type: EventActivate, Provider: "claude", ProfileName: "work", Timestamp: now.Add(-time.Hour)},
+		{Type: EventVerified, Provider: "claude", ProfileName: "home", Timestamp: now.Add(-time.Minute)},
+	} {
+		if err := d.LogEvent(e); err != nil {
+			t.Fatalf("LogEvent() error = %v", err)
+		}
+	}
+
+	got, err = d.LastVerified("claude", "work")
+	if err != nil {
+		t.Fatalf("LastVerified() error = %v", err)
+	}
+	if want := now.Add(-3 * time.Hour); !got.Equal(want) {
+		t.Errorf("LastVerified() = %v, want the refresh at %v", got, want)
+	}
+}
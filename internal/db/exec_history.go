@@ -0,0 +1,102 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExecRecord is one 'caam exec' run recorded in exec_history.
+type ExecRecord struct {
+	StartedAt   time.Time
+	Provider    string
+	ProfileName string
+	Mode        string // capture mode: hash or truncate
+	Args        string // redacted, truncated arguments; empty in hash mode
+	ArgsHash    string
+	WorkDir     string
+	Duration    time.Duration
+	ExitCode    int
+}
+
+// RecordExec stores one exec run.
+func (d *DB) RecordExec(r ExecRecord) error {
+	if d == nil || d.conn == nil {
+		return fmt.Errorf("db is not open")
+	}
+	provider := strings.TrimSpace(r.Provider)
+	profile := strings.TrimSpace(r.ProfileName)
+	if provider == "" || profile == "" {
+		return fmt.Errorf("provider and profile are required")
+	}
+
+	var workDir any
+	if r.WorkDir != "" {
+		workDir = r.WorkDir
+	}
+	if _, err := d.conn.Exec(
+		`INSERT INTO exec_history (started_at, provider, profile_name, mode, args, args_hash, work_dir, duration_seconds, exit_code)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		formatSQLiteTime(r.StartedAt), provider, profile, r.Mode, r.Args, r.ArgsHash, workDir,
+		int64(r.Duration.Seconds()), r.ExitCode,
+	); err != nil {
+		return fmt.Errorf("insert exec_history: %w", err)
+	}
+	return nil
+}
+
+// ExecHistory returns exec runs started since the given time, newest first.
+// Empty provider or profile match any; limit <= 0 means no limit.
+func (d *DB) ExecHistory(provider, profile string, since time.Time, limit int) ([]ExecRecord, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+
+	query := `SELECT started_at, provider, profile_name, mode, args, args_hash, work_dir, duration_seconds, exit_code
+	            FROM exec_history
+	           WHERE datetime(started_at) >= datetime(?)`
+	args := []any{formatSQLiteTime(since)}
+	if provider != "" {
+		query += ` AND provider = ?`
+		args = append(args, provider)
+	}
+	if profile != "" {
+		query += ` AND profile_name = ?`
+		args = append(args, profile)
+	}
+	query += ` ORDER BY datetime(started_at) DESC, id DESC`
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := d.conn.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query exec_history: %w", err)
+	}
+	defer rows.Close()
+
+	var out []ExecRecord
+	for rows.Next() {
+		var startedAt string
+		var workDir sql.NullString
+		var seconds int64
+		var r ExecRecord
+		if err := rows.Scan(&startedAt, &r.Provider, &r.ProfileName, &r.Mode, &r.Args, &r.ArgsHash, &workDir, &seconds, &r.ExitCode); err != nil {
+			return nil, fmt.Errorf("scan exec_history: %w", err)
+		}
+		ts, err := parseSQLiteTime(startedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse started_at: %w", err)
+		}
+		r.StartedAt = ts
+		r.WorkDir = workDir.String
+		r.Duration = time.Duration(seconds) * time.Second
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate exec_history: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExecHistory_RecordAndFilter(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+	records := []ExecRecord{
+		{StartedAt: now.Add(-48 * time.Hour), Provider: "claude", ProfileName: "work", Mode: "truncate", Args: `-p "old"`, ArgsHash: "a1"},
+		{StartedAt: now.Add(-time.Hour), Provider: "claude", ProfileName: "work", Mode: "truncate", Args: `-p "fix bug"`, ArgsHash: "b2", WorkDir: "/src/app", Duration: 90 * time.Second, ExitCode: 1},
+		{StartedAt: now, Provider: "claude", ProfileName: "home", Mode: "hash", ArgsHash: "c3"},
+		{StartedAt: now, Provider: "codex", ProfileName: "work", Mode: "hash", ArgsHash: "d4"},
+	}
+	for _, r := range records {
+		if err := d.RecordExec(r); err != nil {
+			t.Fatalf("RecordExec(%+v) error = %v", r, err)
+		}
+	}
+	if err := d.RecordExec(ExecRecord{Provider: "claude"}); err == nil {
+		t.Error("RecordExec() should reject a record without a profile")
+	}
+
+	got, err := d.ExecHistory("claude", "work", now.Add(-24*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("ExecHistory() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("ExecHistory(claude, work, 24h) len = %d, want 1: %+v", len(got), got)
+	}
+	r := got[0]
+	if r.Args != `-p "fix bug"` || r.ArgsHash != "b2" || r.WorkDir != "/src/app" || r.Duration != 90*time.Second || r.ExitCode != 1 || !r.StartedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("ExecHistory() = %+v", r)
+	}
+
+	all, err := d.ExecHistory("", "", time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("ExecHistory(all) error = %v", err)
+	}
+	if len(all) != 4 || all[len(all)-1].ArgsHash != "a1" {
+		t.Errorf("ExecHistory(all) should list 4 runs newest first, got %+v", all)
+	}
+
+	limited, err := d.ExecHistory("", "work", time.Time{}, 2)
+	if err != nil {
+		t.Fatalf("ExecHistory(limit) error = %v", err)
+	}
+	if len(limited) != 2 || limited[0].ArgsHash != "d4" || limited[1].ArgsHash != "b2" {
+		t.Errorf("ExecHistory(work, limit 2) = %+v", limited)
+	}
+}
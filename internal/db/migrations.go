@@ -94,6 +94,90 @@ VALUES
     ('claude', 5, 0, CURRENT_TIMESTAMP),
     ('codex', 3, 0, CURRENT_TIMESTAMP),
     ('gemini', 2, 0, CURRENT_TIMESTAMP);
+`,
+	},
+	{
+		Version: 4,
+		Name:    "account_profiles",
+		Up: `
+-- Links profile names to the underlying account (provider + email hash) so
+-- cooldowns can be shared by every profile of the same account.
+CREATE TABLE IF NOT EXISTS account_profiles (
+    provider TEXT NOT NULL,
+    profile_name TEXT NOT NULL,
+    account_key TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, profile_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_profiles_key ON account_profiles(provider, account_key);
+`,
+	},
+	{
+		Version: 5,
+		Name:    "wrap_session_work_dir",
+		Up: `
+-- Records where each session ran so reports can group time by project.
+ALTER TABLE wrap_sessions ADD COLUMN work_dir TEXT;
+`,
+	},
+	{
+		Version: 6,
+		Name:    "robot_actions",
+		Up: `
+-- Robot actions run with an idempotency key, so a retried action replays
+-- its first result instead of applying twice. result is NULL while the
+-- action is still running.
+CREATE TABLE IF NOT EXISTS robot_actions (
+    idempotency_key TEXT PRIMARY KEY,
+    request TEXT NOT NULL,
+    result TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_robot_actions_created_at ON robot_actions(created_at);
+`,
+	},
+	{
+		Version: 7,
+		Name:    "vault_sizes",
+		Up: `
+-- Samples of each tool's vault footprint, so 'caam vault stats' can show
+-- growth over time.
+CREATE TABLE IF NOT EXISTS vault_sizes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    recorded_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    provider TEXT NOT NULL,
+    profiles INTEGER NOT NULL,
+    bytes INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_vault_sizes_recorded_at ON vault_sizes(recorded_at);
+`,
+	},
+	{
+		Version: 8,
+		Name:    "exec_history",
+		Up: `
+-- What each 'caam exec' ran, when analytics.exec_history is on. args holds
+-- the redacted, truncated arguments (empty in hash mode); args_hash always
+-- fingerprints them so repeated runs can be told apart.
+CREATE TABLE IF NOT EXISTS exec_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at DATETIME NOT NULL,
+    provider TEXT NOT NULL,
+    profile_name TEXT NOT NULL,
+    mode TEXT NOT NULL,
+    args TEXT NOT NULL DEFAULT '',
+    args_hash TEXT NOT NULL,
+    work_dir TEXT,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    exit_code INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_exec_history_provider_profile ON exec_history(provider, profile_name);
+CREATE INDEX IF NOT EXISTS idx_exec_history_started_at ON exec_history(started_at);
 `,
 	},
 }
@@ -103,6 +187,12 @@ func RunMigrations(db *sql.DB) error {
 		return fmt.Errorf("db is nil")
 	}
 
+	// Every process runs this on open; skip the write transaction when the
+	// schema is already current so opens don't queue on the write lock.
+	if current, err := currentSchemaVersion(db); err == nil && current >= latestSchemaVersion() {
+		return nil
+	}
+
 	tx, err := db.Begin()
 	if err != nil {
 		return fmt.Errorf("begin: %w", err)
@@ -159,6 +249,17 @@ CREATE TABLE IF NOT EXISTS schema_version (
 	return nil
 }
 
+// latestSchemaVersion returns the version the migrations bring the schema to.
+func latestSchemaVersion() int {
+	latest := 0
+	for _, m := range migrations {
+		if m.Version > latest {
+			latest = m.Version
+		}
+	}
+	return latest
+}
+
 func currentSchemaVersion(query sqlQueryer) (int, error) {
 	if query == nil {
 		return 0, fmt.Errorf("query is nil")
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// PostgresBackend keeps the database on a Postgres server, so every machine
+// pointed at it shares cooldowns and usage history.
+//
+// Timestamps are stored as text in the same UTC "2006-01-02 15:04:05" form
+// SQLite uses, which keeps comparisons and scans identical across backends.
+type PostgresBackend struct {
+	DSN string
+}
+
+// Name implements Backend.
+func (b PostgresBackend) Name() string { return "postgres" }
+
+// Open implements Backend.
+func (b PostgresBackend) Open() (*sql.DB, error) {
+	connector, err := pq.NewConnector(b.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres dsn: %w", err)
+	}
+	conn := sql.OpenDB(pgConnector{connector})
+
+	initErr := func() error {
+		if err := conn.Ping(); err != nil {
+			return fmt.Errorf("ping postgres: %w", err)
+		}
+		return RunMigrations(conn)
+	}()
+	if initErr != nil {
+		_ = conn.Close()
+		return nil, initErr
+	}
+	return conn, nil
+}
+
+var (
+	pgDatetimeFunc   = regexp.MustCompile(`\bdatetime\(([^()]*)\)`)
+	pgDateFunc       = regexp.MustCompile(`\bdate\(([^()]*)\)`)
+	pgAutoincrement  = regexp.MustCompile(`\bINTEGER PRIMARY KEY AUTOINCREMENT\b`)
+	pgDatetimeType   = regexp.MustCompile(`\bDATETIME\b`)
+	pgInsertOrIgnore = regexp.MustCompile(`INSERT OR IGNORE INTO ([^;]*)`)
+)
+
+// pgNow is CURRENT_TIMESTAMP in the text form timestamps are stored in.
+const pgNow = `to_char(now() AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS')`
+
+// postgresRebind translates a statement from the SQLite dialect caam's
+// queries and migrations are written in. Timestamps are canonical text, so
+// datetime() normalization is dropped and date() becomes a prefix.
+func postgresRebind(query string) string {
+	query = pgDatetimeFunc.ReplaceAllString(query, "$1")
+	query = pgDateFunc.ReplaceAllString(query, "substr($1, 1, 10)")
+	query = pgAutoincrement.ReplaceAllString(query, "BIGSERIAL PRIMARY KEY")
+	query = pgDatetimeType.ReplaceAllString(query, "TEXT")
+	query = strings.ReplaceAll(query, "CURRENT_TIMESTAMP", pgNow)
+	query = pgInsertOrIgnore.ReplaceAllString(query, "INSERT INTO $1 ON CONFLICT DO NOTHING")
+
+	// ? placeholders become $1, $2, ... outside string literals and
+	// comments.
+	var b strings.Builder
+	n := 0
+	quoted, comment := false, false
+	runes := []rune(query)
+	for i, r := range runes {
+		switch {
+		case comment:
+			comment = r != '\n'
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			comment = true
+		case r == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// pgConnector hands out connections that translate each statement.
+type pgConnector struct {
+	driver.Connector
+}
+
+func (c pgConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pgConn{conn}, nil
+}
+
+// pgConn wraps a pq connection, rebinding every statement before pq sees
+// it. Statements without arguments go through ExecContext/QueryContext, so
+// multi-statement migrations still run in one round trip.
+type pgConn struct {
+	driver.Conn
+}
+
+func (c pgConn) Prepare(query string) (driver.Stmt, error) {
+	return c.Conn.Prepare(postgresRebind(query))
+}
+
+func (c pgConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return p.PrepareContext(ctx, postgresRebind(query))
+	}
+	return c.Prepare(query)
+}
+
+func (c pgConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c pgConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if e, ok := c.Conn.(driver.ExecerContext); ok {
+		return e.ExecContext(ctx, postgresRebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c pgConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if q, ok := c.Conn.(driver.QueryerContext); ok {
+		return q.QueryContext(ctx, postgresRebind(query), args)
+	}
+	return nil, driver.ErrSkip
+}
+
+func (c pgConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
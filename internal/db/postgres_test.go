@@ -0,0 +1,96 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+func TestPostgresRebind(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "placeholders and datetime",
+			in:   `SELECT id FROM limit_events WHERE provider = ? AND datetime(cooldown_until) > datetime(?) ORDER BY datetime(hit_at) DESC`,
+			want: `SELECT id FROM limit_events WHERE provider = $1 AND cooldown_until > $2 ORDER BY hit_at DESC`,
+		},
+		{
+			name: "date prefix",
+			in:   `SELECT date(timestamp) AS day FROM activity_log`,
+			want: `SELECT substr(timestamp, 1, 10) AS day FROM activity_log`,
+		},
+		{
+			name: "question marks in literals and comments stay",
+			in:   "-- why? because\nSELECT '?' || ? FROM t",
+			want: "-- why? because\nSELECT '?' || $1 FROM t",
+		},
+		{
+			name: "schema",
+			in:   `CREATE TABLE x (id INTEGER PRIMARY KEY AUTOINCREMENT, at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP);`,
+			want: `CREATE TABLE x (id BIGSERIAL PRIMARY KEY, at TEXT NOT NULL DEFAULT ` + pgNow + `);`,
+		},
+		{
+			name: "insert or ignore",
+			in:   "INSERT OR IGNORE INTO cost_rates (provider) VALUES ('claude');",
+			want: "INSERT INTO cost_rates (provider) VALUES ('claude') ON CONFLICT DO NOTHING;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := postgresRebind(tt.in); got != tt.want {
+				t.Errorf("postgresRebind()\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostgresRebind_Migrations(t *testing.T) {
+	for _, m := range migrations {
+		got := postgresRebind(m.Up)
+		for _, sqliteOnly := range []string{"AUTOINCREMENT", "DATETIME", "INSERT OR", "?"} {
+			if strings.Contains(got, sqliteOnly) {
+				t.Errorf("migration %d (%s) still contains %q after rebind", m.Version, m.Name, sqliteOnly)
+			}
+		}
+	}
+}
+
+func TestNewBackend(t *testing.T) {
+	t.Setenv("CAAM_HOME", t.TempDir())
+
+	b, err := NewBackend(config.DatabaseConfig{})
+	if err != nil {
+		t.Fatalf("NewBackend(default) error = %v", err)
+	}
+	if sb, ok := b.(SQLiteBackend); !ok || sb.Path != DefaultPath() {
+		t.Fatalf("NewBackend(default) = %#v, want SQLite at %s", b, DefaultPath())
+	}
+
+	b, err = NewBackend(config.DatabaseConfig{Backend: "postgres", DSN: "postgres://caam@db/caam"})
+	if err != nil {
+		t.Fatalf("NewBackend(postgres) error = %v", err)
+	}
+	if b.Name() != "postgres" {
+		t.Fatalf("NewBackend(postgres).Name() = %q", b.Name())
+	}
+
+	if _, err := NewBackend(config.DatabaseConfig{Backend: "postgres"}); err == nil {
+		t.Error("NewBackend(postgres without dsn) should fail")
+	}
+	if _, err := NewBackend(config.DatabaseConfig{Backend: "mysql"}); err == nil {
+		t.Error("NewBackend(mysql) should fail")
+	}
+
+	d, err := OpenBackend(SQLiteBackend{Path: DefaultPath()})
+	if err != nil {
+		t.Fatalf("OpenBackend(sqlite) error = %v", err)
+	}
+	defer d.Close()
+	if d.Backend() != "sqlite" || d.Path() != DefaultPath() {
+		t.Errorf("OpenBackend(sqlite) backend = %q, path = %q", d.Backend(), d.Path())
+	}
+}
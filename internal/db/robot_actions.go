@@ -0,0 +1,146 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RobotActionTTL is how long an idempotency key is remembered. A key older
+// than this is free to be used for a new action.
+const RobotActionTTL = 24 * time.Hour
+
+// RobotActionPendingTimeout is how long a claimed action may stay pending
+// before it is taken as abandoned (its process died) and the key freed.
+const RobotActionPendingTimeout = 10 * time.Minute
+
+// ErrIdempotencyKeyReused is returned when a key is claimed for a request
+// other than the one it was first used for.
+var ErrIdempotencyKeyReused = errors.New("idempotency key already used for a different request")
+
+// RobotAction is a robot action recorded under an idempotency key.
+type RobotAction struct {
+	Key string
+	// Request identifies what was asked, e.g. "activate claude work".
+	Request string
+	// Result is the action's recorded output. Empty while it is running.
+	Result      string
+	CreatedAt   time.Time
+	CompletedAt time.Time
+}
+
+// Pending reports whether the action is still running: claimed but not
+// completed.
+func (a *RobotAction) Pending() bool {
+	return a != nil && a.CompletedAt.IsZero()
+}
+
+// ClaimRobotAction reserves key for request. It returns nil when the key was
+// free and is now held by the caller, who must CompleteRobotAction or
+// ReleaseRobotAction it. When the key is already held for the same request,
+// the existing action is returned instead (pending or completed); for a
+// different request it fails with ErrIdempotencyKeyReused.
+func (d *DB) ClaimRobotAction(key, request string, now time.Time) (*RobotAction, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, fmt.Errorf("idempotency key is required")
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var (
+		existing         RobotAction
+		result           sql.NullString
+		createdAt        string
+		completedAtValue sql.NullString
+	)
+	err = tx.QueryRow(
+		`SELECT idempotency_key, request, result, created_at, completed_at FROM robot_actions WHERE idempotency_key = ?`,
+		key,
+	).Scan(&existing.Key, &existing.Request, &result, &createdAt, &completedAtValue)
+	switch {
+	case err == nil:
+		existing.Result = result.String
+		existing.CreatedAt, _ = parseSQLiteTime(createdAt)
+		if completedAtValue.Valid {
+			existing.CompletedAt, _ = parseSQLiteTime(completedAtValue.String)
+		}
+		age := now.Sub(existing.CreatedAt)
+		abandoned := existing.Pending() && age >= RobotActionPendingTimeout
+		if age < RobotActionTTL && !abandoned {
+			if existing.Request != request {
+				return nil, fmt.Errorf("%w: %q was %q", ErrIdempotencyKeyReused, key, existing.Request)
+			}
+			return &existing, nil
+		}
+		// Expired or abandoned: the key is free again.
+		if _, err := tx.Exec(`DELETE FROM robot_actions WHERE idempotency_key = ?`, key); err != nil {
+			return nil, fmt.Errorf("delete expired robot action: %w", err)
+		}
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		return nil, fmt.Errorf("query robot_actions: %w", err)
+	}
+
+	if _, err := tx.Exec(
+		`INSERT INTO robot_actions (idempotency_key, request, created_at) VALUES (?, ?, ?)`,
+		key, request, formatSQLiteTime(now),
+	); err != nil {
+		return nil, fmt.Errorf("insert robot action: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return nil, nil
+}
+
+// CompleteRobotAction records the result of the action claimed under key.
+func (d *DB) CompleteRobotAction(key, result string, now time.Time) error {
+	if d == nil || d.conn == nil {
+		return fmt.Errorf("db is not open")
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+	res, err := d.conn.Exec(
+		`UPDATE robot_actions SET result = ?, completed_at = ? WHERE idempotency_key = ?`,
+		result, formatSQLiteTime(now), strings.TrimSpace(key),
+	)
+	if err != nil {
+		return fmt.Errorf("update robot action: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("robot action %q not claimed", key)
+	}
+	return nil
+}
+
+// ReleaseRobotAction frees a key whose action failed, so a retry runs the
+// action again rather than replaying the failure. Completed actions are
+// kept.
+func (d *DB) ReleaseRobotAction(key string) error {
+	if d == nil || d.conn == nil {
+		return fmt.Errorf("db is not open")
+	}
+	if _, err := d.conn.Exec(
+		`DELETE FROM robot_actions WHERE idempotency_key = ? AND completed_at IS NULL`,
+		strings.TrimSpace(key),
+	); err != nil {
+		return fmt.Errorf("delete robot action: %w", err)
+	}
+	return nil
+}
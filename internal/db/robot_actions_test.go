@@ -0,0 +1,85 @@
+package db
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRobotActions_ClaimCompleteReplay(t *testing.T) {
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	prev, err := d.ClaimRobotAction("k1", "activate claude work", now)
+	if err != nil || prev != nil {
+		t.Fatalf("first ClaimRobotAction() = %+v, %v; want claimed", prev, err)
+	}
+
+	// A retry while the first run is going sees it pending.
+	prev, err = d.ClaimRobotAction("k1", "activate claude work", now.Add(time.Second))
+	if err != nil || !prev.Pending() {
+		t.Fatalf("ClaimRobotAction() while running = %+v, %v; want pending", prev, err)
+	}
+
+	if err := d.CompleteRobotAction("k1", `{"success":true}`, now.Add(2*time.Second)); err != nil {
+		t.Fatalf("CompleteRobotAction() error = %v", err)
+	}
+	prev, err = d.ClaimRobotAction("k1", "activate claude work", now.Add(time.Minute))
+	if err != nil || prev == nil || prev.Pending() || prev.Result != `{"success":true}` {
+		t.Fatalf("ClaimRobotAction() after completion = %+v, %v; want recorded result", prev, err)
+	}
+
+	if _, err := d.ClaimRobotAction("k1", "cooldown claude work", now.Add(time.Minute)); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Fatalf("ClaimRobotAction() for another request error = %v, want ErrIdempotencyKeyReused", err)
+	}
+
+	// After the TTL the key is free again.
+	prev, err = d.ClaimRobotAction("k1", "cooldown claude work", now.Add(RobotActionTTL+time.Minute))
+	if err != nil || prev != nil {
+		t.Fatalf("ClaimRobotAction() after TTL = %+v, %v; want claimed", prev, err)
+	}
+}
+
+func TestRobotActions_ReleaseAndAbandon(t *testing.T) {
+	d, err := OpenAt(filepath.Join(t.TempDir(), "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	// A released (failed) action can be claimed again at once.
+	if _, err := d.ClaimRobotAction("k2", "backup codex", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ReleaseRobotAction("k2"); err != nil {
+		t.Fatalf("ReleaseRobotAction() error = %v", err)
+	}
+	if prev, err := d.ClaimRobotAction("k2", "backup codex", now); err != nil || prev != nil {
+		t.Fatalf("ClaimRobotAction() after release = %+v, %v; want claimed", prev, err)
+	}
+
+	// A claim that never completes is abandoned after the pending timeout.
+	prev, err := d.ClaimRobotAction("k2", "backup codex", now.Add(RobotActionPendingTimeout+time.Second))
+	if err != nil || prev != nil {
+		t.Fatalf("ClaimRobotAction() after abandon = %+v, %v; want claimed", prev, err)
+	}
+
+	// Completed actions survive a release.
+	if err := d.CompleteRobotAction("k2", `{}`, now); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.ReleaseRobotAction("k2"); err != nil {
+		t.Fatal(err)
+	}
+	if prev, err := d.ClaimRobotAction("k2", "backup codex", now); err != nil || prev == nil || prev.Pending() {
+		t.Fatalf("ClaimRobotAction() after releasing a completed action = %+v, %v; want recorded", prev, err)
+	}
+}
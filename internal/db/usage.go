@@ -0,0 +1,124 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DailyUsage is one profile's activity on one (UTC) day.
+type DailyUsage struct {
+	Day           time.Time // midnight UTC
+	Provider      string
+	ProfileName   string
+	Sessions      int
+	ActiveSeconds int64
+	LimitHits     int
+}
+
+// DailyUsage aggregates activity_log and limit_events into per-day,
+// per-profile rows since the given time, ordered by day then profile.
+// Sessions count activations, active time comes from deactivations and
+// limit hits from recorded cooldowns.
+func (d *DB) DailyUsage(since time.Time) ([]DailyUsage, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+	sinceStr := formatSQLiteTime(since)
+
+	type key struct{ day, provider, profile string }
+	byKey := make(map[key]*DailyUsage)
+	get := func(day, provider, profile string) (*DailyUsage, error) {
+		k := key{day, provider, profile}
+		if u, ok := byKey[k]; ok {
+			return u, nil
+		}
+		ts, err := time.ParseInLocation("2006-01-02", day, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("parse day %q: %w", day, err)
+		}
+		u := &DailyUsage{Day: ts, Provider: provider, ProfileName: profile}
+		byKey[k] = u
+		return u, nil
+	}
+
+	rows, err := d.conn.Query(
+		`SELECT date(timestamp) AS day,
+		        provider,
+		        profile_name,
+		        SUM(CASE WHEN event_type = ? THEN 1 ELSE 0 END),
+		        SUM(CASE WHEN event_type = ? THEN COALESCE(duration_seconds, 0) ELSE 0 END)
+		   FROM activity_log
+		  WHERE datetime(timestamp) >= datetime(?)
+		  GROUP BY day, provider, profile_name`,
+		EventActivate,
+		EventDeactivate,
+		sinceStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query daily activity: %w", err)
+	}
+	for rows.Next() {
+		var day, provider, profile string
+		var sessions int
+		var seconds int64
+		if err := rows.Scan(&day, &provider, &profile, &sessions, &seconds); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan daily activity: %w", err)
+		}
+		u, err := get(day, provider, profile)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		u.Sessions += sessions
+		u.ActiveSeconds += seconds
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, fmt.Errorf("iterate daily activity: %w", err)
+	}
+	rows.Close()
+
+	rows, err = d.conn.Query(
+		`SELECT date(hit_at) AS day, provider, profile_name, COUNT(*)
+		   FROM limit_events
+		  WHERE datetime(hit_at) >= datetime(?)
+		  GROUP BY day, provider, profile_name`,
+		sinceStr,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query daily limit hits: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var day, provider, profile string
+		var hits int
+		if err := rows.Scan(&day, &provider, &profile, &hits); err != nil {
+			return nil, fmt.Errorf("scan daily limit hits: %w", err)
+		}
+		u, err := get(day, provider, profile)
+		if err != nil {
+			return nil, err
+		}
+		u.LimitHits += hits
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate daily limit hits: %w", err)
+	}
+
+	out := make([]DailyUsage, 0, len(byKey))
+	for _, u := range byKey {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Day.Equal(out[j].Day) {
+			return out[i].Day.Before(out[j].Day)
+		}
+		if out[i].Provider != out[j].Provider {
+			return out[i].Provider < out[j].Provider
+		}
+		return out[i].ProfileName < out[j].ProfileName
+	})
+	return out, nil
+}
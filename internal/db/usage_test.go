@@ -0,0 +1,52 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDailyUsage_AggregatesByDayAndProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	today := time.Now().UTC().Truncate(24 * time.Hour).Add(time.Hour)
+	yesterday := today.Add(-24 * time.Hour)
+	events := []Event{
+		{Timestamp: yesterday, Type: EventActivate, Provider: "claude", ProfileName: "work"},
+		{Timestamp: yesterday.Add(time.Minute), Type: EventDeactivate, Provider: "claude", ProfileName: "work", Duration: 2 * time.Hour},
+		{Timestamp: today, Type: EventActivate, Provider: "claude", ProfileName: "work"},
+		{Timestamp: today, Type: EventActivate, Provider: "claude", ProfileName: "work"},
+		{Timestamp: today.AddDate(0, 0, -30), Type: EventActivate, Provider: "claude", ProfileName: "work"},
+	}
+	for _, e := range events {
+		if err := d.LogEvent(e); err != nil {
+			t.Fatalf("LogEvent() error = %v", err)
+		}
+	}
+	if _, err := d.SetCooldown("codex", "main", today, time.Hour, ""); err != nil {
+		t.Fatalf("SetCooldown() error = %v", err)
+	}
+
+	rows, err := d.DailyUsage(today.AddDate(0, 0, -7))
+	if err != nil {
+		t.Fatalf("DailyUsage() error = %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("DailyUsage() len = %d, want 3: %+v", len(rows), rows)
+	}
+
+	if r := rows[0]; !r.Day.Equal(yesterday.Truncate(24*time.Hour)) || r.Sessions != 1 || r.ActiveSeconds != 7200 {
+		t.Errorf("rows[0] = %+v, want yesterday claude/work with 1 session and 7200s", r)
+	}
+	if r := rows[1]; r.Provider != "claude" || r.Sessions != 2 || r.LimitHits != 0 {
+		t.Errorf("rows[1] = %+v, want today claude/work with 2 sessions", r)
+	}
+	if r := rows[2]; r.Provider != "codex" || r.LimitHits != 1 || r.Sessions != 0 {
+		t.Errorf("rows[2] = %+v, want today codex/main with 1 limit hit", r)
+	}
+}
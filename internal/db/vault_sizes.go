@@ -0,0 +1,88 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VaultSize is one sample of a tool's vault footprint.
+type VaultSize struct {
+	RecordedAt time.Time
+	Provider   string
+	Profiles   int
+	Bytes      int64
+}
+
+// RecordVaultSizes stores one sample per tool, all stamped with at.
+func (d *DB) RecordVaultSizes(at time.Time, sizes []VaultSize) error {
+	if d == nil || d.conn == nil {
+		return fmt.Errorf("db is not open")
+	}
+	if len(sizes) == 0 {
+		return nil
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	stamp := formatSQLiteTime(at)
+	for _, s := range sizes {
+		provider := strings.TrimSpace(s.Provider)
+		if provider == "" {
+			return fmt.Errorf("provider is required")
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO vault_sizes (recorded_at, provider, profiles, bytes) VALUES (?, ?, ?, ?)`,
+			stamp, provider, s.Profiles, s.Bytes,
+		); err != nil {
+			return fmt.Errorf("insert vault_sizes: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// VaultSizes returns the samples recorded since the given time, oldest
+// first.
+func (d *DB) VaultSizes(since time.Time) ([]VaultSize, error) {
+	if d == nil || d.conn == nil {
+		return nil, fmt.Errorf("db is not open")
+	}
+
+	rows, err := d.conn.Query(
+		`SELECT recorded_at, provider, profiles, bytes
+		   FROM vault_sizes
+		  WHERE datetime(recorded_at) >= datetime(?)
+		  ORDER BY datetime(recorded_at) ASC, provider ASC`,
+		formatSQLiteTime(since),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query vault_sizes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []VaultSize
+	for rows.Next() {
+		var recordedAt string
+		var s VaultSize
+		if err := rows.Scan(&recordedAt, &s.Provider, &s.Profiles, &s.Bytes); err != nil {
+			return nil, fmt.Errorf("scan vault_sizes: %w", err)
+		}
+		ts, err := parseSQLiteTime(recordedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parse recorded_at: %w", err)
+		}
+		s.RecordedAt = ts
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate vault_sizes: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVaultSizes_RecordAndQuerySince(t *testing.T) {
+	tmpDir := t.TempDir()
+	d, err := OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = d.Close() })
+
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.AddDate(0, 0, -60)
+	if err := d.RecordVaultSizes(old, []VaultSize{{Provider: "codex", Profiles: 1, Bytes: 10}}); err != nil {
+		t.Fatalf("RecordVaultSizes(old) error = %v", err)
+	}
+	if err := d.RecordVaultSizes(now, []VaultSize{
+		{Provider: "codex", Profiles: 2, Bytes: 200},
+		{Provider: "claude", Profiles: 1, Bytes: 50},
+	}); err != nil {
+		t.Fatalf("RecordVaultSizes(now) error = %v", err)
+	}
+	if err := d.RecordVaultSizes(now, []VaultSize{{Provider: " "}}); err == nil {
+		t.Error("RecordVaultSizes() should reject an empty provider")
+	}
+
+	sizes, err := d.VaultSizes(now.AddDate(0, 0, -30))
+	if err != nil {
+		t.Fatalf("VaultSizes() error = %v", err)
+	}
+	if len(sizes) != 2 {
+		t.Fatalf("VaultSizes() len = %d, want 2: %+v", len(sizes), sizes)
+	}
+	if sizes[0].Provider != "claude" || sizes[1].Provider != "codex" || sizes[1].Bytes != 200 || sizes[1].Profiles != 2 {
+		t.Errorf("VaultSizes() = %+v", sizes)
+	}
+	if !sizes[0].RecordedAt.Equal(now) {
+		t.Errorf("RecordedAt = %v, want %v", sizes[0].RecordedAt, now)
+	}
+}
@@ -0,0 +1,171 @@
+// Package devicecode helps finish a device-code login on another device. It
+// watches the login command's output for the verification URL and one-time
+// code and shows the URL as a terminal QR code, so a login on a headless box
+// over SSH can be completed from a phone.
+package devicecode
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/qrcode"
+	"golang.org/x/term"
+)
+
+var (
+	urlPattern  = regexp.MustCompile(`https://[^\s"'<>]+`)
+	codePattern = regexp.MustCompile(`\b[A-Z0-9]{4,5}-[A-Z0-9]{4,5}\b`)
+	ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+)
+
+// quietZone is the QR code's margin in modules. The standard asks for four;
+// two scans fine from a screen and saves width.
+const quietZone = 2
+
+type qrKey struct{}
+
+// WithQR returns a context telling device-code logins whether to show a QR
+// code (they do by default).
+func WithQR(ctx context.Context, enabled bool) context.Context {
+	return context.WithValue(ctx, qrKey{}, enabled)
+}
+
+// QREnabled reports whether ctx asks device-code logins for a QR code.
+func QREnabled(ctx context.Context) bool {
+	enabled, ok := ctx.Value(qrKey{}).(bool)
+	return !ok || enabled
+}
+
+// Watcher scans the output of a device-code login. Once the verification URL
+// appears it is shown as a QR code, followed by the one-time code when there
+// is one, on the stream the URL came from.
+type Watcher struct {
+	qr    bool
+	width int
+
+	mu    sync.Mutex
+	url   string
+	code  string
+	shown bool // the URL has been handled
+	drawn bool // and its QR code printed
+}
+
+// NewWatcher returns a Watcher. qr turns the QR code on; width is the
+// terminal's width in columns (0 if unknown), used to skip a code too wide
+// to scan.
+func NewWatcher(qr bool, width int) *Watcher {
+	return &Watcher{qr: qr, width: width}
+}
+
+// ForTerminal returns a Watcher for output shown on this process's
+// terminal, drawing QR codes as ctx asks.
+func ForTerminal(ctx context.Context) *Watcher {
+	width := 0
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		width = w
+	}
+	return NewWatcher(QREnabled(ctx), width)
+}
+
+// URL returns the verification URL, once seen.
+func (w *Watcher) URL() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.url
+}
+
+// Code returns the one-time code, once seen.
+func (w *Watcher) Code() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.code
+}
+
+// Wrap returns a writer that passes output through to out and scans it.
+func (w *Watcher) Wrap(out io.Writer) io.Writer {
+	return &lineWriter{watcher: w, out: out}
+}
+
+func (w *Watcher) scan(line string, out io.Writer) {
+	line = ansiPattern.ReplaceAllString(line, "")
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.url == "" {
+		w.url = urlPattern.FindString(line)
+	}
+	codeSeen := false
+	if w.code == "" {
+		if w.code = codePattern.FindString(line); w.code != "" {
+			codeSeen = true
+		}
+	}
+	if w.url == "" {
+		return
+	}
+
+	if !w.shown {
+		w.shown = true
+		w.show(out)
+		return
+	}
+	if codeSeen && w.drawn {
+		fmt.Fprintf(out, "\n  One-time code: %s\n\n", w.code)
+	}
+}
+
+// show writes the QR code for the URL, plus the code if it is already known.
+func (w *Watcher) show(out io.Writer) {
+	if !w.qr {
+		return
+	}
+	code, err := qrcode.Encode(w.url)
+	switch {
+	case err != nil:
+		fmt.Fprintf(out, "\n(no QR code: %v)\n", err)
+		return
+	case w.width > 0 && code.TerminalWidth(quietZone) > w.width:
+		fmt.Fprintf(out, "\n(terminal too narrow for the QR code: it needs %d columns; --no-qr hides this)\n", code.TerminalWidth(quietZone))
+		return
+	}
+	w.drawn = true
+	fmt.Fprintf(out, "\nScan to open %s on your phone:\n\n%s", w.url, code.Terminal(quietZone))
+	if w.code != "" {
+		fmt.Fprintf(out, "\n  One-time code: %s\n", w.code)
+	}
+	fmt.Fprintln(out)
+}
+
+// lineWriter forwards writes unchanged and hands each complete line to the
+// watcher.
+type lineWriter struct {
+	watcher *Watcher
+	out     io.Writer
+	buf     []byte
+}
+
+// maxLine bounds the buffered partial line so output without newlines can't
+// grow it forever.
+const maxLine = 64 * 1024
+
+func (l *lineWriter) Write(p []byte) (int, error) {
+	n, err := l.out.Write(p)
+	l.buf = append(l.buf, p[:n]...)
+	for {
+		i := bytes.IndexByte(l.buf, '\n')
+		if i < 0 {
+			break
+		}
+		l.watcher.scan(string(l.buf[:i]), l.out)
+		l.buf = l.buf[i+1:]
+	}
+	if len(l.buf) > maxLine {
+		l.buf = nil
+	}
+	return n, err
+}
@@ -0,0 +1,79 @@
+package devicecode
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWatcher(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWatcher(true, 0)
+	stream := w.Wrap(&out)
+
+	login := "Follow these steps to sign in:\n" +
+		"1. Open this link\n   \x1b[94mhttps://auth.openai.com/codex/device\x1b[0m\n" +
+		"2. Enter this one-time code\n   \x1b[94mABCD-12345\x1b[0m\n"
+	// Arbitrary chunking must not matter.
+	for _, chunk := range strings.SplitAfter(login, "o") {
+		if _, err := stream.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if w.URL() != "https://auth.openai.com/codex/device" {
+		t.Errorf("URL() = %q", w.URL())
+	}
+	if w.Code() != "ABCD-12345" {
+		t.Errorf("Code() = %q", w.Code())
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, login[:60]) {
+		t.Errorf("output doesn't start with the login's own output:\n%s", got)
+	}
+	for _, want := range []string{"Scan to open https://auth.openai.com/codex/device", "█", "One-time code: ABCD-12345"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Count(got, "Scan to open") != 1 {
+		t.Error("QR code shown more than once")
+	}
+}
+
+func TestWatcher_NoQR(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWatcher(false, 0)
+	line := "open https://example.com/device and enter WXYZ-1234\n"
+	if _, err := w.Wrap(&out).Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != line {
+		t.Errorf("output = %q, want it passed through untouched", out.String())
+	}
+	if w.Code() != "WXYZ-1234" {
+		t.Errorf("Code() = %q", w.Code())
+	}
+}
+
+func TestWatcher_NarrowTerminal(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWatcher(true, 20)
+	if _, err := w.Wrap(&out).Write([]byte("https://example.com/device\n")); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "█") || !strings.Contains(out.String(), "too narrow") {
+		t.Errorf("output = %q, want the QR code skipped", out.String())
+	}
+}
+
+func TestQREnabled(t *testing.T) {
+	ctx := context.Background()
+	if !QREnabled(ctx) {
+		t.Error("QR codes should be on by default")
+	}
+	if QREnabled(WithQR(ctx, false)) {
+		t.Error("WithQR(false) should turn them off")
+	}
+}
@@ -0,0 +1,289 @@
+// Package durations parses the human-friendly durations and times accepted by
+// caam's flags: Go durations extended with days and weeks ("1d", "2w",
+// "1d12h") and wall-clock times measured from now ("9am", "tomorrow 14:00",
+// "friday 9am").
+//
+// Durations are elapsed time: "1d" is always 24 hours, even across a
+// daylight-saving change. Wall-clock times are read on the calendar of a
+// zone, so "tomorrow 9am" means 9:00 on tomorrow's clock however long that
+// is from now. Times are returned in UTC; only the calendar arithmetic uses
+// the zone.
+package durations
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+// units maps each unit suffix to its length. Longer suffixes are matched
+// first so "ms" isn't read as minutes.
+var units = []struct {
+	suffix string
+	length time.Duration
+}{
+	{"ms", time.Millisecond},
+	{"us", time.Microsecond},
+	{"µs", time.Microsecond},
+	{"ns", time.Nanosecond},
+	{"w", week},
+	{"d", day},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// Parse parses a duration such as "90m", "2h30m", "1d" or "1.5w". It accepts
+// everything time.ParseDuration does except negative values, plus the units
+// d (24h) and w (7d).
+func Parse(s string) (time.Duration, error) {
+	in := strings.ToLower(strings.TrimSpace(s))
+	if in == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+	if in == "0" {
+		return 0, nil
+	}
+
+	var total time.Duration
+	rest := in
+	for rest != "" {
+		n := 0
+		for n < len(rest) && (rest[n] >= '0' && rest[n] <= '9' || rest[n] == '.') {
+			n++
+		}
+		if n == 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		value, err := strconv.ParseFloat(rest[:n], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		rest = rest[n:]
+
+		var length time.Duration
+		for _, u := range units {
+			if strings.HasPrefix(rest, u.suffix) {
+				length = u.length
+				rest = rest[len(u.suffix):]
+				break
+			}
+		}
+		if length == 0 {
+			return 0, fmt.Errorf("invalid duration %q: missing unit (use s, m, h, d or w)", s)
+		}
+		total += time.Duration(value * float64(length))
+	}
+	return total, nil
+}
+
+// ParseFrom parses s as a duration (see Parse) or as a time after now and
+// returns how long from now that is. Times may be absolute ("2026-01-02
+// 15:04", RFC3339) or wall-clock ("9am", "14:30", "noon", "tomorrow 9am",
+// "friday 9am"). A clock time alone means its next occurrence; a weekday
+// means the next such day, today included while the time is still ahead.
+func ParseFrom(s string, now time.Time) (time.Duration, error) {
+	if d, err := Parse(s); err == nil {
+		return d, nil
+	}
+	t, err := ParseTime(s, now)
+	if err != nil {
+		return 0, err
+	}
+	if !t.After(now) {
+		return 0, fmt.Errorf("%q is in the past", s)
+	}
+	return t.Sub(now), nil
+}
+
+// ParseTime parses an absolute or wall-clock time relative to now, reading
+// the calendar in now's location, and returns it in UTC. See ParseFrom for
+// the accepted forms.
+//
+// A clock time that a daylight-saving change skips (2:30 on a spring-forward
+// night) moves forward by the length of the gap; one that happens twice
+// (1:30 on a fall-back night) is the first occurrence.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	in := strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if in == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(s)); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02t15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, in, now.Location()); err == nil {
+			return wallTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), now.Location()).UTC(), nil
+		}
+	}
+
+	fields := strings.Fields(in)
+	var (
+		hasDay  bool // a day was given
+		weekday bool // the day was a weekday name
+		offset  int  // days from today
+	)
+	switch {
+	case fields[0] == "today":
+		hasDay = true
+		fields = fields[1:]
+	case fields[0] == "tomorrow":
+		hasDay, offset = true, 1
+		fields = fields[1:]
+	default:
+		if wd, ok := parseWeekday(fields[0]); ok {
+			hasDay, weekday = true, true
+			offset = (int(wd) - int(now.Weekday()) + 7) % 7
+			fields = fields[1:]
+		}
+	}
+
+	// A day alone means its start.
+	hour, minute := 0, 0
+	if len(fields) > 0 {
+		var err error
+		if hour, minute, err = parseClock(strings.Join(fields, "")); err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", s)
+		}
+	} else if !hasDay {
+		return time.Time{}, fmt.Errorf("invalid time %q", s)
+	}
+
+	year, month, today := now.Date()
+	t := wallTime(year, month, today+offset, hour, minute, now.Location())
+	if !t.After(now) {
+		switch {
+		case weekday:
+			t = wallTime(year, month, today+offset+7, hour, minute, now.Location()) // today's weekday, time already passed
+		case !hasDay:
+			t = wallTime(year, month, today+offset+1, hour, minute, now.Location())
+		}
+	}
+	return t.UTC(), nil
+}
+
+// wallTime returns the instant loc's clocks show hour:minute on the given
+// day (which may overflow the month, as with time.Date). time.Date leaves
+// clock times inside a daylight-saving gap unspecified; they are read with
+// the offset in force before the gap instead, which moves them forward by
+// its length.
+func wallTime(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	if t.Hour() == hour && t.Minute() == minute {
+		return t
+	}
+	_, before := t.Add(-12 * time.Hour).Zone()
+	return time.Date(year, month, day, hour, minute, 0, 0, time.FixedZone("", before)).In(loc)
+}
+
+// parseWeekday parses a full or three-letter weekday name.
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses "9am", "9:30pm", "14:00", "noon" or "midnight".
+func parseClock(s string) (hour, minute int, err error) {
+	switch s {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem = s[len(s)-2:]
+		s = s[:len(s)-2]
+	}
+
+	hourStr, minuteStr, hasMinutes := strings.Cut(s, ":")
+	hour, err = strconv.Atoi(hourStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if hasMinutes {
+		if len(minuteStr) != 2 {
+			return 0, 0, fmt.Errorf("invalid minutes")
+		}
+		if minute, err = strconv.Atoi(minuteStr); err != nil || minute > 59 {
+			return 0, 0, fmt.Errorf("invalid minutes")
+		}
+	} else if meridiem == "" {
+		// A bare number is ambiguous with a unitless duration.
+		return 0, 0, fmt.Errorf("missing am/pm or minutes")
+	}
+
+	switch meridiem {
+	case "":
+		if hour > 23 {
+			return 0, 0, fmt.Errorf("invalid hour")
+		}
+	default:
+		if hour < 1 || hour > 12 {
+			return 0, 0, fmt.Errorf("invalid hour")
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	return hour, minute, nil
+}
+
+// location returns the zone Value reads wall-clock times in.
+var location = func() *time.Location { return time.Local }
+
+// SetLocationSource sets the zone Value reads wall-clock times ("9am") in;
+// fn is called each time a flag is set. The default is the machine's zone.
+func SetLocationSource(fn func() *time.Location) {
+	location = fn
+}
+
+// Value is a flag value holding a duration that accepts everything ParseFrom
+// does, measured from when the flag is set. Its Type is "duration", so
+// FlagSet.GetDuration reads it like a standard duration flag.
+type Value time.Duration
+
+// NewValue returns a Value defaulting to d.
+func NewValue(d time.Duration) *Value {
+	v := Value(d)
+	return &v
+}
+
+// String returns the duration in time.Duration's canonical form.
+func (v *Value) String() string {
+	return time.Duration(*v).String()
+}
+
+// Set parses s with ParseFrom, reading wall-clock times in the zone from
+// SetLocationSource.
+func (v *Value) Set(s string) error {
+	d, err := ParseFrom(s, time.Now().In(location()))
+	if err != nil {
+		return err
+	}
+	*v = Value(d)
+	return nil
+}
+
+// Type reports "duration".
+func (v *Value) Type() string {
+	return "duration"
+}
+
+// Suggestions are example values offered by shell completion for duration
+// flags.
+var Suggestions = []string{"30m", "90m", "2h30m", "1d", "1w", "tomorrow 9am", "friday 9am"}
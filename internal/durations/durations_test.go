@@ -0,0 +1,221 @@
+package durations
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"90m", 90 * time.Minute, false},
+		{"2h30m", 150 * time.Minute, false},
+		{"1d", 24 * time.Hour, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"1d12h", 36 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"1.5h", 90 * time.Minute, false},
+		{"500ms", 500 * time.Millisecond, false},
+		{" 1H ", time.Hour, false},
+		{"0", 0, false},
+		{"", 0, true},
+		{"90", 0, true},
+		{"xd", 0, true},
+		{"-1h", 0, true},
+		{"1y", 0, true},
+		{"friday", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	loc := time.FixedZone("test", 2*60*60)
+	// Wednesday 2026-10-14 10:00 local.
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, loc)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, loc)
+	}
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"11am", at(14, 11, 0)},
+		{"9am", at(15, 9, 0)}, // already passed today
+		{"9:30pm", at(14, 21, 30)},
+		{"14:00", at(14, 14, 0)},
+		{"noon", at(14, 12, 0)},
+		{"midnight", at(15, 0, 0)},
+		{"12am", at(15, 0, 0)},
+		{"12pm", at(14, 12, 0)},
+		{"today 5pm", at(14, 17, 0)},
+		{"tomorrow 9am", at(15, 9, 0)},
+		{"tomorrow", at(15, 0, 0)},
+		{"friday 9am", at(16, 9, 0)},
+		{"Fri 9 am", at(16, 9, 0)},
+		{"wednesday 11am", at(14, 11, 0)},
+		{"wednesday 9am", at(21, 9, 0)}, // today's has passed
+		{"monday", at(19, 0, 0)},
+		{"2026-10-20 08:15", at(20, 8, 15)},
+		{"2026-10-20", at(20, 0, 0)},
+		{"2026-10-20T08:15:00Z", time.Date(2026, 10, 20, 8, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseTime(tt.in, now)
+			if err != nil {
+				t.Fatalf("ParseTime(%q) error = %v", tt.in, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "9", "25:00", "13pm", "0am", "9:5am", "someday", "friday at 9"} {
+		if got, err := ParseTime(in, now); err == nil {
+			t.Errorf("ParseTime(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestParseFrom(t *testing.T) {
+	now := time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC)
+
+	if d, err := ParseFrom("1d", now); err != nil || d != 24*time.Hour {
+		t.Errorf("ParseFrom(1d) = %v, %v; want 24h", d, err)
+	}
+	if d, err := ParseFrom("friday 9am", now); err != nil || d != 47*time.Hour {
+		t.Errorf("ParseFrom(friday 9am) = %v, %v; want 47h", d, err)
+	}
+	if _, err := ParseFrom("today 9am", now); err == nil {
+		t.Error("ParseFrom(today 9am) should reject a time in the past")
+	}
+}
+
+func TestValue_ReadsAsDurationFlag(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.Var(NewValue(2*time.Hour), "max", "")
+
+	if got, err := fs.GetDuration("max"); err != nil || got != 2*time.Hour {
+		t.Fatalf("default GetDuration = %v, %v; want 2h", got, err)
+	}
+	if err := fs.Parse([]string{"--max", "1d"}); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, err := fs.GetDuration("max"); err != nil || got != 24*time.Hour {
+		t.Errorf("GetDuration = %v, %v; want 24h", got, err)
+	}
+	if err := fs.Set("max", "soon"); err == nil {
+		t.Error("Set(soon) should fail")
+	}
+}
+
+func TestParseTime_DST(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	// Clocks spring forward at 2:00 on 2026-03-08 and fall back at 2:00 on
+	// 2026-11-01.
+	tests := []struct {
+		name string
+		now  time.Time
+		in   string
+		want time.Time // UTC
+		dur  time.Duration
+	}{
+		{
+			name: "tomorrow across spring forward is 23h",
+			now:  time.Date(2026, 3, 7, 9, 0, 0, 0, ny),
+			in:   "tomorrow 9am",
+			want: time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC), // 9:00 EDT
+			dur:  23 * time.Hour,
+		},
+		{
+			name: "tomorrow across fall back is 25h",
+			now:  time.Date(2026, 10, 31, 9, 0, 0, 0, ny),
+			in:   "tomorrow 9am",
+			want: time.Date(2026, 11, 1, 14, 0, 0, 0, time.UTC), // 9:00 EST
+			dur:  25 * time.Hour,
+		},
+		{
+			name: "skipped clock time moves forward by the gap",
+			now:  time.Date(2026, 3, 8, 0, 30, 0, 0, ny),
+			in:   "2:30am",
+			want: time.Date(2026, 3, 8, 7, 30, 0, 0, time.UTC), // 3:30 EDT
+			dur:  2 * time.Hour,
+		},
+		{
+			name: "repeated clock time is the first occurrence",
+			now:  time.Date(2026, 11, 1, 0, 30, 0, 0, ny),
+			in:   "1:30am",
+			want: time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), // 1:30 EDT
+			dur:  time.Hour,
+		},
+		{
+			name: "absolute date across spring forward",
+			now:  time.Date(2026, 3, 7, 12, 0, 0, 0, ny),
+			in:   "2026-03-08 12:00",
+			want: time.Date(2026, 3, 8, 16, 0, 0, 0, time.UTC),
+			dur:  23 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTime(tt.in, tt.now)
+			if err != nil {
+				t.Fatalf("ParseTime(%q) error = %v", tt.in, err)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("ParseTime(%q) location = %v, want UTC", tt.in, got.Location())
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTime(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			if d, err := ParseFrom(tt.in, tt.now); err != nil || d != tt.dur {
+				t.Errorf("ParseFrom(%q) = %v, %v; want %v", tt.in, d, err, tt.dur)
+			}
+		})
+	}
+
+	// Durations are elapsed time, DST or not.
+	if d, err := ParseFrom("1d", time.Date(2026, 3, 7, 9, 0, 0, 0, ny)); err != nil || d != 24*time.Hour {
+		t.Errorf("ParseFrom(1d) across spring forward = %v, %v; want 24h", d, err)
+	}
+}
+
+func TestValue_UsesLocationSource(t *testing.T) {
+	t.Cleanup(func() { SetLocationSource(func() *time.Location { return time.Local }) })
+	east := time.FixedZone("east", 14*60*60)
+	west := time.FixedZone("west", -12*60*60)
+
+	// The same clock reading is a different instant in each zone, 26h apart.
+	durationIn := func(loc *time.Location) time.Duration {
+		SetLocationSource(func() *time.Location { return loc })
+		v := NewValue(0)
+		if err := v.Set("2030-01-01 12:00"); err != nil {
+			t.Fatal(err)
+		}
+		return time.Duration(*v)
+	}
+	e, w := durationIn(east), durationIn(west)
+	if diff := (w - e).Round(time.Minute); diff != 26*time.Hour {
+		t.Errorf("west - east = %v, want 26h", diff)
+	}
+}
@@ -22,12 +22,12 @@ func TestDaemonRefresh(t *testing.T) {
 	// 1. Setup
 	h.StartStep("Setup", "Initialize environment and expiring profile")
 	rootDir := h.TempDir
-	pidFile := filepath.Join(os.TempDir(), "caam-daemon.pid")
-	_ = os.Remove(pidFile)
 	
 	// Setup vault
 	vaultDir := filepath.Join(rootDir, "caam", "vault")
 	h.SetEnv("XDG_DATA_HOME", rootDir)
+	pidFile := filepath.Join(rootDir, "caam", "caam-daemon.pid")
+	_ = os.Remove(pidFile)
 	
 	// Create a profile with an expiring token
 	// Daemon checks profiles in vault.
@@ -0,0 +1,171 @@
+package workflows
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/daemon"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/testkit"
+)
+
+// =============================================================================
+// E2E Tests on the testkit: fake provider, temp vault/DB and fake clock
+// =============================================================================
+
+// TestE2E_Kit_ActivationSwapsLiveAuth checks that activating swaps the live
+// token and that the vault keeps each login intact.
+func TestE2E_Kit_ActivationSwapsLiveAuth(t *testing.T) {
+	k := testkit.New(t)
+	k.AddProfile("alice")
+	k.Clock.Advance(time.Minute)
+	k.AddProfile("bob")
+
+	k.Activate("alice")
+	alice := k.LiveAuth()
+	if alice == nil || alice.Account != "alice" || k.Active() != "alice" {
+		t.Fatalf("after activating alice: live = %+v, active = %q", alice, k.Active())
+	}
+
+	k.Activate("bob")
+	if got := k.LiveAuth(); got.Account != "bob" || got.AccessToken == alice.AccessToken {
+		t.Fatalf("after activating bob: live = %+v", got)
+	}
+
+	// Switching back restores alice's token exactly as it was saved.
+	k.Activate("alice")
+	if got := k.LiveAuth(); *got != *alice {
+		t.Errorf("alice's token changed across switches: %+v, want %+v", got, alice)
+	}
+	if got := k.Provider.Logins(); got != 2 {
+		t.Errorf("Logins() = %d; switching must not log in again", got)
+	}
+}
+
+// TestE2E_Kit_RotationSkipsCooldownUntilItExpires walks a rate limit: the
+// limited profile is skipped while its cooldown runs and is eligible again
+// once the clock passes the end of it.
+func TestE2E_Kit_RotationSkipsCooldownUntilItExpires(t *testing.T) {
+	k := testkit.New(t)
+	for _, name := range []string{"a", "b", "c"} {
+		k.AddProfile(name)
+	}
+	k.Activate("a")
+
+	next, err := k.SelectNext(rotation.AlgorithmRoundRobin)
+	if err != nil || next != "b" {
+		t.Fatalf("round robin from a = %q, %v; want b", next, err)
+	}
+
+	// b hits its limit; rotation moves on to c.
+	k.SetCooldown("b", time.Hour)
+	next, err = k.SelectNext(rotation.AlgorithmRoundRobin)
+	if err != nil || next != "c" {
+		t.Fatalf("round robin with b cooling down = %q, %v; want c", next, err)
+	}
+	k.Activate("c")
+	if next, err := k.SelectNext(rotation.AlgorithmSmart); err != nil || next == "b" {
+		t.Fatalf("smart selection = %q, %v; want anything but b", next, err)
+	}
+
+	// Everything limited: selection reports it instead of picking one.
+	k.SetCooldown("a", 30*time.Minute)
+	k.SetCooldown("c", 30*time.Minute)
+	if _, err := k.SelectNext(rotation.AlgorithmSmart); !errors.Is(err, rotation.ErrAllInCooldown) {
+		t.Fatalf("selection with all in cooldown: err = %v, want ErrAllInCooldown", err)
+	}
+
+	// a and c come back first, b a half hour later.
+	k.Clock.Advance(31 * time.Minute)
+	if k.InCooldown("a") || !k.InCooldown("b") {
+		t.Fatalf("at +31m: a in cooldown = %v, b in cooldown = %v", k.InCooldown("a"), k.InCooldown("b"))
+	}
+	k.Clock.Advance(30 * time.Minute)
+	if k.InCooldown("b") {
+		t.Fatal("b should be out of cooldown after an hour")
+	}
+	next, err = k.SelectNext(rotation.AlgorithmRoundRobin)
+	if err != nil || next != "a" {
+		t.Fatalf("round robin from c after cooldowns = %q, %v; want a", next, err)
+	}
+}
+
+// TestE2E_Kit_SmartRotationAvoidsRecentlyUsed checks that the recency
+// penalty follows the clock: by the wall time the activation was long ago
+// and would earn a bonus instead.
+func TestE2E_Kit_SmartRotationAvoidsRecentlyUsed(t *testing.T) {
+	k := testkit.New(t)
+	k.AddProfile("fresh")
+	k.AddProfile("used")
+
+	k.Activate("used")
+	k.Clock.Advance(5 * time.Minute)
+
+	next, err := k.SelectNext(rotation.AlgorithmSmart)
+	if err != nil || next != "fresh" {
+		t.Fatalf("smart selection = %q, %v; want fresh (used was activated 5m ago)", next, err)
+	}
+}
+
+// TestE2E_Kit_WatchdogRevertsTimeBoxedActivation exercises the daemon's
+// revert check: a time-boxed activation switches back once it is due, and
+// is left alone if something else was activated in the meantime.
+func TestE2E_Kit_WatchdogRevertsTimeBoxedActivation(t *testing.T) {
+	k := testkit.New(t)
+	k.AddProfile("work")
+	k.AddProfile("borrowed")
+	k.Activate("work")
+
+	// 'caam activate fake borrowed --for 2h'
+	k.Activate("borrowed")
+	if err := daemon.ScheduleRevert(daemon.ScheduledRevert{
+		Tool:      k.Tool(),
+		Profile:   "borrowed",
+		RevertTo:  "work",
+		RevertAt:  k.Clock.Now().Add(2 * time.Hour),
+		CreatedAt: k.Clock.Now(),
+	}); err != nil {
+		t.Fatalf("ScheduleRevert() error = %v", err)
+	}
+
+	k.Clock.Advance(time.Hour)
+	if outcomes, err := daemon.ApplyDueReverts(k.Vault, k.Clock.Now()); err != nil || len(outcomes) != 0 {
+		t.Fatalf("revert check at +1h = %+v, %v; want nothing due", outcomes, err)
+	}
+	if k.Active() != "borrowed" {
+		t.Fatalf("active = %q before the time is up, want borrowed", k.Active())
+	}
+
+	k.Clock.Advance(time.Hour)
+	outcomes, err := daemon.ApplyDueReverts(k.Vault, k.Clock.Now())
+	if err != nil || len(outcomes) != 1 || !outcomes[0].Applied || outcomes[0].Err != nil {
+		t.Fatalf("revert check at +2h = %+v, %v; want one applied revert", outcomes, err)
+	}
+	if k.Active() != "work" {
+		t.Fatalf("active = %q after the revert, want work", k.Active())
+	}
+
+	// A revert whose profile was replaced by hand does nothing.
+	k.Activate("borrowed")
+	if err := daemon.ScheduleRevert(daemon.ScheduledRevert{
+		Tool:     k.Tool(),
+		Profile:  "borrowed",
+		RevertTo: "work",
+		RevertAt: k.Clock.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("ScheduleRevert() error = %v", err)
+	}
+	k.AddProfile("third")
+	k.Clock.Advance(2 * time.Hour)
+	outcomes, err = daemon.ApplyDueReverts(k.Vault, k.Clock.Now())
+	if err != nil || len(outcomes) != 1 || outcomes[0].Applied {
+		t.Fatalf("revert of a replaced activation = %+v, %v; want one skipped revert", outcomes, err)
+	}
+	if k.Active() != "third" {
+		t.Errorf("active = %q, want third (left alone)", k.Active())
+	}
+	if pending, err := daemon.LoadReverts(); err != nil || len(pending) != 0 {
+		t.Errorf("pending reverts = %+v, %v; want none", pending, err)
+	}
+}
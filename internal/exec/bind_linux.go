@@ -0,0 +1,45 @@
+//go:build linux
+
+package exec
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// bindSysProcAttr starts the helper in new user and mount namespaces. The
+// caller's uid/gid map to themselves so files keep their real ownership.
+func bindSysProcAttr() (*syscall.SysProcAttr, error) {
+	return &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+	}, nil
+}
+
+// execBound applies mounts in the current (private) mount namespace and
+// replaces the process with bin.
+func execBound(mounts []BindMount, bin string, args []string) error {
+	// Keep our mounts from propagating back to the parent namespace.
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("make mounts private: %w", err)
+	}
+	for _, m := range mounts {
+		if err := syscall.Mount(m.Source, m.Target, "", syscall.MS_BIND, ""); err != nil {
+			return fmt.Errorf("bind %s over %s: %w", m.Source, m.Target, err)
+		}
+	}
+
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return fmt.Errorf("find %s: %w", bin, err)
+	}
+	return syscall.Exec(path, append([]string{bin}, args...), os.Environ())
+}
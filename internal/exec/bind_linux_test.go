@@ -0,0 +1,84 @@
+//go:build linux
+
+package exec
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// TestBindHelperProcess isn't a real test; it stands in for the caam binary
+// when TestBindMounts re-executes the test binary as the bind helper.
+func TestBindHelperProcess(t *testing.T) {
+	if os.Getenv("CAAM_WANT_BIND_HELPER") != "1" {
+		return
+	}
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	err := RunBindHelper(args)
+	os.Stderr.WriteString(err.Error() + "\n")
+	os.Exit(2)
+}
+
+func TestBindMounts(t *testing.T) {
+	tmpDir := t.TempDir()
+	src := filepath.Join(tmpDir, "vault-auth.json")
+	dst := filepath.Join(tmpDir, "auth.json")
+	if err := os.WriteFile(src, []byte("vault"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, []byte("global"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	attr, err := bindSysProcAttr()
+	if err != nil {
+		t.Fatal(err)
+	}
+	helperArgs := append([]string{"-test.run=TestBindHelperProcess", "--"},
+		bindHelperArgs([]BindMount{{Source: src, Target: dst}}, "sh", []string{"-c", `cat "$0"; printf refreshed > "$0"`, dst})[1:]...)
+	cmd := exec.Command(os.Args[0], helperArgs...)
+	cmd.Env = append(os.Environ(), "CAAM_WANT_BIND_HELPER=1")
+	cmd.SysProcAttr = attr
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if errors.Is(err, syscall.EPERM) || errors.Is(err, syscall.EINVAL) || strings.Contains(string(out), "operation not permitted") {
+			t.Skipf("user namespaces unavailable: %v %s", err, out)
+		}
+		t.Fatalf("helper failed: %v\n%s", err, out)
+	}
+
+	if string(out) != "vault" {
+		t.Errorf("tool saw %q, want the vault file", out)
+	}
+	if got, _ := os.ReadFile(dst); string(got) != "global" {
+		t.Errorf("global file = %q, want it untouched", got)
+	}
+	if got, _ := os.ReadFile(src); string(got) != "refreshed" {
+		t.Errorf("vault file = %q, want the tool's write", got)
+	}
+}
+
+func TestRunBindHelper_Usage(t *testing.T) {
+	for _, args := range [][]string{
+		nil,
+		{"/src", "/dst"},
+		{"/src", "--", "sh"},
+		{"/src", "/dst", "--"},
+	} {
+		if err := RunBindHelper(args); err == nil || !strings.Contains(err.Error(), "usage") {
+			t.Errorf("RunBindHelper(%q) = %v, want usage error", args, err)
+		}
+	}
+}
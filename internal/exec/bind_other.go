@@ -0,0 +1,13 @@
+//go:build !linux
+
+package exec
+
+import "syscall"
+
+func bindSysProcAttr() (*syscall.SysProcAttr, error) {
+	return nil, ErrBindUnsupported
+}
+
+func execBound(mounts []BindMount, bin string, args []string) error {
+	return ErrBindUnsupported
+}
@@ -3,6 +3,7 @@ package exec
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -59,6 +60,56 @@ type RunOptions struct {
 	// to use the global user environment. This is required for vault-based
 	// auth file swapping (caam run).
 	UseGlobalEnv bool
+
+	// BindMounts, if set, runs the tool in a private Linux mount namespace
+	// with each Source bind-mounted over its Target. Only the spawned process
+	// sees the mounts; the global files stay as they are.
+	BindMounts []BindMount
+}
+
+// BindMount makes Source visible at Target inside the tool's mount namespace.
+type BindMount struct {
+	Source string
+	Target string
+}
+
+// ErrBindUnsupported is returned when bind mounts are requested on a platform
+// without Linux user namespaces.
+var ErrBindUnsupported = errors.New("bind mounts require Linux user namespaces")
+
+// BindHelperCommand is the hidden caam subcommand that runs inside the new
+// namespaces, applies the bind mounts and then execs the tool. Its arguments
+// are "<source> <target>... -- <bin> [args...]".
+const BindHelperCommand = "__bind-exec"
+
+// RunBindHelper implements BindHelperCommand. On success it does not return.
+func RunBindHelper(args []string) error {
+	sep := -1
+	for i, arg := range args {
+		if arg == "--" {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 || sep%2 != 0 || sep+1 >= len(args) {
+		return fmt.Errorf("usage: %s <source> <target>... -- <bin> [args...]", BindHelperCommand)
+	}
+
+	mounts := make([]BindMount, 0, sep/2)
+	for i := 0; i < sep; i += 2 {
+		mounts = append(mounts, BindMount{Source: args[i], Target: args[i+1]})
+	}
+	return execBound(mounts, args[sep+1], args[sep+2:])
+}
+
+// bindHelperArgs builds the BindHelperCommand invocation that runs bin.
+func bindHelperArgs(mounts []BindMount, bin string, args []string) []string {
+	out := []string{BindHelperCommand}
+	for _, m := range mounts {
+		out = append(out, m.Source, m.Target)
+	}
+	out = append(out, "--", bin)
+	return append(out, args...)
 }
 
 // ExitCodeError wraps a process exit code.
@@ -94,6 +145,18 @@ func (r *Runner) Run(ctx context.Context, opts RunOptions) error {
 	// Build command
 	bin := opts.Provider.DefaultBin()
 	cmd := exec.CommandContext(ctx, bin, opts.Args...)
+	if len(opts.BindMounts) > 0 {
+		attr, err := bindSysProcAttr()
+		if err != nil {
+			return err
+		}
+		self, err := os.Executable()
+		if err != nil {
+			return fmt.Errorf("find caam executable: %w", err)
+		}
+		cmd = exec.CommandContext(ctx, self, bindHelperArgs(opts.BindMounts, bin, opts.Args)...)
+		cmd.SysProcAttr = attr
+	}
 
 	// Set up environment with deduplication (last one wins in our map logic)
 	envMap := make(map[string]string)
@@ -157,11 +157,23 @@ func (r *SmartRunner) Run(ctx context.Context, opts RunOptions) (err error) {
 				DurationSeconds: int(duration.Seconds()),
 				ExitCode:        finalCode,
 				RateLimitHit:    r.handoffCount > 0,
+				WorkDir:         opts.WorkDir,
+			}
+			if session.WorkDir == "" {
+				session.WorkDir, _ = os.Getwd()
 			}
 			if r.handoffCount > 0 {
 				session.Notes = fmt.Sprintf("handoffs: %d", r.handoffCount)
 			}
 			_ = r.db.RecordWrapSession(session)
+			if finalCode == 0 {
+				// A clean exit means the provider accepted this login.
+				_ = r.db.Log(caamdb.Event{
+					Type:        caamdb.EventVerified,
+					Provider:    opts.Provider.ID(),
+					ProfileName: r.currentProfile,
+				})
+			}
 		}
 	}()
 
@@ -298,7 +310,26 @@ func (r *SmartRunner) handleRateLimit(ctx context.Context) {
 		}
 	}()
 
-	// 2. Select best backup profile
+	// 2. Mark current profile as in cooldown before selecting a backup, so
+	// profiles that share its account (which the database cools down too)
+	// aren't picked as the replacement.
+	cooldownDuration := r.cooldownDuration
+	if cooldownDuration == 0 {
+		cooldownDuration = 60 * time.Minute
+	}
+	if r.authPool != nil {
+		r.authPool.SetCooldown(r.loginHandler.Provider(), r.currentProfile, cooldownDuration)
+	}
+	if r.db != nil {
+		ev, err := r.db.SetCooldown(r.loginHandler.Provider(), r.currentProfile, time.Now(), cooldownDuration, "auto-detected via SmartRunner")
+		if err == nil && r.authPool != nil {
+			for _, sibling := range ev.PropagatedTo {
+				r.authPool.SetCooldown(r.loginHandler.Provider(), sibling, cooldownDuration)
+			}
+		}
+	}
+
+	// 3. Select best backup profile
 	r.setState(SelectingBackup)
 
 	// Get all profiles
@@ -323,18 +354,6 @@ func (r *SmartRunner) handleRateLimit(ctx context.Context) {
 
 	r.notifyHandoff(r.currentProfile, nextProfile)
 
-	// 3. Mark current profile as in cooldown (if authPool is available)
-	cooldownDuration := r.cooldownDuration
-	if cooldownDuration == 0 {
-		cooldownDuration = 60 * time.Minute
-	}
-	if r.authPool != nil {
-		r.authPool.SetCooldown(r.loginHandler.Provider(), r.currentProfile, cooldownDuration)
-	}
-	if r.db != nil {
-		r.db.SetCooldown(r.loginHandler.Provider(), r.currentProfile, time.Now(), cooldownDuration, "auto-detected via SmartRunner")
-	}
-
 	// 4. Swap auth files
 	r.setState(SwappingAuth)
 	if err := r.vault.Restore(fileSet, nextProfile); err != nil {
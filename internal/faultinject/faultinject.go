@@ -0,0 +1,224 @@
+// Package faultinject makes chosen filesystem and database steps fail on
+// purpose, so the rollback paths around them can be exercised. It is off
+// unless CAAM_FAULT_INJECT is set or a caller uses Enable, and Check is a
+// single atomic load while it is off.
+//
+//	CAAM_FAULT_INJECT="copy=0.2,rename=0.1,db=0.05,seed=7"
+//
+// Each op's value is the probability that one of its steps fails; seed makes
+// the sequence of failures repeatable. It is a testing aid: nothing in caam
+// documents or relies on it outside 'caam selftest --chaos'.
+package faultinject
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EnvVar is the environment variable that turns fault injection on.
+const EnvVar = "CAAM_FAULT_INJECT"
+
+// Op names a kind of step that can be made to fail.
+type Op string
+
+const (
+	// OpCopy is writing the copy of a file before it is published.
+	OpCopy Op = "copy"
+	// OpRename is publishing a temp file or link by renaming it into place.
+	OpRename Op = "rename"
+	// OpDB is a write inside a database transaction.
+	OpDB Op = "db"
+)
+
+// Ops lists every op, in the order they're reported.
+var Ops = []Op{OpCopy, OpRename, OpDB}
+
+// ErrInjected is wrapped by every injected failure.
+var ErrInjected = errors.New("injected fault")
+
+// Config is a parsed fault injection spec.
+type Config struct {
+	// Rates maps an op to the probability, 0 to 1, that a step fails.
+	Rates map[Op]float64
+	// Seed seeds the random source; 0 picks one from the clock.
+	Seed int64
+}
+
+// String formats c the way Parse reads it.
+func (c Config) String() string {
+	var parts []string
+	for _, op := range Ops {
+		if rate, ok := c.Rates[op]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%g", op, rate))
+		}
+	}
+	if c.Seed != 0 {
+		parts = append(parts, fmt.Sprintf("seed=%d", c.Seed))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Parse reads a spec such as "copy=0.2,rename=0.1,db=0.05,seed=7". "all=R"
+// sets every op's rate.
+func Parse(spec string) (Config, error) {
+	cfg := Config{Rates: make(map[Op]float64)}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("%q: want op=rate", part)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if key == "seed" {
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return Config{}, fmt.Errorf("seed %q: %w", value, err)
+			}
+			cfg.Seed = seed
+			continue
+		}
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return Config{}, fmt.Errorf("%s rate %q: want a number from 0 to 1", key, value)
+		}
+		if key == "all" {
+			for _, op := range Ops {
+				cfg.Rates[op] = rate
+			}
+			continue
+		}
+		if !knownOp(Op(key)) {
+			return Config{}, fmt.Errorf("unknown op %q (want %s)", key, opNames())
+		}
+		cfg.Rates[Op(key)] = rate
+	}
+	return cfg, nil
+}
+
+func knownOp(op Op) bool {
+	for _, known := range Ops {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+func opNames() string {
+	names := make([]string, len(Ops))
+	for i, op := range Ops {
+		names[i] = string(op)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+type state struct {
+	cfg    Config
+	rng    *rand.Rand
+	counts map[Op]int
+}
+
+var (
+	enabled atomic.Bool
+	envOnce sync.Once
+
+	mu      sync.Mutex
+	current *state
+)
+
+func newState(cfg Config) *state {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &state{cfg: cfg, rng: rand.New(rand.NewSource(seed)), counts: make(map[Op]int)}
+}
+
+// loadEnv turns injection on from EnvVar, once per process. It says so on
+// stderr, since a leftover variable would otherwise look like caam breaking.
+func loadEnv() {
+	spec := strings.TrimSpace(os.Getenv(EnvVar))
+	if spec == "" {
+		return
+	}
+	cfg, err := Parse(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "caam: ignoring %s: %v\n", EnvVar, err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "caam: fault injection on (%s=%s)\n", EnvVar, cfg)
+	mu.Lock()
+	current = newState(cfg)
+	mu.Unlock()
+	enabled.Store(true)
+}
+
+// Enable turns injection on with cfg, replacing any spec from the
+// environment, and returns a func that puts the previous state back.
+func Enable(cfg Config) (restore func()) {
+	envOnce.Do(loadEnv)
+	mu.Lock()
+	prev, prevEnabled := current, enabled.Load()
+	current = newState(cfg)
+	mu.Unlock()
+	enabled.Store(true)
+	return func() {
+		mu.Lock()
+		current = prev
+		mu.Unlock()
+		enabled.Store(prevEnabled)
+	}
+}
+
+// Enabled reports whether injection is on.
+func Enabled() bool {
+	envOnce.Do(loadEnv)
+	return enabled.Load()
+}
+
+// Check returns an error wrapping ErrInjected when op's step should fail,
+// and nil otherwise.
+func Check(op Op) error {
+	envOnce.Do(loadEnv)
+	if !enabled.Load() {
+		return nil
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if current == nil {
+		return nil
+	}
+	rate := current.cfg.Rates[op]
+	if rate <= 0 || current.rng.Float64() >= rate {
+		return nil
+	}
+	current.counts[op]++
+	return fmt.Errorf("%w: %s", ErrInjected, op)
+}
+
+// Counts returns how many faults of each op have been injected since
+// injection was last turned on.
+func Counts() map[Op]int {
+	mu.Lock()
+	defer mu.Unlock()
+	counts := make(map[Op]int)
+	if current != nil {
+		for op, n := range current.counts {
+			counts[op] = n
+		}
+	}
+	return counts
+}
@@ -0,0 +1,85 @@
+package faultinject
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse(" copy=0.2, rename=0.1 ,db=0,seed=7")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if cfg.Rates[OpCopy] != 0.2 || cfg.Rates[OpRename] != 0.1 || cfg.Rates[OpDB] != 0 || cfg.Seed != 7 {
+		t.Errorf("Parse() = %+v", cfg)
+	}
+	if got := cfg.String(); got != "copy=0.2,rename=0.1,db=0,seed=7" {
+		t.Errorf("String() = %q", got)
+	}
+
+	all, err := Parse("all=0.5")
+	if err != nil || len(all.Rates) != len(Ops) {
+		t.Errorf("Parse(all) = %+v, %v; want every op", all, err)
+	}
+
+	for _, bad := range []string{"copy", "copy=2", "copy=-1", "copy=x", "unlink=0.1", "seed=x"} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(OpCopy); err != nil {
+		t.Fatalf("Check() with injection off = %v", err)
+	}
+
+	restore := Enable(Config{Rates: map[Op]float64{OpCopy: 1, OpRename: 0.5}, Seed: 1})
+	for i := 0; i < 10; i++ {
+		if err := Check(OpCopy); !errors.Is(err, ErrInjected) {
+			t.Fatalf("Check(copy) at rate 1 = %v, want ErrInjected", err)
+		}
+		if err := Check(OpDB); err != nil {
+			t.Fatalf("Check(db) with no rate = %v", err)
+		}
+	}
+	renames := 0
+	for i := 0; i < 200; i++ {
+		if Check(OpRename) != nil {
+			renames++
+		}
+	}
+	if renames == 0 || renames == 200 {
+		t.Errorf("Check(rename) at rate 0.5 failed %d/200 times", renames)
+	}
+	counts := Counts()
+	if counts[OpCopy] != 10 || counts[OpRename] != renames || counts[OpDB] != 0 {
+		t.Errorf("Counts() = %v", counts)
+	}
+
+	restore()
+	if Enabled() {
+		t.Fatal("Enabled() after restore = true")
+	}
+	if err := Check(OpCopy); err != nil {
+		t.Errorf("Check() after restore = %v", err)
+	}
+}
+
+func TestCheck_SeedRepeats(t *testing.T) {
+	run := func() []bool {
+		restore := Enable(Config{Rates: map[Op]float64{OpDB: 0.3}, Seed: 42})
+		defer restore()
+		var got []bool
+		for i := 0; i < 50; i++ {
+			got = append(got, Check(OpDB) != nil)
+		}
+		return got
+	}
+	first, second := run(), run()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("same seed gave different failures at step %d", i)
+		}
+	}
+}
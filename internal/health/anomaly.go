@@ -0,0 +1,260 @@
+package health
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AnomalyKind classifies a suspicious auth change.
+type AnomalyKind string
+
+const (
+	// AnomalyUnknownCredential means the live auth that matched a profile
+	// was replaced by a credential for another identity that was never
+	// backed up.
+	AnomalyUnknownCredential AnomalyKind = "unknown_credential"
+
+	// AnomalyExpiryRegressed means a profile's token expiry moved backwards,
+	// as when an older token is written over a newer one.
+	AnomalyExpiryRegressed AnomalyKind = "expiry_regressed"
+
+	// AnomalyNewMachine means a profile was activated on a machine not seen
+	// before.
+	AnomalyNewMachine AnomalyKind = "new_machine"
+)
+
+// expiryRegressionTolerance ignores clock noise between expiry sources.
+const expiryRegressionTolerance = time.Minute
+
+// maxAnomalies bounds the stored history; the oldest acknowledged entries
+// are dropped first.
+const maxAnomalies = 100
+
+// Anomaly is a suspicious auth change awaiting acknowledgement.
+type Anomaly struct {
+	// ID is a short stable identifier for 'caam ack'.
+	ID string `json:"id"`
+
+	Kind     AnomalyKind `json:"kind"`
+	Provider string      `json:"provider"`
+	Profile  string      `json:"profile,omitempty"`
+	Detail   string      `json:"detail"`
+
+	// Key identifies the underlying event; an anomaly is raised once per
+	// key, so acknowledged events aren't raised again.
+	Key string `json:"key"`
+
+	DetectedAt time.Time `json:"detected_at"`
+	AckedAt    time.Time `json:"acked_at,omitempty"`
+}
+
+// Acked reports whether the anomaly has been acknowledged.
+func (a Anomaly) Acked() bool {
+	return !a.AckedAt.IsZero()
+}
+
+// LiveAuthObservation records the live auth files of a tool as last seen.
+type LiveAuthObservation struct {
+	// Fingerprint is a hash of the live auth file contents.
+	Fingerprint string `json:"fingerprint"`
+
+	// Profile is the vault profile the files matched, if any.
+	Profile string `json:"profile,omitempty"`
+
+	// Identity is the account (email or id) the credential belongs to.
+	Identity string `json:"identity,omitempty"`
+
+	ObservedAt time.Time `json:"observed_at"`
+}
+
+// anomalyID derives a short id from key.
+func anomalyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:4])
+}
+
+// RecordAnomaly stores a, unless an anomaly with the same key was already
+// recorded. It reports whether a was new.
+func (s *Storage) RecordAnomaly(a Anomaly) (bool, error) {
+	if a.Key == "" {
+		return false, fmt.Errorf("anomaly key is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.acquireFileLock()
+	if err != nil {
+		return false, err
+	}
+	defer s.releaseFileLock(f)
+
+	store, err := s.loadLocked()
+	if err != nil {
+		return false, err
+	}
+
+	for _, existing := range store.Anomalies {
+		if existing.Key == a.Key {
+			return false, nil
+		}
+	}
+
+	a.ID = anomalyID(a.Key)
+	if a.DetectedAt.IsZero() {
+		a.DetectedAt = time.Now()
+	}
+	a.AckedAt = time.Time{}
+	store.Anomalies = append(store.Anomalies, a)
+
+	for i := 0; len(store.Anomalies) > maxAnomalies && i < len(store.Anomalies); {
+		if store.Anomalies[i].Acked() {
+			store.Anomalies = append(store.Anomalies[:i], store.Anomalies[i+1:]...)
+			continue
+		}
+		i++
+	}
+
+	return true, s.saveLocked(store)
+}
+
+// Anomalies returns the recorded anomalies, oldest first. Acknowledged ones
+// are included only with includeAcked.
+func (s *Storage) Anomalies(includeAcked bool) ([]Anomaly, error) {
+	store, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	var out []Anomaly
+	for _, a := range store.Anomalies {
+		if includeAcked || !a.Acked() {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+// AckAnomalies acknowledges the pending anomalies with the given ids (or id
+// prefixes), or every pending anomaly when no ids are given. It returns the
+// anomalies acknowledged and fails without changes if an id matches none.
+func (s *Storage) AckAnomalies(ids ...string) ([]Anomaly, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.acquireFileLock()
+	if err != nil {
+		return nil, err
+	}
+	defer s.releaseFileLock(f)
+
+	store, err := s.loadLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int]bool)
+	for i, a := range store.Anomalies {
+		if !a.Acked() && len(ids) == 0 {
+			selected[i] = true
+		}
+	}
+	for _, id := range ids {
+		id = strings.ToLower(strings.TrimSpace(id))
+		matched := false
+		for i, a := range store.Anomalies {
+			if id != "" && !a.Acked() && strings.HasPrefix(a.ID, id) {
+				selected[i] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no pending anomaly %q", id)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var acked []Anomaly
+	for i := range store.Anomalies {
+		if selected[i] {
+			store.Anomalies[i].AckedAt = now
+			acked = append(acked, store.Anomalies[i])
+		}
+	}
+	return acked, s.saveLocked(store)
+}
+
+// ObserveTokenExpiry records expiresAt as seen for a profile. It reports the
+// previously seen expiry when expiresAt is earlier than it, beyond clock
+// noise. The earlier expiry then becomes the baseline, so one regression is
+// reported once.
+func (s *Storage) ObserveTokenExpiry(provider, name string, expiresAt time.Time) (prev time.Time, regressed bool, err error) {
+	if expiresAt.IsZero() {
+		return time.Time{}, false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.acquireFileLock()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	defer s.releaseFileLock(f)
+
+	store, err := s.loadLocked()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	key := profileKey(provider, name)
+	health := store.Profiles[key]
+	if health == nil {
+		health = &ProfileHealth{}
+		store.Profiles[key] = health
+	}
+
+	prev = health.ExpirySeen
+	regressed = !prev.IsZero() && expiresAt.Before(prev.Add(-expiryRegressionTolerance))
+	if !regressed && !prev.IsZero() && !expiresAt.After(prev) {
+		return prev, false, nil
+	}
+	health.ExpirySeen = expiresAt
+	return prev, regressed, s.saveLocked(store)
+}
+
+// ObserveLiveAuth records obs as tool's current live auth state and returns
+// the previous observation, or nil on the first.
+func (s *Storage) ObserveLiveAuth(tool string, obs LiveAuthObservation) (*LiveAuthObservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.acquireFileLock()
+	if err != nil {
+		return nil, err
+	}
+	defer s.releaseFileLock(f)
+
+	store, err := s.loadLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	prev := store.LiveAuth[tool]
+	if prev != nil && prev.Fingerprint == obs.Fingerprint && prev.Profile == obs.Profile {
+		return prev, nil
+	}
+	if store.LiveAuth == nil {
+		store.LiveAuth = make(map[string]*LiveAuthObservation)
+	}
+	if obs.ObservedAt.IsZero() {
+		obs.ObservedAt = time.Now()
+	}
+	store.LiveAuth[tool] = &obs
+	return prev, s.saveLocked(store)
+}
@@ -0,0 +1,99 @@
+package health
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStorage_RecordAndAckAnomalies(t *testing.T) {
+	storage := NewStorage(filepath.Join(t.TempDir(), "health.json"))
+
+	a := Anomaly{Kind: AnomalyNewMachine, Provider: "claude", Profile: "work", Detail: "activated on laptop", Key: "new_machine|m1"}
+	added, err := storage.RecordAnomaly(a)
+	if err != nil || !added {
+		t.Fatalf("RecordAnomaly() = %v, %v; want added", added, err)
+	}
+	if added, _ := storage.RecordAnomaly(a); added {
+		t.Error("an anomaly with the same key should be recorded once")
+	}
+	if _, err := storage.RecordAnomaly(Anomaly{Kind: AnomalyExpiryRegressed, Provider: "codex", Key: "expiry|x"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := storage.Anomalies(false)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("Anomalies() = %v, %v; want 2 pending", pending, err)
+	}
+	if pending[0].ID == "" || pending[0].ID != anomalyID("new_machine|m1") {
+		t.Errorf("ID = %q, want a stable id derived from the key", pending[0].ID)
+	}
+
+	if _, err := storage.AckAnomalies("zzzz"); err == nil {
+		t.Error("acking an unknown id should fail")
+	}
+	acked, err := storage.AckAnomalies(pending[0].ID[:4])
+	if err != nil || len(acked) != 1 || acked[0].Key != "new_machine|m1" {
+		t.Fatalf("AckAnomalies(prefix) = %v, %v", acked, err)
+	}
+
+	// Acknowledged anomalies aren't raised again.
+	if added, _ := storage.RecordAnomaly(a); added {
+		t.Error("an acknowledged anomaly should not be raised again")
+	}
+	if pending, _ := storage.Anomalies(false); len(pending) != 1 {
+		t.Errorf("pending = %v, want 1", pending)
+	}
+	if all, _ := storage.Anomalies(true); len(all) != 2 {
+		t.Errorf("all = %v, want 2", all)
+	}
+
+	acked, err = storage.AckAnomalies()
+	if err != nil || len(acked) != 1 {
+		t.Fatalf("AckAnomalies() = %v, %v; want the remaining one", acked, err)
+	}
+	if pending, _ := storage.Anomalies(false); len(pending) != 0 {
+		t.Errorf("pending after ack all = %v", pending)
+	}
+}
+
+func TestStorage_ObserveTokenExpiry(t *testing.T) {
+	storage := NewStorage(filepath.Join(t.TempDir(), "health.json"))
+	base := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+
+	if _, regressed, err := storage.ObserveTokenExpiry("claude", "work", base); err != nil || regressed {
+		t.Fatalf("first observation = %v, %v", regressed, err)
+	}
+	if _, regressed, _ := storage.ObserveTokenExpiry("claude", "work", base.Add(time.Hour)); regressed {
+		t.Error("a later expiry is not a regression")
+	}
+	if _, regressed, _ := storage.ObserveTokenExpiry("claude", "work", base.Add(time.Hour-30*time.Second)); regressed {
+		t.Error("differences within the tolerance are not a regression")
+	}
+
+	prev, regressed, err := storage.ObserveTokenExpiry("claude", "work", base)
+	if err != nil || !regressed || !prev.Equal(base.Add(time.Hour)) {
+		t.Fatalf("ObserveTokenExpiry(earlier) = %v, %v, %v; want regression from +1h", prev, regressed, err)
+	}
+	// The earlier expiry is the new baseline.
+	if _, regressed, _ := storage.ObserveTokenExpiry("claude", "work", base); regressed {
+		t.Error("the same regression should be reported once")
+	}
+}
+
+func TestStorage_ObserveLiveAuth(t *testing.T) {
+	storage := NewStorage(filepath.Join(t.TempDir(), "health.json"))
+
+	prev, err := storage.ObserveLiveAuth("codex", LiveAuthObservation{Fingerprint: "a", Profile: "work"})
+	if err != nil || prev != nil {
+		t.Fatalf("first ObserveLiveAuth() = %v, %v; want nil", prev, err)
+	}
+	prev, err = storage.ObserveLiveAuth("codex", LiveAuthObservation{Fingerprint: "b"})
+	if err != nil || prev == nil || prev.Profile != "work" || prev.Fingerprint != "a" {
+		t.Fatalf("ObserveLiveAuth() previous = %+v, %v", prev, err)
+	}
+	prev, _ = storage.ObserveLiveAuth("codex", LiveAuthObservation{Fingerprint: "b"})
+	if prev == nil || prev.Fingerprint != "b" {
+		t.Errorf("previous = %+v, want the stored unknown state", prev)
+	}
+}
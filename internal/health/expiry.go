@@ -203,6 +203,8 @@ func parseClaudeCredentialsFile(path string) (*ExpiryInfo, error) {
 //	  "expires_at": 1734451200,  // Unix timestamp (seconds)
 //	  "token_type": "Bearer"
 //	}
+//
+// Current Codex CLI versions nest the same fields under a "tokens" object.
 func ParseCodexExpiry(authPath string) (*ExpiryInfo, error) {
 	if authPath == "" {
 		codexHome := os.Getenv("CODEX_HOME")
@@ -214,6 +216,9 @@ func ParseCodexExpiry(authPath string) (*ExpiryInfo, error) {
 	}
 
 	info, err := parseOAuthFile(authPath)
+	if errors.Is(err, ErrNoExpiry) {
+		info, err = parseCodexTokens(authPath)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, ErrNoAuthFile
@@ -225,6 +230,25 @@ func ParseCodexExpiry(authPath string) (*ExpiryInfo, error) {
 	return info, nil
 }
 
+// parseCodexTokens extracts expiry info from the nested "tokens" object of a
+// Codex auth file.
+func parseCodexTokens(path string) (*ExpiryInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var auth struct {
+		Tokens json.RawMessage `json:"tokens"`
+	}
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	if len(auth.Tokens) == 0 || string(auth.Tokens) == "null" {
+		return nil, ErrNoExpiry
+	}
+	return parseOAuthJSON(auth.Tokens)
+}
+
 // ParseGeminiExpiry extracts token expiry from Gemini CLI auth files.
 //
 // Gemini CLI stores auth in:
@@ -336,7 +360,11 @@ func parseOAuthFile(path string) (*ExpiryInfo, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseOAuthJSON(data)
+}
 
+// parseOAuthJSON extracts expiry info from an OAuth token object.
+func parseOAuthJSON(data []byte) (*ExpiryInfo, error) {
 	var oauth oauthJSON
 	if err := json.Unmarshal(data, &oauth); err != nil {
 		return nil, fmt.Errorf("parse JSON: %w", err)
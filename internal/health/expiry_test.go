@@ -309,6 +309,32 @@ func TestParseCodexExpiry(t *testing.T) {
 	}
 }
 
+func TestParseCodexExpiry_NestedTokens(t *testing.T) {
+	authPath := filepath.Join(t.TempDir(), "auth.json")
+	authData := `{
+		"OPENAI_API_KEY": null,
+		"tokens": {
+			"access_token": "test_access",
+			"refresh_token": "test_refresh",
+			"expires_at": 1734523200
+		}
+	}`
+	if err := os.WriteFile(authPath, []byte(authData), 0600); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	info, err := ParseCodexExpiry(authPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !info.HasRefreshToken {
+		t.Error("expected HasRefreshToken to be true")
+	}
+	if !info.ExpiresAt.Equal(time.Unix(1734523200, 0)) {
+		t.Errorf("ExpiresAt = %v, want %v", info.ExpiresAt, time.Unix(1734523200, 0))
+	}
+}
+
 func TestParseClaudeExpiry(t *testing.T) {
 	// Create a temp directory structure
 	tmpDir := t.TempDir()
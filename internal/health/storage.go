@@ -39,6 +39,10 @@ type ProfileHealth struct {
 
 	// LastChecked is when health was last verified.
 	LastChecked time.Time `json:"last_checked,omitempty"`
+
+	// ExpirySeen is the latest token expiry observed for the profile, used
+	// to notice an expiry that jumps backwards (see ObserveTokenExpiry).
+	ExpirySeen time.Time `json:"expiry_seen,omitempty"`
 }
 
 // HealthStore holds health data for all profiles.
@@ -51,6 +55,12 @@ type HealthStore struct {
 
 	// UpdatedAt is when the store was last modified.
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// Anomalies are suspicious auth changes, pending until acknowledged.
+	Anomalies []Anomaly `json:"anomalies,omitempty"`
+
+	// LiveAuth maps each tool to the last live auth state observed.
+	LiveAuth map[string]*LiveAuthObservation `json:"live_auth,omitempty"`
 }
 
 // Storage manages health metadata persistence.
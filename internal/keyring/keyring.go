@@ -0,0 +1,253 @@
+// Package keyring stores small secrets in the operating system's credential
+// store: the login keychain on macOS (through security(1)) and the Secret
+// Service on Linux (through secret-tool(1) from libsecret). Other platforms,
+// and hosts without those tools, get a backend that reports ErrUnavailable so
+// callers can fall back to prompting.
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when no secret is stored for a service and account.
+var ErrNotFound = errors.New("secret not found in keyring")
+
+// ErrUnavailable is returned when the host has no usable credential store.
+var ErrUnavailable = errors.New("no OS keyring available")
+
+// Backend is a credential store. Secrets are addressed by service and
+// account, the way both the macOS keychain and the Secret Service key them.
+type Backend interface {
+	// Name describes the store, e.g. "macOS keychain".
+	Name() string
+	Get(service, account string) (string, error)
+	Set(service, account, secret string) error
+	Delete(service, account string) error
+}
+
+// System returns the credential store of the running OS.
+func System() Backend {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return &commandBackend{name: "macOS keychain", run: runCommand, tool: macOSTool{}}
+		}
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return &commandBackend{name: "Secret Service", run: runCommand, tool: secretTool{}}
+		}
+	}
+	return unavailable{}
+}
+
+// runner executes name with args, feeding stdin, and returns its stdout and
+// exit code. A non-nil error means the command could not be run at all.
+type runner func(stdin, name string, args ...string) (string, int, error)
+
+func runCommand(stdin, name string, args ...string) (string, int, error) {
+	c := exec.Command(name, args...)
+	if stdin != "" {
+		c.Stdin = strings.NewReader(stdin)
+	}
+	var stdout bytes.Buffer
+	c.Stdout = &stdout
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.String(), exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return "", -1, err
+	}
+	return stdout.String(), 0, nil
+}
+
+// tool builds the command lines of a CLI credential store.
+type tool interface {
+	get(service, account string) []string
+	// set returns the command line and the stdin carrying the secret. The
+	// secret never goes on the command line, where other local users can
+	// read it from ps or /proc.
+	set(service, account, secret string) ([]string, string, error)
+	del(service, account string) []string
+	// notFound reports whether an exit code means "no such secret".
+	notFound(code int) bool
+	// readBack reports whether a set must be confirmed with a get, for a
+	// tool whose exit status doesn't say whether it stored the secret.
+	readBack() bool
+}
+
+type commandBackend struct {
+	name string
+	run  runner
+	tool tool
+}
+
+func (b *commandBackend) Name() string { return b.name }
+
+func (b *commandBackend) Get(service, account string) (string, error) {
+	argv := b.tool.get(service, account)
+	out, code, err := b.run("", argv[0], argv[1:]...)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", b.name, err)
+	}
+	if code != 0 {
+		if b.tool.notFound(code) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("%s: %s exited with status %d", b.name, argv[0], code)
+	}
+	secret := strings.TrimRight(out, "\r\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (b *commandBackend) Set(service, account, secret string) error {
+	argv, stdin, err := b.tool.set(service, account, secret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", b.name, err)
+	}
+	_, code, err := b.run(stdin, argv[0], argv[1:]...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", b.name, err)
+	}
+	if code != 0 {
+		return fmt.Errorf("%s: %s exited with status %d", b.name, argv[0], code)
+	}
+	if b.tool.readBack() {
+		if got, err := b.Get(service, account); err != nil || got != secret {
+			return fmt.Errorf("%s: %s did not store the secret", b.name, argv[0])
+		}
+	}
+	return nil
+}
+
+func (b *commandBackend) Delete(service, account string) error {
+	argv := b.tool.del(service, account)
+	_, code, err := b.run("", argv[0], argv[1:]...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", b.name, err)
+	}
+	if code != 0 {
+		if b.tool.notFound(code) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("%s: %s exited with status %d", b.name, argv[0], code)
+	}
+	return nil
+}
+
+// macOSTool drives security(1). Exit status 44 is errSecItemNotFound.
+type macOSTool struct{}
+
+func (macOSTool) get(service, account string) []string {
+	return []string{"security", "find-generic-password", "-s", service, "-a", account, "-w"}
+}
+
+// securityMaxLine is the longest command line security -i reads.
+const securityMaxLine = 4096
+
+func (macOSTool) set(service, account, secret string) ([]string, string, error) {
+	// add-generic-password takes the password on argv only, so the command
+	// goes to security's interactive mode on stdin instead; -U updates an
+	// existing item.
+	line := "add-generic-password -U -s " + securityQuote(service) + " -a " + securityQuote(account) + " -w " + securityQuote(secret) + "\n"
+	if strings.ContainsAny(service+account+secret, "\r\n") {
+		return nil, "", fmt.Errorf("secret or name contains a line break")
+	}
+	if len(line) > securityMaxLine {
+		return nil, "", fmt.Errorf("secret too long for security -i (%d bytes, max %d)", len(line), securityMaxLine)
+	}
+	return []string{"security", "-i"}, line, nil
+}
+
+// securityQuote quotes s as one word for security -i.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func (macOSTool) del(service, account string) []string {
+	return []string{"security", "delete-generic-password", "-s", service, "-a", account}
+}
+
+func (macOSTool) notFound(code int) bool { return code == 44 }
+
+// security -i doesn't reliably report a failed command in its exit status.
+func (macOSTool) readBack() bool { return true }
+
+// secretTool drives secret-tool(1), which exits 1 when a lookup finds nothing.
+type secretTool struct{}
+
+func (secretTool) get(service, account string) []string {
+	return []string{"secret-tool", "lookup", "service", service, "account", account}
+}
+
+func (secretTool) set(service, account, secret string) ([]string, string, error) {
+	label := "caam: " + service + " (" + account + ")"
+	return []string{"secret-tool", "store", "--label=" + label, "service", service, "account", account}, secret, nil
+}
+
+func (secretTool) del(service, account string) []string {
+	return []string{"secret-tool", "clear", "service", service, "account", account}
+}
+
+func (secretTool) notFound(code int) bool { return code == 1 }
+
+func (secretTool) readBack() bool { return false }
+
+type unavailable struct{}
+
+func (unavailable) Name() string                       { return "none" }
+func (unavailable) Get(string, string) (string, error) { return "", ErrUnavailable }
+func (unavailable) Set(string, string, string) error   { return ErrUnavailable }
+func (unavailable) Delete(string, string) error        { return ErrUnavailable }
+
+// Memory is an in-process Backend, for tests and for callers that want to
+// keep a secret for the life of the process only.
+type Memory struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// NewMemory returns an empty in-memory keyring.
+func NewMemory() *Memory {
+	return &Memory{secrets: make(map[string]string)}
+}
+
+func (m *Memory) Name() string { return "memory" }
+
+func (m *Memory) Get(service, account string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	secret, ok := m.secrets[service+"\x00"+account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (m *Memory) Set(service, account, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[service+"\x00"+account] = secret
+	return nil
+}
+
+func (m *Memory) Delete(service, account string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := service + "\x00" + account
+	if _, ok := m.secrets[key]; !ok {
+		return ErrNotFound
+	}
+	delete(m.secrets, key)
+	return nil
+}
@@ -0,0 +1,140 @@
+package keyring
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMemory(t *testing.T) {
+	m := NewMemory()
+	if _, err := m.Get("caam", "vault"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get() on empty keyring = %v, want ErrNotFound", err)
+	}
+	if err := m.Set("caam", "vault", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := m.Get("caam", "vault"); err != nil || got != "s3cret" {
+		t.Errorf("Get() = %q, %v", got, err)
+	}
+	if err := m.Delete("caam", "vault"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete("caam", "vault"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete() = %v, want ErrNotFound", err)
+	}
+}
+
+// fakeStore records the command lines it is given and answers like a CLI
+// credential store holding at most one secret.
+type fakeStore struct {
+	calls  []string
+	secret string
+	stdin  string
+}
+
+func (f *fakeStore) run(stdin, name string, args ...string) (string, int, error) {
+	line := name + " " + strings.Join(args, " ")
+	f.calls = append(f.calls, line)
+	if name == "security" && len(args) == 1 && args[0] == "-i" {
+		line += " " + stdin
+	}
+	switch {
+	case strings.Contains(line, " lookup ") || strings.Contains(line, "find-generic-password"):
+		if f.secret == "" {
+			return "", notFoundCode(name), nil
+		}
+		return f.secret + "\n", 0, nil
+	case strings.Contains(line, " store ") || strings.Contains(line, "add-generic-password"):
+		f.stdin = stdin
+		f.secret = stdin
+		if name == "security" {
+			// The secret is the last quoted word of the -i command line.
+			words := strings.Split(strings.TrimSpace(stdin), `"`)
+			f.secret = words[len(words)-2]
+		}
+		return "", 0, nil
+	default:
+		if f.secret == "" {
+			return "", notFoundCode(name), nil
+		}
+		f.secret = ""
+		return "", 0, nil
+	}
+}
+
+func notFoundCode(name string) int {
+	if name == "security" {
+		return 44
+	}
+	return 1
+}
+
+func TestCommandBackends(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		tool tool
+	}{
+		{"secret-tool", secretTool{}},
+		{"security", macOSTool{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &fakeStore{}
+			b := &commandBackend{name: tc.name, run: store.run, tool: tc.tool}
+
+			if _, err := b.Get("caam-vault", "/v"); !errors.Is(err, ErrNotFound) {
+				t.Fatalf("Get() = %v, want ErrNotFound", err)
+			}
+			if err := b.Set("caam-vault", "/v", "key"); err != nil {
+				t.Fatal(err)
+			}
+			if got, err := b.Get("caam-vault", "/v"); err != nil || got != "key" {
+				t.Errorf("Get() = %q, %v", got, err)
+			}
+			if err := b.Delete("caam-vault", "/v"); err != nil {
+				t.Fatal(err)
+			}
+			if err := b.Delete("caam-vault", "/v"); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Delete() of missing secret = %v, want ErrNotFound", err)
+			}
+			if !strings.HasPrefix(store.calls[0], tc.name+" ") {
+				t.Errorf("ran %q, want %s", store.calls[0], tc.name)
+			}
+		})
+	}
+}
+
+func TestSecretsGoOnStdin(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		tool  tool
+		stdin string
+	}{
+		{"secret-tool", secretTool{}, "hunter2"},
+		{"security", macOSTool{}, `add-generic-password -U -s "caam-vault" -a "/my \"v\"" -w "hunter2"` + "\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &fakeStore{}
+			b := &commandBackend{name: tc.name, run: store.run, tool: tc.tool}
+			account := "/v"
+			if tc.name == "security" {
+				account = `/my "v"`
+			}
+			if err := b.Set("caam-vault", account, "hunter2"); err != nil {
+				t.Fatal(err)
+			}
+			if store.stdin != tc.stdin {
+				t.Errorf("stdin = %q, want %q", store.stdin, tc.stdin)
+			}
+			for _, call := range store.calls {
+				if strings.Contains(call, "hunter2") {
+					t.Errorf("secret leaked into argv: %q", call)
+				}
+			}
+		})
+	}
+
+	if _, _, err := (macOSTool{}).set("caam", "/v", strings.Repeat("x", securityMaxLine)); err == nil {
+		t.Error("set() should refuse a command line security -i can't read")
+	}
+}
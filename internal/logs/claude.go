@@ -138,6 +138,8 @@ type claudeLogEntry struct {
 	ConversationUUID string       `json:"conversation_uuid"`
 	MessageUUID      string       `json:"message_uuid"`
 	Usage            *claudeUsage `json:"usage"`
+	Error            any          `json:"error"`
+	Message          any          `json:"message"`
 }
 
 type claudeUsage struct {
@@ -172,6 +174,8 @@ func (s *ClaudeScanner) parseLine(line []byte) (*LogEntry, error) {
 		// If parsing fails, leave as zero time
 	}
 
+	entry.Error = extractErrorMessage(map[string]any{"error": raw.Error, "message": raw.Message}, raw.Type)
+
 	// Extract token counts
 	if raw.Usage != nil {
 		entry.InputTokens = raw.Usage.InputTokens
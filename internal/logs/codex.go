@@ -185,6 +185,7 @@ func (s *CodexScanner) parseLine(line []byte) (*LogEntry, error) {
 		}
 	}
 	applyCodexTokenFields(entry, raw)
+	entry.Error = extractErrorMessage(raw, entry.Type)
 
 	if entry.TotalTokens == 0 {
 		entry.TotalTokens = entry.CalculateTotalTokens()
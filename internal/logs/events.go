@@ -0,0 +1,92 @@
+package logs
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/ratelimit"
+)
+
+// EventKind classifies a failure found in a tool's logs.
+type EventKind string
+
+const (
+	// EventRateLimit is a rate limit, usage limit or quota error.
+	EventRateLimit EventKind = "rate_limit"
+
+	// EventAuthError is an authentication failure such as an expired token.
+	EventAuthError EventKind = "auth_error"
+)
+
+// Event is a rate-limit or auth-error entry extracted from a tool's logs.
+type Event struct {
+	Timestamp time.Time
+	Kind      EventKind
+	Message   string
+}
+
+// authErrorPattern matches authentication failures in error messages.
+var authErrorPattern = regexp.MustCompile(`(?i)\b401\b|unauthori[sz]ed|authentication.?(error|failed|required)|invalid.?(api.?key|x-api-key|grant|token)|token.*(expired|revoked)|(expired|revoked).*token|please.*(log.?in|re-?authenticate)`)
+
+// ExtractEvents returns the rate-limit and auth-error events recorded by
+// entries, oldest first. Only entries carrying an error message are
+// classified; rate limits use the provider's ratelimit patterns and win over
+// auth errors. Entries without a timestamp are skipped.
+func ExtractEvents(provider string, entries []*LogEntry) ([]Event, error) {
+	detector, err := ratelimit.NewDetector(ratelimit.ProviderFromString(provider), nil)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit detector: %w", err)
+	}
+
+	var events []Event
+	for _, entry := range entries {
+		if entry == nil || entry.Error == "" || entry.Timestamp.IsZero() {
+			continue
+		}
+		detector.Reset()
+		switch {
+		case detector.Check(entry.Error):
+			events = append(events, Event{Timestamp: entry.Timestamp, Kind: EventRateLimit, Message: entry.Error})
+		case authErrorPattern.MatchString(entry.Error):
+			events = append(events, Event{Timestamp: entry.Timestamp, Kind: EventAuthError, Message: entry.Error})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// extractErrorMessage returns the error message a log line records: its
+// "error" field (a string, or an object with a type and message) or, for
+// error-typed entries, its "message" string.
+func extractErrorMessage(raw map[string]any, entryType string) string {
+	switch v := raw["error"].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		var parts []string
+		for _, key := range []string{"type", "code", "status", "message"} {
+			switch field := v[key].(type) {
+			case string:
+				if field != "" {
+					parts = append(parts, field)
+				}
+			case float64:
+				parts = append(parts, fmt.Sprintf("%.0f", field))
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, ": ")
+		}
+	}
+	if strings.Contains(strings.ToLower(entryType), "error") {
+		if msg, ok := raw["message"].(string); ok {
+			return strings.TrimSpace(msg)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,72 @@
+package logs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExtractEvents(t *testing.T) {
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	entries := []*LogEntry{
+		{Timestamp: base.Add(2 * time.Minute), Error: "authentication_error: OAuth token has expired"},
+		{Timestamp: base, Error: "rate_limit_error: 429 Too Many Requests"},
+		{Timestamp: base.Add(time.Minute), Type: "response", InputTokens: 10},
+		{Timestamp: base.Add(3 * time.Minute), Error: "connection reset by peer"},
+		{Error: "rate limit exceeded"},
+	}
+
+	events, err := ExtractEvents("claude", entries)
+	if err != nil {
+		t.Fatalf("ExtractEvents() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("ExtractEvents() returned %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].Kind != EventRateLimit || !events[0].Timestamp.Equal(base) {
+		t.Errorf("events[0] = %+v, want rate limit at %s", events[0], base)
+	}
+	if events[1].Kind != EventAuthError {
+		t.Errorf("events[1].Kind = %q, want %q", events[1].Kind, EventAuthError)
+	}
+}
+
+func TestScannersExtractErrorMessages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	claudeLog := `{"timestamp":"2026-03-01T12:00:00Z","type":"error","error":{"type":"rate_limit_error","message":"Number of requests has exceeded your rate limit"}}
+{"timestamp":"2026-03-01T12:01:00Z","type":"assistant","message":{"role":"assistant"}}
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "session.jsonl"), []byte(claudeLog), 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := NewClaudeScannerWithDir(tmpDir).Scan(context.Background(), "", time.Time{})
+	if err != nil {
+		t.Fatalf("Claude Scan() error = %v", err)
+	}
+	if len(result.Entries) != 2 {
+		t.Fatalf("Claude Scan() returned %d entries, want 2", len(result.Entries))
+	}
+	if want := "rate_limit_error: Number of requests has exceeded your rate limit"; result.Entries[0].Error != want {
+		t.Errorf("Claude entry Error = %q, want %q", result.Entries[0].Error, want)
+	}
+	if result.Entries[1].Error != "" {
+		t.Errorf("Claude assistant entry Error = %q, want empty", result.Entries[1].Error)
+	}
+
+	codexDir := t.TempDir()
+	codexLog := `{"timestamp":"2026-03-01T12:00:00Z","type":"error","message":"stream error: 401 Unauthorized"}
+`
+	if err := os.WriteFile(filepath.Join(codexDir, "session-1.jsonl"), []byte(codexLog), 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, err = NewCodexScannerWithDir(codexDir).Scan(context.Background(), "", time.Time{})
+	if err != nil {
+		t.Fatalf("Codex Scan() error = %v", err)
+	}
+	if len(result.Entries) != 1 || result.Entries[0].Error != "stream error: 401 Unauthorized" {
+		t.Fatalf("Codex Scan() entries = %+v, want one error entry", result.Entries)
+	}
+}
@@ -190,6 +190,7 @@ func (s *GeminiScanner) parseLine(line []byte) (*LogEntry, error) {
 		applyTokenFields(entry, tokens)
 	}
 	applyTokenFields(entry, raw)
+	entry.Error = extractErrorMessage(raw, entry.Type)
 
 	if entry.TotalTokens == 0 {
 		entry.TotalTokens = entry.CalculateTotalTokens()
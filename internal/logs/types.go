@@ -35,6 +35,9 @@ type LogEntry struct {
 	CacheCreateTokens int64
 	TotalTokens       int64
 
+	// Error is the error message for entries that record a failed request
+	Error string
+
 	// Raw contains provider-specific fields not in the common schema
 	Raw map[string]any
 }
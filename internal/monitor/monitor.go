@@ -290,9 +290,15 @@ func (m *Monitor) readAccessToken(provider, name string) (string, error) {
 		return "", fmt.Errorf("vault is nil")
 	}
 
+	// Read a plain copy: the vault files may be sealed or in the keychain.
+	profilePath, cleanup, err := m.vault.PlainProfileDir(provider, name)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
 	switch provider {
 	case "claude":
-		profilePath := m.vault.ProfilePath(provider, name)
 		creds := filepath.Join(profilePath, ".credentials.json")
 		token, _, err := usage.ReadClaudeCredentials(creds)
 		if err != nil {
@@ -305,7 +311,7 @@ func (m *Monitor) readAccessToken(provider, name string) (string, error) {
 		}
 		return token, err
 	case "codex":
-		authPath := filepath.Join(m.vault.ProfilePath(provider, name), "auth.json")
+		authPath := filepath.Join(profilePath, "auth.json")
 		token, _, err := usage.ReadCodexCredentials(authPath)
 		return token, err
 	default:
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/keyring"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
 )
 
@@ -158,6 +159,38 @@ func TestMonitorRefreshClaudeFallbackAuth(t *testing.T) {
 	}
 }
 
+func TestMonitorReadAccessToken_EncryptedVault(t *testing.T) {
+	vault := authfile.NewVault(t.TempDir())
+	writeProfileFile(t, vault, "codex", "work", "auth.json", `{"tokens":{"access_token":"tok-work"}}`)
+	if _, err := vault.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := vault.EncryptAll(); err != nil || n != 1 {
+		t.Fatalf("EncryptAll() = %d, %v", n, err)
+	}
+
+	mon := NewMonitor(WithVault(vault))
+	token, err := mon.readAccessToken("codex", "work")
+	if err != nil || token != "tok-work" {
+		t.Errorf("readAccessToken() = %q, %v; want the decrypted token", token, err)
+	}
+}
+
+func TestMonitorReadAccessToken_KeychainStorage(t *testing.T) {
+	vault := authfile.NewVault(t.TempDir())
+	writeProfileFile(t, vault, "codex", "work", "auth.json", `{"tokens":{"access_token":"tok-work"}}`)
+	vault.SetStorage(authfile.NewKeychainBackend(keyring.NewMemory()))
+	if n, err := vault.MigrateStorage(); err != nil || n != 1 {
+		t.Fatalf("MigrateStorage() = %d, %v", n, err)
+	}
+
+	mon := NewMonitor(WithVault(vault))
+	token, err := mon.readAccessToken("codex", "work")
+	if err != nil || token != "tok-work" {
+		t.Errorf("readAccessToken() = %q, %v; want the token from the keychain", token, err)
+	}
+}
+
 func writeProfileFile(t *testing.T, vault *authfile.Vault, provider, profile, name, contents string) {
 	t.Helper()
 	dir := vault.ProfilePath(provider, profile)
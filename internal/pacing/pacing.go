@@ -0,0 +1,73 @@
+// Package pacing spaces out sessions on one account so batch jobs don't
+// spend a whole usage window in its first few minutes.
+package pacing
+
+import (
+	"sort"
+	"time"
+)
+
+// Window is the span MaxPerHour counts sessions over.
+const Window = time.Hour
+
+// Limits caps how often sessions may start on one account. Zero values
+// impose nothing.
+type Limits struct {
+	MaxPerHour int           // sessions that may start in any rolling hour
+	MinGap     time.Duration // least time between two session starts
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	return l.MaxPerHour > 0 || l.MinGap > 0
+}
+
+// Wait returns how long from now until another session may start, given
+// when recent sessions on the account started (in any order). Zero means it
+// may start now.
+func (l Limits) Wait(starts []time.Time, now time.Time) time.Duration {
+	var wait time.Duration
+	if l.MinGap > 0 {
+		var latest time.Time
+		for _, ts := range starts {
+			if ts.After(latest) {
+				latest = ts
+			}
+		}
+		if !latest.IsZero() {
+			wait = max(wait, latest.Add(l.MinGap).Sub(now))
+		}
+	}
+	if l.MaxPerHour > 0 {
+		var recent []time.Time
+		for _, ts := range starts {
+			if ts.After(now.Add(-Window)) {
+				recent = append(recent, ts)
+			}
+		}
+		if len(recent) >= l.MaxPerHour {
+			// A slot frees when the oldest start that keeps the count at
+			// the cap leaves the window.
+			sort.Slice(recent, func(i, j int) bool { return recent[i].Before(recent[j]) })
+			freed := recent[len(recent)-l.MaxPerHour]
+			wait = max(wait, freed.Add(Window).Sub(now))
+		}
+	}
+	return max(wait, 0)
+}
+
+// Since returns how far back Wait needs to see starts.
+func (l Limits) Since(now time.Time) time.Time {
+	return now.Add(-max(Window, l.MinGap))
+}
+
+// InWindow counts the starts in the rolling hour before now.
+func InWindow(starts []time.Time, now time.Time) int {
+	n := 0
+	for _, ts := range starts {
+		if ts.After(now.Add(-Window)) {
+			n++
+		}
+	}
+	return n
+}
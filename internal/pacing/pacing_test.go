@@ -0,0 +1,52 @@
+package pacing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitsWait(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	tests := []struct {
+		name   string
+		limits Limits
+		starts []time.Time
+		want   time.Duration
+	}{
+		{"no limits", Limits{}, []time.Time{ago(time.Second)}, 0},
+		{"no history", Limits{MaxPerHour: 1, MinGap: time.Minute}, nil, 0},
+		{"gap elapsed", Limits{MinGap: 10 * time.Minute}, []time.Time{ago(11 * time.Minute)}, 0},
+		{"gap pending", Limits{MinGap: 10 * time.Minute}, []time.Time{ago(30 * time.Minute), ago(4 * time.Minute)}, 6 * time.Minute},
+		{"under cap", Limits{MaxPerHour: 3}, []time.Time{ago(time.Minute), ago(2 * time.Minute)}, 0},
+		{"at cap", Limits{MaxPerHour: 2}, []time.Time{ago(time.Minute), ago(50 * time.Minute), ago(2 * time.Hour)}, 10 * time.Minute},
+		{"over cap waits for enough to expire", Limits{MaxPerHour: 2}, []time.Time{ago(10 * time.Minute), ago(40 * time.Minute), ago(55 * time.Minute)}, 20 * time.Minute},
+		{"larger limit wins", Limits{MaxPerHour: 1, MinGap: 5 * time.Minute}, []time.Time{ago(20 * time.Minute)}, 40 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.Wait(tt.starts, now); got != tt.want {
+				t.Errorf("Wait() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitsSinceCoversGap(t *testing.T) {
+	now := time.Now()
+	if got := (Limits{MinGap: 3 * time.Hour}).Since(now); !got.Equal(now.Add(-3 * time.Hour)) {
+		t.Errorf("Since() = %v, want 3h back", got)
+	}
+	if got := (Limits{MaxPerHour: 5}).Since(now); !got.Equal(now.Add(-Window)) {
+		t.Errorf("Since() = %v, want one window back", got)
+	}
+}
+
+func TestInWindow(t *testing.T) {
+	now := time.Now()
+	starts := []time.Time{now.Add(-time.Minute), now.Add(-59 * time.Minute), now.Add(-61 * time.Minute)}
+	if got := InWindow(starts, now); got != 2 {
+		t.Errorf("InWindow() = %d, want 2", got)
+	}
+}
@@ -191,6 +191,23 @@ func (p *Profile) IsLocked() bool {
 	return err == nil
 }
 
+// ErrLocked indicates a profile is locked by another process.
+var ErrLocked = errors.New("profile is locked")
+
+// LockedError is returned by Lock when the profile is already locked. It
+// unwraps to ErrLocked.
+type LockedError struct {
+	Name string
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("profile %s is already locked", e.Name)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrLocked
+}
+
 // Lock creates a lock file to indicate the profile is in use.
 // Uses O_EXCL for atomic creation to prevent race conditions.
 func (p *Profile) Lock() error {
@@ -208,7 +225,7 @@ func (p *Profile) Lock() error {
 	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
 	if err != nil {
 		if os.IsExist(err) {
-			return fmt.Errorf("profile %s is already locked", p.Name)
+			return &LockedError{Name: p.Name}
 		}
 		return fmt.Errorf("create lock file: %w", err)
 	}
@@ -447,6 +464,11 @@ func NewStore(basePath string) *Store {
 	return &Store{basePath: basePath}
 }
 
+// BasePath returns the directory holding the store's profiles.
+func (s *Store) BasePath() string {
+	return s.basePath
+}
+
 // DefaultStorePath returns the default profiles directory.
 // Falls back to current directory if home directory cannot be determined.
 func DefaultStorePath() string {
@@ -3,6 +3,7 @@ package profile
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -127,6 +128,8 @@ func TestLockUnlock(t *testing.T) {
 	// Lock again should fail
 	if err := prof.Lock(); err == nil {
 		t.Error("expected Lock() to fail when already locked")
+	} else if !errors.Is(err, ErrLocked) {
+		t.Errorf("expected ErrLocked, got %v", err)
 	}
 
 	// Unlock
@@ -15,13 +15,13 @@ type globMatch struct {
 	pattern string
 }
 
-func matchingGlobs(associations map[string]map[string]string, target string) []globMatch {
-	if len(associations) == 0 {
+func matchingGlobs[V any](patterns map[string]V, target string) []globMatch {
+	if len(patterns) == 0 {
 		return nil
 	}
 
 	matches := make([]globMatch, 0, 4)
-	for key := range associations {
+	for key := range patterns {
 		if !isGlob(key) {
 			continue
 		}
@@ -93,7 +93,7 @@ func TestWildcardCount(t *testing.T) {
 // =============================================================================
 
 func TestMatchingGlobs_EmptyAssociations(t *testing.T) {
-	matches := matchingGlobs(nil, "target")
+	matches := matchingGlobs[map[string]string](nil, "target")
 	if matches != nil {
 		t.Errorf("expected nil for nil associations, got %v", matches)
 	}
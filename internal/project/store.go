@@ -14,6 +14,10 @@ type StoreData struct {
 	Version      int                          `json:"version"`
 	Associations map[string]map[string]string `json:"associations,omitempty"`
 	Defaults     map[string]string            `json:"defaults,omitempty"`
+
+	// Classes maps project paths (or globs) to the profile policy class
+	// required there, e.g. {"/src/employer": "work"}.
+	Classes map[string]string `json:"classes,omitempty"`
 }
 
 type Resolved struct {
@@ -236,6 +240,79 @@ func (s *Store) SetDefault(provider, profile string) error {
 	return s.saveLocked(store)
 }
 
+// SetClass requires profiles activated under projectPath to carry class.
+func (s *Store) SetClass(projectPath, class string) error {
+	class = strings.TrimSpace(class)
+	if class == "" {
+		return fmt.Errorf("class cannot be empty")
+	}
+	key, err := normalizeKey(projectPath)
+	if err != nil {
+		return err
+	}
+
+	// Hold lock for entire read-modify-write cycle to prevent TOCTOU race
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	store, err := s.loadLocked()
+	if err != nil {
+		return err
+	}
+	store.Classes[key] = class
+	return s.saveLocked(store)
+}
+
+// RemoveClass drops the class requirement set on projectPath itself.
+func (s *Store) RemoveClass(projectPath string) error {
+	key, err := normalizeKey(projectPath)
+	if err != nil {
+		return err
+	}
+
+	// Hold lock for entire read-modify-write cycle to prevent TOCTOU race
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	store, err := s.loadLocked()
+	if err != nil {
+		return err
+	}
+	if _, ok := store.Classes[key]; !ok {
+		return nil
+	}
+	delete(store.Classes, key)
+	return s.saveLocked(store)
+}
+
+// RequiredClass returns the profile class required in dir and the path or
+// glob requiring it, using the same nearest-first precedence as Resolve.
+// It returns "" when dir has no requirement.
+func (s *Store) RequiredClass(dir string) (class, source string, err error) {
+	absDir, err := normalizeKey(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	store, err := s.Load()
+	if err != nil {
+		return "", "", err
+	}
+	if len(store.Classes) == 0 {
+		return "", "", nil
+	}
+
+	for _, candidate := range parentDirs(absDir) {
+		if c, ok := store.Classes[candidate]; ok {
+			return c, candidate, nil
+		}
+		for _, m := range matchingGlobs(store.Classes, candidate) {
+			return store.Classes[m.pattern], m.pattern, nil
+		}
+	}
+	return "", "", nil
+}
+
 func (s *Store) Resolve(dir string) (*Resolved, error) {
 	absDir, err := normalizeKey(dir)
 	if err != nil {
@@ -342,6 +419,7 @@ func newStoreData() *StoreData {
 		Version:      1,
 		Associations: make(map[string]map[string]string),
 		Defaults:     make(map[string]string),
+		Classes:      make(map[string]string),
 	}
 }
 
@@ -358,6 +436,23 @@ func normalizeStoreData(store *StoreData) {
 
 	store.Defaults = normalizeProviderMap(store.Defaults)
 	store.Associations = normalizeAssociations(store.Associations)
+	store.Classes = normalizeClasses(store.Classes)
+}
+
+func normalizeClasses(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in))
+	for k, class := range in {
+		class = strings.TrimSpace(class)
+		if class == "" {
+			continue
+		}
+		key, err := normalizeKey(k)
+		if err != nil {
+			continue
+		}
+		out[key] = class
+	}
+	return out
 }
 
 func normalizeProviderMap(in map[string]string) map[string]string {
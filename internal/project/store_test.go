@@ -425,3 +425,46 @@ func TestStore_DeleteProject_InvalidPath(t *testing.T) {
 		t.Fatal("DeleteProject(\"\") expected error, got nil")
 	}
 }
+
+func TestStore_RequiredClass(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStore(filepath.Join(tmpDir, "projects.json"))
+
+	employer := filepath.Join(tmpDir, "employer")
+	if err := store.SetClass(employer, "work"); err != nil {
+		t.Fatalf("SetClass() error = %v", err)
+	}
+	if err := store.SetClass(filepath.Join(employer, "oss"), "personal"); err != nil {
+		t.Fatalf("SetClass() error = %v", err)
+	}
+	if err := store.SetClass(filepath.Join(tmpDir, "clients", "*"), "work"); err != nil {
+		t.Fatalf("SetClass(glob) error = %v", err)
+	}
+
+	tests := []struct {
+		dir        string
+		wantClass  string
+		wantSource string
+	}{
+		{filepath.Join(employer, "api", "internal"), "work", employer},
+		{filepath.Join(employer, "oss", "lib"), "personal", filepath.Join(employer, "oss")},
+		{filepath.Join(tmpDir, "clients", "acme"), "work", filepath.Join(tmpDir, "clients", "*")},
+		{filepath.Join(tmpDir, "hobby"), "", ""},
+	}
+	for _, tt := range tests {
+		class, source, err := store.RequiredClass(tt.dir)
+		if err != nil {
+			t.Fatalf("RequiredClass(%s) error = %v", tt.dir, err)
+		}
+		if class != tt.wantClass || source != tt.wantSource {
+			t.Errorf("RequiredClass(%s) = %q from %q, want %q from %q", tt.dir, class, source, tt.wantClass, tt.wantSource)
+		}
+	}
+
+	if err := store.RemoveClass(employer); err != nil {
+		t.Fatalf("RemoveClass() error = %v", err)
+	}
+	if class, _, _ := store.RequiredClass(filepath.Join(employer, "api")); class != "" {
+		t.Errorf("RequiredClass() after RemoveClass = %q, want none", class)
+	}
+}
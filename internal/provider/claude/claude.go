@@ -25,6 +25,10 @@
 // API key mode (secondary):
 // - Supports apiKeyHelper hook in settings.json that returns auth value
 // - Claude Code sends this as X-Api-Key and Authorization: Bearer headers
+//
+// MCP servers (isolated profiles):
+// - The mcp_servers profile metadata holds an mcpServers JSON object
+// - PrepareProfile renders it into ${HOME}/.claude.json (user scope)
 package claude
 
 import (
@@ -35,14 +39,23 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browser"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/passthrough"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
 )
 
+// MetaMCPServers is the profile metadata key holding the profile's MCP
+// server list as a JSON object of name -> server config.
+const MetaMCPServers = "mcp_servers"
+
 // Provider implements the Claude Code CLI adapter.
 type Provider struct{}
 
@@ -155,6 +168,10 @@ func (p *Provider) PrepareProfile(ctx context.Context, prof *profile.Profile) er
 		}
 	}
 
+	if err := p.renderMCPServers(prof); err != nil {
+		return fmt.Errorf("render MCP servers: %w", err)
+	}
+
 	return nil
 }
 
@@ -219,6 +236,138 @@ exit 1
 	return nil
 }
 
+// ParseMCPServers parses an MCP server list, either a bare object of
+// name -> server config or a .mcp.json-style {"mcpServers": {...}} file.
+// Each server needs a command (stdio) or a url (http, sse).
+func ParseMCPServers(data []byte) (map[string]interface{}, error) {
+	var servers map[string]interface{}
+	if err := json.Unmarshal(data, &servers); err != nil {
+		return nil, fmt.Errorf("parse MCP servers: %w", err)
+	}
+	if wrapped, ok := servers["mcpServers"].(map[string]interface{}); ok && len(servers) == 1 {
+		servers = wrapped
+	}
+
+	for name, raw := range servers {
+		server, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("MCP server %q: config must be an object", name)
+		}
+		_, hasCommand := server["command"].(string)
+		_, hasURL := server["url"].(string)
+		if !hasCommand && !hasURL {
+			return nil, fmt.Errorf("MCP server %q: needs a command or url", name)
+		}
+	}
+	return servers, nil
+}
+
+// MCPServerNames returns the sorted server names in the profile's
+// mcp_servers metadata.
+func MCPServerNames(prof *profile.Profile) ([]string, error) {
+	raw := prof.Metadata[MetaMCPServers]
+	if raw == "" {
+		return nil, nil
+	}
+	servers, err := ParseMCPServers([]byte(raw))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(servers))
+	for name := range servers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// mcpTemplateData is what MCP server strings can reference, e.g.
+// "{{.Home}}/notes" or "--profile={{.Profile}}".
+type mcpTemplateData struct {
+	Profile    string // profile name
+	Home       string // the profile's pseudo-HOME
+	ProfileDir string // the profile's base directory
+}
+
+// renderMCPServers writes the profile's mcp_servers metadata into the
+// mcpServers key of ${HOME}/.claude.json, leaving the rest of the file
+// (session state, OAuth account) alone. Profiles without the metadata are
+// left untouched.
+func (p *Provider) renderMCPServers(prof *profile.Profile) error {
+	raw := prof.Metadata[MetaMCPServers]
+	if raw == "" {
+		return nil
+	}
+	servers, err := ParseMCPServers([]byte(raw))
+	if err != nil {
+		return err
+	}
+
+	data := mcpTemplateData{Profile: prof.Name, Home: prof.HomePath(), ProfileDir: prof.BasePath}
+	rendered, err := expandMCPTemplates(servers, data)
+	if err != nil {
+		return err
+	}
+
+	statePath := filepath.Join(prof.HomePath(), ".claude.json")
+	state := map[string]interface{}{}
+	if existing, err := os.ReadFile(statePath); err == nil {
+		if err := json.Unmarshal(existing, &state); err != nil {
+			return fmt.Errorf("parse %s: %w", statePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("read %s: %w", statePath, err)
+	}
+	state["mcpServers"] = rendered
+
+	out, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", statePath, err)
+	}
+	return atomicWriteFile(statePath, out, 0600)
+}
+
+// expandMCPTemplates executes every string in v as a text/template.
+func expandMCPTemplates(v interface{}, data mcpTemplateData) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		if !strings.Contains(val, "{{") {
+			return val, nil
+		}
+		tmpl, err := template.New("mcp").Option("missingkey=error").Parse(val)
+		if err != nil {
+			return nil, fmt.Errorf("template %q: %w", val, err)
+		}
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("template %q: %w", val, err)
+		}
+		return buf.String(), nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			expanded, err := expandMCPTemplates(child, data)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			expanded, err := expandMCPTemplates(child, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
 // Env returns the environment variables for running Claude in this profile's context.
 func (p *Provider) Env(ctx context.Context, prof *profile.Profile) (map[string]string, error) {
 	env := map[string]string{
@@ -700,6 +849,25 @@ func copyFile(src, dst string) error {
 	return os.Rename(tmpPath, dst)
 }
 
+// SupportsTokenRefresh reports that Claude OAuth tokens can be refreshed.
+func (p *Provider) SupportsTokenRefresh() bool {
+	return true
+}
+
+// ExtractIdentity extracts the account identity from stored Claude auth files.
+func (p *Provider) ExtractIdentity(authDir string) (*identity.Identity, error) {
+	return identity.ExtractFromClaudeCredentials(filepath.Join(authDir, ".credentials.json"))
+}
+
+// TokenExpiry returns the expiry of the OAuth token stored in authDir.
+func (p *Provider) TokenExpiry(authDir string) (time.Time, error) {
+	info, err := health.ParseClaudeExpiry(authDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ExpiresAt, nil
+}
+
 // ValidateToken validates that the authentication token works.
 // For passive validation: checks file existence, format, and expiry timestamps.
 // For active validation: attempts minimal API call (API key mode) or checks OAuth validity.
@@ -1009,3 +1177,6 @@ var timeNow = time.Now
 
 // Ensure Provider implements the interface.
 var _ provider.Provider = (*Provider)(nil)
+var _ provider.TokenRefresher = (*Provider)(nil)
+var _ provider.IdentityExtractor = (*Provider)(nil)
+var _ provider.ExpiryParser = (*Provider)(nil)
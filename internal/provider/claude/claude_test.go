@@ -11,6 +11,7 @@ import (
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/conformance"
 )
 
 // =============================================================================
@@ -1150,3 +1151,143 @@ func writeJSON(t *testing.T, path string, data interface{}) {
 		t.Fatal(err)
 	}
 }
+
+func TestCapabilities(t *testing.T) {
+	caps := provider.CapabilitiesOf(New())
+	if !caps.TokenRefresh || !caps.IdentityExtraction || !caps.ExpiryParsing {
+		t.Errorf("expected refresh, identity and expiry capabilities: %+v", caps)
+	}
+	if caps.DeviceCode {
+		t.Error("claude should not report device code support")
+	}
+}
+
+func TestExtractIdentityAndTokenExpiry(t *testing.T) {
+	dir := t.TempDir()
+	expiresAt := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	creds := map[string]interface{}{
+		"claudeAiOauth": map[string]interface{}{
+			"accessToken":      "token",
+			"email":            "alice@example.com",
+			"subscriptionType": "max",
+			"expiresAt":        expiresAt.UnixMilli(),
+		},
+	}
+	data, _ := json.Marshal(creds)
+	if err := os.WriteFile(filepath.Join(dir, ".credentials.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := New()
+	id, err := p.ExtractIdentity(dir)
+	if err != nil {
+		t.Fatalf("ExtractIdentity() error = %v", err)
+	}
+	if id.Email != "alice@example.com" {
+		t.Errorf("Email = %q, want alice@example.com", id.Email)
+	}
+
+	exp, err := p.TokenExpiry(dir)
+	if err != nil {
+		t.Fatalf("TokenExpiry() error = %v", err)
+	}
+	if !exp.Equal(expiresAt) {
+		t.Errorf("TokenExpiry() = %v, want %v", exp, expiresAt)
+	}
+}
+
+// =============================================================================
+// MCP Server Tests
+// =============================================================================
+
+func TestPrepareProfileRendersMCPServers(t *testing.T) {
+	tmpDir := t.TempDir()
+	prof := &profile.Profile{
+		Name:     "work",
+		Provider: "claude",
+		AuthMode: string(provider.AuthModeOAuth),
+		BasePath: tmpDir,
+		Metadata: map[string]string{
+			MetaMCPServers: `{"mcpServers": {
+				"notes": {"command": "notes-mcp", "args": ["--root", "{{.Home}}/notes", "--tag={{.Profile}}"]},
+				"docs": {"type": "http", "url": "https://docs.example.com/mcp"}
+			}}`,
+		},
+	}
+
+	// Existing session state in .claude.json must survive rendering.
+	statePath := filepath.Join(prof.HomePath(), ".claude.json")
+	if err := os.MkdirAll(prof.HomePath(), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(statePath, []byte(`{"oauthAccount": {"emailAddress": "a@example.com"}, "mcpServers": {"old": {"command": "old"}}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := New().PrepareProfile(context.Background(), prof); err != nil {
+		t.Fatalf("PrepareProfile() error = %v", err)
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		t.Fatalf("read .claude.json: %v", err)
+	}
+	var state struct {
+		OAuthAccount map[string]interface{} `json:"oauthAccount"`
+		MCPServers   map[string]struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+			URL     string   `json:"url"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("parse .claude.json: %v", err)
+	}
+	if state.OAuthAccount["emailAddress"] != "a@example.com" {
+		t.Errorf("oauthAccount lost: %s", data)
+	}
+	if len(state.MCPServers) != 2 {
+		t.Fatalf("mcpServers = %v, want notes and docs only", state.MCPServers)
+	}
+	notes := state.MCPServers["notes"]
+	wantArgs := []string{"--root", filepath.Join(prof.HomePath(), "notes"), "--tag=work"}
+	if notes.Command != "notes-mcp" || strings.Join(notes.Args, " ") != strings.Join(wantArgs, " ") {
+		t.Errorf("notes server = %+v, want args %v", notes, wantArgs)
+	}
+	if state.MCPServers["docs"].URL != "https://docs.example.com/mcp" {
+		t.Errorf("docs server = %+v", state.MCPServers["docs"])
+	}
+}
+
+func TestParseMCPServers(t *testing.T) {
+	if _, err := ParseMCPServers([]byte(`{"a": {"command": "x"}}`)); err != nil {
+		t.Errorf("bare object: %v", err)
+	}
+	if _, err := ParseMCPServers([]byte(`{"a": {"args": ["x"]}}`)); err == nil {
+		t.Error("server without command or url accepted")
+	}
+	if _, err := ParseMCPServers([]byte(`{"a": "x"}`)); err == nil {
+		t.Error("non-object server accepted")
+	}
+	if _, err := ParseMCPServers([]byte(`[`)); err == nil {
+		t.Error("invalid JSON accepted")
+	}
+
+	prof := &profile.Profile{Metadata: map[string]string{MetaMCPServers: `{"b": {"url": "u"}, "a": {"command": "x"}}`}}
+	names, err := MCPServerNames(prof)
+	if err != nil || strings.Join(names, ",") != "a,b" {
+		t.Errorf("MCPServerNames() = %v, %v", names, err)
+	}
+}
+
+// =============================================================================
+// Conformance Tests
+// =============================================================================
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func() provider.Provider { return New() }, conformance.Options{
+		AuthFixture: map[string]string{
+			"$HOME/.claude/.credentials.json": `{"claudeAiOauth": {"accessToken": "conformance", "refreshToken": "refresh"}}`,
+		},
+	})
+}
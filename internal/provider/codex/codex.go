@@ -29,6 +29,9 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browser"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/devicecode"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/passthrough"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
@@ -193,13 +196,16 @@ func (p *Provider) SupportsDeviceCode() bool {
 	return true
 }
 
+// LoginWithDeviceCode runs codex's device-code flow, showing the verification
+// URL as a QR code unless ctx turns that off (devicecode.WithQR).
 func (p *Provider) LoginWithDeviceCode(ctx context.Context, prof *profile.Profile) error {
 	codexHomePath := prof.CodexHomePath()
 
 	cmd := exec.CommandContext(ctx, "codex", "login", "--device-auth")
 	cmd.Env = append(os.Environ(), "CODEX_HOME="+codexHomePath)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	watcher := devicecode.ForTerminal(ctx)
+	cmd.Stdout = watcher.Wrap(os.Stdout)
+	cmd.Stderr = watcher.Wrap(os.Stderr)
 	cmd.Stdin = os.Stdin
 
 	fmt.Println("Starting Codex device code login flow...")
@@ -594,6 +600,25 @@ func copyFile(src, dst string) error {
 	return os.Rename(tmpPath, dst)
 }
 
+// SupportsTokenRefresh reports that Codex OAuth tokens can be refreshed.
+func (p *Provider) SupportsTokenRefresh() bool {
+	return true
+}
+
+// ExtractIdentity extracts the account identity from stored Codex auth files.
+func (p *Provider) ExtractIdentity(authDir string) (*identity.Identity, error) {
+	return identity.ExtractFromCodexAuth(filepath.Join(authDir, "auth.json"))
+}
+
+// TokenExpiry returns the expiry of the token stored in authDir.
+func (p *Provider) TokenExpiry(authDir string) (time.Time, error) {
+	info, err := health.ParseCodexExpiry(filepath.Join(authDir, "auth.json"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ExpiresAt, nil
+}
+
 // ValidateToken validates that the authentication token works.
 // For passive validation: checks file existence, format, and expiry timestamps.
 // For active validation: attempts minimal API call to OpenAI.
@@ -637,6 +662,11 @@ func (p *Provider) validateTokenPassive(ctx context.Context, prof *profile.Profi
 		return result, nil
 	}
 
+	// Current Codex versions nest the tokens under "tokens".
+	if tokens, ok := authData["tokens"].(map[string]interface{}); ok {
+		authData = tokens
+	}
+
 	// Check for access_token field
 	if _, hasToken := authData["access_token"]; !hasToken {
 		if _, hasToken := authData["accessToken"]; !hasToken {
@@ -722,3 +752,6 @@ func parseCodexUnixTime(f float64) time.Time {
 // Ensure Provider implements the interface.
 var _ provider.Provider = (*Provider)(nil)
 var _ provider.DeviceCodeProvider = (*Provider)(nil)
+var _ provider.TokenRefresher = (*Provider)(nil)
+var _ provider.IdentityExtractor = (*Provider)(nil)
+var _ provider.ExpiryParser = (*Provider)(nil)
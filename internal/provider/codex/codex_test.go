@@ -10,6 +10,7 @@ import (
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/conformance"
 )
 
 // =============================================================================
@@ -464,6 +465,39 @@ func TestValidateProfile(t *testing.T) {
 	})
 }
 
+func TestValidateTokenPassive(t *testing.T) {
+	tests := []struct {
+		name  string
+		auth  string
+		valid bool
+	}{
+		{"top-level token", `{"access_token": "tok"}`, true},
+		{"nested tokens", `{"OPENAI_API_KEY": null, "tokens": {"access_token": "tok", "refresh_token": "ref"}}`, true},
+		{"nested expired", `{"tokens": {"access_token": "tok", "expires_at": 1000000000}}`, false},
+		{"no token", `{"tokens": {"refresh_token": "ref"}}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prof := &profile.Profile{Name: "test", Provider: "codex", BasePath: t.TempDir()}
+			if err := os.MkdirAll(prof.CodexHomePath(), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(prof.CodexHomePath(), "auth.json"), []byte(tt.auth), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := New().ValidateToken(context.Background(), prof, true)
+			if err != nil {
+				t.Fatalf("ValidateToken() error = %v", err)
+			}
+			if result.Valid != tt.valid {
+				t.Errorf("Valid = %v, want %v (error %q)", result.Valid, tt.valid, result.Error)
+			}
+		})
+	}
+}
+
 // =============================================================================
 // Interface Compliance Tests
 // =============================================================================
@@ -754,3 +788,15 @@ func writeJSON(t *testing.T, path string, data interface{}) {
 		t.Fatal(err)
 	}
 }
+
+// =============================================================================
+// Conformance Tests
+// =============================================================================
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func() provider.Provider { return New() }, conformance.Options{
+		AuthFixture: map[string]string{
+			"$CODEX_HOME/auth.json": `{"access_token": "conformance"}`,
+		},
+	})
+}
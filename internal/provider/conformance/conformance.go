@@ -0,0 +1,374 @@
+// Package conformance is a test suite that any provider.Provider
+// implementation can run to check the semantics caam relies on:
+// AuthFiles, PrepareProfile, Env, Status, Logout, DetectExistingAuth and
+// ImportAuth, all against a temporary HOME so the real one is never touched.
+//
+// A provider's tests call Run with a factory and a fixture describing what a
+// logged-in auth state looks like:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func() provider.Provider { return New() }, conformance.Options{
+//			AuthFixture: map[string]string{
+//				"$CODEX_HOME/auth.json": `{"access_token": "test"}`,
+//			},
+//		})
+//	}
+//
+// Run uses t.Setenv, so it cannot be used from parallel tests. The suite
+// depends only on the provider and profile packages, so it moves with them
+// if they are ever made importable by out-of-tree providers.
+package conformance
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+)
+
+// Options configures Run.
+type Options struct {
+	// AuthMode is the profile auth mode to test. Defaults to the provider's
+	// first SupportedAuthModes entry.
+	AuthMode provider.AuthMode
+
+	// AuthFixture is a minimal logged-in auth state: file contents keyed by
+	// path. Paths start with an environment variable ($HOME, $CODEX_HOME,
+	// $XDG_CONFIG_HOME, ...) that is expanded against the provider's Env for
+	// a profile, or against the temp system environment for detection and
+	// import. Without a fixture, the logged-in checks are skipped.
+	AuthFixture map[string]string
+}
+
+// Run runs the conformance suite as subtests of t.
+func Run(t *testing.T, newProvider func() provider.Provider, opts Options) {
+	t.Helper()
+
+	t.Run("Identity", func(t *testing.T) {
+		p := newProvider()
+		if p.ID() == "" || p.ID() != strings.ToLower(p.ID()) {
+			t.Errorf("ID() = %q, want a non-empty lowercase id", p.ID())
+		}
+		if p.DisplayName() == "" {
+			t.Error("DisplayName() is empty")
+		}
+		if p.DefaultBin() == "" {
+			t.Error("DefaultBin() is empty")
+		}
+		modes := p.SupportedAuthModes()
+		if len(modes) == 0 {
+			t.Fatal("SupportedAuthModes() is empty")
+		}
+		seen := map[provider.AuthMode]bool{}
+		for _, mode := range modes {
+			if seen[mode] {
+				t.Errorf("SupportedAuthModes() lists %q twice", mode)
+			}
+			seen[mode] = true
+		}
+		if opts.AuthMode != "" && !seen[opts.AuthMode] {
+			t.Errorf("Options.AuthMode %q is not in SupportedAuthModes() %v", opts.AuthMode, modes)
+		}
+	})
+
+	t.Run("AuthFiles", func(t *testing.T) {
+		sys := newSystemEnv(t)
+		files := newProvider().AuthFiles()
+		if len(files) == 0 {
+			t.Fatal("AuthFiles() is empty")
+		}
+		required := false
+		seen := map[string]bool{}
+		for _, spec := range files {
+			if !filepath.IsAbs(spec.Path) {
+				t.Errorf("AuthFiles() path %q is not absolute", spec.Path)
+			}
+			if !within(sys.root, spec.Path) {
+				t.Errorf("AuthFiles() path %q ignores HOME/XDG_CONFIG_HOME/*_HOME (want under %s)", spec.Path, sys.root)
+			}
+			if seen[spec.Path] {
+				t.Errorf("AuthFiles() lists %q twice", spec.Path)
+			}
+			seen[spec.Path] = true
+			required = required || spec.Required
+		}
+		if !required {
+			t.Error("AuthFiles() has no required file")
+		}
+	})
+
+	t.Run("PrepareProfile", func(t *testing.T) {
+		sys := newSystemEnv(t)
+		p := newProvider()
+		prof := newProfile(t, p, opts)
+		before := listTree(t, sys.home)
+
+		if err := p.PrepareProfile(context.Background(), prof); err != nil {
+			t.Fatalf("PrepareProfile() error = %v", err)
+		}
+		if info, err := os.Stat(prof.HomePath()); err != nil || !info.IsDir() {
+			t.Errorf("PrepareProfile() did not create the profile HOME %s", prof.HomePath())
+		}
+		if err := p.PrepareProfile(context.Background(), prof); err != nil {
+			t.Errorf("PrepareProfile() is not idempotent: second call error = %v", err)
+		}
+		if after := listTree(t, sys.home); strings.Join(before, "\n") != strings.Join(after, "\n") {
+			t.Errorf("PrepareProfile() modified the system HOME:\nbefore %v\nafter  %v", before, after)
+		}
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		newSystemEnv(t)
+		p := newProvider()
+		prof := prepareProfile(t, p, opts)
+
+		env, err := p.Env(context.Background(), prof)
+		if err != nil {
+			t.Fatalf("Env() error = %v", err)
+		}
+		if env["HOME"] == "" {
+			t.Error("Env() does not set HOME")
+		}
+		for key, value := range env {
+			if !isHomeVar(key) {
+				continue
+			}
+			if !within(prof.BasePath, value) {
+				t.Errorf("Env() %s=%q points outside the profile %s", key, value, prof.BasePath)
+			}
+		}
+	})
+
+	t.Run("StatusAndLogout", func(t *testing.T) {
+		newSystemEnv(t)
+		p := newProvider()
+		prof := prepareProfile(t, p, opts)
+		ctx := context.Background()
+
+		assertLoggedIn(t, p, prof, false, "fresh profile")
+		if err := p.Logout(ctx, prof); err != nil {
+			t.Errorf("Logout() on a fresh profile error = %v", err)
+		}
+
+		if len(opts.AuthFixture) == 0 {
+			t.Skip("no AuthFixture; skipping logged-in checks")
+		}
+		env, err := p.Env(ctx, prof)
+		if err != nil {
+			t.Fatalf("Env() error = %v", err)
+		}
+		writeFixture(t, opts.AuthFixture, env)
+		assertLoggedIn(t, p, prof, true, "after writing AuthFixture")
+
+		if err := p.Logout(ctx, prof); err != nil {
+			t.Fatalf("Logout() error = %v", err)
+		}
+		assertLoggedIn(t, p, prof, false, "after Logout")
+		if err := p.Logout(ctx, prof); err != nil {
+			t.Errorf("Logout() is not idempotent: second call error = %v", err)
+		}
+	})
+
+	t.Run("DetectAndImport", func(t *testing.T) {
+		sys := newSystemEnv(t)
+		p := newProvider()
+
+		detection, err := p.DetectExistingAuth()
+		if err != nil {
+			t.Fatalf("DetectExistingAuth() error = %v", err)
+		}
+		if detection.Found {
+			t.Errorf("DetectExistingAuth() found auth in an empty HOME: %+v", detection.Primary)
+		}
+
+		if len(opts.AuthFixture) == 0 {
+			t.Skip("no AuthFixture; skipping import checks")
+		}
+		written := writeFixture(t, opts.AuthFixture, sys.env)
+		before := readFiles(t, written)
+
+		detection, err = p.DetectExistingAuth()
+		if err != nil {
+			t.Fatalf("DetectExistingAuth() error = %v", err)
+		}
+		if !detection.Found || detection.Primary == nil {
+			t.Fatalf("DetectExistingAuth() did not find the AuthFixture files %v", written)
+		}
+		if !within(sys.root, detection.Primary.Path) {
+			t.Errorf("DetectExistingAuth() primary %q is outside the temp HOME", detection.Primary.Path)
+		}
+
+		prof := prepareProfile(t, p, opts)
+		copied, err := p.ImportAuth(context.Background(), detection.Primary.Path, prof)
+		if err != nil {
+			t.Fatalf("ImportAuth(%s) error = %v", detection.Primary.Path, err)
+		}
+		if len(copied) == 0 {
+			t.Error("ImportAuth() copied no files")
+		}
+		for _, path := range copied {
+			if !within(prof.BasePath, path) {
+				t.Errorf("ImportAuth() wrote %q outside the profile %s", path, prof.BasePath)
+			}
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("ImportAuth() reported %q but it does not exist", path)
+			}
+		}
+		assertLoggedIn(t, p, prof, true, "after ImportAuth")
+
+		if after := readFiles(t, written); !equalFiles(before, after) {
+			t.Error("DetectExistingAuth()/ImportAuth() modified the source auth files")
+		}
+	})
+}
+
+// systemEnv is the temporary "real" environment a subtest runs in.
+type systemEnv struct {
+	root string
+	home string
+	env  map[string]string
+}
+
+// newSystemEnv points HOME and the tool home variables at a temp dir.
+func newSystemEnv(t *testing.T) systemEnv {
+	t.Helper()
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	if err := os.MkdirAll(home, 0700); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"HOME":            home,
+		"XDG_CONFIG_HOME": filepath.Join(home, ".config"),
+		"CODEX_HOME":      filepath.Join(home, ".codex"),
+		"GEMINI_HOME":     filepath.Join(home, ".gemini"),
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	// Keys in the real environment would make API key modes look logged in.
+	for _, key := range []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY", "GEMINI_API_KEY", "GOOGLE_API_KEY"} {
+		t.Setenv(key, "")
+	}
+	return systemEnv{root: root, home: home, env: env}
+}
+
+func newProfile(t *testing.T, p provider.Provider, opts Options) *profile.Profile {
+	t.Helper()
+	mode := opts.AuthMode
+	if mode == "" {
+		mode = p.SupportedAuthModes()[0]
+	}
+	return &profile.Profile{
+		Name:     "conformance",
+		Provider: p.ID(),
+		AuthMode: string(mode),
+		BasePath: filepath.Join(t.TempDir(), "profiles", p.ID(), "conformance"),
+		Metadata: map[string]string{},
+	}
+}
+
+func prepareProfile(t *testing.T, p provider.Provider, opts Options) *profile.Profile {
+	t.Helper()
+	prof := newProfile(t, p, opts)
+	if err := p.PrepareProfile(context.Background(), prof); err != nil {
+		t.Fatalf("PrepareProfile() error = %v", err)
+	}
+	return prof
+}
+
+func assertLoggedIn(t *testing.T, p provider.Provider, prof *profile.Profile, want bool, when string) {
+	t.Helper()
+	status, err := p.Status(context.Background(), prof)
+	if err != nil {
+		t.Fatalf("Status() %s: error = %v", when, err)
+	}
+	if status == nil {
+		t.Fatalf("Status() %s returned nil", when)
+	}
+	if status.LoggedIn != want {
+		t.Errorf("Status().LoggedIn %s = %v, want %v", when, status.LoggedIn, want)
+	}
+}
+
+// writeFixture writes fixture with its paths expanded against env and
+// returns the written paths, sorted.
+func writeFixture(t *testing.T, fixture map[string]string, env map[string]string) []string {
+	t.Helper()
+	var written []string
+	for rawPath, content := range fixture {
+		path := os.Expand(rawPath, func(key string) string { return env[key] })
+		if !filepath.IsAbs(path) {
+			t.Fatalf("AuthFixture path %q expands to %q; start it with a variable Env sets", rawPath, path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		written = append(written, path)
+	}
+	sort.Strings(written)
+	return written
+}
+
+func readFiles(t *testing.T, paths []string) map[string]string {
+	t.Helper()
+	contents := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read %s: %v", path, err)
+		}
+		contents[path] = string(data)
+	}
+	return contents
+}
+
+func equalFiles(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, content := range a {
+		if b[path] != content {
+			return false
+		}
+	}
+	return true
+}
+
+// listTree returns every path under dir, relative and sorted.
+func listTree(t *testing.T, dir string) []string {
+	t.Helper()
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		paths = append(paths, rel)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk %s: %v", dir, err)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// isHomeVar reports whether an Env key relocates a tool's state directory.
+func isHomeVar(key string) bool {
+	return key == "HOME" || strings.HasSuffix(key, "_HOME") || strings.HasPrefix(key, "XDG_")
+}
+
+// within reports whether path is dir or inside it.
+func within(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -13,6 +13,8 @@
 // Context isolation for caam:
 // - Set HOME to pseudo-home directory to isolate cached Google login tokens.
 // - For Vertex AI profiles, also set CLOUDSDK_CONFIG for gcloud credential isolation.
+// - Vertex AI profiles may use a service account key (GOOGLE_APPLICATION_CREDENTIALS) and a project.
+// - API key and Vertex profiles get a settings.json selecting their auth type.
 //
 // Auth file swapping (PRIMARY use case):
 // - Backup ~/.gemini/settings.json and oauth files after logging in
@@ -31,6 +33,8 @@ import (
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browser"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/passthrough"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
@@ -68,6 +72,20 @@ func (p *Provider) SupportedAuthModes() []provider.AuthMode {
 	}
 }
 
+// Profile metadata keys for Vertex AI profiles.
+const (
+	// MetaVertexProject is the Google Cloud project ID.
+	MetaVertexProject = "vertex_project"
+	// MetaVertexLocation is the Vertex AI region (default us-central1).
+	MetaVertexLocation = "vertex_location"
+	// MetaServiceAccount is the path of a service account JSON key that
+	// PrepareProfile copies into the profile.
+	MetaServiceAccount = "vertex_service_account"
+)
+
+// defaultVertexLocation is used when a project is set without a location.
+const defaultVertexLocation = "us-central1"
+
 // geminiHome returns the Gemini home directory.
 func geminiHome() string {
 	if home := os.Getenv("GEMINI_HOME"); home != "" {
@@ -113,12 +131,23 @@ func (p *Provider) PrepareProfile(ctx context.Context, prof *profile.Profile) er
 		return fmt.Errorf("create .gemini dir: %w", err)
 	}
 
-	// For Vertex AI mode, create gcloud config directory
-	if provider.AuthMode(prof.AuthMode) == provider.AuthModeVertexADC {
+	switch provider.AuthMode(prof.AuthMode) {
+	case provider.AuthModeAPIKey:
+		if err := writeSettingsTemplate(prof, "gemini-api-key"); err != nil {
+			return err
+		}
+	case provider.AuthModeVertexADC:
+		// For Vertex AI mode, create gcloud config directory
 		gcloudDir := filepath.Join(prof.BasePath, "gcloud")
 		if err := os.MkdirAll(gcloudDir, 0700); err != nil {
 			return fmt.Errorf("create gcloud dir: %w", err)
 		}
+		if err := importServiceAccount(prof); err != nil {
+			return err
+		}
+		if err := writeSettingsTemplate(prof, "vertex-ai"); err != nil {
+			return err
+		}
 	}
 
 	// Set up passthrough symlinks
@@ -140,14 +169,129 @@ func (p *Provider) Env(ctx context.Context, prof *profile.Profile) (map[string]s
 		"HOME": prof.HomePath(),
 	}
 
-	// For Vertex AI mode, also set CLOUDSDK_CONFIG for gcloud isolation
-	if provider.AuthMode(prof.AuthMode) == provider.AuthModeVertexADC {
+	switch provider.AuthMode(prof.AuthMode) {
+	case provider.AuthModeAPIKey:
+		// Gemini CLI prefers a .env found above the working directory over
+		// ~/.gemini/.env, so pin the profile's key explicitly.
+		if key := readEnvAPIKey(filepath.Join(prof.HomePath(), ".gemini", ".env")); key != "" {
+			env["GEMINI_API_KEY"] = key
+		}
+	case provider.AuthModeVertexADC:
+		// For Vertex AI mode, also set CLOUDSDK_CONFIG for gcloud isolation
 		env["CLOUDSDK_CONFIG"] = filepath.Join(prof.BasePath, "gcloud")
+
+		project := prof.Metadata[MetaVertexProject]
+		if keyPath := serviceAccountPath(prof); fileExistsGemini(keyPath) {
+			env["GOOGLE_APPLICATION_CREDENTIALS"] = keyPath
+			if project == "" {
+				project = serviceAccountProject(keyPath)
+			}
+		}
+		if project != "" {
+			env["GOOGLE_CLOUD_PROJECT"] = project
+			location := prof.Metadata[MetaVertexLocation]
+			if location == "" {
+				location = defaultVertexLocation
+			}
+			env["GOOGLE_CLOUD_LOCATION"] = location
+		}
 	}
 
 	return env, nil
 }
 
+// serviceAccountPath is where a Vertex profile's service account key lives.
+func serviceAccountPath(prof *profile.Profile) string {
+	return filepath.Join(prof.BasePath, "gcloud", "service_account.json")
+}
+
+// serviceAccountKey is the part of a service account JSON key caam reads.
+type serviceAccountKey struct {
+	Type        string `json:"type"`
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+}
+
+func readServiceAccountKey(path string) (*serviceAccountKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("parse service account key: %w", err)
+	}
+	if key.Type != "service_account" {
+		return nil, fmt.Errorf("%s is not a service account key (type %q)", path, key.Type)
+	}
+	return &key, nil
+}
+
+// serviceAccountProject returns the project_id of a service account key.
+func serviceAccountProject(path string) string {
+	key, err := readServiceAccountKey(path)
+	if err != nil {
+		return ""
+	}
+	return key.ProjectID
+}
+
+// importServiceAccount copies the key named by MetaServiceAccount into the
+// profile, unless the profile already has one.
+func importServiceAccount(prof *profile.Profile) error {
+	source := prof.Metadata[MetaServiceAccount]
+	if source == "" || fileExistsGemini(serviceAccountPath(prof)) {
+		return nil
+	}
+	if _, err := readServiceAccountKey(source); err != nil {
+		return fmt.Errorf("service account key: %w", err)
+	}
+	if err := copyFile(source, serviceAccountPath(prof)); err != nil {
+		return fmt.Errorf("copy service account key: %w", err)
+	}
+	return nil
+}
+
+// writeSettingsTemplate writes a settings.json selecting authType, so Gemini
+// CLI starts in the profile's auth mode instead of prompting. An existing
+// settings.json is left alone.
+func writeSettingsTemplate(prof *profile.Profile, authType string) error {
+	settingsPath := filepath.Join(prof.HomePath(), ".gemini", "settings.json")
+	if _, err := os.Stat(settingsPath); err == nil {
+		return nil
+	}
+	settings := map[string]interface{}{
+		"security": map[string]interface{}{
+			"auth": map[string]interface{}{"selectedType": authType},
+		},
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal settings: %w", err)
+	}
+	if err := atomicWriteFile(settingsPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("write settings.json: %w", err)
+	}
+	return nil
+}
+
+// readEnvAPIKey returns GEMINI_API_KEY from a .env file.
+func readEnvAPIKey(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.TrimSpace(key) != "GEMINI_API_KEY" {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return ""
+}
+
 // Login initiates the authentication flow.
 func (p *Provider) Login(ctx context.Context, prof *profile.Profile) error {
 	switch provider.AuthMode(prof.AuthMode) {
@@ -235,13 +379,26 @@ func (p *Provider) loginWithAPIKey(ctx context.Context, prof *profile.Profile) e
 	return nil
 }
 
-// loginWithVertexADC guides user through gcloud ADC login.
+// loginWithVertexADC guides user through gcloud ADC login. Profiles with a
+// service account key need no login.
 func (p *Provider) loginWithVertexADC(ctx context.Context, prof *profile.Profile) error {
 	env, err := p.Env(ctx, prof)
 	if err != nil {
 		return err
 	}
 
+	if keyPath := serviceAccountPath(prof); fileExistsGemini(keyPath) {
+		key, err := readServiceAccountKey(keyPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Vertex AI mode with service account %s.\n", key.ClientEmail)
+		if env["GOOGLE_CLOUD_PROJECT"] == "" {
+			fmt.Println("No project set; recreate the profile with --project.")
+		}
+		return nil
+	}
+
 	fmt.Println("Vertex AI mode with Application Default Credentials.")
 	fmt.Println("Running: gcloud auth application-default login")
 
@@ -298,6 +455,7 @@ func (p *Provider) Logout(ctx context.Context, prof *profile.Profile) error {
 		filepath.Join(geminiDir, ".env"),
 		filepath.Join(geminiDir, "settings.json"),
 		filepath.Join(geminiDir, "oauth_credentials.json"),
+		serviceAccountPath(prof),
 	}
 	for _, path := range paths {
 		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
@@ -339,6 +497,8 @@ func (p *Provider) Status(ctx context.Context, prof *profile.Profile) (*provider
 		adcPath := filepath.Join(prof.BasePath, "gcloud", "application_default_credentials.json")
 		if _, err := os.Stat(adcPath); err == nil {
 			status.LoggedIn = true
+		} else if fileExistsGemini(serviceAccountPath(prof)) {
+			status.LoggedIn = true
 		}
 	default:
 		// Check for cached Google login tokens
@@ -385,6 +545,11 @@ func (p *Provider) ValidateProfile(ctx context.Context, prof *profile.Profile) e
 		if _, err := os.Stat(gcloudDir); os.IsNotExist(err) {
 			return fmt.Errorf("gcloud config directory missing")
 		}
+		if keyPath := serviceAccountPath(prof); fileExistsGemini(keyPath) {
+			if _, err := readServiceAccountKey(keyPath); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -714,6 +879,34 @@ func atomicWriteFile(path string, data []byte, perm os.FileMode) error {
 	return nil
 }
 
+// SupportsTokenRefresh reports that Gemini OAuth tokens can be refreshed.
+func (p *Provider) SupportsTokenRefresh() bool {
+	return true
+}
+
+// ExtractIdentity extracts the account identity from stored Gemini auth files.
+// settings.json is preferred; oauth_credentials.json is used as a fallback.
+func (p *Provider) ExtractIdentity(authDir string) (*identity.Identity, error) {
+	var lastErr error
+	for _, name := range []string{"settings.json", "oauth_credentials.json"} {
+		id, err := identity.ExtractFromGeminiConfig(filepath.Join(authDir, name))
+		if err == nil {
+			return id, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// TokenExpiry returns the expiry of the OAuth token stored in authDir.
+func (p *Provider) TokenExpiry(authDir string) (time.Time, error) {
+	info, err := health.ParseGeminiExpiry(authDir)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ExpiresAt, nil
+}
+
 // ValidateToken validates that the authentication token works.
 // For passive validation: checks file existence, format, and expiry timestamps.
 // For active validation: attempts minimal API call to Google.
@@ -912,3 +1105,6 @@ func parseGeminiUnixTime(f float64) time.Time {
 
 // Ensure Provider implements the interface.
 var _ provider.Provider = (*Provider)(nil)
+var _ provider.TokenRefresher = (*Provider)(nil)
+var _ provider.IdentityExtractor = (*Provider)(nil)
+var _ provider.ExpiryParser = (*Provider)(nil)
@@ -10,6 +10,7 @@ import (
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/conformance"
 )
 
 // =============================================================================
@@ -1195,3 +1196,146 @@ func writeJSON(t *testing.T, path string, data interface{}) {
 		t.Fatal(err)
 	}
 }
+
+// =============================================================================
+// API Key and Vertex Service Account Tests
+// =============================================================================
+
+func readSelectedAuthType(t *testing.T, prof *profile.Profile) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(prof.HomePath(), ".gemini", "settings.json"))
+	if err != nil {
+		t.Fatalf("read settings.json: %v", err)
+	}
+	var settings struct {
+		Security struct {
+			Auth struct {
+				SelectedType string `json:"selectedType"`
+			} `json:"auth"`
+		} `json:"security"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatalf("parse settings.json: %v", err)
+	}
+	return settings.Security.Auth.SelectedType
+}
+
+func TestAPIKeyProfile(t *testing.T) {
+	prof := &profile.Profile{
+		Name:     "key",
+		Provider: "gemini",
+		AuthMode: string(provider.AuthModeAPIKey),
+		BasePath: t.TempDir(),
+	}
+	p := New()
+	if err := p.PrepareProfile(context.Background(), prof); err != nil {
+		t.Fatalf("PrepareProfile() error = %v", err)
+	}
+	if got := readSelectedAuthType(t, prof); got != "gemini-api-key" {
+		t.Errorf("selectedType = %q, want gemini-api-key", got)
+	}
+
+	env, _ := p.Env(context.Background(), prof)
+	if _, ok := env["GEMINI_API_KEY"]; ok {
+		t.Error("GEMINI_API_KEY should not be set before a key is saved")
+	}
+
+	envPath := filepath.Join(prof.HomePath(), ".gemini", ".env")
+	if err := os.WriteFile(envPath, []byte("# profile key\nexport GEMINI_API_KEY=\"test-key\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	env, _ = p.Env(context.Background(), prof)
+	if env["GEMINI_API_KEY"] != "test-key" {
+		t.Errorf("GEMINI_API_KEY = %q, want test-key", env["GEMINI_API_KEY"])
+	}
+}
+
+func TestVertexServiceAccountProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	keySource := filepath.Join(tmpDir, "sa.json")
+	key := `{"type": "service_account", "project_id": "key-project", "client_email": "ci@key-project.iam.gserviceaccount.com"}`
+	if err := os.WriteFile(keySource, []byte(key), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	prof := &profile.Profile{
+		Name:     "ci",
+		Provider: "gemini",
+		AuthMode: string(provider.AuthModeVertexADC),
+		BasePath: filepath.Join(tmpDir, "profile"),
+		Metadata: map[string]string{MetaServiceAccount: keySource},
+	}
+	p := New()
+	if err := p.PrepareProfile(context.Background(), prof); err != nil {
+		t.Fatalf("PrepareProfile() error = %v", err)
+	}
+	if got := readSelectedAuthType(t, prof); got != "vertex-ai" {
+		t.Errorf("selectedType = %q, want vertex-ai", got)
+	}
+
+	keyPath := filepath.Join(prof.BasePath, "gcloud", "service_account.json")
+	env, _ := p.Env(context.Background(), prof)
+	if env["GOOGLE_APPLICATION_CREDENTIALS"] != keyPath {
+		t.Errorf("GOOGLE_APPLICATION_CREDENTIALS = %q, want %q", env["GOOGLE_APPLICATION_CREDENTIALS"], keyPath)
+	}
+	if env["GOOGLE_CLOUD_PROJECT"] != "key-project" {
+		t.Errorf("GOOGLE_CLOUD_PROJECT = %q, want project from key", env["GOOGLE_CLOUD_PROJECT"])
+	}
+	if env["GOOGLE_CLOUD_LOCATION"] != "us-central1" {
+		t.Errorf("GOOGLE_CLOUD_LOCATION = %q, want default", env["GOOGLE_CLOUD_LOCATION"])
+	}
+
+	// Explicit metadata wins over the key's project.
+	prof.Metadata[MetaVertexProject] = "other-project"
+	prof.Metadata[MetaVertexLocation] = "europe-west4"
+	env, _ = p.Env(context.Background(), prof)
+	if env["GOOGLE_CLOUD_PROJECT"] != "other-project" || env["GOOGLE_CLOUD_LOCATION"] != "europe-west4" {
+		t.Errorf("project/location = %q/%q", env["GOOGLE_CLOUD_PROJECT"], env["GOOGLE_CLOUD_LOCATION"])
+	}
+
+	status, _ := p.Status(context.Background(), prof)
+	if !status.LoggedIn {
+		t.Error("LoggedIn should be true with a service account key")
+	}
+	if err := p.ValidateProfile(context.Background(), prof); err != nil {
+		t.Errorf("ValidateProfile() error = %v", err)
+	}
+
+	if err := p.Logout(context.Background(), prof); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := os.Stat(keyPath); !os.IsNotExist(err) {
+		t.Error("service account key should be removed after logout")
+	}
+}
+
+func TestVertexServiceAccountRejectsOtherKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	keySource := filepath.Join(tmpDir, "adc.json")
+	if err := os.WriteFile(keySource, []byte(`{"type": "authorized_user"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	prof := &profile.Profile{
+		Name:     "bad",
+		Provider: "gemini",
+		AuthMode: string(provider.AuthModeVertexADC),
+		BasePath: filepath.Join(tmpDir, "profile"),
+		Metadata: map[string]string{MetaServiceAccount: keySource},
+	}
+	err := New().PrepareProfile(context.Background(), prof)
+	if err == nil || !strings.Contains(err.Error(), "not a service account key") {
+		t.Errorf("PrepareProfile() error = %v, want service account error", err)
+	}
+}
+
+// =============================================================================
+// Conformance Tests
+// =============================================================================
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func() provider.Provider { return New() }, conformance.Options{
+		AuthFixture: map[string]string{
+			"$HOME/.gemini/settings.json": `{"security": {"auth": {"selectedType": "oauth-personal"}}}`,
+		},
+	})
+}
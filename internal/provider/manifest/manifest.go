@@ -0,0 +1,443 @@
+// Package manifest adds providers declared in YAML files, for tools caam
+// does not ship support for and whose auth is nothing more than a few files
+// (Cursor CLI, Aider, ...). Manifests are YAML rather than TOML so they
+// read like ~/.caam/config.yaml and share its parser; a *.toml file in the
+// providers directory is reported rather than loaded. Each *.yaml file
+// declares one tool:
+//
+//	id: aider                    # defaults to the file name
+//	display_name: Aider
+//	bin: aider                   # defaults to id
+//	auth_modes: [api-key]        # defaults to [oauth]
+//	login: [aider, --login]      # run with HOME pointed at the profile
+//	files:
+//	  - path: ~/.aider/oauth-keys.env
+//	    description: Aider OAuth keys
+//	    required: true
+//
+// Paths start with ~, $HOME or $XDG_CONFIG_HOME (or are absolute) and are
+// resolved against the real HOME for vault backup and activation, and
+// against a profile's pseudo-HOME for isolated profiles. Tools that need
+// more than files, such as token refresh or identity, should be written as
+// a plugin instead (see package plugin).
+package manifest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/passthrough"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+)
+
+// Manifest declares a file-based provider.
+type Manifest struct {
+	ID          string              `yaml:"id"`
+	DisplayName string              `yaml:"display_name"`
+	Bin         string              `yaml:"bin"`
+	AuthModes   []provider.AuthMode `yaml:"auth_modes"`
+	// Login is the command line that logs in; empty means caam can't log
+	// in for this tool and profiles are filled by 'caam backup'.
+	Login []string `yaml:"login"`
+	Files []File   `yaml:"files"`
+
+	// Source is the file the manifest was read from.
+	Source string `yaml:"-"`
+}
+
+// File is one auth file in a manifest.
+type File struct {
+	Path        string `yaml:"path"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+}
+
+// Load reads and validates the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	m.Source = path
+	if m.ID == "" {
+		m.ID = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := m.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Validate checks the fields caam relies on.
+func (m *Manifest) Validate() error {
+	if m.ID == "" || m.ID != strings.ToLower(m.ID) || strings.ContainsAny(m.ID, "./\\ ") {
+		return fmt.Errorf("id %q must be a lowercase name without dots, slashes or spaces", m.ID)
+	}
+	if len(m.Files) == 0 {
+		return fmt.Errorf("no auth files declared")
+	}
+	required := false
+	for _, f := range m.Files {
+		if f.Path == "" {
+			return fmt.Errorf("auth file without a path")
+		}
+		if !strings.HasPrefix(f.Path, "~/") && !strings.HasPrefix(f.Path, "$") && !filepath.IsAbs(f.Path) {
+			return fmt.Errorf("auth file %q must start with ~/, $HOME or $XDG_CONFIG_HOME, or be absolute", f.Path)
+		}
+		required = required || f.Required
+	}
+	if !required {
+		return fmt.Errorf("no auth file is marked required")
+	}
+	return nil
+}
+
+// LoadDir loads every *.yaml and *.yml manifest in dir, sorted by file
+// name. A missing dir has none. Manifests that fail to load, and *.toml
+// files, are skipped and their errors returned alongside the rest.
+func LoadDir(dir string) ([]*Manifest, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{err}
+	}
+	var names []string
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml":
+			names = append(names, entry.Name())
+		case ".toml":
+			errs = append(errs, fmt.Errorf("%s: manifests are YAML; rename it to .yaml", filepath.Join(dir, entry.Name())))
+		}
+	}
+	sort.Strings(names)
+
+	var manifests []*Manifest
+	seen := make(map[string]string)
+	for _, name := range names {
+		m, err := Load(filepath.Join(dir, name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if first, ok := seen[m.ID]; ok {
+			errs = append(errs, fmt.Errorf("%s: id %q is already declared by %s", m.Source, m.ID, first))
+			continue
+		}
+		seen[m.ID] = m.Source
+		manifests = append(manifests, m)
+	}
+	return manifests, errs
+}
+
+// Register adds the manifests in dir to r, skipping IDs r already has (the
+// built-in providers always win). It returns the registered providers and
+// the manifests that failed to load or clashed with a registered ID.
+func Register(r *provider.Registry, dir string) ([]*Provider, []error) {
+	manifests, errs := LoadDir(dir)
+	var registered []*Provider
+	for _, m := range manifests {
+		if _, ok := r.Get(m.ID); ok {
+			errs = append(errs, fmt.Errorf("%s: id %q is already a registered provider", m.Source, m.ID))
+			continue
+		}
+		p := New(m)
+		r.Register(p)
+		registered = append(registered, p)
+	}
+	return registered, errs
+}
+
+// Provider adapts a manifest to provider.Provider.
+type Provider struct {
+	m *Manifest
+}
+
+// New returns the provider m declares. m must be valid.
+func New(m *Manifest) *Provider {
+	return &Provider{m: m}
+}
+
+// Manifest returns the provider's manifest.
+func (p *Provider) Manifest() *Manifest {
+	return p.m
+}
+
+// ID returns the manifest's id.
+func (p *Provider) ID() string {
+	return p.m.ID
+}
+
+// DisplayName returns the manifest's display name, defaulting to its id.
+func (p *Provider) DisplayName() string {
+	if p.m.DisplayName != "" {
+		return p.m.DisplayName
+	}
+	return p.m.ID
+}
+
+// DefaultBin returns the tool binary, defaulting to the id.
+func (p *Provider) DefaultBin() string {
+	if p.m.Bin != "" {
+		return p.m.Bin
+	}
+	return p.m.ID
+}
+
+// SupportedAuthModes returns the manifest's auth modes, defaulting to oauth.
+func (p *Provider) SupportedAuthModes() []provider.AuthMode {
+	if len(p.m.AuthModes) > 0 {
+		return p.m.AuthModes
+	}
+	return []provider.AuthMode{provider.AuthModeOAuth}
+}
+
+// authFilesIn resolves the manifest's files against home, with xdgConfig
+// as $XDG_CONFIG_HOME. Other variables come from the environment.
+func (p *Provider) authFilesIn(home, xdgConfig string) []provider.AuthFileSpec {
+	lookup := func(name string) string {
+		switch name {
+		case "HOME":
+			return home
+		case "XDG_CONFIG_HOME":
+			return xdgConfig
+		}
+		return os.Getenv(name)
+	}
+	specs := make([]provider.AuthFileSpec, 0, len(p.m.Files))
+	for _, f := range p.m.Files {
+		path := f.Path
+		if rest, ok := strings.CutPrefix(path, "~/"); ok {
+			path = filepath.Join(home, rest)
+		}
+		specs = append(specs, provider.AuthFileSpec{
+			Path:        filepath.Clean(os.Expand(path, lookup)),
+			Description: f.Description,
+			Required:    f.Required,
+		})
+	}
+	return specs
+}
+
+// systemAuthFiles resolves the files against the real HOME.
+func (p *Provider) systemAuthFiles() ([]provider.AuthFileSpec, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home dir: %w", err)
+	}
+	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfig == "" {
+		xdgConfig = filepath.Join(homeDir, ".config")
+	}
+	return p.authFilesIn(homeDir, xdgConfig), nil
+}
+
+// profileAuthFiles resolves the files against prof's pseudo-HOME.
+func (p *Provider) profileAuthFiles(prof *profile.Profile) []provider.AuthFileSpec {
+	return p.authFilesIn(prof.HomePath(), prof.XDGConfigPath())
+}
+
+// AuthFiles returns the auth files under the real HOME.
+func (p *Provider) AuthFiles() []provider.AuthFileSpec {
+	files, err := p.systemAuthFiles()
+	if err != nil {
+		return nil
+	}
+	return files
+}
+
+// PrepareProfile creates the profile's pseudo-HOME and passthroughs.
+func (p *Provider) PrepareProfile(ctx context.Context, prof *profile.Profile) error {
+	for _, dir := range []string{prof.HomePath(), prof.XDGConfigPath()} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("create %s: %w", dir, err)
+		}
+	}
+
+	mgr, err := passthrough.NewManager()
+	if err != nil {
+		return fmt.Errorf("create passthrough manager: %w", err)
+	}
+	if err := mgr.SetupPassthroughs(prof.HomePath()); err != nil {
+		return fmt.Errorf("setup passthroughs: %w", err)
+	}
+	return nil
+}
+
+// Env points HOME and XDG_CONFIG_HOME at the profile.
+func (p *Provider) Env(ctx context.Context, prof *profile.Profile) (map[string]string, error) {
+	return map[string]string{
+		"HOME":            prof.HomePath(),
+		"XDG_CONFIG_HOME": prof.XDGConfigPath(),
+	}, nil
+}
+
+// Login runs the manifest's login command in the profile's environment.
+func (p *Provider) Login(ctx context.Context, prof *profile.Profile) error {
+	if len(p.m.Login) == 0 {
+		return fmt.Errorf("%s declares no login command; log in with %s itself and run 'caam backup %s <profile>'", p.m.Source, p.DefaultBin(), p.m.ID)
+	}
+	env, _ := p.Env(ctx, prof)
+
+	cmd := exec.CommandContext(ctx, p.m.Login[0], p.m.Login[1:]...)
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s login: %w", p.m.ID, err)
+	}
+	return nil
+}
+
+// Logout removes the profile's auth files.
+func (p *Provider) Logout(ctx context.Context, prof *profile.Profile) error {
+	for _, spec := range p.profileAuthFiles(prof) {
+		if err := os.Remove(spec.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", spec.Path, err)
+		}
+	}
+	return nil
+}
+
+// Status reports the profile as logged in when its required auth files
+// exist. Contents are not checked: a manifest says where files live, not
+// what they hold.
+func (p *Provider) Status(ctx context.Context, prof *profile.Profile) (*provider.ProfileStatus, error) {
+	status := &provider.ProfileStatus{LoggedIn: true, HasLockFile: prof.IsLocked()}
+	for _, spec := range p.profileAuthFiles(prof) {
+		if !spec.Required {
+			continue
+		}
+		if info, err := os.Stat(spec.Path); err != nil || info.IsDir() {
+			status.LoggedIn = false
+			status.Error = fmt.Sprintf("missing %s", spec.Path)
+			break
+		}
+	}
+	return status, nil
+}
+
+// ValidateProfile checks that the profile is prepared.
+func (p *Provider) ValidateProfile(ctx context.Context, prof *profile.Profile) error {
+	if _, err := os.Stat(prof.HomePath()); err != nil {
+		return fmt.Errorf("home directory missing: %w", err)
+	}
+	return nil
+}
+
+// DetectExistingAuth reports which of the manifest's auth files exist under
+// the real HOME. Contents are not validated.
+func (p *Provider) DetectExistingAuth() (*provider.AuthDetection, error) {
+	files, err := p.systemAuthFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	detection := &provider.AuthDetection{Provider: p.m.ID, Locations: []provider.AuthLocation{}}
+	for _, spec := range files {
+		loc := provider.AuthLocation{Path: spec.Path, Description: spec.Description}
+		if info, err := os.Stat(spec.Path); err == nil && !info.IsDir() {
+			loc.Exists = true
+			loc.IsValid = true
+			loc.LastModified = info.ModTime()
+			loc.FileSize = info.Size()
+		}
+		detection.Locations = append(detection.Locations, loc)
+		if loc.Exists && spec.Required && detection.Primary == nil {
+			locCopy := loc
+			detection.Found = true
+			detection.Primary = &locCopy
+		}
+	}
+	return detection, nil
+}
+
+// ImportAuth copies one of the manifest's auth files from the real HOME to
+// the matching location in the profile.
+func (p *Provider) ImportAuth(ctx context.Context, sourcePath string, prof *profile.Profile) ([]string, error) {
+	systemFiles, err := p.systemAuthFiles()
+	if err != nil {
+		return nil, err
+	}
+	profileFiles := p.profileAuthFiles(prof)
+
+	for i, spec := range systemFiles {
+		if filepath.Clean(spec.Path) != filepath.Clean(sourcePath) {
+			continue
+		}
+		target := profileFiles[i].Path
+		if err := copyFile(sourcePath, target); err != nil {
+			return nil, fmt.Errorf("copy %s: %w", filepath.Base(sourcePath), err)
+		}
+		return []string{target}, nil
+	}
+	return nil, fmt.Errorf("%s is not a %s auth file", sourcePath, p.m.ID)
+}
+
+// ValidateToken reports whether the profile's auth files exist; a manifest
+// gives caam no way to ask the provider, so passive and active checks are
+// the same.
+func (p *Provider) ValidateToken(ctx context.Context, prof *profile.Profile, passive bool) (*provider.ValidationResult, error) {
+	result := &provider.ValidationResult{
+		Provider:  p.m.ID,
+		Profile:   prof.Name,
+		Method:    "passive",
+		CheckedAt: time.Now(),
+	}
+	if !passive {
+		result.Method = "active"
+	}
+	status, _ := p.Status(ctx, prof)
+	result.Valid = status.LoggedIn
+	result.Error = status.Error
+	return result, nil
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,124 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/conformance"
+)
+
+const aiderManifest = `display_name: Aider
+auth_modes: [api-key]
+login: [aider, --login]
+files:
+  - path: ~/.aider/oauth-keys.env
+    description: Aider OAuth keys
+    required: true
+  - path: $XDG_CONFIG_HOME/aider/settings.yml
+    description: Aider settings
+`
+
+func writeManifest(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	m, err := Load(writeManifest(t, t.TempDir(), "aider.yaml", aiderManifest))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if m.ID != "aider" {
+		t.Errorf("ID = %q, want the file name", m.ID)
+	}
+
+	p := New(m)
+	if p.DisplayName() != "Aider" || p.DefaultBin() != "aider" {
+		t.Errorf("DisplayName/DefaultBin = %q/%q", p.DisplayName(), p.DefaultBin())
+	}
+	if modes := p.SupportedAuthModes(); len(modes) != 1 || modes[0] != provider.AuthModeAPIKey {
+		t.Errorf("SupportedAuthModes() = %v", modes)
+	}
+
+	files := p.authFilesIn("/home/u", "/home/u/.config")
+	if len(files) != 2 || files[0].Path != "/home/u/.aider/oauth-keys.env" || !files[0].Required {
+		t.Errorf("auth files = %+v", files)
+	}
+	if files[1].Path != "/home/u/.config/aider/settings.yml" || files[1].Required {
+		t.Errorf("xdg auth file = %+v", files[1])
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"bad id", "id: My Tool\nfiles: [{path: ~/.x, required: true}]\n", "lowercase"},
+		{"no files", "id: x\n", "no auth files"},
+		{"relative path", "id: x\nfiles: [{path: .x/token, required: true}]\n", "must start with"},
+		{"nothing required", "id: x\nfiles: [{path: ~/.x}]\n", "required"},
+		{"not yaml", "id: [\n", "parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeManifest(t, t.TempDir(), "x.yaml", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "aider.yaml", aiderManifest)
+	writeManifest(t, dir, "cursor.yml", "display_name: Cursor\nfiles: [{path: ~/.cursor/cli-config.json, required: true}]\n")
+	writeManifest(t, dir, "codex.yaml", "files: [{path: ~/.codex/auth.json, required: true}]\n")
+	writeManifest(t, dir, "broken.yaml", "files: []\n")
+	writeManifest(t, dir, "README.md", "not a manifest")
+	writeManifest(t, dir, "aider.toml", "id = \"aider\"\n")
+
+	r := provider.NewRegistry()
+	r.Register(New(&Manifest{ID: "codex", Files: []File{{Path: "~/.codex/auth.json", Required: true}}}))
+	registered, errs := Register(r, dir)
+
+	var ids []string
+	for _, p := range registered {
+		ids = append(ids, p.ID())
+	}
+	if strings.Join(ids, ",") != "aider,cursor" {
+		t.Errorf("registered %v, want aider and cursor", ids)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("errors = %v, want the TOML file, the broken manifest and the codex clash", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "aider.toml") {
+		t.Errorf("errors[0] = %v, want the TOML file", errs[0])
+	}
+	if _, ok := r.Get("cursor"); !ok {
+		t.Error("cursor not in registry")
+	}
+
+	if got, errs := Register(provider.NewRegistry(), filepath.Join(dir, "missing")); got != nil || errs != nil {
+		t.Errorf("missing dir = %v, %v; want nothing", got, errs)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	m, err := Load(writeManifest(t, t.TempDir(), "aider.yaml", aiderManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conformance.Run(t, func() provider.Provider { return New(m) }, conformance.Options{
+		AuthFixture: map[string]string{
+			"$HOME/.aider/oauth-keys.env": "AIDER_KEY=test",
+		},
+	})
+}
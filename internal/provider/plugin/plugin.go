@@ -0,0 +1,468 @@
+// Package plugin runs external provider plugins: executables named
+// caam-provider-<id> on PATH that implement a provider for a tool caam does
+// not ship, such as a proprietary internal CLI.
+//
+// Protocol (version 1): caam runs "caam-provider-<id> <method>", writes a
+// JSON request to stdin and reads a JSON response from stdout. A non-zero
+// exit means the call failed; stderr becomes the error message.
+//
+//	describe    {}                      -> {"protocol": 1, "display_name": "...", "default_bin": "...", "auth_modes": ["oauth"]}
+//	auth_files  {"home": "/home/u"}     -> {"files": [{"path": "/home/u/.foo/token", "description": "...", "required": true}]}
+//	status      {"profile": {...}}      -> {"logged_in": true, "account_id": "...", "expires_at": "...", "error": ""}
+//	login       (interactive, see below)
+//
+// auth_files must resolve paths against the given home: caam asks once for
+// the real HOME (vault backup and activation) and once for a profile's
+// pseudo-HOME (isolated profiles). A profile is sent as
+// {"name", "auth_mode", "base_path", "home"}.
+//
+// login keeps the terminal: stdin, stdout and stderr are inherited, the
+// request is in the CAAM_PROVIDER_REQUEST environment variable, HOME points
+// at the profile's pseudo-HOME, and exit status 0 means success.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/passthrough"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+)
+
+// Prefix is the executable name prefix plugins are discovered by.
+const Prefix = "caam-provider-"
+
+// ProtocolVersion is the protocol version caam speaks.
+const ProtocolVersion = 1
+
+// callTimeout bounds non-interactive plugin calls.
+const callTimeout = 10 * time.Second
+
+// RequestEnv carries the login request to the plugin.
+const RequestEnv = "CAAM_PROVIDER_REQUEST"
+
+// Discover returns the plugins on pathList (a PATH-style list), keyed by
+// provider ID. The first executable found for an ID wins, like command
+// lookup.
+func Discover(pathList string) map[string]string {
+	found := make(map[string]string)
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, Prefix) {
+				continue
+			}
+			id := strings.TrimSuffix(strings.TrimPrefix(name, Prefix), ".exe")
+			if id == "" || id != strings.ToLower(id) || strings.ContainsAny(id, ". ") {
+				continue
+			}
+			if _, ok := found[id]; ok {
+				continue
+			}
+			path := filepath.Join(dir, name)
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			found[id] = path
+		}
+	}
+	return found
+}
+
+// Register adds the plugins on $PATH to r, skipping IDs r already has (the
+// built-in providers always win), and returns the registered plugins.
+func Register(r *provider.Registry) []*Provider {
+	var registered []*Provider
+	for id, path := range Discover(os.Getenv("PATH")) {
+		if _, ok := r.Get(id); ok {
+			continue
+		}
+		p := New(id, path)
+		r.Register(p)
+		registered = append(registered, p)
+	}
+	return registered
+}
+
+// Provider adapts a plugin executable to provider.Provider.
+type Provider struct {
+	id   string
+	path string
+
+	once sync.Once
+	desc describeResponse
+	err  error
+}
+
+// New returns the provider for the plugin executable at path.
+func New(id, path string) *Provider {
+	return &Provider{id: id, path: path}
+}
+
+// Path returns the plugin executable.
+func (p *Provider) Path() string {
+	return p.path
+}
+
+type describeResponse struct {
+	Protocol    int                 `json:"protocol"`
+	DisplayName string              `json:"display_name"`
+	DefaultBin  string              `json:"default_bin"`
+	AuthModes   []provider.AuthMode `json:"auth_modes"`
+}
+
+type profileRequest struct {
+	Name     string `json:"name"`
+	AuthMode string `json:"auth_mode"`
+	BasePath string `json:"base_path"`
+	Home     string `json:"home"`
+}
+
+type authFilesResponse struct {
+	Files []provider.AuthFileSpec `json:"files"`
+}
+
+type statusResponse struct {
+	LoggedIn  bool   `json:"logged_in"`
+	AccountID string `json:"account_id"`
+	ExpiresAt string `json:"expires_at"`
+	Error     string `json:"error"`
+}
+
+// describe returns the plugin's self-description, calling it at most once.
+func (p *Provider) describe() (describeResponse, error) {
+	p.once.Do(func() {
+		p.err = p.call(context.Background(), "describe", struct{}{}, &p.desc)
+		if p.err == nil && p.desc.Protocol != ProtocolVersion {
+			p.err = fmt.Errorf("plugin %s speaks protocol %d, caam speaks %d", p.path, p.desc.Protocol, ProtocolVersion)
+		}
+	})
+	return p.desc, p.err
+}
+
+// Describe checks that the plugin answers describe with a supported protocol.
+func (p *Provider) Describe() error {
+	_, err := p.describe()
+	return err
+}
+
+// call runs one non-interactive protocol method.
+func (p *Provider) call(ctx context.Context, method string, req, resp interface{}) error {
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("marshal %s request: %w", method, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, p.path, method)
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("plugin %s %s: %s", p.id, method, msg)
+		}
+		return fmt.Errorf("plugin %s %s: %w", p.id, method, err)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		return fmt.Errorf("plugin %s %s: invalid response: %w", p.id, method, err)
+	}
+	return nil
+}
+
+func requestFor(prof *profile.Profile) profileRequest {
+	return profileRequest{
+		Name:     prof.Name,
+		AuthMode: prof.AuthMode,
+		BasePath: prof.BasePath,
+		Home:     prof.HomePath(),
+	}
+}
+
+// authFilesIn asks the plugin where its auth files live under home.
+func (p *Provider) authFilesIn(home string) ([]provider.AuthFileSpec, error) {
+	var resp authFilesResponse
+	if err := p.call(context.Background(), "auth_files", map[string]string{"home": home}, &resp); err != nil {
+		return nil, err
+	}
+	for _, spec := range resp.Files {
+		if !filepath.IsAbs(spec.Path) {
+			return nil, fmt.Errorf("plugin %s auth_files: path %q is not absolute", p.id, spec.Path)
+		}
+	}
+	return resp.Files, nil
+}
+
+// ID returns the provider ID, taken from the executable name.
+func (p *Provider) ID() string {
+	return p.id
+}
+
+// DisplayName returns the plugin's display name, or its ID if describe fails.
+func (p *Provider) DisplayName() string {
+	if desc, err := p.describe(); err == nil && desc.DisplayName != "" {
+		return desc.DisplayName
+	}
+	return p.id
+}
+
+// DefaultBin returns the tool binary, defaulting to the provider ID.
+func (p *Provider) DefaultBin() string {
+	if desc, err := p.describe(); err == nil && desc.DefaultBin != "" {
+		return desc.DefaultBin
+	}
+	return p.id
+}
+
+// SupportedAuthModes returns the plugin's auth modes, defaulting to oauth.
+func (p *Provider) SupportedAuthModes() []provider.AuthMode {
+	if desc, err := p.describe(); err == nil && len(desc.AuthModes) > 0 {
+		return desc.AuthModes
+	}
+	return []provider.AuthMode{provider.AuthModeOAuth}
+}
+
+// AuthFiles returns the auth files under the real HOME. A failing plugin
+// has none.
+func (p *Provider) AuthFiles() []provider.AuthFileSpec {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	files, err := p.authFilesIn(homeDir)
+	if err != nil {
+		return nil
+	}
+	return files
+}
+
+// PrepareProfile creates the profile's pseudo-HOME and passthroughs.
+func (p *Provider) PrepareProfile(ctx context.Context, prof *profile.Profile) error {
+	for _, dir := range []string{prof.HomePath(), prof.XDGConfigPath()} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("create %s: %w", dir, err)
+		}
+	}
+
+	mgr, err := passthrough.NewManager()
+	if err != nil {
+		return fmt.Errorf("create passthrough manager: %w", err)
+	}
+	if err := mgr.SetupPassthroughs(prof.HomePath()); err != nil {
+		return fmt.Errorf("setup passthroughs: %w", err)
+	}
+	return nil
+}
+
+// Env points HOME and XDG_CONFIG_HOME at the profile.
+func (p *Provider) Env(ctx context.Context, prof *profile.Profile) (map[string]string, error) {
+	return map[string]string{
+		"HOME":            prof.HomePath(),
+		"XDG_CONFIG_HOME": prof.XDGConfigPath(),
+	}, nil
+}
+
+// Login runs the plugin's interactive login in the profile's environment.
+func (p *Provider) Login(ctx context.Context, prof *profile.Profile) error {
+	payload, err := json.Marshal(map[string]profileRequest{"profile": requestFor(prof)})
+	if err != nil {
+		return fmt.Errorf("marshal login request: %w", err)
+	}
+	env, _ := p.Env(ctx, prof)
+
+	cmd := exec.CommandContext(ctx, p.path, "login")
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Env = append(cmd.Env, RequestEnv+"="+string(payload))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s login: %w", p.id, err)
+	}
+	return nil
+}
+
+// Logout removes the profile's auth files.
+func (p *Provider) Logout(ctx context.Context, prof *profile.Profile) error {
+	files, err := p.authFilesIn(prof.HomePath())
+	if err != nil {
+		return err
+	}
+	for _, spec := range files {
+		if err := os.Remove(spec.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove %s: %w", spec.Path, err)
+		}
+	}
+	return nil
+}
+
+// Status asks the plugin whether the profile is logged in.
+func (p *Provider) Status(ctx context.Context, prof *profile.Profile) (*provider.ProfileStatus, error) {
+	var resp statusResponse
+	if err := p.call(ctx, "status", map[string]profileRequest{"profile": requestFor(prof)}, &resp); err != nil {
+		return nil, err
+	}
+	return &provider.ProfileStatus{
+		LoggedIn:    resp.LoggedIn,
+		AccountID:   resp.AccountID,
+		ExpiresAt:   resp.ExpiresAt,
+		Error:       resp.Error,
+		HasLockFile: prof.IsLocked(),
+	}, nil
+}
+
+// ValidateProfile checks that the plugin answers and the profile is prepared.
+func (p *Provider) ValidateProfile(ctx context.Context, prof *profile.Profile) error {
+	if err := p.Describe(); err != nil {
+		return err
+	}
+	if _, err := os.Stat(prof.HomePath()); err != nil {
+		return fmt.Errorf("home directory missing: %w", err)
+	}
+	return nil
+}
+
+// DetectExistingAuth reports which of the plugin's auth files exist under
+// the real HOME. Contents are not validated.
+func (p *Provider) DetectExistingAuth() (*provider.AuthDetection, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home dir: %w", err)
+	}
+	files, err := p.authFilesIn(homeDir)
+	if err != nil {
+		return nil, err
+	}
+
+	detection := &provider.AuthDetection{Provider: p.id, Locations: []provider.AuthLocation{}}
+	for _, spec := range files {
+		loc := provider.AuthLocation{Path: spec.Path, Description: spec.Description}
+		if info, err := os.Stat(spec.Path); err == nil && !info.IsDir() {
+			loc.Exists = true
+			loc.IsValid = true
+			loc.LastModified = info.ModTime()
+			loc.FileSize = info.Size()
+		}
+		detection.Locations = append(detection.Locations, loc)
+		if loc.Exists && spec.Required && detection.Primary == nil {
+			locCopy := loc
+			detection.Found = true
+			detection.Primary = &locCopy
+		}
+	}
+	return detection, nil
+}
+
+// ImportAuth copies one of the plugin's auth files from the real HOME to
+// the matching location in the profile.
+func (p *Provider) ImportAuth(ctx context.Context, sourcePath string, prof *profile.Profile) ([]string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("get home dir: %w", err)
+	}
+	systemFiles, err := p.authFilesIn(homeDir)
+	if err != nil {
+		return nil, err
+	}
+	profileFiles, err := p.authFilesIn(prof.HomePath())
+	if err != nil {
+		return nil, err
+	}
+	if len(systemFiles) != len(profileFiles) {
+		return nil, fmt.Errorf("plugin %s auth_files: file lists differ between homes", p.id)
+	}
+
+	for i, spec := range systemFiles {
+		if filepath.Clean(spec.Path) != filepath.Clean(sourcePath) {
+			continue
+		}
+		target := profileFiles[i].Path
+		if err := copyFile(sourcePath, target); err != nil {
+			return nil, fmt.Errorf("copy %s: %w", filepath.Base(sourcePath), err)
+		}
+		return []string{target}, nil
+	}
+	return nil, fmt.Errorf("%s is not a %s auth file", sourcePath, p.id)
+}
+
+// ValidateToken reports the plugin's status; plugins validate their own
+// tokens, so passive and active checks are the same.
+func (p *Provider) ValidateToken(ctx context.Context, prof *profile.Profile, passive bool) (*provider.ValidationResult, error) {
+	result := &provider.ValidationResult{
+		Provider:  p.id,
+		Profile:   prof.Name,
+		Method:    "passive",
+		CheckedAt: time.Now(),
+	}
+	if !passive {
+		result.Method = "active"
+	}
+
+	status, err := p.Status(ctx, prof)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Valid = status.LoggedIn
+	if !status.LoggedIn {
+		result.Error = status.Error
+		if result.Error == "" {
+			result.Error = "not logged in"
+		}
+	}
+	if status.ExpiresAt != "" {
+		if t, err := time.Parse(time.RFC3339, status.ExpiresAt); err == nil {
+			result.ExpiresAt = t
+		}
+	}
+	return result, nil
+}
+
+func copyFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
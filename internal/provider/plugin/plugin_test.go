@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/conformance"
+)
+
+// fooPlugin is a protocol v1 plugin whose only auth file is ~/.foo/token.
+const fooPlugin = `#!/bin/sh
+req=$(cat)
+home=$(printf '%s' "$req" | sed -n 's/.*"home":"\([^"]*\)".*/\1/p')
+case "$1" in
+describe)
+  echo '{"protocol": 1, "display_name": "Foo CLI", "default_bin": "foo", "auth_modes": ["oauth", "api-key"]}' ;;
+auth_files)
+  printf '{"files": [{"path": "%s/.foo/token", "description": "Foo token", "required": true}]}\n' "$home" ;;
+status)
+  if [ -f "$home/.foo/token" ]; then
+    printf '{"logged_in": true, "account_id": "%s"}\n' "$(cat "$home/.foo/token")"
+  else
+    echo '{"logged_in": false}'
+  fi ;;
+*)
+  echo "unknown method $1" >&2; exit 2 ;;
+esac
+`
+
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin test scripts need /bin/sh")
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	skipWithoutShell(t)
+	first, second := t.TempDir(), t.TempDir()
+	want := writePlugin(t, first, "caam-provider-foo", fooPlugin)
+	writePlugin(t, second, "caam-provider-foo", fooPlugin)
+	writePlugin(t, second, "caam-provider-bar", fooPlugin)
+	if err := os.WriteFile(filepath.Join(second, "caam-provider-noexec"), []byte(fooPlugin), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writePlugin(t, second, "caam-provider-Bad", fooPlugin)
+
+	found := Discover(first + string(os.PathListSeparator) + second + string(os.PathListSeparator) + filepath.Join(first, "missing"))
+	if len(found) != 2 {
+		t.Fatalf("Discover() = %v, want foo and bar", found)
+	}
+	if found["foo"] != want {
+		t.Errorf("Discover()[foo] = %q, want first PATH entry %q", found["foo"], want)
+	}
+}
+
+func TestRegisterSkipsBuiltins(t *testing.T) {
+	skipWithoutShell(t)
+	dir := t.TempDir()
+	writePlugin(t, dir, "caam-provider-foo", fooPlugin)
+	writePlugin(t, dir, "caam-provider-codex", fooPlugin)
+	t.Setenv("PATH", dir)
+
+	r := provider.NewRegistry()
+	builtin := New("codex", "/builtin")
+	r.Register(builtin)
+
+	registered := Register(r)
+	if len(registered) != 1 || registered[0].ID() != "foo" {
+		t.Fatalf("Register() = %v, want only foo", registered)
+	}
+	if got, _ := r.Get("codex"); got != builtin {
+		t.Error("Register() replaced a built-in provider")
+	}
+}
+
+func TestProviderProtocol(t *testing.T) {
+	skipWithoutShell(t)
+	p := New("foo", writePlugin(t, t.TempDir(), "caam-provider-foo", fooPlugin))
+
+	if err := p.Describe(); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	if p.DisplayName() != "Foo CLI" || p.DefaultBin() != "foo" || len(p.SupportedAuthModes()) != 2 {
+		t.Errorf("describe = %q %q %v", p.DisplayName(), p.DefaultBin(), p.SupportedAuthModes())
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	files := p.AuthFiles()
+	if len(files) != 1 || files[0].Path != filepath.Join(home, ".foo", "token") || !files[0].Required {
+		t.Errorf("AuthFiles() = %+v", files)
+	}
+
+	prof := &profile.Profile{Name: "work", Provider: "foo", AuthMode: "oauth", BasePath: t.TempDir()}
+	token := filepath.Join(prof.HomePath(), ".foo", "token")
+	if err := os.MkdirAll(filepath.Dir(token), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(token, []byte("alice"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	status, err := p.Status(context.Background(), prof)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if !status.LoggedIn || status.AccountID != "alice" {
+		t.Errorf("Status() = %+v", status)
+	}
+}
+
+func TestProviderErrors(t *testing.T) {
+	skipWithoutShell(t)
+	dir := t.TempDir()
+
+	old := New("old", writePlugin(t, dir, "caam-provider-old", "#!/bin/sh\necho '{\"protocol\": 99}'\n"))
+	if err := old.Describe(); err == nil {
+		t.Error("Describe() accepted an unsupported protocol version")
+	}
+	if old.DisplayName() != "old" {
+		t.Errorf("DisplayName() fallback = %q, want id", old.DisplayName())
+	}
+
+	broken := New("broken", writePlugin(t, dir, "caam-provider-broken", "#!/bin/sh\ncat >/dev/null\necho 'token store locked' >&2\nexit 1\n"))
+	prof := &profile.Profile{Name: "work", BasePath: t.TempDir()}
+	if _, err := broken.Status(context.Background(), prof); err == nil || err.Error() != "plugin broken status: token store locked" {
+		t.Errorf("Status() error = %v, want the plugin's stderr", err)
+	}
+	if files := broken.AuthFiles(); files != nil {
+		t.Errorf("AuthFiles() of a failing plugin = %v, want none", files)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	skipWithoutShell(t)
+	path := writePlugin(t, t.TempDir(), "caam-provider-foo", fooPlugin)
+	conformance.Run(t, func() provider.Provider { return New("foo", path) }, conformance.Options{
+		AuthFixture: map[string]string{
+			"$HOME/.foo/token": "alice",
+		},
+	})
+}
@@ -3,8 +3,10 @@ package provider
 
 import (
 	"context"
+	"sort"
 	"time"
 
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 )
 
@@ -20,9 +22,9 @@ const (
 
 // AuthFileSpec describes where a tool stores authentication credentials.
 type AuthFileSpec struct {
-	Path        string // Absolute path to the auth file
-	Description string // Human-readable description
-	Required    bool   // Whether this file must exist for auth to work
+	Path        string `json:"path"`        // Absolute path to the auth file
+	Description string `json:"description"` // Human-readable description
+	Required    bool   `json:"required"`    // Whether this file must exist for auth to work
 }
 
 // AuthLocation represents a detected auth file location with metadata.
@@ -67,6 +69,72 @@ type DeviceCodeProvider interface {
 	LoginWithDeviceCode(ctx context.Context, p *profile.Profile) error
 }
 
+// TokenRefresher is implemented by providers whose stored OAuth tokens can be
+// refreshed without going through a browser login again.
+type TokenRefresher interface {
+	Provider
+
+	// SupportsTokenRefresh returns true if stored tokens can be refreshed.
+	SupportsTokenRefresh() bool
+}
+
+// IdentityExtractor is implemented by providers that can extract account
+// identity (email, plan) from a directory of stored auth files, such as a
+// vault profile directory.
+type IdentityExtractor interface {
+	Provider
+
+	// ExtractIdentity reads the auth files in authDir and returns the account identity.
+	ExtractIdentity(authDir string) (*identity.Identity, error)
+}
+
+// ExpiryParser is implemented by providers that can determine when the token
+// stored in a directory of auth files expires.
+type ExpiryParser interface {
+	Provider
+
+	// TokenExpiry returns the expiry of the token stored in authDir.
+	TokenExpiry(authDir string) (time.Time, error)
+}
+
+// Capabilities summarizes what a provider supports, derived from the optional
+// interfaces it implements.
+type Capabilities struct {
+	ID                 string         `json:"id"`
+	DisplayName        string         `json:"display_name"`
+	DefaultBin         string         `json:"default_bin"`
+	AuthModes          []AuthMode     `json:"auth_modes"`
+	DeviceCode         bool           `json:"device_code"`
+	TokenRefresh       bool           `json:"token_refresh"`
+	IdentityExtraction bool           `json:"identity_extraction"`
+	ExpiryParsing      bool           `json:"expiry_parsing"`
+	AuthFiles          []AuthFileSpec `json:"auth_files"`
+}
+
+// CapabilitiesOf reports the capabilities of a provider.
+func CapabilitiesOf(p Provider) Capabilities {
+	caps := Capabilities{
+		ID:          p.ID(),
+		DisplayName: p.DisplayName(),
+		DefaultBin:  p.DefaultBin(),
+		AuthModes:   p.SupportedAuthModes(),
+		AuthFiles:   p.AuthFiles(),
+	}
+	if dc, ok := p.(DeviceCodeProvider); ok {
+		caps.DeviceCode = dc.SupportsDeviceCode()
+	}
+	if tr, ok := p.(TokenRefresher); ok {
+		caps.TokenRefresh = tr.SupportsTokenRefresh()
+	}
+	if _, ok := p.(IdentityExtractor); ok {
+		caps.IdentityExtraction = true
+	}
+	if _, ok := p.(ExpiryParser); ok {
+		caps.ExpiryParsing = true
+	}
+	return caps
+}
+
 // ProfileStatus represents the current authentication state of a profile.
 type ProfileStatus struct {
 	LoggedIn    bool   // Whether the profile has valid auth credentials
@@ -169,6 +237,16 @@ func (r *Registry) All() []Provider {
 	return result
 }
 
+// Capabilities returns the capabilities of all registered providers, sorted by ID.
+func (r *Registry) Capabilities() []Capabilities {
+	result := make([]Capabilities, 0, len(r.providers))
+	for _, p := range r.providers {
+		result = append(result, CapabilitiesOf(p))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
 // IDs returns the IDs of all registered providers.
 func (r *Registry) IDs() []string {
 	result := make([]string, 0, len(r.providers))
@@ -186,6 +264,9 @@ type ProviderMeta struct {
 	DisplayName string // Human-friendly name
 	AccountURL  string // URL to the provider's account/console page
 	Description string // Short description of the account page
+
+	UsageURL         string // URL to the provider's usage/quota dashboard
+	UsageDescription string // Short description of the usage page
 }
 
 // providerMetaRegistry holds static metadata for all known providers.
@@ -195,18 +276,27 @@ var providerMetaRegistry = map[string]ProviderMeta{
 		DisplayName: "Codex (OpenAI)",
 		AccountURL:  "https://platform.openai.com/account",
 		Description: "OpenAI Platform account settings",
+
+		UsageURL:         "https://platform.openai.com/usage",
+		UsageDescription: "OpenAI Platform usage",
 	},
 	"claude": {
 		ID:          "claude",
 		DisplayName: "Claude (Anthropic)",
 		AccountURL:  "https://console.anthropic.com/",
 		Description: "Anthropic Console dashboard",
+
+		UsageURL:         "https://console.anthropic.com/settings/usage",
+		UsageDescription: "Anthropic Console usage",
 	},
 	"gemini": {
 		ID:          "gemini",
 		DisplayName: "Gemini (Google)",
 		AccountURL:  "https://aistudio.google.com/",
 		Description: "Google AI Studio dashboard",
+
+		UsageURL:         "https://one.google.com/about/google-ai-plans/",
+		UsageDescription: "Google AI subscription",
 	},
 }
 
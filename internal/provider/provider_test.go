@@ -382,6 +382,9 @@ func TestAllProviderMeta(t *testing.T) {
 		if meta.AccountURL == "" {
 			t.Errorf("provider %q has empty AccountURL", meta.ID)
 		}
+		if meta.UsageURL == "" || meta.UsageDescription == "" {
+			t.Errorf("provider %q has no usage page", meta.ID)
+		}
 		if meta.DisplayName == "" {
 			t.Errorf("provider %q has empty DisplayName", meta.ID)
 		}
@@ -438,3 +441,48 @@ func TestProviderMetaStruct(t *testing.T) {
 		t.Errorf("Description = %q, want %q", meta.Description, "Test account page")
 	}
 }
+
+// refreshingTestProvider adds optional capabilities on top of testProvider.
+type refreshingTestProvider struct {
+	testProvider
+}
+
+func (p *refreshingTestProvider) SupportsTokenRefresh() bool { return true }
+func (p *refreshingTestProvider) TokenExpiry(authDir string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func TestCapabilitiesOf(t *testing.T) {
+	basic := CapabilitiesOf(&testProvider{id: "basic", displayName: "Basic", defaultBin: "basic"})
+	if basic.ID != "basic" || basic.DefaultBin != "basic" {
+		t.Errorf("unexpected identity fields: %+v", basic)
+	}
+	if basic.DeviceCode || basic.TokenRefresh || basic.IdentityExtraction || basic.ExpiryParsing {
+		t.Errorf("basic provider should report no optional capabilities: %+v", basic)
+	}
+
+	rich := CapabilitiesOf(&refreshingTestProvider{testProvider{id: "rich"}})
+	if !rich.TokenRefresh || !rich.ExpiryParsing {
+		t.Errorf("expected token refresh and expiry parsing: %+v", rich)
+	}
+	if rich.IdentityExtraction || rich.DeviceCode {
+		t.Errorf("unexpected capabilities: %+v", rich)
+	}
+}
+
+func TestRegistryCapabilitiesSorted(t *testing.T) {
+	r := NewRegistry()
+	for _, id := range []string{"zeta", "alpha", "mid"} {
+		r.Register(&testProvider{id: id})
+	}
+
+	caps := r.Capabilities()
+	if len(caps) != 3 {
+		t.Fatalf("Capabilities() len = %d, want 3", len(caps))
+	}
+	for i, want := range []string{"alpha", "mid", "zeta"} {
+		if caps[i].ID != want {
+			t.Errorf("caps[%d].ID = %q, want %q", i, caps[i].ID, want)
+		}
+	}
+}
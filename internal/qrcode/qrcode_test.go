@@ -0,0 +1,101 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncode_Golden(t *testing.T) {
+	// Reference matrix from an independent QR implementation.
+	want := []string{
+		"111111100111101111111",
+		"100000100110101000001",
+		"101110101101101011101",
+		"101110101100101011101",
+		"101110101001101011101",
+		"100000101100101000001",
+		"111111101010101111111",
+		"000000001011100000000",
+		"101111100000101111100",
+		"011101010010100100001",
+		"001100110101010011110",
+		"111010000100000110100",
+		"111010100001010010101",
+		"000000001001111001001",
+		"111111100010101100010",
+		"100000101111111001001",
+		"101110101000100100100",
+		"101110101110100100100",
+		"101110101001010011100",
+		"100000100110000110100",
+		"111111101011010011110",
+	}
+	c, err := Encode("hi")
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if c.Size != len(want) {
+		t.Fatalf("Size = %d, want %d", c.Size, len(want))
+	}
+	for y, row := range want {
+		var got strings.Builder
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				got.WriteByte('1')
+			} else {
+				got.WriteByte('0')
+			}
+		}
+		if got.String() != row {
+			t.Errorf("row %d = %s, want %s", y, got.String(), row)
+		}
+	}
+}
+
+func TestEncode_Versions(t *testing.T) {
+	tests := []struct {
+		n        int
+		wantSize int
+	}{
+		{14, 21},  // version 1
+		{15, 25},  // version 2
+		{36, 29},  // a typical device login URL
+		{213, 57}, // version 10, the largest supported
+	}
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.n))
+		if err != nil {
+			t.Fatalf("Encode(%d bytes) error = %v", tt.n, err)
+		}
+		if c.Size != tt.wantSize {
+			t.Errorf("Encode(%d bytes) size = %d, want %d", tt.n, c.Size, tt.wantSize)
+		}
+	}
+	if _, err := Encode(strings.Repeat("a", 214)); err == nil {
+		t.Error("Encode(214 bytes) should fail")
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	c, err := Encode("hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(c.Terminal(2), "\n"), "\n")
+	// 21 modules + 2×2 quiet = 25 rows, two per line.
+	if len(lines) != 13 {
+		t.Fatalf("lines = %d, want 13", len(lines))
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != c.TerminalWidth(2) {
+			t.Errorf("line %d width = %d, want %d", i, n, c.TerminalWidth(2))
+		}
+	}
+	// The top quiet zone is light; the next line starts the dark finder.
+	if lines[0] != strings.Repeat("█", 25) {
+		t.Errorf("first line = %q, want all light", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "██ ") {
+		t.Errorf("second line = %q, want quiet zone then the finder's dark edge", lines[1])
+	}
+}
@@ -55,9 +55,13 @@ func RefreshProfile(ctx context.Context, provider, profile string, vault *authfi
 		}
 	}
 
-	vaultPath := vault.ProfilePath(provider, profile)
+	// An encrypted vault is refreshed in a plaintext copy, then sealed back.
+	vaultPath, cleanup, err := vault.PlainProfileDir(provider, profile)
+	if err != nil {
+		return fmt.Errorf("open profile: %w", err)
+	}
+	defer cleanup()
 
-	var err error
 	switch provider {
 	case "claude":
 		err = refreshClaude(ctx, vaultPath)
@@ -72,6 +76,9 @@ func RefreshProfile(ctx context.Context, provider, profile string, vault *authfi
 	if err != nil {
 		return err
 	}
+	if err := vault.SavePlainProfileDir(provider, profile, vaultPath); err != nil {
+		return fmt.Errorf("save refreshed auth: %w", err)
+	}
 
 	// If the profile was active, restore the updated files to the active location
 	if isActive && len(preRefreshState) > 0 {
@@ -288,7 +295,7 @@ func snapshotMatchesProfile(fileSet authfile.AuthFileSet, vault *authfile.Vault,
 			continue
 		}
 		backupPath := vault.BackupPath(fileSet.Tool, profile, filepath.Base(spec.Path))
-		backupData, err := os.ReadFile(backupPath)
+		backupData, err := vault.ReadFile(backupPath)
 		if err != nil {
 			return false
 		}
@@ -1,13 +1,16 @@
 package refresh
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 )
 
@@ -550,6 +553,55 @@ func TestSnapshotMatchesProfile_Mismatch(t *testing.T) {
 	}
 }
 
+func TestRefreshProfile_KeepsSymlinkActivation(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	spmCfg := config.DefaultSPMConfig()
+	spmCfg.Runtime.ActivationMode = "symlink"
+	if err := spmCfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The vault the daemon builds, configured like the CLI's.
+	vault := authfile.NewVault(filepath.Join(tmpDir, "vault"))
+	authfile.ApplyVaultSettings(vault)
+
+	profileAuth := vault.BackupPath("codex", "work", "auth.json")
+	if err := os.MkdirAll(filepath.Dir(profileAuth), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(profileAuth, []byte(`{"refresh_token":"old-refresh","access_token":"old-access"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	fileSet := authfile.CodexAuthFiles()
+	if err := vault.Restore(fileSet, "work"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	originalRefresh := RefreshCodexToken
+	t.Cleanup(func() { RefreshCodexToken = originalRefresh })
+	RefreshCodexToken = func(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+		return &TokenResponse{AccessToken: "new-access", RefreshToken: "new-refresh", ExpiresIn: 3600}, nil
+	}
+
+	if err := RefreshProfile(context.Background(), "codex", "work", vault, nil); err != nil {
+		t.Fatalf("RefreshProfile() error = %v", err)
+	}
+
+	live := fileSet.Files[0].Path
+	info, err := os.Lstat(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Fatal("live auth.json should still be a symlink into the vault")
+	}
+	if data, _ := os.ReadFile(live); !strings.Contains(string(data), "new-access") {
+		t.Errorf("live auth = %s, want the refreshed token", data)
+	}
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -10,6 +10,7 @@
 package rotation
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"sort"
@@ -21,6 +22,43 @@ import (
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
 )
 
+// ErrNoProfiles indicates there were no user profiles to select from.
+var ErrNoProfiles = errors.New("no profiles available")
+
+// ErrAllInCooldown indicates every candidate profile is in cooldown.
+var ErrAllInCooldown = errors.New("all profiles are in cooldown")
+
+// SelectionError is returned by Select when no profile can be chosen. It
+// unwraps to ErrNoProfiles or ErrAllInCooldown.
+type SelectionError struct {
+	Tool string
+	Err  error
+
+	// SystemOnly is set when profiles existed but all were system profiles.
+	SystemOnly bool
+
+	// Excluded is set when the only user profiles were excluded from
+	// selection (see Selector.SetExcluded).
+	Excluded bool
+}
+
+func (e *SelectionError) Error() string {
+	switch {
+	case e.Err == ErrAllInCooldown:
+		return fmt.Sprintf("all profiles for %s are in cooldown", e.Tool)
+	case e.Excluded:
+		return fmt.Sprintf("no selectable profiles for %s (the rest are quarantined)", e.Tool)
+	case e.SystemOnly:
+		return fmt.Sprintf("no user profiles available for %s (only system profiles found)", e.Tool)
+	default:
+		return fmt.Sprintf("no profiles available for %s", e.Tool)
+	}
+}
+
+func (e *SelectionError) Unwrap() error {
+	return e.Err
+}
+
 // Algorithm identifies a rotation algorithm.
 type Algorithm string
 
@@ -41,11 +79,30 @@ type Reason struct {
 	Positive bool   // True if this is a good thing, false if it's a problem
 }
 
+// Factor is one scoring component's contribution to a profile's score.
+type Factor struct {
+	Name   string  // Component, e.g. "health" or "recency"
+	Points float64 // Points added (negative for penalties)
+}
+
 // ProfileScore holds scoring information for a single profile.
 type ProfileScore struct {
 	Name    string   // Profile name
 	Score   float64  // Numerical score (higher is better)
 	Reasons []Reason // Human-readable explanations
+	Factors []Factor // Score breakdown by component, in the order applied
+}
+
+// Add adds points to the score under the named factor.
+func (ps *ProfileScore) Add(factor string, points float64) {
+	ps.Score += points
+	for i := range ps.Factors {
+		if ps.Factors[i].Name == factor {
+			ps.Factors[i].Points += points
+			return
+		}
+	}
+	ps.Factors = append(ps.Factors, Factor{Name: factor, Points: points})
 }
 
 // Result is the output of a rotation selection.
@@ -55,6 +112,30 @@ type Result struct {
 	Algorithm    Algorithm      // Which algorithm was used
 }
 
+// Pin makes profile the selection if it was scored and isn't in cooldown,
+// moving it to the front of the alternatives. It reports whether it did.
+func (r *Result) Pin(profile string) bool {
+	if profile == "" {
+		return false
+	}
+	for i, alt := range r.Alternatives {
+		if alt.Name != profile {
+			continue
+		}
+		for _, f := range alt.Factors {
+			if f.Name == "cooldown" {
+				return false
+			}
+		}
+		alt.Reasons = append([]Reason{{Text: "Pinned", Positive: true}}, alt.Reasons...)
+		copy(r.Alternatives[1:i+1], r.Alternatives[:i])
+		r.Alternatives[0] = alt
+		r.Selected = profile
+		return true
+	}
+	return false
+}
+
 // UsageInfo represents real-time rate limit usage for a profile.
 type UsageInfo struct {
 	ProfileName      string
@@ -73,6 +154,8 @@ type Selector struct {
 	rng         *rand.Rand
 	avoidRecent time.Duration // Don't select profiles used within this duration
 	usageData   map[string]*UsageInfo // Real-time usage data by profile name
+	excluded    map[string]bool       // Profiles never to select
+	now         func() time.Time
 }
 
 // NewSelector creates a new profile selector.
@@ -83,6 +166,7 @@ func NewSelector(algorithm Algorithm, healthStore *health.Storage, db *caamdb.DB
 		db:          db,
 		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
 		avoidRecent: 30 * time.Minute, // Default: avoid profiles used in last 30 min
+		now:         time.Now,
 	}
 }
 
@@ -93,6 +177,14 @@ func (s *Selector) SetRNG(rng *rand.Rand) {
 	s.rng = rng
 }
 
+// SetClock sets the source of the current time used for cooldowns and
+// recency (useful for testing).
+func (s *Selector) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
 // SetAvoidRecent sets how long to avoid recently-used profiles.
 func (s *Selector) SetAvoidRecent(d time.Duration) {
 	s.mu.Lock()
@@ -107,6 +199,17 @@ func (s *Selector) SetUsageData(usage map[string]*UsageInfo) {
 	s.usageData = usage
 }
 
+// SetExcluded sets profiles that are never selected, such as quarantined
+// ones.
+func (s *Selector) SetExcluded(profiles []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.excluded = make(map[string]bool, len(profiles))
+	for _, p := range profiles {
+		s.excluded[p] = true
+	}
+}
+
 // Select chooses a profile from the given list using the configured algorithm.
 // Returns an error if no profiles are available or all are in cooldown.
 func (s *Selector) Select(tool string, profiles []string, currentProfile string) (*Result, error) {
@@ -114,19 +217,24 @@ func (s *Selector) Select(tool string, profiles []string, currentProfile string)
 	defer s.mu.Unlock()
 
 	if len(profiles) == 0 {
-		return nil, fmt.Errorf("no profiles available for %s", tool)
+		return nil, &SelectionError{Tool: tool, Err: ErrNoProfiles}
 	}
 
-	// Filter out system profiles (those starting with _)
+	// Filter out system profiles (those starting with _) and excluded ones
 	var available []string
+	excluded := 0
 	for _, p := range profiles {
-		if !strings.HasPrefix(p, "_") {
+		switch {
+		case strings.HasPrefix(p, "_"):
+		case s.excluded[p]:
+			excluded++
+		default:
 			available = append(available, p)
 		}
 	}
 
 	if len(available) == 0 {
-		return nil, fmt.Errorf("no user profiles available for %s (only system profiles found)", tool)
+		return nil, &SelectionError{Tool: tool, Err: ErrNoProfiles, SystemOnly: excluded == 0, Excluded: excluded > 0}
 	}
 
 	// If only one profile, return it
@@ -138,6 +246,7 @@ func (s *Selector) Select(tool string, profiles []string, currentProfile string)
 				Name:    available[0],
 				Score:   100,
 				Reasons: []Reason{{Text: "Only available profile", Positive: true}},
+				Factors: []Factor{{Name: "only", Points: 100}},
 			}},
 		}, nil
 	}
@@ -158,7 +267,7 @@ func (s *Selector) selectRandom(tool string, profiles []string) (*Result, error)
 	var eligible []string
 	var inCooldown []ProfileScore
 
-	now := time.Now()
+	now := s.now()
 	for _, p := range profiles {
 		if s.isInCooldown(tool, p, now) {
 			remaining := s.cooldownRemaining(tool, p, now)
@@ -166,6 +275,7 @@ func (s *Selector) selectRandom(tool string, profiles []string) (*Result, error)
 				Name:    p,
 				Score:   -10000,
 				Reasons: []Reason{{Text: fmt.Sprintf("In cooldown (%s remaining)", formatDuration(remaining)), Positive: false}},
+				Factors: []Factor{{Name: "cooldown", Points: -10000}},
 			})
 		} else {
 			eligible = append(eligible, p)
@@ -173,7 +283,7 @@ func (s *Selector) selectRandom(tool string, profiles []string) (*Result, error)
 	}
 
 	if len(eligible) == 0 {
-		return nil, fmt.Errorf("all profiles for %s are in cooldown", tool)
+		return nil, &SelectionError{Tool: tool, Err: ErrAllInCooldown}
 	}
 
 	idx := s.rng.Intn(len(eligible))
@@ -187,7 +297,7 @@ func (s *Selector) selectRandom(tool string, profiles []string) (*Result, error)
 		if p == selected {
 			reasons = append(reasons, Reason{Text: "Selected", Positive: true})
 		}
-		alternatives = append(alternatives, ProfileScore{Name: p, Score: score, Reasons: reasons})
+		alternatives = append(alternatives, ProfileScore{Name: p, Score: score, Reasons: reasons, Factors: []Factor{{Name: "random", Points: score}}})
 	}
 	alternatives = append(alternatives, inCooldown...)
 
@@ -215,7 +325,7 @@ func (s *Selector) selectRoundRobin(tool string, profiles []string, currentProfi
 	}
 
 	// Filter out profiles in cooldown, find next available
-	now := time.Now()
+	now := s.now()
 	var alternatives []ProfileScore
 
 	for _, p := range sorted {
@@ -225,6 +335,7 @@ func (s *Selector) selectRoundRobin(tool string, profiles []string, currentProfi
 				Name:    p,
 				Score:   -10000,
 				Reasons: []Reason{{Text: fmt.Sprintf("In cooldown (%s remaining)", formatDuration(remaining)), Positive: false}},
+				Factors: []Factor{{Name: "cooldown", Points: -10000}},
 			})
 		}
 	}
@@ -243,10 +354,12 @@ func (s *Selector) selectRoundRobin(tool string, profiles []string, currentProfi
 					if p == candidate {
 						reasons = append(reasons, Reason{Text: "Next in sequence", Positive: true})
 					}
+					points := float64(len(sorted) - position)
 					alternatives = append(alternatives, ProfileScore{
 						Name:    p,
-						Score:   float64(len(sorted) - position),
+						Score:   points,
 						Reasons: reasons,
+						Factors: []Factor{{Name: "position", Points: points}},
 					})
 				}
 			}
@@ -264,12 +377,12 @@ func (s *Selector) selectRoundRobin(tool string, profiles []string, currentProfi
 		}
 	}
 
-	return nil, fmt.Errorf("all profiles for %s are in cooldown", tool)
+	return nil, &SelectionError{Tool: tool, Err: ErrAllInCooldown}
 }
 
 // selectSmart uses multi-factor scoring to select the best profile.
 func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error) {
-	now := time.Now()
+	now := s.now()
 	var scores []ProfileScore
 
 	for _, p := range profiles {
@@ -278,7 +391,7 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 		// Factor 1: Cooldown (disqualifying)
 		if s.isInCooldown(tool, p, now) {
 			remaining := s.cooldownRemaining(tool, p, now)
-			score.Score = -10000
+			score.Add("cooldown", -10000)
 			score.Reasons = append(score.Reasons, Reason{
 				Text:     fmt.Sprintf("In cooldown (%s remaining)", formatDuration(remaining)),
 				Positive: false,
@@ -294,9 +407,9 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 				status := health.CalculateStatus(h)
 				switch status {
 				case health.StatusHealthy:
-					score.Score += 100
+					score.Add("health", 100)
 					if !h.TokenExpiresAt.IsZero() {
-						ttl := time.Until(h.TokenExpiresAt)
+						ttl := h.TokenExpiresAt.Sub(now)
 						score.Reasons = append(score.Reasons, Reason{
 							Text:     fmt.Sprintf("Healthy token (expires in %s)", formatDuration(ttl)),
 							Positive: true,
@@ -308,16 +421,16 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 						})
 					}
 				case health.StatusWarning:
-					score.Score += 50
+					score.Add("health", 50)
 					if !h.TokenExpiresAt.IsZero() {
-						ttl := time.Until(h.TokenExpiresAt)
+						ttl := h.TokenExpiresAt.Sub(now)
 						score.Reasons = append(score.Reasons, Reason{
 							Text:     fmt.Sprintf("Token expiring soon (%s)", formatDuration(ttl)),
 							Positive: false,
 						})
 					}
 				case health.StatusCritical:
-					score.Score -= 50
+					score.Add("health", -50)
 					score.Reasons = append(score.Reasons, Reason{
 						Text:     "Critical status (token expired or many errors)",
 						Positive: false,
@@ -326,7 +439,7 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 
 				// Penalty factor
 				if h.Penalty > 0 {
-					score.Score -= h.Penalty * 10
+					score.Add("penalty", -h.Penalty*10)
 					score.Reasons = append(score.Reasons, Reason{
 						Text:     fmt.Sprintf("Has penalty score (%.1f)", h.Penalty),
 						Positive: false,
@@ -336,19 +449,19 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 				// Plan type bonus
 				switch h.PlanType {
 				case "enterprise":
-					score.Score += 30
+					score.Add("plan", 30)
 					score.Reasons = append(score.Reasons, Reason{
 						Text:     "Enterprise plan",
 						Positive: true,
 					})
 				case "pro":
-					score.Score += 20
+					score.Add("plan", 20)
 					score.Reasons = append(score.Reasons, Reason{
 						Text:     "Pro plan",
 						Positive: true,
 					})
 				case "team":
-					score.Score += 20
+					score.Add("plan", 20)
 					score.Reasons = append(score.Reasons, Reason{
 						Text:     "Team plan",
 						Positive: true,
@@ -365,10 +478,10 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 		// Factor 3: Recency (prefer profiles not used recently)
 		lastUsed := s.getLastActivation(tool, p)
 		if !lastUsed.IsZero() {
-			since := time.Since(lastUsed)
+			since := now.Sub(lastUsed)
 			if since < s.avoidRecent {
 				penalty := float64(s.avoidRecent-since) / float64(time.Hour) * 50
-				score.Score -= penalty
+				score.Add("recency", -penalty)
 				score.Reasons = append(score.Reasons, Reason{
 					Text:     fmt.Sprintf("Used recently (%s ago)", formatDuration(since)),
 					Positive: false,
@@ -378,14 +491,14 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 				if bonus > 50 {
 					bonus = 50 // Cap the bonus
 				}
-				score.Score += bonus
+				score.Add("recency", bonus)
 				score.Reasons = append(score.Reasons, Reason{
 					Text:     fmt.Sprintf("Not used recently (%s ago)", formatDuration(since)),
 					Positive: true,
 				})
 			}
 		} else {
-			score.Score += 25 // Slight bonus for never-used profiles
+			score.Add("recency", 25) // Slight bonus for never-used profiles
 			score.Reasons = append(score.Reasons, Reason{
 				Text:     "Never used before",
 				Positive: true,
@@ -398,7 +511,7 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 				// Use availability score (0-100, higher is better)
 				// Convert to bonus: 100 avail = +100 bonus, 0 avail = -100 penalty
 				usageBonus := float64(usage.AvailScore) - 50 // Center around 0
-				score.Score += usageBonus
+				score.Add("usage", usageBonus)
 
 				if usage.PrimaryPercent >= 80 {
 					score.Reasons = append(score.Reasons, Reason{
@@ -418,7 +531,7 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 				}
 
 				if usage.SecondaryPercent >= 80 {
-					score.Score -= 30 // Extra penalty for high secondary usage
+					score.Add("usage", -30) // Extra penalty for high secondary usage
 					score.Reasons = append(score.Reasons, Reason{
 						Text:     fmt.Sprintf("Secondary limit %d%% used (near limit)", usage.SecondaryPercent),
 						Positive: false,
@@ -426,7 +539,7 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 				}
 			} else if usage != nil && usage.Error != "" {
 				// Fetching failed - slight penalty but don't disqualify
-				score.Score -= 10
+				score.Add("usage", -10)
 				score.Reasons = append(score.Reasons, Reason{
 					Text:     "Usage data unavailable",
 					Positive: false,
@@ -436,7 +549,7 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 
 		// Factor 5: Small random jitter to break ties
 		jitter := s.rng.Float64() * 5
-		score.Score += jitter
+		score.Add("jitter", jitter)
 
 		scores = append(scores, score)
 	}
@@ -448,11 +561,11 @@ func (s *Selector) selectSmart(tool string, profiles []string) (*Result, error)
 
 	// Check if all profiles are in cooldown
 	if len(scores) == 0 {
-		return nil, fmt.Errorf("no profiles available for %s", tool)
+		return nil, &SelectionError{Tool: tool, Err: ErrNoProfiles}
 	}
 
 	if scores[0].Score < -9000 {
-		return nil, fmt.Errorf("all profiles for %s are in cooldown", tool)
+		return nil, &SelectionError{Tool: tool, Err: ErrAllInCooldown}
 	}
 
 	return &Result{
@@ -1,6 +1,7 @@
 package rotation
 
 import (
+	"errors"
 	"math/rand"
 	"path/filepath"
 	"testing"
@@ -104,3 +105,29 @@ func TestSelectSmart_LastActivationNotLostWhenManyNonActivateEventsExist(t *test
 		t.Fatalf("Selected = %q, want %q", result.Selected, "b")
 	}
 }
+
+func TestSelect_AllInCooldownReturnsSentinel(t *testing.T) {
+	tmpDir := t.TempDir()
+	db, err := caamdb.OpenAt(filepath.Join(tmpDir, "caam.db"))
+	if err != nil {
+		t.Fatalf("db.OpenAt() error = %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, p := range []string{"a", "b"} {
+		if _, err := db.SetCooldown("codex", p, time.Now().UTC(), time.Hour, ""); err != nil {
+			t.Fatalf("SetCooldown(%s) error = %v", p, err)
+		}
+	}
+
+	for _, algo := range []Algorithm{AlgorithmSmart, AlgorithmRoundRobin, AlgorithmRandom} {
+		s := NewSelector(algo, nil, db)
+		_, err := s.Select("codex", []string{"a", "b"}, "")
+		if !errors.Is(err, ErrAllInCooldown) {
+			t.Errorf("%s: Select() error = %v, want ErrAllInCooldown", algo, err)
+		}
+		if err != nil && err.Error() != "all profiles for codex are in cooldown" {
+			t.Errorf("%s: unexpected error message: %v", algo, err)
+		}
+	}
+}
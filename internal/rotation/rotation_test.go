@@ -1,6 +1,8 @@
 package rotation
 
 import (
+	"errors"
+	"math"
 	"math/rand"
 	"strings"
 	"testing"
@@ -143,6 +145,92 @@ func TestSelectSmart(t *testing.T) {
 	})
 }
 
+func TestSelectFactorsSumToScore(t *testing.T) {
+	profiles := []string{"alpha", "beta", "gamma"}
+	for _, algorithm := range []Algorithm{AlgorithmSmart, AlgorithmRoundRobin, AlgorithmRandom} {
+		s := NewSelector(algorithm, nil, nil)
+		s.SetRNG(rand.New(rand.NewSource(42)))
+		result, err := s.Select("claude", profiles, "alpha")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", algorithm, err)
+		}
+		for _, alt := range result.Alternatives {
+			if len(alt.Factors) == 0 {
+				t.Errorf("%s: %s has no score factors", algorithm, alt.Name)
+			}
+			var sum float64
+			for _, f := range alt.Factors {
+				sum += f.Points
+			}
+			if math.Abs(sum-alt.Score) > 1e-9 {
+				t.Errorf("%s: %s factors sum to %v, score is %v", algorithm, alt.Name, sum, alt.Score)
+			}
+		}
+	}
+}
+
+func TestProfileScoreAdd(t *testing.T) {
+	var ps ProfileScore
+	ps.Add("health", 100)
+	ps.Add("usage", -30)
+	ps.Add("usage", -10)
+	if ps.Score != 60 {
+		t.Errorf("Score = %v, want 60", ps.Score)
+	}
+	if len(ps.Factors) != 2 || ps.Factors[1].Name != "usage" || ps.Factors[1].Points != -40 {
+		t.Errorf("Factors = %+v, want health then usage -40", ps.Factors)
+	}
+}
+
+func TestResultPin(t *testing.T) {
+	result := &Result{
+		Selected: "alpha",
+		Alternatives: []ProfileScore{
+			{Name: "alpha", Score: 120},
+			{Name: "beta", Score: 80},
+			{Name: "gamma", Score: -10000, Factors: []Factor{{Name: "cooldown", Points: -10000}}},
+		},
+	}
+
+	if result.Pin("gamma") {
+		t.Error("Pin should not select a profile in cooldown")
+	}
+	if result.Pin("missing") {
+		t.Error("Pin should not select an unscored profile")
+	}
+	if !result.Pin("beta") {
+		t.Fatal("Pin(beta) = false, want true")
+	}
+	if result.Selected != "beta" || result.Alternatives[0].Name != "beta" || result.Alternatives[1].Name != "alpha" {
+		t.Errorf("after Pin: selected %q, alternatives %+v", result.Selected, result.Alternatives)
+	}
+	if len(result.Alternatives[0].Reasons) == 0 || result.Alternatives[0].Reasons[0].Text != "Pinned" {
+		t.Errorf("pinned profile reasons = %+v, want Pinned first", result.Alternatives[0].Reasons)
+	}
+}
+
+func TestSelectSkipsExcludedProfiles(t *testing.T) {
+	for _, algorithm := range []Algorithm{AlgorithmSmart, AlgorithmRoundRobin, AlgorithmRandom} {
+		s := NewSelector(algorithm, nil, nil)
+		s.SetExcluded([]string{"alpha", "gamma"})
+		result, err := s.Select("claude", []string{"alpha", "beta", "gamma"}, "")
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", algorithm, err)
+		}
+		if result.Selected != "beta" || len(result.Alternatives) != 1 {
+			t.Errorf("%s: selected %q from %d alternatives, want only beta", algorithm, result.Selected, len(result.Alternatives))
+		}
+	}
+
+	s := NewSelector(AlgorithmSmart, nil, nil)
+	s.SetExcluded([]string{"alpha"})
+	_, err := s.Select("claude", []string{"alpha", "_backup"}, "")
+	var selErr *SelectionError
+	if !errors.As(err, &selErr) || !selErr.Excluded || selErr.SystemOnly {
+		t.Errorf("Select() error = %v, want an excluded-profiles SelectionError", err)
+	}
+}
+
 func TestSelectFiltersSystemProfiles(t *testing.T) {
 	s := NewSelector(AlgorithmSmart, nil, nil)
 
@@ -175,6 +263,9 @@ func TestSelectFiltersSystemProfiles(t *testing.T) {
 		if !strings.Contains(err.Error(), "no user profiles") {
 			t.Errorf("unexpected error message: %v", err)
 		}
+		if !errors.Is(err, ErrNoProfiles) {
+			t.Errorf("expected ErrNoProfiles, got %v", err)
+		}
 	})
 }
 
@@ -0,0 +1,210 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// activityDirName is the directory, inside the sync data directory of every
+// machine, holding one activity file per machine. Each machine only writes
+// its own file; syncs copy the others around.
+const activityDirName = "activity"
+
+// Activation is the profile a machine last activated for a provider.
+type Activation struct {
+	Profile string    `json:"profile"`
+	At      time.Time `json:"at"`
+}
+
+// MachineActivity is what one machine has active, by provider.
+type MachineActivity struct {
+	// MachineID is the machine's sync identity.
+	MachineID string `json:"machine_id"`
+
+	// Machine is the machine's hostname.
+	Machine string `json:"machine"`
+
+	// UpdatedAt is when the machine last changed its activations. Syncs keep
+	// the copy with the later time.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Active maps provider to the profile activated there.
+	Active map[string]Activation `json:"active"`
+}
+
+// ActivityDir returns the local activity directory.
+func ActivityDir() string {
+	return filepath.Join(SyncDataDir(), activityDirName)
+}
+
+// remoteActivityDir returns the activity directory on a remote, which sits
+// in the sync data directory next to its vault.
+func remoteActivityDir(remoteVaultPath string) string {
+	return posixJoin(posixDir(remoteVaultPath), "sync", activityDirName)
+}
+
+// activityFileName returns the file a machine's activity is stored in.
+func activityFileName(machineID string) string {
+	return machineID + ".json"
+}
+
+// RecordActivation notes in dir that the local machine activated
+// provider/profile at the given time.
+func RecordActivation(dir string, local *LocalIdentity, provider, profile string, at time.Time) error {
+	if local == nil || local.ID == "" {
+		return fmt.Errorf("no local identity")
+	}
+	path := filepath.Join(dir, activityFileName(local.ID))
+
+	activity := &MachineActivity{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, activity); err != nil {
+			activity = &MachineActivity{} // rewrite an unreadable file
+		}
+	}
+	activity.MachineID = local.ID
+	activity.Machine = local.Hostname
+	activity.UpdatedAt = at
+	if activity.Active == nil {
+		activity.Active = make(map[string]Activation)
+	}
+	activity.Active[provider] = Activation{Profile: profile, At: at}
+
+	data, err := json.MarshalIndent(activity, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal activity: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create activity dir: %w", err)
+	}
+	return atomicWriteFile(path, data, 0600)
+}
+
+// LoadActivity reads every machine's activity from dir, sorted by machine.
+// Unreadable files are skipped.
+func LoadActivity(dir string) ([]MachineActivity, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var all []MachineActivity
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var a MachineActivity
+		if err := json.Unmarshal(data, &a); err != nil || a.MachineID == "" {
+			continue
+		}
+		all = append(all, a)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Machine < all[j].Machine
+	})
+	return all, nil
+}
+
+// activityUpdatedAt returns the UpdatedAt of an activity file's contents,
+// zero if they can't be parsed.
+func activityUpdatedAt(data []byte) time.Time {
+	var a MachineActivity
+	if err := json.Unmarshal(data, &a); err != nil {
+		return time.Time{}
+	}
+	return a.UpdatedAt
+}
+
+// exchangeActivity brings the activity files on the local machine and the
+// remote up to date with each other: for each machine's file, the side with
+// the later UpdatedAt wins. Files from machines neither side syncs with
+// directly travel along, so activity spreads through the pool.
+func (s *Syncer) exchangeActivity(client *SSHClient) error {
+	localDir := s.state.activityDir()
+	remoteDir := remoteActivityDir(s.remoteVaultPath)
+
+	local := make(map[string][]byte)
+	if entries, err := os.ReadDir(localDir); err == nil {
+		for _, e := range entries {
+			if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+				continue
+			}
+			if data, err := os.ReadFile(filepath.Join(localDir, e.Name())); err == nil {
+				local[e.Name()] = data
+			}
+		}
+	}
+
+	remote := make(map[string][]byte)
+	if entries, err := client.ListDir(remoteDir); err == nil {
+		for _, fi := range entries {
+			if fi.IsDir() || !strings.HasSuffix(fi.Name(), ".json") {
+				continue
+			}
+			if data, err := client.ReadFile(posixJoin(remoteDir, fi.Name())); err == nil {
+				remote[fi.Name()] = data
+			}
+		}
+	}
+
+	var toRemote []string
+	for name, data := range local {
+		r, ok := remote[name]
+		if !ok || activityUpdatedAt(data).After(activityUpdatedAt(r)) {
+			toRemote = append(toRemote, name)
+		}
+	}
+	if len(toRemote) > 0 {
+		if err := client.MkdirAll(remoteDir); err != nil {
+			return fmt.Errorf("create remote activity dir: %w", err)
+		}
+		for _, name := range toRemote {
+			if err := client.WriteFile(posixJoin(remoteDir, name), local[name], 0600); err != nil {
+				return fmt.Errorf("push activity %s: %w", name, err)
+			}
+		}
+	}
+
+	for name, data := range remote {
+		l, ok := local[name]
+		if ok && !activityUpdatedAt(data).After(activityUpdatedAt(l)) {
+			continue
+		}
+		if activityUpdatedAt(data).IsZero() {
+			continue // don't spread unreadable files
+		}
+		if err := os.MkdirAll(localDir, 0700); err != nil {
+			return fmt.Errorf("create activity dir: %w", err)
+		}
+		if err := atomicWriteFile(filepath.Join(localDir, name), data, 0600); err != nil {
+			return fmt.Errorf("pull activity %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// shareActivity exchanges activity with a machine when the pool broadcasts
+// it. Failures don't fail the sync; activity is informational.
+func (s *Syncer) shareActivity(client *SSHClient) {
+	if s.state.Pool == nil || !s.state.Pool.BroadcastActivity {
+		return
+	}
+	_ = s.exchangeActivity(client)
+}
+
+// activityDir returns the activity directory next to the state's files.
+func (s *SyncState) activityDir() string {
+	return filepath.Join(s.basePath, activityDirName)
+}
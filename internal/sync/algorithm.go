@@ -42,6 +42,14 @@ type SyncOperation struct {
 
 	// RemoteFreshness is the freshness of the remote token.
 	RemoteFreshness *TokenFreshness
+
+	// Conflict describes both copies when Direction is SyncConflict.
+	Conflict *Conflict
+
+	// localFiles and remoteFiles are the profile's files on each side when
+	// the operation was planned, by name.
+	localFiles  map[string][]byte
+	remoteFiles map[string][]byte
 }
 
 // SyncResult represents the result of a sync operation.
@@ -58,6 +66,13 @@ type SyncResult struct {
 	// BytesReceived is the number of bytes received during the operation.
 	BytesReceived int64
 
+	// FilesTransferred is the number of profile files copied.
+	FilesTransferred int
+
+	// FilesUnchanged is the number of files skipped because their content
+	// already matched on the other side.
+	FilesUnchanged int
+
 	// Duration is how long the operation took.
 	Duration time.Duration
 
@@ -76,8 +91,15 @@ type Syncer struct {
 	// vaultPath is the local vault directory path.
 	vaultPath string
 
+	// vault reads and writes the local profiles, so encrypted and keychain
+	// profiles sync as plaintext rather than as sealed blobs and stubs.
+	vault *authfile.Vault
+
 	// remoteVaultPath is the remote vault directory path pattern.
 	remoteVaultPath string
+
+	// prefer resolves conflicts; see SyncerConfig.Prefer.
+	prefer string
 }
 
 // SyncerConfig configures a Syncer instance.
@@ -85,12 +107,22 @@ type SyncerConfig struct {
 	// VaultPath is the local vault directory.
 	VaultPath string
 
+	// Vault is the local vault. If nil, the vault at VaultPath is used with
+	// the configured vault settings.
+	Vault *authfile.Vault
+
 	// RemoteVaultPath is the remote vault directory.
 	// If empty, defaults to ~/.local/share/caam/vault
 	RemoteVaultPath string
 
 	// ConnectOptions configures SSH connections.
 	ConnectOptions ConnectOptions
+
+	// Prefer resolves profiles changed on both sides since their last sync:
+	// PreferLocal, PreferRemote or PreferFresher. With PreferAsk (the
+	// default) they are left unresolved as SyncConflict results, for
+	// ResolveConflict once the user has picked a side.
+	Prefer string
 }
 
 // DefaultSyncerConfig returns a default configuration.
@@ -115,12 +147,21 @@ func NewSyncer(config SyncerConfig) (*Syncer, error) {
 	if config.RemoteVaultPath == "" {
 		config.RemoteVaultPath = DefaultSyncerConfig().RemoteVaultPath
 	}
+	if err := ValidatePrefer(config.Prefer); err != nil {
+		return nil, err
+	}
+	if config.Vault == nil {
+		config.Vault = authfile.NewVault(config.VaultPath)
+		authfile.ApplyVaultSettings(config.Vault)
+	}
 
 	return &Syncer{
 		pool:            NewConnectionPool(config.ConnectOptions),
 		state:           state,
 		vaultPath:       config.VaultPath,
+		vault:           config.Vault,
 		remoteVaultPath: config.RemoteVaultPath,
+		prefer:          config.Prefer,
 	}, nil
 }
 
@@ -140,6 +181,7 @@ func (s *Syncer) SyncWithMachine(ctx context.Context, m *Machine) ([]*SyncResult
 		m.SetError(err.Error())
 		return nil, fmt.Errorf("connection failed: %w", err)
 	}
+	s.shareActivity(client)
 
 	// 2. Get local profiles
 	localProfiles, err := s.listLocalProfiles()
@@ -181,6 +223,7 @@ func (s *Syncer) SyncWithMachine(ctx context.Context, m *Machine) ([]*SyncResult
 		}
 
 		if op == nil || op.Direction == SyncSkip {
+			s.recordBase(op)
 			continue // Already in sync
 		}
 
@@ -190,21 +233,24 @@ func (s *Syncer) SyncWithMachine(ctx context.Context, m *Machine) ([]*SyncResult
 		// Record in history
 		action := string(op.Direction)
 		s.state.AddToHistory(HistoryEntry{
-			Timestamp: time.Now(),
-			Trigger:   "manual",
-			Provider:  op.Provider,
-			Profile:   op.Profile,
-			Machine:   m.Name,
-			Action:    action,
-			Success:   result.Success,
-			Error:     errorToString(result.Error),
-			Duration:  result.Duration,
+			Timestamp:        time.Now(),
+			Trigger:          "manual",
+			Provider:         op.Provider,
+			Profile:          op.Profile,
+			Machine:          m.Name,
+			Action:           action,
+			Success:          result.Success,
+			Error:            errorToString(result.Error),
+			Duration:         result.Duration,
+			BytesTransferred: result.BytesSent + result.BytesReceived,
+			FilesTransferred: result.FilesTransferred,
+			FilesUnchanged:   result.FilesUnchanged,
 		})
 
-		// Update queue
+		// Update queue. Conflicts wait for the user, not for a retry.
 		if result.Success {
 			s.state.RemoveFromQueue(op.Provider, op.Profile, m.ID)
-		} else {
+		} else if op.Direction != SyncConflict {
 			s.state.AddToQueue(op.Provider, op.Profile, m.ID, errorToString(result.Error))
 		}
 	}
@@ -215,6 +261,29 @@ func (s *Syncer) SyncWithMachine(ctx context.Context, m *Machine) ([]*SyncResult
 // SyncProfileWithMachine syncs a specific profile with a specific machine.
 // This is useful for queue processing where we only want to retry the failed machine.
 func (s *Syncer) SyncProfileWithMachine(ctx context.Context, provider, profile string, m *Machine) (*SyncResult, error) {
+	return s.syncProfileWithMachine(ctx, provider, profile, m, "retry")
+}
+
+// ResolveConflict settles a profile that changed on both m and locally,
+// keeping the side the user picked: PreferLocal, PreferRemote or
+// PreferFresher. A profile no longer in conflict just syncs as usual.
+func (s *Syncer) ResolveConflict(ctx context.Context, provider, profile string, m *Machine, prefer string) (*SyncResult, error) {
+	if prefer == PreferAsk {
+		return nil, fmt.Errorf("resolving a conflict needs a side to keep")
+	}
+	if err := ValidatePrefer(prefer); err != nil {
+		return nil, err
+	}
+
+	saved := s.prefer
+	s.prefer = prefer
+	defer func() { s.prefer = saved }()
+	return s.syncProfileWithMachine(ctx, provider, profile, m, "conflict")
+}
+
+// syncProfileWithMachine syncs one profile with one machine, recording
+// trigger in the history.
+func (s *Syncer) syncProfileWithMachine(ctx context.Context, provider, profile string, m *Machine, trigger string) (*SyncResult, error) {
 	if m == nil {
 		return nil, fmt.Errorf("machine is nil")
 	}
@@ -256,6 +325,7 @@ func (s *Syncer) SyncProfileWithMachine(ctx context.Context, provider, profile s
 	}
 
 	if op == nil || op.Direction == SyncSkip {
+		s.recordBase(op)
 		return &SyncResult{
 			Operation: &SyncOperation{
 				Provider:  provider,
@@ -271,15 +341,18 @@ func (s *Syncer) SyncProfileWithMachine(ctx context.Context, provider, profile s
 
 	// Record in history
 	s.state.AddToHistory(HistoryEntry{
-		Timestamp: time.Now(),
-		Trigger:   "retry",
-		Provider:  provider,
-		Profile:   profile,
-		Machine:   m.Name,
-		Action:    string(op.Direction),
-		Success:   result.Success,
-		Error:     errorToString(result.Error),
-		Duration:  result.Duration,
+		Timestamp:        time.Now(),
+		Trigger:          trigger,
+		Provider:         provider,
+		Profile:          profile,
+		Machine:          m.Name,
+		Action:           string(op.Direction),
+		Success:          result.Success,
+		Error:            errorToString(result.Error),
+		Duration:         result.Duration,
+		BytesTransferred: result.BytesSent + result.BytesReceived,
+		FilesTransferred: result.FilesTransferred,
+		FilesUnchanged:   result.FilesUnchanged,
 	})
 
 	return result, nil
@@ -316,6 +389,7 @@ func (s *Syncer) SyncProfile(ctx context.Context, provider, profile string) ([]*
 			s.state.AddToQueue(provider, profile, m.ID, err.Error())
 			continue
 		}
+		s.shareActivity(client)
 
 		p := ProfileRef{Provider: provider, Profile: profile}
 		op, err := s.determineSyncOperation(client, m, p)
@@ -334,6 +408,7 @@ func (s *Syncer) SyncProfile(ctx context.Context, provider, profile string) ([]*
 		}
 
 		if op == nil || op.Direction == SyncSkip {
+			s.recordBase(op)
 			continue
 		}
 
@@ -342,20 +417,23 @@ func (s *Syncer) SyncProfile(ctx context.Context, provider, profile string) ([]*
 
 		// Record in history
 		s.state.AddToHistory(HistoryEntry{
-			Timestamp: time.Now(),
-			Trigger:   "manual",
-			Provider:  provider,
-			Profile:   profile,
-			Machine:   m.Name,
-			Action:    string(op.Direction),
-			Success:   result.Success,
-			Error:     errorToString(result.Error),
-			Duration:  result.Duration,
+			Timestamp:        time.Now(),
+			Trigger:          "manual",
+			Provider:         provider,
+			Profile:          profile,
+			Machine:          m.Name,
+			Action:           string(op.Direction),
+			Success:          result.Success,
+			Error:            errorToString(result.Error),
+			Duration:         result.Duration,
+			BytesTransferred: result.BytesSent + result.BytesReceived,
+			FilesTransferred: result.FilesTransferred,
+			FilesUnchanged:   result.FilesUnchanged,
 		})
 
 		if result.Success {
 			s.state.RemoveFromQueue(provider, profile, m.ID)
-		} else {
+		} else if op.Direction != SyncConflict {
 			s.state.AddToQueue(provider, profile, m.ID, errorToString(result.Error))
 		}
 	}
@@ -392,7 +470,11 @@ func (s *Syncer) SyncAll(ctx context.Context) ([]*SyncResult, error) {
 // determineSyncOperation determines what sync operation is needed for a profile.
 func (s *Syncer) determineSyncOperation(client *SSHClient, m *Machine, p ProfileRef) (*SyncOperation, error) {
 	localFresh, localErr := s.getLocalFreshness(p)
-	remoteFresh, remoteErr := s.getRemoteFreshness(client, p)
+	remoteFiles, remoteErr := s.readRemoteProfileFiles(client, p)
+	var remoteFresh *TokenFreshness
+	if remoteErr == nil {
+		remoteFresh, remoteErr = s.remoteFreshness(client, p, remoteFiles)
+	}
 
 	// Check if errors are "not found" vs other errors
 	localNotFound := localErr != nil && os.IsNotExist(localErr)
@@ -406,6 +488,10 @@ func (s *Syncer) determineSyncOperation(client *SSHClient, m *Machine, p Profile
 		Machine:         m,
 		LocalFreshness:  localFresh,
 		RemoteFreshness: remoteFresh,
+		remoteFiles:     remoteFiles,
+	}
+	if localErr == nil {
+		op.localFiles, _ = s.readLocalProfileFiles(filepath.Join(s.vaultPath, p.Provider, p.Profile))
 	}
 
 	switch {
@@ -435,7 +521,28 @@ func (s *Syncer) determineSyncOperation(client *SSHClient, m *Machine, p Profile
 		// Only exists locally: push
 		op.Direction = SyncPush
 		return op, nil
+	}
+
+	if s.conflicting(op) {
+		switch s.prefer {
+		case PreferLocal:
+			op.Direction = SyncPush
+			return op, nil
+		case PreferRemote:
+			op.Direction = SyncPull
+			return op, nil
+		case PreferAsk:
+			op.Direction = SyncConflict
+			op.Conflict = &Conflict{
+				Local:  ConflictSide{Freshness: localFresh, Account: accountFromFiles(p.Provider, op.localFiles)},
+				Remote: ConflictSide{Freshness: remoteFresh, Account: accountFromFiles(p.Provider, op.remoteFiles)},
+			}
+			return op, nil
+		}
+		// PreferFresher: settle it like any other difference.
+	}
 
+	switch {
 	case CompareFreshness(localFresh, remoteFresh):
 		// Local is fresher: push
 		op.Direction = SyncPush
@@ -453,6 +560,48 @@ func (s *Syncer) determineSyncOperation(client *SSHClient, m *Machine, p Profile
 	}
 }
 
+// conflicting reports whether both copies of op's profile changed since it
+// last synced with op's machine. Profiles never synced before don't conflict.
+func (s *Syncer) conflicting(op *SyncOperation) bool {
+	if s.state == nil || op.Machine == nil {
+		return false
+	}
+	local, remote := profileDigest(op.localFiles), profileDigest(op.remoteFiles)
+	if local == remote {
+		return false
+	}
+	base, ok := s.state.syncedBase(op.Machine.ID, op.Provider, op.Profile)
+	return ok && local != base.Local && remote != base.Remote
+}
+
+// recordBase remembers what both copies hold after op succeeded, so the
+// next sync can tell which side changed.
+func (s *Syncer) recordBase(op *SyncOperation) {
+	if s.state == nil || op == nil || op.Machine == nil {
+		return
+	}
+	local, remote := op.localFiles, op.remoteFiles
+	switch op.Direction {
+	case SyncPush:
+		remote = mergeFiles(remote, local)
+	case SyncPull:
+		local = mergeFiles(local, remote)
+	case SyncSkip:
+		// Only identical copies make a base: recording a difference the
+		// freshness rules skipped would hide it.
+		if local == nil || profileDigest(local) != profileDigest(remote) {
+			return
+		}
+	default:
+		return
+	}
+	s.state.recordSyncedBase(op.Machine.ID, op.Provider, op.Profile, syncBase{
+		Local:  profileDigest(local),
+		Remote: profileDigest(remote),
+		At:     time.Now(),
+	})
+}
+
 // executeOperation executes a sync operation.
 func (s *Syncer) executeOperation(client *SSHClient, op *SyncOperation) *SyncResult {
 	start := time.Now()
@@ -463,25 +612,40 @@ func (s *Syncer) executeOperation(client *SSHClient, op *SyncOperation) *SyncRes
 
 	switch op.Direction {
 	case SyncPush:
-		err := s.pushProfile(client, op.Provider, op.Profile)
+		stats, err := s.pushProfile(client, op.Provider, op.Profile)
 		result.Error = err
 		result.Success = err == nil
+		result.BytesSent = stats.bytes
+		result.FilesTransferred = stats.files
+		result.FilesUnchanged = stats.unchanged
 
 	case SyncPull:
-		err := s.pullProfile(client, op.Provider, op.Profile)
+		stats, err := s.pullProfile(client, op.Provider, op.Profile)
 		result.Error = err
 		result.Success = err == nil
+		result.BytesReceived = stats.bytes
+		result.FilesTransferred = stats.files
+		result.FilesUnchanged = stats.unchanged
 
 	case SyncSkip:
 		result.Success = true
+
+	case SyncConflict:
+		result.Error = ErrConflict
 	}
 
+	if result.Success {
+		s.recordBase(op)
+	}
 	result.Duration = time.Since(start)
 	return result
 }
 
-// pushProfile pushes a local profile to the remote machine.
-func (s *Syncer) pushProfile(client *SSHClient, provider, profile string) error {
+// pushProfile pushes a local profile to the remote machine. Files whose
+// content matches what the remote manifest records are skipped, so pushing
+// a profile after a token refresh only sends the changed file.
+func (s *Syncer) pushProfile(client *SSHClient, provider, profile string) (transferStats, error) {
+	var stats transferStats
 	localPath := filepath.Join(s.vaultPath, provider, profile)
 	// Use posixJoin for remote paths since SFTP always uses forward slashes
 	remotePath := posixJoin(s.remoteVaultPath, provider, profile)
@@ -489,22 +653,61 @@ func (s *Syncer) pushProfile(client *SSHClient, provider, profile string) error
 	// Read local files
 	files, err := s.readLocalProfileFiles(localPath)
 	if err != nil {
-		return fmt.Errorf("read local files: %w", err)
+		return stats, fmt.Errorf("read local files: %w", err)
 	}
 
-	// Write to remote
+	manifest := readRemoteManifest(client, remotePath)
+	remoteInfos := remoteFileInfos(client, remotePath)
+
+	// Write changed files to remote
+	var written []string
 	for filename, data := range files {
+		sum := hashBytes(data)
+		if info, ok := remoteInfos[filename]; ok {
+			if recorded, ok := manifest.current(filename, info); ok && recorded == sum {
+				stats.unchanged++
+				continue
+			}
+		}
+
 		remoteFilePath := posixJoin(remotePath, filename)
 		if err := client.WriteFile(remoteFilePath, data, 0600); err != nil {
-			return fmt.Errorf("write remote file %s: %w", filename, err)
+			return stats, fmt.Errorf("write remote file %s: %w", filename, err)
 		}
+		stats.bytes += int64(len(data))
+		stats.files++
+		manifest[filename] = manifestEntry{SHA256: sum}
+		written = append(written, filename)
 	}
 
-	return nil
+	if len(written) == 0 {
+		return stats, nil
+	}
+
+	// Record what was written as the remote now reports it, so the next
+	// sync can tell whether the files changed there in the meantime.
+	remoteInfos = remoteFileInfos(client, remotePath)
+	for _, filename := range written {
+		if info, ok := remoteInfos[filename]; ok {
+			entry := manifest[filename]
+			entry.Size = info.Size()
+			entry.ModTime = info.ModTime()
+			manifest[filename] = entry
+		}
+	}
+	n, err := writeRemoteManifest(client, remotePath, manifest)
+	if err != nil {
+		return stats, fmt.Errorf("write remote manifest: %w", err)
+	}
+	stats.bytes += n
+
+	return stats, nil
 }
 
-// pullProfile pulls a remote profile to the local machine.
-func (s *Syncer) pullProfile(client *SSHClient, provider, profile string) error {
+// pullProfile pulls a remote profile to the local machine, skipping files
+// the remote manifest shows are identical to the local copy.
+func (s *Syncer) pullProfile(client *SSHClient, provider, profile string) (transferStats, error) {
+	var stats transferStats
 	localPath := filepath.Join(s.vaultPath, provider, profile)
 	// Use posixJoin for remote paths since SFTP always uses forward slashes
 	remotePath := posixJoin(s.remoteVaultPath, provider, profile)
@@ -512,33 +715,73 @@ func (s *Syncer) pullProfile(client *SSHClient, provider, profile string) error
 	// List remote files
 	remoteFiles, err := client.ListDir(remotePath)
 	if err != nil {
-		return fmt.Errorf("list remote files: %w", err)
+		return stats, fmt.Errorf("list remote files: %w", err)
 	}
 
 	// Ensure local directory exists
 	if err := os.MkdirAll(localPath, 0700); err != nil {
-		return fmt.Errorf("create local directory: %w", err)
+		return stats, fmt.Errorf("create local directory: %w", err)
+	}
+
+	// Pulled files are plaintext: write them to a plain copy of the profile
+	// and let the vault seal them, or keep them in the keychain, as it does
+	// its own.
+	v := s.localVault()
+	plainPath, cleanup, err := v.PlainProfileDir(provider, profile)
+	if err != nil {
+		return stats, fmt.Errorf("open local profile: %w", err)
 	}
+	defer cleanup()
+
+	manifest := readRemoteManifest(client, remotePath)
+	manifestChanged := false
 
 	// Read remote files and write locally using atomic writes
 	for _, fi := range remoteFiles {
-		if fi.IsDir() {
+		if fi.IsDir() || isSyncMetaFile(fi.Name()) {
 			continue
 		}
 
+		localFilePath := filepath.Join(plainPath, fi.Name())
+		recorded, current := manifest.current(fi.Name(), fi)
+		if current {
+			if local, err := os.ReadFile(localFilePath); err == nil && hashBytes(local) == recorded {
+				stats.unchanged++
+				continue
+			}
+		}
+
 		remoteFilePath := posixJoin(remotePath, fi.Name())
 		data, err := client.ReadFile(remoteFilePath)
 		if err != nil {
-			return fmt.Errorf("read remote file %s: %w", fi.Name(), err)
+			return stats, fmt.Errorf("read remote file %s: %w", fi.Name(), err)
 		}
+		stats.bytes += int64(len(data))
+		stats.files++
 
-		localFilePath := filepath.Join(localPath, fi.Name())
 		if err := atomicWriteFile(localFilePath, data, 0600); err != nil {
-			return fmt.Errorf("write local file %s: %w", fi.Name(), err)
+			return stats, fmt.Errorf("write local file %s: %w", fi.Name(), err)
+		}
+
+		if !current {
+			manifest[fi.Name()] = manifestEntry{SHA256: hashBytes(data), Size: fi.Size(), ModTime: fi.ModTime()}
+			manifestChanged = true
 		}
 	}
+	if err := v.SavePlainProfileDir(provider, profile, plainPath); err != nil {
+		return stats, fmt.Errorf("save local profile: %w", err)
+	}
 
-	return nil
+	// These files changed on the remote since caam last wrote them; record
+	// their hashes so the next sync doesn't download them again. Best effort:
+	// a stale manifest only costs a re-transfer.
+	if manifestChanged {
+		if n, err := writeRemoteManifest(client, remotePath, manifest); err == nil {
+			stats.bytes += n
+		}
+	}
+
+	return stats, nil
 }
 
 // atomicWriteFile writes data to a file atomically using temp file + fsync + rename.
@@ -597,7 +840,16 @@ func localRandomString(n int) string {
 	return string(b)
 }
 
-// readLocalProfileFiles reads all files from a local profile directory.
+// localVault returns the vault holding the local profiles.
+func (s *Syncer) localVault() *authfile.Vault {
+	if s.vault == nil {
+		return authfile.NewVault(s.vaultPath)
+	}
+	return s.vault
+}
+
+// readLocalProfileFiles reads all files from a local profile directory, in
+// plaintext: sealed files are decrypted and keychain stubs resolved.
 func (s *Syncer) readLocalProfileFiles(profilePath string) (map[string][]byte, error) {
 	files := make(map[string][]byte)
 
@@ -612,7 +864,7 @@ func (s *Syncer) readLocalProfileFiles(profilePath string) (map[string][]byte, e
 		}
 
 		filePath := filepath.Join(profilePath, entry.Name())
-		data, err := os.ReadFile(filePath)
+		data, err := s.localVault().ReadFile(filePath)
 		if err != nil {
 			return nil, fmt.Errorf("read %s: %w", entry.Name(), err)
 		}
@@ -632,24 +884,33 @@ func (s *Syncer) getLocalFreshness(p ProfileRef) (*TokenFreshness, error) {
 		return nil, err
 	}
 
-	// Find auth files
-	var authFiles []string
-	entries, err := os.ReadDir(profilePath)
+	files, err := s.readLocalProfileFiles(profilePath)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			authFiles = append(authFiles, filepath.Join(profilePath, entry.Name()))
-		}
+	authFiles := make(map[string][]byte, len(files))
+	for name, data := range files {
+		authFiles[filepath.Join(profilePath, name)] = data
+	}
+	if len(authFiles) == 0 {
+		return nil, fmt.Errorf("no auth files found for %s/%s", p.Provider, p.Profile)
 	}
 
-	return ExtractFreshnessFromFiles(p.Provider, p.Profile, authFiles)
+	return ExtractFreshnessFromBytes(p.Provider, p.Profile, authFiles)
 }
 
 // getRemoteFreshness gets the freshness of a remote profile.
 func (s *Syncer) getRemoteFreshness(client *SSHClient, p ProfileRef) (*TokenFreshness, error) {
+	files, err := s.readRemoteProfileFiles(client, p)
+	if err != nil {
+		return nil, err
+	}
+	return s.remoteFreshness(client, p, files)
+}
+
+// readRemoteProfileFiles reads the files of a remote profile, by name. It
+// returns os.ErrNotExist if the profile isn't there.
+func (s *Syncer) readRemoteProfileFiles(client *SSHClient, p ProfileRef) (map[string][]byte, error) {
 	// Use posixJoin for remote paths since SFTP always uses forward slashes
 	remotePath := posixJoin(s.remoteVaultPath, p.Provider, p.Profile)
 
@@ -663,31 +924,40 @@ func (s *Syncer) getRemoteFreshness(client *SSHClient, p ProfileRef) (*TokenFres
 	}
 
 	// List remote files
-	files, err := client.ListDir(remotePath)
+	entries, err := client.ListDir(remotePath)
 	if err != nil {
 		return nil, err
 	}
 
-	// Read remote auth files
-	authFiles := make(map[string][]byte)
-	for _, fi := range files {
-		if fi.IsDir() {
+	files := make(map[string][]byte)
+	for _, fi := range entries {
+		if fi.IsDir() || isSyncMetaFile(fi.Name()) {
 			continue
 		}
 
-		filePath := posixJoin(remotePath, fi.Name())
-		data, err := client.ReadFile(filePath)
+		data, err := client.ReadFile(posixJoin(remotePath, fi.Name()))
 		if err != nil {
 			continue // Skip files we can't read
 		}
 
-		authFiles[filePath] = data
+		files[fi.Name()] = data
 	}
+	return files, nil
+}
 
-	if len(authFiles) == 0 {
+// remoteFreshness extracts the freshness of a remote profile from its files.
+func (s *Syncer) remoteFreshness(client *SSHClient, p ProfileRef, files map[string][]byte) (*TokenFreshness, error) {
+	if len(files) == 0 {
 		return nil, fmt.Errorf("no auth files found in remote profile")
 	}
 
+	// Extractors match files by path
+	remotePath := posixJoin(s.remoteVaultPath, p.Provider, p.Profile)
+	authFiles := make(map[string][]byte, len(files))
+	for name, data := range files {
+		authFiles[posixJoin(remotePath, name)] = data
+	}
+
 	freshness, err := ExtractFreshnessFromBytes(p.Provider, p.Profile, authFiles)
 	if err != nil {
 		return nil, err
@@ -794,6 +1064,7 @@ type SyncStats struct {
 	Pushed    int
 	Pulled    int
 	Skipped   int
+	Conflicts int
 	Failed    int
 	BytesSent int64
 	BytesRecv int64
@@ -807,6 +1078,10 @@ func AggregateResults(results []*SyncResult) SyncStats {
 	for _, r := range results {
 		stats.Total++
 
+		if r.Operation != nil && r.Operation.Direction == SyncConflict {
+			stats.Conflicts++
+			continue
+		}
 		if !r.Success {
 			stats.Failed++
 			continue
@@ -2,10 +2,16 @@ package sync
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
 )
 
 // TestSyncDirection tests the SyncDirection constants.
@@ -550,3 +556,350 @@ func TestLocalRandomString(t *testing.T) {
 		}
 	})
 }
+
+// TestPushPullSkipUnchangedFiles tests that only changed files are transferred.
+func TestPushPullSkipUnchangedFiles(t *testing.T) {
+	sshPub, _ := setupTestSSHHome(t)
+	port, _ := startTestSSHServer(t, sshPub)
+
+	m := NewMachine("remote", "127.0.0.1")
+	m.Port = port
+
+	localVault := t.TempDir()
+	remoteVault := t.TempDir()
+	localProfile := filepath.Join(localVault, "codex", "work")
+	remoteProfile := filepath.Join(remoteVault, "codex", "work")
+	if err := os.MkdirAll(localProfile, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(localProfile, "auth.json"), []byte(`{"access_token":"one"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(localProfile, "config.toml"), []byte("model = \"o3\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	syncer := &Syncer{
+		pool:            NewConnectionPool(DefaultConnectOptions()),
+		vaultPath:       localVault,
+		remoteVaultPath: remoteVault,
+	}
+	defer syncer.pool.CloseAll()
+	client, err := syncer.pool.Get(m)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	push := func() *SyncResult {
+		t.Helper()
+		r := syncer.executeOperation(client, &SyncOperation{Provider: "codex", Profile: "work", Direction: SyncPush, Machine: m})
+		if !r.Success {
+			t.Fatalf("push failed: %v", r.Error)
+		}
+		return r
+	}
+	pull := func() *SyncResult {
+		t.Helper()
+		r := syncer.executeOperation(client, &SyncOperation{Provider: "codex", Profile: "work", Direction: SyncPull, Machine: m})
+		if !r.Success {
+			t.Fatalf("pull failed: %v", r.Error)
+		}
+		return r
+	}
+
+	if r := push(); r.FilesTransferred != 2 || r.FilesUnchanged != 0 {
+		t.Errorf("first push: transferred %d, unchanged %d; want 2, 0", r.FilesTransferred, r.FilesUnchanged)
+	}
+	if _, err := os.Stat(filepath.Join(remoteProfile, manifestFileName)); err != nil {
+		t.Errorf("push should write the remote manifest: %v", err)
+	}
+
+	if r := push(); r.FilesTransferred != 0 || r.FilesUnchanged != 2 || r.BytesSent != 0 {
+		t.Errorf("repeat push: transferred %d (%d bytes), unchanged %d; want nothing sent", r.FilesTransferred, r.BytesSent, r.FilesUnchanged)
+	}
+
+	// A refreshed token only sends auth.json.
+	if err := os.WriteFile(filepath.Join(localProfile, "auth.json"), []byte(`{"access_token":"two"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if r := push(); r.FilesTransferred != 1 || r.FilesUnchanged != 1 {
+		t.Errorf("push after refresh: transferred %d, unchanged %d; want 1, 1", r.FilesTransferred, r.FilesUnchanged)
+	}
+
+	// A file rewritten on the remote isn't trusted to match the manifest.
+	remoteAuth := filepath.Join(remoteProfile, "auth.json")
+	if err := os.WriteFile(remoteAuth, []byte(`{"access_token":"three"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(remoteAuth, later, later); err != nil {
+		t.Fatal(err)
+	}
+	r := pull()
+	if r.FilesTransferred != 1 || r.FilesUnchanged != 1 {
+		t.Errorf("pull: transferred %d, unchanged %d; want 1, 1", r.FilesTransferred, r.FilesUnchanged)
+	}
+	if data, _ := os.ReadFile(filepath.Join(localProfile, "auth.json")); string(data) != `{"access_token":"three"}` {
+		t.Errorf("local auth.json = %s, want the remote copy", data)
+	}
+	if _, err := os.Stat(filepath.Join(localProfile, manifestFileName)); !os.IsNotExist(err) {
+		t.Error("pull should not copy the manifest")
+	}
+
+	if r := pull(); r.FilesTransferred != 0 || r.FilesUnchanged != 2 || r.BytesReceived != 0 {
+		t.Errorf("repeat pull: transferred %d (%d bytes), unchanged %d; want nothing received", r.FilesTransferred, r.BytesReceived, r.FilesUnchanged)
+	}
+}
+
+// TestPushPullEncryptedVault tests that an encrypted vault syncs plaintext
+// and keeps what it pulls sealed.
+func TestPushPullEncryptedVault(t *testing.T) {
+	sshPub, _ := setupTestSSHHome(t)
+	port, _ := startTestSSHServer(t, sshPub)
+
+	m := NewMachine("remote", "127.0.0.1")
+	m.Port = port
+
+	localVault := t.TempDir()
+	remoteVault := t.TempDir()
+	localAuth := filepath.Join(localVault, "claude", "work", ".claude.json")
+	remoteAuth := filepath.Join(remoteVault, "claude", "work", ".claude.json")
+	if err := os.MkdirAll(filepath.Dir(localAuth), 0700); err != nil {
+		t.Fatal(err)
+	}
+	token := `{"oauthToken": {"access_token": "one", "expiry": "2025-12-20T14:29:00Z"}}`
+	if err := os.WriteFile(localAuth, []byte(token), 0600); err != nil {
+		t.Fatal(err)
+	}
+	v := authfile.NewVault(localVault)
+	if _, err := v.EnableEncryption("correct horse"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := v.EncryptAll(); err != nil || n != 1 {
+		t.Fatalf("EncryptAll() = %d, %v", n, err)
+	}
+
+	syncer := &Syncer{
+		pool:            NewConnectionPool(DefaultConnectOptions()),
+		vaultPath:       localVault,
+		vault:           v,
+		remoteVaultPath: remoteVault,
+	}
+	defer syncer.pool.CloseAll()
+	client, err := syncer.pool.Get(m)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	fresh, err := syncer.getLocalFreshness(ProfileRef{Provider: "claude", Profile: "work"})
+	if err != nil || fresh.ExpiresAt.IsZero() {
+		t.Fatalf("getLocalFreshness() = %+v, %v; want the decrypted expiry", fresh, err)
+	}
+
+	if r := syncer.executeOperation(client, &SyncOperation{Provider: "claude", Profile: "work", Direction: SyncPush, Machine: m}); !r.Success {
+		t.Fatalf("push failed: %v", r.Error)
+	}
+	if data, _ := os.ReadFile(remoteAuth); string(data) != token {
+		t.Errorf("remote .claude.json = %q, want the plaintext", data)
+	}
+
+	refreshed := strings.Replace(token, "one", "two", 1)
+	if err := os.WriteFile(remoteAuth, []byte(refreshed), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(remoteAuth, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if r := syncer.executeOperation(client, &SyncOperation{Provider: "claude", Profile: "work", Direction: SyncPull, Machine: m}); !r.Success {
+		t.Fatalf("pull failed: %v", r.Error)
+	}
+	if onDisk, _ := os.ReadFile(localAuth); strings.Contains(string(onDisk), "access_token") {
+		t.Errorf("pulled .claude.json stored in plaintext: %q", onDisk)
+	}
+	if data, err := v.ReadFile(localAuth); err != nil || string(data) != refreshed {
+		t.Errorf("ReadFile() = %q, %v; want the pulled token", data, err)
+	}
+}
+
+func TestSyncConflictDetection(t *testing.T) {
+	sshPub, _ := setupTestSSHHome(t)
+	port, _ := startTestSSHServer(t, sshPub)
+
+	m := NewMachine("remote", "127.0.0.1")
+	m.Port = port
+
+	localVault := t.TempDir()
+	remoteVault := t.TempDir()
+	localProfile := filepath.Join(localVault, "codex", "work")
+	remoteProfile := filepath.Join(remoteVault, "codex", "work")
+	for _, dir := range []string{localProfile, remoteProfile} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// auth.json for account email, expiring in hours.
+	writeAuth := func(dir, email string, hours int) {
+		t.Helper()
+		enc := base64.RawURLEncoding
+		idToken := enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(`{"email":"`+email+`"}`)) + ".sig"
+		data := fmt.Sprintf(`{"id_token":%q,"expires_at":%d}`, idToken, time.Now().Add(time.Duration(hours)*time.Hour).Unix())
+		if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	syncer := &Syncer{
+		pool:            NewConnectionPool(DefaultConnectOptions()),
+		state:           NewSyncState(t.TempDir()),
+		vaultPath:       localVault,
+		remoteVaultPath: remoteVault,
+	}
+	defer syncer.pool.CloseAll()
+	client, err := syncer.pool.Get(m)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	ref := ProfileRef{Provider: "codex", Profile: "work"}
+	plan := func() *SyncOperation {
+		t.Helper()
+		op, err := syncer.determineSyncOperation(client, m, ref)
+		if err != nil {
+			t.Fatalf("determineSyncOperation: %v", err)
+		}
+		return op
+	}
+
+	// Both exist with no sync recorded yet: freshness decides.
+	writeAuth(localProfile, "alice@example.com", 1)
+	writeAuth(remoteProfile, "alice@example.com", 2)
+	if op := plan(); op.Direction != SyncPull {
+		t.Fatalf("first sync direction = %q, want pull", op.Direction)
+	} else if r := syncer.executeOperation(client, op); !r.Success {
+		t.Fatalf("pull failed: %v", r.Error)
+	}
+
+	// Only the remote changes: no conflict.
+	writeAuth(remoteProfile, "alice@example.com", 3)
+	if op := plan(); op.Direction != SyncPull {
+		t.Fatalf("one-sided change direction = %q, want pull", op.Direction)
+	} else if r := syncer.executeOperation(client, op); !r.Success {
+		t.Fatalf("pull failed: %v", r.Error)
+	}
+
+	// Both change: the conflict is surfaced, and nothing is copied.
+	writeAuth(localProfile, "alice@example.com", 4)
+	writeAuth(remoteProfile, "bob@example.com", 5)
+	op := plan()
+	if op.Direction != SyncConflict || op.Conflict == nil {
+		t.Fatalf("two-sided change direction = %q, want conflict", op.Direction)
+	}
+	if op.Conflict.Local.Account != "alice@example.com" || op.Conflict.Remote.Account != "bob@example.com" {
+		t.Errorf("conflict accounts = %q / %q, want alice / bob", op.Conflict.Local.Account, op.Conflict.Remote.Account)
+	}
+	if !op.Conflict.Remote.Freshness.ExpiresAt.After(op.Conflict.Local.Freshness.ExpiresAt) {
+		t.Error("conflict should carry both copies' expiry")
+	}
+	if r := syncer.executeOperation(client, op); r.Success || !errors.Is(r.Error, ErrConflict) {
+		t.Errorf("executing a conflict: success %v, error %v; want ErrConflict", r.Success, r.Error)
+	}
+	if stats := AggregateResults([]*SyncResult{{Operation: op, Error: ErrConflict}}); stats.Conflicts != 1 || stats.Failed != 0 {
+		t.Errorf("AggregateResults() = %+v, want one conflict and no failures", stats)
+	}
+
+	// Keeping the local copy pushes it, even though the remote is fresher.
+	r, err := syncer.ResolveConflict(context.Background(), "codex", "work", m, PreferLocal)
+	if err != nil || !r.Success || r.Operation.Direction != SyncPush {
+		t.Fatalf("ResolveConflict(local) = %+v, %v; want a successful push", r, err)
+	}
+	remote, err := os.ReadFile(filepath.Join(remoteProfile, "auth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := os.ReadFile(filepath.Join(localProfile, "auth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(remote) != string(local) {
+		t.Error("remote should hold the local copy after keeping local")
+	}
+	if syncer.prefer != PreferAsk {
+		t.Error("ResolveConflict should not change the syncer's preference")
+	}
+	if _, err := syncer.ResolveConflict(context.Background(), "codex", "work", m, PreferAsk); err == nil {
+		t.Error("ResolveConflict without a side should fail")
+	}
+}
+
+func TestExchangeActivity(t *testing.T) {
+	sshPub, _ := setupTestSSHHome(t)
+	port, _ := startTestSSHServer(t, sshPub)
+
+	m := NewMachine("remote", "127.0.0.1")
+	m.Port = port
+
+	remoteRoot := t.TempDir()
+	remoteVault := filepath.Join(remoteRoot, "vault")
+	remoteActivity := filepath.Join(remoteRoot, "sync", activityDirName)
+
+	state := NewSyncState(t.TempDir())
+	state.Pool.BroadcastActivity = true
+	syncer := &Syncer{
+		pool:            NewConnectionPool(DefaultConnectOptions()),
+		state:           state,
+		vaultPath:       t.TempDir(),
+		remoteVaultPath: remoteVault,
+	}
+	defer syncer.pool.CloseAll()
+	client, err := syncer.pool.Get(m)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	now := time.Now()
+	here := &LocalIdentity{ID: "local-id", Hostname: "desk"}
+	there := &LocalIdentity{ID: "remote-id", Hostname: "laptop"}
+	if err := RecordActivation(state.activityDir(), here, "claude", "work-2", now); err != nil {
+		t.Fatalf("RecordActivation() error = %v", err)
+	}
+	// The remote holds its own activity and a stale copy of ours.
+	if err := RecordActivation(remoteActivity, there, "codex", "main", now.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordActivation(remoteActivity, here, "claude", "old", now.Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	syncer.shareActivity(client)
+
+	for name, dir := range map[string]string{"local": state.activityDir(), "remote": remoteActivity} {
+		all, err := LoadActivity(dir)
+		if err != nil {
+			t.Fatalf("LoadActivity(%s) error = %v", name, err)
+		}
+		got := make(map[string]string)
+		for _, a := range all {
+			for provider, act := range a.Active {
+				got[a.Machine+" "+provider] = act.Profile
+			}
+		}
+		want := map[string]string{"desk claude": "work-2", "laptop codex": "main"}
+		if len(got) != len(want) || got["desk claude"] != "work-2" || got["laptop codex"] != "main" {
+			t.Errorf("%s activity = %v, want %v", name, got, want)
+		}
+	}
+
+	// With broadcast off nothing is exchanged.
+	state.Pool.BroadcastActivity = false
+	if err := RecordActivation(state.activityDir(), here, "gemini", "alt", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	syncer.shareActivity(client)
+	all, _ := LoadActivity(remoteActivity)
+	for _, a := range all {
+		if _, ok := a.Active["gemini"]; ok {
+			t.Error("activity was shared with broadcast off")
+		}
+	}
+}
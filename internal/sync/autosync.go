@@ -103,6 +103,10 @@ type AutoSyncConfig struct {
 
 	// Verbose enables verbose logging.
 	Verbose bool
+
+	// OnGiveUp, if set, is called for each queue entry given up on when the
+	// pool's retry policy asks for a notification.
+	OnGiveUp func(entry QueueEntry)
 }
 
 // DefaultAutoSyncConfig returns the default auto-sync configuration.
@@ -211,8 +215,8 @@ func logSyncResults(results []*SyncResult, verbose bool) {
 	}
 
 	stats := AggregateResults(results)
-	log.Printf("Sync complete: %d pushed, %d pulled, %d skipped, %d failed",
-		stats.Pushed, stats.Pulled, stats.Skipped, stats.Failed)
+	log.Printf("Sync complete: %d pushed, %d pulled, %d skipped, %d conflicts, %d failed",
+		stats.Pushed, stats.Pulled, stats.Skipped, stats.Conflicts, stats.Failed)
 }
 
 // logSyncError logs a sync error.
@@ -229,7 +233,8 @@ func getFailedMachines(results []*SyncResult) []string {
 	seen := make(map[string]bool)
 
 	for _, r := range results {
-		if !r.Success && r.Operation != nil && r.Operation.Machine != nil {
+		// Conflicts wait for the user to pick a side; retrying won't help.
+		if !r.Success && r.Operation != nil && r.Operation.Machine != nil && r.Operation.Direction != SyncConflict {
 			machineID := r.Operation.Machine.ID
 			if !seen[machineID] {
 				seen[machineID] = true
@@ -293,8 +298,44 @@ func ProcessQueueIfNeededWithConfig(config AutoSyncConfig) {
 	go processQueue(state, config)
 }
 
-// processQueue processes all pending queue entries.
-func processQueue(state *SyncState, config AutoSyncConfig) {
+// QueueReport summarises one pass over the retry queue.
+type QueueReport struct {
+	// Retried is the number of entries attempted.
+	Retried int
+
+	// Succeeded is the number of attempted entries that synced.
+	Succeeded int
+
+	// Waiting is the number of entries not yet due under the retry policy.
+	Waiting int
+
+	// GaveUp lists entries that ran out of attempts on this pass.
+	GaveUp []QueueEntry
+
+	// Conflicts lists entries dropped because the profile changed on both
+	// sides; they need the user to pick a side with 'caam sync'.
+	Conflicts []QueueEntry
+}
+
+// ProcessQueue retries the queue entries that are due under the pool's
+// retry policy and waits for them to finish. The daemon calls this on every
+// check.
+func ProcessQueue(config AutoSyncConfig) (QueueReport, error) {
+	state, err := LoadSyncState()
+	if err != nil {
+		return QueueReport{}, err
+	}
+	if state.Pool == nil || !state.Pool.Enabled || state.Queue == nil || len(state.Queue.Entries) == 0 {
+		return QueueReport{}, nil
+	}
+	return processQueue(state, config), nil
+}
+
+// processQueue retries the pending queue entries that are due. Entries that
+// fail are rescheduled with backoff until the retry policy gives up on them.
+func processQueue(state *SyncState, config AutoSyncConfig) QueueReport {
+	var report QueueReport
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.SyncTimeout)
 	defer cancel()
 
@@ -307,13 +348,16 @@ func processQueue(state *SyncState, config AutoSyncConfig) {
 	syncer, err := NewSyncer(syncerConfig)
 	if err != nil {
 		logSyncError("create syncer for queue", err, config.Verbose)
-		return
+		return report
 	}
 	defer syncer.Close()
 
 	// Override syncer's state
 	syncer.state = state
 
+	policy := state.retryPolicy()
+	now := time.Now()
+
 	// Track entries to remove after iteration (modifying slice during range is unsafe)
 	type entryKey struct {
 		provider, profile, machine string
@@ -334,6 +378,12 @@ func processQueue(state *SyncState, config AutoSyncConfig) {
 			continue
 		}
 
+		if !policy.Due(entry, now) {
+			report.Waiting++
+			continue
+		}
+		report.Retried++
+
 		// Sync only with the specific machine that failed
 		result, err := syncer.SyncProfileWithMachine(ctx, entry.Provider, entry.Profile, machine)
 		if err != nil {
@@ -343,6 +393,33 @@ func processQueue(state *SyncState, config AutoSyncConfig) {
 
 		if result.Success {
 			toRemove = append(toRemove, entryKey{entry.Provider, entry.Profile, entry.Machine})
+			report.Succeeded++
+			continue
+		}
+		if result.Operation != nil && result.Operation.Direction == SyncConflict {
+			// Both sides changed meanwhile; that's for 'caam sync' to resolve.
+			toRemove = append(toRemove, entryKey{entry.Provider, entry.Profile, entry.Machine})
+			report.Conflicts = append(report.Conflicts, entry)
+			continue
+		}
+
+		// Count the failure and reschedule with backoff.
+		errMsg := errorToString(result.Error)
+		state.AddToQueue(entry.Provider, entry.Profile, entry.Machine, errMsg)
+		entry.Attempts++
+		entry.LastError = errMsg
+
+		if !policy.Exhausted(entry) {
+			continue
+		}
+		report.GaveUp = append(report.GaveUp, entry)
+		if policy.GiveUp == GiveUpKeep {
+			state.MarkGaveUp(entry.Provider, entry.Profile, entry.Machine)
+		} else {
+			toRemove = append(toRemove, entryKey{entry.Provider, entry.Profile, entry.Machine})
+		}
+		if policy.Notify && config.OnGiveUp != nil {
+			config.OnGiveUp(entry)
 		}
 	}
 
@@ -355,6 +432,8 @@ func processQueue(state *SyncState, config AutoSyncConfig) {
 	if err := state.Save(); err != nil {
 		logSyncError("save state", err, config.Verbose)
 	}
+
+	return report
 }
 
 // SetThrottleInterval updates the global throttle interval.
@@ -0,0 +1,166 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
+)
+
+// SyncConflict marks a profile changed on both sides since the last sync.
+// It is only planned when the syncer has no conflict preference.
+const SyncConflict SyncDirection = "conflict"
+
+// Conflict preferences for SyncerConfig.Prefer.
+const (
+	// PreferAsk leaves conflicts unresolved, for the caller to put to the user.
+	PreferAsk = ""
+	// PreferLocal keeps the local copy (push).
+	PreferLocal = "local"
+	// PreferRemote keeps the remote copy (pull).
+	PreferRemote = "remote"
+	// PreferFresher applies the usual freshness rules.
+	PreferFresher = "fresher"
+)
+
+// ErrConflict is the error of a sync result left unresolved because both
+// copies of the profile changed since the last sync.
+var ErrConflict = errors.New("local and remote both changed since the last sync")
+
+// ValidatePrefer checks a conflict preference.
+func ValidatePrefer(prefer string) error {
+	switch prefer {
+	case PreferAsk, PreferLocal, PreferRemote, PreferFresher:
+		return nil
+	}
+	return fmt.Errorf("invalid conflict preference %q (want local, remote or fresher)", prefer)
+}
+
+// Conflict describes both copies of a profile that changed on both sides.
+type Conflict struct {
+	Local  ConflictSide
+	Remote ConflictSide
+}
+
+// ConflictSide is one copy of a conflicting profile.
+type ConflictSide struct {
+	// Freshness is the copy's token freshness (expiry, modification time).
+	Freshness *TokenFreshness
+
+	// Account is the account the copy is logged in as (email or account
+	// ID), empty if unknown.
+	Account string
+}
+
+// syncBase records what both copies of a profile looked like after the last
+// successful sync with a machine, as content digests.
+type syncBase struct {
+	Local  string    `json:"local"`
+	Remote string    `json:"remote"`
+	At     time.Time `json:"at"`
+}
+
+// baseKey identifies a profile on a machine in SyncState.bases.
+func baseKey(machineID, provider, profile string) string {
+	return machineID + "/" + provider + "/" + profile
+}
+
+// syncedBase returns the digests recorded when the profile last synced with
+// the machine.
+func (s *SyncState) syncedBase(machineID, provider, profile string) (syncBase, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	base, ok := s.bases[baseKey(machineID, provider, profile)]
+	return base, ok
+}
+
+// recordSyncedBase records the digests of both copies after a sync.
+func (s *SyncState) recordSyncedBase(machineID, provider, profile string, base syncBase) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.bases == nil {
+		s.bases = make(map[string]syncBase)
+	}
+	s.bases[baseKey(machineID, provider, profile)] = base
+}
+
+// profileDigest fingerprints a profile's files, by name and content.
+func profileDigest(files map[string][]byte) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if !isSyncMetaFile(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte(0)
+		b.WriteString(hashBytes(files[name]))
+		b.WriteByte('\n')
+	}
+	return hashBytes([]byte(b.String()))
+}
+
+// mergeFiles returns base overlaid with over: the files a profile holds after
+// over is copied onto it.
+func mergeFiles(base, over map[string][]byte) map[string][]byte {
+	merged := make(map[string][]byte, len(base)+len(over))
+	for name, data := range base {
+		merged[name] = data
+	}
+	for name, data := range over {
+		merged[name] = data
+	}
+	return merged
+}
+
+// accountFromFiles returns the account a provider's auth files belong to.
+// The identity extractors read files, so the copy is staged in a private
+// temp directory.
+func accountFromFiles(provider string, files map[string][]byte) string {
+	dir, err := os.MkdirTemp("", "caam-conflict-")
+	if err != nil {
+		return ""
+	}
+	defer os.RemoveAll(dir)
+
+	for name, data := range files {
+		if isSyncMetaFile(name) || strings.ContainsAny(name, `/\`) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			return ""
+		}
+	}
+
+	var id *identity.Identity
+	switch provider {
+	case "claude":
+		id, _ = identity.ExtractFromClaudeCredentials(filepath.Join(dir, ".credentials.json"))
+	case "codex":
+		id, _ = identity.ExtractFromCodexAuth(filepath.Join(dir, "auth.json"))
+	case "gemini":
+		for _, name := range []string{"settings.json", "oauth_credentials.json"} {
+			if id, _ = identity.ExtractFromGeminiConfig(filepath.Join(dir, name)); id != nil {
+				break
+			}
+		}
+	}
+	if id == nil {
+		return ""
+	}
+	if id.Email != "" {
+		return id.Email
+	}
+	return id.AccountID
+}
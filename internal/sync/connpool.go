@@ -2,11 +2,19 @@ package sync
 
 import (
 	"sync"
+
+	"golang.org/x/crypto/ssh"
 )
 
 // ConnectionPool manages a pool of SSH connections.
+//
+// Like OpenSSH's ControlMaster, a pooled connection is reused by every
+// operation on its machine until it is released or goes dead, and jump hosts
+// are shared between machines so syncing several hosts behind one bastion
+// connects to the bastion once.
 type ConnectionPool struct {
 	clients map[string]*SSHClient
+	jumps   *jumpCache
 	mu      sync.RWMutex
 	opts    ConnectOptions
 }
@@ -15,19 +23,20 @@ type ConnectionPool struct {
 func NewConnectionPool(opts ConnectOptions) *ConnectionPool {
 	return &ConnectionPool{
 		clients: make(map[string]*SSHClient),
+		jumps:   newJumpCache(),
 		opts:    opts,
 	}
 }
 
 // Get returns a connected SSH client for the given machine.
-// If a connection already exists, it is reused.
+// If a live connection already exists, it is reused.
 func (p *ConnectionPool) Get(machine *Machine) (*SSHClient, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
 	// Check for existing connection
 	if client, exists := p.clients[machine.ID]; exists {
-		if client.IsConnected() {
+		if client.IsAlive() {
 			return client, nil
 		}
 		// Connection exists but is dead - clean it up before creating new one
@@ -37,6 +46,7 @@ func (p *ConnectionPool) Get(machine *Machine) (*SSHClient, error) {
 
 	// Create new connection
 	client := NewSSHClient(machine)
+	client.jumpCache = p.jumps
 	if err := client.Connect(p.opts); err != nil {
 		return nil, err
 	}
@@ -65,6 +75,7 @@ func (p *ConnectionPool) CloseAll() {
 		client.Disconnect()
 		delete(p.clients, id)
 	}
+	p.jumps.closeAll()
 }
 
 // Size returns the number of active connections in the pool.
@@ -113,3 +124,55 @@ func (p *ConnectionPool) ConnectedMachines() []string {
 	}
 	return ids
 }
+
+// jumpCache holds jump host connections shared by the clients of a pool,
+// keyed by the hop chain that reaches them.
+type jumpCache struct {
+	clients map[string]*ssh.Client
+	mu      sync.Mutex
+}
+
+func newJumpCache() *jumpCache {
+	return &jumpCache{clients: make(map[string]*ssh.Client)}
+}
+
+// get returns the live connection for chain, dialing a new one if there is
+// none or the cached one went dead.
+func (j *jumpCache) get(chain string, dial func() (*ssh.Client, error)) (*ssh.Client, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if client, ok := j.clients[chain]; ok {
+		if clientAlive(client) {
+			return client, nil
+		}
+		client.Close()
+		delete(j.clients, chain)
+	}
+
+	client, err := dial()
+	if err != nil {
+		return nil, err
+	}
+	j.clients[chain] = client
+	return client, nil
+}
+
+// size returns the number of cached jump connections.
+func (j *jumpCache) size() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return len(j.clients)
+}
+
+// closeAll closes every cached jump connection.
+func (j *jumpCache) closeAll() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for chain, client := range j.clients {
+		client.Close()
+		delete(j.clients, chain)
+	}
+}
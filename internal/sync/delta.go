@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+)
+
+// manifestFileName is the per-profile manifest caam keeps next to a remote
+// profile. It records the hash of each file caam wrote there, so syncs can
+// skip unchanged files without downloading them.
+const manifestFileName = ".caam_manifest.json"
+
+// fileManifest maps a profile's file names to what caam last wrote.
+type fileManifest map[string]manifestEntry
+
+// manifestEntry describes one file as caam left it on the remote.
+type manifestEntry struct {
+	SHA256  string    `json:"sha256"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// transferStats records what a push or pull moved.
+type transferStats struct {
+	bytes     int64
+	files     int
+	unchanged int
+}
+
+// hashBytes returns the hex SHA-256 of data.
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// isSyncMetaFile reports whether name is caam bookkeeping (the manifest or
+// an in-flight temp file) rather than part of the profile.
+func isSyncMetaFile(name string) bool {
+	return name == manifestFileName || strings.HasPrefix(name, ".caam_tmp_")
+}
+
+// current returns the recorded hash of name, provided the remote file still
+// has the size and modification time caam saw. Anything else - no entry, or
+// a file rewritten on the remote since - means the hash can't be trusted.
+func (m fileManifest) current(name string, info os.FileInfo) (string, bool) {
+	entry, ok := m[name]
+	if !ok || entry.SHA256 == "" {
+		return "", false
+	}
+	if entry.Size != info.Size() || !entry.ModTime.Equal(info.ModTime()) {
+		return "", false
+	}
+	return entry.SHA256, true
+}
+
+// readRemoteManifest loads the manifest of the remote profile at remotePath.
+// A missing or unreadable manifest is empty, so every file transfers.
+func readRemoteManifest(client *SSHClient, remotePath string) fileManifest {
+	manifest := make(fileManifest)
+	data, err := client.ReadFile(posixJoin(remotePath, manifestFileName))
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return make(fileManifest)
+	}
+	return manifest
+}
+
+// writeRemoteManifest saves manifest for the remote profile at remotePath
+// and returns the number of bytes written.
+func writeRemoteManifest(client *SSHClient, remotePath string, manifest fileManifest) (int64, error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := client.WriteFile(posixJoin(remotePath, manifestFileName), data, 0600); err != nil {
+		return 0, err
+	}
+	return int64(len(data)), nil
+}
+
+// remoteFileInfos lists the profile files at remotePath by name. A missing
+// directory lists as empty.
+func remoteFileInfos(client *SSHClient, remotePath string) map[string]os.FileInfo {
+	infos := make(map[string]os.FileInfo)
+	entries, err := client.ListDir(remotePath)
+	if err != nil {
+		return infos
+	}
+	for _, fi := range entries {
+		if fi.IsDir() || isSyncMetaFile(fi.Name()) {
+			continue
+		}
+		infos[fi.Name()] = fi
+	}
+	return infos
+}
@@ -22,7 +22,9 @@ var skipHosts = map[string]bool{
 // It filters out:
 //   - Known code hosting services (github, gitlab, etc.)
 //   - Wildcard hosts (Host *)
-//   - Hosts with ProxyJump (complex setups)
+//   - Hosts with ProxyCommand (arbitrary commands can't be replayed)
+//
+// Hosts with ProxyJump are kept, with the jump chain recorded on the machine.
 func DiscoverFromSSHConfig() ([]*Machine, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -46,7 +48,7 @@ func parseSSHConfig(path string) ([]*Machine, error) {
 
 	var machines []*Machine
 	var current *sshHost
-	var currentHasProxyJump bool
+	var currentHasProxyCommand bool
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
@@ -78,7 +80,7 @@ func parseSSHConfig(path string) ([]*Machine, error) {
 		switch key {
 		case "host":
 			// Save previous host if valid
-			if current != nil && !currentHasProxyJump {
+			if current != nil && !currentHasProxyCommand {
 				machines = append(machines, current.toMachines()...)
 			}
 
@@ -100,14 +102,14 @@ func parseSSHConfig(path string) ([]*Machine, error) {
 			}
 			if len(allowed) == 0 {
 				current = nil
-				currentHasProxyJump = false
+				currentHasProxyCommand = false
 				continue
 			}
 
 			current = &sshHost{
 				names: allowed,
 			}
-			currentHasProxyJump = false
+			currentHasProxyCommand = false
 
 		case "hostname":
 			if current != nil {
@@ -129,14 +131,19 @@ func parseSSHConfig(path string) ([]*Machine, error) {
 				current.identityFile = value
 			}
 
-		case "proxyjump", "proxycommand":
-			// Skip hosts with proxy configurations (complex setups)
-			currentHasProxyJump = true
+		case "proxyjump":
+			if current != nil {
+				current.proxyJump = value
+			}
+
+		case "proxycommand":
+			// Skip hosts that tunnel through arbitrary commands
+			currentHasProxyCommand = true
 		}
 	}
 
 	// Save last host
-	if current != nil && !currentHasProxyJump {
+	if current != nil && !currentHasProxyCommand {
 		machines = append(machines, current.toMachines()...)
 	}
 
@@ -154,6 +161,7 @@ type sshHost struct {
 	port         string
 	user         string
 	identityFile string
+	proxyJump    string
 }
 
 // toMachines converts an SSH host block to Machines.
@@ -201,6 +209,10 @@ func (h *sshHost) toMachines() []*Machine {
 			m.SSHKeyPath = expandPath(h.identityFile)
 		}
 
+		if !strings.EqualFold(h.proxyJump, "none") {
+			m.ProxyJump = h.proxyJump
+		}
+
 		machines = append(machines, m)
 	}
 
@@ -366,6 +366,9 @@ func containsPath(path, filename string) bool {
 }
 
 // ExtractFreshnessFromFiles reads auth files from disk and extracts freshness.
+// The files are parsed as they are on disk, so vault files, which may be
+// sealed or keychain stubs, should be read through the vault and passed to
+// ExtractFreshnessFromBytes instead.
 func ExtractFreshnessFromFiles(provider, profile string, filePaths []string) (*TokenFreshness, error) {
 	extractor := GetExtractor(provider)
 	if extractor == nil {
@@ -0,0 +1,158 @@
+package sync
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxClockSkew is how far a remote clock may drift from the local one before
+// status checks warn. Freshness comparisons rely on token timestamps, so a
+// large skew can make a sync pick the wrong side.
+const MaxClockSkew = time.Minute
+
+// remoteVersionCommand prints the remote caam version. Non-interactive SSH
+// sessions often miss ~/.local/bin from PATH, so common install locations
+// are tried too.
+const remoteVersionCommand = "caam version 2>/dev/null || ~/.local/bin/caam version 2>/dev/null || /usr/local/bin/caam version 2>/dev/null"
+
+// MachineHealth is what the last status check learned about a machine.
+type MachineHealth struct {
+	// CheckedAt is when the check ran.
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Latency is how long connecting took, as measured by the caller.
+	Latency time.Duration `json:"latency,omitempty"`
+
+	// ClockSkew is the remote clock minus the local one, to the second.
+	// Only meaningful when ClockSkewKnown is set.
+	ClockSkew time.Duration `json:"clock_skew,omitempty"`
+
+	// ClockSkewKnown is false when the remote clock couldn't be read.
+	ClockSkewKnown bool `json:"clock_skew_known,omitempty"`
+
+	// CAAMVersion is the remote caam version, empty if caam wasn't found.
+	CAAMVersion string `json:"caam_version,omitempty"`
+
+	// Profiles counts the profiles in the remote vault by provider.
+	Profiles map[string]int `json:"profiles,omitempty"`
+}
+
+// CheckMachineHealth gathers clock skew, caam version and profile counts
+// from a connected machine. vaultPath is the remote vault directory and
+// providers the tools whose profiles are counted. Each probe is best
+// effort; what can't be read is left empty.
+func CheckMachineHealth(client *SSHClient, vaultPath string, providers []string) *MachineHealth {
+	h := &MachineHealth{CheckedAt: time.Now()}
+
+	// Take the local clock halfway through the round trip.
+	before := time.Now()
+	if out, err := client.Run("date +%s"); err == nil {
+		after := time.Now()
+		if secs, err := strconv.ParseInt(strings.TrimSpace(out), 10, 64); err == nil {
+			local := before.Add(after.Sub(before) / 2)
+			h.ClockSkew = time.Unix(secs, 0).Sub(local).Round(time.Second)
+			h.ClockSkewKnown = true
+		}
+	}
+
+	if out, err := client.Run(remoteVersionCommand); err == nil {
+		h.CAAMVersion = parseCAAMVersion(out)
+	}
+
+	h.Profiles = make(map[string]int)
+	for _, provider := range providers {
+		entries, err := client.ListDir(posixJoin(vaultPath, provider))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if e.IsDir() && !strings.HasPrefix(e.Name(), "_") {
+				h.Profiles[provider]++
+			}
+		}
+	}
+
+	return h
+}
+
+// parseCAAMVersion extracts the version from 'caam version' output
+// ("caam v1.2.3 (abc123) built on ...").
+func parseCAAMVersion(out string) string {
+	fields := strings.Fields(out)
+	if len(fields) < 2 || fields[0] != "caam" {
+		return ""
+	}
+	return fields[1]
+}
+
+// VersionsCompatible reports whether two caam versions can sync with each
+// other: same major version. Development builds are compatible with anything.
+func VersionsCompatible(local, remote string) bool {
+	localMajor, ok := majorVersion(local)
+	if !ok {
+		return true
+	}
+	remoteMajor, ok := majorVersion(remote)
+	if !ok {
+		return true
+	}
+	return localMajor == remoteMajor
+}
+
+// majorVersion returns the major number of a version like "v1.2.3" or
+// "1.2.3-rc1". It is not ok for "dev" and other non-release versions.
+func majorVersion(v string) (int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	major, _, _ := strings.Cut(v, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// Warnings lists problems the check found, comparing the remote caam with
+// localVersion.
+func (h *MachineHealth) Warnings(localVersion string) []string {
+	if h == nil {
+		return nil
+	}
+
+	var warnings []string
+	switch {
+	case h.CAAMVersion == "":
+		warnings = append(warnings, "caam not found on the remote")
+	case !VersionsCompatible(localVersion, h.CAAMVersion):
+		warnings = append(warnings, fmt.Sprintf("remote runs caam %s, incompatible with local %s", h.CAAMVersion, localVersion))
+	}
+	if h.ClockSkewKnown && h.ClockSkew > MaxClockSkew {
+		warnings = append(warnings, fmt.Sprintf("clock is %s ahead; freshness comparisons may pick the wrong side", h.ClockSkew))
+	}
+	if h.ClockSkewKnown && h.ClockSkew < -MaxClockSkew {
+		warnings = append(warnings, fmt.Sprintf("clock is %s behind; freshness comparisons may pick the wrong side", -h.ClockSkew))
+	}
+	return warnings
+}
+
+// LastSyncByProvider returns, per provider, when the named machine last
+// completed a push or pull, from the sync history.
+func (s *SyncState) LastSyncByProvider(machineName string) map[string]time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	last := make(map[string]time.Time)
+	if s.History == nil {
+		return last
+	}
+	for _, e := range s.History.Entries {
+		if e.Machine != machineName || !e.Success || e.Action == string(SyncSkip) {
+			continue
+		}
+		if e.Timestamp.After(last[e.Provider]) {
+			last[e.Provider] = e.Timestamp
+		}
+	}
+	return last
+}
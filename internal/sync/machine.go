@@ -56,6 +56,11 @@ type Machine struct {
 	// SSHKeyPath is the path to the SSH private key.
 	SSHKeyPath string `json:"ssh_key_path,omitempty"`
 
+	// ProxyJump is an OpenSSH-style jump host chain used to reach this
+	// machine, e.g. "bastion" or "admin@bastion.example.com:2222,inner".
+	// Hosts may be ~/.ssh/config aliases.
+	ProxyJump string `json:"proxy_jump,omitempty"`
+
 	// RemotePath is the path to caam data on the remote machine.
 	// If empty, defaults to the same path as local data.
 	RemotePath string `json:"remote_path,omitempty"`
@@ -77,6 +82,9 @@ type Machine struct {
 
 	// Source indicates where this machine definition came from.
 	Source string `json:"source"`
+
+	// Health is the result of the last 'caam machines status' check.
+	Health *MachineHealth `json:"health,omitempty"`
 }
 
 // NewMachine creates a new Machine with a generated UUID.
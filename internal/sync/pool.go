@@ -29,9 +29,16 @@ type SyncPool struct {
 	// Defaults to false - must be explicitly enabled by user.
 	AutoSync bool `json:"auto_sync"`
 
+	// BroadcastActivity shares which profile each machine has active with
+	// the rest of the pool on every sync, for 'caam status'. Off by default.
+	BroadcastActivity bool `json:"broadcast_activity,omitempty"`
+
 	// LastFullSync is the timestamp of the last full sync operation.
 	LastFullSync time.Time `json:"last_full_sync,omitempty"`
 
+	// Retry is the policy for retrying failed syncs from the queue.
+	Retry RetryPolicy `json:"retry"`
+
 	// basePath is the directory where pool.json is stored.
 	// If empty, uses the global SyncDataDir().
 	basePath string
@@ -46,6 +53,7 @@ func NewSyncPool() *SyncPool {
 		Machines: make(map[string]*Machine),
 		Enabled:  false,
 		AutoSync: false,
+		Retry:    DefaultRetryPolicy(),
 	}
 }
 
@@ -271,23 +279,30 @@ func (p *SyncPool) Load() error {
 			p.Machines = make(map[string]*Machine)
 			p.Enabled = false
 			p.AutoSync = false
+			p.Retry = DefaultRetryPolicy()
 			return nil
 		}
 		return fmt.Errorf("read pool: %w", err)
 	}
 
-	// Unmarshal into a temporary struct to preserve the lock
-	var loaded SyncPool
+	// Unmarshal into a temporary struct to preserve the lock. Retry starts
+	// from the defaults so a partial policy only overrides what it sets.
+	loaded := SyncPool{Retry: DefaultRetryPolicy()}
 	if err := json.Unmarshal(data, &loaded); err != nil {
 		return fmt.Errorf("parse pool: %w", err)
 	}
+	if err := loaded.Retry.Validate(); err != nil {
+		return fmt.Errorf("invalid pool: %w", err)
+	}
 
 	// Copy fields
 	p.LocalMachineID = loaded.LocalMachineID
 	p.Machines = loaded.Machines
 	p.Enabled = loaded.Enabled
 	p.AutoSync = loaded.AutoSync
+	p.BroadcastActivity = loaded.BroadcastActivity
 	p.LastFullSync = loaded.LastFullSync
+	p.Retry = loaded.Retry
 
 	// Ensure map is initialized
 	if p.Machines == nil {
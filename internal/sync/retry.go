@@ -0,0 +1,105 @@
+package sync
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+)
+
+// Give-up behaviours for queue entries that run out of attempts.
+const (
+	// GiveUpDrop removes the entry from the queue.
+	GiveUpDrop = "drop"
+	// GiveUpKeep leaves the entry queued, but only 'caam sync retry --now'
+	// retries it.
+	GiveUpKeep = "keep"
+)
+
+// RetryPolicy controls how failed syncs waiting in the queue are retried.
+// It is stored with the pool, under "retry" in pool.json.
+type RetryPolicy struct {
+	// MaxAttempts is how many failed attempts an entry gets before it is
+	// given up on. Zero retries forever.
+	MaxAttempts int `json:"max_attempts"`
+
+	// BackoffBase is the wait after the first failure; it doubles with each
+	// further failure.
+	BackoffBase config.Duration `json:"backoff_base"`
+
+	// BackoffMax caps the wait between attempts.
+	BackoffMax config.Duration `json:"backoff_max"`
+
+	// Jitter randomly stretches each wait by up to this fraction (0-1), so
+	// entries that failed together don't all retry together.
+	Jitter float64 `json:"jitter"`
+
+	// GiveUp is GiveUpDrop or GiveUpKeep.
+	GiveUp string `json:"give_up"`
+
+	// Notify sends a desktop notification when an entry is given up on.
+	Notify bool `json:"notify"`
+}
+
+// DefaultRetryPolicy returns the retry policy used when the pool sets none.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 8,
+		BackoffBase: config.Duration(time.Minute),
+		BackoffMax:  config.Duration(6 * time.Hour),
+		Jitter:      0.2,
+		GiveUp:      GiveUpDrop,
+		Notify:      true,
+	}
+}
+
+// Validate checks the policy for values that can't be applied.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("retry.max_attempts must not be negative")
+	}
+	if p.BackoffMax.Duration() > 0 && p.BackoffBase.Duration() > p.BackoffMax.Duration() {
+		return fmt.Errorf("retry.backoff_base (%s) exceeds retry.backoff_max (%s)", p.BackoffBase, p.BackoffMax)
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("retry.jitter must be between 0 and 1, got %v", p.Jitter)
+	}
+	if p.GiveUp != GiveUpDrop && p.GiveUp != GiveUpKeep {
+		return fmt.Errorf("retry.give_up must be %q or %q, got %q", GiveUpDrop, GiveUpKeep, p.GiveUp)
+	}
+	return nil
+}
+
+// Backoff returns how long to wait before the next try of an entry that has
+// failed attempts times, jitter included.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	wait := p.BackoffBase.Duration()
+	limit := p.BackoffMax.Duration()
+	for i := 1; i < attempts; i++ {
+		if limit > 0 && wait >= limit {
+			break
+		}
+		wait *= 2
+	}
+	if limit > 0 && wait > limit {
+		wait = limit
+	}
+	if p.Jitter > 0 && wait > 0 {
+		wait += time.Duration(rand.Float64() * p.Jitter * float64(wait))
+	}
+	return wait
+}
+
+// Exhausted reports whether e has used up its attempts.
+func (p RetryPolicy) Exhausted(e QueueEntry) bool {
+	return p.MaxAttempts > 0 && e.Attempts >= p.MaxAttempts
+}
+
+// Due reports whether e should be retried automatically at now.
+func (p RetryPolicy) Due(e QueueEntry, now time.Time) bool {
+	if e.GaveUp || p.Exhausted(e) {
+		return false
+	}
+	return !now.Before(e.NextAttempt)
+}
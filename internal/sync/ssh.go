@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -42,7 +43,9 @@ type SSHClient struct {
 	machine    *Machine
 	client     *ssh.Client
 	sftp       *sftp.Client
-	agentConn  net.Conn // SSH agent connection (needs cleanup)
+	agentConn  net.Conn      // SSH agent connection (needs cleanup)
+	jumps      []*ssh.Client // ProxyJump hops owned by this client
+	jumpCache  *jumpCache    // Shared hops when the client belongs to a pool
 	connected  bool
 	lastConnAt time.Time
 	opts       ConnectOptions
@@ -113,8 +116,9 @@ func (c *SSHClient) Connect(opts ConnectOptions) error {
 
 	// Connect
 	addr := c.machine.HostPort()
-	client, err := ssh.Dial("tcp", addr, config)
+	client, err := c.dial(addr, config)
 	if err != nil {
+		c.closeJumps()
 		return &SSHError{
 			Machine:    c.machine,
 			Operation:  "connect",
@@ -144,21 +148,215 @@ func (c *SSHClient) Disconnect() error {
 		err := c.client.Close()
 		c.client = nil
 		c.connected = false
+		c.closeJumps()
 		return err
 	}
+	c.closeJumps()
 	return nil
 }
 
+// closeJumps closes the ProxyJump hops this client dialed itself, innermost
+// first. Hops shared through a pool are closed by the pool.
+func (c *SSHClient) closeJumps() {
+	for i := len(c.jumps) - 1; i >= 0; i-- {
+		c.jumps[i].Close()
+	}
+	c.jumps = nil
+}
+
 // IsConnected returns true if the connection is established.
 func (c *SSHClient) IsConnected() bool {
 	return c.connected && c.client != nil
 }
 
+// IsAlive reports whether the connection still answers, by sending an
+// OpenSSH keepalive request. Unlike IsConnected it notices connections the
+// remote end (or a NAT) has dropped.
+func (c *SSHClient) IsAlive() bool {
+	return c.IsConnected() && clientAlive(c.client)
+}
+
+// clientAlive sends a keepalive request over client. Servers that don't know
+// the request reply with a failure, which still proves the link is up.
+func clientAlive(client *ssh.Client) bool {
+	_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+	return err == nil
+}
+
+// dial connects to addr, directly or through the machine's ProxyJump chain.
+func (c *SSHClient) dial(addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	spec := c.machine.ProxyJump
+	hosts := sshConfigHosts()
+	if spec == "" {
+		if entry := lookupSSHConfigHost(hosts, c.machine); entry != nil {
+			spec = entry.ProxyJump
+		}
+	}
+	if spec == "" || strings.EqualFold(spec, "none") {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	hops, err := parseProxyJump(spec, hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	var via *ssh.Client
+	var chain string
+	for _, hop := range hops {
+		chain += hop.String() + ","
+		hopConfig := *config
+		if hop.user != "" {
+			hopConfig.User = hop.user
+		}
+		if hop.keyPath != "" {
+			if signer, err := loadSSHKey(hop.keyPath); err == nil {
+				hopConfig.Auth = append([]ssh.AuthMethod{ssh.PublicKeys(signer)}, config.Auth...)
+			}
+		}
+
+		prev := via
+		dialHop := func() (*ssh.Client, error) {
+			return dialVia(prev, hop.addr, &hopConfig)
+		}
+
+		var next *ssh.Client
+		if c.jumpCache != nil {
+			next, err = c.jumpCache.get(chain, dialHop)
+		} else {
+			next, err = dialHop()
+			if err == nil {
+				c.jumps = append(c.jumps, next)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jump host %s: %w", hop.addr, err)
+		}
+		via = next
+	}
+
+	return dialVia(via, addr, config)
+}
+
+// dialVia opens an SSH connection to addr, tunnelled through via when it is
+// not nil (the equivalent of ssh -J).
+func dialVia(via *ssh.Client, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	if via == nil {
+		return ssh.Dial("tcp", addr, config)
+	}
+
+	conn, err := via.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(sshConn, chans, reqs), nil
+}
+
+// jumpHop is one host of a ProxyJump chain.
+type jumpHop struct {
+	user    string
+	addr    string // host:port
+	keyPath string
+}
+
+func (h jumpHop) String() string {
+	if h.user == "" {
+		return h.addr
+	}
+	return h.user + "@" + h.addr
+}
+
+// parseProxyJump splits an OpenSSH ProxyJump value ("[user@]host[:port]",
+// comma-separated, outermost first) into hops. A hop naming a Host alias
+// from ~/.ssh/config takes its HostName, Port, User and IdentityFile, as ssh
+// itself would.
+func parseProxyJump(spec string, hosts map[string]*Machine) ([]jumpHop, error) {
+	var hops []jumpHop
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimPrefix(strings.TrimSpace(raw), "ssh://")
+		if raw == "" {
+			continue
+		}
+
+		host, port, user := ParseAddress(raw)
+		if host == "" {
+			return nil, fmt.Errorf("invalid ProxyJump host %q", raw)
+		}
+		hop := jumpHop{user: user}
+		if alias, ok := hosts[host]; ok {
+			host = alias.Address
+			if port == 0 {
+				port = alias.Port
+			}
+			if hop.user == "" {
+				hop.user = alias.SSHUser
+			}
+			hop.keyPath = alias.SSHKeyPath
+		}
+		if port == 0 {
+			port = DefaultSSHPort
+		}
+		hop.addr = net.JoinHostPort(host, strconv.Itoa(port))
+		hops = append(hops, hop)
+	}
+
+	if len(hops) == 0 {
+		return nil, fmt.Errorf("invalid ProxyJump %q", spec)
+	}
+	return hops, nil
+}
+
+// sshConfigHosts returns the hosts of ~/.ssh/config by alias. Errors are
+// ignored: a missing or unreadable config just means no aliases.
+func sshConfigHosts() map[string]*Machine {
+	machines, err := DiscoverFromSSHConfig()
+	if err != nil {
+		return nil
+	}
+	hosts := make(map[string]*Machine, len(machines))
+	for _, m := range machines {
+		hosts[m.Name] = m
+	}
+	return hosts
+}
+
+// lookupSSHConfigHost returns the ~/.ssh/config entry for m, matched by name
+// and then by address, or nil.
+func lookupSSHConfigHost(hosts map[string]*Machine, m *Machine) *Machine {
+	if entry, ok := hosts[m.Name]; ok {
+		return entry
+	}
+	if entry, ok := hosts[m.Address]; ok {
+		return entry
+	}
+	return nil
+}
+
 // getAuthMethods returns available SSH authentication methods.
 func (c *SSHClient) getAuthMethods() ([]ssh.AuthMethod, error) {
 	var methods []ssh.AuthMethod
 
-	// 1. Try ssh-agent first
+	// 1. The machine's own key file goes first, so it is offered before an
+	// agent holding many keys exhausts the server's MaxAuthTries. Machines
+	// without one use the IdentityFile of their ~/.ssh/config entry.
+	keyPath := c.machine.SSHKeyPath
+	if keyPath == "" {
+		if entry := lookupSSHConfigHost(sshConfigHosts(), c.machine); entry != nil {
+			keyPath = entry.SSHKeyPath
+		}
+	}
+	if keyPath != "" {
+		if signer, err := loadSSHKey(keyPath); err == nil {
+			methods = append(methods, ssh.PublicKeys(signer))
+		}
+	}
+
+	// 2. Then ssh-agent (which also covers passphrase-protected key files)
 	if c.opts.UseAgent {
 		if agentAuth, agentConn, err := getSSHAgentAuth(); err == nil {
 			methods = append(methods, agentAuth)
@@ -166,13 +364,6 @@ func (c *SSHClient) getAuthMethods() ([]ssh.AuthMethod, error) {
 		}
 	}
 
-	// 2. Add key file if specified
-	if c.machine.SSHKeyPath != "" {
-		if signer, err := loadSSHKey(c.machine.SSHKeyPath); err == nil {
-			methods = append(methods, ssh.PublicKeys(signer))
-		}
-	}
-
 	// 3. Try default keys
 	for _, keyPath := range defaultKeyPaths() {
 		if signer, err := loadSSHKey(keyPath); err == nil {
@@ -487,6 +678,26 @@ func (c *SSHClient) BatchWrite(files map[string][]byte, mode os.FileMode) error
 	return nil
 }
 
+// Run executes cmd in a new session on the remote machine and returns its
+// standard output.
+func (c *SSHClient) Run(cmd string) (string, error) {
+	if !c.connected {
+		return "", &SSHError{Machine: c.machine, Operation: "exec", Underlying: errors.New("not connected")}
+	}
+
+	session, err := c.client.NewSession()
+	if err != nil {
+		return "", &SSHError{Machine: c.machine, Operation: "exec", Underlying: err}
+	}
+	defer session.Close()
+
+	out, err := session.Output(cmd)
+	if err != nil {
+		return string(out), &SSHError{Machine: c.machine, Operation: "exec", Underlying: err}
+	}
+	return string(out), nil
+}
+
 // posixJoin joins path elements using forward slashes (for SFTP/remote paths).
 // Unlike filepath.Join, this always uses forward slashes regardless of OS.
 func posixJoin(elem ...string) string {
@@ -1,10 +1,21 @@
 package sync
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"errors"
+	"io"
 	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
 )
 
 func TestSSHError(t *testing.T) {
@@ -362,3 +373,323 @@ func TestPosixDir(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProxyJump(t *testing.T) {
+	hosts := map[string]*Machine{
+		"bastion": {Name: "bastion", Address: "10.0.0.1", Port: 2200, SSHUser: "ops", SSHKeyPath: "/keys/bastion"},
+	}
+
+	hops, err := parseProxyJump("bastion, admin@gw.example.com:2222,ssh://inner", hosts)
+	if err != nil {
+		t.Fatalf("parseProxyJump() error = %v", err)
+	}
+	want := []jumpHop{
+		{user: "ops", addr: "10.0.0.1:2200", keyPath: "/keys/bastion"},
+		{user: "admin", addr: "gw.example.com:2222"},
+		{addr: "inner:22"},
+	}
+	if len(hops) != len(want) {
+		t.Fatalf("parseProxyJump() = %+v, want %+v", hops, want)
+	}
+	for i := range want {
+		if hops[i] != want[i] {
+			t.Errorf("hop %d = %+v, want %+v", i, hops[i], want[i])
+		}
+	}
+
+	if hops, _ := parseProxyJump("jeff@bastion:22", hosts); hops[0].user != "jeff" || hops[0].addr != "10.0.0.1:22" {
+		t.Errorf("explicit user and port should beat the alias, got %+v", hops[0])
+	}
+	if _, err := parseProxyJump(" , ", hosts); err == nil {
+		t.Error("parseProxyJump() should reject an empty chain")
+	}
+}
+
+// startTestSSHServer runs an SSH server on localhost that accepts key,
+// serves SFTP from the local filesystem and forwards direct-tcpip channels,
+// so it can serve as a sync target or a jump host. It returns the server's
+// port and a count of the connections it authenticated.
+func startTestSSHServer(t *testing.T, key ssh.PublicKey) (int, *atomic.Int32) {
+	t.Helper()
+	return startTestSSHServerWithExec(t, key, nil)
+}
+
+// startTestSSHServerWithExec is startTestSSHServer, additionally answering
+// exec requests with exec, which returns the command's output and exit
+// status. A nil exec rejects them.
+func startTestSSHServerWithExec(t *testing.T, key ssh.PublicKey, exec func(cmd string) (string, uint32)) (int, *atomic.Int32) {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostSigner, err := ssh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, offered ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(offered.Marshal()) == string(key.Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var accepted atomic.Int32
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					conn.Close()
+					return
+				}
+				accepted.Add(1)
+				go ssh.DiscardRequests(reqs)
+				for newChan := range chans {
+					if newChan.ChannelType() == "session" {
+						go serveTestSession(newChan, exec)
+						continue
+					}
+					if newChan.ChannelType() != "direct-tcpip" {
+						newChan.Reject(ssh.UnknownChannelType, "unsupported")
+						continue
+					}
+					var target struct {
+						Host     string
+						Port     uint32
+						OrigHost string
+						OrigPort uint32
+					}
+					if err := ssh.Unmarshal(newChan.ExtraData(), &target); err != nil {
+						newChan.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					upstream, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+					if err != nil {
+						newChan.Reject(ssh.ConnectionFailed, err.Error())
+						continue
+					}
+					channel, chanReqs, err := newChan.Accept()
+					if err != nil {
+						upstream.Close()
+						continue
+					}
+					go ssh.DiscardRequests(chanReqs)
+					go func() {
+						io.Copy(channel, upstream)
+						channel.Close()
+					}()
+					go func() {
+						io.Copy(upstream, channel)
+						upstream.Close()
+					}()
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, &accepted
+}
+
+// serveTestSession accepts a session channel and serves the sftp subsystem,
+// or an exec request when exec is set, on it.
+func serveTestSession(newChan ssh.NewChannel, exec func(cmd string) (string, uint32)) {
+	channel, reqs, err := newChan.Accept()
+	if err != nil {
+		return
+	}
+	defer channel.Close()
+	for req := range reqs {
+		if req.Type == "exec" && exec != nil {
+			req.Reply(true, nil)
+			out, status := exec(string(req.Payload[4:]))
+			io.WriteString(channel, out)
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			return
+		}
+		if req.Type == "subsystem" && string(req.Payload[4:]) == "sftp" {
+			req.Reply(true, nil)
+			server, err := sftp.NewServer(channel)
+			if err != nil {
+				return
+			}
+			server.Serve()
+			return
+		}
+		req.Reply(false, nil)
+	}
+}
+
+// setupTestSSHHome points HOME at a temp dir holding a fresh default key
+// (~/.ssh/id_ed25519) and returns the key's public half and the .ssh dir.
+func setupTestSSHHome(t *testing.T) (ssh.PublicKey, string) {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USER", "tester")
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sshDir, "id_ed25519"), pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sshPub, sshDir
+}
+
+func TestConnectThroughProxyJump(t *testing.T) {
+	sshPub, sshDir := setupTestSSHHome(t)
+
+	bastionPort, bastionConns := startTestSSHServer(t, sshPub)
+	onePort, oneConns := startTestSSHServer(t, sshPub)
+	twoPort, _ := startTestSSHServer(t, sshPub)
+
+	sshConfig := "Host bastion\n    HostName 127.0.0.1\n    Port " + strconv.Itoa(bastionPort) + "\n"
+	if err := os.WriteFile(filepath.Join(sshDir, "config"), []byte(sshConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	one := NewMachine("one", "127.0.0.1")
+	one.Port = onePort
+	one.ProxyJump = "bastion"
+	two := NewMachine("two", "127.0.0.1")
+	two.Port = twoPort
+	two.ProxyJump = "bastion"
+
+	pool := NewConnectionPool(DefaultConnectOptions())
+	first, err := pool.Get(one)
+	if err != nil {
+		t.Fatalf("Get(one) error = %v", err)
+	}
+	if !first.IsAlive() {
+		t.Fatal("connection through the jump host should be alive")
+	}
+	if _, err := pool.Get(two); err != nil {
+		t.Fatalf("Get(two) error = %v", err)
+	}
+	again, err := pool.Get(one)
+	if err != nil {
+		t.Fatalf("second Get(one) error = %v", err)
+	}
+
+	if again != first || oneConns.Load() != 1 {
+		t.Errorf("pooled connection should be reused, got %d connections to one", oneConns.Load())
+	}
+	if bastionConns.Load() != 1 || pool.jumps.size() != 1 {
+		t.Errorf("machines behind one bastion should share it, got %d bastion connections", bastionConns.Load())
+	}
+
+	pool.CloseAll()
+	if pool.jumps.size() != 0 {
+		t.Error("CloseAll() should close shared jump connections")
+	}
+
+	// Outside a pool, the client owns its hops.
+	client := NewSSHClient(one)
+	if err := client.Connect(DefaultConnectOptions()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if len(client.jumps) != 1 {
+		t.Errorf("jumps = %d, want 1", len(client.jumps))
+	}
+	client.Disconnect()
+	if client.jumps != nil {
+		t.Error("Disconnect() should close the client's hops")
+	}
+}
+
+func TestCheckMachineHealth(t *testing.T) {
+	sshPub, _ := setupTestSSHHome(t)
+
+	skew := 5 * time.Minute
+	port, _ := startTestSSHServerWithExec(t, sshPub, func(cmd string) (string, uint32) {
+		switch {
+		case cmd == "date +%s":
+			return strconv.FormatInt(time.Now().Add(skew).Unix(), 10) + "\n", 0
+		case cmd == remoteVersionCommand:
+			return "caam v2.1.0 (abc1234) built on 2026-01-01 with go1.25\n", 0
+		}
+		return "", 127
+	})
+
+	vaultDir := t.TempDir()
+	for _, dir := range []string{"claude/work", "claude/personal", "claude/_original", "codex/main", "aider/main"} {
+		if err := os.MkdirAll(filepath.Join(vaultDir, dir), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := NewMachine("remote", "127.0.0.1")
+	m.Port = port
+	client := NewSSHClient(m)
+	if err := client.Connect(DefaultConnectOptions()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer client.Disconnect()
+
+	h := CheckMachineHealth(client, vaultDir, []string{"claude", "codex", "gemini", "aider"})
+	if !h.ClockSkewKnown || h.ClockSkew < skew-2*time.Second || h.ClockSkew > skew+2*time.Second {
+		t.Errorf("ClockSkew = %v (known %v), want about %v", h.ClockSkew, h.ClockSkewKnown, skew)
+	}
+	if h.CAAMVersion != "v2.1.0" {
+		t.Errorf("CAAMVersion = %q, want v2.1.0", h.CAAMVersion)
+	}
+	if h.Profiles["claude"] != 2 || h.Profiles["codex"] != 1 || h.Profiles["gemini"] != 0 || h.Profiles["aider"] != 1 {
+		t.Errorf("Profiles = %v, want claude:2 codex:1 aider:1", h.Profiles)
+	}
+
+	warnings := h.Warnings("v1.9.0")
+	if len(warnings) != 2 {
+		t.Fatalf("Warnings() = %v, want version and clock warnings", warnings)
+	}
+	if len(h.Warnings("v2.0.3")) != 1 {
+		t.Errorf("same major version should only warn about the clock, got %v", h.Warnings("v2.0.3"))
+	}
+}
+
+func TestVersionsCompatible(t *testing.T) {
+	tests := []struct {
+		local, remote string
+		want          bool
+	}{
+		{"v1.2.3", "v1.0.0", true},
+		{"1.2.3", "v1.9.0-rc1", true},
+		{"v1.2.3", "v2.0.0", false},
+		{"dev", "v2.0.0", true},
+		{"v1.2.3", "dev", true},
+	}
+	for _, tt := range tests {
+		if got := VersionsCompatible(tt.local, tt.remote); got != tt.want {
+			t.Errorf("VersionsCompatible(%q, %q) = %v, want %v", tt.local, tt.remote, got, tt.want)
+		}
+	}
+}
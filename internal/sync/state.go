@@ -23,6 +23,10 @@ type SyncState struct {
 	// History records recent sync operations.
 	History *SyncHistory
 
+	// bases records each profile's content after its last sync with each
+	// machine, to tell conflicts from one-sided changes.
+	bases map[string]syncBase
+
 	basePath string
 	mu       sync.RWMutex
 }
@@ -58,6 +62,14 @@ type QueueEntry struct {
 
 	// LastError is the error from the last attempt.
 	LastError string `json:"last_error,omitempty"`
+
+	// NextAttempt is when the entry is next retried automatically, per the
+	// pool's retry policy.
+	NextAttempt time.Time `json:"next_attempt,omitempty"`
+
+	// GaveUp is set once the entry ran out of attempts and was kept, so only
+	// a forced retry picks it up again.
+	GaveUp bool `json:"gave_up,omitempty"`
 }
 
 // SyncHistory records recent sync operations.
@@ -97,12 +109,22 @@ type HistoryEntry struct {
 
 	// Duration is how long the operation took.
 	Duration time.Duration `json:"duration"`
+
+	// BytesTransferred is how much was copied, in either direction.
+	BytesTransferred int64 `json:"bytes_transferred,omitempty"`
+
+	// FilesTransferred is the number of profile files copied.
+	FilesTransferred int `json:"files_transferred,omitempty"`
+
+	// FilesUnchanged is the number of files skipped as already identical.
+	FilesUnchanged int `json:"files_unchanged,omitempty"`
 }
 
-// Queue and History file names.
+// Queue, History and sync base file names.
 const (
 	queueFileName   = "queue.json"
 	historyFileName = "history.json"
+	basesFileName   = "bases.json"
 )
 
 // Default sizes.
@@ -178,6 +200,13 @@ func (s *SyncState) Load() error {
 		}
 	}
 
+	// Load sync bases
+	if err := s.loadBases(); err != nil {
+		// Non-fatal - without bases, no conflicts are detected until the
+		// next sync records them
+		s.bases = make(map[string]syncBase)
+	}
+
 	return nil
 }
 
@@ -203,6 +232,28 @@ func (s *SyncState) Save() error {
 		return fmt.Errorf("save history: %w", err)
 	}
 
+	// Save sync bases
+	if s.bases != nil {
+		if err := s.saveJSON(basesFileName, s.bases); err != nil {
+			return fmt.Errorf("save sync bases: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadBases loads the sync bases from disk.
+func (s *SyncState) loadBases() error {
+	data, err := os.ReadFile(filepath.Join(s.basePath, basesFileName))
+	if err != nil {
+		return err
+	}
+
+	bases := make(map[string]syncBase)
+	if err := json.Unmarshal(data, &bases); err != nil {
+		return err
+	}
+	s.bases = bases
 	return nil
 }
 
@@ -333,13 +384,17 @@ func (s *SyncState) AddToQueue(provider, profile, machineID, errorMsg string) {
 		}
 	}
 
+	policy := s.retryPolicy()
+	now := time.Now()
+
 	// Check if entry already exists
 	for i, e := range s.Queue.Entries {
 		if e.Provider == provider && e.Profile == profile && e.Machine == machineID {
 			// Update existing entry
 			s.Queue.Entries[i].Attempts++
-			s.Queue.Entries[i].LastAttempt = time.Now()
+			s.Queue.Entries[i].LastAttempt = now
 			s.Queue.Entries[i].LastError = errorMsg
+			s.Queue.Entries[i].NextAttempt = now.Add(policy.Backoff(s.Queue.Entries[i].Attempts))
 			return
 		}
 	}
@@ -349,13 +404,40 @@ func (s *SyncState) AddToQueue(provider, profile, machineID, errorMsg string) {
 		Provider:    provider,
 		Profile:     profile,
 		Machine:     machineID,
-		AddedAt:     time.Now(),
+		AddedAt:     now,
 		Attempts:    1,
-		LastAttempt: time.Now(),
+		LastAttempt: now,
 		LastError:   errorMsg,
+		NextAttempt: now.Add(policy.Backoff(1)),
 	})
 }
 
+// retryPolicy returns the pool's retry policy, or the default without a pool.
+func (s *SyncState) retryPolicy() RetryPolicy {
+	if s.Pool == nil {
+		return DefaultRetryPolicy()
+	}
+	return s.Pool.Retry
+}
+
+// MarkGaveUp flags an entry as out of attempts, so it is only retried when
+// forced.
+func (s *SyncState) MarkGaveUp(provider, profile, machineID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Queue == nil {
+		return
+	}
+
+	for i, e := range s.Queue.Entries {
+		if e.Provider == provider && e.Profile == profile && e.Machine == machineID {
+			s.Queue.Entries[i].GaveUp = true
+			return
+		}
+	}
+}
+
 // RemoveFromQueue removes an entry from the queue.
 func (s *SyncState) RemoveFromQueue(provider, profile, machineID string) {
 	s.mu.Lock()
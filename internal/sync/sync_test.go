@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -525,9 +526,9 @@ Host proxy-server
 		t.Fatalf("parseSSHConfig failed: %v", err)
 	}
 
-	// Should have work-laptop and home-desktop (not github.com, not *, not proxy-server)
-	if len(machines) != 2 {
-		t.Errorf("Expected 2 machines, got %d", len(machines))
+	// Should have work-laptop, home-desktop and proxy-server (not github.com, not *)
+	if len(machines) != 3 {
+		t.Errorf("Expected 3 machines, got %d", len(machines))
 		for _, m := range machines {
 			t.Logf("  Machine: %s (%s)", m.Name, m.Address)
 		}
@@ -557,6 +558,12 @@ Host proxy-server
 	if workLaptop.Source != SourceSSHConfig {
 		t.Errorf("work-laptop source = %q, want %q", workLaptop.Source, SourceSSHConfig)
 	}
+
+	for _, m := range machines {
+		if m.Name == "proxy-server" && m.ProxyJump != "bastion" {
+			t.Errorf("proxy-server ProxyJump = %q, want %q", m.ProxyJump, "bastion")
+		}
+	}
 }
 
 func TestSSHConfigParsing_MultiHost(t *testing.T) {
@@ -2035,3 +2042,144 @@ func TestDefaultAutoSyncConfig(t *testing.T) {
 		t.Error("Verbose should default to false")
 	}
 }
+
+// TestRetryPolicy tests backoff, exhaustion and validation of the retry policy.
+func TestRetryPolicy(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	policy.Jitter = 0
+
+	if got := policy.Backoff(1); got != time.Minute {
+		t.Errorf("Backoff(1) = %v, want 1m", got)
+	}
+	if got := policy.Backoff(4); got != 8*time.Minute {
+		t.Errorf("Backoff(4) = %v, want 8m", got)
+	}
+	if got := policy.Backoff(50); got != 6*time.Hour {
+		t.Errorf("Backoff(50) = %v, want the 6h cap", got)
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 20; i++ {
+		if got := policy.Backoff(2); got < 2*time.Minute || got > 3*time.Minute {
+			t.Fatalf("Backoff(2) with jitter = %v, want within [2m, 3m]", got)
+		}
+	}
+
+	now := time.Now()
+	entry := QueueEntry{Attempts: 3, NextAttempt: now.Add(time.Minute)}
+	if policy.Due(entry, now) {
+		t.Error("entry should not be due before NextAttempt")
+	}
+	if !policy.Due(entry, now.Add(2*time.Minute)) {
+		t.Error("entry should be due after NextAttempt")
+	}
+	entry.Attempts = policy.MaxAttempts
+	if !policy.Exhausted(entry) || policy.Due(entry, now.Add(time.Hour)) {
+		t.Error("entry out of attempts should be exhausted and never due")
+	}
+	policy.MaxAttempts = 0
+	if policy.Exhausted(entry) {
+		t.Error("MaxAttempts 0 should retry forever")
+	}
+
+	bad := DefaultRetryPolicy()
+	bad.GiveUp = "explode"
+	if bad.Validate() == nil {
+		t.Error("Validate should reject an unknown give_up")
+	}
+	bad = DefaultRetryPolicy()
+	bad.Jitter = 2
+	if bad.Validate() == nil {
+		t.Error("Validate should reject jitter above 1")
+	}
+}
+
+// TestSyncPoolLoadRetryPolicy tests that a partial retry policy keeps the other defaults.
+func TestSyncPoolLoadRetryPolicy(t *testing.T) {
+	tmpDir := t.TempDir()
+	poolFile := filepath.Join(tmpDir, "pool.json")
+	os.WriteFile(poolFile, []byte(`{"machines": {}, "retry": {"max_attempts": 3, "jitter": 0}}`), 0600)
+
+	pool := NewSyncPool()
+	pool.SetBasePath(tmpDir)
+	if err := pool.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if pool.Retry.MaxAttempts != 3 || pool.Retry.Jitter != 0 {
+		t.Errorf("Retry = %+v, want max_attempts 3 and jitter 0", pool.Retry)
+	}
+	if pool.Retry.BackoffBase.Duration() != time.Minute || pool.Retry.GiveUp != GiveUpDrop {
+		t.Errorf("Retry = %+v, unset fields should keep their defaults", pool.Retry)
+	}
+
+	os.WriteFile(poolFile, []byte(`{"retry": {"give_up": "sometimes"}}`), 0600)
+	if err := pool.Load(); err == nil {
+		t.Error("Load should reject an invalid retry policy")
+	}
+}
+
+// TestProcessQueueGivesUp tests that processQueue backs off and gives up per policy.
+func TestProcessQueueGivesUp(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", tmpDir)
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	// Nothing listens on this port, so every attempt fails.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	state := NewSyncState(tmpDir)
+	state.Pool.Enable()
+	state.Pool.Retry.MaxAttempts = 2
+	state.Pool.Retry.Jitter = 0
+	state.Pool.Retry.GiveUp = GiveUpKeep
+	m := NewMachine("gone", "127.0.0.1")
+	m.Port = deadPort
+	state.Pool.AddMachine(m)
+
+	state.AddToQueue("claude", "work", m.ID, "connection refused")
+	entry := state.Queue.Entries[0]
+	if want := entry.LastAttempt.Add(time.Minute); !entry.NextAttempt.Equal(want) {
+		t.Errorf("NextAttempt = %v, want one backoff after the failure (%v)", entry.NextAttempt, want)
+	}
+
+	config := DefaultAutoSyncConfig()
+	config.VaultPath = filepath.Join(tmpDir, "vault")
+	var notified []QueueEntry
+	config.OnGiveUp = func(e QueueEntry) { notified = append(notified, e) }
+
+	if report := processQueue(state, config); report.Waiting != 1 || report.Retried != 0 {
+		t.Errorf("report = %+v, want the entry left waiting for its backoff", report)
+	}
+
+	state.Queue.Entries[0].NextAttempt = time.Now().Add(-time.Second)
+	report := processQueue(state, config)
+	if report.Retried != 1 || len(report.GaveUp) != 1 {
+		t.Fatalf("report = %+v, want one retry that gives up", report)
+	}
+	if len(notified) != 1 || notified[0].Attempts != 2 {
+		t.Errorf("notified = %+v, want one notification after 2 attempts", notified)
+	}
+	if len(state.Queue.Entries) != 1 || !state.Queue.Entries[0].GaveUp {
+		t.Fatalf("queue = %+v, want the entry kept and marked as given up", state.Queue.Entries)
+	}
+
+	state.Queue.Entries[0].NextAttempt = time.Time{}
+	if report := processQueue(state, config); report.Retried != 0 {
+		t.Errorf("report = %+v, a given-up entry should not be retried automatically", report)
+	}
+
+	// With the default policy the entry is dropped instead.
+	state.Queue.Entries[0].GaveUp = false
+	state.Pool.Retry.GiveUp = GiveUpDrop
+	state.Pool.Retry.MaxAttempts = 3
+	report = processQueue(state, config)
+	if len(report.GaveUp) != 1 || len(state.Queue.Entries) != 0 {
+		t.Errorf("report = %+v, queue = %+v; want the entry dropped", report, state.Queue.Entries)
+	}
+}
@@ -0,0 +1,102 @@
+// Package termtitle labels the terminal a tool runs in with the account it
+// uses, so a screen full of panes shows which account each one is burning.
+// Inside tmux it renames the window; elsewhere it sets the terminal title
+// with xterm escape sequences, saving the old title on the terminal's title
+// stack where supported.
+package termtitle
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+const (
+	// pushTitle saves the window title on the xterm title stack.
+	pushTitle = "\x1b[22;0t"
+	// popTitle restores the title pushTitle saved.
+	popTitle = "\x1b[23;0t"
+)
+
+// Label is the title shown for tool/profile, e.g. "claude:work-2".
+func Label(tool, profile string) string {
+	return tool + ":" + profile
+}
+
+// Labeler applies labels. The zero value is not usable; see New.
+type Labeler struct {
+	// Out receives escape sequences outside tmux; nil skips them.
+	Out io.Writer
+	// Pane is $TMUX_PANE when running inside tmux.
+	Pane string
+	// Run runs a command and returns its output; tests replace it.
+	Run func(name string, args ...string) ([]byte, error)
+}
+
+// New returns a Labeler for the current process: tmux when $TMUX is set, and
+// otherwise the terminal behind tty, if tty is one.
+func New(tty *os.File) *Labeler {
+	l := &Labeler{Run: runCommand}
+	if os.Getenv("TMUX") != "" {
+		l.Pane = os.Getenv("TMUX_PANE")
+	}
+	if l.Pane == "" && tty != nil && term.IsTerminal(int(tty.Fd())) {
+		l.Out = tty
+	}
+	return l
+}
+
+func runCommand(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// Set shows title and returns a function that puts the previous label back.
+// Failures are silent: a missing label is not worth failing a command over.
+func (l *Labeler) Set(title string) (restore func()) {
+	title = sanitize(title)
+	switch {
+	case l.Pane != "":
+		return l.setTmux(title)
+	case l.Out != nil:
+		fmt.Fprint(l.Out, pushTitle+"\x1b]0;"+title+"\x07")
+		return func() { fmt.Fprint(l.Out, popTitle) }
+	default:
+		return func() {}
+	}
+}
+
+// setTmux renames the pane's window. rename-window turns automatic-rename
+// off, so restoring turns it back on when it was on, and otherwise puts the
+// old name back.
+func (l *Labeler) setTmux(title string) func() {
+	out, err := l.Run("tmux", "display-message", "-p", "-t", l.Pane, "#{window_name}\t#{automatic-rename}")
+	if err != nil {
+		return func() {}
+	}
+	oldName, autoRename, _ := strings.Cut(strings.TrimRight(string(out), "\n"), "\t")
+	if _, err := l.Run("tmux", "rename-window", "-t", l.Pane, title); err != nil {
+		return func() {}
+	}
+	return func() {
+		if autoRename == "1" {
+			_, _ = l.Run("tmux", "set-window-option", "-t", l.Pane, "automatic-rename", "on")
+			return
+		}
+		_, _ = l.Run("tmux", "rename-window", "-t", l.Pane, oldName)
+	}
+}
+
+// sanitize drops control characters so a profile name can't end the escape
+// sequence early or inject another.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, s)
+}
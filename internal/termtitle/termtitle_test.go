@@ -0,0 +1,91 @@
+package termtitle
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSetTerminalTitleRestoresFromStack(t *testing.T) {
+	var out bytes.Buffer
+	l := &Labeler{Out: &out}
+
+	restore := l.Set(Label("claude", "work-2"))
+	if got, want := out.String(), "\x1b[22;0t\x1b]0;claude:work-2\x07"; got != want {
+		t.Fatalf("Set() wrote %q, want %q", got, want)
+	}
+	out.Reset()
+	restore()
+	if got := out.String(); got != "\x1b[23;0t" {
+		t.Errorf("restore wrote %q, want the title pop sequence", got)
+	}
+}
+
+func TestSetStripsControlCharacters(t *testing.T) {
+	var out bytes.Buffer
+	(&Labeler{Out: &out}).Set("claude:evil\x07\x1b]0;pwned")
+	if strings.Count(out.String(), "\x07") != 1 || strings.Count(out.String(), "\x1b") != 2 {
+		t.Errorf("control characters leaked into %q", out.String())
+	}
+}
+
+type fakeTmux struct {
+	calls  [][]string
+	name   string
+	auto   string
+	failOn string
+}
+
+func (f *fakeTmux) run(name string, args ...string) ([]byte, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	if args[0] == f.failOn {
+		return nil, errors.New("tmux failed")
+	}
+	if args[0] == "display-message" {
+		return []byte(f.name + "\t" + f.auto + "\n"), nil
+	}
+	return nil, nil
+}
+
+func (f *fakeTmux) last() string {
+	return strings.Join(f.calls[len(f.calls)-1], " ")
+}
+
+func TestSetTmuxRenamesAndRestoresName(t *testing.T) {
+	tmux := &fakeTmux{name: "editor", auto: "0"}
+	l := &Labeler{Pane: "%3", Run: tmux.run}
+
+	restore := l.Set("codex:main")
+	if got := tmux.last(); got != "tmux rename-window -t %3 codex:main" {
+		t.Fatalf("Set() ran %q", got)
+	}
+	restore()
+	if got := tmux.last(); got != "tmux rename-window -t %3 editor" {
+		t.Errorf("restore ran %q, want the old name back", got)
+	}
+}
+
+func TestSetTmuxRestoresAutomaticRename(t *testing.T) {
+	tmux := &fakeTmux{name: "zsh", auto: "1"}
+	l := &Labeler{Pane: "%1", Run: tmux.run}
+
+	l.Set("claude:work")()
+	if got := tmux.last(); got != "tmux set-window-option -t %1 automatic-rename on" {
+		t.Errorf("restore ran %q, want automatic-rename back on", got)
+	}
+}
+
+func TestSetTmuxFailureIsSilent(t *testing.T) {
+	tmux := &fakeTmux{failOn: "rename-window"}
+	l := &Labeler{Pane: "%1", Run: tmux.run}
+
+	l.Set("claude:work")()
+	if len(tmux.calls) != 2 {
+		t.Errorf("expected no restore after a failed rename, got %v", tmux.calls)
+	}
+}
+
+func TestSetWithoutTerminalIsNoop(t *testing.T) {
+	(&Labeler{}).Set("claude:work")()
+}
@@ -0,0 +1,45 @@
+package testkit
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultStart is when a Kit's clock starts: a fixed instant, so tests that
+// print or compare times are reproducible.
+var DefaultStart = time.Date(2026, time.January, 15, 9, 0, 0, 0, time.UTC)
+
+// Clock is a controllable clock. Its Now method can be handed to code that
+// takes a time source, such as rotation.Selector.SetClock; time only moves
+// when the test calls Advance or Set.
+type Clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewClock returns a clock stopped at start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+// Now returns the clock's current time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward by d and returns the new time.
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
+
+// Set moves the clock to t, which may be in the past.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
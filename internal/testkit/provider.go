@@ -0,0 +1,285 @@
+package testkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+)
+
+// FakeID is the provider ID a Kit's fake provider uses.
+const FakeID = "fake"
+
+// DefaultTokenLifetime is how long tokens issued by a fake login last.
+const DefaultTokenLifetime = 8 * time.Hour
+
+// FakeAuth is the content of a fake provider's auth file.
+type FakeAuth struct {
+	Account     string    `json:"account"`
+	AccessToken string    `json:"access_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// FakeProvider is a provider.Provider that keeps one JSON auth file,
+// $HOME/.<id>/auth.json, and logs in without a browser or network. Token
+// expiry is measured against its clock.
+type FakeProvider struct {
+	id    string
+	clock *Clock
+
+	mu            sync.Mutex
+	tokenLifetime time.Duration
+	loginErr      error
+	logins        int
+}
+
+// NewFakeProvider returns a fake provider with the given ID. A nil clock
+// means the real time.
+func NewFakeProvider(id string, clock *Clock) *FakeProvider {
+	return &FakeProvider{
+		id:            id,
+		clock:         clock,
+		tokenLifetime: DefaultTokenLifetime,
+	}
+}
+
+func (f *FakeProvider) now() time.Time {
+	if f.clock == nil {
+		return time.Now()
+	}
+	return f.clock.Now()
+}
+
+// SetTokenLifetime sets how long tokens from later logins last.
+func (f *FakeProvider) SetTokenLifetime(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tokenLifetime = d
+}
+
+// FailLogins makes later logins fail with err; nil makes them succeed again.
+func (f *FakeProvider) FailLogins(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.loginErr = err
+}
+
+// Logins returns how many logins succeeded.
+func (f *FakeProvider) Logins() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.logins
+}
+
+// AuthPath returns the auth file's path under home.
+func (f *FakeProvider) AuthPath(home string) string {
+	return filepath.Join(home, "."+f.id, "auth.json")
+}
+
+// FileSet returns the auth files for the real HOME, for vault backup and
+// activation.
+func (f *FakeProvider) FileSet() authfile.AuthFileSet {
+	home, _ := os.UserHomeDir()
+	return authfile.AuthFileSet{
+		Tool: f.id,
+		Files: []authfile.AuthFileSpec{{
+			Tool:        f.id,
+			Path:        f.AuthPath(home),
+			Description: "Fake provider token",
+			Required:    true,
+		}},
+	}
+}
+
+// LoginAs writes a fresh token for account under home, as a completed
+// browser login would.
+func (f *FakeProvider) LoginAs(home, account string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.loginErr != nil {
+		return f.loginErr
+	}
+	f.logins++
+	return writeFakeAuth(f.AuthPath(home), FakeAuth{
+		Account:     account,
+		AccessToken: fmt.Sprintf("fake-%s-%d", account, f.logins),
+		ExpiresAt:   f.now().Add(f.tokenLifetime),
+	})
+}
+
+// ReadAuth returns the auth stored under home, or nil if there is none.
+func (f *FakeProvider) ReadAuth(home string) (*FakeAuth, error) {
+	return readFakeAuth(f.AuthPath(home))
+}
+
+func (f *FakeProvider) ID() string          { return f.id }
+func (f *FakeProvider) DisplayName() string { return "Fake (" + f.id + ")" }
+func (f *FakeProvider) DefaultBin() string  { return f.id }
+
+func (f *FakeProvider) SupportedAuthModes() []provider.AuthMode {
+	return []provider.AuthMode{provider.AuthModeOAuth}
+}
+
+func (f *FakeProvider) AuthFiles() []provider.AuthFileSpec {
+	var specs []provider.AuthFileSpec
+	for _, spec := range f.FileSet().Files {
+		specs = append(specs, provider.AuthFileSpec{Path: spec.Path, Description: spec.Description, Required: spec.Required})
+	}
+	return specs
+}
+
+func (f *FakeProvider) PrepareProfile(ctx context.Context, p *profile.Profile) error {
+	return os.MkdirAll(p.HomePath(), 0700)
+}
+
+func (f *FakeProvider) Env(ctx context.Context, p *profile.Profile) (map[string]string, error) {
+	return map[string]string{"HOME": p.HomePath()}, nil
+}
+
+func (f *FakeProvider) Login(ctx context.Context, p *profile.Profile) error {
+	return f.LoginAs(p.HomePath(), p.Name)
+}
+
+func (f *FakeProvider) Logout(ctx context.Context, p *profile.Profile) error {
+	if err := os.Remove(f.AuthPath(p.HomePath())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *FakeProvider) Status(ctx context.Context, p *profile.Profile) (*provider.ProfileStatus, error) {
+	auth, err := f.ReadAuth(p.HomePath())
+	if err != nil {
+		return &provider.ProfileStatus{Error: err.Error()}, nil
+	}
+	if auth == nil {
+		return &provider.ProfileStatus{}, nil
+	}
+	return &provider.ProfileStatus{
+		LoggedIn:  true,
+		AccountID: auth.Account,
+		ExpiresAt: auth.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (f *FakeProvider) ValidateProfile(ctx context.Context, p *profile.Profile) error {
+	if _, err := os.Stat(p.HomePath()); err != nil {
+		return fmt.Errorf("profile home: %w", err)
+	}
+	return nil
+}
+
+func (f *FakeProvider) DetectExistingAuth() (*provider.AuthDetection, error) {
+	detection := &provider.AuthDetection{Provider: f.id}
+	path := f.FileSet().Files[0].Path
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return detection, nil
+		}
+		return nil, err
+	}
+	loc := provider.AuthLocation{
+		Path:         path,
+		Exists:       true,
+		LastModified: info.ModTime(),
+		FileSize:     info.Size(),
+		IsValid:      true,
+		Description:  "Fake provider token",
+	}
+	if _, err := readFakeAuth(path); err != nil {
+		loc.IsValid = false
+		loc.ValidationError = err.Error()
+	}
+	detection.Found = true
+	detection.Locations = []provider.AuthLocation{loc}
+	detection.Primary = &detection.Locations[0]
+	return detection, nil
+}
+
+func (f *FakeProvider) ImportAuth(ctx context.Context, sourcePath string, targetProfile *profile.Profile) ([]string, error) {
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", sourcePath, err)
+	}
+	dst := f.AuthPath(targetProfile.HomePath())
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(dst, data, 0600); err != nil {
+		return nil, err
+	}
+	return []string{dst}, nil
+}
+
+func (f *FakeProvider) ValidateToken(ctx context.Context, p *profile.Profile, passive bool) (*provider.ValidationResult, error) {
+	result := &provider.ValidationResult{
+		Provider:  f.id,
+		Profile:   p.Name,
+		Method:    "active",
+		CheckedAt: f.now(),
+	}
+	if passive {
+		result.Method = "passive"
+	}
+	auth, err := f.ReadAuth(p.HomePath())
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case auth == nil:
+		result.Error = "not logged in"
+	case !auth.ExpiresAt.After(result.CheckedAt):
+		result.ExpiresAt = auth.ExpiresAt
+		result.Error = "token expired"
+	default:
+		result.ExpiresAt = auth.ExpiresAt
+		result.Valid = true
+	}
+	return result, nil
+}
+
+// TokenExpiry implements provider.ExpiryParser for a vault profile
+// directory.
+func (f *FakeProvider) TokenExpiry(authDir string) (time.Time, error) {
+	auth, err := readFakeAuth(filepath.Join(authDir, "auth.json"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	if auth == nil {
+		return time.Time{}, fmt.Errorf("no auth.json in %s", authDir)
+	}
+	return auth.ExpiresAt, nil
+}
+
+func writeFakeAuth(path string, auth FakeAuth) error {
+	data, err := json.MarshalIndent(auth, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+func readFakeAuth(path string) (*FakeAuth, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var auth FakeAuth
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &auth, nil
+}
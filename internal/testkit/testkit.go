@@ -0,0 +1,199 @@
+// Package testkit builds isolated caam worlds for integration tests: a fake
+// provider, a temp vault, health store and database, and a controllable
+// clock, so end-to-end behavior (activation, rotation, cooldowns, scheduled
+// reverts) can be tested without real credentials or real waiting.
+//
+//	k := testkit.New(t)
+//	k.AddProfile("work")
+//	k.AddProfile("personal")
+//	k.Activate("work")
+//	k.SetCooldown("work", time.Hour)
+//	k.Clock.Advance(61 * time.Minute)
+//
+// New points HOME, CAAM_HOME and the XDG and tool home variables at a temp
+// dir with t.Setenv, so kits cannot be used from parallel tests. The clock
+// drives the fake provider's token expiry, cooldowns and rotation recency;
+// code that reads time.Now directly still sees the real time.
+package testkit
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/health"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/rotation"
+)
+
+var _ provider.ExpiryParser = (*FakeProvider)(nil)
+
+// Kit is one isolated caam world.
+type Kit struct {
+	t testing.TB
+
+	// Root is the temp dir everything lives under; Home is the fake HOME.
+	Root string
+	Home string
+
+	Clock    *Clock
+	Provider *FakeProvider
+	Vault    *authfile.Vault
+	Health   *health.Storage
+	DB       *caamdb.DB
+}
+
+// New returns a kit whose clock starts at DefaultStart. Everything it
+// creates is removed when the test ends.
+func New(t testing.TB) *Kit {
+	t.Helper()
+	root := t.TempDir()
+	home := filepath.Join(root, "home")
+	if err := os.MkdirAll(home, 0700); err != nil {
+		t.Fatalf("testkit: create home: %v", err)
+	}
+	for key, value := range map[string]string{
+		"HOME":            home,
+		"CAAM_HOME":       filepath.Join(root, "caam"),
+		"XDG_CONFIG_HOME": filepath.Join(home, ".config"),
+		"XDG_DATA_HOME":   filepath.Join(home, ".local", "share"),
+		"CODEX_HOME":      filepath.Join(home, ".codex"),
+		"GEMINI_HOME":     filepath.Join(home, ".gemini"),
+	} {
+		t.Setenv(key, value)
+	}
+
+	db, err := caamdb.OpenAt(filepath.Join(root, "caam.db"))
+	if err != nil {
+		t.Fatalf("testkit: open db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	clock := NewClock(DefaultStart)
+	fake := NewFakeProvider(FakeID, clock)
+	authfile.RegisterAuthFileSet(FakeID, fake.FileSet)
+
+	return &Kit{
+		t:        t,
+		Root:     root,
+		Home:     home,
+		Clock:    clock,
+		Provider: fake,
+		Vault:    authfile.NewVault(filepath.Join(root, "vault")),
+		Health:   health.NewStorage(filepath.Join(root, "health.json")),
+		DB:       db,
+	}
+}
+
+// FileSet returns the fake provider's live auth files.
+func (k *Kit) FileSet() authfile.AuthFileSet {
+	return k.Provider.FileSet()
+}
+
+// Tool is the tool name the kit's profiles are stored under.
+func (k *Kit) Tool() string {
+	return k.Provider.ID()
+}
+
+// AddProfile logs in as name and saves the login to the vault, like
+// 'caam add'. The live auth stays logged in as name.
+func (k *Kit) AddProfile(name string) {
+	k.t.Helper()
+	if err := k.Provider.LoginAs(k.Home, name); err != nil {
+		k.t.Fatalf("testkit: login as %s: %v", name, err)
+	}
+	if err := k.Vault.Backup(k.FileSet(), name); err != nil {
+		k.t.Fatalf("testkit: back up %s: %v", name, err)
+	}
+}
+
+// Activate restores name from the vault and records the activation at the
+// clock's time, like 'caam activate'.
+func (k *Kit) Activate(name string) {
+	k.t.Helper()
+	if err := k.Vault.Restore(k.FileSet(), name); err != nil {
+		k.t.Fatalf("testkit: activate %s: %v", name, err)
+	}
+	if err := k.DB.LogEvent(caamdb.Event{
+		Type:        caamdb.EventActivate,
+		Provider:    k.Tool(),
+		ProfileName: name,
+		Timestamp:   k.Clock.Now(),
+	}); err != nil {
+		k.t.Fatalf("testkit: log activation of %s: %v", name, err)
+	}
+}
+
+// Active returns the active profile's name, or "" if the live auth matches
+// no profile.
+func (k *Kit) Active() string {
+	k.t.Helper()
+	name, err := k.Vault.ActiveProfile(k.FileSet())
+	if err != nil {
+		k.t.Fatalf("testkit: detect active profile: %v", err)
+	}
+	return name
+}
+
+// LiveAuth returns the live auth file's content, or nil when logged out.
+func (k *Kit) LiveAuth() *FakeAuth {
+	k.t.Helper()
+	auth, err := k.Provider.ReadAuth(k.Home)
+	if err != nil {
+		k.t.Fatalf("testkit: read live auth: %v", err)
+	}
+	return auth
+}
+
+// Profiles returns the vault's profiles for the fake provider.
+func (k *Kit) Profiles() []string {
+	k.t.Helper()
+	names, err := k.Vault.List(k.Tool())
+	if err != nil {
+		k.t.Fatalf("testkit: list profiles: %v", err)
+	}
+	return names
+}
+
+// SetCooldown puts name in cooldown for d from the clock's time, like
+// 'caam cooldown set'.
+func (k *Kit) SetCooldown(name string, d time.Duration) {
+	k.t.Helper()
+	if _, err := k.DB.SetCooldown(k.Tool(), name, k.Clock.Now(), d, ""); err != nil {
+		k.t.Fatalf("testkit: set cooldown on %s: %v", name, err)
+	}
+}
+
+// InCooldown reports whether name is in cooldown at the clock's time.
+func (k *Kit) InCooldown(name string) bool {
+	k.t.Helper()
+	ev, err := k.DB.ActiveCooldown(k.Tool(), name, k.Clock.Now())
+	if err != nil {
+		k.t.Fatalf("testkit: check cooldown on %s: %v", name, err)
+	}
+	return ev != nil
+}
+
+// Selector returns a rotation selector over the kit's health store and
+// database that runs on the kit's clock, with a fixed random seed.
+func (k *Kit) Selector(algorithm rotation.Algorithm) *rotation.Selector {
+	s := rotation.NewSelector(algorithm, k.Health, k.DB)
+	s.SetClock(k.Clock.Now)
+	s.SetRNG(rand.New(rand.NewSource(1)))
+	return s
+}
+
+// SelectNext picks the profile rotation would switch to from the active
+// one, like 'caam activate --auto'.
+func (k *Kit) SelectNext(algorithm rotation.Algorithm) (string, error) {
+	k.t.Helper()
+	result, err := k.Selector(algorithm).Select(k.Tool(), k.Profiles(), k.Active())
+	if err != nil {
+		return "", err
+	}
+	return result.Selected, nil
+}
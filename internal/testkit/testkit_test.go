@@ -0,0 +1,72 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider/conformance"
+)
+
+func TestFakeProviderConformance(t *testing.T) {
+	conformance.Run(t, func() provider.Provider { return NewFakeProvider(FakeID, NewClock(DefaultStart)) }, conformance.Options{
+		AuthFixture: map[string]string{
+			"$HOME/.fake/auth.json": `{"account": "test", "access_token": "t", "expires_at": "2026-01-15T17:00:00Z"}`,
+		},
+	})
+}
+
+func TestFakeProvider_TokenExpiryFollowsClock(t *testing.T) {
+	clock := NewClock(DefaultStart)
+	fake := NewFakeProvider(FakeID, clock)
+	fake.SetTokenLifetime(time.Hour)
+	prof := &profile.Profile{Name: "work", Provider: FakeID, BasePath: t.TempDir()}
+	ctx := context.Background()
+
+	if err := fake.Login(ctx, prof); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	result, err := fake.ValidateToken(ctx, prof, true)
+	if err != nil || !result.Valid {
+		t.Fatalf("ValidateToken() = %+v, %v; want valid right after login", result, err)
+	}
+	if want := DefaultStart.Add(time.Hour); !result.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", result.ExpiresAt, want)
+	}
+
+	clock.Advance(time.Hour)
+	if result, _ := fake.ValidateToken(ctx, prof, true); result.Valid {
+		t.Error("token should be expired once the clock reaches its expiry")
+	}
+
+	fake.FailLogins(errors.New("browser closed"))
+	if err := fake.Login(ctx, prof); err == nil {
+		t.Error("Login() should fail after FailLogins")
+	}
+	if got := fake.Logins(); got != 1 {
+		t.Errorf("Logins() = %d, want 1", got)
+	}
+}
+
+func TestKit_AddAndActivate(t *testing.T) {
+	k := New(t)
+	k.AddProfile("work")
+	k.AddProfile("personal")
+
+	if got := k.Active(); got != "personal" {
+		t.Fatalf("Active() = %q after adding personal last, want personal", got)
+	}
+	k.Activate("work")
+	if got := k.Active(); got != "work" {
+		t.Fatalf("Active() = %q, want work", got)
+	}
+	if auth := k.LiveAuth(); auth == nil || auth.Account != "work" {
+		t.Fatalf("LiveAuth() = %+v, want work's token", auth)
+	}
+	if last, err := k.DB.LastActivation(k.Tool(), "work"); err != nil || !last.Equal(DefaultStart) {
+		t.Errorf("LastActivation() = %v, %v; want the clock's time", last, err)
+	}
+}
@@ -285,7 +285,7 @@ func (p *DetailPanel) View() string {
 		{"e", "Edit profile"},
 		{"o", "Open in browser"},
 		{"d", "Delete profile"},
-		{"/", "Search profiles"},
+		{"/", "Search all providers"},
 	}
 
 	var actionRows []string
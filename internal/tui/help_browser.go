@@ -0,0 +1,553 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// helpTopicListWidth is the width of the topic column.
+const helpTopicListWidth = 30
+
+// HelpBrowser is the help screen: a list of topics (keyboard shortcuts and
+// task walkthroughs) next to the selected topic, searchable with /.
+type HelpBrowser struct {
+	topics   []HelpTopic
+	matches  []int // indexes into topics shown in the list
+	selected int   // index into matches
+	offset   int   // first content line shown
+
+	searching bool
+	query     string
+
+	width  int
+	height int
+
+	styles HelpBrowserStyles
+}
+
+type HelpBrowserStyles struct {
+	Border   lipgloss.Style
+	Title    lipgloss.Style
+	Topic    lipgloss.Style
+	Selected lipgloss.Style
+	Divider  lipgloss.Style
+	Heading  lipgloss.Style
+	Section  lipgloss.Style
+	Text     lipgloss.Style
+	Bold     lipgloss.Style
+	Code     lipgloss.Style
+	Bullet   lipgloss.Style
+	Match    lipgloss.Style
+	Search   lipgloss.Style
+	Empty    lipgloss.Style
+	Footer   lipgloss.Style
+}
+
+// NewHelpBrowserStyles returns themed styles for the help browser.
+func NewHelpBrowserStyles(theme Theme) HelpBrowserStyles {
+	p := theme.Palette
+
+	return HelpBrowserStyles{
+		Border: lipgloss.NewStyle().
+			Border(theme.Border).
+			BorderForeground(p.BorderMuted).
+			Background(p.Surface).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Accent),
+		Topic: lipgloss.NewStyle().
+			Foreground(p.Text),
+		Selected: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Accent).
+			Background(p.Selection),
+		Divider: lipgloss.NewStyle().
+			Foreground(p.BorderMuted),
+		Heading: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Accent),
+		Section: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.AccentAlt),
+		Text: lipgloss.NewStyle().
+			Foreground(p.Text),
+		Bold: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Text),
+		Code: lipgloss.NewStyle().
+			Foreground(p.Info),
+		Bullet: lipgloss.NewStyle().
+			Foreground(p.Accent),
+		Match: lipgloss.NewStyle().
+			Foreground(p.Text).
+			Background(p.Selection),
+		Search: lipgloss.NewStyle().
+			Foreground(p.Warning),
+		Empty: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Italic(true),
+		Footer: lipgloss.NewStyle().
+			Foreground(p.Muted),
+	}
+}
+
+// NewHelpBrowserWithTheme creates a help browser over the built-in topics.
+func NewHelpBrowserWithTheme(theme Theme) *HelpBrowser {
+	h := &HelpBrowser{
+		topics: helpTopics,
+		styles: NewHelpBrowserStyles(theme),
+	}
+	h.filter()
+	return h
+}
+
+// Reset clears the search and goes back to the first topic.
+func (h *HelpBrowser) Reset() {
+	if h == nil {
+		return
+	}
+	h.searching = false
+	h.query = ""
+	h.filter()
+}
+
+func (h *HelpBrowser) SetSize(width, height int) {
+	if h == nil {
+		return
+	}
+	h.width = width
+	h.height = height
+	h.clampOffset()
+}
+
+// Query returns the current search text.
+func (h *HelpBrowser) Query() string {
+	if h == nil {
+		return ""
+	}
+	return h.query
+}
+
+// SelectedTopic returns the topic being shown, or nil when nothing matches.
+func (h *HelpBrowser) SelectedTopic() *HelpTopic {
+	if h == nil || len(h.matches) == 0 {
+		return nil
+	}
+	return &h.topics[h.matches[h.selected]]
+}
+
+// Update handles a key and reports whether the help screen should close.
+func (h *HelpBrowser) Update(msg tea.KeyMsg) bool {
+	if h == nil {
+		return true
+	}
+
+	if h.searching {
+		switch msg.Type {
+		case tea.KeyEscape:
+			h.Reset()
+		case tea.KeyEnter:
+			h.searching = false
+		case tea.KeyBackspace:
+			if len(h.query) > 0 {
+				runes := []rune(h.query)
+				h.query = string(runes[:len(runes)-1])
+				h.filter()
+			}
+		case tea.KeyRunes, tea.KeySpace:
+			h.query += string(msg.Runes)
+			h.filter()
+		}
+		return false
+	}
+
+	switch msg.String() {
+	case "esc":
+		if h.query != "" {
+			h.Reset()
+			return false
+		}
+		return true
+	case "q", "?":
+		return true
+	case "/":
+		h.searching = true
+	case "up", "k":
+		h.selectTopic(h.selected - 1)
+	case "down", "j", "tab":
+		h.selectTopic(h.selected + 1)
+	case "pgup", "ctrl+u":
+		h.scroll(-h.pageSize())
+	case "pgdown", "ctrl+d", " ":
+		h.scroll(h.pageSize())
+	case "home", "g":
+		h.offset = 0
+	case "end", "G":
+		h.scroll(len(h.contentLines()))
+	}
+	return false
+}
+
+// filter narrows the topic list to topics mentioning the query and shows
+// the first match.
+func (h *HelpBrowser) filter() {
+	h.matches = h.matches[:0]
+	q := strings.ToLower(strings.TrimSpace(h.query))
+	for i, topic := range h.topics {
+		if q == "" || strings.Contains(strings.ToLower(topic.Title), q) || strings.Contains(strings.ToLower(topic.Body), q) {
+			h.matches = append(h.matches, i)
+		}
+	}
+	h.selectTopic(0)
+}
+
+// selectTopic shows matches[i], scrolled to its first line mentioning the
+// query.
+func (h *HelpBrowser) selectTopic(i int) {
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(h.matches) {
+		i = len(h.matches) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	h.selected = i
+	h.offset = 0
+	for n, line := range h.contentLines() {
+		if h.lineMatches(line) {
+			h.offset = n
+			break
+		}
+	}
+	h.clampOffset()
+}
+
+func (h *HelpBrowser) scroll(delta int) {
+	h.offset += delta
+	h.clampOffset()
+}
+
+func (h *HelpBrowser) clampOffset() {
+	maxOffset := len(h.contentLines()) - h.pageSize()
+	if h.offset > maxOffset {
+		h.offset = maxOffset
+	}
+	if h.offset < 0 {
+		h.offset = 0
+	}
+}
+
+// size returns the screen size, defaulting to a standard terminal before
+// the first resize.
+func (h *HelpBrowser) size() (int, int) {
+	width, height := h.width, h.height
+	if width <= 0 {
+		width = 80
+	}
+	if height <= 0 {
+		height = 24
+	}
+	return width, height
+}
+
+// pageSize is how many content lines fit on screen.
+func (h *HelpBrowser) pageSize() int {
+	_, height := h.size()
+	// Border, padding, title, blank lines and footer.
+	if rows := height - 8; rows > 3 {
+		return rows
+	}
+	return 3
+}
+
+func (h *HelpBrowser) contentWidth() int {
+	width, _ := h.size()
+	// Border, padding, topic column and divider.
+	if w := width - 6 - helpTopicListWidth - 3; w > 20 {
+		return w
+	}
+	return 20
+}
+
+func (h *HelpBrowser) contentLines() []helpLine {
+	topic := h.SelectedTopic()
+	if topic == nil {
+		return nil
+	}
+	return renderHelpMarkdown(topic.Body, h.contentWidth(), h.styles)
+}
+
+func (h *HelpBrowser) lineMatches(line helpLine) bool {
+	q := strings.ToLower(strings.TrimSpace(h.query))
+	return q != "" && strings.Contains(strings.ToLower(line.plain), q)
+}
+
+func (h *HelpBrowser) View() string {
+	if h == nil {
+		return ""
+	}
+	width, height := h.size()
+
+	title := h.styles.Title.Render("caam help")
+	switch {
+	case h.searching:
+		title += "   " + h.styles.Search.Render("/"+h.query+"█")
+	case h.query != "":
+		title += "   " + h.styles.Search.Render(fmt.Sprintf("matching %q", h.query))
+	}
+
+	rows := h.pageSize()
+	topics := make([]string, 0, rows)
+	for i, idx := range h.matches {
+		if len(topics) == rows {
+			break
+		}
+		name := ansi.Truncate(h.topics[idx].Title, helpTopicListWidth-3, "…")
+		if i == h.selected {
+			topics = append(topics, h.styles.Selected.Width(helpTopicListWidth).Render("▸ "+name))
+		} else {
+			topics = append(topics, h.styles.Topic.Width(helpTopicListWidth).Render("  "+name))
+		}
+	}
+	if len(h.matches) == 0 {
+		topics = append(topics, h.styles.Empty.Width(helpTopicListWidth).Render("No topics match."))
+	}
+
+	lines := h.contentLines()
+	end := h.offset + rows
+	if end > len(lines) {
+		end = len(lines)
+	}
+	content := make([]string, 0, rows)
+	for _, line := range lines[h.offset:end] {
+		if h.lineMatches(line) {
+			content = append(content, h.styles.Match.Render(line.plain))
+		} else {
+			content = append(content, line.styled)
+		}
+	}
+
+	divider := h.styles.Divider.Render(strings.Repeat(" │ \n", rows-1) + " │ ")
+	list := lipgloss.NewStyle().Width(helpTopicListWidth).Height(rows).Render(strings.Join(topics, "\n"))
+	body := lipgloss.JoinHorizontal(lipgloss.Top, list, divider, strings.Join(content, "\n"))
+
+	more := ""
+	if len(lines) > 0 {
+		more = fmt.Sprintf("lines %d-%d of %d   ", h.offset+1, end, len(lines))
+	}
+	footer := h.styles.Footer.Render(more + "[j/k] topic  [pgup/pgdn] scroll  [/] search  [esc] back")
+
+	inner := lipgloss.JoinVertical(lipgloss.Left, title, "", body, "", footer)
+	return h.styles.Border.Width(width - 2).Height(height - 2).Render(inner)
+}
+
+// helpLine is one rendered line of a help topic; plain is its text without
+// styling, for search.
+type helpLine struct {
+	styled string
+	plain  string
+}
+
+// helpSpanKind is the inline style of a run of text.
+type helpSpanKind int
+
+const (
+	helpSpanText helpSpanKind = iota
+	helpSpanBold
+	helpSpanCode
+)
+
+type helpSpan struct {
+	text string
+	kind helpSpanKind
+}
+
+// renderHelpMarkdown renders a topic body (see HelpTopic) into lines at most
+// width cells wide. Code blocks are not wrapped.
+func renderHelpMarkdown(body string, width int, styles HelpBrowserStyles) []helpLine {
+	var out []helpLine
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1].plain != "" {
+			out = append(out, helpLine{})
+		}
+	}
+
+	// pending collects a paragraph or list item whose source spans lines.
+	var pending []string
+	var first, rest string
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		out = append(out, wrapHelpText(strings.Join(pending, " "), first, rest, width, styles)...)
+		pending = nil
+	}
+
+	inCode := false
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimRight(raw, " ")
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			flush()
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			text := ansi.Truncate("  "+line, width, "…")
+			out = append(out, helpLine{styled: styles.Code.Render(text), plain: text})
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flush()
+			blank()
+		case strings.HasPrefix(trimmed, "# "):
+			flush()
+			blank()
+			text := strings.ToUpper(strings.TrimPrefix(trimmed, "# "))
+			out = append(out, helpLine{styled: styles.Heading.Render(text), plain: text}, helpLine{})
+		case strings.HasPrefix(trimmed, "## "):
+			flush()
+			blank()
+			text := strings.TrimPrefix(trimmed, "## ")
+			out = append(out, helpLine{styled: styles.Section.Render(text), plain: text})
+		case strings.HasPrefix(trimmed, "- "):
+			flush()
+			pending = []string{strings.TrimPrefix(trimmed, "- ")}
+			first, rest = "  • ", "    "
+		case isOrderedItem(trimmed):
+			flush()
+			num, text, _ := strings.Cut(trimmed, ". ")
+			pending = []string{text}
+			first = "  " + num + ". "
+			rest = strings.Repeat(" ", len(first))
+		case len(pending) > 0:
+			pending = append(pending, trimmed)
+		default:
+			pending = []string{trimmed}
+			first, rest = "", ""
+		}
+	}
+	flush()
+
+	for len(out) > 0 && out[len(out)-1].plain == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// isOrderedItem reports whether s starts like "1. ".
+func isOrderedItem(s string) bool {
+	num, _, ok := strings.Cut(s, ". ")
+	if !ok || num == "" || len(num) > 2 {
+		return false
+	}
+	for _, r := range num {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// wrapHelpText word-wraps text with inline markup, starting the first line
+// with first and the others with rest.
+func wrapHelpText(text, first, rest string, width int, styles HelpBrowserStyles) []helpLine {
+	type word struct {
+		text  string
+		kind  helpSpanKind
+		space bool // preceded by a space
+	}
+	var words []word
+	spaceNext := false
+	for _, span := range parseHelpInline(text) {
+		for i, w := range strings.Split(span.text, " ") {
+			if w == "" {
+				spaceNext = spaceNext || i > 0
+				continue
+			}
+			words = append(words, word{text: w, kind: span.kind, space: i > 0 || spaceNext})
+			spaceNext = false
+		}
+	}
+
+	render := func(w word) string {
+		switch w.kind {
+		case helpSpanBold:
+			return styles.Bold.Render(w.text)
+		case helpSpanCode:
+			return styles.Code.Render(w.text)
+		}
+		return styles.Text.Render(w.text)
+	}
+	prefix := func(p string) string {
+		if strings.Contains(p, "•") {
+			return styles.Bullet.Render(p)
+		}
+		return styles.Text.Render(p)
+	}
+
+	var out []helpLine
+	styled, plain := prefix(first), first
+	lineWidth, started := ansi.StringWidth(first), false
+	for _, w := range words {
+		cost := ansi.StringWidth(w.text)
+		if started && w.space {
+			cost++
+		}
+		if started && lineWidth+cost > width {
+			out = append(out, helpLine{styled: styled, plain: plain})
+			styled, plain = prefix(rest), rest
+			lineWidth, started = ansi.StringWidth(rest), false
+			cost = ansi.StringWidth(w.text)
+		}
+		if started && w.space {
+			styled += styles.Text.Render(" ")
+			plain += " "
+		}
+		styled += render(w)
+		plain += w.text
+		lineWidth += cost
+		started = true
+	}
+	return append(out, helpLine{styled: styled, plain: plain})
+}
+
+// parseHelpInline splits text into plain, **bold** and `code` runs.
+func parseHelpInline(text string) []helpSpan {
+	var spans []helpSpan
+	for text != "" {
+		code := strings.Index(text, "`")
+		bold := strings.Index(text, "**")
+		if code < 0 && bold < 0 {
+			spans = append(spans, helpSpan{text: text})
+			break
+		}
+
+		marker, kind, at := "`", helpSpanCode, code
+		if code < 0 || (bold >= 0 && bold < code) {
+			marker, kind, at = "**", helpSpanBold, bold
+		}
+		end := strings.Index(text[at+len(marker):], marker)
+		if end < 0 {
+			spans = append(spans, helpSpan{text: text})
+			break
+		}
+		if at > 0 {
+			spans = append(spans, helpSpan{text: text[:at]})
+		}
+		inner := text[at+len(marker) : at+len(marker)+end]
+		spans = append(spans, helpSpan{text: inner, kind: kind})
+		text = text[at+len(marker)+end+len(marker):]
+	}
+	return spans
+}
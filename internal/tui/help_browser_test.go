@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func runeKeys(s string) []tea.KeyMsg {
+	var keys []tea.KeyMsg
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+func TestRenderHelpMarkdown(t *testing.T) {
+	body := "# Move\n\nRun `caam bundle export` to write a **bundle** of every profile in the vault.\n\n" +
+		"## Steps\n1. Export the vault.\n2. Copy it\n   to the other machine.\n- a bullet\n\n```\ncaam bundle import x.zip\n```"
+	lines := renderHelpMarkdown(body, 30, NewHelpBrowserStyles(DefaultTheme()))
+
+	var plain []string
+	for _, l := range lines {
+		plain = append(plain, l.plain)
+		if w := ansi.StringWidth(l.styled); w > 30 {
+			t.Errorf("line %q is %d cells wide, want <= 30", l.plain, w)
+		}
+	}
+	got := strings.Join(plain, "\n")
+
+	for _, want := range []string{"MOVE", "Run caam bundle export to", "Steps", "  1. Export the vault.", "  2. Copy it to the other", "     machine.", "  • a bullet", "  caam bundle import x.zip"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rendered help missing %q:\n%s", want, got)
+		}
+	}
+	if strings.ContainsAny(got, "`*#") {
+		t.Errorf("markup left in rendered help:\n%s", got)
+	}
+}
+
+func TestParseHelpInline(t *testing.T) {
+	spans := parseHelpInline("run `caam ls` and **look**, then * stays")
+	want := []helpSpan{
+		{text: "run "},
+		{text: "caam ls", kind: helpSpanCode},
+		{text: " and "},
+		{text: "look", kind: helpSpanBold},
+		{text: ", then * stays"},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("spans = %+v, want %+v", spans, want)
+	}
+	for i := range want {
+		if spans[i] != want[i] {
+			t.Errorf("spans[%d] = %+v, want %+v", i, spans[i], want[i])
+		}
+	}
+}
+
+func TestHelpBrowser_Search(t *testing.T) {
+	h := NewHelpBrowserWithTheme(DefaultTheme())
+	h.SetSize(100, 20)
+
+	if h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}}) {
+		t.Fatal("/ should start a search, not close help")
+	}
+	for _, k := range runeKeys("bundle") {
+		h.Update(k)
+	}
+	if len(h.matches) == 0 || len(h.matches) == len(h.topics) {
+		t.Fatalf("searching for bundle matched %d of %d topics", len(h.matches), len(h.topics))
+	}
+	if topic := h.SelectedTopic(); topic == nil || topic.Title != "Keyboard shortcuts" {
+		t.Fatalf("selected topic = %+v, want the first match", topic)
+	}
+
+	// Moving to the next match scrolls to the line mentioning the query.
+	h.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	h.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if topic := h.SelectedTopic(); topic == nil || topic.Title != "Move to another machine" {
+		t.Fatalf("selected topic = %+v, want Move to another machine", topic)
+	}
+	lines := h.contentLines()
+	if h.offset >= len(lines) || !strings.Contains(strings.ToLower(lines[h.offset].plain), "bundle") {
+		t.Errorf("offset %d does not start at a match", h.offset)
+	}
+	if !strings.Contains(h.View(), `matching "bundle"`) {
+		t.Error("view should show the active search")
+	}
+
+	// esc clears the search first, then closes.
+	if h.Update(tea.KeyMsg{Type: tea.KeyEsc}) {
+		t.Fatal("esc with a search active should clear it, not close")
+	}
+	if h.Query() != "" || len(h.matches) != len(h.topics) {
+		t.Fatalf("query = %q with %d matches after esc", h.Query(), len(h.matches))
+	}
+	if !h.Update(tea.KeyMsg{Type: tea.KeyEsc}) {
+		t.Fatal("esc without a search should close help")
+	}
+}
+
+func TestHelpBrowser_NoMatches(t *testing.T) {
+	h := NewHelpBrowserWithTheme(DefaultTheme())
+	h.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	for _, k := range runeKeys("zzzqqq") {
+		h.Update(k)
+	}
+	if h.SelectedTopic() != nil {
+		t.Fatal("no topic should be selected")
+	}
+	if !strings.Contains(h.View(), "No topics match.") {
+		t.Error("view should say nothing matches")
+	}
+}
+
+func TestHelpKeys_TypingStaysInHelp(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 40
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = updated.(Model)
+	for _, k := range append(runeKeys("/"), runeKeys("quit")...) {
+		updated, _ = m.Update(k)
+		m = updated.(Model)
+	}
+	if m.state != stateHelp {
+		t.Fatalf("typing a search should stay in help, state = %v", m.state)
+	}
+	if m.helpBrowser.Query() != "quit" {
+		t.Errorf("query = %q, want quit", m.helpBrowser.Query())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	m = updated.(Model)
+	if m.state != stateList {
+		t.Fatalf("q should leave help, state = %v", m.state)
+	}
+
+	// Opening help again starts fresh.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	m = updated.(Model)
+	if m.helpBrowser.Query() != "" {
+		t.Errorf("query = %q after reopening help, want empty", m.helpBrowser.Query())
+	}
+}
@@ -0,0 +1,180 @@
+package tui
+
+// HelpTopic is one page of the help browser. Body is a small markdown
+// subset: "#"/"##" headings, "-" and "1." lists, fenced code blocks,
+// `code` and **bold**.
+type HelpTopic struct {
+	Title string
+	Body  string
+}
+
+// helpTopics are the pages of the help browser, in the order listed. The
+// walkthroughs cover tasks people otherwise only find in the CLI help.
+var helpTopics = []HelpTopic{
+	{
+		Title: "Keyboard shortcuts",
+		Body: "# Keyboard shortcuts" + `
+
+## Navigation
+` + "```" + `
+↑/k     Move up                    ←/h     Previous provider
+↓/j     Move down                  →       Next provider
+tab     Cycle providers            /       Search all providers
+` + "```" + `
+
+## Profile actions
+` + "```" + `
+enter   Activate selected profile (instant switch!)
+l       Login/refresh OAuth token
+e       Edit profile settings
+o       Open usage dashboard in the account's browser
+d       Delete profile (with confirmation)
+p       Set project association for current directory
+` + "```" + `
+
+## Vault & data
+` + "```" + `
+b       Backup current auth to a new profile
+u       Toggle usage stats panel (1/2/3/4 for time ranges, m for metric)
+t       Toggle event timeline (activations, limits, syncs)
+S       Toggle sync panel
+V       Switch vault (config contexts)
+E       Export vault to encrypted bundle
+I       Import vault from bundle
+` + "```" + `
+
+## General
+` + "```" + `
+?       Toggle this help
+q/esc   Quit
+` + "```" + `
+
+# Health status indicators
+` + "```" + `
+🟢  Healthy   Token valid >1hr, no recent errors
+🟡  Warning   Token expiring soon or minor issues
+🔴  Critical  Token expired or repeated errors
+⚪  Unknown   Health data not available
+` + "```",
+	},
+	{
+		Title: "Add an account",
+		Body: "# Add an account" + `
+
+Each account you use lives in the vault as a **profile**: a saved copy of the
+tool's auth files that caam can swap in instantly.
+
+## From scratch
+1. Quit the TUI and run ` + "`caam add claude work`" + ` (or codex, gemini).
+2. caam backs up whatever login you have now, clears it and starts the
+   tool's own login flow.
+3. Finish the login in your browser; caam saves it as the profile **work**
+   and activates it.
+
+On a machine without a browser, ` + "`caam add codex --device-code`" + ` prints a
+one-time code and a QR code you can scan with your phone.
+
+## From the login you already have
+1. Log in with the tool itself as usual.
+2. Press **b** here and type a profile name, or run
+   ` + "`caam backup claude personal`" + `.
+
+Names may use letters, digits, ` + "`-`, `_`, `.`, `@` and `+`" + `, so an
+email address works as a name.
+
+## From a token
+` + "`caam add-token`" + ` saves an access or refresh token you already have
+without running a login flow.`,
+	},
+	{
+		Title: "Switch accounts",
+		Body: "# Switch accounts" + `
+
+- Select a profile and press **enter** to make it the active login.
+- From a shell: ` + "`caam activate claude work`" + `. Names match
+  case-insensitively and by alias; a close typo asks before switching.
+- ` + "`caam ls`" + ` numbers each profile; ` + "`caam activate claude 2`" + ` or just
+  ` + "`caam 2`" + ` (for your default tool) switches to number 2.
+- ` + "`caam pick`" + ` opens a quick picker.
+
+The login you had before caam is kept as ` + "`_original`" + `; get it back
+with ` + "`caam restore-original claude`" + `.
+
+## Projects
+Press **p** to tie the selected profile to the current directory. Activating
+in that directory (or with ` + "`project.auto_activate`" + `) picks it for you.`,
+	},
+	{
+		Title: "Rotate when you hit a limit",
+		Body: "# Rotate when you hit a limit" + `
+
+When an account runs into its usage limit, caam can move you to the next
+one instead of leaving you waiting.
+
+## Automatically
+Wrap the tool with ` + "`caam run`" + `:
+
+` + "```" + `
+caam run claude -- "explain this code"
+alias claude='caam run claude --precheck --'
+` + "```" + `
+
+When a rate limit shows up, the profile goes into cooldown, the next best
+profile is activated and the command runs again. ` + "`--precheck`" + ` also
+switches before running when usage is already near the limit.
+
+## By hand
+1. ` + "`caam cooldown set claude/work`" + ` marks a profile as limited
+   (default: an hour, or ` + "`--for 3h`" + `).
+2. ` + "`caam activate claude --auto`" + ` picks the best profile not in cooldown.
+3. ` + "`caam next claude`" + ` previews that choice; ` + "`caam cooldown list`" + `
+   shows what is cooling down and until when.
+
+## Choosing the next profile
+Set ` + "`stealth.rotation.algorithm`" + ` in ~/.caam/config.yaml:
+- **smart**: scores health, cooldown, recent use and plan type
+- **round_robin**: goes through profiles in order
+- **random**: picks any profile not in cooldown`,
+	},
+	{
+		Title: "Move to another machine",
+		Body: "# Move to another machine" + `
+
+## The whole vault
+1. Press **E** here, or run ` + "`caam bundle export -e`" + ` for an encrypted
+   bundle (you choose the password).
+2. Copy the bundle to the other machine.
+3. Press **I** there, or run ` + "`caam bundle import <bundle.zip>`" + `.
+
+## A single profile (e.g. a headless server)
+` + "```" + `
+caam export codex/work -o work.tar.gz
+scp work.tar.gz server:
+ssh server caam import work.tar.gz
+ssh server caam activate codex work
+` + "```" + `
+
+## Keep machines in sync
+Press **S** to open the sync panel, add machines with **a**, or run
+` + "`caam sync add <name> <address>`" + ` and ` + "`caam sync enable`" + `. Refreshed
+logins are then pushed to the other machines over SSH.`,
+	},
+	{
+		Title: "Keep logins healthy",
+		Body: "# Keep logins healthy" + `
+
+The health icon next to each profile shows how long its token has left and
+whether it has failed recently.
+
+- **l** refreshes or logs in again for the selected profile;
+  ` + "`caam refresh`" + ` refreshes every token close to expiry.
+- ` + "`caam daemon start`" + ` refreshes tokens in the background before they
+  expire; it picks up changes to ~/.caam/config.yaml as you save them.
+- ` + "`caam ls --expiry`" + ` lists expiry times. They are shown in your local
+  zone next to UTC; set ` + "`display.timezone`" + ` to change the zone.
+- ` + "`caam doctor`" + ` checks the setup and suggests fixes.
+
+Profiles with recent errors rank lower for rotation; the penalty shrinks by
+a fifth every five minutes without new errors.`,
+	},
+}
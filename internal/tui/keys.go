@@ -12,18 +12,20 @@ type keyMap struct {
 	Tab   key.Binding
 
 	// Actions
-	Enter   key.Binding
-	Backup  key.Binding
-	Delete  key.Binding
-	Edit    key.Binding
-	Login   key.Binding
-	Open    key.Binding
-	Search  key.Binding
-	Project key.Binding
-	Usage   key.Binding
-	Sync    key.Binding
-	Export  key.Binding
-	Import  key.Binding
+	Enter    key.Binding
+	Backup   key.Binding
+	Delete   key.Binding
+	Edit     key.Binding
+	Login    key.Binding
+	Open     key.Binding
+	Search   key.Binding
+	Project  key.Binding
+	Usage    key.Binding
+	Timeline key.Binding
+	Sync     key.Binding
+	Vault    key.Binding
+	Export   key.Binding
+	Import   key.Binding
 
 	// Confirmation
 	Confirm key.Binding
@@ -93,10 +95,18 @@ func defaultKeyMap() keyMap {
 			key.WithKeys("u"),
 			key.WithHelp("u", "usage stats"),
 		),
+		Timeline: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "event timeline"),
+		),
 		Sync: key.NewBinding(
 			key.WithKeys("S"),
 			key.WithHelp("S", "sync pool"),
 		),
+		Vault: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "switch vault"),
+		),
 		Export: key.NewBinding(
 			key.WithKeys("E"),
 			key.WithHelp("E", "export vault"),
@@ -135,7 +145,7 @@ func (k keyMap) FullHelp() [][]key.Binding {
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Enter, k.Backup, k.Delete, k.Edit},
 		{k.Login, k.Open, k.Search, k.Project, k.Usage},
-		{k.Sync, k.Export, k.Import},
+		{k.Timeline, k.Sync, k.Vault, k.Export, k.Import},
 		{k.Help, k.Quit},
 	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/signals"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/watcher"
 )
 
@@ -22,9 +23,10 @@ type badgeExpiredMsg struct {
 }
 
 type projectContextLoadedMsg struct {
-	cwd      string
-	resolved *project.Resolved
-	err      error
+	cwd        string
+	resolved   *project.Resolved
+	projectUse map[string]usage.ProjectUse
+	err        error
 }
 
 type usageStatsLoadedMsg struct {
@@ -32,6 +34,11 @@ type usageStatsLoadedMsg struct {
 	err   error
 }
 
+type timelineLoadedMsg struct {
+	entries []TimelineEntry
+	err     error
+}
+
 type signalsReadyMsg struct {
 	handler *signals.Handler
 	err     error
@@ -19,9 +19,11 @@ import (
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/identity"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/provider"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/refresh"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/signals"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/watcher"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,12 +31,9 @@ import (
 	"github.com/charmbracelet/x/ansi"
 )
 
-// providerAccountURLs maps provider names to their account management URLs.
-var providerAccountURLs = map[string]string{
-	"claude": "https://console.anthropic.com/",
-	"codex":  "https://platform.openai.com/",
-	"gemini": "https://aistudio.google.com/",
-}
+// newBrowserLauncher creates the launcher for the 'o' action (swapped out in
+// tests).
+var newBrowserLauncher = browser.NewLauncher
 
 // viewState represents the current view/mode of the TUI.
 type viewState int
@@ -53,6 +52,8 @@ const (
 	stateEditProfile
 	stateSyncAdd
 	stateSyncEdit
+	stateSyncConflict
+	stateVaultPicker
 )
 
 type layoutMode int
@@ -112,6 +113,7 @@ type Profile struct {
 type vaultProfileMeta struct {
 	Description string
 	Account     string
+	LastUsed    time.Time
 }
 
 // Model is the main Bubble Tea model for the caam TUI.
@@ -141,7 +143,9 @@ type Model struct {
 	profilesPanel *ProfilesPanel
 	detailPanel   *DetailPanel
 	usagePanel    *UsagePanel
+	timelinePanel *TimelinePanel
 	syncPanel     *SyncPanel
+	helpBrowser   *HelpBrowser
 
 	// Status message
 	statusMsg string
@@ -155,13 +159,19 @@ type Model struct {
 	signals *signals.Handler
 
 	// Runtime configuration
-	runtime config.RuntimeConfig
+	runtime     config.RuntimeConfig
+	contextName string // active config context, shown in the header
 
 	// Project context
 	cwd            string
 	projectStore   *project.Store
 	projectContext *project.Resolved
 
+	// Projects past alerts.project_share_threshold of their profile's
+	// limit window, by provider
+	projectShareThreshold int
+	projectUse            map[string]usage.ProjectUse
+
 	// Health storage for profile health data
 	healthStorage *health.Storage
 
@@ -181,6 +191,11 @@ type Model struct {
 	pendingSyncMachine  string
 	pendingEditProvider string
 	pendingEditProfile  string
+
+	// Sync conflicts awaiting a choice, shown one at a time
+	syncConflictDialog *SyncConflictDialog
+	vaultPicker        *VaultPickerDialog
+	pendingConflicts   []syncConflict
 }
 
 // DefaultProviders returns the default list of provider names.
@@ -201,7 +216,7 @@ func NewWithProviders(providers []string) Model {
 	if len(providers) > 0 {
 		profilesPanel.SetProvider(providers[0])
 	}
-	defaultRuntime := config.DefaultSPMConfig().Runtime
+	defaults := config.DefaultSPMConfig()
 	return Model{
 		providers:      providers,
 		activeProvider: 0,
@@ -214,16 +229,20 @@ func NewWithProviders(providers []string) Model {
 		profilesPanel:  profilesPanel,
 		detailPanel:    NewDetailPanelWithTheme(theme),
 		usagePanel:     NewUsagePanelWithTheme(theme),
+		timelinePanel:  NewTimelinePanelWithTheme(theme),
 		syncPanel:      NewSyncPanelWithTheme(theme),
+		helpBrowser:    NewHelpBrowserWithTheme(theme),
 		vaultPath:      authfile.DefaultVaultPath(),
 		badges:         make(map[string]profileBadge),
-		runtime:        defaultRuntime,
+		runtime:        defaults.Runtime,
 		cwd:            cwd,
 		profileStore:   profile.NewStore(profile.DefaultStorePath()),
 		profileMeta:    make(map[string]map[string]*profile.Profile),
 		vaultMeta:      make(map[string]map[string]vaultProfileMeta),
 		projectStore:   project.NewStore(""),
 		healthStorage:  health.NewStorage(""),
+
+		projectShareThreshold: defaults.Alerts.ProjectShareThreshold,
 	}
 }
 
@@ -246,7 +265,14 @@ func (m Model) loadProjectContext() tea.Cmd {
 			return projectContextLoadedMsg{}
 		}
 		resolved, err := m.projectStore.Resolve(m.cwd)
-		return projectContextLoadedMsg{cwd: m.cwd, resolved: resolved, err: err}
+		if err != nil {
+			return projectContextLoadedMsg{cwd: m.cwd, err: err}
+		}
+		return projectContextLoadedMsg{
+			cwd:        m.cwd,
+			resolved:   resolved,
+			projectUse: loadProjectUse(resolved, m.projectShareThreshold, time.Now()),
+		}
 	}
 }
 
@@ -322,7 +348,58 @@ func (m Model) loadUsageStats() tea.Cmd {
 		if err != nil {
 			return usageStatsLoadedMsg{err: err}
 		}
-		return usageStatsLoadedMsg{stats: stats}
+
+		dailySince := since
+		if days <= 0 || days > usageSparkMaxDays {
+			dailySince = time.Now().UTC().AddDate(0, 0, -usageSparkMaxDays)
+		}
+		daily, err := db.DailyUsage(dailySince)
+		if err != nil {
+			return usageStatsLoadedMsg{err: err}
+		}
+		// Limit hits are totalled over the whole range, which for "All
+		// time" reaches past the daily chart.
+		hits := daily
+		if !dailySince.Equal(since) {
+			if hits, err = db.DailyUsage(since); err != nil {
+				return usageStatsLoadedMsg{err: err}
+			}
+		}
+		return usageStatsLoadedMsg{stats: mergeDailyUsage(stats, daily, hits)}
+	}
+}
+
+// loadTimeline gathers recent activity_log events, limit hits and sync
+// history for the timeline panel.
+func (m Model) loadTimeline() tea.Cmd {
+	if m.timelinePanel == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		db, err := caamdb.Open()
+		if err != nil {
+			return timelineLoadedMsg{err: err}
+		}
+		defer db.Close()
+
+		events, err := db.ListRecentEvents(timelineLimit)
+		if err != nil {
+			return timelineLoadedMsg{err: err}
+		}
+		cooldowns, err := db.ListRecentCooldowns(timelineLimit)
+		if err != nil {
+			return timelineLoadedMsg{err: err}
+		}
+
+		// Sync history is optional; a missing or unreadable state just
+		// leaves syncs out.
+		var history []sync.HistoryEntry
+		if state, err := sync.LoadSyncState(); err == nil {
+			history = state.RecentHistory(timelineLimit)
+		}
+
+		return timelineLoadedMsg{entries: buildTimeline(events, cooldowns, history)}
 	}
 }
 
@@ -370,6 +447,44 @@ func queryUsageStats(db *caamdb.DB, since time.Time) ([]ProfileUsage, error) {
 	return out, nil
 }
 
+// mergeDailyUsage attaches the per-day breakdown to each profile's totals and
+// sums limit hits over the selected range. Profiles that only hit limits get
+// a row of their own.
+func mergeDailyUsage(stats []ProfileUsage, daily, rangeDaily []caamdb.DailyUsage) []ProfileUsage {
+	index := make(map[string]int, len(stats))
+	for i, s := range stats {
+		index[s.Provider+"/"+s.ProfileName] = i
+	}
+	row := func(provider, profile string) int {
+		key := provider + "/" + profile
+		if i, ok := index[key]; ok {
+			return i
+		}
+		stats = append(stats, ProfileUsage{Provider: provider, ProfileName: profile})
+		index[key] = len(stats) - 1
+		return len(stats) - 1
+	}
+
+	for _, d := range rangeDaily {
+		if d.LimitHits > 0 {
+			stats[row(d.Provider, d.ProfileName)].LimitHits += d.LimitHits
+		}
+	}
+	for _, d := range daily {
+		i, ok := index[d.Provider+"/"+d.ProfileName]
+		if !ok {
+			continue
+		}
+		stats[i].Daily = append(stats[i].Daily, UsageDay{
+			Day:       d.Day,
+			Sessions:  d.Sessions,
+			Hours:     float64(d.ActiveSeconds) / 3600,
+			LimitHits: d.LimitHits,
+		})
+	}
+	return stats
+}
+
 func formatSQLiteSince(t time.Time) string {
 	if t.IsZero() {
 		return "1970-01-01 00:00:00"
@@ -377,9 +492,17 @@ func formatSQLiteSince(t time.Time) string {
 	return t.UTC().Format("2006-01-02 15:04:05")
 }
 
+// openVault returns the vault at m.vaultPath with the configured activation
+// mode, durability and storage, like the CLI's.
+func (m Model) openVault() *authfile.Vault {
+	v := authfile.NewVault(m.vaultPath)
+	authfile.ApplyVaultSettings(v)
+	return v
+}
+
 // loadProfiles loads profiles for all providers.
 func (m Model) loadProfiles() tea.Msg {
-	vault := authfile.NewVault(m.vaultPath)
+	vault := m.openVault()
 	profiles := make(map[string][]Profile)
 	meta := make(map[string]map[string]*profile.Profile)
 	vaultMeta := make(map[string]map[string]vaultProfileMeta)
@@ -389,6 +512,11 @@ func (m Model) loadProfiles() tea.Msg {
 		store = profile.NewStore(profile.DefaultStorePath())
 	}
 
+	db, _ := caamdb.Open()
+	if db != nil {
+		defer db.Close()
+	}
+
 	for _, name := range m.providers {
 		names, err := vault.List(name)
 		if err != nil {
@@ -419,7 +547,7 @@ func (m Model) loadProfiles() tea.Msg {
 					meta[name][prof] = loaded
 				}
 			}
-			vaultMeta[name][prof] = loadVaultProfileMeta(vault, name, prof)
+			vaultMeta[name][prof] = loadVaultProfileMeta(vault, db, name, prof)
 		}
 		profiles[name] = ps
 	}
@@ -461,6 +589,21 @@ type refreshResultMsg struct {
 
 // activateResultMsg is sent when a profile activation completes.
 type activateResultMsg struct {
+	provider string
+	profile  string
+	safety   *authfile.ActivationSafety
+	err      error
+}
+
+// deleteResultMsg is sent when a profile deletion completes.
+type deleteResultMsg struct {
+	provider string
+	profile  string
+	err      error
+}
+
+// backupResultMsg is sent when a backup of the current auth completes.
+type backupResultMsg struct {
 	provider string
 	profile  string
 	err      error
@@ -490,6 +633,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.usagePanel.SetLoading(true)
 			cmds = append(cmds, m.loadUsageStats())
 		}
+		if m.timelinePanel != nil && m.timelinePanel.Visible() {
+			m.timelinePanel.SetLoading(true)
+			cmds = append(cmds, m.loadTimeline())
+		}
 		return m, tea.Batch(cmds...)
 
 	case dumpStatsMsg:
@@ -513,6 +660,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.cwd = msg.cwd
 		}
 		m.projectContext = msg.resolved
+		m.projectUse = msg.projectUse
 		m.syncProfilesPanel()
 		return m, nil
 
@@ -570,6 +718,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case timelineLoadedMsg:
+		if msg.err != nil {
+			m.statusMsg = msg.err.Error()
+			if m.timelinePanel != nil {
+				m.timelinePanel.SetLoading(false)
+			}
+			return m, nil
+		}
+		if m.timelinePanel != nil {
+			m.timelinePanel.SetEntries(msg.entries)
+		}
+		return m, nil
+
 	case syncStateLoadedMsg:
 		if msg.err != nil {
 			m.statusMsg = "Failed to load sync state: " + msg.err.Error()
@@ -650,9 +811,33 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				stats.Skipped,
 				stats.Failed,
 			)
+			if stats.Conflicts > 0 {
+				m.statusMsg += fmt.Sprintf(", %d conflicts", stats.Conflicts)
+			}
+			m.pendingConflicts = append(m.pendingConflicts, msg.conflicts...)
+			if m.syncConflictDialog == nil {
+				m.showNextSyncConflict()
+			}
 		}
 		return m, m.loadSyncState()
 
+	case syncConflictResolvedMsg:
+		profile := msg.item.provider + "/" + msg.item.profile
+		switch {
+		case msg.err != nil:
+			m.statusMsg = "Conflict not resolved (" + profile + "): " + msg.err.Error()
+		case !msg.result.Success:
+			m.statusMsg = "Conflict not resolved (" + profile + "): " + msg.result.Error.Error()
+		case msg.result.Operation.Direction == sync.SyncPush:
+			m.statusMsg = "Kept local copy of " + profile + " on " + msg.item.machineName
+		case msg.result.Operation.Direction == sync.SyncPull:
+			m.statusMsg = "Kept remote copy of " + profile + " from " + msg.item.machineName
+		default:
+			m.statusMsg = profile + " is up to date"
+		}
+		m.showNextSyncConflict()
+		return m, m.loadSyncState()
+
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
 
@@ -717,7 +902,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.showError(msg.err, "Activate")
 			return m, nil
 		}
-		m.showActivateSuccess(msg.provider, msg.profile)
+		m.showActivateSuccess(msg.provider, msg.profile, msg.safety)
 		// Refresh profiles to update active state
 		ctx := refreshContext{
 			provider:        msg.provider,
@@ -725,6 +910,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, m.refreshProfiles(ctx)
 
+	case deleteResultMsg:
+		if msg.err != nil {
+			m.showError(msg.err, fmt.Sprintf("Delete %s", msg.profile))
+			return m, nil
+		}
+		m.showDeleteSuccess(msg.profile)
+		// Refresh profiles with context for intelligent selection restoration
+		ctx := refreshContext{
+			provider:       msg.provider,
+			deletedProfile: msg.profile,
+		}
+		return m, m.refreshProfiles(ctx)
+
+	case backupResultMsg:
+		if msg.err != nil {
+			m.showError(msg.err, "Backup")
+			return m, nil
+		}
+		m.showBackupSuccess(msg.provider, msg.profile)
+		// Refresh profiles to show the new backup
+		ctx := refreshContext{
+			provider:        msg.provider,
+			selectedProfile: msg.profile,
+		}
+		return m, m.refreshProfiles(ctx)
+
 	case refreshResultMsg:
 		if msg.err != nil {
 			m.showError(msg.err, "Refresh")
@@ -793,9 +1004,22 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.usagePanel.SetTimeRange(0)
 			m.usagePanel.SetLoading(true)
 			return m, m.loadUsageStats()
+		case "m":
+			m.usagePanel.NextMetric()
+			return m, nil
 		}
 	}
 
+	// Timeline overlay likewise.
+	if m.timelinePanel != nil && m.timelinePanel.Visible() {
+		return m.handleTimelineKeys(msg)
+	}
+
+	// A pending sync conflict takes keys even over the sync panel.
+	if m.state == stateSyncConflict {
+		return m.handleSyncConflictKeys(msg)
+	}
+
 	// Sync panel overlay gets keys when visible.
 	if m.syncPanel != nil && m.syncPanel.Visible() {
 		return m.handleSyncPanelKeys(msg)
@@ -808,9 +1032,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case stateSearch:
 		return m.handleSearchKeys(msg)
 	case stateHelp:
-		// Any key returns to list
-		m.state = stateList
-		return m, nil
+		return m.handleHelpKeys(msg)
 	case stateBackupDialog:
 		return m.handleBackupDialogKeys(msg)
 	case stateConfirmOverwrite:
@@ -827,6 +1049,8 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleSyncAddKeys(msg)
 	case stateSyncEdit:
 		return m.handleSyncEditKeys(msg)
+	case stateVaultPicker:
+		return m.handleVaultPickerKeys(msg)
 	}
 
 	// Normal list view key handling
@@ -839,6 +1063,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 
 	case key.Matches(msg, m.keys.Help):
+		m.helpBrowser.Reset()
 		m.state = stateHelp
 		return m, nil
 
@@ -936,6 +1161,20 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case key.Matches(msg, m.keys.Vault):
+		return m.openVaultPicker()
+
+	case key.Matches(msg, m.keys.Timeline):
+		if m.timelinePanel == nil {
+			return m, nil
+		}
+		m.timelinePanel.Toggle()
+		if m.timelinePanel.Visible() {
+			m.timelinePanel.SetLoading(true)
+			return m, m.loadTimeline()
+		}
+		return m, nil
+
 	case key.Matches(msg, m.keys.Sync):
 		if m.syncPanel == nil {
 			return m, nil
@@ -971,7 +1210,8 @@ func (m Model) handleConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// handleSearchKeys handles keys in search/filter mode.
+// handleSearchKeys handles keys in search mode, which searches every
+// provider's profiles at once.
 func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.Type {
 	case tea.KeyEscape:
@@ -979,16 +1219,40 @@ func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.state = stateList
 		m.searchQuery = ""
 		m.statusMsg = ""
-		m.syncProfilesPanel() // Restore full list
+		m.endGlobalSearch()
 		return m, nil
 
 	case tea.KeyEnter:
-		// Accept current filter and return to list
+		// Jump to the selected result in its own provider
 		m.state = stateList
-		if m.searchQuery != "" {
-			m.statusMsg = fmt.Sprintf("Filtered by: %s", m.searchQuery)
-		} else {
+		var info *ProfileInfo
+		if m.profilesPanel != nil {
+			info = m.profilesPanel.GetSelectedProfile()
+		}
+		if info == nil {
 			m.statusMsg = ""
+			m.endGlobalSearch()
+			return m, nil
+		}
+		provider, name := info.Provider, info.Name
+		for i, p := range m.providers {
+			if p == provider {
+				m.activeProvider = i
+			}
+		}
+		m.selectedProfileName = name
+		m.endGlobalSearch()
+		m.statusMsg = fmt.Sprintf("Found %s/%s (search: %s)", provider, name, m.searchQuery)
+		return m, nil
+
+	case tea.KeyUp, tea.KeyDown:
+		if m.profilesPanel != nil {
+			if msg.Type == tea.KeyUp {
+				m.profilesPanel.MoveUp()
+			} else {
+				m.profilesPanel.MoveDown()
+			}
+			m.selected = m.profilesPanel.GetSelected()
 		}
 		return m, nil
 
@@ -1009,36 +1273,44 @@ func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// applySearchFilter filters the profiles panel based on the search query.
+// applySearchFilter fills the profiles panel with the profiles of every
+// provider that match the search query.
 func (m *Model) applySearchFilter() {
 	if m.profilesPanel == nil {
 		return
 	}
 
-	provider := m.currentProvider()
-	profiles := m.profiles[provider]
-	projectDefault := m.projectDefaultForProvider(provider)
-
-	// Filter profiles by name (case-insensitive)
+	// Match name, account and notes (case-insensitive)
 	var filtered []ProfileInfo
 	query := strings.ToLower(m.searchQuery)
 
-	for _, p := range profiles {
-		info := m.buildProfileInfo(provider, p, projectDefault)
-		if profileMatchesQuery(info, query) {
-			filtered = append(filtered, info)
+	for _, provider := range m.providers {
+		projectDefault := m.projectDefaultForProvider(provider)
+		for _, p := range m.profiles[provider] {
+			info := m.buildProfileInfo(provider, p, projectDefault)
+			info.Provider = provider
+			if profileMatchesQuery(info, query) {
+				filtered = append(filtered, info)
+			}
 		}
 	}
 
+	m.profilesPanel.SetGlobal(true)
 	m.profilesPanel.SetProfiles(filtered)
 	m.selected = 0
 	m.profilesPanel.SetSelected(0)
-	if info := m.profilesPanel.GetSelectedProfile(); info != nil {
-		m.selectedProfileName = info.Name
-	} else {
-		m.selectedProfileName = ""
+	m.statusMsg = fmt.Sprintf("/%s (%d matches across providers)", m.searchQuery, len(filtered))
+}
+
+// endGlobalSearch returns the profiles panel to the active provider.
+func (m *Model) endGlobalSearch() {
+	if m.profilesPanel != nil {
+		m.profilesPanel.SetGlobal(false)
+	}
+	if m.providerPanel != nil {
+		m.syncProviderPanel()
 	}
-	m.statusMsg = fmt.Sprintf("/%s (%d matches)", m.searchQuery, len(filtered))
+	m.syncProfilesPanel()
 }
 
 // handleActivateProfile initiates profile activation with confirmation.
@@ -1100,7 +1372,7 @@ func (m Model) handleBackupProfile() (tea.Model, tea.Cmd) {
 	// Create text input dialog for profile name
 	m.backupDialog = NewTextInputDialog(
 		fmt.Sprintf("Backup %s Auth", provider),
-		"Enter profile name (alphanumeric, underscore, hyphen, or period):",
+		"Enter profile name (letters, numbers, underscore, hyphen, period, @, or +):",
 	)
 	m.backupDialog.SetStyles(m.styles)
 	m.backupDialog.SetPlaceholder("work-main")
@@ -1156,20 +1428,21 @@ func (m Model) processBackupSubmit(profileName string) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Only allow alphanumeric, underscore, hyphen, and period
-	// This matches the vault validation in authfile.go and profile.go
-	// to prevent shell injection and filesystem issues
-	for _, r := range profileName {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
-			(r >= '0' && r <= '9') || r == '_' || r == '-' || r == '.') {
-			m.statusMsg = "Profile name can only contain letters, numbers, underscore, hyphen, and period"
-			m.backupDialog.Reset()
-			return m, nil
+	// Use the vault's own validation, offering the sanitized name in the
+	// dialog so Enter accepts it.
+	if err := authfile.ValidateProfileName(profileName); err != nil {
+		m.backupDialog.Reset()
+		if suggestion := authfile.SanitizeProfileName(profileName); suggestion != "" {
+			m.backupDialog.SetValue(suggestion)
+			m.statusMsg = fmt.Sprintf("Invalid profile name - did you mean %s? Press Enter to accept", suggestion)
+		} else {
+			m.statusMsg = "Profile name can only contain letters, numbers, underscore, hyphen, period, @, and +"
 		}
+		return m, nil
 	}
 
 	// Check if profile already exists
-	vault := authfile.NewVault(m.vaultPath)
+	vault := m.openVault()
 	profiles, err := vault.List(provider)
 	if err != nil {
 		m.statusMsg = fmt.Sprintf("Error listing profiles: %v", err)
@@ -1244,6 +1517,38 @@ func (m Model) handleConfirmOverwriteKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 // handleSyncPanelKeys handles keys when the sync panel is visible.
+// handleTimelineKeys handles keys while the timeline panel is open.
+func (m Model) handleTimelineKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "t":
+		m.timelinePanel.Toggle()
+	case "up", "k":
+		m.timelinePanel.Scroll(-1)
+	case "down", "j":
+		m.timelinePanel.Scroll(1)
+	case "pgup":
+		m.timelinePanel.Scroll(-m.timelinePanel.PageSize())
+	case "pgdown":
+		m.timelinePanel.Scroll(m.timelinePanel.PageSize())
+	case "r":
+		m.timelinePanel.SetLoading(true)
+		return m, m.loadTimeline()
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) handleHelpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+	if m.helpBrowser.Update(msg) {
+		m.state = stateList
+	}
+	return m, nil
+}
+
 func (m Model) handleSyncPanelKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.syncPanel == nil {
 		return m, nil
@@ -1321,18 +1626,33 @@ func (m Model) executeBackup(profileName string) (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	vault := authfile.NewVault(m.vaultPath)
-	if err := vault.Backup(fileSet, profileName); err != nil {
-		m.state = stateList
-		m.statusMsg = fmt.Sprintf("Backup failed: %v", err)
-		return m, nil
-	}
-
 	m.state = stateList
-	m.statusMsg = fmt.Sprintf("Backed up %s auth to '%s'", provider, profileName)
+	m.statusMsg = fmt.Sprintf("Backing up %s auth to '%s'...", provider, profileName)
+	return m, m.doBackupProfile(fileSet, profileName)
+}
 
-	// Reload profiles to show the new backup
-	return m, m.loadProfiles
+// doBackupProfile returns a tea.Cmd that saves the current auth files as profile.
+func (m Model) doBackupProfile(fileSet authfile.AuthFileSet, profile string) tea.Cmd {
+	return func() tea.Msg {
+		vault := m.openVault()
+		return backupResultMsg{
+			provider: fileSet.Tool,
+			profile:  profile,
+			err:      vault.Backup(fileSet, profile),
+		}
+	}
+}
+
+// doDeleteProfile returns a tea.Cmd that removes profile from the vault.
+func (m Model) doDeleteProfile(provider, profile string) tea.Cmd {
+	return func() tea.Msg {
+		vault := m.openVault()
+		return deleteResultMsg{
+			provider: provider,
+			profile:  profile,
+			err:      vault.Delete(provider, profile),
+		}
+	}
 }
 
 // handleLoginProfile initiates login/refresh for the selected profile.
@@ -1353,7 +1673,7 @@ func (m Model) handleLoginProfile() (tea.Model, tea.Cmd) {
 // doRefreshProfile returns a tea.Cmd that performs the token refresh.
 func (m Model) doRefreshProfile(provider, profile string) tea.Cmd {
 	return func() tea.Msg {
-		vault := authfile.NewVault(m.vaultPath)
+		vault := m.openVault()
 
 		// Get health storage for updating health data after refresh
 		store := health.NewStorage("")
@@ -1382,40 +1702,58 @@ func (m Model) doActivateProfile(provider, profile string) tea.Cmd {
 			}
 		}
 
-		vault := authfile.NewVault(m.vaultPath)
-		if err := vault.Restore(fileSet, profile); err != nil {
-			return activateResultMsg{
-				provider: provider,
-				profile:  profile,
-				err:      err,
-			}
+		// Same safety steps as 'caam activate': _original on first use and
+		// auto-backups per the safety config.
+		opts := authfile.ActivateOptions{}
+		if spmCfg, err := config.LoadSPMConfig(); err == nil {
+			opts.BackupMode = spmCfg.Safety.AutoBackupBeforeSwitch
+			opts.MaxAutoBackups = spmCfg.Safety.MaxAutoBackups
 		}
 
+		vault := m.openVault()
+		safety, err := vault.Activate(fileSet, profile, opts)
 		return activateResultMsg{
 			provider: provider,
 			profile:  profile,
-			err:      nil,
+			safety:   safety,
+			err:      err,
 		}
 	}
 }
 
-// handleOpenInBrowser opens the account page in browser.
+// handleOpenInBrowser opens the provider's usage dashboard in the browser
+// profile configured for the selected account.
 func (m Model) handleOpenInBrowser() (tea.Model, tea.Cmd) {
-	provider := m.currentProvider()
-	url, ok := providerAccountURLs[provider]
-	if !ok {
-		m.statusMsg = fmt.Sprintf("No account URL for %s", provider)
+	name := m.currentProvider()
+	meta, ok := provider.GetProviderMeta(name)
+	if !ok || meta.UsageURL == "" {
+		m.statusMsg = fmt.Sprintf("No usage page for %s", name)
 		return m, nil
 	}
 
-	launcher := &browser.DefaultLauncher{}
-	if err := launcher.Open(url); err != nil {
+	var cfg *browser.Config
+	account := ""
+	if info := m.selectedProfileInfo(); info != nil {
+		account = info.Name
+		if prof := m.profileMetaFor(name, info.Name); prof != nil && prof.HasBrowserConfig() {
+			cfg = &browser.Config{
+				Command:    prof.BrowserCommand,
+				ProfileDir: prof.BrowserProfileDir,
+			}
+		}
+	}
+
+	if err := newBrowserLauncher(cfg).Open(meta.UsageURL); err != nil {
 		// If browser launch fails, show the URL so user can copy it
-		m.statusMsg = fmt.Sprintf("Open in browser: %s", url)
+		m.statusMsg = fmt.Sprintf("Open in browser: %s", meta.UsageURL)
 		return m, nil
 	}
 
-	m.statusMsg = fmt.Sprintf("Opened %s account page in browser", strings.ToUpper(provider[:1])+provider[1:])
+	if account != "" && cfg != nil {
+		m.statusMsg = fmt.Sprintf("Opened %s for %s in its browser profile", meta.UsageDescription, account)
+	} else {
+		m.statusMsg = fmt.Sprintf("Opened %s in browser", meta.UsageDescription)
+	}
 	return m, nil
 }
 
@@ -1595,6 +1933,53 @@ func (m Model) handleSyncAddKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// showNextSyncConflict opens the dialog for the next pending conflict, or
+// returns to the list when none are left.
+func (m *Model) showNextSyncConflict() {
+	if len(m.pendingConflicts) == 0 {
+		m.syncConflictDialog = nil
+		if m.state == stateSyncConflict {
+			m.state = stateList
+		}
+		return
+	}
+	m.syncConflictDialog = newSyncConflictDialog(m.pendingConflicts[0])
+	m.pendingConflicts = m.pendingConflicts[1:]
+	m.syncConflictDialog.SetStyles(m.styles)
+	m.syncConflictDialog.SetWidth(m.dialogWidth(m.syncConflictDialog.width))
+	m.state = stateSyncConflict
+}
+
+func (m Model) handleSyncConflictKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.syncConflictDialog == nil {
+		m.state = stateList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.syncConflictDialog, cmd = m.syncConflictDialog.Update(msg)
+
+	switch m.syncConflictDialog.Result() {
+	case DialogResultSubmit:
+		item := m.syncConflictDialog.item
+		choice := m.syncConflictDialog.Choice()
+		// The next conflict opens once this one is resolved, so two syncs
+		// never run at once.
+		m.syncConflictDialog = nil
+		m.state = stateList
+		m.statusMsg = "Resolving " + item.provider + "/" + item.profile + "..."
+		return m, m.resolveSyncConflict(item, choice)
+
+	case DialogResultCancel:
+		item := m.syncConflictDialog.item
+		m.statusMsg = "Left " + item.provider + "/" + item.profile + " unresolved"
+		m.showNextSyncConflict()
+		return m, nil
+	}
+
+	return m, cmd
+}
+
 func (m Model) handleSyncEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.syncEditDialog == nil {
 		m.state = stateList
@@ -1637,7 +2022,8 @@ func (m Model) handleSyncEditKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m Model) handleEnterSearchMode() (tea.Model, tea.Cmd) {
 	m.state = stateSearch
 	m.searchQuery = ""
-	m.statusMsg = "Type to filter profiles (Esc to cancel)"
+	m.applySearchFilter()
+	m.statusMsg = "Type to search all providers (↑/↓ select, Enter jumps, Esc cancels)"
 	return m, nil
 }
 
@@ -1701,25 +2087,11 @@ func (m Model) executeConfirmedAction() (tea.Model, tea.Cmd) {
 		if info != nil {
 			provider := m.currentProvider()
 
-			// Perform the deletion via vault
-			vault := authfile.NewVault(m.vaultPath)
-			if err := vault.Delete(provider, info.Name); err != nil {
-				m.showError(err, fmt.Sprintf("Delete %s", info.Name))
-				m.state = stateList
-				m.pendingAction = confirmNone
-				return m, nil
-			}
-
-			m.showDeleteSuccess(info.Name)
+			m.statusMsg = fmt.Sprintf("Deleting %s...", info.Name)
 			m.state = stateList
 			m.pendingAction = confirmNone
 
-			// Refresh profiles with context for intelligent selection restoration
-			ctx := refreshContext{
-				provider:       provider,
-				deletedProfile: info.Name,
-			}
-			return m, m.refreshProfiles(ctx)
+			return m, m.doDeleteProfile(provider, info.Name)
 		}
 	}
 	m.state = stateList
@@ -1795,7 +2167,9 @@ func (m Model) vaultMetaFor(provider, name string) vaultProfileMeta {
 	return byProvider[name]
 }
 
-func loadVaultProfileMeta(vault *authfile.Vault, provider, name string) vaultProfileMeta {
+// loadVaultProfileMeta reads a vault profile's description, account and,
+// when db is non-nil, when it was last activated or run.
+func loadVaultProfileMeta(vault *authfile.Vault, db *caamdb.DB, provider, name string) vaultProfileMeta {
 	meta := vaultProfileMeta{}
 	if vault == nil || provider == "" || name == "" {
 		return meta
@@ -1812,7 +2186,14 @@ func loadVaultProfileMeta(vault *authfile.Vault, provider, name string) vaultPro
 		}
 	}
 
-	meta.Account = vaultIdentityEmail(provider, profileDir)
+	// The credentials may be sealed or in the keychain; parse a plain copy.
+	if plainDir, cleanup, err := vault.PlainProfileDir(provider, name); err == nil {
+		meta.Account = vaultIdentityEmail(provider, plainDir)
+		cleanup()
+	}
+	if db != nil {
+		meta.LastUsed, _ = db.LastUsed(provider, name)
+	}
 	return meta
 }
 
@@ -1889,6 +2270,9 @@ func (m Model) buildProfileInfo(provider string, p Profile, projectDefault strin
 	if description == "" {
 		description = vmeta.Description
 	}
+	if vmeta.LastUsed.After(lastUsed) {
+		lastUsed = vmeta.LastUsed
+	}
 
 	healthStatus := health.StatusUnknown
 	errorCount := 0
@@ -2052,9 +2436,12 @@ func (m Model) syncDetailPanel() {
 	if description == "" {
 		description = vmeta.Description
 	}
+	if vmeta.LastUsed.After(lastUsedAt) {
+		lastUsedAt = vmeta.LastUsed
+	}
 
 	if path == "" {
-		vault := authfile.NewVault(m.vaultPath)
+		vault := m.openVault()
 		path = vault.ProfilePath(provider, profileName)
 	}
 
@@ -2122,11 +2509,25 @@ func (m Model) View() string {
 			return m.dialogOverlayView(m.syncEditDialog.View())
 		}
 		return m.mainView()
+	case stateSyncConflict:
+		if m.syncConflictDialog != nil {
+			return m.dialogOverlayView(m.syncConflictDialog.View())
+		}
+		return m.mainView()
+	case stateVaultPicker:
+		if m.vaultPicker != nil {
+			return m.dialogOverlayView(m.vaultPicker.View())
+		}
+		return m.mainView()
 	default:
 		if m.usagePanel != nil && m.usagePanel.Visible() {
 			m.usagePanel.SetSize(m.width, m.height)
 			return m.usagePanel.View()
 		}
+		if m.timelinePanel != nil && m.timelinePanel.Visible() {
+			m.timelinePanel.SetSize(m.width, m.height)
+			return m.timelinePanel.View()
+		}
 		if m.syncPanel != nil && m.syncPanel.Visible() {
 			m.syncPanel.SetSize(m.width, m.height)
 			return m.syncPanel.View()
@@ -2257,6 +2658,11 @@ func trimLeftANSI(s string, left int) string {
 func (m Model) mainView() string {
 	// Header
 	headerLines := []string{m.styles.Header.Render("caam - Coding Agent Account Manager")}
+	vaultLine := "Vault: " + displayVaultPath(m.vaultPath)
+	if m.contextName != "" {
+		vaultLine = "Context: " + m.contextName + "  " + vaultLine
+	}
+	headerLines = append(headerLines, m.styles.StatusText.Render(vaultLine))
 	if projectLine := m.projectContextLine(); projectLine != "" {
 		headerLines = append(headerLines, m.styles.StatusText.Render(projectLine))
 	}
@@ -2532,6 +2938,9 @@ func (m *Model) clampDialogWidths() {
 	if m.editDialog != nil {
 		m.editDialog.SetWidth(m.dialogWidth(m.editDialog.width))
 	}
+	if m.syncConflictDialog != nil {
+		m.syncConflictDialog.SetWidth(m.dialogWidth(m.syncConflictDialog.width))
+	}
 	if m.syncAddDialog != nil {
 		m.syncAddDialog.SetWidth(m.dialogWidth(m.syncAddDialog.width))
 	}
@@ -2560,7 +2969,11 @@ func (m Model) projectContextLine() string {
 		return fmt.Sprintf("Project: %s (no association)", m.cwd)
 	}
 
-	return fmt.Sprintf("Project: %s → %s", source, profile)
+	line := fmt.Sprintf("Project: %s → %s", source, profile)
+	if use, ok := m.projectUse[provider]; ok && use.ProfileName == profile {
+		line += fmt.Sprintf("  ⚠ used %d%% of its %s window", int(use.Share*100), use.Window)
+	}
+	return line
 }
 
 func (m Model) projectDefaultForProvider(provider string) string {
@@ -2675,61 +3088,8 @@ func (m Model) renderStatusBar(layout layoutSpec) string {
 
 // helpView renders the help screen.
 func (m Model) helpView() string {
-	help := `
-caam - Coding Agent Account Manager
-====================================
-
-KEYBOARD SHORTCUTS
-
-Navigation
-  ↑/k     Move up                    ←/h     Previous provider
-  ↓/j     Move down                  →       Next provider
-  tab     Cycle providers            /       Search/filter profiles
-
-Profile Actions
-  enter   Activate selected profile (instant switch!)
-  l       Login/refresh OAuth token
-  e       Edit profile settings
-  o       Open account page in browser
-  d       Delete profile (with confirmation)
-  p       Set project association for current directory
-
-Vault & Data
-  b       Backup current auth to a new profile
-  u       Toggle usage stats panel (1/2/3/4 for time ranges)
-  S       Toggle sync panel
-  E       Export vault to encrypted bundle
-  I       Import vault from bundle
-
-General
-  ?       Toggle this help
-  q/esc   Quit
-
-HEALTH STATUS INDICATORS
-  🟢  Healthy   Token valid >1hr, no recent errors
-  🟡  Warning   Token expiring soon or minor issues
-  🔴  Critical  Token expired or repeated errors
-  ⚪  Unknown   Health data not available
-
-SMART PROFILE FEATURES (via CLI)
-  caam activate <tool> --auto     Smart rotation picks best profile
-  caam run <tool> -- <args>       Wrap CLI with auto-failover on rate limits
-  caam cooldown set <profile>     Mark profile as rate-limited
-  caam cooldown list              View active cooldowns
-  caam next <tool>                Preview which profile rotation would pick
-
-ROTATION ALGORITHMS (config.yaml → stealth.rotation.algorithm)
-  smart       Multi-factor scoring: health, cooldown, recency, plan type
-  round_robin Sequential cycling through profiles
-  random      Random selection
-
-PROJECT ASSOCIATIONS
-  Profiles can be linked to directories. When you activate in a project
-  directory, caam uses the associated profile automatically.
-
-Press any key to return...
-`
-	return m.styles.Help.Render(help)
+	m.helpBrowser.SetSize(m.width, m.height)
+	return m.helpBrowser.View()
 }
 
 func (m Model) dumpStatsLine() string {
@@ -2761,8 +3121,9 @@ func (m Model) dumpStatsLine() string {
 	)
 }
 
-// Run starts the TUI application.
-func Run() error {
+// Run starts the TUI application. A non-empty contextName is shown in the
+// header.
+func Run(contextName string) error {
 	spmCfg, err := config.LoadSPMConfig()
 	if err != nil {
 		// Keep the TUI usable even with a broken config file.
@@ -2776,6 +3137,8 @@ func Run() error {
 
 	m := New()
 	m.runtime = spmCfg.Runtime
+	m.projectShareThreshold = spmCfg.Alerts.ProjectShareThreshold
+	m.contextName = contextName
 
 	pidPath := signals.DefaultPIDFilePath()
 	pidWritten := false
@@ -2867,7 +3230,7 @@ type profilesRefreshedMsg struct {
 // while preserving selection context for intelligent index restoration.
 func (m Model) refreshProfiles(ctx refreshContext) tea.Cmd {
 	return func() tea.Msg {
-		vault := authfile.NewVault(m.vaultPath)
+		vault := m.openVault()
 		profiles := make(map[string][]Profile)
 		meta := make(map[string]map[string]*profile.Profile)
 		vaultMeta := make(map[string]map[string]vaultProfileMeta)
@@ -2877,6 +3240,11 @@ func (m Model) refreshProfiles(ctx refreshContext) tea.Cmd {
 			store = profile.NewStore(profile.DefaultStorePath())
 		}
 
+		db, _ := caamdb.Open()
+		if db != nil {
+			defer db.Close()
+		}
+
 		for _, name := range m.providers {
 			names, err := vault.List(name)
 			if err != nil {
@@ -2910,7 +3278,7 @@ func (m Model) refreshProfiles(ctx refreshContext) tea.Cmd {
 						meta[name][prof] = loaded
 					}
 				}
-				vaultMeta[name][prof] = loadVaultProfileMeta(vault, name, prof)
+				vaultMeta[name][prof] = loadVaultProfileMeta(vault, db, name, prof)
 			}
 			profiles[name] = ps
 		}
@@ -3035,8 +3403,15 @@ func (m *Model) showSuccess(format string, args ...interface{}) {
 }
 
 // showActivateSuccess shows a success message for profile activation.
-func (m *Model) showActivateSuccess(provider, profile string) {
-	m.showSuccess("Activated %s for %s", profile, provider)
+func (m *Model) showActivateSuccess(provider, profile string, safety *authfile.ActivationSafety) {
+	switch {
+	case safety != nil && safety.OriginalBackup != "":
+		m.showSuccess("Activated %s for %s (original login saved as %s)", profile, provider, safety.OriginalBackup)
+	case safety != nil && safety.AutoBackup != "":
+		m.showSuccess("Activated %s for %s (previous login saved as %s)", profile, provider, safety.AutoBackup)
+	default:
+		m.showSuccess("Activated %s for %s", profile, provider)
+	}
 }
 
 // showDeleteSuccess shows a success message for profile deletion.
@@ -3044,6 +3419,11 @@ func (m *Model) showDeleteSuccess(profile string) {
 	m.showSuccess("Deleted %s", profile)
 }
 
+// showBackupSuccess shows a success message for a backup of the current auth.
+func (m *Model) showBackupSuccess(provider, profile string) {
+	m.showSuccess("Backed up %s auth to %s", provider, profile)
+}
+
 // showRefreshSuccess shows a success message for token refresh.
 func (m *Model) showRefreshSuccess(profile string, expiresAt time.Time) {
 	if expiresAt.IsZero() {
@@ -3083,7 +3463,7 @@ func (m Model) formatError(err error) string {
 // sets the selection to the specified index after refresh.
 func (m Model) refreshProfilesWithIndex(provider string, index int) tea.Cmd {
 	return func() tea.Msg {
-		vault := authfile.NewVault(m.vaultPath)
+		vault := m.openVault()
 		profiles := make(map[string][]Profile)
 
 		for _, name := range m.providers {
@@ -2,11 +2,18 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/browser"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/profile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
 	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/watcher"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -283,6 +290,113 @@ func TestDeleteProfileConfirm(t *testing.T) {
 	}
 }
 
+func TestBackupAndDelete_RunInBackground(t *testing.T) {
+	tmpDir := t.TempDir()
+	codexHome := filepath.Join(tmpDir, "codex")
+	t.Setenv("CODEX_HOME", codexHome)
+	if err := os.MkdirAll(codexHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(codexHome, "auth.json"), []byte(`{"access_token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New()
+	m.vaultPath = filepath.Join(tmpDir, "vault")
+	for i, p := range m.providers {
+		if p == "codex" {
+			m.activeProvider = i
+		}
+	}
+	vault := authfile.NewVault(m.vaultPath)
+
+	result, cmd := m.executeBackup("work")
+	m = result.(Model)
+	if cmd == nil || !strings.Contains(m.statusMsg, "Backing up") {
+		t.Fatalf("executeBackup() should run in the background, status %q", m.statusMsg)
+	}
+	msg := cmd()
+	if _, err := os.Stat(vault.BackupPath("codex", "work", "auth.json")); err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	result, cmd = m.Update(msg)
+	m = result.(Model)
+	if !strings.Contains(m.statusMsg, "Backed up codex auth to work") || cmd == nil {
+		t.Errorf("after backup: status %q, refresh cmd %v", m.statusMsg, cmd != nil)
+	}
+
+	msg = m.doDeleteProfile("codex", "work")()
+	if profiles, _ := vault.List("codex"); len(profiles) != 0 {
+		t.Fatalf("profile still in vault: %v", profiles)
+	}
+	result, cmd = m.Update(msg)
+	m = result.(Model)
+	if !strings.Contains(m.statusMsg, "Deleted work") || cmd == nil {
+		t.Errorf("after delete: status %q, refresh cmd %v", m.statusMsg, cmd != nil)
+	}
+
+	result, _ = m.Update(deleteResultMsg{provider: "codex", profile: "gone", err: fmt.Errorf("boom")})
+	m = result.(Model)
+	if !strings.Contains(m.statusMsg, "Delete gone") {
+		t.Errorf("failed delete should name the profile, got %q", m.statusMsg)
+	}
+}
+
+func TestLoadVaultProfileMeta_EncryptedVault(t *testing.T) {
+	vault := authfile.NewVault(t.TempDir())
+	dir := vault.ProfilePath("claude", "work")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	creds := `{"claudeAiOauth": {"accessToken": "tok", "email": "alice@example.com"}}`
+	if err := os.WriteFile(filepath.Join(dir, ".credentials.json"), []byte(creds), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vault.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := vault.EncryptAll(); err != nil || n != 1 {
+		t.Fatalf("EncryptAll() = %d, %v", n, err)
+	}
+
+	if got := loadVaultProfileMeta(vault, nil, "claude", "work").Account; got != "alice@example.com" {
+		t.Errorf("Account = %q, want the email from the decrypted credentials", got)
+	}
+}
+
+func TestDoActivateProfile_HonorsActivationMode(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", filepath.Join(tmpDir, "caam"))
+	t.Setenv("CODEX_HOME", filepath.Join(tmpDir, "codex_home"))
+	spmCfg := config.DefaultSPMConfig()
+	spmCfg.Runtime.ActivationMode = "symlink"
+	if err := spmCfg.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	m := New()
+	m.vaultPath = filepath.Join(tmpDir, "vault")
+	dir := authfile.NewVault(m.vaultPath).ProfilePath("codex", "work")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(`{"access_token":"work"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := m.doActivateProfile("codex", "work")().(activateResultMsg)
+	if msg.err != nil {
+		t.Fatalf("activate error = %v", msg.err)
+	}
+	info, err := os.Lstat(filepath.Join(os.Getenv("CODEX_HOME"), "auth.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("activation_mode = symlink should link the live auth into the vault")
+	}
+}
+
 func TestHandleLoginProfile(t *testing.T) {
 	m := New()
 	m.profiles = map[string][]Profile{
@@ -310,6 +424,54 @@ func TestHandleOpenInBrowser(t *testing.T) {
 	}
 }
 
+// fakeLauncher records the URL it was asked to open.
+type fakeLauncher struct {
+	cfg    *browser.Config
+	opened string
+}
+
+func (f *fakeLauncher) Name() string           { return "fake" }
+func (f *fakeLauncher) SupportsProfiles() bool { return true }
+func (f *fakeLauncher) Open(url string) error  { f.opened = url; return nil }
+
+func TestHandleOpenInBrowser_UsesAccountBrowserProfile(t *testing.T) {
+	launcher := &fakeLauncher{}
+	oldLauncher := newBrowserLauncher
+	newBrowserLauncher = func(cfg *browser.Config) browser.Launcher {
+		launcher.cfg = cfg
+		return launcher
+	}
+	defer func() { newBrowserLauncher = oldLauncher }()
+
+	m := New()
+	m.profiles = map[string][]Profile{
+		"claude": {{Name: "work"}},
+	}
+	m.profileMeta = map[string]map[string]*profile.Profile{
+		"claude": {
+			"work": {
+				Name:              "work",
+				Provider:          "claude",
+				BrowserCommand:    "google-chrome",
+				BrowserProfileDir: "Profile 2",
+			},
+		},
+	}
+
+	result, _ := m.handleOpenInBrowser()
+	updated := result.(Model)
+
+	if want := "https://console.anthropic.com/settings/usage"; launcher.opened != want {
+		t.Errorf("opened %q, want %q", launcher.opened, want)
+	}
+	if launcher.cfg == nil || launcher.cfg.ProfileDir != "Profile 2" {
+		t.Errorf("launcher config = %+v, want the work profile's browser", launcher.cfg)
+	}
+	if !strings.Contains(updated.statusMsg, "work") {
+		t.Errorf("statusMsg = %q, want it to name the account", updated.statusMsg)
+	}
+}
+
 func TestHandleBackupProfile(t *testing.T) {
 	m := New()
 
@@ -602,7 +764,7 @@ func TestFormatError(t *testing.T) {
 // TestShowActivateSuccess tests the showActivateSuccess method.
 func TestShowActivateSuccess(t *testing.T) {
 	m := New()
-	m.showActivateSuccess("claude", "work@example.com")
+	m.showActivateSuccess("claude", "work@example.com", nil)
 
 	if !strings.Contains(m.statusMsg, "Activated") {
 		t.Errorf("expected 'Activated' in status, got %q", m.statusMsg)
@@ -613,6 +775,11 @@ func TestShowActivateSuccess(t *testing.T) {
 	if !strings.Contains(m.statusMsg, "claude") {
 		t.Errorf("expected provider name in status, got %q", m.statusMsg)
 	}
+
+	m.showActivateSuccess("claude", "work@example.com", &authfile.ActivationSafety{AutoBackup: "_backup_20260101_120000"})
+	if !strings.Contains(m.statusMsg, "_backup_20260101_120000") {
+		t.Errorf("expected auto-backup in status, got %q", m.statusMsg)
+	}
 }
 
 // TestShowRefreshSuccess tests the showRefreshSuccess method.
@@ -816,6 +983,19 @@ func TestProjectContextLine(t *testing.T) {
 	if !strings.Contains(result, "no association") {
 		t.Errorf("expected 'no association' in result, got %q", result)
 	}
+
+	// Test a project past its share of the profile's window
+	m.projectContext = &project.Resolved{
+		Profiles: map[string]string{"claude": "work-2"},
+		Sources:  map[string]string{"claude": "/src/foo"},
+	}
+	m.projectUse = map[string]usage.ProjectUse{
+		"claude": {ProfileName: "work-2", Window: "5h", Share: 0.7},
+	}
+	result = m.projectContextLine()
+	if result != "Project: /src/foo → work-2  ⚠ used 70% of its 5h window" {
+		t.Errorf("unexpected project line with usage warning: %q", result)
+	}
 }
 
 // TestProjectDefaultForProvider tests the projectDefaultForProvider method.
@@ -1218,6 +1398,56 @@ func TestApplySearchFilter(t *testing.T) {
 	}
 }
 
+// TestSearchSpansProvidersAndJumps checks that '/' searches every provider
+// and Enter switches to the selected result's provider.
+func TestSearchSpansProvidersAndJumps(t *testing.T) {
+	m := New()
+	m.profiles = map[string][]Profile{
+		"claude": {{Name: "work@example.com"}, {Name: "home@example.com"}},
+		"codex":  {{Name: "work-codex"}},
+		"gemini": {{Name: "other"}},
+	}
+	m.profilesPanel = NewProfilesPanel()
+	m.syncProfilesPanel()
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	m = updated.(Model)
+	for _, r := range "work" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	if m.profilesPanel.Count() != 2 {
+		t.Fatalf("expected 2 matches across providers, got %d (%s)", m.profilesPanel.Count(), m.statusMsg)
+	}
+	if view := m.profilesPanel.View(); !strings.Contains(view, "All Profiles") || !strings.Contains(view, "codex/work-codex") {
+		t.Errorf("expected provider-labelled results, got %q", view)
+	}
+
+	// Select the codex result and jump to it.
+	for i := 0; i < m.profilesPanel.Count(); i++ {
+		if info := m.profilesPanel.GetSelectedProfile(); info != nil && info.Provider == "codex" {
+			break
+		}
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m = updated.(Model)
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+
+	if m.state != stateList {
+		t.Errorf("expected stateList after Enter, got %v", m.state)
+	}
+	if got := m.currentProvider(); got != "codex" {
+		t.Errorf("expected to jump to codex, got %s", got)
+	}
+	if m.selectedProfileName != "work-codex" {
+		t.Errorf("expected work-codex selected, got %q", m.selectedProfileName)
+	}
+	if strings.Contains(m.profilesPanel.View(), "All Profiles") {
+		t.Error("expected the panel to show one provider again")
+	}
+}
+
 // TestApplySearchFilterNilPanel tests applySearchFilter with nil profilesPanel.
 func TestApplySearchFilterNilPanel(t *testing.T) {
 	m := New()
@@ -14,6 +14,7 @@ import (
 // ProfileInfo represents a profile with all displayable information.
 type ProfileInfo struct {
 	Name           string
+	Provider       string // Set on cross-provider search results
 	Badge          string
 	ProjectDefault bool
 	AuthMode       string
@@ -32,6 +33,7 @@ type ProfileInfo struct {
 // ProfilesPanel renders the center panel showing profiles for the selected provider.
 type ProfilesPanel struct {
 	provider string
+	global   bool // Showing search results from every provider
 	profiles []ProfileInfo
 	selected int
 	width    int
@@ -189,6 +191,12 @@ func (p *ProfilesPanel) SetProvider(provider string) {
 	p.provider = provider
 }
 
+// SetGlobal switches between one provider's profiles and cross-provider
+// search results, which are labelled provider/name.
+func (p *ProfilesPanel) SetGlobal(global bool) {
+	p.global = global
+}
+
 // SetProfiles sets the profiles to display, sorted by last used.
 func (p *ProfilesPanel) SetProfiles(profiles []ProfileInfo) {
 	// Sort by last used (most recent first), then by name
@@ -269,11 +277,17 @@ func (p *ProfilesPanel) SetSize(width, height int) {
 func (p *ProfilesPanel) View() string {
 	// Title
 	title := p.styles.Title.Render(capitalizeFirst(p.provider) + " Profiles")
+	if p.global {
+		title = p.styles.Title.Render("All Profiles")
+	}
 
 	if len(p.profiles) == 0 {
 		empty := p.styles.Empty.Render(
 			fmt.Sprintf("No profiles saved for %s\n\nUse 'caam backup %s <email>' to save a profile",
 				p.provider, p.provider))
+		if p.global {
+			empty = p.styles.Empty.Render("No matching profiles in any provider")
+		}
 		inner := lipgloss.JoinVertical(lipgloss.Left, title, empty)
 		if p.width > 0 {
 			return p.styles.Border.Width(p.width - 2).Render(inner)
@@ -397,7 +411,11 @@ func (p *ProfilesPanel) View() string {
 		}
 
 		// Build row cells with proper padding
-		paddedName := padRight(formatNameWithBadge(prof.Name, prof.Badge, colWidths.name-2), colWidths.name-2)
+		name := prof.Name
+		if p.global && prof.Provider != "" {
+			name = prof.Provider + "/" + name
+		}
+		paddedName := padRight(formatNameWithBadge(name, prof.Badge, colWidths.name-2), colWidths.name-2)
 		paddedStatusText := padRight(statusText, colWidths.status)
 		renderedStatus := statusStyle.Render(paddedStatusText)
 
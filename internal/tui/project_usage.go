@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/project"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/usage"
+)
+
+// loadProjectUse estimates, for each provider associated with a project,
+// how much of the associated profile's limit window that project has used,
+// keeping the ones at or above threshold percent.
+func loadProjectUse(resolved *project.Resolved, threshold int, now time.Time) map[string]usage.ProjectUse {
+	if resolved == nil || threshold <= 0 {
+		return nil
+	}
+	db, err := caamdb.Open()
+	if err != nil {
+		return nil
+	}
+	defer db.Close()
+
+	out := make(map[string]usage.ProjectUse)
+	for provider, source := range resolved.Sources {
+		profileName := resolved.Profiles[provider]
+		if source == "" || source == "<default>" || profileName == "" {
+			continue
+		}
+		use, ok := projectUseFor(db, provider, profileName, source, now)
+		if ok && use.Share*100 >= float64(threshold) {
+			out[provider] = use
+		}
+	}
+	return out
+}
+
+// projectUseFor applies the provider's limit model to the profile's wrap
+// sessions and limit hits, charging the ones that ran in dir.
+func projectUseFor(db *caamdb.DB, provider, profileName, dir string, now time.Time) (usage.ProjectUse, bool) {
+	model, ok := usage.LimitModelFor(provider)
+	if !ok {
+		return usage.ProjectUse{}, false
+	}
+	since := now.Add(-4 * model.MaxWindow())
+
+	sessions, err := db.GetWrapSessions(provider, since, 5000)
+	if err != nil {
+		return usage.ProjectUse{}, false
+	}
+	var samples []usage.ProjectSample
+	for _, s := range sessions {
+		if s.ProfileName != profileName {
+			continue
+		}
+		d := time.Duration(s.DurationSeconds) * time.Second
+		if d <= 0 && !s.EndedAt.IsZero() {
+			d = s.EndedAt.Sub(s.StartedAt)
+		}
+		samples = append(samples, usage.ProjectSample{
+			UsageSample: usage.UsageSample{Start: s.StartedAt, Duration: d},
+			WorkDir:     s.WorkDir,
+		})
+	}
+
+	events, err := db.CooldownHistory(provider, profileName, since)
+	if err != nil {
+		return usage.ProjectUse{}, false
+	}
+	var hits []time.Time
+	for _, ev := range events {
+		hits = append(hits, ev.HitAt)
+	}
+
+	use, ok := usage.EstimateProjectUse(model, samples, hits, dir, now)
+	use.ProfileName = profileName
+	return use, ok
+}
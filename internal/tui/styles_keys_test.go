@@ -161,7 +161,9 @@ func TestDefaultKeyMap(t *testing.T) {
 		assertKeyBinding(t, km.Search, "Search")
 		assertKeyBinding(t, km.Project, "Project")
 		assertKeyBinding(t, km.Usage, "Usage")
+		assertKeyBinding(t, km.Timeline, "Timeline")
 		assertKeyBinding(t, km.Sync, "Sync")
+		assertKeyBinding(t, km.Vault, "Vault")
 		assertKeyBinding(t, km.Export, "Export")
 		assertKeyBinding(t, km.Import, "Import")
 	})
@@ -229,9 +231,9 @@ func TestKeyMapFullHelp(t *testing.T) {
 		t.Errorf("Secondary actions group should have 5 bindings, got %d", len(fullHelp[2]))
 	}
 
-	// Group 4: Advanced (Sync, Export, Import)
-	if len(fullHelp[3]) != 3 {
-		t.Errorf("Advanced group should have 3 bindings, got %d", len(fullHelp[3]))
+	// Group 4: Advanced (Timeline, Sync, Vault, Export, Import)
+	if len(fullHelp[3]) != 5 {
+		t.Errorf("Advanced group should have 5 bindings, got %d", len(fullHelp[3]))
 	}
 
 	// Group 5: General (Help, Quit)
@@ -262,7 +264,9 @@ func TestKeyBindingsHaveKeys(t *testing.T) {
 		{"Search", km.Search},
 		{"Project", km.Project},
 		{"Usage", km.Usage},
+		{"Timeline", km.Timeline},
 		{"Sync", km.Sync},
+		{"Vault", km.Vault},
 		{"Export", km.Export},
 		{"Import", km.Import},
 		{"Confirm", km.Confirm},
@@ -303,7 +307,9 @@ func TestKeyBindingsHaveHelp(t *testing.T) {
 		{"Search", km.Search, "search profiles"},
 		{"Project", km.Project, "set project association"},
 		{"Usage", km.Usage, "usage stats"},
+		{"Timeline", km.Timeline, "event timeline"},
 		{"Sync", km.Sync, "sync pool"},
+		{"Vault", km.Vault, "switch vault"},
 		{"Export", km.Export, "export vault"},
 		{"Import", km.Import, "import bundle"},
 		{"Confirm", km.Confirm, "confirm"},
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// syncConflict is a profile that changed on both sides, awaiting a choice.
+type syncConflict struct {
+	machineID   string
+	machineName string
+	provider    string
+	profile     string
+	conflict    sync.Conflict
+}
+
+// SyncConflictDialog shows both copies of a conflicting profile and lets the
+// user keep one.
+type SyncConflictDialog struct {
+	item   syncConflict
+	choice string // sync.PreferLocal, PreferRemote or PreferFresher
+	result DialogResult
+	styles Styles
+	width  int
+}
+
+// newSyncConflictDialog creates a dialog for item.
+func newSyncConflictDialog(item syncConflict) *SyncConflictDialog {
+	return &SyncConflictDialog{
+		item:   item,
+		result: DialogResultNone,
+		styles: DefaultStyles(),
+		width:  64,
+	}
+}
+
+// SetStyles sets the styles for the dialog.
+func (d *SyncConflictDialog) SetStyles(styles Styles) {
+	d.styles = styles
+}
+
+// SetWidth sets the dialog width.
+func (d *SyncConflictDialog) SetWidth(width int) {
+	d.width = width
+}
+
+// Result returns the dialog result.
+func (d *SyncConflictDialog) Result() DialogResult {
+	return d.result
+}
+
+// Choice returns the side to keep once the dialog is submitted.
+func (d *SyncConflictDialog) Choice() string {
+	return d.choice
+}
+
+// Update handles messages for the dialog.
+func (d *SyncConflictDialog) Update(msg tea.Msg) (*SyncConflictDialog, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+	switch keyMsg.String() {
+	case "l":
+		d.choice = sync.PreferLocal
+		d.result = DialogResultSubmit
+	case "r":
+		d.choice = sync.PreferRemote
+		d.result = DialogResultSubmit
+	case "f":
+		d.choice = sync.PreferFresher
+		d.result = DialogResultSubmit
+	case "s", "esc":
+		d.result = DialogResultCancel
+	}
+	return d, nil
+}
+
+// View renders the dialog.
+func (d *SyncConflictDialog) View() string {
+	var content strings.Builder
+
+	content.WriteString(d.styles.DialogTitle.Render("Sync Conflict"))
+	content.WriteString("\n\n")
+	fmt.Fprintf(&content, "%s/%s changed here and on %s since the last sync.\n\n",
+		d.item.provider, d.item.profile, d.item.machineName)
+	content.WriteString("Local:  " + conflictSideSummary(d.item.conflict.Local) + "\n")
+	content.WriteString("Remote: " + conflictSideSummary(d.item.conflict.Remote) + "\n\n")
+
+	help := d.styles.StatusKey.Render("l") + " keep local  " +
+		d.styles.StatusKey.Render("r") + " keep remote  " +
+		d.styles.StatusKey.Render("f") + " fresher  " +
+		d.styles.StatusKey.Render("s/esc") + " skip"
+	content.WriteString(help)
+
+	return d.styles.DialogFocused.
+		Width(d.width).
+		Render(content.String())
+}
+
+// conflictSideSummary describes one copy: account, expiry and when it was
+// last modified.
+func conflictSideSummary(side sync.ConflictSide) string {
+	parts := []string{"unknown account"}
+	if side.Account != "" {
+		parts[0] = side.Account
+	}
+	if f := side.Freshness; f != nil {
+		switch {
+		case f.ExpiresAt.IsZero():
+			parts = append(parts, "expiry unknown")
+		case time.Until(f.ExpiresAt) <= 0:
+			parts = append(parts, "expired")
+		default:
+			parts = append(parts, formatDuration(time.Until(f.ExpiresAt)))
+		}
+		if !f.ModifiedAt.IsZero() {
+			parts = append(parts, "modified "+formatTimeAgo(f.ModifiedAt))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// conflictsFromResults picks the unresolved conflicts out of sync results.
+func conflictsFromResults(results []*sync.SyncResult) []syncConflict {
+	var conflicts []syncConflict
+	for _, r := range results {
+		op := r.Operation
+		if op == nil || op.Direction != sync.SyncConflict || op.Conflict == nil || op.Machine == nil {
+			continue
+		}
+		conflicts = append(conflicts, syncConflict{
+			machineID:   op.Machine.ID,
+			machineName: op.Machine.Name,
+			provider:    op.Provider,
+			profile:     op.Profile,
+			conflict:    *op.Conflict,
+		})
+	}
+	return conflicts
+}
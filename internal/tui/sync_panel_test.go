@@ -3,7 +3,9 @@ package tui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -144,3 +146,59 @@ func TestSyncPanel_SelectedMachine(t *testing.T) {
 
 // Note: TestGetStatusIcon, TestFormatTimeAgo, TestTruncateString, TestToMachineInfo
 // are defined in sync_test.go
+
+func TestModel_SyncConflictDialog(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", tmpDir)
+
+	m := New()
+	m.width = 120
+	m.height = 40
+	m.syncPanel.Toggle()
+
+	conflict := func(profile string) syncConflict {
+		return syncConflict{
+			machineID:   "id-1",
+			machineName: "laptop",
+			provider:    "claude",
+			profile:     profile,
+			conflict: sync.Conflict{
+				Local:  sync.ConflictSide{Account: "alice@example.com", Freshness: &sync.TokenFreshness{ExpiresAt: time.Now().Add(3 * time.Hour)}},
+				Remote: sync.ConflictSide{Account: "bob@example.com"},
+			},
+		}
+	}
+	model, _ := m.Update(syncCompletedMsg{
+		machineID:   "id-1",
+		machineName: "laptop",
+		stats:       sync.SyncStats{Conflicts: 2},
+		conflicts:   []syncConflict{conflict("work"), conflict("home")},
+	})
+	m = model.(Model)
+	if m.state != stateSyncConflict || m.syncConflictDialog == nil {
+		t.Fatalf("a sync with conflicts should open the conflict dialog, state = %v", m.state)
+	}
+	view := m.View()
+	for _, want := range []string{"claude/work", "laptop", "alice@example.com", "bob@example.com", "2h left"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("conflict dialog missing %q:\n%s", want, view)
+		}
+	}
+
+	// Skipping moves on to the next conflict, ahead of the sync panel's keys.
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = model.(Model)
+	if m.syncConflictDialog == nil || m.syncConflictDialog.item.profile != "home" {
+		t.Fatalf("skip should show the next conflict")
+	}
+
+	// Choosing a side starts resolving it.
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	m = model.(Model)
+	if cmd == nil || m.state != stateList || m.syncConflictDialog != nil {
+		t.Fatalf("keeping local should close the dialog and resolve, state = %v", m.state)
+	}
+	if !m.syncPanel.Visible() {
+		t.Error("the sync panel should stay open")
+	}
+}
@@ -54,9 +54,17 @@ type syncCompletedMsg struct {
 	machineID string
 	machineName string
 	stats   sync.SyncStats
+	conflicts []syncConflict
 	err       error
 }
 
+// syncConflictResolvedMsg is sent when a conflict has been resolved.
+type syncConflictResolvedMsg struct {
+	item   syncConflict
+	result *sync.SyncResult
+	err    error
+}
+
 // loadSyncState loads the sync state from disk.
 func (m Model) loadSyncState() tea.Cmd {
 	return func() tea.Msg {
@@ -224,6 +232,31 @@ func (m Model) syncWithMachine(machineID string) tea.Cmd {
 			machineID:   machineID,
 			machineName: machine.Name,
 			stats:       sync.AggregateResults(results),
+			conflicts:   conflictsFromResults(results),
+		}
+	}
+}
+
+// resolveSyncConflict keeps the chosen side of a conflicting profile.
+func (m Model) resolveSyncConflict(item syncConflict, prefer string) tea.Cmd {
+	return func() tea.Msg {
+		state, err := sync.LoadSyncState()
+		if err != nil {
+			return syncConflictResolvedMsg{item: item, err: err}
 		}
+
+		machine := state.Pool.GetMachine(item.machineID)
+		if machine == nil {
+			return syncConflictResolvedMsg{item: item, err: fmt.Errorf("machine not found")}
+		}
+
+		syncer, err := sync.NewSyncer(sync.DefaultSyncerConfig())
+		if err != nil {
+			return syncConflictResolvedMsg{item: item, err: err}
+		}
+		defer syncer.Close()
+
+		result, err := syncer.ResolveConflict(context.Background(), item.provider, item.profile, machine, prefer)
+		return syncConflictResolvedMsg{item: item, result: result, err: err}
 	}
 }
@@ -0,0 +1,304 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// timelineLimit is how many records are read from each source.
+const timelineLimit = 200
+
+// Timeline entry kinds besides the activity_log event types.
+const (
+	timelineKindLimit = "limit"
+	timelineKindSync  = "sync"
+)
+
+// TimelinePanel lists recent activations, cooldowns, limit hits and syncs in
+// one scrollable, newest-first list.
+type TimelinePanel struct {
+	visible bool
+	loading bool
+
+	entries []TimelineEntry
+	offset  int
+
+	width  int
+	height int
+
+	styles TimelinePanelStyles
+}
+
+// TimelineEntry is one line of the timeline.
+type TimelineEntry struct {
+	At       time.Time
+	Kind     string
+	Provider string
+	Profile  string
+	Detail   string
+	Failed   bool
+}
+
+type TimelinePanelStyles struct {
+	Border    lipgloss.Style
+	Title     lipgloss.Style
+	Time      lipgloss.Style
+	Kind      lipgloss.Style
+	Failed    lipgloss.Style
+	Detail    lipgloss.Style
+	Empty     lipgloss.Style
+	Footer    lipgloss.Style
+	Providers map[string]lipgloss.Style
+	Provider  lipgloss.Style
+}
+
+// NewTimelinePanelStyles returns themed styles for the timeline panel.
+func NewTimelinePanelStyles(theme Theme) TimelinePanelStyles {
+	p := theme.Palette
+
+	return TimelinePanelStyles{
+		Border: lipgloss.NewStyle().
+			Border(theme.Border).
+			BorderForeground(p.BorderMuted).
+			Background(p.Surface).
+			Padding(1, 2),
+		Title: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Accent),
+		Time: lipgloss.NewStyle().
+			Foreground(p.Muted),
+		Kind: lipgloss.NewStyle().
+			Foreground(p.Text),
+		Failed: lipgloss.NewStyle().
+			Foreground(p.Danger),
+		Detail: lipgloss.NewStyle().
+			Foreground(p.Text),
+		Empty: lipgloss.NewStyle().
+			Foreground(p.Muted).
+			Italic(true),
+		Footer: lipgloss.NewStyle().
+			Foreground(p.Muted),
+		Providers: map[string]lipgloss.Style{
+			"claude": lipgloss.NewStyle().Foreground(p.Accent),
+			"codex":  lipgloss.NewStyle().Foreground(p.Info),
+			"gemini": lipgloss.NewStyle().Foreground(p.AccentAlt),
+		},
+		Provider: lipgloss.NewStyle().Foreground(p.Text),
+	}
+}
+
+// NewTimelinePanelWithTheme creates a new timeline panel using a theme.
+func NewTimelinePanelWithTheme(theme Theme) *TimelinePanel {
+	return &TimelinePanel{
+		styles: NewTimelinePanelStyles(theme),
+	}
+}
+
+func (t *TimelinePanel) Toggle() {
+	if t == nil {
+		return
+	}
+	t.visible = !t.visible
+}
+
+func (t *TimelinePanel) Visible() bool {
+	if t == nil {
+		return false
+	}
+	return t.visible
+}
+
+func (t *TimelinePanel) SetLoading(loading bool) {
+	if t == nil {
+		return
+	}
+	t.loading = loading
+}
+
+func (t *TimelinePanel) SetSize(width, height int) {
+	if t == nil {
+		return
+	}
+	t.width = width
+	t.height = height
+	t.clampOffset()
+}
+
+// SetEntries replaces the timeline and scrolls back to the newest entry.
+func (t *TimelinePanel) SetEntries(entries []TimelineEntry) {
+	if t == nil {
+		return
+	}
+	t.loading = false
+	t.entries = entries
+	t.offset = 0
+}
+
+// Scroll moves the view by delta lines; positive goes back in time.
+func (t *TimelinePanel) Scroll(delta int) {
+	if t == nil {
+		return
+	}
+	t.offset += delta
+	t.clampOffset()
+}
+
+// PageSize is how many entries fit on screen.
+func (t *TimelinePanel) PageSize() int {
+	if t == nil {
+		return 0
+	}
+	if t.height <= 0 {
+		return len(t.entries)
+	}
+	// Border, padding, title, subtitle, blank line and footer.
+	if rows := t.height - 11; rows > 1 {
+		return rows
+	}
+	return 1
+}
+
+func (t *TimelinePanel) clampOffset() {
+	maxOffset := len(t.entries) - t.PageSize()
+	if t.offset > maxOffset {
+		t.offset = maxOffset
+	}
+	if t.offset < 0 {
+		t.offset = 0
+	}
+}
+
+func (t *TimelinePanel) View() string {
+	if t == nil {
+		return ""
+	}
+
+	title := t.styles.Title.Render("Event Timeline")
+
+	if t.loading {
+		return t.render(title, "", t.styles.Empty.Render("Loading events…"))
+	}
+
+	if len(t.entries) == 0 {
+		body := t.styles.Empty.Render("No events recorded yet.\n\nActivations, limit hits and syncs show up here as they happen.")
+		return t.render(title, "", body)
+	}
+
+	end := t.offset + t.PageSize()
+	if end > len(t.entries) {
+		end = len(t.entries)
+	}
+
+	var rows []string
+	for _, e := range t.entries[t.offset:end] {
+		rows = append(rows, t.renderEntry(e))
+	}
+
+	subtitle := fmt.Sprintf("Newest first, showing %d-%d of %d", t.offset+1, end, len(t.entries))
+	footer := t.styles.Footer.Render("\nPress [t] to toggle, [j/k] to scroll, [pgup/pgdn] to page, [r] to reload, [esc] to close")
+	return t.render(title, subtitle, strings.Join(rows, "\n")+"\n"+footer)
+}
+
+func (t *TimelinePanel) renderEntry(e TimelineEntry) string {
+	ts := t.styles.Time.Render(e.At.Local().Format("Jan 02 15:04:05"))
+
+	kindStyle := t.styles.Kind
+	if e.Failed {
+		kindStyle = t.styles.Failed
+	}
+	kind := kindStyle.Render(fmt.Sprintf("%-10s", e.Kind))
+
+	providerStyle, ok := t.styles.Providers[e.Provider]
+	if !ok {
+		providerStyle = t.styles.Provider
+	}
+	label := e.Provider
+	if e.Profile != "" {
+		label += "/" + e.Profile
+	}
+	who := providerStyle.Render(fmt.Sprintf("%-22s", label))
+
+	line := ts + "  " + kind + "  " + who
+	if e.Detail != "" {
+		line += "  " + t.styles.Detail.Render(e.Detail)
+	}
+	return line
+}
+
+func (t *TimelinePanel) render(title, subtitle, body string) string {
+	inner := lipgloss.JoinVertical(lipgloss.Left, title, subtitle, "", body)
+	if t.width > 0 {
+		return t.styles.Border.Width(t.width - 2).Height(t.height - 2).Render(inner)
+	}
+	return t.styles.Border.Render(inner)
+}
+
+// buildTimeline merges activity_log events, limit hits and sync history into
+// one list, newest first.
+func buildTimeline(events []caamdb.Event, cooldowns []caamdb.CooldownEvent, history []sync.HistoryEntry) []TimelineEntry {
+	entries := make([]TimelineEntry, 0, len(events)+len(cooldowns)+len(history))
+
+	for _, e := range events {
+		entry := TimelineEntry{
+			At:       e.Timestamp,
+			Kind:     e.Type,
+			Provider: e.Provider,
+			Profile:  e.ProfileName,
+			Failed:   e.Type == caamdb.EventError,
+		}
+		if e.Duration > 0 {
+			entry.Detail = "after " + e.Duration.Round(time.Second).String()
+		}
+		if msg, ok := e.Details["error"].(string); ok && msg != "" {
+			entry.Detail = msg
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, c := range cooldowns {
+		detail := "cooldown until " + c.CooldownUntil.Local().Format("Jan 02 15:04")
+		if c.Notes != "" {
+			detail += " (" + c.Notes + ")"
+		}
+		entries = append(entries, TimelineEntry{
+			At:       c.HitAt,
+			Kind:     timelineKindLimit,
+			Provider: c.Provider,
+			Profile:  c.ProfileName,
+			Detail:   detail,
+			Failed:   true,
+		})
+	}
+
+	for _, h := range history {
+		detail := h.Action
+		if h.Machine != "" {
+			detail += " with " + h.Machine
+		}
+		if h.Trigger != "" {
+			detail += " [" + h.Trigger + "]"
+		}
+		if !h.Success && h.Error != "" {
+			detail += ": " + h.Error
+		}
+		entries = append(entries, TimelineEntry{
+			At:       h.Timestamp,
+			Kind:     timelineKindSync,
+			Provider: h.Provider,
+			Profile:  h.Profile,
+			Detail:   detail,
+			Failed:   !h.Success,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.After(entries[j].At)
+	})
+	return entries
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/sync"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBuildTimeline_MergesSourcesNewestFirst(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	entries := buildTimeline(
+		[]caamdb.Event{
+			{Timestamp: now.Add(-3 * time.Hour), Type: caamdb.EventActivate, Provider: "claude", ProfileName: "work"},
+			{Timestamp: now.Add(-time.Hour), Type: caamdb.EventError, Provider: "codex", ProfileName: "main", Details: map[string]any{"error": "token expired"}},
+		},
+		[]caamdb.CooldownEvent{
+			{Provider: "claude", ProfileName: "work", HitAt: now.Add(-2 * time.Hour), CooldownUntil: now, Notes: "batch"},
+		},
+		[]sync.HistoryEntry{
+			{Timestamp: now.Add(-30 * time.Minute), Trigger: "backup", Provider: "claude", Profile: "work", Machine: "laptop", Action: "push", Success: false, Error: "timeout"},
+		},
+	)
+
+	if len(entries) != 4 {
+		t.Fatalf("len(entries) = %d, want 4", len(entries))
+	}
+	wantKinds := []string{timelineKindSync, caamdb.EventError, timelineKindLimit, caamdb.EventActivate}
+	for i, want := range wantKinds {
+		if entries[i].Kind != want {
+			t.Fatalf("entries[%d].Kind = %q, want %q", i, entries[i].Kind, want)
+		}
+	}
+	if !entries[0].Failed || !strings.Contains(entries[0].Detail, "laptop") || !strings.Contains(entries[0].Detail, "timeout") {
+		t.Fatalf("sync entry = %+v, want failed push with laptop", entries[0])
+	}
+	if entries[1].Detail != "token expired" {
+		t.Fatalf("error entry detail = %q, want error message", entries[1].Detail)
+	}
+	if !strings.Contains(entries[2].Detail, "batch") {
+		t.Fatalf("limit entry detail = %q, want notes", entries[2].Detail)
+	}
+	if entries[3].Failed {
+		t.Fatalf("activate entry marked failed")
+	}
+}
+
+func TestTimelinePanel_ScrollAndView(t *testing.T) {
+	p := NewTimelinePanelWithTheme(DefaultTheme())
+	p.SetSize(120, 16) // room for 5 rows
+
+	if out := p.View(); !strings.Contains(out, "No events recorded") {
+		t.Fatalf("empty View() missing placeholder")
+	}
+
+	now := time.Now()
+	var entries []TimelineEntry
+	for i := 0; i < 12; i++ {
+		entries = append(entries, TimelineEntry{At: now.Add(-time.Duration(i) * time.Minute), Kind: "activate", Provider: "claude", Profile: "p" + string(rune('a'+i))})
+	}
+	p.SetEntries(entries)
+
+	out := p.View()
+	if !strings.Contains(out, "claude/pa") || strings.Contains(out, "claude/pf") {
+		t.Fatalf("View() should show only the first page")
+	}
+	if !strings.Contains(out, "showing 1-5 of 12") {
+		t.Fatalf("View() missing range subtitle")
+	}
+
+	p.Scroll(100)
+	if out := p.View(); !strings.Contains(out, "claude/pl") || !strings.Contains(out, "showing 8-12 of 12") {
+		t.Fatalf("Scroll past the end should stop at the oldest page")
+	}
+	p.Scroll(-100)
+	if p.offset != 0 {
+		t.Fatalf("offset = %d, want 0 after scrolling back", p.offset)
+	}
+}
+
+func TestModel_TimelinePanel_Toggle(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", tmpDir)
+
+	m := New()
+	m.width = 120
+	m.height = 40
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = model.(Model)
+	if !m.timelinePanel.Visible() {
+		t.Fatalf("timeline panel not visible after toggle")
+	}
+	if cmd == nil {
+		t.Fatalf("opening the timeline should load events")
+	}
+
+	model, _ = m.Update(timelineLoadedMsg{entries: []TimelineEntry{{At: time.Now(), Kind: "activate", Provider: "codex", Profile: "main"}}})
+	m = model.(Model)
+	if out := m.View(); !strings.Contains(out, "codex/main") {
+		t.Fatalf("View() missing loaded entry")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	m = model.(Model)
+	if m.timelinePanel.Visible() {
+		t.Fatalf("timeline panel still visible after esc")
+	}
+}
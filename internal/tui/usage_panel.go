@@ -4,13 +4,43 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
 
+// UsageMetric selects what the usage panel charts.
+type UsageMetric int
+
+const (
+	UsageMetricHours UsageMetric = iota
+	UsageMetricSessions
+	UsageMetricLimitHits
+)
+
+// String returns the metric's display name.
+func (m UsageMetric) String() string {
+	switch m {
+	case UsageMetricSessions:
+		return "sessions"
+	case UsageMetricLimitHits:
+		return "limit hits"
+	default:
+		return "hours"
+	}
+}
+
+// usageSparkMaxDays caps the daily chart, which is also its span for "All
+// time".
+const usageSparkMaxDays = 30
+
+// sparkLevels are the bar heights of a sparkline, lowest first.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
 type UsagePanel struct {
 	visible   bool
 	timeRange int // 1=24h, 7=week, 30=month, 0=all
+	metric    UsageMetric
 	loading   bool
 
 	stats []ProfileUsage
@@ -26,7 +56,19 @@ type ProfileUsage struct {
 	ProfileName  string
 	SessionCount int
 	TotalHours   float64
-	Percentage   float64
+	LimitHits    int
+	Percentage   float64 // of the busiest profile, for the selected metric
+
+	// Daily holds the days with activity, oldest first.
+	Daily []UsageDay
+}
+
+// UsageDay is one profile's activity on one UTC day.
+type UsageDay struct {
+	Day       time.Time // midnight UTC
+	Sessions  int
+	Hours     float64
+	LimitHits int
 }
 
 type UsagePanelStyles struct {
@@ -36,6 +78,11 @@ type UsagePanelStyles struct {
 	BarFill lipgloss.Style
 	Empty   lipgloss.Style
 	Footer  lipgloss.Style
+	Spark   lipgloss.Style
+	Heading lipgloss.Style
+
+	// Providers colors each provider's share of the stacked summary bar.
+	Providers map[string]lipgloss.Style
 }
 
 func DefaultUsagePanelStyles() UsagePanelStyles {
@@ -64,6 +111,16 @@ func NewUsagePanelStyles(theme Theme) UsagePanelStyles {
 			Italic(true),
 		Footer: lipgloss.NewStyle().
 			Foreground(p.Muted),
+		Spark: lipgloss.NewStyle().
+			Foreground(p.Accent),
+		Heading: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.Text),
+		Providers: map[string]lipgloss.Style{
+			"claude": lipgloss.NewStyle().Foreground(p.Accent),
+			"codex":  lipgloss.NewStyle().Foreground(p.Info),
+			"gemini": lipgloss.NewStyle().Foreground(p.AccentAlt),
+		},
 	}
 }
 
@@ -107,6 +164,23 @@ func (u *UsagePanel) TimeRange() int {
 	return u.timeRange
 }
 
+// Metric returns the metric being charted.
+func (u *UsagePanel) Metric() UsageMetric {
+	if u == nil {
+		return UsageMetricHours
+	}
+	return u.metric
+}
+
+// NextMetric cycles hours -> sessions -> limit hits and re-sorts the stats.
+func (u *UsagePanel) NextMetric() {
+	if u == nil {
+		return
+	}
+	u.metric = (u.metric + 1) % 3
+	u.SetStats(u.stats)
+}
+
 func (u *UsagePanel) SetLoading(loading bool) {
 	if u == nil {
 		return
@@ -132,31 +206,60 @@ func (u *UsagePanel) SetStats(stats []ProfileUsage) {
 	copied := make([]ProfileUsage, len(stats))
 	copy(copied, stats)
 	sort.Slice(copied, func(i, j int) bool {
-		if copied[i].TotalHours == copied[j].TotalHours {
-			if copied[i].SessionCount == copied[j].SessionCount {
-				return copied[i].Provider+"/"+copied[i].ProfileName < copied[j].Provider+"/"+copied[j].ProfileName
+		vi, vj := u.metricValue(copied[i]), u.metricValue(copied[j])
+		if vi == vj {
+			if copied[i].TotalHours == copied[j].TotalHours {
+				if copied[i].SessionCount == copied[j].SessionCount {
+					return copied[i].Provider+"/"+copied[i].ProfileName < copied[j].Provider+"/"+copied[j].ProfileName
+				}
+				return copied[i].SessionCount > copied[j].SessionCount
 			}
-			return copied[i].SessionCount > copied[j].SessionCount
+			return copied[i].TotalHours > copied[j].TotalHours
 		}
-		return copied[i].TotalHours > copied[j].TotalHours
+		return vi > vj
 	})
 
-	maxHours := 0.0
+	maxValue := 0.0
 	for _, s := range copied {
-		if s.TotalHours > maxHours {
-			maxHours = s.TotalHours
+		if v := u.metricValue(s); v > maxValue {
+			maxValue = v
 		}
 	}
 
 	for i := range copied {
-		if maxHours > 0 {
-			copied[i].Percentage = copied[i].TotalHours / maxHours
+		copied[i].Percentage = 0
+		if maxValue > 0 {
+			copied[i].Percentage = u.metricValue(copied[i]) / maxValue
 		}
 	}
 
 	u.stats = copied
 }
 
+// metricValue returns a profile's total for the selected metric.
+func (u *UsagePanel) metricValue(s ProfileUsage) float64 {
+	switch u.metric {
+	case UsageMetricSessions:
+		return float64(s.SessionCount)
+	case UsageMetricLimitHits:
+		return float64(s.LimitHits)
+	default:
+		return s.TotalHours
+	}
+}
+
+// dayValue returns a day's value for the selected metric.
+func (u *UsagePanel) dayValue(d UsageDay) float64 {
+	switch u.metric {
+	case UsageMetricSessions:
+		return float64(d.Sessions)
+	case UsageMetricLimitHits:
+		return float64(d.LimitHits)
+	default:
+		return d.Hours
+	}
+}
+
 func (u *UsagePanel) View() string {
 	if u == nil {
 		return ""
@@ -182,25 +285,152 @@ func (u *UsagePanel) View() string {
 		}
 	}
 
-	var totalSessions int
+	days := u.sparkDays()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	series := make([][]float64, len(u.stats))
+	maxDay := 0.0
+	for i, s := range u.stats {
+		series[i] = u.dailySeries(s, days, today)
+		for _, v := range series[i] {
+			if v > maxDay {
+				maxDay = v
+			}
+		}
+	}
+
+	var totalSessions, totalHits int
 	var totalHours float64
 
 	var rows []string
-	for _, s := range u.stats {
+	for i, s := range u.stats {
 		totalSessions += s.SessionCount
 		totalHours += s.TotalHours
+		totalHits += s.LimitHits
 
 		label := fmt.Sprintf("%s/%s", s.Provider, s.ProfileName)
 		bar := u.renderBar(s.Percentage, barWidth)
-		rows = append(rows, u.styles.Row.Render(fmt.Sprintf("%-22s  %s  %3d sess  %5.1fh", label, bar, s.SessionCount, s.TotalHours)))
+		line := fmt.Sprintf("%-22s  %s  %3d sess  %5.1fh  %2d hits", label, bar, s.SessionCount, s.TotalHours, s.LimitHits)
+		if days > 1 {
+			line += "  " + u.styles.Spark.Render(renderSparkline(series[i], maxDay))
+		}
+		rows = append(rows, u.styles.Row.Render(line))
 	}
 
-	footer := u.styles.Footer.Render(fmt.Sprintf("\nTotal: %d sessions, %.1f hours\n\nPress [u] to toggle, [1-4] for time range, [esc] to close", totalSessions, totalHours))
-	body := strings.Join(rows, "\n") + footer
+	heading := fmt.Sprintf("Charting %s", u.metric)
+	if days > 1 {
+		heading += fmt.Sprintf(" · daily, last %d days", days)
+	}
+
+	summary := u.providerSummary(barWidth * 2)
+	footer := u.styles.Footer.Render(fmt.Sprintf("\nTotal: %d sessions, %.1f hours, %d limit hits\n\nPress [u] to toggle, [1-4] for time range, [m] for metric, [esc] to close", totalSessions, totalHours, totalHits))
+	body := u.styles.Heading.Render(heading) + "\n" + strings.Join(rows, "\n") + "\n\n" + summary + footer
 
 	return u.render(title, timeRange, body)
 }
 
+// sparkDays returns how many days the daily chart spans for the time range.
+func (u *UsagePanel) sparkDays() int {
+	if u.timeRange <= 0 || u.timeRange > usageSparkMaxDays {
+		return usageSparkMaxDays
+	}
+	return u.timeRange
+}
+
+// dailySeries returns the metric for each of the last days days, ending
+// today, with zero for days without activity.
+func (u *UsagePanel) dailySeries(s ProfileUsage, days int, today time.Time) []float64 {
+	series := make([]float64, days)
+	start := today.AddDate(0, 0, -(days - 1))
+	for _, d := range s.Daily {
+		idx := int(d.Day.Sub(start).Hours() / 24)
+		if idx >= 0 && idx < days {
+			series[idx] += u.dayValue(d)
+		}
+	}
+	return series
+}
+
+// renderSparkline draws one character per value, scaled to peak. Days with
+// nothing are left blank so activity stands out.
+func renderSparkline(values []float64, peak float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		if v <= 0 || peak <= 0 {
+			b.WriteRune(' ')
+			continue
+		}
+		level := int(v / peak * float64(len(sparkLevels)-1))
+		if level >= len(sparkLevels) {
+			level = len(sparkLevels) - 1
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// providerSummary renders a bar stacked by each provider's share of the
+// selected metric, followed by the per-provider totals.
+func (u *UsagePanel) providerSummary(width int) string {
+	totals := make(map[string]float64)
+	var providers []string
+	var grand float64
+	for _, s := range u.stats {
+		if _, ok := totals[s.Provider]; !ok {
+			providers = append(providers, s.Provider)
+		}
+		v := u.metricValue(s)
+		totals[s.Provider] += v
+		grand += v
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		if totals[providers[i]] == totals[providers[j]] {
+			return providers[i] < providers[j]
+		}
+		return totals[providers[i]] > totals[providers[j]]
+	})
+
+	var bar strings.Builder
+	var legend []string
+	used := 0
+	for i, p := range providers {
+		style, ok := u.styles.Providers[p]
+		if !ok {
+			style = u.styles.BarFill
+		}
+		share := 0.0
+		if grand > 0 {
+			share = totals[p] / grand
+		}
+		n := int(share*float64(width) + 0.5)
+		if i == len(providers)-1 && grand > 0 {
+			n = width - used
+		}
+		if used+n > width {
+			n = width - used
+		}
+		used += n
+		bar.WriteString(style.Render(strings.Repeat("█", n)))
+		legend = append(legend, style.Render(p)+" "+u.formatMetric(totals[p])+fmt.Sprintf(" (%.0f%%)", share*100))
+	}
+	if used < width {
+		bar.WriteString(strings.Repeat(" ", width-used))
+	}
+
+	return u.styles.Heading.Render("By provider") + "\n" + bar.String() + "\n" + strings.Join(legend, "  ")
+}
+
+// formatMetric renders a total of the selected metric with its unit.
+func (u *UsagePanel) formatMetric(v float64) string {
+	switch u.metric {
+	case UsageMetricSessions:
+		return fmt.Sprintf("%.0f sess", v)
+	case UsageMetricLimitHits:
+		return fmt.Sprintf("%.0f hits", v)
+	default:
+		return fmt.Sprintf("%.1fh", v)
+	}
+}
+
 func (u *UsagePanel) render(title, timeRange, body string) string {
 	inner := lipgloss.JoinVertical(lipgloss.Left, title, timeRange, "", body)
 	if u.width > 0 {
@@ -3,7 +3,9 @@ package tui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	caamdb "github.com/Dicklesworthstone/coding_agent_account_manager/internal/db"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -135,6 +137,84 @@ func TestUsagePanel_NilReceiver(t *testing.T) {
 	u.SetLoading(true)
 	u.SetSize(100, 50)
 	u.SetStats(nil)
+	u.NextMetric()
 	_ = u.Visible()
 	_ = u.TimeRange()
+	_ = u.Metric()
+}
+
+func TestUsagePanel_NextMetric_ResortsByMetric(t *testing.T) {
+	u := NewUsagePanel()
+	u.SetStats([]ProfileUsage{
+		{Provider: "claude", ProfileName: "a", SessionCount: 5, TotalHours: 1.0, LimitHits: 0},
+		{Provider: "codex", ProfileName: "b", SessionCount: 1, TotalHours: 2.0, LimitHits: 3},
+	})
+	if u.stats[0].ProfileName != "b" {
+		t.Fatalf("hours: stats[0] = %q, want b", u.stats[0].ProfileName)
+	}
+
+	u.NextMetric()
+	if u.Metric() != UsageMetricSessions || u.stats[0].ProfileName != "a" {
+		t.Fatalf("sessions: metric = %v, stats[0] = %q, want a", u.Metric(), u.stats[0].ProfileName)
+	}
+	if u.stats[1].Percentage != 0.2 {
+		t.Fatalf("sessions: stats[1].Percentage = %v, want 0.2", u.stats[1].Percentage)
+	}
+
+	u.NextMetric()
+	if u.Metric() != UsageMetricLimitHits || u.stats[0].ProfileName != "b" || u.stats[1].Percentage != 0 {
+		t.Fatalf("limit hits: metric = %v, stats = %+v", u.Metric(), u.stats)
+	}
+
+	u.NextMetric()
+	if u.Metric() != UsageMetricHours {
+		t.Fatalf("metric = %v, want hours after a full cycle", u.Metric())
+	}
+}
+
+func TestUsagePanel_View_DailyChartAndProviderSummary(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	u := NewUsagePanel()
+	u.SetSize(160, 40)
+	u.SetStats([]ProfileUsage{
+		{Provider: "claude", ProfileName: "a", SessionCount: 2, TotalHours: 3, Daily: []UsageDay{
+			{Day: today.AddDate(0, 0, -1), Sessions: 1, Hours: 1},
+			{Day: today, Sessions: 1, Hours: 2},
+		}},
+		{Provider: "codex", ProfileName: "b", SessionCount: 1, TotalHours: 1},
+	})
+
+	out := u.View()
+	for _, want := range []string{"daily, last 7 days", "By provider", "claude 3.0h (75%)", "codex 1.0h (25%)", "█"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() missing %q:\n%s", want, out)
+		}
+	}
+
+	series := u.dailySeries(u.stats[0], 7, today)
+	if series[5] != 1 || series[6] != 2 || series[0] != 0 {
+		t.Errorf("dailySeries() = %v, want yesterday 1 and today 2", series)
+	}
+	if got := renderSparkline(series, 2); got != "     ▄█" {
+		t.Errorf("renderSparkline() = %q", got)
+	}
+}
+
+func TestMergeDailyUsage(t *testing.T) {
+	day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	daily := []caamdb.DailyUsage{
+		{Day: day, Provider: "claude", ProfileName: "a", Sessions: 2, ActiveSeconds: 3600},
+		{Day: day, Provider: "codex", ProfileName: "b", LimitHits: 2},
+	}
+	stats := mergeDailyUsage([]ProfileUsage{{Provider: "claude", ProfileName: "a", SessionCount: 2, TotalHours: 1}}, daily, daily)
+
+	if len(stats) != 2 {
+		t.Fatalf("len = %d, want 2 (limit-only profile gets a row)", len(stats))
+	}
+	if len(stats[0].Daily) != 1 || stats[0].Daily[0].Hours != 1 {
+		t.Errorf("stats[0].Daily = %+v, want one day with 1h", stats[0].Daily)
+	}
+	if stats[1].ProfileName != "b" || stats[1].LimitHits != 2 || len(stats[1].Daily) != 1 {
+		t.Errorf("stats[1] = %+v, want codex/b with 2 limit hits", stats[1])
+	}
 }
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// vaultChoice is one vault the TUI can switch to: the default vault (empty
+// context) or a config context.
+type vaultChoice struct {
+	context string
+	path    string // "" for the default vault
+}
+
+// label returns how the choice is shown in the picker.
+func (c vaultChoice) label() string {
+	if c.context == "" {
+		return "(no context)"
+	}
+	return c.context
+}
+
+// vaultChoices lists the default vault followed by every config context.
+func vaultChoices(cfg *config.Config) []vaultChoice {
+	choices := []vaultChoice{{}}
+	if cfg == nil {
+		return choices
+	}
+	for _, name := range cfg.ListContexts() {
+		ctx, _ := cfg.GetContext(name)
+		choices = append(choices, vaultChoice{context: name, path: ctx.VaultPath})
+	}
+	return choices
+}
+
+// VaultPickerDialog lets the user pick the vault context to work in.
+type VaultPickerDialog struct {
+	choices []vaultChoice
+	cursor  int
+	current string // context in use when the picker opened
+	result  DialogResult
+	styles  Styles
+	width   int
+}
+
+// newVaultPickerDialog creates a picker with the cursor on the current
+// context.
+func newVaultPickerDialog(choices []vaultChoice, current string) *VaultPickerDialog {
+	d := &VaultPickerDialog{
+		choices: choices,
+		current: current,
+		result:  DialogResultNone,
+		styles:  DefaultStyles(),
+		width:   64,
+	}
+	for i, c := range choices {
+		if c.context == current {
+			d.cursor = i
+		}
+	}
+	return d
+}
+
+// SetStyles sets the styles for the dialog.
+func (d *VaultPickerDialog) SetStyles(styles Styles) {
+	d.styles = styles
+}
+
+// SetWidth sets the dialog width.
+func (d *VaultPickerDialog) SetWidth(width int) {
+	d.width = width
+}
+
+// Result returns the dialog result.
+func (d *VaultPickerDialog) Result() DialogResult {
+	return d.result
+}
+
+// Selected returns the choice under the cursor.
+func (d *VaultPickerDialog) Selected() vaultChoice {
+	if d.cursor < 0 || d.cursor >= len(d.choices) {
+		return vaultChoice{}
+	}
+	return d.choices[d.cursor]
+}
+
+// Update handles messages for the dialog.
+func (d *VaultPickerDialog) Update(msg tea.Msg) (*VaultPickerDialog, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return d, nil
+	}
+	switch keyMsg.String() {
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "down", "j":
+		if d.cursor < len(d.choices)-1 {
+			d.cursor++
+		}
+	case "enter":
+		d.result = DialogResultSubmit
+	case "esc", "q":
+		d.result = DialogResultCancel
+	}
+	return d, nil
+}
+
+// View renders the dialog.
+func (d *VaultPickerDialog) View() string {
+	var content strings.Builder
+
+	content.WriteString(d.styles.DialogTitle.Render("Switch Vault"))
+	content.WriteString("\n\n")
+
+	for i, c := range d.choices {
+		marker := "  "
+		if i == d.cursor {
+			marker = "> "
+		}
+		line := marker + c.label() + "  " + displayVaultPath(c.path)
+		if c.context == d.current {
+			line += "  (current)"
+		}
+		if i == d.cursor {
+			line = d.styles.SelectedItem.Render(line)
+		}
+		content.WriteString(line + "\n")
+	}
+	if len(d.choices) == 1 {
+		content.WriteString("\nAdd vaults with 'caam config set-context <name> --vault-path <dir>'.\n")
+	}
+
+	content.WriteString("\n")
+	help := d.styles.StatusKey.Render("enter") + " switch  " +
+		d.styles.StatusKey.Render("j/k") + " move  " +
+		d.styles.StatusKey.Render("esc") + " cancel"
+	content.WriteString(help)
+
+	return d.styles.DialogFocused.
+		Width(d.width).
+		Render(content.String())
+}
+
+// displayVaultPath shortens a vault path for display, with "default vault"
+// for the built-in location.
+func displayVaultPath(path string) string {
+	if path == "" {
+		return "default vault"
+	}
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.Join("~", rel)
+		}
+	}
+	return path
+}
+
+// switchVault makes choice the vault the TUI works in: the vault override is
+// set as the CLI does for --context, the watcher is restarted on the new
+// directory and the profiles are reloaded.
+func (m Model) switchVault(choice vaultChoice) (tea.Model, tea.Cmd) {
+	authfile.SetVaultPathOverride(choice.path)
+	m.vaultPath = authfile.DefaultVaultPath()
+	m.contextName = choice.context
+
+	if m.watcher != nil {
+		_ = m.watcher.Close()
+		m.watcher = nil
+	}
+	m.badges = make(map[string]profileBadge)
+	m.selected = 0
+	m.statusMsg = "Switched to " + choice.label() + " (" + displayVaultPath(choice.path) + ")"
+
+	return m, tea.Batch(m.loadProfiles, m.initWatcher())
+}
+
+func (m Model) handleVaultPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.vaultPicker == nil {
+		m.state = stateList
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.vaultPicker, cmd = m.vaultPicker.Update(msg)
+
+	switch m.vaultPicker.Result() {
+	case DialogResultSubmit:
+		choice := m.vaultPicker.Selected()
+		m.vaultPicker = nil
+		m.state = stateList
+		if choice.context == m.contextName {
+			return m, nil
+		}
+		return m.switchVault(choice)
+
+	case DialogResultCancel:
+		m.vaultPicker = nil
+		m.state = stateList
+		return m, nil
+	}
+
+	return m, cmd
+}
+
+// openVaultPicker shows the vault picker with the contexts from the config.
+func (m Model) openVaultPicker() (tea.Model, tea.Cmd) {
+	cfg, err := config.Load()
+	if err != nil {
+		m.statusMsg = "Failed to load contexts: " + err.Error()
+		return m, nil
+	}
+	m.vaultPicker = newVaultPickerDialog(vaultChoices(cfg), m.contextName)
+	m.vaultPicker.SetStyles(m.styles)
+	m.vaultPicker.SetWidth(m.dialogWidth(m.vaultPicker.width))
+	m.state = stateVaultPicker
+	return m, nil
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/authfile"
+	"github.com/Dicklesworthstone/coding_agent_account_manager/internal/config"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVaultChoices(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.SetContext("work", config.ContextConfig{VaultPath: "/srv/work-vault"})
+	cfg.SetContext("acme", config.ContextConfig{})
+
+	choices := vaultChoices(cfg)
+	if len(choices) != 3 {
+		t.Fatalf("len(choices) = %d, want 3", len(choices))
+	}
+	if choices[0].context != "" || choices[1].context != "acme" || choices[2].context != "work" {
+		t.Fatalf("choices = %+v, want default then sorted contexts", choices)
+	}
+	if choices[2].path != "/srv/work-vault" {
+		t.Fatalf("work path = %q", choices[2].path)
+	}
+	if got := displayVaultPath(""); got != "default vault" {
+		t.Fatalf("displayVaultPath(\"\") = %q", got)
+	}
+}
+
+func TestModel_SwitchVault(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("CAAM_HOME", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, "config"))
+	t.Cleanup(func() { authfile.SetVaultPathOverride("") })
+
+	clientVault := filepath.Join(tmpDir, "client-vault")
+	if err := os.MkdirAll(filepath.Join(clientVault, "claude", "client"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.DefaultConfig()
+	cfg.SetContext("client", config.ContextConfig{VaultPath: clientVault})
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := New()
+	m.width = 120
+	m.height = 40
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("V")})
+	m = model.(Model)
+	if m.state != stateVaultPicker || m.vaultPicker == nil {
+		t.Fatalf("state = %v, want vault picker", m.state)
+	}
+	if out := m.View(); !strings.Contains(out, "client") {
+		t.Fatalf("picker view missing context")
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = model.(Model)
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+
+	if m.state != stateList {
+		t.Fatalf("state = %v, want list after switching", m.state)
+	}
+	if m.vaultPath != clientVault || m.contextName != "client" {
+		t.Fatalf("vaultPath = %q context = %q, want the client vault", m.vaultPath, m.contextName)
+	}
+	if authfile.DefaultVaultPath() != clientVault {
+		t.Fatalf("vault override not applied")
+	}
+	if cmd == nil {
+		t.Fatalf("switching should reload profiles and the watcher")
+	}
+
+	if msg, ok := m.loadProfiles().(profilesLoadedMsg); !ok || len(msg.profiles["claude"]) != 1 {
+		t.Fatalf("loadProfiles() after switch = %+v, want the client vault's profile", msg)
+	}
+	if header := m.mainView(); !strings.Contains(header, "Context: client") {
+		t.Fatalf("header missing active context")
+	}
+}
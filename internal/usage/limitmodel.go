@@ -0,0 +1,237 @@
+package usage
+
+import (
+	"sort"
+	"time"
+)
+
+// LimitWindow describes one rate limit window in a provider's limit model.
+type LimitWindow struct {
+	// Name identifies the window (e.g., "5h", "weekly", "daily").
+	Name string `json:"name"`
+
+	// Duration is the window length.
+	Duration time.Duration `json:"duration"`
+
+	// Capped describes what the provider counts against this window
+	// (e.g., "usage", "messages", "requests").
+	Capped string `json:"capped"`
+
+	// DefaultBudget is the estimated active usage time the window allows
+	// before a limit is hit, used until a budget can be learned from history.
+	DefaultBudget time.Duration `json:"default_budget"`
+}
+
+// LimitModel is the known limit structure of a provider.
+type LimitModel struct {
+	Provider    string        `json:"provider"`
+	Description string        `json:"description"`
+	Windows     []LimitWindow `json:"windows"`
+}
+
+// limitModels encodes each provider's published limit structure. Budgets are
+// deliberately conservative; the shortest window's budget is replaced by a
+// learned value once a profile has hit a limit.
+var limitModels = map[string]LimitModel{
+	"claude": {
+		Provider:    "claude",
+		Description: "Rolling 5-hour usage window plus a weekly cap",
+		Windows: []LimitWindow{
+			{Name: "5h", Duration: 5 * time.Hour, Capped: "usage", DefaultBudget: 3 * time.Hour},
+			{Name: "weekly", Duration: 7 * 24 * time.Hour, Capped: "usage", DefaultBudget: 40 * time.Hour},
+		},
+	},
+	"codex": {
+		Provider:    "codex",
+		Description: "ChatGPT plan message caps over a 5-hour window plus a weekly cap",
+		Windows: []LimitWindow{
+			{Name: "5h", Duration: 5 * time.Hour, Capped: "messages", DefaultBudget: 3 * time.Hour},
+			{Name: "weekly", Duration: 7 * 24 * time.Hour, Capped: "messages", DefaultBudget: 30 * time.Hour},
+		},
+	},
+	"gemini": {
+		Provider:    "gemini",
+		Description: "Daily request quota",
+		Windows: []LimitWindow{
+			{Name: "daily", Duration: 24 * time.Hour, Capped: "requests", DefaultBudget: 8 * time.Hour},
+		},
+	},
+}
+
+// LimitModelFor returns the limit model for a provider.
+func LimitModelFor(provider string) (LimitModel, bool) {
+	m, ok := limitModels[provider]
+	return m, ok
+}
+
+// LimitModels returns all known limit models sorted by provider.
+func LimitModels() []LimitModel {
+	out := make([]LimitModel, 0, len(limitModels))
+	for _, m := range limitModels {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
+
+// MaxWindow returns the longest window in the model.
+func (m LimitModel) MaxWindow() time.Duration {
+	var longest time.Duration
+	for _, w := range m.Windows {
+		if w.Duration > longest {
+			longest = w.Duration
+		}
+	}
+	return longest
+}
+
+// UsageSample is a span of recorded activity on a profile.
+type UsageSample struct {
+	Start    time.Time
+	Duration time.Duration
+}
+
+// WindowEstimate is the estimated state of one limit window.
+type WindowEstimate struct {
+	Window    string        `json:"window"`
+	Used      time.Duration `json:"used"`
+	Budget    time.Duration `json:"budget"`
+	Learned   bool          `json:"learned"`
+	Headroom  float64       `json:"headroom"`
+	Exhausted bool          `json:"exhausted"`
+	ResetsAt  time.Time     `json:"resets_at,omitempty"`
+}
+
+// HeadroomEstimate is the estimated remaining capacity of a profile.
+type HeadroomEstimate struct {
+	Provider    string           `json:"provider"`
+	ProfileName string           `json:"profile_name"`
+	Windows     []WindowEstimate `json:"windows"`
+
+	// Headroom is the tightest window's remaining fraction (0.0 to 1.0).
+	Headroom float64 `json:"headroom"`
+
+	// Samples is the number of usage samples the estimate is based on.
+	Samples int `json:"samples"`
+}
+
+// EstimateHeadroom estimates remaining capacity per window from recorded
+// usage and limit hits.
+//
+// Headroom is the unused share of each window's budget. Limit hits can't be
+// attributed to a specific cap, so they are charged to the shortest window:
+// a recent hit marks it exhausted until it rolls over, and the usage that led
+// up to the latest hit becomes its learned budget. Longer windows are
+// estimated against their default budget.
+func EstimateHeadroom(model LimitModel, samples []UsageSample, hits []time.Time, now time.Time) HeadroomEstimate {
+	est := HeadroomEstimate{
+		Provider: model.Provider,
+		Headroom: 1,
+		Samples:  len(samples),
+	}
+
+	sortedHits := append([]time.Time(nil), hits...)
+	sort.Slice(sortedHits, func(i, j int) bool { return sortedHits[i].Before(sortedHits[j]) })
+
+	shortest := -1
+	for i, w := range model.Windows {
+		if shortest < 0 || w.Duration < model.Windows[shortest].Duration {
+			shortest = i
+		}
+	}
+
+	for i, w := range model.Windows {
+		we := WindowEstimate{
+			Window: w.Name,
+			Budget: w.DefaultBudget,
+			Used:   usedBetween(samples, now.Add(-w.Duration), now),
+		}
+
+		windowHits := sortedHits
+		if i != shortest {
+			windowHits = nil
+		}
+
+		// Learn the budget from the most recent limit hit.
+		for j := len(windowHits) - 1; j >= 0; j-- {
+			h := windowHits[j]
+			if used := usedBetween(samples, h.Add(-w.Duration), h); used > 0 {
+				we.Budget = used
+				we.Learned = true
+				break
+			}
+		}
+
+		// A hit inside the current window means it's spent until it resets.
+		for j := len(windowHits) - 1; j >= 0; j-- {
+			h := windowHits[j]
+			if h.After(now) || h.Before(now.Add(-w.Duration)) {
+				continue
+			}
+			resetsAt := firstActivity(samples, h.Add(-w.Duration), h, h).Add(w.Duration)
+			if resetsAt.After(now) {
+				we.Exhausted = true
+				we.ResetsAt = resetsAt
+			}
+			break
+		}
+
+		switch {
+		case we.Exhausted:
+			we.Headroom = 0
+		case we.Budget <= 0:
+			we.Headroom = 1
+		default:
+			we.Headroom = 1 - float64(we.Used)/float64(we.Budget)
+			if we.Headroom < 0 {
+				we.Headroom = 0
+			}
+		}
+
+		if we.Headroom < est.Headroom {
+			est.Headroom = we.Headroom
+		}
+		est.Windows = append(est.Windows, we)
+	}
+
+	return est
+}
+
+// usedBetween sums the portion of each sample that overlaps [from, to].
+func usedBetween(samples []UsageSample, from, to time.Time) time.Duration {
+	var total time.Duration
+	for _, s := range samples {
+		start := s.Start
+		end := s.Start.Add(s.Duration)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+// firstActivity returns the earliest sample start within [from, to], or
+// fallback if there is none.
+func firstActivity(samples []UsageSample, from, to, fallback time.Time) time.Time {
+	first := fallback
+	for _, s := range samples {
+		end := s.Start.Add(s.Duration)
+		if end.Before(from) || s.Start.After(to) {
+			continue
+		}
+		start := s.Start
+		if start.Before(from) {
+			start = from
+		}
+		if start.Before(first) {
+			first = start
+		}
+	}
+	return first
+}
@@ -0,0 +1,113 @@
+package usage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLimitModels_KnownProviders(t *testing.T) {
+	for _, provider := range []string{"claude", "codex", "gemini"} {
+		m, ok := LimitModelFor(provider)
+		if !ok {
+			t.Fatalf("LimitModelFor(%q) not found", provider)
+		}
+		if len(m.Windows) == 0 {
+			t.Errorf("%s model has no windows", provider)
+		}
+	}
+
+	claude, _ := LimitModelFor("claude")
+	if claude.MaxWindow() != 7*24*time.Hour {
+		t.Errorf("claude MaxWindow() = %v, want weekly", claude.MaxWindow())
+	}
+
+	if _, ok := LimitModelFor("unknown"); ok {
+		t.Error("LimitModelFor(unknown) should not be found")
+	}
+	if got := len(LimitModels()); got != 3 {
+		t.Errorf("LimitModels() len = %d, want 3", got)
+	}
+}
+
+func TestEstimateHeadroom_NoHistory(t *testing.T) {
+	model, _ := LimitModelFor("gemini")
+	now := time.Now()
+
+	est := EstimateHeadroom(model, nil, nil, now)
+	if est.Headroom != 1 {
+		t.Errorf("Headroom = %v, want 1 with no recorded usage", est.Headroom)
+	}
+	if est.Windows[0].Learned {
+		t.Error("budget should not be learned without limit hits")
+	}
+}
+
+func TestEstimateHeadroom_DefaultBudget(t *testing.T) {
+	model := LimitModel{
+		Provider: "test",
+		Windows:  []LimitWindow{{Name: "5h", Duration: 5 * time.Hour, DefaultBudget: 2 * time.Hour}},
+	}
+	now := time.Now()
+	samples := []UsageSample{
+		{Start: now.Add(-90 * time.Minute), Duration: time.Hour},
+		// Mostly outside the window; only the last 30 minutes count.
+		{Start: now.Add(-5*time.Hour - 30*time.Minute), Duration: time.Hour},
+	}
+
+	est := EstimateHeadroom(model, samples, nil, now)
+	w := est.Windows[0]
+	if w.Used != 90*time.Minute {
+		t.Errorf("Used = %v, want 1h30m", w.Used)
+	}
+	if math.Abs(est.Headroom-0.25) > 0.001 {
+		t.Errorf("Headroom = %v, want 0.25", est.Headroom)
+	}
+}
+
+func TestEstimateHeadroom_LearnsBudgetFromHit(t *testing.T) {
+	model := LimitModel{
+		Provider: "test",
+		Windows:  []LimitWindow{{Name: "5h", Duration: 5 * time.Hour, DefaultBudget: 10 * time.Hour}},
+	}
+	now := time.Now()
+	hit := now.Add(-24 * time.Hour)
+	samples := []UsageSample{
+		// One hour of use led to the old hit.
+		{Start: hit.Add(-time.Hour), Duration: time.Hour},
+		// 30 minutes of use in the current window.
+		{Start: now.Add(-30 * time.Minute), Duration: 30 * time.Minute},
+	}
+
+	est := EstimateHeadroom(model, samples, []time.Time{hit}, now)
+	w := est.Windows[0]
+	if !w.Learned || w.Budget != time.Hour {
+		t.Fatalf("Budget = %v (learned=%v), want learned 1h", w.Budget, w.Learned)
+	}
+	if w.Exhausted {
+		t.Error("window should not be exhausted by a hit outside it")
+	}
+	if math.Abs(est.Headroom-0.5) > 0.001 {
+		t.Errorf("Headroom = %v, want 0.5", est.Headroom)
+	}
+}
+
+func TestEstimateHeadroom_RecentHitExhaustsWindow(t *testing.T) {
+	model, _ := LimitModelFor("claude")
+	now := time.Now()
+	start := now.Add(-2 * time.Hour)
+	hit := now.Add(-30 * time.Minute)
+	samples := []UsageSample{{Start: start, Duration: 90 * time.Minute}}
+
+	est := EstimateHeadroom(model, samples, []time.Time{hit}, now)
+	if est.Headroom != 0 {
+		t.Fatalf("Headroom = %v, want 0 after a recent hit", est.Headroom)
+	}
+	w := est.Windows[0]
+	if !w.Exhausted {
+		t.Fatal("5h window should be exhausted")
+	}
+	if want := start.Add(5 * time.Hour); !w.ResetsAt.Equal(want) {
+		t.Errorf("ResetsAt = %v, want %v", w.ResetsAt, want)
+	}
+}
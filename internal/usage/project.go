@@ -0,0 +1,95 @@
+package usage
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ProjectSample is a UsageSample along with the directory it ran in.
+type ProjectSample struct {
+	UsageSample
+	WorkDir string
+}
+
+// ProjectUse is how much of a profile's limit window one project used.
+type ProjectUse struct {
+	Provider    string        `json:"provider"`
+	ProfileName string        `json:"profile_name"`
+	Project     string        `json:"project"`
+	Window      string        `json:"window"`
+	Used        time.Duration `json:"used"`
+	Budget      time.Duration `json:"budget"`
+
+	// Share is Used as a fraction of Budget (may exceed 1).
+	Share float64 `json:"share"`
+}
+
+// EstimateProjectUse charges the samples that ran in project (the directory,
+// anything below it, or a directory matching it as a glob) against the
+// profile's limit windows, with budgets estimated as EstimateHeadroom does
+// from all samples. It returns the window where the project's share is
+// largest, or false if the project used none of any window.
+func EstimateProjectUse(model LimitModel, samples []ProjectSample, hits []time.Time, project string, now time.Time) (ProjectUse, bool) {
+	all := make([]UsageSample, 0, len(samples))
+	var mine []UsageSample
+	for _, s := range samples {
+		all = append(all, s.UsageSample)
+		if InProject(s.WorkDir, project) {
+			mine = append(mine, s.UsageSample)
+		}
+	}
+	if len(mine) == 0 {
+		return ProjectUse{}, false
+	}
+
+	est := EstimateHeadroom(model, all, hits, now)
+	var best ProjectUse
+	found := false
+	for i, w := range model.Windows {
+		budget := est.Windows[i].Budget
+		used := usedBetween(mine, now.Add(-w.Duration), now)
+		if used <= 0 || budget <= 0 {
+			continue
+		}
+		share := float64(used) / float64(budget)
+		if !found || share > best.Share {
+			best = ProjectUse{
+				Provider: model.Provider,
+				Project:  project,
+				Window:   w.Name,
+				Used:     used,
+				Budget:   budget,
+				Share:    share,
+			}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// InProject reports whether dir is project or lies below it. project may be
+// a glob, as in project associations.
+func InProject(dir, project string) bool {
+	if dir == "" || project == "" {
+		return false
+	}
+	dir = filepath.Clean(dir)
+	project = filepath.Clean(project)
+	glob := strings.ContainsAny(project, "*?[")
+	for {
+		if dir == project {
+			return true
+		}
+		if glob {
+			if ok, _ := filepath.Match(project, dir); ok {
+				return true
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
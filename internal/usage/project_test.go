@@ -0,0 +1,57 @@
+package usage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimateProjectUse(t *testing.T) {
+	model := LimitModel{
+		Provider: "claude",
+		Windows: []LimitWindow{
+			{Name: "5h", Duration: 5 * time.Hour, DefaultBudget: 2 * time.Hour},
+			{Name: "weekly", Duration: 7 * 24 * time.Hour, DefaultBudget: 40 * time.Hour},
+		},
+	}
+	now := time.Now()
+	samples := []ProjectSample{
+		{UsageSample{Start: now.Add(-3 * time.Hour), Duration: time.Hour}, "/src/foo"},
+		{UsageSample{Start: now.Add(-90 * time.Minute), Duration: 24 * time.Minute}, "/src/foo/sub"},
+		{UsageSample{Start: now.Add(-time.Hour), Duration: 30 * time.Minute}, "/src/bar"},
+	}
+
+	use, ok := EstimateProjectUse(model, samples, nil, "/src/foo", now)
+	if !ok {
+		t.Fatal("EstimateProjectUse() found no use")
+	}
+	if use.Window != "5h" || use.Used != 84*time.Minute || use.Budget != 2*time.Hour {
+		t.Errorf("use = %+v, want 84m of the 5h window's 2h", use)
+	}
+	if math.Abs(use.Share-0.7) > 1e-9 {
+		t.Errorf("Share = %v, want 0.7", use.Share)
+	}
+
+	if _, ok := EstimateProjectUse(model, samples, nil, "/src/baz", now); ok {
+		t.Error("a project without sessions should report no use")
+	}
+}
+
+func TestInProject(t *testing.T) {
+	tests := []struct {
+		dir, project string
+		want         bool
+	}{
+		{"/src/foo", "/src/foo", true},
+		{"/src/foo/a/b", "/src/foo", true},
+		{"/src/foobar", "/src/foo", false},
+		{"/src/client-x/api", "/src/client-*", true},
+		{"/work", "/src/client-*", false},
+		{"", "/src/foo", false},
+	}
+	for _, tt := range tests {
+		if got := InProject(tt.dir, tt.project); got != tt.want {
+			t.Errorf("InProject(%q, %q) = %v, want %v", tt.dir, tt.project, got, tt.want)
+		}
+	}
+}
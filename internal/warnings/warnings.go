@@ -130,11 +130,15 @@ func (c *Checker) CheckActive(ctx context.Context) []Warning {
 func (c *Checker) checkVaultProfile(ctx context.Context, tool, profileName string) []Warning {
 	var warnings []Warning
 
-	vaultPath := c.vault.ProfilePath(tool, profileName)
+	// Parse a plain copy: the vault files may be sealed or in the keychain.
+	vaultPath, cleanup, err := c.vault.PlainProfileDir(tool, profileName)
+	if err != nil {
+		return warnings
+	}
+	defer cleanup()
 
 	// Parse expiry based on tool type
 	var expInfo *health.ExpiryInfo
-	var err error
 
 	switch tool {
 	case "claude":
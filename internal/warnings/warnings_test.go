@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -251,6 +252,29 @@ func TestNewChecker(t *testing.T) {
 	}
 }
 
+func TestCheckVaultProfile_EncryptedVault(t *testing.T) {
+	vault := authfile.NewVault(t.TempDir())
+	dir := vault.ProfilePath("codex", "work")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	auth := fmt.Sprintf(`{"access_token":"tok","expires_at":%d}`, time.Now().Add(30*time.Minute).Unix())
+	if err := os.WriteFile(filepath.Join(dir, "auth.json"), []byte(auth), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vault.EnableEncryption("pass"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := vault.EncryptAll(); err != nil || n != 1 {
+		t.Fatalf("EncryptAll() = %d, %v", n, err)
+	}
+
+	got := NewChecker(vault, nil, nil).checkVaultProfile(context.Background(), "codex", "work")
+	if len(got) != 1 || got[0].Level != LevelCritical {
+		t.Errorf("checkVaultProfile() = %+v, want a critical expiry warning", got)
+	}
+}
+
 func TestPrintInfoLevel(t *testing.T) {
 	warnings := []Warning{
 		{
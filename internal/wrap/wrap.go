@@ -73,6 +73,9 @@ type Config struct {
 	// Algorithm is the rotation algorithm to use (smart, round_robin, random).
 	Algorithm rotation.Algorithm
 
+	// Excluded are profiles never to switch to, such as quarantined ones.
+	Excluded []string
+
 	// Stdout is where to write stdout. Defaults to os.Stdout.
 	Stdout io.Writer
 
@@ -110,6 +113,7 @@ func ConfigFromGlobal(cfg *config.Config, provider string) Config {
 		CooldownDuration:  wrapCfg.CooldownDuration.Duration(),
 		NotifyOnSwitch:    true,
 		Algorithm:         rotation.AlgorithmSmart,
+		Excluded:          cfg.QuarantinedProfiles(provider),
 		Stdout:            os.Stdout,
 		Stderr:            os.Stderr,
 	}
@@ -230,6 +234,7 @@ func (w *Wrapper) Run(ctx context.Context) *Result {
 
 	// Create selector
 	selector := rotation.NewSelector(w.config.Algorithm, w.healthStore, w.db)
+	selector.SetExcluded(w.config.Excluded)
 
 	// Select initial profile
 	currentProfile := ""
@@ -522,6 +527,10 @@ func (w *Wrapper) recordSession(result *Result) {
 		EndedAt:      result.StartTime.Add(result.Duration),
 		ExitCode:     result.ExitCode,
 		RateLimitHit: result.RateLimitHit,
+		WorkDir:      w.config.WorkDir,
+	}
+	if session.WorkDir == "" {
+		session.WorkDir, _ = os.Getwd()
 	}
 
 	// Notes can include retry count or error info